@@ -0,0 +1,335 @@
+// Package bandwidth counts the bytes the server sends and receives on the
+// network, by domain and by tool, so operators can see where the egress of
+// a scraping workload goes.
+package bandwidth
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxRecentCalls bounds the per-call history kept for Stats; the oldest
+// calls are dropped first
+const maxRecentCalls = 100
+
+// Usage is a count of bytes and the requests that moved them
+type Usage struct {
+	BytesSent     int64 `json:"bytes_sent"`
+	BytesReceived int64 `json:"bytes_received"`
+	Requests      int64 `json:"requests"`
+}
+
+func (u *Usage) add(o Usage) {
+	u.BytesSent += o.BytesSent
+	u.BytesReceived += o.BytesReceived
+	u.Requests += o.Requests
+}
+
+func (u Usage) minus(o Usage) Usage {
+	return Usage{
+		BytesSent:     u.BytesSent - o.BytesSent,
+		BytesReceived: u.BytesReceived - o.BytesReceived,
+		Requests:      u.Requests - o.Requests,
+	}
+}
+
+// ToolUsage is the traffic of every call to one tool
+type ToolUsage struct {
+	Usage
+	Calls int64 `json:"calls"`
+}
+
+// CallUsage is the traffic of one tool call
+type CallUsage struct {
+	Tool       string    `json:"tool"`
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+	Usage
+	// Overlapped is set when other calls ran at the same time; their
+	// traffic is then counted against this call as well
+	Overlapped bool `json:"overlapped,omitempty"`
+}
+
+// Stats is a snapshot of a Meter
+type Stats struct {
+	Since   time.Time             `json:"since"`
+	Total   Usage                 `json:"total"`
+	Domains map[string]Usage      `json:"domains"`
+	Tools   map[string]*ToolUsage `json:"tools"`
+	Recent  []CallUsage           `json:"recent_calls"`
+}
+
+// TopDomains returns the domains sorted by bytes moved, most first, at
+// most limit of them (all when limit is 0)
+func (s *Stats) TopDomains(limit int) []string {
+	domains := make([]string, 0, len(s.Domains))
+	for domain := range s.Domains {
+		domains = append(domains, domain)
+	}
+	sort.Slice(domains, func(i, j int) bool {
+		a, b := s.Domains[domains[i]], s.Domains[domains[j]]
+		if ta, tb := a.BytesSent+a.BytesReceived, b.BytesSent+b.BytesReceived; ta != tb {
+			return ta > tb
+		}
+		return domains[i] < domains[j]
+	})
+	if limit > 0 && len(domains) > limit {
+		domains = domains[:limit]
+	}
+	return domains
+}
+
+// Meter accumulates traffic from the browser and the server's own HTTP
+// clients. A nil *Meter ignores everything, so callers need not check.
+type Meter struct {
+	mutex   sync.Mutex
+	since   time.Time
+	total   Usage
+	domains map[string]*Usage
+	tools   map[string]*ToolUsage
+	recent  []CallUsage
+	// Calls in progress, to flag the ones that overlap
+	active  int
+	overlap map[int64]bool
+	nextID  int64
+}
+
+func NewMeter() *Meter {
+	m := &Meter{}
+	m.reset()
+	return m
+}
+
+func (m *Meter) reset() {
+	m.since = time.Now()
+	m.total = Usage{}
+	m.domains = make(map[string]*Usage)
+	m.tools = make(map[string]*ToolUsage)
+	m.recent = nil
+	if m.overlap == nil {
+		m.overlap = make(map[int64]bool)
+	}
+}
+
+// Reset zeroes every counter. Calls in progress are still recorded when
+// they end, with only the traffic from after the reset.
+func (m *Meter) Reset() {
+	if m == nil {
+		return
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.reset()
+}
+
+// Record adds traffic to host's count. A record with bytes sent counts as
+// a request; one with only bytes received finishes a request already
+// counted. Data URLs and other requests without a host are counted under
+// "(none)".
+func (m *Meter) Record(host string, sent, received int64) {
+	if m == nil || sent <= 0 && received <= 0 {
+		return
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		host = "(none)"
+	}
+	usage := Usage{BytesSent: max(sent, 0), BytesReceived: max(received, 0)}
+	if sent > 0 {
+		usage.Requests = 1
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.total.add(usage)
+	d, ok := m.domains[host]
+	if !ok {
+		d = &Usage{}
+		m.domains[host] = d
+	}
+	d.add(usage)
+}
+
+// RecordURL is Record for the host of rawURL
+func (m *Meter) RecordURL(rawURL string, sent, received int64) {
+	m.Record(Host(rawURL), sent, received)
+}
+
+// Host is the host name of rawURL without its port, or "" when it has none
+func Host(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// BeginCall starts attributing traffic to a call of tool and returns the
+// function that ends it. Traffic is not tagged with the call that caused
+// it, so a call is charged with everything moved while it ran.
+func (m *Meter) BeginCall(tool string) func() CallUsage {
+	if m == nil {
+		return func() CallUsage { return CallUsage{Tool: tool} }
+	}
+	started := time.Now()
+	m.mutex.Lock()
+	before := m.total
+	since := m.since
+	m.nextID++
+	id := m.nextID
+	if m.active > 0 {
+		for other := range m.overlap {
+			m.overlap[other] = true
+		}
+		m.overlap[id] = true
+	} else {
+		m.overlap[id] = false
+	}
+	m.active++
+	m.mutex.Unlock()
+
+	return func() CallUsage {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+		m.active--
+		call := CallUsage{
+			Tool:       tool,
+			StartedAt:  started,
+			DurationMs: time.Since(started).Milliseconds(),
+			Overlapped: m.overlap[id],
+		}
+		delete(m.overlap, id)
+		if m.since != since {
+			// Reset while running: count from the reset
+			before = Usage{}
+		}
+		call.Usage = m.total.minus(before)
+
+		t, ok := m.tools[tool]
+		if !ok {
+			t = &ToolUsage{}
+			m.tools[tool] = t
+		}
+		t.Usage.add(call.Usage)
+		t.Calls++
+		m.recent = append(m.recent, call)
+		if over := len(m.recent) - maxRecentCalls; over > 0 {
+			m.recent = append([]CallUsage(nil), m.recent[over:]...)
+		}
+		return call
+	}
+}
+
+// Stats copies the meter's counters
+func (m *Meter) Stats() Stats {
+	if m == nil {
+		return Stats{Domains: map[string]Usage{}, Tools: map[string]*ToolUsage{}}
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	stats := Stats{
+		Since:   m.since,
+		Total:   m.total,
+		Domains: make(map[string]Usage, len(m.domains)),
+		Tools:   make(map[string]*ToolUsage, len(m.tools)),
+		Recent:  append([]CallUsage(nil), m.recent...),
+	}
+	for host, usage := range m.domains {
+		stats.Domains[host] = *usage
+	}
+	for tool, usage := range m.tools {
+		clone := *usage
+		stats.Tools[tool] = &clone
+	}
+	return stats
+}
+
+// Transport wraps base (http.DefaultTransport when nil) so the requests
+// it carries are counted. Header sizes are estimated from the header
+// fields, since net/http does not report what it wrote on the wire, and
+// bodies the transport decompressed count at their decompressed size.
+func (m *Meter) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if m == nil {
+		return base
+	}
+	return &meteredTransport{meter: m, base: base}
+}
+
+type meteredTransport struct {
+	meter *Meter
+	base  http.RoundTripper
+}
+
+func (t *meteredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	sent := requestSize(req)
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.meter.Record(req.URL.Hostname(), sent, 0)
+		return resp, err
+	}
+	resp.Body = &meteredBody{
+		ReadCloser: resp.Body,
+		done: func(read int64) {
+			t.meter.Record(req.URL.Hostname(), sent, responseHeaderSize(resp)+read)
+		},
+	}
+	return resp, nil
+}
+
+// requestSize estimates the bytes of a request line, its headers and body
+func requestSize(req *http.Request) int64 {
+	size := int64(len(req.Method) + len(req.URL.RequestURI()) + len(" HTTP/1.1\r\n"))
+	size += int64(len("Host: \r\n") + len(req.Host))
+	size += headerSize(req.Header) + 2
+	if req.ContentLength > 0 {
+		size += req.ContentLength
+	}
+	return size
+}
+
+// responseHeaderSize estimates the bytes of a status line and headers
+func responseHeaderSize(resp *http.Response) int64 {
+	return int64(len(resp.Proto)+len(resp.Status)+3) + headerSize(resp.Header) + 2
+}
+
+func headerSize(h http.Header) int64 {
+	var size int64
+	for key, values := range h {
+		for _, value := range values {
+			size += int64(len(key) + len(value) + 4)
+		}
+	}
+	return size
+}
+
+// meteredBody counts a response body as it is read and reports the total
+// once, on EOF or Close, whichever comes first
+type meteredBody struct {
+	io.ReadCloser
+	read int64
+	once sync.Once
+	done func(int64)
+}
+
+func (b *meteredBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if err == io.EOF {
+		b.once.Do(func() { b.done(b.read) })
+	}
+	return n, err
+}
+
+func (b *meteredBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.read) })
+	return err
+}
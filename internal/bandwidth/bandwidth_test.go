@@ -0,0 +1,90 @@
+package bandwidth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMeterCountsByDomainAndTool(t *testing.T) {
+	m := NewMeter()
+	end := m.BeginCall("scrape")
+	m.Record("Example.com.", 100, 0)
+	m.Record("example.com", 0, 5000)
+	m.RecordURL("https://cdn.example.net:8443/a.js", 50, 700)
+	m.Record("", 10, 0)
+	call := end()
+
+	if call.BytesSent != 160 || call.BytesReceived != 5700 || call.Requests != 3 || call.Overlapped {
+		t.Errorf("call = %+v", call)
+	}
+	stats := m.Stats()
+	if d := stats.Domains["example.com"]; d.BytesReceived != 5000 || d.Requests != 1 {
+		t.Errorf("example.com = %+v", d)
+	}
+	if _, ok := stats.Domains["(none)"]; !ok {
+		t.Errorf("Expected hostless traffic under (none), got %v", stats.Domains)
+	}
+	if top := stats.TopDomains(1); len(top) != 1 || top[0] != "example.com" {
+		t.Errorf("TopDomains = %v", top)
+	}
+	if tool := stats.Tools["scrape"]; tool == nil || tool.Calls != 1 || tool.BytesReceived != 5700 {
+		t.Errorf("scrape = %+v", tool)
+	}
+
+	m.Reset()
+	if stats := m.Stats(); stats.Total != (Usage{}) || len(stats.Domains) != 0 || len(stats.Recent) != 0 {
+		t.Errorf("after Reset = %+v", stats)
+	}
+}
+
+func TestMeterFlagsOverlappingCalls(t *testing.T) {
+	m := NewMeter()
+	endA := m.BeginCall("a")
+	endB := m.BeginCall("b")
+	m.Record("example.com", 10, 10)
+	a, b := endA(), endB()
+	if !a.Overlapped || !b.Overlapped || a.BytesSent != 10 || b.BytesSent != 10 {
+		t.Errorf("a = %+v, b = %+v", a, b)
+	}
+
+	c := m.BeginCall("c")()
+	if c.Overlapped {
+		t.Errorf("Expected a lone call not to overlap: %+v", c)
+	}
+}
+
+func TestNilMeterIgnoresTraffic(t *testing.T) {
+	var m *Meter
+	m.Record("example.com", 1, 1)
+	m.Reset()
+	if call := m.BeginCall("x")(); call.Tool != "x" || call.Requests != 0 {
+		t.Errorf("call = %+v", call)
+	}
+	if m.Transport(nil) != http.DefaultTransport {
+		t.Error("Expected a nil meter to leave the transport alone")
+	}
+}
+
+func TestTransportCountsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("x", 1000))
+	}))
+	defer server.Close()
+
+	m := NewMeter()
+	client := &http.Client{Transport: m.Transport(nil)}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	d := m.Stats().Domains["127.0.0.1"]
+	if d.Requests != 1 || d.BytesReceived < 1000 || d.BytesSent < int64(len("hello")) {
+		t.Errorf("127.0.0.1 = %+v", d)
+	}
+}
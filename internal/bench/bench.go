@@ -0,0 +1,436 @@
+// Package bench measures how fast the browser works on this host and
+// recommends a page pool size and timeouts sized to it.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+)
+
+// Options configures what `rodmcp bench` measures
+type Options struct {
+	// Iterations is the number of samples taken per measurement
+	Iterations int
+	// Concurrency is how many pages are created at once when measuring
+	// page-creation throughput
+	Concurrency int
+	// URL is the page navigated to; empty serves a fixed test page from a
+	// local server so results don't depend on the network
+	URL    string
+	Logger *logger.Logger
+}
+
+// DefaultOptions returns the options used by `rodmcp bench`
+func DefaultOptions() Options {
+	return Options{
+		Iterations:  10,
+		Concurrency: 4,
+	}
+}
+
+// Stats summarizes the samples of one measurement
+type Stats struct {
+	Samples int           `json:"samples"`
+	Errors  int           `json:"errors"`
+	Min     time.Duration `json:"min_ns"`
+	Median  time.Duration `json:"median_ns"`
+	P95     time.Duration `json:"p95_ns"`
+	Max     time.Duration `json:"max_ns"`
+	Mean    time.Duration `json:"mean_ns"`
+	// Error is the first error a sample failed with
+	Error string `json:"error,omitempty"`
+}
+
+// Host describes the machine and browser the benchmark ran on
+type Host struct {
+	OS      string `json:"os"`
+	Arch    string `json:"arch"`
+	CPUs    int    `json:"cpus"`
+	Browser string `json:"browser"`
+	URL     string `json:"url"`
+}
+
+// Recommendation is a setting suggested for this host
+type Recommendation struct {
+	// Setting is a command-line flag ("--page-pool") or a dotted config
+	// file key ("timeouts.navigate_page")
+	Setting string `json:"setting"`
+	Value   string `json:"value"`
+	Reason  string `json:"reason"`
+}
+
+// Report holds the measurements of one benchmark run
+type Report struct {
+	Host        Host          `json:"host"`
+	Iterations  int           `json:"iterations"`
+	Concurrency int           `json:"concurrency"`
+	Launch      time.Duration `json:"launch_ns"`
+
+	PageCreation   Stats   `json:"page_creation"`
+	PagesPerSecond float64 `json:"pages_per_second"`
+	Navigation     Stats   `json:"navigation"`
+	Eval           Stats   `json:"eval"`
+	ScreenshotPNG  Stats   `json:"screenshot_png"`
+	ScreenshotJPEG Stats   `json:"screenshot_jpeg"`
+	// PNGBytes and JPEGBytes are the average encoded screenshot sizes
+	PNGBytes  int `json:"png_bytes"`
+	JPEGBytes int `json:"jpeg_bytes"`
+
+	Recommendations []Recommendation `json:"recommendations"`
+	Duration        time.Duration    `json:"duration_ns"`
+}
+
+const (
+	benchWidth  = 1280
+	benchHeight = 800
+	launchLimit = 60 * time.Second
+)
+
+// benchPage is navigated to when no URL is given: enough layout and text
+// to make rendering and screenshot encoding do real work
+const benchPage = `<!DOCTYPE html>
+<html><head><title>rodmcp bench</title>
+<style>
+body { font-family: sans-serif; margin: 0; }
+.card { display: inline-block; width: 280px; margin: 8px; padding: 12px;
+        border-radius: 8px; background: linear-gradient(135deg, #4f46e5, #06b6d4); color: #fff; }
+</style></head>
+<body><h1>rodmcp bench</h1><div id="cards"></div>
+<script>
+const cards = document.getElementById('cards');
+for (let i = 0; i < 60; i++) {
+  const card = document.createElement('div');
+  card.className = 'card';
+  card.innerHTML = '<h3>Card ' + i + '</h3><p>' + 'Lorem ipsum dolor sit amet. '.repeat(4) + '</p>';
+  cards.appendChild(card);
+}
+</script></body></html>`
+
+// Run launches a headless browser, measures it and recommends settings.
+// It fails only when the browser cannot be launched; a measurement whose
+// samples all fail reports the error in its Stats.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	started := time.Now()
+	if opts.Iterations <= 0 {
+		opts.Iterations = DefaultOptions().Iterations
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultOptions().Concurrency
+	}
+
+	log := opts.Logger
+	if log == nil {
+		var err error
+		log, err = logger.New(logger.Config{LogLevel: "error", LogDir: os.TempDir()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	url := opts.URL
+	if url == "" {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			fmt.Fprint(w, benchPage)
+		}))
+		defer server.Close()
+		url = server.URL
+	}
+
+	config := browser.Config{Headless: true, WindowWidth: benchWidth, WindowHeight: benchHeight}
+	mgr := browser.NewManager(log, config)
+
+	report := &Report{
+		Host: Host{
+			OS:   runtime.GOOS,
+			Arch: runtime.GOARCH,
+			CPUs: runtime.NumCPU(),
+			URL:  url,
+		},
+		Iterations:  opts.Iterations,
+		Concurrency: opts.Concurrency,
+	}
+	report.Host.Browser, _ = mgr.FindBrowser()
+	if report.Host.Browser == "" {
+		report.Host.Browser = "downloaded by Rod"
+	}
+
+	start := time.Now()
+	if err := launch(ctx, mgr, config); err != nil {
+		return nil, fmt.Errorf("failed to launch the browser: %w", err)
+	}
+	defer mgr.Stop()
+	report.Launch = time.Since(start)
+
+	report.PageCreation, report.PagesPerSecond = measurePageCreation(ctx, mgr, opts)
+
+	_, pageID, err := mgr.NewPageWithOptionsContext(ctx, "", browser.PageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the benchmark page: %w", err)
+	}
+
+	var navigation sampler
+	for i := 0; i < opts.Iterations && ctx.Err() == nil; i++ {
+		navigation.time(func() error { return mgr.NavigateExistingPageContext(ctx, pageID, url) })
+	}
+	report.Navigation = navigation.stats()
+
+	var eval sampler
+	for i := 0; i < opts.Iterations && ctx.Err() == nil; i++ {
+		eval.time(func() error {
+			_, err := mgr.ExecuteScriptContext(ctx, pageID, "1 + 1", browser.ScriptTimeout)
+			return err
+		})
+	}
+	report.Eval = eval.stats()
+
+	report.ScreenshotPNG, report.PNGBytes = measureScreenshot(ctx, mgr, pageID, "png", opts.Iterations)
+	report.ScreenshotJPEG, report.JPEGBytes = measureScreenshot(ctx, mgr, pageID, "jpeg", opts.Iterations)
+	mgr.ClosePage(pageID)
+
+	report.Recommendations = Recommend(report)
+	report.Duration = time.Since(started)
+	return report, ctx.Err()
+}
+
+// launch starts the browser, giving up after launchLimit
+func launch(ctx context.Context, mgr *browser.Manager, config browser.Config) error {
+	launchCtx, cancel := context.WithTimeout(ctx, launchLimit)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("panic: %v", r)
+			}
+		}()
+		done <- mgr.Start(config)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-launchCtx.Done():
+		return fmt.Errorf("timed out after %s", launchLimit)
+	}
+}
+
+// measurePageCreation opens Iterations blank pages, Concurrency at a time,
+// and reports how long each took and how many opened per second overall
+func measurePageCreation(ctx context.Context, mgr *browser.Manager, opts Options) (Stats, float64) {
+	var (
+		mu      sync.Mutex
+		created sampler
+		pageIDs []string
+		wg      sync.WaitGroup
+	)
+	jobs := make(chan struct{}, opts.Iterations)
+	for i := 0; i < opts.Iterations; i++ {
+		jobs <- struct{}{}
+	}
+	close(jobs)
+
+	start := time.Now()
+	for w := 0; w < opts.Concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range jobs {
+				if ctx.Err() != nil {
+					return
+				}
+				begin := time.Now()
+				_, pageID, err := mgr.NewPageWithOptionsContext(ctx, "", browser.PageOptions{})
+				elapsed := time.Since(begin)
+
+				mu.Lock()
+				created.record(elapsed, err)
+				if err == nil {
+					pageIDs = append(pageIDs, pageID)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	wall := time.Since(start)
+
+	for _, pageID := range pageIDs {
+		mgr.ClosePage(pageID)
+	}
+
+	stats := created.stats()
+	var perSecond float64
+	if stats.Samples > 0 && wall > 0 {
+		perSecond = float64(stats.Samples) / wall.Seconds()
+	}
+	return stats, perSecond
+}
+
+// measureScreenshot captures the viewport in format and reports how long
+// each capture took and its average size
+func measureScreenshot(ctx context.Context, mgr *browser.Manager, pageID, format string, iterations int) (Stats, int) {
+	var shots sampler
+	total := 0
+	for i := 0; i < iterations && ctx.Err() == nil; i++ {
+		shots.time(func() error {
+			capture, err := mgr.CaptureScreenshotContext(ctx, pageID, browser.ScreenshotOptions{Format: format})
+			if err == nil {
+				total += len(capture.Data)
+			}
+			return err
+		})
+	}
+	stats := shots.stats()
+	if stats.Samples == 0 {
+		return stats, 0
+	}
+	return stats, total / stats.Samples
+}
+
+// sampler collects the durations of successful samples and counts the
+// failed ones
+type sampler struct {
+	samples  []time.Duration
+	errors   int
+	firstErr error
+}
+
+func (s *sampler) time(op func() error) {
+	start := time.Now()
+	err := op()
+	s.record(time.Since(start), err)
+}
+
+func (s *sampler) record(elapsed time.Duration, err error) {
+	if err != nil {
+		s.errors++
+		if s.firstErr == nil {
+			s.firstErr = err
+		}
+		return
+	}
+	s.samples = append(s.samples, elapsed)
+}
+
+func (s *sampler) stats() Stats {
+	stats := Summarize(s.samples)
+	stats.Errors = s.errors
+	if s.firstErr != nil {
+		stats.Error = s.firstErr.Error()
+	}
+	return stats
+}
+
+// Summarize computes the min, median, 95th percentile, max and mean of
+// samples
+func Summarize(samples []time.Duration) Stats {
+	if len(samples) == 0 {
+		return Stats{}
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	return Stats{
+		Samples: len(sorted),
+		Min:     sorted[0],
+		Median:  percentile(sorted, 50),
+		P95:     percentile(sorted, 95),
+		Max:     sorted[len(sorted)-1],
+		Mean:    total / time.Duration(len(sorted)),
+	}
+}
+
+// percentile picks the nearest-rank percentile p of sorted samples
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Format renders a report as a human-readable summary
+func Format(r *Report) string {
+	var b strings.Builder
+	b.WriteString("⏱️  RodMCP Bench\n")
+	b.WriteString("===============\n\n")
+
+	fmt.Fprintf(&b, "Host: %s/%s, %d CPUs\n", r.Host.OS, r.Host.Arch, r.Host.CPUs)
+	fmt.Fprintf(&b, "Browser: %s\n", r.Host.Browser)
+	fmt.Fprintf(&b, "Page: %s\n", r.Host.URL)
+	fmt.Fprintf(&b, "Samples: %d per measurement, %d pages at once\n\n", r.Iterations, r.Concurrency)
+
+	fmt.Fprintf(&b, "Browser launch: %s\n\n", r.Launch.Round(time.Millisecond))
+	fmt.Fprintf(&b, "%-18s %8s %8s %8s %8s\n", "", "min", "median", "p95", "max")
+	rows := []struct {
+		name  string
+		stats Stats
+		note  string
+	}{
+		{"Page creation", r.PageCreation, fmt.Sprintf("%.1f pages/s", r.PagesPerSecond)},
+		{"Navigation", r.Navigation, ""},
+		{"Eval roundtrip", r.Eval, ""},
+		{"Screenshot (PNG)", r.ScreenshotPNG, formatBytes(r.PNGBytes)},
+		{"Screenshot (JPEG)", r.ScreenshotJPEG, formatBytes(r.JPEGBytes)},
+	}
+	for _, row := range rows {
+		if row.stats.Samples == 0 {
+			fmt.Fprintf(&b, "%-18s failed: %s\n", row.name, row.stats.Error)
+			continue
+		}
+		fmt.Fprintf(&b, "%-18s %8s %8s %8s %8s", row.name,
+			formatDuration(row.stats.Min), formatDuration(row.stats.Median),
+			formatDuration(row.stats.P95), formatDuration(row.stats.Max))
+		if row.note != "" {
+			fmt.Fprintf(&b, "  %s", row.note)
+		}
+		if row.stats.Errors > 0 {
+			fmt.Fprintf(&b, "  (%d failed: %s)", row.stats.Errors, row.stats.Error)
+		}
+		b.WriteString("\n")
+	}
+
+	if len(r.Recommendations) > 0 {
+		b.WriteString("\nRecommended configuration:\n")
+		for _, rec := range r.Recommendations {
+			fmt.Fprintf(&b, "  %s %s\n     %s\n", rec.Setting, rec.Value, rec.Reason)
+		}
+		if snippet := ConfigSnippet(r.Recommendations); snippet != "" {
+			fmt.Fprintf(&b, "\nConfig file (--config):\n%s\n", snippet)
+		}
+	}
+
+	fmt.Fprintf(&b, "\nFinished in %s\n", r.Duration.Round(time.Millisecond))
+	return b.String()
+}
+
+func formatDuration(d time.Duration) string {
+	if d < 10*time.Millisecond {
+		return d.Round(10 * time.Microsecond).String()
+	}
+	return d.Round(time.Millisecond).String()
+}
+
+func formatBytes(n int) string {
+	if n >= 1024 {
+		return fmt.Sprintf("%.0f KB", float64(n)/1024)
+	}
+	return fmt.Sprintf("%d B", n)
+}
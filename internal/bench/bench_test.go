@@ -0,0 +1,157 @@
+package bench
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func ms(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+func TestSummarize(t *testing.T) {
+	if s := Summarize(nil); s.Samples != 0 {
+		t.Errorf("Expected no samples to summarize to zero stats, got %+v", s)
+	}
+
+	var samples []time.Duration
+	for i := 20; i >= 1; i-- {
+		samples = append(samples, ms(i))
+	}
+	s := Summarize(samples)
+	if s.Samples != 20 || s.Min != ms(1) || s.Max != ms(20) {
+		t.Errorf("Unexpected count or range: %+v", s)
+	}
+	if s.Median != ms(10) || s.P95 != ms(19) {
+		t.Errorf("Expected median 10ms and p95 19ms, got %s and %s", s.Median, s.P95)
+	}
+	if s.Mean != 10500*time.Microsecond {
+		t.Errorf("Expected mean 10.5ms, got %s", s.Mean)
+	}
+	if samples[0] != ms(20) {
+		t.Error("Expected Summarize not to reorder the caller's samples")
+	}
+}
+
+func TestSamplerCountsErrors(t *testing.T) {
+	var s sampler
+	s.record(ms(5), nil)
+	s.record(ms(7), errFake("first"))
+	s.record(ms(9), errFake("second"))
+
+	stats := s.stats()
+	if stats.Samples != 1 || stats.Errors != 2 || stats.Error != "first" {
+		t.Errorf("Expected one sample and the first of two errors, got %+v", stats)
+	}
+}
+
+type errFake string
+
+func (e errFake) Error() string { return string(e) }
+
+func fastReport() *Report {
+	fast := Stats{Samples: 10, Min: ms(1), Median: ms(2), P95: ms(4), Max: ms(5)}
+	return &Report{
+		Host:           Host{CPUs: 8},
+		Concurrency:    4,
+		PageCreation:   Stats{Samples: 10, Median: ms(30), P95: ms(50)},
+		Navigation:     fast,
+		Eval:           fast,
+		ScreenshotPNG:  fast,
+		ScreenshotJPEG: fast,
+	}
+}
+
+func recommendations(r *Report) map[string]string {
+	values := make(map[string]string)
+	for _, rec := range Recommend(r) {
+		values[rec.Setting] = rec.Value
+	}
+	return values
+}
+
+func TestRecommendFastHostKeepsDefaults(t *testing.T) {
+	got := recommendations(fastReport())
+	want := map[string]string{
+		"--page-pool":                      "0",
+		"timeouts.navigate_page":           "30s",
+		"timeouts.take_screenshot":         "30s",
+		"timeouts.default":                 "30s",
+		"browser_health.page_ping_timeout": "5s",
+	}
+	for setting, value := range want {
+		if got[setting] != value {
+			t.Errorf("Expected %s %s on a fast host, got %q", setting, value, got[setting])
+		}
+	}
+}
+
+func TestRecommendSlowHostRaisesLimits(t *testing.T) {
+	r := fastReport()
+	r.Host.CPUs = 2
+	r.PageCreation.Median = ms(400)
+	r.Navigation.P95 = ms(2600)
+	r.ScreenshotJPEG.P95 = ms(1600)
+	r.Eval.P95 = ms(130)
+
+	got := recommendations(r)
+	want := map[string]string{
+		"--page-pool":                      "2",
+		"timeouts.navigate_page":           "55s",
+		"timeouts.take_screenshot":         "35s",
+		"timeouts.default":                 "55s",
+		"browser_health.page_ping_timeout": "7s",
+	}
+	for setting, value := range want {
+		if got[setting] != value {
+			t.Errorf("Expected %s %s on a slow host, got %q", setting, value, got[setting])
+		}
+	}
+}
+
+func TestRecommendSkipsFailedMeasurements(t *testing.T) {
+	r := fastReport()
+	r.Navigation = Stats{Errors: 10, Error: "boom"}
+	r.PageCreation = Stats{}
+
+	got := recommendations(r)
+	if _, ok := got["timeouts.navigate_page"]; ok {
+		t.Error("Expected no navigation timeout without navigation samples")
+	}
+	if _, ok := got["--page-pool"]; ok {
+		t.Error("Expected no pool size without page creation samples")
+	}
+}
+
+func TestConfigSnippet(t *testing.T) {
+	snippet := ConfigSnippet(Recommend(fastReport()))
+
+	var config map[string]map[string]string
+	if err := json.Unmarshal([]byte(snippet), &config); err != nil {
+		t.Fatalf("Expected valid JSON, got %v:\n%s", err, snippet)
+	}
+	if config["timeouts"]["navigate_page"] != "30s" || config["browser_health"]["page_ping_timeout"] != "5s" {
+		t.Errorf("Unexpected config: %v", config)
+	}
+	if strings.Contains(snippet, "page-pool") {
+		t.Error("Expected flags to stay out of the config snippet")
+	}
+
+	if ConfigSnippet([]Recommendation{{Setting: "--page-pool", Value: "2"}}) != "" {
+		t.Error("Expected an empty snippet when only flags are recommended")
+	}
+}
+
+func TestFormatReportsFailures(t *testing.T) {
+	r := fastReport()
+	r.Eval = Stats{Errors: 10, Error: "context deadline exceeded"}
+	r.Recommendations = Recommend(r)
+
+	out := Format(r)
+	if !strings.Contains(out, "Eval roundtrip") || !strings.Contains(out, "failed: context deadline exceeded") {
+		t.Errorf("Expected the failed measurement to be reported, got:\n%s", out)
+	}
+	if !strings.Contains(out, "--page-pool 0") || !strings.Contains(out, `"navigate_page": "30s"`) {
+		t.Errorf("Expected recommendations and a config snippet, got:\n%s", out)
+	}
+}
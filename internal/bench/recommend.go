@@ -0,0 +1,132 @@
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+const (
+	// defaultCallTimeout is the server's limit for tool calls without a
+	// configured timeout; the benchmark page is local, so real sites need
+	// at least that much
+	defaultCallTimeout = 30 * time.Second
+	// timeoutHeadroom is how many times the p95 a timeout allows, for
+	// heavier pages and a host busy with other calls
+	timeoutHeadroom = 20
+	// pingHeadroom is the same for page pings, which are tiny scripts
+	pingHeadroom = 50
+	// poolThreshold is the median page creation time above which warm
+	// pages are worth keeping
+	poolThreshold = 100 * time.Millisecond
+	maxPoolSize   = 8
+)
+
+// Recommend suggests a page pool size and timeouts for the host r was
+// measured on. Timeouts never go below the server defaults.
+func Recommend(r *Report) []Recommendation {
+	var recs []Recommendation
+
+	if r.PageCreation.Samples > 0 {
+		median := formatDuration(r.PageCreation.Median)
+		if r.PageCreation.Median >= poolThreshold {
+			size := r.Concurrency
+			if size > r.Host.CPUs {
+				size = r.Host.CPUs
+			}
+			if size < 2 {
+				size = 2
+			}
+			if size > maxPoolSize {
+				size = maxPoolSize
+			}
+			recs = append(recs, Recommendation{
+				Setting: "--page-pool",
+				Value:   fmt.Sprint(size),
+				Reason:  fmt.Sprintf("pages take %s to open; warm pages spare screen_scrape that wait", median),
+			})
+		} else {
+			recs = append(recs, Recommendation{
+				Setting: "--page-pool",
+				Value:   "0",
+				Reason:  fmt.Sprintf("pages open in %s, so a pool would save little", median),
+			})
+		}
+	}
+
+	if r.Navigation.Samples > 0 {
+		recs = append(recs, timeoutRecommendation("timeouts.navigate_page", "navigation", r.Navigation.P95,
+			timeoutHeadroom, defaultCallTimeout, 5*time.Second))
+	}
+
+	var screenshotP95 time.Duration
+	for _, s := range []Stats{r.ScreenshotPNG, r.ScreenshotJPEG} {
+		if s.Samples > 0 && s.P95 > screenshotP95 {
+			screenshotP95 = s.P95
+		}
+	}
+	if screenshotP95 > 0 {
+		recs = append(recs, timeoutRecommendation("timeouts.take_screenshot", "viewport screenshot", screenshotP95,
+			timeoutHeadroom, defaultCallTimeout, 5*time.Second))
+	}
+
+	var slowest time.Duration
+	for _, s := range []Stats{r.Navigation, r.Eval, r.ScreenshotPNG, r.ScreenshotJPEG} {
+		if s.Samples > 0 && s.P95 > slowest {
+			slowest = s.P95
+		}
+	}
+	if slowest > 0 {
+		recs = append(recs, timeoutRecommendation("timeouts.default", "slowest operation", slowest,
+			timeoutHeadroom, defaultCallTimeout, 5*time.Second))
+	}
+
+	if r.Eval.Samples > 0 {
+		recs = append(recs, timeoutRecommendation("browser_health.page_ping_timeout", "eval roundtrip", r.Eval.P95,
+			pingHeadroom, browser.DefaultPagePingTimeout, time.Second))
+	}
+
+	return recs
+}
+
+// timeoutRecommendation allows headroom times p95, rounded up to step and
+// no less than floor
+func timeoutRecommendation(setting, what string, p95 time.Duration, headroom int, floor, step time.Duration) Recommendation {
+	value := p95 * time.Duration(headroom)
+	if rem := value % step; rem != 0 {
+		value += step - rem
+	}
+	reason := fmt.Sprintf("%s p95 is %s; %dx that is %s", what, formatDuration(p95), headroom, value)
+	if value <= floor {
+		value = floor
+		reason = fmt.Sprintf("%s p95 is %s, so the default %s is ample", what, formatDuration(p95), floor)
+	}
+	return Recommendation{Setting: setting, Value: value.String(), Reason: reason}
+}
+
+// ConfigSnippet renders the config file recommendations as the JSON to put
+// in a --config file; flags are left out
+func ConfigSnippet(recs []Recommendation) string {
+	config := make(map[string]map[string]string)
+	for _, rec := range recs {
+		section, key, ok := strings.Cut(rec.Setting, ".")
+		if !ok || strings.HasPrefix(rec.Setting, "-") {
+			continue
+		}
+		if config[section] == nil {
+			config[section] = make(map[string]string)
+		}
+		config[section][key] = rec.Value
+	}
+	if len(config) == 0 {
+		return ""
+	}
+	encoded, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return ""
+	}
+	return string(encoded)
+}
@@ -0,0 +1,150 @@
+package browser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	// MaxArchiveResourceSize skips cached resources larger than this when
+	// collecting a snapshot; they are linked rather than inlined
+	MaxArchiveResourceSize = 10 * 1024 * 1024
+	archiveTimeout         = 60 * time.Second
+)
+
+// archivedResourceTypes are the resource types worth inlining into a
+// snapshot; scripts are dropped from archives and documents, XHRs and
+// media streams aren't part of the rendered page
+var archivedResourceTypes = map[proto.NetworkResourceType]bool{
+	proto.NetworkResourceTypeStylesheet: true,
+	proto.NetworkResourceTypeImage:      true,
+	proto.NetworkResourceTypeFont:       true,
+}
+
+// PageResource is a subresource as the browser has it cached
+type PageResource struct {
+	MIMEType string
+	Content  []byte
+}
+
+// PageSnapshot is a page's current DOM and the stylesheets, images and
+// fonts it loaded
+type PageSnapshot struct {
+	URL     string
+	BaseURL string
+	Title   string
+	HTML    string
+	// Resources maps absolute URLs to their cached content
+	Resources map[string]PageResource
+}
+
+// serializeDocumentScript returns the live DOM, including the doctype and
+// form state that outerHTML alone leaves out
+const serializeDocumentScript = `() => {
+	// Copy form state into attributes on a clone so the page is untouched
+	const root = document.documentElement.cloneNode(true);
+	const fields = 'input, textarea, select option';
+	const live = document.documentElement.querySelectorAll(fields);
+	root.querySelectorAll(fields).forEach((el, i) => {
+		const source = live[i];
+		if (el.tagName === 'OPTION') {
+			el.toggleAttribute('selected', source.selected);
+		} else if (el.type === 'checkbox' || el.type === 'radio') {
+			el.toggleAttribute('checked', source.checked);
+		} else if (el.type !== 'password' && el.type !== 'file') {
+			if (el.tagName === 'TEXTAREA') el.textContent = source.value;
+			else el.setAttribute('value', source.value);
+		}
+	});
+	const doctype = document.doctype ? new XMLSerializer().serializeToString(document.doctype) + '\n' : '';
+	return {
+		url: document.URL,
+		base_url: document.baseURI,
+		title: document.title,
+		html: doctype + root.outerHTML,
+	};
+}`
+
+// MHTMLSnapshot captures the page and all its resources as a single MHTML
+// document, which Chromium-based browsers open offline
+func (m *Manager) MHTMLSnapshot(pageID string) (string, error) {
+	start := time.Now()
+	var data string
+	err := m.withPage(pageID, archiveTimeout, func(p *rod.Page) error {
+		result, err := proto.PageCaptureSnapshot{Format: proto.PageCaptureSnapshotFormatMhtml}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to capture snapshot: %w", err)
+		}
+		data = result.Data
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	m.logger.LogBrowserAction("mhtml_snapshot", pageID, time.Since(start).Milliseconds())
+	return data, nil
+}
+
+// Snapshot returns the page's serialized DOM together with the cached
+// content of the stylesheets, images and fonts its main frame loaded, for
+// building a self-contained copy. Resources the browser no longer has are
+// left out.
+func (m *Manager) Snapshot(pageID string) (*PageSnapshot, error) {
+	start := time.Now()
+	snapshot := &PageSnapshot{Resources: make(map[string]PageResource)}
+	err := m.withPage(pageID, archiveTimeout, func(p *rod.Page) error {
+		result, err := p.Eval(serializeDocumentScript)
+		if err != nil {
+			return fmt.Errorf("failed to serialize page: %w", err)
+		}
+		snapshot.URL = result.Value.Get("url").Str()
+		snapshot.BaseURL = result.Value.Get("base_url").Str()
+		snapshot.Title = result.Value.Get("title").Str()
+		snapshot.HTML = result.Value.Get("html").Str()
+
+		tree, err := proto.PageGetResourceTree{}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to list page resources: %w", err)
+		}
+		if tree.FrameTree == nil || tree.FrameTree.Frame == nil {
+			return nil
+		}
+		frameID := tree.FrameTree.Frame.ID
+		for _, res := range tree.FrameTree.Resources {
+			if !archivedResourceTypes[res.Type] || res.Failed || res.Canceled {
+				continue
+			}
+			if res.ContentSize != nil && *res.ContentSize > MaxArchiveResourceSize {
+				continue
+			}
+			if _, ok := snapshot.Resources[res.URL]; ok {
+				continue
+			}
+			content, err := proto.PageGetResourceContent{FrameID: frameID, URL: res.URL}.Call(p)
+			if err != nil {
+				// Evicted from the cache; the archive links to it instead
+				continue
+			}
+			data := []byte(content.Content)
+			if content.Base64Encoded {
+				if data, err = base64.StdEncoding.DecodeString(content.Content); err != nil {
+					continue
+				}
+			}
+			if len(data) > MaxArchiveResourceSize {
+				continue
+			}
+			snapshot.Resources[res.URL] = PageResource{MIMEType: res.MIMEType, Content: data}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.logger.LogBrowserAction("snapshot", pageID, time.Since(start).Milliseconds())
+	return snapshot, nil
+}
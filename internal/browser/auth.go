@@ -0,0 +1,241 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// maxAuthAttempts bounds the challenges remembered as already answered, so
+// a browser that runs for days does not grow the set without end
+const maxAuthAttempts = 1000
+
+// HTTPCredential is a username and password for one origin's HTTP Basic or
+// Digest authentication
+type HTTPCredential struct {
+	Origin   string `json:"origin"`
+	Username string `json:"username"`
+	Password string `json:"-"`
+}
+
+// authenticator answers the browser's authentication challenges: the
+// proxy's with the configured proxy credentials, and sites' with the
+// credentials registered for their origin
+type authenticator struct {
+	mutex sync.Mutex
+	sites map[string]HTTPCredential
+	// The browser the challenge handler is installed on
+	browser *rod.Browser
+	// Challenges already answered, by request and source; a repeat means
+	// the credentials were refused
+	attempted map[string]bool
+}
+
+func newAuthenticator() *authenticator {
+	return &authenticator{
+		sites:     make(map[string]HTTPCredential),
+		attempted: make(map[string]bool),
+	}
+}
+
+// NormalizeOrigin reduces a URL or origin to scheme://host[:port], dropping
+// default ports, for matching authentication challenges
+func NormalizeOrigin(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		return "", fmt.Errorf("origin %q needs a scheme, e.g. https://%s", raw, raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid origin %q: %w", raw, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("origin %q must be http or https", raw)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return "", fmt.Errorf("origin %q has no host", raw)
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	if port == "" || scheme == "http" && port == "80" || scheme == "https" && port == "443" {
+		return scheme + "://" + host, nil
+	}
+	return scheme + "://" + host + ":" + port, nil
+}
+
+// SetHTTPCredentials registers credentials the browser answers origin's
+// Basic and Digest authentication challenges with, on every page, replacing
+// any registered for it before
+func (m *Manager) SetHTTPCredentials(origin, username, password string) error {
+	normalized, err := NormalizeOrigin(origin)
+	if err != nil {
+		return err
+	}
+	if username == "" {
+		return fmt.Errorf("username is required")
+	}
+
+	m.auth.mutex.Lock()
+	m.auth.sites[normalized] = HTTPCredential{Origin: normalized, Username: username, Password: password}
+	m.auth.mutex.Unlock()
+
+	m.mutex.RLock()
+	browser := m.browser
+	m.mutex.RUnlock()
+	if browser != nil {
+		m.installAuthHandler(browser)
+	}
+
+	m.logger.WithComponent("browser").Info("HTTP credentials registered",
+		zap.String("origin", normalized),
+		zap.String("username", username))
+	return nil
+}
+
+// RemoveHTTPCredentials forgets origin's credentials, reporting whether
+// there were any
+func (m *Manager) RemoveHTTPCredentials(origin string) (bool, error) {
+	normalized, err := NormalizeOrigin(origin)
+	if err != nil {
+		return false, err
+	}
+	m.auth.mutex.Lock()
+	defer m.auth.mutex.Unlock()
+	_, ok := m.auth.sites[normalized]
+	delete(m.auth.sites, normalized)
+	return ok, nil
+}
+
+// ClearHTTPCredentials forgets every origin's credentials
+func (m *Manager) ClearHTTPCredentials() int {
+	m.auth.mutex.Lock()
+	defer m.auth.mutex.Unlock()
+	n := len(m.auth.sites)
+	m.auth.sites = make(map[string]HTTPCredential)
+	return n
+}
+
+// HTTPCredentials lists the registered credentials by origin, without
+// their passwords
+func (m *Manager) HTTPCredentials() []HTTPCredential {
+	m.auth.mutex.Lock()
+	defer m.auth.mutex.Unlock()
+	list := make([]HTTPCredential, 0, len(m.auth.sites))
+	for _, c := range m.auth.sites {
+		list = append(list, HTTPCredential{Origin: c.Origin, Username: c.Username})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Origin < list[j].Origin })
+	return list
+}
+
+// authChallengeResponse decides how to answer a challenge: with the proxy
+// credentials for the proxy, with the origin's credentials for a site, and
+// with the browser's default handling otherwise or when the same request
+// was already answered and refused
+func (m *Manager) authChallengeResponse(e *proto.FetchAuthRequired) *proto.FetchAuthChallengeResponse {
+	deflt := &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseDefault}
+	if e.AuthChallenge == nil {
+		return deflt
+	}
+
+	var username, password string
+	if e.AuthChallenge.Source == proto.FetchAuthChallengeSourceProxy {
+		username, password = m.config.ProxyUsername, m.config.ProxyPassword
+	} else {
+		origin, err := NormalizeOrigin(e.AuthChallenge.Origin)
+		if err != nil {
+			return deflt
+		}
+		m.auth.mutex.Lock()
+		c, ok := m.auth.sites[origin]
+		m.auth.mutex.Unlock()
+		if !ok {
+			return deflt
+		}
+		username, password = c.Username, c.Password
+	}
+	if username == "" {
+		return deflt
+	}
+
+	key := string(e.RequestID) + " " + string(e.AuthChallenge.Source)
+	m.auth.mutex.Lock()
+	retry := m.auth.attempted[key]
+	if len(m.auth.attempted) >= maxAuthAttempts {
+		m.auth.attempted = make(map[string]bool)
+	}
+	m.auth.attempted[key] = true
+	m.auth.mutex.Unlock()
+
+	if retry {
+		m.logger.WithComponent("browser").Warn("Credentials were rejected",
+			zap.String("source", string(e.AuthChallenge.Source)),
+			zap.String("origin", e.AuthChallenge.Origin),
+			zap.String("username", username))
+		return &proto.FetchAuthChallengeResponse{Response: proto.FetchAuthChallengeResponseResponseCancelAuth}
+	}
+	return &proto.FetchAuthChallengeResponse{
+		Response: proto.FetchAuthChallengeResponseResponseProvideCredentials,
+		Username: username,
+		Password: password,
+	}
+}
+
+// installAuthHandler answers authentication challenges on browser for as
+// long as it runs. Every request then passes through the Fetch domain, so
+// the handler is only installed once there are proxy or site credentials,
+// and only once per browser.
+func (m *Manager) installAuthHandler(browser *rod.Browser) {
+	m.auth.mutex.Lock()
+	needed := m.config.ProxyUsername != "" || len(m.auth.sites) > 0
+	if !needed || m.auth.browser == browser {
+		m.auth.mutex.Unlock()
+		return
+	}
+	m.auth.browser = browser
+	m.auth.mutex.Unlock()
+
+	browser.EnableDomain("", &proto.FetchEnable{HandleAuthRequests: true})
+
+	wait := browser.Context(m.ctx).EachEvent(
+		func(e *proto.FetchRequestPaused) {
+			if err := (proto.FetchContinueRequest{RequestID: e.RequestID}).Call(browser); err != nil {
+				m.logger.WithComponent("browser").Debug("Failed to continue request",
+					zap.String("request_id", string(e.RequestID)),
+					zap.Error(err))
+			}
+		},
+		func(e *proto.FetchAuthRequired) {
+			response := m.authChallengeResponse(e)
+			if err := (proto.FetchContinueWithAuth{RequestID: e.RequestID, AuthChallengeResponse: response}).Call(browser); err != nil {
+				m.logger.WithComponent("browser").Debug("Failed to answer authentication challenge",
+					zap.String("request_id", string(e.RequestID)),
+					zap.Error(err))
+			}
+		},
+	)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Authentication handler stopped after panic",
+					zap.Any("panic", r))
+			}
+		}()
+		wait()
+	}()
+
+	m.logger.WithComponent("browser").Info("Answering authentication challenges",
+		zap.String("proxy", m.config.ProxyServer),
+		zap.Int("sites", len(m.HTTPCredentials())))
+}
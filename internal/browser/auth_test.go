@@ -0,0 +1,92 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestNormalizeOrigin(t *testing.T) {
+	cases := map[string]string{
+		"https://Jenkins.Internal:8443/job/build?x=1": "https://jenkins.internal:8443",
+		"https://example.com:443":                     "https://example.com",
+		"http://example.com:80/":                      "http://example.com",
+		"http://[::1]:8080":                           "http://[::1]:8080",
+	}
+	for raw, want := range cases {
+		if got, err := NormalizeOrigin(raw); err != nil || got != want {
+			t.Errorf("NormalizeOrigin(%q) = %q, %v; want %q", raw, got, err, want)
+		}
+	}
+	for _, bad := range []string{"example.com", "ftp://example.com", "https://"} {
+		if _, err := NormalizeOrigin(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestHTTPCredentialsRegistry(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	if err := manager.SetHTTPCredentials("https://staging.internal/login", "admin", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetHTTPCredentials("http://router.lan", "root", "pw"); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetHTTPCredentials("https://x.internal", "", "pw"); err == nil {
+		t.Error("Expected a missing username to be rejected")
+	}
+
+	list := manager.HTTPCredentials()
+	if len(list) != 2 || list[0].Origin != "http://router.lan" || list[1].Username != "admin" || list[1].Password != "" {
+		t.Errorf("HTTPCredentials = %+v", list)
+	}
+
+	if removed, err := manager.RemoveHTTPCredentials("https://staging.internal"); err != nil || !removed {
+		t.Errorf("RemoveHTTPCredentials = %v, %v", removed, err)
+	}
+	if removed, _ := manager.RemoveHTTPCredentials("https://staging.internal"); removed {
+		t.Error("Expected nothing left to remove")
+	}
+	if n := manager.ClearHTTPCredentials(); n != 1 || len(manager.HTTPCredentials()) != 0 {
+		t.Errorf("ClearHTTPCredentials = %d", n)
+	}
+}
+
+func TestAuthChallengeResponse(t *testing.T) {
+	manager := newQueueTestManager(t)
+	manager.config.ProxyUsername = "proxyuser"
+	manager.config.ProxyPassword = "proxypass"
+	if err := manager.SetHTTPCredentials("https://staging.internal", "admin", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := func(id, source, origin string) *proto.FetchAuthRequired {
+		return &proto.FetchAuthRequired{
+			RequestID:     proto.FetchRequestID(id),
+			AuthChallenge: &proto.FetchAuthChallenge{Source: proto.FetchAuthChallengeSource(source), Origin: origin, Scheme: "basic"},
+		}
+	}
+
+	resp := manager.authChallengeResponse(challenge("1", "Server", "https://staging.internal:443"))
+	if resp.Response != proto.FetchAuthChallengeResponseResponseProvideCredentials || resp.Username != "admin" || resp.Password != "hunter2" {
+		t.Errorf("site challenge = %+v", resp)
+	}
+	// The same request challenged again means the password was wrong
+	resp = manager.authChallengeResponse(challenge("1", "Server", "https://staging.internal"))
+	if resp.Response != proto.FetchAuthChallengeResponseResponseCancelAuth {
+		t.Errorf("repeated challenge = %+v", resp)
+	}
+
+	resp = manager.authChallengeResponse(challenge("2", "Proxy", "http://proxy.corp:3128"))
+	if resp.Response != proto.FetchAuthChallengeResponseResponseProvideCredentials || resp.Username != "proxyuser" {
+		t.Errorf("proxy challenge = %+v", resp)
+	}
+
+	// Credentials never go to an origin they were not registered for
+	resp = manager.authChallengeResponse(challenge("3", "Server", "https://evil.example"))
+	if resp.Response != proto.FetchAuthChallengeResponseResponseDefault || resp.Username != "" {
+		t.Errorf("unknown origin = %+v", resp)
+	}
+}
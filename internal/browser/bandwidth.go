@@ -0,0 +1,111 @@
+package browser
+
+import (
+	"sync"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/bandwidth"
+)
+
+// bandwidthTracker feeds the browser's traffic into a bandwidth.Meter. It
+// keeps its own record of requests in flight, since the request log is
+// cleared on every navigation and bounded in size.
+type bandwidthTracker struct {
+	mutex sync.Mutex
+	meter *bandwidth.Meter
+	// Host of each request in flight, by page and request ID
+	hosts map[string]map[proto.NetworkRequestID]string
+}
+
+func newBandwidthTracker() *bandwidthTracker {
+	return &bandwidthTracker{hosts: make(map[string]map[proto.NetworkRequestID]string)}
+}
+
+// SetBandwidthMeter counts the traffic of every page against meter from
+// now on; nil stops counting
+func (m *Manager) SetBandwidthMeter(meter *bandwidth.Meter) {
+	m.bandwidth.mutex.Lock()
+	m.bandwidth.meter = meter
+	m.bandwidth.mutex.Unlock()
+}
+
+// BandwidthMeter returns the meter set by SetBandwidthMeter, or nil
+func (m *Manager) BandwidthMeter() *bandwidth.Meter {
+	m.bandwidth.mutex.Lock()
+	defer m.bandwidth.mutex.Unlock()
+	return m.bandwidth.meter
+}
+
+// requestBytes estimates the bytes of a request as sent: the request line,
+// headers and body. The browser reports only what it received.
+func requestBytes(req *proto.NetworkRequest) int64 {
+	size := int64(len(req.Method) + len(req.URL) + len(" HTTP/1.1\r\n") + 2)
+	for key, value := range req.Headers {
+		size += int64(len(key) + len(value.String()) + 4)
+	}
+	if req.PostData != "" {
+		size += int64(len(req.PostData))
+	} else {
+		for _, entry := range req.PostDataEntries {
+			size += int64(len(entry.Bytes))
+		}
+	}
+	return size
+}
+
+func (t *bandwidthTracker) sent(pageID string, e *proto.NetworkRequestWillBeSent) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.meter == nil || e.Request == nil {
+		return
+	}
+	hosts, ok := t.hosts[pageID]
+	if !ok {
+		hosts = make(map[proto.NetworkRequestID]string)
+		t.hosts[pageID] = hosts
+	}
+	// A redirect reuses the request ID; the previous hop is done
+	if previous, ok := hosts[e.RequestID]; ok && e.RedirectResponse != nil {
+		t.meter.Record(previous, 0, int64(e.RedirectResponse.EncodedDataLength))
+	}
+	host := bandwidth.Host(e.Request.URL)
+	hosts[e.RequestID] = host
+	t.meter.Record(host, requestBytes(e.Request), 0)
+}
+
+func (t *bandwidthTracker) finished(pageID string, id proto.NetworkRequestID, received float64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	host, ok := t.hosts[pageID][id]
+	if !ok {
+		return
+	}
+	delete(t.hosts[pageID], id)
+	if t.meter != nil {
+		t.meter.Record(host, 0, int64(received))
+	}
+}
+
+func (t *bandwidthTracker) forget(pageID string) {
+	t.mutex.Lock()
+	delete(t.hosts, pageID)
+	t.mutex.Unlock()
+}
+
+// bandwidthEventHandlers returns the CDP callbacks that count a page's
+// traffic; they run on the page's event watcher
+func (m *Manager) bandwidthEventHandlers(pageID string) []interface{} {
+	t := m.bandwidth
+	return []interface{}{
+		func(e *proto.NetworkRequestWillBeSent) {
+			t.sent(pageID, e)
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			t.finished(pageID, e.RequestID, e.EncodedDataLength)
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			t.finished(pageID, e.RequestID, 0)
+		},
+	}
+}
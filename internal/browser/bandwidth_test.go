@@ -0,0 +1,47 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+
+	"rodmcp/internal/bandwidth"
+)
+
+func TestBandwidthTrackerCountsRequestsAndRedirects(t *testing.T) {
+	meter := bandwidth.NewMeter()
+	tracker := newBandwidthTracker()
+	tracker.meter = meter
+
+	request := &proto.NetworkRequest{
+		URL:      "http://example.com/old",
+		Method:   "POST",
+		Headers:  proto.NetworkHeaders{"Accept": gson.New("*/*")},
+		PostData: "q=1",
+	}
+	tracker.sent("page", &proto.NetworkRequestWillBeSent{RequestID: "1", Request: request})
+	tracker.sent("page", &proto.NetworkRequestWillBeSent{
+		RequestID:        "1",
+		Request:          &proto.NetworkRequest{URL: "https://www.example.com/new", Method: "GET"},
+		RedirectResponse: &proto.NetworkResponse{EncodedDataLength: 300},
+	})
+	tracker.finished("page", "1", 2000)
+	// Unknown and repeated finishes are ignored
+	tracker.finished("page", "1", 2000)
+	tracker.finished("other", "9", 50)
+
+	stats := meter.Stats()
+	old := stats.Domains["example.com"]
+	if old.Requests != 1 || old.BytesReceived != 300 || old.BytesSent != requestBytes(request) {
+		t.Errorf("example.com = %+v", old)
+	}
+	if www := stats.Domains["www.example.com"]; www.Requests != 1 || www.BytesReceived != 2000 {
+		t.Errorf("www.example.com = %+v", www)
+	}
+
+	tracker.forget("page")
+	if len(tracker.hosts) != 0 {
+		t.Errorf("hosts = %v", tracker.hosts)
+	}
+}
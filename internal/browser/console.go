@@ -0,0 +1,253 @@
+package browser
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// MaxConsoleEntries bounds each page's console log; the oldest entries
+// are overwritten first
+const MaxConsoleEntries = 1000
+
+// Console entry levels, least to most severe
+const (
+	ConsoleLevelDebug   = "debug"
+	ConsoleLevelInfo    = "info"
+	ConsoleLevelWarning = "warning"
+	ConsoleLevelError   = "error"
+)
+
+// ConsoleLevels lists the entry levels, least to most severe
+var ConsoleLevels = []string{ConsoleLevelDebug, ConsoleLevelInfo, ConsoleLevelWarning, ConsoleLevelError}
+
+// ConsoleEntry is a console message, uncaught exception or browser log
+// message such as a failed resource load
+type ConsoleEntry struct {
+	Level string `json:"level"`
+	// Source is "console" for console API calls, "exception" for uncaught
+	// exceptions and rejections, and otherwise the browser's log source:
+	// "network", "security", "violation", "deprecation", ...
+	Source string `json:"source"`
+	// Type is the console method for console entries: log, warn, table, ...
+	Type      string    `json:"type,omitempty"`
+	Text      string    `json:"text"`
+	URL       string    `json:"url,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	Column    int       `json:"column,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ConsoleLevelRank orders levels by severity; unknown levels rank lowest
+func ConsoleLevelRank(level string) int {
+	for i, l := range ConsoleLevels {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// consoleRing is a fixed-capacity buffer of one page's entries since its
+// last top-level navigation
+type consoleRing struct {
+	entries []ConsoleEntry
+	next    int
+	dropped int
+}
+
+func (r *consoleRing) add(entry ConsoleEntry) {
+	if len(r.entries) < MaxConsoleEntries {
+		r.entries = append(r.entries, entry)
+		return
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % MaxConsoleEntries
+	r.dropped++
+}
+
+// ordered returns the entries oldest first
+func (r *consoleRing) ordered() []ConsoleEntry {
+	out := make([]ConsoleEntry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// consoleRecorder keeps the console log of every managed page
+type consoleRecorder struct {
+	mutex sync.Mutex
+	pages map[string]*consoleRing
+}
+
+func newConsoleRecorder() *consoleRecorder {
+	return &consoleRecorder{pages: make(map[string]*consoleRing)}
+}
+
+func (c *consoleRecorder) add(pageID string, entry ConsoleEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ring, ok := c.pages[pageID]
+	if !ok {
+		ring = &consoleRing{}
+		c.pages[pageID] = ring
+	}
+	ring.add(entry)
+}
+
+func (c *consoleRecorder) reset(pageID string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	delete(c.pages, pageID)
+}
+
+func (c *consoleRecorder) snapshot(pageID string) ([]ConsoleEntry, int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	ring, ok := c.pages[pageID]
+	if !ok {
+		return nil, 0
+	}
+	return ring.ordered(), ring.dropped
+}
+
+// remoteObjectsText joins console arguments the way the devtools console
+// prints them: strings as-is, objects by their description
+func remoteObjectsText(args []*proto.RuntimeRemoteObject) string {
+	parts := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch {
+		case arg == nil:
+		case arg.Description != "":
+			parts = append(parts, arg.Description)
+		case arg.Type == proto.RuntimeRemoteObjectTypeUndefined:
+			parts = append(parts, "undefined")
+		default:
+			parts = append(parts, arg.Value.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// runtimeTime converts a CDP timestamp, in milliseconds since the epoch
+func runtimeTime(t proto.RuntimeTimestamp) time.Time {
+	if t <= 0 {
+		return time.Now()
+	}
+	return time.UnixMicro(int64(float64(t) * 1000))
+}
+
+// topFrame returns the location of the innermost stack frame; CDP lines
+// and columns are 0-based, entries are 1-based like devtools
+func topFrame(stack *proto.RuntimeStackTrace) (url string, line, column int) {
+	if stack == nil || len(stack.CallFrames) == 0 {
+		return "", 0, 0
+	}
+	frame := stack.CallFrames[0]
+	return frame.URL, frame.LineNumber + 1, frame.ColumnNumber + 1
+}
+
+func consoleAPIEntry(e *proto.RuntimeConsoleAPICalled) ConsoleEntry {
+	level := ConsoleLevelInfo
+	switch e.Type {
+	case proto.RuntimeConsoleAPICalledTypeDebug:
+		level = ConsoleLevelDebug
+	case proto.RuntimeConsoleAPICalledTypeWarning:
+		level = ConsoleLevelWarning
+	case proto.RuntimeConsoleAPICalledTypeError, proto.RuntimeConsoleAPICalledTypeAssert:
+		level = ConsoleLevelError
+	}
+	entry := ConsoleEntry{
+		Level:     level,
+		Source:    "console",
+		Type:      string(e.Type),
+		Text:      remoteObjectsText(e.Args),
+		Timestamp: runtimeTime(e.Timestamp),
+	}
+	entry.URL, entry.Line, entry.Column = topFrame(e.StackTrace)
+	return entry
+}
+
+func exceptionEntry(e *proto.RuntimeExceptionThrown) ConsoleEntry {
+	details := e.ExceptionDetails
+	text := details.Text
+	if details.Exception != nil && details.Exception.Description != "" {
+		text = details.Exception.Description
+	}
+	return ConsoleEntry{
+		Level:     ConsoleLevelError,
+		Source:    "exception",
+		Text:      text,
+		URL:       details.URL,
+		Line:      details.LineNumber + 1,
+		Column:    details.ColumnNumber + 1,
+		Timestamp: runtimeTime(e.Timestamp),
+	}
+}
+
+func logEntry(e *proto.LogLogEntry) ConsoleEntry {
+	level := string(e.Level)
+	if e.Level == proto.LogLogEntryLevelVerbose {
+		level = ConsoleLevelDebug
+	}
+	entry := ConsoleEntry{
+		Level:     level,
+		Source:    string(e.Source),
+		Text:      e.Text,
+		URL:       e.URL,
+		Timestamp: runtimeTime(e.Timestamp),
+	}
+	if e.LineNumber != nil {
+		entry.Line = *e.LineNumber + 1
+	}
+	return entry
+}
+
+// consoleEventHandlers returns the CDP callbacks that feed a page's
+// console log; they run on the page's event watcher
+func (m *Manager) consoleEventHandlers(pageID string) []interface{} {
+	c := m.console
+	return []interface{}{
+		func(e *proto.PageFrameNavigated) {
+			// A new top-level document starts a fresh log
+			if e.Frame != nil && e.Frame.ParentID == "" {
+				c.reset(pageID)
+			}
+		},
+		func(e *proto.RuntimeConsoleAPICalled) {
+			c.add(pageID, consoleAPIEntry(e))
+		},
+		func(e *proto.RuntimeExceptionThrown) {
+			if e.ExceptionDetails != nil {
+				c.add(pageID, exceptionEntry(e))
+			}
+		},
+		func(e *proto.LogEntryAdded) {
+			if e.Entry != nil {
+				c.add(pageID, logEntry(e.Entry))
+			}
+		},
+	}
+}
+
+// ConsoleLogs returns a page's console messages, uncaught exceptions and
+// browser log messages since its last top-level navigation, oldest first,
+// and how many older entries were dropped to stay within the log's
+// capacity
+func (m *Manager) ConsoleLogs(pageID string) ([]ConsoleEntry, int, error) {
+	if _, err := m.GetPage(pageID); err != nil {
+		return nil, 0, err
+	}
+	entries, dropped := m.console.snapshot(pageID)
+	return entries, dropped, nil
+}
+
+// ClearConsoleLogs empties a page's console log
+func (m *Manager) ClearConsoleLogs(pageID string) error {
+	if _, err := m.GetPage(pageID); err != nil {
+		return err
+	}
+	m.console.reset(pageID)
+	return nil
+}
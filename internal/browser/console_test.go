@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+func TestConsoleRing(t *testing.T) {
+	var r consoleRing
+	for i := 0; i < MaxConsoleEntries+3; i++ {
+		r.add(ConsoleEntry{Line: i})
+	}
+	entries := r.ordered()
+	if len(entries) != MaxConsoleEntries || r.dropped != 3 {
+		t.Fatalf("got %d entries, %d dropped", len(entries), r.dropped)
+	}
+	if entries[0].Line != 3 || entries[len(entries)-1].Line != MaxConsoleEntries+2 {
+		t.Errorf("entries out of order: first %d, last %d", entries[0].Line, entries[len(entries)-1].Line)
+	}
+}
+
+func TestConsoleEntries(t *testing.T) {
+	warn := consoleAPIEntry(&proto.RuntimeConsoleAPICalled{
+		Type: proto.RuntimeConsoleAPICalledTypeWarning,
+		Args: []*proto.RuntimeRemoteObject{
+			{Type: proto.RuntimeRemoteObjectTypeString, Value: gson.New("slow response:")},
+			{Type: proto.RuntimeRemoteObjectTypeNumber, Value: gson.New(1200), Description: "1200"},
+			{Type: proto.RuntimeRemoteObjectTypeUndefined},
+		},
+		Timestamp:  1700000000000,
+		StackTrace: &proto.RuntimeStackTrace{CallFrames: []*proto.RuntimeCallFrame{{URL: "https://example.com/app.js", LineNumber: 9, ColumnNumber: 4}}},
+	})
+	if warn.Level != ConsoleLevelWarning || warn.Text != "slow response: 1200 undefined" || warn.Type != "warning" {
+		t.Errorf("console entry = %+v", warn)
+	}
+	if warn.Line != 10 || warn.Column != 5 || warn.Timestamp.Unix() != 1700000000 {
+		t.Errorf("console entry location = %+v", warn)
+	}
+
+	exception := exceptionEntry(&proto.RuntimeExceptionThrown{ExceptionDetails: &proto.RuntimeExceptionDetails{
+		Text:      "Uncaught",
+		Exception: &proto.RuntimeRemoteObject{Description: "TypeError: x is undefined"},
+		URL:       "https://example.com/app.js",
+	}})
+	if exception.Level != ConsoleLevelError || exception.Text != "TypeError: x is undefined" || exception.Line != 1 {
+		t.Errorf("exception entry = %+v", exception)
+	}
+
+	failed := logEntry(&proto.LogLogEntry{
+		Source: proto.LogLogEntrySourceNetwork,
+		Level:  proto.LogLogEntryLevelError,
+		Text:   "Failed to load resource: the server responded with a status of 404 ()",
+		URL:    "https://example.com/missing.png",
+	})
+	if failed.Source != "network" || failed.Level != ConsoleLevelError || failed.Line != 0 {
+		t.Errorf("log entry = %+v", failed)
+	}
+	if verbose := logEntry(&proto.LogLogEntry{Level: proto.LogLogEntryLevelVerbose}); verbose.Level != ConsoleLevelDebug {
+		t.Errorf("verbose mapped to %q", verbose.Level)
+	}
+}
+
+func TestConsoleLogsUnknownPage(t *testing.T) {
+	m := NewManager(createTestLogger(t), Config{Headless: true})
+	if _, _, err := m.ConsoleLogs("nope"); err == nil {
+		t.Error("expected an error for an unknown page")
+	}
+}
@@ -0,0 +1,117 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const cookieTimeout = 10 * time.Second
+
+// withPage runs fn on the page with the page held and timeout set. Cookies
+// belong to the browser, but the page is where the CDP calls go.
+func (m *Manager) withPage(pageID string, timeout time.Duration, fn func(p *rod.Page) error) error {
+	return m.withPageContext(context.Background(), pageID, timeout, fn)
+}
+
+// withPageContext is withPage with the wait for the page and fn also
+// bounded by ctx's deadline
+func (m *Manager) withPageContext(ctx context.Context, pageID string, timeout time.Duration, fn func(p *rod.Page) error) error {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	stepCtx, cancel := stepContext(ctx, timeout)
+	defer cancel()
+	return deadlineError(ctx, fn(page.Context(stepCtx)))
+}
+
+// Cookies returns the cookies the browser would send to urls, or to the
+// page's current URL when urls is empty
+func (m *Manager) Cookies(pageID string, urls []string) ([]*proto.NetworkCookie, error) {
+	start := time.Now()
+	var cookies []*proto.NetworkCookie
+	err := m.withPage(pageID, cookieTimeout, func(p *rod.Page) error {
+		result, err := proto.NetworkGetCookies{Urls: urls}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to read cookies: %w", err)
+		}
+		cookies = result.Cookies
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.logger.LogBrowserAction("cookies_read", pageID, time.Since(start).Milliseconds())
+	return cookies, nil
+}
+
+// SetCookies stores cookies in the browser. Cookies with neither a URL nor
+// a domain are set for the page's current URL.
+func (m *Manager) SetCookies(pageID string, cookies []*proto.NetworkCookieParam) error {
+	start := time.Now()
+	err := m.withPage(pageID, cookieTimeout, func(p *rod.Page) error {
+		for _, cookie := range cookies {
+			if cookie.URL != "" || cookie.Domain != "" {
+				continue
+			}
+			info, err := p.Info()
+			if err != nil {
+				return fmt.Errorf("failed to read page URL: %w", err)
+			}
+			cookie.URL = info.URL
+		}
+		if err := (proto.NetworkSetCookies{Cookies: cookies}).Call(p); err != nil {
+			return fmt.Errorf("failed to set cookies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.LogBrowserAction("cookies_set", pageID, time.Since(start).Milliseconds())
+	return nil
+}
+
+// DeleteCookies deletes the cookies named in req that match its URL, domain
+// and path, whichever are set
+func (m *Manager) DeleteCookies(pageID string, req proto.NetworkDeleteCookies) error {
+	start := time.Now()
+	err := m.withPage(pageID, cookieTimeout, func(p *rod.Page) error {
+		if err := req.Call(p); err != nil {
+			return fmt.Errorf("failed to delete cookies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.LogBrowserAction("cookies_deleted", pageID, time.Since(start).Milliseconds())
+	return nil
+}
+
+// ClearCookies removes every cookie in the browser
+func (m *Manager) ClearCookies(pageID string) error {
+	start := time.Now()
+	err := m.withPage(pageID, cookieTimeout, func(p *rod.Page) error {
+		if err := (proto.NetworkClearBrowserCookies{}).Call(p); err != nil {
+			return fmt.Errorf("failed to clear cookies: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	m.logger.LogBrowserAction("cookies_cleared", pageID, time.Since(start).Milliseconds())
+	return nil
+}
@@ -0,0 +1,42 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Operations with a Context variant take the deadline of the tool call
+// they run for. Each step keeps its own limit (queue wait, navigation,
+// script) but none outlives the caller's deadline, so the limits bound the
+// call together instead of adding up.
+
+// stepContext bounds one step of an operation to limit and to the caller's
+// deadline, whichever comes first
+func stepContext(ctx context.Context, limit time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, limit)
+}
+
+// checkDeadline fails an operation the caller has no time left for before
+// it takes the page
+func checkDeadline(ctx context.Context) error {
+	if ctx == nil || ctx.Err() == nil {
+		return nil
+	}
+	return deadlineError(ctx, ctx.Err())
+}
+
+// deadlineError says so when it was the caller's deadline, rather than the
+// step's own limit, that cut a step short
+func deadlineError(ctx context.Context, err error) error {
+	if err == nil || ctx == nil || ctx.Err() == nil {
+		return err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%w (the call ran out of time)", err)
+	}
+	return fmt.Errorf("%w (the call was cancelled)", err)
+}
@@ -0,0 +1,103 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+
+	"github.com/go-rod/rod"
+)
+
+func TestStepContextKeepsCallerDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	step, stepCancel := stepContext(parent, time.Hour)
+	defer stepCancel()
+	deadline, ok := step.Deadline()
+	if !ok || time.Until(deadline) > time.Second {
+		t.Errorf("A step must not outlive the caller's deadline, got %v", deadline)
+	}
+
+	step, stepCancel = stepContext(context.Background(), 20*time.Millisecond)
+	defer stepCancel()
+	if deadline, ok := step.Deadline(); !ok || time.Until(deadline) > 20*time.Millisecond {
+		t.Errorf("A step should keep its own shorter limit, got %v", deadline)
+	}
+}
+
+func TestDeadlineError(t *testing.T) {
+	if err := deadlineError(context.Background(), nil); err != nil {
+		t.Errorf("deadlineError(nil) = %v", err)
+	}
+	stepErr := context.DeadlineExceeded
+	if err := deadlineError(context.Background(), stepErr); err != stepErr {
+		t.Errorf("A step's own timeout should pass through, got %v", err)
+	}
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-expired.Done()
+	if err := deadlineError(expired, stepErr); err == nil || !strings.Contains(err.Error(), "ran out of time") {
+		t.Errorf("deadlineError = %v", err)
+	}
+	if err := checkDeadline(expired); err == nil {
+		t.Error("Expected an expired call to be refused")
+	}
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if err := checkDeadline(cancelled); err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("checkDeadline = %v", err)
+	}
+}
+
+func TestPageWaitBoundedByCallDeadline(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+
+	release, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// A call with 50ms left gives up on a busy page then, not after the
+	// 30s queue timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := manager.acquirePageContext(ctx, "page_1"); err == nil || !strings.Contains(err.Error(), "ran out of time") {
+		t.Errorf("acquirePageContext = %v", err)
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("Waited %s for a busy page", waited)
+	}
+}
+
+func TestElementWaitBoundedByCallDeadline(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+	manager.pages["page_1"] = &rod.Page{}
+
+	release, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	// Element calls share the call's deadline instead of waiting out the
+	// queue timeout and then the element timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := manager.ClickContext(ctx, "page_1", nil, "#submit", ElementTimeout); err == nil || !strings.Contains(err.Error(), "ran out of time") {
+		t.Errorf("ClickContext = %v", err)
+	}
+	if waited := time.Since(start); waited > 5*time.Second {
+		t.Errorf("Waited %s for a busy page", waited)
+	}
+}
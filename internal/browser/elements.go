@@ -0,0 +1,303 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// ElementTimeout is how long element operations wait for a selector to
+// match when the caller gives no timeout
+const ElementTimeout = 10 * time.Second
+
+// withElement holds the page, waits up to timeout for selector (see
+// Selector for the forms it takes) inside frames and runs fn on the
+// element, logging action when it succeeds. The queue wait, the lookup and
+// fn all end by ctx's deadline.
+func (m *Manager) withElement(ctx context.Context, pageID string, frames FramePath, selector string, timeout time.Duration, action string, fn func(el *rod.Element) error) error {
+	start := time.Now()
+	if timeout <= 0 {
+		timeout = ElementTimeout
+	}
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	elCtx, cancel := stepContext(ctx, timeout)
+	defer cancel()
+
+	target, err := enterFrames(page.Context(elCtx), frames)
+	if err != nil {
+		return deadlineError(ctx, err)
+	}
+	el, err := sel.Find(target)
+	if err != nil {
+		return deadlineError(ctx, err)
+	}
+	if err := fn(el); err != nil {
+		if ctx.Err() != nil {
+			return deadlineError(ctx, err)
+		}
+		if elCtx.Err() != nil {
+			return fmt.Errorf("%s on %s timed out after %v: %w", action, selector, timeout, err)
+		}
+		return err
+	}
+
+	m.logger.LogBrowserAction(action, pageID, time.Since(start).Milliseconds())
+	return nil
+}
+
+// FindElement waits up to timeout for the first element matching selector.
+// The element is detached from the timeout, so callers can keep using it.
+func (m *Manager) FindElement(pageID, selector string, timeout time.Duration) (*rod.Element, error) {
+	var found *rod.Element
+	err := m.withElement(context.Background(), pageID, nil, selector, timeout, "element_found", func(el *rod.Element) error {
+		found = el.Context(context.Background())
+		return nil
+	})
+	return found, err
+}
+
+// Click scrolls the element matching selector inside frames into view,
+// waits until it can be clicked and clicks it with the left mouse button
+// through the CDP input domain, so pages see a trusted click
+func (m *Manager) Click(pageID string, frames FramePath, selector string, timeout time.Duration) error {
+	return m.ClickContext(context.Background(), pageID, frames, selector, timeout)
+}
+
+// ClickContext is Click finished by ctx's deadline
+func (m *Manager) ClickContext(ctx context.Context, pageID string, frames FramePath, selector string, timeout time.Duration) error {
+	return m.withElement(ctx, pageID, frames, selector, timeout, "element_clicked", func(el *rod.Element) error {
+		if err := el.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return fmt.Errorf("failed to click %s: %w", selector, err)
+		}
+		return nil
+	})
+}
+
+// Input focuses the element matching selector inside frames, waits until
+// it is enabled and writable and inserts text, firing input and change
+// events. When clear is set the existing value is replaced; otherwise text
+// is appended.
+func (m *Manager) Input(pageID string, frames FramePath, selector, text string, clear bool, timeout time.Duration) error {
+	return m.InputContext(context.Background(), pageID, frames, selector, text, clear, timeout)
+}
+
+// InputContext is Input finished by ctx's deadline
+func (m *Manager) InputContext(ctx context.Context, pageID string, frames FramePath, selector, text string, clear bool, timeout time.Duration) error {
+	return m.withElement(ctx, pageID, frames, selector, timeout, "element_input", func(el *rod.Element) error {
+		if clear {
+			if err := el.SelectAllText(); err != nil {
+				return fmt.Errorf("failed to select existing text in %s: %w", selector, err)
+			}
+		} else if _, err := el.Eval(`() => {
+			if (typeof this.setSelectionRange === 'function' && typeof this.value === 'string') {
+				try { this.setSelectionRange(this.value.length, this.value.length); } catch (e) {}
+			}
+		}`); err != nil {
+			return fmt.Errorf("failed to move the cursor to the end of %s: %w", selector, err)
+		}
+		if err := el.Input(text); err != nil {
+			return fmt.Errorf("failed to type into %s: %w", selector, err)
+		}
+		return nil
+	})
+}
+
+// Hover moves the mouse over the centre of the element matching selector,
+// so CSS :hover rules and mouse listeners fire as they would for a person
+func (m *Manager) Hover(pageID, selector string, timeout time.Duration) error {
+	return m.HoverContext(context.Background(), pageID, selector, timeout)
+}
+
+// HoverContext is Hover finished by ctx's deadline
+func (m *Manager) HoverContext(ctx context.Context, pageID, selector string, timeout time.Duration) error {
+	return m.withElement(ctx, pageID, nil, selector, timeout, "element_hovered", func(el *rod.Element) error {
+		if err := el.Hover(); err != nil {
+			return fmt.Errorf("failed to hover over %s: %w", selector, err)
+		}
+		return nil
+	})
+}
+
+// ElementText returns the visible text of the element matching selector
+// inside frames
+func (m *Manager) ElementText(pageID string, frames FramePath, selector string, timeout time.Duration) (string, error) {
+	return m.ElementTextContext(context.Background(), pageID, frames, selector, timeout)
+}
+
+// ElementTextContext is ElementText finished by ctx's deadline
+func (m *Manager) ElementTextContext(ctx context.Context, pageID string, frames FramePath, selector string, timeout time.Duration) (string, error) {
+	var text string
+	err := m.withElement(ctx, pageID, frames, selector, timeout, "element_text", func(el *rod.Element) error {
+		var err error
+		if text, err = el.Text(); err != nil {
+			return fmt.Errorf("failed to read text of %s: %w", selector, err)
+		}
+		return nil
+	})
+	return text, err
+}
+
+// ElementAttribute returns the named attribute of the element matching
+// selector, and whether the element has it
+func (m *Manager) ElementAttribute(pageID, selector, name string, timeout time.Duration) (string, bool, error) {
+	return m.ElementAttributeContext(context.Background(), pageID, selector, name, timeout)
+}
+
+// ElementAttributeContext is ElementAttribute finished by ctx's deadline
+func (m *Manager) ElementAttributeContext(ctx context.Context, pageID, selector, name string, timeout time.Duration) (string, bool, error) {
+	var value *string
+	err := m.withElement(ctx, pageID, nil, selector, timeout, "element_attribute", func(el *rod.Element) error {
+		var err error
+		if value, err = el.Attribute(name); err != nil {
+			return fmt.Errorf("failed to read attribute %s of %s: %w", name, selector, err)
+		}
+		return nil
+	})
+	if err != nil || value == nil {
+		return "", false, err
+	}
+	return *value, true, nil
+}
+
+// stableSampleInterval is how long ElementState watches an element's shape
+// before calling it stable
+const stableSampleInterval = 100 * time.Millisecond
+
+// ElementRect is an element's bounding box in CSS pixels, relative to the
+// viewport
+type ElementRect struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// ElementState holds the checks Rod makes before acting on an element.
+// Interactable means a click at the element's centre would reach it right
+// now, without scrolling; BlockedBy says why not when it is false.
+type ElementState struct {
+	Visible      bool         `json:"visible"`
+	Enabled      bool         `json:"enabled"`
+	Stable       bool         `json:"stable"`
+	Focused      bool         `json:"focused"`
+	InViewport   bool         `json:"in_viewport"`
+	Interactable bool         `json:"interactable"`
+	BlockedBy    string       `json:"blocked_by,omitempty"`
+	Rect         *ElementRect `json:"rect,omitempty"`
+}
+
+// ElementState reads the state of the element matching selector through
+// Rod's element APIs and the CDP DOM domain, without scrolling or focusing
+// it, so the result matches what click and type will find
+func (m *Manager) ElementState(pageID, selector string, timeout time.Duration) (*ElementState, error) {
+	return m.ElementStateContext(context.Background(), pageID, selector, timeout)
+}
+
+// ElementStateContext is ElementState finished by ctx's deadline
+func (m *Manager) ElementStateContext(ctx context.Context, pageID, selector string, timeout time.Duration) (*ElementState, error) {
+	var state *ElementState
+	err := m.withElement(ctx, pageID, nil, selector, timeout, "element_state", func(el *rod.Element) error {
+		var err error
+		if state, err = readElementState(el); err != nil {
+			return fmt.Errorf("failed to read state of %s: %w", selector, err)
+		}
+		return nil
+	})
+	return state, err
+}
+
+func readElementState(el *rod.Element) (*ElementState, error) {
+	state := &ElementState{}
+	var err error
+	if state.Visible, err = el.Visible(); err != nil {
+		return nil, err
+	}
+	disabled, err := el.Disabled()
+	if err != nil {
+		return nil, err
+	}
+	state.Enabled = !disabled
+	if state.Focused, err = el.Matches(":focus"); err != nil {
+		return nil, err
+	}
+
+	// Elements that are not rendered have no content quads; that is a
+	// state, not a failure
+	shape, err := el.Shape()
+	if err != nil || len(shape.Quads) == 0 {
+		state.BlockedBy = "element is not rendered"
+		return state, nil
+	}
+	box := shape.Box()
+	state.Rect = &ElementRect{X: box.X, Y: box.Y, Width: box.Width, Height: box.Height}
+
+	metrics, err := proto.PageGetLayoutMetrics{}.Call(el.Page().Context(el.GetContext()))
+	if err != nil {
+		return nil, err
+	}
+	if vp := metrics.CSSLayoutViewport; vp != nil {
+		state.InViewport = rectInViewport(state.Rect, float64(vp.ClientWidth), float64(vp.ClientHeight))
+	}
+
+	time.Sleep(stableSampleInterval)
+	if later, err := el.Shape(); err == nil {
+		state.Stable = reflect.DeepEqual(shape.Quads, later.Quads)
+	}
+
+	if _, err := el.Interactable(); err == nil {
+		state.Interactable = true
+	} else if reason := interactableBlocker(err); reason != "" {
+		state.BlockedBy = reason
+	} else {
+		return nil, err
+	}
+	return state, nil
+}
+
+// rectInViewport reports whether any part of rect lies inside a viewport of
+// the given size
+func rectInViewport(rect *ElementRect, width, height float64) bool {
+	return rect.Width > 0 && rect.Height > 0 &&
+		rect.X < width && rect.X+rect.Width > 0 &&
+		rect.Y < height && rect.Y+rect.Height > 0
+}
+
+// interactableBlocker describes why Rod's interactability check failed, or
+// returns "" when err is not one of its verdicts
+func interactableBlocker(err error) string {
+	var covered *rod.CoveredError
+	var invisible *rod.InvisibleShapeError
+	var noPointer *rod.NoPointerEventsError
+	switch {
+	case errors.As(err, &covered):
+		if covered.Element == nil {
+			return "covered by another element"
+		}
+		return "covered by " + covered.Element.String()
+	case errors.As(err, &invisible):
+		return "no visible area inside the viewport"
+	case errors.As(err, &noPointer):
+		return "pointer-events is none"
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-rod/rod"
+)
+
+func TestElementActionsRequireKnownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	if err := manager.Click("missing", nil, "#a", 0); err == nil {
+		t.Error("Expected Click on an unknown page to fail")
+	}
+	if _, err := manager.ElementText("missing", nil, "#a", 0); err == nil {
+		t.Error("Expected ElementText on an unknown page to fail")
+	}
+	if _, err := manager.FindElement("missing", "#a", 0); err == nil {
+		t.Error("Expected FindElement on an unknown page to fail")
+	}
+	if _, err := manager.ElementState("missing", "#a", 0); err == nil {
+		t.Error("Expected ElementState on an unknown page to fail")
+	}
+}
+
+func TestRectInViewport(t *testing.T) {
+	tests := []struct {
+		rect ElementRect
+		want bool
+	}{
+		{ElementRect{X: 10, Y: 10, Width: 50, Height: 20}, true},
+		{ElementRect{X: -40, Y: 10, Width: 50, Height: 20}, true},
+		{ElementRect{X: 10, Y: 600, Width: 50, Height: 20}, false},
+		{ElementRect{X: -60, Y: 10, Width: 50, Height: 20}, false},
+		{ElementRect{X: 10, Y: 10, Width: 0, Height: 20}, false},
+	}
+	for _, tt := range tests {
+		if got := rectInViewport(&tt.rect, 800, 600); got != tt.want {
+			t.Errorf("rectInViewport(%+v) = %v, want %v", tt.rect, got, tt.want)
+		}
+	}
+}
+
+func TestInteractableBlocker(t *testing.T) {
+	if got := interactableBlocker(fmt.Errorf("click: %w", &rod.NoPointerEventsError{})); got != "pointer-events is none" {
+		t.Errorf("pointer-events blocker = %q", got)
+	}
+	if got := interactableBlocker(&rod.CoveredError{}); got != "covered by another element" {
+		t.Errorf("covered blocker = %q", got)
+	}
+	if got := interactableBlocker(errors.New("context deadline exceeded")); got != "" {
+		t.Errorf("other errors should not be blockers, got %q", got)
+	}
+}
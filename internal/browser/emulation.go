@@ -0,0 +1,249 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// DeviceEmulation is the device a page presents itself as: the size of its
+// viewport in CSS pixels, how many device pixels each of those is, whether
+// it behaves like a phone and takes touch input, and its user agent
+type DeviceEmulation struct {
+	Name              string  `json:"name,omitempty"`
+	Width             int     `json:"width"`
+	Height            int     `json:"height"`
+	DeviceScaleFactor float64 `json:"device_scale_factor"`
+	Mobile            bool    `json:"mobile"`
+	Touch             bool    `json:"touch"`
+	Landscape         bool    `json:"landscape,omitempty"`
+	// UserAgent replaces the browser's own when set
+	UserAgent string `json:"user_agent,omitempty"`
+}
+
+const (
+	iOSUserAgent     = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	iPadUserAgent    = "Mozilla/5.0 (iPad; CPU OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1"
+	androidUserAgent = "Mozilla/5.0 (Linux; Android 14; %s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36"
+)
+
+// deviceCatalog holds common devices in portrait orientation, keyed by
+// deviceKey of their names
+var deviceCatalog = map[string]DeviceEmulation{}
+
+func init() {
+	for _, d := range []DeviceEmulation{
+		{Name: "iPhone SE", Width: 375, Height: 667, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iOSUserAgent},
+		{Name: "iPhone 14", Width: 390, Height: 844, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iOSUserAgent},
+		{Name: "iPhone 14 Pro Max", Width: 430, Height: 932, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iOSUserAgent},
+		{Name: "iPhone 15 Pro", Width: 393, Height: 852, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: iOSUserAgent},
+		{Name: "Pixel 7", Width: 412, Height: 915, DeviceScaleFactor: 2.625, Mobile: true, Touch: true, UserAgent: fmt.Sprintf(androidUserAgent, "Pixel 7")},
+		{Name: "Pixel 8 Pro", Width: 448, Height: 998, DeviceScaleFactor: 2.25, Mobile: true, Touch: true, UserAgent: fmt.Sprintf(androidUserAgent, "Pixel 8 Pro")},
+		{Name: "Galaxy S23", Width: 360, Height: 780, DeviceScaleFactor: 3, Mobile: true, Touch: true, UserAgent: fmt.Sprintf(androidUserAgent, "SM-S911B")},
+		{Name: "iPad", Width: 810, Height: 1080, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUserAgent},
+		{Name: "iPad Mini", Width: 744, Height: 1133, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUserAgent},
+		{Name: "iPad Pro 12.9", Width: 1024, Height: 1366, DeviceScaleFactor: 2, Mobile: true, Touch: true, UserAgent: iPadUserAgent},
+		{Name: "Laptop", Width: 1366, Height: 768, DeviceScaleFactor: 1},
+		{Name: "Laptop HiDPI", Width: 1440, Height: 900, DeviceScaleFactor: 2},
+		{Name: "Desktop", Width: 1920, Height: 1080, DeviceScaleFactor: 1},
+	} {
+		deviceCatalog[deviceKey(d.Name)] = d
+	}
+}
+
+// deviceKey folds case, spaces and punctuation so "iphone-14" finds
+// "iPhone 14"
+func deviceKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// LookupDevice returns the catalog device called name
+func LookupDevice(name string) (DeviceEmulation, bool) {
+	d, ok := deviceCatalog[deviceKey(name)]
+	return d, ok
+}
+
+// DeviceNames lists the catalog's devices by name
+func DeviceNames() []string {
+	names := make([]string, 0, len(deviceCatalog))
+	for _, d := range deviceCatalog {
+		names = append(names, d.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Validate checks that the emulation describes a usable screen
+func (d *DeviceEmulation) Validate() error {
+	if d.Width < 100 || d.Width > 7680 || d.Height < 100 || d.Height > 7680 {
+		return fmt.Errorf("viewport must be between 100 and 7680 pixels on each side, not %dx%d", d.Width, d.Height)
+	}
+	if d.DeviceScaleFactor < 0.5 || d.DeviceScaleFactor > 5 {
+		return fmt.Errorf("device_scale_factor must be between 0.5 and 5, not %g", d.DeviceScaleFactor)
+	}
+	return nil
+}
+
+// viewport is the width and height, turned sideways for a portrait device
+// held in landscape
+func (d *DeviceEmulation) viewport() (int, int) {
+	if d.Landscape && d.Width < d.Height {
+		return d.Height, d.Width
+	}
+	return d.Width, d.Height
+}
+
+// EmulateDevice makes a page present itself as d, replacing any device it
+// emulated before. The viewport changes at once; pages that only read the
+// user agent or mobile flag when they load need reloading.
+func (m *Manager) EmulateDevice(pageID string, d *DeviceEmulation) error {
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	width, height := d.viewport()
+	orientation := &proto.EmulationScreenOrientation{Type: proto.EmulationScreenOrientationTypePortraitPrimary}
+	if width > height {
+		orientation = &proto.EmulationScreenOrientation{Type: proto.EmulationScreenOrientationTypeLandscapePrimary, Angle: 90}
+	}
+	metrics := proto.EmulationSetDeviceMetricsOverride{
+		Width:             width,
+		Height:            height,
+		DeviceScaleFactor: d.DeviceScaleFactor,
+		Mobile:            d.Mobile,
+		ScreenWidth:       &width,
+		ScreenHeight:      &height,
+		ScreenOrientation: orientation,
+	}
+	if err := metrics.Call(p); err != nil {
+		return fmt.Errorf("failed to set device metrics: %w", err)
+	}
+
+	touch := proto.EmulationSetTouchEmulationEnabled{Enabled: d.Touch}
+	if d.Touch {
+		points := 5
+		touch.MaxTouchPoints = &points
+	}
+	if err := touch.Call(p); err != nil {
+		return fmt.Errorf("failed to set touch emulation: %w", err)
+	}
+
+	m.emulationMutex.Lock()
+	previous := m.emulations[pageID]
+	m.emulationMutex.Unlock()
+	if d.UserAgent != "" || previous != nil && previous.UserAgent != "" {
+		if err := m.setUserAgent(pageID, p, d.UserAgent); err != nil {
+			return err
+		}
+	}
+
+	applied := *d
+	m.emulationMutex.Lock()
+	if m.emulations == nil {
+		m.emulations = make(map[string]*DeviceEmulation)
+	}
+	m.emulations[pageID] = &applied
+	m.emulationMutex.Unlock()
+
+	m.logger.WithComponent("browser").Debug("Device emulated",
+		zap.String("page_id", pageID),
+		zap.String("device", d.Name),
+		zap.Int("width", width),
+		zap.Int("height", height))
+	return nil
+}
+
+// ClearDeviceEmulation returns a page to the browser window's own size,
+// scale and user agent
+func (m *Manager) ClearDeviceEmulation(pageID string) error {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	if err := (proto.EmulationClearDeviceMetricsOverride{}).Call(p); err != nil {
+		return fmt.Errorf("failed to clear device metrics: %w", err)
+	}
+	if err := (proto.EmulationSetTouchEmulationEnabled{Enabled: false}).Call(p); err != nil {
+		return fmt.Errorf("failed to clear touch emulation: %w", err)
+	}
+	if previous := m.DeviceEmulation(pageID); previous != nil && previous.UserAgent != "" {
+		if err := m.setUserAgent(pageID, p, ""); err != nil {
+			return err
+		}
+	}
+	m.forgetDeviceEmulation(pageID)
+	return nil
+}
+
+// DeviceEmulation returns the device a page emulates, or nil
+func (m *Manager) DeviceEmulation(pageID string) *DeviceEmulation {
+	m.emulationMutex.Lock()
+	defer m.emulationMutex.Unlock()
+	if d := m.emulations[pageID]; d != nil {
+		clone := *d
+		return &clone
+	}
+	return nil
+}
+
+// setUserAgent overrides the page's user agent; an empty one restores the
+// browser's own. A user agent set with SetUserAgent wins over userAgent,
+// and the Accept-Language of any location emulation rides along, since
+// they all share one override.
+func (m *Manager) setUserAgent(pageID string, p proto.Client, userAgent string) error {
+	if override := m.UserAgentOverride(pageID); override != "" {
+		userAgent = override
+	}
+	if userAgent == "" {
+		version, err := proto.BrowserGetVersion{}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to read browser user agent: %w", err)
+		}
+		userAgent = version.UserAgent
+	}
+	if err := (proto.EmulationSetUserAgentOverride{UserAgent: userAgent, AcceptLanguage: m.acceptLanguage(pageID)}).Call(p); err != nil {
+		return fmt.Errorf("failed to set user agent: %w", err)
+	}
+	return nil
+}
+
+// forgetDeviceEmulation drops the bookkeeping for a page that is going away
+func (m *Manager) forgetDeviceEmulation(pageID string) {
+	m.emulationMutex.Lock()
+	delete(m.emulations, pageID)
+	m.emulationMutex.Unlock()
+}
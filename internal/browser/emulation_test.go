@@ -0,0 +1,63 @@
+package browser
+
+import "testing"
+
+func TestLookupDevice(t *testing.T) {
+	for _, name := range []string{"iPhone 14", "iphone-14", "IPHONE_14", "ipad pro 12.9"} {
+		if _, ok := LookupDevice(name); !ok {
+			t.Errorf("LookupDevice(%q) found nothing", name)
+		}
+	}
+	if _, ok := LookupDevice("Nokia 3310"); ok {
+		t.Error("Expected unknown device to be missing")
+	}
+	if names := DeviceNames(); len(names) != len(deviceCatalog) || names[0] > names[len(names)-1] {
+		t.Errorf("DeviceNames = %v", names)
+	}
+}
+
+func TestDeviceEmulationViewport(t *testing.T) {
+	phone, _ := LookupDevice("Pixel 7")
+	if err := phone.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if w, h := phone.viewport(); w != 412 || h != 915 {
+		t.Errorf("portrait viewport = %dx%d", w, h)
+	}
+	phone.Landscape = true
+	if w, h := phone.viewport(); w != 915 || h != 412 {
+		t.Errorf("landscape viewport = %dx%d", w, h)
+	}
+
+	// Screens that are already wide stay that way
+	desktop, _ := LookupDevice("Desktop")
+	desktop.Landscape = true
+	if w, h := desktop.viewport(); w != 1920 || h != 1080 {
+		t.Errorf("desktop viewport = %dx%d", w, h)
+	}
+
+	for _, bad := range []DeviceEmulation{
+		{Width: 50, Height: 800, DeviceScaleFactor: 1},
+		{Width: 400, Height: 10000, DeviceScaleFactor: 1},
+		{Width: 400, Height: 800, DeviceScaleFactor: 0},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestEmulateDeviceRequiresKnownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+	phone, _ := LookupDevice("iPhone 14")
+
+	if err := manager.EmulateDevice("missing", &phone); err == nil {
+		t.Error("Expected EmulateDevice on an unknown page to fail")
+	}
+	if err := manager.ClearDeviceEmulation("missing"); err == nil {
+		t.Error("Expected ClearDeviceEmulation on an unknown page to fail")
+	}
+	if d := manager.DeviceEmulation("missing"); d != nil {
+		t.Errorf("DeviceEmulation = %+v", d)
+	}
+}
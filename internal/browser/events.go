@@ -0,0 +1,397 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// PageEventType identifies a class of browser event that can be streamed to clients
+type PageEventType string
+
+const (
+	PageEventLoad         PageEventType = "load"
+	PageEventNavigation   PageEventType = "navigation"
+	PageEventDialog       PageEventType = "dialog"
+	PageEventDownload     PageEventType = "download"
+	PageEventConsoleError PageEventType = "console_error"
+)
+
+// AllPageEventTypes lists every event type that can be subscribed to
+var AllPageEventTypes = []PageEventType{
+	PageEventLoad,
+	PageEventNavigation,
+	PageEventDialog,
+	PageEventDownload,
+	PageEventConsoleError,
+}
+
+// PageEvent is a significant browser event emitted for a managed page
+type PageEvent struct {
+	PageID    string                 `json:"page_id"`
+	Type      PageEventType          `json:"type"`
+	URL       string                 `json:"url,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// PageEventHandler receives page events for subscribed pages
+type PageEventHandler func(PageEvent)
+
+// pageEventHub tracks per-page subscriptions and the active event sink
+type pageEventHub struct {
+	mutex         sync.RWMutex
+	handler       PageEventHandler
+	subscriptions map[string]map[PageEventType]bool
+	downloads     map[string]string             // download GUID -> page ID
+	watchers      map[string]context.CancelFunc // page ID -> stops that page's watcher
+	downloadWatch *rod.Browser                  // browser instance the download watcher is attached to
+}
+
+func newPageEventHub() *pageEventHub {
+	return &pageEventHub{
+		subscriptions: make(map[string]map[PageEventType]bool),
+		downloads:     make(map[string]string),
+		watchers:      make(map[string]context.CancelFunc),
+	}
+}
+
+// ParsePageEventType validates a user-supplied event type name
+func ParsePageEventType(name string) (PageEventType, error) {
+	for _, t := range AllPageEventTypes {
+		if string(t) == name {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown page event type %q", name)
+}
+
+// SetPageEventHandler sets the sink that receives subscribed page events
+func (m *Manager) SetPageEventHandler(handler PageEventHandler) {
+	m.events.mutex.Lock()
+	defer m.events.mutex.Unlock()
+	m.events.handler = handler
+}
+
+// SubscribePageEvents enables streaming of the given event types for a page.
+// An empty list subscribes to every event type.
+func (m *Manager) SubscribePageEvents(pageID string, eventTypes []PageEventType) error {
+	if _, err := m.GetPage(pageID); err != nil {
+		return err
+	}
+	if len(eventTypes) == 0 {
+		eventTypes = AllPageEventTypes
+	}
+
+	m.events.mutex.Lock()
+	subs, exists := m.events.subscriptions[pageID]
+	if !exists {
+		subs = make(map[PageEventType]bool)
+		m.events.subscriptions[pageID] = subs
+	}
+	wantDownloads := false
+	for _, t := range eventTypes {
+		subs[t] = true
+		if t == PageEventDownload {
+			wantDownloads = true
+		}
+	}
+	m.events.mutex.Unlock()
+
+	if wantDownloads {
+		m.ensureDownloadWatcher()
+	}
+
+	m.logger.WithComponent("browser").Info("Page event subscription updated",
+		zap.String("page_id", pageID),
+		zap.Int("event_types", len(subs)))
+	return nil
+}
+
+// UnsubscribePageEvents disables the given event types for a page.
+// An empty list removes every subscription for the page.
+func (m *Manager) UnsubscribePageEvents(pageID string, eventTypes []PageEventType) {
+	m.events.mutex.Lock()
+	defer m.events.mutex.Unlock()
+
+	if len(eventTypes) == 0 {
+		delete(m.events.subscriptions, pageID)
+		return
+	}
+	if subs, exists := m.events.subscriptions[pageID]; exists {
+		for _, t := range eventTypes {
+			delete(subs, t)
+		}
+		if len(subs) == 0 {
+			delete(m.events.subscriptions, pageID)
+		}
+	}
+}
+
+// PageEventSubscriptions returns the event types currently subscribed for a page
+func (m *Manager) PageEventSubscriptions(pageID string) []PageEventType {
+	m.events.mutex.RLock()
+	defer m.events.mutex.RUnlock()
+
+	var result []PageEventType
+	for _, t := range AllPageEventTypes {
+		if m.events.subscriptions[pageID][t] {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// emitPageEvent forwards an event to the handler if the page is subscribed to it
+func (m *Manager) emitPageEvent(event PageEvent) {
+	m.events.mutex.RLock()
+	handler := m.events.handler
+	subscribed := m.events.subscriptions[event.PageID][event.Type]
+	m.events.mutex.RUnlock()
+
+	if handler == nil || !subscribed {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.WithComponent("browser").Error("Page event handler panicked",
+				zap.String("page_id", event.PageID),
+				zap.Any("panic", r))
+		}
+	}()
+	handler(event)
+}
+
+// watchPageEvents listens for CDP events on a page until stopPageEvents is
+// called, and records the page's network requests and console output
+func (m *Manager) watchPageEvents(pageID string, page *rod.Page) {
+	ctx, cancel := context.WithCancel(m.ctx)
+	m.events.mutex.Lock()
+	m.events.watchers[pageID] = cancel
+	m.events.mutex.Unlock()
+
+	callbacks := []interface{}{
+		func(e *proto.PageLoadEventFired) {
+			m.emitPageEvent(PageEvent{PageID: pageID, Type: PageEventLoad, URL: m.trackedPageURL(pageID)})
+		},
+		func(e *proto.PageFrameNavigated) {
+			if e.Frame == nil || e.Frame.ParentID != "" {
+				return
+			}
+			m.mutex.Lock()
+			if _, exists := m.pages[pageID]; exists {
+				m.pageURLs[pageID] = e.Frame.URL
+			}
+			m.mutex.Unlock()
+			m.emitPageEvent(PageEvent{PageID: pageID, Type: PageEventNavigation, URL: e.Frame.URL})
+		},
+		func(e *proto.PageJavascriptDialogOpening) {
+			m.emitPageEvent(PageEvent{
+				PageID:  pageID,
+				Type:    PageEventDialog,
+				URL:     e.URL,
+				Message: e.Message,
+				Data: map[string]interface{}{
+					"dialog_type":    string(e.Type),
+					"default_prompt": e.DefaultPrompt,
+				},
+			})
+		},
+		func(e *proto.RuntimeConsoleAPICalled) {
+			if e.Type != proto.RuntimeConsoleAPICalledTypeError {
+				return
+			}
+			m.emitPageEvent(PageEvent{
+				PageID:  pageID,
+				Type:    PageEventConsoleError,
+				URL:     m.trackedPageURL(pageID),
+				Message: remoteObjectsText(e.Args),
+				Data:    map[string]interface{}{"source": "console"},
+			})
+		},
+		func(e *proto.RuntimeExceptionThrown) {
+			if e.ExceptionDetails == nil {
+				return
+			}
+			message := e.ExceptionDetails.Text
+			if e.ExceptionDetails.Exception != nil && e.ExceptionDetails.Exception.Description != "" {
+				message = e.ExceptionDetails.Exception.Description
+			}
+			m.emitPageEvent(PageEvent{
+				PageID:  pageID,
+				Type:    PageEventConsoleError,
+				URL:     e.ExceptionDetails.URL,
+				Message: message,
+				Data: map[string]interface{}{
+					"source": "exception",
+					"line":   e.ExceptionDetails.LineNumber,
+					"column": e.ExceptionDetails.ColumnNumber,
+				},
+			})
+		},
+	}
+	callbacks = append(callbacks, m.networkEventHandlers(pageID, page)...)
+	callbacks = append(callbacks, m.bandwidthEventHandlers(pageID)...)
+	callbacks = append(callbacks, m.captureEventHandlers(pageID)...)
+	callbacks = append(callbacks, m.consoleEventHandlers(pageID)...)
+	callbacks = append(callbacks, m.watchdogEventHandlers(pageID)...)
+	wait := page.Context(ctx).EachEvent(combineEventHandlers(callbacks)...)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Page event watcher stopped",
+					zap.String("page_id", pageID),
+					zap.Any("reason", r))
+			}
+		}()
+		wait()
+	}()
+}
+
+// combineEventHandlers merges callbacks for the same CDP event into one that
+// calls each in turn, since EachEvent keeps only the last callback it is
+// given for an event
+func combineEventHandlers(callbacks []interface{}) []interface{} {
+	byType := make(map[reflect.Type][]reflect.Value)
+	var order []reflect.Type
+	for _, cb := range callbacks {
+		fn := reflect.ValueOf(cb)
+		if _, seen := byType[fn.Type()]; !seen {
+			order = append(order, fn.Type())
+		}
+		byType[fn.Type()] = append(byType[fn.Type()], fn)
+	}
+
+	combined := make([]interface{}, 0, len(order))
+	for _, t := range order {
+		fns := byType[t]
+		if len(fns) == 1 {
+			combined = append(combined, fns[0].Interface())
+			continue
+		}
+		combined = append(combined, reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+			for _, fn := range fns {
+				fn.Call(args)
+			}
+			return nil
+		}).Interface())
+	}
+	return combined
+}
+
+// stopPageEvents ends the page's event watcher and drops its subscriptions
+func (m *Manager) stopPageEvents(pageID string) {
+	m.events.mutex.Lock()
+	cancel := m.events.watchers[pageID]
+	delete(m.events.watchers, pageID)
+	delete(m.events.subscriptions, pageID)
+	m.events.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	m.network.reset(pageID)
+	m.network.stopCapture(pageID)
+	m.bandwidth.forget(pageID)
+	m.console.reset(pageID)
+}
+
+// ensureDownloadWatcher attaches a browser-level download listener once per browser instance
+func (m *Manager) ensureDownloadWatcher() {
+	m.mutex.RLock()
+	browser := m.browser
+	m.mutex.RUnlock()
+	if browser == nil {
+		return
+	}
+
+	m.events.mutex.Lock()
+	if m.events.downloadWatch == browser {
+		m.events.mutex.Unlock()
+		return
+	}
+	m.events.downloadWatch = browser
+	m.events.mutex.Unlock()
+
+	err := proto.BrowserSetDownloadBehavior{
+		Behavior:      proto.BrowserSetDownloadBehaviorBehaviorDefault,
+		EventsEnabled: true,
+	}.Call(browser)
+	if err != nil {
+		m.logger.WithComponent("browser").Warn("Failed to enable download events", zap.Error(err))
+	}
+
+	wait := browser.EachEvent(
+		func(e *proto.BrowserDownloadWillBegin) {
+			pageID := m.pageIDForFrame(e.FrameID)
+			if pageID == "" {
+				return
+			}
+			m.events.mutex.Lock()
+			m.events.downloads[e.GUID] = pageID
+			m.events.mutex.Unlock()
+		},
+		func(e *proto.BrowserDownloadProgress) {
+			if e.State == proto.BrowserDownloadProgressStateInProgress {
+				return
+			}
+			m.events.mutex.Lock()
+			pageID := m.events.downloads[e.GUID]
+			delete(m.events.downloads, e.GUID)
+			m.events.mutex.Unlock()
+			if pageID == "" {
+				return
+			}
+			m.emitPageEvent(PageEvent{
+				PageID:  pageID,
+				Type:    PageEventDownload,
+				Message: fmt.Sprintf("download %s", e.State),
+				Data: map[string]interface{}{
+					"guid":           e.GUID,
+					"state":          string(e.State),
+					"received_bytes": e.ReceivedBytes,
+					"total_bytes":    e.TotalBytes,
+				},
+			})
+		},
+	)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Download watcher stopped", zap.Any("reason", r))
+			}
+		}()
+		wait()
+	}()
+}
+
+// pageIDForFrame maps a main frame ID back to the managed page that owns it
+func (m *Manager) pageIDForFrame(frameID proto.PageFrameID) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for id, page := range m.pages {
+		if page.FrameID == frameID {
+			return id
+		}
+	}
+	return ""
+}
+
+func (m *Manager) trackedPageURL(pageID string) string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.pageURLs[pageID]
+}
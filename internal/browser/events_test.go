@@ -0,0 +1,106 @@
+package browser
+
+import (
+	"rodmcp/internal/logger"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestParsePageEventType(t *testing.T) {
+	for _, eventType := range AllPageEventTypes {
+		parsed, err := ParsePageEventType(string(eventType))
+		if err != nil {
+			t.Errorf("Expected %s to parse, got error: %v", eventType, err)
+		}
+		if parsed != eventType {
+			t.Errorf("Expected %s, got %s", eventType, parsed)
+		}
+	}
+
+	if _, err := ParsePageEventType("keypress"); err == nil {
+		t.Error("Expected error for unknown event type")
+	}
+}
+
+func TestEmitPageEventRespectsSubscriptions(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+
+	var received []PageEvent
+	manager.SetPageEventHandler(func(event PageEvent) {
+		received = append(received, event)
+	})
+
+	// Subscribe directly through the hub since no browser page exists in this test
+	manager.events.subscriptions["page_1"] = map[PageEventType]bool{
+		PageEventLoad:         true,
+		PageEventConsoleError: true,
+	}
+
+	manager.emitPageEvent(PageEvent{PageID: "page_1", Type: PageEventLoad})
+	manager.emitPageEvent(PageEvent{PageID: "page_1", Type: PageEventDialog})
+	manager.emitPageEvent(PageEvent{PageID: "page_2", Type: PageEventLoad})
+
+	if len(received) != 1 {
+		t.Fatalf("Expected 1 event delivered, got %d", len(received))
+	}
+	if received[0].Timestamp.IsZero() {
+		t.Error("Expected timestamp to be filled in")
+	}
+
+	manager.UnsubscribePageEvents("page_1", []PageEventType{PageEventLoad})
+	subs := manager.PageEventSubscriptions("page_1")
+	if len(subs) != 1 || subs[0] != PageEventConsoleError {
+		t.Errorf("Expected only console_error to remain subscribed, got %v", subs)
+	}
+
+	manager.emitPageEvent(PageEvent{PageID: "page_1", Type: PageEventLoad})
+	if len(received) != 1 {
+		t.Errorf("Expected unsubscribed event to be dropped, got %d events", len(received))
+	}
+
+	manager.stopPageEvents("page_1")
+	if len(manager.PageEventSubscriptions("page_1")) != 0 {
+		t.Error("Expected subscriptions to be cleared when the page stops")
+	}
+}
+
+func TestEmitPageEventHandlerPanic(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+	manager.SetPageEventHandler(func(event PageEvent) {
+		panic("handler failure")
+	})
+	manager.events.subscriptions["page_1"] = map[PageEventType]bool{PageEventLoad: true}
+
+	// Must not propagate the handler panic into the event loop
+	manager.emitPageEvent(PageEvent{PageID: "page_1", Type: PageEventLoad})
+}
+
+func TestSubscribePageEventsUnknownPage(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+
+	if err := manager.SubscribePageEvents("missing", nil); err == nil {
+		t.Error("Expected error subscribing to a page that does not exist")
+	}
+}
+
+func TestCombineEventHandlers(t *testing.T) {
+	var calls []string
+	combined := combineEventHandlers([]interface{}{
+		func(e *proto.NetworkLoadingFinished) { calls = append(calls, "log") },
+		func(e *proto.PageLoadEventFired) { calls = append(calls, "load") },
+		func(e *proto.NetworkLoadingFinished) { calls = append(calls, "capture") },
+	})
+	if len(combined) != 2 {
+		t.Fatalf("got %d callbacks, want one per event", len(combined))
+	}
+
+	combined[0].(func(*proto.NetworkLoadingFinished))(&proto.NetworkLoadingFinished{})
+	combined[1].(func(*proto.PageLoadEventFired))(&proto.PageLoadEventFired{})
+	if len(calls) != 3 || calls[0] != "log" || calls[1] != "capture" || calls[2] != "load" {
+		t.Errorf("calls = %v", calls)
+	}
+}
@@ -0,0 +1,236 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// Fingerprint is the set of browser properties a site can read to tell
+// visitors apart. Zero values leave the browser's own value in place.
+type Fingerprint struct {
+	UserAgent           string   `json:"user_agent,omitempty"`
+	Platform            string   `json:"platform,omitempty"` // navigator.platform, e.g. Win32, MacIntel, Linux x86_64
+	Languages           []string `json:"languages,omitempty"`
+	ScreenWidth         int      `json:"screen_width,omitempty"`
+	ScreenHeight        int      `json:"screen_height,omitempty"`
+	DeviceScaleFactor   float64  `json:"device_scale_factor,omitempty"`
+	HardwareConcurrency int      `json:"hardware_concurrency,omitempty"`
+	WebGLVendor         string   `json:"webgl_vendor,omitempty"`
+	WebGLRenderer       string   `json:"webgl_renderer,omitempty"`
+	// NoiseSeed perturbs canvas reads. The same seed always yields the same
+	// noise, so a profile hashes identically on every visit. 0 disables it.
+	NoiseSeed uint32 `json:"noise_seed,omitempty"`
+}
+
+var chromeMajorPattern = regexp.MustCompile(`Chrome/(\d+)`)
+
+// userAgentMetadata builds client hints that agree with the user agent and
+// platform, so Sec-CH-UA headers do not give the real browser away
+func (fp *Fingerprint) userAgentMetadata() *proto.EmulationUserAgentMetadata {
+	match := chromeMajorPattern.FindStringSubmatch(fp.UserAgent)
+	if match == nil {
+		return nil
+	}
+	platform := "Linux"
+	switch {
+	case strings.HasPrefix(fp.Platform, "Win"):
+		platform = "Windows"
+	case strings.HasPrefix(fp.Platform, "Mac"):
+		platform = "macOS"
+	}
+	return &proto.EmulationUserAgentMetadata{
+		Brands: []*proto.EmulationUserAgentBrandVersion{
+			{Brand: "Chromium", Version: match[1]},
+			{Brand: "Google Chrome", Version: match[1]},
+			{Brand: "Not=A?Brand", Version: "99"},
+		},
+		Platform:     platform,
+		Architecture: "x86",
+	}
+}
+
+// fingerprintScript overrides the navigator, screen, WebGL and canvas
+// properties the CDP emulation commands do not cover
+func fingerprintScript(fp *Fingerprint) (string, error) {
+	config, err := json.Marshal(fp)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`(() => {
+	const fp = %s;
+	const define = (obj, prop, value) => {
+		try { Object.defineProperty(obj, prop, { get: () => value, configurable: true }); } catch (e) {}
+	};
+	if (fp.platform) define(Navigator.prototype, 'platform', fp.platform);
+	if (fp.languages && fp.languages.length) {
+		define(Navigator.prototype, 'languages', Object.freeze(fp.languages.slice()));
+		define(Navigator.prototype, 'language', fp.languages[0]);
+	}
+	if (fp.hardware_concurrency) define(Navigator.prototype, 'hardwareConcurrency', fp.hardware_concurrency);
+	if (fp.screen_width && fp.screen_height) {
+		define(Screen.prototype, 'width', fp.screen_width);
+		define(Screen.prototype, 'height', fp.screen_height);
+		define(Screen.prototype, 'availWidth', fp.screen_width);
+		define(Screen.prototype, 'availHeight', fp.screen_height - 40);
+	}
+
+	if (fp.webgl_vendor || fp.webgl_renderer) {
+		const patch = (proto) => {
+			if (!proto) return;
+			const original = proto.getParameter;
+			proto.getParameter = function (param) {
+				// UNMASKED_VENDOR_WEBGL and UNMASKED_RENDERER_WEBGL
+				if (param === 37445 && fp.webgl_vendor) return fp.webgl_vendor;
+				if (param === 37446 && fp.webgl_renderer) return fp.webgl_renderer;
+				return original.call(this, param);
+			};
+		};
+		patch(window.WebGLRenderingContext && WebGLRenderingContext.prototype);
+		patch(window.WebGL2RenderingContext && WebGL2RenderingContext.prototype);
+	}
+
+	if (fp.noise_seed) {
+		// Flip the low bit of a seeded subset of pixels; invisible, but it
+		// changes the canvas hash consistently for this profile
+		const noisy = (data) => {
+			let state = fp.noise_seed >>> 0;
+			for (let i = 0; i < data.length; i += 4) {
+				state = (Math.imul(state ^ (state >>> 15), 2246822519) + 0x6D2B79F5) >>> 0;
+				if ((state & 15) === 0) data[i] ^= 1;
+			}
+			return data;
+		};
+		const getImageData = CanvasRenderingContext2D.prototype.getImageData;
+		CanvasRenderingContext2D.prototype.getImageData = function () {
+			const image = getImageData.apply(this, arguments);
+			noisy(image.data);
+			return image;
+		};
+		const withNoise = (canvas) => {
+			if (!canvas.width || !canvas.height) return canvas;
+			const ctx = canvas.getContext('2d');
+			if (!ctx) return canvas;
+			const copy = document.createElement('canvas');
+			copy.width = canvas.width;
+			copy.height = canvas.height;
+			const copyCtx = copy.getContext('2d');
+			const image = getImageData.call(ctx, 0, 0, canvas.width, canvas.height);
+			copyCtx.putImageData(new ImageData(noisy(image.data), image.width, image.height), 0, 0);
+			return copy;
+		};
+		const toDataURL = HTMLCanvasElement.prototype.toDataURL;
+		HTMLCanvasElement.prototype.toDataURL = function () {
+			return toDataURL.apply(withNoise(this), arguments);
+		};
+		const toBlob = HTMLCanvasElement.prototype.toBlob;
+		HTMLCanvasElement.prototype.toBlob = function () {
+			return toBlob.apply(withNoise(this), arguments);
+		};
+	}
+})();`, config), nil
+}
+
+// ApplyFingerprint makes a page present fp, replacing any fingerprint
+// applied before. User agent, languages and screen size change at once;
+// the script-level overrides take effect from the page's next navigation.
+func (m *Manager) ApplyFingerprint(pageID string, fp *Fingerprint) error {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return m.applyFingerprint(pageID, page, fp)
+}
+
+// HasFingerprint reports whether a fingerprint has been applied to a page
+func (m *Manager) HasFingerprint(pageID string) bool {
+	m.fingerprintMutex.Lock()
+	defer m.fingerprintMutex.Unlock()
+	_, ok := m.fingerprintScripts[pageID]
+	return ok
+}
+
+func (m *Manager) applyFingerprint(pageID string, page *rod.Page, fp *Fingerprint) error {
+	script, err := fingerprintScript(fp)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	if fp.UserAgent != "" || len(fp.Languages) > 0 || fp.Platform != "" {
+		userAgent := fp.UserAgent
+		if userAgent == "" {
+			version, err := proto.BrowserGetVersion{}.Call(p)
+			if err != nil {
+				return fmt.Errorf("failed to read browser user agent: %w", err)
+			}
+			userAgent = version.UserAgent
+		}
+		override := proto.EmulationSetUserAgentOverride{
+			UserAgent:         userAgent,
+			AcceptLanguage:    strings.Join(fp.Languages, ","),
+			Platform:          fp.Platform,
+			UserAgentMetadata: fp.userAgentMetadata(),
+		}
+		if err := override.Call(p); err != nil {
+			return fmt.Errorf("failed to set user agent: %w", err)
+		}
+	}
+
+	if fp.ScreenWidth > 0 && fp.ScreenHeight > 0 || fp.DeviceScaleFactor > 0 {
+		metrics := proto.EmulationSetDeviceMetricsOverride{DeviceScaleFactor: fp.DeviceScaleFactor}
+		if fp.ScreenWidth > 0 && fp.ScreenHeight > 0 {
+			metrics.ScreenWidth = &fp.ScreenWidth
+			metrics.ScreenHeight = &fp.ScreenHeight
+		}
+		if err := metrics.Call(p); err != nil {
+			return fmt.Errorf("failed to set screen metrics: %w", err)
+		}
+	}
+
+	m.fingerprintMutex.Lock()
+	previous, hadPrevious := m.fingerprintScripts[pageID]
+	m.fingerprintMutex.Unlock()
+	if hadPrevious {
+		proto.PageRemoveScriptToEvaluateOnNewDocument{Identifier: previous}.Call(p)
+	}
+
+	added, err := proto.PageAddScriptToEvaluateOnNewDocument{Source: script}.Call(p)
+	if err != nil {
+		return fmt.Errorf("failed to install fingerprint script: %w", err)
+	}
+
+	m.fingerprintMutex.Lock()
+	if m.fingerprintScripts == nil {
+		m.fingerprintScripts = make(map[string]proto.PageScriptIdentifier)
+	}
+	m.fingerprintScripts[pageID] = added.Identifier
+	m.fingerprintMutex.Unlock()
+
+	m.logger.WithComponent("browser").Debug("Fingerprint applied",
+		zap.String("page_id", pageID),
+		zap.String("user_agent", fp.UserAgent))
+	return nil
+}
+
+// forgetFingerprint drops the bookkeeping for a page that is going away
+func (m *Manager) forgetFingerprint(pageID string) {
+	m.fingerprintMutex.Lock()
+	delete(m.fingerprintScripts, pageID)
+	m.fingerprintMutex.Unlock()
+}
@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprintUserAgentMetadata(t *testing.T) {
+	fp := &Fingerprint{
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:  "MacIntel",
+	}
+	meta := fp.userAgentMetadata()
+	if meta == nil || meta.Platform != "macOS" || meta.Brands[1].Version != "131" {
+		t.Errorf("metadata = %+v", meta)
+	}
+
+	// Client hints are left alone for non-Chromium user agents
+	firefox := &Fingerprint{UserAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:128.0) Gecko/20100101 Firefox/128.0"}
+	if meta := firefox.userAgentMetadata(); meta != nil {
+		t.Errorf("Expected no metadata for Firefox, got %+v", meta)
+	}
+}
+
+func TestFingerprintScript(t *testing.T) {
+	script, err := fingerprintScript(&Fingerprint{Platform: "Win32", WebGLVendor: "Google Inc. (Intel)", NoiseSeed: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"platform":"Win32"`, `"noise_seed":7`, "37445"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script is missing %s", want)
+		}
+	}
+	// Empty fields are omitted so the script leaves them untouched
+	if strings.Contains(script, "user_agent") {
+		t.Error("script carries an empty user_agent")
+	}
+}
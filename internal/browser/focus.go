@@ -0,0 +1,166 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+const focusTimeout = 10 * time.Second
+
+// MaxTabOrder bounds how many elements TabOrder reports
+const MaxTabOrder = 500
+
+// FocusTarget describes an element that can take keyboard focus
+type FocusTarget struct {
+	Selector string `json:"selector"`
+	Tag      string `json:"tag"`
+	Role     string `json:"role,omitempty"`
+	Label    string `json:"label,omitempty"`
+	TabIndex int    `json:"tab_index"`
+	// Visible is false for elements with an empty box, which keyboard
+	// users reach without seeing where focus went
+	Visible bool `json:"visible"`
+}
+
+// focusScript defines describe(el), which builds a FocusTarget, and
+// deepActive(), the focused element looking through open shadow roots and
+// same-origin frames
+const focusScript = selectorForScript + `
+	const describe = (el) => {
+		const labelled = el.getAttribute('aria-labelledby');
+		let label = el.getAttribute('aria-label') || '';
+		if (!label && labelled) {
+			label = labelled.split(/\s+/).map((id) => document.getElementById(id)).filter(Boolean).map(textOf).join(' ');
+		}
+		if (!label && el.labels && el.labels.length) label = textOf(el.labels[0]);
+		if (!label) label = textOf(el) || el.getAttribute('placeholder') || el.getAttribute('title') || el.getAttribute('alt') || '';
+		const rect = el.getBoundingClientRect();
+		return {
+			selector: selectorFor(el),
+			tag: el.tagName.toLowerCase(),
+			role: el.getAttribute('role') || '',
+			label: label.slice(0, 80),
+			tab_index: el.tabIndex,
+			visible: rect.width > 0 && rect.height > 0,
+		};
+	};
+	const deepActive = () => {
+		let el = document.activeElement;
+		while (el) {
+			if (el.shadowRoot && el.shadowRoot.activeElement) {
+				el = el.shadowRoot.activeElement;
+				continue;
+			}
+			let inner = null;
+			try { inner = el.contentDocument && el.contentDocument.activeElement; } catch (e) {}
+			if (!inner || inner === el.contentDocument.body) break;
+			el = inner;
+		}
+		return el;
+	};
+`
+
+// tabOrderScript walks the document, including open shadow roots, and
+// lists the elements sequential focus navigation visits: positive tabindex
+// values first in ascending order, then tabindex 0 in document order.
+// Disabled, inert, hidden and tabindex="-1" elements are skipped.
+const tabOrderScript = `(limit) => {` + focusScript + `
+	const candidates = [];
+	const skipped = (el) => {
+		if (el.disabled || el.closest('[inert]')) return true;
+		if (el.closest('fieldset[disabled]') && !el.closest('legend')) return true;
+		return el.getClientRects().length === 0 || getComputedStyle(el).visibility === 'hidden';
+	};
+	const walk = (root) => {
+		const walker = document.createTreeWalker(root, NodeFilter.SHOW_ELEMENT);
+		for (let el = walker.nextNode(); el; el = walker.nextNode()) {
+			if (el.tabIndex >= 0 && !skipped(el) && !(el.tagName === 'INPUT' && el.type === 'hidden')) {
+				candidates.push(el);
+			}
+			if (el.shadowRoot) walk(el.shadowRoot);
+		}
+	};
+	walk(document.body || document.documentElement);
+
+	const ordered = candidates.filter((el) => el.tabIndex > 0).sort((a, b) => a.tabIndex - b.tabIndex)
+		.concat(candidates.filter((el) => el.tabIndex === 0));
+	return { total: ordered.length, elements: ordered.slice(0, limit).map(describe) };
+}`
+
+// Focus moves keyboard focus to the element matching selector, scrolling it
+// into view first. It fails when the element cannot take focus.
+func (m *Manager) Focus(pageID, selector string, timeout time.Duration) error {
+	return m.FocusContext(context.Background(), pageID, selector, timeout)
+}
+
+// FocusContext is Focus finished by ctx's deadline
+func (m *Manager) FocusContext(ctx context.Context, pageID, selector string, timeout time.Duration) error {
+	return m.withElement(ctx, pageID, nil, selector, timeout, "element_focused", func(el *rod.Element) error {
+		if err := el.Focus(); err != nil {
+			return fmt.Errorf("failed to focus %s: %w", selector, err)
+		}
+		focused, err := el.Matches(":focus-within")
+		if err != nil {
+			return fmt.Errorf("failed to focus %s: %w", selector, err)
+		}
+		if !focused {
+			return fmt.Errorf("%s cannot take focus; give it a tabindex or pick a form control, link or button", selector)
+		}
+		return nil
+	})
+}
+
+// FocusedElement describes the element that has keyboard focus, or returns
+// nil when focus is on the page itself
+func (m *Manager) FocusedElement(pageID string) (*FocusTarget, error) {
+	start := time.Now()
+	var target *FocusTarget
+	err := m.withPage(pageID, focusTimeout, func(p *rod.Page) error {
+		result, err := p.Eval(`() => {` + focusScript + `
+			const el = deepActive();
+			if (!el || el === document.body || el === document.documentElement) return null;
+			return describe(el);
+		}`)
+		if err != nil {
+			return fmt.Errorf("failed to read focused element: %w", err)
+		}
+		if result.Value.Nil() {
+			return nil
+		}
+		return json.Unmarshal([]byte(result.Value.JSON("", "")), &target)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.logger.LogBrowserAction("focused_element", pageID, time.Since(start).Milliseconds())
+	return target, nil
+}
+
+// TabOrder lists up to limit elements in the order the Tab key visits them,
+// and how many there are in total. Elements inside iframes are not listed.
+func (m *Manager) TabOrder(pageID string, limit int) ([]FocusTarget, int, error) {
+	start := time.Now()
+	if limit <= 0 || limit > MaxTabOrder {
+		limit = MaxTabOrder
+	}
+	var order struct {
+		Total    int           `json:"total"`
+		Elements []FocusTarget `json:"elements"`
+	}
+	err := m.withPage(pageID, focusTimeout, func(p *rod.Page) error {
+		result, err := p.Eval(tabOrderScript, limit)
+		if err != nil {
+			return fmt.Errorf("failed to walk tab order: %w", err)
+		}
+		return json.Unmarshal([]byte(result.Value.JSON("", "")), &order)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	m.logger.LogBrowserAction("tab_order", pageID, time.Since(start).Milliseconds())
+	return order.Elements, order.Total, nil
+}
@@ -0,0 +1,245 @@
+package browser
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Defaults for crash bundles
+const (
+	DefaultCrashLogLines = 200
+	// maxCrashBundles is how many bundles are kept; older ones are removed
+	maxCrashBundles = 20
+	// chromeOutputLimit bounds the browser output kept for a bundle
+	chromeOutputLimit = 64 * 1024
+)
+
+// CrashReport describes a forensic bundle collected after the browser
+// crashed or stopped responding
+type CrashReport struct {
+	Dir          string     `json:"dir"`
+	Reason       string     `json:"reason"`
+	Time         time.Time  `json:"time"`
+	BrowserPID   int        `json:"browser_pid,omitempty"`
+	RestartCount int        `json:"restart_count"`
+	Pages        []PageInfo `json:"pages"`
+	// Files lists what the bundle holds, relative to Dir
+	Files []string `json:"files"`
+}
+
+// CrashHandler receives each crash report once its bundle is written
+type CrashHandler func(CrashReport)
+
+// SetCrashHandler sets the sink told about each crash bundle
+func (m *Manager) SetCrashHandler(handler CrashHandler) {
+	m.crashMutex.Lock()
+	defer m.crashMutex.Unlock()
+	m.crashHandler = handler
+}
+
+// outputTail keeps the last bytes of the browser's stdout and stderr
+type outputTail struct {
+	mutex sync.Mutex
+	buf   []byte
+	limit int
+}
+
+func newOutputTail(limit int) *outputTail {
+	return &outputTail{limit: limit}
+}
+
+func (t *outputTail) Write(p []byte) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.buf = append(t.buf, p...)
+	if over := len(t.buf) - t.limit; over > 0 {
+		t.buf = append([]byte(nil), t.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (t *outputTail) String() string {
+	if t == nil {
+		return ""
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return string(t.buf)
+}
+
+// pageSnapshotLocked lists the open pages by their last known URL, without
+// asking the browser, which may be gone. The caller holds m.mutex.
+func (m *Manager) pageSnapshotLocked() []PageInfo {
+	pages := make([]PageInfo, 0, len(m.pages))
+	for id := range m.pages {
+		pages = append(pages, PageInfo{PageID: id, URL: m.pageURLs[id]})
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].PageID < pages[j].PageID })
+	return pages
+}
+
+// recordCrash writes a forensic bundle in the background and passes its
+// report to the crash handler. It does nothing without Config.CrashDir.
+func (m *Manager) recordCrash(reason string, pages []PageInfo, pid, restartCount int, output *outputTail) {
+	if m.config.CrashDir == "" {
+		return
+	}
+	report := CrashReport{
+		Reason:       reason,
+		Time:         time.Now(),
+		BrowserPID:   pid,
+		RestartCount: restartCount,
+		Pages:        pages,
+	}
+	config := m.config
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Error("Crash bundle collection panicked",
+					zap.Any("panic", r))
+			}
+		}()
+		if err := writeCrashBundle(&report, config, output); err != nil {
+			m.logger.WithComponent("browser").Error("Failed to write crash bundle",
+				zap.String("reason", reason),
+				zap.Error(err))
+			return
+		}
+		m.logger.WithComponent("browser").Warn("Browser crash bundle written",
+			zap.String("dir", report.Dir),
+			zap.String("reason", reason),
+			zap.Int("pages", len(pages)))
+
+		m.crashMutex.Lock()
+		handler := m.crashHandler
+		m.crashMutex.Unlock()
+		if handler != nil {
+			handler(report)
+		}
+	}()
+}
+
+// writeCrashBundle fills a new timestamped directory under config.CrashDir
+// and records it in report
+func writeCrashBundle(report *CrashReport, config Config, output *outputTail) error {
+	name := "crash-" + report.Time.UTC().Format("20060102-150405.000")
+	dir := filepath.Join(config.CrashDir, name)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create crash directory: %w", err)
+	}
+	report.Dir = dir
+
+	write := func(file, content string) {
+		if content == "" {
+			return
+		}
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0600); err == nil {
+			report.Files = append(report.Files, file)
+		}
+	}
+
+	lines := config.CrashLogLines
+	if lines <= 0 {
+		lines = DefaultCrashLogLines
+	}
+	if config.LogFile != "" {
+		if tail, err := tailLines(config.LogFile, lines); err == nil {
+			write("server.log", tail)
+		}
+	}
+	write("chrome-output.log", output.String())
+	if meminfo, err := os.ReadFile("/proc/meminfo"); err == nil {
+		write("meminfo.txt", string(meminfo))
+	}
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	summary := map[string]interface{}{
+		"report": report,
+		"browser": map[string]interface{}{
+			"headless":     config.Headless,
+			"container":    config.Container,
+			"proxy_server": config.ProxyServer,
+			"window":       fmt.Sprintf("%dx%d", config.WindowWidth, config.WindowHeight),
+		},
+		"server_memory": map[string]interface{}{
+			"heap_alloc_bytes": mem.HeapAlloc,
+			"sys_bytes":        mem.Sys,
+			"goroutines":       runtime.NumGoroutine(),
+		},
+		"system": map[string]interface{}{
+			"os":   runtime.GOOS,
+			"arch": runtime.GOARCH,
+			"cpus": runtime.NumCPU(),
+		},
+	}
+	// crash.json lists itself among the files
+	report.Files = append(report.Files, "crash.json")
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode crash report: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "crash.json"), data, 0600); err != nil {
+		return fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	pruneCrashBundles(config.CrashDir, maxCrashBundles)
+	return nil
+}
+
+// tailLines returns the last n lines of the file at path
+func tailLines(path string, n int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	ring := make([]string, 0, n)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if len(ring) == n {
+			ring = ring[1:]
+		}
+		ring = append(ring, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if len(ring) == 0 {
+		return "", nil
+	}
+	return strings.Join(ring, "\n") + "\n", nil
+}
+
+// pruneCrashBundles removes all but the newest keep bundles in dir
+func pruneCrashBundles(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	var bundles []string
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), "crash-") {
+			bundles = append(bundles, entry.Name())
+		}
+	}
+	// Names sort by time
+	sort.Strings(bundles)
+	for len(bundles) > keep {
+		os.RemoveAll(filepath.Join(dir, bundles[0]))
+		bundles = bundles[1:]
+	}
+}
@@ -0,0 +1,124 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutputTailKeepsLastBytes(t *testing.T) {
+	tail := newOutputTail(10)
+	fmt.Fprint(tail, "0123456789")
+	fmt.Fprint(tail, "abc")
+	if got := tail.String(); got != "3456789abc" {
+		t.Errorf("tail = %q", got)
+	}
+	var none *outputTail
+	if none.String() != "" {
+		t.Error("Expected a nil tail to be empty")
+	}
+}
+
+func TestTailLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	var lines []string
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := tailLines(path, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tail != "line 8\nline 9\nline 10\n" {
+		t.Errorf("tailLines = %q", tail)
+	}
+}
+
+func TestRecordCrashWritesBundle(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "rodmcp.log")
+	if err := os.WriteFile(logFile, []byte("first\nsecond\nthird\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := newQueueTestManager(t)
+	manager.config = Config{CrashDir: filepath.Join(dir, "crashes"), LogFile: logFile, CrashLogLines: 2}
+	reports := make(chan CrashReport, 1)
+	manager.SetCrashHandler(func(report CrashReport) { reports <- report })
+
+	output := newOutputTail(chromeOutputLimit)
+	fmt.Fprint(output, "[0101/000000.000:FATAL:memory.cc] Out of memory")
+	pages := []PageInfo{{PageID: "page_1", URL: "https://example.com"}}
+	manager.recordCrash("browser process 42 exited", pages, 42, 1, output)
+
+	var report CrashReport
+	select {
+	case report = <-reports:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Crash handler was not called")
+	}
+	if !strings.HasPrefix(filepath.Base(report.Dir), "crash-") || report.BrowserPID != 42 {
+		t.Errorf("report = %+v", report)
+	}
+
+	serverLog, err := os.ReadFile(filepath.Join(report.Dir, "server.log"))
+	if err != nil || string(serverLog) != "second\nthird\n" {
+		t.Errorf("server.log = %q, %v", serverLog, err)
+	}
+	chromeLog, err := os.ReadFile(filepath.Join(report.Dir, "chrome-output.log"))
+	if err != nil || !strings.Contains(string(chromeLog), "Out of memory") {
+		t.Errorf("chrome-output.log = %q, %v", chromeLog, err)
+	}
+	data, err := os.ReadFile(filepath.Join(report.Dir, "crash.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary struct {
+		Report CrashReport `json:"report"`
+	}
+	if err := json.Unmarshal(data, &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary.Report.Reason != "browser process 42 exited" || len(summary.Report.Pages) != 1 {
+		t.Errorf("crash.json report = %+v", summary.Report)
+	}
+}
+
+func TestRecordCrashDisabledWithoutDir(t *testing.T) {
+	manager := newQueueTestManager(t)
+	called := make(chan struct{}, 1)
+	manager.SetCrashHandler(func(CrashReport) { called <- struct{}{} })
+	manager.recordCrash("test", nil, 0, 1, nil)
+
+	select {
+	case <-called:
+		t.Error("Expected no bundle without a crash directory")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestPruneCrashBundles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"crash-20260101-000000.000", "crash-20260102-000000.000", "crash-20260103-000000.000", "other"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pruneCrashBundles(dir, 2)
+
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if strings.Join(names, ",") != "crash-20260102-000000.000,crash-20260103-000000.000,other" {
+		t.Errorf("left %v", names)
+	}
+}
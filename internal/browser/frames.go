@@ -0,0 +1,63 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// FrameSeparator splits the steps of a nested frame selector
+const FrameSeparator = ">>"
+
+// FramePath locates an iframe by the selectors of each iframe element from
+// the top document down, so {"#checkout", "iframe.card"} is the card frame
+// inside the checkout frame. An empty path is the page itself.
+type FramePath []string
+
+// ParseFramePath reads a frame selector such as "#checkout >> iframe.card",
+// checking that every step is a valid selector. A blank string is the page
+// itself.
+func ParseFramePath(raw string) (FramePath, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var path FramePath
+	for _, step := range strings.Split(raw, FrameSeparator) {
+		step = strings.TrimSpace(step)
+		if _, err := ParseSelector(step); err != nil {
+			return nil, fmt.Errorf("invalid frame selector %q: %w", raw, err)
+		}
+		path = append(path, step)
+	}
+	return path, nil
+}
+
+// String joins the path back into a frame selector
+func (f FramePath) String() string {
+	return strings.Join(f, " "+FrameSeparator+" ")
+}
+
+// enterFrames follows frames down from page, waiting for each iframe for as
+// long as page's context allows, and returns the innermost frame
+func enterFrames(page *rod.Page, frames FramePath) (*rod.Page, error) {
+	for i, raw := range frames {
+		sel, err := ParseSelector(raw)
+		if err != nil {
+			return nil, err
+		}
+		el, err := sel.Find(page)
+		if err != nil {
+			return nil, fmt.Errorf("frame %s not found: %w", frames[:i+1], err)
+		}
+		frame, err := el.Frame()
+		if err != nil {
+			return nil, fmt.Errorf("failed to enter frame %s: %w", frames[:i+1], err)
+		}
+		if frame.FrameID == "" {
+			return nil, fmt.Errorf("frame selector %s matched an element that is not an iframe", frames[:i+1])
+		}
+		page = frame
+	}
+	return page, nil
+}
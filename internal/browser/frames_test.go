@@ -0,0 +1,51 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFramePath(t *testing.T) {
+	cases := []struct {
+		raw  string
+		want FramePath
+	}{
+		{"", nil},
+		{"  ", nil},
+		{"iframe#pay", FramePath{"iframe#pay"}},
+		{"#checkout >> iframe[title='Card number']", FramePath{"#checkout", "iframe[title='Card number']"}},
+		{"//iframe[1]>>xpath=//iframe", FramePath{"//iframe[1]", "xpath=//iframe"}},
+	}
+	for _, tc := range cases {
+		got, err := ParseFramePath(tc.raw)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseFramePath(%q) = %#v, %v; want %#v", tc.raw, got, err, tc.want)
+		}
+	}
+
+	for _, raw := range []string{"#a >>", ">> #a", "#a >> css= >> #b"} {
+		if _, err := ParseFramePath(raw); err == nil {
+			t.Errorf("ParseFramePath(%q): expected an error", raw)
+		}
+	}
+
+	path := FramePath{"#checkout", "iframe.card"}
+	if got := path.String(); got != "#checkout >> iframe.card" {
+		t.Errorf("String() = %q", got)
+	}
+	if again, _ := ParseFramePath(path.String()); !reflect.DeepEqual(again, path) {
+		t.Errorf("round trip = %#v", again)
+	}
+}
+
+func TestFrameActionsRequireKnownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+	frames := FramePath{"iframe"}
+
+	if err := manager.Click("missing", frames, "#a", 0); err == nil {
+		t.Error("Expected Click in a frame on an unknown page to fail")
+	}
+	if _, err := manager.ExecuteScriptInFrame("missing", frames, "1"); err == nil {
+		t.Error("Expected ExecuteScriptInFrame on an unknown page to fail")
+	}
+}
@@ -0,0 +1,240 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+	"go.uber.org/zap"
+)
+
+// Limits on what SetExtraHeaders accepts, to keep requests within what
+// servers take
+const (
+	maxExtraHeaders     = 50
+	maxHeaderValueBytes = 8192
+)
+
+// forbiddenExtraHeaders are the headers the browser computes itself for
+// every request; overriding them breaks requests rather than changing them
+var forbiddenExtraHeaders = map[string]bool{
+	"Host":              true,
+	"Content-Length":    true,
+	"Connection":        true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+	"Keep-Alive":        true,
+	"Te":                true,
+	"Trailer":           true,
+}
+
+// ValidateUserAgent checks that a user agent can be sent as a header value
+func ValidateUserAgent(userAgent string) error {
+	if len(userAgent) > 1024 {
+		return fmt.Errorf("user agent is %d bytes; at most 1024 are allowed", len(userAgent))
+	}
+	if strings.ContainsAny(userAgent, "\r\n\x00") {
+		return fmt.Errorf("user agent must not contain line breaks or NUL")
+	}
+	return nil
+}
+
+// ValidateExtraHeaders checks that headers can be added to every request,
+// and returns them with canonical names
+func ValidateExtraHeaders(headers map[string]string) (map[string]string, error) {
+	if len(headers) > maxExtraHeaders {
+		return nil, fmt.Errorf("%d headers given; at most %d are allowed", len(headers), maxExtraHeaders)
+	}
+	canonical := make(map[string]string, len(headers))
+	for name, value := range headers {
+		name = strings.TrimSpace(name)
+		if name == "" || !isHeaderToken(name) {
+			return nil, fmt.Errorf("%q is not a valid header name", name)
+		}
+		key := http.CanonicalHeaderKey(name)
+		if forbiddenExtraHeaders[key] {
+			return nil, fmt.Errorf("header %s is set by the browser and cannot be overridden", key)
+		}
+		if key == "User-Agent" {
+			return nil, fmt.Errorf("set the user agent with SetUserAgent rather than as a header")
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("value of header %s must not contain line breaks or NUL", key)
+		}
+		if len(value) > maxHeaderValueBytes {
+			return nil, fmt.Errorf("value of header %s is %d bytes; at most %d are allowed", key, len(value), maxHeaderValueBytes)
+		}
+		if _, dup := canonical[key]; dup {
+			return nil, fmt.Errorf("header %s is given twice", key)
+		}
+		canonical[key] = value
+	}
+	return canonical, nil
+}
+
+// isHeaderToken reports whether name holds only the characters RFC 7230
+// allows in a header name
+func isHeaderToken(name string) bool {
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SetUserAgent makes a page send userAgent, in navigator.userAgent and the
+// User-Agent header, until it is closed. It takes precedence over the user
+// agent of an emulated device; an empty one removes the override.
+func (m *Manager) SetUserAgent(pageID, userAgent string) error {
+	if err := ValidateUserAgent(userAgent); err != nil {
+		return err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	m.emulationMutex.Lock()
+	previous := m.userAgents[pageID]
+	if userAgent == "" {
+		delete(m.userAgents, pageID)
+	} else {
+		if m.userAgents == nil {
+			m.userAgents = make(map[string]string)
+		}
+		m.userAgents[pageID] = userAgent
+	}
+	m.emulationMutex.Unlock()
+
+	if userAgent == "" && previous == "" {
+		return nil
+	}
+	deviceAgent := ""
+	if d := m.DeviceEmulation(pageID); d != nil {
+		deviceAgent = d.UserAgent
+	}
+	if err := m.setUserAgent(pageID, p, deviceAgent); err != nil {
+		m.emulationMutex.Lock()
+		if previous == "" {
+			delete(m.userAgents, pageID)
+		} else {
+			m.userAgents[pageID] = previous
+		}
+		m.emulationMutex.Unlock()
+		return err
+	}
+
+	m.logger.WithComponent("browser").Debug("User agent set",
+		zap.String("page_id", pageID),
+		zap.String("user_agent", userAgent))
+	return nil
+}
+
+// UserAgentOverride returns the user agent set on a page by SetUserAgent,
+// or ""
+func (m *Manager) UserAgentOverride(pageID string) string {
+	m.emulationMutex.Lock()
+	defer m.emulationMutex.Unlock()
+	return m.userAgents[pageID]
+}
+
+// SetExtraHeaders adds headers to every request the page makes from now on,
+// navigations and subresources alike, replacing any set before. An empty
+// set removes them.
+func (m *Manager) SetExtraHeaders(pageID string, headers map[string]string) error {
+	canonical, err := ValidateExtraHeaders(headers)
+	if err != nil {
+		return err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	if err := (proto.NetworkEnable{}).Call(p); err != nil {
+		return fmt.Errorf("failed to enable network domain: %w", err)
+	}
+	networkHeaders := make(proto.NetworkHeaders, len(canonical))
+	for name, value := range canonical {
+		networkHeaders[name] = gson.New(value)
+	}
+	if err := (proto.NetworkSetExtraHTTPHeaders{Headers: networkHeaders}).Call(p); err != nil {
+		return fmt.Errorf("failed to set extra headers: %w", err)
+	}
+
+	m.emulationMutex.Lock()
+	if len(canonical) == 0 {
+		delete(m.extraHeaders, pageID)
+	} else {
+		if m.extraHeaders == nil {
+			m.extraHeaders = make(map[string]map[string]string)
+		}
+		m.extraHeaders[pageID] = canonical
+	}
+	m.emulationMutex.Unlock()
+
+	m.logger.WithComponent("browser").Debug("Extra headers set",
+		zap.String("page_id", pageID),
+		zap.Strings("headers", sortedHeaderNames(canonical)))
+	return nil
+}
+
+// ExtraHeaders returns a copy of the headers set on a page by
+// SetExtraHeaders, or nil
+func (m *Manager) ExtraHeaders(pageID string) map[string]string {
+	m.emulationMutex.Lock()
+	defer m.emulationMutex.Unlock()
+	headers := m.extraHeaders[pageID]
+	if headers == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(headers))
+	for name, value := range headers {
+		clone[name] = value
+	}
+	return clone
+}
+
+func sortedHeaderNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// forgetRequestOverrides drops the bookkeeping for a page that is going away
+func (m *Manager) forgetRequestOverrides(pageID string) {
+	m.emulationMutex.Lock()
+	delete(m.userAgents, pageID)
+	delete(m.extraHeaders, pageID)
+	m.emulationMutex.Unlock()
+}
@@ -0,0 +1,64 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateExtraHeaders(t *testing.T) {
+	headers, err := ValidateExtraHeaders(map[string]string{"authorization": "Bearer abc", " x-api-key ": "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if headers["Authorization"] != "Bearer abc" || headers["X-Api-Key"] != "k" || len(headers) != 2 {
+		t.Errorf("ValidateExtraHeaders = %v", headers)
+	}
+
+	for _, bad := range []map[string]string{
+		{"Host": "example.com"},
+		{"content-length": "10"},
+		{"User-Agent": "bot"},
+		{"Bad Name": "x"},
+		{"": "x"},
+		{"X-Injected": "a\r\nSet-Cookie: b"},
+		{"X-A": "1", "x-a": "2"},
+		{"X-Big": strings.Repeat("a", maxHeaderValueBytes+1)},
+	} {
+		if _, err := ValidateExtraHeaders(bad); err == nil {
+			t.Errorf("Expected %v to be rejected", bad)
+		}
+	}
+
+	if err := ValidateUserAgent("Mozilla/5.0 (X11; Linux x86_64) rodmcp"); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateUserAgent("bot\nX-Evil: 1"); err == nil {
+		t.Error("Expected a user agent with a line break to be rejected")
+	}
+}
+
+func TestRequestOverridesRequireKnownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	if err := manager.SetUserAgent("missing", "custom"); err == nil {
+		t.Error("Expected SetUserAgent on an unknown page to fail")
+	}
+	if err := manager.SetExtraHeaders("missing", map[string]string{"X-Test": "1"}); err == nil {
+		t.Error("Expected SetExtraHeaders on an unknown page to fail")
+	}
+	if ua := manager.UserAgentOverride("missing"); ua != "" {
+		t.Errorf("UserAgentOverride = %q", ua)
+	}
+
+	manager.userAgents = map[string]string{"page_1": "custom"}
+	manager.extraHeaders = map[string]map[string]string{"page_1": {"X-Test": "1"}}
+	headers := manager.ExtraHeaders("page_1")
+	headers["X-Test"] = "changed"
+	if manager.extraHeaders["page_1"]["X-Test"] != "1" {
+		t.Error("ExtraHeaders should return a copy")
+	}
+	manager.forgetRequestOverrides("page_1")
+	if manager.UserAgentOverride("page_1") != "" || manager.ExtraHeaders("page_1") != nil {
+		t.Error("Expected overrides to be forgotten")
+	}
+}
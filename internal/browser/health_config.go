@@ -0,0 +1,160 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Defaults for the fields of HealthConfig left at zero
+const (
+	DefaultHealthCheckInterval = 10 * time.Second
+	DefaultHealthFailures      = 3
+	DefaultRestartBackoff      = 2 * time.Second
+	DefaultMaxRestartBackoff   = time.Minute
+	DefaultMaxRestarts         = 3
+	DefaultPagePingTimeout     = 5 * time.Second
+)
+
+// HealthConfig tunes how the manager watches the browser and restarts it.
+// Zero fields take the defaults above.
+type HealthConfig struct {
+	// CheckInterval is the time between health checks of the browser
+	CheckInterval time.Duration `json:"check_interval"`
+	// FailureThreshold is the number of failed checks in a row after which
+	// an unresponsive browser is restarted
+	FailureThreshold int `json:"failure_threshold"`
+	// RestartBackoff is the delay before the first restart; each restart
+	// after it waits twice as long, with jitter, up to MaxRestartBackoff
+	RestartBackoff    time.Duration `json:"restart_backoff"`
+	MaxRestartBackoff time.Duration `json:"max_restart_backoff"`
+	// MaxRestarts is the number of restarts allowed before giving up,
+	// counted afresh once five minutes pass without a restart
+	MaxRestarts int `json:"max_restarts"`
+	// PagePingTimeout is how long each page has to answer the watchdog's
+	// ping before it counts as missed
+	PagePingTimeout time.Duration `json:"page_ping_timeout"`
+}
+
+// UnmarshalJSON reads durations as strings ("15s", "2m") or numbers of
+// seconds
+func (h *HealthConfig) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		CheckInterval     interface{} `json:"check_interval"`
+		FailureThreshold  int         `json:"failure_threshold"`
+		RestartBackoff    interface{} `json:"restart_backoff"`
+		MaxRestartBackoff interface{} `json:"max_restart_backoff"`
+		MaxRestarts       int         `json:"max_restarts"`
+		PagePingTimeout   interface{} `json:"page_ping_timeout"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	parsed := HealthConfig{FailureThreshold: raw.FailureThreshold, MaxRestarts: raw.MaxRestarts}
+	for _, field := range []struct {
+		name  string
+		value interface{}
+		dest  *time.Duration
+	}{
+		{"check_interval", raw.CheckInterval, &parsed.CheckInterval},
+		{"restart_backoff", raw.RestartBackoff, &parsed.RestartBackoff},
+		{"max_restart_backoff", raw.MaxRestartBackoff, &parsed.MaxRestartBackoff},
+		{"page_ping_timeout", raw.PagePingTimeout, &parsed.PagePingTimeout},
+	} {
+		d, err := healthDuration(field.value)
+		if err != nil {
+			return fmt.Errorf("%s %w", field.name, err)
+		}
+		*field.dest = d
+	}
+	*h = parsed
+	return nil
+}
+
+func healthDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case nil:
+		return 0, nil
+	case string:
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("is invalid: %w", err)
+		}
+		return d, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("must be a duration string or a number of seconds")
+	}
+}
+
+// Validate rejects negative settings and a backoff cap below the first delay
+func (h HealthConfig) Validate() error {
+	if h.CheckInterval < 0 || h.RestartBackoff < 0 || h.MaxRestartBackoff < 0 || h.PagePingTimeout < 0 {
+		return fmt.Errorf("health check interval, restart backoff and page ping timeout must not be negative")
+	}
+	if h.FailureThreshold < 0 || h.MaxRestarts < 0 {
+		return fmt.Errorf("failure threshold and max restarts must not be negative")
+	}
+	if h.CheckInterval > 0 && h.CheckInterval < 100*time.Millisecond {
+		return fmt.Errorf("health check interval must be at least 100ms, not %s", h.CheckInterval)
+	}
+	if h.PagePingTimeout > 0 && h.PagePingTimeout < 100*time.Millisecond {
+		return fmt.Errorf("page ping timeout must be at least 100ms, not %s", h.PagePingTimeout)
+	}
+	d := h.withDefaults()
+	if d.MaxRestartBackoff < d.RestartBackoff {
+		return fmt.Errorf("max restart backoff %s is below the restart backoff %s", d.MaxRestartBackoff, d.RestartBackoff)
+	}
+	return nil
+}
+
+// withDefaults fills in the zero fields
+func (h HealthConfig) withDefaults() HealthConfig {
+	if h.CheckInterval <= 0 {
+		h.CheckInterval = DefaultHealthCheckInterval
+	}
+	if h.FailureThreshold <= 0 {
+		h.FailureThreshold = DefaultHealthFailures
+	}
+	if h.RestartBackoff <= 0 {
+		h.RestartBackoff = DefaultRestartBackoff
+	}
+	if h.MaxRestartBackoff <= 0 {
+		h.MaxRestartBackoff = DefaultMaxRestartBackoff
+		if h.MaxRestartBackoff < h.RestartBackoff {
+			h.MaxRestartBackoff = h.RestartBackoff
+		}
+	}
+	if h.MaxRestarts <= 0 {
+		h.MaxRestarts = DefaultMaxRestarts
+	}
+	if h.PagePingTimeout <= 0 {
+		h.PagePingTimeout = DefaultPagePingTimeout
+	}
+	return h
+}
+
+// restartDelay is the wait before restart number attempt (from 1): the
+// backoff doubled for each earlier attempt, capped, then jittered down by
+// up to half so browsers restarting together spread out
+func (h HealthConfig) restartDelay(attempt int, jitter func() float64) time.Duration {
+	h = h.withDefaults()
+	delay := h.RestartBackoff
+	for i := 1; i < attempt && delay < h.MaxRestartBackoff; i++ {
+		delay *= 2
+	}
+	if delay > h.MaxRestartBackoff {
+		delay = h.MaxRestartBackoff
+	}
+	if jitter == nil {
+		jitter = rand.Float64
+	}
+	return delay/2 + time.Duration(jitter()*float64(delay/2))
+}
+
+// health is the manager's health settings with defaults filled in
+func (m *Manager) health() HealthConfig {
+	return m.config.Health.withDefaults()
+}
@@ -0,0 +1,70 @@
+package browser
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestHealthConfigDefaults(t *testing.T) {
+	h := HealthConfig{}.withDefaults()
+	if h.CheckInterval != DefaultHealthCheckInterval || h.FailureThreshold != DefaultHealthFailures ||
+		h.RestartBackoff != DefaultRestartBackoff || h.MaxRestartBackoff != DefaultMaxRestartBackoff ||
+		h.MaxRestarts != DefaultMaxRestarts || h.PagePingTimeout != DefaultPagePingTimeout {
+		t.Errorf("withDefaults = %+v", h)
+	}
+
+	// A long first backoff raises the default cap with it
+	h = HealthConfig{RestartBackoff: 2 * time.Minute}.withDefaults()
+	if h.MaxRestartBackoff != 2*time.Minute {
+		t.Errorf("MaxRestartBackoff = %s", h.MaxRestartBackoff)
+	}
+}
+
+func TestHealthConfigUnmarshalAndValidate(t *testing.T) {
+	var h HealthConfig
+	err := json.Unmarshal([]byte(`{"check_interval": "30s", "failure_threshold": 5, "restart_backoff": 1.5, "max_restarts": 10, "page_ping_timeout": "2s"}`), &h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.CheckInterval != 30*time.Second || h.FailureThreshold != 5 || h.RestartBackoff != 1500*time.Millisecond || h.MaxRestarts != 10 ||
+		h.PagePingTimeout != 2*time.Second {
+		t.Errorf("Unmarshal = %+v", h)
+	}
+	if err := h.Validate(); err != nil {
+		t.Error(err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"restart_backoff": "soon"}`), &h); err == nil {
+		t.Error("Expected an invalid duration to be rejected")
+	}
+	for _, bad := range []HealthConfig{
+		{CheckInterval: -time.Second},
+		{CheckInterval: time.Millisecond},
+		{MaxRestarts: -1},
+		{PagePingTimeout: time.Millisecond},
+		{RestartBackoff: time.Minute, MaxRestartBackoff: time.Second},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestRestartDelayBacksOffWithJitter(t *testing.T) {
+	h := HealthConfig{RestartBackoff: time.Second, MaxRestartBackoff: 10 * time.Second}
+	full := func() float64 { return 1 }
+	none := func() float64 { return 0 }
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second, 5: 10 * time.Second, 50: 10 * time.Second} {
+		if got := h.restartDelay(attempt, full); got != want {
+			t.Errorf("restartDelay(%d) = %s, want %s", attempt, got, want)
+		}
+		if got := h.restartDelay(attempt, none); got != want/2 {
+			t.Errorf("restartDelay(%d) with no jitter left = %s, want %s", attempt, got, want/2)
+		}
+	}
+	if got := h.restartDelay(3, nil); got < 2*time.Second || got > 4*time.Second {
+		t.Errorf("restartDelay(3) = %s, want between 2s and 4s", got)
+	}
+}
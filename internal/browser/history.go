@@ -0,0 +1,161 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// History actions taken by NavigateHistory
+const (
+	HistoryBack       = "back"
+	HistoryForward    = "forward"
+	HistoryReload     = "reload"
+	HistoryHardReload = "hard_reload"
+)
+
+// HistoryActions lists the actions NavigateHistory takes
+var HistoryActions = []string{HistoryBack, HistoryForward, HistoryReload, HistoryHardReload}
+
+// ValidateHistoryAction rejects anything but the history actions
+func ValidateHistoryAction(action string) error {
+	for _, a := range HistoryActions {
+		if action == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown history action %q (use back, forward, reload or hard_reload)", action)
+}
+
+// historyTarget picks the entry back or forward moves to, or fails at
+// either end of the history
+func historyTarget(history *proto.PageGetNavigationHistoryResult, action string) (*proto.PageNavigationEntry, error) {
+	index := history.CurrentIndex
+	if action == HistoryBack {
+		index--
+	} else {
+		index++
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("cannot go back: already at the first page in history")
+	}
+	if index >= len(history.Entries) {
+		return nil, fmt.Errorf("cannot go forward: already at the last page in history")
+	}
+	return history.Entries[index], nil
+}
+
+// NavigateHistory moves the page back or forward in its history, or
+// reloads it (hard_reload bypasses the cache), and reports where it landed
+func (m *Manager) NavigateHistory(pageID, action string) (PageInfo, error) {
+	return m.NavigateHistoryContext(context.Background(), pageID, action)
+}
+
+// NavigateHistoryContext is NavigateHistory finished by ctx's deadline
+func (m *Manager) NavigateHistoryContext(ctx context.Context, pageID, action string) (PageInfo, error) {
+	if err := ValidateHistoryAction(action); err != nil {
+		return PageInfo{}, err
+	}
+	info, err := m.navigateHistory(ctx, pageID, action)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	// The hook runs after the page lock is released so it can script the page
+	m.runNavigationHook(pageID, info.URL)
+	return info, nil
+}
+
+func (m *Manager) navigateHistory(parent context.Context, pageID, action string) (PageInfo, error) {
+	start := time.Now()
+
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return PageInfo{}, err
+	}
+
+	release, err := m.acquirePageContext(parent, pageID)
+	if err != nil {
+		return PageInfo{}, err
+	}
+	defer release()
+
+	ctx, cancel := stepContext(parent, m.navigationTimeout())
+	defer cancel()
+	page = page.Context(ctx)
+
+	var target *proto.PageNavigationEntry
+	if action == HistoryBack || action == HistoryForward {
+		history, err := proto.PageGetNavigationHistory{}.Call(page)
+		if err != nil {
+			return PageInfo{}, deadlineError(parent, fmt.Errorf("failed to read navigation history: %w", err))
+		}
+		if target, err = historyTarget(history, action); err != nil {
+			return PageInfo{}, err
+		}
+	}
+
+	// A fragment-only move, or a page restored from the back/forward cache,
+	// lands without a load event, so any of them ends the wait
+	wait := waitHistoryLanding(page)
+
+	switch action {
+	case HistoryReload, HistoryHardReload:
+		err = proto.PageReload{IgnoreCache: action == HistoryHardReload}.Call(page)
+	default:
+		err = proto.PageNavigateToHistoryEntry{EntryID: target.ID}.Call(page)
+	}
+	if err != nil {
+		return PageInfo{}, m.pageFailure(pageID, start, deadlineError(parent, fmt.Errorf("failed to %s: %w", action, err)))
+	}
+	if err := wait(); err != nil {
+		return PageInfo{}, m.pageFailure(pageID, start, deadlineError(parent, fmt.Errorf("failed to wait for page load after %s: %w", action, err)))
+	}
+
+	info := PageInfo{PageID: pageID}
+	if pageInfo, err := page.Info(); err == nil && pageInfo != nil {
+		info.URL = pageInfo.URL
+		info.Title = pageInfo.Title
+	} else if target != nil {
+		info.URL = target.URL
+		info.Title = target.Title
+	}
+
+	m.mutex.Lock()
+	if _, exists := m.pages[pageID]; exists && info.URL != "" {
+		m.pageURLs[pageID] = info.URL
+	}
+	m.mutex.Unlock()
+
+	m.logger.LogBrowserAction("history_"+action, info.URL, time.Since(start).Milliseconds())
+	return info, nil
+}
+
+// waitHistoryLanding returns a wait for the page's next load, navigation
+// within the document or restore from the back/forward cache, whichever
+// comes first
+func waitHistoryLanding(page *rod.Page) func() error {
+	ctx := page.GetContext()
+	landed := make(chan struct{})
+	wait := page.EachEvent(
+		func(e *proto.PageLoadEventFired) bool { return true },
+		func(e *proto.PageNavigatedWithinDocument) bool { return e.FrameID == page.FrameID },
+		func(e *proto.PageFrameNavigated) bool {
+			return e.Type == proto.PageNavigationTypeBackForwardCacheRestore && e.Frame != nil && e.Frame.ParentID == ""
+		},
+	)
+	go func() {
+		wait()
+		close(landed)
+	}()
+	return func() error {
+		select {
+		case <-landed:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"rodmcp/internal/logger"
+)
+
+func TestValidateHistoryAction(t *testing.T) {
+	for _, action := range HistoryActions {
+		if err := ValidateHistoryAction(action); err != nil {
+			t.Errorf("%s: %v", action, err)
+		}
+	}
+	for _, bad := range []string{"", "Back", "refresh", "go"} {
+		if err := ValidateHistoryAction(bad); err == nil {
+			t.Errorf("Expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestHistoryTarget(t *testing.T) {
+	history := &proto.PageGetNavigationHistoryResult{
+		CurrentIndex: 1,
+		Entries: []*proto.PageNavigationEntry{
+			{ID: 1, URL: "https://example.com/a"},
+			{ID: 2, URL: "https://example.com/b"},
+			{ID: 3, URL: "https://example.com/c"},
+		},
+	}
+	if entry, err := historyTarget(history, HistoryBack); err != nil || entry.ID != 1 {
+		t.Errorf("back = %v, %v", entry, err)
+	}
+	if entry, err := historyTarget(history, HistoryForward); err != nil || entry.ID != 3 {
+		t.Errorf("forward = %v, %v", entry, err)
+	}
+
+	history.CurrentIndex = 0
+	if _, err := historyTarget(history, HistoryBack); err == nil {
+		t.Error("Expected going back from the first entry to fail")
+	}
+	history.CurrentIndex = 2
+	if _, err := historyTarget(history, HistoryForward); err == nil {
+		t.Error("Expected going forward from the last entry to fail")
+	}
+}
+
+func TestNavigateHistoryRequiresKnownPage(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+
+	if _, err := manager.NavigateHistory("missing", HistoryReload); err == nil {
+		t.Error("Expected an unknown page to be rejected")
+	}
+	if _, err := manager.NavigateHistory("missing", "sideways"); err == nil {
+		t.Error("Expected an unknown action to be rejected")
+	}
+}
@@ -0,0 +1,197 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// MaxUnnamedReported bounds how many unnamed elements PageStructure lists;
+// UnnamedTotal still counts all of them
+const MaxUnnamedReported = 50
+
+// Landmark is a region of the page screen readers let users jump to
+type Landmark struct {
+	Role     string `json:"role"`
+	Label    string `json:"label,omitempty"`
+	Tag      string `json:"tag"`
+	Selector string `json:"selector"`
+}
+
+// Heading is an h1-h6 or role="heading" element
+type Heading struct {
+	Level    int    `json:"level"`
+	Text     string `json:"text"`
+	Selector string `json:"selector"`
+}
+
+// UnnamedElement is an interactive element or image with no accessible
+// name, which screen readers announce only by its role
+type UnnamedElement struct {
+	Tag      string `json:"tag"`
+	Role     string `json:"role"`
+	Selector string `json:"selector"`
+}
+
+// PageStructure is the accessibility outline of a page, in document order
+type PageStructure struct {
+	Landmarks    []Landmark       `json:"landmarks"`
+	Headings     []Heading        `json:"headings"`
+	Unnamed      []UnnamedElement `json:"unnamed"`
+	UnnamedTotal int              `json:"unnamed_total"`
+}
+
+// pageStructureScript walks the document, including open shadow roots,
+// skipping hidden subtrees. Roles follow the HTML-AAM mappings: header and
+// footer are only landmarks outside sectioning content, and section and
+// form only when they have a name.
+const pageStructureScript = `(limit) => {` + selectorForScript + `
+	const clean = (s) => (s || '').replace(/\s+/g, ' ').trim();
+	const hidden = (el) => el.getAttribute('aria-hidden') === 'true' || el.hidden ||
+		(el.getClientRects().length === 0 && getComputedStyle(el).display !== 'contents');
+
+	// contentName is the name an element gets from its contents: text,
+	// plus the labels and alt text of images and icons inside it
+	const contentName = (el) => {
+		let name = '';
+		for (const node of el.childNodes) {
+			if (node.nodeType === Node.TEXT_NODE) {
+				name += node.textContent;
+			} else if (node.nodeType === Node.ELEMENT_NODE && node.getAttribute('aria-hidden') !== 'true') {
+				const tag = node.tagName.toLowerCase();
+				const label = node.getAttribute('aria-label');
+				if (label) name += ' ' + label;
+				else if (tag === 'img' || tag === 'area') name += ' ' + (node.getAttribute('alt') || '');
+				else if (tag === 'svg') name += ' ' + ((node.querySelector('title') || {}).textContent || '');
+				else if (tag !== 'script' && tag !== 'style') name += ' ' + contentName(node);
+			}
+		}
+		return name;
+	};
+	const nameFromContent = new Set(['a', 'button', 'summary', 'option', 'legend', 'caption', 'th', 'td',
+		'h1', 'h2', 'h3', 'h4', 'h5', 'h6']);
+	const contentRoles = new Set(['button', 'link', 'tab', 'menuitem', 'menuitemcheckbox', 'menuitemradio',
+		'checkbox', 'radio', 'switch', 'option', 'treeitem', 'heading', 'cell', 'gridcell', 'columnheader', 'rowheader']);
+
+	// nameOf approximates the accessible name computation closely enough to
+	// tell named elements from unnamed ones
+	const nameOf = (el) => {
+		const labelledby = el.getAttribute('aria-labelledby');
+		if (labelledby) {
+			const text = clean(labelledby.split(/\s+/).map((id) => document.getElementById(id))
+				.filter(Boolean).map(contentName).join(' '));
+			if (text) return text;
+		}
+		const label = clean(el.getAttribute('aria-label'));
+		if (label) return label;
+		const tag = el.tagName.toLowerCase();
+		const type = (el.getAttribute('type') || '').toLowerCase();
+		if (tag === 'input' && ['submit', 'reset'].includes(type)) return clean(el.value) || type;
+		if (tag === 'input' && type === 'button') return clean(el.value);
+		if (tag === 'input' && type === 'image') return clean(el.getAttribute('alt'));
+		if (el.labels && el.labels.length) {
+			const text = clean(Array.from(el.labels).map(contentName).join(' '));
+			if (text) return text;
+		}
+		if (tag === 'img' || tag === 'area') return clean(el.getAttribute('alt'));
+		if (tag === 'svg') return clean((el.querySelector('title') || {}).textContent);
+		if (tag === 'fieldset') {
+			const legend = el.querySelector('legend');
+			if (legend) return clean(contentName(legend));
+		}
+		if (nameFromContent.has(tag) || contentRoles.has(el.getAttribute('role'))) {
+			const text = clean(contentName(el));
+			if (text) return text;
+		}
+		return clean(el.getAttribute('title')) || clean(el.getAttribute('placeholder'));
+	};
+
+	const sectioning = 'article, aside, main, nav, section, [role=article], [role=complementary], [role=main], [role=navigation], [role=region]';
+	const landmarkRoles = new Set(['banner', 'navigation', 'main', 'contentinfo', 'complementary', 'region', 'form', 'search']);
+	const landmarkRole = (el) => {
+		const role = (el.getAttribute('role') || '').split(/\s+/)[0];
+		if (role) return landmarkRoles.has(role) ? role : '';
+		switch (el.tagName.toLowerCase()) {
+		case 'header': return el.parentElement && el.parentElement.closest(sectioning) ? '' : 'banner';
+		case 'footer': return el.parentElement && el.parentElement.closest(sectioning) ? '' : 'contentinfo';
+		case 'nav': return 'navigation';
+		case 'main': return 'main';
+		case 'aside': return 'complementary';
+		case 'search': return 'search';
+		case 'section': return el.hasAttribute('aria-label') || el.hasAttribute('aria-labelledby') || el.hasAttribute('title') ? 'region' : '';
+		case 'form': return el.hasAttribute('aria-label') || el.hasAttribute('aria-labelledby') || el.hasAttribute('title') ? 'form' : '';
+		}
+		return '';
+	};
+
+	const widgetRoles = new Set(['button', 'link', 'checkbox', 'radio', 'switch', 'tab', 'menuitem', 'combobox',
+		'textbox', 'searchbox', 'slider', 'spinbutton', 'listbox', 'option', 'img']);
+	// needsName returns the role of elements that must have a name, or ''
+	const needsName = (el) => {
+		const role = el.getAttribute('role');
+		if (role === 'presentation' || role === 'none') return '';
+		if (role && widgetRoles.has(role)) return role;
+		const tag = el.tagName.toLowerCase();
+		const type = (el.getAttribute('type') || 'text').toLowerCase();
+		switch (tag) {
+		case 'a': return el.hasAttribute('href') ? 'link' : '';
+		case 'button': return 'button';
+		case 'select': return 'combobox';
+		case 'textarea': return 'textbox';
+		case 'iframe': return 'iframe';
+		case 'img': return el.hasAttribute('alt') ? '' : 'img';
+		case 'input':
+			if (type === 'hidden') return '';
+			if (['submit', 'reset', 'button', 'image'].includes(type)) return 'button';
+			if (type === 'checkbox' || type === 'radio') return type;
+			return 'textbox';
+		}
+		return '';
+	};
+
+	const result = { landmarks: [], headings: [], unnamed: [], unnamed_total: 0 };
+	const visit = (el) => {
+		if (hidden(el)) return;
+		const tag = el.tagName.toLowerCase();
+		const role = landmarkRole(el);
+		if (role) {
+			result.landmarks.push({ role: role, label: nameOf(el).slice(0, 80), tag: tag, selector: selectorFor(el) });
+		}
+		let level = /^h[1-6]$/.test(tag) ? Number(tag[1]) : 0;
+		if (el.getAttribute('role') === 'heading') level = Number(el.getAttribute('aria-level')) || 2;
+		if (level) {
+			result.headings.push({ level: level, text: clean(contentName(el)).slice(0, 120), selector: selectorFor(el) });
+		}
+		const widget = needsName(el);
+		if (widget && !nameOf(el)) {
+			if (result.unnamed.length < limit) result.unnamed.push({ tag: tag, role: widget, selector: selectorFor(el) });
+			result.unnamed_total++;
+		}
+		if (el.shadowRoot) Array.from(el.shadowRoot.children).forEach(visit);
+		// Elements inside an svg are drawing primitives, not content
+		if (tag !== 'svg') Array.from(el.children).forEach(visit);
+	};
+	visit(document.body || document.documentElement);
+	return result;
+}`
+
+// PageStructure reports the page's landmarks, headings and the interactive
+// elements and images that have no accessible name
+func (m *Manager) PageStructure(pageID string) (*PageStructure, error) {
+	start := time.Now()
+	var structure PageStructure
+	err := m.withPage(pageID, focusTimeout, func(p *rod.Page) error {
+		result, err := p.Eval(pageStructureScript, MaxUnnamedReported)
+		if err != nil {
+			return fmt.Errorf("failed to read page structure: %w", err)
+		}
+		return json.Unmarshal([]byte(result.Value.JSON("", "")), &structure)
+	})
+	if err != nil {
+		return nil, err
+	}
+	m.logger.LogBrowserAction("page_structure", pageID, time.Since(start).Milliseconds())
+	return &structure, nil
+}
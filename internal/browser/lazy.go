@@ -0,0 +1,190 @@
+package browser
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// lazyLauncher starts the browser when the first page is needed and, with
+// an idle timeout, shuts it down again once nothing has used it for that
+// long. Servers that mostly run file and HTTP tools then hold no Chrome
+// process while they wait.
+type lazyLauncher struct {
+	// mutex serializes launching and idle shutdown
+	mutex    sync.Mutex
+	enabled  bool
+	idle     time.Duration
+	launches int
+
+	// inUse counts calls between ensureBrowser and their release, which
+	// idle shutdown waits out
+	inUse int
+
+	usedMutex sync.Mutex
+	lastUsed  time.Time
+
+	// quit ends idle monitoring; nil until StartLazy
+	quit    chan struct{}
+	stopped bool
+}
+
+// end stops idle monitoring for good
+func (l *lazyLauncher) end() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.quit != nil && !l.stopped {
+		close(l.quit)
+	}
+	l.stopped = true
+}
+
+// LazyStatus describes a lazily started browser
+type LazyStatus struct {
+	Enabled  bool          `json:"enabled"`
+	Running  bool          `json:"running"`
+	Launches int           `json:"launches"`
+	Idle     time.Duration `json:"idle_timeout"`
+	LastUsed time.Time     `json:"last_used,omitempty"`
+}
+
+// StartLazy configures the manager like Start but defers launching Chrome
+// until a page is first created. With config.IdleShutdown set, the browser
+// is shut down, closing its pages, after going unused for that long, and
+// launched again on the next page.
+func (m *Manager) StartLazy(config Config) {
+	m.config = config
+	m.pool.setSize(config.PagePoolSize)
+
+	m.lazy.mutex.Lock()
+	m.lazy.enabled = true
+	m.lazy.idle = config.IdleShutdown
+	watch := config.IdleShutdown > 0 && m.lazy.quit == nil && !m.lazy.stopped
+	if watch {
+		m.lazy.quit = make(chan struct{})
+	}
+	quit := m.lazy.quit
+	m.lazy.mutex.Unlock()
+
+	m.logger.WithComponent("browser").Info("Browser will launch on first use",
+		zap.Duration("idle_shutdown", config.IdleShutdown))
+	if watch {
+		go m.watchIdleBrowser(config.IdleShutdown, quit)
+	}
+}
+
+// LazyStatus reports whether the browser starts lazily and whether it is
+// running now
+func (m *Manager) LazyStatus() LazyStatus {
+	m.lazy.mutex.Lock()
+	status := LazyStatus{Enabled: m.lazy.enabled, Launches: m.lazy.launches, Idle: m.lazy.idle}
+	m.lazy.mutex.Unlock()
+	status.Running = m.browserRunning()
+	status.LastUsed = m.lastUsed()
+	return status
+}
+
+func (m *Manager) browserRunning() bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.browser != nil
+}
+
+// parked reports whether a lazy browser is simply not launched yet, which
+// is not a fault
+func (m *Manager) parked() bool {
+	m.lazy.mutex.Lock()
+	enabled := m.lazy.enabled
+	m.lazy.mutex.Unlock()
+	return enabled && !m.browserRunning()
+}
+
+// touch records that the browser was just used, holding off idle shutdown
+func (m *Manager) touch() {
+	m.lazy.usedMutex.Lock()
+	m.lazy.lastUsed = time.Now()
+	m.lazy.usedMutex.Unlock()
+}
+
+func (m *Manager) lastUsed() time.Time {
+	m.lazy.usedMutex.Lock()
+	defer m.lazy.usedMutex.Unlock()
+	return m.lazy.lastUsed
+}
+
+// ensureBrowser launches a lazy browser that is not running yet. The
+// browser is kept running until release is called, so a page can be
+// created on it without idle shutdown closing it first.
+func (m *Manager) ensureBrowser() (release func(), err error) {
+	m.lazy.mutex.Lock()
+	defer m.lazy.mutex.Unlock()
+	m.touch()
+	if m.lazy.enabled && !m.browserRunning() {
+		m.logger.WithComponent("browser").Info("Launching browser on first use",
+			zap.Int("previous_launches", m.lazy.launches))
+		if err := m.Start(m.config); err != nil {
+			return nil, err
+		}
+		m.lazy.launches++
+	}
+	m.lazy.inUse++
+	return m.releaseBrowser, nil
+}
+
+// releaseBrowser ends a use begun by ensureBrowser
+func (m *Manager) releaseBrowser() {
+	m.lazy.mutex.Lock()
+	m.lazy.inUse--
+	m.lazy.mutex.Unlock()
+	m.touch()
+}
+
+// watchIdleBrowser shuts the browser down whenever it has been idle for
+// longer than idle, until Stop is called
+func (m *Manager) watchIdleBrowser(idle time.Duration, quit <-chan struct{}) {
+	interval := idle / 4
+	if interval > 30*time.Second {
+		interval = 30 * time.Second
+	}
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+			m.shutdownIfIdle(idle)
+		}
+	}
+}
+
+// shutdownIfIdle closes the browser's pages and the browser itself when
+// nothing has used it for idle and no page operation or page creation is
+// in progress
+func (m *Manager) shutdownIfIdle(idle time.Duration) bool {
+	m.lazy.mutex.Lock()
+	defer m.lazy.mutex.Unlock()
+	if m.lazy.inUse > 0 || !m.browserRunning() || time.Since(m.lastUsed()) < idle || m.pageQueue.busy() {
+		return false
+	}
+
+	m.logger.WithComponent("browser").Info("Shutting down idle browser",
+		zap.Duration("idle", time.Since(m.lastUsed())),
+		zap.Int("pages", len(m.ListPages())))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	m.CloseAllPages(ctx)
+	cancel()
+	m.stop()
+
+	m.mutex.Lock()
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.mutex.Unlock()
+	return true
+}
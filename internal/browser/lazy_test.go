@@ -0,0 +1,96 @@
+package browser
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+func TestStartLazyDefersLaunch(t *testing.T) {
+	manager := newQueueTestManager(t)
+	manager.StartLazy(Config{Headless: true})
+	defer manager.Stop()
+
+	status := manager.LazyStatus()
+	if !status.Enabled || status.Running || status.Launches != 0 {
+		t.Errorf("Expected an enabled, parked browser, got %+v", status)
+	}
+	if err := manager.CheckHealth(); err != nil {
+		t.Errorf("A parked browser should be healthy, got %v", err)
+	}
+	if manager.shutdownIfIdle(0) {
+		t.Error("Expected no shutdown while the browser is not running")
+	}
+}
+
+func TestParkedBrowserRemembersVisibility(t *testing.T) {
+	manager := newQueueTestManager(t)
+	manager.StartLazy(Config{Headless: true})
+	defer manager.Stop()
+
+	if err := manager.SetVisibility(true); err != nil {
+		t.Fatalf("SetVisibility on a parked browser failed: %v", err)
+	}
+	if manager.config.Headless {
+		t.Error("Expected the next launch to be visible")
+	}
+}
+
+func TestStopEndsIdleWatcher(t *testing.T) {
+	manager := newQueueTestManager(t)
+	manager.StartLazy(Config{Headless: true, IdleShutdown: time.Minute})
+
+	quit := manager.lazy.quit
+	if quit == nil {
+		t.Fatal("Expected idle monitoring to start")
+	}
+	manager.Stop()
+	manager.Stop()
+
+	select {
+	case <-quit:
+	default:
+		t.Error("Expected Stop to end idle monitoring")
+	}
+}
+
+func TestAcquirePageHoldsOffIdleShutdown(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	before := manager.lastUsed()
+	release, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	if !manager.lastUsed().After(before) {
+		t.Error("Expected AcquirePage to mark the browser used")
+	}
+	if !manager.pageQueue.busy() {
+		t.Error("Expected the queue to be busy while a page is held")
+	}
+	release()
+	if manager.pageQueue.busy() {
+		t.Error("Expected the queue to be idle after release")
+	}
+}
+
+func TestEnsureBrowserHoldsOffIdleShutdown(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	release, err := manager.ensureBrowser()
+	if err != nil {
+		t.Fatalf("ensureBrowser failed: %v", err)
+	}
+	// A page is being created on the running browser
+	manager.browser = rod.New()
+	defer func() { manager.browser = nil }()
+	if manager.shutdownIfIdle(0) {
+		t.Error("Expected no shutdown between ensureBrowser and its release")
+	}
+	release()
+	if manager.lazy.inUse != 0 {
+		t.Errorf("Expected release to end the use, %d left", manager.lazy.inUse)
+	}
+}
@@ -0,0 +1,239 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// Geolocation is the position navigator.geolocation reports
+type Geolocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	// Accuracy is the radius in metres the position is good to
+	Accuracy float64 `json:"accuracy"`
+}
+
+// LocationEmulation is where a page believes it is: its position, the
+// timezone its clocks use and the locale it formats and negotiates
+// content in. Empty fields leave the browser's own value alone.
+type LocationEmulation struct {
+	Geolocation *Geolocation `json:"geolocation,omitempty"`
+	// Timezone is an IANA ID such as "Europe/Berlin"
+	Timezone string `json:"timezone,omitempty"`
+	// Locale is a BCP 47 tag such as "de-DE", used by Intl and toLocale*
+	Locale string `json:"locale,omitempty"`
+	// AcceptLanguage is sent with requests and read by navigator.languages;
+	// it defaults to the locale followed by its bare language
+	AcceptLanguage string `json:"accept_language,omitempty"`
+}
+
+var (
+	localePattern   = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+	timezonePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+\-]*(/[A-Za-z0-9_+\-]+)*$`)
+)
+
+// Validate checks the emulation's values and fills in AcceptLanguage from
+// the locale when it is missing
+func (l *LocationEmulation) Validate() error {
+	if l.Geolocation == nil && l.Timezone == "" && l.Locale == "" && l.AcceptLanguage == "" {
+		return fmt.Errorf("give a geolocation, timezone, locale or accept_language")
+	}
+	if g := l.Geolocation; g != nil {
+		if g.Latitude < -90 || g.Latitude > 90 {
+			return fmt.Errorf("latitude must be between -90 and 90, not %g", g.Latitude)
+		}
+		if g.Longitude < -180 || g.Longitude > 180 {
+			return fmt.Errorf("longitude must be between -180 and 180, not %g", g.Longitude)
+		}
+		if g.Accuracy < 0 {
+			return fmt.Errorf("accuracy cannot be negative")
+		}
+		if g.Accuracy == 0 {
+			g.Accuracy = 100
+		}
+	}
+	if l.Timezone != "" && !timezonePattern.MatchString(l.Timezone) {
+		return fmt.Errorf("timezone %q is not an IANA timezone ID such as America/New_York", l.Timezone)
+	}
+	if l.Locale != "" {
+		if !localePattern.MatchString(l.Locale) {
+			return fmt.Errorf("locale %q is not a language tag such as en-US", l.Locale)
+		}
+		if l.AcceptLanguage == "" {
+			l.AcceptLanguage = acceptLanguageFor(l.Locale)
+		}
+	}
+	return nil
+}
+
+// acceptLanguageFor lists a locale and then its bare language, the way
+// browsers configured for that locale do: "de-DE" becomes "de-DE,de;q=0.9"
+func acceptLanguageFor(locale string) string {
+	language, _, found := strings.Cut(locale, "-")
+	if !found {
+		return locale
+	}
+	return locale + "," + language + ";q=0.9"
+}
+
+// EmulateLocation overrides where a page believes it is, replacing any
+// location it emulated before. Geolocation also grants the geolocation
+// permission, which Chrome holds for the whole browser rather than one page.
+func (m *Manager) EmulateLocation(pageID string, l *LocationEmulation) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+	previous := m.LocationEmulation(pageID)
+
+	if g := l.Geolocation; g != nil {
+		grant := proto.BrowserGrantPermissions{Permissions: []proto.BrowserPermissionType{proto.BrowserPermissionTypeGeolocation}}
+		if err := grant.Call(page.Browser().Context(ctx)); err != nil {
+			return fmt.Errorf("failed to grant geolocation permission: %w", err)
+		}
+		override := proto.EmulationSetGeolocationOverride{Latitude: &g.Latitude, Longitude: &g.Longitude, Accuracy: &g.Accuracy}
+		if err := override.Call(p); err != nil {
+			return fmt.Errorf("failed to set geolocation: %w", err)
+		}
+	} else if previous != nil && previous.Geolocation != nil {
+		if err := (proto.EmulationClearGeolocationOverride{}).Call(p); err != nil {
+			return fmt.Errorf("failed to clear geolocation: %w", err)
+		}
+	}
+
+	// An empty ID or locale restores the browser's own
+	if l.Timezone != "" || previous != nil && previous.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{TimezoneID: l.Timezone}).Call(p); err != nil {
+			return fmt.Errorf("failed to set timezone %q: %w", l.Timezone, err)
+		}
+	}
+	if l.Locale != "" || previous != nil && previous.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{Locale: l.Locale}).Call(p); err != nil {
+			return fmt.Errorf("failed to set locale %q: %w", l.Locale, err)
+		}
+	}
+
+	applied := *l
+	m.emulationMutex.Lock()
+	if m.locations == nil {
+		m.locations = make(map[string]*LocationEmulation)
+	}
+	m.locations[pageID] = &applied
+	m.emulationMutex.Unlock()
+
+	if l.AcceptLanguage != "" || previous != nil && previous.AcceptLanguage != "" {
+		userAgent := ""
+		if d := m.DeviceEmulation(pageID); d != nil {
+			userAgent = d.UserAgent
+		}
+		if err := m.setUserAgent(pageID, p, userAgent); err != nil {
+			return err
+		}
+	}
+
+	m.logger.WithComponent("browser").Debug("Location emulated",
+		zap.String("page_id", pageID),
+		zap.String("timezone", l.Timezone),
+		zap.String("locale", l.Locale),
+		zap.Bool("geolocation", l.Geolocation != nil))
+	return nil
+}
+
+// ClearLocationEmulation returns a page to the browser's own position,
+// timezone and languages
+func (m *Manager) ClearLocationEmulation(pageID string) error {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	previous := m.LocationEmulation(pageID)
+	if previous == nil {
+		return nil
+	}
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	if previous.Geolocation != nil {
+		if err := (proto.EmulationClearGeolocationOverride{}).Call(p); err != nil {
+			return fmt.Errorf("failed to clear geolocation: %w", err)
+		}
+	}
+	if previous.Timezone != "" {
+		if err := (proto.EmulationSetTimezoneOverride{}).Call(p); err != nil {
+			return fmt.Errorf("failed to clear timezone: %w", err)
+		}
+	}
+	if previous.Locale != "" {
+		if err := (proto.EmulationSetLocaleOverride{}).Call(p); err != nil {
+			return fmt.Errorf("failed to clear locale: %w", err)
+		}
+	}
+	m.forgetLocationEmulation(pageID)
+	if previous.AcceptLanguage != "" {
+		userAgent := ""
+		if d := m.DeviceEmulation(pageID); d != nil {
+			userAgent = d.UserAgent
+		}
+		if err := m.setUserAgent(pageID, p, userAgent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LocationEmulation returns the location a page emulates, or nil
+func (m *Manager) LocationEmulation(pageID string) *LocationEmulation {
+	m.emulationMutex.Lock()
+	defer m.emulationMutex.Unlock()
+	if l := m.locations[pageID]; l != nil {
+		clone := *l
+		if l.Geolocation != nil {
+			g := *l.Geolocation
+			clone.Geolocation = &g
+		}
+		return &clone
+	}
+	return nil
+}
+
+// acceptLanguage is the Accept-Language a page's location emulation asks for
+func (m *Manager) acceptLanguage(pageID string) string {
+	m.emulationMutex.Lock()
+	defer m.emulationMutex.Unlock()
+	if l := m.locations[pageID]; l != nil {
+		return l.AcceptLanguage
+	}
+	return ""
+}
+
+// forgetLocationEmulation drops the bookkeeping for a page that is going away
+func (m *Manager) forgetLocationEmulation(pageID string) {
+	m.emulationMutex.Lock()
+	delete(m.locations, pageID)
+	m.emulationMutex.Unlock()
+}
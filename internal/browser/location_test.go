@@ -0,0 +1,52 @@
+package browser
+
+import "testing"
+
+func TestLocationEmulationValidate(t *testing.T) {
+	l := LocationEmulation{Geolocation: &Geolocation{Latitude: 52.52, Longitude: 13.405}, Timezone: "Europe/Berlin", Locale: "de-DE"}
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if l.AcceptLanguage != "de-DE,de;q=0.9" || l.Geolocation.Accuracy != 100 {
+		t.Errorf("Validate filled in %+v", l)
+	}
+
+	// An explicit Accept-Language is left alone
+	l = LocationEmulation{Locale: "fr", AcceptLanguage: "fr-CH,fr;q=0.8"}
+	if err := l.Validate(); err != nil || l.AcceptLanguage != "fr-CH,fr;q=0.8" {
+		t.Errorf("Validate = %v, %+v", err, l)
+	}
+	if acceptLanguageFor("ja") != "ja" {
+		t.Errorf("acceptLanguageFor(ja) = %q", acceptLanguageFor("ja"))
+	}
+
+	for _, bad := range []LocationEmulation{
+		{},
+		{Geolocation: &Geolocation{Latitude: 91}},
+		{Geolocation: &Geolocation{Longitude: -181}},
+		{Geolocation: &Geolocation{Accuracy: -1}},
+		{Timezone: "Europe/ Berlin"},
+		{Locale: "german please"},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestEmulateLocationRequiresKnownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	if err := manager.EmulateLocation("missing", &LocationEmulation{Timezone: "Asia/Tokyo"}); err == nil {
+		t.Error("Expected EmulateLocation on an unknown page to fail")
+	}
+	if err := manager.ClearLocationEmulation("missing"); err == nil {
+		t.Error("Expected ClearLocationEmulation on an unknown page to fail")
+	}
+	if l := manager.LocationEmulation("missing"); l != nil {
+		t.Errorf("LocationEmulation = %+v", l)
+	}
+	if lang := manager.acceptLanguage("missing"); lang != "" {
+		t.Errorf("acceptLanguage = %q", lang)
+	}
+}
@@ -27,6 +27,8 @@ const (
 	NavigationTimeout = 10 * time.Second
 	// Connection timeout - how long to wait when checking if a URL is reachable
 	ConnectionTimeout = 5 * time.Second
+	// Script timeout - how long ExecuteScript lets a script run
+	ScriptTimeout = 10 * time.Second
 )
 
 type Manager struct {
@@ -42,17 +44,67 @@ type Manager struct {
 	// Browser process lifecycle management
 	browserPID        int
 	controlURL        string
+	profileDir        string
 	launcher          *launcher.Launcher
 	healthTicker      *time.Ticker
 	lastHealthy       time.Time
 	restartCount      int
-	maxRestarts       int
+	healthFailures    int        // Failed health checks in a row
 	lastRestart       time.Time  // Track when last restart occurred
 	restartInProgress bool       // Prevent concurrent restart attempts
+
+	// Credentials for proxy and site authentication challenges
+	auth *authenticator
+
+	// Crash forensics: the launched browser's recent output and the sink
+	// told about each bundle
+	chromeOutput *outputTail
+	crashHandler CrashHandler
+	crashMutex   sync.Mutex
 	
 	// Connection monitoring
 	wsConnections  map[string]bool  // Track WebSocket connections
 	connMutex      sync.RWMutex
+
+	// Page event streaming
+	events *pageEventHub
+
+	// Per-page operation queue
+	pageQueue *pageQueue
+
+	// Warm blank pages for fast page creation
+	pool *pagePool
+
+	// Per-page request interception for blocked resource types
+	blocking *resourceBlocker
+
+	// Per-page log of the requests each page made
+	network *networkRecorder
+
+	// Per-page traffic counting for a bandwidth meter
+	bandwidth *bandwidthTracker
+
+	// Launch on first use and idle shutdown (see StartLazy)
+	lazy lazyLauncher
+
+	// Per-page log of console messages, exceptions and browser log entries
+	console *consoleRecorder
+
+	// Pings pages and recovers those that stop responding
+	watchdog pageWatchdog
+
+	// Called after each successful navigation
+	navHook      NavigationHook
+	navHookMutex sync.RWMutex
+
+	// Init scripts installed by ApplyFingerprint, by page
+	fingerprintScripts map[string]proto.PageScriptIdentifier
+	fingerprintMutex   sync.Mutex
+	emulations         map[string]*DeviceEmulation
+	locations          map[string]*LocationEmulation
+	userAgents         map[string]string
+	extraHeaders       map[string]map[string]string
+	emulationMutex     sync.Mutex
 }
 
 type Config struct {
@@ -61,6 +113,59 @@ type Config struct {
 	SlowMotion   time.Duration
 	WindowWidth  int
 	WindowHeight int
+	// Container enables browser flags needed inside Docker/Kubernetes
+	// (no sandbox, no /dev/shm, no GPU)
+	Container bool
+	// PagePoolSize is the number of blank pages kept warm for NewPooledPage (0 disables the pool)
+	PagePoolSize int
+	// NavigationTimeout bounds each navigation and page load (0 uses the
+	// NavigationTimeout constant)
+	NavigationTimeout time.Duration
+	// IdleShutdown closes a browser started with StartLazy after it goes
+	// unused for this long (0 keeps it running)
+	IdleShutdown time.Duration
+	// Health tunes health checks and automatic restarts
+	Health HealthConfig
+	// ProxyServer routes browser traffic through a proxy, as accepted by
+	// Chrome's --proxy-server (see ParseProxyServer); ProxyBypassList lists
+	// hosts reached directly, e.g. "localhost;*.internal"
+	ProxyServer     string
+	ProxyBypassList string
+	// ProxyUsername and ProxyPassword answer the proxy's authentication
+	// challenges
+	ProxyUsername string
+	ProxyPassword string
+	// CrashDir receives a forensic bundle whenever the browser crashes or
+	// is restarted for not responding (empty disables bundles)
+	CrashDir string
+	// LogFile is the server log a bundle copies its last CrashLogLines
+	// lines from (0 uses DefaultCrashLogLines)
+	LogFile       string
+	CrashLogLines int
+}
+
+// DetectContainer reports whether the process appears to run inside a container
+func DetectContainer() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return true
+	}
+	for _, marker := range []string{"/.dockerenv", "/run/.containerenv"} {
+		if _, err := os.Stat(marker); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// applyContainerFlags adds the Chrome flags required to run without the
+// privileges and shared memory a container usually lacks
+func applyContainerFlags(l *launcher.Launcher, config Config) *launcher.Launcher {
+	if !config.Container {
+		return l
+	}
+	return l.NoSandbox(true).
+		Set("disable-dev-shm-usage").
+		Set("disable-gpu")
 }
 
 func NewManager(log *logger.Logger, config Config) *Manager {
@@ -72,9 +177,17 @@ func NewManager(log *logger.Logger, config Config) *Manager {
 		pageURLs:      make(map[string]string),
 		ctx:           ctx,
 		cancel:        cancel,
-		maxRestarts:   3,
+		config:        config,
 		wsConnections: make(map[string]bool),
 		lastHealthy:   time.Now(),
+		events:        newPageEventHub(),
+		pageQueue:     newPageQueue(),
+		pool:          newPagePool(config.PagePoolSize),
+		blocking:      newResourceBlocker(),
+		network:       newNetworkRecorder(),
+		bandwidth:     newBandwidthTracker(),
+		console:       newConsoleRecorder(),
+		auth:          newAuthenticator(),
 	}
 }
 
@@ -84,6 +197,7 @@ func (m *Manager) Start(config Config) error {
 
 	// Store config for potential restarts
 	m.config = config
+	m.pool.setSize(config.PagePoolSize)
 
 	// Find a working browser binary
 	browserPath, err := m.findWorkingBrowser()
@@ -108,6 +222,17 @@ func (m *Manager) Start(config Config) error {
 		l = l.Devtools(true)
 	}
 
+	l = applyContainerFlags(l, config)
+	l = applyProxyFlags(l, config)
+
+	// Keep the browser's recent output for crash bundles
+	output := newOutputTail(chromeOutputLimit)
+	l = l.Logger(output)
+
+	// Keep the profile under ProfileRoot so a crashed run's browser can be found later
+	m.profileDir = newProfileDir()
+	l = l.UserDataDir(m.profileDir)
+
 	// Store launcher for process management
 	m.launcher = l
 	
@@ -171,6 +296,8 @@ func (m *Manager) Start(config Config) error {
 			if config.Debug {
 				l = l.Devtools(true)
 			}
+
+			l = applyProxyFlags(applyContainerFlags(l, config), config).UserDataDir(m.profileDir).Logger(output)
 			
 			// Try fallback launch with timeout
 			urlChan2 := make(chan string, 1)
@@ -271,13 +398,18 @@ func (m *Manager) Start(config Config) error {
 		return fmt.Errorf("browser connected but not responsive: %w", verifyErr)
 	}
 
+	m.installAuthHandler(browser)
+
 	m.mutex.Lock()
 	m.browser = browser
+	m.chromeOutput = output
 	m.lastHealthy = time.Now()
 	m.mutex.Unlock()
 	
 	// Start health monitoring
 	m.startHealthMonitoring()
+
+	m.warmPagePool()
 	
 	duration := time.Since(start).Milliseconds()
 	m.logger.LogBrowserAction("started", url, duration)
@@ -285,7 +417,14 @@ func (m *Manager) Start(config Config) error {
 	return nil
 }
 
+// Stop shuts the browser down for good, ending idle shutdown monitoring
 func (m *Manager) Stop() error {
+	m.lazy.end()
+	return m.stop()
+}
+
+// stop shuts the browser down; restarts call it and then Start again
+func (m *Manager) stop() error {
 	m.logger.LogBrowserAction("stopping", "", 0)
 	start := time.Now()
 
@@ -312,6 +451,7 @@ func (m *Manager) Stop() error {
 		}
 	}
 	m.pages = make(map[string]*rod.Page)
+	m.drainPagePool()
 
 	// Close browser safely with multiple nil checks and panic recovery
 	if m.browser != nil {
@@ -339,6 +479,8 @@ func (m *Manager) Stop() error {
 		m.browser = nil // Ensure it's marked as nil after close attempt
 	}
 
+	m.removeProfile()
+
 	// Cancel context safely
 	if m.cancel != nil {
 		m.cancel()
@@ -351,8 +493,30 @@ func (m *Manager) Stop() error {
 }
 
 func (m *Manager) NewPage(url string) (*rod.Page, string, error) {
+	return m.NewPageWithOptions(url, PageOptions{})
+}
+
+// NewPageWithOptions creates a page, applies opts, then navigates to url
+func (m *Manager) NewPageWithOptions(url string, opts PageOptions) (*rod.Page, string, error) {
+	return m.NewPageWithOptionsContext(context.Background(), url, opts)
+}
+
+// NewPageWithOptionsContext is NewPageWithOptions finished by ctx's deadline
+func (m *Manager) NewPageWithOptionsContext(ctx context.Context, url string, opts PageOptions) (*rod.Page, string, error) {
 	start := time.Now()
 
+	if err := checkDeadline(ctx); err != nil {
+		return nil, "", err
+	}
+	if _, err := NormalizeResourceTypes(opts.BlockResources); err != nil {
+		return nil, "", err
+	}
+	release, err := m.ensureBrowser()
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
 	m.mutex.RLock()
 	browser := m.browser
 	m.mutex.RUnlock()
@@ -366,7 +530,7 @@ func (m *Manager) NewPage(url string) (*rod.Page, string, error) {
 		m.logger.WithComponent("browser").Warn("Browser connection unhealthy, attempting restart", zap.Error(err))
 		
 		// Attempt to restart browser
-		if restartErr := m.restartBrowser(); restartErr != nil {
+		if restartErr := m.restartBrowser(fmt.Sprintf("browser connection unhealthy: %v", err)); restartErr != nil {
 			return nil, "", fmt.Errorf("browser connection unhealthy and restart failed: %w", restartErr)
 		}
 		
@@ -383,7 +547,6 @@ func (m *Manager) NewPage(url string) (*rod.Page, string, error) {
 	// Use Page() instead of MustPage() to handle connection errors gracefully
 	// Add timeout and panic recovery for Page creation
 	var page *rod.Page
-	var err error
 	
 	func() {
 		defer func() {
@@ -392,16 +555,22 @@ func (m *Manager) NewPage(url string) (*rod.Page, string, error) {
 			}
 		}()
 		
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		createCtx, cancel := stepContext(ctx, 5*time.Second)
 		defer cancel()
 		
-		page, err = browser.Context(ctx).Page(proto.TargetCreateTarget{})
+		page, err = browser.Context(createCtx).Page(proto.TargetCreateTarget{})
 	}()
 	
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to create new page: %w", err)
+		return nil, "", deadlineError(ctx, fmt.Errorf("failed to create new page: %w", err))
 	}
 
+	return m.registerPage(ctx, page, url, opts, start)
+}
+
+// registerPage tracks a freshly created or pooled page, applies opts and
+// navigates it to url by ctx's deadline
+func (m *Manager) registerPage(ctx context.Context, page *rod.Page, url string, opts PageOptions, start time.Time) (*rod.Page, string, error) {
 	pageID := fmt.Sprintf("page_%d", time.Now().UnixNano())
 
 	// Normalize URL for storage and navigation
@@ -418,41 +587,78 @@ func (m *Manager) NewPage(url string) (*rod.Page, string, error) {
 	m.pageURLs[pageID] = normalizedURL  // Store normalized URL for reliable retrieval
 	m.mutex.Unlock()
 
+	m.watchPageEvents(pageID, page)
+
+	if len(opts.BlockResources) > 0 {
+		if err := m.applyResourceBlocking(pageID, page, opts.BlockResources); err != nil {
+			m.closePage(pageID)
+			return nil, "", err
+		}
+	}
+
+	if opts.Fingerprint != nil {
+		if err := m.applyFingerprint(pageID, page, opts.Fingerprint); err != nil {
+			m.closePage(pageID)
+			return nil, "", err
+		}
+	}
+
 	if normalizedURL != "" {
 		// Check if URL is reachable first
-		if err := m.isURLReachable(normalizedURL); err != nil {
+		if err := m.isURLReachable(ctx, normalizedURL); err != nil {
 			m.closePage(pageID)
-			return nil, "", fmt.Errorf("URL not reachable: %w", err)
+			return nil, "", deadlineError(ctx, fmt.Errorf("URL not reachable: %w", err))
 		}
 
 		// Navigate with timeout
-		ctx, cancel := context.WithTimeout(context.Background(), NavigationTimeout)
+		navCtx, cancel := stepContext(ctx, m.navigationTimeout())
 		defer cancel()
 		
-		if err := page.Context(ctx).Navigate(normalizedURL); err != nil {
+		if err := page.Context(navCtx).Navigate(normalizedURL); err != nil {
 			m.closePage(pageID)
-			return nil, "", fmt.Errorf("failed to navigate to %s: %w", normalizedURL, err)
+			return nil, "", deadlineError(ctx, fmt.Errorf("failed to navigate to %s: %w", normalizedURL, err))
 		}
 
 		// Wait for page load with timeout
-		if err := page.Context(ctx).WaitLoad(); err != nil {
+		if err := page.Context(navCtx).WaitLoad(); err != nil {
 			m.closePage(pageID)
-			return nil, "", fmt.Errorf("failed to wait for page load: %w", err)
+			return nil, "", deadlineError(ctx, fmt.Errorf("failed to wait for page load: %w", err))
 		}
 	}
 
 	duration := time.Since(start).Milliseconds()
 	m.logger.LogBrowserAction("page_created", normalizedURL, duration)
 
+	if normalizedURL != "" {
+		m.runNavigationHook(pageID, normalizedURL)
+	}
+
 	return page, pageID, nil
 }
 
+// navigationTimeout is the configured limit for a navigation and page load
+func (m *Manager) navigationTimeout() time.Duration {
+	if m.config.NavigationTimeout > 0 {
+		return m.config.NavigationTimeout
+	}
+	return NavigationTimeout
+}
+
 func (m *Manager) GetPage(pageID string) (*rod.Page, error) {
+	m.touch()
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
+	// A page being recovered fails fast instead of timing out
+	recovering, last := m.watchdog.status(pageID, time.Time{})
+	if recovering {
+		return nil, fmt.Errorf("page %s stopped responding and is being recovered; retry in a few seconds", pageID)
+	}
 	page, exists := m.pages[pageID]
 	if !exists {
+		if last != nil && last.Action == PageRecoveryClose {
+			return nil, fmt.Errorf("page not found: %s; %s", pageID, last)
+		}
 		return nil, fmt.Errorf("page not found: %s", pageID)
 	}
 
@@ -463,9 +669,61 @@ func (m *Manager) ClosePage(pageID string) error {
 	return m.closePage(pageID)
 }
 
+// CloseAllPages closes every open page concurrently, giving up when ctx
+// expires. It returns the number of pages closed cleanly.
+func (m *Manager) CloseAllPages(ctx context.Context) int {
+	pageIDs := m.ListPages()
+	if len(pageIDs) == 0 {
+		return 0
+	}
+
+	results := make(chan error, len(pageIDs))
+	for _, pageID := range pageIDs {
+		go func(id string) {
+			defer func() {
+				if r := recover(); r != nil {
+					results <- fmt.Errorf("closing page %s panicked: %v", id, r)
+				}
+			}()
+			results <- m.closePage(id)
+		}(pageID)
+	}
+
+	closed := 0
+	for range pageIDs {
+		select {
+		case err := <-results:
+			if err == nil {
+				closed++
+			}
+		case <-ctx.Done():
+			m.logger.WithComponent("browser").Warn("Gave up closing pages",
+				zap.Int("closed", closed),
+				zap.Int("total", len(pageIDs)))
+			return closed
+		}
+	}
+	return closed
+}
+
 func (m *Manager) closePage(pageID string) error {
 	start := time.Now()
 
+	if _, err := m.GetPage(pageID); err != nil {
+		return err
+	}
+
+	// Let an in-flight operation on this page finish, but never block closing for long
+	queueCtx, queueCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	release, err := m.AcquirePage(queueCtx, pageID)
+	queueCancel()
+	if err == nil {
+		defer func() {
+			release()
+			m.pageQueue.forget(pageID)
+		}()
+	}
+
 	m.mutex.Lock()
 	page, exists := m.pages[pageID]
 	if exists {
@@ -478,6 +736,10 @@ func (m *Manager) closePage(pageID string) error {
 		return fmt.Errorf("page not found: %s", pageID)
 	}
 
+	m.stopPageEvents(pageID)
+	m.forgetPageSettings(pageID)
+	m.watchdog.forget(pageID)
+
 	// Use a separate timeout context for closing to avoid context cancellation issues
 	closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -505,6 +767,11 @@ func (m *Manager) ListPages() []string {
 }
 
 func (m *Manager) Screenshot(pageID string) ([]byte, error) {
+	return m.ScreenshotContext(context.Background(), pageID)
+}
+
+// ScreenshotContext is Screenshot finished by ctx's deadline
+func (m *Manager) ScreenshotContext(ctx context.Context, pageID string) ([]byte, error) {
 	start := time.Now()
 
 	page, err := m.GetPage(pageID)
@@ -512,13 +779,19 @@ func (m *Manager) Screenshot(pageID string) ([]byte, error) {
 		return nil, err
 	}
 
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Add timeout context for screenshot operation
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	shotCtx, cancel := stepContext(ctx, 10*time.Second)
 	defer cancel()
 
-	screenshot, err := page.Context(ctx).Screenshot(true, nil)
+	screenshot, err := page.Context(shotCtx).Screenshot(true, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to take screenshot: %w", err)
+		return nil, m.pageFailure(pageID, start, deadlineError(ctx, fmt.Errorf("failed to take screenshot: %w", err)))
 	}
 
 	duration := time.Since(start).Milliseconds()
@@ -527,7 +800,71 @@ func (m *Manager) Screenshot(pageID string) ([]byte, error) {
 	return screenshot, nil
 }
 
+// ElementScreenshot captures a PNG of the first element matching selector
+func (m *Manager) ElementScreenshot(pageID, selector string) ([]byte, error) {
+	return m.ElementScreenshotContext(context.Background(), pageID, selector)
+}
+
+// ElementScreenshotContext is ElementScreenshot finished by ctx's deadline
+func (m *Manager) ElementScreenshotContext(ctx context.Context, pageID, selector string) ([]byte, error) {
+	start := time.Now()
+
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	shotCtx, cancel := stepContext(ctx, 10*time.Second)
+	defer cancel()
+
+	el, err := sel.Find(page.Context(shotCtx))
+	if err != nil {
+		return nil, m.pageFailure(pageID, start, deadlineError(ctx, err))
+	}
+	screenshot, err := el.Screenshot(proto.PageCaptureScreenshotFormatPng, 0)
+	if err != nil {
+		return nil, m.pageFailure(pageID, start, deadlineError(ctx, fmt.Errorf("failed to take element screenshot: %w", err)))
+	}
+
+	duration := time.Since(start).Milliseconds()
+	m.logger.LogBrowserAction("element_screenshot", pageID, duration)
+
+	return screenshot, nil
+}
+
 func (m *Manager) ExecuteScript(pageID string, script string) (interface{}, error) {
+	return m.ExecuteScriptWithTimeout(pageID, script, ScriptTimeout)
+}
+
+// ExecuteScriptWithTimeout is ExecuteScript with a caller-chosen limit on
+// how long the script may run
+func (m *Manager) ExecuteScriptWithTimeout(pageID string, script string, timeout time.Duration) (interface{}, error) {
+	return m.executeScript(context.Background(), pageID, nil, script, timeout)
+}
+
+// ExecuteScriptContext is ExecuteScriptWithTimeout cut short by ctx's
+// deadline when that comes first; the wait for the page counts against it
+func (m *Manager) ExecuteScriptContext(ctx context.Context, pageID string, script string, timeout time.Duration) (interface{}, error) {
+	return m.executeScript(ctx, pageID, nil, script, timeout)
+}
+
+// ExecuteScriptInFrame is ExecuteScript run inside the iframe at frames;
+// finding the frame counts against the script's time limit
+func (m *Manager) ExecuteScriptInFrame(pageID string, frames FramePath, script string) (interface{}, error) {
+	return m.executeScript(context.Background(), pageID, frames, script, ScriptTimeout)
+}
+
+func (m *Manager) executeScript(ctx context.Context, pageID string, frames FramePath, script string, timeout time.Duration) (interface{}, error) {
 	start := time.Now()
 
 	page, err := m.GetPage(pageID)
@@ -535,6 +872,12 @@ func (m *Manager) ExecuteScript(pageID string, script string) (interface{}, erro
 		return nil, err
 	}
 
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	// Clean up the script
 	script = strings.TrimSpace(script)
 	
@@ -577,13 +920,18 @@ func (m *Manager) ExecuteScript(pageID string, script string) (interface{}, erro
 	}
 
 	// Add timeout context for script execution
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	scriptCtx, cancel := stepContext(ctx, timeout)
 	defer cancel()
 
+	target, err := enterFrames(page.Context(scriptCtx), frames)
+	if err != nil {
+		return nil, m.pageFailure(pageID, start, deadlineError(ctx, err))
+	}
+
 	// Execute the script using page.Eval
-	result, err := page.Context(ctx).Eval(wrappedScript)
+	result, err := target.Eval(wrappedScript)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute script: %w", err)
+		return nil, m.pageFailure(pageID, start, deadlineError(ctx, fmt.Errorf("failed to execute script: %w", err)))
 	}
 
 	duration := time.Since(start).Milliseconds()
@@ -593,6 +941,22 @@ func (m *Manager) ExecuteScript(pageID string, script string) (interface{}, erro
 }
 
 func (m *Manager) NavigateExistingPage(pageID string, url string) error {
+	return m.NavigateExistingPageContext(context.Background(), pageID, url)
+}
+
+// NavigateExistingPageContext is NavigateExistingPage finished by ctx's
+// deadline; the wait for the page and the reachability check count
+// against it too
+func (m *Manager) NavigateExistingPageContext(ctx context.Context, pageID string, url string) error {
+	if err := m.navigateExistingPage(ctx, pageID, url); err != nil {
+		return err
+	}
+	// The hook runs after the page lock is released so it can script the page
+	m.runNavigationHook(pageID, url)
+	return nil
+}
+
+func (m *Manager) navigateExistingPage(ctx context.Context, pageID string, url string) error {
 	start := time.Now()
 
 	page, err := m.GetPage(pageID)
@@ -600,24 +964,30 @@ func (m *Manager) NavigateExistingPage(pageID string, url string) error {
 		return err
 	}
 
+	release, err := m.acquirePageContext(ctx, pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	// Check if URL is reachable first (skip for empty URLs)
 	if url != "" {
-		if err := m.isURLReachable(url); err != nil {
-			return fmt.Errorf("URL not reachable: %w", err)
+		if err := m.isURLReachable(ctx, url); err != nil {
+			return deadlineError(ctx, fmt.Errorf("URL not reachable: %w", err))
 		}
 	}
 
 	// Navigate with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), NavigationTimeout)
+	navCtx, cancel := stepContext(ctx, m.navigationTimeout())
 	defer cancel()
 
-	if err := page.Context(ctx).Navigate(url); err != nil {
-		return fmt.Errorf("failed to navigate to %s: %w", url, err)
+	if err := page.Context(navCtx).Navigate(url); err != nil {
+		return m.pageFailure(pageID, start, deadlineError(ctx, fmt.Errorf("failed to navigate to %s: %w", url, err)))
 	}
 
 	// Wait for page load with timeout
-	if err := page.Context(ctx).WaitLoad(); err != nil {
-		return fmt.Errorf("failed to wait for page load: %w", err)
+	if err := page.Context(navCtx).WaitLoad(); err != nil {
+		return m.pageFailure(pageID, start, deadlineError(ctx, fmt.Errorf("failed to wait for page load: %w", err)))
 	}
 
 	duration := time.Since(start).Milliseconds()
@@ -691,6 +1061,11 @@ func (m *Manager) GetPageInfo(pageID string) (map[string]interface{}, error) {
 	return info, nil
 }
 
+// IsVisible reports whether the browser runs with a window
+func (m *Manager) IsVisible() bool {
+	return !m.config.Headless
+}
+
 func (m *Manager) SetVisibility(visible bool) error {
 	m.logger.LogBrowserAction("set_visibility", "", 0)
 	start := time.Now()
@@ -700,6 +1075,11 @@ func (m *Manager) SetVisibility(visible bool) error {
 	m.mutex.RUnlock()
 	
 	if browser == nil {
+		if m.parked() {
+			// The next launch picks the setting up
+			m.config.Headless = !visible
+			return nil
+		}
 		return fmt.Errorf("browser not started")
 	}
 
@@ -729,7 +1109,7 @@ func (m *Manager) SetVisibility(visible bool) error {
 	m.config.Headless = !visible
 
 	// Stop current browser
-	if err := m.Stop(); err != nil {
+	if err := m.stop(); err != nil {
 		return fmt.Errorf("failed to stop browser for visibility change: %w", err)
 	}
 
@@ -774,6 +1154,12 @@ func (m *Manager) SetVisibility(visible bool) error {
 	return nil
 }
 
+// FindBrowser reports which browser binary Start would use. An empty path
+// means no system browser works and Rod will download its own.
+func (m *Manager) FindBrowser() (string, error) {
+	return m.findWorkingBrowser()
+}
+
 // findWorkingBrowser attempts to find a working browser binary with proper fallbacks
 func (m *Manager) findWorkingBrowser() (string, error) {
 	// Check for environment variable override first
@@ -846,7 +1232,7 @@ func (m *Manager) isBrowserWorking(browserPath string) bool {
 }
 
 // isURLReachable checks if a URL is reachable before attempting navigation
-func (m *Manager) isURLReachable(targetURL string) error {
+func (m *Manager) isURLReachable(ctx context.Context, targetURL string) error {
 	// Skip check for empty URLs and file:// URLs
 	if targetURL == "" || strings.HasPrefix(targetURL, "file://") {
 		return nil
@@ -876,10 +1262,10 @@ func (m *Manager) isURLReachable(targetURL string) error {
 		}
 		
 		// Use HEAD request for faster check
-		ctx, cancel := context.WithTimeout(context.Background(), ConnectionTimeout)
+		headCtx, cancel := stepContext(ctx, ConnectionTimeout)
 		defer cancel()
 		
-		req, err := http.NewRequestWithContext(ctx, "HEAD", targetURL, nil)
+		req, err := http.NewRequestWithContext(headCtx, "HEAD", targetURL, nil)
 		if err != nil {
 			// For well-known domains like example.com, don't fail on request creation errors
 			if strings.Contains(targetURL, "example.com") {
@@ -947,8 +1333,9 @@ func (m *Manager) testBrowserConnection(browser *rod.Browser) error {
 	return err
 }
 
-// restartBrowser safely restarts the browser with improved error handling
-func (m *Manager) restartBrowser() error {
+// restartBrowser safely restarts the browser with improved error handling.
+// reason says what went wrong, for the crash bundle.
+func (m *Manager) restartBrowser(reason string) error {
 	m.mutex.Lock()
 	// Check if restart is already in progress
 	if m.restartInProgress {
@@ -958,9 +1345,10 @@ func (m *Manager) restartBrowser() error {
 	}
 	
 	// Check restart count to prevent infinite loops
-	if m.restartCount >= m.maxRestarts {
+	health := m.health()
+	if m.restartCount >= health.MaxRestarts {
 		m.mutex.Unlock()
-		return fmt.Errorf("browser restart limit exceeded (%d/%d)", m.restartCount, m.maxRestarts)
+		return fmt.Errorf("browser restart limit exceeded (%d/%d)", m.restartCount, health.MaxRestarts)
 	}
 	
 	// Mark restart as in progress
@@ -968,6 +1356,7 @@ func (m *Manager) restartBrowser() error {
 	m.restartCount++
 	currentRestartCount := m.restartCount
 	m.lastRestart = time.Now()
+	m.recordCrash(reason, m.pageSnapshotLocked(), m.browserPID, currentRestartCount, m.chromeOutput)
 	m.mutex.Unlock()
 	
 	// Ensure we clear the restart flag when done
@@ -977,9 +1366,11 @@ func (m *Manager) restartBrowser() error {
 		m.mutex.Unlock()
 	}()
 	
+	delay := health.restartDelay(currentRestartCount, nil)
 	m.logger.WithComponent("browser").Info("Attempting to restart browser",
 		zap.Int("restart_attempt", currentRestartCount),
-		zap.Int("max_restarts", m.maxRestarts))
+		zap.Int("max_restarts", health.MaxRestarts),
+		zap.Duration("backoff", delay))
 	
 	// Stop browser with extra safety (ignore panics)
 	func() {
@@ -988,11 +1379,11 @@ func (m *Manager) restartBrowser() error {
 				m.logger.WithComponent("browser").Warn("Panic during browser stop, continuing", zap.Any("panic", r))
 			}
 		}()
-		m.Stop()
+		m.stop()
 	}()
 	
-	// Wait a bit before restarting to avoid rapid restart loops
-	time.Sleep(2 * time.Second)
+	// Back off before restarting to avoid rapid restart loops
+	time.Sleep(delay)
 	
 	// Create new context
 	m.ctx, m.cancel = context.WithCancel(context.Background())
@@ -1015,6 +1406,10 @@ func (m *Manager) CheckHealth() error {
 	m.mutex.RUnlock()
 	
 	if browser == nil {
+		if m.parked() {
+			// A lazy browser launches when it is next needed
+			return nil
+		}
 		return fmt.Errorf("browser not started")
 	}
 	
@@ -1076,7 +1471,7 @@ func (m *Manager) EnsureHealthy() error {
 			zap.Error(err))
 		
 		// Attempt to restart the browser
-		if restartErr := m.restartBrowser(); restartErr != nil {
+		if restartErr := m.restartBrowser(fmt.Sprintf("browser unhealthy: %v", err)); restartErr != nil {
 			// Check if it's because a restart is already in progress
 			if strings.Contains(restartErr.Error(), "already in progress") {
 				m.logger.WithComponent("browser").Debug("Restart already in progress from another routine")
@@ -1215,6 +1610,33 @@ func (m *Manager) extractBrowserPID(controlURL string) int {
 	return 0
 }
 
+// removeProfile waits briefly for the browser to exit and deletes its profile directory
+func (m *Manager) removeProfile() {
+	if m.launcher == nil || m.profileDir == "" {
+		return
+	}
+	l := m.launcher
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Profile cleanup panicked", zap.Any("panic", r))
+			}
+		}()
+		l.Cleanup()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		// Left for RemoveStaleProfiles on the next start
+		m.logger.WithComponent("browser").Debug("Browser still running, leaving profile for later cleanup",
+			zap.String("profile", m.profileDir))
+	}
+}
+
 // startHealthMonitoring starts periodic health checks of the browser process
 func (m *Manager) startHealthMonitoring() {
 	// Stop existing ticker with proper locking
@@ -1222,7 +1644,8 @@ func (m *Manager) startHealthMonitoring() {
 	if m.healthTicker != nil {
 		m.healthTicker.Stop()
 	}
-	m.healthTicker = time.NewTicker(10 * time.Second)
+	m.healthTicker = time.NewTicker(m.health().CheckInterval)
+	m.healthFailures = 0
 	ticker := m.healthTicker // Local copy for goroutine
 	m.mutex.Unlock()
 	
@@ -1260,7 +1683,7 @@ func (m *Manager) performHealthCheck() {
 	if pid > 0 && !m.isProcessRunning(pid) {
 		m.logger.WithComponent("browser").Warn("Browser process died", 
 			zap.Int("pid", pid))
-		m.handleBrowserDeath()
+		m.handleBrowserDeath(fmt.Sprintf("browser process %d exited", pid))
 		return
 	}
 	
@@ -1281,21 +1704,31 @@ func (m *Manager) performHealthCheck() {
 	}()
 	
 	if err != nil {
-		m.logger.WithComponent("browser").Warn("Browser health check failed", 
-			zap.Error(err))
 		// Don't immediately restart - wait for multiple failures
+		threshold := m.health().FailureThreshold
 		m.mutex.Lock()
+		m.healthFailures++
+		failures := m.healthFailures
 		timeSinceHealthy := time.Since(m.lastHealthy)
 		m.mutex.Unlock()
-		
-		if timeSinceHealthy > 30*time.Second {
-			m.logger.WithComponent("browser").Warn("Browser unresponsive for too long, marking for restart")
-			m.handleBrowserDeath()
+
+		m.logger.WithComponent("browser").Warn("Browser health check failed", 
+			zap.Error(err),
+			zap.Int("consecutive_failures", failures),
+			zap.Int("failure_threshold", threshold))
+		if failures >= threshold {
+			m.logger.WithComponent("browser").Warn("Browser unresponsive for too long, marking for restart",
+				zap.Duration("unhealthy_for", timeSinceHealthy))
+			m.handleBrowserDeath(fmt.Sprintf("browser unresponsive after %d failed health checks: %v", failures, err))
 		}
 	} else {
 		m.mutex.Lock()
 		m.lastHealthy = time.Now()
+		m.healthFailures = 0
 		m.mutex.Unlock()
+
+		// With the browser answering, a page that does not is hung on its own
+		m.checkPages()
 	}
 }
 
@@ -1316,19 +1749,22 @@ func (m *Manager) isProcessRunning(pid int) bool {
 }
 
 // handleBrowserDeath handles when the browser process dies unexpectedly
-func (m *Manager) handleBrowserDeath() {
+func (m *Manager) handleBrowserDeath(reason string) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	
-	if m.restartCount >= m.maxRestarts {
+	health := m.health()
+	if m.restartCount >= health.MaxRestarts {
 		m.logger.WithComponent("browser").Error("Browser restart limit exceeded", 
 			zap.Int("restart_count", m.restartCount),
-			zap.Int("max_restarts", m.maxRestarts))
+			zap.Int("max_restarts", health.MaxRestarts))
 		return
 	}
+	delay := health.restartDelay(m.restartCount+1, nil)
 	
 	m.logger.WithComponent("browser").Info("Attempting automatic browser restart", 
-		zap.Int("restart_attempt", m.restartCount+1))
+		zap.Int("restart_attempt", m.restartCount+1),
+		zap.Duration("backoff", delay))
 	
 	// Stop health monitoring during restart
 	if m.healthTicker != nil {
@@ -1336,6 +1772,9 @@ func (m *Manager) handleBrowserDeath() {
 		m.healthTicker = nil
 	}
 	
+	// Collect the evidence before the page list is cleared
+	m.recordCrash(reason, m.pageSnapshotLocked(), m.browserPID, m.restartCount+1, m.chromeOutput)
+
 	// Clean up current browser
 	m.browser = nil
 	m.browserPID = 0
@@ -1348,6 +1787,8 @@ func (m *Manager) handleBrowserDeath() {
 	
 	// Increment restart count
 	m.restartCount++
+	m.lastRestart = time.Now()
+	m.healthFailures = 0
 	
 	// Restart browser in background
 	go func() {
@@ -1358,8 +1799,8 @@ func (m *Manager) handleBrowserDeath() {
 			}
 		}()
 		
-		// Wait a bit before restart
-		time.Sleep(2 * time.Second)
+		// Back off before restarting
+		time.Sleep(delay)
 		
 		if err := m.Start(m.config); err != nil {
 			m.logger.WithComponent("browser").Error("Failed to restart browser", 
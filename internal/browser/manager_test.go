@@ -1,6 +1,7 @@
 package browser
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -322,19 +323,19 @@ func TestIsURLReachable(t *testing.T) {
 	manager := NewManager(log, config)
 	
 	// Test file:// URL (should always pass)
-	err := manager.isURLReachable("file:///tmp/test.html")
+	err := manager.isURLReachable(context.Background(), "file:///tmp/test.html")
 	if err != nil {
 		t.Errorf("file:// URL should be reachable: %v", err)
 	}
 	
 	// Test invalid URL
-	err = manager.isURLReachable("invalid://url")
+	err = manager.isURLReachable(context.Background(), "invalid://url")
 	if err == nil {
 		t.Error("Invalid URL should not be reachable")
 	}
 	
 	// Test unreachable HTTP URL
-	err = manager.isURLReachable("http://nonexistent.localhost:99999/test")
+	err = manager.isURLReachable(context.Background(), "http://nonexistent.localhost:99999/test")
 	if err == nil {
 		t.Error("Unreachable URL should not be reachable")
 	}
@@ -345,7 +346,7 @@ func TestIsURLReachable(t *testing.T) {
 	}))
 	defer server.Close()
 	
-	err = manager.isURLReachable(server.URL)
+	err = manager.isURLReachable(context.Background(), server.URL)
 	if err != nil {
 		t.Errorf("Test server URL should be reachable: %v", err)
 	}
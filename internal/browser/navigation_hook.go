@@ -0,0 +1,39 @@
+package browser
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// NavigationHook runs after a page finishes loading a URL, whether from page
+// creation or navigation of an existing page
+type NavigationHook func(pageID, url string)
+
+// SetNavigationHook installs a hook run after every successful navigation,
+// e.g. to dismiss cookie banners. Pass nil to remove it.
+func (m *Manager) SetNavigationHook(hook NavigationHook) {
+	m.navHookMutex.Lock()
+	defer m.navHookMutex.Unlock()
+	m.navHook = hook
+}
+
+// runNavigationHook calls the hook, if any. A failing hook never fails the
+// navigation it follows.
+func (m *Manager) runNavigationHook(pageID, url string) {
+	m.navHookMutex.RLock()
+	hook := m.navHook
+	m.navHookMutex.RUnlock()
+	if hook == nil {
+		return
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.WithComponent("browser").Error("Navigation hook panicked",
+				zap.String("page_id", pageID),
+				zap.String("panic", fmt.Sprint(r)))
+		}
+	}()
+	hook(pageID, url)
+}
@@ -0,0 +1,28 @@
+package browser
+
+import (
+	"testing"
+
+	"rodmcp/internal/logger"
+)
+
+func TestNavigationHook(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+
+	// No hook installed is a no-op
+	manager.runNavigationHook("page_1", "https://example.com")
+
+	var gotPage, gotURL string
+	manager.SetNavigationHook(func(pageID, url string) {
+		gotPage, gotURL = pageID, url
+	})
+	manager.runNavigationHook("page_1", "https://example.com")
+	if gotPage != "page_1" || gotURL != "https://example.com" {
+		t.Errorf("hook got %q %q", gotPage, gotURL)
+	}
+
+	// A panicking hook must not take the navigation down with it
+	manager.SetNavigationHook(func(pageID, url string) { panic("boom") })
+	manager.runNavigationHook("page_1", "https://example.com")
+}
@@ -0,0 +1,287 @@
+package browser
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// maxNetworkEntries bounds each page's request log; the oldest entries are
+// dropped first
+const maxNetworkEntries = 2000
+
+// NetworkRequest is one request made by a page, as seen by the browser
+type NetworkRequest struct {
+	RequestID    string    `json:"request_id"`
+	URL          string    `json:"url"`
+	Method       string    `json:"method"`
+	ResourceType string    `json:"resource_type"`
+	DocumentURL  string    `json:"document_url,omitempty"`
+	Initiator    string    `json:"initiator,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	// MixedContent is the browser's classification of an insecure request
+	// from a secure page: "blockable" or "optionally-blockable"
+	MixedContent string `json:"mixed_content,omitempty"`
+
+	Status        int     `json:"status,omitempty"`
+	MimeType      string  `json:"mime_type,omitempty"`
+	Protocol      string  `json:"protocol,omitempty"`
+	RemoteIP      string  `json:"remote_ip,omitempty"`
+	FromCache     bool    `json:"from_cache,omitempty"`
+	RedirectedTo  string  `json:"redirected_to,omitempty"`
+	EncodedBytes  float64 `json:"encoded_bytes,omitempty"`
+	DurationMs    float64 `json:"duration_ms,omitempty"`
+	Finished      bool    `json:"finished"`
+	Failed        bool    `json:"failed,omitempty"`
+	ErrorText     string  `json:"error_text,omitempty"`
+	BlockedReason string  `json:"blocked_reason,omitempty"`
+
+	started proto.MonotonicTime
+	// seq orders requests across every page's log; see networkRecorder.seq
+	seq uint64
+}
+
+// pageNetworkLog is the request log of one page since its last top-level
+// navigation
+type pageNetworkLog struct {
+	requests []*NetworkRequest
+	// inFlight maps a request ID to its latest entry; a redirect reuses
+	// the ID for the next hop
+	inFlight map[proto.NetworkRequestID]*NetworkRequest
+	dropped  int
+	// lastStarted is the seq of the latest request to start
+	lastStarted uint64
+}
+
+// networkRecorder keeps the request log of every managed page, and the
+// captures running on them
+type networkRecorder struct {
+	mutex    sync.Mutex
+	pages    map[string]*pageNetworkLog
+	captures map[string]*NetworkCapture
+	// seq counts the requests recorded so far, so waiters can tell the
+	// requests that started after they began
+	seq uint64
+	// changed is closed and replaced whenever a log changes
+	changed chan struct{}
+}
+
+func newNetworkRecorder() *networkRecorder {
+	return &networkRecorder{
+		pages:    make(map[string]*pageNetworkLog),
+		captures: make(map[string]*NetworkCapture),
+		changed:  make(chan struct{}),
+	}
+}
+
+// notify wakes everyone waiting on the logs; the caller holds the mutex
+func (r *networkRecorder) notify() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+func (r *networkRecorder) log(pageID string) *pageNetworkLog {
+	l, ok := r.pages[pageID]
+	if !ok {
+		l = &pageNetworkLog{inFlight: make(map[proto.NetworkRequestID]*NetworkRequest)}
+		r.pages[pageID] = l
+	}
+	return l
+}
+
+func (r *networkRecorder) reset(pageID string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.pages, pageID)
+	r.notify()
+}
+
+func (r *networkRecorder) requestWillBeSent(pageID string, mainFrame proto.PageFrameID, e *proto.NetworkRequestWillBeSent) {
+	if e.Request == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	defer r.notify()
+
+	// A new top-level document starts a fresh log
+	navigation := e.Type == proto.NetworkResourceTypeDocument &&
+		string(e.RequestID) == string(e.LoaderID) &&
+		(mainFrame == "" || e.FrameID == mainFrame)
+	if navigation && e.RedirectResponse == nil {
+		delete(r.pages, pageID)
+	}
+	l := r.log(pageID)
+
+	if previous, ok := l.inFlight[e.RequestID]; ok && e.RedirectResponse != nil {
+		previous.redirected(e)
+	}
+
+	entry := newNetworkRequest(e)
+	r.seq++
+	entry.seq = r.seq
+	l.lastStarted = r.seq
+	l.requests = append(l.requests, entry)
+	l.inFlight[e.RequestID] = entry
+	if over := len(l.requests) - maxNetworkEntries; over > 0 {
+		for _, old := range l.requests[:over] {
+			if l.inFlight[proto.NetworkRequestID(old.RequestID)] == old {
+				delete(l.inFlight, proto.NetworkRequestID(old.RequestID))
+			}
+		}
+		l.requests = append([]*NetworkRequest(nil), l.requests[over:]...)
+		l.dropped += over
+	}
+}
+
+// newNetworkRequest starts an entry for a request the browser is sending
+func newNetworkRequest(e *proto.NetworkRequestWillBeSent) *NetworkRequest {
+	entry := &NetworkRequest{
+		RequestID:    string(e.RequestID),
+		URL:          e.Request.URL,
+		Method:       e.Request.Method,
+		ResourceType: string(e.Type),
+		DocumentURL:  e.DocumentURL,
+		StartedAt:    e.WallTime.Time(),
+		started:      e.Timestamp,
+	}
+	if e.Request.MixedContentType != "" && e.Request.MixedContentType != proto.SecurityMixedContentTypeNone {
+		entry.MixedContent = string(e.Request.MixedContentType)
+	}
+	if e.Initiator != nil {
+		entry.Initiator = string(e.Initiator.Type)
+		if e.Initiator.URL != "" {
+			entry.Initiator += " " + e.Initiator.URL
+		}
+	}
+	return entry
+}
+
+// elapsedMs is the time from the request starting to t
+func (entry *NetworkRequest) elapsedMs(t proto.MonotonicTime) float64 {
+	return float64((t.Duration() - entry.started.Duration()).Microseconds()) / 1000
+}
+
+// redirected finishes an entry whose request e redirects to a new URL
+func (entry *NetworkRequest) redirected(e *proto.NetworkRequestWillBeSent) {
+	entry.responded(e.RedirectResponse)
+	entry.RedirectedTo = e.Request.URL
+	entry.DurationMs = entry.elapsedMs(e.Timestamp)
+	entry.Finished = true
+}
+
+func (entry *NetworkRequest) responded(response *proto.NetworkResponse) {
+	entry.Status = response.Status
+	entry.MimeType = response.MIMEType
+	entry.Protocol = response.Protocol
+	entry.RemoteIP = response.RemoteIPAddress
+	entry.FromCache = entry.FromCache || response.FromDiskCache ||
+		response.FromServiceWorker || response.FromPrefetchCache
+}
+
+func (entry *NetworkRequest) loaded(e *proto.NetworkLoadingFinished) {
+	entry.EncodedBytes = e.EncodedDataLength
+	entry.DurationMs = entry.elapsedMs(e.Timestamp)
+}
+
+func (entry *NetworkRequest) failed(e *proto.NetworkLoadingFailed) {
+	entry.Failed = true
+	entry.ErrorText = e.ErrorText
+	entry.BlockedReason = string(e.BlockedReason)
+	entry.DurationMs = entry.elapsedMs(e.Timestamp)
+}
+
+func (r *networkRecorder) update(pageID string, id proto.NetworkRequestID, fn func(*NetworkRequest)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if l, ok := r.pages[pageID]; ok {
+		if entry, ok := l.inFlight[id]; ok {
+			fn(entry)
+			r.notify()
+		}
+	}
+}
+
+func (r *networkRecorder) finish(pageID string, id proto.NetworkRequestID, fn func(*NetworkRequest)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if l, ok := r.pages[pageID]; ok {
+		if entry, ok := l.inFlight[id]; ok {
+			fn(entry)
+			entry.Finished = true
+			delete(l.inFlight, id)
+			r.notify()
+		}
+	}
+}
+
+// snapshot copies a page's log so callers never see it change underneath them
+func (r *networkRecorder) snapshot(pageID string) ([]NetworkRequest, int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	l, ok := r.pages[pageID]
+	if !ok {
+		return nil, 0
+	}
+	requests := make([]NetworkRequest, len(l.requests))
+	for i, entry := range l.requests {
+		requests[i] = *entry
+	}
+	return requests, l.dropped
+}
+
+// networkEventHandlers returns the CDP callbacks that feed a page's request
+// log; they run on the page's event watcher
+func (m *Manager) networkEventHandlers(pageID string, page *rod.Page) []interface{} {
+	r := m.network
+	return []interface{}{
+		func(e *proto.NetworkRequestWillBeSent) {
+			r.requestWillBeSent(pageID, page.FrameID, e)
+		},
+		func(e *proto.NetworkResponseReceived) {
+			if e.Response == nil {
+				return
+			}
+			r.update(pageID, e.RequestID, func(entry *NetworkRequest) {
+				entry.responded(e.Response)
+			})
+		},
+		func(e *proto.NetworkRequestServedFromCache) {
+			r.update(pageID, e.RequestID, func(entry *NetworkRequest) {
+				entry.FromCache = true
+			})
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			r.finish(pageID, e.RequestID, func(entry *NetworkRequest) {
+				entry.loaded(e)
+			})
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			r.finish(pageID, e.RequestID, func(entry *NetworkRequest) {
+				entry.failed(e)
+			})
+		},
+	}
+}
+
+// NetworkRequests returns the requests a page has made since its last
+// top-level navigation, oldest first, and how many older entries were
+// dropped to stay within the log's capacity
+func (m *Manager) NetworkRequests(pageID string) ([]NetworkRequest, int, error) {
+	if _, err := m.GetPage(pageID); err != nil {
+		return nil, 0, err
+	}
+	requests, dropped := m.network.snapshot(pageID)
+	return requests, dropped, nil
+}
+
+// ClearNetworkRequests empties a page's request log
+func (m *Manager) ClearNetworkRequests(pageID string) error {
+	if _, err := m.GetPage(pageID); err != nil {
+		return err
+	}
+	m.network.reset(pageID)
+	return nil
+}
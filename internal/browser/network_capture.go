@@ -0,0 +1,230 @@
+package browser
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// maxCapturedRequests bounds a capture; later requests are counted as
+// dropped rather than kept
+const maxCapturedRequests = 5000
+
+// maxCapturedPostData bounds how much of each request body a capture keeps
+const maxCapturedPostData = 64 << 10
+
+// responseBodyTimeout bounds fetching one response body from the browser
+const responseBodyTimeout = 10 * time.Second
+
+// CapturedRequest is a NetworkRequest with the detail a HAR file needs
+type CapturedRequest struct {
+	NetworkRequest
+	RequestHeaders  map[string]string `json:"request_headers,omitempty"`
+	PostData        string            `json:"post_data,omitempty"`
+	StatusText      string            `json:"status_text,omitempty"`
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// BodyBytes is the decoded size of the response body
+	BodyBytes int `json:"body_bytes,omitempty"`
+	// Timing is the browser's breakdown of the request in milliseconds
+	// after Timing.RequestTime; EndMs is when the response finished on
+	// the same scale
+	Timing *proto.NetworkResourceTiming `json:"timing,omitempty"`
+	EndMs  float64                      `json:"end_ms,omitempty"`
+}
+
+// NetworkCapture is every request a page made between StartNetworkCapture
+// and StopNetworkCapture. Unlike the request log it survives navigation.
+type NetworkCapture struct {
+	StartedAt time.Time          `json:"started_at"`
+	StoppedAt time.Time          `json:"stopped_at"`
+	Requests  []*CapturedRequest `json:"requests"`
+	Dropped   int                `json:"dropped"`
+
+	inFlight map[proto.NetworkRequestID]*CapturedRequest
+}
+
+func newNetworkCapture() *NetworkCapture {
+	return &NetworkCapture{
+		StartedAt: time.Now(),
+		inFlight:  make(map[proto.NetworkRequestID]*CapturedRequest),
+	}
+}
+
+// flattenHeaders turns CDP headers into strings; repeated headers arrive
+// joined by newlines
+func flattenHeaders(headers proto.NetworkHeaders) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	flat := make(map[string]string, len(headers))
+	for name, value := range headers {
+		flat[name] = value.Str()
+	}
+	return flat
+}
+
+// endMs places t on the scale of the entry's timing breakdown
+func (entry *CapturedRequest) endMs(t proto.MonotonicTime) float64 {
+	if entry.Timing == nil {
+		return entry.elapsedMs(t)
+	}
+	return (float64(t) - entry.Timing.RequestTime) * 1000
+}
+
+func (entry *CapturedRequest) responded(response *proto.NetworkResponse) {
+	entry.NetworkRequest.responded(response)
+	entry.StatusText = response.StatusText
+	entry.ResponseHeaders = flattenHeaders(response.Headers)
+	entry.Timing = response.Timing
+}
+
+func (c *NetworkCapture) sent(e *proto.NetworkRequestWillBeSent) {
+	if e.Request == nil {
+		return
+	}
+	if previous, ok := c.inFlight[e.RequestID]; ok && e.RedirectResponse != nil {
+		previous.responded(e.RedirectResponse)
+		previous.NetworkRequest.redirected(e)
+		previous.EndMs = previous.endMs(e.Timestamp)
+		delete(c.inFlight, e.RequestID)
+	}
+	if len(c.Requests) >= maxCapturedRequests {
+		c.Dropped++
+		return
+	}
+
+	entry := &CapturedRequest{
+		NetworkRequest: *newNetworkRequest(e),
+		RequestHeaders: flattenHeaders(e.Request.Headers),
+		PostData:       e.Request.PostData,
+	}
+	if len(entry.PostData) > maxCapturedPostData {
+		entry.PostData = entry.PostData[:maxCapturedPostData]
+	}
+	c.Requests = append(c.Requests, entry)
+	c.inFlight[e.RequestID] = entry
+}
+
+func (c *NetworkCapture) update(id proto.NetworkRequestID, fn func(*CapturedRequest)) {
+	if entry, ok := c.inFlight[id]; ok {
+		fn(entry)
+	}
+}
+
+func (c *NetworkCapture) finish(id proto.NetworkRequestID, t proto.MonotonicTime, fn func(*CapturedRequest)) {
+	if entry, ok := c.inFlight[id]; ok {
+		fn(entry)
+		entry.EndMs = entry.endMs(t)
+		entry.Finished = true
+		delete(c.inFlight, id)
+	}
+}
+
+// capture runs fn on the page's capture while one is running
+func (r *networkRecorder) capture(pageID string, fn func(*NetworkCapture)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if c, ok := r.captures[pageID]; ok {
+		fn(c)
+	}
+}
+
+// stopCapture ends the page's capture, returning it if one was running
+func (r *networkRecorder) stopCapture(pageID string) (*NetworkCapture, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	c, ok := r.captures[pageID]
+	delete(r.captures, pageID)
+	return c, ok
+}
+
+// captureEventHandlers returns the CDP callbacks that feed a page's
+// capture; they do nothing while no capture is running
+func (m *Manager) captureEventHandlers(pageID string) []interface{} {
+	r := m.network
+	return []interface{}{
+		func(e *proto.NetworkRequestWillBeSent) {
+			r.capture(pageID, func(c *NetworkCapture) { c.sent(e) })
+		},
+		func(e *proto.NetworkResponseReceived) {
+			if e.Response == nil {
+				return
+			}
+			r.capture(pageID, func(c *NetworkCapture) {
+				c.update(e.RequestID, func(entry *CapturedRequest) { entry.responded(e.Response) })
+			})
+		},
+		func(e *proto.NetworkRequestServedFromCache) {
+			r.capture(pageID, func(c *NetworkCapture) {
+				c.update(e.RequestID, func(entry *CapturedRequest) { entry.FromCache = true })
+			})
+		},
+		func(e *proto.NetworkDataReceived) {
+			r.capture(pageID, func(c *NetworkCapture) {
+				c.update(e.RequestID, func(entry *CapturedRequest) { entry.BodyBytes += e.DataLength })
+			})
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			r.capture(pageID, func(c *NetworkCapture) {
+				c.finish(e.RequestID, e.Timestamp, func(entry *CapturedRequest) { entry.loaded(e) })
+			})
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			r.capture(pageID, func(c *NetworkCapture) {
+				c.finish(e.RequestID, e.Timestamp, func(entry *CapturedRequest) { entry.failed(e) })
+			})
+		},
+	}
+}
+
+// StartNetworkCapture begins recording every request the page makes, with
+// headers and timings, until StopNetworkCapture
+func (m *Manager) StartNetworkCapture(pageID string) error {
+	if _, err := m.GetPage(pageID); err != nil {
+		return err
+	}
+	r := m.network
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.captures[pageID]; ok {
+		return fmt.Errorf("page %s is already capturing network traffic", pageID)
+	}
+	r.captures[pageID] = newNetworkCapture()
+	m.logger.LogBrowserAction("network_capture_started", pageID, 0)
+	return nil
+}
+
+// StopNetworkCapture ends the page's capture and returns what it recorded.
+// Requests still in flight are returned unfinished.
+func (m *Manager) StopNetworkCapture(pageID string) (*NetworkCapture, error) {
+	c, ok := m.network.stopCapture(pageID)
+	if !ok {
+		return nil, fmt.Errorf("page %s is not capturing network traffic; call start_network_capture first", pageID)
+	}
+	c.StoppedAt = time.Now()
+	m.logger.LogBrowserAction("network_capture_stopped", pageID, c.StoppedAt.Sub(c.StartedAt).Milliseconds())
+	return c, nil
+}
+
+// ResponseBody returns the decoded body of a request the page made, while
+// the browser still has it buffered. Redirects, failed requests and bodies
+// evicted from the buffer return an error.
+func (m *Manager) ResponseBody(pageID, requestID string) ([]byte, error) {
+	var body []byte
+	err := m.withPage(pageID, responseBodyTimeout, func(p *rod.Page) error {
+		result, err := proto.NetworkGetResponseBody{RequestID: proto.NetworkRequestID(requestID)}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to get response body: %w", err)
+		}
+		if !result.Base64Encoded {
+			body = []byte(result.Body)
+			return nil
+		}
+		body, err = base64.StdEncoding.DecodeString(result.Body)
+		return err
+	})
+	return body, err
+}
@@ -0,0 +1,162 @@
+package browser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/ysmood/gson"
+)
+
+func sendRequest(r *networkRecorder, pageID, id, url string, resourceType proto.NetworkResourceType, at float64) {
+	r.requestWillBeSent(pageID, "main", &proto.NetworkRequestWillBeSent{
+		RequestID: proto.NetworkRequestID(id),
+		LoaderID:  "loader",
+		Request:   &proto.NetworkRequest{URL: url, Method: "GET"},
+		Type:      resourceType,
+		FrameID:   "main",
+		Timestamp: proto.MonotonicTime(at),
+	})
+}
+
+func TestNetworkRecorderLifecycle(t *testing.T) {
+	r := newNetworkRecorder()
+	sendRequest(r, "p", "loader", "https://example.com/", proto.NetworkResourceTypeDocument, 1)
+	sendRequest(r, "p", "2", "https://cdn.example.net/app.js", proto.NetworkResourceTypeScript, 1.5)
+	sendRequest(r, "p", "3", "http://ads.example.org/pixel.gif", proto.NetworkResourceTypeImage, 1.5)
+
+	r.update("p", "2", func(e *NetworkRequest) { e.Status = 200 })
+	r.finish("p", "2", func(e *NetworkRequest) { e.EncodedBytes = 2048 })
+	r.finish("p", "3", func(e *NetworkRequest) {
+		e.Failed = true
+		e.BlockedReason = string(proto.NetworkBlockedReasonMixedContent)
+	})
+
+	requests, dropped := r.snapshot("p")
+	if len(requests) != 3 || dropped != 0 {
+		t.Fatalf("got %d requests, %d dropped", len(requests), dropped)
+	}
+	if script := requests[1]; script.Status != 200 || script.EncodedBytes != 2048 || !script.Finished {
+		t.Errorf("script entry not updated: %+v", script)
+	}
+	if pixel := requests[2]; !pixel.Failed || pixel.BlockedReason != "mixed-content" {
+		t.Errorf("pixel entry not marked failed: %+v", pixel)
+	}
+
+	// Updates for finished requests are ignored rather than reopening them
+	r.update("p", "2", func(e *NetworkRequest) { e.Status = 500 })
+	if requests, _ := r.snapshot("p"); requests[1].Status != 200 {
+		t.Errorf("finished request changed: %+v", requests[1])
+	}
+
+	// A new top-level document starts a fresh log
+	sendRequest(r, "p", "loader", "https://example.com/next", proto.NetworkResourceTypeDocument, 3)
+	if requests, _ := r.snapshot("p"); len(requests) != 1 || requests[0].URL != "https://example.com/next" {
+		t.Errorf("navigation did not reset the log: %+v", requests)
+	}
+}
+
+func TestNetworkRecorderRedirect(t *testing.T) {
+	r := newNetworkRecorder()
+	sendRequest(r, "p", "loader", "http://example.com/", proto.NetworkResourceTypeDocument, 1)
+	r.requestWillBeSent("p", "main", &proto.NetworkRequestWillBeSent{
+		RequestID:        "loader",
+		LoaderID:         "loader",
+		Request:          &proto.NetworkRequest{URL: "https://example.com/", Method: "GET"},
+		RedirectResponse: &proto.NetworkResponse{Status: 301},
+		Type:             proto.NetworkResourceTypeDocument,
+		FrameID:          "main",
+		Timestamp:        2,
+	})
+
+	requests, _ := r.snapshot("p")
+	if len(requests) != 2 {
+		t.Fatalf("expected both hops to be kept, got %+v", requests)
+	}
+	if hop := requests[0]; hop.Status != 301 || hop.RedirectedTo != "https://example.com/" || !hop.Finished {
+		t.Errorf("redirect hop not recorded: %+v", hop)
+	}
+}
+
+func TestNetworkRecorderCapacity(t *testing.T) {
+	r := newNetworkRecorder()
+	for i := 0; i < maxNetworkEntries+10; i++ {
+		sendRequest(r, "p", string(rune('a'+i%26))+string(rune(i)), "https://example.com/x", proto.NetworkResourceTypeFetch, float64(i))
+	}
+	requests, dropped := r.snapshot("p")
+	if len(requests) != maxNetworkEntries || dropped != 10 {
+		t.Errorf("got %d requests, %d dropped", len(requests), dropped)
+	}
+
+	r.reset("p")
+	if requests, _ := r.snapshot("p"); len(requests) != 0 {
+		t.Errorf("reset left %d requests", len(requests))
+	}
+}
+
+func TestNetworkCapture(t *testing.T) {
+	c := newNetworkCapture()
+	c.sent(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", LoaderID: "1", Type: proto.NetworkResourceTypeDocument, Timestamp: 10,
+		Request: &proto.NetworkRequest{URL: "http://example.com/", Method: "GET"},
+	})
+	// Redirects close the first hop and open a second entry
+	c.sent(&proto.NetworkRequestWillBeSent{
+		RequestID: "1", LoaderID: "1", Type: proto.NetworkResourceTypeDocument, Timestamp: 10.2,
+		Request:          &proto.NetworkRequest{URL: "https://example.com/", Method: "GET"},
+		RedirectResponse: &proto.NetworkResponse{Status: 301, StatusText: "Moved Permanently"},
+	})
+	c.update("1", func(e *CapturedRequest) {
+		e.responded(&proto.NetworkResponse{
+			Status: 200, StatusText: "OK",
+			Headers: proto.NetworkHeaders{"Content-Type": gson.New("text/html")},
+			Timing:  &proto.NetworkResourceTiming{RequestTime: 10.2, ReceiveHeadersEnd: 50},
+		})
+	})
+	c.update("1", func(e *CapturedRequest) { e.BodyBytes += 1000 })
+	c.finish("1", 10.3, func(e *CapturedRequest) {})
+
+	// A new document does not reset a capture the way it resets the log
+	c.sent(&proto.NetworkRequestWillBeSent{
+		RequestID: "2", LoaderID: "2", Type: proto.NetworkResourceTypeDocument, Timestamp: 11,
+		Request: &proto.NetworkRequest{URL: "https://example.com/next", Method: "POST", PostData: "a=1"},
+	})
+
+	if len(c.Requests) != 3 {
+		t.Fatalf("got %d requests, want 3", len(c.Requests))
+	}
+	if hop := c.Requests[0]; hop.Status != 301 || hop.StatusText != "Moved Permanently" || hop.RedirectedTo != "https://example.com/" {
+		t.Errorf("redirect hop = %+v", hop)
+	}
+	page := c.Requests[1]
+	if page.Status != 200 || page.BodyBytes != 1000 || !page.Finished || page.ResponseHeaders["Content-Type"] != "text/html" {
+		t.Errorf("page entry = %+v", page)
+	}
+	if page.EndMs < 99 || page.EndMs > 101 {
+		t.Errorf("EndMs = %v, want about 100", page.EndMs)
+	}
+	if next := c.Requests[2]; next.Finished || next.PostData != "a=1" {
+		t.Errorf("in-flight entry = %+v", next)
+	}
+
+	for i := 0; i < maxCapturedRequests; i++ {
+		c.sent(&proto.NetworkRequestWillBeSent{
+			RequestID: proto.NetworkRequestID(fmt.Sprint("x", i)),
+			Request:   &proto.NetworkRequest{URL: "https://example.com/x", Method: "GET"},
+		})
+	}
+	if len(c.Requests) != maxCapturedRequests || c.Dropped != 3 {
+		t.Errorf("got %d requests, %d dropped", len(c.Requests), c.Dropped)
+	}
+}
+
+func TestNetworkCaptureStopWithoutStart(t *testing.T) {
+	m := NewManager(createTestLogger(t), Config{Headless: true})
+	if _, err := m.StopNetworkCapture("nope"); err == nil || !strings.Contains(err.Error(), "start_network_capture") {
+		t.Errorf("err = %v", err)
+	}
+	if err := m.StartNetworkCapture("nope"); err == nil {
+		t.Error("expected an error for an unknown page")
+	}
+}
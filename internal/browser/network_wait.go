@@ -0,0 +1,185 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// What WaitForRequestContext waits for
+const (
+	// NetworkWaitRequest waits for the page to send a matching request
+	NetworkWaitRequest = "request"
+	// NetworkWaitResponse waits for a matching request to finish loading
+	// or fail
+	NetworkWaitResponse = "response"
+)
+
+// NetworkWaitEvents lists the events WaitForRequestContext accepts
+var NetworkWaitEvents = []string{NetworkWaitRequest, NetworkWaitResponse}
+
+// maxListedInFlight bounds how many pending URLs a timeout error names
+const maxListedInFlight = 5
+
+// NetworkWait is what to wait for in a page's request log
+type NetworkWait struct {
+	// Event is NetworkWaitRequest or NetworkWaitResponse (the default)
+	Event string
+	// Match picks the request; nil matches any
+	Match func(NetworkRequest) bool
+	// IncludeExisting also accepts requests already in the log, for when
+	// whatever triggers the request ran before the wait began
+	IncludeExisting bool
+	// Timeout bounds the wait within the caller's deadline
+	Timeout time.Duration
+}
+
+// activity reports a page's requests in flight, the seq of the latest one
+// to start, and a channel closed at the next change to any log
+func (r *networkRecorder) activity(pageID string) ([]string, uint64, <-chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	l, ok := r.pages[pageID]
+	if !ok {
+		return nil, 0, r.changed
+	}
+	inFlight := make([]string, 0, len(l.inFlight))
+	for _, entry := range l.requests {
+		if !entry.Finished && l.inFlight[proto.NetworkRequestID(entry.RequestID)] == entry {
+			inFlight = append(inFlight, entry.URL)
+		}
+	}
+	return inFlight, l.lastStarted, r.changed
+}
+
+// find returns the first request in a page's log after seq that match
+// accepts, and a channel closed at the next change to any log
+func (r *networkRecorder) find(pageID string, after uint64, match func(*NetworkRequest) bool) (NetworkRequest, bool, <-chan struct{}) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if l, ok := r.pages[pageID]; ok {
+		for _, entry := range l.requests {
+			if entry.seq > after && match(entry) {
+				return *entry, true, r.changed
+			}
+		}
+	}
+	return NetworkRequest{}, false, r.changed
+}
+
+// sequence is the seq of the latest request recorded on any page
+func (r *networkRecorder) sequence() uint64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.seq
+}
+
+// WaitForNetworkIdleContext waits until no more than maxInFlight of the
+// page's requests have been in flight for idle, and returns how long that
+// took. A request starting restarts the idle period, even one that
+// finishes within it.
+func (m *Manager) WaitForNetworkIdleContext(ctx context.Context, pageID string, idle time.Duration, maxInFlight int, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	if _, err := m.GetPage(pageID); err != nil {
+		return 0, err
+	}
+	waitCtx, cancel := stepContext(ctx, timeout)
+	defer cancel()
+
+	var quietSince time.Time
+	var lastStarted uint64
+	for {
+		inFlight, started, changed := m.network.activity(pageID)
+		now := time.Now()
+		switch {
+		case len(inFlight) > maxInFlight:
+			quietSince = time.Time{}
+		case quietSince.IsZero() || started != lastStarted:
+			quietSince = now
+		}
+		lastStarted = started
+		if !quietSince.IsZero() && now.Sub(quietSince) >= idle {
+			m.logger.LogBrowserAction("network_idle", pageID, time.Since(start).Milliseconds())
+			return time.Since(start), nil
+		}
+
+		var quiet <-chan time.Time
+		var timer *time.Timer
+		if !quietSince.IsZero() {
+			timer = time.NewTimer(idle - now.Sub(quietSince))
+			quiet = timer.C
+		}
+		select {
+		case <-changed:
+		case <-quiet:
+		case <-waitCtx.Done():
+			err := fmt.Errorf("network did not go idle for %s within %s", idle, timeout)
+			if len(inFlight) > 0 {
+				err = fmt.Errorf("%w; %d requests still in flight: %s", err, len(inFlight), listInFlight(inFlight))
+			}
+			return 0, deadlineError(ctx, err)
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if _, err := m.GetPage(pageID); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// listInFlight names the first few pending URLs
+func listInFlight(urls []string) string {
+	if len(urls) > maxListedInFlight {
+		return strings.Join(urls[:maxListedInFlight], ", ") + fmt.Sprintf(" and %d more", len(urls)-maxListedInFlight)
+	}
+	return strings.Join(urls, ", ")
+}
+
+// WaitForRequestContext waits for the page to send, or finish loading, a
+// request wait.Match accepts, and returns it. A failed request counts as a
+// response; check its Failed field.
+func (m *Manager) WaitForRequestContext(ctx context.Context, pageID string, wait NetworkWait) (NetworkRequest, error) {
+	start := time.Now()
+	if wait.Event == "" {
+		wait.Event = NetworkWaitResponse
+	}
+	if wait.Event != NetworkWaitRequest && wait.Event != NetworkWaitResponse {
+		return NetworkRequest{}, fmt.Errorf("unknown network event %q (use %s)", wait.Event, strings.Join(NetworkWaitEvents, " or "))
+	}
+	if _, err := m.GetPage(pageID); err != nil {
+		return NetworkRequest{}, err
+	}
+
+	var after uint64
+	if !wait.IncludeExisting {
+		after = m.network.sequence()
+	}
+	match := func(entry *NetworkRequest) bool {
+		if wait.Event == NetworkWaitResponse && !entry.Finished {
+			return false
+		}
+		return wait.Match == nil || wait.Match(*entry)
+	}
+
+	waitCtx, cancel := stepContext(ctx, wait.Timeout)
+	defer cancel()
+	for {
+		entry, ok, changed := m.network.find(pageID, after, match)
+		if ok {
+			m.logger.LogBrowserAction("network_"+wait.Event+"_matched", pageID, time.Since(start).Milliseconds())
+			return entry, nil
+		}
+		select {
+		case <-changed:
+			if _, err := m.GetPage(pageID); err != nil {
+				return NetworkRequest{}, err
+			}
+		case <-waitCtx.Done():
+			return NetworkRequest{}, deadlineError(ctx, fmt.Errorf("no matching %s within %s", wait.Event, wait.Timeout))
+		}
+	}
+}
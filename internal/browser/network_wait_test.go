@@ -0,0 +1,112 @@
+package browser
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func newNetworkWaitManager(t *testing.T) *Manager {
+	m := NewManager(createTestLogger(t), Config{Headless: true})
+	m.pages["p"] = &rod.Page{}
+	return m
+}
+
+func TestWaitForNetworkIdle(t *testing.T) {
+	m := newNetworkWaitManager(t)
+	sendRequest(m.network, "p", "loader", "https://example.com/", proto.NetworkResourceTypeDocument, 1)
+	sendRequest(m.network, "p", "2", "https://example.com/api/feed", proto.NetworkResourceTypeXHR, 1.1)
+	sendRequest(m.network, "p", "3", "https://example.com/events", proto.NetworkResourceTypeEventSource, 1.2)
+	m.network.finish("p", "loader", func(*NetworkRequest) {})
+
+	// Two requests in flight keep the page busy until the timeout
+	_, err := m.WaitForNetworkIdleContext(context.Background(), "p", 20*time.Millisecond, 0, 60*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "2 requests still in flight: https://example.com/api/feed, https://example.com/events") {
+		t.Errorf("Expected a timeout naming the pending requests, got %v", err)
+	}
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		m.network.finish("p", "2", func(*NetworkRequest) {})
+	}()
+	// The event stream never finishes, so allow one request in flight
+	waited, err := m.WaitForNetworkIdleContext(context.Background(), "p", 50*time.Millisecond, 1, time.Second)
+	if err != nil {
+		t.Fatalf("Expected the page to go idle, got %v", err)
+	}
+	if waited < 50*time.Millisecond {
+		t.Errorf("Expected to wait out the idle period, waited %s", waited)
+	}
+
+	// A request that starts and finishes restarts the idle period
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		sendRequest(m.network, "p", "4", "https://example.com/ping", proto.NetworkResourceTypePing, 2)
+		m.network.finish("p", "4", func(*NetworkRequest) {})
+	}()
+	waited, err = m.WaitForNetworkIdleContext(context.Background(), "p", 50*time.Millisecond, 1, time.Second)
+	if err != nil || waited < 80*time.Millisecond {
+		t.Errorf("Expected the ping to restart the idle period, waited %s (%v)", waited, err)
+	}
+
+	if _, err := m.WaitForNetworkIdleContext(context.Background(), "missing", time.Millisecond, 0, time.Second); err == nil {
+		t.Error("Expected an error for an unknown page")
+	}
+}
+
+func TestWaitForRequest(t *testing.T) {
+	m := newNetworkWaitManager(t)
+	sendRequest(m.network, "p", "1", "https://example.com/api/orders", proto.NetworkResourceTypeFetch, 1)
+	m.network.finish("p", "1", func(e *NetworkRequest) { e.Status = 200 })
+	orders := func(r NetworkRequest) bool { return strings.HasSuffix(r.URL, "/api/orders") }
+
+	// Requests from before the wait only count when asked for
+	if _, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Match: orders, Timeout: 20 * time.Millisecond}); err == nil || !strings.Contains(err.Error(), "no matching response") {
+		t.Errorf("Expected the earlier request to be ignored, got %v", err)
+	}
+	if r, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Match: orders, IncludeExisting: true, Timeout: time.Second}); err != nil || r.RequestID != "1" {
+		t.Errorf("Expected the earlier request, got %+v (%v)", r, err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sendRequest(m.network, "p", "2", "https://example.com/api/orders", proto.NetworkResourceTypeFetch, 2)
+		time.Sleep(20 * time.Millisecond)
+		m.network.finish("p", "2", func(e *NetworkRequest) { e.Status = 201 })
+	}()
+	sent, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Event: NetworkWaitRequest, Match: orders, Timeout: time.Second})
+	if err != nil || sent.RequestID != "2" || sent.Finished {
+		t.Errorf("Expected the request as it was sent, got %+v (%v)", sent, err)
+	}
+	got, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Match: orders, IncludeExisting: true, Timeout: time.Second})
+	if err != nil || got.RequestID != "1" {
+		t.Errorf("Expected the first finished match, got %+v (%v)", got, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := m.WaitForRequestContext(ctx, "p", NetworkWait{Match: orders, Timeout: time.Second}); err == nil || !strings.Contains(err.Error(), "ran out of time") {
+		t.Errorf("Expected the caller's deadline to end the wait, got %v", err)
+	}
+	if _, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Event: "redirect"}); err == nil {
+		t.Error("Expected an error for an unknown event")
+	}
+}
+
+func TestWaitForRequestPageClosed(t *testing.T) {
+	m := newNetworkWaitManager(t)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		m.mutex.Lock()
+		delete(m.pages, "p")
+		m.mutex.Unlock()
+		m.network.reset("p")
+	}()
+	if _, err := m.WaitForRequestContext(context.Background(), "p", NetworkWait{Timeout: time.Second}); err == nil || !strings.Contains(err.Error(), "page not found") {
+		t.Errorf("Expected closing the page to end the wait, got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package browser
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ProfileRoot is where rodmcp keeps browser profiles. Each run gets a
+// directory named "<owner pid>-<unix nanos>" so leftovers from crashed runs
+// can be told apart from live ones.
+func ProfileRoot() string {
+	return filepath.Join(os.TempDir(), "rodmcp", "profiles")
+}
+
+// newProfileDir returns a fresh profile directory owned by this process
+func newProfileDir() string {
+	return filepath.Join(ProfileRoot(), fmt.Sprintf("%d-%d", os.Getpid(), time.Now().UnixNano()))
+}
+
+// OrphanProcess is a browser process whose owning rodmcp process has exited
+type OrphanProcess struct {
+	PID         int    `json:"pid"`
+	Name        string `json:"name"`
+	UserDataDir string `json:"user_data_dir"`
+	OwnerPID    int    `json:"owner_pid"`
+}
+
+// profileOwner extracts the owning PID from a profile directory name
+func profileOwner(dir string) (int, bool) {
+	owner, _, found := strings.Cut(filepath.Base(dir), "-")
+	if !found {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(owner)
+	if err != nil || pid <= 0 {
+		return 0, false
+	}
+	return pid, true
+}
+
+// processAlive reports whether a process with the given PID exists and has
+// not already exited (zombies awaiting their parent count as gone)
+func ProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	if err := proc.Signal(syscall.Signal(0)); err != nil && !errors.Is(err, syscall.EPERM) {
+		return false
+	}
+	if stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat")); err == nil {
+		if i := strings.LastIndexByte(string(stat), ')'); i >= 0 && i+2 < len(stat) && stat[i+2] == 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// FindOrphanBrowsers lists processes started with a user-data-dir under root
+// whose owning rodmcp process is gone. Detection relies on /proc, so other
+// platforms report nothing.
+func FindOrphanBrowsers(root string) ([]OrphanProcess, error) {
+	if runtime.GOOS != "linux" {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read /proc: %w", err)
+	}
+
+	prefix := "--user-data-dir=" + filepath.Clean(root) + string(filepath.Separator)
+	var orphans []OrphanProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		cmdline, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "cmdline"))
+		if err != nil || len(cmdline) == 0 {
+			continue
+		}
+
+		args := strings.Split(strings.TrimRight(string(cmdline), "\x00"), "\x00")
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, prefix) {
+				continue
+			}
+			dir := strings.TrimPrefix(arg, "--user-data-dir=")
+			owner, ok := profileOwner(dir)
+			if !ok || ProcessAlive(owner) {
+				break
+			}
+			orphans = append(orphans, OrphanProcess{
+				PID:         pid,
+				Name:        filepath.Base(args[0]),
+				UserDataDir: dir,
+				OwnerPID:    owner,
+			})
+			break
+		}
+	}
+	return orphans, nil
+}
+
+// KillOrphanBrowsers terminates orphaned browser processes, escalating to
+// SIGKILL for any that survive the grace period. It returns how many exited.
+func KillOrphanBrowsers(orphans []OrphanProcess, grace time.Duration) int {
+	for _, o := range orphans {
+		if proc, err := os.FindProcess(o.PID); err == nil {
+			proc.Signal(syscall.SIGTERM)
+		}
+	}
+
+	deadline := time.Now().Add(grace)
+	remaining := orphans
+	for len(remaining) > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+		var alive []OrphanProcess
+		for _, o := range remaining {
+			if ProcessAlive(o.PID) {
+				alive = append(alive, o)
+			}
+		}
+		remaining = alive
+	}
+
+	for _, o := range remaining {
+		if proc, err := os.FindProcess(o.PID); err == nil {
+			proc.Kill()
+		}
+	}
+	if len(remaining) > 0 {
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	exited := 0
+	for _, o := range orphans {
+		if !ProcessAlive(o.PID) {
+			exited++
+		}
+	}
+	return exited
+}
+
+// RemoveStaleProfiles deletes profile directories under root whose owner has
+// exited and which no running process still uses. It returns the removed paths.
+func RemoveStaleProfiles(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool)
+	if orphans, err := FindOrphanBrowsers(root); err == nil {
+		for _, o := range orphans {
+			inUse[filepath.Clean(o.UserDataDir)] = true
+		}
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		owner, ok := profileOwner(dir)
+		if !ok || ProcessAlive(owner) || inUse[dir] {
+			continue
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return removed, fmt.Errorf("failed to remove %s: %w", dir, err)
+		}
+		removed = append(removed, dir)
+	}
+	return removed, nil
+}
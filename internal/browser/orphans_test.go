@@ -0,0 +1,97 @@
+package browser
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// deadPID returns the PID of a process that has already exited
+func deadPID(t *testing.T) int {
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("Cannot run helper process: %v", err)
+	}
+	return cmd.Process.Pid
+}
+
+func TestProfileOwner(t *testing.T) {
+	if pid, ok := profileOwner("/tmp/rodmcp/profiles/1234-5678"); !ok || pid != 1234 {
+		t.Errorf("Expected owner 1234, got %d %v", pid, ok)
+	}
+	for _, dir := range []string{"/tmp/rodmcp/profiles/default", "/tmp/x/abc-1", "/tmp/x/-1"} {
+		if _, ok := profileOwner(dir); ok {
+			t.Errorf("Expected %s to have no owner", dir)
+		}
+	}
+}
+
+func TestFindAndKillOrphanBrowsers(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Orphan detection requires /proc")
+	}
+
+	root := t.TempDir()
+	orphanDir := filepath.Join(root, fmt.Sprintf("%d-1", deadPID(t)))
+	liveDir := filepath.Join(root, fmt.Sprintf("%d-2", os.Getpid()))
+
+	// Stand-ins for browsers: the user-data-dir flag lands in the shell's argv
+	orphan := exec.Command("sh", "-c", "sleep 30", "--user-data-dir="+orphanDir)
+	owned := exec.Command("sh", "-c", "sleep 30", "--user-data-dir="+liveDir)
+	for _, cmd := range []*exec.Cmd{orphan, owned} {
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("Failed to start helper: %v", err)
+		}
+	}
+	defer owned.Process.Kill()
+	defer orphan.Process.Kill()
+
+	found, err := FindOrphanBrowsers(root)
+	if err != nil {
+		t.Fatalf("FindOrphanBrowsers failed: %v", err)
+	}
+	if len(found) != 1 || found[0].PID != orphan.Process.Pid || found[0].UserDataDir != orphanDir {
+		t.Fatalf("Expected only the orphaned helper, got %+v", found)
+	}
+
+	if killed := KillOrphanBrowsers(found, 2*time.Second); killed != 1 {
+		t.Errorf("Expected 1 process stopped, got %d", killed)
+	}
+	orphan.Wait()
+	if !ProcessAlive(owned.Process.Pid) {
+		t.Error("Process with a live owner must not be killed")
+	}
+}
+
+func TestRemoveStaleProfiles(t *testing.T) {
+	root := t.TempDir()
+	stale := filepath.Join(root, fmt.Sprintf("%d-1", deadPID(t)))
+	live := filepath.Join(root, fmt.Sprintf("%d-2", os.Getpid()))
+	unrelated := filepath.Join(root, "keep-me")
+	for _, dir := range []string{stale, live, unrelated} {
+		if err := os.MkdirAll(filepath.Join(dir, "Default"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := RemoveStaleProfiles(root)
+	if err != nil {
+		t.Fatalf("RemoveStaleProfiles failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != stale {
+		t.Errorf("Expected only %s removed, got %v", stale, removed)
+	}
+	for _, dir := range []string{live, unrelated} {
+		if _, err := os.Stat(dir); err != nil {
+			t.Errorf("Expected %s to be kept: %v", dir, err)
+		}
+	}
+
+	if removed, err := RemoveStaleProfiles(filepath.Join(root, "missing")); err != nil || len(removed) != 0 {
+		t.Errorf("Expected missing root to be a no-op, got %v %v", removed, err)
+	}
+}
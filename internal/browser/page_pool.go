@@ -0,0 +1,301 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	neturl "net/url"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// pagePool keeps blank pages warm so short-lived pages (such as a scrape of a
+// single URL) skip the cost of creating a new browser target
+type pagePool struct {
+	mutex   sync.Mutex
+	size    int
+	idle    []*rod.Page
+	filling bool
+	hits    int64
+	misses  int64
+}
+
+// PagePoolStats reports the pool's configuration and effectiveness
+type PagePoolStats struct {
+	Size   int   `json:"size"`
+	Idle   int   `json:"idle"`
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+func newPagePool(size int) *pagePool {
+	if size < 0 {
+		size = 0
+	}
+	return &pagePool{size: size}
+}
+
+func (p *pagePool) setSize(size int) {
+	if p == nil {
+		return
+	}
+	if size < 0 {
+		size = 0
+	}
+	p.mutex.Lock()
+	p.size = size
+	p.mutex.Unlock()
+}
+
+func (p *pagePool) enabled() bool {
+	if p == nil {
+		return false
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.size > 0
+}
+
+// take pops a warm page, or returns nil when the pool is empty or disabled
+func (p *pagePool) take() *rod.Page {
+	if p == nil {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.size == 0 {
+		return nil
+	}
+	if len(p.idle) == 0 {
+		p.misses++
+		return nil
+	}
+	page := p.idle[len(p.idle)-1]
+	p.idle = p.idle[:len(p.idle)-1]
+	p.hits++
+	return page
+}
+
+// put returns a page to the pool, reporting false when the pool is full
+func (p *pagePool) put(page *rod.Page) bool {
+	if p == nil {
+		return false
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if len(p.idle) >= p.size {
+		return false
+	}
+	p.idle = append(p.idle, page)
+	return true
+}
+
+func (p *pagePool) full() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.idle) >= p.size
+}
+
+// drain empties the pool and returns the pages that were idle
+func (p *pagePool) drain() []*rod.Page {
+	if p == nil {
+		return nil
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	pages := p.idle
+	p.idle = nil
+	return pages
+}
+
+func (p *pagePool) stats() PagePoolStats {
+	if p == nil {
+		return PagePoolStats{}
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return PagePoolStats{Size: p.size, Idle: len(p.idle), Hits: p.hits, Misses: p.misses}
+}
+
+// PagePoolStats returns the current state of the warm page pool
+func (m *Manager) PagePoolStats() PagePoolStats {
+	return m.pool.stats()
+}
+
+// PagePoolEnabled reports whether pooled pages are recycled instead of closed
+func (m *Manager) PagePoolEnabled() bool {
+	return m.pool.enabled()
+}
+
+// NewPooledPage works like NewPageWithOptions but takes a warm blank page from
+// the pool when one is available. Pages obtained this way should be handed
+// back with RecyclePage once the caller is done with them.
+func (m *Manager) NewPooledPage(url string, opts PageOptions) (*rod.Page, string, error) {
+	start := time.Now()
+
+	if _, err := NormalizeResourceTypes(opts.BlockResources); err != nil {
+		return nil, "", err
+	}
+
+	page := m.pool.take()
+	if m.pool.enabled() {
+		m.warmPagePool()
+	}
+	if page == nil {
+		return m.NewPageWithOptions(url, opts)
+	}
+
+	return m.registerPage(context.Background(), page, url, opts, start)
+}
+
+// RecyclePage clears a page's storage and returns it to the pool. When the
+// pool is disabled or full the page is simply closed.
+func (m *Manager) RecyclePage(pageID string) error {
+	// Fingerprint, device, location and request overrides outlive a reset,
+	// so such pages are not reused
+	if !m.pool.enabled() || m.pool.full() || m.HasFingerprint(pageID) || m.DeviceEmulation(pageID) != nil || m.LocationEmulation(pageID) != nil ||
+		m.UserAgentOverride(pageID) != "" || m.ExtraHeaders(pageID) != nil {
+		return m.closePage(pageID)
+	}
+
+	start := time.Now()
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	queueCtx, queueCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	release, err := m.AcquirePage(queueCtx, pageID)
+	queueCancel()
+	if err != nil {
+		// Still busy - don't hand a page in use to someone else
+		return m.closePage(pageID)
+	}
+
+	m.mutex.Lock()
+	delete(m.pages, pageID)
+	delete(m.pageURLs, pageID)
+	m.mutex.Unlock()
+	m.stopPageEvents(pageID)
+	m.stopResourceBlocking(pageID, page, true)
+	release()
+	m.pageQueue.forget(pageID)
+
+	if err := m.resetPage(page); err != nil {
+		m.logger.WithComponent("browser").Debug("Discarding page that could not be reset",
+			zap.String("page_id", pageID),
+			zap.Error(err))
+		m.closeDetachedPage(page)
+		return nil
+	}
+
+	if !m.pool.put(page) {
+		m.closeDetachedPage(page)
+		return nil
+	}
+
+	m.logger.LogBrowserAction("page_recycled", pageID, time.Since(start).Milliseconds())
+	return nil
+}
+
+// resetPage clears the storage of the page's current origin and leaves it blank
+func (m *Manager) resetPage(page *rod.Page) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("page reset panicked: %v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p := page.Context(ctx)
+
+	// Session storage survives navigation within a tab, so clear it explicitly
+	p.Eval(`() => { try { sessionStorage.clear(); } catch (e) {} }`)
+
+	if info, infoErr := p.Info(); infoErr == nil && info != nil {
+		if parsed, parseErr := neturl.Parse(info.URL); parseErr == nil &&
+			(parsed.Scheme == "http" || parsed.Scheme == "https") {
+			origin := parsed.Scheme + "://" + parsed.Host
+			if err := (proto.StorageClearDataForOrigin{Origin: origin, StorageTypes: "all"}).Call(p); err != nil {
+				return fmt.Errorf("failed to clear storage for %s: %w", origin, err)
+			}
+		}
+	}
+
+	if err := p.Navigate("about:blank"); err != nil {
+		return fmt.Errorf("failed to blank page: %w", err)
+	}
+	return nil
+}
+
+// warmPagePool tops the pool up in the background
+func (m *Manager) warmPagePool() {
+	pool := m.pool
+	if !pool.enabled() {
+		return
+	}
+
+	pool.mutex.Lock()
+	if pool.filling || len(pool.idle) >= pool.size {
+		pool.mutex.Unlock()
+		return
+	}
+	pool.filling = true
+	pool.mutex.Unlock()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Warn("Page pool warm-up panicked", zap.Any("panic", r))
+			}
+			pool.mutex.Lock()
+			pool.filling = false
+			pool.mutex.Unlock()
+		}()
+
+		for !pool.full() {
+			m.mutex.RLock()
+			browser := m.browser
+			m.mutex.RUnlock()
+			if browser == nil {
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			page, err := browser.Context(ctx).Page(proto.TargetCreateTarget{URL: "about:blank"})
+			cancel()
+			if err != nil {
+				m.logger.WithComponent("browser").Warn("Failed to warm page pool", zap.Error(err))
+				return
+			}
+
+			if !pool.put(page.Context(context.Background())) {
+				m.closeDetachedPage(page)
+				return
+			}
+		}
+	}()
+}
+
+// drainPagePool closes every idle pooled page
+func (m *Manager) drainPagePool() {
+	for _, page := range m.pool.drain() {
+		m.closeDetachedPage(page)
+	}
+}
+
+// closeDetachedPage closes a page that is not tracked in the pages map
+func (m *Manager) closeDetachedPage(page *rod.Page) {
+	defer func() {
+		if r := recover(); r != nil {
+			m.logger.WithComponent("browser").Debug("Closing pooled page panicked", zap.Any("panic", r))
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	page.Context(ctx).Close()
+}
@@ -0,0 +1,75 @@
+package browser
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod"
+)
+
+func TestPagePoolTakeAndPut(t *testing.T) {
+	pool := newPagePool(2)
+
+	if page := pool.take(); page != nil {
+		t.Fatal("Expected empty pool to return nil")
+	}
+
+	first, second, third := &rod.Page{}, &rod.Page{}, &rod.Page{}
+	if !pool.put(first) || !pool.put(second) {
+		t.Fatal("Expected pool to accept pages up to its size")
+	}
+	if pool.put(third) {
+		t.Error("Expected full pool to reject a page")
+	}
+	if !pool.full() {
+		t.Error("Expected pool to report full")
+	}
+
+	if page := pool.take(); page != second {
+		t.Error("Expected most recently returned page first")
+	}
+
+	stats := pool.stats()
+	if stats.Size != 2 || stats.Idle != 1 || stats.Hits != 1 || stats.Misses != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if drained := pool.drain(); len(drained) != 1 || drained[0] != first {
+		t.Errorf("Expected drain to return the idle page, got %d pages", len(drained))
+	}
+	if pool.stats().Idle != 0 {
+		t.Error("Expected pool to be empty after drain")
+	}
+}
+
+func TestPagePoolDisabled(t *testing.T) {
+	pool := newPagePool(0)
+	if pool.enabled() {
+		t.Error("Expected size 0 pool to be disabled")
+	}
+	if pool.put(&rod.Page{}) {
+		t.Error("Expected disabled pool to reject pages")
+	}
+	if pool.take() != nil {
+		t.Error("Expected disabled pool to return nil")
+	}
+	if pool.stats().Misses != 0 {
+		t.Error("Expected disabled pool not to count misses")
+	}
+
+	pool.setSize(3)
+	if !pool.enabled() {
+		t.Error("Expected pool to be enabled after resizing")
+	}
+}
+
+func TestNewPooledPageWithoutBrowser(t *testing.T) {
+	manager := newQueueTestManager(t)
+	manager.pool.setSize(2)
+
+	if _, _, err := manager.NewPooledPage("about:blank", PageOptions{}); err == nil {
+		t.Fatal("Expected error without a started browser")
+	}
+	if stats := manager.PagePoolStats(); stats.Misses != 1 || stats.Idle != 0 {
+		t.Errorf("Expected one miss and no idle pages, got %+v", stats)
+	}
+}
@@ -0,0 +1,111 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PageQueueTimeout bounds how long an operation waits for its turn on a busy page
+const PageQueueTimeout = 30 * time.Second
+
+// pageQueue serializes operations per page so concurrent tool calls against
+// the same page run one at a time while different pages proceed in parallel.
+// Each page has a one-slot channel; waiting senders are served in arrival order.
+type pageQueue struct {
+	mutex   sync.Mutex
+	slots   map[string]chan struct{}
+	waiting map[string]int
+}
+
+func newPageQueue() *pageQueue {
+	return &pageQueue{
+		slots:   make(map[string]chan struct{}),
+		waiting: make(map[string]int),
+	}
+}
+
+func (q *pageQueue) slot(pageID string) chan struct{} {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	s, ok := q.slots[pageID]
+	if !ok {
+		s = make(chan struct{}, 1)
+		q.slots[pageID] = s
+	}
+	q.waiting[pageID]++
+	return s
+}
+
+func (q *pageQueue) done(pageID string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.waiting[pageID]--
+	if q.waiting[pageID] <= 0 {
+		delete(q.waiting, pageID)
+	}
+}
+
+func (q *pageQueue) depth(pageID string) int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.waiting[pageID]
+}
+
+// busy reports whether any page has an operation running or waiting
+func (q *pageQueue) busy() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.waiting) > 0
+}
+
+// forget drops a closed page's slot once nobody is queued on it
+func (q *pageQueue) forget(pageID string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	if q.waiting[pageID] == 0 {
+		delete(q.slots, pageID)
+	}
+}
+
+// AcquirePage waits for exclusive use of a page and returns a function that
+// releases it. It fails if ctx ends before the page becomes free.
+func (m *Manager) AcquirePage(ctx context.Context, pageID string) (func(), error) {
+	m.touch()
+	slot := m.pageQueue.slot(pageID)
+
+	select {
+	case slot <- struct{}{}:
+	case <-ctx.Done():
+		m.pageQueue.done(pageID)
+		return nil, fmt.Errorf("page %s is busy with another operation: %w", pageID, ctx.Err())
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			<-slot
+			m.pageQueue.done(pageID)
+		})
+	}, nil
+}
+
+// PageQueueDepth reports how many operations are running or waiting on a page
+func (m *Manager) PageQueueDepth(pageID string) int {
+	return m.pageQueue.depth(pageID)
+}
+
+// acquirePageWithTimeout acquires a page using the default queue timeout
+func (m *Manager) acquirePageWithTimeout(pageID string) (func(), error) {
+	return m.acquirePageContext(context.Background(), pageID)
+}
+
+// acquirePageContext acquires a page within the default queue timeout and
+// ctx's deadline, whichever comes first
+func (m *Manager) acquirePageContext(ctx context.Context, pageID string) (func(), error) {
+	queueCtx, cancel := stepContext(ctx, PageQueueTimeout)
+	defer cancel()
+	release, err := m.AcquirePage(queueCtx, pageID)
+	return release, deadlineError(ctx, err)
+}
@@ -0,0 +1,110 @@
+package browser
+
+import (
+	"context"
+	"rodmcp/internal/logger"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newQueueTestManager(t *testing.T) *Manager {
+	log, err := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return NewManager(log, Config{Headless: true})
+}
+
+func TestAcquirePageSerializesSamePage(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	release, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatalf("First acquire failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			r, err := manager.AcquirePage(context.Background(), "page_1")
+			if err != nil {
+				t.Errorf("Queued acquire failed: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, n)
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			r()
+		}(i)
+	}
+
+	// Wait until every goroutine is queued behind the holder
+	deadline := time.Now().Add(2 * time.Second)
+	for manager.PageQueueDepth("page_1") < 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if depth := manager.PageQueueDepth("page_1"); depth != 4 {
+		t.Fatalf("Expected queue depth 4, got %d", depth)
+	}
+
+	mu.Lock()
+	if len(order) != 0 {
+		t.Errorf("Expected queued operations to wait, %d ran early", len(order))
+	}
+	mu.Unlock()
+
+	release()
+	// Releasing twice must not free a slot someone else holds
+	release()
+	wg.Wait()
+
+	if len(order) != 3 {
+		t.Errorf("Expected all 3 queued operations to run, got %d", len(order))
+	}
+	if depth := manager.PageQueueDepth("page_1"); depth != 0 {
+		t.Errorf("Expected empty queue, got depth %d", depth)
+	}
+}
+
+func TestAcquirePageDifferentPagesRunInParallel(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	release1, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatalf("Acquire page_1 failed: %v", err)
+	}
+	defer release1()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	release2, err := manager.AcquirePage(ctx, "page_2")
+	if err != nil {
+		t.Fatalf("Expected page_2 to be available while page_1 is busy: %v", err)
+	}
+	release2()
+}
+
+func TestAcquirePageTimeout(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	release, err := manager.AcquirePage(context.Background(), "page_1")
+	if err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := manager.AcquirePage(ctx, "page_1"); err == nil {
+		t.Fatal("Expected busy page to time out")
+	}
+	if depth := manager.PageQueueDepth("page_1"); depth != 1 {
+		t.Errorf("Expected timed-out waiter to leave the queue, depth %d", depth)
+	}
+}
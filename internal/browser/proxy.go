@@ -0,0 +1,68 @@
+package browser
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+// proxySchemes are the proxy types Chrome's --proxy-server takes
+var proxySchemes = map[string]bool{"http": true, "https": true, "socks4": true, "socks5": true}
+
+// ParseProxyServer checks a proxy given as a URL or host:port and splits
+// off any credentials in it, which Chrome ignores in --proxy-server. A
+// scheme-specific list such as "http=proxy1:8080;https=proxy2:8080" is
+// passed through as is.
+func ParseProxyServer(raw string) (server, username, password string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", "", nil
+	}
+	if strings.Contains(raw, "=") {
+		if strings.Contains(raw, "@") {
+			return "", "", "", fmt.Errorf("credentials are not supported in a per-scheme proxy list; give them separately")
+		}
+		return raw, "", "", nil
+	}
+
+	withScheme := raw
+	if !strings.Contains(raw, "://") {
+		withScheme = "http://" + raw
+	}
+	u, err := url.Parse(withScheme)
+	if err != nil {
+		return "", "", "", fmt.Errorf("invalid proxy server %q: %w", raw, err)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if !proxySchemes[scheme] {
+		return "", "", "", fmt.Errorf("unsupported proxy scheme %q (use http, https, socks4 or socks5)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return "", "", "", fmt.Errorf("proxy server %q has no host", raw)
+	}
+	if u.Path != "" && u.Path != "/" || u.RawQuery != "" {
+		return "", "", "", fmt.Errorf("proxy server %q must not have a path or query", raw)
+	}
+	if u.User != nil {
+		username = u.User.Username()
+		password, _ = u.User.Password()
+		if strings.HasPrefix(scheme, "socks") {
+			return "", "", "", fmt.Errorf("Chrome does not support authenticated SOCKS proxies")
+		}
+	}
+	return scheme + "://" + u.Host, username, password, nil
+}
+
+// applyProxyFlags routes the browser through the configured proxy
+func applyProxyFlags(l *launcher.Launcher, config Config) *launcher.Launcher {
+	if config.ProxyServer == "" {
+		return l
+	}
+	l = l.Proxy(config.ProxyServer)
+	if config.ProxyBypassList != "" {
+		l = l.Set("proxy-bypass-list", config.ProxyBypassList)
+	}
+	return l
+}
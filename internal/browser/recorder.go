@@ -0,0 +1,196 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// recorderBinding is the CDP binding the recorder script reports through
+const recorderBinding = "__rodmcpRecord"
+
+// maxRecordedActions bounds a recording so a forgotten one cannot grow
+// without limit
+const maxRecordedActions = 2000
+
+// Recorded action types
+const (
+	RecordedNavigate = "navigate"
+	RecordedClick    = "click"
+	RecordedInput    = "input"
+	RecordedSelect   = "select"
+	RecordedKey      = "key"
+)
+
+// RecordedAction is one thing the user did while a page was being recorded
+type RecordedAction struct {
+	Type     string `json:"type"`
+	Selector string `json:"selector,omitempty"`
+	// Value is the typed text, chosen option or pressed key
+	Value string `json:"value,omitempty"`
+	// Sensitive marks input into a password field
+	Sensitive bool      `json:"sensitive,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// Recording collects a page's user actions until stopped
+type Recording struct {
+	PageID  string
+	Started time.Time
+
+	mutex   sync.Mutex
+	actions []RecordedAction
+	stop    func()
+	stopped bool
+}
+
+// recorderScript listens for clicks, committed input, option changes and
+// Enter presses in the capture phase, so page handlers cannot hide them,
+// and reports each with a selector for its element
+const recorderScript = `(() => {
+	if (window.__rodmcpRecorder) return;
+	window.__rodmcpRecorder = true;
+
+	const send = (action) => {
+		try { window.` + recorderBinding + `(JSON.stringify(action)); } catch (e) {}
+	};
+` + selectorForScript + `
+	const isTextField = (el) => el.tagName === 'TEXTAREA' || el.isContentEditable ||
+		(el.tagName === 'INPUT' && !['checkbox', 'radio', 'button', 'submit', 'reset', 'file', 'image', 'hidden'].includes(el.type));
+	const reportInput = (el) => {
+		const value = el.isContentEditable ? el.innerText : el.value;
+		if (el.__rodmcpRecorded === value) return;
+		el.__rodmcpRecorded = value;
+		send({ type: 'input', selector: selectorFor(el), value: value, sensitive: el.type === 'password' });
+	};
+
+	document.addEventListener('click', (event) => {
+		if (!event.isTrusted || !(event.target instanceof Element)) return;
+		const el = event.target.closest('a, button, input, select, textarea, label, summary, [role="button"], [role="link"], [role="tab"], [role="menuitem"], [onclick]') || event.target;
+		if (isTextField(el) || el.tagName === 'SELECT') return;
+		send({ type: 'click', selector: selectorFor(el) });
+	}, true);
+
+	document.addEventListener('change', (event) => {
+		const el = event.target;
+		if (!event.isTrusted || !(el instanceof Element)) return;
+		if (el.tagName === 'SELECT') {
+			send({ type: 'select', selector: selectorFor(el), value: el.value });
+		} else if (isTextField(el)) {
+			reportInput(el);
+		}
+	}, true);
+
+	document.addEventListener('keydown', (event) => {
+		const el = event.target;
+		if (!event.isTrusted || event.key !== 'Enter' || !(el instanceof Element) || el.tagName === 'TEXTAREA' || !isTextField(el)) return;
+		// Enter can submit before change fires, so report the text first
+		reportInput(el);
+		send({ type: 'key', selector: selectorFor(el), value: 'Enter' });
+	}, true);
+})()`
+
+// StartRecording begins capturing the user's clicks, typing and navigation
+// on a page. The recorder survives navigation: its script is added to every
+// new document and reports through a CDP binding.
+func (m *Manager) StartRecording(pageID string) (*Recording, error) {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Recording{PageID: pageID, Started: time.Now()}
+	if info, err := page.Info(); err == nil && info.URL != "" {
+		rec.add(RecordedAction{Type: RecordedNavigate, URL: info.URL})
+	}
+
+	if err := (proto.RuntimeAddBinding{Name: recorderBinding}).Call(page); err != nil {
+		return nil, fmt.Errorf("failed to add recorder binding: %w", err)
+	}
+	remove, err := page.EvalOnNewDocument(recorderScript)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install recorder: %w", err)
+	}
+	if _, err := page.Eval(`() => ` + recorderScript); err != nil {
+		remove()
+		return nil, fmt.Errorf("failed to start recorder: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	wait := page.Context(ctx).EachEvent(
+		func(e *proto.RuntimeBindingCalled) {
+			if e.Name != recorderBinding {
+				return
+			}
+			var action RecordedAction
+			if err := json.Unmarshal([]byte(e.Payload), &action); err == nil {
+				rec.add(action)
+			}
+		},
+		func(e *proto.PageFrameNavigated) {
+			if e.Frame != nil && e.Frame.ParentID == "" {
+				rec.add(RecordedAction{Type: RecordedNavigate, URL: e.Frame.URL})
+			}
+		},
+	)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Recorder stopped",
+					zap.String("page_id", pageID),
+					zap.Any("reason", r))
+			}
+		}()
+		wait()
+	}()
+
+	rec.stop = func() {
+		cancel()
+		remove()
+		proto.RuntimeRemoveBinding{Name: recorderBinding}.Call(page)
+	}
+	m.logger.LogBrowserAction("recording_started", pageID, 0)
+	return rec, nil
+}
+
+func (r *Recording) add(action RecordedAction) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.stopped || len(r.actions) >= maxRecordedActions {
+		return
+	}
+	if action.Time.IsZero() {
+		action.Time = time.Now()
+	}
+	r.actions = append(r.actions, action)
+}
+
+// Actions returns what has been recorded so far
+func (r *Recording) Actions() []RecordedAction {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RecordedAction(nil), r.actions...)
+}
+
+// Stop ends the recording and returns its actions. Stopping twice is
+// harmless.
+func (r *Recording) Stop() []RecordedAction {
+	r.mutex.Lock()
+	stop := r.stop
+	if r.stopped {
+		stop = nil
+	}
+	r.stopped = true
+	r.mutex.Unlock()
+
+	if stop != nil {
+		stop()
+	}
+	return r.Actions()
+}
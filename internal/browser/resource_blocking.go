@@ -0,0 +1,169 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// BlockableResourceTypes lists the resource types a page can refuse to load
+var BlockableResourceTypes = []string{"image", "font", "media", "stylesheet"}
+
+var blockableResourceTypes = map[string]proto.NetworkResourceType{
+	"image":      proto.NetworkResourceTypeImage,
+	"font":       proto.NetworkResourceTypeFont,
+	"media":      proto.NetworkResourceTypeMedia,
+	"stylesheet": proto.NetworkResourceTypeStylesheet,
+}
+
+// PageOptions configures a page before its first navigation
+type PageOptions struct {
+	// BlockResources lists resource types (see BlockableResourceTypes) the page will not load
+	BlockResources []string
+	// Fingerprint, when set, is applied before the first navigation
+	Fingerprint *Fingerprint
+}
+
+// resourceBlocker tracks the request interception attached to each page
+type resourceBlocker struct {
+	mutex   sync.Mutex
+	cancels map[string]context.CancelFunc
+	types   map[string][]string
+}
+
+func newResourceBlocker() *resourceBlocker {
+	return &resourceBlocker{
+		cancels: make(map[string]context.CancelFunc),
+		types:   make(map[string][]string),
+	}
+}
+
+// NormalizeResourceTypes validates resource type names, lower-casing and de-duplicating them
+func NormalizeResourceTypes(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	var normalized []string
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := blockableResourceTypes[name]; !ok {
+			return nil, fmt.Errorf("unknown resource type %q (supported: %s)", name, strings.Join(BlockableResourceTypes, ", "))
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized, nil
+}
+
+// BlockResources makes a page fail requests for the given resource types,
+// replacing any previous setting. An empty list removes blocking.
+func (m *Manager) BlockResources(pageID string, resourceTypes []string) error {
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+	return m.applyResourceBlocking(pageID, page, resourceTypes)
+}
+
+// BlockedResources returns the resource types currently blocked on a page
+func (m *Manager) BlockedResources(pageID string) []string {
+	m.blocking.mutex.Lock()
+	defer m.blocking.mutex.Unlock()
+	return append([]string(nil), m.blocking.types[pageID]...)
+}
+
+func (m *Manager) applyResourceBlocking(pageID string, page *rod.Page, resourceTypes []string) error {
+	normalized, err := NormalizeResourceTypes(resourceTypes)
+	if err != nil {
+		return err
+	}
+
+	m.stopResourceBlocking(pageID, page, len(normalized) == 0)
+	if len(normalized) == 0 {
+		return nil
+	}
+
+	// Only requests of the blocked types are paused, so everything else loads untouched
+	patterns := make([]*proto.FetchRequestPattern, 0, len(normalized))
+	for _, name := range normalized {
+		patterns = append(patterns, &proto.FetchRequestPattern{
+			URLPattern:   "*",
+			ResourceType: blockableResourceTypes[name],
+		})
+	}
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	watched := page.Context(ctx)
+
+	enableCtx, enableCancel := context.WithTimeout(ctx, 5*time.Second)
+	defer enableCancel()
+	if err := (proto.FetchEnable{Patterns: patterns}).Call(page.Context(enableCtx)); err != nil {
+		cancel()
+		return fmt.Errorf("failed to enable resource blocking: %w", err)
+	}
+
+	m.blocking.mutex.Lock()
+	m.blocking.cancels[pageID] = cancel
+	m.blocking.types[pageID] = normalized
+	m.blocking.mutex.Unlock()
+
+	wait := watched.EachEvent(func(e *proto.FetchRequestPaused) {
+		failErr := proto.FetchFailRequest{
+			RequestID:   e.RequestID,
+			ErrorReason: proto.NetworkErrorReasonBlockedByClient,
+		}.Call(watched)
+		if failErr != nil && ctx.Err() == nil {
+			m.logger.WithComponent("browser").Debug("Failed to block request",
+				zap.String("page_id", pageID),
+				zap.String("url", e.Request.URL),
+				zap.Error(failErr))
+		}
+	})
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				m.logger.WithComponent("browser").Debug("Resource blocker stopped after panic",
+					zap.String("page_id", pageID),
+					zap.Any("panic", r))
+			}
+		}()
+		wait()
+	}()
+
+	m.logger.WithComponent("browser").Debug("Resource blocking enabled",
+		zap.String("page_id", pageID),
+		zap.Strings("types", normalized))
+	return nil
+}
+
+// stopResourceBlocking detaches a page's blocker. With disable set the Fetch
+// domain is turned off too, which matters for pages that stay open.
+func (m *Manager) stopResourceBlocking(pageID string, page *rod.Page, disable bool) {
+	if m.blocking == nil {
+		return
+	}
+
+	m.blocking.mutex.Lock()
+	cancel := m.blocking.cancels[pageID]
+	delete(m.blocking.cancels, pageID)
+	delete(m.blocking.types, pageID)
+	m.blocking.mutex.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+
+	if disable && page != nil {
+		ctx, ctxCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer ctxCancel()
+		proto.FetchDisable{}.Call(page.Context(ctx))
+	}
+}
@@ -0,0 +1,45 @@
+package browser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeResourceTypes(t *testing.T) {
+	got, err := NormalizeResourceTypes([]string{"Image", " font ", "image", "stylesheet"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	want := []string{"image", "font", "stylesheet"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	if _, err := NormalizeResourceTypes([]string{"image", "script"}); err == nil {
+		t.Error("Expected error for unsupported resource type")
+	}
+
+	if got, err := NormalizeResourceTypes(nil); err != nil || len(got) != 0 {
+		t.Errorf("Expected empty result for no types, got %v %v", got, err)
+	}
+}
+
+func TestBlockResourcesUnknownPage(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	if err := manager.BlockResources("missing", []string{"image"}); err == nil {
+		t.Error("Expected error for unknown page")
+	}
+	if blocked := manager.BlockedResources("missing"); len(blocked) != 0 {
+		t.Errorf("Expected nothing blocked, got %v", blocked)
+	}
+}
+
+func TestNewPageWithOptionsRejectsUnknownResource(t *testing.T) {
+	manager := newQueueTestManager(t)
+
+	_, _, err := manager.NewPageWithOptions("about:blank", PageOptions{BlockResources: []string{"video"}})
+	if err == nil {
+		t.Fatal("Expected error for unknown resource type")
+	}
+}
@@ -0,0 +1,162 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const (
+	// DefaultScreenshotMaxHeight is how much of a tall page a full-page
+	// capture keeps when no limit is given; Chrome struggles to encode
+	// images much taller than this
+	DefaultScreenshotMaxHeight = 16384
+	// MaxScreenshotHeight bounds the configurable limit
+	MaxScreenshotHeight = 32768
+	// DefaultScreenshotQuality is used for JPEG and WebP captures when no
+	// quality is given
+	DefaultScreenshotQuality = 80
+
+	screenshotTimeout         = 10 * time.Second
+	fullPageScreenshotTimeout = 30 * time.Second
+)
+
+// ScreenshotFormats lists the image formats a capture can be encoded in
+var ScreenshotFormats = []string{
+	string(proto.PageCaptureScreenshotFormatPng),
+	string(proto.PageCaptureScreenshotFormatJpeg),
+	string(proto.PageCaptureScreenshotFormatWebp),
+}
+
+// ScreenshotOptions controls CaptureScreenshot
+type ScreenshotOptions struct {
+	// FullPage captures the whole scroll height instead of the viewport
+	FullPage bool
+	// MaxHeight cuts full-page captures off at this many CSS pixels
+	// (0 for DefaultScreenshotMaxHeight)
+	MaxHeight int
+	// Format is one of ScreenshotFormats ("" for png)
+	Format string
+	// Quality is the JPEG or WebP quality, 1-100 (0 for
+	// DefaultScreenshotQuality); ignored for PNG
+	Quality int
+}
+
+// PageCapture is an encoded screenshot and the area it covers
+type PageCapture struct {
+	Data   []byte
+	Format string
+	// Width and Height are the captured area in CSS pixels
+	Width  int
+	Height int
+	// ContentHeight is the page's full scroll height; it exceeds Height
+	// when a full-page capture was cut off at MaxHeight
+	ContentHeight int
+}
+
+// Truncated reports whether a full-page capture stopped short of the
+// bottom of the page
+func (c *PageCapture) Truncated() bool {
+	return c.ContentHeight > c.Height
+}
+
+// fullPageClip returns the area covering the page's whole content,
+// cut off at maxHeight, and the content's full height
+func fullPageClip(metrics *proto.PageGetLayoutMetricsResult, maxHeight int) (*proto.PageViewport, int, error) {
+	if metrics.CSSContentSize == nil {
+		return nil, 0, fmt.Errorf("failed to get page content size")
+	}
+	width := math.Ceil(metrics.CSSContentSize.Width)
+	height := math.Ceil(metrics.CSSContentSize.Height)
+	if metrics.CSSLayoutViewport != nil {
+		// Short pages still fill the viewport
+		width = math.Max(width, float64(metrics.CSSLayoutViewport.ClientWidth))
+		height = math.Max(height, float64(metrics.CSSLayoutViewport.ClientHeight))
+	}
+	contentHeight := int(height)
+	if contentHeight > maxHeight {
+		height = float64(maxHeight)
+	}
+	return &proto.PageViewport{Width: width, Height: height, Scale: 1}, contentHeight, nil
+}
+
+// CaptureScreenshot takes a screenshot of the viewport or, with FullPage,
+// of the entire page using CDP's captureBeyondViewport so the viewport
+// isn't resized and responsive layouts don't reflow
+func (m *Manager) CaptureScreenshot(pageID string, opts ScreenshotOptions) (*PageCapture, error) {
+	return m.CaptureScreenshotContext(context.Background(), pageID, opts)
+}
+
+// CaptureScreenshotContext is CaptureScreenshot finished by ctx's deadline
+func (m *Manager) CaptureScreenshotContext(ctx context.Context, pageID string, opts ScreenshotOptions) (*PageCapture, error) {
+	start := time.Now()
+
+	format := proto.PageCaptureScreenshotFormatPng
+	if opts.Format != "" {
+		format = proto.PageCaptureScreenshotFormat(opts.Format)
+	}
+	req := proto.PageCaptureScreenshot{Format: format}
+	switch format {
+	case proto.PageCaptureScreenshotFormatPng:
+	case proto.PageCaptureScreenshotFormatJpeg, proto.PageCaptureScreenshotFormatWebp:
+		quality := opts.Quality
+		if quality == 0 {
+			quality = DefaultScreenshotQuality
+		}
+		if quality < 1 || quality > 100 {
+			return nil, fmt.Errorf("quality must be between 1 and 100")
+		}
+		req.Quality = &quality
+	default:
+		return nil, fmt.Errorf("unsupported screenshot format %q", opts.Format)
+	}
+	maxHeight := opts.MaxHeight
+	if maxHeight == 0 {
+		maxHeight = DefaultScreenshotMaxHeight
+	}
+	if maxHeight < 1 || maxHeight > MaxScreenshotHeight {
+		return nil, fmt.Errorf("max height must be between 1 and %d", MaxScreenshotHeight)
+	}
+
+	timeout := screenshotTimeout
+	if opts.FullPage {
+		timeout = fullPageScreenshotTimeout
+	}
+	capture := &PageCapture{Format: string(format)}
+	err := m.withPageContext(ctx, pageID, timeout, func(p *rod.Page) error {
+		metrics, err := proto.PageGetLayoutMetrics{}.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to get page size: %w", err)
+		}
+		if opts.FullPage {
+			clip, contentHeight, err := fullPageClip(metrics, maxHeight)
+			if err != nil {
+				return err
+			}
+			req.Clip = clip
+			req.CaptureBeyondViewport = true
+			capture.Width, capture.Height, capture.ContentHeight = int(clip.Width), int(clip.Height), contentHeight
+		} else if metrics.CSSVisualViewport != nil {
+			capture.Width = int(metrics.CSSVisualViewport.ClientWidth)
+			capture.Height = int(metrics.CSSVisualViewport.ClientHeight)
+			capture.ContentHeight = capture.Height
+		}
+
+		shot, err := req.Call(p)
+		if err != nil {
+			return fmt.Errorf("failed to take screenshot: %w", err)
+		}
+		capture.Data = shot.Data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.LogBrowserAction("screenshot", pageID, time.Since(start).Milliseconds())
+	return capture, nil
+}
@@ -0,0 +1,49 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestFullPageClip(t *testing.T) {
+	metrics := &proto.PageGetLayoutMetricsResult{
+		CSSLayoutViewport: &proto.PageLayoutViewport{ClientWidth: 1280, ClientHeight: 720},
+		CSSContentSize:    &proto.DOMRect{Width: 1280, Height: 5000.4},
+	}
+	clip, contentHeight, err := fullPageClip(metrics, DefaultScreenshotMaxHeight)
+	if err != nil || clip.Width != 1280 || clip.Height != 5001 || contentHeight != 5001 {
+		t.Errorf("got %+v, %d, %v", clip, contentHeight, err)
+	}
+
+	// Tall pages are cut off at the limit
+	clip, contentHeight, _ = fullPageClip(metrics, 2000)
+	if clip.Height != 2000 || contentHeight != 5001 {
+		t.Errorf("limited clip = %+v, content height %d", clip, contentHeight)
+	}
+
+	// Pages shorter than the viewport still fill it
+	metrics.CSSContentSize = &proto.DOMRect{Width: 800, Height: 300}
+	if clip, _, _ = fullPageClip(metrics, 2000); clip.Width != 1280 || clip.Height != 720 {
+		t.Errorf("short page clip = %+v", clip)
+	}
+
+	if _, _, err := fullPageClip(&proto.PageGetLayoutMetricsResult{}, 2000); err == nil {
+		t.Error("expected an error without a content size")
+	}
+}
+
+func TestCaptureScreenshotOptions(t *testing.T) {
+	manager := NewManager(createTestLogger(t), Config{Headless: true})
+	cases := map[string]ScreenshotOptions{
+		"unsupported screenshot format": {Format: "gif"},
+		"quality must be":               {Format: "jpeg", Quality: 101},
+		"max height must be":            {FullPage: true, MaxHeight: MaxScreenshotHeight + 1},
+	}
+	for want, opts := range cases {
+		if _, err := manager.CaptureScreenshot("nonexistent", opts); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("CaptureScreenshot(%+v) = %v, want error containing %q", opts, err, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package browser
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// Selector kinds. Text selectors are compiled to XPath, so the browser only
+// ever sees CSS or XPath.
+const (
+	SelectorCSS   = "css"
+	SelectorXPath = "xpath"
+	SelectorText  = "text"
+)
+
+// Selector is an element selector as tools accept it:
+//
+//	#submit, .nav a            CSS
+//	css=button.primary         CSS, explicitly
+//	//button[@type='submit']   XPath: starts with /, ./ or (
+//	xpath=//button             XPath, explicitly
+//	text=sign in               the innermost element whose text contains
+//	                           "sign in", ignoring case
+//	text="Sign in"             the innermost element whose whole text is
+//	                           exactly "Sign in"
+type Selector struct {
+	Raw  string
+	Kind string
+	// Query is the CSS selector or XPath expression sent to the browser
+	Query string
+}
+
+// ParseSelector works out what kind of selector raw is
+func ParseSelector(raw string) (Selector, error) {
+	trimmed := strings.TrimSpace(raw)
+	sel := Selector{Raw: raw}
+	switch {
+	case trimmed == "":
+		return sel, fmt.Errorf("selector cannot be empty")
+	case strings.HasPrefix(trimmed, "css="):
+		sel.Kind, sel.Query = SelectorCSS, strings.TrimSpace(trimmed[len("css="):])
+	case strings.HasPrefix(trimmed, "xpath="):
+		sel.Kind, sel.Query = SelectorXPath, strings.TrimSpace(trimmed[len("xpath="):])
+	case strings.HasPrefix(trimmed, "text="):
+		sel.Kind = SelectorText
+		sel.Query = textXPath(strings.TrimSpace(trimmed[len("text="):]))
+	case strings.HasPrefix(trimmed, "/") || strings.HasPrefix(trimmed, "./") || strings.HasPrefix(trimmed, "("):
+		sel.Kind, sel.Query = SelectorXPath, trimmed
+	default:
+		sel.Kind, sel.Query = SelectorCSS, trimmed
+	}
+	if sel.Query == "" || sel.Query == textXPath("") {
+		return sel, fmt.Errorf("selector %q has nothing after its prefix", raw)
+	}
+	return sel, nil
+}
+
+// IsXPath reports whether the browser evaluates the selector as XPath
+func (s Selector) IsXPath() bool {
+	return s.Kind != SelectorCSS
+}
+
+// Find waits for the first element matching the selector, for as long as
+// p's context allows
+func (s Selector) Find(p *rod.Page) (*rod.Element, error) {
+	var (
+		el  *rod.Element
+		err error
+	)
+	if s.IsXPath() {
+		el, err = p.ElementX(s.Query)
+	} else {
+		el, err = p.Element(s.Query)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("element not found with selector %s: %w", s.Raw, err)
+	}
+	return el, nil
+}
+
+// JSFirst is a JavaScript expression for the first matching element, or
+// null. The query is embedded as a JSON string, so quotes in selectors
+// cannot break out of it.
+func (s Selector) JSFirst() string {
+	query, _ := json.Marshal(s.Query)
+	if s.IsXPath() {
+		return fmt.Sprintf("document.evaluate(%s, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue", query)
+	}
+	return fmt.Sprintf("document.querySelector(%s)", query)
+}
+
+// JSAll is a JavaScript expression for an array of every matching element
+func (s Selector) JSAll() string {
+	query, _ := json.Marshal(s.Query)
+	if s.IsXPath() {
+		return fmt.Sprintf(`((q) => {
+			const found = document.evaluate(q, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+			const elements = [];
+			for (let i = 0; i < found.snapshotLength; i++) elements.push(found.snapshotItem(i));
+			return elements;
+		})(%s)`, query)
+	}
+	return fmt.Sprintf("Array.from(document.querySelectorAll(%s))", query)
+}
+
+// textXPath compiles the text of a text= selector. Quoted text must match
+// the element's whole (whitespace-normalised) text; otherwise the text is
+// looked for case-insensitively. Either way the innermost match wins, so
+// text=Login finds the button rather than the body around it.
+func textXPath(text string) string {
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		match := fmt.Sprintf("normalize-space(.)=%s", XPathLiteral(text[1:len(text)-1]))
+		return fmt.Sprintf("//body//*[%s][not(.//*[%s])]", match, match)
+	}
+	const upper, lower = "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "abcdefghijklmnopqrstuvwxyz"
+	match := fmt.Sprintf("contains(translate(normalize-space(.), '%s', '%s'), %s)", upper, lower, XPathLiteral(strings.ToLower(text)))
+	return fmt.Sprintf("//body//*[%s][not(.//*[%s])]", match, match)
+}
+
+// XPathLiteral quotes s as an XPath string. XPath has no escapes, so text
+// holding both kinds of quote is built with concat().
+func XPathLiteral(s string) string {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'"
+	}
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`
+	}
+	parts := strings.Split(s, "'")
+	quoted := make([]string, 0, 2*len(parts))
+	for i, part := range parts {
+		if i > 0 {
+			quoted = append(quoted, `"'"`)
+		}
+		quoted = append(quoted, "'"+part+"'")
+	}
+	return "concat(" + strings.Join(quoted, ", ") + ")"
+}
+
+// selectorForScript defines selectorFor(el) for page scripts. It prefers
+// stable attributes, then the text of links and buttons, then a CSS path
+// anchored at the nearest unique id, and returns a selector ParseSelector
+// accepts.
+const selectorForScript = `
+	const escape = (s) => (window.CSS && CSS.escape) ? CSS.escape(s) : s.replace(/[^\w-]/g, '\\$&');
+	const unique = (selector) => {
+		try { return document.querySelectorAll(selector).length === 1; } catch (e) { return false; }
+	};
+	const textOf = (el) => (el.innerText || '').trim().replace(/\s+/g, ' ');
+
+	// selectorFor prefers stable attributes, then the text of links and
+	// buttons, then a CSS path anchored at the nearest unique id
+	const selectorFor = (el) => {
+		const tag = el.tagName.toLowerCase();
+		if (el.id && unique('#' + escape(el.id))) return '#' + escape(el.id);
+		for (const attr of ['data-testid', 'data-test', 'data-cy', 'name', 'aria-label', 'placeholder']) {
+			const value = el.getAttribute(attr);
+			if (!value) continue;
+			const selector = tag + '[' + attr + '=' + JSON.stringify(value) + ']';
+			if (unique(selector)) return selector;
+		}
+		if (tag === 'a' || tag === 'button' || el.getAttribute('role') === 'button') {
+			const text = textOf(el);
+			if (text && text.length <= 40 && !text.includes('"')) {
+				const same = Array.from(document.querySelectorAll('a, button, [role="button"]')).filter((other) => textOf(other) === text);
+				if (same.length === 1) return 'text="' + text + '"';
+			}
+		}
+		const parts = [];
+		for (let node = el; node && node.nodeType === 1 && node !== document.documentElement; node = node.parentElement) {
+			if (node !== el && node.id && unique('#' + escape(node.id))) {
+				parts.unshift('#' + escape(node.id));
+				break;
+			}
+			let part = node.tagName.toLowerCase();
+			const parent = node.parentElement;
+			if (parent) {
+				const same = Array.from(parent.children).filter((child) => child.tagName === node.tagName);
+				if (same.length > 1) part += ':nth-of-type(' + (same.indexOf(node) + 1) + ')';
+			}
+			parts.unshift(part);
+		}
+		return parts.join(' > ');
+	};
+`
@@ -0,0 +1,66 @@
+package browser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	cases := []struct {
+		raw, kind, query string
+	}{
+		{"#submit", SelectorCSS, "#submit"},
+		{`button[title="it's"]`, SelectorCSS, `button[title="it's"]`},
+		{"css=div > a", SelectorCSS, "div > a"},
+		{"//button[text()='Login']", SelectorXPath, "//button[text()='Login']"},
+		{"(//a)[2]", SelectorXPath, "(//a)[2]"},
+		{"./span", SelectorXPath, "./span"},
+		{"xpath=//input", SelectorXPath, "//input"},
+	}
+	for _, tc := range cases {
+		sel, err := ParseSelector(tc.raw)
+		if err != nil || sel.Kind != tc.kind || sel.Query != tc.query {
+			t.Errorf("ParseSelector(%q) = %+v, %v; want %s %q", tc.raw, sel, err, tc.kind, tc.query)
+		}
+	}
+
+	sel, err := ParseSelector("text=Sign In")
+	if err != nil || sel.Kind != SelectorText || !sel.IsXPath() || !strings.Contains(sel.Query, "'sign in'") {
+		t.Errorf("text selector = %+v, %v", sel, err)
+	}
+	sel, _ = ParseSelector(`text="Sign In"`)
+	if !strings.Contains(sel.Query, "normalize-space(.)='Sign In'") {
+		t.Errorf("exact text selector = %q", sel.Query)
+	}
+
+	for _, raw := range []string{"", "  ", "css=", "xpath=", "text="} {
+		if _, err := ParseSelector(raw); err == nil {
+			t.Errorf("ParseSelector(%q): expected an error", raw)
+		}
+	}
+}
+
+func TestXPathLiteral(t *testing.T) {
+	cases := map[string]string{
+		"plain":       "'plain'",
+		"it's":        `"it's"`,
+		`say "hi"`:    `'say "hi"'`,
+		`it's "fine"`: `concat('it', "'", 's "fine"')`,
+	}
+	for in, want := range cases {
+		if got := XPathLiteral(in); got != want {
+			t.Errorf("XPathLiteral(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestSelectorJS(t *testing.T) {
+	css, _ := ParseSelector(`a[title='x"y']`)
+	if got := css.JSFirst(); got != `document.querySelector("a[title='x\"y']")` {
+		t.Errorf("css JSFirst = %s", got)
+	}
+	xpath, _ := ParseSelector("//a")
+	if !strings.Contains(xpath.JSFirst(), "document.evaluate(\"//a\"") || !strings.Contains(xpath.JSAll(), "snapshotItem") {
+		t.Errorf("xpath JS = %s / %s", xpath.JSFirst(), xpath.JSAll())
+	}
+}
@@ -0,0 +1,156 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// SessionState is the browser state that keeps a user signed in: every
+// cookie in the browser plus localStorage and sessionStorage for the page's
+// origin
+type SessionState struct {
+	URL            string                 `json:"url"`
+	Origin         string                 `json:"origin,omitempty"`
+	Cookies        []*proto.NetworkCookie `json:"cookies"`
+	LocalStorage   map[string]string      `json:"local_storage,omitempty"`
+	SessionStorage map[string]string      `json:"session_storage,omitempty"`
+	CapturedAt     time.Time              `json:"captured_at"`
+}
+
+// HasStorage reports whether the session carries any web storage, which can
+// only be restored once a page is on the session's origin
+func (s *SessionState) HasStorage() bool {
+	return len(s.LocalStorage) > 0 || len(s.SessionStorage) > 0
+}
+
+const sessionTimeout = 10 * time.Second
+
+// CaptureSession reads the cookies and web storage a page currently sees
+func (m *Manager) CaptureSession(pageID string) (*SessionState, error) {
+	start := time.Now()
+
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+	p := page.Context(ctx)
+
+	cookies, err := proto.NetworkGetAllCookies{}.Call(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cookies: %w", err)
+	}
+
+	state := &SessionState{Cookies: cookies.Cookies, CapturedAt: time.Now().UTC()}
+	result, err := p.Eval(`() => {
+		const read = (name) => {
+			const items = {};
+			try {
+				const storage = window[name];
+				for (let i = 0; i < storage.length; i++) {
+					const key = storage.key(i);
+					items[key] = storage.getItem(key);
+				}
+			} catch (e) {}
+			return items;
+		};
+		return {
+			url: location.href,
+			origin: location.origin,
+			local: read('localStorage'),
+			session: read('sessionStorage'),
+		};
+	}`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read web storage: %w", err)
+	}
+	var storage struct {
+		URL     string            `json:"url"`
+		Origin  string            `json:"origin"`
+		Local   map[string]string `json:"local"`
+		Session map[string]string `json:"session"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.JSON("", "")), &storage); err != nil {
+		return nil, fmt.Errorf("failed to read web storage: %w", err)
+	}
+	state.URL = storage.URL
+	state.Origin = storage.Origin
+	if len(storage.Local) > 0 {
+		state.LocalStorage = storage.Local
+	}
+	if len(storage.Session) > 0 {
+		state.SessionStorage = storage.Session
+	}
+
+	m.logger.LogBrowserAction("session_captured", pageID, time.Since(start).Milliseconds())
+	return state, nil
+}
+
+// RestoreSession loads a captured session into the browser. Cookies are
+// always restored; localStorage and sessionStorage only when the page is
+// already on the session's origin, since storage is per origin. It reports
+// whether storage was restored.
+func (m *Manager) RestoreSession(pageID string, state *SessionState) (bool, error) {
+	start := time.Now()
+
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return false, err
+	}
+
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+	p := page.Context(ctx)
+
+	if len(state.Cookies) > 0 {
+		if err := p.SetCookies(proto.CookiesToParams(state.Cookies)); err != nil {
+			return false, fmt.Errorf("failed to restore cookies: %w", err)
+		}
+	}
+
+	restored := false
+	if state.HasStorage() && state.Origin != "" {
+		local, session := state.LocalStorage, state.SessionStorage
+		if local == nil {
+			local = map[string]string{}
+		}
+		if session == nil {
+			session = map[string]string{}
+		}
+		result, err := p.Eval(`(origin, local, session) => {
+			if (location.origin !== origin) return false;
+			for (const [key, value] of Object.entries(local)) {
+				localStorage.setItem(key, value);
+			}
+			for (const [key, value] of Object.entries(session)) {
+				sessionStorage.setItem(key, value);
+			}
+			return true;
+		}`, state.Origin, local, session)
+		if err != nil {
+			return false, fmt.Errorf("failed to restore web storage: %w", err)
+		}
+		restored = result.Value.Bool()
+	}
+
+	m.logger.LogBrowserAction("session_restored", pageID, time.Since(start).Milliseconds())
+	return restored, nil
+}
@@ -0,0 +1,96 @@
+package browser
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// MaxTextBlocks bounds how many blocks TextBlocks returns
+const MaxTextBlocks = 2000
+
+// TextBlock is the visible text of one block-level element, or the value
+// of a text attribute such as alt or placeholder
+type TextBlock struct {
+	Selector string `json:"selector"`
+	Text     string `json:"text"`
+	// Attribute is set when Text came from an attribute rather than the
+	// element's content
+	Attribute string `json:"attribute,omitempty"`
+	// NoSpellcheck is set inside spellcheck="false" and translate="no"
+	// content, which authors mark as not prose
+	NoSpellcheck bool `json:"no_spellcheck,omitempty"`
+}
+
+// textBlocksScript groups the root's visible text nodes by their nearest
+// block-level ancestor, keeping the raw text so doubled spaces survive.
+// Code, scripts and form field values are skipped.
+const textBlocksScript = `(limit) => {` + selectorForScript + `
+	const skip = new Set(['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'CODE', 'PRE', 'KBD', 'SAMP', 'TEXTAREA', 'SVG', 'svg']);
+	const visible = new Map();
+	const isVisible = (el) => {
+		if (!visible.has(el)) {
+			const style = getComputedStyle(el);
+			visible.set(el, el.getClientRects().length > 0 && style.visibility !== 'hidden' && style.opacity !== '0');
+		}
+		return visible.get(el);
+	};
+	const blockOf = (el) => {
+		for (let node = el; node && node !== this; node = node.parentElement) {
+			const display = getComputedStyle(node).display;
+			if (!display.startsWith('inline') && display !== 'contents') return node;
+		}
+		return this;
+	};
+	const noSpellcheck = (el) => !!el.closest('[spellcheck="false"], [translate="no"], .notranslate');
+
+	const blocks = new Map();
+	const walker = document.createTreeWalker(this, NodeFilter.SHOW_TEXT, {
+		acceptNode: (node) => {
+			const parent = node.parentElement;
+			if (!parent || !node.textContent.trim()) return NodeFilter.FILTER_REJECT;
+			for (let el = parent; el && el !== this.parentElement; el = el.parentElement) {
+				if (skip.has(el.tagName) || el.getAttribute('aria-hidden') === 'true') return NodeFilter.FILTER_REJECT;
+			}
+			return isVisible(parent) ? NodeFilter.FILTER_ACCEPT : NodeFilter.FILTER_REJECT;
+		},
+	});
+	for (let node = walker.nextNode(); node && blocks.size <= limit; node = walker.nextNode()) {
+		const block = blockOf(node.parentElement);
+		if (!blocks.has(block)) blocks.set(block, { el: block, text: '' });
+		blocks.get(block).text += node.textContent;
+	}
+
+	const result = [];
+	for (const { el, text } of blocks.values()) {
+		if (result.length >= limit) break;
+		result.push({ selector: selectorFor(el), text: text.trim().slice(0, 5000), no_spellcheck: noSpellcheck(el) });
+	}
+	const attributes = this.querySelectorAll('[alt], [title], [placeholder], [aria-label]');
+	for (const el of attributes) {
+		if (result.length >= limit) break;
+		for (const attribute of ['alt', 'title', 'placeholder', 'aria-label']) {
+			const value = (el.getAttribute(attribute) || '').trim();
+			if (value) result.push({ selector: selectorFor(el), text: value.slice(0, 1000), attribute: attribute, no_spellcheck: noSpellcheck(el) });
+		}
+	}
+	return result.slice(0, limit);
+}`
+
+// TextBlocks returns the visible text inside the element matching
+// selector, one block per paragraph-like element in document order,
+// followed by its alt, title, placeholder and aria-label attributes
+func (m *Manager) TextBlocks(pageID, selector string, timeout time.Duration) ([]TextBlock, error) {
+	var blocks []TextBlock
+	err := m.withElement(context.Background(), pageID, nil, selector, timeout, "text_blocks", func(el *rod.Element) error {
+		result, err := el.Eval(textBlocksScript, MaxTextBlocks)
+		if err != nil {
+			return fmt.Errorf("failed to read text in %s: %w", selector, err)
+		}
+		return json.Unmarshal([]byte(result.Value.JSON("", "")), &blocks)
+	})
+	return blocks, err
+}
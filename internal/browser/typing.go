@@ -0,0 +1,95 @@
+package browser
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod/lib/input"
+)
+
+// maxTypingDuration bounds a single TypeIntoElement call
+const maxTypingDuration = 30 * time.Second
+
+// typeableKey reports whether r has a keyboard mapping, so it can be sent as
+// real key events rather than inserted as text
+func typeableKey(r rune) (key input.Key, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	if r == '\n' {
+		return input.Enter, true
+	}
+	key = input.Key(r)
+	key.Info()
+	return key, true
+}
+
+// TypeIntoElement focuses the element matching selector and enters text one
+// character at a time with real key events, so masked and framework-controlled
+// inputs see the same keydown/input/keyup sequence as a person typing.
+// Characters without a keyboard mapping are inserted as text. When clear is
+// set the existing value is selected and deleted first.
+func (m *Manager) TypeIntoElement(pageID, selector, text string, clear bool, delay time.Duration) error {
+	start := time.Now()
+
+	sel, err := ParseSelector(selector)
+	if err != nil {
+		return err
+	}
+	page, err := m.GetPage(pageID)
+	if err != nil {
+		return err
+	}
+
+	release, err := m.acquirePageWithTimeout(pageID)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), maxTypingDuration)
+	defer cancel()
+	p := page.Context(ctx)
+
+	el, err := sel.Find(p)
+	if err != nil {
+		return err
+	}
+
+	if clear {
+		if err := el.SelectAllText(); err != nil {
+			return fmt.Errorf("failed to select existing text: %w", err)
+		}
+		if err := el.Type(input.Backspace); err != nil {
+			return fmt.Errorf("failed to clear existing text: %w", err)
+		}
+	}
+
+	if err := el.Focus(); err != nil {
+		return fmt.Errorf("failed to focus element: %w", err)
+	}
+
+	for _, r := range text {
+		if key, ok := typeableKey(r); ok {
+			err = p.Keyboard.Type(key)
+		} else {
+			err = p.InsertText(string(r))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to type into %s: %w", selector, err)
+		}
+		if delay > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("typing into %s timed out", selector)
+			case <-time.After(delay):
+			}
+		}
+	}
+
+	m.logger.LogBrowserAction("typed_text", pageID, time.Since(start).Milliseconds())
+	return nil
+}
@@ -0,0 +1,16 @@
+package browser
+
+import "testing"
+
+func TestTypeableKey(t *testing.T) {
+	for _, r := range "aZ9 @\n" {
+		if _, ok := typeableKey(r); !ok {
+			t.Errorf("Expected %q to have a key mapping", r)
+		}
+	}
+	for _, r := range "é中🙂" {
+		if _, ok := typeableKey(r); ok {
+			t.Errorf("Expected %q to be inserted as text", r)
+		}
+	}
+}
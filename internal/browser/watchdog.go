@@ -0,0 +1,390 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"go.uber.org/zap"
+)
+
+// Recovery actions the page watchdog takes, mildest first
+const (
+	PageRecoveryReload = "reloaded"
+	PageRecoveryReopen = "reopened"
+	PageRecoveryClose  = "closed"
+)
+
+const (
+	// pageHungPings is how many pings in a row a page must miss on the
+	// regular checks before it is treated as hung
+	pageHungPings = 2
+	// pageRecoveryMemory is how long a finished recovery is reported in the
+	// errors of operations it cut short
+	pageRecoveryMemory = 5 * time.Minute
+)
+
+// PageRecovery describes what the watchdog did about a page that stopped
+// responding
+type PageRecovery struct {
+	PageID string    `json:"page_id"`
+	Reason string    `json:"reason"`
+	Action string    `json:"action"`
+	Time   time.Time `json:"time"`
+	// Error is why the page could not be kept, when it was closed
+	Error string `json:"error,omitempty"`
+}
+
+// String explains the recovery in words fit for a tool error
+func (r PageRecovery) String() string {
+	switch r.Action {
+	case PageRecoveryReload:
+		return fmt.Sprintf("page %s stopped responding (%s) and was reloaded; its unsaved state is gone, so retry from the current page", r.PageID, r.Reason)
+	case PageRecoveryReopen:
+		return fmt.Sprintf("page %s stopped responding (%s) and was reopened in a new tab at its last URL; its per-page settings such as emulation and extra headers were reset", r.PageID, r.Reason)
+	default:
+		return fmt.Sprintf("page %s stopped responding (%s) and was closed because it could not be recovered: %s; open a new page", r.PageID, r.Reason, r.Error)
+	}
+}
+
+// pageWatchdog tracks pages that miss pings, pages being recovered and the
+// last recovery of each page
+type pageWatchdog struct {
+	mutex      sync.Mutex
+	missed     map[string]int
+	recovering map[string]time.Time
+	recoveries map[string]PageRecovery
+	// Pages showing a JavaScript dialog, which blocks evaluation without
+	// the page being hung
+	dialogs map[string]bool
+}
+
+// initLocked creates the maps on first write, so a zero watchdog works.
+// The caller holds w.mutex.
+func (w *pageWatchdog) initLocked() {
+	if w.missed == nil {
+		w.missed = make(map[string]int)
+		w.recovering = make(map[string]time.Time)
+		w.recoveries = make(map[string]PageRecovery)
+		w.dialogs = make(map[string]bool)
+	}
+}
+
+// miss counts a missed ping and reports whether the page now counts as hung
+func (w *pageWatchdog) miss(pageID string, threshold int) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.initLocked()
+	w.missed[pageID]++
+	return w.missed[pageID] >= threshold
+}
+
+func (w *pageWatchdog) answered(pageID string) {
+	w.mutex.Lock()
+	delete(w.missed, pageID)
+	w.mutex.Unlock()
+}
+
+// begin marks a page as being recovered, reporting false if it already is
+func (w *pageWatchdog) begin(pageID string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.initLocked()
+	if _, busy := w.recovering[pageID]; busy {
+		return false
+	}
+	w.recovering[pageID] = time.Now()
+	delete(w.missed, pageID)
+	return true
+}
+
+func (w *pageWatchdog) end(recovery PageRecovery) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.initLocked()
+	delete(w.recovering, recovery.PageID)
+	delete(w.dialogs, recovery.PageID)
+	w.recoveries[recovery.PageID] = recovery
+	// Recoveries are reported for a while, then forgotten
+	for id, r := range w.recoveries {
+		if time.Since(r.Time) > pageRecoveryMemory {
+			delete(w.recoveries, id)
+		}
+	}
+}
+
+// status reports whether the page is being recovered, and its last
+// recovery if it finished at or after since
+func (w *pageWatchdog) status(pageID string, since time.Time) (recovering bool, last *PageRecovery) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	_, recovering = w.recovering[pageID]
+	if r, ok := w.recoveries[pageID]; ok && !r.Time.Before(since) && time.Since(r.Time) <= pageRecoveryMemory {
+		last = &r
+	}
+	return recovering, last
+}
+
+func (w *pageWatchdog) setDialog(pageID string, open bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.initLocked()
+	if open {
+		w.dialogs[pageID] = true
+	} else {
+		delete(w.dialogs, pageID)
+	}
+}
+
+func (w *pageWatchdog) dialogOpen(pageID string) bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.dialogs[pageID]
+}
+
+// forget drops a closed page's ping count and dialog state, keeping its
+// recovery for the errors that report it
+func (w *pageWatchdog) forget(pageID string) {
+	w.mutex.Lock()
+	delete(w.missed, pageID)
+	delete(w.dialogs, pageID)
+	w.mutex.Unlock()
+}
+
+// watchdogEventHandlers follow a page's JavaScript dialogs, which block
+// pings while they are open
+func (m *Manager) watchdogEventHandlers(pageID string) []interface{} {
+	return []interface{}{
+		func(e *proto.PageJavascriptDialogOpening) {
+			m.watchdog.setDialog(pageID, true)
+		},
+		func(e *proto.PageJavascriptDialogClosed) {
+			m.watchdog.setDialog(pageID, false)
+		},
+	}
+}
+
+// pingPage evaluates a trivial expression in the page, which fails once its
+// renderer or CDP session stops responding
+func (m *Manager) pingPage(page *rod.Page, timeout time.Duration) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("ping panicked: %v", r)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	_, err = proto.RuntimeEvaluate{Expression: "1", ReturnByValue: true}.Call(page.Context(ctx))
+	return err
+}
+
+// checkPages pings every open page at once and recovers those that missed
+// too many pings. It runs after the browser itself passed its health check,
+// so a page missing pings is hung on its own.
+func (m *Manager) checkPages() {
+	m.mutex.RLock()
+	pages := make(map[string]*rod.Page, len(m.pages))
+	for id, page := range m.pages {
+		pages[id] = page
+	}
+	m.mutex.RUnlock()
+
+	timeout := m.health().PagePingTimeout
+	var wg sync.WaitGroup
+	for pageID, page := range pages {
+		if recovering, _ := m.watchdog.status(pageID, time.Now()); recovering || m.watchdog.dialogOpen(pageID) {
+			continue
+		}
+		wg.Add(1)
+		go func(pageID string, page *rod.Page) {
+			defer wg.Done()
+			err := m.pingPage(page, timeout)
+			if err == nil {
+				m.watchdog.answered(pageID)
+				return
+			}
+			m.logger.WithComponent("browser").Warn("Page missed a ping",
+				zap.String("page_id", pageID),
+				zap.Error(err))
+			if m.watchdog.miss(pageID, pageHungPings) {
+				go m.recoverPage(pageID, page, fmt.Sprintf("no answer within %s", timeout))
+			}
+		}(pageID, page)
+	}
+	wg.Wait()
+}
+
+// suspectPage pings a page whose operation just timed out, and recovers it
+// straight away if it does not answer either
+func (m *Manager) suspectPage(pageID string) {
+	page, err := m.GetPage(pageID)
+	if err != nil || m.watchdog.dialogOpen(pageID) {
+		return
+	}
+	timeout := m.health().PagePingTimeout
+	go func() {
+		if err := m.pingPage(page, timeout); err != nil {
+			m.recoverPage(pageID, page, fmt.Sprintf("an operation timed out and a ping got no answer within %s", timeout))
+		}
+	}()
+}
+
+// pageFailure explains an operation's error on pageID with the recovery
+// the watchdog made while it ran, if any. An operation that timed out
+// gets its page checked, so the calls after it do not wait out the same
+// timeout.
+func (m *Manager) pageFailure(pageID string, start time.Time, err error) error {
+	if err == nil {
+		return nil
+	}
+	if recovering, last := m.watchdog.status(pageID, start); last != nil {
+		return fmt.Errorf("%w; %s", err, last)
+	} else if recovering {
+		return fmt.Errorf("%w; page %s stopped responding and is being recovered", err, pageID)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		m.suspectPage(pageID)
+	}
+	return err
+}
+
+// recoverPage tries to bring a hung page back: reload it, else replace it
+// with a new tab at its last URL under the same page ID, else close it
+func (m *Manager) recoverPage(pageID string, page *rod.Page, reason string) {
+	if !m.watchdog.begin(pageID) {
+		return
+	}
+	recovery := PageRecovery{PageID: pageID, Reason: reason}
+	defer func() {
+		if r := recover(); r != nil {
+			recovery.Action = PageRecoveryClose
+			recovery.Error = fmt.Sprintf("recovery panicked: %v", r)
+			m.dropHungPage(pageID, page)
+		}
+		recovery.Time = time.Now()
+		m.watchdog.end(recovery)
+		m.logger.WithComponent("browser").Warn("Recovered hung page",
+			zap.String("page_id", pageID),
+			zap.String("reason", reason),
+			zap.String("action", recovery.Action),
+			zap.String("error", recovery.Error))
+	}()
+
+	m.logger.WithComponent("browser").Warn("Page stopped responding, recovering",
+		zap.String("page_id", pageID),
+		zap.String("reason", reason))
+
+	timeout := m.health().PagePingTimeout
+	if err := m.reloadHungPage(page, timeout); err == nil {
+		recovery.Action = PageRecoveryReload
+		return
+	}
+	err := m.reopenHungPage(pageID, page, timeout)
+	if err == nil {
+		recovery.Action = PageRecoveryReopen
+		return
+	}
+	recovery.Action = PageRecoveryClose
+	recovery.Error = err.Error()
+	m.dropHungPage(pageID, page)
+}
+
+// reloadHungPage reloads the page and checks it answers afterwards
+func (m *Manager) reloadHungPage(page *rod.Page, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	err := proto.PageReload{}.Call(page.Context(ctx))
+	cancel()
+	if err != nil {
+		return fmt.Errorf("reload failed: %w", err)
+	}
+	// The reloaded document may take a moment before it can answer
+	return m.pingPage(page, 2*timeout)
+}
+
+// reopenHungPage opens a new tab at the page's last URL, closes the hung
+// one and keeps the page ID pointing at the new tab. Per-page settings
+// belonged to the old tab and are dropped.
+func (m *Manager) reopenHungPage(pageID string, page *rod.Page, timeout time.Duration) error {
+	m.mutex.RLock()
+	browser := m.browser
+	url := m.pageURLs[pageID]
+	m.mutex.RUnlock()
+	if browser == nil {
+		return fmt.Errorf("browser not running")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	fresh, err := browser.Context(ctx).Page(proto.TargetCreateTarget{URL: url})
+	if err != nil {
+		return fmt.Errorf("failed to open a replacement tab: %w", err)
+	}
+	if err := m.pingPage(fresh, 2*timeout); err != nil {
+		proto.TargetCloseTarget{TargetID: fresh.TargetID}.Call(browser.Context(ctx))
+		return fmt.Errorf("replacement tab did not answer either: %w", err)
+	}
+
+	m.mutex.Lock()
+	_, exists := m.pages[pageID]
+	if exists {
+		m.pages[pageID] = fresh
+	}
+	m.mutex.Unlock()
+	if !exists {
+		// Closed while we were busy
+		proto.TargetCloseTarget{TargetID: fresh.TargetID}.Call(browser.Context(ctx))
+		return fmt.Errorf("page was closed during recovery")
+	}
+
+	m.forgetPageSettings(pageID)
+	m.events.mutex.Lock()
+	stopWatcher := m.events.watchers[pageID]
+	m.events.mutex.Unlock()
+	if stopWatcher != nil {
+		stopWatcher()
+	}
+	m.watchPageEvents(pageID, fresh)
+
+	// The hung tab is closed by the browser, which does not need its renderer
+	proto.TargetCloseTarget{TargetID: page.TargetID}.Call(browser.Context(ctx))
+	return nil
+}
+
+// dropHungPage forgets a page that could not be recovered and closes its
+// tab without waiting on the page itself
+func (m *Manager) dropHungPage(pageID string, page *rod.Page) {
+	m.mutex.Lock()
+	browser := m.browser
+	current, exists := m.pages[pageID]
+	if exists && current == page {
+		delete(m.pages, pageID)
+		delete(m.pageURLs, pageID)
+	}
+	m.mutex.Unlock()
+	if !exists || current != page {
+		return
+	}
+
+	m.stopPageEvents(pageID)
+	m.forgetPageSettings(pageID)
+	m.watchdog.forget(pageID)
+	m.pageQueue.forget(pageID)
+	if browser != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		proto.TargetCloseTarget{TargetID: page.TargetID}.Call(browser.Context(ctx))
+	}
+}
+
+// forgetPageSettings drops the per-page settings tied to a page's tab
+func (m *Manager) forgetPageSettings(pageID string) {
+	m.stopResourceBlocking(pageID, nil, false)
+	m.forgetFingerprint(pageID)
+	m.forgetDeviceEmulation(pageID)
+	m.forgetLocationEmulation(pageID)
+	m.forgetRequestOverrides(pageID)
+}
@@ -0,0 +1,104 @@
+package browser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod"
+	"rodmcp/internal/logger"
+)
+
+func TestPageWatchdogBookkeeping(t *testing.T) {
+	var w pageWatchdog
+
+	if w.miss("page_1", 2) {
+		t.Error("One missed ping must not count as hung")
+	}
+	if !w.miss("page_1", 2) {
+		t.Error("Two missed pings in a row should count as hung")
+	}
+	w.answered("page_1")
+	if w.miss("page_1", 2) {
+		t.Error("An answered ping should reset the count")
+	}
+
+	if !w.begin("page_1") {
+		t.Fatal("begin should start a recovery")
+	}
+	if w.begin("page_1") {
+		t.Error("A page already being recovered must not be recovered twice")
+	}
+	start := time.Now()
+	if recovering, last := w.status("page_1", start); !recovering || last != nil {
+		t.Errorf("status during recovery = %v, %v", recovering, last)
+	}
+	w.end(PageRecovery{PageID: "page_1", Reason: "no answer within 5s", Action: PageRecoveryReload, Time: time.Now()})
+	if recovering, last := w.status("page_1", start); recovering || last == nil || last.Action != PageRecoveryReload {
+		t.Errorf("status after recovery = %v, %v", recovering, last)
+	}
+	if _, last := w.status("page_1", time.Now().Add(time.Second)); last != nil {
+		t.Error("A recovery before the operation started must not be reported")
+	}
+
+	w.setDialog("page_2", true)
+	if !w.dialogOpen("page_2") {
+		t.Error("Expected the dialog to be tracked")
+	}
+	w.setDialog("page_2", false)
+	if w.dialogOpen("page_2") {
+		t.Error("Expected the closed dialog to be forgotten")
+	}
+}
+
+func TestPageRecoveryString(t *testing.T) {
+	for action, want := range map[string]string{
+		PageRecoveryReload: "was reloaded",
+		PageRecoveryReopen: "reopened in a new tab",
+		PageRecoveryClose:  "was closed because it could not be recovered: tab gone",
+	} {
+		r := PageRecovery{PageID: "page_1", Reason: "no answer within 5s", Action: action, Error: "tab gone"}
+		if got := r.String(); !strings.Contains(got, want) || !strings.Contains(got, "no answer within 5s") {
+			t.Errorf("%s: %q", action, got)
+		}
+	}
+}
+
+func TestRecoveryReportedInPageErrors(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	manager := NewManager(log, Config{Headless: true})
+	manager.pages["page_1"] = &rod.Page{}
+
+	// Calls fail fast while the page is being recovered
+	manager.watchdog.begin("page_1")
+	if _, err := manager.GetPage("page_1"); err == nil || !strings.Contains(err.Error(), "being recovered") {
+		t.Errorf("GetPage during recovery = %v", err)
+	}
+
+	start := time.Now()
+	manager.watchdog.end(PageRecovery{PageID: "page_1", Reason: "no answer", Action: PageRecoveryReopen, Time: time.Now()})
+	if _, err := manager.GetPage("page_1"); err != nil {
+		t.Errorf("GetPage after reopening = %v", err)
+	}
+	err := manager.pageFailure("page_1", start, fmt.Errorf("failed to execute script: %w", context.Canceled))
+	if err == nil || !strings.Contains(err.Error(), "reopened") || !errors.Is(err, context.Canceled) {
+		t.Errorf("pageFailure = %v", err)
+	}
+
+	// A page that had to be closed says so instead of just not existing
+	delete(manager.pages, "page_1")
+	manager.watchdog.end(PageRecovery{PageID: "page_1", Reason: "no answer", Action: PageRecoveryClose, Error: "tab gone", Time: time.Now()})
+	if _, err := manager.GetPage("page_1"); err == nil || !strings.Contains(err.Error(), "could not be recovered") {
+		t.Errorf("GetPage after closing = %v", err)
+	}
+	if _, err := manager.GetPage("page_2"); err == nil || strings.Contains(err.Error(), "recovered") {
+		t.Errorf("GetPage of an unknown page = %v", err)
+	}
+
+	if err := manager.pageFailure("page_3", start, nil); err != nil {
+		t.Errorf("pageFailure(nil) = %v", err)
+	}
+}
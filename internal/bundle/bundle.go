@@ -0,0 +1,200 @@
+// Package bundle generates the container build files for running RodMCP in
+// Docker or Kubernetes with a pinned Chromium build.
+package bundle
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+// Options controls the generated image
+type Options struct {
+	// ChromiumRevision is the Chromium snapshot baked into the image
+	ChromiumRevision int
+	Port             int
+	Image            string
+	// GracePeriod is the Kubernetes termination grace period in seconds
+	GracePeriod int
+}
+
+// DefaultOptions pins Chromium to the revision this build of Rod was tested with
+func DefaultOptions() Options {
+	return Options{
+		ChromiumRevision: launcher.RevisionDefault,
+		Port:             8080,
+		Image:            "rodmcp:latest",
+		GracePeriod:      30,
+	}
+}
+
+// ShutdownGrace is how long the server should spend draining before the
+// orchestrator's grace period ends, leaving headroom for the browser to exit
+func (o Options) ShutdownGrace() int {
+	if o.GracePeriod > 10 {
+		return o.GracePeriod - 5
+	}
+	return o.GracePeriod
+}
+
+var dockerfileTemplate = template.Must(template.New("Dockerfile").Parse(`# Generated by 'rodmcp bundle' - Chromium revision {{.ChromiumRevision}}
+FROM golang:1.24-bookworm AS build
+WORKDIR /src
+COPY go.mod go.sum ./
+RUN go mod download
+COPY . .
+ARG VERSION=dev
+RUN CGO_ENABLED=0 go build -trimpath -ldflags "-s -w -X main.Version=${VERSION}" -o /out/rodmcp ./cmd/server
+
+FROM debian:bookworm-slim AS chromium
+ARG CHROMIUM_REVISION={{.ChromiumRevision}}
+RUN apt-get update \
+    && apt-get install -y --no-install-recommends ca-certificates curl unzip \
+    && rm -rf /var/lib/apt/lists/*
+RUN curl -fsSL -o /tmp/chrome.zip \
+        "https://storage.googleapis.com/chromium-browser-snapshots/Linux_x64/${CHROMIUM_REVISION}/chrome-linux.zip" \
+    && unzip -q /tmp/chrome.zip -d /opt \
+    && rm /tmp/chrome.zip
+
+FROM debian:bookworm-slim
+RUN apt-get update \
+    && apt-get install -y --no-install-recommends \
+        ca-certificates tini fonts-liberation fonts-noto-color-emoji \
+        libasound2 libatk-bridge2.0-0 libatk1.0-0 libcairo2 libcups2 libdbus-1-3 \
+        libdrm2 libgbm1 libglib2.0-0 libnspr4 libnss3 libpango-1.0-0 \
+        libx11-6 libxcb1 libxcomposite1 libxdamage1 libxext6 libxfixes3 \
+        libxkbcommon0 libxrandr2 \
+    && rm -rf /var/lib/apt/lists/* \
+    && useradd --create-home --uid 10001 rodmcp
+COPY --from=chromium /opt/chrome-linux /opt/chromium
+COPY --from=build /out/rodmcp /usr/local/bin/rodmcp
+ENV RODMCP_BROWSER_PATH=/opt/chromium/chrome
+USER rodmcp
+WORKDIR /home/rodmcp/work
+EXPOSE {{.Port}}
+STOPSIGNAL SIGTERM
+ENTRYPOINT ["/usr/bin/tini", "--", "rodmcp"]
+CMD ["http", "--listen", "0.0.0.0", "--port", "{{.Port}}", "--container", "--shutdown-grace", "{{.ShutdownGrace}}s", "--log-dir", "/tmp/rodmcp-logs"]
+`))
+
+var dockerignoreTemplate = template.Must(template.New(".dockerignore").Parse(`.git
+logs
+*.log
+*.png
+test_files
+`))
+
+var kubernetesTemplate = template.Must(template.New("kubernetes.yaml").Parse(`# Generated by 'rodmcp bundle'
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: rodmcp
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app: rodmcp
+  template:
+    metadata:
+      labels:
+        app: rodmcp
+    spec:
+      terminationGracePeriodSeconds: {{.GracePeriod}}
+      containers:
+        - name: rodmcp
+          image: {{.Image}}
+          ports:
+            - containerPort: {{.Port}}
+          livenessProbe:
+            httpGet:
+              path: /livez
+              port: {{.Port}}
+            periodSeconds: 10
+          readinessProbe:
+            httpGet:
+              path: /readyz
+              port: {{.Port}}
+            initialDelaySeconds: 5
+            periodSeconds: 5
+          resources:
+            requests:
+              cpu: 500m
+              memory: 512Mi
+            limits:
+              memory: 2Gi
+          volumeMounts:
+            - name: dshm
+              mountPath: /dev/shm
+      volumes:
+        - name: dshm
+          emptyDir:
+            medium: Memory
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: rodmcp
+spec:
+  selector:
+    app: rodmcp
+  ports:
+    - port: {{.Port}}
+      targetPort: {{.Port}}
+`))
+
+// Render returns the generated files keyed by file name
+func Render(opts Options) (map[string]string, error) {
+	if opts.ChromiumRevision <= 0 {
+		return nil, fmt.Errorf("chromium revision must be positive")
+	}
+	if opts.Port <= 0 || opts.Port > 65535 {
+		return nil, fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	files := make(map[string]string)
+	for _, tmpl := range []*template.Template{dockerfileTemplate, dockerignoreTemplate, kubernetesTemplate} {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, opts); err != nil {
+			return nil, fmt.Errorf("failed to render %s: %w", tmpl.Name(), err)
+		}
+		files[tmpl.Name()] = buf.String()
+	}
+	return files, nil
+}
+
+// Write renders the bundle files into dir. Existing files are only replaced
+// when overwrite is set. It returns the paths written.
+func Write(dir string, opts Options, overwrite bool) ([]string, error) {
+	files, err := Render(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	names := []string{"Dockerfile", ".dockerignore", "kubernetes.yaml"}
+	if !overwrite {
+		for _, name := range names {
+			path := filepath.Join(dir, name)
+			if _, err := os.Stat(path); err == nil {
+				return nil, fmt.Errorf("%s already exists (use --force to overwrite)", path)
+			}
+		}
+	}
+
+	var written []string
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(files[name]), 0644); err != nil {
+			return written, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
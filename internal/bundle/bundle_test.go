@@ -0,0 +1,55 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderPinsChromium(t *testing.T) {
+	opts := DefaultOptions()
+	opts.ChromiumRevision = 1234567
+	opts.Port = 9090
+	opts.GracePeriod = 60
+
+	files, err := Render(opts)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	dockerfile := files["Dockerfile"]
+	for _, want := range []string{"CHROMIUM_REVISION=1234567", "EXPOSE 9090", `"--shutdown-grace", "55s"`, "RODMCP_BROWSER_PATH"} {
+		if !strings.Contains(dockerfile, want) {
+			t.Errorf("Expected Dockerfile to contain %q", want)
+		}
+	}
+	manifest := files["kubernetes.yaml"]
+	for _, want := range []string{"terminationGracePeriodSeconds: 60", "path: /readyz", "path: /livez"} {
+		if !strings.Contains(manifest, want) {
+			t.Errorf("Expected manifest to contain %q", want)
+		}
+	}
+
+	opts.Port = 0
+	if _, err := Render(opts); err == nil {
+		t.Error("Expected invalid port to be rejected")
+	}
+}
+
+func TestWriteRefusesOverwrite(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "kubernetes.yaml"), []byte("mine"), 0644)
+
+	if _, err := Write(dir, DefaultOptions(), false); err == nil {
+		t.Fatal("Expected existing files to block writing")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "Dockerfile")); err == nil {
+		t.Error("Expected no files written when one already exists")
+	}
+
+	written, err := Write(dir, DefaultOptions(), true)
+	if err != nil || len(written) != 3 {
+		t.Fatalf("Expected forced write of 3 files, got %v %v", written, err)
+	}
+}
@@ -0,0 +1,182 @@
+// Package clientconfig writes RodMCP entries into the MCP configuration
+// files used by desktop clients such as Claude Desktop, Cursor and Windsurf.
+package clientconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Client identifies a supported MCP client
+type Client string
+
+const (
+	ClientClaude   Client = "claude"
+	ClientCursor   Client = "cursor"
+	ClientWindsurf Client = "windsurf"
+)
+
+// SupportedClients lists the clients install-client can configure
+var SupportedClients = []Client{ClientClaude, ClientCursor, ClientWindsurf}
+
+// ParseClient validates a client name
+func ParseClient(name string) (Client, error) {
+	normalized := Client(strings.ToLower(strings.TrimSpace(name)))
+	if normalized == "claude-desktop" {
+		normalized = ClientClaude
+	}
+	for _, c := range SupportedClients {
+		if c == normalized {
+			return c, nil
+		}
+	}
+	names := make([]string, len(SupportedClients))
+	for i, c := range SupportedClients {
+		names[i] = string(c)
+	}
+	return "", fmt.Errorf("unknown client %q (supported: %s)", name, strings.Join(names, ", "))
+}
+
+// Environment holds the platform details used to locate config files
+type Environment struct {
+	GOOS    string
+	Home    string
+	AppData string
+}
+
+// ConfigPath returns the MCP config file location for a client on the given platform
+func ConfigPath(client Client, env Environment) (string, error) {
+	if env.Home == "" {
+		return "", fmt.Errorf("home directory is unknown")
+	}
+
+	switch client {
+	case ClientClaude:
+		switch env.GOOS {
+		case "darwin":
+			return filepath.Join(env.Home, "Library", "Application Support", "Claude", "claude_desktop_config.json"), nil
+		case "windows":
+			appData := env.AppData
+			if appData == "" {
+				appData = filepath.Join(env.Home, "AppData", "Roaming")
+			}
+			return filepath.Join(appData, "Claude", "claude_desktop_config.json"), nil
+		default:
+			return filepath.Join(env.Home, ".config", "Claude", "claude_desktop_config.json"), nil
+		}
+	case ClientCursor:
+		return filepath.Join(env.Home, ".cursor", "mcp.json"), nil
+	case ClientWindsurf:
+		return filepath.Join(env.Home, ".codeium", "windsurf", "mcp_config.json"), nil
+	}
+	return "", fmt.Errorf("unknown client %q", client)
+}
+
+// ServerEntry is the mcpServers entry written for RodMCP
+type ServerEntry struct {
+	Command string            `json:"command"`
+	Args    []string          `json:"args"`
+	Env     map[string]string `json:"env,omitempty"`
+}
+
+// InstallResult describes what Install changed
+type InstallResult struct {
+	Path       string `json:"path"`
+	BackupPath string `json:"backup_path,omitempty"`
+	Created    bool   `json:"created"`
+	Replaced   bool   `json:"replaced"`
+	Content    string `json:"content"`
+}
+
+// Install adds or replaces the named server entry in the config file at path,
+// preserving every other key. An existing file is backed up before it is
+// rewritten. With dryRun set nothing is written.
+func Install(path, name string, entry ServerEntry, dryRun bool) (*InstallResult, error) {
+	if name == "" {
+		return nil, fmt.Errorf("server name must not be empty")
+	}
+	if entry.Command == "" {
+		return nil, fmt.Errorf("server command must not be empty")
+	}
+
+	result := &InstallResult{Path: path}
+	config := make(map[string]interface{})
+
+	existing, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if strings.TrimSpace(string(existing)) != "" {
+			if err := json.Unmarshal(existing, &config); err != nil {
+				return nil, fmt.Errorf("existing config %s is not valid JSON (fix or move it first): %w", path, err)
+			}
+		}
+	case os.IsNotExist(err):
+		result.Created = true
+	default:
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	servers, ok := config["mcpServers"].(map[string]interface{})
+	if !ok {
+		if _, present := config["mcpServers"]; present {
+			return nil, fmt.Errorf("mcpServers in %s is not an object", path)
+		}
+		servers = make(map[string]interface{})
+	}
+	_, result.Replaced = servers[name]
+
+	// Round-trip through JSON so the entry is stored as a plain map like its neighbours
+	encodedEntry, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	var entryMap map[string]interface{}
+	json.Unmarshal(encodedEntry, &entryMap)
+	servers[name] = entryMap
+	config["mcpServers"] = servers
+
+	content, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	result.Content = string(content) + "\n"
+
+	if dryRun {
+		return result, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	if !result.Created {
+		backup := fmt.Sprintf("%s.%s.bak", path, time.Now().Format("20060102-150405"))
+		if err := os.WriteFile(backup, existing, 0600); err != nil {
+			return nil, fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		result.BackupPath = backup
+	}
+
+	// Write to a temp file and rename so a crash never leaves a truncated config
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".rodmcp-config-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	if _, err := tmp.WriteString(result.Content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to write config: %w", err)
+	}
+	tmp.Close()
+	os.Chmod(tmp.Name(), 0644)
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	return result, nil
+}
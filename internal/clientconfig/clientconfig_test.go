@@ -0,0 +1,93 @@
+package clientconfig
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigPath(t *testing.T) {
+	cases := []struct {
+		client Client
+		env    Environment
+		want   string
+	}{
+		{ClientClaude, Environment{GOOS: "darwin", Home: "/Users/a"}, "/Users/a/Library/Application Support/Claude/claude_desktop_config.json"},
+		{ClientClaude, Environment{GOOS: "linux", Home: "/home/a"}, "/home/a/.config/Claude/claude_desktop_config.json"},
+		{ClientClaude, Environment{GOOS: "windows", Home: "/h", AppData: "/appdata"}, "/appdata/Claude/claude_desktop_config.json"},
+		{ClientCursor, Environment{GOOS: "linux", Home: "/home/a"}, "/home/a/.cursor/mcp.json"},
+		{ClientWindsurf, Environment{GOOS: "darwin", Home: "/Users/a"}, "/Users/a/.codeium/windsurf/mcp_config.json"},
+	}
+	for _, c := range cases {
+		got, err := ConfigPath(c.client, c.env)
+		if err != nil || got != filepath.FromSlash(c.want) {
+			t.Errorf("ConfigPath(%s, %+v) = %q, %v; want %q", c.client, c.env, got, err, c.want)
+		}
+	}
+
+	if _, err := ParseClient("vscode"); err == nil {
+		t.Error("Expected unknown client to be rejected")
+	}
+	if c, err := ParseClient("Claude"); err != nil || c != ClientClaude {
+		t.Errorf("Expected case-insensitive client parsing, got %v %v", c, err)
+	}
+}
+
+func TestInstallPreservesExistingConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "config.json")
+	entry := ServerEntry{Command: "/usr/local/bin/rodmcp", Args: []string{"--headless"}}
+
+	// Dry run writes nothing
+	result, err := Install(path, "rodmcp", entry, true)
+	if err != nil || !result.Created || !strings.Contains(result.Content, "/usr/local/bin/rodmcp") {
+		t.Fatalf("Unexpected dry run result: %+v %v", result, err)
+	}
+	if _, err := os.Stat(path); err == nil {
+		t.Fatal("Expected dry run not to write the config")
+	}
+
+	os.MkdirAll(filepath.Dir(path), 0755)
+	os.WriteFile(path, []byte(`{"theme":"dark","mcpServers":{"other":{"command":"other-server"}}}`), 0644)
+
+	result, err = Install(path, "rodmcp", entry, false)
+	if err != nil {
+		t.Fatalf("Install failed: %v", err)
+	}
+	if result.Created || result.Replaced || result.BackupPath == "" {
+		t.Errorf("Unexpected install result: %+v", result)
+	}
+	if _, err := os.Stat(result.BackupPath); err != nil {
+		t.Errorf("Expected backup file: %v", err)
+	}
+
+	var config map[string]interface{}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatalf("Written config is not JSON: %v", err)
+	}
+	servers := config["mcpServers"].(map[string]interface{})
+	if config["theme"] != "dark" || servers["other"] == nil || servers["rodmcp"] == nil {
+		t.Errorf("Expected existing keys preserved and rodmcp added, got %v", config)
+	}
+
+	result, err = Install(path, "rodmcp", entry, false)
+	if err != nil || !result.Replaced {
+		t.Errorf("Expected second install to replace the entry: %+v %v", result, err)
+	}
+}
+
+func TestInstallRejectsInvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	os.WriteFile(path, []byte(`{not json`), 0644)
+
+	if _, err := Install(path, "rodmcp", ServerEntry{Command: "rodmcp"}, false); err == nil {
+		t.Error("Expected invalid existing JSON to be rejected")
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "{not json" {
+		t.Error("Expected invalid config to be left untouched")
+	}
+}
@@ -103,9 +103,12 @@ type ConnectionManager struct {
 	lastActivity  time.Time
 	activityMutex sync.RWMutex
 	
-	// Persistent stdin scanner for MCP protocol
-	stdinScanner  *bufio.Scanner
-	scannerMutex  sync.Mutex
+	// A single reader goroutine owns stdin so a read that outlives its
+	// timeout can never race a later read and swallow a message
+	input      io.Reader
+	output     io.Writer
+	lines      chan readResult
+	readerOnce sync.Once
 	
 	// Connection stats
 	connectionAttempts int64
@@ -116,6 +119,12 @@ type ConnectionManager struct {
 	config Config
 }
 
+// readResult is one line (or the terminal error) from the input reader
+type readResult struct {
+	line string
+	err  error
+}
+
 // Config defines configuration options for the ConnectionManager
 type Config struct {
 	// Buffer sizes
@@ -166,20 +175,26 @@ func NewConnectionManager(log *logger.Logger, config Config) *ConnectionManager
 		cancel:       cancel,
 		lastActivity: time.Now(),
 		config:       config,
+		input:        os.Stdin,
+		output:       os.Stdout,
+		lines:        make(chan readResult, 16),
 	}
 }
 
+// SetIO replaces stdin/stdout as the message streams. It must be called before Start.
+func (cm *ConnectionManager) SetIO(in io.Reader, out io.Writer) {
+	cm.input = in
+	cm.output = out
+}
+
 // Start initializes the connection manager
 func (cm *ConnectionManager) Start() error {
 	cm.logger.WithComponent("connection").Info("Starting connection manager",
 		zap.Int("input_buffer_size", cm.config.InputBufferSize),
 		zap.Int("output_buffer_size", cm.config.OutputBufferSize))
 
-	// Initialize persistent stdin scanner for MCP protocol
-	cm.scannerMutex.Lock()
-	cm.stdinScanner = bufio.NewScanner(os.Stdin)
-	cm.stdinScanner.Buffer(make([]byte, cm.config.InputBufferSize), cm.config.InputBufferSize)
-	cm.scannerMutex.Unlock()
+	// Start the persistent input reader for the MCP protocol
+	cm.readerOnce.Do(func() { go cm.readLoop() })
 
 	// Start health checking
 	cm.healthCheck = time.NewTicker(cm.config.HealthCheckInterval)
@@ -188,12 +203,84 @@ func (cm *ConnectionManager) Start() error {
 	go cm.healthCheckLoop()
 	go cm.reconnectLoop()
 	
+	cm.mutex.Lock()
 	cm.connected = true
+	cm.mutex.Unlock()
 	cm.updateActivity()
 	
 	return nil
 }
 
+// maxConsecutiveReadErrors is how many back-to-back input errors are
+// tolerated before the input is treated as closed
+const maxConsecutiveReadErrors = 5
+
+// readLoop scans lines from the input and hands them to ReadMessage. A
+// scanner error (such as an oversized line) is reported and scanning resumes
+// with a fresh scanner; end of input is reported once and closes the stream.
+func (cm *ConnectionManager) readLoop() {
+	defer func() {
+		if r := recover(); r != nil {
+			cm.logger.WithComponent("connection").Error("Input reader panicked", zap.Any("panic", r))
+			cm.deliver(readResult{err: fmt.Errorf("read panic: %v", r)})
+		}
+		close(cm.lines)
+	}()
+
+	consecutiveErrors := 0
+	for {
+		scanner := bufio.NewScanner(cm.input)
+		scanner.Buffer(make([]byte, cm.config.InputBufferSize), cm.config.InputBufferSize)
+
+		for scanner.Scan() {
+			line := scanner.Text()
+			cm.inputBuffer.Write([]byte(line + "\n"))
+			cm.updateActivity()
+			consecutiveErrors = 0
+			if !cm.deliver(readResult{line: line}) {
+				return
+			}
+		}
+
+		err := scanner.Err()
+		if err == nil {
+			// EOF - the client closed its end of the pipe
+			cm.logger.WithComponent("connection").Debug("EOF received from stdin")
+			cm.deliver(readResult{err: io.EOF})
+			return
+		}
+		if !cm.deliver(readResult{err: err}) {
+			return
+		}
+
+		// A line that overflowed the buffer is skipped; anything else is a
+		// broken stream that gets a short back-off and, eventually, gives up
+		if err != bufio.ErrTooLong {
+			consecutiveErrors++
+			if consecutiveErrors >= maxConsecutiveReadErrors {
+				cm.logger.WithComponent("connection").Warn("Input keeps failing - treating it as closed", zap.Error(err))
+				cm.deliver(readResult{err: io.EOF})
+				return
+			}
+			select {
+			case <-time.After(time.Duration(consecutiveErrors) * 100 * time.Millisecond):
+			case <-cm.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// deliver passes a read result to ReadMessage, giving up when the manager stops
+func (cm *ConnectionManager) deliver(result readResult) bool {
+	select {
+	case cm.lines <- result:
+		return true
+	case <-cm.ctx.Done():
+		return false
+	}
+}
+
 // Stop gracefully shuts down the connection manager
 func (cm *ConnectionManager) Stop() error {
 	cm.logger.WithComponent("connection").Info("Stopping connection manager")
@@ -229,62 +316,32 @@ func (cm *ConnectionManager) ReadMessage() (string, error) {
 	ctx, cancel := context.WithTimeout(cm.ctx, cm.config.ReadTimeout)
 	defer cancel()
 
-	// Channel to receive the result
-	resultCh := make(chan string, 1)
-	errorCh := make(chan error, 1)
-
-	// Read in goroutine with proper signal handling using persistent scanner
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				errorCh <- fmt.Errorf("read panic: %v", r)
-			}
-		}()
-
-		cm.scannerMutex.Lock()
-		scanner := cm.stdinScanner
-		cm.scannerMutex.Unlock()
-		
-		if scanner == nil {
-			errorCh <- fmt.Errorf("scanner not initialized")
-			return
+	select {
+	case result, ok := <-cm.lines:
+		if !ok {
+			// The reader has finished; input will never produce more data
+			return "", io.EOF
 		}
-		
-		if scanner.Scan() {
-			line := scanner.Text()
-			cm.inputBuffer.Write([]byte(line + "\n"))
-			cm.updateActivity()
-			resultCh <- line
-		} else {
-			if err := scanner.Err(); err != nil {
-				// Check for specific error types
-				if isConnectionError(err) {
-					cm.logger.WithComponent("connection").Warn("Connection error detected", zap.Error(err))
-					cm.handleConnectionLoss(err)
-					errorCh <- fmt.Errorf("connection lost: %w", err)
-				} else {
-					cm.logger.WithComponent("connection").Debug("Scanner error, treating as recoverable", zap.Error(err))
-					// For non-critical scanner errors, signal to continue instead of failing
-					errorCh <- fmt.Errorf("scanner error (recoverable): %w", err)
-				}
-			} else {
-				// EOF - scanner returns false with no error when EOF is reached
-				cm.logger.WithComponent("connection").Debug("EOF received from stdin")
-				// Return io.EOF so the server can handle it properly
-				errorCh <- io.EOF
-			}
+		if result.err == nil {
+			return result.line, nil
 		}
-	}()
-
-	select {
-	case result := <-resultCh:
-		return result, nil
-	case err := <-errorCh:
-		return "", err
+		if result.err == io.EOF {
+			return "", io.EOF
+		}
+		// Check for specific error types
+		if isConnectionError(result.err) {
+			cm.logger.WithComponent("connection").Warn("Connection error detected", zap.Error(result.err))
+			cm.handleConnectionLoss(result.err)
+			return "", fmt.Errorf("connection lost: %w", result.err)
+		}
+		cm.logger.WithComponent("connection").Debug("Scanner error, treating as recoverable", zap.Error(result.err))
+		// For non-critical scanner errors, signal to continue instead of failing
+		return "", fmt.Errorf("scanner error (recoverable): %w", result.err)
 	case <-ctx.Done():
+		if cm.ctx.Err() != nil {
+			return "", fmt.Errorf("connection manager stopped")
+		}
 		return "", fmt.Errorf("read timeout after %v", cm.config.ReadTimeout)
-	case <-cm.ctx.Done():
-		return "", fmt.Errorf("connection manager stopped")
 	}
 }
 
@@ -313,7 +370,7 @@ func (cm *ConnectionManager) WriteMessage(message string) error {
 		cm.outputBuffer.Write(data)
 		
 		// Write to stdout with signal handling
-		_, err := os.Stdout.Write(data)
+		_, err := cm.output.Write(data)
 		if err != nil {
 			if isConnectionError(err) {
 				cm.handleConnectionLoss(err)
@@ -461,24 +518,10 @@ func (cm *ConnectionManager) attemptReconnect() {
 	cm.connected = true
 }
 
-// reinitializeConnection attempts to reinitialize the stdin scanner and connection
+// reinitializeConnection checks that the streams are usable again. The input
+// reader restarts its own scanner after errors, so nothing is recreated here.
 func (cm *ConnectionManager) reinitializeConnection() bool {
-	// Test basic connection first
-	if !cm.testConnection() {
-		return false
-	}
-	
-	// Reinitialize the stdin scanner
-	cm.scannerMutex.Lock()
-	defer cm.scannerMutex.Unlock()
-	
-	cm.logger.WithComponent("connection").Debug("Reinitializing stdin scanner for reconnection")
-	
-	// Create new scanner instance
-	cm.stdinScanner = bufio.NewScanner(os.Stdin)
-	cm.stdinScanner.Buffer(make([]byte, cm.config.InputBufferSize), cm.config.InputBufferSize)
-	
-	return true
+	return cm.testConnection()
 }
 
 // testConnection tests if the connection is working
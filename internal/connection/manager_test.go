@@ -2,6 +2,9 @@ package connection
 
 import (
 	"bytes"
+	"io"
+	"rodmcp/internal/logger"
+	"strings"
 	"testing"
 	"time"
 )
@@ -200,4 +203,57 @@ type testError struct {
 
 func (e *testError) Error() string {
 	return e.msg
-}
\ No newline at end of file
+}
+func TestConnectionManager_ReadSurvivesTimeout(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	config := DefaultConfig()
+	config.ReadTimeout = 20 * time.Millisecond
+	cm := NewConnectionManager(log, config)
+
+	inReader, inWriter := io.Pipe()
+	cm.SetIO(inReader, io.Discard)
+	if err := cm.Start(); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer cm.Stop()
+
+	// Several reads time out while the client is silent
+	for i := 0; i < 3; i++ {
+		if _, err := cm.ReadMessage(); err == nil || !strings.Contains(err.Error(), "timeout") {
+			t.Fatalf("Expected read timeout, got %v", err)
+		}
+	}
+
+	// Messages sent after the stall must all arrive, in order
+	go func() {
+		inWriter.Write([]byte("first\nsecond\n"))
+		inWriter.Close()
+	}()
+
+	for _, want := range []string{"first", "second"} {
+		var line string
+		var err error
+		for attempt := 0; attempt < 50; attempt++ {
+			line, err = cm.ReadMessage()
+			if err == nil {
+				break
+			}
+		}
+		if line != want {
+			t.Fatalf("Expected %q, got %q (%v)", want, line, err)
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt < 50; attempt++ {
+		if _, err = cm.ReadMessage(); err == io.EOF {
+			break
+		}
+	}
+	if err != io.EOF {
+		t.Fatalf("Expected EOF after input closed, got %v", err)
+	}
+	if _, err := cm.ReadMessage(); err != io.EOF {
+		t.Errorf("Expected EOF to persist, got %v", err)
+	}
+}
@@ -0,0 +1,478 @@
+// Package doctor runs environment self-tests for RodMCP and suggests fixes
+// for the problems it finds.
+package doctor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"strings"
+	"time"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Result describes one diagnostic check
+type Result struct {
+	Name     string        `json:"name"`
+	Status   Status        `json:"status"`
+	Detail   string        `json:"detail"`
+	Fix      string        `json:"fix,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Options configures which checks run and what they inspect
+type Options struct {
+	LogDir        string
+	ScreenshotDir string
+	EgressURL     string
+	SkipNetwork   bool
+	SkipStdio     bool
+	// Executable is the rodmcp binary used for the stdio handshake check
+	Executable string
+	Logger     *logger.Logger
+}
+
+// DefaultOptions returns the options used by `rodmcp doctor`
+func DefaultOptions() Options {
+	exe, _ := os.Executable()
+	return Options{
+		LogDir:        "logs",
+		ScreenshotDir: ".",
+		EgressURL:     "https://example.com",
+		Executable:    exe,
+	}
+}
+
+const (
+	browserCheckTimeout = 60 * time.Second
+	networkCheckTimeout = 10 * time.Second
+	stdioCheckTimeout   = 60 * time.Second
+)
+
+// Run executes all checks in order and returns their results
+func Run(ctx context.Context, opts Options) []Result {
+	var results []Result
+
+	results = append(results, timed(func() Result { return CheckDirectoryWritable("Log directory", opts.LogDir, "--log-dir") }))
+	results = append(results, timed(func() Result {
+		return CheckDirectoryWritable("Screenshot directory", opts.ScreenshotDir, "a writable working directory")
+	}))
+
+	browserResults := checkBrowser(ctx, opts)
+	results = append(results, browserResults...)
+
+	if opts.SkipNetwork {
+		results = append(results, Result{Name: "Network egress", Status: StatusSkip, Detail: "skipped by request"})
+	} else {
+		results = append(results, timed(func() Result { return CheckNetworkEgress(ctx, opts.EgressURL) }))
+	}
+
+	browserOK := true
+	for _, r := range browserResults {
+		if r.Status == StatusFail {
+			browserOK = false
+		}
+	}
+	switch {
+	case opts.SkipStdio:
+		results = append(results, Result{Name: "Stdio framing", Status: StatusSkip, Detail: "skipped by request"})
+	case !browserOK:
+		results = append(results, Result{Name: "Stdio framing", Status: StatusSkip, Detail: "skipped because the browser could not be launched"})
+	default:
+		results = append(results, timed(func() Result { return CheckStdioFraming(ctx, opts.Executable, opts.LogDir) }))
+	}
+
+	return results
+}
+
+// HasFailures reports whether any check failed
+func HasFailures(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Format renders results as a human-readable report
+func Format(results []Result) string {
+	icons := map[Status]string{
+		StatusPass: "✅",
+		StatusWarn: "⚠️ ",
+		StatusFail: "❌",
+		StatusSkip: "⏭️ ",
+	}
+
+	var b strings.Builder
+	b.WriteString("🩺 RodMCP Doctor\n")
+	b.WriteString("================\n\n")
+
+	counts := make(map[Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+		fmt.Fprintf(&b, "%s %s: %s", icons[r.Status], r.Name, r.Detail)
+		if r.Duration >= time.Millisecond {
+			fmt.Fprintf(&b, " (%s)", r.Duration.Round(time.Millisecond))
+		}
+		b.WriteString("\n")
+		if r.Fix != "" {
+			fmt.Fprintf(&b, "   → Fix: %s\n", r.Fix)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n%d passed, %d warnings, %d failed, %d skipped\n",
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+	return b.String()
+}
+
+func timed(check func() Result) Result {
+	start := time.Now()
+	r := check()
+	r.Duration = time.Since(start)
+	return r
+}
+
+// CheckDirectoryWritable verifies a file can be created in dir. Missing
+// directories are not created; their nearest existing parent is checked instead.
+func CheckDirectoryWritable(name, dir, flagHint string) Result {
+	if dir == "" {
+		dir = "."
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Result{Name: name, Status: StatusFail, Detail: err.Error(), Fix: "Pass an absolute path via " + flagHint}
+	}
+
+	target := abs
+	missing := false
+	for {
+		info, err := os.Stat(target)
+		if err == nil {
+			if !info.IsDir() {
+				return Result{
+					Name:   name,
+					Status: StatusFail,
+					Detail: fmt.Sprintf("%s exists but is not a directory", target),
+					Fix:    fmt.Sprintf("Remove %s or choose a different path via %s", target, flagHint),
+				}
+			}
+			break
+		}
+		missing = true
+		parent := filepath.Dir(target)
+		if parent == target {
+			break
+		}
+		target = parent
+	}
+
+	probe, err := os.CreateTemp(target, ".rodmcp-doctor-*")
+	if err != nil {
+		return Result{
+			Name:   name,
+			Status: StatusFail,
+			Detail: fmt.Sprintf("%s is not writable: %v", target, err),
+			Fix:    fmt.Sprintf("Run 'chmod u+w %s' or choose a writable path via %s", target, flagHint),
+		}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	if missing {
+		return Result{Name: name, Status: StatusPass, Detail: fmt.Sprintf("%s will be created (parent %s is writable)", abs, target)}
+	}
+	return Result{Name: name, Status: StatusPass, Detail: fmt.Sprintf("%s is writable", abs)}
+}
+
+// checkBrowser covers discovery, launch and headless rendering
+func checkBrowser(ctx context.Context, opts Options) []Result {
+	log := opts.Logger
+	if log == nil {
+		var err error
+		log, err = logger.New(logger.Config{LogLevel: "error", LogDir: os.TempDir()})
+		if err != nil {
+			return []Result{{Name: "Browser discovery", Status: StatusFail, Detail: err.Error()}}
+		}
+	}
+
+	config := browser.Config{Headless: true, WindowWidth: 800, WindowHeight: 600}
+	mgr := browser.NewManager(log, config)
+
+	var results []Result
+
+	start := time.Now()
+	path, err := mgr.FindBrowser()
+	discovery := Result{Name: "Browser discovery", Duration: time.Since(start)}
+	switch {
+	case err != nil:
+		discovery.Status = StatusFail
+		discovery.Detail = err.Error()
+		discovery.Fix = "Install Chromium or Google Chrome, or set RODMCP_BROWSER_PATH to a working browser binary"
+	case path == "":
+		discovery.Status = StatusWarn
+		discovery.Detail = "no system browser found; Rod will download Chromium on first launch"
+		discovery.Fix = "Install Chromium/Chrome or set RODMCP_BROWSER_PATH to avoid the download and its network requirement"
+	default:
+		discovery.Status = StatusPass
+		discovery.Detail = path
+	}
+	results = append(results, discovery)
+
+	launchCtx, cancel := context.WithTimeout(ctx, browserCheckTimeout)
+	defer cancel()
+
+	type launchResult struct {
+		shot []byte
+		err  error
+		step string
+	}
+	done := make(chan launchResult, 1)
+	start = time.Now()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- launchResult{err: fmt.Errorf("panic: %v", r), step: "launch"}
+			}
+		}()
+		if err := mgr.Start(config); err != nil {
+			done <- launchResult{err: err, step: "launch"}
+			return
+		}
+		defer mgr.Stop()
+
+		_, pageID, err := mgr.NewPage("about:blank")
+		if err != nil {
+			done <- launchResult{err: err, step: "render"}
+			return
+		}
+		script := `document.body.style.margin = '0';
+document.body.innerHTML = '<div style="width:100vw;height:50vh;background:#ff0000"></div><div style="height:50vh;background:#0000ff"></div>';
+return true;`
+		if _, err := mgr.ExecuteScript(pageID, script); err != nil {
+			done <- launchResult{err: err, step: "render"}
+			return
+		}
+		shot, err := mgr.Screenshot(pageID)
+		done <- launchResult{shot: shot, err: err, step: "render"}
+	}()
+
+	var lr launchResult
+	select {
+	case lr = <-done:
+	case <-launchCtx.Done():
+		lr = launchResult{err: fmt.Errorf("timed out after %s", browserCheckTimeout), step: "launch"}
+	}
+	elapsed := time.Since(start)
+
+	launch := Result{Name: "Browser launch", Duration: elapsed}
+	render := Result{Name: "Headless rendering"}
+	if lr.err != nil && lr.step == "launch" {
+		launch.Status = StatusFail
+		launch.Detail = lr.err.Error()
+		launch.Fix = launchFix(lr.err)
+		render.Status = StatusSkip
+		render.Detail = "skipped because the browser could not be launched"
+		return append(results, launch, render)
+	}
+	launch.Status = StatusPass
+	launch.Detail = "headless browser started and responded"
+
+	if lr.err != nil {
+		render.Status = StatusFail
+		render.Detail = lr.err.Error()
+		render.Fix = "Check that the browser can render pages headlessly; missing fonts or GPU libraries are common causes"
+		return append(results, launch, render)
+	}
+	if err := verifyRenderedScreenshot(lr.shot); err != nil {
+		render.Status = StatusFail
+		render.Detail = err.Error()
+		render.Fix = "Install fonts and graphics libraries for headless Chromium (e.g. fonts-liberation, libgbm1, libnss3)"
+	} else {
+		render.Status = StatusPass
+		render.Detail = fmt.Sprintf("rendered a test page and captured a %d byte screenshot", len(lr.shot))
+	}
+	return append(results, launch, render)
+}
+
+// launchFix maps common launch errors to actionable advice
+func launchFix(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "shared object") || strings.Contains(msg, "dependencies"):
+		return "Install the browser's system libraries (e.g. 'apt-get install libnss3 libatk1.0-0 libgbm1 libasound2') or use a full Chrome install"
+	case strings.Contains(msg, "browser binary") || strings.Contains(msg, "download"):
+		return "Install Chromium or Google Chrome (e.g. 'apt-get install chromium') or set RODMCP_BROWSER_PATH; the automatic download needs network access"
+	case strings.Contains(msg, "timed out"):
+		return "The browser started too slowly; check CPU/memory limits and that no sandbox restrictions block Chrome"
+	case strings.Contains(msg, "sandbox"):
+		return "Chrome's sandbox is unavailable; run as a non-root user or in a container with the required capabilities"
+	default:
+		return "Run 'rodmcp --log-level debug' and check the logs directory for the full launch error"
+	}
+}
+
+// verifyRenderedScreenshot checks the screenshot decodes and shows the test page
+func verifyRenderedScreenshot(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("screenshot was empty")
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("screenshot is not a valid PNG: %w", err)
+	}
+	bounds := img.Bounds()
+	if bounds.Dx() == 0 || bounds.Dy() == 0 {
+		return fmt.Errorf("screenshot has zero size")
+	}
+	top := img.At(bounds.Min.X+bounds.Dx()/2, bounds.Min.Y+bounds.Dy()/4)
+	bottom := img.At(bounds.Min.X+bounds.Dx()/2, bounds.Min.Y+bounds.Dy()*3/4)
+	tr, _, tb, _ := top.RGBA()
+	br, _, bb, _ := bottom.RGBA()
+	if tr <= tb || bb <= br {
+		return fmt.Errorf("screenshot did not show the test page content (blank or incorrect rendering)")
+	}
+	return nil
+}
+
+// CheckNetworkEgress verifies outbound HTTP(S) requests succeed
+func CheckNetworkEgress(ctx context.Context, url string) Result {
+	result := Result{Name: "Network egress"}
+	reqCtx, cancel := context.WithTimeout(ctx, networkCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodHead, url, nil)
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		return result
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		result.Status = StatusWarn
+		result.Detail = fmt.Sprintf("request to %s failed: %v", url, err)
+		result.Fix = "Check proxy settings (HTTPS_PROXY) and firewall rules; local pages will still work without egress"
+		return result
+	}
+	resp.Body.Close()
+	result.Status = StatusPass
+	result.Detail = fmt.Sprintf("%s responded with %s", url, resp.Status)
+	return result
+}
+
+// CheckStdioFraming starts the server in stdio mode, sends an initialize
+// request, and verifies the first line on stdout is a JSON-RPC response
+func CheckStdioFraming(ctx context.Context, executable, logDir string) Result {
+	if executable == "" {
+		return Result{Name: "Stdio framing", Status: StatusSkip, Detail: "could not determine the rodmcp executable path"}
+	}
+	return CheckStdioHandshake(ctx, "Stdio framing", executable, []string{"--headless", "--log-level", "error", "--log-dir", logDir})
+}
+
+// CheckStdioHandshake runs command with args as an MCP stdio server and
+// verifies it answers an initialize request with a JSON-RPC response
+func CheckStdioHandshake(ctx context.Context, name, command string, args []string) Result {
+	result := Result{Name: name}
+
+	checkCtx, cancel := context.WithTimeout(ctx, stdioCheckTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(checkCtx, command, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		return result
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		return result
+	}
+	cmd.Stderr = io.Discard
+	if err := cmd.Start(); err != nil {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("failed to start %s: %v", command, err)
+		return result
+	}
+	defer func() {
+		stdin.Close()
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	request := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05","capabilities":{},"clientInfo":{"name":"rodmcp-doctor","version":"1.0.0"}}}` + "\n"
+	if _, err := io.WriteString(stdin, request); err != nil {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("failed to write to server stdin: %v", err)
+		return result
+	}
+
+	lineChan := make(chan string, 1)
+	errChan := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(stdout)
+		line, err := reader.ReadString('\n')
+		if err != nil && line == "" {
+			errChan <- err
+			return
+		}
+		lineChan <- line
+	}()
+
+	var line string
+	select {
+	case line = <-lineChan:
+	case err := <-errChan:
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("server closed stdout without responding: %v", err)
+		result.Fix = "Run 'rodmcp --log-level debug' and check the logs for startup errors"
+		return result
+	case <-checkCtx.Done():
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("no response to initialize within %s", stdioCheckTimeout)
+		result.Fix = "Check the logs directory for startup errors; the browser may be slow to launch"
+		return result
+	}
+
+	var response struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(line), &response); err != nil || response.JSONRPC != "2.0" {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("first stdout line is not a JSON-RPC message: %.80q", line)
+		result.Fix = "Something is writing to stdout before the MCP handshake (shell profile output, wrappers); stdout must carry only JSON-RPC"
+		return result
+	}
+	if string(response.ID) != "1" || len(response.Result) == 0 {
+		result.Status = StatusFail
+		result.Detail = fmt.Sprintf("unexpected initialize response: %.120s", line)
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Detail = "initialize handshake completed over newline-delimited JSON-RPC"
+	return result
+}
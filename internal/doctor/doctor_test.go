@@ -0,0 +1,78 @@
+package doctor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckDirectoryWritable(t *testing.T) {
+	dir := t.TempDir()
+
+	if r := CheckDirectoryWritable("dir", dir, "--dir"); r.Status != StatusPass {
+		t.Errorf("Expected existing temp dir to pass, got %+v", r)
+	}
+
+	missing := filepath.Join(dir, "a", "b")
+	r := CheckDirectoryWritable("dir", missing, "--dir")
+	if r.Status != StatusPass || !strings.Contains(r.Detail, "will be created") {
+		t.Errorf("Expected missing dir with writable parent to pass, got %+v", r)
+	}
+	if _, err := os.Stat(missing); err == nil {
+		t.Error("Expected the check not to create the directory")
+	}
+
+	file := filepath.Join(dir, "file.txt")
+	os.WriteFile(file, []byte("x"), 0644)
+	if r := CheckDirectoryWritable("dir", file, "--dir"); r.Status != StatusFail || r.Fix == "" {
+		t.Errorf("Expected a regular file to fail with a fix, got %+v", r)
+	}
+}
+
+func TestCheckNetworkEgress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	if r := CheckNetworkEgress(context.Background(), server.URL); r.Status != StatusPass {
+		t.Errorf("Expected reachable server to pass, got %+v", r)
+	}
+
+	server.Close()
+	if r := CheckNetworkEgress(context.Background(), server.URL); r.Status != StatusWarn || r.Fix == "" {
+		t.Errorf("Expected unreachable server to warn with a fix, got %+v", r)
+	}
+}
+
+func TestFormatAndHasFailures(t *testing.T) {
+	results := []Result{
+		{Name: "A", Status: StatusPass, Detail: "ok"},
+		{Name: "B", Status: StatusFail, Detail: "broken", Fix: "repair it"},
+	}
+	out := Format(results)
+	if !strings.Contains(out, "Fix: repair it") || !strings.Contains(out, "1 passed, 0 warnings, 1 failed") {
+		t.Errorf("Unexpected report:\n%s", out)
+	}
+	if !HasFailures(results) || HasFailures(results[:1]) {
+		t.Error("HasFailures returned the wrong result")
+	}
+}
+
+func TestCheckStdioHandshake(t *testing.T) {
+	ok := CheckStdioHandshake(context.Background(), "handshake", "sh",
+		[]string{"-c", `read line; echo '{"jsonrpc":"2.0","id":1,"result":{"protocolVersion":"2024-11-05"}}'`})
+	if ok.Status != StatusPass {
+		t.Errorf("Expected JSON-RPC response to pass, got %+v", ok)
+	}
+
+	noisy := CheckStdioHandshake(context.Background(), "handshake", "sh",
+		[]string{"-c", `echo 'Welcome!'; read line`})
+	if noisy.Status != StatusFail || noisy.Fix == "" {
+		t.Errorf("Expected non-JSON stdout to fail with a fix, got %+v", noisy)
+	}
+}
@@ -1,9 +1,11 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -13,8 +15,15 @@ import (
 type Logger struct {
 	*zap.Logger
 	sugar *zap.SugaredLogger
+
+	// redact masks secrets in logged tool arguments (see SetArgumentRedactor)
+	redact atomic.Pointer[ArgumentRedactor]
 }
 
+// ArgumentRedactor returns a copy of a tool call's arguments with secrets,
+// such as passwords and authorization headers, masked. args is not modified.
+type ArgumentRedactor func(tool string, args map[string]interface{}) map[string]interface{}
+
 type Config struct {
 	LogLevel    string
 	LogDir      string
@@ -112,7 +121,44 @@ func (l *Logger) WithRequest(requestID string) *zap.Logger {
 	return l.Logger.With(zap.String("request_id", requestID))
 }
 
+// SetArgumentRedactor masks tool arguments with redact wherever this logger
+// records a tool call: tools/call requests and tool executions
+func (l *Logger) SetArgumentRedactor(redact ArgumentRedactor) {
+	l.redact.Store(&redact)
+}
+
+// RedactArguments returns args as they may be logged for a call to tool
+func (l *Logger) RedactArguments(tool string, args map[string]interface{}) map[string]interface{} {
+	if redact := l.redact.Load(); redact != nil && *redact != nil && args != nil {
+		return (*redact)(tool, args)
+	}
+	return args
+}
+
+// redactCallParams masks the arguments in a tools/call request's params
+func (l *Logger) redactCallParams(params interface{}) interface{} {
+	if l.redact.Load() == nil {
+		return params
+	}
+	encoded, err := json.Marshal(params)
+	if err != nil {
+		return params
+	}
+	var call map[string]interface{}
+	if err := json.Unmarshal(encoded, &call); err != nil {
+		return params
+	}
+	name, _ := call["name"].(string)
+	if args, ok := call["arguments"].(map[string]interface{}); ok {
+		call["arguments"] = l.RedactArguments(name, args)
+	}
+	return call
+}
+
 func (l *Logger) LogMCPRequest(method string, params interface{}) {
+	if method == "tools/call" {
+		params = l.redactCallParams(params)
+	}
 	l.WithComponent("mcp").Info("MCP request",
 		zap.String("method", method),
 		zap.Any("params", params),
@@ -142,6 +188,7 @@ func (l *Logger) LogBrowserAction(action string, url string, duration int64) {
 }
 
 func (l *Logger) LogToolExecution(toolName string, args map[string]interface{}, success bool, duration int64) {
+	args = l.RedactArguments(toolName, args)
 	if success {
 		l.WithComponent("tools").Info("Tool execution successful",
 			zap.String("tool", toolName),
@@ -0,0 +1,355 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// defaultApprovalTimeout is how long a held call waits for a decision when
+// the policy does not say
+const defaultApprovalTimeout = 5 * time.Minute
+
+// approvalPreviewLength bounds string arguments shown to the operator
+const approvalPreviewLength = 500
+
+var errApprovalNotFound = errors.New("no pending approval with that id")
+
+// ApprovalRule holds calls to a tool until an operator approves them. With
+// OutsideWorkdir set, only calls whose "path" argument resolves outside the
+// working directory are held. In a config file a rule may also be just the
+// tool name.
+type ApprovalRule struct {
+	Tool           string `json:"tool"`
+	OutsideWorkdir bool   `json:"outside_workdir,omitempty"`
+}
+
+func (r *ApprovalRule) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*r = ApprovalRule{Tool: name}
+		return nil
+	}
+	type plain ApprovalRule
+	var rule plain
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return err
+	}
+	if rule.Tool == "" {
+		return fmt.Errorf("approval rule needs a tool name")
+	}
+	*r = ApprovalRule(rule)
+	return nil
+}
+
+// ApprovalPolicy is the "approval" object of the config file:
+//
+//	{"timeout": "5m", "rules": ["execute_script", {"tool": "write_file", "outside_workdir": true}]}
+type ApprovalPolicy struct {
+	Rules []ApprovalRule
+
+	// Timeout is how long a held call waits before it is denied;
+	// defaultApprovalTimeout when zero
+	Timeout time.Duration
+
+	// WorkDir is the directory OutsideWorkdir rules compare against; the
+	// process working directory when empty
+	WorkDir string
+
+	// AskClient also asks the client's user through elicitation, for
+	// deployments where the person at the client is the one to decide
+	AskClient bool
+}
+
+func (p *ApprovalPolicy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Rules     []ApprovalRule `json:"rules"`
+		Timeout   interface{}    `json:"timeout"`
+		WorkDir   string         `json:"workdir"`
+		AskClient bool           `json:"ask_client"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	policy := ApprovalPolicy{Rules: raw.Rules, WorkDir: raw.WorkDir, AskClient: raw.AskClient}
+	if raw.Timeout != nil {
+		d, err := parseConfigDuration(raw.Timeout)
+		if err != nil {
+			return fmt.Errorf("approval timeout %w", err)
+		}
+		policy.Timeout = d
+	}
+	*p = policy
+	return nil
+}
+
+// requires reports why a call needs approval, or false when it may run
+func (p ApprovalPolicy) requires(name string, args map[string]interface{}) (string, bool) {
+	for _, rule := range p.Rules {
+		if rule.Tool != name {
+			continue
+		}
+		if !rule.OutsideWorkdir {
+			return name + " always requires approval", true
+		}
+		if target, outside := p.outsideWorkdir(args); outside {
+			return fmt.Sprintf("%s targets %s, outside the working directory", name, target), true
+		}
+	}
+	return "", false
+}
+
+// outsideWorkdir resolves the call's "path" argument the way the file tools
+// do, relative to "cwd" or the working directory
+func (p ApprovalPolicy) outsideWorkdir(args map[string]interface{}) (string, bool) {
+	path, _ := args["path"].(string)
+	if path == "" {
+		return "", false
+	}
+	workDir := p.WorkDir
+	if workDir == "" {
+		workDir, _ = os.Getwd()
+	}
+	if !filepath.IsAbs(path) {
+		base, _ := args["cwd"].(string)
+		if base == "" {
+			base = workDir
+		}
+		path = filepath.Join(base, path)
+	}
+	path = filepath.Clean(path)
+	rel, err := filepath.Rel(workDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path, true
+	}
+	return path, false
+}
+
+// PendingApproval is a held tool call as shown to the operator
+type PendingApproval struct {
+	ID          string                 `json:"id"`
+	Tool        string                 `json:"tool"`
+	Reason      string                 `json:"reason"`
+	Arguments   map[string]interface{} `json:"arguments"`
+	RequestedAt time.Time              `json:"requested_at"`
+	ExpiresAt   time.Time              `json:"expires_at"`
+
+	decision chan approvalDecision
+}
+
+type approvalDecision struct {
+	approved bool
+	reason   string
+}
+
+// approvalGate holds calls the policy marks as dangerous until someone
+// approves or denies them
+type approvalGate struct {
+	mutex   sync.Mutex
+	policy  ApprovalPolicy
+	pending map[string]*PendingApproval
+	nextID  int
+}
+
+// SetApprovalPolicy holds calls matching the policy's rules until they are
+// approved with DecideApproval. It returns the rule tool names that match no
+// registered tool, so call it after registering tools.
+func (c *core) SetApprovalPolicy(policy ApprovalPolicy) []string {
+	c.approvals.mutex.Lock()
+	c.approvals.policy = policy
+	c.approvals.mutex.Unlock()
+
+	c.toolsMutex.RLock()
+	defer c.toolsMutex.RUnlock()
+	var unknown []string
+	for _, rule := range policy.Rules {
+		if _, exists := c.tools[rule.Tool]; !exists {
+			unknown = append(unknown, rule.Tool)
+		}
+	}
+	return unknown
+}
+
+// PendingApprovals lists the calls waiting for a decision, oldest first
+func (c *core) PendingApprovals() []PendingApproval {
+	c.approvals.mutex.Lock()
+	defer c.approvals.mutex.Unlock()
+	list := make([]PendingApproval, 0, len(c.approvals.pending))
+	for _, p := range c.approvals.pending {
+		entry := *p
+		entry.decision = nil
+		list = append(list, entry)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].RequestedAt.Before(list[j].RequestedAt)
+	})
+	return list
+}
+
+// DecideApproval approves or denies a held call. The reason is passed back
+// to the client when the call is denied.
+func (c *core) DecideApproval(id string, approve bool, reason string) error {
+	c.approvals.mutex.Lock()
+	p, ok := c.approvals.pending[id]
+	if ok {
+		delete(c.approvals.pending, id)
+	}
+	c.approvals.mutex.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", errApprovalNotFound, id)
+	}
+
+	c.logger.WithComponent(c.component).Info("Tool call approval decided",
+		zap.String("id", id),
+		zap.String("tool", p.Tool),
+		zap.Bool("approved", approve),
+		zap.String("reason", reason))
+	p.decision <- approvalDecision{approved: approve, reason: reason}
+	return nil
+}
+
+// denyPendingApprovals refuses every held call, used when draining
+func (c *core) denyPendingApprovals(reason string) {
+	c.approvals.mutex.Lock()
+	pending := c.approvals.pending
+	c.approvals.pending = nil
+	c.approvals.mutex.Unlock()
+	for _, p := range pending {
+		p.decision <- approvalDecision{reason: reason}
+	}
+}
+
+// awaitApproval holds the call when the policy requires it. It returns nil
+// when the call may run, otherwise the response to send instead.
+func (c *core) awaitApproval(ctx context.Context, name string, args map[string]interface{}) *types.CallToolResponse {
+	c.approvals.mutex.Lock()
+	reason, required := c.approvals.policy.requires(name, args)
+	if !required {
+		c.approvals.mutex.Unlock()
+		return nil
+	}
+	timeout := c.approvals.policy.Timeout
+	if timeout <= 0 {
+		timeout = defaultApprovalTimeout
+	}
+	c.approvals.nextID++
+	now := time.Now()
+	p := &PendingApproval{
+		ID:          strconv.Itoa(c.approvals.nextID),
+		Tool:        name,
+		Reason:      reason,
+		Arguments:   previewArguments(args),
+		RequestedAt: now,
+		ExpiresAt:   now.Add(timeout),
+		decision:    make(chan approvalDecision, 1),
+	}
+	if c.approvals.pending == nil {
+		c.approvals.pending = make(map[string]*PendingApproval)
+	}
+	c.approvals.pending[p.ID] = p
+	askClient := c.approvals.policy.AskClient
+	c.approvals.mutex.Unlock()
+
+	c.logger.WithComponent(c.component).Warn("Tool call waiting for approval",
+		zap.String("id", p.ID),
+		zap.String("tool", name),
+		zap.String("reason", reason))
+	if c.push != nil {
+		c.SendLogMessage("warning", fmt.Sprintf("%s is waiting for operator approval (id %s)", name, p.ID), map[string]interface{}{
+			"tool":   name,
+			"id":     p.ID,
+			"reason": reason,
+		})
+	}
+
+	if askClient && c.elicit != nil {
+		// Stop asking once the call is decided some other way
+		askCtx, stopAsking := context.WithCancel(ctx)
+		defer stopAsking()
+		go c.askClientApproval(askCtx, p)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	var refusal string
+	select {
+	case decision := <-p.decision:
+		if decision.approved {
+			return nil
+		}
+		refusal = "denied by the operator"
+		if decision.reason != "" {
+			refusal += ": " + decision.reason
+		}
+	case <-timer.C:
+		refusal = fmt.Sprintf("not approved within %s", timeout)
+	case <-ctx.Done():
+		refusal = "cancelled while waiting for approval"
+	}
+
+	// A decision may have raced the timeout; drop the entry either way
+	c.approvals.mutex.Lock()
+	delete(c.approvals.pending, p.ID)
+	c.approvals.mutex.Unlock()
+
+	c.logger.WithComponent(c.component).Warn("Tool call not approved",
+		zap.String("id", p.ID),
+		zap.String("tool", name),
+		zap.String("refusal", refusal))
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%s was not run: %s (%s)", name, refusal, reason),
+			Data: map[string]interface{}{"approval_id": p.ID, "approved": false},
+		}},
+		IsError: true,
+	}
+}
+
+// responseLimit is the longest a call to name can take to answer: its
+// timeout plus any wait for approval, with some slack for writing the
+// response. It is zero for unknown tools.
+func (c *core) responseLimit(name string, args map[string]interface{}) time.Duration {
+	c.toolsMutex.RLock()
+	tool, exists := c.tools[name]
+	c.toolsMutex.RUnlock()
+	if !exists {
+		return 0
+	}
+	limit := c.callTimeout(tool, args) + perCallTimeoutGrace
+
+	c.approvals.mutex.Lock()
+	defer c.approvals.mutex.Unlock()
+	if _, required := c.approvals.policy.requires(name, args); required {
+		wait := c.approvals.policy.Timeout
+		if wait <= 0 {
+			wait = defaultApprovalTimeout
+		}
+		limit += wait
+	}
+	return limit
+}
+
+// previewArguments copies args with long strings clipped for display
+func previewArguments(args map[string]interface{}) map[string]interface{} {
+	preview := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if s, ok := value.(string); ok && len(s) > approvalPreviewLength {
+			value = s[:approvalPreviewLength] + fmt.Sprintf("... (%d more bytes)", len(s)-approvalPreviewLength)
+		}
+		preview[key] = value
+	}
+	return preview
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"rodmcp/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+// ApprovalController is the part of a server the approval endpoints drive.
+// Server and HTTPServer both implement it.
+type ApprovalController interface {
+	PendingApprovals() []PendingApproval
+	DecideApproval(id string, approve bool, reason string) error
+}
+
+// NewApprovalServer serves the approval admin endpoints on addr:
+//
+//	GET  /approvals               calls waiting for a decision
+//	POST /approvals/{id}/approve
+//	POST /approvals/{id}/deny     optional body {"reason": "..."}
+//
+// It listens separately from the MCP endpoints so an agent cannot approve its
+// own calls. When token is set every request needs "Authorization: Bearer
+// <token>".
+func NewApprovalServer(log *logger.Logger, addr string, c ApprovalController, token string) *http.Server {
+	adminLog := log.WithComponent("approval-admin")
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /approvals", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminJSON(w, http.StatusOK, map[string]interface{}{"pending": c.PendingApprovals()})
+	})
+	mux.HandleFunc("POST /approvals/{id}/{decision}", func(w http.ResponseWriter, r *http.Request) {
+		decision := r.PathValue("decision")
+		if decision != "approve" && decision != "deny" {
+			writeAdminJSON(w, http.StatusNotFound, map[string]string{"error": "decision must be approve or deny"})
+			return
+		}
+		var body struct {
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(io.LimitReader(r.Body, 64*1024)).Decode(&body); err != nil && err != io.EOF {
+			writeAdminJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("invalid body: %v", err)})
+			return
+		}
+		id := r.PathValue("id")
+		if err := c.DecideApproval(id, decision == "approve", body.Reason); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errApprovalNotFound) {
+				status = http.StatusNotFound
+			}
+			writeAdminJSON(w, status, map[string]string{"error": err.Error()})
+			return
+		}
+		writeAdminJSON(w, http.StatusOK, map[string]interface{}{"id": id, "approved": decision == "approve"})
+	})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				adminLog.Error("Approval endpoint panicked", zap.Any("panic", rec), zap.String("path", r.URL.Path))
+				writeAdminJSON(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+			}
+		}()
+		if token != "" {
+			given := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(given, []byte("Bearer "+token)) != 1 {
+				writeAdminJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or wrong bearer token"})
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+
+	return &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// waitForPending polls until a call is held for approval
+func waitForPending(t *testing.T, c *core) PendingApproval {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if pending := c.PendingApprovals(); len(pending) > 0 {
+			return pending[0]
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("no call was held for approval")
+	return PendingApproval{}
+}
+
+func TestApprovalPolicyConfig(t *testing.T) {
+	var config struct {
+		Approval ApprovalPolicy `json:"approval"`
+	}
+	data := `{"approval": {"timeout": "2m", "rules": ["execute_script", {"tool": "write_file", "outside_workdir": true}]}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatal(err)
+	}
+	policy := config.Approval
+	if policy.Timeout != 2*time.Minute || len(policy.Rules) != 2 || !policy.Rules[1].OutsideWorkdir {
+		t.Fatalf("policy = %+v", policy)
+	}
+
+	policy.WorkDir = "/srv/site"
+	if _, held := policy.requires("execute_script", nil); !held {
+		t.Error("execute_script should always be held")
+	}
+	for path, held := range map[string]bool{
+		"index.html":        false,
+		"/srv/site/a/b.css": false,
+		"../other/x.html":   true,
+		"/etc/passwd":       true,
+		"/srv/site-backup":  true,
+	} {
+		if _, got := policy.requires("write_file", map[string]interface{}{"path": path}); got != held {
+			t.Errorf("write_file %s held = %v, want %v", path, got, held)
+		}
+	}
+	if _, held := policy.requires("write_file", map[string]interface{}{"path": "x.html", "cwd": "/tmp"}); !held {
+		t.Error("a relative path under an outside cwd should be held")
+	}
+
+	if err := json.Unmarshal([]byte(`{"approval": {"timeout": "-1s"}}`), &config); err == nil {
+		t.Error("expected a negative timeout to be rejected")
+	}
+}
+
+func TestCoreApprovalGate(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := NewRecordingTestTool("runner", types.ToolSchema{Type: "object"})
+	c.RegisterTool(tool)
+	c.RegisterTool(NewRecordingTestTool("reader", types.ToolSchema{Type: "object"}))
+	if unknown := c.SetApprovalPolicy(ApprovalPolicy{Rules: []ApprovalRule{{Tool: "runner"}, {Tool: "missing"}}}); len(unknown) != 1 || unknown[0] != "missing" {
+		t.Errorf("unknown tools = %v", unknown)
+	}
+
+	// Tools without a rule run straight away
+	if resp, err := c.callTool(context.Background(), "reader", map[string]interface{}{}); err != nil || resp.IsError {
+		t.Fatalf("reader call failed: %v %+v", err, resp)
+	}
+
+	// Approved calls run
+	done := make(chan *types.CallToolResponse, 1)
+	go func() {
+		resp, _ := c.callTool(context.Background(), "runner", map[string]interface{}{"n": 1.0})
+		done <- resp
+	}()
+	pending := waitForPending(t, c)
+	if pending.Tool != "runner" || pending.Arguments["n"] != 1.0 || tool.LastArgs() != nil {
+		t.Fatalf("pending = %+v, ran early = %v", pending, tool.LastArgs())
+	}
+	if err := c.DecideApproval(pending.ID, true, ""); err != nil {
+		t.Fatal(err)
+	}
+	if resp := <-done; resp.IsError || tool.LastArgs() == nil {
+		t.Fatalf("approved call did not run: %+v", resp)
+	}
+	if err := c.DecideApproval(pending.ID, true, ""); err == nil {
+		t.Error("expected deciding twice to fail")
+	}
+
+	// Denied calls do not, and the reason reaches the client
+	tool2 := NewRecordingTestTool("deleter", types.ToolSchema{Type: "object"})
+	c.RegisterTool(tool2)
+	c.SetApprovalPolicy(ApprovalPolicy{Rules: []ApprovalRule{{Tool: "deleter"}}, Timeout: 50 * time.Millisecond})
+	go func() {
+		resp, _ := c.callTool(context.Background(), "deleter", map[string]interface{}{})
+		done <- resp
+	}()
+	c.DecideApproval(waitForPending(t, c).ID, false, "not today")
+	if resp := <-done; !resp.IsError || !strings.Contains(resp.Content[0].Text, "not today") || tool2.LastArgs() != nil {
+		t.Errorf("denied call = %+v", resp)
+	}
+
+	// Undecided calls are denied when the approval timeout passes
+	resp, _ := c.callTool(context.Background(), "deleter", map[string]interface{}{})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "not approved within") || len(c.PendingApprovals()) != 0 {
+		t.Errorf("timed out call = %+v", resp)
+	}
+
+	// Dry runs never execute, so they are not held
+	c.SetDryRun(true)
+	c.RegisterTool(planningTestTool{NewRecordingTestTool("sender", types.ToolSchema{Type: "object"})})
+	c.SetApprovalPolicy(ApprovalPolicy{Rules: []ApprovalRule{{Tool: "sender"}}, Timeout: time.Minute})
+	if resp, _ := c.callTool(context.Background(), "sender", map[string]interface{}{"method": "POST"}); resp.IsError {
+		t.Errorf("dry run was held: %+v", resp)
+	}
+}
+
+func TestApprovalServer(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(NewRecordingTestTool("runner", types.ToolSchema{Type: "object"}))
+	c.SetApprovalPolicy(ApprovalPolicy{Rules: []ApprovalRule{{Tool: "runner"}}})
+	srv := httptest.NewServer(NewApprovalServer(log, "", c, "secret").Handler)
+	defer srv.Close()
+
+	request := func(method, path, token, body string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var decoded map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&decoded)
+		return resp.StatusCode, decoded
+	}
+
+	if status, _ := request("GET", "/approvals", "wrong", ""); status != http.StatusUnauthorized {
+		t.Errorf("wrong token status = %d", status)
+	}
+
+	done := make(chan *types.CallToolResponse, 1)
+	go func() {
+		resp, _ := c.callTool(context.Background(), "runner", map[string]interface{}{})
+		done <- resp
+	}()
+	id := waitForPending(t, c).ID
+
+	status, body := request("GET", "/approvals", "secret", "")
+	if list, _ := body["pending"].([]interface{}); status != http.StatusOK || len(list) != 1 {
+		t.Fatalf("list = %d %v", status, body)
+	}
+	if status, _ := request("POST", "/approvals/999/approve", "secret", ""); status != http.StatusNotFound {
+		t.Errorf("unknown id status = %d", status)
+	}
+	if status, _ := request("POST", "/approvals/"+id+"/deny", "secret", `{"reason": "blocked by policy"}`); status != http.StatusOK {
+		t.Errorf("deny status = %d", status)
+	}
+	if resp := <-done; !resp.IsError || !strings.Contains(resp.Content[0].Text, "blocked by policy") {
+		t.Errorf("denied call = %+v", resp)
+	}
+}
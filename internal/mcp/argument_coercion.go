@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rodmcp/pkg/types"
+)
+
+// coercion describes one argument rewritten to fit the tool's schema
+type coercion struct {
+	Field string
+	From  string
+	To    string
+}
+
+func (c coercion) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Field, c.From, c.To)
+}
+
+// coerceArguments fixes the type mismatches LLM clients commonly make:
+// numbers and booleans sent as strings, a single value where an array is
+// expected, arrays and objects sent as JSON text, and stray whitespace
+// around enum values. Free-text strings are never trimmed, since spaces in
+// typed text, scripts or file content are significant. It returns args
+// unchanged when nothing needed fixing.
+func coerceArguments(schema types.ToolSchema, args map[string]interface{}) (map[string]interface{}, []coercion) {
+	if len(args) == 0 || len(schema.Properties) == 0 {
+		return args, nil
+	}
+	normalizedSchema, err := normalizeJSON(map[string]interface{}{
+		"type":       "object",
+		"properties": schema.Properties,
+	})
+	if err != nil {
+		return args, nil
+	}
+	normalizedArgs, err := normalizeJSON(args)
+	if err != nil {
+		return args, nil
+	}
+	rootSchema, _ := normalizedSchema.(map[string]interface{})
+
+	c := &coercer{}
+	coerced := c.coerce("", normalizedArgs, rootSchema)
+	if len(c.coercions) == 0 {
+		return args, nil
+	}
+	result, _ := coerced.(map[string]interface{})
+	return result, c.coercions
+}
+
+type coercer struct {
+	coercions []coercion
+}
+
+func (c *coercer) record(field string, from, to interface{}) {
+	c.coercions = append(c.coercions, coercion{Field: field, From: formatValue(from), To: formatValue(to)})
+}
+
+func (c *coercer) coerce(field string, value interface{}, schema map[string]interface{}) interface{} {
+	if value == nil || schema == nil {
+		return value
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if alternatives, ok := schema[key].([]interface{}); ok {
+			return c.coerceAlternatives(field, value, alternatives)
+		}
+	}
+
+	switch schemaTypeName(schema["type"], value) {
+	case "integer", "number":
+		if s, ok := value.(string); ok {
+			n, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+			if err == nil && !math.IsInf(n, 0) && !math.IsNaN(n) &&
+				(schema["type"] != "integer" || n == math.Trunc(n)) {
+				c.record(field, value, n)
+				return n
+			}
+		}
+
+	case "boolean":
+		if s, ok := value.(string); ok {
+			switch strings.ToLower(strings.TrimSpace(s)) {
+			case "true":
+				c.record(field, value, true)
+				return true
+			case "false":
+				c.record(field, value, false)
+				return false
+			}
+		}
+
+	case "string":
+		if s, ok := value.(string); ok && (schema["enum"] != nil || schema["pattern"] != nil) {
+			if trimmed := strings.TrimSpace(s); trimmed != s {
+				c.record(field, value, trimmed)
+				return trimmed
+			}
+		}
+
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		list, ok := value.([]interface{})
+		if !ok {
+			if parsed, isJSON := parseJSONText(value, '['); isJSON {
+				c.record(field, value, "array")
+				list = parsed.([]interface{})
+			} else {
+				c.record(field, value, []interface{}{value})
+				list = []interface{}{value}
+			}
+		}
+		for i, item := range list {
+			list[i] = c.coerce(fmt.Sprintf("%s[%d]", field, i), item, items)
+		}
+		return list
+
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			parsed, isJSON := parseJSONText(value, '{')
+			if !isJSON {
+				return value
+			}
+			c.record(field, value, "object")
+			obj = parsed.(map[string]interface{})
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		extra, _ := schema["additionalProperties"].(map[string]interface{})
+
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propSchema, ok := properties[name].(map[string]interface{})
+			if !ok {
+				propSchema = extra
+			}
+			path := name
+			if field != "" {
+				path = field + "." + name
+			}
+			obj[name] = c.coerce(path, obj[name], propSchema)
+		}
+		return obj
+	}
+	return value
+}
+
+// coerceAlternatives leaves a value alone when it already fits one of the
+// alternatives, and otherwise takes the first alternative it can be
+// coerced into
+func (c *coercer) coerceAlternatives(field string, value interface{}, alternatives []interface{}) interface{} {
+	for _, alternative := range alternatives {
+		if alt, ok := alternative.(map[string]interface{}); ok && matches(value, alt) {
+			return value
+		}
+	}
+	for _, alternative := range alternatives {
+		alt, ok := alternative.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		probe := &coercer{}
+		coerced := probe.coerce(field, value, alt)
+		if len(probe.coercions) > 0 && matches(coerced, alt) {
+			c.coercions = append(c.coercions, probe.coercions...)
+			return coerced
+		}
+	}
+	return value
+}
+
+// schemaTypeName picks the schema type to coerce towards. With a list of
+// types, a value that already fits one is left as it is.
+func schemaTypeName(schemaType interface{}, value interface{}) string {
+	switch t := schemaType.(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && typeNameMatches(value, s) {
+				return ""
+			}
+		}
+		for _, name := range t {
+			if s, ok := name.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// parseJSONText decodes a string holding a JSON array or object
+func parseJSONText(value interface{}, open byte) (interface{}, bool) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, false
+	}
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != open {
+		return nil, false
+	}
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+		return nil, false
+	}
+	switch parsed.(type) {
+	case []interface{}:
+		return parsed, open == '['
+	case map[string]interface{}:
+		return parsed, open == '{'
+	}
+	return nil, false
+}
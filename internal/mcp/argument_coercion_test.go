@@ -0,0 +1,128 @@
+package mcp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+func TestCoerceArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want map[string]interface{}
+	}{
+		{
+			name: "integer from string",
+			args: map[string]interface{}{"url": "x", "quality": " 80 "},
+			want: map[string]interface{}{"url": "x", "quality": float64(80)},
+		},
+		{
+			name: "boolean from string",
+			args: map[string]interface{}{"url": "x", "full": "True"},
+			want: map[string]interface{}{"url": "x", "full": true},
+		},
+		{
+			name: "single value to array",
+			args: map[string]interface{}{"url": "x", "tags": "news"},
+			want: map[string]interface{}{"url": "x", "tags": []interface{}{"news"}},
+		},
+		{
+			name: "array from JSON text",
+			args: map[string]interface{}{"url": "x", "tags": `["a", "b"]`},
+			want: map[string]interface{}{"url": "x", "tags": []interface{}{"a", "b"}},
+		},
+		{
+			name: "object from JSON text",
+			args: map[string]interface{}{"url": "x", "selectors": `{"title": "h1"}`},
+			want: map[string]interface{}{"url": "x", "selectors": map[string]interface{}{"title": "h1"}},
+		},
+		{
+			name: "enum value trimmed",
+			args: map[string]interface{}{"url": "x", "format": "png\n"},
+			want: map[string]interface{}{"url": "x", "format": "png"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, coercions := coerceArguments(testSchema(), tt.args)
+			if len(coercions) == 0 {
+				t.Fatal("expected a coercion to be reported")
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			if err := validateArguments("t", testSchema(), got); err != nil {
+				t.Errorf("coerced arguments still invalid: %v", err)
+			}
+		})
+	}
+}
+
+func TestCoerceArgumentsLeavesValidInputAlone(t *testing.T) {
+	unchanged := []map[string]interface{}{
+		{"url": "  padded free text  "}, // whitespace in free text is significant
+		{"url": "x", "row": "5"},        // already fits the string alternative
+		{"url": "x", "quality": "eighty"},
+		{"url": "x", "quality": "80.5"}, // not an integer
+		{"url": "x", "full": "yes"},
+		{"url": "x", "format": nil},
+	}
+	for _, args := range unchanged {
+		got, coercions := coerceArguments(testSchema(), args)
+		if len(coercions) != 0 {
+			t.Errorf("%v: unexpected coercions %v", args, coercions)
+		}
+		if !reflect.DeepEqual(got, args) {
+			t.Errorf("%v: arguments changed to %v", args, got)
+		}
+	}
+}
+
+func TestCoerceArgumentsAlternatives(t *testing.T) {
+	schema := types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"count": map[string]interface{}{
+				"anyOf": []map[string]interface{}{{"type": "integer"}, {"type": "boolean"}},
+			},
+		},
+	}
+	got, coercions := coerceArguments(schema, map[string]interface{}{"count": "3"})
+	if len(coercions) != 1 || got["count"] != float64(3) {
+		t.Errorf("got %v with coercions %v", got, coercions)
+	}
+}
+
+func TestCoreCallToolCoercesArguments(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := NewRecordingTestTool("record", testSchema())
+	c.RegisterTool(tool)
+
+	result, err := c.callTool(context.Background(), "record", map[string]interface{}{
+		"url":     "https://example.com",
+		"quality": "80",
+		"tags":    "news",
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result %+v", result)
+	}
+	args := tool.LastArgs()
+	if args["quality"] != float64(80) || !reflect.DeepEqual(args["tags"], []interface{}{"news"}) {
+		t.Errorf("tool received %v", args)
+	}
+
+	if _, err := c.callTool(context.Background(), "record", map[string]interface{}{
+		"url":     "https://example.com",
+		"quality": "eighty",
+	}); err == nil {
+		t.Error("expected uncoercible argument to be rejected")
+	}
+}
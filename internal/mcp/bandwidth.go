@@ -0,0 +1,119 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"rodmcp/internal/bandwidth"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// SetBandwidthMeter charges each tool call with the traffic moved while it
+// ran. The same meter should be given to the browser manager and the HTTP
+// tools so there is traffic to charge.
+func (c *core) SetBandwidthMeter(meter *bandwidth.Meter) {
+	c.toolsMutex.Lock()
+	c.bandwidth = meter
+	c.toolsMutex.Unlock()
+}
+
+// BandwidthMeter returns the meter set by SetBandwidthMeter, or nil
+func (c *core) BandwidthMeter() *bandwidth.Meter {
+	c.toolsMutex.RLock()
+	defer c.toolsMutex.RUnlock()
+	return c.bandwidth
+}
+
+// meterCall starts charging traffic to a call of tool; the returned
+// function ends it and logs what the call moved
+func (c *core) meterCall(tool string) func() {
+	meter := c.BandwidthMeter()
+	if meter == nil {
+		return func() {}
+	}
+	end := meter.BeginCall(tool)
+	return func() {
+		usage := end()
+		if usage.BytesSent == 0 && usage.BytesReceived == 0 {
+			return
+		}
+		c.logger.WithComponent(c.component).Debug("Tool bandwidth",
+			zap.String("tool", tool),
+			zap.Int64("bytes_sent", usage.BytesSent),
+			zap.Int64("bytes_received", usage.BytesReceived),
+			zap.Int64("requests", usage.Requests),
+			zap.Bool("overlapped", usage.Overlapped))
+	}
+}
+
+// executeMetered runs a tool, charging it with the traffic moved until it
+// returns or panics
+func (c *core) executeMetered(ctx context.Context, tool Tool, args map[string]interface{}) (*types.CallToolResponse, error) {
+	defer c.meterCall(tool.Name())()
+	return executeTool(ctx, tool, args)
+}
+
+// handleMetrics serves the bandwidth counters and job queue depth in the
+// Prometheus text format
+func (s *HTTPServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	writeBandwidthMetrics(w, s.BandwidthMeter().Stats())
+	fmt.Fprintf(w, "# HELP rodmcp_tool_calls_in_flight Tool calls currently executing.\n")
+	fmt.Fprintf(w, "# TYPE rodmcp_tool_calls_in_flight gauge\n")
+	fmt.Fprintf(w, "rodmcp_tool_calls_in_flight %d\n", s.InFlight())
+	writeJobQueueMetrics(w, s.JobQueueStats())
+}
+
+// writeBandwidthMetrics writes stats as Prometheus counters by domain and
+// by tool
+func writeBandwidthMetrics(w io.Writer, stats bandwidth.Stats) {
+	series := []struct {
+		name, help string
+		value      func(bandwidth.Usage) int64
+	}{
+		{"rodmcp_bandwidth_sent_bytes_total", "Bytes sent", func(u bandwidth.Usage) int64 { return u.BytesSent }},
+		{"rodmcp_bandwidth_received_bytes_total", "Bytes received", func(u bandwidth.Usage) int64 { return u.BytesReceived }},
+		{"rodmcp_bandwidth_requests_total", "Requests made", func(u bandwidth.Usage) int64 { return u.Requests }},
+	}
+	domains := stats.TopDomains(0)
+	sort.Strings(domains)
+	tools := make([]string, 0, len(stats.Tools))
+	for tool := range stats.Tools {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	for _, s := range series {
+		fmt.Fprintf(w, "# HELP %s %s, by destination domain.\n", s.name, s.help)
+		fmt.Fprintf(w, "# TYPE %s counter\n", s.name)
+		for _, domain := range domains {
+			fmt.Fprintf(w, "%s{domain=\"%s\"} %d\n", s.name, escapeLabel(domain), s.value(stats.Domains[domain]))
+		}
+		toolName := strings.Replace(s.name, "rodmcp_bandwidth_", "rodmcp_tool_bandwidth_", 1)
+		fmt.Fprintf(w, "# HELP %s %s while each tool ran.\n", toolName, s.help)
+		fmt.Fprintf(w, "# TYPE %s counter\n", toolName)
+		for _, tool := range tools {
+			fmt.Fprintf(w, "%s{tool=\"%s\"} %d\n", toolName, escapeLabel(tool), s.value(stats.Tools[tool].Usage))
+		}
+	}
+	fmt.Fprintf(w, "# HELP rodmcp_tool_calls_total Tool calls metered for bandwidth.\n")
+	fmt.Fprintf(w, "# TYPE rodmcp_tool_calls_total counter\n")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "rodmcp_tool_calls_total{tool=\"%s\"} %d\n", escapeLabel(tool), stats.Tools[tool].Calls)
+	}
+}
+
+// escapeLabel quotes a Prometheus label value
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
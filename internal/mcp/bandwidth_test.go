@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/bandwidth"
+	"rodmcp/internal/logger"
+)
+
+func TestCoreChargesToolCallsWithTraffic(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	meter := bandwidth.NewMeter()
+	c.SetBandwidthMeter(meter)
+
+	blocking := NewBlockingTestTool("fetch")
+	c.RegisterTool(blocking)
+	c.RegisterTool(NewPanicTestTool("panicky"))
+	go func() {
+		<-blocking.Started()
+		meter.Record("example.com", 200, 4000)
+		blocking.Release()
+	}()
+	if _, err := c.callTool(context.Background(), "fetch", nil); err != nil {
+		t.Fatal(err)
+	}
+	c.callTool(context.Background(), "panicky", nil)
+
+	stats := meter.Stats()
+	if fetch := stats.Tools["fetch"]; fetch == nil || fetch.Calls != 1 || fetch.BytesReceived != 4000 {
+		t.Errorf("fetch = %+v", fetch)
+	}
+	// A call that panics is still metered and ended
+	if panicky := stats.Tools["panicky"]; panicky == nil || panicky.Calls != 1 || panicky.BytesReceived != 0 {
+		t.Errorf("panicky = %+v", panicky)
+	}
+}
+
+func TestWriteBandwidthMetrics(t *testing.T) {
+	meter := bandwidth.NewMeter()
+	end := meter.BeginCall("http_request")
+	meter.Record("api.example.com", 120, 900)
+	end()
+
+	var out bytes.Buffer
+	writeBandwidthMetrics(&out, meter.Stats())
+	for _, want := range []string{
+		"# TYPE rodmcp_bandwidth_received_bytes_total counter",
+		`rodmcp_bandwidth_received_bytes_total{domain="api.example.com"} 900`,
+		`rodmcp_tool_bandwidth_sent_bytes_total{tool="http_request"} 120`,
+		`rodmcp_tool_calls_total{tool="http_request"} 1`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in\n%s", want, out.String())
+		}
+	}
+	if got := escapeLabel(`a"b\c`); got != `a\"b\\c` {
+		t.Errorf("escapeLabel = %s", got)
+	}
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"rodmcp/pkg/types"
+)
+
+// clientRequestPrefix marks the IDs of requests the server sends the client
+const clientRequestPrefix = "rodmcp-req-"
+
+// clientRequests matches the client's responses to requests the server sent
+type clientRequests struct {
+	mutex   sync.Mutex
+	seq     int
+	waiting map[string]chan types.JSONRPCResponse
+}
+
+func (r *clientRequests) add() (string, chan types.JSONRPCResponse) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.seq++
+	id := clientRequestPrefix + strconv.Itoa(r.seq)
+	ch := make(chan types.JSONRPCResponse, 1)
+	if r.waiting == nil {
+		r.waiting = make(map[string]chan types.JSONRPCResponse)
+	}
+	r.waiting[id] = ch
+	return id, ch
+}
+
+func (r *clientRequests) remove(id string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.waiting, id)
+}
+
+// deliver hands a response to the request waiting for it, reporting false
+// when nothing is
+func (r *clientRequests) deliver(resp types.JSONRPCResponse) bool {
+	id, _ := resp.ID.(string)
+	r.mutex.Lock()
+	ch, ok := r.waiting[id]
+	delete(r.waiting, id)
+	r.mutex.Unlock()
+	if ok {
+		ch <- resp
+	}
+	return ok
+}
+
+// request sends method to the client and decodes its result into result,
+// waiting until the client answers or ctx ends. Tool calls run outside the
+// message loop, so a tool can wait here while the answer is read.
+func (s *Server) request(ctx context.Context, method string, params, result interface{}) error {
+	id, ch := s.clientRequests.add()
+	defer s.clientRequests.remove(id)
+
+	if err := s.writeMessage(types.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params}); err != nil {
+		return fmt.Errorf("failed to send %s: %w", method, err)
+	}
+
+	select {
+	case resp := <-ch:
+		if resp.Error != nil {
+			return fmt.Errorf("client rejected %s: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+		}
+		data, err := json.Marshal(resp.Result)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("no answer to %s: %w", method, ctx.Err())
+	}
+}
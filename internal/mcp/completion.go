@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// maxCompletionValues is the most values one completion response may
+	// carry under the MCP specification
+	maxCompletionValues = 100
+
+	// completionTimeout bounds a completion source; completions are typed
+	// interactively, so a slow source is better skipped than waited on
+	completionTimeout = 5 * time.Second
+)
+
+var errUnsupportedReference = errors.New("unsupported completion reference")
+
+// CompletionFunc suggests values for a tool argument. value is what the
+// user has typed so far and args holds other arguments already filled in.
+// Suggestions not starting with value are dropped by the caller.
+type CompletionFunc func(value string, args map[string]string) ([]string, error)
+
+// anyTool registers a completion for an argument of every tool
+const anyTool = "*"
+
+// RegisterCompletion adds a completion source for argument of tool. Use
+// "*" as tool to complete the argument wherever it appears; a source
+// registered for a specific tool takes precedence.
+func (c *core) RegisterCompletion(tool, argument string, fn CompletionFunc) {
+	c.toolsMutex.Lock()
+	defer c.toolsMutex.Unlock()
+	c.completions[tool+"/"+argument] = fn
+}
+
+// complete answers a completion/complete request for a tool argument.
+// Arguments without a registered source fall back to their schema's enum
+// or boolean values. Errors wrap errUnsupportedReference or
+// errToolNotFound when those are the cause.
+func (c *core) complete(ctx context.Context, req types.CompleteRequest) (types.Completion, error) {
+	if req.Ref.Type != "ref/tool" {
+		return types.Completion{}, fmt.Errorf("%w: %q (rodmcp completes tool arguments with ref/tool)",
+			errUnsupportedReference, req.Ref.Type)
+	}
+
+	c.toolsMutex.RLock()
+	tool, exists := c.tools[req.Ref.Name]
+	fn := c.completions[req.Ref.Name+"/"+req.Argument.Name]
+	if fn == nil {
+		fn = c.completions[anyTool+"/"+req.Argument.Name]
+	}
+	c.toolsMutex.RUnlock()
+	if !exists {
+		return types.Completion{}, fmt.Errorf("%w: %s", errToolNotFound, req.Ref.Name)
+	}
+
+	prop, known := tool.InputSchema().Properties[req.Argument.Name].(map[string]interface{})
+	if !known {
+		return types.Completion{Values: []string{}}, nil
+	}
+
+	var candidates []string
+	if fn != nil {
+		var contextArgs map[string]string
+		if req.Context != nil {
+			contextArgs = req.Context.Arguments
+		}
+		values, err := c.runCompletion(ctx, fn, req.Argument.Value, contextArgs)
+		if err != nil {
+			// A failing source means no suggestions, not a failed request
+			c.logger.WithComponent(c.component).Debug("Completion source failed",
+				zap.String("tool", req.Ref.Name),
+				zap.String("argument", req.Argument.Name),
+				zap.Error(err))
+		}
+		candidates = values
+	} else {
+		candidates = schemaCompletions(prop)
+	}
+
+	return completionResult(candidates, req.Argument.Value), nil
+}
+
+// runCompletion calls fn with panic protection, giving up after
+// completionTimeout
+func (c *core) runCompletion(ctx context.Context, fn CompletionFunc, value string, args map[string]string) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	type completionOutcome struct {
+		values []string
+		err    error
+	}
+	done := make(chan completionOutcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- completionOutcome{err: fmt.Errorf("completion panicked: %v", r)}
+			}
+		}()
+		values, err := fn(value, args)
+		done <- completionOutcome{values: values, err: err}
+	}()
+
+	select {
+	case outcome := <-done:
+		return outcome.values, outcome.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("completion timed out: %w", ctx.Err())
+	}
+}
+
+// schemaCompletions lists the values a schema allows outright
+func schemaCompletions(prop map[string]interface{}) []string {
+	if enum := toStringSlice(prop["enum"]); len(enum) > 0 {
+		return enum
+	}
+	if prop["type"] == "boolean" {
+		return []string{"true", "false"}
+	}
+	return nil
+}
+
+func toStringSlice(value interface{}) []string {
+	switch typed := value.(type) {
+	case []string:
+		return typed
+	case []interface{}:
+		out := make([]string, 0, len(typed))
+		for _, item := range typed {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	}
+	return nil
+}
+
+// completionResult keeps the candidates starting with value (ignoring
+// case) in their original order, drops duplicates and caps the list at
+// maxCompletionValues
+func completionResult(candidates []string, value string) types.Completion {
+	prefix := strings.ToLower(value)
+	seen := make(map[string]bool, len(candidates))
+	values := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		if seen[candidate] || !strings.HasPrefix(strings.ToLower(candidate), prefix) {
+			continue
+		}
+		seen[candidate] = true
+		values = append(values, candidate)
+	}
+
+	completion := types.Completion{Values: values, Total: len(values)}
+	if len(values) > maxCompletionValues {
+		completion.Values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	return completion
+}
@@ -0,0 +1,179 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+func completeRequest(tool, argument, value string) types.CompleteRequest {
+	return types.CompleteRequest{
+		Ref:      types.CompletionReference{Type: "ref/tool", Name: tool},
+		Argument: types.CompletionArgument{Name: argument, Value: value},
+	}
+}
+
+func TestCoreComplete(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(NewRecordingTestTool("record", testSchema()))
+	c.RegisterCompletion("*", "url", func(value string, args map[string]string) ([]string, error) {
+		return []string{"https://b.example", "https://a.example", "ftp://x", "https://a.example"}, nil
+	})
+
+	// Registered source, filtered by prefix, order kept, duplicates dropped
+	got, err := c.complete(context.Background(), completeRequest("record", "url", "HTTPS"))
+	if err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+	if want := []string{"https://b.example", "https://a.example"}; !reflect.DeepEqual(got.Values, want) || got.Total != 2 {
+		t.Errorf("got %+v, want %v", got, want)
+	}
+
+	// A tool-specific source takes precedence over "*"
+	c.RegisterCompletion("record", "url", func(string, map[string]string) ([]string, error) {
+		return []string{"https://specific.example"}, nil
+	})
+	got, _ = c.complete(context.Background(), completeRequest("record", "url", ""))
+	if !reflect.DeepEqual(got.Values, []string{"https://specific.example"}) {
+		t.Errorf("tool-specific source not used: %v", got.Values)
+	}
+
+	// Enum and boolean parameters complete from the schema
+	got, _ = c.complete(context.Background(), completeRequest("record", "format", "j"))
+	if !reflect.DeepEqual(got.Values, []string{"jpeg"}) {
+		t.Errorf("enum completion = %v", got.Values)
+	}
+	got, _ = c.complete(context.Background(), completeRequest("record", "full", ""))
+	if !reflect.DeepEqual(got.Values, []string{"true", "false"}) {
+		t.Errorf("boolean completion = %v", got.Values)
+	}
+
+	// Unknown arguments have no suggestions
+	got, err = c.complete(context.Background(), completeRequest("record", "nope", ""))
+	if err != nil || len(got.Values) != 0 {
+		t.Errorf("unknown argument: %+v %v", got, err)
+	}
+}
+
+func TestCoreCompleteErrors(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(NewRecordingTestTool("record", testSchema()))
+
+	req := completeRequest("record", "url", "")
+	req.Ref.Type = "ref/prompt"
+	if _, err := c.complete(context.Background(), req); !errors.Is(err, errUnsupportedReference) {
+		t.Errorf("expected errUnsupportedReference, got %v", err)
+	}
+	if _, err := c.complete(context.Background(), completeRequest("missing", "url", "")); !errors.Is(err, errToolNotFound) {
+		t.Errorf("expected errToolNotFound, got %v", err)
+	}
+
+	// A panicking source yields no suggestions rather than an error
+	c.RegisterCompletion("record", "url", func(string, map[string]string) ([]string, error) {
+		panic("source exploded")
+	})
+	got, err := c.complete(context.Background(), completeRequest("record", "url", ""))
+	if err != nil || len(got.Values) != 0 {
+		t.Errorf("panicking source: %+v %v", got, err)
+	}
+}
+
+func TestCompletionResultCapped(t *testing.T) {
+	var candidates []string
+	for i := 0; i < maxCompletionValues+20; i++ {
+		candidates = append(candidates, fmt.Sprintf("page_%03d", i))
+	}
+	got := completionResult(candidates, "page_")
+	if len(got.Values) != maxCompletionValues || got.Total != maxCompletionValues+20 || !got.HasMore {
+		t.Errorf("got %d values, total %d, hasMore %v", len(got.Values), got.Total, got.HasMore)
+	}
+}
+
+func TestHTTPServerHandleComplete(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 0)
+	server.RegisterTool(NewRecordingTestTool("record", testSchema()))
+
+	post := func(req types.CompleteRequest) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(req)
+		rr := httptest.NewRecorder()
+		server.handleComplete(rr, httptest.NewRequest(http.MethodPost, "/mcp/completion/complete", bytes.NewReader(body)))
+		return rr
+	}
+
+	rr := post(completeRequest("record", "format", "p"))
+	var result types.CompleteResult
+	if err := json.Unmarshal(rr.Body.Bytes(), &result); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", rr.Code, rr.Body.String())
+	}
+	if !reflect.DeepEqual(result.Completion.Values, []string{"png"}) {
+		t.Errorf("values = %v", result.Completion.Values)
+	}
+
+	if rr := post(completeRequest("missing", "url", "")); rr.Code != http.StatusNotFound {
+		t.Errorf("missing tool status = %d", rr.Code)
+	}
+}
+
+func TestStdioCompletionCapability(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+	server.RegisterTool(NewRecordingTestTool("record", testSchema()))
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+	defer inWriter.Close()
+	go server.Start()
+	defer server.Stop()
+
+	responses := bufio.NewScanner(outReader)
+	readResult := func() map[string]interface{} {
+		t.Helper()
+		lines := make(chan string, 1)
+		go func() {
+			if responses.Scan() {
+				lines <- responses.Text()
+			}
+		}()
+		select {
+		case line := <-lines:
+			var msg struct {
+				Result map[string]interface{} `json:"result"`
+			}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("Invalid response %q: %v", line, err)
+			}
+			return msg.Result
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+			return nil
+		}
+	}
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	capabilities, _ := readResult()["capabilities"].(map[string]interface{})
+	if _, ok := capabilities["completions"]; !ok {
+		t.Errorf("completions capability not advertised: %v", capabilities)
+	}
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":2,"method":"completion/complete","params":{"ref":{"type":"ref/tool","name":"record"},"argument":{"name":"format","value":"j"}}}`)
+	completion, _ := readResult()["completion"].(map[string]interface{})
+	if values, _ := completion["values"].([]interface{}); len(values) != 1 || values[0] != "jpeg" {
+		t.Errorf("completion = %v", completion)
+	}
+}
@@ -0,0 +1,475 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"rodmcp/internal/bandwidth"
+	"rodmcp/internal/circuitbreaker"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// toolCallTimeout caps how long a client waits for a tool call when no
+// timeout is configured for the tool (see SetToolTimeouts)
+const toolCallTimeout = 30 * time.Second
+
+var (
+	errToolNotFound = errors.New("tool not found")
+	errDraining     = errors.New("server is shutting down")
+	errToolTimeout  = errors.New("tool execution timed out")
+)
+
+type Tool interface {
+	Name() string
+	Description() string
+	InputSchema() types.ToolSchema
+	Execute(args map[string]interface{}) (*types.CallToolResponse, error)
+}
+
+type BrowserHealthChecker interface {
+	CheckHealth() error
+	EnsureHealthy() error
+}
+
+// core is the transport-independent part of an MCP server: the tool
+// registry, tool call dispatch, drain tracking, browser health monitoring
+// and delivery of log messages and notifications. Server and HTTPServer
+// embed it so a feature added here reaches both transports.
+type core struct {
+	logger         *logger.Logger
+	component      string // log component, "mcp" or "http-mcp"
+	tools          map[string]Tool
+	toolsMutex     sync.RWMutex
+	initialized    atomic.Bool
+	version        types.MCPVersion
+	info           types.ServerInfo
+	circuitBreaker *circuitbreaker.MultiLevelCircuitBreaker
+	browserManager BrowserHealthChecker
+
+	// Tool calls in progress, tracked so shutdown can drain them
+	calls callTracker
+
+	// compat keeps renamed tool parameters working under their old names
+	compat bool
+
+	// Per-tool call timeouts from the config file; guarded by toolsMutex
+	timeouts ToolTimeouts
+
+	// dryRun plans every call to a PlanningTool instead of running it
+	dryRun bool
+
+	// Calls held for operator approval (see SetApprovalPolicy)
+	approvals approvalGate
+
+	// elicit asks the client's user for input; nil when the transport
+	// cannot send the client requests
+	elicit func(ctx context.Context, req types.ElicitRequest) (*types.ElicitResult, error)
+
+	// Time spent waiting for the client's user or model, which tool
+	// timeouts leave out
+	userWaits userWaits
+
+	// Argument completion sources keyed by "tool/argument"; guarded by
+	// toolsMutex
+	completions map[string]CompletionFunc
+
+	// push delivers a notification to the client; nil when the transport
+	// has no way to reach the client unprompted
+	push func(method string, params interface{}) error
+
+	// Traffic meter charged per tool call; guarded by toolsMutex
+	bandwidth *bandwidth.Meter
+
+	// Workers HTTP tool calls wait for (see SetJobQueue)
+	jobs jobQueue
+
+	// Which connections may only watch (see SetObserverMode)
+	observers observerPolicy
+}
+
+func newCore(log *logger.Logger, component, name string) *core {
+	circuitBreaker := circuitbreaker.NewMultiLevelCircuitBreaker()
+	circuitBreaker.BrowserCircuitBreaker.CircuitBreaker.OnStateChange(func(from, to circuitbreaker.State) {
+		log.WithComponent("circuit-breaker").Warn("Browser circuit breaker state changed",
+			zap.String("from", from.String()),
+			zap.String("to", to.String()))
+	})
+	circuitBreaker.NetworkCircuitBreaker.CircuitBreaker.OnStateChange(func(from, to circuitbreaker.State) {
+		log.WithComponent("circuit-breaker").Warn("Network circuit breaker state changed",
+			zap.String("from", from.String()),
+			zap.String("to", to.String()))
+	})
+
+	c := &core{
+		logger:      log,
+		component:   component,
+		tools:       make(map[string]Tool),
+		completions: make(map[string]CompletionFunc),
+		version:     types.CurrentMCPVersion,
+		info: types.ServerInfo{
+			Name:    name,
+			Version: "1.0.0",
+		},
+		circuitBreaker: circuitBreaker,
+		compat:         true,
+	}
+	// Secrets tools take stay out of every log entry for their calls
+	log.SetArgumentRedactor(c.redactArguments)
+	return c
+}
+
+func (c *core) RegisterTool(tool Tool) {
+	c.toolsMutex.Lock()
+	defer c.toolsMutex.Unlock()
+	c.tools[tool.Name()] = tool
+	c.logger.WithComponent(c.component).Info("Tool registered",
+		zap.String("tool", tool.Name()))
+}
+
+// Tools returns the registered tools sorted by name
+func (c *core) Tools() []Tool {
+	c.toolsMutex.RLock()
+	defer c.toolsMutex.RUnlock()
+	tools := make([]Tool, 0, len(c.tools))
+	for _, tool := range c.tools {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name() < tools[j].Name()
+	})
+	return tools
+}
+
+func (c *core) toolCount() int {
+	c.toolsMutex.RLock()
+	defer c.toolsMutex.RUnlock()
+	return len(c.tools)
+}
+
+// toolList describes the registered tools for a tools/list response
+func (c *core) toolList() []types.Tool {
+	registered := c.Tools()
+	tools := make([]types.Tool, 0, len(registered))
+	for _, tool := range registered {
+		meta := ToolMeta(tool)
+		tools = append(tools, types.Tool{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			InputSchema: withDryRunArgument(tool, tool.InputSchema()),
+			Meta:        &meta,
+		})
+	}
+	return tools
+}
+
+// SetBrowserManager enables browser health monitoring and readiness checks
+func (c *core) SetBrowserManager(browserMgr BrowserHealthChecker) {
+	c.browserManager = browserMgr
+	c.logger.WithComponent(c.component).Info("Browser manager registered for health monitoring")
+}
+
+// SetCompatibilityMode controls whether old names of renamed tool
+// parameters are accepted with a warning (the default) or rejected
+func (c *core) SetCompatibilityMode(enabled bool) {
+	c.compat = enabled
+}
+
+// BeginDrain stops the server from accepting new tool calls. Calls already
+// running are left to finish; see WaitForInFlight.
+func (c *core) BeginDrain() {
+	c.calls.drain()
+	c.denyPendingApprovals("server is shutting down")
+	c.logger.WithComponent(c.component).Info("Draining tool calls",
+		zap.Int("in_flight", c.calls.count()))
+}
+
+// WaitForInFlight blocks until every running tool call has returned or ctx
+// expires. Calls that outlived their response timeout are still counted,
+// since they may be writing files or driving the browser.
+func (c *core) WaitForInFlight(ctx context.Context) error {
+	return c.calls.wait(ctx)
+}
+
+// InFlight returns the number of tool calls currently executing
+func (c *core) InFlight() int {
+	return c.calls.count()
+}
+
+// CallTool runs a registered tool the way a client's tools/call would, with
+// the same argument checks, approval gate and timeouts. It lets features
+// such as workflows drive other tools from inside the server.
+func (c *core) CallTool(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return c.callTool(context.Background(), name, args)
+}
+
+// CallToolContext is CallTool for calls made on behalf of a running call,
+// such as the steps of a batch: they end when ctx does, if not before, and
+// run in its scope. They skip the job queue, since the call making them
+// already holds a worker.
+func (c *core) CallToolContext(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return c.callTool(context.WithValue(ctx, priorityKey{}, nil), name, args)
+}
+
+// callTool runs a registered tool, giving up after its callTimeout or when
+// ctx ends. The tool keeps running in the background after a timeout and
+// stays counted as in flight until it returns; a ContextTool is told to
+// stop. Renamed parameters are
+// resolved and loosely typed arguments coerced to the tool's InputSchema
+// first; arguments that still do not fit are rejected with an
+// *ArgumentsError before the tool runs. Dry-run calls are planned rather
+// than executed (see PlanningTool). Other errors wrap errToolNotFound,
+// errObserverDenied, errQueueFull, errDraining or errToolTimeout when those
+// are the cause.
+func (c *core) callTool(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+	c.toolsMutex.RLock()
+	tool, exists := c.tools[name]
+	c.toolsMutex.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", errToolNotFound, name)
+	}
+	dryRun, args := c.takeDryRun(tool, args)
+	args, warnings, err := resolveLegacyArguments(tool, args, c.compat)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		c.logger.WithComponent(c.component).Warn("Deprecated tool usage",
+			zap.String("tool", name),
+			zap.String("warning", warning))
+		if c.push != nil {
+			c.SendLogMessage("warning", warning, map[string]interface{}{"tool": name})
+		}
+	}
+
+	schema := tool.InputSchema()
+	args, coercions := coerceArguments(schema, args)
+	if len(coercions) > 0 {
+		fixed := make([]string, 0, len(coercions))
+		for _, coerced := range coercions {
+			fixed = append(fixed, coerced.String())
+		}
+		c.logger.WithComponent(c.component).Info("Coerced tool arguments",
+			zap.String("tool", name),
+			zap.Strings("coercions", fixed))
+	}
+	if err := validateArguments(name, schema, args); err != nil {
+		return nil, err
+	}
+	if err := c.checkScope(ctx, tool, args); err != nil {
+		c.logger.WithComponent(c.component).Warn("Observer call refused",
+			zap.String("tool", name))
+		return nil, err
+	}
+	if !dryRun {
+		if refused := c.awaitApproval(ctx, name, args); refused != nil {
+			return refused, nil
+		}
+	}
+
+	// Time spent waiting for a worker counts against the call's timeout
+	timeout := c.callTimeout(tool, args)
+	release := func() {}
+	if priority, queued := jobPriority(ctx); queued && !dryRun {
+		waitStart := time.Now()
+		queueCtx, cancelQueue := context.WithTimeout(ctx, timeout)
+		release, err = c.jobs.acquire(queueCtx, priority)
+		cancelQueue()
+		switch {
+		case errors.Is(err, errQueueFull):
+			c.logger.WithComponent(c.component).Warn("Job queue full",
+				zap.String("tool", name),
+				zap.String("priority", priority.String()))
+			return nil, err
+		case err != nil && ctx.Err() == nil:
+			return nil, fmt.Errorf("%w: tool '%s' waited %s for a worker", errToolTimeout, name, timeout)
+		case err != nil:
+			return nil, fmt.Errorf("tool '%s' cancelled while queued: %v", name, ctx.Err())
+		}
+		timeout -= time.Since(waitStart)
+	}
+
+	if !c.calls.begin() {
+		release()
+		return nil, errDraining
+	}
+
+	c.logger.WithComponent(c.component).Debug("Executing tool",
+		zap.String("tool", name))
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	// A ContextTool's work ends with the call, not after it
+	callCtx, cancelCall := context.WithTimeout(ctx, timeout)
+	defer cancelCall()
+	waitedBefore, _ := c.userWaits.waited()
+	var extended time.Duration
+
+	type toolResult struct {
+		result *types.CallToolResponse
+		err    error
+	}
+	resultChan := make(chan toolResult, 1)
+	go func() {
+		defer c.calls.end()
+		// The worker is held until the tool returns, even after a timeout
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				c.logger.WithComponent(c.component).Error("Tool panicked",
+					zap.String("tool", name),
+					zap.Any("panic", r))
+				resultChan <- toolResult{err: fmt.Errorf("tool '%s' panicked: %v", name, r)}
+			}
+		}()
+		var result *types.CallToolResponse
+		var err error
+		if dryRun {
+			result, err = c.planCall(callCtx, tool, args)
+		} else {
+			result, err = c.executeMetered(callCtx, tool, args)
+		}
+		resultChan <- toolResult{result: result, err: err}
+	}()
+
+	for {
+		select {
+		case res := <-resultChan:
+			if res.result != nil && len(warnings) > 0 {
+				// Put the warnings in the result too: the model reading it is
+				// the one that has to update its calls
+				for _, warning := range warnings {
+					res.result.Content = append(res.result.Content, types.ToolContent{
+						Type: "text",
+						Text: "Warning: " + warning,
+					})
+				}
+			}
+			return res.result, res.err
+		case <-timer.C:
+			// Time spent waiting on the client's user or model does not count
+			waited, asking := c.userWaits.waited()
+			if extra := waited - waitedBefore - extended; extra > 0 {
+				extended += extra
+				timer.Reset(extra)
+				continue
+			} else if asking {
+				timer.Reset(time.Second)
+				continue
+			}
+			// callCtx expires with the timer, give or take the moment between
+			// arming them; wait for it so a ContextTool is stopped by its
+			// deadline rather than by the cancel on return
+			<-callCtx.Done()
+			c.logger.WithComponent(c.component).Warn("Tool execution timed out",
+				zap.String("tool", name),
+				zap.Duration("timeout", timeout))
+			return nil, fmt.Errorf("%w: tool '%s' execution timed out after %s", errToolTimeout, name, timeout)
+		case <-ctx.Done():
+			c.logger.WithComponent(c.component).Warn("Tool execution cancelled",
+				zap.String("tool", name),
+				zap.Error(ctx.Err()))
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("%w: tool '%s' execution timed out: %v", errToolTimeout, name, ctx.Err())
+			}
+			return nil, fmt.Errorf("tool '%s' execution cancelled: %v", name, ctx.Err())
+		}
+	}
+}
+
+// checkBrowser runs the browser health check, treating a check that hangs
+// or panics as a failure
+func (c *core) checkBrowser(timeout time.Duration) error {
+	if c.browserManager == nil {
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("health check panicked: %v", r)
+			}
+		}()
+		done <- c.browserManager.CheckHealth()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return errors.New("health check timed out")
+	}
+}
+
+// runHealthMonitor keeps the browser healthy through the circuit breaker
+// until ctx ends
+func (c *core) runHealthMonitor(ctx context.Context) {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.browserManager != nil {
+				err := c.circuitBreaker.ExecuteBrowserOperation(func() error {
+					return c.browserManager.EnsureHealthy()
+				})
+				if err != nil {
+					// Health check failures are handled by the circuit breaker
+					// Log at debug level to avoid noise
+					c.logger.WithComponent(c.component).Debug("Browser health check failed",
+						zap.Error(err))
+				}
+			}
+
+			c.logger.WithComponent(c.component).Debug("Circuit breaker status",
+				zap.Any("circuit_breaker_stats", c.circuitBreaker.GetOverallStats()))
+		}
+	}
+}
+
+// SendLogMessage sends a notifications/message to the client, or logs it
+// locally when the transport cannot push
+func (c *core) SendLogMessage(level string, message string, data map[string]interface{}) error {
+	if c.push == nil {
+		log := c.logger.WithComponent(c.component)
+		switch level {
+		case "error":
+			log.Error(message, zap.Any("data", data))
+		case "warn", "warning":
+			log.Warn(message, zap.Any("data", data))
+		case "debug":
+			log.Debug(message, zap.Any("data", data))
+		default:
+			log.Info(message, zap.Any("data", data))
+		}
+		return nil
+	}
+
+	logData, _ := json.Marshal(data)
+	return c.push("notifications/message", types.LoggingMessage{
+		Level:  level,
+		Data:   json.RawMessage(logData),
+		Logger: "rodmcp",
+	})
+}
+
+// SendNotification sends a JSON-RPC notification with the given method and
+// params, or logs it locally when the transport cannot push
+func (c *core) SendNotification(method string, params interface{}) error {
+	if c.push == nil {
+		c.logger.WithComponent(c.component).Info("Notification",
+			zap.String("method", method),
+			zap.Any("params", params))
+		return nil
+	}
+	return c.push(method, params)
+}
@@ -0,0 +1,113 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+func TestCoreCallTool(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(NewSimpleTestTool("simple", "Simple", "ok"))
+	c.RegisterTool(NewPanicTestTool("panicky"))
+
+	if resp, err := c.callTool(context.Background(), "simple", map[string]interface{}{"message": "hi"}); err != nil || resp == nil {
+		t.Errorf("simple = %+v, %v", resp, err)
+	}
+	if _, err := c.callTool(context.Background(), "missing", nil); !errors.Is(err, errToolNotFound) {
+		t.Errorf("Expected errToolNotFound, got %v", err)
+	}
+	if _, err := c.callTool(context.Background(), "panicky", nil); err == nil || !strings.Contains(err.Error(), "tool exploded") {
+		t.Errorf("Expected recovered panic, got %v", err)
+	}
+	if c.InFlight() != 0 {
+		t.Errorf("InFlight = %d after calls returned", c.InFlight())
+	}
+
+	// A cancelled request stops waiting but the call stays tracked until it returns
+	blocking := NewBlockingTestTool("blocking")
+	c.RegisterTool(blocking)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-blocking.Started()
+		cancel()
+	}()
+	if _, err := c.callTool(ctx, "blocking", nil); err == nil || !strings.Contains(err.Error(), "cancelled") {
+		t.Errorf("Expected cancellation, got %v", err)
+	}
+	if c.InFlight() != 1 {
+		t.Errorf("InFlight = %d while the tool still runs", c.InFlight())
+	}
+	blocking.Release()
+	if err := c.WaitForInFlight(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	c.BeginDrain()
+	if _, err := c.callTool(context.Background(), "simple", map[string]interface{}{"message": "hi"}); !errors.Is(err, errDraining) {
+		t.Errorf("Expected errDraining, got %v", err)
+	}
+}
+
+func TestCoreToolListSorted(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		c.RegisterTool(NewSimpleTestTool(name, name, "ok"))
+	}
+	var names []string
+	for _, tool := range c.toolList() {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "alpha,mid,zeta" {
+		t.Errorf("toolList order = %v", names)
+	}
+}
+
+func TestCoreNotificationDelivery(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "http-mcp", "rodmcp-http")
+
+	// Without a push channel messages are only logged
+	if err := c.SendLogMessage("warn", "hello", nil); err != nil {
+		t.Error(err)
+	}
+	if err := c.SendNotification("notifications/test", nil); err != nil {
+		t.Error(err)
+	}
+
+	var methods []string
+	c.push = func(method string, params interface{}) error {
+		methods = append(methods, method)
+		return nil
+	}
+	c.SendLogMessage("info", "hello", map[string]interface{}{"k": "v"})
+	c.SendNotification("notifications/test", nil)
+	if strings.Join(methods, ",") != "notifications/message,notifications/test" {
+		t.Errorf("pushed methods = %v", methods)
+	}
+}
+
+func TestHTTPServerRecoversToolPanic(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	server.RegisterTool(NewPanicTestTool("panicky"))
+
+	body, _ := json.Marshal(types.CallToolRequest{Name: "panicky"})
+	req := httptest.NewRequest("POST", "/mcp/tools/call", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	server.handleToolsCall(rr, req)
+
+	if rr.Code != http.StatusInternalServerError || !strings.Contains(rr.Body.String(), "tool exploded") {
+		t.Errorf("got %d %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,26 @@
+package mcp
+
+import (
+	"context"
+
+	"rodmcp/pkg/types"
+)
+
+// ContextTool is implemented by tools that can stop their work when the
+// call they run for ends. The server calls ExecuteContext instead of
+// Execute with a context whose deadline is the call's timeout and which is
+// cancelled when the client cancels or the server stops waiting, so the
+// browser work underneath is bounded by the one timeout the caller sees.
+// Time spent waiting on the client's user extends a call but not this
+// deadline, so tools that ask the user should not implement it.
+type ContextTool interface {
+	ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error)
+}
+
+// executeTool runs a tool, through ExecuteContext when it takes a context
+func executeTool(ctx context.Context, tool Tool, args map[string]interface{}) (*types.CallToolResponse, error) {
+	if ct, ok := tool.(ContextTool); ok {
+		return ct.ExecuteContext(ctx, args)
+	}
+	return tool.Execute(args)
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// callTracker counts tool calls in progress so shutdown can stop taking new
+// ones and wait for the rest to finish before the browser goes away
+type callTracker struct {
+	mutex    sync.Mutex
+	draining bool
+	active   int
+	idle     chan struct{} // closed when active drops to zero
+}
+
+// begin registers a new call, reporting false once draining has started
+func (c *callTracker) begin() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.draining {
+		return false
+	}
+	c.active++
+	return true
+}
+
+func (c *callTracker) end() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.active--
+	if c.active == 0 && c.idle != nil {
+		close(c.idle)
+		c.idle = nil
+	}
+}
+
+func (c *callTracker) drain() {
+	c.mutex.Lock()
+	c.draining = true
+	c.mutex.Unlock()
+}
+
+func (c *callTracker) count() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.active
+}
+
+// wait blocks until no calls are running or ctx expires
+func (c *callTracker) wait(ctx context.Context) error {
+	c.mutex.Lock()
+	if c.active == 0 {
+		c.mutex.Unlock()
+		return nil
+	}
+	if c.idle == nil {
+		c.idle = make(chan struct{})
+	}
+	idle := c.idle
+	c.mutex.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d tool calls still running: %w", c.count(), ctx.Err())
+	}
+}
@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// dryRunArgument is the per-call argument that asks for a plan instead of
+// the real action
+const dryRunArgument = "dry_run"
+
+// PlanningTool is implemented by tools that can change things outside the
+// browser session: files, repositories, databases, remote services or
+// submitted forms. In dry-run mode the server calls Plan and, when the plan
+// mutates, returns it instead of running Execute. Tools without it are
+// treated as read-only and run normally, so every tool that writes a file
+// or sends data off the machine needs one.
+type PlanningTool interface {
+	Plan(args map[string]interface{}) (*types.ToolPlan, error)
+}
+
+// SetDryRun makes every call to a PlanningTool describe what it would do
+// instead of doing it. Without it, callers can still ask for a plan per
+// call with "dry_run": true.
+func (c *core) SetDryRun(enabled bool) {
+	c.dryRun = enabled
+}
+
+// takeDryRun reports whether the call should only be planned, removing the
+// dry_run argument so the tool never sees it. args is not modified.
+func (c *core) takeDryRun(tool Tool, args map[string]interface{}) (bool, map[string]interface{}) {
+	if _, ok := tool.(PlanningTool); !ok {
+		return false, args
+	}
+	value, present := args[dryRunArgument]
+	if !present {
+		return c.dryRun, args
+	}
+	rest := make(map[string]interface{}, len(args)-1)
+	for key, v := range args {
+		if key != dryRunArgument {
+			rest[key] = v
+		}
+	}
+	requested, _ := value.(bool)
+	if s, ok := value.(string); ok {
+		requested = strings.EqualFold(s, "true")
+	}
+	return c.dryRun || requested, rest
+}
+
+// planCall runs a dry-run call: the plan is returned when the call would
+// change something, otherwise the tool runs as usual
+func (c *core) planCall(ctx context.Context, tool Tool, args map[string]interface{}) (*types.CallToolResponse, error) {
+	plan, err := tool.(PlanningTool).Plan(args)
+	if err != nil {
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Dry run: %s would fail: %v", tool.Name(), err),
+				Data: map[string]interface{}{"dry_run": true, "tool": tool.Name()},
+			}},
+			IsError: true,
+		}, nil
+	}
+	if !plan.Mutates {
+		return c.executeMetered(ctx, tool, args)
+	}
+
+	c.logger.WithComponent(c.component).Info("Dry run: tool call not executed",
+		zap.String("tool", tool.Name()),
+		zap.String("plan", plan.Summary))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dry run: %s was not executed. It would %s", tool.Name(), plan.Summary)
+	keys := make([]string, 0, len(plan.Details))
+	for key := range plan.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n  %s: %v", key, plan.Details[key])
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: b.String(),
+			Data: map[string]interface{}{"dry_run": true, "tool": tool.Name(), "plan": plan},
+		}},
+	}, nil
+}
+
+// withDryRunArgument adds the dry_run argument to a planning tool's
+// published schema
+func withDryRunArgument(tool Tool, schema types.ToolSchema) types.ToolSchema {
+	if _, ok := tool.(PlanningTool); !ok {
+		return schema
+	}
+	properties := make(map[string]interface{}, len(schema.Properties)+1)
+	for key, value := range schema.Properties {
+		properties[key] = value
+	}
+	properties[dryRunArgument] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Describe what this call would change without doing it (default: false)",
+		"default":     false,
+	}
+	schema.Properties = properties
+	return schema
+}
@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/internal/webtools"
+	"rodmcp/pkg/types"
+)
+
+// planningTestTool records calls and plans a mutation for every method but GET
+type planningTestTool struct {
+	*RecordingTestTool
+}
+
+func (t planningTestTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	method, _ := args["method"].(string)
+	return &types.ToolPlan{
+		Mutates: method != "GET",
+		Summary: "send " + method,
+		Details: map[string]interface{}{"method": method},
+	}, nil
+}
+
+func TestCoreDryRun(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := planningTestTool{NewRecordingTestTool("sender", types.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"method": map[string]interface{}{"type": "string"}},
+	})}
+	c.RegisterTool(tool)
+	plain := NewRecordingTestTool("reader", types.ToolSchema{Type: "object"})
+	c.RegisterTool(plain)
+
+	// Per-call dry run returns the plan and never executes
+	resp, err := c.callTool(context.Background(), "sender", map[string]interface{}{"method": "POST", "dry_run": true})
+	if err != nil || resp.IsError {
+		t.Fatalf("dry run failed: %v %+v", err, resp)
+	}
+	if !strings.Contains(resp.Content[0].Text, "would send POST") || tool.LastArgs() != nil {
+		t.Errorf("expected a plan without execution, got %q (args %v)", resp.Content[0].Text, tool.LastArgs())
+	}
+
+	// Read-only calls run even in dry-run mode, without the dry_run argument
+	c.callTool(context.Background(), "sender", map[string]interface{}{"method": "GET", "dry_run": true})
+	if args := tool.LastArgs(); args == nil || args["dry_run"] != nil {
+		t.Errorf("read-only call args = %v", args)
+	}
+
+	// Server-wide mode plans without being asked; tools that cannot mutate run
+	c.SetDryRun(true)
+	resp, _ = c.callTool(context.Background(), "sender", map[string]interface{}{"method": "DELETE"})
+	if data, _ := resp.Content[0].Data.(map[string]interface{}); data["dry_run"] != true {
+		t.Errorf("server dry run did not plan: %+v", resp)
+	}
+	if resp, _ := c.callTool(context.Background(), "reader", map[string]interface{}{}); resp.Content[0].Text != "recorded" {
+		t.Errorf("non-planning tool did not run: %+v", resp)
+	}
+
+	// Only planning tools advertise the argument
+	for _, listed := range c.toolList() {
+		_, has := listed.InputSchema.Properties["dry_run"]
+		if has != (listed.Name == "sender") {
+			t.Errorf("%s advertises dry_run = %v", listed.Name, has)
+		}
+	}
+	if _, leaked := tool.InputSchema().Properties["dry_run"]; leaked {
+		t.Error("advertising dry_run modified the tool's own schema")
+	}
+}
+
+func TestDryRunReachesBatchedCalls(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := planningTestTool{NewRecordingTestTool("sender", types.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"method": map[string]interface{}{"type": "string"}},
+	})}
+	c.RegisterTool(tool)
+	dir := t.TempDir()
+	c.RegisterTool(webtools.NewRunBatchTool(log, nil, c.CallToolContext, nil))
+	c.RegisterTool(webtools.NewSaveWorkflowTool(log, dir))
+	c.RegisterTool(webtools.NewRunWorkflowTool(log, dir, c.CallTool))
+
+	send := map[string]interface{}{"tool": "sender", "args": map[string]interface{}{"method": "POST"}}
+	resp, err := c.callTool(context.Background(), "run_batch", map[string]interface{}{
+		"dry_run": true,
+		"calls":   []interface{}{send},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("dry-run batch failed: %v %+v", err, resp)
+	}
+	if tool.LastArgs() != nil {
+		t.Errorf("batched call ran during a dry run: %v", tool.LastArgs())
+	}
+	data, _ := resp.Content[0].Data.(map[string]interface{})
+	if encoded, _ := json.Marshal(data["results"]); !strings.Contains(string(encoded), "would send POST") {
+		t.Errorf("batch results = %s, want the call's plan", encoded)
+	}
+
+	if _, err := c.callTool(context.Background(), "save_workflow", map[string]interface{}{
+		"name":  "post",
+		"steps": []interface{}{send},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = c.callTool(context.Background(), "run_workflow", map[string]interface{}{"name": "post", "dry_run": true})
+	if err != nil || resp.IsError {
+		t.Fatalf("dry-run workflow failed: %v %+v", err, resp)
+	}
+	if tool.LastArgs() != nil {
+		t.Errorf("workflow step ran during a dry run: %v", tool.LastArgs())
+	}
+
+	// Without dry_run the calls run
+	c.callTool(context.Background(), "run_batch", map[string]interface{}{"calls": []interface{}{send}})
+	if args := tool.LastArgs(); args == nil || args["method"] != "POST" {
+		t.Errorf("batched call args = %v", args)
+	}
+}
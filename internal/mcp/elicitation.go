@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// elicitationTimeout is how long the server waits for the user to answer
+const elicitationTimeout = 5 * time.Minute
+
+// ErrElicitationUnsupported is returned when the client did not declare the
+// elicitation capability, or the transport cannot send it requests
+var ErrElicitationUnsupported = errors.New("client does not support elicitation")
+
+// userWaits measures time spent waiting for the client's user to answer (or
+// its model, see Sample), which does not count toward tool call timeouts.
+// Waits are not tied to a call, so an answer pending in one call pauses
+// every call's clock; clients ask one question at a time, so in practice
+// this is the call that asked.
+type userWaits struct {
+	mutex  sync.Mutex
+	active int
+	since  time.Time
+	total  time.Duration
+}
+
+func (w *userWaits) begin() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.active == 0 {
+		w.since = time.Now()
+	}
+	w.active++
+}
+
+func (w *userWaits) end() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.active--
+	if w.active == 0 {
+		w.total += time.Since(w.since)
+	}
+}
+
+// waited returns the total time spent waiting so far and whether a wait is
+// in progress
+func (w *userWaits) waited() (time.Duration, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.active > 0 {
+		return w.total + time.Since(w.since), true
+	}
+	return w.total, false
+}
+
+// setClientCapabilities records what the client declared in initialize
+func (s *Server) setClientCapabilities(caps types.ClientCapabilities) {
+	s.clientMutex.Lock()
+	defer s.clientMutex.Unlock()
+	s.clientCaps = caps
+}
+
+// Elicit asks the client's user for the fields in req.RequestedSchema and
+// returns their answer. It fails with ErrElicitationUnsupported when the
+// client cannot be asked. Time spent waiting does not count toward the
+// calling tool's timeout.
+func (s *Server) Elicit(req types.ElicitRequest) (*types.ElicitResult, error) {
+	return s.elicitContext(s.ctx, req)
+}
+
+// elicitContext is Elicit giving up when ctx ends
+func (s *Server) elicitContext(ctx context.Context, req types.ElicitRequest) (*types.ElicitResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, elicitationTimeout)
+	defer cancel()
+
+	s.clientMutex.Lock()
+	supported := s.clientCaps.Elicitation != nil
+	s.clientMutex.Unlock()
+	if !supported {
+		return nil, ErrElicitationUnsupported
+	}
+
+	s.userWaits.begin()
+	defer s.userWaits.end()
+
+	s.logger.WithComponent("mcp").Info("Asking the user for input",
+		zap.String("message", req.Message))
+	var result types.ElicitResult
+	if err := s.request(ctx, types.ElicitationMethod, req, &result); err != nil {
+		return nil, err
+	}
+	switch result.Action {
+	case "accept", "decline", "cancel":
+	default:
+		return nil, fmt.Errorf("invalid elicitation action %q", result.Action)
+	}
+	s.logger.WithComponent("mcp").Info("User answered",
+		zap.String("action", result.Action))
+	return &result, nil
+}
+
+// askClientApproval asks the user to approve a held call through
+// elicitation. A user who declines denies the call; when the client cannot
+// be asked, the call keeps waiting for the admin endpoint or its timeout.
+func (c *core) askClientApproval(ctx context.Context, p *PendingApproval) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.logger.WithComponent(c.component).Error("Approval request panicked", zap.Any("panic", r))
+		}
+	}()
+	result, err := c.elicit(ctx, types.ElicitRequest{
+		Message: fmt.Sprintf("Allow this %s call? %s", p.Tool, p.Reason),
+		RequestedSchema: types.ToolSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"approve": map[string]interface{}{
+					"type":        "boolean",
+					"title":       "Approve",
+					"description": fmt.Sprintf("Run %s with the arguments shown", p.Tool),
+				},
+			},
+			Required: []string{"approve"},
+		},
+	})
+	if err != nil {
+		c.logger.WithComponent(c.component).Warn("Could not ask the client to approve a call",
+			zap.String("id", p.ID),
+			zap.Error(err))
+		return
+	}
+	approved, _ := result.Content["approve"].(bool)
+	if result.Action == "accept" && approved {
+		c.DecideApproval(p.ID, true, "")
+		return
+	}
+	c.DecideApproval(p.ID, false, "declined by the user")
+}
@@ -0,0 +1,147 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// askingTool asks the user for a name and greets them
+type askingTool struct {
+	*SimpleTestTool
+	ask func(types.ElicitRequest) (*types.ElicitResult, error)
+}
+
+func (t askingTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	result, err := t.ask(types.ElicitRequest{
+		Message: "What is your name?",
+		RequestedSchema: types.ToolSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{"name": map[string]interface{}{"type": "string"}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	name, _ := result.Content["name"].(string)
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{Type: "text", Text: result.Action + ": hello " + name}},
+	}, nil
+}
+
+func TestElicitRoundTrip(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+	server.RegisterTool(askingTool{NewSimpleTestTool("greet", "Greets the user", ""), server.Elicit})
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+	go server.Start()
+	defer server.Stop()
+	defer inWriter.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	readMessage := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case line := <-lines:
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("Invalid message %q: %v", line, err)
+			}
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for message")
+			return nil
+		}
+	}
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"elicitation":{}}}}`)
+	readMessage()
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"greet","arguments":{"message":"x"}}}`)
+	request := readMessage()
+	if request["method"] != types.ElicitationMethod {
+		t.Fatalf("Expected elicitation request, got %v", request)
+	}
+	id, _ := json.Marshal(request["id"])
+	fmt.Fprintf(inWriter, `{"jsonrpc":"2.0","id":%s,"result":{"action":"accept","content":{"name":"Ada"}}}`+"\n", id)
+
+	msg := readMessage()
+	result, _ := msg["result"].(map[string]interface{})
+	content, _ := result["content"].([]interface{})
+	if msg["id"] != float64(2) || len(content) == 0 {
+		t.Fatalf("Expected tool result, got %v", msg)
+	}
+	if text := content[0].(map[string]interface{})["text"]; text != "accept: hello Ada" {
+		t.Errorf("text = %v", text)
+	}
+}
+
+func TestElicitRequiresClientCapability(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+	defer server.Stop()
+
+	if _, err := server.Elicit(types.ElicitRequest{Message: "hi"}); !errors.Is(err, ErrElicitationUnsupported) {
+		t.Errorf("Expected ErrElicitationUnsupported, got %v", err)
+	}
+}
+
+func TestUserWaitsDoNotCountTowardTimeout(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "test", "test")
+	c.SetToolTimeouts(ToolTimeouts{"waiting": 100 * time.Millisecond})
+	c.RegisterTool(askingTool{NewSimpleTestTool("waiting", "Waits for the user", ""), func(types.ElicitRequest) (*types.ElicitResult, error) {
+		c.userWaits.begin()
+		defer c.userWaits.end()
+		time.Sleep(300 * time.Millisecond)
+		return &types.ElicitResult{Action: "decline"}, nil
+	}})
+
+	result, err := c.callTool(context.Background(), "waiting", map[string]interface{}{"message": "x"})
+	if err != nil {
+		t.Fatalf("Expected the wait for the user not to time the call out, got %v", err)
+	}
+	if result.Content[0].Text != "decline: hello " {
+		t.Errorf("text = %q", result.Content[0].Text)
+	}
+}
+
+func TestApprovalAsksClient(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := NewRecordingTestTool("runner", types.ToolSchema{Type: "object"})
+	c.RegisterTool(tool)
+	c.SetApprovalPolicy(ApprovalPolicy{Rules: []ApprovalRule{{Tool: "runner"}}, Timeout: time.Minute, AskClient: true})
+
+	approve := true
+	c.elicit = func(ctx context.Context, req types.ElicitRequest) (*types.ElicitResult, error) {
+		return &types.ElicitResult{Action: "accept", Content: map[string]interface{}{"approve": approve}}, nil
+	}
+	if resp, err := c.callTool(context.Background(), "runner", map[string]interface{}{}); err != nil || resp.IsError || tool.LastArgs() == nil {
+		t.Fatalf("approved call = %+v, %v", resp, err)
+	}
+
+	approve = false
+	resp, err := c.callTool(context.Background(), "runner", map[string]interface{}{})
+	if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, "declined by the user") {
+		t.Errorf("declined call = %+v, %v", resp, err)
+	}
+}
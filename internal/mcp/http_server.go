@@ -3,48 +3,64 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"rodmcp/internal/logger"
 	"rodmcp/pkg/types"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+// HTTPServer exposes the MCP tools as plain HTTP endpoints. HTTP has no
+// channel to push to the client, so log messages and notifications are only
+// logged locally.
 type HTTPServer struct {
-	logger      *logger.Logger
-	tools       map[string]Tool
-	toolsMutex  sync.RWMutex
+	*core
+	port int
+	host string
+
+	// Set by Start; guarded by serverMutex because Shutdown may run
+	// from another goroutine
 	server      *http.Server
-	initialized bool
-	version     types.MCPVersion
-	info        types.ServerInfo
-	port        int
+	serverMutex sync.Mutex
+
+	// Stops the health monitor on shutdown
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Readiness reporting for container orchestrators
+	draining atomic.Bool
 }
 
 // NewHTTPServer creates a new HTTP-based MCP server
 func NewHTTPServer(log *logger.Logger, port int) *HTTPServer {
+	ctx, cancel := context.WithCancel(context.Background())
 	return &HTTPServer{
-		logger: log,
-		tools:  make(map[string]Tool),
-		version: types.CurrentMCPVersion,
-		info: types.ServerInfo{
-			Name:    "rodmcp-http",
-			Version: "1.0.0",
-		},
-		port: port,
+		core:   newCore(log, "http-mcp", "rodmcp-http"),
+		port:   port,
+		ctx:    ctx,
+		cancel: cancel,
 	}
 }
 
-func (s *HTTPServer) RegisterTool(tool Tool) {
-	s.toolsMutex.Lock()
-	defer s.toolsMutex.Unlock()
-	s.tools[tool.Name()] = tool
-	s.logger.WithComponent("http-mcp").Info("Tool registered",
-		zap.String("tool", tool.Name()))
+// SetListenHost sets the interface to bind, e.g. "0.0.0.0" in a container.
+// An empty host binds all interfaces.
+func (s *HTTPServer) SetListenHost(host string) {
+	s.host = host
+}
+
+// BeginDrain marks the server as shutting down so /readyz fails and load
+// balancers stop routing new requests to it. New tool calls are refused while
+// running ones are left to finish.
+func (s *HTTPServer) BeginDrain() {
+	s.draining.Store(true)
+	s.core.BeginDrain()
 }
 
 func (s *HTTPServer) Start() error {
@@ -55,7 +71,7 @@ func (s *HTTPServer) Start() error {
 		return func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, "+PriorityHeader)
 			
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -67,16 +83,20 @@ func (s *HTTPServer) Start() error {
 	}
 	
 	// MCP endpoints
-	mux.HandleFunc("/mcp/initialize", corsHandler(s.handleInitialize))
-	mux.HandleFunc("/mcp/tools/list", corsHandler(s.handleToolsList))
-	mux.HandleFunc("/mcp/tools/call", corsHandler(s.handleToolsCall))
+	mux.HandleFunc("/mcp/initialize", corsHandler(s.requireToken(s.handleInitialize)))
+	mux.HandleFunc("/mcp/tools/list", corsHandler(s.requireToken(s.handleToolsList)))
+	mux.HandleFunc("/mcp/tools/call", corsHandler(s.requireToken(s.handleToolsCall)))
+	mux.HandleFunc("/mcp/completion/complete", corsHandler(s.requireToken(s.handleComplete)))
 	mux.HandleFunc("/health", corsHandler(s.handleHealth))
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/livez", s.handleLive)
+	mux.HandleFunc("/readyz", s.handleReady)
 	
 	// Server info endpoint
 	mux.HandleFunc("/", corsHandler(s.handleRoot))
 
-	s.server = &http.Server{
-		Addr:         ":" + strconv.Itoa(s.port),
+	server := &http.Server{
+		Addr:         net.JoinHostPort(s.host, strconv.Itoa(s.port)),
 		Handler:      mux,
 		ReadTimeout:  30 * time.Second,
 		WriteTimeout: 30 * time.Second,
@@ -84,22 +104,38 @@ func (s *HTTPServer) Start() error {
 	}
 
 	s.logger.WithComponent("http-mcp").Info("Starting HTTP MCP server",
+		zap.String("host", s.host),
 		zap.Int("port", s.port),
 		zap.String("version", string(s.version)))
 
-	return s.server.ListenAndServe()
+	s.serverMutex.Lock()
+	s.server = server
+	s.serverMutex.Unlock()
+
+	go s.runHealthMonitor(s.ctx)
+	return server.ListenAndServe()
 }
 
 func (s *HTTPServer) Stop() error {
-	if s.server == nil {
-		return nil
-	}
-	
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
+	return s.Shutdown(ctx)
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests
+// until ctx expires
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	s.serverMutex.Lock()
+	server := s.server
+	s.serverMutex.Unlock()
+	if server == nil {
+		return nil
+	}
 	
+	s.BeginDrain()
+	s.cancel()
 	s.logger.WithComponent("http-mcp").Info("Shutting down HTTP MCP server")
-	return s.server.Shutdown(ctx)
+	return server.Shutdown(ctx)
 }
 
 func (s *HTTPServer) handleRoot(w http.ResponseWriter, r *http.Request) {
@@ -108,21 +144,23 @@ func (s *HTTPServer) handleRoot(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	s.toolsMutex.RLock()
-	toolCount := len(s.tools)
-	s.toolsMutex.RUnlock()
+	toolCount := s.toolCount()
 	
 	response := map[string]interface{}{
 		"service":     "RodMCP HTTP Server",
 		"version":     s.info.Version,
 		"protocol":    s.version,
 		"tools":       toolCount,
-		"initialized": s.initialized,
+		"initialized": s.initialized.Load(),
 		"endpoints": map[string]string{
 			"initialize":  "/mcp/initialize",
 			"tools_list":  "/mcp/tools/list", 
 			"tools_call":  "/mcp/tools/call",
+			"completion":  "/mcp/completion/complete",
 			"health":      "/health",
+			"metrics":     "/metrics",
+			"live":        "/livez",
+			"ready":       "/readyz",
 		},
 	}
 	
@@ -136,21 +174,57 @@ func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	s.toolsMutex.RLock()
-	toolCount := len(s.tools)
-	s.toolsMutex.RUnlock()
+	toolCount := s.toolCount()
 	
 	health := map[string]interface{}{
 		"status":      "healthy",
 		"tools":       toolCount,
-		"initialized": s.initialized,
+		"initialized": s.initialized.Load(),
 		"timestamp":   time.Now().UTC().Format(time.RFC3339),
 	}
+	if jobs := s.JobQueueStats(); jobs != nil {
+		health["job_queue"] = jobs
+	}
 	
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(health)
 }
 
+// handleLive reports that the process is up; it never checks dependencies so
+// a slow browser does not get the container restarted
+func (s *HTTPServer) handleLive(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "alive"})
+}
+
+// handleReady reports whether the server should receive traffic
+func (s *HTTPServer) handleReady(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	checks := map[string]string{"server": "ok", "browser": "ok"}
+
+	if s.draining.Load() {
+		status = http.StatusServiceUnavailable
+		checks["server"] = "draining"
+	}
+
+	if err := s.checkBrowser(5 * time.Second); err != nil {
+		status = http.StatusServiceUnavailable
+		checks["browser"] = err.Error()
+	}
+
+	state := "ready"
+	if status != http.StatusOK {
+		state = "not_ready"
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": state,
+		"checks": checks,
+	})
+}
+
 func (s *HTTPServer) handleInitialize(w http.ResponseWriter, r *http.Request) {
 	if r.Method != "POST" {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -170,13 +244,14 @@ func (s *HTTPServer) handleInitialize(w http.ResponseWriter, r *http.Request) {
 			zap.String("server_version", string(s.version)))
 	}
 	
-	s.initialized = true
+	s.initialized.Store(true)
 	
 	response := types.InitializeResponse{
 		ProtocolVersion: s.version,
 		Capabilities: types.ServerCapabilities{
-			Tools:   &types.ToolsCapability{},
-			Logging: &types.LoggingCapability{},
+			Tools:       &types.ToolsCapability{},
+			Logging:     &types.LoggingCapability{},
+			Completions: &types.CompletionsCapability{},
 		},
 		ServerInfo: s.info,
 	}
@@ -191,20 +266,8 @@ func (s *HTTPServer) handleToolsList(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	s.toolsMutex.RLock()
-	defer s.toolsMutex.RUnlock()
-	
-	var tools []types.Tool
-	for _, tool := range s.tools {
-		tools = append(tools, types.Tool{
-			Name:        tool.Name(),
-			Description: tool.Description(),
-			InputSchema: tool.InputSchema(),
-		})
-	}
-	
 	result := map[string]interface{}{
-		"tools": tools,
+		"tools": s.visibleTools(r.Context(), s.toolList()),
 	}
 	
 	w.Header().Set("Content-Type", "application/json")
@@ -223,22 +286,46 @@ func (s *HTTPServer) handleToolsCall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	s.toolsMutex.RLock()
-	tool, exists := s.tools[callReq.Name]
-	s.toolsMutex.RUnlock()
-	
-	if !exists {
-		s.sendHTTPError(w, http.StatusNotFound, "Tool not found", fmt.Sprintf("Tool '%s' is not available", callReq.Name))
+	priority, err := ParsePriority(r.Header.Get(PriorityHeader))
+	if err != nil {
+		s.sendHTTPError(w, http.StatusBadRequest, "Invalid priority", err.Error())
 		return
 	}
-	
+
 	// Log the tool execution attempt
 	s.logger.WithComponent("http-mcp").Info("Executing tool",
 		zap.String("tool", callReq.Name),
-		zap.Any("args", callReq.Arguments))
+		zap.Any("args", s.redactArguments(callReq.Name, callReq.Arguments)))
 	
-	result, err := tool.Execute(callReq.Arguments)
-	if err != nil {
+	// Calls may be allowed to run past the server's write timeout, or wait
+	// for approval first; keep the response open for them
+	if limit := s.responseLimit(callReq.Name, callReq.Arguments); limit > 0 {
+		http.NewResponseController(w).SetWriteDeadline(time.Now().Add(limit))
+	}
+
+	result, err := s.callTool(withJobPriority(r.Context(), priority), callReq.Name, callReq.Arguments)
+	var argsErr *ArgumentsError
+	switch {
+	case errors.As(err, &argsErr):
+		s.sendHTTPError(w, http.StatusBadRequest, "Invalid arguments", argsErr)
+		return
+	case errors.Is(err, errToolNotFound):
+		s.sendHTTPError(w, http.StatusNotFound, "Tool not found", fmt.Sprintf("Tool '%s' is not available", callReq.Name))
+		return
+	case errors.Is(err, errDraining):
+		s.sendHTTPError(w, http.StatusServiceUnavailable, "Server is shutting down", "New tool calls are not accepted while draining")
+		return
+	case errors.Is(err, errObserverDenied):
+		s.sendHTTPError(w, http.StatusForbidden, "Not allowed for observers", err.Error())
+		return
+	case errors.Is(err, errQueueFull):
+		w.Header().Set("Retry-After", "1")
+		s.sendHTTPError(w, http.StatusTooManyRequests, "Job queue is full", err.Error())
+		return
+	case errors.Is(err, errToolTimeout):
+		s.sendHTTPError(w, http.StatusGatewayTimeout, "Tool execution timed out", err.Error())
+		return
+	case err != nil:
 		s.logger.WithComponent("http-mcp").Error("Tool execution failed",
 			zap.String("tool", callReq.Name),
 			zap.Error(err))
@@ -253,6 +340,35 @@ func (s *HTTPServer) handleToolsCall(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(result)
 }
 
+func (s *HTTPServer) handleComplete(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var completeReq types.CompleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&completeReq); err != nil {
+		s.sendHTTPError(w, http.StatusBadRequest, "Invalid JSON", err.Error())
+		return
+	}
+
+	completion, err := s.complete(r.Context(), completeReq)
+	switch {
+	case errors.Is(err, errUnsupportedReference):
+		s.sendHTTPError(w, http.StatusBadRequest, "Unsupported reference", err.Error())
+		return
+	case errors.Is(err, errToolNotFound):
+		s.sendHTTPError(w, http.StatusNotFound, "Tool not found", fmt.Sprintf("Tool '%s' is not available", completeReq.Ref.Name))
+		return
+	case err != nil:
+		s.sendHTTPError(w, http.StatusInternalServerError, "Completion failed", err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(types.CompleteResult{Completion: completion})
+}
+
 func (s *HTTPServer) sendHTTPError(w http.ResponseWriter, statusCode int, message string, details interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -267,18 +383,3 @@ func (s *HTTPServer) sendHTTPError(w http.ResponseWriter, statusCode int, messag
 	
 	json.NewEncoder(w).Encode(errorResponse)
 }
-
-// SendLogMessage sends a log message (for HTTP, we just log it internally)
-func (s *HTTPServer) SendLogMessage(level string, message string, data map[string]interface{}) error {
-	switch level {
-	case "error":
-		s.logger.WithComponent("http-mcp").Error(message, zap.Any("data", data))
-	case "warn":
-		s.logger.WithComponent("http-mcp").Warn(message, zap.Any("data", data))
-	case "debug":
-		s.logger.WithComponent("http-mcp").Debug(message, zap.Any("data", data))
-	default:
-		s.logger.WithComponent("http-mcp").Info(message, zap.Any("data", data))
-	}
-	return nil
-}
\ No newline at end of file
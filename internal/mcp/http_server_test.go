@@ -2,10 +2,12 @@ package mcp
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"rodmcp/internal/browser"
 	"rodmcp/internal/logger"
 	"rodmcp/pkg/types"
 	"strings"
@@ -198,7 +200,7 @@ func TestHTTPServerHandleInitialize(t *testing.T) {
 		t.Errorf("Expected server name 'rodmcp-http', got %s", response.ServerInfo.Name)
 	}
 	
-	if !server.initialized {
+	if !server.initialized.Load() {
 		t.Error("Server should be initialized after initialize request")
 	}
 }
@@ -303,7 +305,7 @@ func TestHTTPServerHandleToolsCall(t *testing.T) {
 	callReq := types.CallToolRequest{
 		Name: "call_http_tool",
 		Arguments: map[string]interface{}{
-			"test": "value",
+			"message": "value",
 		},
 	}
 	
@@ -643,4 +645,81 @@ func BenchmarkHTTPServerHandleToolsList(b *testing.B) {
 		rr := httptest.NewRecorder()
 		server.handleToolsList(rr, req)
 	}
-}
\ No newline at end of file
+}
+func TestHTTPServerReadiness(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+
+	rr := httptest.NewRecorder()
+	server.handleReady(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected ready without a browser manager, got %d", rr.Code)
+	}
+
+	// A browser manager that was never started is not ready
+	server.SetBrowserManager(browser.NewManager(log, browser.Config{Headless: true}))
+	rr = httptest.NewRecorder()
+	server.handleReady(rr, httptest.NewRequest("GET", "/readyz", nil))
+	if rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 with an unstarted browser, got %d", rr.Code)
+	}
+
+	server.SetBrowserManager(nil)
+	server.BeginDrain()
+	rr = httptest.NewRecorder()
+	server.handleReady(rr, httptest.NewRequest("GET", "/readyz", nil))
+	var response map[string]interface{}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	if rr.Code != http.StatusServiceUnavailable || response["checks"].(map[string]interface{})["server"] != "draining" {
+		t.Errorf("Expected draining server to be not ready, got %d %v", rr.Code, response)
+	}
+
+	// Liveness ignores draining and browser state
+	rr = httptest.NewRecorder()
+	server.handleLive(rr, httptest.NewRequest("GET", "/livez", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Expected liveness to pass, got %d", rr.Code)
+	}
+}
+
+func TestHTTPServerDrainRefusesNewToolCalls(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	tool := NewBlockingTestTool("slow_http_tool")
+	server.RegisterTool(tool)
+
+	call := func() *httptest.ResponseRecorder {
+		body, _ := json.Marshal(types.CallToolRequest{Name: "slow_http_tool", Arguments: map[string]interface{}{}})
+		req := httptest.NewRequest("POST", "/mcp/tools/call", bytes.NewBuffer(body))
+		rr := httptest.NewRecorder()
+		server.handleToolsCall(rr, req)
+		return rr
+	}
+
+	first := make(chan *httptest.ResponseRecorder, 1)
+	go func() { first <- call() }()
+	select {
+	case <-tool.Started():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tool call never started")
+	}
+
+	server.BeginDrain()
+	if rr := call(); rr.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while draining, got %d", rr.Code)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	if err := server.WaitForInFlight(ctx); err == nil {
+		t.Error("Expected drain to time out while the call is running")
+	}
+	cancel()
+
+	tool.Release()
+	if rr := <-first; rr.Code != http.StatusOK {
+		t.Errorf("Expected in-flight call to complete with 200, got %d", rr.Code)
+	}
+	if err := server.WaitForInFlight(context.Background()); err != nil {
+		t.Errorf("Expected drain to finish, got %v", err)
+	}
+}
@@ -0,0 +1,318 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// PriorityHeader names the HTTP header a client sets to "background" for
+// calls that may wait behind interactive ones
+const PriorityHeader = "X-RodMCP-Priority"
+
+// defaultMaxQueued bounds the calls of each priority waiting for a worker
+// when the config does not say
+const defaultMaxQueued = 64
+
+var errQueueFull = errors.New("job queue is full")
+
+// Priority orders tool calls waiting in the job queue
+type Priority int
+
+const (
+	// PriorityBackground is for scheduled scrapes, monitors and other work
+	// nobody is waiting on
+	PriorityBackground Priority = iota
+	// PriorityInteractive is for calls a user or model is waiting on
+	PriorityInteractive
+)
+
+// priorities lists the priorities in the order their calls are started
+var priorities = []Priority{PriorityInteractive, PriorityBackground}
+
+func (p Priority) String() string {
+	if p == PriorityBackground {
+		return "background"
+	}
+	return "interactive"
+}
+
+// ParsePriority reads a priority name; empty means interactive
+func ParsePriority(name string) (Priority, error) {
+	switch name {
+	case "", "interactive":
+		return PriorityInteractive, nil
+	case "background":
+		return PriorityBackground, nil
+	}
+	return PriorityInteractive, fmt.Errorf("unknown priority %q (use interactive or background)", name)
+}
+
+// JobQueueConfig is the "job_queue" object of the config file:
+//
+//	{"workers": 4, "max_queued": 64, "reserved_interactive": 1}
+type JobQueueConfig struct {
+	// Workers is how many HTTP tool calls run at once; 0 runs them all
+	// as they arrive
+	Workers int `json:"workers"`
+
+	// MaxQueued is how many calls of each priority may wait for a worker
+	// before new ones are refused; defaultMaxQueued when zero
+	MaxQueued int `json:"max_queued"`
+
+	// ReservedInteractive is how many workers background calls may not
+	// take, so a burst of them cannot shut interactive calls out. It
+	// defaults to 1 with two or more workers.
+	ReservedInteractive *int `json:"reserved_interactive,omitempty"`
+}
+
+// Validate rejects settings the queue cannot run with
+func (c JobQueueConfig) Validate() error {
+	if c.Workers < 0 {
+		return fmt.Errorf("workers must not be negative")
+	}
+	if c.MaxQueued < 0 {
+		return fmt.Errorf("max_queued must not be negative")
+	}
+	if c.ReservedInteractive != nil {
+		if *c.ReservedInteractive < 0 {
+			return fmt.Errorf("reserved_interactive must not be negative")
+		}
+		if c.Workers > 0 && *c.ReservedInteractive >= c.Workers {
+			return fmt.Errorf("reserved_interactive must leave background calls at least one of the %d workers", c.Workers)
+		}
+	}
+	return nil
+}
+
+// JobQueueStats describes the calls of one priority in the job queue
+type JobQueueStats struct {
+	Priority string `json:"priority"`
+	Running  int    `json:"running"`
+	Queued   int    `json:"queued"`
+	// Started and Rejected count calls since the server started
+	Started  int64 `json:"started"`
+	Rejected int64 `json:"rejected"`
+	// Waited is the total time started calls spent queued
+	Waited time.Duration `json:"waited_ns"`
+}
+
+// jobQueue lets at most workers tool calls run at once and starts waiting
+// calls interactive first, oldest first
+type jobQueue struct {
+	mutex     sync.Mutex
+	workers   int
+	maxQueued int
+	reserved  int
+
+	running [2]int
+	waiting [2][]*jobWaiter
+	stats   [2]JobQueueStats
+}
+
+// jobWaiter is a call waiting for a worker; ready is closed when it gets one
+type jobWaiter struct {
+	ready chan struct{}
+}
+
+func (q *jobQueue) configure(config JobQueueConfig) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	q.workers = config.Workers
+	q.maxQueued = config.MaxQueued
+	if q.maxQueued == 0 {
+		q.maxQueued = defaultMaxQueued
+	}
+	q.reserved = 0
+	if config.ReservedInteractive != nil {
+		q.reserved = *config.ReservedInteractive
+	} else if q.workers > 1 {
+		q.reserved = 1
+	}
+	q.dispatchLocked()
+}
+
+func (q *jobQueue) enabled() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.workers > 0
+}
+
+// acquire waits for a worker for a call of priority p until ctx ends. The
+// returned function frees the worker; it is safe to call more than once.
+func (q *jobQueue) acquire(ctx context.Context, p Priority) (func(), error) {
+	q.mutex.Lock()
+	if q.workers <= 0 {
+		q.mutex.Unlock()
+		return func() {}, nil
+	}
+	if len(q.waiting[p]) == 0 && q.canStartLocked(p) {
+		q.startLocked(p)
+		q.mutex.Unlock()
+		return q.releaser(p), nil
+	}
+	if len(q.waiting[p]) >= q.maxQueued {
+		q.stats[p].Rejected++
+		q.mutex.Unlock()
+		return nil, fmt.Errorf("%w: %d %s calls are already waiting", errQueueFull, q.maxQueued, p)
+	}
+	waiter := &jobWaiter{ready: make(chan struct{})}
+	q.waiting[p] = append(q.waiting[p], waiter)
+	q.mutex.Unlock()
+
+	queued := time.Now()
+	select {
+	case <-waiter.ready:
+		q.mutex.Lock()
+		q.stats[p].Waited += time.Since(queued)
+		q.mutex.Unlock()
+		return q.releaser(p), nil
+	case <-ctx.Done():
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		if !q.removeLocked(p, waiter) {
+			// Started as ctx ended; hand the worker on
+			q.running[p]--
+			q.dispatchLocked()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+func (q *jobQueue) releaser(p Priority) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			q.mutex.Lock()
+			defer q.mutex.Unlock()
+			q.running[p]--
+			q.dispatchLocked()
+		})
+	}
+}
+
+// canStartLocked reports whether a call of priority p may take a worker
+// now; background calls leave the reserved workers alone
+func (q *jobQueue) canStartLocked(p Priority) bool {
+	if q.workers <= 0 {
+		return true
+	}
+	total := q.running[PriorityInteractive] + q.running[PriorityBackground]
+	if total >= q.workers {
+		return false
+	}
+	return p == PriorityInteractive || q.running[PriorityBackground] < q.workers-q.reserved
+}
+
+func (q *jobQueue) startLocked(p Priority) {
+	q.running[p]++
+	q.stats[p].Started++
+}
+
+// dispatchLocked starts waiting calls while workers are free, interactive
+// calls first
+func (q *jobQueue) dispatchLocked() {
+	for _, p := range priorities {
+		for len(q.waiting[p]) > 0 && q.canStartLocked(p) {
+			waiter := q.waiting[p][0]
+			q.waiting[p] = q.waiting[p][1:]
+			q.startLocked(p)
+			close(waiter.ready)
+		}
+	}
+}
+
+// removeLocked takes a waiter out of the queue, reporting false when it was
+// already started
+func (q *jobQueue) removeLocked(p Priority, waiter *jobWaiter) bool {
+	for i, w := range q.waiting[p] {
+		if w == waiter {
+			q.waiting[p] = append(q.waiting[p][:i], q.waiting[p][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *jobQueue) snapshot() []JobQueueStats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	stats := make([]JobQueueStats, 0, len(priorities))
+	for _, p := range priorities {
+		s := q.stats[p]
+		s.Priority = p.String()
+		s.Running = q.running[p]
+		s.Queued = len(q.waiting[p])
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// SetJobQueue bounds how many HTTP tool calls run at once. Calls beyond
+// that wait, interactive ones first; calls made from inside the server
+// (workflow steps) skip the queue since their caller already holds a worker.
+func (c *core) SetJobQueue(config JobQueueConfig) {
+	c.jobs.configure(config)
+}
+
+// JobQueueStats returns the running and waiting calls of each priority, or
+// nil when no job queue is set
+func (c *core) JobQueueStats() []JobQueueStats {
+	if !c.jobs.enabled() {
+		return nil
+	}
+	return c.jobs.snapshot()
+}
+
+// AcquireJobSlot waits for a worker for background work started inside the
+// server, such as a monitor check, and returns the function that frees it
+func (c *core) AcquireJobSlot(ctx context.Context, p Priority) (func(), error) {
+	return c.jobs.acquire(ctx, p)
+}
+
+type priorityKey struct{}
+
+// withJobPriority queues the calls made with ctx at priority p
+func withJobPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// jobPriority reports the priority ctx's calls queue at, or false for
+// calls that skip the queue
+func jobPriority(ctx context.Context) (Priority, bool) {
+	p, ok := ctx.Value(priorityKey{}).(Priority)
+	return p, ok
+}
+
+// writeJobQueueMetrics writes the queue's gauges and counters in the
+// Prometheus text format
+func writeJobQueueMetrics(w io.Writer, stats []JobQueueStats) {
+	if len(stats) == 0 {
+		return
+	}
+	series := []struct {
+		name, help, kind string
+		value            func(JobQueueStats) string
+	}{
+		{"rodmcp_job_queue_depth", "Tool calls waiting for a worker", "gauge",
+			func(s JobQueueStats) string { return fmt.Sprint(s.Queued) }},
+		{"rodmcp_job_queue_running", "Tool calls holding a worker", "gauge",
+			func(s JobQueueStats) string { return fmt.Sprint(s.Running) }},
+		{"rodmcp_job_queue_started_total", "Tool calls given a worker", "counter",
+			func(s JobQueueStats) string { return fmt.Sprint(s.Started) }},
+		{"rodmcp_job_queue_rejected_total", "Tool calls refused because the queue was full", "counter",
+			func(s JobQueueStats) string { return fmt.Sprint(s.Rejected) }},
+		{"rodmcp_job_queue_wait_seconds_total", "Time tool calls spent waiting for a worker", "counter",
+			func(s JobQueueStats) string { return fmt.Sprintf("%g", s.Waited.Seconds()) }},
+	}
+	for _, s := range series {
+		fmt.Fprintf(w, "# HELP %s %s, by priority.\n", s.name, s.help)
+		fmt.Fprintf(w, "# TYPE %s %s\n", s.name, s.kind)
+		for _, stat := range stats {
+			fmt.Fprintf(w, "%s{priority=\"%s\"} %s\n", s.name, stat.Priority, s.value(stat))
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+)
+
+func intPtr(n int) *int { return &n }
+
+// waitQueued waits until n calls of priority p are waiting in q
+func waitQueued(t *testing.T, q *jobQueue, p Priority, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		q.mutex.Lock()
+		queued := len(q.waiting[p])
+		q.mutex.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Expected %d %s calls queued", n, p)
+}
+
+func TestParsePriority(t *testing.T) {
+	for name, want := range map[string]Priority{"": PriorityInteractive, "interactive": PriorityInteractive, "background": PriorityBackground} {
+		if got, err := ParsePriority(name); err != nil || got != want {
+			t.Errorf("ParsePriority(%q) = %v, %v", name, got, err)
+		}
+	}
+	if _, err := ParsePriority("urgent"); err == nil {
+		t.Error("Expected an unknown priority to be rejected")
+	}
+}
+
+func TestJobQueueConfigValidate(t *testing.T) {
+	valid := []JobQueueConfig{{}, {Workers: 4, MaxQueued: 10}, {Workers: 2, ReservedInteractive: intPtr(1)}}
+	for _, c := range valid {
+		if err := c.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", c, err)
+		}
+	}
+	invalid := []JobQueueConfig{{Workers: -1}, {MaxQueued: -1}, {Workers: 2, ReservedInteractive: intPtr(2)}, {ReservedInteractive: intPtr(-1)}}
+	for _, c := range invalid {
+		if err := c.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", c)
+		}
+	}
+}
+
+func TestJobQueueDisabledRunsEverything(t *testing.T) {
+	var q jobQueue
+	for i := 0; i < 10; i++ {
+		if _, err := q.acquire(context.Background(), PriorityBackground); err != nil {
+			t.Fatalf("Expected no queue without workers, got %v", err)
+		}
+	}
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	if stats := newCore(log, "http-mcp", "rodmcp").JobQueueStats(); stats != nil {
+		t.Errorf("Expected no stats without a queue, got %v", stats)
+	}
+}
+
+func TestJobQueueStartsInteractiveFirst(t *testing.T) {
+	var q jobQueue
+	q.configure(JobQueueConfig{Workers: 1})
+
+	release, err := q.acquire(context.Background(), PriorityInteractive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan Priority, 2)
+	wait := func(p Priority) {
+		release, err := q.acquire(context.Background(), p)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		order <- p
+		release()
+	}
+	go wait(PriorityBackground)
+	waitQueued(t, &q, PriorityBackground, 1)
+	go wait(PriorityInteractive)
+	waitQueued(t, &q, PriorityInteractive, 1)
+
+	release()
+	if first := <-order; first != PriorityInteractive {
+		t.Errorf("Expected the interactive call to start first, got %s", first)
+	}
+	if second := <-order; second != PriorityBackground {
+		t.Errorf("Expected the background call to start next, got %s", second)
+	}
+
+	stats := q.snapshot()
+	if stats[0].Started != 2 || stats[1].Started != 1 || stats[1].Waited <= 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestJobQueueReservesInteractiveWorker(t *testing.T) {
+	var q jobQueue
+	q.configure(JobQueueConfig{Workers: 2})
+
+	if _, err := q.acquire(context.Background(), PriorityBackground); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	if _, err := q.acquire(ctx, PriorityBackground); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected a second background call to wait for the reserved worker, got %v", err)
+	}
+	if q.snapshot()[1].Queued != 0 {
+		t.Error("Expected the timed out call to leave the queue")
+	}
+	if _, err := q.acquire(context.Background(), PriorityInteractive); err != nil {
+		t.Errorf("Expected an interactive call to get the reserved worker, got %v", err)
+	}
+}
+
+func TestJobQueueRefusesWhenFull(t *testing.T) {
+	var q jobQueue
+	q.configure(JobQueueConfig{Workers: 1, MaxQueued: 1})
+
+	release, _ := q.acquire(context.Background(), PriorityInteractive)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.acquire(ctx, PriorityBackground)
+	waitQueued(t, &q, PriorityBackground, 1)
+
+	if _, err := q.acquire(ctx, PriorityBackground); !errors.Is(err, errQueueFull) {
+		t.Errorf("Expected a full background queue to refuse, got %v", err)
+	}
+	// A background burst does not keep interactive calls from queueing
+	go q.acquire(ctx, PriorityInteractive)
+	waitQueued(t, &q, PriorityInteractive, 1)
+
+	if rejected := q.snapshot()[1].Rejected; rejected != 1 {
+		t.Errorf("Expected one rejected background call, got %d", rejected)
+	}
+	release()
+}
+
+func TestHTTPServerJobQueue(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	blocking := NewBlockingTestTool("blocking")
+	server.RegisterTool(blocking)
+	server.SetJobQueue(JobQueueConfig{Workers: 1, MaxQueued: 1})
+
+	call := func(priority string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/mcp/tools/call", bytes.NewBufferString(`{"name": "blocking"}`))
+		if priority != "" {
+			req.Header.Set(PriorityHeader, priority)
+		}
+		w := httptest.NewRecorder()
+		server.handleToolsCall(w, req)
+		return w
+	}
+
+	if w := call("urgent"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown priority, got %d", w.Code)
+	}
+
+	done := make(chan int, 2)
+	go func() { done <- call("").Code }()
+	<-blocking.Started()
+	go func() { done <- call("background").Code }()
+	waitQueued(t, &server.jobs, PriorityBackground, 1)
+
+	w := call("background")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 429 with Retry-After from a full queue, got %d", w.Code)
+	}
+
+	metrics := httptest.NewRecorder()
+	server.handleMetrics(metrics, httptest.NewRequest("GET", "/metrics", nil))
+	for _, line := range []string{
+		`rodmcp_job_queue_depth{priority="background"} 1`,
+		`rodmcp_job_queue_running{priority="interactive"} 1`,
+		`rodmcp_job_queue_rejected_total{priority="background"} 1`,
+	} {
+		if !strings.Contains(metrics.Body.String(), line) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", line, metrics.Body.String())
+		}
+	}
+
+	blocking.Release()
+	for i := 0; i < 2; i++ {
+		if code := <-done; code != http.StatusOK {
+			t.Errorf("Expected queued calls to succeed, got %d", code)
+		}
+	}
+}
+
+func TestInProcessCallsSkipJobQueue(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	blocking := NewBlockingTestTool("blocking")
+	server.RegisterTool(blocking)
+	server.RegisterTool(NewSimpleTestTool("simple", "Simple", "ok"))
+	server.SetJobQueue(JobQueueConfig{Workers: 1})
+
+	go server.callTool(withJobPriority(context.Background(), PriorityInteractive), "blocking", nil)
+	<-blocking.Started()
+	defer blocking.Release()
+
+	// A workflow step runs while its workflow holds the only worker
+	if _, err := server.CallTool("simple", map[string]interface{}{"message": "hi"}); err != nil {
+		t.Errorf("Expected an in-process call to skip the queue, got %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package mcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"rodmcp/pkg/types"
+)
+
+// ObservableTool is implemented by tools some of whose calls only look:
+// listing pages, reading page info, taking screenshots, reading console logs.
+// Observer connections, such as a dashboard watching an autonomous agent,
+// may make those calls and no others. Tools without it are off limits to
+// observers.
+type ObservableTool interface {
+	// Observes reports whether the call with args changes nothing: no
+	// page, browser setting, file or remote service
+	Observes(args map[string]interface{}) bool
+}
+
+// Scope is what a connection may do
+type Scope string
+
+const (
+	// ScopeFull may call every tool
+	ScopeFull Scope = "full"
+	// ScopeObserver may only make the calls ObservableTools allow
+	ScopeObserver Scope = "observer"
+)
+
+var (
+	errObserverDenied = errors.New("observer connections can only watch")
+	errUnauthorized   = errors.New("missing or wrong bearer token")
+)
+
+// AccessToken lets HTTP clients presenting it as "Authorization: Bearer
+// <token>" in with Scope. In a config file it is one entry of the
+// "access_tokens" array:
+//
+//	{"name": "dashboard", "token": "...", "scope": "observer"}
+type AccessToken struct {
+	// Name identifies the token in configuration errors
+	Name  string `json:"name"`
+	Token string `json:"token"`
+	// TokenEnv names an environment variable holding the token, to keep it
+	// out of the config file
+	TokenEnv string `json:"token_env,omitempty"`
+	// Scope is ScopeFull when empty
+	Scope Scope `json:"scope,omitempty"`
+}
+
+// Validate rejects tokens that could not be presented or scopes that do not
+// exist
+func (t AccessToken) Validate() error {
+	if t.Token == "" {
+		if t.TokenEnv != "" {
+			return fmt.Errorf("access token %q: $%s is not set", t.Name, t.TokenEnv)
+		}
+		return fmt.Errorf("access token %q has no token", t.Name)
+	}
+	switch t.Scope {
+	case "", ScopeFull, ScopeObserver:
+		return nil
+	}
+	return fmt.Errorf("access token %q: unknown scope %q (use full or observer)", t.Name, t.Scope)
+}
+
+// observerPolicy decides which connections are observers
+type observerPolicy struct {
+	// all makes every connection an observer (--observer)
+	all bool
+	// tokens admit HTTP clients; when set, clients without one are refused
+	tokens []AccessToken
+}
+
+// SetObserverMode makes every connection an observer: it can list pages,
+// read page info, take screenshots and read console and network logs, but
+// not change anything
+func (c *core) SetObserverMode(enabled bool) {
+	c.observers.all = enabled
+}
+
+// scopeOf reports the scope calls made with ctx run in
+func (c *core) scopeOf(ctx context.Context) Scope {
+	if c.observers.all {
+		return ScopeObserver
+	}
+	if scope, ok := ctx.Value(scopeKey{}).(Scope); ok && scope != "" {
+		return scope
+	}
+	return ScopeFull
+}
+
+type scopeKey struct{}
+
+// withScope runs the calls made with ctx in scope
+func withScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// observes reports whether an observer may make the call
+func observes(tool Tool, args map[string]interface{}) bool {
+	observable, ok := tool.(ObservableTool)
+	return ok && observable.Observes(args)
+}
+
+// checkScope refuses calls the connection's scope does not allow
+func (c *core) checkScope(ctx context.Context, tool Tool, args map[string]interface{}) error {
+	if c.scopeOf(ctx) != ScopeObserver || observes(tool, args) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s with these arguments could change the browser or server", errObserverDenied, tool.Name())
+}
+
+// visibleTools narrows a tools/list response to the tools an observer can
+// call at all
+func (c *core) visibleTools(ctx context.Context, tools []types.Tool) []types.Tool {
+	if c.scopeOf(ctx) != ScopeObserver {
+		return tools
+	}
+	c.toolsMutex.RLock()
+	defer c.toolsMutex.RUnlock()
+	visible := make([]types.Tool, 0, len(tools))
+	for _, tool := range tools {
+		if _, ok := c.tools[tool.Name].(ObservableTool); ok {
+			visible = append(visible, tool)
+		}
+	}
+	return visible
+}
+
+// SetAccessTokens makes the HTTP MCP endpoints require a bearer token and
+// gives each client its token's scope. Health, readiness and metrics
+// endpoints stay open for orchestrators.
+func (s *HTTPServer) SetAccessTokens(tokens []AccessToken) {
+	s.observers.tokens = tokens
+}
+
+// authenticate finds the scope of the request's bearer token, or fails when
+// tokens are required and the request has none that matches
+func (s *HTTPServer) authenticate(r *http.Request) (AccessToken, error) {
+	if len(s.observers.tokens) == 0 {
+		return AccessToken{Scope: ScopeFull}, nil
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return AccessToken{}, errUnauthorized
+	}
+	for _, token := range s.observers.tokens {
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token.Token)) == 1 {
+			if token.Scope == "" {
+				token.Scope = ScopeFull
+			}
+			return token, nil
+		}
+	}
+	return AccessToken{}, errUnauthorized
+}
+
+// requireToken admits requests with a valid bearer token, running them in
+// the token's scope
+func (s *HTTPServer) requireToken(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, err := s.authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="rodmcp"`)
+			s.sendHTTPError(w, http.StatusUnauthorized, "Unauthorized", err.Error())
+			return
+		}
+		handler(w, r.WithContext(withScope(r.Context(), token.Scope)))
+	}
+}
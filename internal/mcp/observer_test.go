@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/internal/webtools"
+	"rodmcp/pkg/types"
+)
+
+// observingTestTool records calls and lets observers make the "list" ones
+type observingTestTool struct {
+	*RecordingTestTool
+}
+
+func (t observingTestTool) Observes(args map[string]interface{}) bool {
+	action, _ := args["action"].(string)
+	return action == "list"
+}
+
+func newObserverTestTools() (observingTestTool, *RecordingTestTool) {
+	schema := types.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{"action": map[string]interface{}{"type": "string"}},
+	}
+	return observingTestTool{NewRecordingTestTool("tabs", schema)}, NewRecordingTestTool("writer", schema)
+}
+
+func TestCoreObserverMode(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tabs, writer := newObserverTestTools()
+	c.RegisterTool(tabs)
+	c.RegisterTool(writer)
+	ctx := context.Background()
+
+	if _, err := c.callTool(ctx, "writer", nil); err != nil {
+		t.Fatalf("Expected full connections to call anything, got %v", err)
+	}
+
+	c.SetObserverMode(true)
+	if _, err := c.callTool(ctx, "tabs", map[string]interface{}{"action": "list"}); err != nil {
+		t.Errorf("Expected an observer to list tabs, got %v", err)
+	}
+	if _, err := c.callTool(ctx, "tabs", map[string]interface{}{"action": "close"}); !errors.Is(err, errObserverDenied) {
+		t.Errorf("Expected an observer to be refused closing a tab, got %v", err)
+	}
+	if _, err := c.callTool(ctx, "writer", nil); !errors.Is(err, errObserverDenied) {
+		t.Errorf("Expected an observer to be refused a tool that does not observe, got %v", err)
+	}
+	if args := tabs.LastArgs(); args["action"] != "list" {
+		t.Errorf("Expected the refused call never to run, last args %v", args)
+	}
+
+	listed := c.visibleTools(ctx, c.toolList())
+	if len(listed) != 1 || listed[0].Name != "tabs" {
+		t.Errorf("Expected observers to see only observable tools, got %v", listed)
+	}
+}
+
+func TestAccessTokenValidate(t *testing.T) {
+	valid := []AccessToken{{Token: "a"}, {Token: "a", Scope: ScopeObserver}, {Token: "a", Scope: ScopeFull}}
+	for _, token := range valid {
+		if err := token.Validate(); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", token, err)
+		}
+	}
+	invalid := []AccessToken{{Name: "empty"}, {Name: "env", TokenEnv: "UNSET"}, {Token: "a", Scope: "admin"}}
+	for _, token := range invalid {
+		if err := token.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", token)
+		}
+	}
+}
+
+func TestHTTPServerAccessTokens(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	tabs, writer := newObserverTestTools()
+	server.RegisterTool(tabs)
+	server.RegisterTool(writer)
+	server.SetAccessTokens([]AccessToken{
+		{Name: "agent", Token: "agent-token"},
+		{Name: "dashboard", Token: "dashboard-token", Scope: ScopeObserver},
+	})
+
+	request := func(handler http.HandlerFunc, method, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/mcp", bytes.NewBufferString(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		server.requireToken(handler)(w, req)
+		return w
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		w := request(server.handleToolsList, "GET", token, "")
+		if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("Expected token %q to be refused with 401, got %d", token, w.Code)
+		}
+	}
+
+	w := request(server.handleToolsList, "GET", "dashboard-token", "")
+	var list struct {
+		Tools []types.Tool `json:"tools"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &list)
+	if len(list.Tools) != 1 || list.Tools[0].Name != "tabs" {
+		t.Errorf("Expected the observer token to list only tabs, got %v", list.Tools)
+	}
+
+	if w := request(server.handleToolsCall, "POST", "dashboard-token", `{"name": "writer"}`); w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 for an observer calling writer, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := request(server.handleToolsCall, "POST", "dashboard-token", `{"name": "tabs", "arguments": {"action": "list"}}`); w.Code != http.StatusOK {
+		t.Errorf("Expected an observer to list tabs, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := request(server.handleToolsCall, "POST", "agent-token", `{"name": "writer"}`); w.Code != http.StatusOK {
+		t.Errorf("Expected a full token to call writer, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestObserverCannotReadLoggedSecrets(t *testing.T) {
+	logDir := t.TempDir()
+	log, err := logger.New(logger.Config{LogLevel: "info", LogDir: logDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(webtools.NewGetServerLogsTool(log, logDir))
+	ctx := context.Background()
+
+	// Tools log the arguments of the agent's calls
+	log.LogToolExecution("type_text", map[string]interface{}{"selector": "#password", "text": "hunter2"}, true, 1)
+	log.Sync()
+	resp, err := c.callTool(ctx, "get_server_logs", map[string]interface{}{"component": "tools"})
+	if err != nil || !strings.Contains(fmt.Sprint(resp), "type_text") {
+		t.Fatalf("Expected the full connection to read the logged call, got %+v, %v", resp, err)
+	}
+	if strings.Contains(fmt.Sprint(resp), "hunter2") {
+		t.Errorf("Expected the call's arguments to be left out, got %+v", resp)
+	}
+
+	observer := withScope(ctx, ScopeObserver)
+	resp, err = c.callTool(observer, "get_server_logs", nil)
+	if !errors.Is(err, errObserverDenied) || resp != nil {
+		t.Errorf("Expected an observer to be refused the server log, got %+v, %v", resp, err)
+	}
+	for _, tool := range c.visibleTools(observer, c.toolList()) {
+		if tool.Name == "get_server_logs" {
+			t.Error("Expected get_server_logs to be hidden from observers")
+		}
+	}
+}
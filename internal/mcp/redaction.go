@@ -0,0 +1,75 @@
+package mcp
+
+// redactedValue replaces a secret in logged arguments
+const redactedValue = "[redacted]"
+
+// SensitiveTool is implemented by tools that take secrets, such as
+// passwords or authorization headers. The server masks the named arguments
+// wherever it logs a call; an object argument keeps its keys and has each
+// value masked, so the log still shows which headers were set.
+type SensitiveTool interface {
+	SensitiveArguments() []string
+}
+
+// redactArguments returns a copy of args fit for the log. Lists of tool
+// calls, such as run_batch's calls or a workflow's steps, have each call's
+// arguments masked by that tool's rules as well. args is not modified.
+func (c *core) redactArguments(name string, args map[string]interface{}) map[string]interface{} {
+	c.toolsMutex.RLock()
+	tool := c.tools[name]
+	c.toolsMutex.RUnlock()
+
+	sensitive := make(map[string]bool)
+	if st, ok := tool.(SensitiveTool); ok {
+		for _, arg := range st.SensitiveArguments() {
+			sensitive[arg] = true
+		}
+	}
+	redacted := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		if sensitive[key] && value != nil {
+			redacted[key] = maskValue(value)
+		} else {
+			redacted[key] = c.redactNestedCalls(value)
+		}
+	}
+	return redacted
+}
+
+// maskValue masks a secret, keeping an object's keys
+func maskValue(value interface{}) interface{} {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return redactedValue
+	}
+	masked := make(map[string]interface{}, len(object))
+	for key := range object {
+		masked[key] = redactedValue
+	}
+	return masked
+}
+
+// redactNestedCalls masks the arguments of the tool calls ({"tool", "args"}
+// objects) found anywhere inside value
+func (c *core) redactNestedCalls(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = c.redactNestedCalls(item)
+		}
+		return items
+	case map[string]interface{}:
+		object := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			object[key] = c.redactNestedCalls(item)
+		}
+		if tool, ok := v["tool"].(string); ok {
+			if args, ok := v["args"].(map[string]interface{}); ok {
+				object["args"] = c.redactArguments(tool, args)
+			}
+		}
+		return object
+	}
+	return value
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/internal/webtools"
+	"rodmcp/pkg/types"
+)
+
+// sensitiveTestTool records calls and marks its password and headers secret
+type sensitiveTestTool struct {
+	*RecordingTestTool
+}
+
+func (t sensitiveTestTool) SensitiveArguments() []string {
+	return []string{"password", "headers"}
+}
+
+func TestLoggedArgumentsRedacted(t *testing.T) {
+	logDir := t.TempDir()
+	log, err := logger.New(logger.Config{LogLevel: "info", LogDir: logDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(sensitiveTestTool{NewRecordingTestTool("sign_in", types.ToolSchema{Type: "object"})})
+
+	args := map[string]interface{}{
+		"user":     "ann",
+		"password": "hunter2",
+		"headers":  map[string]interface{}{"Authorization": "Bearer s3cret"},
+	}
+	log.LogMCPRequest("tools/call", types.CallToolRequest{Name: "sign_in", Arguments: args})
+	log.LogToolExecution("sign_in", args, true, 1)
+	// Calls made through a batch are masked by their own tool's rules
+	log.LogMCPRequest("tools/call", types.CallToolRequest{Name: "run_batch", Arguments: map[string]interface{}{
+		"calls": []interface{}{map[string]interface{}{"tool": "sign_in", "args": args}},
+	}})
+	log.Sync()
+
+	logged, err := os.ReadFile(filepath.Join(logDir, "rodmcp.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"hunter2", "s3cret"} {
+		if strings.Contains(string(logged), secret) {
+			t.Errorf("%q reached the log:\n%s", secret, logged)
+		}
+	}
+	for _, kept := range []string{`"user":"ann"`, `"Authorization":"[redacted]"`} {
+		if strings.Count(string(logged), kept) != 3 {
+			t.Errorf("Expected %s in every entry:\n%s", kept, logged)
+		}
+	}
+	if args["password"] != "hunter2" {
+		t.Error("Redaction modified the caller's arguments")
+	}
+}
+
+func TestToolSecretsRedactedFromRequestLog(t *testing.T) {
+	logDir := t.TempDir()
+	log, err := logger.New(logger.Config{LogLevel: "info", LogDir: logDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(webtools.NewSetExtraHeadersTool(log, nil))
+	c.RegisterTool(webtools.NewSetHTTPCredentialsTool(log, nil))
+
+	log.LogMCPRequest("tools/call", types.CallToolRequest{Name: "set_extra_headers", Arguments: map[string]interface{}{
+		"headers": map[string]interface{}{"Authorization": "Bearer s3cret", "X-Api-Key": "k3y"},
+	}})
+	log.LogMCPRequest("tools/call", types.CallToolRequest{Name: "set_http_credentials", Arguments: map[string]interface{}{
+		"origin": "https://jenkins.internal", "username": "admin", "password": "hunter2",
+	}})
+	log.Sync()
+
+	logged, err := os.ReadFile(filepath.Join(logDir, "rodmcp.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"s3cret", "k3y", "hunter2"} {
+		if strings.Contains(string(logged), secret) {
+			t.Errorf("%q reached the log:\n%s", secret, logged)
+		}
+	}
+	if !strings.Contains(string(logged), `"X-Api-Key":"[redacted]"`) || !strings.Contains(string(logged), `"username":"admin"`) {
+		t.Errorf("Expected header names and other arguments to stay:\n%s", logged)
+	}
+}
@@ -0,0 +1,54 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// samplingTimeout is how long the server waits for the client's model,
+// including any review of the request by the user
+const samplingTimeout = 2 * time.Minute
+
+// ErrSamplingUnsupported is returned when the client did not declare the
+// sampling capability
+var ErrSamplingUnsupported = errors.New("client does not support sampling")
+
+// Sample has the client's model generate a message for req, so server-side
+// features can get model help without an API key of their own. It fails
+// with ErrSamplingUnsupported when the client cannot be asked. Like Elicit,
+// the wait does not count toward the calling tool's timeout, since clients
+// may show the request to the user first.
+func (s *Server) Sample(req types.CreateMessageRequest) (*types.CreateMessageResult, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, samplingTimeout)
+	defer cancel()
+
+	s.clientMutex.Lock()
+	supported := s.clientCaps.Sampling != nil
+	s.clientMutex.Unlock()
+	if !supported {
+		return nil, ErrSamplingUnsupported
+	}
+	if req.MaxTokens <= 0 {
+		return nil, fmt.Errorf("maxTokens must be positive")
+	}
+
+	s.userWaits.begin()
+	defer s.userWaits.end()
+
+	start := time.Now()
+	var result types.CreateMessageResult
+	if err := s.request(ctx, types.SamplingMethod, req, &result); err != nil {
+		return nil, err
+	}
+	s.logger.WithComponent("mcp").Info("Client model answered",
+		zap.String("model", result.Model),
+		zap.String("stop_reason", result.StopReason),
+		zap.Duration("duration", time.Since(start)))
+	return &result, nil
+}
@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+func TestSampleRoundTrip(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+	go server.Start()
+	defer server.Stop()
+	defer inWriter.Close()
+
+	if _, err := server.Sample(types.CreateMessageRequest{MaxTokens: 10}); !errors.Is(err, ErrSamplingUnsupported) {
+		t.Fatalf("Expected ErrSamplingUnsupported before initialize, got %v", err)
+	}
+
+	lines := make(chan string, 4)
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	readMessage := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case line := <-lines:
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("Invalid message %q: %v", line, err)
+			}
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for message")
+			return nil
+		}
+	}
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{"sampling":{}}}}`)
+	readMessage()
+
+	type answer struct {
+		result *types.CreateMessageResult
+		err    error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		result, err := server.Sample(types.CreateMessageRequest{
+			Messages:  []types.SamplingMessage{{Role: "user", Content: types.SamplingContent{Type: "text", Text: "hi"}}},
+			MaxTokens: 10,
+		})
+		done <- answer{result, err}
+	}()
+
+	request := readMessage()
+	params, _ := request["params"].(map[string]interface{})
+	if request["method"] != types.SamplingMethod || params["maxTokens"] != float64(10) {
+		t.Fatalf("Expected sampling request, got %v", request)
+	}
+	id, _ := json.Marshal(request["id"])
+	fmt.Fprintf(inWriter, `{"jsonrpc":"2.0","id":%s,"result":{"role":"assistant","content":{"type":"text","text":"hello"},"model":"test-model"}}`+"\n", id)
+
+	select {
+	case got := <-done:
+		if got.err != nil || got.result.Content.Text != "hello" || got.result.Model != "test-model" {
+			t.Errorf("Sample = %+v, %v", got.result, got.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Sample never returned")
+	}
+}
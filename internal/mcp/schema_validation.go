@@ -0,0 +1,353 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"rodmcp/pkg/types"
+)
+
+var errInvalidArguments = errors.New("invalid arguments")
+
+// ArgumentError describes one argument that does not fit a tool's
+// InputSchema. Expected echoes the schema fragment the value was checked
+// against so the caller can correct it without another tools/list.
+type ArgumentError struct {
+	Field    string      `json:"field"`
+	Message  string      `json:"message"`
+	Expected interface{} `json:"expected,omitempty"`
+}
+
+// ArgumentsError collects every schema violation in one tool call
+type ArgumentsError struct {
+	Tool   string          `json:"tool"`
+	Errors []ArgumentError `json:"errors"`
+}
+
+func (e *ArgumentsError) Error() string {
+	parts := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		parts = append(parts, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return fmt.Sprintf("invalid arguments for %s: %s", e.Tool, strings.Join(parts, "; "))
+}
+
+func (e *ArgumentsError) Unwrap() error {
+	return errInvalidArguments
+}
+
+// validateArguments checks args against a tool's input schema. It supports
+// the subset of JSON Schema the tools use: type, enum, minimum/maximum,
+// minLength/maxLength, pattern, minItems/maxItems, items, properties,
+// required, additionalProperties, oneOf and anyOf. Unknown keywords and
+// arguments the schema does not mention are accepted.
+func validateArguments(toolName string, schema types.ToolSchema, args map[string]interface{}) error {
+	root := map[string]interface{}{
+		"type":       "object",
+		"properties": schema.Properties,
+	}
+	if len(schema.Required) > 0 {
+		root["required"] = schema.Required
+	}
+
+	// Schemas are written with Go types ([]string, int, nested maps); a JSON
+	// round trip gives the same shapes the arguments arrive in
+	normalized, err := normalizeJSON(root)
+	rootSchema, ok := normalized.(map[string]interface{})
+	if err != nil || !ok {
+		// A schema that cannot be encoded is a tool bug, not a caller error
+		return nil
+	}
+	var argValue interface{} = map[string]interface{}{}
+	if args != nil {
+		if argValue, err = normalizeJSON(args); err != nil {
+			return &ArgumentsError{Tool: toolName, Errors: []ArgumentError{{
+				Field:   "arguments",
+				Message: "arguments are not JSON-encodable: " + err.Error(),
+			}}}
+		}
+	}
+
+	v := &schemaValidator{}
+	v.check("", argValue, rootSchema)
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ArgumentsError{Tool: toolName, Errors: v.errors}
+}
+
+func normalizeJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+type schemaValidator struct {
+	errors []ArgumentError
+}
+
+func (v *schemaValidator) fail(field string, schema map[string]interface{}, format string, a ...interface{}) {
+	if field == "" {
+		field = "arguments"
+	}
+	v.errors = append(v.errors, ArgumentError{
+		Field:    field,
+		Message:  fmt.Sprintf(format, a...),
+		Expected: schemaFragment(schema),
+	})
+}
+
+// schemaFragment trims a schema for echoing back: examples can be long and
+// nested property lists are summarized by name
+func schemaFragment(schema map[string]interface{}) map[string]interface{} {
+	fragment := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "examples":
+			continue
+		case "properties":
+			if props, ok := value.(map[string]interface{}); ok {
+				names := make([]string, 0, len(props))
+				for name := range props {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				fragment["properties"] = names
+				continue
+			}
+		}
+		fragment[key] = value
+	}
+	return fragment
+}
+
+// matches reports whether value satisfies schema without recording errors
+func matches(value interface{}, schema map[string]interface{}) bool {
+	probe := &schemaValidator{}
+	probe.check("", value, schema)
+	return len(probe.errors) == 0
+}
+
+func (v *schemaValidator) check(field string, value interface{}, schema map[string]interface{}) {
+	if alternatives, ok := schema["oneOf"].([]interface{}); ok {
+		v.checkAlternatives(field, value, schema, alternatives)
+		return
+	}
+	if alternatives, ok := schema["anyOf"].([]interface{}); ok {
+		v.checkAlternatives(field, value, schema, alternatives)
+		return
+	}
+
+	if !typeMatches(value, schema["type"]) {
+		v.fail(field, schema, "expected %s, got %s", describeType(schema["type"]), jsonTypeOf(value))
+		return
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		found := false
+		for _, allowed := range enum {
+			if reflect.DeepEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(field, schema, "%s is not one of the allowed values", formatValue(value))
+			return
+		}
+	}
+
+	switch typed := value.(type) {
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && typed < min {
+			v.fail(field, schema, "%v is below the minimum of %v", typed, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && typed > max {
+			v.fail(field, schema, "%v is above the maximum of %v", typed, max)
+		}
+
+	case string:
+		length := len([]rune(typed))
+		if min, ok := schema["minLength"].(float64); ok && float64(length) < min {
+			v.fail(field, schema, "must be at least %v characters", min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && float64(length) > max {
+			v.fail(field, schema, "must be at most %v characters", max)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(typed) {
+				v.fail(field, schema, "%s does not match the pattern %s", formatValue(typed), pattern)
+			}
+		}
+
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(typed)) < min {
+			v.fail(field, schema, "must have at least %v items", min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(typed)) > max {
+			v.fail(field, schema, "must have at most %v items", max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range typed {
+				v.check(fmt.Sprintf("%s[%d]", field, i), item, items)
+			}
+		}
+
+	case map[string]interface{}:
+		v.checkObject(field, typed, schema)
+	}
+}
+
+func (v *schemaValidator) checkAlternatives(field string, value interface{}, schema map[string]interface{}, alternatives []interface{}) {
+	for _, alternative := range alternatives {
+		if alt, ok := alternative.(map[string]interface{}); ok && matches(value, alt) {
+			return
+		}
+	}
+	v.fail(field, schema, "%s does not match any of the accepted forms", jsonTypeOf(value))
+}
+
+func (v *schemaValidator) checkObject(field string, value map[string]interface{}, schema map[string]interface{}) {
+	join := func(name string) string {
+		if field == "" {
+			return name
+		}
+		return field + "." + name
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if argument, present := value[key]; !present || argument == nil {
+				propSchema, _ := properties[key].(map[string]interface{})
+				if propSchema == nil {
+					propSchema = map[string]interface{}{}
+				}
+				v.fail(join(key), propSchema, "is required")
+			}
+		}
+	}
+
+	names := make([]string, 0, len(value))
+	for name := range value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		argument := value[name]
+		if propSchema, ok := properties[name].(map[string]interface{}); ok {
+			// null stands for "not given" on optional arguments
+			if argument != nil {
+				v.check(join(name), argument, propSchema)
+			}
+			continue
+		}
+		switch extra := schema["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				v.fail(join(name), schema, "is not an accepted property")
+			}
+		case map[string]interface{}:
+			if argument != nil {
+				v.check(join(name), argument, extra)
+			}
+		}
+	}
+}
+
+// typeMatches checks a value against a schema type, which may be a single
+// name or a list of names. Types the validator does not know are accepted.
+func typeMatches(value interface{}, schemaType interface{}) bool {
+	switch t := schemaType.(type) {
+	case nil:
+		return true
+	case string:
+		return typeNameMatches(value, t)
+	case []interface{}:
+		for _, name := range t {
+			if s, ok := name.(string); ok && typeNameMatches(value, s) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func typeNameMatches(value interface{}, name string) bool {
+	switch name {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func describeType(schemaType interface{}) string {
+	if names, ok := schemaType.([]interface{}); ok {
+		parts := make([]string, 0, len(names))
+		for _, name := range names {
+			parts = append(parts, fmt.Sprint(name))
+		}
+		return strings.Join(parts, " or ")
+	}
+	return fmt.Sprint(schemaType)
+}
+
+func jsonTypeOf(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func formatValue(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil || len(data) > 80 {
+		return jsonTypeOf(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+func testSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"url":     map[string]interface{}{"type": "string", "minLength": 1},
+			"format":  map[string]interface{}{"type": "string", "enum": []string{"png", "jpeg"}},
+			"quality": map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100},
+			"full":    map[string]interface{}{"type": "boolean"},
+			"tags": map[string]interface{}{
+				"type":     "array",
+				"items":    map[string]interface{}{"type": "string"},
+				"maxItems": 2,
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"row": map[string]interface{}{
+				"oneOf": []map[string]interface{}{{"type": "integer"}, {"type": "string"}},
+			},
+		},
+		Required: []string{"url"},
+	}
+}
+
+func TestValidateArguments(t *testing.T) {
+	valid := []map[string]interface{}{
+		{"url": "https://example.com"},
+		{"url": "x", "format": "png", "quality": float64(80), "full": true},
+		{"url": "x", "quality": 80}, // Go ints from in-process callers
+		{"url": "x", "tags": []interface{}{"a", "b"}, "selectors": map[string]interface{}{"title": "h1"}},
+		{"url": "x", "row": float64(2)},
+		{"url": "x", "row": "header"},
+		{"url": "x", "format": nil},           // null means not given
+		{"url": "x", "unknown_extra": "kept"}, // unlisted arguments are accepted
+	}
+	for _, args := range valid {
+		if err := validateArguments("t", testSchema(), args); err != nil {
+			t.Errorf("%v: unexpected error %v", args, err)
+		}
+	}
+
+	invalid := []struct {
+		args  map[string]interface{}
+		field string
+		want  string
+	}{
+		{map[string]interface{}{}, "url", "is required"},
+		{map[string]interface{}{"url": nil}, "url", "is required"},
+		{map[string]interface{}{"url": ""}, "url", "at least 1"},
+		{map[string]interface{}{"url": float64(5)}, "url", "expected string, got integer"},
+		{map[string]interface{}{"url": "x", "format": "gif"}, "format", "not one of the allowed values"},
+		{map[string]interface{}{"url": "x", "quality": float64(1.5)}, "quality", "expected integer, got number"},
+		{map[string]interface{}{"url": "x", "quality": float64(101)}, "quality", "above the maximum"},
+		{map[string]interface{}{"url": "x", "full": "yes"}, "full", "expected boolean"},
+		{map[string]interface{}{"url": "x", "tags": []interface{}{"a", float64(1)}}, "tags[1]", "expected string"},
+		{map[string]interface{}{"url": "x", "tags": []interface{}{"a", "b", "c"}}, "tags", "at most 2 items"},
+		{map[string]interface{}{"url": "x", "selectors": map[string]interface{}{"n": true}}, "selectors.n", "expected string"},
+		{map[string]interface{}{"url": "x", "row": true}, "row", "accepted forms"},
+	}
+	for _, tc := range invalid {
+		err := validateArguments("t", testSchema(), tc.args)
+		var argsErr *ArgumentsError
+		if !errors.As(err, &argsErr) {
+			t.Errorf("%v: expected ArgumentsError, got %v", tc.args, err)
+			continue
+		}
+		got := argsErr.Errors[0]
+		if got.Field != tc.field || !strings.Contains(got.Message, tc.want) {
+			t.Errorf("%v: got %s: %s, want %s: %s", tc.args, got.Field, got.Message, tc.field, tc.want)
+		}
+		if got.Expected == nil {
+			t.Errorf("%v: no schema fragment echoed", tc.args)
+		}
+	}
+
+	// Every violation is reported, not just the first
+	err := validateArguments("t", testSchema(), map[string]interface{}{"format": "gif", "full": "no"})
+	var argsErr *ArgumentsError
+	if !errors.As(err, &argsErr) || len(argsErr.Errors) != 3 {
+		t.Errorf("Expected 3 errors, got %v", err)
+	}
+}
+
+func TestValidationErrorResponses(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewHTTPServer(log, 8080)
+	server.RegisterTool(NewSimpleTestTool("needs_message", "Needs message", "ok"))
+
+	body, _ := json.Marshal(types.CallToolRequest{Name: "needs_message", Arguments: map[string]interface{}{"message": float64(3)}})
+	rr := httptest.NewRecorder()
+	server.handleToolsCall(rr, httptest.NewRequest("POST", "/mcp/tools/call", bytes.NewReader(body)))
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", rr.Code)
+	}
+
+	var response struct {
+		Error struct {
+			Details ArgumentsError `json:"details"`
+		} `json:"error"`
+	}
+	json.Unmarshal(rr.Body.Bytes(), &response)
+	details := response.Error.Details
+	if details.Tool != "needs_message" || len(details.Errors) != 1 || details.Errors[0].Field != "message" {
+		t.Errorf("details = %+v", details)
+	}
+	expected, _ := details.Errors[0].Expected.(map[string]interface{})
+	if expected["type"] != "string" {
+		t.Errorf("Expected schema fragment with type string, got %v", details.Errors[0].Expected)
+	}
+}
@@ -3,47 +3,46 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"rodmcp/internal/circuitbreaker"
 	"rodmcp/internal/connection"
 	"rodmcp/internal/logger"
 	"rodmcp/pkg/types"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 )
 
 
+// Server speaks MCP as JSON-RPC over stdio
 type Server struct {
-	logger           *logger.Logger
-	tools            map[string]Tool
-	toolsMutex       sync.RWMutex
-	initialized      bool
-	version          types.MCPVersion
-	info             types.ServerInfo
+	*core
 	ctx              context.Context
 	cancel           context.CancelFunc
 	connectionMgr    *connection.ConnectionManager
-	circuitBreaker   *circuitbreaker.MultiLevelCircuitBreaker
-	browserManager   BrowserHealthChecker // Interface for browser health checking
 	lastActivity     time.Time            // Last activity timestamp for heartbeat monitoring
-}
 
-type Tool interface {
-	Name() string
-	Description() string
-	InputSchema() types.ToolSchema
-	Execute(args map[string]interface{}) (*types.CallToolResponse, error)
-}
+	// Keep-alive: ping the client after this much inbound silence (0 disables)
+	keepAliveInterval time.Duration
+	lastInbound       atomic.Int64 // unix nanos of the last message from the client
+	lastPong          atomic.Int64 // unix nanos of the last answered keep-alive ping
+	pingSeq           atomic.Int64
 
-type BrowserHealthChecker interface {
-	CheckHealth() error
-	EnsureHealthy() error
+	// What the client declared in initialize; guarded by clientMutex
+	clientMutex sync.Mutex
+	clientCaps  types.ClientCapabilities
+
+	// Requests sent to the client that are waiting for an answer
+	clientRequests clientRequests
 }
 
+// keepAlivePingPrefix marks the IDs of server-initiated keep-alive pings
+const keepAlivePingPrefix = "rodmcp-ping-"
+
 func NewServer(log *logger.Logger) *Server {
 	ctx, cancel := context.WithCancel(context.Background())
 	
@@ -54,52 +53,34 @@ func NewServer(log *logger.Logger) *Server {
 	connConfig.ReadTimeout = 5 * time.Minute
 	connManager := connection.NewConnectionManager(log, connConfig)
 	
-	// Initialize circuit breakers for different operation types
-	circuitBreaker := circuitbreaker.NewMultiLevelCircuitBreaker()
-	
 	server := &Server{
-		logger:         log,
-		tools:          make(map[string]Tool),
-		version:        types.CurrentMCPVersion,
-		info: types.ServerInfo{
-			Name:    "rodmcp",
-			Version: "1.0.0",
-		},
-		ctx:            ctx,
-		cancel:         cancel,
-		connectionMgr:  connManager,
-		circuitBreaker: circuitBreaker,
-		lastActivity:   time.Now(),
+		core:          newCore(log, "mcp", "rodmcp"),
+		ctx:           ctx,
+		cancel:        cancel,
+		connectionMgr: connManager,
+		lastActivity:  time.Now(),
 	}
-	
-	// Set up circuit breaker callbacks
-	circuitBreaker.BrowserCircuitBreaker.CircuitBreaker.OnStateChange(func(from, to circuitbreaker.State) {
-		log.WithComponent("circuit-breaker").Warn("Browser circuit breaker state changed",
-			zap.String("from", from.String()),
-			zap.String("to", to.String()))
-	})
-	
-	circuitBreaker.NetworkCircuitBreaker.CircuitBreaker.OnStateChange(func(from, to circuitbreaker.State) {
-		log.WithComponent("circuit-breaker").Warn("Network circuit breaker state changed",
-			zap.String("from", from.String()),
-			zap.String("to", to.String()))
-	})
+	server.push = func(method string, params interface{}) error {
+		return server.writeMessage(types.JSONRPCRequest{
+			JSONRPC: "2.0",
+			Method:  method,
+			Params:  params,
+		})
+	}
+	server.elicit = server.elicitContext
 	
 	return server
 }
 
-
-func (s *Server) RegisterTool(tool Tool) {
-	s.toolsMutex.Lock()
-	defer s.toolsMutex.Unlock()
-	s.tools[tool.Name()] = tool
-	s.logger.WithComponent("mcp").Info("Tool registered",
-		zap.String("tool", tool.Name()))
+// SetIO replaces stdin/stdout as the transport streams. It must be called before Start.
+func (s *Server) SetIO(in io.Reader, out io.Writer) {
+	s.connectionMgr.SetIO(in, out)
 }
 
-func (s *Server) SetBrowserManager(browserMgr BrowserHealthChecker) {
-	s.browserManager = browserMgr
-	s.logger.WithComponent("mcp").Info("Browser manager registered for health monitoring")
+// SetKeepAlive enables MCP pings to the client whenever it has been silent
+// for interval, so a stalled pipe is noticed. Zero disables keep-alive.
+func (s *Server) SetKeepAlive(interval time.Duration) {
+	s.keepAliveInterval = interval
 }
 
 func (s *Server) Start() error {
@@ -119,7 +100,12 @@ func (s *Server) startMessageLoop() error {
 	s.logger.WithComponent("mcp").Info("Starting robust message loop with connection management")
 
 	// Start health monitoring in background
-	go s.startHealthMonitor()
+	go s.runHealthMonitor(s.ctx)
+
+	s.lastInbound.Store(time.Now().UnixNano())
+	if s.keepAliveInterval > 0 {
+		go s.startKeepAlive()
+	}
 
 	// Track consecutive timeouts to prevent infinite loops
 	consecutiveTimeouts := 0
@@ -203,6 +189,7 @@ func (s *Server) startMessageLoop() error {
 
 			// Reset timeout counter on successful message read
 			consecutiveTimeouts = 0
+			s.lastInbound.Store(time.Now().UnixNano())
 
 			s.logger.WithComponent("mcp").Debug("Received message",
 				zap.String("message", line))
@@ -217,42 +204,59 @@ func (s *Server) startMessageLoop() error {
 	}
 }
 
-func (s *Server) startHealthMonitor() {
-	ticker := time.NewTicker(30 * time.Second)
+// startKeepAlive pings the client after a period of silence. Pings that go
+// unanswered are logged; a broken pipe surfaces through the write error and
+// the connection manager's recovery.
+func (s *Server) startKeepAlive() {
+	ticker := time.NewTicker(s.keepAliveInterval)
 	defer ticker.Stop()
 
+	unanswered := 0
 	for {
 		select {
 		case <-s.ctx.Done():
 			return
 		case <-ticker.C:
-			// Check browser health if we have a browser manager
-			if s.browserManager != nil {
-				err := s.circuitBreaker.ExecuteBrowserOperation(func() error {
-					return s.browserManager.EnsureHealthy()
-				})
-				
-				if err != nil {
-					// Health check failures are handled by the circuit breaker
-					// Log at debug level to avoid noise
-					s.logger.WithComponent("mcp").Debug("Browser health check failed",
-						zap.Error(err))
-				}
+			lastInbound := time.Unix(0, s.lastInbound.Load())
+			if time.Since(lastInbound) < s.keepAliveInterval {
+				unanswered = 0
+				continue
+			}
+
+			if s.lastPong.Load() < s.lastInbound.Load() {
+				unanswered++
+			}
+			if unanswered == 3 {
+				s.logger.WithComponent("mcp").Warn("Client has not answered keep-alive pings",
+					zap.Duration("silent_for", time.Since(lastInbound)))
+			}
+
+			id := fmt.Sprintf("%s%d", keepAlivePingPrefix, s.pingSeq.Add(1))
+			if err := s.writeMessage(types.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "ping"}); err != nil {
+				s.logger.WithComponent("mcp").Debug("Keep-alive ping failed", zap.Error(err))
 			}
-			
-			// Log connection stats
-			stats := s.connectionMgr.GetStats()
-			s.logger.WithComponent("mcp").Debug("Connection health check",
-				zap.Any("connection_stats", stats))
-			
-			// Log circuit breaker stats
-			cbStats := s.circuitBreaker.GetOverallStats()
-			s.logger.WithComponent("mcp").Debug("Circuit breaker status",
-				zap.Any("circuit_breaker_stats", cbStats))
 		}
 	}
 }
 
+// handleResponse processes responses the client sends to server-initiated requests
+func (s *Server) handleResponse(data []byte) error {
+	var resp types.JSONRPCResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("invalid response from client: %w", err)
+	}
+	if id, ok := resp.ID.(string); ok && strings.HasPrefix(id, keepAlivePingPrefix) {
+		s.lastPong.Store(time.Now().UnixNano())
+		s.logger.WithComponent("mcp").Debug("Keep-alive ping answered", zap.String("id", id))
+		return nil
+	}
+	if s.clientRequests.deliver(resp) {
+		return nil
+	}
+	s.logger.WithComponent("mcp").Debug("Ignoring response to unknown request", zap.Any("id", resp.ID))
+	return nil
+}
+
 func (s *Server) handleMessage(data []byte) error {
 	// Don't process messages if we're not connected
 	if !s.connectionMgr.IsConnected() {
@@ -265,17 +269,33 @@ func (s *Server) handleMessage(data []byte) error {
 		return s.sendError(nil, -32700, "Parse error", nil)
 	}
 
+	// Responses carry no method; never answer them with an error
+	if req.Method == "" && req.ID != nil {
+		return s.handleResponse(data)
+	}
+
 	s.logger.LogMCPRequest(req.Method, req.Params)
 
 	switch req.Method {
+	case "ping":
+		return s.sendResponse(req.ID, map[string]interface{}{})
 	case "initialize":
 		return s.handleInitialize(&req)
 	case "tools/list":
 		return s.handleToolsList(&req)
 	case "tools/call":
-		return s.handleToolsCall(&req)
+		// Run the call off the message loop so the loop can read the
+		// client's answers to requests the tool sends (see Elicit)
+		go func() {
+			if err := s.handleToolsCall(&req); err != nil {
+				s.logger.WithComponent("mcp").Error("Failed to answer tool call", zap.Error(err))
+			}
+		}()
+		return nil
+	case "completion/complete":
+		return s.handleComplete(&req)
 	case "notifications/initialized":
-		s.initialized = true
+		s.initialized.Store(true)
 		s.logger.WithComponent("mcp").Info("Server initialized")
 		return nil
 	default:
@@ -292,6 +312,8 @@ func (s *Server) handleInitialize(req *types.JSONRPCRequest) error {
 		}
 	}
 
+	s.setClientCapabilities(initReq.Capabilities)
+
 	// Version negotiation
 	if initReq.ProtocolVersion != s.version {
 		s.logger.WithComponent("mcp").Warn("Protocol version mismatch",
@@ -302,8 +324,14 @@ func (s *Server) handleInitialize(req *types.JSONRPCRequest) error {
 	response := types.InitializeResponse{
 		ProtocolVersion: s.version,
 		Capabilities: types.ServerCapabilities{
-			Tools:   &types.ToolsCapability{},
-			Logging: &types.LoggingCapability{},
+			Tools:       &types.ToolsCapability{},
+			Logging:     &types.LoggingCapability{},
+			Completions: &types.CompletionsCapability{},
+			Experimental: map[string]interface{}{
+				"pageEvents": map[string]interface{}{
+					"notification": types.PageEventNotificationMethod,
+				},
+			},
 		},
 		ServerInfo: s.info,
 	}
@@ -312,20 +340,8 @@ func (s *Server) handleInitialize(req *types.JSONRPCRequest) error {
 }
 
 func (s *Server) handleToolsList(req *types.JSONRPCRequest) error {
-	s.toolsMutex.RLock()
-	defer s.toolsMutex.RUnlock()
-
-	var tools []types.Tool
-	for _, tool := range s.tools {
-		tools = append(tools, types.Tool{
-			Name:        tool.Name(),
-			Description: tool.Description(),
-			InputSchema: tool.InputSchema(),
-		})
-	}
-
 	result := map[string]interface{}{
-		"tools": tools,
+		"tools": s.visibleTools(s.ctx, s.toolList()),
 	}
 
 	return s.sendResponse(req.ID, result)
@@ -354,50 +370,17 @@ func (s *Server) handleToolsCall(req *types.JSONRPCRequest) error {
 		return s.sendError(req.ID, -32001, "Server not connected", nil)
 	}
 
-	s.toolsMutex.RLock()
-	tool, exists := s.tools[callReq.Name]
-	s.toolsMutex.RUnlock()
-
-	if !exists {
+	result, err := s.callTool(s.ctx, callReq.Name, callReq.Arguments)
+	var argsErr *ArgumentsError
+	switch {
+	case errors.As(err, &argsErr):
+		return s.sendError(req.ID, -32602, "Invalid params: "+argsErr.Error(), argsErr)
+	case errors.Is(err, errToolNotFound):
 		return s.sendError(req.ID, -32601, "Tool not found", nil)
-	}
-
-	s.logger.WithComponent("mcp").Debug("Executing tool", 
-		zap.String("tool", callReq.Name))
-
-	// Create context with 30-second timeout for tool execution
-	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
-	defer cancel()
-
-	// Execute tool with timeout using goroutine
-	type toolResult struct {
-		result interface{}
-		err    error
-	}
-	
-	resultChan := make(chan toolResult, 1)
-	go func() {
-		result, err := tool.Execute(callReq.Arguments)
-		resultChan <- toolResult{result: result, err: err}
-	}()
-
-	var result interface{}
-	var err error
-
-	select {
-	case res := <-resultChan:
-		result = res.result
-		err = res.err
-	case <-ctx.Done():
-		if ctx.Err() == context.DeadlineExceeded {
-			err = fmt.Errorf("tool '%s' execution timed out after 30 seconds", callReq.Name)
-		} else {
-			err = fmt.Errorf("tool '%s' execution cancelled: %v", callReq.Name, ctx.Err())
-		}
-		s.logger.WithComponent("mcp").Warn("Tool execution timed out",
-			zap.String("tool", callReq.Name),
-			zap.Duration("timeout", 30*time.Second),
-			zap.Error(ctx.Err()))
+	case errors.Is(err, errDraining):
+		return s.sendError(req.ID, -32000, "Server is shutting down", nil)
+	case errors.Is(err, errObserverDenied):
+		return s.sendError(req.ID, -32000, "Not allowed for observers", err.Error())
 	}
 	if err != nil {
 		s.logger.LogMCPResponse(req.Method, nil, err)
@@ -408,6 +391,25 @@ func (s *Server) handleToolsCall(req *types.JSONRPCRequest) error {
 	return s.sendResponse(req.ID, result)
 }
 
+func (s *Server) handleComplete(req *types.JSONRPCRequest) error {
+	var completeReq types.CompleteRequest
+	if req.Params != nil {
+		params, _ := json.Marshal(req.Params)
+		if err := json.Unmarshal(params, &completeReq); err != nil {
+			return s.sendError(req.ID, -32602, "Invalid params", nil)
+		}
+	}
+
+	completion, err := s.complete(s.ctx, completeReq)
+	switch {
+	case errors.Is(err, errUnsupportedReference), errors.Is(err, errToolNotFound):
+		return s.sendError(req.ID, -32602, "Invalid params: "+err.Error(), nil)
+	case err != nil:
+		return s.sendError(req.ID, -32603, "Completion failed", err.Error())
+	}
+	return s.sendResponse(req.ID, types.CompleteResult{Completion: completion})
+}
+
 func (s *Server) sendResponse(id interface{}, result interface{}) error {
 	response := types.JSONRPCResponse{
 		JSONRPC: "2.0",
@@ -450,24 +452,6 @@ func (s *Server) writeMessage(message interface{}) error {
 	return nil
 }
 
-func (s *Server) SendLogMessage(level string, message string, data map[string]interface{}) error {
-	logData, _ := json.Marshal(data)
-
-	logMsg := types.LoggingMessage{
-		Level:  level,
-		Data:   json.RawMessage(logData),
-		Logger: "rodmcp",
-	}
-
-	notification := types.JSONRPCRequest{
-		JSONRPC: "2.0",
-		Method:  "notifications/message",
-		Params:  logMsg,
-	}
-
-	return s.writeMessage(notification)
-}
-
 // updateActivity updates the last activity timestamp
 func (s *Server) updateActivity() {
 	s.lastActivity = time.Now()
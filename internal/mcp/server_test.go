@@ -1,10 +1,14 @@
 package mcp
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"rodmcp/internal/logger"
 	"rodmcp/pkg/types"
+	"strings"
 	"testing"
 	"time"
 )
@@ -307,7 +311,7 @@ func TestHandleNotificationsInitialized(t *testing.T) {
 	}
 	defer server.connectionMgr.Stop()
 	
-	if server.initialized {
+	if server.initialized.Load() {
 		t.Error("Server should not be initialized initially")
 	}
 	
@@ -327,7 +331,7 @@ func TestHandleNotificationsInitialized(t *testing.T) {
 		t.Errorf("handleMessage failed for initialized notification: %v", err)
 	}
 	
-	if !server.initialized {
+	if !server.initialized.Load() {
 		t.Error("Server should be initialized after notification")
 	}
 }
@@ -541,4 +545,171 @@ func BenchmarkHandleToolsCall(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = server.handleToolsCall(&reqData)
 	}
-}
\ No newline at end of file
+}
+func TestStdioPingAndEOF(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+
+	done := make(chan error, 1)
+	go func() { done <- server.Start() }()
+	defer server.Stop()
+
+	responses := bufio.NewScanner(outReader)
+	readResponse := func() map[string]interface{} {
+		t.Helper()
+		lines := make(chan string, 1)
+		go func() {
+			if responses.Scan() {
+				lines <- responses.Text()
+			}
+		}()
+		select {
+		case line := <-lines:
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("Invalid response %q: %v", line, err)
+			}
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+			return nil
+		}
+	}
+
+	// A response from the client must not be answered with an error
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":"rodmcp-ping-1","result":{}}`)
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":7,"method":"ping"}`)
+
+	msg := readResponse()
+	if msg["id"] != float64(7) || msg["error"] != nil {
+		t.Fatalf("Expected ping response for id 7, got %v", msg)
+	}
+	if result, ok := msg["result"].(map[string]interface{}); !ok || len(result) != 0 {
+		t.Errorf("Expected empty ping result, got %v", msg["result"])
+	}
+	if server.lastPong.Load() == 0 {
+		t.Error("Expected keep-alive pong to be recorded")
+	}
+
+	// Closing stdin ends the message loop without an error
+	inWriter.Close()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected clean shutdown on EOF, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Server did not stop after stdin closed")
+	}
+}
+
+func TestKeepAlivePingsSilentClient(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+	server.SetKeepAlive(50 * time.Millisecond)
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+	defer inWriter.Close()
+
+	go server.Start()
+	defer server.Stop()
+
+	lines := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		if scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	select {
+	case line := <-lines:
+		var msg types.JSONRPCRequest
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("Invalid ping %q: %v", line, err)
+		}
+		id, _ := msg.ID.(string)
+		if msg.Method != "ping" || !strings.HasPrefix(id, keepAlivePingPrefix) {
+			t.Errorf("Expected keep-alive ping, got %s", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("No keep-alive ping sent")
+	}
+}
+
+func TestDrainWaitsForInFlightToolCall(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	server := NewServer(log)
+	tool := NewBlockingTestTool("slow_tool")
+	server.RegisterTool(tool)
+
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	server.SetIO(inReader, outWriter)
+	go server.Start()
+	defer server.Stop()
+	defer inWriter.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	readResponse := func() map[string]interface{} {
+		t.Helper()
+		select {
+		case line := <-lines:
+			var msg map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &msg); err != nil {
+				t.Fatalf("Invalid response %q: %v", line, err)
+			}
+			return msg
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for response")
+			return nil
+		}
+	}
+
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"slow_tool","arguments":{}}}`)
+	select {
+	case <-tool.Started():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tool call never started")
+	}
+
+	server.BeginDrain()
+	if server.InFlight() != 1 {
+		t.Errorf("Expected 1 call in flight, got %d", server.InFlight())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	err := server.WaitForInFlight(ctx)
+	cancel()
+	if err == nil || !strings.Contains(err.Error(), "1 tool calls still running") {
+		t.Errorf("Expected drain to time out with the call running, got %v", err)
+	}
+
+	tool.Release()
+	if msg := readResponse(); msg["id"] != float64(1) || msg["error"] != nil {
+		t.Fatalf("Expected in-flight call to complete, got %v", msg)
+	}
+	if err := server.WaitForInFlight(context.Background()); err != nil {
+		t.Errorf("Expected drain to finish, got %v", err)
+	}
+
+	// New calls are refused once draining has started
+	fmt.Fprintln(inWriter, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"slow_tool","arguments":{}}}`)
+	msg := readResponse()
+	errObj, _ := msg["error"].(map[string]interface{})
+	if msg["id"] != float64(2) || errObj == nil || errObj["message"] != "Server is shutting down" {
+		t.Errorf("Expected shutting down error, got %v", msg)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"rodmcp/internal/webtools"
 	"rodmcp/internal/logger"
 	"rodmcp/pkg/types"
+	"sync"
 )
 
 // Simple test tool that doesn't require external dependencies
@@ -100,4 +101,115 @@ func (t *ErrorTestTool) Execute(args map[string]interface{}) (*types.CallToolRes
 // Real help tool wrapper for testing
 func NewTestHelpTool(log *logger.Logger) Tool {
 	return webtools.NewHelpTool(log)
-}
\ No newline at end of file
+}
+// BlockingTestTool holds each call until Release is called, for testing
+// behaviour while a tool call is in flight
+type BlockingTestTool struct {
+	name    string
+	started chan struct{}
+	release chan struct{}
+}
+
+func NewBlockingTestTool(name string) *BlockingTestTool {
+	return &BlockingTestTool{
+		name:    name,
+		started: make(chan struct{}, 16),
+		release: make(chan struct{}),
+	}
+}
+
+func (t *BlockingTestTool) Name() string {
+	return t.name
+}
+
+func (t *BlockingTestTool) Description() string {
+	return "Blocks until released"
+}
+
+func (t *BlockingTestTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{Type: "object", Properties: map[string]interface{}{}}
+}
+
+// Started is signalled each time a call begins executing
+func (t *BlockingTestTool) Started() <-chan struct{} {
+	return t.started
+}
+
+// Release lets every blocked and future call return
+func (t *BlockingTestTool) Release() {
+	close(t.release)
+}
+
+func (t *BlockingTestTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	t.started <- struct{}{}
+	<-t.release
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{Type: "text", Text: "released"}},
+	}, nil
+}
+
+// PanicTestTool panics on every call, for testing panic recovery
+type PanicTestTool struct {
+	name string
+}
+
+func NewPanicTestTool(name string) *PanicTestTool {
+	return &PanicTestTool{name: name}
+}
+
+func (t *PanicTestTool) Name() string {
+	return t.name
+}
+
+func (t *PanicTestTool) Description() string {
+	return "Panics when called"
+}
+
+func (t *PanicTestTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{Type: "object", Properties: map[string]interface{}{}}
+}
+
+func (t *PanicTestTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	panic("tool exploded")
+}
+
+// RecordingTestTool keeps the arguments of its last call, for testing what
+// reaches a tool after the server has processed them
+type RecordingTestTool struct {
+	name   string
+	schema types.ToolSchema
+	mu     sync.Mutex
+	args   map[string]interface{}
+}
+
+func NewRecordingTestTool(name string, schema types.ToolSchema) *RecordingTestTool {
+	return &RecordingTestTool{name: name, schema: schema}
+}
+
+func (t *RecordingTestTool) Name() string {
+	return t.name
+}
+
+func (t *RecordingTestTool) Description() string {
+	return "Records the arguments it is called with"
+}
+
+func (t *RecordingTestTool) InputSchema() types.ToolSchema {
+	return t.schema
+}
+
+// LastArgs returns the arguments of the most recent call
+func (t *RecordingTestTool) LastArgs() map[string]interface{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.args
+}
+
+func (t *RecordingTestTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	t.mu.Lock()
+	t.args = args
+	t.mu.Unlock()
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{Type: "text", Text: "recorded"}},
+	}, nil
+}
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultTimeoutKey is the ToolTimeouts entry used for tools without one of
+// their own
+const DefaultTimeoutKey = "default"
+
+// perCallTimeoutGrace is added to a call's own "timeout" argument so the tool
+// can report its timeout before the server gives up on it
+const perCallTimeoutGrace = 5 * time.Second
+
+// ToolTimeouts maps tool names to how long a call may run. In a config file
+// each value is a duration string ("45s", "2m") or a number of seconds.
+type ToolTimeouts map[string]time.Duration
+
+func (t *ToolTimeouts) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	timeouts := make(ToolTimeouts, len(raw))
+	for name, value := range raw {
+		d, err := parseConfigDuration(value)
+		if err != nil {
+			return fmt.Errorf("timeout for %s %w", name, err)
+		}
+		timeouts[name] = d
+	}
+	*t = timeouts
+	return nil
+}
+
+// parseConfigDuration reads a positive duration from a decoded JSON value:
+// a duration string ("45s", "2m") or a number of seconds
+func parseConfigDuration(value interface{}) (time.Duration, error) {
+	var d time.Duration
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("is invalid: %w", err)
+		}
+		d = parsed
+	case float64:
+		d = time.Duration(v * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("must be a duration string or a number of seconds")
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("must be positive")
+	}
+	return d, nil
+}
+
+// Lookup returns the configured timeout for a tool, falling back to the
+// default entry
+func (t ToolTimeouts) Lookup(name string) (time.Duration, bool) {
+	if d, ok := t[name]; ok {
+		return d, true
+	}
+	d, ok := t[DefaultTimeoutKey]
+	return d, ok
+}
+
+// SetToolTimeouts replaces the hardcoded tool call timeout with per-tool
+// values. It returns the configured names that match no registered tool, so
+// call it after registering tools.
+func (c *core) SetToolTimeouts(timeouts ToolTimeouts) []string {
+	c.toolsMutex.Lock()
+	defer c.toolsMutex.Unlock()
+	c.timeouts = timeouts
+
+	var unknown []string
+	for name := range timeouts {
+		if _, exists := c.tools[name]; !exists && name != DefaultTimeoutKey {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
+
+// callTimeout is how long a call to tool may run: the configured timeout
+// for the tool (or toolCallTimeout), raised to fit a longer "timeout"
+// argument (seconds) when the tool accepts one
+func (c *core) callTimeout(tool Tool, args map[string]interface{}) time.Duration {
+	c.toolsMutex.RLock()
+	limit, ok := c.timeouts.Lookup(tool.Name())
+	c.toolsMutex.RUnlock()
+	if !ok {
+		limit = toolCallTimeout
+	}
+
+	if _, declared := tool.InputSchema().Properties["timeout"]; declared {
+		if seconds, ok := args["timeout"].(float64); ok && seconds > 0 {
+			if requested := time.Duration(seconds*float64(time.Second)) + perCallTimeoutGrace; requested > limit {
+				limit = requested
+			}
+		}
+	}
+	return limit
+}
@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// timeoutArgTool is a SimpleTestTool that declares a "timeout" argument
+type timeoutArgTool struct {
+	*SimpleTestTool
+}
+
+func (t timeoutArgTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"timeout": map[string]interface{}{"type": "number"},
+		},
+	}
+}
+
+func TestToolTimeoutsUnmarshal(t *testing.T) {
+	var config struct {
+		Timeouts ToolTimeouts `json:"timeouts"`
+	}
+	if err := json.Unmarshal([]byte(`{"timeouts": {"navigate_page": "45s", "execute_script": 90, "default": "1m"}}`), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.Timeouts["navigate_page"] != 45*time.Second || config.Timeouts["execute_script"] != 90*time.Second {
+		t.Errorf("timeouts = %v", config.Timeouts)
+	}
+	if d, ok := config.Timeouts.Lookup("screen_scrape"); !ok || d != time.Minute {
+		t.Errorf("default lookup = %v, %v", d, ok)
+	}
+
+	for _, bad := range []string{`{"a": "soon"}`, `{"a": 0}`, `{"a": true}`} {
+		var timeouts ToolTimeouts
+		if err := json.Unmarshal([]byte(bad), &timeouts); err == nil {
+			t.Errorf("expected an error for %s", bad)
+		}
+	}
+}
+
+func TestCoreToolTimeouts(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	blocking := NewBlockingTestTool("blocking")
+	c.RegisterTool(blocking)
+	c.RegisterTool(NewSimpleTestTool("simple", "Simple", "ok"))
+	withArg := timeoutArgTool{NewSimpleTestTool("waiter", "Waiter", "ok")}
+	c.RegisterTool(withArg)
+
+	unknown := c.SetToolTimeouts(ToolTimeouts{"blocking": 50 * time.Millisecond, "waiter": 20 * time.Second, "typo_tool": time.Second})
+	if len(unknown) != 1 || unknown[0] != "typo_tool" {
+		t.Errorf("unknown names = %v", unknown)
+	}
+
+	if got := c.callTimeout(c.tools["simple"], nil); got != toolCallTimeout {
+		t.Errorf("unconfigured tool timeout = %v", got)
+	}
+	// A per-call timeout longer than the configured one wins; shorter ones
+	// leave the configured limit alone
+	if got := c.callTimeout(withArg, map[string]interface{}{"timeout": float64(60)}); got != 60*time.Second+perCallTimeoutGrace {
+		t.Errorf("per-call timeout = %v", got)
+	}
+	if got := c.callTimeout(withArg, map[string]interface{}{"timeout": float64(5)}); got != 20*time.Second {
+		t.Errorf("short per-call timeout = %v", got)
+	}
+	// Tools without a timeout argument ignore one
+	if got := c.callTimeout(c.tools["simple"], map[string]interface{}{"timeout": float64(600)}); got != toolCallTimeout {
+		t.Errorf("undeclared timeout argument changed the limit: %v", got)
+	}
+
+	start := time.Now()
+	if _, err := c.callTool(context.Background(), "blocking", nil); !errors.Is(err, errToolTimeout) {
+		t.Errorf("Expected errToolTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("configured timeout not applied, call took %v", elapsed)
+	}
+	blocking.Release()
+	if err := c.WaitForInFlight(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// contextTestTool is a SimpleTestTool that waits for its call to end
+type contextTestTool struct {
+	*SimpleTestTool
+	deadline chan time.Time
+	stopped  chan error
+}
+
+func (t contextTestTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	deadline, _ := ctx.Deadline()
+	t.deadline <- deadline
+	<-ctx.Done()
+	t.stopped <- ctx.Err()
+	return nil, ctx.Err()
+}
+
+func TestContextToolGetsCallDeadline(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := contextTestTool{
+		SimpleTestTool: NewSimpleTestTool("ctx_tool", "Context tool", "ok"),
+		deadline:       make(chan time.Time, 1),
+		stopped:        make(chan error, 1),
+	}
+	c.RegisterTool(tool)
+	c.SetToolTimeouts(ToolTimeouts{"ctx_tool": 50 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := c.callTool(context.Background(), "ctx_tool", map[string]interface{}{"message": "hi"}); !errors.Is(err, errToolTimeout) {
+		t.Fatalf("Expected errToolTimeout, got %v", err)
+	}
+	if deadline := <-tool.deadline; deadline.IsZero() || deadline.Sub(start) > time.Second {
+		t.Errorf("Expected the call's deadline, got %v", deadline)
+	}
+	// The tool is told to stop rather than left running after the timeout
+	select {
+	case err := <-tool.stopped:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the deadline to stop the tool, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tool was not stopped when its call timed out")
+	}
+	if err := c.WaitForInFlight(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallToolContextEndsWithCaller(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := contextTestTool{
+		SimpleTestTool: NewSimpleTestTool("ctx_tool", "Context tool", "ok"),
+		deadline:       make(chan time.Time, 1),
+		stopped:        make(chan error, 1),
+	}
+	c.RegisterTool(tool)
+
+	// A step of a batch with 50ms left gets those 50ms, not its own timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.CallToolContext(withJobPriority(ctx, PriorityBackground), "ctx_tool", map[string]interface{}{"message": "hi"}); !errors.Is(err, errToolTimeout) {
+		t.Fatalf("Expected errToolTimeout, got %v", err)
+	}
+	if err := <-tool.stopped; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the caller's deadline to stop the tool, got %v", err)
+	}
+	if err := c.WaitForInFlight(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
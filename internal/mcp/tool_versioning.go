@@ -0,0 +1,107 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+
+	"rodmcp/pkg/types"
+)
+
+// MetadataTool is implemented by tools that declare a version, are
+// deprecated, or have renamed parameters. Tools without it are reported at
+// types.DefaultToolVersion.
+type MetadataTool interface {
+	Meta() types.ToolMeta
+}
+
+// ToolMeta returns the version and deprecation metadata published for tool
+func ToolMeta(tool Tool) types.ToolMeta {
+	var meta types.ToolMeta
+	if described, ok := tool.(MetadataTool); ok {
+		meta = described.Meta()
+	}
+	if meta.Version == "" {
+		meta.Version = types.DefaultToolVersion
+	}
+	return meta
+}
+
+// resolveLegacyArguments applies a tool's parameter renames and collects
+// deprecation warnings for the call. In compatibility mode an old parameter
+// name is moved to its current name with a warning; otherwise it is
+// rejected so the caller learns about the rename instead of the argument
+// being silently ignored. args is not modified.
+func resolveLegacyArguments(tool Tool, args map[string]interface{}, compat bool) (map[string]interface{}, []string, error) {
+	meta := ToolMeta(tool)
+	name := tool.Name()
+
+	var warnings []string
+	if meta.Deprecated {
+		warning := fmt.Sprintf("tool '%s' is deprecated", name)
+		if meta.DeprecationMessage != "" {
+			warning += ": " + meta.DeprecationMessage
+		}
+		if meta.ReplacedBy != "" {
+			warning += fmt.Sprintf(" (use '%s' instead)", meta.ReplacedBy)
+		}
+		warnings = append(warnings, warning)
+	}
+
+	oldNames := make([]string, 0, len(meta.RenamedParameters))
+	for oldName := range meta.RenamedParameters {
+		if _, present := args[oldName]; present {
+			oldNames = append(oldNames, oldName)
+		}
+	}
+	sort.Strings(oldNames)
+
+	if len(oldNames) > 0 {
+		if !compat {
+			argErr := &ArgumentsError{Tool: name}
+			for _, oldName := range oldNames {
+				argErr.Errors = append(argErr.Errors, ArgumentError{
+					Field: oldName,
+					Message: fmt.Sprintf("was renamed to '%s' in version %s",
+						meta.RenamedParameters[oldName], meta.Version),
+				})
+			}
+			return nil, nil, argErr
+		}
+
+		renamed := make(map[string]interface{}, len(args))
+		for key, value := range args {
+			renamed[key] = value
+		}
+		for _, oldName := range oldNames {
+			newName := meta.RenamedParameters[oldName]
+			delete(renamed, oldName)
+			if _, given := args[newName]; given {
+				warnings = append(warnings, fmt.Sprintf(
+					"parameter '%s' of %s was renamed to '%s'; both were given, so '%s' was ignored",
+					oldName, name, newName, oldName))
+				continue
+			}
+			renamed[newName] = args[oldName]
+			warnings = append(warnings, fmt.Sprintf(
+				"parameter '%s' of %s was renamed to '%s' in version %s; the old name still works but will be removed",
+				oldName, name, newName, meta.Version))
+		}
+		args = renamed
+	}
+
+	// Parameters marked "deprecated" in the schema still work
+	properties := tool.InputSchema().Properties
+	given := make([]string, 0, len(args))
+	for key := range args {
+		given = append(given, key)
+	}
+	sort.Strings(given)
+	for _, key := range given {
+		prop, _ := properties[key].(map[string]interface{})
+		if deprecated, _ := prop["deprecated"].(bool); deprecated {
+			warnings = append(warnings, fmt.Sprintf("parameter '%s' of %s is deprecated", key, name))
+		}
+	}
+
+	return args, warnings, nil
+}
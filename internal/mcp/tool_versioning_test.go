@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// renamedTestTool is a recording tool whose "target" parameter used to be
+// called "element"
+type renamedTestTool struct {
+	*RecordingTestTool
+	meta types.ToolMeta
+}
+
+func newRenamedTestTool(meta types.ToolMeta) *renamedTestTool {
+	return &renamedTestTool{
+		RecordingTestTool: NewRecordingTestTool("renamed", types.ToolSchema{
+			Type: "object",
+			Properties: map[string]interface{}{
+				"target": map[string]interface{}{"type": "string"},
+				"legacy": map[string]interface{}{"type": "boolean", "deprecated": true},
+			},
+			Required: []string{"target"},
+		}),
+		meta: meta,
+	}
+}
+
+func (t *renamedTestTool) Meta() types.ToolMeta {
+	return t.meta
+}
+
+func TestToolMetaDefaults(t *testing.T) {
+	meta := ToolMeta(NewSimpleTestTool("plain", "plain", "ok"))
+	if meta.Version != types.DefaultToolVersion || meta.Deprecated {
+		t.Errorf("unexpected default metadata %+v", meta)
+	}
+
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(newRenamedTestTool(types.ToolMeta{Version: "2.0.0"}))
+	listed := c.toolList()
+	if len(listed) != 1 || listed[0].Meta == nil || listed[0].Meta.Version != "2.0.0" {
+		t.Errorf("tools/list metadata = %+v", listed)
+	}
+}
+
+func TestCallToolRenamedParameterCompat(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	tool := newRenamedTestTool(types.ToolMeta{
+		Version:           "2.0.0",
+		RenamedParameters: map[string]string{"element": "target"},
+	})
+	c.RegisterTool(tool)
+
+	args := map[string]interface{}{"element": "#menu"}
+	result, err := c.callTool(context.Background(), "renamed", args)
+	if err != nil {
+		t.Fatalf("call with old parameter name failed: %v", err)
+	}
+	if got := tool.LastArgs(); got["target"] != "#menu" || got["element"] != nil {
+		t.Errorf("tool received %v", got)
+	}
+	if _, stillThere := args["element"]; !stillThere {
+		t.Error("caller's arguments were modified")
+	}
+	last := result.Content[len(result.Content)-1].Text
+	if !strings.Contains(last, "renamed to 'target'") {
+		t.Errorf("expected rename warning in result, got %q", last)
+	}
+
+	// The current name wins when both are given
+	if _, err := c.callTool(context.Background(), "renamed", map[string]interface{}{
+		"element": "old", "target": "new",
+	}); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if got := tool.LastArgs()["target"]; got != "new" {
+		t.Errorf("target = %v, want new", got)
+	}
+}
+
+func TestCallToolRenamedParameterStrict(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.SetCompatibilityMode(false)
+	c.RegisterTool(newRenamedTestTool(types.ToolMeta{
+		Version:           "2.0.0",
+		RenamedParameters: map[string]string{"element": "target"},
+	}))
+
+	_, err := c.callTool(context.Background(), "renamed", map[string]interface{}{"element": "#menu"})
+	var argErr *ArgumentsError
+	if !errors.As(err, &argErr) || argErr.Errors[0].Field != "element" {
+		t.Fatalf("expected ArgumentsError for element, got %v", err)
+	}
+	if !strings.Contains(argErr.Errors[0].Message, "renamed to 'target'") {
+		t.Errorf("unexpected message %q", argErr.Errors[0].Message)
+	}
+}
+
+func TestCallToolDeprecationWarnings(t *testing.T) {
+	log, _ := logger.New(logger.Config{LogLevel: "error", LogDir: "/tmp"})
+	c := newCore(log, "mcp", "rodmcp")
+	c.RegisterTool(newRenamedTestTool(types.ToolMeta{
+		Version:            "1.2.0",
+		Deprecated:         true,
+		DeprecationMessage: "superseded",
+		ReplacedBy:         "replacement",
+	}))
+
+	result, err := c.callTool(context.Background(), "renamed", map[string]interface{}{
+		"target": "x", "legacy": true,
+	})
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	var text []string
+	for _, content := range result.Content {
+		text = append(text, content.Text)
+	}
+	joined := strings.Join(text, "\n")
+	for _, want := range []string{"tool 'renamed' is deprecated: superseded (use 'replacement' instead)", "parameter 'legacy' of renamed is deprecated"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("result missing %q:\n%s", want, joined)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package secrets loads named credential profiles from a local JSON file so
+// tools can sign in without passwords ever passing through tool arguments,
+// responses or logs.
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Profile is one set of credentials, optionally with a login recipe
+// describing the site's sign-in form
+type Profile struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	// Login holds recipe keys such as url, username_selector,
+	// password_selector, submit_selector and success_selector
+	Login map[string]string `json:"login,omitempty"`
+}
+
+// Store holds the profiles read from a secrets file
+type Store struct {
+	path     string
+	profiles map[string]Profile
+	mode     os.FileMode
+}
+
+// Empty returns a store with no profiles, for when no file is configured
+func Empty() *Store {
+	return &Store{profiles: map[string]Profile{}}
+}
+
+// Load reads a secrets file of the form
+//
+//	{"profiles": {"github": {"username": "me", "password": "env:GITHUB_PASSWORD"}}}
+//
+// Username and password values of the form env:NAME are read from the
+// environment when the profile is used, so the file need not hold them.
+func Load(path string) (*Store, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets file: %w", err)
+	}
+
+	var file struct {
+		Profiles map[string]Profile `json:"profiles"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse secrets file %s: %w", path, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = map[string]Profile{}
+	}
+	return &Store{path: path, profiles: file.Profiles, mode: info.Mode().Perm()}, nil
+}
+
+// Path returns the file the store was loaded from ("" for an empty store)
+func (s *Store) Path() string {
+	return s.path
+}
+
+// ReadableByOthers reports whether the secrets file grants any group or
+// world permissions
+func (s *Store) ReadableByOthers() bool {
+	return s.mode&0077 != 0
+}
+
+// Names lists the profile names, sorted
+func (s *Store) Names() []string {
+	names := make([]string, 0, len(s.profiles))
+	for name := range s.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Profile returns the named profile with env: references resolved
+func (s *Store) Profile(name string) (Profile, error) {
+	profile, ok := s.profiles[name]
+	if !ok {
+		if s.path == "" {
+			return Profile{}, fmt.Errorf("no secrets file configured (start the server with --secrets-file)")
+		}
+		return Profile{}, fmt.Errorf("no credential profile named %q", name)
+	}
+
+	var err error
+	if profile.Username, err = resolve(profile.Username); err != nil {
+		return Profile{}, fmt.Errorf("profile %s username: %w", name, err)
+	}
+	if profile.Password, err = resolve(profile.Password); err != nil {
+		return Profile{}, fmt.Errorf("profile %s password: %w", name, err)
+	}
+	return profile, nil
+}
+
+// resolve expands an env:NAME reference
+func resolve(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, "env:")
+	if !ok {
+		return value, nil
+	}
+	resolved, set := os.LookupEnv(name)
+	if !set {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return resolved, nil
+}
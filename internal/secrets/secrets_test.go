@@ -0,0 +1,76 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeSecrets(t *testing.T, content string, mode os.FileMode) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "secrets.json")
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAndResolve(t *testing.T) {
+	t.Setenv("RODMCP_TEST_PASSWORD", "hunter2")
+	path := writeSecrets(t, `{"profiles": {
+		"github": {"username": "me", "password": "env:RODMCP_TEST_PASSWORD",
+		           "login": {"url": "https://github.com/login"}},
+		"broken": {"username": "me", "password": "env:RODMCP_TEST_UNSET"}
+	}}`, 0600)
+
+	store, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if store.ReadableByOthers() {
+		t.Error("0600 file reported as readable by others")
+	}
+	if got := strings.Join(store.Names(), ","); got != "broken,github" {
+		t.Errorf("Names = %s", got)
+	}
+
+	profile, err := store.Profile("github")
+	if err != nil {
+		t.Fatalf("Profile: %v", err)
+	}
+	if profile.Username != "me" || profile.Password != "hunter2" || profile.Login["url"] != "https://github.com/login" {
+		t.Errorf("profile = %+v", profile)
+	}
+
+	if _, err := store.Profile("broken"); err == nil || !strings.Contains(err.Error(), "RODMCP_TEST_UNSET") {
+		t.Errorf("Expected unset variable error, got %v", err)
+	}
+	if _, err := store.Profile("missing"); err == nil {
+		t.Error("Expected error for missing profile")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+	if _, err := Load(writeSecrets(t, `{`, 0600)); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+
+	store, err := Load(writeSecrets(t, `{}`, 0644))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !store.ReadableByOthers() {
+		t.Error("0644 file not reported as readable by others")
+	}
+}
+
+func TestEmptyStore(t *testing.T) {
+	_, err := Empty().Profile("github")
+	if err == nil || !strings.Contains(err.Error(), "--secrets-file") {
+		t.Errorf("Expected hint about --secrets-file, got %v", err)
+	}
+}
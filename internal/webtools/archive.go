@@ -0,0 +1,422 @@
+package webtools
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Archive formats
+const (
+	archiveHTML  = "html"
+	archiveMHTML = "mhtml"
+)
+
+// maxImportDepth bounds how deeply nested @imports are inlined
+const maxImportDepth = 4
+
+var (
+	cssURLPattern    = regexp.MustCompile(`url\(\s*("[^"]*"|'[^']*'|[^)\s]*)\s*\)`)
+	cssImportPattern = regexp.MustCompile(`@import\s+("[^"]*"|'[^']*')`)
+)
+
+// linkAttributes are made absolute so links in an archive still lead to
+// the live site
+var linkAttributes = map[string]bool{"href": true, "action": true, "formaction": true, "cite": true}
+
+// assetAttributes are inlined when the browser has the resource cached
+// and made absolute otherwise
+var assetAttributes = map[string]bool{"src": true, "poster": true, "background": true, "data": true}
+
+// archiveStats counts what archiving did with a page's references
+type archiveStats struct {
+	Inlined int      // resources embedded as data URIs
+	Missing []string // resources the browser didn't have, linked instead
+}
+
+// pageArchiver rewrites a snapshot's HTML into a self-contained document
+type pageArchiver struct {
+	resources map[string]browser.PageResource
+	stats     archiveStats
+}
+
+// resolveArchiveURL returns ref as an absolute URL; fragments and data:,
+// javascript: and similar URLs are returned unchanged
+func resolveArchiveURL(base *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") || base == nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return ref
+	}
+	return base.ResolveReference(u).String()
+}
+
+// inline returns a data URI for the resource at ref, or its absolute URL
+// when the browser didn't have it
+func (a *pageArchiver) inline(base *url.URL, ref string, depth int) string {
+	abs := resolveArchiveURL(base, ref)
+	if !strings.HasPrefix(abs, "http:") && !strings.HasPrefix(abs, "https:") {
+		return abs
+	}
+	res, ok := a.resources[abs]
+	if !ok {
+		// The cache keys URLs without their fragment, e.g. sprite.svg#icon
+		if i := strings.IndexByte(abs, '#'); i >= 0 {
+			if res, ok = a.resources[abs[:i]]; ok {
+				return a.dataURI(abs[:i], res, depth) + abs[i:]
+			}
+		}
+		a.stats.Missing = append(a.stats.Missing, abs)
+		return abs
+	}
+	return a.dataURI(abs, res, depth)
+}
+
+func (a *pageArchiver) dataURI(abs string, res browser.PageResource, depth int) string {
+	a.stats.Inlined++
+	content := res.Content
+	mimeType := res.MIMEType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	if mimeType == "text/css" {
+		base, _ := url.Parse(abs)
+		content = []byte(a.css(string(content), base, depth+1))
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(content)
+}
+
+// css inlines the url() and @import references in a stylesheet, resolved
+// against base
+func (a *pageArchiver) css(css string, base *url.URL, depth int) string {
+	if depth > maxImportDepth {
+		return css
+	}
+	css = cssImportPattern.ReplaceAllStringFunc(css, func(match string) string {
+		ref := cssImportPattern.FindStringSubmatch(match)[1]
+		return "@import url(" + ref + ")"
+	})
+	return cssURLPattern.ReplaceAllStringFunc(css, func(match string) string {
+		ref := strings.Trim(cssURLPattern.FindStringSubmatch(match)[1], `"'`)
+		if ref == "" || strings.HasPrefix(ref, "data:") || strings.HasPrefix(ref, "#") {
+			return match
+		}
+		return `url("` + a.inline(base, ref, depth) + `")`
+	})
+}
+
+// srcset inlines each candidate URL of a srcset attribute
+func (a *pageArchiver) srcset(value string, base *url.URL) string {
+	if strings.Contains(value, "data:") {
+		return value
+	}
+	candidates := strings.Split(value, ",")
+	for i, candidate := range candidates {
+		fields := strings.Fields(candidate)
+		if len(fields) == 0 {
+			continue
+		}
+		fields[0] = a.inline(base, fields[0], 0)
+		candidates[i] = strings.Join(fields, " ")
+	}
+	return strings.Join(candidates, ", ")
+}
+
+// htmlAttr returns the value of n's key attribute, or "" when it has none
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// droppedFromArchive reports whether an element has no place in an archive:
+// scripts would change or re-fetch the page, <base> is replaced by
+// absolute URLs and a CSP would block the inlined data URIs
+func droppedFromArchive(n *html.Node) bool {
+	switch n.Data {
+	case "script", "base":
+		return true
+	case "link":
+		rel := strings.ToLower(htmlAttr(n, "rel"))
+		return strings.Contains(rel, "preload") || strings.Contains(rel, "prefetch") || strings.Contains(rel, "preconnect") || strings.Contains(rel, "dns-prefetch")
+	case "meta":
+		equiv := strings.ToLower(htmlAttr(n, "http-equiv"))
+		// The archive is written as UTF-8 whatever the page declared
+		return equiv == "content-security-policy" || equiv == "content-type" || htmlAttr(n, "charset") != ""
+	}
+	return false
+}
+
+// embeddedLink reports whether a <link> with this rel is part of how the
+// page looks, like icons and stylesheets, rather than a pointer to
+// another document
+func embeddedLink(rel string) bool {
+	rel = strings.ToLower(rel)
+	return strings.Contains(rel, "icon") || strings.Contains(rel, "stylesheet")
+}
+
+// rewrite inlines or absolutizes the references of n and its descendants
+func (a *pageArchiver) rewrite(n *html.Node, base *url.URL) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.ElementNode && droppedFromArchive(c) {
+			n.RemoveChild(c)
+		} else {
+			a.rewrite(c, base)
+		}
+		c = next
+	}
+	if n.Type != html.ElementNode {
+		return
+	}
+
+	if n.Data == "style" && n.FirstChild != nil && n.FirstChild.Type == html.TextNode {
+		n.FirstChild.Data = a.css(n.FirstChild.Data, base, 0)
+	}
+	if n.Data == "link" && strings.Contains(strings.ToLower(htmlAttr(n, "rel")), "stylesheet") {
+		if res, ok := a.resources[resolveArchiveURL(base, htmlAttr(n, "href"))]; ok {
+			// Swap the link for the stylesheet itself, keeping its media query
+			sheet, _ := url.Parse(resolveArchiveURL(base, htmlAttr(n, "href")))
+			a.stats.Inlined++
+			style := &html.Node{Type: html.ElementNode, Data: "style"}
+			if media := htmlAttr(n, "media"); media != "" {
+				style.Attr = []html.Attribute{{Key: "media", Val: media}}
+			}
+			style.AppendChild(&html.Node{Type: html.TextNode, Data: a.css(string(res.Content), sheet, 1)})
+			n.Parent.InsertBefore(style, n)
+			n.Parent.RemoveChild(n)
+			return
+		}
+	}
+
+	attrs := n.Attr[:0]
+	for _, at := range n.Attr {
+		key := strings.ToLower(at.Key)
+		switch {
+		case strings.HasPrefix(key, "on"), key == "integrity", key == "nonce":
+			// Inline handlers would run script, and integrity hashes no
+			// longer match inlined content
+			continue
+		case key == "style":
+			at.Val = a.css(at.Val, base, 0)
+		case key == "srcset":
+			at.Val = a.srcset(at.Val, base)
+		case key == "href" && n.Data == "link" && embeddedLink(htmlAttr(n, "rel")):
+			at.Val = a.inline(base, at.Val, 0)
+		case linkAttributes[key]:
+			at.Val = resolveArchiveURL(base, at.Val)
+		case assetAttributes[key]:
+			if n.Data == "iframe" || n.Data == "frame" {
+				at.Val = resolveArchiveURL(base, at.Val)
+			} else {
+				at.Val = a.inline(base, at.Val, 0)
+			}
+		}
+		attrs = append(attrs, at)
+	}
+	n.Attr = attrs
+}
+
+// findHTMLElement returns the first element named tag in n's subtree
+func findHTMLElement(n *html.Node, tag string) *html.Node {
+	if n.Type == html.ElementNode && n.Data == tag {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findHTMLElement(c, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// buildArchiveHTML turns a snapshot into a single HTML file: stylesheets,
+// images and fonts are embedded as data URIs, links and anything not
+// cached point at the live site, and scripts are removed so the archive
+// shows the page exactly as it was captured
+func buildArchiveHTML(snapshot *browser.PageSnapshot, now time.Time) (string, archiveStats, error) {
+	doc, err := html.Parse(strings.NewReader(snapshot.HTML))
+	if err != nil {
+		return "", archiveStats{}, fmt.Errorf("failed to parse page: %w", err)
+	}
+	baseURL := snapshot.BaseURL
+	if baseURL == "" {
+		baseURL = snapshot.URL
+	}
+	base, _ := url.Parse(baseURL)
+
+	a := &pageArchiver{resources: snapshot.Resources}
+	a.rewrite(doc, base)
+	a.stats.Missing = uniqueStrings(a.stats.Missing)
+
+	if head := findHTMLElement(doc, "head"); head != nil {
+		head.InsertBefore(&html.Node{Type: html.ElementNode, Data: "meta", Attr: []html.Attribute{{Key: "charset", Val: "utf-8"}}}, head.FirstChild)
+	}
+	if root := findHTMLElement(doc, "html"); root != nil {
+		// "--" can't appear inside a comment
+		source := strings.ReplaceAll(snapshot.URL, "--", "%2D%2D")
+		doc.InsertBefore(&html.Node{Type: html.CommentNode, Data: fmt.Sprintf(" Archived from %s on %s ", source, now.UTC().Format(time.RFC3339))}, root)
+	}
+
+	var out strings.Builder
+	if err := html.Render(&out, doc); err != nil {
+		return "", archiveStats{}, fmt.Errorf("failed to render archive: %w", err)
+	}
+	return out.String(), a.stats, nil
+}
+
+// archiveFormat picks the format from the format argument or, failing
+// that, the file extension
+func archiveFormat(format, path string) (string, error) {
+	switch format {
+	case archiveHTML, archiveMHTML:
+		return format, nil
+	case "":
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".mhtml", ".mht":
+			return archiveMHTML, nil
+		}
+		return archiveHTML, nil
+	}
+	return "", fmt.Errorf("format must be %s or %s", archiveHTML, archiveMHTML)
+}
+
+// ArchivePageTool saves a self-contained copy of a page
+type ArchivePageTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
+}
+
+func NewArchivePageTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator) *ArchivePageTool {
+	return &ArchivePageTool{logger: log, browserMgr: mgr, validator: validator}
+}
+
+func (t *ArchivePageTool) Name() string {
+	return "archive_page"
+}
+
+func (t *ArchivePageTool) Description() string {
+	return "Save a self-contained snapshot of a page as it currently is, so it can be viewed offline after the site changes. html embeds stylesheets, images and fonts and drops scripts; mhtml is the browser's own single-file format, which Chromium-based browsers open"
+}
+
+func (t *ArchivePageTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "File to write, e.g. evidence/home.html; must be inside the allowed paths",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "html or mhtml (default: mhtml for .mhtml and .mht paths, otherwise html)",
+				"enum":        []string{archiveHTML, archiveMHTML},
+			},
+			"overwrite": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Replace an existing file at path",
+				"default":     false,
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+func (t *ArchivePageTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		path, err := resolveFileArg(t.validator, args, "write")
+		if err != nil {
+			return fail(err.Error())
+		}
+		requested, _ := args["format"].(string)
+		format, err := archiveFormat(requested, path)
+		if err != nil {
+			return fail(err.Error())
+		}
+		if overwrite, _ := args["overwrite"].(bool); !overwrite {
+			if _, err := os.Stat(path); err == nil {
+				return fail(fmt.Sprintf("%s already exists; pass overwrite=true to replace it", path))
+			}
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		var content string
+		data := map[string]interface{}{"page_id": pageID, "format": format}
+		var text string
+		if format == archiveMHTML {
+			if content, err = t.browserMgr.MHTMLSnapshot(pageID); err != nil {
+				return fail(err.Error())
+			}
+			text = "Archived page as MHTML"
+		} else {
+			snapshot, err := t.browserMgr.Snapshot(pageID)
+			if err != nil {
+				return fail(err.Error())
+			}
+			var stats archiveStats
+			if content, stats, err = buildArchiveHTML(snapshot, time.Now()); err != nil {
+				return fail(err.Error())
+			}
+			text = fmt.Sprintf("Archived %s with %d resources embedded", snapshot.URL, stats.Inlined)
+			if len(stats.Missing) > 0 {
+				text += fmt.Sprintf("; %d weren't cached by the browser and still point at the live site", len(stats.Missing))
+			}
+			data["url"] = snapshot.URL
+			data["title"] = snapshot.Title
+			data["inlined"] = stats.Inlined
+			data["missing"] = stats.Missing
+		}
+
+		if err := t.validator.ValidateFileSize(int64(len(content))); err != nil {
+			return fail(err.Error())
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fail(fmt.Sprintf("Failed to create directory: %v", err))
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return fail(fmt.Sprintf("Failed to write %s: %v", path, err))
+		}
+		data["path"] = path
+		data["size_bytes"] = len(content)
+		text += fmt.Sprintf(" to %s (%.1f KB)", path, float64(len(content))/1024)
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
@@ -0,0 +1,120 @@
+package webtools
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+func TestBuildArchiveHTML(t *testing.T) {
+	snapshot := &browser.PageSnapshot{
+		URL:     "https://example.com/blog/post",
+		BaseURL: "https://example.com/blog/",
+		HTML: `<!DOCTYPE html><html><head><meta charset="iso-8859-1"><base href="/blog/">
+<link rel="stylesheet" href="site.css" media="screen" integrity="sha384-x">
+<link rel="preload" href="font.woff2" as="font">
+<link rel="canonical" href="/blog/post">
+<script src="app.js"></script>
+<style>.hero { background: url('img/hero.png') }</style>
+</head><body onload="init()">
+<a href="../about#team">About</a>
+<img src="img/logo.png" srcset="img/logo.png 1x, img/logo@2x.png 2x" alt="Logo">
+<div style="background-image: url(img/missing.png)"></div>
+<iframe src="/embed"></iframe>
+<form action="/search"><input name="q" value="archive"></form>
+</body></html>`,
+		Resources: map[string]browser.PageResource{
+			"https://example.com/blog/site.css":      {MIMEType: "text/css", Content: []byte(`@import "theme.css"; body { font-family: x; src: url("fonts/x.woff2") }`)},
+			"https://example.com/blog/theme.css":     {MIMEType: "text/css", Content: []byte(`h1 { color: red }`)},
+			"https://example.com/blog/fonts/x.woff2": {MIMEType: "font/woff2", Content: []byte("wOF2")},
+			"https://example.com/blog/img/hero.png":  {MIMEType: "image/png", Content: []byte("hero")},
+			"https://example.com/blog/img/logo.png":  {MIMEType: "image/png", Content: []byte("logo")},
+		},
+	}
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	out, stats, err := buildArchiveHTML(snapshot, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logo := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("logo"))
+	theme := "data:text/css;base64," + base64.StdEncoding.EncodeToString([]byte("h1 { color: red }"))
+	for _, want := range []string{
+		"<!-- Archived from https://example.com/blog/post on 2026-10-16T12:00:00Z -->",
+		`<head><meta charset="utf-8"/>`,
+		`<style media="screen">@import url("` + theme + `"); body { font-family: x; src: url("data:font/woff2;base64,`,
+		`url("data:image/png;base64,` + base64.StdEncoding.EncodeToString([]byte("hero")) + `")`,
+		`<link rel="canonical" href="https://example.com/blog/post"/>`,
+		`href="https://example.com/about#team"`,
+		`src="` + logo + `" srcset="` + logo + ` 1x, https://example.com/blog/img/logo@2x.png 2x"`,
+		`url(&#34;https://example.com/blog/img/missing.png&#34;)`,
+		`<iframe src="https://example.com/embed">`,
+		`action="https://example.com/search"`,
+		`value="archive"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("archive missing %q:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"<script", "<base", "iso-8859-1", "preload", "integrity", "onload", `href="site.css"`} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("archive still contains %q", unwanted)
+		}
+	}
+	if stats.Inlined != 6 || len(stats.Missing) != 2 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestArchiveFormat(t *testing.T) {
+	cases := []struct{ format, path, want string }{
+		{"", "page.html", archiveHTML},
+		{"", "page.MHT", archiveMHTML},
+		{"", "page.mhtml", archiveMHTML},
+		{"html", "page.mhtml", archiveHTML},
+		{"mhtml", "page", archiveMHTML},
+	}
+	for _, tc := range cases {
+		if got, err := archiveFormat(tc.format, tc.path); err != nil || got != tc.want {
+			t.Errorf("archiveFormat(%q, %q) = %q, %v; want %q", tc.format, tc.path, got, err, tc.want)
+		}
+	}
+	if _, err := archiveFormat("pdf", "page.pdf"); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
+
+func TestArchivePageValidation(t *testing.T) {
+	log := createTestLogger(t)
+	dir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{AllowedPaths: []string{dir}, MaxFileSize: 1024})
+	tool := NewArchivePageTool(log, browser.NewManager(log, browser.Config{Headless: true}), validator)
+
+	existing := filepath.Join(dir, "existing.html")
+	if err := os.WriteFile(existing, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"outside allowed paths", map[string]interface{}{"path": "/etc/page.html"}, "file access denied"},
+		{"bad format", map[string]interface{}{"path": filepath.Join(dir, "page.pdf"), "format": "pdf"}, "format must be"},
+		{"existing file", map[string]interface{}{"path": existing}, "already exists"},
+		{"no pages", map[string]interface{}{"path": filepath.Join(dir, "page.html")}, "No browser pages"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+)
+
+// auditCheck is one scored finding of a page audit
+type auditCheck struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"` // pass, warn, fail
+	Message string `json:"message"`
+	Weight  int    `json:"weight"`
+}
+
+// auditChecks collects the findings of one audit
+type auditChecks []auditCheck
+
+func (c *auditChecks) add(id, status string, weight int, format string, a ...interface{}) {
+	*c = append(*c, auditCheck{ID: id, Status: status, Message: fmt.Sprintf(format, a...), Weight: weight})
+}
+
+// score rates the checks from 0 to 100. A pass earns a check's full weight,
+// a warning half of it.
+func (c auditChecks) score() int {
+	total, earned := 0, 0
+	for _, check := range c {
+		total += check.Weight * 2
+		switch check.Status {
+		case "pass":
+			earned += check.Weight * 2
+		case "warn":
+			earned += check.Weight
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return earned * 100 / total
+}
+
+func (c auditChecks) status(id string) string {
+	for _, check := range c {
+		if check.ID == id {
+			return check.Status
+		}
+	}
+	return ""
+}
+
+// format lists failures first, then warnings, then passes
+func (c auditChecks) format(b *strings.Builder) {
+	for _, status := range []string{"fail", "warn", "pass"} {
+		for _, check := range c {
+			if check.Status == status {
+				fmt.Fprintf(b, "[%s] %s: %s\n", check.Status, check.ID, check.Message)
+			}
+		}
+	}
+}
+
+func auditGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	}
+	return "F"
+}
@@ -0,0 +1,218 @@
+package webtools
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"rodmcp/internal/bandwidth"
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// configuredBandwidth holds the meter the tools' own HTTP clients count
+// their traffic against
+var configuredBandwidth struct {
+	mutex sync.RWMutex
+	meter *bandwidth.Meter
+}
+
+// SetBandwidthMeter counts the traffic of http_request, crawl_site's
+// robots.txt fetches and the other requests tools make outside the browser
+// against meter
+func SetBandwidthMeter(meter *bandwidth.Meter) {
+	configuredBandwidth.mutex.Lock()
+	defer configuredBandwidth.mutex.Unlock()
+	configuredBandwidth.meter = meter
+}
+
+func configuredBandwidthMeter() *bandwidth.Meter {
+	configuredBandwidth.mutex.RLock()
+	defer configuredBandwidth.mutex.RUnlock()
+	return configuredBandwidth.meter
+}
+
+// meteredTransport wraps base (http.DefaultTransport when nil) so its
+// requests count against the configured meter, whenever that is set
+func meteredTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return configuredBandwidthMeter().Transport(base).RoundTrip(req)
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// BrowserStatsTool reports the bytes the server has moved, by domain and by
+// tool, for attributing the egress of scraping workloads
+type BrowserStatsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	meter      *bandwidth.Meter
+}
+
+// NewBrowserStatsTool reports on meter; mgr may be nil
+func NewBrowserStatsTool(log *logger.Logger, mgr *browser.Manager, meter *bandwidth.Meter) *BrowserStatsTool {
+	return &BrowserStatsTool{logger: log, browserMgr: mgr, meter: meter}
+}
+
+func (t *BrowserStatsTool) Name() string {
+	return "browser_stats"
+}
+
+func (t *BrowserStatsTool) Description() string {
+	return "Report bytes sent and received by the browser and by http_request, per destination domain and per tool, with the most recent tool calls. Use action 'reset' to start a new accounting period"
+}
+
+func (t *BrowserStatsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"get", "reset"},
+				"description": "get the counters, or reset them after reporting",
+				"default":     "get",
+			},
+			"top_domains": map[string]interface{}{
+				"type":        "integer",
+				"description": "Report only this many domains, those with the most traffic (0 for all)",
+				"default":     20,
+				"minimum":     0,
+			},
+			"recent_calls": map[string]interface{}{
+				"type":        "integer",
+				"description": "Include this many of the latest tool calls with their own traffic",
+				"default":     10,
+				"minimum":     0,
+				"maximum":     100,
+			},
+		},
+	}
+}
+
+func (t *BrowserStatsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "get"
+		}
+		if action != "get" && action != "reset" {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse("action must be one of get, reset"), nil
+		}
+		if t.meter == nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse("Bandwidth accounting is not enabled on this server"), nil
+		}
+		topDomains := 20
+		if v, ok := args["top_domains"].(float64); ok && v >= 0 {
+			topDomains = int(v)
+		}
+		recentCalls := 10
+		if v, ok := args["recent_calls"].(float64); ok && v >= 0 {
+			recentCalls = int(v)
+		}
+
+		stats := t.meter.Stats()
+		if action == "reset" {
+			t.meter.Reset()
+		}
+		data := bandwidthReport(stats, topDomains, recentCalls)
+		if t.browserMgr != nil {
+			data["open_pages"] = len(t.browserMgr.ListPages())
+		}
+
+		text := fmt.Sprintf("Since %s: %s sent, %s received in %d requests across %d domains",
+			stats.Since.UTC().Format(time.RFC3339),
+			formatBytes(stats.Total.BytesSent), formatBytes(stats.Total.BytesReceived),
+			stats.Total.Requests, len(stats.Domains))
+		if top := stats.TopDomains(1); len(top) > 0 {
+			d := stats.Domains[top[0]]
+			text += fmt.Sprintf("; most traffic to %s (%s)", top[0], formatBytes(d.BytesSent+d.BytesReceived))
+		}
+		if action == "reset" {
+			text += "; counters reset"
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// bandwidthReport trims stats to the busiest domains and the latest calls,
+// with tools sorted by traffic
+func bandwidthReport(stats bandwidth.Stats, topDomains, recentCalls int) map[string]interface{} {
+	domains := make([]map[string]interface{}, 0)
+	for _, host := range stats.TopDomains(topDomains) {
+		u := stats.Domains[host]
+		domains = append(domains, map[string]interface{}{
+			"domain":         host,
+			"bytes_sent":     u.BytesSent,
+			"bytes_received": u.BytesReceived,
+			"requests":       u.Requests,
+		})
+	}
+
+	names := make([]string, 0, len(stats.Tools))
+	for name := range stats.Tools {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := stats.Tools[names[i]], stats.Tools[names[j]]
+		if ta, tb := a.BytesSent+a.BytesReceived, b.BytesSent+b.BytesReceived; ta != tb {
+			return ta > tb
+		}
+		return names[i] < names[j]
+	})
+	tools := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		u := stats.Tools[name]
+		tools = append(tools, map[string]interface{}{
+			"tool":           name,
+			"calls":          u.Calls,
+			"bytes_sent":     u.BytesSent,
+			"bytes_received": u.BytesReceived,
+			"requests":       u.Requests,
+		})
+	}
+
+	recent := stats.Recent
+	if len(recent) > recentCalls {
+		recent = recent[len(recent)-recentCalls:]
+	}
+	return map[string]interface{}{
+		"since":         stats.Since,
+		"total":         stats.Total,
+		"domains":       domains,
+		"domains_total": len(stats.Domains),
+		"tools":         tools,
+		"recent_calls":  recent,
+	}
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,70 @@
+package webtools
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/bandwidth"
+)
+
+func TestBrowserStatsTool(t *testing.T) {
+	meter := bandwidth.NewMeter()
+	meter.Record("big.example.com", 100, 3*1024*1024)
+	meter.Record("small.example.com", 100, 10)
+	end := meter.BeginCall("scrape_urls")
+	meter.Record("big.example.com", 10, 10)
+	end()
+
+	tool := NewBrowserStatsTool(createTestLogger(t), nil, meter)
+	resp, err := tool.Execute(map[string]interface{}{"top_domains": float64(1), "action": "reset"})
+	if err != nil || resp.IsError {
+		t.Fatalf("browser_stats = %+v, %v", resp, err)
+	}
+	text := resp.Content[0].Text
+	if !strings.Contains(text, "most traffic to big.example.com (3.0 MiB)") || !strings.Contains(text, "counters reset") {
+		t.Errorf("text = %q", text)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if domains := data["domains"].([]map[string]interface{}); len(domains) != 1 || data["domains_total"] != 2 {
+		t.Errorf("domains = %v", data["domains"])
+	}
+	if tools := data["tools"].([]map[string]interface{}); len(tools) != 1 || tools[0]["tool"] != "scrape_urls" {
+		t.Errorf("tools = %v", data["tools"])
+	}
+	if meter.Stats().Total.Requests != 0 {
+		t.Error("Expected reset to zero the meter")
+	}
+
+	resp, _ = NewBrowserStatsTool(createTestLogger(t), nil, nil).Execute(map[string]interface{}{})
+	if !resp.IsError {
+		t.Error("Expected an error without a meter")
+	}
+}
+
+func TestMeteredTransportUsesConfiguredMeter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	meter := bandwidth.NewMeter()
+	SetBandwidthMeter(meter)
+	defer SetBandwidthMeter(nil)
+
+	client := &http.Client{Transport: meteredTransport(nil)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if d := meter.Stats().Domains["127.0.0.1"]; d.Requests != 1 || d.BytesReceived < 2 {
+		t.Errorf("127.0.0.1 = %+v", d)
+	}
+	if formatBytes(512) != "512 B" || formatBytes(1536) != "1.5 KiB" {
+		t.Errorf("formatBytes = %s, %s", formatBytes(512), formatBytes(1536))
+	}
+}
@@ -0,0 +1,262 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// batchCall is one entry of run_batch's calls
+type batchCall struct {
+	ID   string                 `json:"id,omitempty"`
+	Tool string                 `json:"tool"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// batchResult is one call's entry in run_batch's results, in call order
+type batchResult struct {
+	ID    string      `json:"id"`
+	Tool  string      `json:"tool"`
+	OK    bool        `json:"ok"`
+	Text  string      `json:"text,omitempty"`
+	Error string      `json:"error,omitempty"`
+	Data  interface{} `json:"data,omitempty"`
+	// NotRun marks calls left out because an earlier one failed
+	NotRun bool `json:"not_run,omitempty"`
+}
+
+// RunBatchTool runs a list of tool calls in one request, saving an agent a
+// round trip per step of a flow it has already planned
+type RunBatchTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	call       ContextToolCaller
+	tools      func() []DescribableTool
+}
+
+// NewRunBatchTool creates a run_batch tool. call runs each call, ending it
+// with the batch; a nil call leaves the tool unable to run anything. tools
+// lists the registered tools, so the batch knows which of them take a
+// page_id.
+func NewRunBatchTool(log *logger.Logger, mgr *browser.Manager, call ContextToolCaller, tools func() []DescribableTool) *RunBatchTool {
+	return &RunBatchTool{logger: log, browserMgr: mgr, call: call, tools: tools}
+}
+
+func (t *RunBatchTool) Name() string {
+	return "run_batch"
+}
+
+func (t *RunBatchTool) Description() string {
+	return "Run several tool calls in order in one request, all against one page, and return every call's result. Stops at the first failure unless continue_on_error is set. Arguments can use earlier results as {{steps.<id>.data...}} templates, as in workflows"
+}
+
+func (t *RunBatchTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"calls": map[string]interface{}{
+				"type":        "array",
+				"description": "Tool calls to run in order",
+				"minItems":    1,
+				"maxItems":    maxWorkflowSteps,
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":   map[string]interface{}{"type": "string", "description": "Name for the call's result in templates and results (default: step1, step2, ...)"},
+						"tool": map[string]interface{}{"type": "string"},
+						"args": map[string]interface{}{"type": "object"},
+					},
+					"required": []string{"tool"},
+				},
+				"examples": []interface{}{[]interface{}{
+					map[string]interface{}{"tool": "type_text", "args": map[string]interface{}{"selector": "#q", "text": "rodmcp"}},
+					map[string]interface{}{"tool": "click_element", "args": map[string]interface{}{"selector": "button[type=submit]"}},
+					map[string]interface{}{"tool": "wait_for_element", "args": map[string]interface{}{"selector": ".results"}},
+					map[string]interface{}{"id": "results", "tool": "get_element_text", "args": map[string]interface{}{"selector": ".results"}},
+				}},
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page the calls run against, for every call that takes a page_id and does not set its own (default: the current page). A call that opens a page, such as navigate_page, moves the rest of the batch onto it",
+			},
+			"continue_on_error": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Run the remaining calls after one fails (default: false)",
+				"default":     false,
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Seconds the whole batch may take (default: the server's tool timeout)",
+			},
+			"dry_run": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Run every call as a dry run: calls that would write files, commit or send data return what they would do instead (default: false)",
+				"default":     false,
+			},
+		},
+		Required: []string{"calls"},
+	}
+}
+
+// pageTools lists the registered tools that take a page_id
+func (t *RunBatchTool) pageTools() map[string]bool {
+	takesPage := make(map[string]bool)
+	if t.tools == nil {
+		return takesPage
+	}
+	for _, tool := range t.tools() {
+		if _, ok := tool.InputSchema().Properties["page_id"]; ok {
+			takesPage[tool.Name()] = true
+		}
+	}
+	return takesPage
+}
+
+// onPage wraps call so calls that take a page_id run against *pageID unless
+// they name a page, and calls that open a page move *pageID onto it
+func onPage(call ToolCaller, takesPage map[string]bool, pageID *string) ToolCaller {
+	return func(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		if _, given := args["page_id"]; takesPage[name] && !given && *pageID != "" {
+			pinned := make(map[string]interface{}, len(args)+1)
+			for key, value := range args {
+				pinned[key] = value
+			}
+			pinned["page_id"] = *pageID
+			args = pinned
+		}
+		resp, err := call(name, args)
+		if err == nil && resp != nil && !resp.IsError && !takesPage[name] {
+			if data, ok := stepResult(resp)["data"].(map[string]interface{}); ok {
+				if opened, ok := data["page_id"].(string); ok && opened != "" {
+					*pageID = opened
+				}
+			}
+		}
+		return resp, err
+	}
+}
+
+// parseBatch turns run_batch's calls into workflow steps, checking them as
+// a workflow would be
+func parseBatch(raw interface{}) (*workflow, error) {
+	var calls []batchCall
+	encoded, _ := json.Marshal(raw)
+	if err := json.Unmarshal(encoded, &calls); err != nil {
+		return nil, fmt.Errorf("calls must be a list of {tool, args, id}: %v", err)
+	}
+	w := &workflow{Name: "batch", Steps: make([]workflowStep, len(calls))}
+	for i, call := range calls {
+		if call.Tool == "run_batch" {
+			return nil, fmt.Errorf("call %d: run_batch cannot run another batch", i+1)
+		}
+		w.Steps[i] = workflowStep{ID: call.ID, Tool: call.Tool, Args: call.Args}
+	}
+	if err := w.validate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// batchResults lines up a run's outcomes with the calls, marking the ones
+// a failure kept from running
+func batchResults(w *workflow, run *workflowRun) []batchResult {
+	results := make([]batchResult, len(w.Steps))
+	for i, step := range w.Steps {
+		results[i] = batchResult{ID: step.ID, Tool: step.Tool, NotRun: true}
+		if i >= len(run.Steps) {
+			continue
+		}
+		outcome := run.Steps[i]
+		results[i] = batchResult{ID: step.ID, Tool: step.Tool, OK: outcome.OK, Text: outcome.Text, Error: outcome.Error}
+		if result, ok := run.Results[step.ID].(map[string]interface{}); ok {
+			results[i].Data = result["data"]
+		}
+	}
+	return results
+}
+
+func (t *RunBatchTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs each call within the batch's call, so none outlives
+// it or the batch's timeout
+func (t *RunBatchTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+		if t.call == nil {
+			return fail("run_batch is not available in this mode")
+		}
+
+		w, err := parseBatch(args["calls"])
+		if err != nil {
+			return fail(fmt.Sprintf("Invalid batch: %v", err))
+		}
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" && t.browserMgr != nil {
+			pageID = t.browserMgr.GetCurrentPageID()
+		}
+		continueOnError, _ := args["continue_on_error"].(bool)
+
+		if seconds, ok := args["timeout"].(float64); ok && seconds > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, time.Duration(seconds*float64(time.Second)))
+			defer cancel()
+		}
+		var call ToolCaller = func(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+			return t.call(ctx, name, args)
+		}
+		dryRun, _ := args["dry_run"].(bool)
+		if dryRun {
+			call = planOnly(call)
+		}
+		runner := &workflowRunner{call: onPage(call, t.pageTools(), &pageID), continueOnError: continueOnError, ctx: ctx}
+		run, err := runner.run(w, nil)
+		if err != nil {
+			return fail(fmt.Sprintf("Batch: %v", err))
+		}
+		t.logger.LogToolExecution(t.Name(), args, !run.Failed, time.Since(start).Milliseconds())
+
+		results := batchResults(w, run)
+		succeeded := 0
+		for _, result := range results {
+			if result.OK {
+				succeeded++
+			}
+		}
+		text := fmt.Sprintf("Batch: %d of %d calls succeeded", succeeded, len(results))
+		if dryRun {
+			text += " (dry run)"
+		}
+		for _, result := range results {
+			if !result.OK && !result.NotRun {
+				text += fmt.Sprintf("; %s (%s) failed: %s", result.ID, result.Tool, result.Error)
+				if notRun := len(results) - len(run.Steps); notRun > 0 {
+					text += fmt.Sprintf("; %d not run", notRun)
+				}
+				break
+			}
+		}
+		data := map[string]interface{}{
+			"results": results,
+			"failed":  run.Failed,
+			"dry_run": dryRun,
+		}
+		if pageID != "" {
+			data["page_id"] = pageID
+		}
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+			IsError: run.Failed,
+		}, nil
+	})
+}
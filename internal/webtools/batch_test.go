@@ -0,0 +1,192 @@
+package webtools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/pkg/types"
+)
+
+// batchTestTool is a registered tool as run_batch sees it
+type batchTestTool struct {
+	name      string
+	takesPage bool
+}
+
+func (b batchTestTool) Name() string        { return b.name }
+func (b batchTestTool) Description() string { return b.name }
+func (b batchTestTool) InputSchema() types.ToolSchema {
+	schema := types.ToolSchema{Type: "object", Properties: map[string]interface{}{}}
+	if b.takesPage {
+		schema.Properties["page_id"] = map[string]interface{}{"type": "string"}
+	}
+	return schema
+}
+
+func newBatchTestTool(fake *fakeToolCaller) *RunBatchTool {
+	call := func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		return fake.call(name, args)
+	}
+	return NewRunBatchTool(nil, nil, call, func() []DescribableTool {
+		return []DescribableTool{
+			batchTestTool{name: "navigate_page"},
+			batchTestTool{name: "click_element", takesPage: true},
+			batchTestTool{name: "get_element_text", takesPage: true},
+			batchTestTool{name: "http_request"},
+		}
+	})
+}
+
+func TestRunBatch(t *testing.T) {
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"navigate_page": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("navigated", map[string]interface{}{"page_id": "page_2"})
+		},
+		"click_element": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("clicked", nil)
+		},
+		"get_element_text": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("Results", map[string]interface{}{"text": "Results"})
+		},
+		"http_request": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("ok", nil)
+		},
+	}}
+	tool := newBatchTestTool(fake)
+	tool.logger = createTestLogger(t)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"page_id": "page_1",
+		"calls": []interface{}{
+			map[string]interface{}{"tool": "click_element", "args": map[string]interface{}{"selector": "#go"}},
+			map[string]interface{}{"tool": "http_request", "args": map[string]interface{}{"url": "https://example.com"}},
+			map[string]interface{}{"tool": "click_element", "args": map[string]interface{}{"page_id": "page_9", "selector": "#other"}},
+			map[string]interface{}{"id": "open", "tool": "navigate_page", "args": map[string]interface{}{"url": "https://example.com"}},
+			map[string]interface{}{"id": "title", "tool": "get_element_text", "args": map[string]interface{}{"selector": "h1"}},
+			map[string]interface{}{"tool": "http_request", "args": map[string]interface{}{"url": "{{steps.title.text}}"}},
+		},
+	})
+	if err != nil || resp.IsError || !strings.Contains(resp.Content[0].Text, "6 of 6 calls succeeded") {
+		t.Fatalf("run_batch = %+v, %v", resp, err)
+	}
+
+	// Calls that take a page run on the batch's page unless they name one,
+	// and follow the page navigate_page opened
+	wantPages := []interface{}{"page_1", nil, "page_9", nil, "page_2", nil}
+	for i, want := range wantPages {
+		if got := fake.args[i]["page_id"]; got != want {
+			t.Errorf("call %d (%s) page_id = %v, want %v", i+1, fake.calls[i], got, want)
+		}
+	}
+	if fake.args[5]["url"] != "Results" {
+		t.Errorf("Expected templates over earlier results, got %v", fake.args[5])
+	}
+
+	data := resp.Content[0].Data.(map[string]interface{})
+	results := data["results"].([]batchResult)
+	if len(results) != 6 || results[3].ID != "open" || results[0].ID != "step1" || results[4].Text != "Results" {
+		t.Errorf("results = %+v", results)
+	}
+	if title, _ := results[4].Data.(map[string]interface{}); title["text"] != "Results" {
+		t.Errorf("Expected each result's data, got %+v", results[4])
+	}
+	if data["page_id"] != "page_2" {
+		t.Errorf("Expected the batch to end on page_2, got %v", data["page_id"])
+	}
+}
+
+func TestRunBatchErrors(t *testing.T) {
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"click_element": func(args map[string]interface{}) *types.CallToolResponse {
+			return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: "no such element"}}, IsError: true}
+		},
+		"get_element_text": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("text", nil)
+		},
+	}}
+	tool := newBatchTestTool(fake)
+	tool.logger = createTestLogger(t)
+	calls := []interface{}{
+		map[string]interface{}{"tool": "get_element_text"},
+		map[string]interface{}{"id": "click", "tool": "click_element"},
+		map[string]interface{}{"tool": "get_element_text"},
+	}
+
+	// Stop at the first failure
+	resp, _ := tool.Execute(map[string]interface{}{"calls": calls})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "1 of 3 calls succeeded; click (click_element) failed: no such element; 1 not run") {
+		t.Errorf("stop on error = %+v", resp)
+	}
+	results := resp.Content[0].Data.(map[string]interface{})["results"].([]batchResult)
+	if results[1].OK || results[1].NotRun || !results[2].NotRun || len(fake.calls) != 2 {
+		t.Errorf("results = %+v after calls %v", results, fake.calls)
+	}
+
+	// Or carry on past it
+	fake.calls = nil
+	resp, _ = tool.Execute(map[string]interface{}{"calls": calls, "continue_on_error": true})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "2 of 3 calls succeeded") || len(fake.calls) != 3 {
+		t.Errorf("continue on error = %+v after calls %v", resp, fake.calls)
+	}
+
+	for _, calls := range []interface{}{
+		nil,
+		[]interface{}{},
+		[]interface{}{map[string]interface{}{"args": map[string]interface{}{}}},
+		[]interface{}{map[string]interface{}{"tool": "run_batch"}},
+		[]interface{}{map[string]interface{}{"id": "a", "tool": "x"}, map[string]interface{}{"id": "a", "tool": "y"}},
+		"click",
+	} {
+		resp, _ := tool.Execute(map[string]interface{}{"calls": calls})
+		if !resp.IsError || !strings.Contains(resp.Content[0].Text, "Invalid batch") {
+			t.Errorf("calls %v: expected an invalid batch, got %+v", calls, resp)
+		}
+	}
+
+	resp, _ = NewRunBatchTool(createTestLogger(t), nil, nil, nil).Execute(map[string]interface{}{"calls": calls})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "not available") {
+		t.Errorf("Expected run_batch without a caller to refuse, got %+v", resp)
+	}
+}
+
+func TestRunBatchTimeout(t *testing.T) {
+	var called []string
+	call := func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		called = append(called, name)
+		if name == "wait" {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return textResponse("ok", nil), nil
+	}
+	tool := NewRunBatchTool(createTestLogger(t), nil, call, nil)
+
+	start := time.Now()
+	resp, _ := tool.ExecuteContext(context.Background(), map[string]interface{}{
+		"timeout":           0.05,
+		"continue_on_error": true,
+		"calls": []interface{}{
+			map[string]interface{}{"tool": "click_element"},
+			map[string]interface{}{"tool": "wait"},
+			map[string]interface{}{"tool": "click_element"},
+			map[string]interface{}{"tool": "click_element"},
+		},
+	})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Expected the batch to stop at its timeout, took %s", elapsed)
+	}
+	// The call past the deadline fails, and nothing after it starts, even
+	// with continue_on_error
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "1 of 4 calls succeeded; step2 (wait) failed: context deadline exceeded; 1 not run") {
+		t.Errorf("timed out batch = %+v", resp)
+	}
+	results := resp.Content[0].Data.(map[string]interface{})["results"].([]batchResult)
+	if results[2].Error != "not started: the call ran out of time" || !results[3].NotRun {
+		t.Errorf("results = %+v", results)
+	}
+	if len(called) != 2 {
+		t.Errorf("Expected no calls after the timeout, got %v", called)
+	}
+}
@@ -0,0 +1,306 @@
+package webtools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// maxSolveDuration keeps a solve inside the server's per-call limit
+const maxSolveDuration = 25 * time.Second
+
+// challengeReport describes a CAPTCHA or anti-bot page found after loading
+type challengeReport struct {
+	Provider string `json:"provider"` // cloudflare, turnstile, recaptcha, hcaptcha, datadome, perimeterx or unknown
+	Kind     string `json:"kind"`     // interstitial, captcha or block
+	Blocking bool   `json:"blocking"` // the challenge replaces the page's content
+	Evidence string `json:"evidence"`
+	SiteKey  string `json:"sitekey,omitempty"`
+}
+
+// solvable reports whether the challenge is a widget an external service
+// can return a token for
+func (r *challengeReport) solvable() bool {
+	switch r.Provider {
+	case "recaptcha", "hcaptcha", "turnstile":
+		return r.SiteKey != ""
+	}
+	return false
+}
+
+func (r *challengeReport) summary() string {
+	if r.Blocking {
+		return fmt.Sprintf("blocked by %s %s (%s)", r.Provider, r.Kind, r.Evidence)
+	}
+	return fmt.Sprintf("%s %s on page (%s)", r.Provider, r.Kind, r.Evidence)
+}
+
+// challengeScript looks for known interstitials first, then CAPTCHA
+// widgets, then generic "are you a robot" pages. Invisible reCAPTCHA v3
+// badges are not challenges and are ignored.
+const challengeScript = `
+	const title = document.title || '';
+	const text = document.body ? (document.body.innerText || '') : '';
+	const has = (sel) => { try { return !!document.querySelector(sel); } catch (e) { return false; } };
+	const frame = (pattern) => Array.from(document.querySelectorAll('iframe'))
+		.find(f => pattern.test(f.src || '') && !/[?&]size=invisible/.test(f.src || ''));
+	// Challenge pages carry little besides the challenge itself
+	const sparse = text.trim().length < 1000;
+	const found = (provider, kind, blocking, evidence, widgetSel) => {
+		const widget = widgetSel ? document.querySelector(widgetSel) : null;
+		const key = widget ? (widget.getAttribute('data-sitekey') || '') : '';
+		return { provider: provider, kind: kind, blocking: blocking, evidence: evidence, sitekey: key };
+	};
+
+	if (/^just a moment/i.test(title) || has('#challenge-form') || has('#cf-challenge-running') ||
+		has('.cf-browser-verification') || has('#challenge-stage')) {
+		return found('cloudflare', 'interstitial', true, 'title: ' + title);
+	}
+	if (/attention required/i.test(title) && /cloudflare/i.test(title + text)) {
+		return found('cloudflare', 'block', true, 'title: ' + title);
+	}
+	if (frame(/captcha-delivery\.com/)) {
+		return found('datadome', 'captcha', true, 'captcha-delivery.com iframe');
+	}
+	if (has('#px-captcha')) {
+		return found('perimeterx', 'captcha', true, '#px-captcha');
+	}
+	if (has('.cf-turnstile') || frame(/challenges\.cloudflare\.com/)) {
+		return found('turnstile', 'captcha', sparse, 'Turnstile widget', '.cf-turnstile');
+	}
+	if (has('.h-captcha') || frame(/hcaptcha\.com/)) {
+		return found('hcaptcha', 'captcha', sparse, 'hCaptcha widget', '.h-captcha, [data-hcaptcha-widget-id]');
+	}
+	if (has('.g-recaptcha:not([data-size="invisible"])') || frame(/\/recaptcha\/(api2|enterprise)\/(anchor|bframe)/)) {
+		return found('recaptcha', 'captcha', sparse, 'reCAPTCHA widget', '.g-recaptcha');
+	}
+	if (sparse && /access denied|are you a robot|verify you are (a )?human|unusual traffic|bot detection/i.test(title + ' ' + text)) {
+		return found('unknown', 'block', true, 'page text: ' + (title || text.trim().slice(0, 80)));
+	}
+	return null;
+`
+
+// detectChallenge checks a loaded page for a CAPTCHA or anti-bot challenge.
+// It returns nil when none is found or the page cannot be inspected.
+func detectChallenge(mgr *browser.Manager, pageID string) *challengeReport {
+	raw, err := mgr.ExecuteScript(pageID, challengeScript)
+	if err != nil || raw == nil {
+		return nil
+	}
+	var report *challengeReport
+	if err := decodeScriptValue(raw, &report); err != nil {
+		return nil
+	}
+	return report
+}
+
+// ChallengeSolveRequest is what an external solving service is given
+type ChallengeSolveRequest struct {
+	Provider string `json:"provider"`
+	SiteKey  string `json:"sitekey"`
+	PageURL  string `json:"page_url"`
+}
+
+// ChallengeSolver obtains a response token for a CAPTCHA widget from an
+// external service
+type ChallengeSolver interface {
+	Solve(ctx context.Context, req ChallengeSolveRequest) (string, error)
+}
+
+// webhookSolver posts challenges to an HTTP endpoint that answers with
+// {"token": "..."} or {"error": "..."}
+type webhookSolver struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookChallengeSolver creates a solver that POSTs a
+// ChallengeSolveRequest as JSON to url
+func NewWebhookChallengeSolver(url string) ChallengeSolver {
+	return &webhookSolver{url: url, client: &http.Client{Timeout: maxSolveDuration}}
+}
+
+func (s *webhookSolver) Solve(ctx context.Context, req ChallengeSolveRequest) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("solver request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Token string `json:"token"`
+		Error string `json:"error"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err := json.Unmarshal(data, &result); err != nil {
+		return "", fmt.Errorf("solver returned HTTP %d with an unreadable body", resp.StatusCode)
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("solver: %s", result.Error)
+	}
+	if resp.StatusCode != http.StatusOK || result.Token == "" {
+		return "", fmt.Errorf("solver returned HTTP %d without a token", resp.StatusCode)
+	}
+	return result.Token, nil
+}
+
+// injectTokenScript puts a solved token where the widget's form expects it
+// and fires the widget's data-callback, if any
+func injectTokenScript(provider, token string) string {
+	providerJSON, _ := json.Marshal(provider)
+	tokenJSON, _ := json.Marshal(token)
+	return fmt.Sprintf(`
+		const provider = %s;
+		const token = %s;
+		const fields = {
+			recaptcha: ['#g-recaptcha-response', 'textarea[name="g-recaptcha-response"]'],
+			hcaptcha: ['textarea[name="h-captcha-response"]', 'textarea[name="g-recaptcha-response"]'],
+			turnstile: ['input[name="cf-turnstile-response"]']
+		}[provider] || [];
+		let filled = 0;
+		for (const sel of fields) {
+			document.querySelectorAll(sel).forEach(el => { el.value = token; filled++; });
+		}
+		const widget = document.querySelector({ recaptcha: '.g-recaptcha', hcaptcha: '.h-captcha', turnstile: '.cf-turnstile' }[provider]);
+		const name = widget ? widget.getAttribute('data-callback') : '';
+		let callback = false;
+		if (name && typeof window[name] === 'function') {
+			window[name](token);
+			callback = true;
+		}
+		return { filled: filled, callback: callback };
+	`, providerJSON, tokenJSON)
+}
+
+// SolveCaptchaTool hands a page's CAPTCHA widget to an external solving
+// service and submits the returned token
+type SolveCaptchaTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	solver     ChallengeSolver
+}
+
+// NewSolveCaptchaTool creates a solve_captcha tool. With a nil solver the
+// tool only reports what it finds.
+func NewSolveCaptchaTool(log *logger.Logger, mgr *browser.Manager, solver ChallengeSolver) *SolveCaptchaTool {
+	return &SolveCaptchaTool{logger: log, browserMgr: mgr, solver: solver}
+}
+
+func (t *SolveCaptchaTool) Name() string {
+	return "solve_captcha"
+}
+
+func (t *SolveCaptchaTool) Description() string {
+	return "Detect a CAPTCHA (reCAPTCHA, hCaptcha, Turnstile) on a page and, when an external solver is configured with --captcha-solver-url, fill in the token it returns. Interstitials such as Cloudflare's 'Just a moment' page are reported but cannot be solved this way"
+}
+
+func (t *SolveCaptchaTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *SolveCaptchaTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string, data map[string]interface{}) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{Type: "text", Text: message, Data: data}},
+				IsError: true,
+			}, nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		report := detectChallenge(t.browserMgr, pageID)
+		if report == nil {
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: "No CAPTCHA or anti-bot challenge found",
+					Data: map[string]interface{}{"page_id": pageID},
+				}},
+			}, nil
+		}
+
+		data := map[string]interface{}{"page_id": pageID, "challenge": report}
+		if !report.solvable() {
+			return fail(fmt.Sprintf("Found %s, which cannot be solved with a token", report.summary()), data)
+		}
+		if t.solver == nil {
+			return fail(fmt.Sprintf("Found %s but no solver is configured (start the server with --captcha-solver-url)", report.summary()), data)
+		}
+
+		pageURL := ""
+		if info, err := t.browserMgr.GetPageInfo(pageID); err == nil {
+			pageURL, _ = info["url"].(string)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), maxSolveDuration)
+		defer cancel()
+		token, err := t.solver.Solve(ctx, ChallengeSolveRequest{
+			Provider: report.Provider,
+			SiteKey:  report.SiteKey,
+			PageURL:  pageURL,
+		})
+		if err != nil {
+			return fail(fmt.Sprintf("Solving %s failed: %v", report.Provider, err), data)
+		}
+
+		raw, err := t.browserMgr.ExecuteScript(pageID, injectTokenScript(report.Provider, token))
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to submit the %s token: %v", report.Provider, err), data)
+		}
+		var injected struct {
+			Filled   int  `json:"filled"`
+			Callback bool `json:"callback"`
+		}
+		decodeScriptValue(raw, &injected)
+		if injected.Filled == 0 && !injected.Callback {
+			return fail(fmt.Sprintf("Got a %s token but the page has no response field or callback to take it", report.Provider), data)
+		}
+
+		data["fields_filled"] = injected.Filled
+		data["callback_called"] = injected.Callback
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Solved %s; token placed in %d fields. Submit the form if the page does not continue by itself", report.Provider, injected.Filled),
+				Data: data,
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,65 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChallengeReportSolvable(t *testing.T) {
+	cases := []struct {
+		report challengeReport
+		want   bool
+	}{
+		{challengeReport{Provider: "recaptcha", SiteKey: "k"}, true},
+		{challengeReport{Provider: "hcaptcha", SiteKey: "k"}, true},
+		{challengeReport{Provider: "turnstile", SiteKey: "k"}, true},
+		{challengeReport{Provider: "recaptcha"}, false},
+		{challengeReport{Provider: "cloudflare", Kind: "interstitial", SiteKey: "k"}, false},
+		{challengeReport{Provider: "datadome"}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.report.solvable(); got != tc.want {
+			t.Errorf("%+v: solvable = %v, want %v", tc.report, got, tc.want)
+		}
+	}
+
+	blocked := challengeReport{Provider: "cloudflare", Kind: "interstitial", Blocking: true, Evidence: "title: Just a moment..."}
+	if !strings.HasPrefix(blocked.summary(), "blocked by cloudflare interstitial") {
+		t.Errorf("summary = %q", blocked.summary())
+	}
+}
+
+func TestWebhookChallengeSolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req ChallengeSolveRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.SiteKey {
+		case "good":
+			json.NewEncoder(w).Encode(map[string]string{"token": "tok-" + req.Provider})
+		case "refused":
+			json.NewEncoder(w).Encode(map[string]string{"error": "balance too low"})
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+			w.Write([]byte("upstream down"))
+		}
+	}))
+	defer server.Close()
+
+	solver := NewWebhookChallengeSolver(server.URL)
+	ctx := context.Background()
+
+	token, err := solver.Solve(ctx, ChallengeSolveRequest{Provider: "hcaptcha", SiteKey: "good", PageURL: "https://example.com"})
+	if err != nil || token != "tok-hcaptcha" {
+		t.Errorf("Solve = %q, %v", token, err)
+	}
+	if _, err := solver.Solve(ctx, ChallengeSolveRequest{SiteKey: "refused"}); err == nil || !strings.Contains(err.Error(), "balance too low") {
+		t.Errorf("Expected solver error, got %v", err)
+	}
+	if _, err := solver.Solve(ctx, ChallengeSolveRequest{SiteKey: "other"}); err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("Expected HTTP status error, got %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package webtools
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+	"golang.org/x/text/encoding"
+)
+
+// charsetSniffLength is how far into the content declarations are looked
+// for, as browsers do
+const charsetSniffLength = 1024
+
+// xmlEncodingPattern matches the encoding in an XML declaration
+var xmlEncodingPattern = regexp.MustCompile(`^\s*<\?xml[^>]*?\sencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// textEncodingSchema describes the encoding argument of tools that decode
+// text
+func textEncodingSchema(what string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Character encoding of " + what + ", when it is mislabelled or undeclared. By default it is detected from a byte order mark, the charset in Content-Type, an HTML <meta charset> or XML declaration, and otherwise read as UTF-8 when valid and Windows-1252 when not. Takes WHATWG labels such as shift_jis, gbk, euc-kr or iso-8859-1",
+		"examples":    []string{"shift_jis", "gbk", "iso-8859-1", "euc-kr"},
+	}
+}
+
+// parseTextEncoding reads the optional encoding argument, checking that it
+// names an encoding this server can decode
+func parseTextEncoding(args map[string]interface{}) (string, error) {
+	raw, ok := args["encoding"]
+	if !ok || raw == nil {
+		return "", nil
+	}
+	label, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("encoding must be a string")
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return "", nil
+	}
+	if e, _ := charset.Lookup(label); e == nil {
+		return "", fmt.Errorf("unknown encoding %q", label)
+	}
+	return label, nil
+}
+
+// decodeText converts content to UTF-8 and returns it with the name of the
+// encoding it was read as. label forces the encoding; otherwise it is
+// detected with contentType, the Content-Type header, when there is one.
+func decodeText(content []byte, contentType, label string) (string, string, error) {
+	var (
+		e    encoding.Encoding
+		name string
+	)
+	if label != "" {
+		if e, name = charset.Lookup(label); e == nil {
+			return "", "", fmt.Errorf("unknown encoding %q", label)
+		}
+	} else {
+		e, name = detectCharset(content, contentType)
+	}
+	text := content
+	if name != "utf-8" {
+		var err error
+		if text, err = e.NewDecoder().Bytes(content); err != nil {
+			return "", "", fmt.Errorf("failed to decode %s text: %w", name, err)
+		}
+	}
+	// The decoders keep a byte order mark as U+FEFF; it isn't text
+	return strings.TrimPrefix(string(text), "\uFEFF"), name, nil
+}
+
+// detectCharset works out content's encoding. A byte order mark or the
+// charset in contentType is trusted; after that valid UTF-8 wins, then an
+// XML or HTML declaration, then the web's Windows-1252 default.
+func detectCharset(content []byte, contentType string) (encoding.Encoding, string) {
+	if e, name, certain := charset.DetermineEncoding(content, contentType); certain {
+		return e, name
+	}
+	if utf8.Valid(content) {
+		e, name := charset.Lookup("utf-8")
+		return e, name
+	}
+	head := content
+	if len(head) > charsetSniffLength {
+		head = head[:charsetSniffLength]
+	}
+	if m := xmlEncodingPattern.FindSubmatch(head); m != nil {
+		if e, name := charset.Lookup(string(m[1])); e != nil {
+			return e, name
+		}
+	}
+	// Without a declaration this falls back to Windows-1252
+	e, name, _ := charset.DetermineEncoding(head, "")
+	return e, name
+}
+
+// isTextContentType reports whether a Content-Type header describes text
+// that should be decoded, rather than binary data; a missing header counts
+// as text
+func isTextContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/x-javascript",
+		"application/ecmascript", "application/x-www-form-urlencoded", "application/xhtml+xml":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package webtools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func encodeText(t *testing.T, e encoding.Encoding, s string) []byte {
+	t.Helper()
+	out, err := e.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestDecodeText(t *testing.T) {
+	utf16, _ := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().Bytes([]byte("héllo"))
+	cases := []struct {
+		name        string
+		content     []byte
+		contentType string
+		label       string
+		text        string
+		encoding    string
+	}{
+		{"header charset", encodeText(t, charmap.ISO8859_1, "café"), "text/plain; charset=ISO-8859-1", "", "café", "windows-1252"},
+		{"meta charset", encodeText(t, japanese.ShiftJIS, `<html><head><meta charset="shift_jis"></head><body>日本語</body></html>`), "text/html", "", "日本語", "shift_jis"},
+		{"xml declaration", encodeText(t, simplifiedchinese.GBK, `<?xml version="1.0" encoding="GBK"?><p>中文</p>`), "", "", "中文", "gbk"},
+		{"utf-16 bom", utf16, "", "", "héllo", "utf-16le"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFplain"), "", "", "plain", "utf-8"},
+		{"utf-8 past the sniff window", []byte(strings.Repeat("a", 2000) + "ü"), "", "", "ü", "utf-8"},
+		{"undeclared legacy bytes", []byte("caf\xE9"), "", "", "café", "windows-1252"},
+		{"label overrides detection", encodeText(t, japanese.ShiftJIS, "テスト"), "text/plain; charset=utf-8", "Shift_JIS", "テスト", "shift_jis"},
+	}
+	for _, c := range cases {
+		text, name, err := decodeText(c.content, c.contentType, c.label)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if !strings.Contains(text, c.text) || name != c.encoding {
+			t.Errorf("%s: got %q as %s, want %q as %s", c.name, text, name, c.text, c.encoding)
+		}
+		if strings.HasPrefix(text, "\uFEFF") {
+			t.Errorf("%s: byte order mark left in the text", c.name)
+		}
+	}
+
+	if _, err := parseTextEncoding(map[string]interface{}{"encoding": "klingon"}); err == nil {
+		t.Error("unknown encoding accepted")
+	}
+	if label, err := parseTextEncoding(map[string]interface{}{"encoding": " gbk "}); err != nil || label != "gbk" {
+		t.Errorf("parseTextEncoding = %q, %v", label, err)
+	}
+}
+
+func TestIsTextContentType(t *testing.T) {
+	for _, ct := range []string{"", "text/html; charset=euc-kr", "application/json", "application/ld+json", "image/svg+xml"} {
+		if !isTextContentType(ct) {
+			t.Errorf("%q should be decoded as text", ct)
+		}
+	}
+	for _, ct := range []string{"image/png", "application/octet-stream", "application/pdf"} {
+		if isTextContentType(ct) {
+			t.Errorf("%q should be left as bytes", ct)
+		}
+	}
+}
+
+func TestHTTPRequestDecodesLegacyCharset(t *testing.T) {
+	body := encodeText(t, japanese.ShiftJIS, "<html><body>こんにちは</body></html>")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=Shift_JIS")
+		w.Write(body)
+	}))
+	defer server.Close()
+
+	resp, err := NewHTTPRequestTool(createTestLogger(t), nil, nil).Execute(map[string]interface{}{"url": server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if !strings.Contains(data["body"].(string), "こんにちは") || data["charset"] != "shift_jis" {
+		t.Errorf("body = %q, charset = %v", data["body"], data["charset"])
+	}
+	if data["response_size"] != len(body) {
+		t.Errorf("response_size = %v, want the raw %d bytes", data["response_size"], len(body))
+	}
+}
+
+func TestReadFileDecodesLegacyCharset(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "legacy.txt")
+	if err := os.WriteFile(path, encodeText(t, simplifiedchinese.GBK, "你好，世界"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tool := NewReadFileTool(createTestLogger(t), NewPathValidator(&FileAccessConfig{AllowedPaths: []string{dir}, MaxFileSize: 1024}))
+
+	resp, err := tool.Execute(map[string]interface{}{"path": path, "encoding": "gbk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if resp.Content[0].Text != "你好，世界" || data["encoding"] != "gbk" {
+		t.Errorf("read %q as %v", resp.Content[0].Text, data["encoding"])
+	}
+
+	if err := os.WriteFile(path, []byte("plain ascii"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err = tool.Execute(map[string]interface{}{"path": path})
+	if err != nil || resp.Content[0].Data.(map[string]interface{})["encoding"] != "utf-8" {
+		t.Errorf("ascii file: %v %v", resp, err)
+	}
+}
@@ -0,0 +1,501 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+const (
+	// diffCellSize is the grid used to group changed pixels into regions
+	diffCellSize = 32
+	// maxDiffRegions keeps the region list readable on a page that changed
+	// everywhere
+	maxDiffRegions = 20
+	// maxCompareLines bounds the changed text aligned line by line; lines
+	// past it on either page are left out of the diff
+	maxCompareLines       = 3000
+	defaultMaxTextChanges = 50
+	defaultCompareWidth   = 800
+)
+
+// pageCapture is what compare_pages records for one side
+type pageCapture struct {
+	URL        string   `json:"url"`
+	FinalURL   string   `json:"final_url"`
+	Title      string   `json:"title"`
+	Screenshot []byte   `json:"-"`
+	Lines      []string `json:"-"`
+}
+
+// imageComparison is the visual difference between two screenshots
+type imageComparison struct {
+	ChangedPixels int               `json:"changed_pixels"`
+	TotalPixels   int               `json:"total_pixels"`
+	Ratio         float64           `json:"ratio"`
+	SizeA         [2]int            `json:"size_a"`
+	SizeB         [2]int            `json:"size_b"`
+	Regions       []image.Rectangle `json:"-"`
+	Diff          *image.RGBA       `json:"-"`
+}
+
+// compareImages compares two screenshots over the area both cover, counting
+// pixels only one of them covers as changed. The diff image is b faded to
+// grey with changed pixels in red.
+func compareImages(a, b image.Image) imageComparison {
+	ab, bb := a.Bounds(), b.Bounds()
+	width, height := ab.Dx(), ab.Dy()
+	if bb.Dx() > width {
+		width = bb.Dx()
+	}
+	if bb.Dy() > height {
+		height = bb.Dy()
+	}
+	result := imageComparison{
+		TotalPixels: width * height,
+		SizeA:       [2]int{ab.Dx(), ab.Dy()},
+		SizeB:       [2]int{bb.Dx(), bb.Dy()},
+		Diff:        image.NewRGBA(image.Rect(0, 0, width, height)),
+	}
+	if result.TotalPixels == 0 {
+		return result
+	}
+
+	differ := func(x, y uint32) bool {
+		d := int(x>>8) - int(y>>8)
+		return d > pixelTolerance || d < -pixelTolerance
+	}
+	cols, rows := (width+diffCellSize-1)/diffCellSize, (height+diffCellSize-1)/diffCellSize
+	cells := make([]bool, cols*rows)
+	red := color.RGBA{R: 255, A: 255}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			inA := x < ab.Dx() && y < ab.Dy()
+			inB := x < bb.Dx() && y < bb.Dy()
+			changed := inA != inB
+			var r2, g2, b2 uint32
+			if inB {
+				r2, g2, b2, _ = b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			}
+			if inA && inB {
+				r1, g1, b1, a1 := a.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+				_, _, _, a2 := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+				changed = differ(r1, r2) || differ(g1, g2) || differ(b1, b2) || differ(a1, a2)
+			}
+			if changed {
+				result.ChangedPixels++
+				cells[(y/diffCellSize)*cols+x/diffCellSize] = true
+				result.Diff.SetRGBA(x, y, red)
+				continue
+			}
+			// Faded grey keeps the layout visible behind the red
+			grey := uint8(((r2+g2+b2)/3>>8)/4 + 190)
+			result.Diff.SetRGBA(x, y, color.RGBA{R: grey, G: grey, B: grey, A: 255})
+		}
+	}
+	result.Ratio = float64(result.ChangedPixels) / float64(result.TotalPixels)
+	result.Regions = changedRegions(cells, cols, rows, width, height)
+	return result
+}
+
+// changedRegions merges touching changed grid cells into bounding boxes,
+// largest first
+func changedRegions(cells []bool, cols, rows, width, height int) []image.Rectangle {
+	seen := make([]bool, len(cells))
+	var regions []image.Rectangle
+	for start := range cells {
+		if !cells[start] || seen[start] {
+			continue
+		}
+		box := image.Rectangle{Min: image.Pt(cols, rows)}
+		stack := []int{start}
+		seen[start] = true
+		for len(stack) > 0 {
+			cell := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			cx, cy := cell%cols, cell/cols
+			box = box.Union(image.Rect(cx, cy, cx+1, cy+1))
+			for _, d := range [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+				nx, ny := cx+d[0], cy+d[1]
+				if nx < 0 || ny < 0 || nx >= cols || ny >= rows {
+					continue
+				}
+				next := ny*cols + nx
+				if cells[next] && !seen[next] {
+					seen[next] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		regions = append(regions, image.Rect(
+			box.Min.X*diffCellSize, box.Min.Y*diffCellSize,
+			min(box.Max.X*diffCellSize, width), min(box.Max.Y*diffCellSize, height)))
+	}
+
+	area := func(r image.Rectangle) int { return r.Dx() * r.Dy() }
+	for i := 1; i < len(regions); i++ {
+		for j := i; j > 0 && area(regions[j]) > area(regions[j-1]); j-- {
+			regions[j], regions[j-1] = regions[j-1], regions[j]
+		}
+	}
+	return regions
+}
+
+// textLineChange is a line present on only one of the two pages
+type textLineChange struct {
+	Op   string `json:"op"` // "-" only on page A, "+" only on page B
+	Line string `json:"line"`
+}
+
+// diffLines lists the lines that differ between a and b in reading order,
+// aligning the two by longest common subsequence
+func diffLines(a, b []string) []textLineChange {
+	start := 0
+	for start < len(a) && start < len(b) && a[start] == b[start] {
+		start++
+	}
+	endA, endB := len(a), len(b)
+	for endA > start && endB > start && a[endA-1] == b[endB-1] {
+		endA--
+		endB--
+	}
+	midA, midB := a[start:endA], b[start:endB]
+	if len(midA) > maxCompareLines {
+		midA = midA[:maxCompareLines]
+	}
+	if len(midB) > maxCompareLines {
+		midB = midB[:maxCompareLines]
+	}
+
+	n, m := len(midA), len(midB)
+	lengths := make([][]uint16, n+1)
+	for i := range lengths {
+		lengths[i] = make([]uint16, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if midA[i] == midB[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var changes []textLineChange
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && midA[i] == midB[j]:
+			i++
+			j++
+		case j >= m || (i < n && lengths[i+1][j] >= lengths[i][j+1]):
+			changes = append(changes, textLineChange{Op: "-", Line: midA[i]})
+			i++
+		default:
+			changes = append(changes, textLineChange{Op: "+", Line: midB[j]})
+			j++
+		}
+	}
+	return changes
+}
+
+func comparePageScript(selector string) string {
+	return fmt.Sprintf(`
+		const root = %s;
+		if (!root) return { error: 'no element matches the selector' };
+		return { url: location.href, title: document.title, text: root.innerText || '' };
+	`, rootExpression(selector))
+}
+
+// rootExpression is the JS expression for the compared element
+func rootExpression(selector string) string {
+	if selector == "" {
+		return "document.body"
+	}
+	quoted, _ := json.Marshal(selector)
+	return fmt.Sprintf("document.querySelector(%s)", quoted)
+}
+
+// splitTextLines breaks innerText into trimmed, non-empty lines
+func splitTextLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// ComparePagesTool loads two URLs and reports how they differ
+type ComparePagesTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewComparePagesTool(log *logger.Logger, mgr *browser.Manager) *ComparePagesTool {
+	return &ComparePagesTool{logger: log, browserMgr: mgr}
+}
+
+func (t *ComparePagesTool) Name() string {
+	return "compare_pages"
+}
+
+func (t *ComparePagesTool) Description() string {
+	return "Load two URLs (e.g. staging vs production) side by side, then report the visual difference between their screenshots (changed area and regions, with a highlighted diff image) and the text lines found on only one of them"
+}
+
+func (t *ComparePagesTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"url_a": map[string]interface{}{
+				"type":        "string",
+				"description": "First URL, usually the reference (e.g. production)",
+				"examples":    []string{"https://example.com/pricing"},
+			},
+			"url_b": map[string]interface{}{
+				"type":        "string",
+				"description": "Second URL, compared against url_a (e.g. staging)",
+				"examples":    []string{"https://staging.example.com/pricing"},
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Compare only this element on both pages instead of the viewport and body text",
+			},
+			"wait_for": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to wait for on both pages before capturing",
+			},
+			"include_images": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Return both screenshots and the diff image (default: true)",
+				"default":     true,
+			},
+			"inline_max_width": map[string]interface{}{
+				"type":        "integer",
+				"description": "Shrink returned images to at most this many pixels wide (default: 800)",
+				"default":     defaultCompareWidth,
+				"minimum":     1,
+				"maximum":     maxInlineWidth,
+			},
+			"max_text_changes": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most differing text lines to report (default: 50)",
+				"default":     defaultMaxTextChanges,
+				"minimum":     0,
+				"maximum":     1000,
+			},
+		},
+		Required: []string{"url_a", "url_b"},
+	}
+}
+
+// capture opens url in a fresh page and records its screenshot and text
+func (t *ComparePagesTool) capture(url, selector, waitFor string) (*pageCapture, error) {
+	_, pageID, err := t.browserMgr.NewPage(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", url, err)
+	}
+	defer t.browserMgr.ClosePage(pageID)
+
+	if waitFor != "" {
+		if _, err := t.browserMgr.ExecuteScript(pageID, waitForSelectorScript(waitFor)); err != nil {
+			return nil, fmt.Errorf("%s: wait_for %s: %w", url, waitFor, err)
+		}
+	}
+
+	raw, err := t.browserMgr.ExecuteScript(pageID, comparePageScript(selector))
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read page: %w", url, err)
+	}
+	var page struct {
+		Error string `json:"error"`
+		URL   string `json:"url"`
+		Title string `json:"title"`
+		Text  string `json:"text"`
+	}
+	if err := decodeScriptValue(raw, &page); err != nil {
+		return nil, fmt.Errorf("%s: failed to read page: %w", url, err)
+	}
+	if page.Error != "" {
+		return nil, fmt.Errorf("%s: %s", url, page.Error)
+	}
+
+	var shot []byte
+	if selector != "" {
+		shot, err = t.browserMgr.ElementScreenshot(pageID, selector)
+	} else {
+		shot, err = t.browserMgr.Screenshot(pageID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: screenshot failed: %w", url, err)
+	}
+
+	return &pageCapture{
+		URL:        url,
+		FinalURL:   page.URL,
+		Title:      page.Title,
+		Screenshot: shot,
+		Lines:      splitTextLines(page.Text),
+	}, nil
+}
+
+func (t *ComparePagesTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		urlA, _ := args["url_a"].(string)
+		urlB, _ := args["url_b"].(string)
+		if urlA == "" || urlB == "" {
+			return fail("url_a and url_b are required")
+		}
+		selector, _ := args["selector"].(string)
+		waitFor, _ := args["wait_for"].(string)
+		includeImages := true
+		if val, ok := args["include_images"].(bool); ok {
+			includeImages = val
+		}
+		shrink := imageShrink{maxWidth: defaultCompareWidth}
+		if val, ok := args["inline_max_width"].(float64); ok {
+			if val < 1 || val > maxInlineWidth {
+				return fail(fmt.Sprintf("inline_max_width must be between 1 and %d", maxInlineWidth))
+			}
+			shrink.maxWidth = int(val)
+		}
+		maxTextChanges := defaultMaxTextChanges
+		if val, ok := args["max_text_changes"].(float64); ok {
+			maxTextChanges = int(val)
+		}
+
+		// Both pages load at once so timing-dependent content lines up
+		var captures [2]*pageCapture
+		var errs [2]error
+		var wg sync.WaitGroup
+		for i, url := range []string{urlA, urlB} {
+			wg.Add(1)
+			go func(i int, url string) {
+				defer wg.Done()
+				defer func() {
+					if r := recover(); r != nil {
+						errs[i] = fmt.Errorf("%s: capture panicked: %v", url, r)
+					}
+				}()
+				captures[i], errs[i] = t.capture(url, selector, waitFor)
+			}(i, url)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				return fail(err.Error())
+			}
+		}
+		a, b := captures[0], captures[1]
+
+		imgA, _, err := image.Decode(bytes.NewReader(a.Screenshot))
+		if err != nil {
+			return fail(fmt.Sprintf("failed to decode screenshot of %s: %v", urlA, err))
+		}
+		imgB, _, err := image.Decode(bytes.NewReader(b.Screenshot))
+		if err != nil {
+			return fail(fmt.Sprintf("failed to decode screenshot of %s: %v", urlB, err))
+		}
+		visual := compareImages(imgA, imgB)
+		textChanges := diffLines(a.Lines, b.Lines)
+		textTotal := len(textChanges)
+		if len(textChanges) > maxTextChanges {
+			textChanges = textChanges[:maxTextChanges]
+		}
+
+		regions := visual.Regions
+		if len(regions) > maxDiffRegions {
+			regions = regions[:maxDiffRegions]
+		}
+		regionData := make([]map[string]int, len(regions))
+		for i, r := range regions {
+			regionData[i] = map[string]int{"x": r.Min.X, "y": r.Min.Y, "width": r.Dx(), "height": r.Dy()}
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "A: %s", a.FinalURL)
+		if a.Title != "" {
+			fmt.Fprintf(&text, " (%q)", a.Title)
+		}
+		fmt.Fprintf(&text, "\nB: %s", b.FinalURL)
+		if b.Title != "" {
+			fmt.Fprintf(&text, " (%q)", b.Title)
+		}
+		fmt.Fprintf(&text, "\n\nVisual: %.2f%% of pixels differ", visual.Ratio*100)
+		if visual.SizeA != visual.SizeB {
+			fmt.Fprintf(&text, " (sizes differ: %dx%d vs %dx%d)", visual.SizeA[0], visual.SizeA[1], visual.SizeB[0], visual.SizeB[1])
+		}
+		if len(visual.Regions) > 0 {
+			fmt.Fprintf(&text, " in %d regions", len(visual.Regions))
+			for _, r := range regions {
+				fmt.Fprintf(&text, "\n  - %dx%d at (%d, %d)", r.Dx(), r.Dy(), r.Min.X, r.Min.Y)
+			}
+		}
+		if textTotal == 0 {
+			text.WriteString("\n\nText: identical")
+		} else {
+			fmt.Fprintf(&text, "\n\nText: %d lines differ (- only on A, + only on B)", textTotal)
+			if textTotal > len(textChanges) {
+				fmt.Fprintf(&text, ", showing the first %d", len(textChanges))
+			}
+			for _, c := range textChanges {
+				fmt.Fprintf(&text, "\n%s %s", c.Op, clipText(c.Line, 200))
+			}
+		}
+
+		content := []types.ToolContent{{
+			Type: "text",
+			Text: text.String(),
+			Data: map[string]interface{}{
+				"page_a":             a,
+				"page_b":             b,
+				"visual":             visual,
+				"regions":            regionData,
+				"text_changes":       textChanges,
+				"text_changes_total": textTotal,
+				"identical":          visual.ChangedPixels == 0 && textTotal == 0,
+			},
+		}}
+
+		if includeImages {
+			text.WriteString("\n\nImages: A, B, then the difference in red over B")
+			content[0].Text = text.String()
+			var diffPNG bytes.Buffer
+			if err := png.Encode(&diffPNG, visual.Diff); err != nil {
+				return fail(fmt.Sprintf("failed to encode diff image: %v", err))
+			}
+			for _, shot := range [][]byte{a.Screenshot, b.Screenshot, diffPNG.Bytes()} {
+				item, err := inlineImageContent(shot, shrink)
+				if err != nil {
+					return fail(err.Error())
+				}
+				content = append(content, item)
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{Content: content}, nil
+	})
+}
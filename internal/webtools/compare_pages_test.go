@@ -0,0 +1,96 @@
+package webtools
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func filledImage(width, height int, c color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCompareImages(t *testing.T) {
+	white := color.RGBA{255, 255, 255, 255}
+	a := filledImage(128, 128, white)
+	b := filledImage(128, 128, white)
+
+	if result := compareImages(a, b); result.ChangedPixels != 0 || len(result.Regions) != 0 {
+		t.Errorf("identical images differ: %+v", result)
+	}
+
+	// Two separate changed blocks, plus antialiasing-level noise elsewhere
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			b.SetRGBA(x, y, color.RGBA{0, 0, 0, 255})
+			b.SetRGBA(100+x, 100+y, color.RGBA{0, 0, 0, 255})
+		}
+	}
+	b.SetRGBA(64, 64, color.RGBA{250, 250, 250, 255})
+
+	result := compareImages(a, b)
+	if result.ChangedPixels != 200 {
+		t.Errorf("changed pixels = %d, want 200", result.ChangedPixels)
+	}
+	want := []image.Rectangle{image.Rect(0, 0, 32, 32), image.Rect(96, 96, 128, 128)}
+	if !reflect.DeepEqual(result.Regions, want) {
+		t.Errorf("regions = %v, want %v", result.Regions, want)
+	}
+	if got := result.Diff.RGBAAt(5, 5); got != (color.RGBA{255, 0, 0, 255}) {
+		t.Errorf("changed pixel not highlighted: %v", got)
+	}
+
+	// Area covered by only one image counts as changed
+	result = compareImages(a, filledImage(128, 64, white))
+	if result.ChangedPixels != 128*64 || result.SizeB != [2]int{128, 64} {
+		t.Errorf("size mismatch: %+v", result)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"Pricing", "Basic $10", "Pro $20", "Contact us"}
+	b := []string{"Pricing", "Basic $10", "Pro $25", "Team $50", "Contact us"}
+
+	got := diffLines(a, b)
+	want := []textLineChange{
+		{Op: "-", Line: "Pro $20"},
+		{Op: "+", Line: "Pro $25"},
+		{Op: "+", Line: "Team $50"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("diffLines = %+v, want %+v", got, want)
+	}
+	if got := diffLines(a, a); len(got) != 0 {
+		t.Errorf("identical text differs: %+v", got)
+	}
+}
+
+func TestSplitTextLines(t *testing.T) {
+	got := splitTextLines("  Title \n\n\tSome   text\n \n")
+	if want := []string{"Title", "Some text"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("splitTextLines = %v, want %v", got, want)
+	}
+}
+
+func TestComparePagesRequiresURLs(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewComparePagesTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{"url_a": "https://example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "url_b") {
+		t.Errorf("Expected missing URL error, got %q", resp.Content[0].Text)
+	}
+}
@@ -0,0 +1,129 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"rodmcp/internal/browser"
+)
+
+// Completion sources for the completion/complete request. Each returns the
+// candidates for what has been typed so far; the server filters them by
+// prefix, so a source may return more than matches.
+
+// PageIDCompletions suggests the IDs of open pages
+func PageIDCompletions(mgr *browser.Manager) func(string, map[string]string) ([]string, error) {
+	return func(string, map[string]string) ([]string, error) {
+		pages := mgr.ListPages()
+		sort.Strings(pages)
+		return pages, nil
+	}
+}
+
+// RecipeNameCompletions suggests the names of saved scrape recipes
+func RecipeNameCompletions(dir string) func(string, map[string]string) ([]string, error) {
+	store := newRecipeStore(dir)
+	return func(string, map[string]string) ([]string, error) {
+		return storedNames(store.dir)
+	}
+}
+
+// WorkflowNameCompletions suggests the names of saved workflows
+func WorkflowNameCompletions(dir string) func(string, map[string]string) ([]string, error) {
+	store := newWorkflowStore(dir)
+	return func(string, map[string]string) ([]string, error) {
+		return storedNames(store.dir)
+	}
+}
+
+// SessionNameCompletions suggests the names of sessions saved by login
+func SessionNameCompletions(dir string) func(string, map[string]string) ([]string, error) {
+	store := newSessionStore(dir)
+	return func(string, map[string]string) ([]string, error) {
+		return storedNames(store.dir)
+	}
+}
+
+// FingerprintNameCompletions suggests the names of fingerprint profiles
+func FingerprintNameCompletions(dir string) func(string, map[string]string) ([]string, error) {
+	store := newFingerprintStore(dir)
+	return func(string, map[string]string) ([]string, error) {
+		return storedNames(store.dir)
+	}
+}
+
+// storedNames lists the documents a name-keyed JSON store keeps in dir
+func storedNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() || !recipeNamePattern.MatchString(name) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// PathCompletions suggests files and directories the validator allows
+// reading, resolving relative input against a cwd argument the way the
+// file tools do. Directories end in "/" so the next request lists their
+// contents. When the typed directory is outside the allowed paths, the
+// allowed roots themselves are suggested.
+func PathCompletions(validator *PathValidator) func(string, map[string]string) ([]string, error) {
+	return func(value string, args map[string]string) ([]string, error) {
+		dirPart, prefix := filepath.Split(value)
+		listDir := dirPart
+		if listDir == "" {
+			listDir = "."
+		}
+
+		resolved, err := validator.ResolvePath(listDir, args["cwd"])
+		if err == nil {
+			err = validator.ValidatePath(resolved, "read")
+		}
+		if err != nil {
+			var roots []string
+			for _, root := range validator.GetAllowedPaths() {
+				roots = append(roots, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator))
+			}
+			return roots, nil
+		}
+
+		entries, err := os.ReadDir(resolved)
+		if err != nil {
+			return nil, err
+		}
+
+		var suggestions []string
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+				continue
+			}
+			// Hidden entries only when asked for
+			if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+				continue
+			}
+			if validator.ValidatePath(filepath.Join(resolved, name), "read") != nil {
+				continue
+			}
+			suggestion := dirPart + name
+			if entry.IsDir() {
+				suggestion += string(filepath.Separator)
+			}
+			suggestions = append(suggestions, suggestion)
+		}
+		return suggestions, nil
+	}
+}
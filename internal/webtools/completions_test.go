@@ -0,0 +1,76 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestStoredNameCompletions(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"shop.json", "news.json", "notes.txt", "bad name.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested.json"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := RecipeNameCompletions(dir)("", nil)
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if want := []string{"news", "shop"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("names = %v, want %v", names, want)
+	}
+
+	// A store that was never written to has no names
+	names, err = SessionNameCompletions(filepath.Join(dir, "missing"))("", nil)
+	if err != nil || len(names) != 0 {
+		t.Errorf("missing store: %v %v", names, err)
+	}
+}
+
+func TestPathCompletions(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"src", "docs"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{"src/main.go", "src/menu.go", "src/.env", "readme.md"} {
+		if err := os.WriteFile(filepath.Join(root, file), nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	validator := NewPathValidator(&FileAccessConfig{AllowedPaths: []string{root}, MaxFileSize: 1024})
+	complete := PathCompletions(validator)
+	cwd := map[string]string{"cwd": root}
+
+	got, err := complete("", cwd)
+	if err != nil {
+		t.Fatalf("completion failed: %v", err)
+	}
+	if want := []string{"docs/", "readme.md", "src/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("top level = %v, want %v", got, want)
+	}
+
+	got, _ = complete("src/m", cwd)
+	if want := []string{"src/main.go", "src/menu.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("src/m = %v, want %v", got, want)
+	}
+
+	// Hidden files only when the prefix asks for them
+	got, _ = complete("src/.", cwd)
+	if want := []string{"src/.env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("src/. = %v, want %v", got, want)
+	}
+
+	// Outside the allowed paths, the allowed roots are offered instead
+	got, _ = complete("/", nil)
+	if want := []string{root + "/"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("/ = %v, want %v", got, want)
+	}
+}
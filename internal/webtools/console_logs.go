@@ -0,0 +1,183 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// defaultConsoleLimit is how many of the most recent entries
+// get_console_logs returns when no limit is given
+const defaultConsoleLimit = 100
+
+// filterConsoleEntries keeps entries at or above minLevel and, when source
+// is set, from that source
+func filterConsoleEntries(entries []browser.ConsoleEntry, minLevel, source string) []browser.ConsoleEntry {
+	rank := browser.ConsoleLevelRank(minLevel)
+	kept := []browser.ConsoleEntry{}
+	for _, e := range entries {
+		if browser.ConsoleLevelRank(e.Level) < rank {
+			continue
+		}
+		if source != "" && e.Source != source {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
+// formatConsoleEntry renders an entry as one line, e.g.
+// `[error] exception: Uncaught TypeError: x is undefined (app.js:12:5)`
+func formatConsoleEntry(e browser.ConsoleEntry) string {
+	text := fmt.Sprintf("[%s] %s: %s", e.Level, e.Source, clipText(strings.TrimSpace(e.Text), 300))
+	if e.URL != "" {
+		location := e.URL
+		if e.Line > 0 {
+			location += fmt.Sprintf(":%d", e.Line)
+			if e.Column > 0 {
+				location += fmt.Sprintf(":%d", e.Column)
+			}
+		}
+		text += " (" + location + ")"
+	}
+	return text
+}
+
+// GetConsoleLogsTool returns what a page has written to the console
+type GetConsoleLogsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewGetConsoleLogsTool(log *logger.Logger, mgr *browser.Manager) *GetConsoleLogsTool {
+	return &GetConsoleLogsTool{logger: log, browserMgr: mgr}
+}
+
+func (t *GetConsoleLogsTool) Name() string {
+	return "get_console_logs"
+}
+
+func (t *GetConsoleLogsTool) Description() string {
+	return "Get the console messages, uncaught exceptions and browser log messages (failed resource loads, security and deprecation warnings) a page has produced since its last navigation, oldest first"
+}
+
+func (t *GetConsoleLogsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"level": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return entries at this level or more severe",
+				"enum":        browser.ConsoleLevels,
+				"default":     browser.ConsoleLevelDebug,
+			},
+			"source": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return entries from this source: console, exception, network, security, violation, deprecation, ...",
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most recent entries to return",
+				"default":     defaultConsoleLimit,
+				"minimum":     1,
+				"maximum":     browser.MaxConsoleEntries,
+			},
+			"clear": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Empty the page's console log after reading it, so the next call shows only new entries (default: false)",
+				"default":     false,
+			},
+		},
+	}
+}
+
+func (t *GetConsoleLogsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		level := browser.ConsoleLevelDebug
+		if v, _ := args["level"].(string); v != "" {
+			if browser.ConsoleLevelRank(v) < 0 {
+				return fail(fmt.Sprintf("level must be one of %s", strings.Join(browser.ConsoleLevels, ", ")))
+			}
+			level = v
+		}
+		source, _ := args["source"].(string)
+		limit := defaultConsoleLimit
+		if v, ok := args["limit"].(float64); ok {
+			if v < 1 || v > browser.MaxConsoleEntries || v != float64(int(v)) {
+				return fail(fmt.Sprintf("limit must be a whole number between 1 and %d", browser.MaxConsoleEntries))
+			}
+			limit = int(v)
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		entries, dropped, err := t.browserMgr.ConsoleLogs(pageID)
+		if err != nil {
+			return fail(err.Error())
+		}
+		if clear, _ := args["clear"].(bool); clear {
+			if err := t.browserMgr.ClearConsoleLogs(pageID); err != nil {
+				return fail(err.Error())
+			}
+		}
+
+		counts := make(map[string]int)
+		for _, e := range entries {
+			counts[e.Level]++
+		}
+		matched := filterConsoleEntries(entries, level, source)
+		shown := matched
+		if len(shown) > limit {
+			shown = shown[len(shown)-limit:]
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "%d console entries since the last navigation (%d errors, %d warnings)",
+			len(entries), counts[browser.ConsoleLevelError], counts[browser.ConsoleLevelWarning])
+		if len(matched) != len(entries) {
+			fmt.Fprintf(&text, ", %d matching", len(matched))
+		}
+		if len(shown) < len(matched) {
+			fmt.Fprintf(&text, "; showing the last %d", len(shown))
+		}
+		for _, e := range shown {
+			text.WriteString("\n" + formatConsoleEntry(e))
+		}
+		if dropped > 0 {
+			fmt.Fprintf(&text, "\n%d older entries were dropped from the log", dropped)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text.String(),
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"total":   len(entries),
+					"matched": len(matched),
+					"errors":  counts[browser.ConsoleLevelError],
+					"dropped": dropped,
+					"entries": shown,
+				},
+			}},
+		}, nil
+	})
+}
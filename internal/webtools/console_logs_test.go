@@ -0,0 +1,54 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestFilterConsoleEntries(t *testing.T) {
+	entries := []browser.ConsoleEntry{
+		{Level: "debug", Source: "console", Text: "render"},
+		{Level: "info", Source: "console", Text: "ready"},
+		{Level: "warning", Source: "deprecation", Text: "unload is deprecated"},
+		{Level: "error", Source: "network", Text: "Failed to load resource", URL: "https://example.com/a.png"},
+		{Level: "error", Source: "exception", Text: "TypeError: x is undefined", URL: "https://example.com/app.js", Line: 12, Column: 5},
+	}
+	if got := filterConsoleEntries(entries, "warning", ""); len(got) != 3 {
+		t.Errorf("warning and above = %d entries, want 3", len(got))
+	}
+	got := filterConsoleEntries(entries, "debug", "exception")
+	if len(got) != 1 {
+		t.Fatalf("exception entries = %+v", got)
+	}
+	if line := formatConsoleEntry(got[0]); line != "[error] exception: TypeError: x is undefined (https://example.com/app.js:12:5)" {
+		t.Errorf("formatted = %q", line)
+	}
+	if line := formatConsoleEntry(entries[1]); line != "[info] console: ready" {
+		t.Errorf("formatted = %q", line)
+	}
+}
+
+func TestGetConsoleLogsValidation(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewGetConsoleLogsTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"bad level", map[string]interface{}{"level": "fatal"}, "level must be one of"},
+		{"bad limit", map[string]interface{}{"limit": float64(0)}, "limit must be"},
+		{"no pages", map[string]interface{}{}, "No browser pages"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
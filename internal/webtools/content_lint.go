@@ -0,0 +1,486 @@
+package webtools
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Content checks lint_content runs
+const (
+	lintSpelling     = "spelling"
+	lintDoubleSpaces = "double_spaces"
+	lintLoremIpsum   = "lorem_ipsum"
+	lintTodo         = "todo"
+)
+
+var lintChecks = []string{lintSpelling, lintDoubleSpaces, lintLoremIpsum, lintTodo}
+
+const (
+	defaultLintLocale    = "en_US"
+	defaultLintMaxIssues = 100
+)
+
+var (
+	// Collapsed by the browser, but still there when the text is copied or
+	// syndicated; non-breaking spaces render doubled
+	doubleSpacePattern = regexp.MustCompile(`\S[ \x{00a0}]{2,}\S`)
+	loremPattern       = regexp.MustCompile(`(?i)\b(lorem ipsum|dolor sit amet|consectetur adipiscing|sed do eiusmod)\b`)
+	todoPattern        = regexp.MustCompile(`\b(TODO|FIXME|TBD|TKTK|XXX)\b|\[(?i:insert|placeholder|add)\b[^\]]{0,40}\]`)
+	// URLs, email addresses and handles are not words
+	nonWordPattern = regexp.MustCompile(`(?i)\b(https?://|www\.)\S+|\S+@\S+\.\S+|[@#]\w+`)
+	wordPattern    = regexp.MustCompile(`[\p{L}\p{N}][\p{L}\p{N}'’]*`)
+)
+
+// spellDictionary is the set of words a spellcheck accepts
+type spellDictionary map[string]bool
+
+// read adds the words in r to d. It reads plain word lists, one
+// word per line, and hunspell .dic files, whose first line is a count and
+// whose entries carry /FLAGS after the word.
+func (d spellDictionary) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	first := true
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if first {
+			first = false
+			if _, err := fmt.Sscanf(line, "%d", new(int)); err == nil && !strings.ContainsFunc(line, unicode.IsLetter) {
+				continue
+			}
+		}
+		if i := strings.IndexAny(line, "/\t "); i >= 0 {
+			line = line[:i]
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			d[strings.ReplaceAll(line, "’", "'")] = true
+		}
+	}
+	return scanner.Err()
+}
+
+// stems are suffixes stripped from a word the dictionary does not list,
+// with what replaces them, so hunspell stem lists accept inflections
+var stems = [][2]string{
+	{"'s", ""}, {"s'", "s"}, {"ies", "y"}, {"ied", "y"}, {"es", ""}, {"s", ""},
+	{"ed", ""}, {"ed", "e"}, {"ing", ""}, {"ing", "e"}, {"ly", ""}, {"er", ""}, {"est", ""},
+}
+
+// knows reports whether the dictionary accepts word as written, in lower
+// case, or as an inflection of a listed word
+func (d spellDictionary) knows(word string) bool {
+	lower := strings.ToLower(word)
+	if d[word] || d[lower] {
+		return true
+	}
+	for _, stem := range stems {
+		if base, ok := strings.CutSuffix(lower, stem[0]); ok && len(base) > 1 && d[base+stem[1]] {
+			return true
+		}
+	}
+	return false
+}
+
+// suggest returns up to three listed words one edit away from word
+func (d spellDictionary) suggest(word string) []string {
+	lower := []rune(strings.ToLower(word))
+	seen := make(map[string]bool)
+	var out []string
+	try := func(candidate []rune) {
+		s := string(candidate)
+		if len(out) < 3 && !seen[s] && d[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	for i := 0; i < len(lower); i++ {
+		if i+1 < len(lower) {
+			swapped := append([]rune(nil), lower...)
+			swapped[i], swapped[i+1] = swapped[i+1], swapped[i]
+			try(swapped)
+		}
+		try(append(append([]rune(nil), lower[:i]...), lower[i+1:]...))
+	}
+	for i := 0; i <= len(lower); i++ {
+		for c := 'a'; c <= 'z'; c++ {
+			try(append(append(append([]rune(nil), lower[:i]...), c), lower[i:]...))
+			if i < len(lower) && lower[i] != c {
+				replaced := append([]rune(nil), lower...)
+				replaced[i] = c
+				try(replaced)
+			}
+		}
+	}
+	return out
+}
+
+// systemDictionaryPaths lists where operating systems install the
+// dictionary for locale, most specific first
+func systemDictionaryPaths(locale string) []string {
+	locale = strings.ReplaceAll(locale, "-", "_")
+	var paths []string
+	for _, dir := range []string{"/usr/share/hunspell", "/usr/share/myspell", "/usr/share/myspell/dicts", "/Library/Spelling"} {
+		paths = append(paths, dir+"/"+locale+".dic")
+	}
+	switch strings.ToLower(locale) {
+	case "en_us":
+		paths = append(paths, "/usr/share/dict/american-english")
+	case "en_gb":
+		paths = append(paths, "/usr/share/dict/british-english")
+	case "en_ca":
+		paths = append(paths, "/usr/share/dict/canadian-english")
+	}
+	if strings.HasPrefix(strings.ToLower(locale), "en") {
+		paths = append(paths, "/usr/share/dict/words")
+	}
+	return paths
+}
+
+// contentIssue is one problem lint_content found
+type contentIssue struct {
+	Check     string   `json:"check"`
+	Text      string   `json:"text"`
+	Selector  string   `json:"selector"`
+	Attribute string   `json:"attribute,omitempty"`
+	Count     int      `json:"count,omitempty"`
+	Suggest   []string `json:"suggestions,omitempty"`
+}
+
+// contentLintReport is everything lint_content found. Misspellings are
+// reported once per word, at the first place they appear.
+type contentLintReport struct {
+	Blocks int            `json:"blocks"`
+	Words  int            `json:"words"`
+	Counts map[string]int `json:"counts"`
+	Issues []contentIssue `json:"issues"`
+	// SpellingSkipped explains why spelling was not checked
+	SpellingSkipped string `json:"spelling_skipped,omitempty"`
+}
+
+// excerpt returns the match, widened to whole words, with up to three
+// words either side, for reports
+func excerpt(text string, start, end int) string {
+	for start > 0 && !unicode.IsSpace(rune(text[start-1])) {
+		start--
+	}
+	for end < len(text) && !unicode.IsSpace(rune(text[end])) {
+		end++
+	}
+	before, after := strings.Fields(text[:start]), strings.Fields(text[end:])
+	// The match itself is kept as written so doubled spaces show
+	words := append(before[max(len(before)-3, 0):], text[start:end])
+	return strings.Join(append(words, after[:min(len(after), 3)]...), " ")
+}
+
+// lintContent runs checks over blocks. dict may be nil when spelling is not
+// checked; ignore lists extra words to accept.
+func lintContent(blocks []browser.TextBlock, checks map[string]bool, dict spellDictionary, ignore map[string]bool) contentLintReport {
+	report := contentLintReport{Blocks: len(blocks), Counts: make(map[string]int), Issues: []contentIssue{}}
+	add := func(issue contentIssue) {
+		report.Counts[issue.Check]++
+		report.Issues = append(report.Issues, issue)
+	}
+	misspelled := make(map[string]int)
+
+	for _, block := range blocks {
+		text := block.Text
+		at := func(check, found string) contentIssue {
+			return contentIssue{Check: check, Text: found, Selector: block.Selector, Attribute: block.Attribute}
+		}
+		if checks[lintDoubleSpaces] {
+			for _, m := range doubleSpacePattern.FindAllStringIndex(text, -1) {
+				add(at(lintDoubleSpaces, excerpt(text, m[0], m[1])))
+			}
+		}
+		if checks[lintLoremIpsum] {
+			if m := loremPattern.FindStringIndex(text); m != nil {
+				add(at(lintLoremIpsum, excerpt(text, m[0], m[1])))
+			}
+		}
+		if checks[lintTodo] {
+			for _, m := range todoPattern.FindAllStringIndex(text, -1) {
+				add(at(lintTodo, excerpt(text, m[0], m[1])))
+			}
+		}
+
+		words := wordPattern.FindAllString(nonWordPattern.ReplaceAllString(text, " "), -1)
+		report.Words += len(words)
+		if dict == nil || block.NoSpellcheck {
+			continue
+		}
+		for _, word := range words {
+			word = strings.Trim(strings.ReplaceAll(word, "’", "'"), "'")
+			if !isSpellcheckable(word) || ignore[strings.ToLower(word)] || dict.knows(word) {
+				continue
+			}
+			key := strings.ToLower(word)
+			if misspelled[key] == 0 {
+				issue := at(lintSpelling, word)
+				issue.Suggest = dict.suggest(word)
+				add(issue)
+			}
+			misspelled[key]++
+		}
+	}
+	for i := range report.Issues {
+		if report.Issues[i].Check == lintSpelling {
+			report.Issues[i].Count = misspelled[strings.ToLower(report.Issues[i].Text)]
+		}
+	}
+	return report
+}
+
+// isSpellcheckable skips words a dictionary cannot judge: single letters,
+// anything with digits, acronyms and mixed-case names like iPhone
+func isSpellcheckable(word string) bool {
+	runes := []rune(word)
+	if len(runes) < 2 {
+		return false
+	}
+	for i, r := range runes {
+		if unicode.IsDigit(r) || (i > 0 && unicode.IsUpper(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+var lintCheckTitles = map[string]string{
+	lintSpelling:     "Spelling",
+	lintDoubleSpaces: "Double spaces",
+	lintLoremIpsum:   "Lorem ipsum",
+	lintTodo:         "TODO markers",
+}
+
+func formatContentLint(report contentLintReport, maxIssues int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Checked %d text blocks (%d words): %d issues", report.Blocks, report.Words, len(report.Issues))
+	if report.SpellingSkipped != "" {
+		fmt.Fprintf(&b, "\nSpelling not checked: %s", report.SpellingSkipped)
+	}
+	shown := 0
+	for _, check := range lintChecks {
+		if report.Counts[check] == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d):", lintCheckTitles[check], report.Counts[check])
+		for _, issue := range report.Issues {
+			if issue.Check != check {
+				continue
+			}
+			if shown >= maxIssues {
+				break
+			}
+			shown++
+			where := issue.Selector
+			if issue.Attribute != "" {
+				where += " [" + issue.Attribute + "]"
+			}
+			fmt.Fprintf(&b, "\n  %q", issue.Text)
+			if issue.Count > 1 {
+				fmt.Fprintf(&b, " x%d", issue.Count)
+			}
+			if len(issue.Suggest) > 0 {
+				fmt.Fprintf(&b, " (did you mean %s?)", strings.Join(issue.Suggest, ", "))
+			}
+			fmt.Fprintf(&b, " in %s", where)
+		}
+	}
+	if shown < len(report.Issues) {
+		fmt.Fprintf(&b, "\n... %d more issues not shown", len(report.Issues)-shown)
+	}
+	return b.String()
+}
+
+// LintContentTool checks a page's visible copy for spelling mistakes and
+// unfinished content
+type LintContentTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
+
+	mutex sync.Mutex
+	dicts map[string]spellDictionary
+}
+
+func NewLintContentTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator) *LintContentTool {
+	return &LintContentTool{logger: log, browserMgr: mgr, validator: validator, dicts: make(map[string]spellDictionary)}
+}
+
+func (t *LintContentTool) Name() string {
+	return "lint_content"
+}
+
+func (t *LintContentTool) Description() string {
+	return "Proofread a page's visible text, alt text and placeholders before publishing: spelling against a hunspell or word-list dictionary, doubled spaces, leftover lorem ipsum and TODO/FIXME/TBD markers, with a selector for each problem"
+}
+
+func (t *LintContentTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Only check text inside this element, e.g. main or article. " + selectorSyntax,
+				"default":     "body",
+			},
+			"checks": map[string]interface{}{
+				"type":        "array",
+				"description": "Checks to run (default: all)",
+				"items":       map[string]interface{}{"type": "string", "enum": lintChecks},
+			},
+			"locale": map[string]interface{}{
+				"type":        "string",
+				"description": "Spelling locale; its hunspell dictionary or system word list is used, e.g. en_US, en_GB, de_DE",
+				"default":     defaultLintLocale,
+			},
+			"dictionary": map[string]interface{}{
+				"type":        "string",
+				"description": "Dictionary file to use instead of the locale's: a word list with one word per line, or a hunspell .dic file. Must be inside the allowed paths",
+			},
+			"ignore_words": map[string]interface{}{
+				"type":        "array",
+				"description": "Extra words to accept, such as product names and jargon",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"max_issues": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most issues to list in the text response",
+				"default":     defaultLintMaxIssues,
+				"minimum":     1,
+			},
+		},
+	}
+}
+
+// dictionary loads and caches the dictionary at path
+func (t *LintContentTool) dictionary(path string) (spellDictionary, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if dict, ok := t.dicts[path]; ok {
+		return dict, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	dict := make(spellDictionary)
+	if err := dict.read(file); err != nil {
+		return nil, fmt.Errorf("failed to read dictionary %s: %w", path, err)
+	}
+	t.dicts[path] = dict
+	return dict, nil
+}
+
+func (t *LintContentTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		checks := make(map[string]bool)
+		if list, ok := args["checks"].([]interface{}); ok && len(list) > 0 {
+			for _, item := range list {
+				check, _ := item.(string)
+				if !containsString(lintChecks, check) {
+					return fail(fmt.Sprintf("unknown check %q; use %s", check, strings.Join(lintChecks, ", ")))
+				}
+				checks[check] = true
+			}
+		} else {
+			for _, check := range lintChecks {
+				checks[check] = true
+			}
+		}
+		maxIssues := defaultLintMaxIssues
+		if v, ok := args["max_issues"].(float64); ok {
+			if v < 1 || v != float64(int(v)) {
+				return fail("max_issues must be a positive whole number")
+			}
+			maxIssues = int(v)
+		}
+		selector, _ := args["selector"].(string)
+		if strings.TrimSpace(selector) == "" {
+			selector = "body"
+		}
+
+		var dict spellDictionary
+		skipped := ""
+		if checks[lintSpelling] {
+			if custom, _ := args["dictionary"].(string); custom != "" {
+				path, err := resolveFileArg(t.validator, map[string]interface{}{"path": custom, "cwd": args["cwd"]}, "read")
+				if err != nil {
+					return fail(err.Error())
+				}
+				if dict, err = t.dictionary(path); err != nil {
+					return fail(fmt.Sprintf("Failed to load dictionary: %v", err))
+				}
+			} else {
+				locale, _ := args["locale"].(string)
+				if locale == "" {
+					locale = defaultLintLocale
+				}
+				paths := systemDictionaryPaths(locale)
+				for _, path := range paths {
+					if d, err := t.dictionary(path); err == nil {
+						dict = d
+						break
+					}
+				}
+				if dict == nil {
+					skipped = fmt.Sprintf("no %s dictionary found (looked in %s); install a hunspell dictionary or pass dictionary",
+						locale, strings.Join(paths, ", "))
+				}
+			}
+		}
+		ignore := make(map[string]bool)
+		if list, ok := args["ignore_words"].([]interface{}); ok {
+			for _, item := range list {
+				if word, ok := item.(string); ok {
+					ignore[strings.ToLower(strings.TrimSpace(word))] = true
+				}
+			}
+		}
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		blocks, err := t.browserMgr.TextBlocks(pageID, selector, browser.ElementTimeout)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read page text: %v", err))
+		}
+
+		report := lintContent(blocks, checks, dict, ignore)
+		report.SpellingSkipped = skipped
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: formatContentLint(report, maxIssues),
+				Data: map[string]interface{}{
+					"page_id":  pageID,
+					"selector": selector,
+					"report":   report,
+				},
+			}},
+		}, nil
+	})
+}
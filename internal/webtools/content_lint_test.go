@@ -0,0 +1,122 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func testDictionary(t *testing.T, content string) spellDictionary {
+	t.Helper()
+	dict := make(spellDictionary)
+	if err := dict.read(strings.NewReader(content)); err != nil {
+		t.Fatal(err)
+	}
+	return dict
+}
+
+func TestSpellDictionary(t *testing.T) {
+	// hunspell .dic: a count line, then stems with affix flags
+	dict := testDictionary(t, "6\nreceive/DRSZG\nthe\nstory/SM\nLondon/M\nhope/DSG\nwe\n")
+	for _, word := range []string{"receive", "Receive", "received", "receiving", "stories", "London", "hoped", "hoping"} {
+		if !dict.knows(word) {
+			t.Errorf("knows(%q) = false", word)
+		}
+	}
+	for _, word := range []string{"recieve", "6", "teh"} {
+		if dict.knows(word) {
+			t.Errorf("knows(%q) = true", word)
+		}
+	}
+	if got := dict.suggest("recieve"); len(got) != 1 || got[0] != "receive" {
+		t.Errorf("suggest(recieve) = %v", got)
+	}
+	if got := dict.suggest("teh"); len(got) == 0 || got[0] != "the" {
+		t.Errorf("suggest(teh) = %v", got)
+	}
+}
+
+func TestLintContent(t *testing.T) {
+	dict := testDictionary(t, "we\nwill\nreceive\nyour\norder\nsoon\nthanks\nfor\nshopping\nphoto\nof\nthe\nteam\nsee\nprice\n")
+	blocks := []browser.TextBlock{
+		{Selector: "main > p:nth-of-type(1)", Text: "We will recieve your order soon.  Thanks for shopping with Acme!"},
+		{Selector: "#price", Text: "TODO: price TBD, see https://wiki.example.com/pricing"},
+		{Selector: "aside p", Text: "Lorem ipsum dolor sit amet, consectetur adipiscing elit."},
+		{Selector: "img.hero", Text: "Photo of teh team", Attribute: "alt"},
+		{Selector: "p.sku", Text: "Order XJ-9000 via iPhone, recieve", NoSpellcheck: true},
+		{Selector: "p.again", Text: "recieve"},
+	}
+	all := map[string]bool{lintSpelling: true, lintDoubleSpaces: true, lintLoremIpsum: true, lintTodo: true}
+	report := lintContent(blocks, all, dict, map[string]bool{"acme": true, "lorem": true, "ipsum": true, "dolor": true, "sit": true, "amet": true, "consectetur": true, "adipiscing": true, "elit": true, "with": true, "todo": true, "tbd": true})
+
+	want := map[string]int{lintSpelling: 2, lintDoubleSpaces: 1, lintLoremIpsum: 1, lintTodo: 2}
+	for check, n := range want {
+		if report.Counts[check] != n {
+			t.Errorf("%s: %d issues, want %d (%+v)", check, report.Counts[check], n, report.Issues)
+		}
+	}
+
+	var recieve, teh *contentIssue
+	for i, issue := range report.Issues {
+		switch issue.Text {
+		case "recieve":
+			recieve = &report.Issues[i]
+		case "teh":
+			teh = &report.Issues[i]
+		}
+	}
+	if recieve == nil || recieve.Count != 2 || recieve.Selector != "main > p:nth-of-type(1)" || len(recieve.Suggest) == 0 {
+		t.Errorf("recieve issue = %+v", recieve)
+	}
+	if teh == nil || teh.Attribute != "alt" {
+		t.Errorf("teh issue = %+v", teh)
+	}
+
+	text := formatContentLint(report, 100)
+	for _, s := range []string{`"recieve" x2 (did you mean receive?) in main > p:nth-of-type(1)`, `in img.hero [alt]`, "Double spaces (1):", `"recieve your order soon.  Thanks for shopping with"`} {
+		if !strings.Contains(text, s) {
+			t.Errorf("report missing %q:\n%s", s, text)
+		}
+	}
+	if short := formatContentLint(report, 2); !strings.Contains(short, "4 more issues not shown") {
+		t.Errorf("truncated report:\n%s", short)
+	}
+
+	// Without a dictionary the other checks still run
+	if report := lintContent(blocks, all, nil, nil); report.Counts[lintSpelling] != 0 || report.Counts[lintTodo] != 2 {
+		t.Errorf("counts without dictionary = %v", report.Counts)
+	}
+}
+
+func TestLintContentValidation(t *testing.T) {
+	log := createTestLogger(t)
+	dir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{AllowedPaths: []string{dir}, MaxFileSize: 1024})
+	tool := NewLintContentTool(log, browser.NewManager(log, browser.Config{Headless: true}), validator)
+
+	if err := os.WriteFile(filepath.Join(dir, "words.txt"), []byte("hello\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"unknown check", map[string]interface{}{"checks": []interface{}{"grammar"}}, "unknown check"},
+		{"bad max_issues", map[string]interface{}{"max_issues": float64(0)}, "max_issues must be"},
+		{"dictionary outside allowed paths", map[string]interface{}{"dictionary": "/etc/passwd"}, "file access denied"},
+		{"missing dictionary", map[string]interface{}{"dictionary": filepath.Join(dir, "none.dic")}, "Failed to load dictionary"},
+		{"no pages", map[string]interface{}{"dictionary": filepath.Join(dir, "words.txt")}, "No browser pages"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,483 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// cookieSummary is a cookie as the cookie tools report it
+func cookieSummary(c *proto.NetworkCookie) map[string]interface{} {
+	summary := map[string]interface{}{
+		"name":      c.Name,
+		"value":     c.Value,
+		"domain":    c.Domain,
+		"path":      c.Path,
+		"secure":    c.Secure,
+		"http_only": c.HTTPOnly,
+		"session":   c.Session,
+	}
+	if c.SameSite != "" {
+		summary["same_site"] = string(c.SameSite)
+	}
+	if !c.Session && c.Expires > 0 {
+		summary["expires"] = time.Unix(int64(c.Expires), 0).UTC().Format(time.RFC3339)
+	}
+	return summary
+}
+
+// filterCookies keeps the cookies called name (when set) that would be
+// sent to domain (when set)
+func filterCookies(cookies []*proto.NetworkCookie, name, domain string) []*proto.NetworkCookie {
+	var kept []*proto.NetworkCookie
+	for _, c := range cookies {
+		if name != "" && c.Name != name {
+			continue
+		}
+		if domain != "" && !cookieMatchesHost(c.Domain, strings.TrimPrefix(domain, ".")) {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	return kept
+}
+
+// cookieParamFromArgs builds the cookie set_cookie stores
+func cookieParamFromArgs(args map[string]interface{}, now time.Time) (*proto.NetworkCookieParam, error) {
+	name, _ := args["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+	if strings.ContainsAny(name, "=;, \t\r\n") {
+		return nil, fmt.Errorf("cookie name %q cannot contain '=', ';', ',' or whitespace", name)
+	}
+	value, _ := args["value"].(string)
+	if strings.ContainsAny(value, ";\r\n") {
+		return nil, fmt.Errorf("cookie value cannot contain ';' or line breaks")
+	}
+
+	cookie := &proto.NetworkCookieParam{Name: name, Value: value}
+	cookie.URL, _ = args["url"].(string)
+	cookie.Domain, _ = args["domain"].(string)
+	cookie.Path, _ = args["path"].(string)
+	cookie.Secure, _ = args["secure"].(bool)
+	cookie.HTTPOnly, _ = args["http_only"].(bool)
+	if cookie.URL != "" {
+		if err := ValidateURL(cookie.URL, "set_cookie"); err != nil {
+			return nil, err
+		}
+	}
+
+	if sameSite, _ := args["same_site"].(string); sameSite != "" {
+		switch strings.ToLower(sameSite) {
+		case "strict":
+			cookie.SameSite = proto.NetworkCookieSameSiteStrict
+		case "lax":
+			cookie.SameSite = proto.NetworkCookieSameSiteLax
+		case "none":
+			if !cookie.Secure {
+				return nil, fmt.Errorf("same_site None needs secure=true; browsers drop it otherwise")
+			}
+			cookie.SameSite = proto.NetworkCookieSameSiteNone
+		default:
+			return nil, fmt.Errorf("same_site must be Strict, Lax or None")
+		}
+	}
+
+	expires, hasExpires := args["expires"].(float64)
+	maxAge, hasMaxAge := args["max_age"].(float64)
+	switch {
+	case hasExpires && hasMaxAge:
+		return nil, fmt.Errorf("use expires or max_age, not both")
+	case hasExpires:
+		cookie.Expires = proto.TimeSinceEpoch(expires)
+	case hasMaxAge:
+		if maxAge <= 0 {
+			return nil, fmt.Errorf("max_age must be positive; use delete_cookies to remove a cookie")
+		}
+		cookie.Expires = proto.TimeSinceEpoch(now.Add(time.Duration(maxAge * float64(time.Second))).Unix())
+	}
+	return cookie, nil
+}
+
+// pageIDOrCurrent resolves the page_id argument, defaulting to the current page
+func pageIDOrCurrent(mgr *browser.Manager, args map[string]interface{}) string {
+	pageID, _ := args["page_id"].(string)
+	if pageID == "" {
+		pageID = mgr.GetCurrentPageID()
+	}
+	return pageID
+}
+
+// GetCookiesTool lists cookies
+type GetCookiesTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewGetCookiesTool(log *logger.Logger, mgr *browser.Manager) *GetCookiesTool {
+	return &GetCookiesTool{logger: log, browserMgr: mgr}
+}
+
+func (t *GetCookiesTool) Name() string {
+	return "get_cookies"
+}
+
+func (t *GetCookiesTool) Description() string {
+	return "List the cookies the browser sends to the current page, or to the given URLs, with their flags and expiry"
+}
+
+func (t *GetCookiesTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"urls": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "URLs to list cookies for (default: the page's URL)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Only cookies with this name",
+			},
+			"domain": map[string]interface{}{
+				"type":        "string",
+				"description": "Only cookies sent to this host, e.g. example.com",
+			},
+		},
+	}
+}
+
+func (t *GetCookiesTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		var urls []string
+		if list, ok := args["urls"].([]interface{}); ok {
+			for _, item := range list {
+				u, _ := item.(string)
+				if err := ValidateURL(u, t.Name()); err != nil {
+					return fail(err.Error())
+				}
+				urls = append(urls, u)
+			}
+		}
+
+		cookies, err := t.browserMgr.Cookies(pageID, urls)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read cookies: %v", err))
+		}
+		name, _ := args["name"].(string)
+		domain, _ := args["domain"].(string)
+		cookies = filterCookies(cookies, name, domain)
+
+		summaries := make([]map[string]interface{}, len(cookies))
+		names := make([]string, len(cookies))
+		for i, c := range cookies {
+			summaries[i] = cookieSummary(c)
+			names[i] = c.Name
+		}
+		text := fmt.Sprintf("Found %d cookies", len(cookies))
+		if len(names) > 0 {
+			text += ": " + strings.Join(names, ", ")
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"count":   len(cookies),
+					"cookies": summaries,
+				},
+			}},
+		}, nil
+	})
+}
+
+// SetCookieTool stores one cookie
+type SetCookieTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSetCookieTool(log *logger.Logger, mgr *browser.Manager) *SetCookieTool {
+	return &SetCookieTool{logger: log, browserMgr: mgr}
+}
+
+func (t *SetCookieTool) Name() string {
+	return "set_cookie"
+}
+
+func (t *SetCookieTool) Description() string {
+	return "Set a cookie in the browser, for the current page's URL unless a url or domain is given. Replaces any cookie with the same name, domain and path"
+}
+
+func (t *SetCookieTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Cookie name",
+			},
+			"value": map[string]interface{}{
+				"type":        "string",
+				"description": "Cookie value",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "URL the cookie is for; sets its domain, path and secure flag (default: the page's URL)",
+			},
+			"domain": map[string]interface{}{
+				"type":        "string",
+				"description": "Domain, e.g. .example.com to include subdomains",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path (default: /)",
+			},
+			"secure": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Only send over HTTPS",
+			},
+			"http_only": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Hide the cookie from page scripts",
+			},
+			"same_site": map[string]interface{}{
+				"type":        "string",
+				"description": "SameSite policy; None needs secure",
+				"enum":        []string{"Strict", "Lax", "None"},
+			},
+			"expires": map[string]interface{}{
+				"type":        "number",
+				"description": "Expiry as Unix seconds (default: a session cookie)",
+			},
+			"max_age": map[string]interface{}{
+				"type":        "number",
+				"description": "Expiry as seconds from now, instead of expires",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+		Required: []string{"name", "value"},
+	}
+}
+
+func (t *SetCookieTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		cookie, err := cookieParamFromArgs(args, time.Now())
+		if err != nil {
+			return fail(err.Error())
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		if err := t.browserMgr.SetCookies(pageID, []*proto.NetworkCookieParam{cookie}); err != nil {
+			return fail(fmt.Sprintf("Failed to set cookie %s: %v", cookie.Name, err))
+		}
+
+		scope := cookie.Domain
+		if scope == "" {
+			scope = cookie.URL
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Set cookie %s for %s", cookie.Name, scope),
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"name":    cookie.Name,
+					"url":     cookie.URL,
+					"domain":  cookie.Domain,
+				},
+			}},
+		}, nil
+	})
+}
+
+// DeleteCookiesTool deletes cookies by name
+type DeleteCookiesTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewDeleteCookiesTool(log *logger.Logger, mgr *browser.Manager) *DeleteCookiesTool {
+	return &DeleteCookiesTool{logger: log, browserMgr: mgr}
+}
+
+func (t *DeleteCookiesTool) Name() string {
+	return "delete_cookies"
+}
+
+func (t *DeleteCookiesTool) Description() string {
+	return "Delete the cookies with a name, optionally only those for a URL, domain or path. Use clear_cookies to delete every cookie"
+}
+
+func (t *DeleteCookiesTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the cookies to delete",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "Only cookies that would be sent to this URL",
+			},
+			"domain": map[string]interface{}{
+				"type":        "string",
+				"description": "Only cookies with exactly this domain",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Only cookies with exactly this path",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
+func (t *DeleteCookiesTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		req := proto.NetworkDeleteCookies{}
+		req.Name, _ = args["name"].(string)
+		req.URL, _ = args["url"].(string)
+		req.Domain, _ = args["domain"].(string)
+		req.Path, _ = args["path"].(string)
+		if req.Name == "" {
+			return fail("name is required")
+		}
+		if req.URL != "" {
+			if err := ValidateURL(req.URL, t.Name()); err != nil {
+				return fail(err.Error())
+			}
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		// CDP does not say what it deleted, so count before and after
+		before, err := t.browserMgr.Cookies(pageID, nil)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read cookies: %v", err))
+		}
+		if err := t.browserMgr.DeleteCookies(pageID, req); err != nil {
+			return fail(fmt.Sprintf("Failed to delete cookies: %v", err))
+		}
+		after, err := t.browserMgr.Cookies(pageID, nil)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read cookies: %v", err))
+		}
+		deleted := len(filterCookies(before, req.Name, "")) - len(filterCookies(after, req.Name, ""))
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Deleted cookies named %s (%d visible to the page)", req.Name, deleted),
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"name":    req.Name,
+					"deleted": deleted,
+				},
+			}},
+		}, nil
+	})
+}
+
+// ClearCookiesTool deletes every cookie in the browser
+type ClearCookiesTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewClearCookiesTool(log *logger.Logger, mgr *browser.Manager) *ClearCookiesTool {
+	return &ClearCookiesTool{logger: log, browserMgr: mgr}
+}
+
+func (t *ClearCookiesTool) Name() string {
+	return "clear_cookies"
+}
+
+func (t *ClearCookiesTool) Description() string {
+	return "Delete every cookie in the browser, signing out of all sites"
+}
+
+func (t *ClearCookiesTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *ClearCookiesTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		if err := t.browserMgr.ClearCookies(pageID); err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(fmt.Sprintf("Failed to clear cookies: %v", err)), nil
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: "Cleared all cookies",
+				Data: map[string]interface{}{"page_id": pageID},
+			}},
+		}, nil
+	})
+}
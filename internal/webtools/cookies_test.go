@@ -0,0 +1,63 @@
+package webtools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestCookieParamFromArgs(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cookie, err := cookieParamFromArgs(map[string]interface{}{
+		"name":      "session",
+		"value":     "abc",
+		"domain":    ".example.com",
+		"secure":    true,
+		"http_only": true,
+		"same_site": "none",
+		"max_age":   float64(3600),
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cookie.Domain != ".example.com" || !cookie.Secure || !cookie.HTTPOnly || cookie.SameSite != proto.NetworkCookieSameSiteNone {
+		t.Errorf("cookie = %+v", cookie)
+	}
+	if cookie.Expires != proto.TimeSinceEpoch(1700003600) {
+		t.Errorf("expires = %v, want an hour from now", cookie.Expires)
+	}
+
+	bad := []map[string]interface{}{
+		{"value": "x"},
+		{"name": "a b", "value": "x"},
+		{"name": "a", "value": "x;y"},
+		{"name": "a", "value": "x", "same_site": "None"},
+		{"name": "a", "value": "x", "same_site": "sometimes"},
+		{"name": "a", "value": "x", "expires": float64(1), "max_age": float64(1)},
+		{"name": "a", "value": "x", "max_age": float64(0)},
+		{"name": "a", "value": "x", "url": "ftp://example.com"},
+	}
+	for _, args := range bad {
+		if _, err := cookieParamFromArgs(args, now); err == nil {
+			t.Errorf("cookieParamFromArgs(%v) accepted", args)
+		}
+	}
+}
+
+func TestFilterCookies(t *testing.T) {
+	cookies := []*proto.NetworkCookie{
+		{Name: "sid", Domain: ".example.com"},
+		{Name: "sid", Domain: "other.org"},
+		{Name: "theme", Domain: "shop.example.com"},
+	}
+	if got := filterCookies(cookies, "sid", ""); len(got) != 2 {
+		t.Errorf("by name = %d cookies, want 2", len(got))
+	}
+	if got := filterCookies(cookies, "", "shop.example.com"); len(got) != 2 {
+		t.Errorf("by domain = %d cookies, want 2", len(got))
+	}
+	if got := filterCookies(cookies, "theme", "example.com"); len(got) != 0 {
+		t.Errorf("theme is not sent to example.com, got %d cookies", len(got))
+	}
+}
@@ -0,0 +1,412 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCrawlDir holds crawl frontiers and results when no
+	// --crawl-dir is configured, relative to the working directory
+	defaultCrawlDir = "crawls"
+
+	defaultCrawlMaxPages    = 100
+	maxCrawlMaxPages        = 100000
+	defaultCrawlMaxDepth    = 3
+	defaultCrawlConcurrency = 4
+	maxCrawlConcurrency     = 16
+	defaultCrawlPerHost     = 1
+	defaultCrawlDelay       = time.Second
+	// maxCrawlDelay caps a robots.txt Crawl-delay so one host can't stall
+	// a crawl indefinitely
+	maxCrawlDelay = time.Minute
+)
+
+// crawlConfig is what a crawl was started with; it is kept with the
+// frontier so a resumed crawl behaves the same
+type crawlConfig struct {
+	ID        string   `json:"id"`
+	StartURLs []string `json:"start_urls"`
+	// Include lists URL patterns (* wildcards) a link must match to be
+	// followed; empty means the start URLs' hosts
+	Include        []string               `json:"include,omitempty"`
+	Exclude        []string               `json:"exclude,omitempty"`
+	MaxPages       int                    `json:"max_pages"`
+	MaxDepth       int                    `json:"max_depth"`
+	Concurrency    int                    `json:"concurrency"`
+	PerHost        int                    `json:"per_host"`
+	DelayMs        int                    `json:"delay_ms"`
+	RespectRobots  bool                   `json:"respect_robots"`
+	Selectors      map[string]interface{} `json:"selectors,omitempty"`
+	WaitFor        string                 `json:"wait_for,omitempty"`
+	BlockResources []string               `json:"block_resources,omitempty"`
+	// Sessions maps domains to saved session names; only the names are
+	// stored, the cookies are read from the session store on every run
+	Sessions map[string]string `json:"sessions,omitempty"`
+}
+
+// crawlItem is a URL waiting to be crawled
+type crawlItem struct {
+	URL   string `json:"url"`
+	Depth int    `json:"depth"`
+}
+
+// crawlPageResult is what crawling one URL produced; it is appended to the
+// crawl's pages.jsonl
+type crawlPageResult struct {
+	URL        string      `json:"url"`
+	FinalURL   string      `json:"final_url,omitempty"`
+	Depth      int         `json:"depth"`
+	Title      string      `json:"title,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	Links      int         `json:"links"`
+	Error      string      `json:"error,omitempty"`
+	Blocked    bool        `json:"blocked_by_robots,omitempty"`
+	Session    string      `json:"session,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+	CrawledAt  time.Time   `json:"crawled_at"`
+
+	links      []string
+	crawlDelay time.Duration
+	// interrupted is set when the crawl was paused mid-page; the URL
+	// stays queued
+	interrupted bool
+}
+
+// crawlHost is the scheduling state of one host during a run
+type crawlHost struct {
+	active int
+	nextAt time.Time
+	delay  time.Duration
+}
+
+// crawlFrontier is a crawl's persistent state: its settings, the URLs
+// still to visit and every URL it has seen. URLs leave the queue only once
+// crawled, so a crawl interrupted mid-page picks them up again on resume.
+type crawlFrontier struct {
+	Config    crawlConfig     `json:"config"`
+	Queue     []crawlItem     `json:"queue"`
+	Seen      map[string]bool `json:"seen"`
+	Crawled   int             `json:"crawled"`
+	Failed    int             `json:"failed"`
+	Blocked   int             `json:"blocked"`
+	StartedAt time.Time       `json:"started_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+
+	active map[string]bool
+	hosts  map[string]*crawlHost
+}
+
+func newCrawlFrontier(cfg crawlConfig, now time.Time) *crawlFrontier {
+	f := &crawlFrontier{Config: cfg, Seen: make(map[string]bool), StartedAt: now, UpdatedAt: now}
+	for _, raw := range cfg.StartURLs {
+		if u, ok := normalizeCrawlURL(raw); ok && !f.Seen[u] {
+			f.Seen[u] = true
+			f.Queue = append(f.Queue, crawlItem{URL: u})
+		}
+	}
+	return f
+}
+
+// normalizeCrawlURL drops the fragment so anchors on one page aren't
+// crawled twice; only http and https URLs are crawlable
+func normalizeCrawlURL(raw string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", false
+	}
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String(), true
+}
+
+func crawlHostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		return u.Host
+	}
+	return ""
+}
+
+// inScope reports whether a discovered link should be followed
+func (f *crawlFrontier) inScope(link string) bool {
+	for _, pattern := range f.Config.Exclude {
+		if matchURLPattern(pattern, link) {
+			return false
+		}
+	}
+	if len(f.Config.Include) > 0 {
+		for _, pattern := range f.Config.Include {
+			if matchURLPattern(pattern, link) {
+				return true
+			}
+		}
+		return false
+	}
+	host := crawlHostOf(link)
+	for _, start := range f.Config.StartURLs {
+		if crawlHostOf(start) == host {
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue adds a link found at depth-1, unless it is out of scope, already
+// seen, too deep or past the crawl's page limit
+func (f *crawlFrontier) enqueue(link string, depth int) bool {
+	u, ok := normalizeCrawlURL(link)
+	if !ok || f.Seen[u] || depth > f.Config.MaxDepth || len(f.Seen) >= f.Config.MaxPages || !f.inScope(u) {
+		return false
+	}
+	f.Seen[u] = true
+	f.Queue = append(f.Queue, crawlItem{URL: u, Depth: depth})
+	return true
+}
+
+func (f *crawlFrontier) host(name string) *crawlHost {
+	if f.hosts == nil {
+		f.hosts = make(map[string]*crawlHost)
+	}
+	h, ok := f.hosts[name]
+	if !ok {
+		h = &crawlHost{delay: time.Duration(f.Config.DelayMs) * time.Millisecond}
+		f.hosts[name] = h
+	}
+	return h
+}
+
+// next returns the first queued URL whose host has a free slot and has
+// waited out its delay. When none is ready it returns how long until one
+// is, or 0 when everything left is waiting on a page in progress.
+func (f *crawlFrontier) next(now time.Time) (crawlItem, time.Duration, bool) {
+	var wait time.Duration
+	for _, item := range f.Queue {
+		if f.active[item.URL] {
+			continue
+		}
+		h := f.host(crawlHostOf(item.URL))
+		if h.active >= f.Config.PerHost {
+			continue
+		}
+		if until := h.nextAt.Sub(now); until > 0 {
+			if wait == 0 || until < wait {
+				wait = until
+			}
+			continue
+		}
+		return item, 0, true
+	}
+	return crawlItem{}, wait, false
+}
+
+// dispatch marks an item as being crawled
+func (f *crawlFrontier) dispatch(item crawlItem, now time.Time) {
+	if f.active == nil {
+		f.active = make(map[string]bool)
+	}
+	f.active[item.URL] = true
+	h := f.host(crawlHostOf(item.URL))
+	h.active++
+	h.nextAt = now.Add(h.delay)
+}
+
+// complete records an item's result and queues the links it found
+func (f *crawlFrontier) complete(item crawlItem, result *crawlPageResult, now time.Time) {
+	delete(f.active, item.URL)
+	h := f.host(crawlHostOf(item.URL))
+	h.active--
+	if result.crawlDelay > h.delay {
+		h.delay = min(result.crawlDelay, maxCrawlDelay)
+		h.nextAt = now.Add(h.delay)
+	}
+	if result.interrupted {
+		return
+	}
+
+	for i, queued := range f.Queue {
+		if queued.URL == item.URL {
+			f.Queue = append(f.Queue[:i], f.Queue[i+1:]...)
+			break
+		}
+	}
+	switch {
+	case result.Blocked:
+		f.Blocked++
+	case result.Error != "":
+		f.Failed++
+	default:
+		f.Crawled++
+	}
+	if final, ok := normalizeCrawlURL(result.FinalURL); ok {
+		// Redirect targets count as seen so they aren't crawled again
+		f.Seen[final] = true
+	}
+	for _, link := range result.links {
+		f.enqueue(link, item.Depth+1)
+	}
+	f.UpdatedAt = now
+}
+
+// runCrawl crawls the frontier until its queue is empty or ctx is
+// cancelled, calling fetch for each URL and persist after each result.
+// In-flight pages are allowed to finish (or report that they were
+// interrupted) before it returns.
+func runCrawl(ctx context.Context, f *crawlFrontier, fetch func(context.Context, crawlItem) *crawlPageResult, persist func(*crawlPageResult)) {
+	type outcome struct {
+		item   crawlItem
+		result *crawlPageResult
+	}
+	outcomes := make(chan outcome)
+	active := 0
+	done := ctx.Done()
+	for {
+		var wait time.Duration
+		for ctx.Err() == nil && active < f.Config.Concurrency {
+			item, until, ok := f.next(time.Now())
+			if !ok {
+				wait = until
+				break
+			}
+			f.dispatch(item, time.Now())
+			active++
+			go func(item crawlItem) {
+				outcomes <- outcome{item, fetch(ctx, item)}
+			}(item)
+		}
+		if active == 0 && (ctx.Err() != nil || wait == 0) {
+			return
+		}
+
+		var timer <-chan time.Time
+		if wait > 0 {
+			timer = time.After(wait)
+		}
+		select {
+		case o := <-outcomes:
+			active--
+			f.complete(o.item, o.result, time.Now())
+			persist(o.result)
+		case <-timer:
+		case <-done:
+			done = nil
+		}
+	}
+}
+
+// crawlStore keeps each crawl in its own directory: frontier.json with
+// the resumable state and pages.jsonl with every page's result
+type crawlStore struct {
+	dir string
+}
+
+func newCrawlStore(dir string) *crawlStore {
+	if dir == "" {
+		dir = defaultCrawlDir
+	}
+	return &crawlStore{dir: dir}
+}
+
+func (s *crawlStore) crawlDir(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+func (s *crawlStore) load(id string) (*crawlFrontier, error) {
+	data, err := os.ReadFile(filepath.Join(s.crawlDir(id), "frontier.json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no crawl named %q", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f crawlFrontier
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("crawl %q is corrupt: %w", id, err)
+	}
+	if f.Seen == nil {
+		f.Seen = make(map[string]bool)
+	}
+	return &f, nil
+}
+
+func (s *crawlStore) exists(id string) bool {
+	_, err := os.Stat(filepath.Join(s.crawlDir(id), "frontier.json"))
+	return err == nil
+}
+
+func (s *crawlStore) save(f *crawlFrontier) error {
+	dir := s.crawlDir(f.Config.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create crawl directory: %w", err)
+	}
+	return writeJSONFile(filepath.Join(dir, "frontier.json"), f)
+}
+
+// appendResult adds a page's result to the crawl's pages.jsonl
+func (s *crawlStore) appendResult(id string, result *crawlPageResult) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(s.crawlDir(id), "pages.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// recentResults returns the last limit results of a crawl, oldest first
+func (s *crawlStore) recentResults(id string, limit int) ([]crawlPageResult, error) {
+	data, err := os.ReadFile(filepath.Join(s.crawlDir(id), "pages.jsonl"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+	var results []crawlPageResult
+	for _, line := range lines {
+		var r crawlPageResult
+		if json.Unmarshal([]byte(line), &r) == nil {
+			results = append(results, r)
+		}
+	}
+	return results, nil
+}
+
+// list returns the IDs of every stored crawl, sorted
+func (s *crawlStore) list() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, entry := range entries {
+		if entry.IsDir() && s.exists(entry.Name()) {
+			ids = append(ids, entry.Name())
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *crawlStore) remove(id string) error {
+	return os.RemoveAll(s.crawlDir(id))
+}
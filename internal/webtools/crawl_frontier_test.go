@@ -0,0 +1,223 @@
+package webtools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSite maps URLs to the links on that page
+type fakeSite map[string][]string
+
+func (s fakeSite) fetch(ctx context.Context, item crawlItem) *crawlPageResult {
+	links, ok := s[item.URL]
+	if !ok {
+		return &crawlPageResult{URL: item.URL, Error: "not found"}
+	}
+	return &crawlPageResult{URL: item.URL, links: links}
+}
+
+func testCrawlConfig(start ...string) crawlConfig {
+	return crawlConfig{
+		ID:          "test",
+		StartURLs:   start,
+		MaxPages:    100,
+		MaxDepth:    5,
+		Concurrency: 4,
+		PerHost:     2,
+	}
+}
+
+func TestCrawlFrontierScope(t *testing.T) {
+	f := newCrawlFrontier(testCrawlConfig("https://example.com/"), time.Now())
+	cases := []struct {
+		link string
+		want bool
+	}{
+		{"https://example.com/a", true},
+		{"https://example.com/a#frag", false}, // already seen without the fragment
+		{"https://example.com/#top", false},   // the start URL again
+		{"https://other.com/", false},
+		{"mailto:someone@example.com", false},
+		{"javascript:void(0)", false},
+	}
+	for _, c := range cases {
+		if got := f.enqueue(c.link, 1); got != c.want {
+			t.Errorf("enqueue(%s) = %v, want %v", c.link, got, c.want)
+		}
+	}
+
+	cfg := testCrawlConfig("https://example.com/")
+	cfg.Include = []string{"https://example.com/docs/*"}
+	cfg.Exclude = []string{"*.pdf"}
+	cfg.MaxDepth = 1
+	f = newCrawlFrontier(cfg, time.Now())
+	if f.enqueue("https://example.com/blog/", 1) {
+		t.Error("link outside include patterns was queued")
+	}
+	if f.enqueue("https://example.com/docs/guide.pdf", 1) {
+		t.Error("excluded link was queued")
+	}
+	if !f.enqueue("https://example.com/docs/guide", 1) {
+		t.Error("included link was not queued")
+	}
+	if f.enqueue("https://example.com/docs/deep", 2) {
+		t.Error("link past max_depth was queued")
+	}
+}
+
+func TestCrawlFrontierMaxPages(t *testing.T) {
+	cfg := testCrawlConfig("https://example.com/")
+	cfg.MaxPages = 3
+	f := newCrawlFrontier(cfg, time.Now())
+	for i := 0; i < 5; i++ {
+		f.enqueue(fmt.Sprintf("https://example.com/%d", i), 1)
+	}
+	if len(f.Seen) != 3 || len(f.Queue) != 3 {
+		t.Errorf("seen %d, queued %d; want 3 each", len(f.Seen), len(f.Queue))
+	}
+}
+
+func TestCrawlFrontierPoliteness(t *testing.T) {
+	cfg := testCrawlConfig("https://a.com/1", "https://a.com/2", "https://b.com/1")
+	cfg.PerHost = 1
+	cfg.DelayMs = 1000
+	f := newCrawlFrontier(cfg, time.Now())
+	now := time.Now()
+
+	first, _, ok := f.next(now)
+	if !ok || first.URL != "https://a.com/1" {
+		t.Fatalf("first = %v %v", first, ok)
+	}
+	f.dispatch(first, now)
+	second, _, ok := f.next(now)
+	if !ok || second.URL != "https://b.com/1" {
+		t.Fatalf("a.com is busy, so b.com should be next; got %v %v", second, ok)
+	}
+	f.dispatch(second, now)
+	if item, wait, ok := f.next(now); ok || wait != 0 {
+		t.Fatalf("both hosts are busy; got %v %v %v", item, wait, ok)
+	}
+
+	f.complete(first, &crawlPageResult{URL: first.URL}, now.Add(100*time.Millisecond))
+	item, wait, ok := f.next(now.Add(100 * time.Millisecond))
+	if ok || wait != 900*time.Millisecond {
+		t.Fatalf("a.com should wait out its delay; got %v %v %v", item, wait, ok)
+	}
+	if item, _, ok = f.next(now.Add(time.Second)); !ok || item.URL != "https://a.com/2" {
+		t.Fatalf("a.com/2 should be ready after the delay; got %v %v", item, ok)
+	}
+
+	// A longer robots.txt Crawl-delay replaces the configured delay
+	f.dispatch(item, now.Add(time.Second))
+	f.complete(item, &crawlPageResult{URL: item.URL, crawlDelay: 5 * time.Second}, now.Add(time.Second))
+	if got := f.host("a.com").delay; got != 5*time.Second {
+		t.Errorf("delay = %v, want 5s", got)
+	}
+}
+
+func TestRunCrawl(t *testing.T) {
+	site := fakeSite{
+		"https://example.com/":  {"https://example.com/a", "https://example.com/b", "https://elsewhere.com/"},
+		"https://example.com/a": {"https://example.com/", "https://example.com/c"},
+		"https://example.com/b": {"https://example.com/missing"},
+		"https://example.com/c": nil,
+	}
+	f := newCrawlFrontier(testCrawlConfig("https://example.com/"), time.Now())
+	var mu sync.Mutex
+	var crawled []string
+	runCrawl(context.Background(), f, site.fetch, func(r *crawlPageResult) {
+		mu.Lock()
+		crawled = append(crawled, r.URL)
+		mu.Unlock()
+	})
+
+	sort.Strings(crawled)
+	want := []string{"https://example.com/", "https://example.com/a", "https://example.com/b", "https://example.com/c", "https://example.com/missing"}
+	if fmt.Sprint(crawled) != fmt.Sprint(want) {
+		t.Errorf("crawled %v, want %v", crawled, want)
+	}
+	if f.Crawled != 4 || f.Failed != 1 || len(f.Queue) != 0 {
+		t.Errorf("crawled %d, failed %d, queued %d", f.Crawled, f.Failed, len(f.Queue))
+	}
+}
+
+func TestRunCrawlPauseAndResume(t *testing.T) {
+	site := fakeSite{"https://example.com/": nil}
+	for i := 0; i < 10; i++ {
+		page := fmt.Sprintf("https://example.com/%d", i)
+		site["https://example.com/"] = append(site["https://example.com/"], page)
+		site[page] = nil
+	}
+	store := newCrawlStore(t.TempDir())
+	cfg := testCrawlConfig("https://example.com/")
+	cfg.Concurrency = 1
+	f := newCrawlFrontier(cfg, time.Now())
+	if err := store.save(f); err != nil {
+		t.Fatal(err)
+	}
+
+	// Pause once three pages have been crawled; the rest are interrupted
+	ctx, cancel := context.WithCancel(context.Background())
+	pages := 0
+	fetch := func(ctx context.Context, item crawlItem) *crawlPageResult {
+		if ctx.Err() != nil {
+			return &crawlPageResult{URL: item.URL, interrupted: true}
+		}
+		return site.fetch(ctx, item)
+	}
+	runCrawl(ctx, f, fetch, func(r *crawlPageResult) {
+		if !r.interrupted {
+			if err := store.appendResult(f.Config.ID, r); err != nil {
+				t.Fatal(err)
+			}
+			if pages++; pages == 3 {
+				cancel()
+			}
+		}
+		if err := store.save(f); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if f.Crawled != 3 {
+		t.Fatalf("crawled %d before pausing, want 3", f.Crawled)
+	}
+
+	// Resume from disk as a restarted server would
+	resumed, err := store.load("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resumed.Queue) != 8 || len(resumed.Seen) != 11 {
+		t.Fatalf("resumed with %d queued and %d seen, want 8 and 11", len(resumed.Queue), len(resumed.Seen))
+	}
+	runCrawl(context.Background(), resumed, fetch, func(r *crawlPageResult) {
+		if err := store.appendResult(resumed.Config.ID, r); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if resumed.Crawled != 11 || len(resumed.Queue) != 0 {
+		t.Errorf("crawled %d, queued %d after resuming; want 11 and 0", resumed.Crawled, len(resumed.Queue))
+	}
+
+	results, err := store.recentResults("test", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, r := range results {
+		if seen[r.URL] {
+			t.Errorf("%s was crawled twice", r.URL)
+		}
+		seen[r.URL] = true
+	}
+	if len(results) != 11 {
+		t.Errorf("%d results recorded, want 11", len(results))
+	}
+	if ids, _ := store.list(); fmt.Sprint(ids) != "[test]" {
+		t.Errorf("list = %v", ids)
+	}
+}
@@ -0,0 +1,717 @@
+package webtools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// crawlUserAgent is the product token matched against robots.txt groups
+	crawlUserAgent = "rodmcp"
+
+	defaultCrawlPageTimeout = 30 * time.Second
+	defaultCrawlResults     = 20
+	// crawlPauseTimeout bounds how long pause waits for the frontier to be
+	// saved after the crawl is cancelled
+	crawlPauseTimeout = 10 * time.Second
+)
+
+// crawlLinksScript returns the page's title, final URL and absolute link
+// targets
+const crawlLinksScript = `return {
+	title: document.title,
+	url: location.href,
+	links: Array.from(document.querySelectorAll('a[href], area[href]'), a => a.href),
+};`
+
+// runningCrawl is a crawl running in the background
+type runningCrawl struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// CrawlSiteTool crawls sites in the background from a frontier kept on
+// disk, so a crawl can be paused, survive a server restart and be resumed
+type CrawlSiteTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	scraper    *ScreenScrapeTool
+	store      *crawlStore
+	sessions   *sessionStore
+	client     *http.Client
+
+	mu      sync.Mutex
+	running map[string]*runningCrawl
+	gate    JobGate
+}
+
+// NewCrawlSiteTool creates a crawl_site tool keeping frontiers and results
+// under crawlDir (default: crawls/ under the working directory) and reading
+// the sessions crawls sign in with from sessionDir
+func NewCrawlSiteTool(log *logger.Logger, mgr *browser.Manager, crawlDir, sessionDir string) *CrawlSiteTool {
+	return &CrawlSiteTool{
+		logger:     log,
+		browserMgr: mgr,
+		scraper:    NewScreenScrapeTool(log, mgr),
+		store:      newCrawlStore(crawlDir),
+		sessions:   newSessionStore(sessionDir),
+		client:     &http.Client{Timeout: robotsTimeout, Transport: meteredTransport(nil)},
+		running:    make(map[string]*runningCrawl),
+	}
+}
+
+// SetJobGate makes each crawled page wait for a worker, behind the
+// server's interactive calls
+func (t *CrawlSiteTool) SetJobGate(gate JobGate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gate = gate
+}
+
+// StopAll pauses every running crawl, saving their frontiers, for server
+// shutdown
+func (t *CrawlSiteTool) StopAll() {
+	t.mu.Lock()
+	crawls := make([]*runningCrawl, 0, len(t.running))
+	for _, c := range t.running {
+		c.cancel()
+		crawls = append(crawls, c)
+	}
+	t.mu.Unlock()
+	for _, c := range crawls {
+		select {
+		case <-c.done:
+		case <-time.After(crawlPauseTimeout):
+		}
+	}
+}
+
+func (t *CrawlSiteTool) Name() string {
+	return "crawl_site"
+}
+
+func (t *CrawlSiteTool) Description() string {
+	return "Crawl a site in the background, following links from start URLs within scope and optionally scraping each page. The frontier is saved to disk after every page, so a crawl can be paused and resumed later, even after a server restart. Politeness: honours robots.txt, limits concurrent pages per host and waits between requests to the same host"
+}
+
+func (t *CrawlSiteTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "start a new crawl or resume a paused one, pause a running crawl, show a crawl's status and recent pages, list stored crawls, or delete a crawl's data",
+				"enum":        []string{"start", "pause", "status", "list", "delete"},
+				"default":     "start",
+			},
+			"id": map[string]interface{}{
+				"type":        "string",
+				"description": "Crawl ID. Starting an ID that already has a saved frontier resumes it (required except for list)",
+			},
+			"start_urls": map[string]interface{}{
+				"type":        "array",
+				"description": "URLs to start from (new crawls)",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"include": map[string]interface{}{
+				"type":        "array",
+				"description": "URL patterns (* wildcards) links must match to be followed (default: the start URLs' hosts)",
+				"items":       map[string]interface{}{"type": "string"},
+				"examples":    []interface{}{[]string{"https://example.com/docs/*"}},
+			},
+			"exclude": map[string]interface{}{
+				"type":        "array",
+				"description": "URL patterns (* wildcards) never to follow",
+				"items":       map[string]interface{}{"type": "string"},
+				"examples":    []interface{}{[]string{"*/logout*", "*.pdf"}},
+			},
+			"max_pages": map[string]interface{}{
+				"type":        "integer",
+				"description": "Stop discovering URLs once this many have been found",
+				"default":     defaultCrawlMaxPages,
+				"minimum":     1,
+				"maximum":     maxCrawlMaxPages,
+			},
+			"max_depth": map[string]interface{}{
+				"type":        "integer",
+				"description": "Links followed from a start URL before stopping (0 crawls only the start URLs)",
+				"default":     defaultCrawlMaxDepth,
+				"minimum":     0,
+			},
+			"concurrency": map[string]interface{}{
+				"type":        "integer",
+				"description": "Pages crawled at the same time across all hosts",
+				"default":     defaultCrawlConcurrency,
+				"minimum":     1,
+				"maximum":     maxCrawlConcurrency,
+			},
+			"per_host": map[string]interface{}{
+				"type":        "integer",
+				"description": "Pages crawled at the same time on one host",
+				"default":     defaultCrawlPerHost,
+				"minimum":     1,
+				"maximum":     maxCrawlConcurrency,
+			},
+			"delay_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "Minimum milliseconds between starting requests to the same host; a longer robots.txt Crawl-delay wins",
+				"default":     int(defaultCrawlDelay / time.Millisecond),
+				"minimum":     0,
+			},
+			"respect_robots": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Skip URLs robots.txt disallows for the rodmcp user agent",
+				"default":     true,
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Selector schema in screen_scrape format to extract from every page (default: only title and links are recorded)",
+				"additionalProperties": scrapeSelectorValueSchema(),
+			},
+			"wait_for": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to wait for on each page before reading it",
+			},
+			"block_resources": map[string]interface{}{
+				"type":        "array",
+				"description": "Resource types each page should not load",
+				"items": map[string]interface{}{
+					"type": "string",
+					"enum": browser.BlockableResourceTypes,
+				},
+			},
+			"sessions": domainSessionsSchema(),
+			"restart": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Discard the saved frontier and results for this ID and start over (start)",
+				"default":     false,
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of recent page results to return for status (default: 20)",
+				"default":     defaultCrawlResults,
+			},
+		},
+	}
+}
+
+func (t *CrawlSiteTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "start"
+		}
+
+		var resp *types.CallToolResponse
+		switch action {
+		case "start":
+			resp = t.start(args)
+		case "pause":
+			resp = t.pause(args)
+		case "status":
+			resp = t.status(args)
+		case "list":
+			resp = t.list()
+		case "delete":
+			resp = t.delete(args)
+		default:
+			resp = recipeErrorResponse(fmt.Sprintf("Error: unknown action %q (use start, pause, status, list, or delete)", action))
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, !resp.IsError, time.Since(start).Milliseconds())
+		return resp, nil
+	})
+}
+
+// crawlID validates the id argument
+func crawlID(args map[string]interface{}) (string, error) {
+	id, _ := args["id"].(string)
+	if !recipeNamePattern.MatchString(id) {
+		return "", fmt.Errorf("id must be 1-64 letters, digits, '.', '_' or '-'")
+	}
+	return id, nil
+}
+
+// crawlStringList reads an optional array-of-strings argument
+func crawlStringList(args map[string]interface{}, key string) ([]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be an array of strings", key)
+	}
+	values := make([]string, 0, len(list))
+	for i, item := range list {
+		s, ok := item.(string)
+		if !ok || s == "" {
+			return nil, fmt.Errorf("%s[%d] must be a non-empty string", key, i)
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// parseCrawlConfig validates the arguments for a new crawl
+func parseCrawlConfig(id string, args map[string]interface{}) (crawlConfig, error) {
+	cfg := crawlConfig{
+		ID:            id,
+		MaxPages:      defaultCrawlMaxPages,
+		MaxDepth:      defaultCrawlMaxDepth,
+		Concurrency:   defaultCrawlConcurrency,
+		PerHost:       defaultCrawlPerHost,
+		DelayMs:       int(defaultCrawlDelay / time.Millisecond),
+		RespectRobots: true,
+	}
+	var err error
+	if cfg.StartURLs, err = crawlStringList(args, "start_urls"); err != nil {
+		return cfg, err
+	}
+	if len(cfg.StartURLs) == 0 {
+		return cfg, fmt.Errorf("start_urls is required to start a new crawl")
+	}
+	for _, u := range cfg.StartURLs {
+		if _, ok := normalizeCrawlURL(u); !ok {
+			return cfg, fmt.Errorf("start URL %q is not an http or https URL", u)
+		}
+	}
+	if cfg.Include, err = crawlStringList(args, "include"); err != nil {
+		return cfg, err
+	}
+	if cfg.Exclude, err = crawlStringList(args, "exclude"); err != nil {
+		return cfg, err
+	}
+
+	ints := []struct {
+		key      string
+		dst      *int
+		min, max int
+	}{
+		{"max_pages", &cfg.MaxPages, 1, maxCrawlMaxPages},
+		{"max_depth", &cfg.MaxDepth, 0, 1000},
+		{"concurrency", &cfg.Concurrency, 1, maxCrawlConcurrency},
+		{"per_host", &cfg.PerHost, 1, maxCrawlConcurrency},
+		{"delay_ms", &cfg.DelayMs, 0, int(maxCrawlDelay / time.Millisecond)},
+	}
+	for _, p := range ints {
+		if val, ok := args[p.key].(float64); ok {
+			*p.dst = int(val)
+		}
+		if *p.dst < p.min || *p.dst > p.max {
+			return cfg, fmt.Errorf("%s must be between %d and %d", p.key, p.min, p.max)
+		}
+	}
+	if val, ok := args["respect_robots"].(bool); ok {
+		cfg.RespectRobots = val
+	}
+
+	cfg.Selectors, _ = args["selectors"].(map[string]interface{})
+	if len(cfg.Selectors) > 0 {
+		if _, err := parseScrapeFields(cfg.Selectors); err != nil {
+			return cfg, err
+		}
+	}
+	cfg.WaitFor, _ = args["wait_for"].(string)
+	if cfg.BlockResources, err = parseBlockResources(args); err != nil {
+		return cfg, err
+	}
+	if cfg.Sessions, err = parseDomainSessions(args); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+func (t *CrawlSiteTool) start(args map[string]interface{}) *types.CallToolResponse {
+	id, err := crawlID(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, running := t.running[id]; running {
+		return recipeErrorResponse(fmt.Sprintf("Error: crawl %s is already running; pause it first", id))
+	}
+
+	restart, _ := args["restart"].(bool)
+	if restart {
+		if err := t.store.remove(id); err != nil {
+			return recipeErrorResponse(fmt.Sprintf("Error: failed to discard crawl %s: %v", id, err))
+		}
+	}
+
+	var f *crawlFrontier
+	resumed := t.store.exists(id)
+	if resumed {
+		if f, err = t.store.load(id); err != nil {
+			return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+		}
+		if len(f.Queue) == 0 {
+			return recipeErrorResponse(fmt.Sprintf("Error: crawl %s has already finished; pass restart=true to crawl again", id))
+		}
+	} else {
+		cfg, err := parseCrawlConfig(id, args)
+		if err != nil {
+			return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+		}
+		f = newCrawlFrontier(cfg, time.Now().UTC())
+	}
+	// Sessions are read now so a resumed crawl signs in with their
+	// current cookies
+	sessions, err := loadDomainSessions(t.sessions, f.Config.Sessions)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	if err := t.store.save(f); err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &runningCrawl{cancel: cancel, done: make(chan struct{})}
+	t.running[id] = c
+	go t.run(ctx, c, f, sessions)
+
+	text := fmt.Sprintf("Started crawl %s from %d URLs", id, len(f.Queue))
+	if resumed {
+		text = fmt.Sprintf("Resumed crawl %s with %d URLs queued (%d crawled so far)", id, len(f.Queue), f.Crawled)
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text,
+			Data: map[string]interface{}{
+				"id":        id,
+				"resumed":   resumed,
+				"queued":    len(f.Queue),
+				"config":    f.Config,
+				"directory": t.store.crawlDir(id),
+			},
+		}},
+	}
+}
+
+func (t *CrawlSiteTool) pause(args map[string]interface{}) *types.CallToolResponse {
+	id, err := crawlID(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	t.mu.Lock()
+	c, ok := t.running[id]
+	t.mu.Unlock()
+	if !ok {
+		return recipeErrorResponse(fmt.Sprintf("Error: no running crawl with id %q", id))
+	}
+	c.cancel()
+	select {
+	case <-c.done:
+	case <-time.After(crawlPauseTimeout):
+		return recipeErrorResponse(fmt.Sprintf("Error: crawl %s did not stop within %v", id, crawlPauseTimeout))
+	}
+	return t.status(args)
+}
+
+func (t *CrawlSiteTool) isRunning(id string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, ok := t.running[id]
+	return ok
+}
+
+// crawlState describes a stored crawl
+func (t *CrawlSiteTool) crawlState(f *crawlFrontier) string {
+	switch {
+	case t.isRunning(f.Config.ID):
+		return "running"
+	case len(f.Queue) == 0:
+		return "finished"
+	}
+	return "paused"
+}
+
+func crawlSummary(f *crawlFrontier, state string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         f.Config.ID,
+		"state":      state,
+		"start_urls": f.Config.StartURLs,
+		"queued":     len(f.Queue),
+		"discovered": len(f.Seen),
+		"crawled":    f.Crawled,
+		"failed":     f.Failed,
+		"blocked":    f.Blocked,
+		"started_at": f.StartedAt.Format(time.RFC3339),
+		"updated_at": f.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (t *CrawlSiteTool) status(args map[string]interface{}) *types.CallToolResponse {
+	id, err := crawlID(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	f, err := t.store.load(id)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	limit := defaultCrawlResults
+	if val, ok := args["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+	results, err := t.store.recentResults(id, limit)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error reading results: %v", err))
+	}
+
+	state := t.crawlState(f)
+	data := crawlSummary(f, state)
+	data["config"] = f.Config
+	data["recent"] = results
+	data["results_file"] = filepath.Join(t.store.crawlDir(id), "pages.jsonl")
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Crawl %s is %s: %d crawled, %d failed, %d blocked by robots.txt, %d queued", id, state, f.Crawled, f.Failed, f.Blocked, len(f.Queue)),
+			Data: data,
+		}},
+	}
+}
+
+func (t *CrawlSiteTool) list() *types.CallToolResponse {
+	ids, err := t.store.list()
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error listing crawls: %v", err))
+	}
+	crawls := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		f, err := t.store.load(id)
+		if err != nil {
+			crawls = append(crawls, map[string]interface{}{"id": id, "error": err.Error()})
+			continue
+		}
+		crawls = append(crawls, crawlSummary(f, t.crawlState(f)))
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%d crawls stored", len(crawls)),
+			Data: map[string]interface{}{"crawls": crawls},
+		}},
+	}
+}
+
+func (t *CrawlSiteTool) delete(args map[string]interface{}) *types.CallToolResponse {
+	id, err := crawlID(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	if t.isRunning(id) {
+		return recipeErrorResponse(fmt.Sprintf("Error: crawl %s is running; pause it first", id))
+	}
+	if !t.store.exists(id) {
+		return recipeErrorResponse(fmt.Sprintf("Error: no crawl named %q", id))
+	}
+	if err := t.store.remove(id); err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: failed to delete crawl %s: %v", id, err))
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Deleted crawl %s", id),
+			Data: map[string]interface{}{"id": id},
+		}},
+	}
+}
+
+// run crawls until the frontier is exhausted or the crawl is paused
+func (t *CrawlSiteTool) run(ctx context.Context, c *runningCrawl, f *crawlFrontier, sessions *domainSessions) {
+	id := f.Config.ID
+	log := t.logger.WithComponent("crawl")
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error("Crawl panicked", zap.String("id", id), zap.Any("panic", r))
+		}
+		t.mu.Lock()
+		delete(t.running, id)
+		t.mu.Unlock()
+		close(c.done)
+	}()
+
+	t.mu.Lock()
+	gate := t.gate
+	t.mu.Unlock()
+
+	robots := &robotsCache{client: t.client, rules: make(map[string]*robotsRules)}
+	fetch := func(ctx context.Context, item crawlItem) *crawlPageResult {
+		if gate != nil {
+			release, err := gate(ctx)
+			if err != nil {
+				result := &crawlPageResult{URL: item.URL, Depth: item.Depth, CrawledAt: time.Now().UTC()}
+				if ctx.Err() != nil {
+					result.interrupted = true
+				} else {
+					result.Error = err.Error()
+				}
+				return result
+			}
+			defer release()
+		}
+		return t.crawlPage(ctx, f.Config, robots, sessions, item)
+	}
+	persist := func(result *crawlPageResult) {
+		if !result.interrupted {
+			if err := t.store.appendResult(id, result); err != nil {
+				log.Warn("Failed to record crawl result", zap.String("id", id), zap.Error(err))
+			}
+		}
+		if err := t.store.save(f); err != nil {
+			log.Warn("Failed to save crawl frontier", zap.String("id", id), zap.Error(err))
+		}
+	}
+	runCrawl(ctx, f, fetch, persist)
+
+	log.Info("Crawl stopped",
+		zap.String("id", id),
+		zap.Bool("paused", ctx.Err() != nil),
+		zap.Int("crawled", f.Crawled),
+		zap.Int("queued", len(f.Queue)))
+}
+
+// robotsCache fetches each origin's robots.txt once per run
+type robotsCache struct {
+	client *http.Client
+	mu     sync.Mutex
+	rules  map[string]*robotsRules
+}
+
+func (c *robotsCache) get(ctx context.Context, u *url.URL) *robotsRules {
+	origin := robotsOrigin(u)
+	c.mu.Lock()
+	rules, ok := c.rules[origin]
+	c.mu.Unlock()
+	if ok {
+		return rules
+	}
+	rules = fetchRobots(ctx, c.client, origin, crawlUserAgent)
+	c.mu.Lock()
+	c.rules[origin] = rules
+	c.mu.Unlock()
+	return rules
+}
+
+// crawlPage checks robots.txt and then loads, reads and optionally scrapes
+// one URL. When ctx is cancelled first the result is marked interrupted
+// so the URL stays queued.
+func (t *CrawlSiteTool) crawlPage(ctx context.Context, cfg crawlConfig, robots *robotsCache, sessions *domainSessions, item crawlItem) *crawlPageResult {
+	start := time.Now()
+	result := &crawlPageResult{URL: item.URL, Depth: item.Depth}
+	finish := func() *crawlPageResult {
+		result.DurationMs = time.Since(start).Milliseconds()
+		result.CrawledAt = time.Now().UTC()
+		return result
+	}
+
+	if cfg.RespectRobots {
+		u, _ := url.Parse(item.URL)
+		rules := robots.get(ctx, u)
+		if ctx.Err() != nil {
+			result.interrupted = true
+			return finish()
+		}
+		result.crawlDelay = rules.crawlDelay
+		if !rules.allowed(u) {
+			result.Blocked = true
+			return finish()
+		}
+	}
+
+	pageCtx, cancel := context.WithTimeout(ctx, defaultCrawlPageTimeout)
+	defer cancel()
+	done := make(chan *crawlPageResult, 1)
+	go func() {
+		loaded := &crawlPageResult{}
+		defer func() {
+			if r := recover(); r != nil {
+				loaded.Error = fmt.Sprintf("crawl panicked: %v", r)
+			}
+			done <- loaded
+		}()
+		if err := t.loadPage(cfg, sessions, item.URL, loaded); err != nil {
+			loaded.Error = err.Error()
+		}
+	}()
+
+	select {
+	case loaded := <-done:
+		result.FinalURL = loaded.FinalURL
+		result.Title = loaded.Title
+		result.Data = loaded.Data
+		result.Error = loaded.Error
+		result.Session = loaded.Session
+		result.links = loaded.links
+		result.Links = len(loaded.links)
+	case <-pageCtx.Done():
+		if ctx.Err() != nil {
+			result.interrupted = true
+		} else {
+			result.Error = fmt.Sprintf("timed out after %v", defaultCrawlPageTimeout)
+		}
+	}
+	return finish()
+}
+
+// loadPage opens url, signed in with its domain's session if any, and
+// fills in its title, links and scraped data
+func (t *CrawlSiteTool) loadPage(cfg crawlConfig, sessions *domainSessions, url string, result *crawlPageResult) error {
+	pageID, session, release, err := sessions.openPage(t.browserMgr, url, browser.PageOptions{BlockResources: cfg.BlockResources})
+	if err != nil {
+		return err
+	}
+	result.Session = session
+	defer func() {
+		if err := release(); err != nil {
+			t.logger.WithComponent("crawl").Debug("Failed to release crawl page",
+				zap.String("page_id", pageID),
+				zap.Error(err))
+		}
+	}()
+
+	if cfg.WaitFor != "" {
+		if _, err := t.browserMgr.ExecuteScript(pageID, waitForSelectorScript(cfg.WaitFor)); err != nil {
+			return fmt.Errorf("wait_for %s: %w", cfg.WaitFor, err)
+		}
+	}
+	raw, err := t.browserMgr.ExecuteScript(pageID, crawlLinksScript)
+	if err != nil {
+		return fmt.Errorf("failed to read page: %w", err)
+	}
+	var page struct {
+		Title string   `json:"title"`
+		URL   string   `json:"url"`
+		Links []string `json:"links"`
+	}
+	if err := decodeScriptValue(raw, &page); err != nil {
+		return fmt.Errorf("failed to read page: %w", err)
+	}
+	result.Title = page.Title
+	result.FinalURL = page.URL
+	result.links = uniqueStrings(page.Links)
+
+	if len(cfg.Selectors) > 0 {
+		if result.Data, err = t.scraper.scrapeSingle(pageID, nil, cfg.Selectors); err != nil {
+			return fmt.Errorf("scrape failed: %w", err)
+		}
+	}
+	return nil
+}
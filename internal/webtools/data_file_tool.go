@@ -0,0 +1,699 @@
+package webtools
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDataLimit  = 100
+	maxDataLimit      = 10000
+	maxDistinctValues = 1000
+)
+
+// DataTable is a parsed tabular data file
+type DataTable struct {
+	Columns []string
+	Rows    []map[string]interface{}
+}
+
+// DataCondition is a single "column op value" clause of a where expression
+type DataCondition struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ColumnStats summarizes the values of one column
+type ColumnStats struct {
+	Count    int      `json:"count"`
+	Empty    int      `json:"empty"`
+	Numeric  int      `json:"numeric"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Sum      *float64 `json:"sum,omitempty"`
+	Mean     *float64 `json:"mean,omitempty"`
+	Distinct int      `json:"distinct"`
+}
+
+// ReadDataFileTool parses CSV/TSV/JSON/NDJSON files with column selection and filtering
+type ReadDataFileTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewReadDataFileTool(log *logger.Logger, validator *PathValidator) *ReadDataFileTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &ReadDataFileTool{
+		logger:    log,
+		validator: validator,
+	}
+}
+
+func (t *ReadDataFileTool) Name() string {
+	return "read_data_file"
+}
+
+func (t *ReadDataFileTool) Description() string {
+	return "Parse a CSV, TSV, JSON, or NDJSON data file and return selected columns, filtered rows, a limit/offset window, and optional per-column summary stats"
+}
+
+func (t *ReadDataFileTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the data file",
+				"examples":    []string{"products.csv", "data/results.ndjson"},
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "File format (detected from the extension if omitted)",
+				"enum":        []string{"csv", "tsv", "json", "ndjson"},
+			},
+			"columns": map[string]interface{}{
+				"type":        "array",
+				"description": "Columns to return (all columns if omitted)",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"where": map[string]interface{}{
+				"type":        "string",
+				"description": "Filter expression: clauses of the form 'column op value' joined with '&&'. Operators: ==, !=, >, >=, <, <=, contains, startswith, endswith. Numbers compare numerically; quote string values with ' or \"",
+				"examples":    []string{"price > 10", "category == 'books' && stock >= 1", "title contains 'guide'"},
+			},
+			"limit": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum number of rows to return",
+				"default":     defaultDataLimit,
+				"minimum":     0,
+				"maximum":     maxDataLimit,
+			},
+			"offset": map[string]interface{}{
+				"type":        "number",
+				"description": "Number of matching rows to skip",
+				"default":     0,
+				"minimum":     0,
+			},
+			"stats": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Include summary stats (count, empty, numeric min/max/sum/mean, distinct) for the selected columns over all matching rows",
+				"default":     false,
+			},
+			"has_header": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Whether the first CSV/TSV row is a header (columns are named col_1, col_2, ... otherwise)",
+				"default":     true,
+			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths)",
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+func (t *ReadDataFileTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		type result struct {
+			response *types.CallToolResponse
+			err      error
+		}
+		resultChan := make(chan result, 1)
+
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					resultChan <- result{err: fmt.Errorf("data file processing panicked: %v", r)}
+				}
+			}()
+			resp, err := t.process(args)
+			resultChan <- result{resp, err}
+		}()
+
+		var res result
+		select {
+		case res = <-resultChan:
+		case <-ctx.Done():
+			res = result{err: fmt.Errorf("reading data file timed out after 30 seconds")}
+		}
+
+		duration := time.Since(start).Milliseconds()
+		if res.err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, duration)
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error reading data file: %v", res.err),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, duration)
+		return res.response, nil
+	})
+}
+
+func (t *ReadDataFileTool) process(args map[string]interface{}) (*types.CallToolResponse, error) {
+	pathStr, ok := args["path"].(string)
+	if !ok || pathStr == "" {
+		return nil, fmt.Errorf("path must be a non-empty string")
+	}
+
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+	if err := t.validator.ValidatePath(cleanPath, "read"); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+
+	info, err := os.Stat(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access file %s: %w", cleanPath, err)
+	}
+	if err := t.validator.ValidateFileSize(info.Size()); err != nil {
+		return nil, err
+	}
+
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = detectDataFormat(cleanPath)
+	}
+
+	hasHeader := true
+	if val, ok := args["has_header"].(bool); ok {
+		hasHeader = val
+	}
+
+	f, err := os.Open(cleanPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cleanPath, err)
+	}
+	defer f.Close()
+
+	table, err := ParseDataFile(f, format, hasHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []DataCondition
+	if where, ok := args["where"].(string); ok && strings.TrimSpace(where) != "" {
+		conditions, err = ParseWhereExpression(where)
+		if err != nil {
+			return nil, err
+		}
+		for _, cond := range conditions {
+			if !containsString(table.Columns, cond.Column) {
+				return nil, fmt.Errorf("where references unknown column %q", cond.Column)
+			}
+		}
+	}
+
+	columns := table.Columns
+	if raw, ok := args["columns"].([]interface{}); ok && len(raw) > 0 {
+		columns = nil
+		for _, c := range raw {
+			name, _ := c.(string)
+			if !containsString(table.Columns, name) {
+				return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(table.Columns, ", "))
+			}
+			columns = append(columns, name)
+		}
+	}
+
+	limit := defaultDataLimit
+	if val, ok := args["limit"].(float64); ok {
+		limit = int(val)
+	}
+	if limit < 0 {
+		limit = 0
+	}
+	if limit > maxDataLimit {
+		limit = maxDataLimit
+	}
+	offset := 0
+	if val, ok := args["offset"].(float64); ok && val > 0 {
+		offset = int(val)
+	}
+	includeStats, _ := args["stats"].(bool)
+
+	var matched []map[string]interface{}
+	for _, row := range table.Rows {
+		if MatchesConditions(row, conditions) {
+			matched = append(matched, row)
+		}
+	}
+
+	end := offset + limit
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	if end > len(matched) {
+		end = len(matched)
+	}
+
+	rows := make([]map[string]interface{}, 0, end-offset)
+	for _, row := range matched[offset:end] {
+		projected := make(map[string]interface{}, len(columns))
+		for _, c := range columns {
+			projected[c] = row[c]
+		}
+		rows = append(rows, projected)
+	}
+
+	data := map[string]interface{}{
+		"path":          cleanPath,
+		"format":        format,
+		"columns":       columns,
+		"rows":          rows,
+		"total_rows":    len(table.Rows),
+		"matched_rows":  len(matched),
+		"returned_rows": len(rows),
+		"offset":        offset,
+		"has_more":      end < len(matched),
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%s (%s): %d rows, %d matched, returning %d from offset %d\n",
+		cleanPath, format, len(table.Rows), len(matched), len(rows), offset)
+	fmt.Fprintf(&text, "Columns: %s\n", strings.Join(columns, ", "))
+
+	if includeStats {
+		stats := ComputeColumnStats(matched, columns)
+		data["stats"] = stats
+		text.WriteString("Stats:\n")
+		for _, c := range columns {
+			s := stats[c]
+			fmt.Fprintf(&text, "  %s: count=%d empty=%d distinct=%d", c, s.Count, s.Empty, s.Distinct)
+			if s.Mean != nil {
+				fmt.Fprintf(&text, " min=%g max=%g mean=%g", *s.Min, *s.Max, *s.Mean)
+			}
+			text.WriteString("\n")
+		}
+	}
+
+	t.logger.WithComponent("tools").Info("Data file read",
+		zap.String("path", cleanPath),
+		zap.String("format", format),
+		zap.Int("matched_rows", len(matched)),
+		zap.Int("returned_rows", len(rows)))
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text.String(),
+			Data: data,
+		}},
+	}, nil
+}
+
+// detectDataFormat guesses the file format from its extension
+func detectDataFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tsv", ".tab":
+		return "tsv"
+	case ".json":
+		return "json"
+	case ".ndjson", ".jsonl":
+		return "ndjson"
+	default:
+		return "csv"
+	}
+}
+
+// ParseDataFile reads a data file in the given format into a DataTable
+func ParseDataFile(r io.Reader, format string, hasHeader bool) (*DataTable, error) {
+	switch format {
+	case "csv", "tsv":
+		reader := csv.NewReader(r)
+		if format == "tsv" {
+			reader.Comma = '\t'
+			reader.LazyQuotes = true
+		}
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", format, err)
+		}
+		return tableFromRecords(records, hasHeader), nil
+	case "json":
+		var items []map[string]interface{}
+		if err := json.NewDecoder(r).Decode(&items); err != nil {
+			return nil, fmt.Errorf("failed to parse json (expected an array of objects): %w", err)
+		}
+		return tableFromObjects(items), nil
+	case "ndjson":
+		var items []map[string]interface{}
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := strings.TrimSpace(scanner.Text())
+			if text == "" {
+				continue
+			}
+			var item map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &item); err != nil {
+				return nil, fmt.Errorf("failed to parse ndjson line %d: %w", line, err)
+			}
+			items = append(items, item)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read ndjson: %w", err)
+		}
+		return tableFromObjects(items), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (use csv, tsv, json, or ndjson)", format)
+	}
+}
+
+func tableFromRecords(records [][]string, hasHeader bool) *DataTable {
+	table := &DataTable{}
+	if len(records) == 0 {
+		return table
+	}
+
+	width := 0
+	for _, rec := range records {
+		if len(rec) > width {
+			width = len(rec)
+		}
+	}
+
+	body := records
+	if hasHeader {
+		body = records[1:]
+		seen := make(map[string]int)
+		for i := 0; i < width; i++ {
+			name := ""
+			if i < len(records[0]) {
+				name = strings.TrimSpace(records[0][i])
+			}
+			if name == "" {
+				name = fmt.Sprintf("col_%d", i+1)
+			}
+			if n := seen[name]; n > 0 {
+				seen[name] = n + 1
+				name = fmt.Sprintf("%s_%d", name, n+1)
+			} else {
+				seen[name] = 1
+			}
+			table.Columns = append(table.Columns, name)
+		}
+	} else {
+		for i := 0; i < width; i++ {
+			table.Columns = append(table.Columns, fmt.Sprintf("col_%d", i+1))
+		}
+	}
+
+	for _, rec := range body {
+		row := make(map[string]interface{}, width)
+		for i, c := range table.Columns {
+			if i < len(rec) {
+				row[c] = rec[i]
+			} else {
+				row[c] = ""
+			}
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func tableFromObjects(items []map[string]interface{}) *DataTable {
+	table := &DataTable{Rows: items}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		// Keys within an object have no stable order, so sort each object's new keys
+		var keys []string
+		for k := range item {
+			if !seen[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			seen[k] = true
+			table.Columns = append(table.Columns, k)
+		}
+	}
+	return table
+}
+
+// ParseWhereExpression parses "col op value && col op value" into conditions
+func ParseWhereExpression(expr string) ([]DataCondition, error) {
+	var conditions []DataCondition
+	for _, clause := range splitWhereClauses(expr) {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			return nil, fmt.Errorf("empty clause in where expression %q", expr)
+		}
+		cond, err := parseWhereClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cond)
+	}
+	return conditions, nil
+}
+
+// splitWhereClauses splits on && (or the word "and") outside of quotes
+func splitWhereClauses(expr string) []string {
+	var clauses []string
+	var current strings.Builder
+	var quote rune
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			if r == quote {
+				quote = 0
+			}
+			current.WriteRune(r)
+			continue
+		}
+		if r == '\'' || r == '"' {
+			quote = r
+			current.WriteRune(r)
+			continue
+		}
+		if r == '&' && i+1 < len(runes) && runes[i+1] == '&' {
+			clauses = append(clauses, current.String())
+			current.Reset()
+			i++
+			continue
+		}
+		if (r == ' ') && i+4 < len(runes) && strings.EqualFold(string(runes[i+1:i+4]), "and") && runes[i+4] == ' ' {
+			clauses = append(clauses, current.String())
+			current.Reset()
+			i += 4
+			continue
+		}
+		current.WriteRune(r)
+	}
+	clauses = append(clauses, current.String())
+	return clauses
+}
+
+var whereOperators = []string{">=", "<=", "!=", "==", ">", "<", "=", " contains ", " startswith ", " endswith "}
+
+func parseWhereClause(clause string) (DataCondition, error) {
+	// Pick the earliest operator in the clause, preferring the longer one on ties,
+	// so operator characters inside the value cannot split the clause
+	lower := strings.ToLower(clause)
+	bestIdx, bestOp := -1, ""
+	for _, op := range whereOperators {
+		idx := strings.Index(lower, op)
+		if idx <= 0 {
+			continue
+		}
+		if bestIdx == -1 || idx < bestIdx || (idx == bestIdx && len(op) > len(bestOp)) {
+			bestIdx, bestOp = idx, op
+		}
+	}
+	if bestIdx == -1 {
+		return DataCondition{}, fmt.Errorf("invalid where clause %q (expected 'column op value')", clause)
+	}
+
+	column := unquoteWhereValue(strings.TrimSpace(clause[:bestIdx]))
+	value := unquoteWhereValue(strings.TrimSpace(clause[bestIdx+len(bestOp):]))
+	operator := strings.TrimSpace(bestOp)
+	if operator == "=" {
+		operator = "=="
+	}
+	if column == "" {
+		return DataCondition{}, fmt.Errorf("invalid where clause %q (missing column)", clause)
+	}
+	return DataCondition{Column: column, Operator: operator, Value: value}, nil
+}
+
+func unquoteWhereValue(s string) string {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// MatchesConditions reports whether a row satisfies every condition
+func MatchesConditions(row map[string]interface{}, conditions []DataCondition) bool {
+	for _, cond := range conditions {
+		if !matchCondition(row[cond.Column], cond) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchCondition(raw interface{}, cond DataCondition) bool {
+	value := dataValueString(raw)
+
+	switch cond.Operator {
+	case "contains":
+		return strings.Contains(strings.ToLower(value), strings.ToLower(cond.Value))
+	case "startswith":
+		return strings.HasPrefix(strings.ToLower(value), strings.ToLower(cond.Value))
+	case "endswith":
+		return strings.HasSuffix(strings.ToLower(value), strings.ToLower(cond.Value))
+	}
+
+	left, leftNum := dataValueNumber(raw)
+	right, rightErr := strconv.ParseFloat(cond.Value, 64)
+	if leftNum && rightErr == nil {
+		switch cond.Operator {
+		case "==":
+			return left == right
+		case "!=":
+			return left != right
+		case ">":
+			return left > right
+		case ">=":
+			return left >= right
+		case "<":
+			return left < right
+		case "<=":
+			return left <= right
+		}
+		return false
+	}
+
+	cmp := strings.Compare(value, cond.Value)
+	switch cond.Operator {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	}
+	return false
+}
+
+func dataValueString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
+
+func dataValueNumber(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+			return 0, false
+		}
+		return f, true
+	}
+	return 0, false
+}
+
+// ComputeColumnStats summarizes each column over the given rows
+func ComputeColumnStats(rows []map[string]interface{}, columns []string) map[string]ColumnStats {
+	stats := make(map[string]ColumnStats, len(columns))
+	for _, c := range columns {
+		var s ColumnStats
+		distinct := make(map[string]bool)
+		var min, max, sum float64
+		for _, row := range rows {
+			s.Count++
+			str := dataValueString(row[c])
+			if strings.TrimSpace(str) == "" {
+				s.Empty++
+				continue
+			}
+			if len(distinct) < maxDistinctValues {
+				distinct[str] = true
+			}
+			if f, ok := dataValueNumber(row[c]); ok {
+				if s.Numeric == 0 || f < min {
+					min = f
+				}
+				if s.Numeric == 0 || f > max {
+					max = f
+				}
+				sum += f
+				s.Numeric++
+			}
+		}
+		s.Distinct = len(distinct)
+		if s.Numeric > 0 {
+			mean := sum / float64(s.Numeric)
+			s.Min, s.Max, s.Sum, s.Mean = &min, &max, &sum, &mean
+		}
+		stats[c] = s
+	}
+	return stats
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
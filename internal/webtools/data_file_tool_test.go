@@ -0,0 +1,133 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseWhereExpression(t *testing.T) {
+	conditions, err := ParseWhereExpression(`price >= 10 && title contains 'a > b' and category = "books"`)
+	if err != nil {
+		t.Fatalf("ParseWhereExpression failed: %v", err)
+	}
+	expected := []DataCondition{
+		{Column: "price", Operator: ">=", Value: "10"},
+		{Column: "title", Operator: "contains", Value: "a > b"},
+		{Column: "category", Operator: "==", Value: "books"},
+	}
+	if len(conditions) != len(expected) {
+		t.Fatalf("Expected %d conditions, got %d: %+v", len(expected), len(conditions), conditions)
+	}
+	for i, c := range expected {
+		if conditions[i] != c {
+			t.Errorf("Condition %d: expected %+v, got %+v", i, c, conditions[i])
+		}
+	}
+
+	if _, err := ParseWhereExpression("price"); err == nil {
+		t.Error("Expected error for clause without operator")
+	}
+	if _, err := ParseWhereExpression("price > 1 &&"); err == nil {
+		t.Error("Expected error for empty trailing clause")
+	}
+}
+
+func TestParseDataFileFormats(t *testing.T) {
+	csvTable, err := ParseDataFile(strings.NewReader("name,price\nmug,4.5\ncup,12\n"), "csv", true)
+	if err != nil {
+		t.Fatalf("csv parse failed: %v", err)
+	}
+	if len(csvTable.Rows) != 2 || csvTable.Columns[1] != "price" {
+		t.Errorf("Unexpected csv table: %+v", csvTable)
+	}
+
+	tsvTable, err := ParseDataFile(strings.NewReader("a\tb\n1\t2\n"), "tsv", false)
+	if err != nil {
+		t.Fatalf("tsv parse failed: %v", err)
+	}
+	if len(tsvTable.Rows) != 2 || tsvTable.Columns[0] != "col_1" {
+		t.Errorf("Unexpected headerless tsv table: %+v", tsvTable)
+	}
+
+	jsonTable, err := ParseDataFile(strings.NewReader(`[{"b":1,"a":2},{"c":3}]`), "json", true)
+	if err != nil {
+		t.Fatalf("json parse failed: %v", err)
+	}
+	if strings.Join(jsonTable.Columns, ",") != "a,b,c" {
+		t.Errorf("Expected columns in first-seen sorted order, got %v", jsonTable.Columns)
+	}
+
+	ndTable, err := ParseDataFile(strings.NewReader("{\"x\":1}\n\n{\"x\":2}\n"), "ndjson", true)
+	if err != nil {
+		t.Fatalf("ndjson parse failed: %v", err)
+	}
+	if len(ndTable.Rows) != 2 {
+		t.Errorf("Expected 2 ndjson rows, got %d", len(ndTable.Rows))
+	}
+
+	if _, err := ParseDataFile(strings.NewReader(""), "xml", true); err == nil {
+		t.Error("Expected error for unsupported format")
+	}
+}
+
+func TestReadDataFileTool(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "products.csv"), []byte(
+		"name,category,price\n"+
+			"Mug,kitchen,4.50\n"+
+			"Guide to Go,books,30\n"+
+			"Cookbook,books,12\n"+
+			"Plate,kitchen,\n"), 0644)
+
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{dir},
+		RestrictToWorkingDir: false,
+		MaxFileSize:          1024 * 1024,
+	})
+	tool := NewReadDataFileTool(createTestLogger(t), validator)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"path":    "products.csv",
+		"cwd":     dir,
+		"where":   "category == books && price > 10",
+		"columns": []interface{}{"name", "price"},
+		"limit":   float64(1),
+		"offset":  float64(1),
+		"stats":   true,
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("read_data_file failed: %v %v", err, resp)
+	}
+
+	data := resp.Content[0].Data.(map[string]interface{})
+	if data["matched_rows"] != 2 || data["returned_rows"] != 1 {
+		t.Errorf("Unexpected row counts: %v", data)
+	}
+	rows := data["rows"].([]map[string]interface{})
+	if rows[0]["name"] != "Cookbook" || rows[0]["category"] != nil {
+		t.Errorf("Expected projected second match, got %v", rows[0])
+	}
+	stats := data["stats"].(map[string]ColumnStats)
+	if stats["price"].Mean == nil || *stats["price"].Mean != 21 {
+		t.Errorf("Expected mean price 21 over matches, got %+v", stats["price"])
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{
+		"path":  filepath.Join(dir, "products.csv"),
+		"stats": true,
+	})
+	stats = resp.Content[0].Data.(map[string]interface{})["stats"].(map[string]ColumnStats)
+	if stats["price"].Empty != 1 || stats["category"].Distinct != 2 {
+		t.Errorf("Unexpected stats over all rows: %+v", stats)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{
+		"path":  filepath.Join(dir, "products.csv"),
+		"where": "color == red",
+	})
+	if !resp.IsError {
+		t.Error("Expected unknown where column to be rejected")
+	}
+}
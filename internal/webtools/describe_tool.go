@@ -0,0 +1,353 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DescribableTool is the metadata subset of a tool needed to document it
+type DescribableTool interface {
+	Name() string
+	Description() string
+	InputSchema() types.ToolSchema
+}
+
+// ParameterDescription documents a single tool parameter
+type ParameterDescription struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required"`
+	Default     interface{}   `json:"default,omitempty"`
+	Minimum     interface{}   `json:"minimum,omitempty"`
+	Maximum     interface{}   `json:"maximum,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Examples    []interface{} `json:"examples,omitempty"`
+	Deprecated  bool          `json:"deprecated,omitempty"`
+}
+
+// ToolDescription is the machine-readable description of a tool
+type ToolDescription struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Required    []string                 `json:"required"`
+	Parameters  []ParameterDescription   `json:"parameters"`
+	Examples    []map[string]interface{} `json:"examples"`
+	InputSchema types.ToolSchema         `json:"inputSchema"`
+	Meta        types.ToolMeta           `json:"meta"`
+}
+
+// DescribeTool builds a full description of a tool, including example arguments
+// generated from the schema's examples, defaults and enums
+func DescribeTool(tool DescribableTool) ToolDescription {
+	schema := tool.InputSchema()
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	var params []ParameterDescription
+	for name, raw := range schema.Properties {
+		def, _ := raw.(map[string]interface{})
+		param := ParameterDescription{Name: name, Type: "unknown", Required: required[name]}
+		if t, ok := def["type"].(string); ok {
+			param.Type = t
+		}
+		param.Description, _ = def["description"].(string)
+		param.Default = def["default"]
+		param.Minimum = def["minimum"]
+		param.Maximum = def["maximum"]
+		param.Enum = toInterfaceSlice(def["enum"])
+		param.Examples = toInterfaceSlice(def["examples"])
+		param.Deprecated, _ = def["deprecated"].(bool)
+		params = append(params, param)
+	}
+
+	// Required parameters first, then alphabetical
+	sort.Slice(params, func(i, j int) bool {
+		if params[i].Required != params[j].Required {
+			return params[i].Required
+		}
+		return params[i].Name < params[j].Name
+	})
+
+	requiredList := schema.Required
+	if requiredList == nil {
+		requiredList = []string{}
+	}
+
+	var meta types.ToolMeta
+	if versioned, ok := tool.(interface{ Meta() types.ToolMeta }); ok {
+		meta = versioned.Meta()
+	}
+	if meta.Version == "" {
+		meta.Version = types.DefaultToolVersion
+	}
+
+	return ToolDescription{
+		Name:        tool.Name(),
+		Description: tool.Description(),
+		Required:    requiredList,
+		Parameters:  params,
+		Examples:    BuildToolExamples(params),
+		InputSchema: schema,
+		Meta:        meta,
+	}
+}
+
+// BuildToolExamples returns a minimal example using only required parameters
+// and, when it differs, a fuller example using every parameter with a known value
+func BuildToolExamples(params []ParameterDescription) []map[string]interface{} {
+	minimal := make(map[string]interface{})
+	full := make(map[string]interface{})
+
+	for _, p := range params {
+		if p.Required {
+			minimal[p.Name] = exampleValue(p, 0)
+			full[p.Name] = exampleValue(p, 1)
+			continue
+		}
+		// Optional parameters only appear when the schema provides a concrete value
+		if len(p.Examples) > 0 || p.Default != nil || len(p.Enum) > 0 {
+			full[p.Name] = exampleValue(p, 1)
+		}
+	}
+
+	examples := []map[string]interface{}{minimal}
+	if !reflect.DeepEqual(minimal, full) {
+		examples = append(examples, full)
+	}
+	return examples
+}
+
+// exampleValue picks the index-th schema example, falling back to the default,
+// first enum value, or a type-appropriate placeholder
+func exampleValue(p ParameterDescription, index int) interface{} {
+	if len(p.Examples) > 0 {
+		if index < len(p.Examples) {
+			return p.Examples[index]
+		}
+		return p.Examples[0]
+	}
+	if p.Default != nil {
+		return p.Default
+	}
+	if len(p.Enum) > 0 {
+		return p.Enum[0]
+	}
+	switch p.Type {
+	case "number", "integer":
+		if p.Minimum != nil {
+			return p.Minimum
+		}
+		return 1
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	default:
+		return "<" + p.Name + ">"
+	}
+}
+
+// toInterfaceSlice converts typed schema slices ([]string, []int, ...) to []interface{}
+func toInterfaceSlice(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil
+	}
+	out := make([]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// FormatToolDescription renders a tool description as human-readable text
+func FormatToolDescription(desc ToolDescription) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "🛠️  Tool: %s\n", desc.Name)
+	b.WriteString("=" + strings.Repeat("=", len(desc.Name)+10) + "\n")
+	fmt.Fprintf(&b, "📖 Description: %s\n", desc.Description)
+	fmt.Fprintf(&b, "🏷️  Version: %s\n", desc.Meta.Version)
+	if desc.Meta.Deprecated {
+		b.WriteString("⚠️  Deprecated")
+		if desc.Meta.DeprecationMessage != "" {
+			b.WriteString(": " + desc.Meta.DeprecationMessage)
+		}
+		if desc.Meta.ReplacedBy != "" {
+			fmt.Fprintf(&b, " (use %s instead)", desc.Meta.ReplacedBy)
+		}
+		b.WriteString("\n")
+	}
+	if len(desc.Meta.RenamedParameters) > 0 {
+		oldNames := make([]string, 0, len(desc.Meta.RenamedParameters))
+		for oldName := range desc.Meta.RenamedParameters {
+			oldNames = append(oldNames, oldName)
+		}
+		sort.Strings(oldNames)
+		for _, oldName := range oldNames {
+			fmt.Fprintf(&b, "🔁 Renamed: %s → %s\n", oldName, desc.Meta.RenamedParameters[oldName])
+		}
+	}
+	b.WriteString("\n")
+
+	b.WriteString("📋 Parameters:\n")
+	if len(desc.Required) > 0 {
+		fmt.Fprintf(&b, "  Required: %s\n", strings.Join(desc.Required, ", "))
+	} else {
+		b.WriteString("  Required: (none)\n")
+	}
+
+	if len(desc.Parameters) > 0 {
+		b.WriteString("\n")
+	}
+	for _, p := range desc.Parameters {
+		required := ""
+		if p.Required {
+			required = " (required)"
+		}
+		if p.Deprecated {
+			required += " (deprecated)"
+		}
+		fmt.Fprintf(&b, "  %-15s [%s]%s\n", p.Name, p.Type, required)
+		if p.Description != "" {
+			fmt.Fprintf(&b, "                  %s\n", p.Description)
+		}
+		if p.Default != nil {
+			fmt.Fprintf(&b, "                  Default: %v\n", p.Default)
+		}
+		if len(p.Enum) > 0 {
+			fmt.Fprintf(&b, "                  Values: %v\n", p.Enum)
+		}
+		if p.Minimum != nil {
+			fmt.Fprintf(&b, "                  Minimum: %v\n", p.Minimum)
+		}
+		if p.Maximum != nil {
+			fmt.Fprintf(&b, "                  Maximum: %v\n", p.Maximum)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("💡 Example Usage:\n")
+	for _, example := range desc.Examples {
+		encoded, err := json.Marshal(example)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "  %s\n", encoded)
+	}
+
+	return b.String()
+}
+
+// DescribeToolTool exposes tool descriptions and examples over MCP
+type DescribeToolTool struct {
+	logger *logger.Logger
+	tools  func() []DescribableTool
+}
+
+// NewDescribeToolTool creates the describe_tool tool. The tools function is
+// called on each request so tools registered later are still described.
+func NewDescribeToolTool(log *logger.Logger, tools func() []DescribableTool) *DescribeToolTool {
+	return &DescribeToolTool{logger: log, tools: tools}
+}
+
+func (t *DescribeToolTool) Name() string {
+	return "describe_tool"
+}
+
+func (t *DescribeToolTool) Description() string {
+	return "Describe a tool's parameters and show complete JSON example arguments built from its schema"
+}
+
+func (t *DescribeToolTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"tool": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the tool to describe",
+				"examples":    []string{"click_element", "navigate_page"},
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "Output format: 'text' for a readable summary, 'json' for the machine-readable description",
+				"enum":        []string{"text", "json"},
+				"default":     "text",
+			},
+		},
+		Required: []string{"tool"},
+	}
+}
+
+func (t *DescribeToolTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		name, _ := args["tool"].(string)
+		format := "text"
+		if val, ok := args["format"].(string); ok && val != "" {
+			format = val
+		}
+
+		var names []string
+		var found DescribableTool
+		for _, tool := range t.tools() {
+			names = append(names, tool.Name())
+			if tool.Name() == name {
+				found = tool
+			}
+		}
+
+		if found == nil {
+			sort.Strings(names)
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Tool '%s' not found. Available tools: %s", name, strings.Join(names, ", ")),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		desc := DescribeTool(found)
+		text := FormatToolDescription(desc)
+		if format == "json" {
+			encoded, err := json.MarshalIndent(desc, "", "  ")
+			if err != nil {
+				t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Failed to encode description: %v", err),
+					}},
+					IsError: true,
+				}, nil
+			}
+			text = string(encoded)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: desc,
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,78 @@
+package webtools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDescribeToolExamples(t *testing.T) {
+	log := createTestLogger(t)
+
+	desc := DescribeTool(NewClickElementTool(log, nil))
+	if len(desc.Examples) != 2 {
+		t.Fatalf("Expected minimal and full examples, got %v", desc.Examples)
+	}
+	if desc.Examples[0]["selector"] != "#submit-button" || len(desc.Examples[0]) != 1 {
+		t.Errorf("Expected minimal example from schema examples, got %v", desc.Examples[0])
+	}
+	if desc.Examples[1]["timeout"] == nil {
+		t.Errorf("Expected full example to include optional timeout, got %v", desc.Examples[1])
+	}
+	if desc.Parameters[0].Name != "selector" || !desc.Parameters[0].Required {
+		t.Errorf("Expected required parameters first, got %+v", desc.Parameters[0])
+	}
+
+	// Required parameters without examples still get a placeholder value
+	desc = DescribeTool(NewGitCommitTool(log, nil))
+	if desc.Examples[0]["message"] == nil {
+		t.Errorf("Expected required message in example, got %v", desc.Examples[0])
+	}
+}
+
+func TestDescribeToolTool(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewDescribeToolTool(log, func() []DescribableTool {
+		return []DescribableTool{NewCheckPortTool(log), NewWaitTool(log)}
+	})
+
+	resp, err := tool.Execute(map[string]interface{}{"tool": "wait"})
+	if err != nil || resp.IsError {
+		t.Fatalf("describe_tool failed: %v %v", err, resp)
+	}
+	if !strings.Contains(resp.Content[0].Text, `{"seconds":3}`) {
+		t.Errorf("Expected example in text output, got %s", resp.Content[0].Text)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"tool": "check_port", "format": "json"})
+	var decoded ToolDescription
+	if err := json.Unmarshal([]byte(resp.Content[0].Text), &decoded); err != nil {
+		t.Fatalf("Expected JSON output: %v", err)
+	}
+	if decoded.Name != "check_port" || len(decoded.Examples) == 0 {
+		t.Errorf("Unexpected JSON description: %+v", decoded)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"tool": "missing"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "check_port") {
+		t.Errorf("Expected not-found error listing tools, got %v", resp.Content[0].Text)
+	}
+}
+
+func TestDescribeToolMeta(t *testing.T) {
+	log := createTestLogger(t)
+
+	desc := DescribeTool(NewWaitTool(log))
+	if desc.Meta.Version != "1.0.0" {
+		t.Errorf("Expected default version, got %+v", desc.Meta)
+	}
+
+	desc = DescribeTool(NewKeyboardShortcutTool(log, nil))
+	if desc.Meta.Version != "2.0.0" || desc.Meta.RenamedParameters["element_selector"] != "selector" {
+		t.Errorf("Expected keyboard_shortcuts rename metadata, got %+v", desc.Meta)
+	}
+	text := FormatToolDescription(desc)
+	if !strings.Contains(text, "Version: 2.0.0") || !strings.Contains(text, "element_selector → selector") {
+		t.Errorf("Expected version and rename in text output, got %s", text)
+	}
+}
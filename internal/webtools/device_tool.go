@@ -0,0 +1,243 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// measureViewportScript reads back what the page sees after emulation
+const measureViewportScript = `({width: window.innerWidth, height: window.innerHeight, device_pixel_ratio: window.devicePixelRatio, max_touch_points: navigator.maxTouchPoints, user_agent: navigator.userAgent})`
+
+// EmulateDeviceTool makes a page look like a phone, tablet or other screen
+type EmulateDeviceTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewEmulateDeviceTool(log *logger.Logger, mgr *browser.Manager) *EmulateDeviceTool {
+	return &EmulateDeviceTool{logger: log, browserMgr: mgr}
+}
+
+func (t *EmulateDeviceTool) Name() string {
+	return "emulate_device"
+}
+
+func (t *EmulateDeviceTool) Description() string {
+	return "Emulate a device on one page: viewport size, device scale factor, mobile flag, touch input and user agent. Pick a built-in device such as 'iPhone 14', 'Pixel 7' or 'iPad', or give the fields yourself; other pages keep the browser window's size"
+}
+
+func (t *EmulateDeviceTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"set", "reset", "list"},
+				"description": "set an emulation, reset the page to the browser window, or list the built-in devices",
+				"default":     "set",
+			},
+			"device": map[string]interface{}{
+				"type":        "string",
+				"enum":        browser.DeviceNames(),
+				"description": "Built-in device to emulate. Fields below override its values",
+			},
+			"width": map[string]interface{}{
+				"type":        "integer",
+				"description": "Viewport width in CSS pixels",
+				"minimum":     100,
+				"maximum":     7680,
+			},
+			"height": map[string]interface{}{
+				"type":        "integer",
+				"description": "Viewport height in CSS pixels",
+				"minimum":     100,
+				"maximum":     7680,
+			},
+			"device_scale_factor": map[string]interface{}{
+				"type":        "number",
+				"description": "Device pixels per CSS pixel (window.devicePixelRatio)",
+				"minimum":     0.5,
+				"maximum":     5,
+			},
+			"mobile": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Behave like a mobile browser: honour the meta viewport tag and use overlay scrollbars",
+			},
+			"touch": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Report touch support and deliver touch events",
+			},
+			"user_agent": map[string]interface{}{
+				"type":        "string",
+				"description": "User agent to send and report; empty keeps the browser's own",
+			},
+			"landscape": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Turn a portrait device sideways",
+				"default":     false,
+			},
+			"reload": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Reload the page afterwards, for sites that only check the user agent or screen size when they load",
+				"default":     false,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to emulate the device on (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *EmulateDeviceTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+		succeed := func(text string, data interface{}) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+			}, nil
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "set"
+		}
+		if action == "list" {
+			devices := make([]browser.DeviceEmulation, 0)
+			for _, name := range browser.DeviceNames() {
+				d, _ := browser.LookupDevice(name)
+				devices = append(devices, d)
+			}
+			return succeed(fmt.Sprintf("%d built-in devices", len(devices)), map[string]interface{}{"devices": devices})
+		}
+		if action != "set" && action != "reset" {
+			return fail("action must be one of set, reset, list")
+		}
+
+		var device *browser.DeviceEmulation
+		if action == "set" {
+			var err error
+			if device, err = deviceEmulationFromArgs(args); err != nil {
+				return fail(err.Error())
+			}
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		data := map[string]interface{}{"page_id": pageID}
+		var text string
+		if action == "reset" {
+			if err := t.browserMgr.ClearDeviceEmulation(pageID); err != nil {
+				return fail(fmt.Sprintf("Failed to reset device emulation: %v", err))
+			}
+			text = fmt.Sprintf("Page %s is back to the browser window's size and user agent", pageID)
+		} else {
+			if err := t.browserMgr.EmulateDevice(pageID, device); err != nil {
+				return fail(fmt.Sprintf("Failed to emulate device: %v", err))
+			}
+			data["device"] = t.browserMgr.DeviceEmulation(pageID)
+			label := device.Name
+			if label == "" {
+				label = "custom device"
+			}
+			text = fmt.Sprintf("Page %s now emulates %s", pageID, label)
+		}
+
+		// The page's own view of the screen confirms the change took
+		if raw, err := t.browserMgr.ExecuteScript(pageID, measureViewportScript); err == nil {
+			var measured map[string]interface{}
+			if decodeScriptValue(raw, &measured) == nil {
+				data["measured"] = measured
+				text += fmt.Sprintf(" (viewport %vx%v at %vx)", measured["width"], measured["height"], measured["device_pixel_ratio"])
+			}
+		}
+
+		if reload, _ := args["reload"].(bool); reload {
+			if _, err := t.browserMgr.ExecuteScript(pageID, "location.reload(); return true;"); err != nil {
+				return fail(fmt.Sprintf("Emulation applied but reloading failed: %v", err))
+			}
+			data["reloaded"] = true
+			text += "; reloading"
+		}
+		return succeed(text, data)
+	})
+}
+
+// deviceEmulationFromArgs starts from the named catalog device, if any, and
+// lays the explicitly given fields over it
+func deviceEmulationFromArgs(args map[string]interface{}) (*browser.DeviceEmulation, error) {
+	d := &browser.DeviceEmulation{DeviceScaleFactor: 1}
+	if name, _ := args["device"].(string); strings.TrimSpace(name) != "" {
+		found, ok := browser.LookupDevice(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown device %q; built-in devices are %s", name, strings.Join(browser.DeviceNames(), ", "))
+		}
+		*d = found
+	}
+
+	custom := false
+	for _, field := range []struct {
+		key string
+		dst *int
+	}{
+		{"width", &d.Width},
+		{"height", &d.Height},
+	} {
+		if v, ok := args[field.key].(float64); ok {
+			*field.dst = int(v)
+			custom = true
+		}
+	}
+	if v, ok := args["device_scale_factor"].(float64); ok {
+		d.DeviceScaleFactor = v
+		custom = true
+	}
+	for _, field := range []struct {
+		key string
+		dst *bool
+	}{
+		{"mobile", &d.Mobile},
+		{"touch", &d.Touch},
+	} {
+		if v, ok := args[field.key].(bool); ok {
+			*field.dst = v
+			custom = true
+		}
+	}
+	// Turning a device sideways leaves it the same device
+	if v, ok := args["landscape"].(bool); ok {
+		d.Landscape = v
+	}
+	if v, ok := args["user_agent"].(string); ok {
+		d.UserAgent = strings.TrimSpace(v)
+		custom = true
+	}
+
+	if d.Width == 0 || d.Height == 0 {
+		return nil, fmt.Errorf("give a device or both width and height")
+	}
+	// A tweaked catalog device is no longer that device
+	if custom && d.Name != "" {
+		d.Name += " (modified)"
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,59 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDeviceEmulationFromArgs(t *testing.T) {
+	d, err := deviceEmulationFromArgs(map[string]interface{}{"device": "ipad", "landscape": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "iPad" || !d.Touch || !d.Landscape || d.UserAgent == "" {
+		t.Errorf("iPad = %+v", d)
+	}
+
+	d, err = deviceEmulationFromArgs(map[string]interface{}{"device": "Pixel 7", "user_agent": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.UserAgent != "" || d.Name != "Pixel 7 (modified)" || d.Width != 412 {
+		t.Errorf("modified Pixel 7 = %+v", d)
+	}
+
+	d, err = deviceEmulationFromArgs(map[string]interface{}{"width": float64(800), "height": float64(600)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "" || d.DeviceScaleFactor != 1 || d.Mobile {
+		t.Errorf("custom = %+v", d)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"device": "Nokia 3310"},
+		{"width": float64(800)},
+		{"device": "iPhone 14", "device_scale_factor": float64(9)},
+	} {
+		if _, err := deviceEmulationFromArgs(args); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestEmulateDeviceTool(t *testing.T) {
+	tool := NewEmulateDeviceTool(createTestLogger(t), nil)
+
+	resp, err := tool.Execute(map[string]interface{}{"action": "list"})
+	if err != nil || resp.IsError || !strings.HasSuffix(resp.Content[0].Text, "built-in devices") {
+		t.Errorf("list = %+v, %v", resp, err)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"device": "Nokia 3310"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "iPhone 14") {
+		t.Errorf("Expected unknown device error listing the catalog, got %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "rotate"})
+	if !resp.IsError {
+		t.Error("Expected error for unknown action")
+	}
+}
@@ -0,0 +1,152 @@
+package webtools
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+)
+
+// domainSessions picks the saved session a page is opened with by the
+// host of its URL, so batch tools can reach signed-in areas without
+// credentials in every call
+type domainSessions struct {
+	// domains are sorted longest first so the most specific one wins
+	domains []string
+	names   map[string]string
+	states  map[string]*browser.SessionState
+}
+
+// domainSessionsSchema describes the sessions argument
+func domainSessionsSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"description":          "Saved session (from save_session or login) to use per domain, e.g. {\"example.com\": \"example-login\"}. A domain covers its subdomains and the most specific match wins; the session's cookies for that host are set before each page loads",
+		"additionalProperties": map[string]interface{}{"type": "string"},
+		"examples": []interface{}{
+			map[string]interface{}{"shop.example.com": "shop-admin", "example.com": "example-login"},
+		},
+	}
+}
+
+// normalizeSessionDomain lowercases a domain and strips a leading "*." or
+// "." so both spellings cover subdomains
+func normalizeSessionDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.TrimPrefix(domain, ".")
+}
+
+// parseDomainSessions validates the sessions argument into domain →
+// session name
+func parseDomainSessions(args map[string]interface{}) (map[string]string, error) {
+	raw, ok := args["sessions"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("sessions must map domains to session names")
+	}
+	names := make(map[string]string, len(obj))
+	for domain, value := range obj {
+		name, ok := value.(string)
+		if !ok || !recipeNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("sessions[%q] must be a saved session name", domain)
+		}
+		normalized := normalizeSessionDomain(domain)
+		if normalized == "" || strings.ContainsAny(normalized, "/:*") {
+			return nil, fmt.Errorf("sessions key %q is not a domain", domain)
+		}
+		names[normalized] = name
+	}
+	return names, nil
+}
+
+// loadDomainSessions reads the sessions named in names. It returns nil when
+// there are none.
+func loadDomainSessions(store *sessionStore, names map[string]string) (*domainSessions, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	d := &domainSessions{names: names, states: make(map[string]*browser.SessionState)}
+	for domain, name := range names {
+		state, err := store.load(name)
+		if err != nil {
+			return nil, err
+		}
+		d.domains = append(d.domains, domain)
+		d.states[domain] = state
+	}
+	sort.Slice(d.domains, func(i, j int) bool {
+		if len(d.domains[i]) != len(d.domains[j]) {
+			return len(d.domains[i]) > len(d.domains[j])
+		}
+		return d.domains[i] < d.domains[j]
+	})
+	return d, nil
+}
+
+// forURL returns the name of the session for rawURL's host and the cookies
+// from it that apply there. Cookies the session holds for other sites are
+// left out.
+func (d *domainSessions) forURL(rawURL string) (string, []*proto.NetworkCookieParam) {
+	if d == nil {
+		return "", nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range d.domains {
+		if !cookieMatchesHost(domain, host) {
+			continue
+		}
+		var cookies []*proto.NetworkCookie
+		for _, cookie := range d.states[domain].Cookies {
+			if cookieMatchesHost(cookie.Domain, host) {
+				cookies = append(cookies, cookie)
+			}
+		}
+		return d.names[domain], proto.CookiesToParams(cookies)
+	}
+	return "", nil
+}
+
+// openPage opens rawURL in a pooled page, first setting the cookies of
+// the session for its domain when there is one. It returns the session
+// used and a release function to call when done with the page.
+func (d *domainSessions) openPage(mgr *browser.Manager, rawURL string, opts browser.PageOptions) (pageID, session string, release func() error, err error) {
+	session, cookies := d.forURL(rawURL)
+	if session == "" {
+		_, pageID, err = mgr.NewPooledPage(rawURL, opts)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("failed to load page: %w", err)
+		}
+		return pageID, "", func() error { return mgr.RecyclePage(pageID) }, nil
+	}
+
+	// Recycling clears the origin's cookies, which other pages on the
+	// session still need, so these pages are closed instead
+	_, pageID, err = mgr.NewPooledPage("", opts)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("failed to open page: %w", err)
+	}
+	release = func() error { return mgr.ClosePage(pageID) }
+	if len(cookies) > 0 {
+		if err := mgr.SetCookies(pageID, cookies); err != nil {
+			release()
+			return "", "", nil, fmt.Errorf("failed to apply session %q: %w", session, err)
+		}
+	}
+	if err := mgr.NavigateExistingPage(pageID, rawURL); err != nil {
+		release()
+		return "", "", nil, fmt.Errorf("failed to load page: %w", err)
+	}
+	return pageID, session, release, nil
+}
@@ -0,0 +1,91 @@
+package webtools
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+)
+
+func TestParseDomainSessions(t *testing.T) {
+	names, err := parseDomainSessions(map[string]interface{}{
+		"sessions": map[string]interface{}{"*.Example.com": "main", "shop.example.com": "shop"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names["example.com"] != "main" || names["shop.example.com"] != "shop" {
+		t.Errorf("names = %v", names)
+	}
+
+	bad := []map[string]interface{}{
+		{"sessions": "main"},
+		{"sessions": map[string]interface{}{"example.com": 3.0}},
+		{"sessions": map[string]interface{}{"example.com": "../etc"}},
+		{"sessions": map[string]interface{}{"https://example.com/": "main"}},
+	}
+	for _, args := range bad {
+		if _, err := parseDomainSessions(args); err == nil {
+			t.Errorf("parseDomainSessions(%v) succeeded", args)
+		}
+	}
+	if names, err := parseDomainSessions(map[string]interface{}{}); err != nil || names != nil {
+		t.Errorf("no sessions argument: %v %v", names, err)
+	}
+}
+
+func TestDomainSessionsForURL(t *testing.T) {
+	store := newSessionStore(t.TempDir())
+	main := &browser.SessionState{Cookies: []*proto.NetworkCookie{
+		{Name: "sid", Value: "1", Domain: ".example.com", Path: "/"},
+		{Name: "host", Value: "2", Domain: "www.example.com", Path: "/"},
+		{Name: "other", Value: "3", Domain: ".other.com", Path: "/"},
+	}}
+	shop := &browser.SessionState{Cookies: []*proto.NetworkCookie{
+		{Name: "cart", Value: "4", Domain: "shop.example.com", Path: "/"},
+	}}
+	for name, state := range map[string]*browser.SessionState{"main": main, "shop": shop} {
+		if _, err := store.save(name, state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := loadDomainSessions(store, map[string]string{"example.com": "missing"}); err == nil {
+		t.Error("loading an unknown session succeeded")
+	}
+	sessions, err := loadDomainSessions(store, map[string]string{"example.com": "main", "shop.example.com": "shop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		url     string
+		session string
+		cookies string
+	}{
+		{"https://www.example.com/account", "main", "host,sid"},
+		{"https://example.com/", "main", "sid"},
+		{"https://shop.example.com:8443/cart", "shop", "cart"},
+		{"https://notexample.com/", "", ""},
+		{"https://other.com/", "", ""},
+	}
+	for _, c := range cases {
+		session, cookies := sessions.forURL(c.url)
+		var names []string
+		for _, cookie := range cookies {
+			names = append(names, cookie.Name)
+		}
+		sort.Strings(names)
+		if session != c.session || strings.Join(names, ",") != c.cookies {
+			t.Errorf("forURL(%s) = %q %v, want %q %s", c.url, session, names, c.session, c.cookies)
+		}
+	}
+
+	var none *domainSessions
+	if session, cookies := none.forURL("https://example.com/"); session != "" || cookies != nil {
+		t.Error("nil sessions should apply to nothing")
+	}
+}
@@ -0,0 +1,396 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxElementExistsWait bounds how long element_exists will poll
+const maxElementExistsWait = 30
+
+// countElements returns how many elements match selector on the page,
+// optionally counting only those that are visible
+func countElements(mgr *browser.Manager, pageID, selector string, visibleOnly bool) (int, error) {
+	selectorJSON, _ := json.Marshal(selector)
+	script := fmt.Sprintf(`
+		const elements = Array.from(document.querySelectorAll(%s));
+		if (!%t) {
+			return elements.length;
+		}
+		return elements.filter(el => {
+			const style = window.getComputedStyle(el);
+			if (style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0') {
+				return false;
+			}
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		}).length;
+	`, selectorJSON, visibleOnly)
+
+	result, err := mgr.ExecuteScript(pageID, script)
+	if err != nil {
+		return 0, err
+	}
+	count, err := strconv.Atoi(fmt.Sprint(result))
+	if err != nil {
+		return 0, fmt.Errorf("unexpected count result %v", result)
+	}
+	return count, nil
+}
+
+// resolveQueryPage returns the page_id argument or the first open page
+func resolveQueryPage(mgr *browser.Manager, args map[string]interface{}) (string, bool) {
+	if pageID, ok := args["page_id"].(string); ok && pageID != "" {
+		return pageID, true
+	}
+	pages := mgr.ListPages()
+	if len(pages) == 0 {
+		return "", false
+	}
+	return pages[0], true
+}
+
+func queryErrorResponse(message string) *types.CallToolResponse {
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: message,
+		}},
+		IsError: true,
+	}
+}
+
+// CountElementsTool reports how many elements match a selector. Unlike
+// assert_element a count of zero is a normal result, not an error.
+type CountElementsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewCountElementsTool(log *logger.Logger, mgr *browser.Manager) *CountElementsTool {
+	return &CountElementsTool{logger: log, browserMgr: mgr}
+}
+
+func (t *CountElementsTool) Name() string {
+	return "count_elements"
+}
+
+func (t *CountElementsTool) Description() string {
+	return "Count elements matching a CSS selector. Returns the number without treating zero as a failure, so it is safe for branching logic"
+}
+
+func (t *CountElementsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to count",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (optional, uses first page if not specified)",
+			},
+			"visible_only": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Only count elements that are visible (default: false)",
+				"default":     false,
+			},
+		},
+		Required: []string{"selector"},
+	}
+}
+
+func (t *CountElementsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		selector, _ := args["selector"].(string)
+		if strings.TrimSpace(selector) == "" {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return queryErrorResponse("selector is required"), nil
+		}
+		visibleOnly, _ := args["visible_only"].(bool)
+
+		pageID, ok := resolveQueryPage(t.browserMgr, args)
+		if !ok {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		count, err := countElements(t.browserMgr, pageID, selector, visibleOnly)
+		t.logger.LogToolExecution(t.Name(), args, err == nil, time.Since(start).Milliseconds())
+		if err != nil {
+			return queryErrorResponse(fmt.Sprintf("Failed to count elements: %v", err)), nil
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("%d elements match %s", count, selector),
+				Data: map[string]interface{}{
+					"count":        count,
+					"selector":     selector,
+					"visible_only": visibleOnly,
+					"page_id":      pageID,
+				},
+			}},
+		}, nil
+	})
+}
+
+// ElementExistsTool reports whether an element is present, optionally waiting
+// briefly for it. A missing element is a normal false result, not an error.
+type ElementExistsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewElementExistsTool(log *logger.Logger, mgr *browser.Manager) *ElementExistsTool {
+	return &ElementExistsTool{logger: log, browserMgr: mgr}
+}
+
+func (t *ElementExistsTool) Name() string {
+	return "element_exists"
+}
+
+func (t *ElementExistsTool) Description() string {
+	return "Check whether an element matching a CSS selector exists (or is visible). Returns true/false without treating absence as a failure, so it is safe for branching logic"
+}
+
+func (t *ElementExistsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to look for",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (optional, uses first page if not specified)",
+			},
+			"visible": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Require the element to be visible, not just present in the DOM (default: false)",
+				"default":     false,
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Seconds to keep checking before answering false (default: 0, check once)",
+				"default":     0,
+				"minimum":     0,
+				"maximum":     maxElementExistsWait,
+			},
+		},
+		Required: []string{"selector"},
+	}
+}
+
+func (t *ElementExistsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		selector, _ := args["selector"].(string)
+		if strings.TrimSpace(selector) == "" {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return queryErrorResponse("selector is required"), nil
+		}
+		visible, _ := args["visible"].(bool)
+
+		wait := 0.0
+		if val, ok := args["timeout"].(float64); ok {
+			if val < 0 || val > maxElementExistsWait {
+				t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+				return queryErrorResponse(fmt.Sprintf("timeout must be between 0 and %d seconds", maxElementExistsWait)), nil
+			}
+			wait = val
+		}
+
+		pageID, ok := resolveQueryPage(t.browserMgr, args)
+		if !ok {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		deadline := start.Add(time.Duration(wait * float64(time.Second)))
+		var count int
+		var err error
+		for {
+			count, err = countElements(t.browserMgr, pageID, selector, visible)
+			if (err == nil && count > 0) || !time.Now().Before(deadline) {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, err == nil, time.Since(start).Milliseconds())
+		if err != nil {
+			return queryErrorResponse(fmt.Sprintf("Failed to check element: %v", err)), nil
+		}
+
+		exists := count > 0
+		text := fmt.Sprintf("Element %s exists", selector)
+		if !exists {
+			text = fmt.Sprintf("Element %s does not exist", selector)
+		}
+		if visible {
+			text = fmt.Sprintf("Element %s is visible", selector)
+			if !exists {
+				text = fmt.Sprintf("Element %s is not visible", selector)
+			}
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"exists":     exists,
+					"count":      count,
+					"selector":   selector,
+					"visible":    visible,
+					"page_id":    pageID,
+					"elapsed_ms": time.Since(start).Milliseconds(),
+				},
+			}},
+		}, nil
+	})
+}
+
+// GetElementStateTool reports the checks click and type make before acting,
+// so an agent can see why an action would wait or fail before trying it
+type GetElementStateTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewGetElementStateTool(log *logger.Logger, mgr *browser.Manager) *GetElementStateTool {
+	return &GetElementStateTool{logger: log, browserMgr: mgr}
+}
+
+func (t *GetElementStateTool) Name() string {
+	return "get_element_state"
+}
+
+func (t *GetElementStateTool) Description() string {
+	return "Report whether an element is visible, enabled, stable (not moving), focused, in the viewport and interactable (a click would reach it rather than a covering element), plus its bounding rect. Uses the same checks click_element and type_text wait for"
+}
+
+func (t *GetElementStateTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Element to inspect. " + selectorSyntax,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (optional, uses first page if not specified)",
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Seconds to wait for the element to appear (default: 10)",
+				"default":     10,
+				"minimum":     0,
+				"maximum":     maxElementExistsWait,
+			},
+		},
+		Required: []string{"selector"},
+	}
+}
+
+func (t *GetElementStateTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *GetElementStateTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		selector, _ := args["selector"].(string)
+		if strings.TrimSpace(selector) == "" {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return queryErrorResponse("selector is required"), nil
+		}
+
+		timeout := browser.ElementTimeout
+		if val, ok := args["timeout"].(float64); ok {
+			if val < 0 || val > maxElementExistsWait {
+				t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+				return queryErrorResponse(fmt.Sprintf("timeout must be between 0 and %d seconds", maxElementExistsWait)), nil
+			}
+			if val > 0 {
+				timeout = time.Duration(val * float64(time.Second))
+			}
+		}
+
+		pageID, ok := resolveQueryPage(t.browserMgr, args)
+		if !ok {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		state, err := t.browserMgr.ElementStateContext(ctx, pageID, selector, timeout)
+		t.logger.LogToolExecution(t.Name(), args, err == nil, time.Since(start).Milliseconds())
+		if err != nil {
+			return queryErrorResponse(fmt.Sprintf("Failed to read element state: %v", err)), nil
+		}
+
+		data := map[string]interface{}{
+			"selector":     selector,
+			"page_id":      pageID,
+			"visible":      state.Visible,
+			"enabled":      state.Enabled,
+			"stable":       state.Stable,
+			"focused":      state.Focused,
+			"in_viewport":  state.InViewport,
+			"interactable": state.Interactable,
+		}
+		if state.BlockedBy != "" {
+			data["blocked_by"] = state.BlockedBy
+		}
+		if state.Rect != nil {
+			data["rect"] = state.Rect
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Element %s is %s", selector, describeElementState(state)),
+				Data: data,
+			}},
+		}, nil
+	})
+}
+
+// describeElementState lists an element's state in words, e.g. "visible,
+// enabled, moving, not focused, in viewport, not interactable (covered by
+// <div.modal>)"
+func describeElementState(state *browser.ElementState) string {
+	word := func(ok bool, yes, no string) string {
+		if ok {
+			return yes
+		}
+		return no
+	}
+	parts := []string{
+		word(state.Visible, "visible", "hidden"),
+		word(state.Enabled, "enabled", "disabled"),
+		word(state.Stable, "stable", "moving"),
+		word(state.Focused, "focused", "not focused"),
+		word(state.InViewport, "in viewport", "outside viewport"),
+		word(state.Interactable, "interactable", "not interactable"),
+	}
+	text := strings.Join(parts, ", ")
+	if state.BlockedBy != "" {
+		text += " (" + state.BlockedBy + ")"
+	}
+	return text
+}
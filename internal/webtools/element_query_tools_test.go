@@ -0,0 +1,51 @@
+package webtools
+
+import (
+	"rodmcp/internal/browser"
+	"rodmcp/pkg/types"
+	"strings"
+	"testing"
+)
+
+func TestElementQueryToolsValidation(t *testing.T) {
+	log := createTestLogger(t)
+	mgr := browser.NewManager(log, browser.Config{Headless: true})
+
+	cases := []struct {
+		name string
+		tool interface {
+			Execute(map[string]interface{}) (*types.CallToolResponse, error)
+		}
+		args map[string]interface{}
+		want string
+	}{
+		{"count without selector", NewCountElementsTool(log, mgr), map[string]interface{}{}, "selector is required"},
+		{"exists without selector", NewElementExistsTool(log, mgr), map[string]interface{}{"selector": " "}, "selector is required"},
+		{"exists timeout too long", NewElementExistsTool(log, mgr), map[string]interface{}{"selector": "h1", "timeout": float64(60)}, "timeout must be between"},
+		{"count without pages", NewCountElementsTool(log, mgr), map[string]interface{}{"selector": "h1"}, "No browser pages"},
+		{"exists without pages", NewElementExistsTool(log, mgr), map[string]interface{}{"selector": "h1"}, "No browser pages"},
+		{"state without selector", NewGetElementStateTool(log, mgr), map[string]interface{}{}, "selector is required"},
+		{"state timeout too long", NewGetElementStateTool(log, mgr), map[string]interface{}{"selector": "h1", "timeout": float64(60)}, "timeout must be between"},
+		{"state without pages", NewGetElementStateTool(log, mgr), map[string]interface{}{"selector": "h1"}, "No browser pages"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.tool.Execute(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("Expected error containing %q, got %q", tc.want, resp.Content[0].Text)
+			}
+		})
+	}
+}
+
+func TestDescribeElementState(t *testing.T) {
+	state := &browser.ElementState{Visible: true, Enabled: true, InViewport: true, BlockedBy: "covered by <div.modal>"}
+	want := "visible, enabled, moving, not focused, in viewport, not interactable (covered by <div.modal>)"
+	if got := describeElementState(state); got != want {
+		t.Errorf("describeElementState = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package webtools
+
+import (
+	"fmt"
+
+	"rodmcp/pkg/types"
+)
+
+// ElicitFunc asks the client's user for input in the middle of a tool call.
+// It fails when the client cannot be asked (see mcp.Server.Elicit).
+type ElicitFunc func(req types.ElicitRequest) (*types.ElicitResult, error)
+
+// askString asks the user for a single string field. It returns "" when
+// nobody can be asked or the user declines or cancels, so callers fall back
+// to what they would have done without asking. With options the user
+// chooses one of them.
+func askString(ask ElicitFunc, message, field, title string, options []string) (string, error) {
+	if ask == nil {
+		return "", nil
+	}
+	property := map[string]interface{}{
+		"type":  "string",
+		"title": title,
+	}
+	if len(options) > 0 {
+		property["enum"] = options
+	}
+	result, err := ask(types.ElicitRequest{
+		Message: message,
+		RequestedSchema: types.ToolSchema{
+			Type:       "object",
+			Properties: map[string]interface{}{field: property},
+			Required:   []string{field},
+		},
+	})
+	if err != nil || result.Action != "accept" {
+		return "", err
+	}
+	value, _ := result.Content[field].(string)
+	if len(options) > 0 && value != "" && !containsString(options, value) {
+		return "", fmt.Errorf("%q is not one of the offered choices", value)
+	}
+	return value, nil
+}
@@ -0,0 +1,32 @@
+package webtools
+
+import (
+	"testing"
+
+	"rodmcp/pkg/types"
+)
+
+func TestAskString(t *testing.T) {
+	answer := func(action, value string) ElicitFunc {
+		return func(req types.ElicitRequest) (*types.ElicitResult, error) {
+			if _, ok := req.RequestedSchema.Properties["profile"]; !ok {
+				t.Errorf("schema = %+v", req.RequestedSchema)
+			}
+			return &types.ElicitResult{Action: action, Content: map[string]interface{}{"profile": value}}, nil
+		}
+	}
+	options := []string{"work", "personal"}
+
+	if got, err := askString(nil, "?", "profile", "Profile", options); got != "" || err != nil {
+		t.Errorf("nil asker = %q, %v", got, err)
+	}
+	if got, err := askString(answer("accept", "work"), "?", "profile", "Profile", options); got != "work" || err != nil {
+		t.Errorf("accept = %q, %v", got, err)
+	}
+	if got, err := askString(answer("decline", "work"), "?", "profile", "Profile", options); got != "" || err != nil {
+		t.Errorf("decline = %q, %v", got, err)
+	}
+	if _, err := askString(answer("accept", "other"), "?", "profile", "Profile", options); err == nil {
+		t.Error("Expected error for an answer outside the choices")
+	}
+}
@@ -267,6 +267,29 @@ func GetToolExamples(toolName string) []ToolExample {
 				},
 				Expected: "Completes checkout form with billing information and validation",
 			},
+			{
+				Name: "Signup Wizard",
+				Description: "Fill a three-page signup wizard in one call",
+				Parameters: map[string]interface{}{
+					"steps": []interface{}{
+						map[string]interface{}{
+							"fields":        map[string]interface{}{"Email": "jane@example.com", "Password": "s3cret-pass"},
+							"next_selector": "button.next",
+						},
+						map[string]interface{}{
+							"fields":        map[string]interface{}{"Phone": "5551234567"},
+							"field_modes":   map[string]interface{}{"Phone": "type"},
+							"next_selector": "button.next",
+							"wait_for":      "#plan-options",
+						},
+						map[string]interface{}{
+							"fields": map[string]interface{}{"plan": "pro"},
+							"submit": true,
+						},
+					},
+				},
+				Expected: "Fills each page, clicks Next, waits for the following page and reports results per step",
+			},
 		},
 		
 		"wait_for_condition": {
@@ -330,6 +353,18 @@ func GetToolExamples(toolName string) []ToolExample {
 				},
 				Expected: "Passes if success alert is visible on screen",
 			},
+			{
+				Name: "Wait Until Counter Updates",
+				Description: "Poll an assertion until async content settles instead of checking once",
+				Parameters: map[string]interface{}{
+					"selector": "#cart-count",
+					"assertion": "exact_text",
+					"expected_value": "3",
+					"timeout": 10,
+					"retry_until_timeout": true,
+				},
+				Expected: "Passes as soon as the cart count reads 3, fails only if it never does within 10 seconds",
+			},
 		},
 		
 		"extract_table": {
@@ -366,6 +401,27 @@ func GetToolExamples(toolName string) []ToolExample {
 				},
 				Expected: "Returns array of arrays with cell values for custom processing",
 			},
+			{
+				Name: "Quarterly Report With Grouped Headers",
+				Description: "Extract a report whose headers span columns, keeping the totals row apart",
+				Parameters: map[string]interface{}{
+					"selector": "table.report",
+					"header_rows": 2,
+					"footer_rows": "separate",
+					"nested_tables": "skip",
+				},
+				Expected: "Returns rows keyed by combined headers like 'Q1 / Revenue' with the totals in a footer field",
+			},
+			{
+				Name: "Stream A Huge Table To CSV",
+				Description: "Export a 50,000 row transaction log without loading it into memory",
+				Parameters: map[string]interface{}{
+					"selector": "#transactions",
+					"output_file": "exports/transactions.csv",
+					"chunk_size": 2000,
+				},
+				Expected: "Writes the table to exports/transactions.csv chunk by chunk and returns the row count and file size",
+			},
 		},
 		
 		"keyboard_shortcuts": {
@@ -86,6 +86,32 @@ func (pv *PathValidator) ValidatePath(inputPath string, operation string) error
 	return nil
 }
 
+// ResolvePath resolves inputPath against an optional per-call working directory.
+// The working directory itself must exist and be within the allowed paths;
+// relative paths are joined onto it while absolute paths are left as-is.
+func (pv *PathValidator) ResolvePath(inputPath string, cwd string) (string, error) {
+	if cwd == "" {
+		return filepath.Clean(inputPath), nil
+	}
+
+	if err := pv.ValidatePath(cwd, "cwd"); err != nil {
+		return "", fmt.Errorf("invalid cwd: %w", err)
+	}
+
+	info, err := os.Stat(cwd)
+	if err != nil {
+		return "", fmt.Errorf("invalid cwd %s: %w", cwd, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("invalid cwd: %s is not a directory", cwd)
+	}
+
+	if filepath.IsAbs(inputPath) {
+		return filepath.Clean(inputPath), nil
+	}
+	return filepath.Join(cwd, inputPath), nil
+}
+
 // ValidateFileSize checks if a file size is within limits for write operations
 func (pv *PathValidator) ValidateFileSize(size int64) error {
 	if pv.config.MaxFileSize > 0 && size > pv.config.MaxFileSize {
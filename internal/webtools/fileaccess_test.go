@@ -261,4 +261,111 @@ func TestGetAllowedPaths(t *testing.T) {
 	if !tempDirIncluded {
 		t.Error("Expected temp directory to be in allowed paths list")
 	}
-}
\ No newline at end of file
+}
+func TestPathValidatorResolvePath(t *testing.T) {
+	allowedDir := t.TempDir()
+	projectDir := filepath.Join(allowedDir, "project")
+	if err := os.Mkdir(projectDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	outsideDir := t.TempDir()
+
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{allowedDir},
+		RestrictToWorkingDir: false,
+	})
+
+	// No cwd keeps the existing behaviour
+	resolved, err := validator.ResolvePath("./a/../b.txt", "")
+	if err != nil || resolved != "b.txt" {
+		t.Errorf("Expected cleaned relative path, got %q (err %v)", resolved, err)
+	}
+
+	resolved, err = validator.ResolvePath("index.html", projectDir)
+	if err != nil {
+		t.Fatalf("Expected cwd inside allowed paths to resolve, got %v", err)
+	}
+	if resolved != filepath.Join(projectDir, "index.html") {
+		t.Errorf("Expected path joined onto cwd, got %s", resolved)
+	}
+
+	absolute := filepath.Join(allowedDir, "abs.txt")
+	if resolved, _ := validator.ResolvePath(absolute, projectDir); resolved != absolute {
+		t.Errorf("Expected absolute path to ignore cwd, got %s", resolved)
+	}
+
+	if _, err := validator.ResolvePath("index.html", outsideDir); err == nil {
+		t.Error("Expected cwd outside allowed paths to be rejected")
+	}
+
+	if _, err := validator.ResolvePath("index.html", filepath.Join(allowedDir, "missing")); err == nil {
+		t.Error("Expected non-existent cwd to be rejected")
+	}
+
+	filePath := filepath.Join(allowedDir, "file.txt")
+	os.WriteFile(filePath, []byte("x"), 0644)
+	if _, err := validator.ResolvePath("index.html", filePath); err == nil {
+		t.Error("Expected cwd pointing at a file to be rejected")
+	}
+}
+
+func TestFileToolsWithCwd(t *testing.T) {
+	allowedDir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{allowedDir},
+		RestrictToWorkingDir: false,
+		MaxFileSize:          1024,
+	})
+	log := createTestLogger(t)
+
+	writeTool := NewWriteFileTool(log, validator)
+	if _, err := writeTool.Execute(map[string]interface{}{
+		"path":    "notes.txt",
+		"content": "hello",
+		"cwd":     allowedDir,
+	}); err != nil {
+		t.Fatalf("write_file with cwd failed: %v", err)
+	}
+
+	readTool := NewReadFileTool(log, validator)
+	resp, err := readTool.Execute(map[string]interface{}{"path": "notes.txt", "cwd": allowedDir})
+	if err != nil {
+		t.Fatalf("read_file with cwd failed: %v", err)
+	}
+	if resp.Content[0].Text != "hello" {
+		t.Errorf("Expected file contents, got %q", resp.Content[0].Text)
+	}
+
+	listTool := NewListDirectoryTool(log, validator)
+	resp, err = listTool.Execute(map[string]interface{}{"cwd": allowedDir})
+	if err != nil {
+		t.Fatalf("list_directory with cwd failed: %v", err)
+	}
+	if resp.Content[0].Data.(map[string]interface{})["item_count"] != 1 {
+		t.Errorf("Expected one item in cwd listing, got %v", resp.Content[0].Data)
+	}
+
+	pageTool := NewCreatePageToolWithValidator(log, validator)
+	resp, err = pageTool.Execute(map[string]interface{}{
+		"filename": "page",
+		"title":    "Test",
+		"html":     "<p>hi</p>",
+		"cwd":      allowedDir,
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("create_page with cwd failed: %v %v", err, resp)
+	}
+	if _, err := os.Stat(filepath.Join(allowedDir, "page.html")); err != nil {
+		t.Errorf("Expected page to be created in cwd: %v", err)
+	}
+
+	resp, _ = pageTool.Execute(map[string]interface{}{
+		"filename": "page",
+		"title":    "Test",
+		"html":     "<p>hi</p>",
+		"cwd":      t.TempDir(),
+	})
+	if resp == nil || !resp.IsError {
+		t.Error("Expected create_page to reject cwd outside allowed paths")
+	}
+}
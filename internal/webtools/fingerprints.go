@@ -0,0 +1,451 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// defaultFingerprintDir is where fingerprint profiles are kept when no
+// --fingerprint-dir is configured, relative to the working directory
+const defaultFingerprintDir = "fingerprints"
+
+// fingerprintProfile is a named, persisted fingerprint. Reusing the same
+// profile keeps every property, including canvas noise, identical across
+// visits.
+type fingerprintProfile struct {
+	Name        string              `json:"name"`
+	Fingerprint browser.Fingerprint `json:"fingerprint"`
+	URLPatterns []string            `json:"url_patterns,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
+
+// fingerprintPreset is a consistent combination of OS, screen and GPU that
+// generated profiles draw from
+type fingerprintPreset struct {
+	userAgent string
+	platform  string
+	screens   [][3]float64 // width, height, device scale factor
+	gpus      [][2]string  // WebGL vendor, renderer
+}
+
+var fingerprintPresets = map[string]fingerprintPreset{
+	"windows": {
+		userAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		platform:  "Win32",
+		screens:   [][3]float64{{1920, 1080, 1}, {1536, 864, 1.25}, {1366, 768, 1}, {2560, 1440, 1}},
+		gpus: [][2]string{
+			{"Google Inc. (NVIDIA)", "ANGLE (NVIDIA, NVIDIA GeForce GTX 1650 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+			{"Google Inc. (Intel)", "ANGLE (Intel, Intel(R) UHD Graphics 630 Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+			{"Google Inc. (AMD)", "ANGLE (AMD, AMD Radeon RX 580 Series Direct3D11 vs_5_0 ps_5_0, D3D11)"},
+		},
+	},
+	"mac": {
+		userAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		platform:  "MacIntel",
+		screens:   [][3]float64{{1440, 900, 2}, {1512, 982, 2}, {1728, 1117, 2}, {1920, 1080, 1}},
+		gpus: [][2]string{
+			{"Google Inc. (Apple)", "ANGLE (Apple, ANGLE Metal Renderer: Apple M1, Unspecified Version)"},
+			{"Google Inc. (Apple)", "ANGLE (Apple, ANGLE Metal Renderer: Apple M2, Unspecified Version)"},
+		},
+	},
+	"linux": {
+		userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		platform:  "Linux x86_64",
+		screens:   [][3]float64{{1920, 1080, 1}, {2560, 1440, 1}, {1366, 768, 1}},
+		gpus: [][2]string{
+			{"Google Inc. (Intel)", "ANGLE (Intel, Mesa Intel(R) UHD Graphics 620 (KBL GT2), OpenGL 4.6)"},
+			{"Google Inc. (NVIDIA Corporation)", "ANGLE (NVIDIA Corporation, NVIDIA GeForce GTX 1060/PCIe/SSE2, OpenGL 4.5.0)"},
+		},
+	},
+}
+
+// fingerprintPresetNames lists the presets in a stable order
+func fingerprintPresetNames() []string {
+	names := make([]string, 0, len(fingerprintPresets))
+	for name := range fingerprintPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// generateFingerprint picks a random but internally consistent fingerprint
+// from a preset; an empty preset picks one at random
+func generateFingerprint(preset string) (browser.Fingerprint, error) {
+	if preset == "" {
+		names := fingerprintPresetNames()
+		preset = names[rand.IntN(len(names))]
+	}
+	p, ok := fingerprintPresets[preset]
+	if !ok {
+		return browser.Fingerprint{}, fmt.Errorf("unknown preset %q (use %s)", preset, strings.Join(fingerprintPresetNames(), ", "))
+	}
+	screen := p.screens[rand.IntN(len(p.screens))]
+	gpu := p.gpus[rand.IntN(len(p.gpus))]
+	cores := []int{4, 8, 8, 12, 16}
+	return browser.Fingerprint{
+		UserAgent:           p.userAgent,
+		Platform:            p.platform,
+		Languages:           []string{"en-US", "en"},
+		ScreenWidth:         int(screen[0]),
+		ScreenHeight:        int(screen[1]),
+		DeviceScaleFactor:   screen[2],
+		HardwareConcurrency: cores[rand.IntN(len(cores))],
+		WebGLVendor:         gpu[0],
+		WebGLRenderer:       gpu[1],
+		NoiseSeed:           rand.Uint32() | 1,
+	}, nil
+}
+
+// fingerprintStore keeps fingerprint profiles as one JSON file each
+type fingerprintStore struct {
+	dir string
+}
+
+func newFingerprintStore(dir string) *fingerprintStore {
+	if dir == "" {
+		dir = defaultFingerprintDir
+	}
+	return &fingerprintStore{dir: dir}
+}
+
+func (s *fingerprintStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *fingerprintStore) exists(name string) bool {
+	_, err := os.Stat(s.path(name))
+	return err == nil
+}
+
+// save writes a profile, replacing any with the same name
+func (s *fingerprintStore) save(profile *fingerprintProfile) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create fingerprint directory: %w", err)
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := s.path(profile.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// load reads the named profile
+func (s *fingerprintStore) load(name string) (*fingerprintProfile, error) {
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid fingerprint profile name %q", name)
+	}
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no fingerprint profile named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var profile fingerprintProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("fingerprint profile %q is corrupt: %w", name, err)
+	}
+	return &profile, nil
+}
+
+// remove deletes the named profile
+func (s *fingerprintStore) remove(name string) error {
+	if !recipeNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid fingerprint profile name %q", name)
+	}
+	err := os.Remove(s.path(name))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no fingerprint profile named %q", name)
+	}
+	return err
+}
+
+// list returns every readable profile, sorted by name
+func (s *fingerprintStore) list() ([]*fingerprintProfile, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles []*fingerprintProfile
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		if profile, err := s.load(name); err == nil {
+			profiles = append(profiles, profile)
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles, nil
+}
+
+// findForURL returns the profile assigned to url through its URL patterns,
+// preferring the most specific pattern as recipes do
+func (s *fingerprintStore) findForURL(url string) (*fingerprintProfile, error) {
+	profiles, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	var best *fingerprintProfile
+	bestScore := -1
+	for _, profile := range profiles {
+		for _, pattern := range profile.URLPatterns {
+			if !matchURLPattern(pattern, url) {
+				continue
+			}
+			score := len(pattern) - 10*strings.Count(pattern, "*")
+			if score > bestScore {
+				best, bestScore = profile, score
+			}
+		}
+	}
+	return best, nil
+}
+
+// FingerprintProfileTool creates, inspects and applies fingerprint profiles
+type FingerprintProfileTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	store      *fingerprintStore
+}
+
+// NewFingerprintProfileTool creates a fingerprint_profile tool keeping
+// profiles in dir (default: fingerprints under the working directory)
+func NewFingerprintProfileTool(log *logger.Logger, mgr *browser.Manager, dir string) *FingerprintProfileTool {
+	return &FingerprintProfileTool{logger: log, browserMgr: mgr, store: newFingerprintStore(dir)}
+}
+
+func (t *FingerprintProfileTool) Name() string {
+	return "fingerprint_profile"
+}
+
+func (t *FingerprintProfileTool) Description() string {
+	return "Manage persistent browser fingerprint profiles (user agent, platform, languages, screen size, WebGL vendor and seeded canvas noise). Create a profile once and apply it to a page, pass it to navigate_page, or give it url_patterns so matching sites always see the same fingerprint"
+}
+
+func (t *FingerprintProfileTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"create", "list", "get", "delete", "apply"},
+				"description": "create a profile, list or get profiles, delete one, or apply one to a page",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Profile name (letters, digits, '.', '_', '-')",
+			},
+			"preset": map[string]interface{}{
+				"type":        "string",
+				"enum":        fingerprintPresetNames(),
+				"description": "create: operating system to generate a consistent profile for (random if omitted). Explicit fields below override generated ones",
+			},
+			"user_agent":           map[string]interface{}{"type": "string"},
+			"platform":             map[string]interface{}{"type": "string", "description": "navigator.platform, e.g. Win32, MacIntel"},
+			"languages":            map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"screen_width":         map[string]interface{}{"type": "integer"},
+			"screen_height":        map[string]interface{}{"type": "integer"},
+			"hardware_concurrency": map[string]interface{}{"type": "integer"},
+			"webgl_vendor":         map[string]interface{}{"type": "string"},
+			"webgl_renderer":       map[string]interface{}{"type": "string"},
+			"canvas_noise": map[string]interface{}{
+				"type":        "boolean",
+				"description": "create: add seeded canvas noise",
+				"default":     true,
+			},
+			"url_patterns": map[string]interface{}{
+				"type":        "array",
+				"items":       map[string]interface{}{"type": "string"},
+				"description": "create: URLs (with * wildcards) navigate_page applies this profile to automatically",
+			},
+			"overwrite": map[string]interface{}{
+				"type":        "boolean",
+				"description": "create: replace an existing profile. Sites that saw the old one will see a new visitor",
+				"default":     false,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "apply: page to apply the profile to (uses current page if not specified)",
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (t *FingerprintProfileTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+		succeed := func(text string, data interface{}) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+			}, nil
+		}
+
+		action, _ := args["action"].(string)
+		name, _ := args["name"].(string)
+		if action != "list" && !recipeNamePattern.MatchString(name) {
+			return fail("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+		}
+
+		switch action {
+		case "create":
+			profile, err := fingerprintProfileFromArgs(name, args)
+			if err != nil {
+				return fail(err.Error())
+			}
+			if overwrite, _ := args["overwrite"].(bool); !overwrite && t.store.exists(name) {
+				return fail(fmt.Sprintf("Fingerprint profile %q already exists; pass overwrite=true to replace it", name))
+			}
+			path, err := t.store.save(profile)
+			if err != nil {
+				return fail(fmt.Sprintf("Failed to save fingerprint profile: %v", err))
+			}
+			return succeed(fmt.Sprintf("Saved fingerprint profile %s (%s, %dx%d)", name, profile.Fingerprint.Platform,
+				profile.Fingerprint.ScreenWidth, profile.Fingerprint.ScreenHeight),
+				map[string]interface{}{"path": path, "profile": profile})
+
+		case "list":
+			profiles, err := t.store.list()
+			if err != nil {
+				return fail(fmt.Sprintf("Failed to list fingerprint profiles: %v", err))
+			}
+			summaries := make([]map[string]interface{}, 0, len(profiles))
+			for _, p := range profiles {
+				summaries = append(summaries, map[string]interface{}{
+					"name":         p.Name,
+					"platform":     p.Fingerprint.Platform,
+					"user_agent":   p.Fingerprint.UserAgent,
+					"url_patterns": p.URLPatterns,
+				})
+			}
+			return succeed(fmt.Sprintf("%d fingerprint profiles", len(profiles)), map[string]interface{}{"profiles": summaries})
+
+		case "get":
+			profile, err := t.store.load(name)
+			if err != nil {
+				return fail(err.Error())
+			}
+			return succeed(fmt.Sprintf("Fingerprint profile %s", name), profile)
+
+		case "delete":
+			if err := t.store.remove(name); err != nil {
+				return fail(err.Error())
+			}
+			return succeed(fmt.Sprintf("Deleted fingerprint profile %s", name), map[string]interface{}{"name": name})
+
+		case "apply":
+			profile, err := t.store.load(name)
+			if err != nil {
+				return fail(err.Error())
+			}
+			pageID, _ := args["page_id"].(string)
+			if pageID == "" {
+				pageID = t.browserMgr.GetCurrentPageID()
+				if pageID == "" {
+					return createNoPagesErrorResponse(t.Name()), nil
+				}
+			}
+			if err := t.browserMgr.ApplyFingerprint(pageID, &profile.Fingerprint); err != nil {
+				return fail(fmt.Sprintf("Failed to apply fingerprint profile %s: %v", name, err))
+			}
+			return succeed(fmt.Sprintf("Applied fingerprint profile %s to %s; it takes full effect from the next navigation", name, pageID),
+				map[string]interface{}{"name": name, "page_id": pageID})
+		}
+		return fail("action must be one of create, list, get, delete, apply")
+	})
+}
+
+// fingerprintProfileFromArgs generates a profile from the preset and lays
+// any explicitly given fields over it
+func fingerprintProfileFromArgs(name string, args map[string]interface{}) (*fingerprintProfile, error) {
+	preset, _ := args["preset"].(string)
+	fp, err := generateFingerprint(preset)
+	if err != nil {
+		return nil, err
+	}
+
+	strField := func(key string, dst *string) {
+		if v, ok := args[key].(string); ok && v != "" {
+			*dst = v
+		}
+	}
+	strField("user_agent", &fp.UserAgent)
+	strField("platform", &fp.Platform)
+	strField("webgl_vendor", &fp.WebGLVendor)
+	strField("webgl_renderer", &fp.WebGLRenderer)
+
+	for _, field := range []struct {
+		key      string
+		dst      *int
+		min, max int
+	}{
+		{"screen_width", &fp.ScreenWidth, 320, 7680},
+		{"screen_height", &fp.ScreenHeight, 240, 4320},
+		{"hardware_concurrency", &fp.HardwareConcurrency, 1, 128},
+	} {
+		if v, ok := args[field.key].(float64); ok {
+			if int(v) < field.min || int(v) > field.max {
+				return nil, fmt.Errorf("%s must be between %d and %d", field.key, field.min, field.max)
+			}
+			*field.dst = int(v)
+		}
+	}
+
+	if raw, ok := args["languages"].([]interface{}); ok {
+		fp.Languages = nil
+		for i, item := range raw {
+			lang, ok := item.(string)
+			if !ok || strings.TrimSpace(lang) == "" {
+				return nil, fmt.Errorf("languages[%d] must be a non-empty string", i)
+			}
+			fp.Languages = append(fp.Languages, strings.TrimSpace(lang))
+		}
+	}
+	if noise, ok := args["canvas_noise"].(bool); ok && !noise {
+		fp.NoiseSeed = 0
+	}
+
+	profile := &fingerprintProfile{Name: name, Fingerprint: fp, CreatedAt: time.Now().UTC()}
+	if raw, ok := args["url_patterns"].([]interface{}); ok {
+		for i, item := range raw {
+			pattern, ok := item.(string)
+			if !ok || strings.TrimSpace(pattern) == "" {
+				return nil, fmt.Errorf("url_patterns[%d] must be a non-empty string", i)
+			}
+			profile.URLPatterns = append(profile.URLPatterns, strings.TrimSpace(pattern))
+		}
+	}
+	return profile, nil
+}
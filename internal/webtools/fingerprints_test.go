@@ -0,0 +1,90 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateFingerprint(t *testing.T) {
+	fp, err := generateFingerprint("mac")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fp.Platform != "MacIntel" || !strings.Contains(fp.UserAgent, "Macintosh") || !strings.Contains(fp.WebGLRenderer, "Apple") {
+		t.Errorf("inconsistent mac fingerprint: %+v", fp)
+	}
+	if fp.NoiseSeed == 0 || fp.ScreenWidth == 0 || fp.HardwareConcurrency == 0 {
+		t.Errorf("incomplete fingerprint: %+v", fp)
+	}
+	if _, err := generateFingerprint("amiga"); err == nil {
+		t.Error("Expected error for unknown preset")
+	}
+}
+
+func TestFingerprintProfileFromArgs(t *testing.T) {
+	profile, err := fingerprintProfileFromArgs("shop", map[string]interface{}{
+		"preset":       "windows",
+		"languages":    []interface{}{"de-DE", "de"},
+		"screen_width": float64(1280),
+		"canvas_noise": false,
+		"url_patterns": []interface{}{"https://shop.example.com/*"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp := profile.Fingerprint
+	if fp.Platform != "Win32" || fp.Languages[0] != "de-DE" || fp.ScreenWidth != 1280 || fp.NoiseSeed != 0 {
+		t.Errorf("overrides not applied: %+v", fp)
+	}
+
+	if _, err := fingerprintProfileFromArgs("x", map[string]interface{}{"screen_width": float64(10)}); err == nil {
+		t.Error("Expected error for tiny screen")
+	}
+}
+
+func TestFingerprintProfileTool(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewFingerprintProfileTool(createTestLogger(t), nil, dir)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"action":       "create",
+		"name":         "shop",
+		"preset":       "linux",
+		"url_patterns": []interface{}{"https://shop.example.com/*"},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("create: %+v, %v", resp, err)
+	}
+	first, _ := newFingerprintStore(dir).load("shop")
+
+	// A second create must not silently change what sites have already seen
+	resp, _ = tool.Execute(map[string]interface{}{"action": "create", "name": "shop"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "overwrite") {
+		t.Errorf("Expected overwrite refusal, got %+v", resp)
+	}
+	again, _ := newFingerprintStore(dir).load("shop")
+	if again.Fingerprint.NoiseSeed != first.Fingerprint.NoiseSeed {
+		t.Error("Profile changed without overwrite")
+	}
+
+	store := newFingerprintStore(dir)
+	if found, _ := store.findForURL("https://shop.example.com/cart"); found == nil || found.Name != "shop" {
+		t.Errorf("findForURL = %+v", found)
+	}
+	if found, _ := store.findForURL("https://other.example.com/"); found != nil {
+		t.Errorf("Unexpected match %+v", found)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"action": "list"})
+	if resp.IsError || resp.Content[0].Text != "1 fingerprint profiles" {
+		t.Errorf("list = %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "delete", "name": "shop"})
+	if resp.IsError || store.exists("shop") {
+		t.Errorf("delete = %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "get", "name": "../shop"})
+	if !resp.IsError {
+		t.Error("Expected error for path-like name")
+	}
+}
@@ -0,0 +1,255 @@
+package webtools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// defaultTabOrderLimit is how many elements tab_order lists when no limit
+// is given
+const defaultTabOrderLimit = 100
+
+// describeFocusTarget names an element the way a keyboard test report
+// would, e.g. `button "Sign in" (#login)`
+func describeFocusTarget(target browser.FocusTarget) string {
+	kind := target.Tag
+	if target.Role != "" {
+		kind = target.Role
+	}
+	text := kind
+	if target.Label != "" {
+		text += fmt.Sprintf(" %q", target.Label)
+	}
+	text += " (" + target.Selector + ")"
+	if !target.Visible {
+		text += " [not visible]"
+	}
+	return text
+}
+
+// FocusElementTool moves keyboard focus to an element
+type FocusElementTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewFocusElementTool(log *logger.Logger, mgr *browser.Manager) *FocusElementTool {
+	return &FocusElementTool{logger: log, browserMgr: mgr}
+}
+
+func (t *FocusElementTool) Name() string {
+	return "focus_element"
+}
+
+func (t *FocusElementTool) Description() string {
+	return "Move keyboard focus to an element, as a starting point for keyboard_shortcuts or keyboard accessibility checks. Fails if the element cannot take focus"
+}
+
+func (t *FocusElementTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Element to focus. " + selectorSyntax,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+		Required: []string{"selector"},
+	}
+}
+
+func (t *FocusElementTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *FocusElementTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		selector, _ := args["selector"].(string)
+		if strings.TrimSpace(selector) == "" {
+			return fail("selector is required")
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		if err := t.browserMgr.FocusContext(ctx, pageID, selector, browser.ElementTimeout); err != nil {
+			return fail(fmt.Sprintf("Failed to focus: %v", err))
+		}
+		data := map[string]interface{}{"selector": selector, "page_id": pageID}
+		text := fmt.Sprintf("Focused %s", selector)
+		if target, err := t.browserMgr.FocusedElement(pageID); err == nil && target != nil {
+			data["focused"] = target
+			text = "Focused " + describeFocusTarget(*target)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// GetFocusedElementTool reports which element has keyboard focus
+type GetFocusedElementTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewGetFocusedElementTool(log *logger.Logger, mgr *browser.Manager) *GetFocusedElementTool {
+	return &GetFocusedElementTool{logger: log, browserMgr: mgr}
+}
+
+func (t *GetFocusedElementTool) Name() string {
+	return "get_focused_element"
+}
+
+func (t *GetFocusedElementTool) Description() string {
+	return "Report the element that has keyboard focus, looking inside open shadow roots and same-origin frames: a selector for it, its tag, role, accessible label and tabindex"
+}
+
+func (t *GetFocusedElementTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *GetFocusedElementTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		target, err := t.browserMgr.FocusedElement(pageID)
+		t.logger.LogToolExecution(t.Name(), args, err == nil, time.Since(start).Milliseconds())
+		if err != nil {
+			return recipeErrorResponse(fmt.Sprintf("Failed to read focused element: %v", err)), nil
+		}
+
+		data := map[string]interface{}{"page_id": pageID, "focused": target != nil}
+		text := "Nothing is focused; focus is on the page itself"
+		if target != nil {
+			data["element"] = target
+			text = "Focused: " + describeFocusTarget(*target)
+		}
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// TabOrderTool lists the elements the Tab key visits, in order
+type TabOrderTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewTabOrderTool(log *logger.Logger, mgr *browser.Manager) *TabOrderTool {
+	return &TabOrderTool{logger: log, browserMgr: mgr}
+}
+
+func (t *TabOrderTool) Name() string {
+	return "tab_order"
+}
+
+func (t *TabOrderTool) Description() string {
+	return "List the focusable elements in the order the Tab key visits them (positive tabindex first, then document order, including open shadow roots), skipping disabled, hidden and inert ones. Does not move focus"
+}
+
+func (t *TabOrderTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most elements to list",
+				"default":     defaultTabOrderLimit,
+				"minimum":     1,
+				"maximum":     browser.MaxTabOrder,
+			},
+		},
+	}
+}
+
+func (t *TabOrderTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		limit := defaultTabOrderLimit
+		if v, ok := args["limit"].(float64); ok {
+			if v < 1 || v > browser.MaxTabOrder || v != float64(int(v)) {
+				return fail(fmt.Sprintf("limit must be a whole number between 1 and %d", browser.MaxTabOrder))
+			}
+			limit = int(v)
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		elements, total, err := t.browserMgr.TabOrder(pageID, limit)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read tab order: %v", err))
+		}
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "%d focusable elements in tab order", total)
+		if total > len(elements) {
+			fmt.Fprintf(&text, " (showing the first %d)", len(elements))
+		}
+		for i, el := range elements {
+			fmt.Fprintf(&text, "\n%d. %s", i+1, describeFocusTarget(el))
+			if el.TabIndex > 0 {
+				fmt.Fprintf(&text, " tabindex=%d", el.TabIndex)
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text.String(),
+				Data: map[string]interface{}{
+					"page_id":   pageID,
+					"total":     total,
+					"elements":  elements,
+					"truncated": total > len(elements),
+				},
+			}},
+		}, nil
+	})
+}
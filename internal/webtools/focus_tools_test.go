@@ -0,0 +1,53 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+	"rodmcp/pkg/types"
+)
+
+func TestDescribeFocusTarget(t *testing.T) {
+	tests := []struct {
+		target browser.FocusTarget
+		want   string
+	}{
+		{browser.FocusTarget{Selector: "#login", Tag: "button", Label: "Sign in", Visible: true}, `button "Sign in" (#login)`},
+		{browser.FocusTarget{Selector: "div.menu", Tag: "div", Role: "menuitem", Visible: true}, `menuitem (div.menu)`},
+		{browser.FocusTarget{Selector: "a.skip", Tag: "a", Label: "Skip to content"}, `a "Skip to content" (a.skip) [not visible]`},
+	}
+	for _, tt := range tests {
+		if got := describeFocusTarget(tt.target); got != tt.want {
+			t.Errorf("describeFocusTarget(%+v) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestFocusToolsValidation(t *testing.T) {
+	log := createTestLogger(t)
+	mgr := browser.NewManager(log, browser.Config{Headless: true})
+
+	cases := []struct {
+		name string
+		tool interface {
+			Execute(map[string]interface{}) (*types.CallToolResponse, error)
+		}
+		args map[string]interface{}
+		want string
+	}{
+		{"focus without selector", NewFocusElementTool(log, mgr), map[string]interface{}{}, "selector is required"},
+		{"focus without pages", NewFocusElementTool(log, mgr), map[string]interface{}{"selector": "#q"}, "No browser pages"},
+		{"focused without pages", NewGetFocusedElementTool(log, mgr), map[string]interface{}{}, "No browser pages"},
+		{"tab order bad limit", NewTabOrderTool(log, mgr), map[string]interface{}{"limit": float64(0)}, "limit must be"},
+		{"tab order without pages", NewTabOrderTool(log, mgr), map[string]interface{}{}, "No browser pages"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
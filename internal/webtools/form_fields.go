@@ -0,0 +1,419 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// formFieldTarget is where a form_fill field key resolved to
+type formFieldTarget struct {
+	Selector  string `json:"selector"`
+	MatchedBy string `json:"matched_by"` // selector, name, id, label, aria-label or placeholder
+	Found     bool   `json:"found"`
+}
+
+// resolveFieldsScript maps each key to a field in the form. Keys are tried as
+// CSS selectors first, then as name/id attributes, then matched against label
+// text, aria-label and placeholder, so callers can address fields the way
+// they appear on screen. Fields without an id are tagged with a data
+// attribute so the returned selector is unique.
+const resolveFieldsScript = `
+	const formSelector = %s;
+	const keys = %s;
+	const form = document.querySelector(formSelector) || document;
+	const controls = Array.from(form.querySelectorAll('input, select, textarea, [contenteditable=""], [contenteditable="true"]'))
+		.filter(el => !['hidden', 'submit', 'button', 'reset', 'image'].includes((el.type || '').toLowerCase()));
+
+	const normalize = s => (s || '').replace(/\s+/g, ' ').replace(/[*:]+\s*$/, '').trim().toLowerCase();
+
+	const labelText = el => {
+		const texts = [];
+		if (el.labels) {
+			for (const label of el.labels) texts.push(label.textContent);
+		}
+		const labelledBy = el.getAttribute('aria-labelledby');
+		if (labelledBy) {
+			for (const id of labelledBy.split(/\s+/)) {
+				const ref = document.getElementById(id);
+				if (ref) texts.push(ref.textContent);
+			}
+		}
+		return texts.map(normalize).filter(Boolean);
+	};
+
+	let tagged = document.querySelectorAll('[data-rodmcp-field]').length;
+	const selectorFor = el => {
+		if (el.id && document.querySelectorAll('#' + CSS.escape(el.id)).length === 1) {
+			return '#' + CSS.escape(el.id);
+		}
+		let tag = el.getAttribute('data-rodmcp-field');
+		if (!tag) {
+			tag = 'f' + (++tagged);
+			el.setAttribute('data-rodmcp-field', tag);
+		}
+		return '[data-rodmcp-field="' + tag + '"]';
+	};
+
+	const find = key => {
+		try {
+			const el = form.querySelector(key) || document.querySelector(key);
+			if (el) return { selector: key, matched_by: 'selector', found: true };
+		} catch (e) {
+			// Not a valid CSS selector - treat it as a name or label
+		}
+
+		const wanted = normalize(key);
+		const strategies = [
+			['name', el => el.name === key],
+			['id', el => el.id === key],
+			['label', el => labelText(el).includes(wanted)],
+			['aria-label', el => normalize(el.getAttribute('aria-label')) === wanted],
+			['placeholder', el => normalize(el.getAttribute('placeholder')) === wanted],
+			['label', el => labelText(el).some(text => text.startsWith(wanted) || wanted.startsWith(text))],
+		];
+		for (const [matchedBy, test] of strategies) {
+			const el = controls.find(test);
+			if (el) return { selector: selectorFor(el), matched_by: matchedBy, found: true };
+		}
+		return { selector: key, matched_by: '', found: false };
+	};
+
+	const result = {};
+	for (const key of keys) result[key] = find(key);
+	return result;
+`
+
+// resolveFormFields works out the selector for each field key of form_fill
+func (t *FormFillTool) resolveFormFields(pageID, formSelector string, keys []string) (map[string]formFieldTarget, error) {
+	formJSON, _ := json.Marshal(formSelector)
+	keysJSON, _ := json.Marshal(keys)
+	data, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(resolveFieldsScript, formJSON, keysJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve form fields: %w", err)
+	}
+
+	targets := make(map[string]formFieldTarget, len(keys))
+	if err := decodeScriptValue(data, &targets); err != nil {
+		return nil, fmt.Errorf("failed to read resolved form fields: %w", err)
+	}
+	return targets, nil
+}
+
+// decodeScriptValue converts a value returned by ExecuteScript into v
+func decodeScriptValue(data interface{}, v interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// sortedFieldKeys returns the keys of a fields map in a stable order
+func sortedFieldKeys(fields map[string]interface{}) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Form fill input modes
+const (
+	inputModeSet  = "set"  // assign the value directly and fire events
+	inputModeType = "type" // send real key events, for masked or controlled inputs
+)
+
+// maxTypingDelayMs bounds the per-keystroke delay in typing mode
+const maxTypingDelayMs = 200
+
+// describeFieldScript reports whether a field accepts typed text
+const describeFieldScript = `
+	const formSelector = %s;
+	const selector = %s;
+	const form = document.querySelector(formSelector);
+	const el = (form && form.querySelector(selector)) || document.querySelector(selector);
+	if (!el) {
+		throw new Error('Field not found with selector: ' + selector);
+	}
+	const tag = el.tagName.toLowerCase();
+	const type = (el.type || '').toLowerCase();
+	const textTypes = ['', 'text', 'email', 'password', 'search', 'tel', 'url', 'number'];
+	return {
+		tagName: tag,
+		type: type,
+		typeable: (tag === 'input' && textTypes.includes(type)) || tag === 'textarea' || el.isContentEditable,
+	};
+`
+
+// fireFieldEventsScript dispatches change and blur after typing, which real
+// keystrokes alone don't trigger until focus moves
+const fireFieldEventsScript = `
+	const formSelector = %s;
+	const selector = %s;
+	const form = document.querySelector(formSelector);
+	const element = (form && form.querySelector(selector)) || document.querySelector(selector);
+	if (element) {
+		element.dispatchEvent(new Event('change', { bubbles: true }));
+		element.dispatchEvent(new Event('blur', { bubbles: true }));
+	}
+	return true;
+`
+
+// typeField enters a value with real key events. Fields that can't take
+// typed text (selects, checkboxes, radios) fall back to direct assignment.
+func (t *FormFillTool) typeField(pageID, formSelector, fieldSelector string, value interface{}, triggerEvents bool, delay time.Duration) (map[string]interface{}, error) {
+	text, isText := value.(string)
+	if num, ok := value.(float64); ok {
+		text, isText = fmt.Sprintf("%v", num), true
+	}
+	if !isText {
+		return t.fillSingleField(pageID, formSelector, fieldSelector, value, triggerEvents)
+	}
+
+	formJSON, _ := json.Marshal(formSelector)
+	selectorJSON, _ := json.Marshal(fieldSelector)
+	data, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(describeFieldScript, formJSON, selectorJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect field: %w", err)
+	}
+	var info struct {
+		TagName  string `json:"tagName"`
+		Type     string `json:"type"`
+		Typeable bool   `json:"typeable"`
+	}
+	if err := decodeScriptValue(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to inspect field: %w", err)
+	}
+	if !info.Typeable {
+		return t.fillSingleField(pageID, formSelector, fieldSelector, value, triggerEvents)
+	}
+
+	if err := t.browserMgr.TypeIntoElement(pageID, fieldSelector, text, true, delay); err != nil {
+		return nil, err
+	}
+	if triggerEvents {
+		if _, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(fireFieldEventsScript, formJSON, selectorJSON)); err != nil {
+			return nil, fmt.Errorf("failed to fire change events: %w", err)
+		}
+	}
+
+	return map[string]interface{}{
+		"selector":  fieldSelector,
+		"tagName":   info.TagName,
+		"type":      info.Type,
+		"value":     text,
+		"valueType": "string",
+		"success":   true,
+		"method":    "typed",
+	}, nil
+}
+
+// parseFieldModes reads input_mode and field_modes, returning the default
+// mode and any per-field overrides
+func parseFieldModes(args map[string]interface{}) (string, map[string]string, error) {
+	mode := inputModeSet
+	if val, ok := args["input_mode"].(string); ok && val != "" {
+		mode = val
+	}
+	if mode != inputModeSet && mode != inputModeType {
+		return "", nil, fmt.Errorf("input_mode must be %q or %q", inputModeSet, inputModeType)
+	}
+
+	overrides := map[string]string{}
+	if raw, ok := args["field_modes"].(map[string]interface{}); ok {
+		for key, val := range raw {
+			m, _ := val.(string)
+			if m != inputModeSet && m != inputModeType {
+				return "", nil, fmt.Errorf("field_modes[%s] must be %q or %q", key, inputModeSet, inputModeType)
+			}
+			overrides[key] = m
+		}
+	}
+	return mode, overrides, nil
+}
+
+// filledField records what was written to a field so it can be verified
+type filledField struct {
+	Key      string      `json:"key"`
+	Selector string      `json:"selector"`
+	Expected interface{} `json:"expected"`
+}
+
+// fieldVerification compares a field's final value with what was requested
+type fieldVerification struct {
+	Field             string      `json:"field"`
+	Expected          interface{} `json:"expected"`
+	Actual            interface{} `json:"actual"`
+	Matches           bool        `json:"matches"`
+	Formatted         bool        `json:"formatted,omitempty"` // same characters, reformatted by an input mask
+	Valid             bool        `json:"valid"`
+	ValidationMessage string      `json:"validation_message,omitempty"`
+}
+
+// readFieldsScript reads back the final value and validity of filled fields
+const readFieldsScript = `
+	const formSelector = %s;
+	const fields = %s;
+	const form = document.querySelector(formSelector) || document;
+	return fields.map(f => {
+		let el = null;
+		try {
+			el = form.querySelector(f.selector) || document.querySelector(f.selector);
+		} catch (e) {}
+		if (!el) {
+			return { key: f.key, found: false };
+		}
+		const tag = el.tagName.toLowerCase();
+		const type = (el.type || '').toLowerCase();
+		let actual;
+		if (type === 'checkbox' || type === 'radio') {
+			actual = el.checked;
+		} else if ('value' in el) {
+			actual = el.value;
+		} else {
+			actual = el.textContent;
+		}
+		const selectedText = tag === 'select' && el.selectedIndex >= 0 ? el.options[el.selectedIndex].text : '';
+		return {
+			key: f.key,
+			found: true,
+			actual: actual,
+			selected_text: selectedText,
+			valid: el.validity ? el.validity.valid : true,
+			validation_message: el.validationMessage || '',
+		};
+	});
+`
+
+// verifyFields reads each filled field back and compares it with the intended value
+func (t *FormFillTool) verifyFields(pageID, formSelector string, filled []filledField) ([]fieldVerification, error) {
+	if len(filled) == 0 {
+		return nil, nil
+	}
+	formJSON, _ := json.Marshal(formSelector)
+	fieldsJSON, _ := json.Marshal(filled)
+	data, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(readFieldsScript, formJSON, fieldsJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read back field values: %w", err)
+	}
+
+	var states []struct {
+		Key               string      `json:"key"`
+		Found             bool        `json:"found"`
+		Actual            interface{} `json:"actual"`
+		SelectedText      string      `json:"selected_text"`
+		Valid             bool        `json:"valid"`
+		ValidationMessage string      `json:"validation_message"`
+	}
+	if err := decodeScriptValue(data, &states); err != nil {
+		return nil, fmt.Errorf("failed to read back field values: %w", err)
+	}
+
+	expected := make(map[string]interface{}, len(filled))
+	for _, f := range filled {
+		expected[f.Key] = f.Expected
+	}
+
+	results := make([]fieldVerification, 0, len(states))
+	for _, state := range states {
+		v := fieldVerification{
+			Field:             state.Key,
+			Expected:          expected[state.Key],
+			Actual:            state.Actual,
+			Valid:             state.Valid,
+			ValidationMessage: state.ValidationMessage,
+		}
+		if state.Found {
+			v.Matches, v.Formatted = fieldValueMatches(v.Expected, state.Actual, state.SelectedText)
+		} else {
+			v.ValidationMessage = "field no longer present"
+		}
+		results = append(results, v)
+	}
+	return results, nil
+}
+
+// fieldValueMatches reports whether a field's final value reflects the
+// requested one. formatted is set when the value only matches after ignoring
+// punctuation and spacing an input mask may have added, e.g. a phone number.
+func fieldValueMatches(expected, actual interface{}, selectedText string) (matches, formatted bool) {
+	// Checkboxes and radios report checked state; form_fill sets it from the
+	// value's truthiness, as JavaScript's Boolean() would
+	if got, ok := actual.(bool); ok {
+		want := false
+		switch v := expected.(type) {
+		case bool:
+			want = v
+		case string:
+			want = v != ""
+		case float64:
+			want = v != 0
+		}
+		return want == got, false
+	}
+
+	want := fmt.Sprintf("%v", expected)
+	got := fmt.Sprintf("%v", actual)
+	if want == got || (selectedText != "" && strings.EqualFold(strings.TrimSpace(selectedText), strings.TrimSpace(want))) {
+		return true, false
+	}
+
+	alnum := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, s)
+	}
+	if a := alnum(want); a != "" && a == alnum(got) {
+		return true, true
+	}
+	return false, false
+}
+
+// validityError is a browser-native constraint validation failure
+type validityError struct {
+	Field    string `json:"field"`
+	Selector string `json:"selector"`
+	Message  string `json:"message"`
+	// ValueMissing marks a required field left empty, which
+	// validate_required already reports
+	ValueMissing bool `json:"value_missing"`
+}
+
+// validityScript collects constraint validation messages for the whole form
+const validityScript = `
+	const form = document.querySelector(%s);
+	if (!form) {
+		return [];
+	}
+	const elements = form.elements ? Array.from(form.elements) : Array.from(form.querySelectorAll('input, select, textarea'));
+	return elements
+		.filter(el => el.willValidate && el.validity && !el.validity.valid)
+		.map(el => ({
+			value_missing: el.validity.valueMissing,
+			field: el.name || el.id || el.tagName.toLowerCase(),
+			selector: el.id ? '#' + CSS.escape(el.id) : (el.name ? el.tagName.toLowerCase() + '[name="' + el.name + '"]' : ''),
+			message: el.validationMessage,
+		}));
+`
+
+// validityErrors reports fields that fail the browser's constraint validation
+func (t *FormFillTool) validityErrors(pageID, formSelector string) ([]validityError, error) {
+	formJSON, _ := json.Marshal(formSelector)
+	data, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(validityScript, formJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check form validity: %w", err)
+	}
+	var errs []validityError
+	if err := decodeScriptValue(data, &errs); err != nil {
+		return nil, fmt.Errorf("failed to check form validity: %w", err)
+	}
+	return errs, nil
+}
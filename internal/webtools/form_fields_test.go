@@ -0,0 +1,75 @@
+package webtools
+
+import (
+	"rodmcp/internal/browser"
+	"strings"
+	"testing"
+)
+
+func TestFieldValueMatches(t *testing.T) {
+	cases := []struct {
+		name          string
+		expected      interface{}
+		actual        interface{}
+		selectedText  string
+		wantMatch     bool
+		wantFormatted bool
+	}{
+		{"exact text", "jane@example.com", "jane@example.com", "", true, false},
+		{"different text", "jane", "john", "", false, false},
+		{"masked phone", "5551234567", "(555) 123-4567", "", true, true},
+		{"number", float64(42), "42", "", true, false},
+		{"checkbox checked", true, true, "", true, false},
+		{"checkbox not checked", true, false, "", false, false},
+		{"checkbox from string", "yes", true, "", true, false},
+		{"select by option text", "United States", "US", "United States", true, false},
+		{"empty field", "hello", "", "", false, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			match, formatted := fieldValueMatches(tc.expected, tc.actual, tc.selectedText)
+			if match != tc.wantMatch || formatted != tc.wantFormatted {
+				t.Errorf("fieldValueMatches(%v, %v) = %v, %v; want %v, %v", tc.expected, tc.actual, match, formatted, tc.wantMatch, tc.wantFormatted)
+			}
+		})
+	}
+}
+
+func TestParseFieldModes(t *testing.T) {
+	mode, overrides, err := parseFieldModes(map[string]interface{}{})
+	if err != nil || mode != inputModeSet || len(overrides) != 0 {
+		t.Errorf("Expected default set mode, got %q %v %v", mode, overrides, err)
+	}
+
+	mode, overrides, err = parseFieldModes(map[string]interface{}{
+		"input_mode":  "type",
+		"field_modes": map[string]interface{}{"Country": "set"},
+	})
+	if err != nil || mode != inputModeType || overrides["Country"] != inputModeSet {
+		t.Errorf("Expected type mode with Country override, got %q %v %v", mode, overrides, err)
+	}
+
+	if _, _, err := parseFieldModes(map[string]interface{}{"input_mode": "paste"}); err == nil {
+		t.Error("Expected error for unknown input_mode")
+	}
+	if _, _, err := parseFieldModes(map[string]interface{}{"field_modes": map[string]interface{}{"Name": 1.0}}); err == nil {
+		t.Error("Expected error for non-string field mode")
+	}
+}
+
+func TestFormFillStepsValidation(t *testing.T) {
+	log := createTestLogger(t)
+	mgr := browser.NewManager(log, browser.Config{Headless: true})
+	tool := NewFormFillTool(log, mgr)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"steps": []interface{}{map[string]interface{}{"fields": map[string]interface{}{"#a": "b"}}},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
@@ -0,0 +1,235 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/pkg/types"
+)
+
+// defaultStepTimeout is how long a wizard step waits for the next page
+const defaultStepTimeout = 10 * time.Second
+
+// stepSettleDelay gives a clicked next button time to start navigation or
+// re-render before readiness is checked, so the old step isn't mistaken for
+// the new one
+const stepSettleDelay = 300 * time.Millisecond
+
+// formStepOptions are the form_fill arguments a step inherits from the call
+// unless it overrides them
+var formStepOptions = []string{
+	"form_selector", "validate_required", "trigger_events", "input_mode",
+	"field_modes", "typing_delay_ms", "verify_values", "report_validity",
+}
+
+// formStepSchema describes one entry of the steps argument
+func formStepSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"description": "Fill a multi-page form or wizard in one call. Each step fills its fields, clicks next_selector, and waits for the next step before continuing. Steps inherit form_selector, input_mode and the other options unless they set their own",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"fields": map[string]interface{}{
+					"type":        "object",
+					"description": "Fields to fill on this step, keyed like the top-level fields",
+				},
+				"form_selector": map[string]interface{}{
+					"type":        "string",
+					"description": "Form for this step (defaults to the top-level form_selector)",
+				},
+				"next_selector": map[string]interface{}{
+					"type":        "string",
+					"description": "Button or link to click after filling, e.g. a Next or Continue button",
+				},
+				"wait_for": map[string]interface{}{
+					"type":        "string",
+					"description": "Selector that becomes visible once the next step has loaded (defaults to the next step's form)",
+				},
+				"submit": map[string]interface{}{
+					"type":        "boolean",
+					"description": "Submit the form after this step instead of clicking next_selector",
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": "Seconds to wait for the next step to appear (default: 10)",
+				},
+			},
+		},
+	}
+}
+
+// executeFormSteps runs form_fill across the pages of a wizard, stopping at
+// the first step that fails
+func (t *FormFillTool) executeFormSteps(args map[string]interface{}, steps []interface{}) (*types.CallToolResponse, error) {
+	start := time.Now()
+
+	pageID, _ := args["page_id"].(string)
+	if pageID == "" {
+		pages := t.browserMgr.ListPages()
+		if len(pages) == 0 {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		pageID = pages[0]
+	}
+
+	stepArgs := make([]map[string]interface{}, 0, len(steps))
+	for i, raw := range steps {
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("steps[%d] must be an object", i)
+		}
+		merged := map[string]interface{}{"page_id": pageID}
+		for _, key := range formStepOptions {
+			if val, ok := args[key]; ok {
+				merged[key] = val
+			}
+		}
+		for key, val := range step {
+			merged[key] = val
+		}
+		stepArgs = append(stepArgs, merged)
+	}
+
+	var results []map[string]interface{}
+	failed := false
+	for i, step := range stepArgs {
+		stepStart := time.Now()
+		result := map[string]interface{}{"step": i + 1}
+		results = append(results, result)
+
+		if err := t.runFormStep(pageID, step, stepArgs, i, result); err != nil {
+			result["error"] = err.Error()
+			failed = true
+		}
+		result["duration_ms"] = time.Since(stepStart).Milliseconds()
+		if failed {
+			break
+		}
+	}
+
+	completed := len(results)
+	if failed {
+		completed--
+	}
+	t.logger.LogToolExecution(t.Name(), args, !failed, time.Since(start).Milliseconds())
+
+	text := fmt.Sprintf("Form wizard completed: %d of %d steps", completed, len(stepArgs))
+	if failed {
+		text = fmt.Sprintf("Form wizard stopped at step %d of %d: %v", len(results), len(stepArgs), results[len(results)-1]["error"])
+	}
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text,
+			Data: map[string]interface{}{
+				"steps":           results,
+				"steps_total":     len(stepArgs),
+				"steps_completed": completed,
+				"page_id":         pageID,
+			},
+		}},
+		IsError: failed,
+	}, nil
+}
+
+// runFormStep fills one wizard step, clicks through to the next and waits for
+// it, recording what happened in result
+func (t *FormFillTool) runFormStep(pageID string, step map[string]interface{}, all []map[string]interface{}, index int, result map[string]interface{}) error {
+	if fields, ok := step["fields"].(map[string]interface{}); ok && len(fields) > 0 {
+		resp, err := t.executeFormFill(step)
+		if err != nil {
+			return err
+		}
+		if len(resp.Content) > 0 {
+			result["summary"] = resp.Content[0].Text
+			result["fill"] = resp.Content[0].Data
+		}
+		if resp.IsError {
+			return fmt.Errorf("%s", result["summary"])
+		}
+	}
+
+	next, _ := step["next_selector"].(string)
+	if next == "" {
+		return nil
+	}
+	if submit, _ := step["submit"].(bool); submit {
+		return fmt.Errorf("a step can't both submit and click next_selector")
+	}
+
+	if err := t.clickStepButton(pageID, next); err != nil {
+		return err
+	}
+	result["clicked"] = next
+
+	waitFor, _ := step["wait_for"].(string)
+	if waitFor == "" && index+1 < len(all) {
+		waitFor, _ = all[index+1]["form_selector"].(string)
+		if waitFor == "" {
+			waitFor = "form"
+		}
+	}
+	if waitFor == "" {
+		return nil
+	}
+
+	timeout := defaultStepTimeout
+	if val, ok := step["timeout"].(float64); ok && val > 0 {
+		timeout = time.Duration(val * float64(time.Second))
+	}
+	if err := t.waitForStep(pageID, waitFor, timeout); err != nil {
+		return err
+	}
+	result["waited_for"] = waitFor
+	return nil
+}
+
+// clickStepButton clicks a wizard's next button
+func (t *FormFillTool) clickStepButton(pageID, selector string) error {
+	selectorJSON, _ := json.Marshal(selector)
+	script := fmt.Sprintf(`
+		const element = document.querySelector(%s);
+		if (!element) {
+			throw new Error('Next button not found with selector: ' + %s);
+		}
+		element.click();
+		return true;
+	`, selectorJSON, selectorJSON)
+	if _, err := t.browserMgr.ExecuteScript(pageID, script); err != nil {
+		return fmt.Errorf("failed to click %s: %w", selector, err)
+	}
+	return nil
+}
+
+// waitForStep waits until the page has finished loading and selector is
+// visible. Script errors while the page navigates are expected and retried.
+func (t *FormFillTool) waitForStep(pageID, selector string, timeout time.Duration) error {
+	time.Sleep(stepSettleDelay)
+
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		ready, err := t.browserMgr.ExecuteScript(pageID, `document.readyState`)
+		if err == nil && strings.Trim(fmt.Sprint(ready), `"`) == "complete" {
+			count, countErr := countElements(t.browserMgr, pageID, selector, true)
+			if countErr == nil && count > 0 {
+				return nil
+			}
+			err = countErr
+		}
+		if err != nil {
+			lastErr = err
+		}
+		if !time.Now().Before(deadline) {
+			if lastErr != nil {
+				return fmt.Errorf("next step (%s) did not appear within %v: %v", selector, timeout, lastErr)
+			}
+			return fmt.Errorf("next step (%s) did not appear within %v", selector, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
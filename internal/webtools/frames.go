@@ -0,0 +1,38 @@
+package webtools
+
+import (
+	"fmt"
+
+	"rodmcp/internal/browser"
+)
+
+// frameSelectorSchema describes the frame_selector argument of tools that
+// can reach into iframes
+func frameSelectorSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Work inside an iframe instead of the top page: the iframe element's selector, with >> between the steps of nested frames. Each step takes the same selector forms as selector",
+		"examples":    []string{"iframe#checkout", "#checkout >> iframe[title='Card number']", "//iframe[contains(@src, 'embed')]"},
+	}
+}
+
+// parseFrameSelector reads the optional frame_selector argument; nil is the
+// top page
+func parseFrameSelector(args map[string]interface{}) (browser.FramePath, error) {
+	raw, ok := args["frame_selector"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	text, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("frame_selector must be a string")
+	}
+	return browser.ParseFramePath(text)
+}
+
+// addFrameData records the frame a tool worked in on its response data
+func addFrameData(data map[string]interface{}, frames browser.FramePath) {
+	if len(frames) > 0 {
+		data["frame_selector"] = frames.String()
+	}
+}
@@ -0,0 +1,69 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/pkg/types"
+)
+
+func TestParseFrameSelector(t *testing.T) {
+	frames, err := parseFrameSelector(map[string]interface{}{"frame_selector": "#outer >> iframe.inner"})
+	if err != nil || len(frames) != 2 || frames[1] != "iframe.inner" {
+		t.Fatalf("frames = %#v, %v", frames, err)
+	}
+	data := map[string]interface{}{}
+	addFrameData(data, frames)
+	if data["frame_selector"] != "#outer >> iframe.inner" {
+		t.Errorf("frame data = %v", data)
+	}
+
+	if frames, err := parseFrameSelector(map[string]interface{}{}); frames != nil || err != nil {
+		t.Errorf("no frame_selector: %v %v", frames, err)
+	}
+	if _, err := parseFrameSelector(map[string]interface{}{"frame_selector": 3.0}); err == nil {
+		t.Error("non-string frame_selector accepted")
+	}
+}
+
+func TestFrameToolsRejectBadFrameSelector(t *testing.T) {
+	log := createTestLogger(t)
+	tools := []interface {
+		Name() string
+		InputSchema() types.ToolSchema
+		Execute(map[string]interface{}) (*types.CallToolResponse, error)
+	}{
+		NewClickElementTool(log, nil),
+		NewTypeTextTool(log, nil),
+		NewGetElementTextTool(log, nil),
+		NewAssertElementTool(log, nil),
+		NewScreenScrapeTool(log, nil),
+	}
+	args := map[string]interface{}{
+		"selector":       "#a",
+		"text":           "x",
+		"assertion":      "exists",
+		"selectors":      map[string]interface{}{"a": "#a"},
+		"page_id":        "page",
+		"frame_selector": "iframe >> ",
+	}
+	for _, tool := range tools {
+		if _, ok := tool.InputSchema().Properties["frame_selector"]; !ok {
+			t.Errorf("%s has no frame_selector property", tool.Name())
+		}
+		resp, err := tool.Execute(args)
+		if err == nil && (resp == nil || !resp.IsError) {
+			t.Errorf("%s accepted a bad frame_selector", tool.Name())
+			continue
+		}
+		msg := ""
+		if err != nil {
+			msg = err.Error()
+		} else {
+			msg = resp.Content[0].Text
+		}
+		if !strings.Contains(msg, "frame selector") {
+			t.Errorf("%s: error %q does not mention the frame selector", tool.Name(), msg)
+		}
+	}
+}
@@ -0,0 +1,743 @@
+package webtools
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	gitdiff "github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"go.uber.org/zap"
+)
+
+const (
+	diffContextLines = 3
+	maxDiffBytes     = 256 * 1024
+	gitToolTimeout   = 30 * time.Second
+)
+
+// runGitWithTimeout runs a git operation with panic recovery and the standard tool timeout
+func runGitWithTimeout(fn func() (*types.CallToolResponse, error)) (*types.CallToolResponse, error) {
+	type result struct {
+		resp *types.CallToolResponse
+		err  error
+	}
+	resultChan := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultChan <- result{err: fmt.Errorf("git operation panicked: %v", r)}
+			}
+		}()
+		resp, err := fn()
+		resultChan <- result{resp: resp, err: err}
+	}()
+
+	select {
+	case res := <-resultChan:
+		return res.resp, res.err
+	case <-time.After(gitToolTimeout):
+		return nil, fmt.Errorf("git operation timed out after %v", gitToolTimeout)
+	}
+}
+
+// gitRepoSchema holds the repository location parameters shared by the git tools
+func gitRepoSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"path": map[string]interface{}{
+			"type":        "string",
+			"description": "Directory inside the repository (the repository root is found by walking up)",
+			"default":     ".",
+		},
+		"cwd": map[string]interface{}{
+			"type":        "string",
+			"description": "Working directory that relative paths are resolved against (must be within allowed paths)",
+		},
+	}
+}
+
+// openGitRepo opens the repository containing the given path, requiring both the
+// path and the repository root to be within the allowed paths
+func openGitRepo(validator *PathValidator, args map[string]interface{}, operation string) (*git.Repository, string, error) {
+	pathStr := "."
+	if val, ok := args["path"].(string); ok && val != "" {
+		pathStr = val
+	}
+	cwd, _ := args["cwd"].(string)
+
+	cleanPath, err := validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, "", fmt.Errorf("repository access denied: %w", err)
+	}
+	if err := validator.ValidatePath(cleanPath, operation); err != nil {
+		return nil, "", fmt.Errorf("repository access denied: %w", err)
+	}
+
+	repo, err := git.PlainOpenWithOptions(cleanPath, &git.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		if errors.Is(err, git.ErrRepositoryNotExists) {
+			return nil, "", fmt.Errorf("%s is not inside a git repository", cleanPath)
+		}
+		return nil, "", fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, "", fmt.Errorf("repository has no worktree: %w", err)
+	}
+	root := wt.Filesystem.Root()
+	if err := validator.ValidatePath(root, operation); err != nil {
+		return nil, "", fmt.Errorf("repository root access denied: %w", err)
+	}
+
+	return repo, root, nil
+}
+
+// gitErrorResponse builds the standard error result for the git tools
+func gitErrorResponse(err error) *types.CallToolResponse {
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Git error: %v", err),
+		}},
+		IsError: true,
+	}
+}
+
+// headBranch returns the short branch name, or a description for detached/empty repos
+func headBranch(repo *git.Repository) (string, *plumbing.Reference) {
+	head, err := repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "(no commits yet)", nil
+		}
+		return "(unknown)", nil
+	}
+	if head.Name().IsBranch() {
+		return head.Name().Short(), head
+	}
+	return "(detached at " + head.Hash().String()[:7] + ")", head
+}
+
+// GitStatusTool reports working tree status for a repository under an allowed path
+type GitStatusTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewGitStatusTool(log *logger.Logger, validator *PathValidator) *GitStatusTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &GitStatusTool{logger: log, validator: validator}
+}
+
+func (t *GitStatusTool) Name() string {
+	return "git_status"
+}
+
+func (t *GitStatusTool) Description() string {
+	return "Show the branch and changed files (staged, unstaged, untracked) of a git repository under an allowed path"
+}
+
+func (t *GitStatusTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type:       "object",
+		Properties: gitRepoSchema(),
+	}
+}
+
+func (t *GitStatusTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		resp, err := runGitWithTimeout(func() (*types.CallToolResponse, error) {
+			return t.status(args)
+		})
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return gitErrorResponse(err), nil
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return resp, nil
+	})
+}
+
+func (t *GitStatusTool) status(args map[string]interface{}) (*types.CallToolResponse, error) {
+	repo, root, err := openGitRepo(t.validator, args, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	wt, _ := repo.Worktree()
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute status: %w", err)
+	}
+
+	branch, _ := headBranch(repo)
+
+	var paths []string
+	for p := range status {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var files []map[string]interface{}
+	var text strings.Builder
+	fmt.Fprintf(&text, "Repository: %s\nBranch: %s\n", root, branch)
+	if len(paths) == 0 {
+		text.WriteString("Working tree clean\n")
+	}
+	for _, p := range paths {
+		fs := status[p]
+		files = append(files, map[string]interface{}{
+			"path":     p,
+			"staging":  string(fs.Staging),
+			"worktree": string(fs.Worktree),
+		})
+		fmt.Fprintf(&text, "  %c%c %s\n", fs.Staging, fs.Worktree, p)
+	}
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text.String(),
+			Data: map[string]interface{}{
+				"root":   root,
+				"branch": branch,
+				"clean":  len(paths) == 0,
+				"files":  files,
+			},
+		}},
+	}, nil
+}
+
+// GitDiffTool shows uncommitted changes as a unified diff
+type GitDiffTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewGitDiffTool(log *logger.Logger, validator *PathValidator) *GitDiffTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &GitDiffTool{logger: log, validator: validator}
+}
+
+func (t *GitDiffTool) Name() string {
+	return "git_diff"
+}
+
+func (t *GitDiffTool) Description() string {
+	return "Show uncommitted changes in a git repository under an allowed path as a unified diff (working tree or staged changes against HEAD)"
+}
+
+func (t *GitDiffTool) InputSchema() types.ToolSchema {
+	props := gitRepoSchema()
+	props["staged"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Diff the staged index against HEAD instead of the working tree",
+		"default":     false,
+	}
+	props["files"] = map[string]interface{}{
+		"type":        "array",
+		"description": "Limit the diff to these repository-relative file paths",
+		"items":       map[string]interface{}{"type": "string"},
+	}
+	return types.ToolSchema{
+		Type:       "object",
+		Properties: props,
+	}
+}
+
+func (t *GitDiffTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		resp, err := runGitWithTimeout(func() (*types.CallToolResponse, error) {
+			return t.diff(args)
+		})
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return gitErrorResponse(err), nil
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return resp, nil
+	})
+}
+
+func (t *GitDiffTool) diff(args map[string]interface{}) (*types.CallToolResponse, error) {
+	repo, root, err := openGitRepo(t.validator, args, "read")
+	if err != nil {
+		return nil, err
+	}
+
+	staged, _ := args["staged"].(bool)
+	filter := make(map[string]bool)
+	if raw, ok := args["files"].([]interface{}); ok {
+		for _, f := range raw {
+			if name, ok := f.(string); ok {
+				filter[filepath.ToSlash(filepath.Clean(name))] = true
+			}
+		}
+	}
+
+	patch, changed, err := buildGitDiff(repo, root, staged, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	truncated := false
+	if len(patch) > maxDiffBytes {
+		patch = patch[:maxDiffBytes] + "\n... diff truncated ...\n"
+		truncated = true
+	}
+
+	text := patch
+	if text == "" {
+		text = "No changes"
+	}
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text,
+			Data: map[string]interface{}{
+				"root":      root,
+				"staged":    staged,
+				"files":     changed,
+				"truncated": truncated,
+			},
+		}},
+	}, nil
+}
+
+// buildGitDiff renders HEAD-vs-worktree (or HEAD-vs-index) changes for every changed file
+func buildGitDiff(repo *git.Repository, root string, staged bool, filter map[string]bool) (string, []string, error) {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return "", nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to compute status: %w", err)
+	}
+
+	var headTree *object.Tree
+	if _, head := headBranch(repo); head != nil {
+		commit, err := repo.CommitObject(head.Hash())
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to read HEAD commit: %w", err)
+		}
+		if headTree, err = commit.Tree(); err != nil {
+			return "", nil, fmt.Errorf("failed to read HEAD tree: %w", err)
+		}
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read index: %w", err)
+	}
+
+	var paths []string
+	for p, fs := range status {
+		if len(filter) > 0 && !filter[p] {
+			continue
+		}
+		code := fs.Worktree
+		if staged {
+			code = fs.Staging
+		}
+		if code == git.Unmodified || (code == git.Untracked && staged) {
+			continue
+		}
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var out strings.Builder
+	for _, p := range paths {
+		oldContent, oldExists := "", false
+		if headTree != nil {
+			if f, err := headTree.File(p); err == nil {
+				if oldContent, err = f.Contents(); err == nil {
+					oldExists = true
+				}
+			}
+		}
+
+		newContent, newExists := "", false
+		if staged {
+			if entry, err := idx.Entry(p); err == nil {
+				if blob, err := repo.BlobObject(entry.Hash); err == nil {
+					if r, err := blob.Reader(); err == nil {
+						data, rerr := io.ReadAll(r)
+						r.Close()
+						if rerr == nil {
+							newContent, newExists = string(data), true
+						}
+					}
+				}
+			}
+		} else if data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(p))); err == nil {
+			newContent, newExists = string(data), true
+		}
+
+		out.WriteString(formatFileDiff(p, oldContent, oldExists, newContent, newExists))
+	}
+
+	return out.String(), paths, nil
+}
+
+// formatFileDiff renders one file's change as a unified diff
+func formatFileDiff(path, oldContent string, oldExists bool, newContent string, newExists bool) string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "diff --git a/%s b/%s\n", path, path)
+
+	if strings.ContainsRune(oldContent, 0) || strings.ContainsRune(newContent, 0) {
+		out.WriteString("Binary files differ\n")
+		return out.String()
+	}
+
+	oldName, newName := "a/"+path, "b/"+path
+	if !oldExists {
+		out.WriteString("new file\n")
+		oldName = "/dev/null"
+	}
+	if !newExists {
+		out.WriteString("deleted file\n")
+		newName = "/dev/null"
+	}
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", oldName, newName)
+
+	type lineOp struct {
+		op   diffmatchpatch.Operation
+		text string
+	}
+	var ops []lineOp
+	for _, d := range gitdiff.Do(oldContent, newContent) {
+		lines := strings.SplitAfter(d.Text, "\n")
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		for _, l := range lines {
+			ops = append(ops, lineOp{d.Type, l})
+		}
+	}
+
+	// Group changes into hunks with surrounding context
+	i := 0
+	oldLine, newLine := 1, 1
+	for i < len(ops) {
+		if ops[i].op == diffmatchpatch.DiffEqual {
+			oldLine++
+			newLine++
+			i++
+			continue
+		}
+
+		startIdx := i - diffContextLines
+		if startIdx < 0 {
+			startIdx = 0
+		}
+		hunkOld := oldLine - (i - startIdx)
+		hunkNew := newLine - (i - startIdx)
+
+		// Extend the hunk until a run of more than 2*context equal lines
+		end := i
+		equalRun := 0
+		for j := i; j < len(ops); j++ {
+			if ops[j].op == diffmatchpatch.DiffEqual {
+				equalRun++
+				if equalRun > 2*diffContextLines {
+					break
+				}
+			} else {
+				equalRun = 0
+				end = j
+			}
+		}
+		stopIdx := end + diffContextLines + 1
+		if stopIdx > len(ops) {
+			stopIdx = len(ops)
+		}
+
+		var body strings.Builder
+		oldCount, newCount := 0, 0
+		for _, o := range ops[startIdx:stopIdx] {
+			prefix := " "
+			switch o.op {
+			case diffmatchpatch.DiffDelete:
+				prefix = "-"
+				oldCount++
+			case diffmatchpatch.DiffInsert:
+				prefix = "+"
+				newCount++
+			default:
+				oldCount++
+				newCount++
+			}
+			body.WriteString(prefix + o.text)
+			if !strings.HasSuffix(o.text, "\n") {
+				body.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		if oldCount == 0 {
+			hunkOld--
+		}
+		if newCount == 0 {
+			hunkNew--
+		}
+		fmt.Fprintf(&out, "@@ -%d,%d +%d,%d @@\n", hunkOld, oldCount, hunkNew, newCount)
+		out.WriteString(body.String())
+
+		// Advance line counters past everything emitted after the first change
+		for _, o := range ops[i:stopIdx] {
+			switch o.op {
+			case diffmatchpatch.DiffDelete:
+				oldLine++
+			case diffmatchpatch.DiffInsert:
+				newLine++
+			default:
+				oldLine++
+				newLine++
+			}
+		}
+		i = stopIdx
+	}
+
+	return out.String()
+}
+
+// GitCommitTool stages and commits changes to checkpoint generated work
+type GitCommitTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewGitCommitTool(log *logger.Logger, validator *PathValidator) *GitCommitTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &GitCommitTool{logger: log, validator: validator}
+}
+
+func (t *GitCommitTool) Name() string {
+	return "git_commit"
+}
+
+func (t *GitCommitTool) Description() string {
+	return "Stage and commit changes in a git repository under an allowed path, optionally initializing the repository first"
+}
+
+func (t *GitCommitTool) InputSchema() types.ToolSchema {
+	props := gitRepoSchema()
+	props["message"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Commit message",
+		"examples":    []string{"Checkpoint: landing page layout", "Add product pages"},
+	}
+	props["files"] = map[string]interface{}{
+		"type":        "array",
+		"description": "Repository-relative paths to stage before committing (all changes including untracked files if omitted)",
+		"items":       map[string]interface{}{"type": "string"},
+	}
+	props["init"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Initialize a new repository at path if one does not exist",
+		"default":     false,
+	}
+	props["author_name"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Author name (defaults to the git config user.name, then 'RodMCP')",
+	}
+	props["author_email"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Author email (defaults to the git config user.email, then 'rodmcp@localhost')",
+	}
+	return types.ToolSchema{
+		Type:       "object",
+		Properties: props,
+		Required:   []string{"message"},
+	}
+}
+
+func (t *GitCommitTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		resp, err := runGitWithTimeout(func() (*types.CallToolResponse, error) {
+			return t.commit(args)
+		})
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return gitErrorResponse(err), nil
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return resp, nil
+	})
+}
+
+func (t *GitCommitTool) commit(args map[string]interface{}) (*types.CallToolResponse, error) {
+	message, ok := args["message"].(string)
+	if !ok || strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("message must be a non-empty string")
+	}
+
+	initialized := false
+	repo, root, err := openGitRepo(t.validator, args, "write")
+	if err != nil {
+		doInit, _ := args["init"].(bool)
+		if !doInit || !strings.Contains(err.Error(), "not inside a git repository") {
+			return nil, err
+		}
+		if repo, root, err = t.initRepo(args); err != nil {
+			return nil, err
+		}
+		initialized = true
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	var staged []string
+	if raw, ok := args["files"].([]interface{}); ok && len(raw) > 0 {
+		for _, f := range raw {
+			name, _ := f.(string)
+			if name == "" {
+				continue
+			}
+			full := filepath.Join(root, filepath.FromSlash(name))
+			if err := t.validator.ValidatePath(full, "write"); err != nil {
+				return nil, fmt.Errorf("cannot stage %s: %w", name, err)
+			}
+			if _, err := os.Lstat(full); os.IsNotExist(err) {
+				if _, err := wt.Remove(filepath.ToSlash(name)); err != nil {
+					return nil, fmt.Errorf("failed to stage deletion of %s: %w", name, err)
+				}
+			} else if _, err := wt.Add(filepath.ToSlash(name)); err != nil {
+				return nil, fmt.Errorf("failed to stage %s: %w", name, err)
+			}
+			staged = append(staged, name)
+		}
+	} else {
+		if err := wt.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+			return nil, fmt.Errorf("failed to stage changes: %w", err)
+		}
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute status: %w", err)
+	}
+	committed := 0
+	for p, fs := range status {
+		if fs.Staging != git.Unmodified && fs.Staging != git.Untracked {
+			committed++
+			if len(staged) == 0 || !containsString(staged, p) {
+				staged = append(staged, p)
+			}
+		}
+	}
+	if committed == 0 {
+		return nil, fmt.Errorf("nothing to commit in %s", root)
+	}
+	sort.Strings(staged)
+
+	name, email := commitAuthor(repo, args)
+	hash, err := wt.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{Name: name, Email: email, When: time.Now()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("commit failed: %w", err)
+	}
+
+	branch, _ := headBranch(repo)
+	t.logger.WithComponent("tools").Info("Git commit created",
+		zap.String("root", root),
+		zap.String("hash", hash.String()),
+		zap.Int("files", committed))
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Committed %d file(s) to %s as %s: %s", committed, branch, hash.String()[:7], message),
+			Data: map[string]interface{}{
+				"root":        root,
+				"branch":      branch,
+				"hash":        hash.String(),
+				"files":       staged,
+				"initialized": initialized,
+			},
+		}},
+	}, nil
+}
+
+// initRepo creates a new repository at the requested path
+func (t *GitCommitTool) initRepo(args map[string]interface{}) (*git.Repository, string, error) {
+	pathStr := "."
+	if val, ok := args["path"].(string); ok && val != "" {
+		pathStr = val
+	}
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		return nil, "", err
+	}
+	absPath, err := filepath.Abs(cleanPath)
+	if err != nil {
+		return nil, "", err
+	}
+	repo, err := git.PlainInit(absPath, false)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	return repo, absPath, nil
+}
+
+// commitAuthor picks the author from arguments, then git config, then defaults
+func commitAuthor(repo *git.Repository, args map[string]interface{}) (string, string) {
+	name, _ := args["author_name"].(string)
+	email, _ := args["author_email"].(string)
+
+	if name == "" || email == "" {
+		for _, scope := range []config.Scope{config.LocalScope, config.GlobalScope} {
+			cfg, err := repo.ConfigScoped(scope)
+			if err != nil {
+				continue
+			}
+			if name == "" {
+				name = cfg.User.Name
+			}
+			if email == "" {
+				email = cfg.User.Email
+			}
+		}
+	}
+	if name == "" {
+		name = "RodMCP"
+	}
+	if email == "" {
+		email = "rodmcp@localhost"
+	}
+	return name, email
+}
@@ -0,0 +1,110 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newGitTestValidator(dir string) *PathValidator {
+	return NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{dir},
+		RestrictToWorkingDir: false,
+		MaxFileSize:          1024 * 1024,
+	})
+}
+
+func TestGitCommitStatusDiff(t *testing.T) {
+	dir := t.TempDir()
+	log := createTestLogger(t)
+	validator := newGitTestValidator(dir)
+
+	commitTool := NewGitCommitTool(log, validator)
+	statusTool := NewGitStatusTool(log, validator)
+	diffTool := NewGitDiffTool(log, validator)
+
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>One</h1>\n<p>a</p>\n"), 0644)
+
+	// Without init the directory is not a repository
+	resp, _ := commitTool.Execute(map[string]interface{}{"path": dir, "message": "first"})
+	if !resp.IsError {
+		t.Fatal("Expected commit outside a repository to fail without init")
+	}
+
+	resp, err := commitTool.Execute(map[string]interface{}{"path": dir, "message": "first", "init": true})
+	if err != nil || resp.IsError {
+		t.Fatalf("git_commit with init failed: %v %v", err, resp)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if data["initialized"] != true || len(data["hash"].(string)) != 40 {
+		t.Errorf("Unexpected commit data: %v", data)
+	}
+
+	resp, _ = statusTool.Execute(map[string]interface{}{"cwd": dir})
+	if resp.IsError || resp.Content[0].Data.(map[string]interface{})["clean"] != true {
+		t.Fatalf("Expected clean tree after commit: %v", resp.Content[0].Text)
+	}
+
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("<h1>Two</h1>\n<p>a</p>\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "about.html"), []byte("about\n"), 0644)
+
+	resp, _ = statusTool.Execute(map[string]interface{}{"path": dir})
+	if !strings.Contains(resp.Content[0].Text, "M index.html") || !strings.Contains(resp.Content[0].Text, "?? about.html") {
+		t.Errorf("Unexpected status output: %s", resp.Content[0].Text)
+	}
+
+	resp, _ = diffTool.Execute(map[string]interface{}{"path": dir})
+	text := resp.Content[0].Text
+	for _, want := range []string{"-<h1>One</h1>", "+<h1>Two</h1>", "+++ b/about.html", "--- /dev/null"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected diff to contain %q, got:\n%s", want, text)
+		}
+	}
+
+	// Nothing is staged yet
+	resp, _ = diffTool.Execute(map[string]interface{}{"path": dir, "staged": true})
+	if resp.Content[0].Text != "No changes" {
+		t.Errorf("Expected no staged changes, got %s", resp.Content[0].Text)
+	}
+
+	resp, _ = commitTool.Execute(map[string]interface{}{
+		"path":        dir,
+		"message":     "only index",
+		"files":       []interface{}{"index.html"},
+		"author_name": "Tester",
+	})
+	if resp.IsError {
+		t.Fatalf("Selective commit failed: %s", resp.Content[0].Text)
+	}
+
+	resp, _ = statusTool.Execute(map[string]interface{}{"path": dir})
+	files := resp.Content[0].Data.(map[string]interface{})["files"].([]map[string]interface{})
+	if len(files) != 1 || files[0]["path"] != "about.html" {
+		t.Errorf("Expected only about.html left uncommitted, got %v", files)
+	}
+}
+
+func TestGitToolsAccessDenied(t *testing.T) {
+	allowed := t.TempDir()
+	outside := t.TempDir()
+	log := createTestLogger(t)
+	validator := newGitTestValidator(allowed)
+
+	resp, _ := NewGitCommitTool(log, validator).Execute(map[string]interface{}{
+		"path":    outside,
+		"message": "escape",
+		"init":    true,
+	})
+	if !resp.IsError {
+		t.Error("Expected commit outside allowed paths to be rejected")
+	}
+	if _, err := os.Stat(filepath.Join(outside, ".git")); err == nil {
+		t.Error("Expected no repository to be created outside allowed paths")
+	}
+
+	resp, _ = NewGitStatusTool(log, validator).Execute(map[string]interface{}{"path": outside})
+	if !resp.IsError {
+		t.Error("Expected status outside allowed paths to be rejected")
+	}
+}
@@ -0,0 +1,288 @@
+package webtools
+
+import (
+	"net/url"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"rodmcp/internal/browser"
+)
+
+// HAR 1.2 (http://www.softwareishard.com/blog/har-12-spec/), the format
+// browser devtools import and export. Fields prefixed with _ are the
+// custom fields Chrome writes too.
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Pages   []harPage   `json:"pages"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harPage struct {
+	StartedDateTime string         `json:"startedDateTime"`
+	ID              string         `json:"id"`
+	Title           string         `json:"title"`
+	PageTimings     harPageTimings `json:"pageTimings"`
+}
+
+type harPageTimings struct {
+	OnContentLoad float64 `json:"onContentLoad"`
+	OnLoad        float64 `json:"onLoad"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harEntry struct {
+	PageRef         string      `json:"pageref"`
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ServerIPAddress string      `json:"serverIPAddress,omitempty"`
+	ResourceType    string      `json:"_resourceType,omitempty"`
+	TransferSize    float64     `json:"_transferSize"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+}
+
+// harTimings are in milliseconds; -1 marks a phase that did not happen,
+// such as DNS on a reused connection
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+const harTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// rodmcpVersion is the module version recorded in the binary, for the
+// creator fields of exported files
+func rodmcpVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+// buildHAR converts a capture into a HAR log with a single page covering
+// the whole capture
+func buildHAR(capture *browser.NetworkCapture, title string) *harFile {
+	const pageID = "page_1"
+	har := &harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "rodmcp", Version: rodmcpVersion()},
+		Pages: []harPage{{
+			StartedDateTime: capture.StartedAt.Format(harTimeFormat),
+			ID:              pageID,
+			Title:           title,
+			PageTimings:     harPageTimings{OnContentLoad: -1, OnLoad: -1},
+		}},
+		Entries: []*harEntry{},
+	}}
+	for _, r := range capture.Requests {
+		entry := harEntryFor(r)
+		entry.PageRef = pageID
+		har.Log.Entries = append(har.Log.Entries, entry)
+	}
+	return har
+}
+
+func harEntryFor(r *browser.CapturedRequest) *harEntry {
+	timings, total := harTimingsFor(r)
+	entry := &harEntry{
+		StartedDateTime: r.StartedAt.Format(harTimeFormat),
+		Time:            total,
+		Request: harRequest{
+			Method:      r.Method,
+			URL:         r.URL,
+			HTTPVersion: harHTTPVersion(r.Protocol),
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(r.RequestHeaders),
+			QueryString: harQueryString(r.URL),
+			HeadersSize: -1,
+			BodySize:    len(r.PostData),
+		},
+		Response: harResponse{
+			Status:      r.Status,
+			StatusText:  r.StatusText,
+			HTTPVersion: harHTTPVersion(r.Protocol),
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(r.ResponseHeaders),
+			Content:     harContent{Size: r.BodyBytes, MimeType: r.MimeType},
+			RedirectURL: r.RedirectedTo,
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings:         timings,
+		ServerIPAddress: strings.Trim(r.RemoteIP, "[]"),
+		ResourceType:    strings.ToLower(r.ResourceType),
+		TransferSize:    r.EncodedBytes,
+	}
+	if r.PostData != "" {
+		entry.Request.PostData = &harPostData{MimeType: headerValue(r.RequestHeaders, "Content-Type"), Text: r.PostData}
+	}
+	switch {
+	case r.Failed:
+		entry.Error = r.ErrorText
+		if r.BlockedReason != "" {
+			entry.Error += " (blocked: " + r.BlockedReason + ")"
+		}
+	case !r.Finished:
+		entry.Error = "not finished when the capture stopped"
+	}
+	return entry
+}
+
+// harTimingsFor splits a request's time into HAR phases using the
+// browser's timing breakdown, and returns the phases' total
+func harTimingsFor(r *browser.CapturedRequest) (harTimings, float64) {
+	t := harTimings{Blocked: -1, DNS: -1, Connect: -1, SSL: -1}
+	timing := r.Timing
+	if timing == nil {
+		// Cached, failed and data: requests have no breakdown
+		t.Wait = r.DurationMs
+		return t, r.DurationMs
+	}
+
+	phase := func(start, end float64) float64 {
+		if start < 0 || end < start {
+			return -1
+		}
+		return end - start
+	}
+	blocked := timing.SendStart
+	if timing.DNSStart >= 0 {
+		blocked = timing.DNSStart
+	} else if timing.ConnectStart >= 0 {
+		blocked = timing.ConnectStart
+	}
+	if blocked > 0 {
+		t.Blocked = blocked
+	}
+	t.DNS = phase(timing.DNSStart, timing.DNSEnd)
+	t.Connect = phase(timing.ConnectStart, timing.ConnectEnd)
+	t.SSL = phase(timing.SslStart, timing.SslEnd)
+	t.Send = max(phase(timing.SendStart, timing.SendEnd), 0)
+	t.Wait = max(phase(timing.SendEnd, timing.ReceiveHeadersEnd), 0)
+	if r.EndMs > timing.ReceiveHeadersEnd {
+		t.Receive = r.EndMs - timing.ReceiveHeadersEnd
+	}
+
+	// ssl is part of connect, so it is not added again
+	total := t.Send + t.Wait + t.Receive
+	for _, v := range []float64{t.Blocked, t.DNS, t.Connect} {
+		if v > 0 {
+			total += v
+		}
+	}
+	return t, total
+}
+
+// harHTTPVersion turns the ALPN protocol Chrome reports into the
+// request-line form HAR viewers expect
+func harHTTPVersion(protocol string) string {
+	switch strings.ToLower(protocol) {
+	case "h2":
+		return "HTTP/2.0"
+	case "h3", "h3-29":
+		return "HTTP/3.0"
+	case "":
+		return ""
+	}
+	return strings.ToUpper(protocol)
+}
+
+// harHeaders lists headers sorted by name; CDP joins repeated headers
+// with newlines, HAR lists each separately
+func harHeaders(headers map[string]string) []harNameValue {
+	list := []harNameValue{}
+	for name, value := range headers {
+		for _, v := range strings.Split(value, "\n") {
+			list = append(list, harNameValue{Name: name, Value: v})
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+func harQueryString(rawURL string) []harNameValue {
+	list := []harNameValue{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return list
+	}
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range query[name] {
+			list = append(list, harNameValue{Name: name, Value: value})
+		}
+	}
+	return list
+}
+
+// headerValue looks a header up case-insensitively
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
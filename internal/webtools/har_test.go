@@ -0,0 +1,82 @@
+package webtools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+)
+
+func TestBuildHAR(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	capture := &browser.NetworkCapture{
+		StartedAt: started,
+		StoppedAt: started.Add(2 * time.Second),
+		Requests: []*browser.CapturedRequest{
+			{
+				NetworkRequest: browser.NetworkRequest{
+					URL: "https://example.com/api?b=2&a=1", Method: "POST", ResourceType: "Fetch",
+					StartedAt: started, Status: 201, Protocol: "h2", RemoteIP: "[2001:db8::1]",
+					EncodedBytes: 900, Finished: true,
+				},
+				RequestHeaders:  map[string]string{"Content-Type": "application/json"},
+				PostData:        `{"q":1}`,
+				StatusText:      "Created",
+				ResponseHeaders: map[string]string{"set-cookie": "a=1\nb=2"},
+				BodyBytes:       2048,
+				Timing: &proto.NetworkResourceTiming{
+					DNSStart: 2, DNSEnd: 10, ConnectStart: 10, ConnectEnd: 40, SslStart: 20, SslEnd: 40,
+					SendStart: 41, SendEnd: 42, ReceiveHeadersEnd: 90,
+				},
+				EndMs: 100,
+			},
+			{
+				NetworkRequest: browser.NetworkRequest{
+					URL: "https://ads.example.net/x.js", Method: "GET", StartedAt: started,
+					Failed: true, ErrorText: "net::ERR_BLOCKED_BY_CLIENT", DurationMs: 3, Finished: true,
+				},
+			},
+		},
+	}
+
+	har := buildHAR(capture, "https://example.com/")
+	if har.Log.Version != "1.2" || len(har.Log.Pages) != 1 || len(har.Log.Entries) != 2 {
+		t.Fatalf("har = %+v", har.Log)
+	}
+
+	api := har.Log.Entries[0]
+	want := harTimings{Blocked: 2, DNS: 8, Connect: 30, SSL: 20, Send: 1, Wait: 48, Receive: 10}
+	if api.Timings != want || api.Time != 99 {
+		t.Errorf("timings = %+v (time %v), want %+v (time 99)", api.Timings, api.Time, want)
+	}
+	if api.Request.HTTPVersion != "HTTP/2.0" || api.ServerIPAddress != "2001:db8::1" || api.PageRef != "page_1" {
+		t.Errorf("entry = %+v", api)
+	}
+	if q := api.Request.QueryString; len(q) != 2 || q[0].Name != "a" {
+		t.Errorf("queryString = %+v", q)
+	}
+	if api.Request.PostData == nil || api.Request.PostData.MimeType != "application/json" {
+		t.Errorf("postData = %+v", api.Request.PostData)
+	}
+	if h := api.Response.Headers; len(h) != 2 || h[1].Value != "b=2" {
+		t.Errorf("repeated headers not split: %+v", h)
+	}
+	if api.Response.Content.Size != 2048 || api.Response.Status != 201 {
+		t.Errorf("response = %+v", api.Response)
+	}
+
+	blocked := har.Log.Entries[1]
+	if !strings.Contains(blocked.Error, "ERR_BLOCKED_BY_CLIENT") || blocked.Time != 3 || blocked.Timings.DNS != -1 {
+		t.Errorf("failed entry = %+v", blocked)
+	}
+
+	// HAR viewers reject null arrays
+	encoded, _ := json.Marshal(har)
+	if strings.Contains(string(encoded), "null") {
+		t.Errorf("HAR contains null: %s", encoded)
+	}
+}
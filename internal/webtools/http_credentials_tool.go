@@ -0,0 +1,141 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// SetHTTPCredentialsTool registers the credentials the browser answers
+// HTTP Basic and Digest authentication challenges with
+type SetHTTPCredentialsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSetHTTPCredentialsTool(log *logger.Logger, mgr *browser.Manager) *SetHTTPCredentialsTool {
+	return &SetHTTPCredentialsTool{logger: log, browserMgr: mgr}
+}
+
+func (t *SetHTTPCredentialsTool) Name() string {
+	return "set_http_credentials"
+}
+
+func (t *SetHTTPCredentialsTool) Description() string {
+	return "Register a username and password for an origin so the browser answers its HTTP Basic or Digest authentication prompts automatically, on every page, instead of failing with 401. Passwords are never echoed back or logged"
+}
+
+// SensitiveArguments keeps the password out of the server's logs (see
+// mcp.SensitiveTool)
+func (t *SetHTTPCredentialsTool) SensitiveArguments() []string {
+	return []string{"password"}
+}
+
+func (t *SetHTTPCredentialsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"set", "remove", "list", "clear"},
+				"description": "set registers credentials for origin, remove forgets them, list shows the registered origins and usernames, clear forgets all",
+				"default":     "set",
+			},
+			"origin": map[string]interface{}{
+				"type":        "string",
+				"description": "Origin or any URL on it, e.g. 'https://jenkins.internal:8443' (required for set and remove)",
+			},
+			"username": map[string]interface{}{
+				"type":        "string",
+				"description": "Username to answer the origin's challenges with (required for set)",
+			},
+			"password": map[string]interface{}{
+				"type":        "string",
+				"description": "Password to answer the origin's challenges with",
+			},
+		},
+	}
+}
+
+func (t *SetHTTPCredentialsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		// Keep the password out of the log
+		logArgs := args
+		if _, ok := args["password"]; ok {
+			logArgs = make(map[string]interface{}, len(args))
+			for key, value := range args {
+				logArgs[key] = value
+			}
+			logArgs["password"] = "[redacted]"
+		}
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), logArgs, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "set"
+		}
+		origin, _ := args["origin"].(string)
+		username, _ := args["username"].(string)
+		password, _ := args["password"].(string)
+		username = strings.TrimSpace(username)
+
+		var text string
+		switch action {
+		case "set":
+			if origin == "" || username == "" {
+				return fail("origin and username are required to set credentials")
+			}
+			if err := t.browserMgr.SetHTTPCredentials(origin, username, password); err != nil {
+				return fail(fmt.Sprintf("Failed to set credentials: %v", err))
+			}
+			normalized, _ := browser.NormalizeOrigin(origin)
+			text = fmt.Sprintf("The browser will sign in to %s as %s when asked", normalized, username)
+			if strings.HasPrefix(normalized, "http://") {
+				text += "; the origin is plain HTTP, so Basic credentials cross the network unencrypted"
+			}
+		case "remove":
+			if origin == "" {
+				return fail("origin is required to remove credentials")
+			}
+			removed, err := t.browserMgr.RemoveHTTPCredentials(origin)
+			if err != nil {
+				return fail(err.Error())
+			}
+			normalized, _ := browser.NormalizeOrigin(origin)
+			text = fmt.Sprintf("No credentials were registered for %s", normalized)
+			if removed {
+				text = fmt.Sprintf("Forgot the credentials for %s", normalized)
+			}
+		case "clear":
+			text = fmt.Sprintf("Forgot the credentials for %d origin(s)", t.browserMgr.ClearHTTPCredentials())
+		case "list":
+		default:
+			return fail("action must be one of set, remove, list, clear")
+		}
+
+		credentials := t.browserMgr.HTTPCredentials()
+		if action == "list" {
+			text = fmt.Sprintf("Credentials are registered for %d origin(s)", len(credentials))
+			for _, c := range credentials {
+				text += fmt.Sprintf("\n- %s as %s", c.Origin, c.Username)
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), logArgs, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{"credentials": credentials},
+			}},
+		}, nil
+	})
+}
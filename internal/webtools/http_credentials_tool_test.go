@@ -0,0 +1,43 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestSetHTTPCredentialsTool(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewSetHTTPCredentialsTool(log, browser.NewManager(log, browser.Config{}))
+
+	resp, _ := tool.Execute(map[string]interface{}{"origin": "https://staging.internal"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "username are required") {
+		t.Errorf("Expected missing username error, got %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"origin": "staging.internal", "username": "admin"})
+	if !resp.IsError {
+		t.Error("Expected an origin without a scheme to be rejected")
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"origin": "http://router.lan/admin", "username": "root", "password": "secret"})
+	if resp.IsError || !strings.Contains(resp.Content[0].Text, "http://router.lan as root") || !strings.Contains(resp.Content[0].Text, "unencrypted") {
+		t.Errorf("set = %+v", resp)
+	}
+	if strings.Contains(resp.Content[0].Text, "secret") {
+		t.Error("The password must not be echoed")
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"action": "list"})
+	if resp.IsError || !strings.Contains(resp.Content[0].Text, "1 origin(s)") {
+		t.Errorf("list = %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "remove", "origin": "http://router.lan"})
+	if resp.IsError || !strings.Contains(resp.Content[0].Text, "Forgot") {
+		t.Errorf("remove = %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "rotate"})
+	if !resp.IsError {
+		t.Error("Expected error for unknown action")
+	}
+}
@@ -0,0 +1,404 @@
+package webtools
+
+import (
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Ways http_request can find the next page of an API's results
+const (
+	paginateLinkHeader = "link_header"
+	paginateCursor     = "cursor"
+	paginatePage       = "page"
+)
+
+const (
+	defaultPaginatePages = 10
+	maxPaginatePages     = 100
+)
+
+// linkHeaderPattern matches one <uri>; params entry of a Link header
+var linkHeaderPattern = regexp.MustCompile(`<([^>]*)>([^<]*)`)
+
+// paginationSpec is http_request's paginate argument: where each page's
+// results are, how to reach the next page and when to give up
+type paginationSpec struct {
+	Strategy    string
+	MaxPages    int
+	Items       string
+	CursorField string
+	CursorParam string
+	PageParam   string
+	StartPage   int
+}
+
+// jsonPage is one fetched page of results
+type jsonPage struct {
+	header http.Header
+	status int
+	body   interface{}
+	size   int
+}
+
+// pageFetcher requests pageURL the way the first page was requested; the
+// body is nil when the response is not JSON
+type pageFetcher func(pageURL string) (*jsonPage, error)
+
+// paginateSchema describes http_request's paginate argument
+func paginateSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "Follow the API's pagination and return every page's results merged into one json array. The request is repeated for each page with the same method, headers and body",
+		"properties": map[string]interface{}{
+			"strategy": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{paginateLinkHeader, paginateCursor, paginatePage},
+				"description": "link_header follows the rel=\"next\" URL in the Link header (GitHub style); cursor reads the next cursor from cursor_field and sends it as cursor_param, or follows it when it is a URL; page counts up page_param until a page comes back empty or short",
+			},
+			"items": map[string]interface{}{
+				"type":        "string",
+				"description": "Dot path to the array of results in each page, e.g. 'data' or 'result.items'. By default the page itself when it is an array, or its only array field",
+			},
+			"cursor_field": map[string]interface{}{
+				"type":        "string",
+				"description": "cursor: dot path to the next cursor or next-page URL, e.g. 'meta.next_cursor' or 'links.next'. Pagination stops when it is missing or empty",
+			},
+			"cursor_param": map[string]interface{}{
+				"type":        "string",
+				"description": "cursor: query parameter the cursor is sent in",
+				"default":     "cursor",
+			},
+			"page_param": map[string]interface{}{
+				"type":        "string",
+				"description": "page: query parameter holding the page number",
+				"default":     "page",
+			},
+			"start_page": map[string]interface{}{
+				"type":        "integer",
+				"description": "page: number of the first page, when the URL doesn't already give it (default 1)",
+			},
+			"max_pages": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most pages to fetch, counting the first",
+				"default":     defaultPaginatePages,
+				"minimum":     1,
+				"maximum":     maxPaginatePages,
+			},
+		},
+		"required": []string{"strategy"},
+		"examples": []interface{}{
+			map[string]interface{}{"strategy": paginateLinkHeader, "max_pages": 5},
+			map[string]interface{}{"strategy": paginateCursor, "items": "data", "cursor_field": "meta.next_cursor"},
+			map[string]interface{}{"strategy": paginatePage, "items": "results", "page_param": "p"},
+		},
+	}
+}
+
+// parsePagination reads the optional paginate argument
+func parsePagination(args map[string]interface{}) (*paginationSpec, error) {
+	raw, ok := args["paginate"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("paginate must be an object with a strategy")
+	}
+	str := func(key, fallback string) string {
+		if v, ok := obj[key].(string); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+		return fallback
+	}
+
+	spec := &paginationSpec{
+		Strategy:    str("strategy", ""),
+		MaxPages:    defaultPaginatePages,
+		Items:       str("items", ""),
+		CursorField: str("cursor_field", ""),
+		CursorParam: str("cursor_param", "cursor"),
+		PageParam:   str("page_param", "page"),
+	}
+	switch spec.Strategy {
+	case paginateLinkHeader, paginatePage:
+	case paginateCursor:
+		if spec.CursorField == "" {
+			return nil, fmt.Errorf("paginate.cursor_field is required for the cursor strategy")
+		}
+	default:
+		return nil, fmt.Errorf("paginate.strategy must be one of %s, %s, %s", paginateLinkHeader, paginateCursor, paginatePage)
+	}
+	if v, ok := obj["max_pages"].(float64); ok {
+		if v < 1 || v > maxPaginatePages {
+			return nil, fmt.Errorf("paginate.max_pages must be between 1 and %d", maxPaginatePages)
+		}
+		spec.MaxPages = int(v)
+	}
+	if v, ok := obj["start_page"].(float64); ok {
+		if v < 0 {
+			return nil, fmt.Errorf("paginate.start_page must not be negative")
+		}
+		spec.StartPage = int(v)
+	}
+	return spec, nil
+}
+
+// follow merges the results of first, which the caller has already fetched
+// from firstURL, with those of the pages after it. A page that fails ends
+// the run rather than losing the pages before it; the summary says why
+// pagination stopped and whether every page was read.
+func (p *paginationSpec) follow(firstURL string, first *jsonPage, fetch pageFetcher) ([]interface{}, map[string]interface{}, error) {
+	pageNumber := p.StartPage
+	if pageNumber == 0 {
+		pageNumber = 1
+		if u, err := neturl.Parse(firstURL); err == nil {
+			if n, err := strconv.Atoi(u.Query().Get(p.PageParam)); err == nil {
+				pageNumber = n
+			}
+		}
+	}
+
+	items := []interface{}{}
+	summary := map[string]interface{}{"strategy": p.Strategy}
+	seen := map[string]bool{firstURL: true}
+	pageURL, page := firstURL, first
+	pages, bytesRead, firstCount := 0, 0, -1
+	complete := false
+	var stopped string
+	for {
+		pageItems, err := p.pageItems(page.body)
+		if err != nil {
+			if pages == 0 {
+				return nil, nil, err
+			}
+			stopped = fmt.Sprintf("page %d: %v", pages+1, err)
+			break
+		}
+		pages++
+		bytesRead += page.size
+		items = append(items, pageItems...)
+		if firstCount < 0 {
+			firstCount = len(pageItems)
+		}
+
+		next, reason := p.nextURL(pageURL, page, len(pageItems), firstCount, pageNumber+1)
+		if next == "" {
+			stopped, complete = reason, true
+			break
+		}
+		if seen[next] {
+			stopped, complete = "the next page is one already fetched", true
+			break
+		}
+		// Every page is sent the request's credentials, so a page may not
+		// hand them on to another site
+		if !sameOrigin(firstURL, next) {
+			stopped = fmt.Sprintf("the next page %s is on another origin", next)
+			summary["next_url"] = next
+			break
+		}
+		if pages >= p.MaxPages {
+			stopped = fmt.Sprintf("reached max_pages (%d)", p.MaxPages)
+			summary["next_url"] = next
+			break
+		}
+		seen[next] = true
+
+		if page, err = fetch(next); err != nil {
+			stopped = fmt.Sprintf("page %d: %v", pages+1, err)
+			break
+		}
+		if page.status < 200 || page.status > 299 {
+			stopped = fmt.Sprintf("page %d returned HTTP %d", pages+1, page.status)
+			break
+		}
+		if page.body == nil {
+			stopped = fmt.Sprintf("page %d is not JSON", pages+1)
+			break
+		}
+		pageURL = next
+		pageNumber++
+	}
+
+	summary["pages"] = pages
+	summary["items"] = len(items)
+	summary["bytes"] = bytesRead
+	summary["stopped"] = stopped
+	summary["complete"] = complete
+	return items, summary, nil
+}
+
+// pageItems finds the array of results in a page
+func (p *paginationSpec) pageItems(body interface{}) ([]interface{}, error) {
+	if p.Items != "" {
+		value, ok := jsonPathValue(body, p.Items)
+		if !ok || value == nil {
+			// A last page may leave the field out instead of sending []
+			return nil, nil
+		}
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("paginate.items %q is not an array", p.Items)
+		}
+		return list, nil
+	}
+
+	switch v := body.(type) {
+	case []interface{}:
+		return v, nil
+	case map[string]interface{}:
+		var found []interface{}
+		count := 0
+		for _, field := range v {
+			if list, ok := field.([]interface{}); ok {
+				found = list
+				count++
+			}
+		}
+		if count == 1 {
+			return found, nil
+		}
+	}
+	return nil, fmt.Errorf("set paginate.items to the field holding each page's results")
+}
+
+// nextURL works out the page after page, fetched from pageURL, or returns
+// "" and why there is none. count is how many results the page held and
+// firstCount how many the first page did; nextPage is the page number to
+// ask for next.
+func (p *paginationSpec) nextURL(pageURL string, page *jsonPage, count, firstCount, nextPage int) (string, string) {
+	base, err := neturl.Parse(pageURL)
+	if err != nil {
+		return "", fmt.Sprintf("invalid page URL: %v", err)
+	}
+
+	switch p.Strategy {
+	case paginateLinkHeader:
+		next := nextLink(page.header)
+		if next == "" {
+			return "", "no rel=\"next\" Link header"
+		}
+		ref, err := base.Parse(next)
+		if err != nil {
+			return "", fmt.Sprintf("invalid next link %q", next)
+		}
+		return ref.String(), ""
+
+	case paginateCursor:
+		value, _ := jsonPathValue(page.body, p.CursorField)
+		cursor := cursorString(value)
+		if cursor == "" {
+			return "", fmt.Sprintf("no cursor in %s", p.CursorField)
+		}
+		// Some APIs hand back the whole next-page URL instead of a cursor
+		if strings.HasPrefix(cursor, "http://") || strings.HasPrefix(cursor, "https://") || strings.HasPrefix(cursor, "/") {
+			ref, err := base.Parse(cursor)
+			if err != nil {
+				return "", fmt.Sprintf("invalid next URL %q", cursor)
+			}
+			return ref.String(), ""
+		}
+		return withQueryParam(base, p.CursorParam, cursor), ""
+
+	default:
+		if count == 0 {
+			return "", "an empty page"
+		}
+		if count < firstCount {
+			return "", "a page shorter than the first"
+		}
+		return withQueryParam(base, p.PageParam, strconv.Itoa(nextPage)), ""
+	}
+}
+
+// sameOrigin reports whether two URLs share a scheme and host
+func sameOrigin(a, b string) bool {
+	ua, err := neturl.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := neturl.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) && strings.EqualFold(ua.Host, ub.Host)
+}
+
+// nextLink returns the rel="next" target of a response's Link headers
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, m := range linkHeaderPattern.FindAllStringSubmatch(value, -1) {
+			if linkRelIsNext(m[2]) {
+				return strings.TrimSpace(m[1])
+			}
+		}
+	}
+	return ""
+}
+
+// linkRelIsNext reports whether a Link entry's params include next among
+// its space-separated rel values
+func linkRelIsNext(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		name, value, ok := strings.Cut(param, "=")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), ",")), `"`)
+		for _, rel := range strings.Fields(value) {
+			if strings.EqualFold(rel, "next") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cursorString renders a cursor value for a query string; null and empty
+// values mean there is no next page
+func cursorString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// withQueryParam is u with the query parameter key set to value
+func withQueryParam(u *neturl.URL, key, value string) string {
+	next := *u
+	query := next.Query()
+	query.Set(key, value)
+	next.RawQuery = query.Encode()
+	return next.String()
+}
+
+// jsonPathValue follows a dot path such as "data.items" or "pages.0.next"
+// through decoded JSON
+func jsonPathValue(value interface{}, path string) (interface{}, bool) {
+	for _, part := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[part]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
@@ -0,0 +1,198 @@
+package webtools
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestNextLink(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=3>; rel="next last"`)
+	if got := nextLink(header); got != "https://api.example.com/items?page=3" {
+		t.Errorf("nextLink = %q", got)
+	}
+	header.Set("Link", `</items?page=9>; rel=last`)
+	if got := nextLink(header); got != "" {
+		t.Errorf("nextLink without next = %q", got)
+	}
+}
+
+func TestJSONPathValue(t *testing.T) {
+	body := map[string]interface{}{"meta": map[string]interface{}{"pages": []interface{}{"a", "b"}}}
+	if v, ok := jsonPathValue(body, "meta.pages.1"); !ok || v != "b" {
+		t.Errorf("meta.pages.1 = %v, %v", v, ok)
+	}
+	for _, path := range []string{"meta.missing", "meta.pages.2", "meta.pages.x.y"} {
+		if _, ok := jsonPathValue(body, path); ok {
+			t.Errorf("Expected %s to be missing", path)
+		}
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	spec, err := parsePagination(map[string]interface{}{"paginate": map[string]interface{}{"strategy": "page"}})
+	if err != nil || spec.PageParam != "page" || spec.MaxPages != defaultPaginatePages {
+		t.Errorf("spec = %+v, %v", spec, err)
+	}
+	for _, raw := range []interface{}{
+		"page",
+		map[string]interface{}{"strategy": "offset"},
+		map[string]interface{}{"strategy": "cursor"},
+		map[string]interface{}{"strategy": "page", "max_pages": float64(1000)},
+	} {
+		if _, err := parsePagination(map[string]interface{}{"paginate": raw}); err == nil {
+			t.Errorf("Expected error for %v", raw)
+		}
+	}
+}
+
+// newPagedServer serves items 1..total, size to a page, as page.
+// page(w, r, first, last) writes the page holding items first..last.
+func newPagedServer(t *testing.T, size, total int, page func(w http.ResponseWriter, r *http.Request, first, last int)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.URL.Query().Get("page"))
+		if err != nil {
+			n = 1
+		}
+		if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+			n, _ = strconv.Atoi(cursor)
+		}
+		first := (n-1)*size + 1
+		last := first + size - 1
+		if last > total {
+			last = total
+		}
+		w.Header().Set("Content-Type", "application/json")
+		page(w, r, first, last)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func itemsJSON(first, last int) string {
+	out := "["
+	for i := first; i <= last; i++ {
+		if i > first {
+			out += ","
+		}
+		out += fmt.Sprintf(`{"id": %d}`, i)
+	}
+	return out + "]"
+}
+
+func runPaginated(t *testing.T, url string, spec map[string]interface{}) ([]interface{}, map[string]interface{}) {
+	t.Helper()
+	resp, err := NewHTTPRequestTool(createTestLogger(t), nil, nil).Execute(map[string]interface{}{"url": url, "paginate": spec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	return data["json"].([]interface{}), data["pagination"].(map[string]interface{})
+}
+
+func TestHTTPRequestPaginateLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = newPagedServer(t, 2, 5, func(w http.ResponseWriter, r *http.Request, first, last int) {
+		if last < 5 {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/items?page=%d>; rel="next"`, server.URL, (last/2)+1))
+		}
+		fmt.Fprint(w, itemsJSON(first, last))
+	})
+
+	items, summary := runPaginated(t, server.URL+"/items", map[string]interface{}{"strategy": "link_header"})
+	if len(items) != 5 || summary["pages"] != 3 || summary["complete"] != true {
+		t.Errorf("items = %v, summary = %v", items, summary)
+	}
+
+	items, summary = runPaginated(t, server.URL+"/items", map[string]interface{}{"strategy": "link_header", "max_pages": float64(2)})
+	if len(items) != 4 || summary["complete"] != false || summary["next_url"] != server.URL+"/items?page=3" {
+		t.Errorf("truncated items = %v, summary = %v", items, summary)
+	}
+}
+
+func TestHTTPRequestPaginateCursor(t *testing.T) {
+	server := newPagedServer(t, 2, 5, func(w http.ResponseWriter, r *http.Request, first, last int) {
+		next := "null"
+		if last < 5 {
+			next = fmt.Sprintf(`"%d"`, (last/2)+1)
+		}
+		fmt.Fprintf(w, `{"data": %s, "meta": {"next_cursor": %s}}`, itemsJSON(first, last), next)
+	})
+
+	items, summary := runPaginated(t, server.URL, map[string]interface{}{
+		"strategy": "cursor", "items": "data", "cursor_field": "meta.next_cursor",
+	})
+	if len(items) != 5 || summary["pages"] != 3 || summary["stopped"] != "no cursor in meta.next_cursor" {
+		t.Errorf("items = %v, summary = %v", items, summary)
+	}
+}
+
+func TestHTTPRequestPaginatePageNumbers(t *testing.T) {
+	server := newPagedServer(t, 2, 4, func(w http.ResponseWriter, r *http.Request, first, last int) {
+		if r.URL.Query().Get("page") == "3" {
+			// Running past the end is an error on some APIs
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "no such page"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"results": %s, "count": 4}`, itemsJSON(first, last))
+	})
+
+	items, summary := runPaginated(t, server.URL, map[string]interface{}{"strategy": "page"})
+	if len(items) != 4 || summary["stopped"] != "page 3 returned HTTP 404" || summary["complete"] != false {
+		t.Errorf("items = %v, summary = %v", items, summary)
+	}
+}
+
+func TestHTTPRequestPaginateStopsOnShortPage(t *testing.T) {
+	server := newPagedServer(t, 2, 3, func(w http.ResponseWriter, r *http.Request, first, last int) {
+		fmt.Fprint(w, itemsJSON(first, last))
+	})
+	items, summary := runPaginated(t, server.URL+"?page=1", map[string]interface{}{"strategy": "page"})
+	if len(items) != 3 || summary["stopped"] != "a page shorter than the first" || summary["complete"] != true {
+		t.Errorf("items = %v, summary = %v", items, summary)
+	}
+}
+
+func TestHTTPRequestPaginateStaysOnOrigin(t *testing.T) {
+	var leaked []string
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		leaked = append(leaked, r.Header.Get("Authorization"))
+		fmt.Fprint(w, `{"data": [], "next": null}`)
+	}))
+	t.Cleanup(other.Close)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Link", fmt.Sprintf(`<%s/steal>; rel="next"`, other.URL))
+		fmt.Fprintf(w, `{"data": %s, "next": "%s/steal"}`, itemsJSON(1, 2), other.URL)
+	}))
+	t.Cleanup(server.Close)
+
+	tool := NewHTTPRequestTool(createTestLogger(t), nil, nil)
+	for _, spec := range []map[string]interface{}{
+		{"strategy": "link_header", "items": "data"},
+		{"strategy": "cursor", "items": "data", "cursor_field": "next"},
+	} {
+		resp, err := tool.Execute(map[string]interface{}{
+			"url":      server.URL,
+			"headers":  map[string]interface{}{"Authorization": "Bearer secret"},
+			"paginate": spec,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		summary := resp.Content[0].Data.(map[string]interface{})["pagination"].(map[string]interface{})
+		stopped, _ := summary["stopped"].(string)
+		if summary["pages"] != 1 || summary["complete"] != false || !strings.Contains(stopped, "another origin") || summary["next_url"] != other.URL+"/steal" {
+			t.Errorf("%s: summary = %v", spec["strategy"], summary)
+		}
+	}
+	if len(leaked) != 0 {
+		t.Errorf("Expected no request to the other origin, got %d", len(leaked))
+	}
+}
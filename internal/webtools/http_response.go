@@ -0,0 +1,142 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"rodmcp/internal/browser"
+)
+
+// maxInlineBinaryBytes caps binary bodies returned as base64; larger ones
+// have to be saved with save_to
+const maxInlineBinaryBytes = 1 << 20
+
+// Kinds of response body http_request tells apart
+const (
+	bodyKindJSON   = "json"
+	bodyKindHTML   = "html"
+	bodyKindXML    = "xml"
+	bodyKindText   = "text"
+	bodyKindBinary = "binary"
+)
+
+// responseMediaType is the body's media type from contentType, or sniffed
+// from the body when the server sent none
+func responseMediaType(contentType string, body []byte) string {
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0]))
+	}
+	return mediaType
+}
+
+// responseBodyKind sorts a media type into one of the body kinds
+func responseBodyKind(mediaType string) string {
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return bodyKindJSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return bodyKindHTML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return bodyKindXML
+	case isTextContentType(mediaType):
+		return bodyKindText
+	}
+	return bodyKindBinary
+}
+
+// extractSchema describes http_request's extract argument
+func extractSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":                 "object",
+		"description":          "Pull fields out of an HTML or XML response instead of returning the whole document, with the same selectors as screen_scrape: CSS by default, XPath when it starts with / or xpath=, '@href'/'@text' directives, 'regex:' filters, ['li@text'] for every match and nested {'selector', 'fields'} schemas. The body is parsed without running its scripts or loading anything it links to",
+		"additionalProperties": scrapeSelectorValueSchema(),
+		"examples": []interface{}{
+			map[string]interface{}{"title": "//title@text", "links": []interface{}{"a@href"}},
+			map[string]interface{}{"items": map[string]interface{}{"selector": "item", "multiple": true, "fields": map[string]interface{}{"title": "title@text", "link": "link@text"}}},
+		},
+	}
+}
+
+// parsedDocumentScript parses the response body into a detached document
+// the scrape script then runs against; such documents never run scripts or
+// fetch resources. An HTML body gets a base element so relative links
+// resolve against the request URL.
+const parsedDocumentScript = `
+	const document = new DOMParser().parseFromString(%s, %s);
+	const baseURL = %s;
+	if (document.head && !document.querySelector('base[href]')) {
+		const base = document.createElement('base');
+		base.href = baseURL;
+		document.head.prepend(base);
+	}
+`
+
+// extractFromBody runs the extract fields over an HTML or XML body in a
+// scratch browser page
+func (t *HTTPRequestTool) extractFromBody(body, kind, url string, fields []scrapeField) (map[string]interface{}, error) {
+	if t.browserMgr == nil {
+		return nil, fmt.Errorf("extract needs the browser, which this server has not started")
+	}
+	mimeType := "text/html"
+	if kind == bodyKindXML {
+		mimeType = "application/xml"
+	}
+
+	_, pageID, err := t.browserMgr.NewPooledPage("", browser.PageOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a page for extraction: %w", err)
+	}
+	defer func() {
+		if err := t.browserMgr.RecyclePage(pageID); err != nil {
+			t.logger.WithComponent("tools").Warn("Failed to recycle extraction page",
+				zap.String("page_id", pageID),
+				zap.Error(err))
+		}
+	}()
+
+	bodyJSON, _ := json.Marshal(body)
+	mimeJSON, _ := json.Marshal(mimeType)
+	urlJSON, _ := json.Marshal(url)
+	fieldsJSON, _ := json.Marshal(fields)
+	script := fmt.Sprintf(parsedDocumentScript, bodyJSON, mimeJSON, urlJSON) + fmt.Sprintf(scrapeScript, "null", fieldsJSON)
+	raw, err := t.browserMgr.ExecuteScript(pageID, script)
+	if err != nil {
+		return nil, fmt.Errorf("extraction failed: %w", err)
+	}
+	var item map[string]interface{}
+	if err := decodeScriptValue(raw, &item); err != nil {
+		return nil, fmt.Errorf("unexpected extraction result: %w", err)
+	}
+	applyScrapeFilters(item, fields)
+	return item, nil
+}
+
+// saveResponseBody writes the raw body to the save_to path, resolved and
+// checked like the file tools' paths
+func (t *HTTPRequestTool) saveResponseBody(args map[string]interface{}, body []byte) (string, error) {
+	saveTo, _ := args["save_to"].(string)
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(saveTo, cwd)
+	if err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+	if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+	if err := t.validator.ValidateFileSize(int64(len(body))); err != nil {
+		return "", err
+	}
+	if err := writeMonitorFile(cleanPath, body); err != nil {
+		return "", fmt.Errorf("failed to save response body to %s: %w", cleanPath, err)
+	}
+	return cleanPath, nil
+}
@@ -0,0 +1,114 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResponseBodyKind(t *testing.T) {
+	cases := map[string]string{
+		"application/json":         bodyKindJSON,
+		"application/problem+json": bodyKindJSON,
+		"text/html":                bodyKindHTML,
+		"application/xhtml+xml":    bodyKindHTML,
+		"text/xml":                 bodyKindXML,
+		"application/rss+xml":      bodyKindXML,
+		"text/csv":                 bodyKindText,
+		"image/png":                bodyKindBinary,
+		"application/octet-stream": bodyKindBinary,
+	}
+	for mediaType, want := range cases {
+		if got := responseBodyKind(mediaType); got != want {
+			t.Errorf("responseBodyKind(%s) = %s, want %s", mediaType, got, want)
+		}
+	}
+
+	if got := responseMediaType("Text/HTML; charset=utf-8", nil); got != "text/html" {
+		t.Errorf("media type = %s", got)
+	}
+	if got := responseMediaType("", []byte("\x89PNG\r\n\x1a\n")); got != "image/png" {
+		t.Errorf("sniffed media type = %s", got)
+	}
+}
+
+func newContentServer(t *testing.T, contentType string, body []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPRequestParsesJSON(t *testing.T) {
+	server := newContentServer(t, "application/json", []byte(`{"users": [{"id": 1}]}`))
+	resp, err := NewHTTPRequestTool(createTestLogger(t), nil, nil).Execute(map[string]interface{}{"url": server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	users := data["json"].(map[string]interface{})["users"].([]interface{})
+	if len(users) != 1 || data["body_kind"] != bodyKindJSON || data["content_type"] != "application/json" {
+		t.Errorf("data = %v", data)
+	}
+}
+
+func TestHTTPRequestBinaryBody(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0xff}, 32)...)
+	server := newContentServer(t, "image/png", png)
+	dir := t.TempDir()
+	tool := NewHTTPRequestTool(createTestLogger(t), nil, NewPathValidator(&FileAccessConfig{AllowedPaths: []string{dir}, MaxFileSize: 1024}))
+
+	resp, err := tool.Execute(map[string]interface{}{"url": server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if data["body_base64"] != base64.StdEncoding.EncodeToString(png) || data["body"] != nil {
+		t.Errorf("binary body not returned as base64: %v", data)
+	}
+
+	resp, err = tool.Execute(map[string]interface{}{"url": server.URL, "save_to": "logo.png", "cwd": dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved, err := os.ReadFile(filepath.Join(dir, "logo.png"))
+	if err != nil || !bytes.Equal(saved, png) {
+		t.Errorf("saved body = %v, %v", saved, err)
+	}
+	data = resp.Content[0].Data.(map[string]interface{})
+	if data["saved_to"] != filepath.Join(dir, "logo.png") || data["body_base64"] != nil {
+		t.Errorf("data = %v", data)
+	}
+
+	if _, err := tool.Execute(map[string]interface{}{"url": server.URL, "save_to": "/etc/logo.png"}); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("save outside allowed paths: %v", err)
+	}
+	if _, err := tool.Execute(map[string]interface{}{"url": server.URL, "transform": map[string]interface{}{"jq": "."}}); err == nil {
+		t.Error("transform of a binary body succeeded")
+	}
+}
+
+func TestHTTPRequestExtractArguments(t *testing.T) {
+	server := newContentServer(t, "application/json", []byte(`{}`))
+	tool := NewHTTPRequestTool(createTestLogger(t), nil, nil)
+
+	if _, err := tool.Execute(map[string]interface{}{"url": server.URL, "extract": "h1"}); err == nil {
+		t.Error("non-object extract accepted")
+	}
+	if _, err := tool.Execute(map[string]interface{}{"url": server.URL, "extract": map[string]interface{}{"title": "h1@text"}}); err == nil || !strings.Contains(err.Error(), "HTML or XML") {
+		t.Errorf("extract from JSON: %v", err)
+	}
+
+	html := newContentServer(t, "text/html", []byte("<h1>Hi</h1>"))
+	if _, err := tool.Execute(map[string]interface{}{"url": html.URL, "extract": map[string]interface{}{"title": "h1@text"}}); err == nil || !strings.Contains(err.Error(), "browser") {
+		t.Errorf("extract without a browser: %v", err)
+	}
+}
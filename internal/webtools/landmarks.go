@@ -0,0 +1,175 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// structureIssues lists the structural accessibility problems in a page
+// outline, most important first
+func structureIssues(s *browser.PageStructure) []string {
+	var issues []string
+
+	counts := make(map[string]int)
+	unlabelled := make(map[string]int)
+	for _, l := range s.Landmarks {
+		counts[l.Role]++
+		if l.Label == "" {
+			unlabelled[l.Role]++
+		}
+	}
+	switch {
+	case counts["main"] == 0:
+		issues = append(issues, "No main landmark; add a <main> element so users can skip straight to the content")
+	case counts["main"] > 1:
+		issues = append(issues, fmt.Sprintf("%d main landmarks; a page should have one", counts["main"]))
+	}
+	for _, role := range []string{"banner", "contentinfo"} {
+		if counts[role] > 1 {
+			issues = append(issues, fmt.Sprintf("%d %s landmarks; a page should have at most one", counts[role], role))
+		}
+	}
+	for _, role := range []string{"navigation", "complementary", "region", "form", "search"} {
+		if counts[role] > 1 && unlabelled[role] > 0 {
+			issues = append(issues, fmt.Sprintf("%d %s landmarks, %d without a label to tell them apart", counts[role], role, unlabelled[role]))
+		}
+	}
+
+	var h1s, empty int
+	var skips []string
+	for i, h := range s.Headings {
+		if h.Level == 1 {
+			h1s++
+		}
+		if h.Text == "" {
+			empty++
+		}
+		if i > 0 && h.Level > s.Headings[i-1].Level+1 {
+			skips = append(skips, fmt.Sprintf("h%d -> h%d", s.Headings[i-1].Level, h.Level))
+		}
+	}
+	if h1s == 0 {
+		issues = append(issues, "No level 1 heading")
+	}
+	if len(skips) > 0 {
+		issues = append(issues, "Heading levels skipped: "+strings.Join(uniqueStrings(skips), ", "))
+	}
+	if empty > 0 {
+		issues = append(issues, fmt.Sprintf("%d empty headings", empty))
+	}
+
+	if s.UnnamedTotal > 0 {
+		issues = append(issues, fmt.Sprintf("%d elements have no accessible name", s.UnnamedTotal))
+	}
+	return issues
+}
+
+// formatPageStructure renders the outline the way a screen reader's
+// landmark and heading lists show it
+func formatPageStructure(s *browser.PageStructure, issues []string) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Landmarks (%d):\n", len(s.Landmarks))
+	for _, l := range s.Landmarks {
+		fmt.Fprintf(&b, "  %s", l.Role)
+		if l.Label != "" {
+			fmt.Fprintf(&b, " %q", l.Label)
+		}
+		fmt.Fprintf(&b, " (%s)\n", l.Selector)
+	}
+
+	fmt.Fprintf(&b, "Headings (%d):\n", len(s.Headings))
+	for _, h := range s.Headings {
+		text := h.Text
+		if text == "" {
+			text = "(empty)"
+		}
+		fmt.Fprintf(&b, "  %sh%d %s\n", strings.Repeat("  ", h.Level-1), h.Level, clipText(text, 80))
+	}
+
+	if s.UnnamedTotal > 0 {
+		fmt.Fprintf(&b, "Missing accessible names (%d):\n", s.UnnamedTotal)
+		for _, u := range s.Unnamed {
+			fmt.Fprintf(&b, "  %s (%s)\n", u.Role, u.Selector)
+		}
+		if s.UnnamedTotal > len(s.Unnamed) {
+			fmt.Fprintf(&b, "  ... and %d more\n", s.UnnamedTotal-len(s.Unnamed))
+		}
+	}
+
+	if len(issues) == 0 {
+		b.WriteString("No structural issues found")
+	} else {
+		b.WriteString("Issues:")
+		for _, issue := range issues {
+			b.WriteString("\n  - " + issue)
+		}
+	}
+	return b.String()
+}
+
+// ListLandmarksTool outlines a page's landmarks, headings and unnamed
+// controls
+type ListLandmarksTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewListLandmarksTool(log *logger.Logger, mgr *browser.Manager) *ListLandmarksTool {
+	return &ListLandmarksTool{logger: log, browserMgr: mgr}
+}
+
+func (t *ListLandmarksTool) Name() string {
+	return "list_landmarks"
+}
+
+func (t *ListLandmarksTool) Description() string {
+	return "Outline a page's accessibility structure: landmarks (banner, navigation, main, contentinfo and labelled regions), the heading hierarchy, and links, buttons, form fields and images with no accessible name, flagging problems such as a missing main landmark or skipped heading levels"
+}
+
+func (t *ListLandmarksTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *ListLandmarksTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+
+		structure, err := t.browserMgr.PageStructure(pageID)
+		t.logger.LogToolExecution(t.Name(), args, err == nil, time.Since(start).Milliseconds())
+		if err != nil {
+			return recipeErrorResponse(fmt.Sprintf("Failed to read page structure: %v", err)), nil
+		}
+
+		issues := structureIssues(structure)
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: formatPageStructure(structure, issues),
+				Data: map[string]interface{}{
+					"page_id":   pageID,
+					"structure": structure,
+					"issues":    issues,
+				},
+			}},
+		}, nil
+	})
+}
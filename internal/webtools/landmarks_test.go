@@ -0,0 +1,72 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestStructureIssues(t *testing.T) {
+	good := &browser.PageStructure{
+		Landmarks: []browser.Landmark{
+			{Role: "banner", Tag: "header", Selector: "header"},
+			{Role: "navigation", Label: "Main", Tag: "nav", Selector: "nav:nth-of-type(1)"},
+			{Role: "navigation", Label: "Footer", Tag: "nav", Selector: "nav:nth-of-type(2)"},
+			{Role: "main", Tag: "main", Selector: "main"},
+		},
+		Headings: []browser.Heading{{Level: 1, Text: "Docs"}, {Level: 2, Text: "Install"}, {Level: 3, Text: "Linux"}, {Level: 2, Text: "Usage"}},
+	}
+	if issues := structureIssues(good); len(issues) != 0 {
+		t.Errorf("well-formed page has issues: %v", issues)
+	}
+	if text := formatPageStructure(good, nil); !strings.Contains(text, `navigation "Main" (nav:nth-of-type(1))`) ||
+		!strings.Contains(text, "\n      h3 Linux") || !strings.HasSuffix(text, "No structural issues found") {
+		t.Errorf("unexpected outline:\n%s", text)
+	}
+
+	bad := &browser.PageStructure{
+		Landmarks: []browser.Landmark{
+			{Role: "navigation", Tag: "nav"},
+			{Role: "navigation", Label: "Footer", Tag: "nav"},
+			{Role: "contentinfo", Tag: "footer"},
+			{Role: "contentinfo", Tag: "footer"},
+		},
+		Headings:     []browser.Heading{{Level: 2, Text: "Intro"}, {Level: 4, Text: ""}},
+		Unnamed:      []browser.UnnamedElement{{Tag: "button", Role: "button", Selector: "button.close"}},
+		UnnamedTotal: 60,
+	}
+	issues := structureIssues(bad)
+	want := []string{
+		"No main landmark",
+		"2 contentinfo landmarks",
+		"2 navigation landmarks, 1 without a label",
+		"No level 1 heading",
+		"h2 -> h4",
+		"1 empty headings",
+		"60 elements have no accessible name",
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v", issues)
+	}
+	for i, w := range want {
+		if !strings.Contains(issues[i], w) {
+			t.Errorf("issue %d = %q, want it to mention %q", i, issues[i], w)
+		}
+	}
+	text := formatPageStructure(bad, issues)
+	for _, w := range []string{"button (button.close)", "... and 59 more", "h4 (empty)"} {
+		if !strings.Contains(text, w) {
+			t.Errorf("outline missing %q:\n%s", w, text)
+		}
+	}
+}
+
+func TestListLandmarksNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewListLandmarksTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("got %+v, %v", resp, err)
+	}
+}
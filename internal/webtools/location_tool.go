@@ -0,0 +1,200 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// measureLocationScript reads back what the page sees after emulation
+const measureLocationScript = `({timezone: Intl.DateTimeFormat().resolvedOptions().timeZone, locale: Intl.DateTimeFormat().resolvedOptions().locale, languages: navigator.languages, now: new Date().toString()})`
+
+// EmulateLocationTool overrides a page's geolocation, timezone and locale
+type EmulateLocationTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewEmulateLocationTool(log *logger.Logger, mgr *browser.Manager) *EmulateLocationTool {
+	return &EmulateLocationTool{logger: log, browserMgr: mgr}
+}
+
+func (t *EmulateLocationTool) Name() string {
+	return "emulate_location"
+}
+
+func (t *EmulateLocationTool) Description() string {
+	return "Make one page believe it is somewhere else: override navigator.geolocation coordinates, the timezone its clocks use, and the locale and Accept-Language it formats and requests content in"
+}
+
+func (t *EmulateLocationTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"set", "reset"},
+				"description": "set the overrides, or reset the page to the browser's own location, timezone and languages",
+				"default":     "set",
+			},
+			"latitude": map[string]interface{}{
+				"type":        "number",
+				"description": "Latitude reported by navigator.geolocation (requires longitude)",
+				"minimum":     -90,
+				"maximum":     90,
+			},
+			"longitude": map[string]interface{}{
+				"type":        "number",
+				"description": "Longitude reported by navigator.geolocation (requires latitude)",
+				"minimum":     -180,
+				"maximum":     180,
+			},
+			"accuracy": map[string]interface{}{
+				"type":        "number",
+				"description": "Accuracy of the position in metres",
+				"default":     100,
+			},
+			"timezone": map[string]interface{}{
+				"type":        "string",
+				"description": "IANA timezone ID such as 'Europe/Berlin' or 'America/New_York'",
+			},
+			"locale": map[string]interface{}{
+				"type":        "string",
+				"description": "Locale such as 'de-DE' for Intl, toLocaleString and date formatting",
+			},
+			"accept_language": map[string]interface{}{
+				"type":        "string",
+				"description": "Accept-Language header and navigator.languages, e.g. 'fr-CH,fr;q=0.9'. Defaults to the locale",
+			},
+			"reload": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Reload the page afterwards, for sites that only read the location or language when they load",
+				"default":     false,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to override (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *EmulateLocationTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "set"
+		}
+		if action != "set" && action != "reset" {
+			return fail("action must be one of set, reset")
+		}
+
+		var location *browser.LocationEmulation
+		if action == "set" {
+			var err error
+			if location, err = locationEmulationFromArgs(args); err != nil {
+				return fail(err.Error())
+			}
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		data := map[string]interface{}{"page_id": pageID}
+		var text string
+		if action == "reset" {
+			if err := t.browserMgr.ClearLocationEmulation(pageID); err != nil {
+				return fail(fmt.Sprintf("Failed to reset location emulation: %v", err))
+			}
+			text = fmt.Sprintf("Page %s is back to the browser's own location, timezone and languages", pageID)
+		} else {
+			if err := t.browserMgr.EmulateLocation(pageID, location); err != nil {
+				return fail(fmt.Sprintf("Failed to emulate location: %v", err))
+			}
+			data["location"] = t.browserMgr.LocationEmulation(pageID)
+			text = fmt.Sprintf("Page %s now emulates %s", pageID, describeLocation(location))
+		}
+
+		// The page's own view of its clock and languages confirms the change took
+		if raw, err := t.browserMgr.ExecuteScript(pageID, measureLocationScript); err == nil {
+			var measured map[string]interface{}
+			if decodeScriptValue(raw, &measured) == nil {
+				data["measured"] = measured
+				text += fmt.Sprintf(" (page reports timezone %v, locale %v)", measured["timezone"], measured["locale"])
+			}
+		}
+
+		if reload, _ := args["reload"].(bool); reload {
+			if _, err := t.browserMgr.ExecuteScript(pageID, "location.reload(); return true;"); err != nil {
+				return fail(fmt.Sprintf("Location applied but reloading failed: %v", err))
+			}
+			data["reloaded"] = true
+			text += "; reloading"
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// locationEmulationFromArgs collects the overrides the caller gave
+func locationEmulationFromArgs(args map[string]interface{}) (*browser.LocationEmulation, error) {
+	l := &browser.LocationEmulation{}
+	latitude, hasLatitude := args["latitude"].(float64)
+	longitude, hasLongitude := args["longitude"].(float64)
+	if hasLatitude != hasLongitude {
+		return nil, fmt.Errorf("give both latitude and longitude")
+	}
+	if hasLatitude {
+		l.Geolocation = &browser.Geolocation{Latitude: latitude, Longitude: longitude}
+		if accuracy, ok := args["accuracy"].(float64); ok {
+			l.Geolocation.Accuracy = accuracy
+		}
+	}
+	l.Timezone, _ = args["timezone"].(string)
+	l.Locale, _ = args["locale"].(string)
+	l.AcceptLanguage, _ = args["accept_language"].(string)
+	l.Timezone = strings.TrimSpace(l.Timezone)
+	l.Locale = strings.TrimSpace(l.Locale)
+	l.AcceptLanguage = strings.TrimSpace(l.AcceptLanguage)
+
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// describeLocation names the overrides in a location for the summary line
+func describeLocation(l *browser.LocationEmulation) string {
+	var parts []string
+	if g := l.Geolocation; g != nil {
+		parts = append(parts, fmt.Sprintf("position %.4f, %.4f", g.Latitude, g.Longitude))
+	}
+	if l.Timezone != "" {
+		parts = append(parts, "timezone "+l.Timezone)
+	}
+	if l.Locale != "" {
+		parts = append(parts, "locale "+l.Locale)
+	}
+	if l.AcceptLanguage != "" {
+		parts = append(parts, "Accept-Language "+l.AcceptLanguage)
+	}
+	return strings.Join(parts, ", ")
+}
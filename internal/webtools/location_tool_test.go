@@ -0,0 +1,48 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLocationEmulationFromArgs(t *testing.T) {
+	l, err := locationEmulationFromArgs(map[string]interface{}{
+		"latitude":  float64(35.6762),
+		"longitude": float64(139.6503),
+		"timezone":  " Asia/Tokyo ",
+		"locale":    "ja-JP",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Geolocation == nil || l.Timezone != "Asia/Tokyo" || l.AcceptLanguage != "ja-JP,ja;q=0.9" {
+		t.Errorf("Tokyo = %+v", l)
+	}
+	if summary := describeLocation(l); !strings.Contains(summary, "position 35.6762, 139.6503") || !strings.Contains(summary, "timezone Asia/Tokyo") {
+		t.Errorf("describeLocation = %q", summary)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"latitude": float64(10)},
+		{"timezone": "not a zone"},
+		{"locale": "!!"},
+	} {
+		if _, err := locationEmulationFromArgs(args); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestEmulateLocationTool(t *testing.T) {
+	tool := NewEmulateLocationTool(createTestLogger(t), nil)
+
+	resp, _ := tool.Execute(map[string]interface{}{"longitude": float64(2.35)})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "latitude and longitude") {
+		t.Errorf("Expected missing latitude error, got %+v", resp)
+	}
+	resp, _ = tool.Execute(map[string]interface{}{"action": "teleport"})
+	if !resp.IsError {
+		t.Error("Expected error for unknown action")
+	}
+}
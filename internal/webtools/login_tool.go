@@ -0,0 +1,583 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/internal/secrets"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// loginFormWait is how long to wait for a sign-in form to render
+	loginFormWait = 5 * time.Second
+
+	defaultLoginVerifyTimeout = 8 * time.Second
+	maxLoginVerifyTimeout     = 15 * time.Second
+
+	loginPollInterval = 250 * time.Millisecond
+)
+
+// loginRecipeKeys are the recipe settings a credential profile's login block
+// may hold and tool arguments may override
+var loginRecipeKeys = []string{
+	"url", "username_selector", "password_selector", "submit_selector",
+	"success_selector", "success_url_contains", "failure_selector",
+}
+
+// loginFields reports which sign-in controls were found and marked
+type loginFields struct {
+	Username bool `json:"username"`
+	Password bool `json:"password"`
+	Submit   bool `json:"submit"`
+}
+
+// loginState is the page as seen while verifying a login
+type loginState struct {
+	URL             string `json:"url"`
+	Ready           string `json:"ready"`
+	SuccessSelector bool   `json:"success_selector"`
+	Failure         string `json:"failure"`
+	PasswordVisible bool   `json:"password_visible"`
+}
+
+// loginFieldSelector is the selector for a control marked by loginFieldsScript
+func loginFieldSelector(role string) string {
+	return fmt.Sprintf(`[data-rodmcp-login="%s"]`, role)
+}
+
+// LoginTool signs in with a stored credential profile and saves the
+// resulting session
+type LoginTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	secrets    *secrets.Store
+	sessions   *sessionStore
+
+	// ask fills in a missing profile or username; nil when the client
+	// cannot be asked
+	ask ElicitFunc
+}
+
+// NewLoginTool creates a login tool reading credentials from store and
+// keeping sessions in sessionDir (default: sessions/ under the working
+// directory). A nil store has no profiles.
+func NewLoginTool(log *logger.Logger, mgr *browser.Manager, store *secrets.Store, sessionDir string) *LoginTool {
+	if store == nil {
+		store = secrets.Empty()
+	}
+	return &LoginTool{
+		logger:     log,
+		browserMgr: mgr,
+		secrets:    store,
+		sessions:   newSessionStore(sessionDir),
+	}
+}
+
+// SetElicitor lets the tool ask the client's user to pick a profile when the
+// named one does not exist, and for the username when the profile has none
+func (t *LoginTool) SetElicitor(fn ElicitFunc) {
+	t.ask = fn
+}
+
+func (t *LoginTool) Name() string {
+	return "login"
+}
+
+func (t *LoginTool) Description() string {
+	return "Sign in with a named credential profile from the secrets file: fills the login form (from the profile's login recipe, the given selectors, or auto-detected username/password fields, including two-step forms), verifies success, and saves the session for reuse. Credentials never appear in arguments or results"
+}
+
+func (t *LoginTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"profile": map[string]interface{}{
+				"type":        "string",
+				"description": "Credential profile name from the secrets file",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "Login page URL (default: the profile's login url; opens a new page unless page_id is given)",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to sign in on (uses current page if neither page_id nor url is available)",
+			},
+			"username_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Username/email input (auto-detected if omitted)",
+			},
+			"password_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Password input (auto-detected if omitted)",
+			},
+			"submit_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Submit button (auto-detected if omitted; Enter is pressed when none is found)",
+			},
+			"success_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Element that only appears when signed in, e.g. an account menu",
+			},
+			"success_url_contains": map[string]interface{}{
+				"type":        "string",
+				"description": "Text the URL contains once signed in, e.g. '/dashboard'",
+			},
+			"failure_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Error message element; when it appears the login fails with its text",
+			},
+			"timeout_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "How long to wait for the success condition (default: 8000, max: 15000). Without a success condition, success means the password field went away",
+				"default":     int(defaultLoginVerifyTimeout / time.Millisecond),
+			},
+			"reuse_session": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Try the saved session first and skip the form if it is still signed in (default: true)",
+				"default":     true,
+			},
+			"save_session": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Save cookies and localStorage after signing in (default: true)",
+				"default":     true,
+			},
+			"session_name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name to save and reuse the session under (default: the profile name)",
+			},
+		},
+		Required: []string{"profile"},
+	}
+}
+
+func (t *LoginTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		profileName, _ := args["profile"].(string)
+		if profileName == "" {
+			return fail("profile is required")
+		}
+		profile, err := t.secrets.Profile(profileName)
+		if err != nil {
+			chosen := t.chooseProfile(profileName)
+			if chosen == "" {
+				return fail(fmt.Sprintf("Error: %v", err))
+			}
+			profileName = chosen
+			if profile, err = t.secrets.Profile(profileName); err != nil {
+				return fail(fmt.Sprintf("Error: %v", err))
+			}
+		}
+		if profile.Password == "" {
+			return fail(fmt.Sprintf("Error: profile %s has no password", profileName))
+		}
+
+		recipe := make(map[string]string, len(loginRecipeKeys))
+		for _, key := range loginRecipeKeys {
+			if val, ok := args[key].(string); ok && val != "" {
+				recipe[key] = val
+			} else if val := profile.Login[key]; val != "" {
+				recipe[key] = val
+			}
+		}
+
+		timeout := defaultLoginVerifyTimeout
+		if val, ok := args["timeout_ms"].(float64); ok {
+			timeout = time.Duration(val) * time.Millisecond
+		}
+		if timeout <= 0 || timeout > maxLoginVerifyTimeout {
+			return fail(fmt.Sprintf("timeout_ms must be between 1 and %d", maxLoginVerifyTimeout.Milliseconds()))
+		}
+
+		sessionName := profileName
+		if val, ok := args["session_name"].(string); ok && val != "" {
+			sessionName = val
+		}
+		if !recipeNamePattern.MatchString(sessionName) {
+			return fail("session_name must be 1-64 letters, digits, '.', '_' or '-'")
+		}
+		reuse, save := true, true
+		if val, ok := args["reuse_session"].(bool); ok {
+			reuse = val
+		}
+		if val, ok := args["save_session"].(bool); ok {
+			save = val
+		}
+
+		pageID, err := t.openLoginPage(args, recipe["url"])
+		if err != nil {
+			return fail(fmt.Sprintf("Error: %v", err))
+		}
+
+		data := map[string]interface{}{
+			"profile": profileName,
+			"page_id": pageID,
+		}
+
+		if reuse && t.sessions.exists(sessionName) {
+			if state, ok := t.tryReuseSession(pageID, sessionName, recipe); ok {
+				data["url"] = state.URL
+				data["reused_session"] = sessionName
+				t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Already signed in as %s using saved session %s", profileName, sessionName),
+						Data: data,
+					}},
+				}, nil
+			}
+		}
+
+		if err := t.fillAndSubmit(pageID, recipe, profile); err != nil {
+			return fail(fmt.Sprintf("Login failed: %v", err))
+		}
+
+		state, verifiedBy, err := t.verifyLogin(pageID, recipe, timeout)
+		if err != nil {
+			return fail(fmt.Sprintf("Login failed: %v", err))
+		}
+		data["url"] = state.URL
+		data["verified_by"] = verifiedBy
+
+		if save {
+			session, err := t.browserMgr.CaptureSession(pageID)
+			if err != nil {
+				return fail(fmt.Sprintf("Signed in, but saving the session failed: %v", err))
+			}
+			path, err := t.sessions.save(sessionName, session)
+			if err != nil {
+				return fail(fmt.Sprintf("Signed in, but saving the session failed: %v", err))
+			}
+			data["session_file"] = path
+			data["cookies"] = len(session.Cookies)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Signed in as %s (verified by %s)", profileName, verifiedBy),
+				Data: data,
+			}},
+		}, nil
+	})
+}
+
+// openLoginPage returns the page to sign in on, opening or navigating to
+// the login URL as needed
+func (t *LoginTool) openLoginPage(args map[string]interface{}, url string) (string, error) {
+	pageID, _ := args["page_id"].(string)
+	if pageID == "" {
+		if url != "" {
+			_, newPageID, err := t.browserMgr.NewPage(url)
+			if err != nil {
+				return "", fmt.Errorf("failed to open %s: %w", url, err)
+			}
+			return newPageID, nil
+		}
+		pageID = t.browserMgr.GetCurrentPageID()
+		if pageID == "" {
+			return "", fmt.Errorf("no page to sign in on; pass url or page_id")
+		}
+		return pageID, nil
+	}
+
+	if url != "" {
+		if err := t.browserMgr.NavigateExistingPage(pageID, url); err != nil {
+			return "", fmt.Errorf("failed to open %s: %w", url, err)
+		}
+	}
+	return pageID, nil
+}
+
+// tryReuseSession restores a saved session, reloads the page and reports
+// whether it is already signed in
+func (t *LoginTool) tryReuseSession(pageID, name string, recipe map[string]string) (*loginState, bool) {
+	session, err := t.sessions.load(name)
+	if err != nil {
+		return nil, false
+	}
+	if _, err := t.browserMgr.RestoreSession(pageID, session); err != nil {
+		return nil, false
+	}
+
+	url := recipe["url"]
+	if url == "" {
+		if info, err := t.browserMgr.GetPageInfo(pageID); err == nil {
+			url, _ = info["url"].(string)
+		}
+	}
+	if err := t.browserMgr.NavigateExistingPage(pageID, url); err != nil {
+		return nil, false
+	}
+	// localStorage can only be written once the page is on its origin
+	if restored, err := t.browserMgr.RestoreSession(pageID, session); err == nil && restored {
+		t.browserMgr.NavigateExistingPage(pageID, url)
+	}
+
+	state, _, err := t.verifyLogin(pageID, recipe, 2*time.Second)
+	return state, err == nil
+}
+
+// fillAndSubmit enters the credentials and submits the form. When only a
+// username field is present (two-step sign-in), it submits that first and
+// waits for the password field.
+func (t *LoginTool) fillAndSubmit(pageID string, recipe map[string]string, profile secrets.Profile) error {
+	fields, err := t.waitForLoginFields(pageID, recipe, func(f loginFields) bool {
+		return f.Password || f.Username
+	})
+	if err != nil {
+		return err
+	}
+
+	username := profile.Username
+	if fields.Username && username == "" {
+		username = t.askUsername(pageID)
+	}
+	if fields.Username && username != "" {
+		if err := t.browserMgr.TypeIntoElement(pageID, loginFieldSelector("username"), username, true, 0); err != nil {
+			return fmt.Errorf("failed to enter username: %w", err)
+		}
+	}
+
+	if !fields.Password {
+		if err := t.submit(pageID, fields, "username"); err != nil {
+			return err
+		}
+		fields, err = t.waitForLoginFields(pageID, recipe, func(f loginFields) bool { return f.Password })
+		if err != nil {
+			return fmt.Errorf("password field did not appear after submitting the username")
+		}
+	}
+
+	if err := t.browserMgr.TypeIntoElement(pageID, loginFieldSelector("password"), profile.Password, true, 0); err != nil {
+		return fmt.Errorf("failed to enter password: %w", err)
+	}
+	return t.submit(pageID, fields, "password")
+}
+
+// chooseProfile asks the user which stored profile to use in place of the
+// unknown one requested, returning "" when they cannot or do not choose
+func (t *LoginTool) chooseProfile(requested string) string {
+	names := t.secrets.Names()
+	if len(names) == 0 {
+		return ""
+	}
+	chosen, err := askString(t.ask,
+		fmt.Sprintf("There is no credential profile named %q. Which profile should be used to sign in?", requested),
+		"profile", "Credential profile", names)
+	if err != nil {
+		t.logger.WithComponent("tools").Debug("Could not ask for a login profile", zap.Error(err))
+	}
+	return chosen
+}
+
+// askUsername asks the user for the username to sign in with when the
+// profile has none, returning "" when they cannot or do not answer. The
+// password is never asked for; it only comes from the secrets file.
+func (t *LoginTool) askUsername(pageID string) string {
+	site := pageID
+	if info, err := t.browserMgr.GetPageInfo(pageID); err == nil {
+		if url, _ := info["url"].(string); url != "" {
+			site = url
+		}
+	}
+	username, err := askString(t.ask,
+		fmt.Sprintf("Which username or email should be used to sign in at %s?", site),
+		"username", "Username or email", nil)
+	if err != nil {
+		t.logger.WithComponent("tools").Debug("Could not ask for a login username", zap.Error(err))
+	}
+	return username
+}
+
+// submit clicks the detected submit control, or presses Enter in the last
+// filled field when there is none
+func (t *LoginTool) submit(pageID string, fields loginFields, lastField string) error {
+	if fields.Submit {
+		script := fmt.Sprintf(`
+			const button = document.querySelector('%s');
+			if (!button) return false;
+			button.click();
+			return true;`, loginFieldSelector("submit"))
+		raw, err := t.browserMgr.ExecuteScript(pageID, script)
+		var clicked bool
+		if err == nil && decodeScriptValue(raw, &clicked) == nil && clicked {
+			return nil
+		}
+	}
+	if err := t.browserMgr.TypeIntoElement(pageID, loginFieldSelector(lastField), "\n", false, 0); err != nil {
+		return fmt.Errorf("failed to submit the login form: %w", err)
+	}
+	return nil
+}
+
+// waitForLoginFields polls until ready accepts the detected fields
+func (t *LoginTool) waitForLoginFields(pageID string, recipe map[string]string, ready func(loginFields) bool) (loginFields, error) {
+	script := loginFieldsScript(recipe["username_selector"], recipe["password_selector"], recipe["submit_selector"])
+	deadline := time.Now().Add(loginFormWait)
+	for {
+		var fields loginFields
+		raw, err := t.browserMgr.ExecuteScript(pageID, script)
+		if err == nil && decodeScriptValue(raw, &fields) == nil && ready(fields) {
+			return fields, nil
+		}
+		if time.Now().After(deadline) {
+			return loginFields{}, fmt.Errorf("no login form found (set username_selector and password_selector if it is unusual)")
+		}
+		time.Sleep(loginPollInterval)
+	}
+}
+
+// verifyLogin polls the page until the success condition holds, the failure
+// selector appears or the timeout passes. It returns which condition
+// confirmed the login.
+func (t *LoginTool) verifyLogin(pageID string, recipe map[string]string, timeout time.Duration) (*loginState, string, error) {
+	script := loginStateScript(recipe["success_selector"], recipe["failure_selector"])
+	successURL := recipe["success_url_contains"]
+	deadline := time.Now().Add(timeout)
+	var last loginState
+	for {
+		var state loginState
+		// Errors are expected while the page navigates after submitting
+		raw, err := t.browserMgr.ExecuteScript(pageID, script)
+		if err == nil && decodeScriptValue(raw, &state) == nil {
+			last = state
+			if state.Failure != "" {
+				return &state, "", fmt.Errorf("the site reported: %s", state.Failure)
+			}
+			if verifiedBy := loginVerified(state, recipe["success_selector"], successURL); verifiedBy != "" {
+				return &state, verifiedBy, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(loginPollInterval)
+	}
+
+	if recipe["success_selector"] == "" && successURL == "" {
+		return &last, "", fmt.Errorf("the password field is still showing after %s; the credentials may be wrong", timeout)
+	}
+	return &last, "", fmt.Errorf("success condition not met within %s (page is at %s)", timeout, last.URL)
+}
+
+// loginVerified names the condition showing state is signed in, or returns
+// "" when it is not (yet). Without explicit conditions, a loaded page
+// without a password field counts as signed in.
+func loginVerified(state loginState, successSelector, successURL string) string {
+	switch {
+	case successSelector != "" && state.SuccessSelector:
+		return "success_selector"
+	case successURL != "" && strings.Contains(state.URL, successURL):
+		return "success_url_contains"
+	case successSelector == "" && successURL == "" && state.Ready == "complete" && !state.PasswordVisible:
+		return "password_field_gone"
+	}
+	return ""
+}
+
+// loginFieldsScript finds the username, password and submit controls, using
+// the given selectors where set, and marks them with data-rodmcp-login so
+// later steps can address them
+func loginFieldsScript(usernameSel, passwordSel, submitSel string) string {
+	marks, _ := json.Marshal(map[string]string{
+		"username": usernameSel,
+		"password": passwordSel,
+		"submit":   submitSel,
+	})
+	return fmt.Sprintf(`
+		const marks = %s;
+		document.querySelectorAll('[data-rodmcp-login]').forEach(e => e.removeAttribute('data-rodmcp-login'));
+
+		const visible = (el) => {
+			const r = el.getBoundingClientRect();
+			const s = getComputedStyle(el);
+			return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none' && !el.disabled;
+		};
+		const all = (sel, scope) => {
+			try { return Array.from((scope || document).querySelectorAll(sel)).filter(visible); } catch (e) { return []; }
+		};
+
+		const password = marks.password ? all(marks.password)[0] :
+			(all('input[type="password"]:not([autocomplete="new-password"])')[0] || all('input[type="password"]')[0]);
+
+		let username = null;
+		if (marks.username) {
+			username = all(marks.username)[0];
+		} else {
+			const scope = (password && password.form) || document;
+			const textual = all('input', scope).filter(e =>
+				['', 'text', 'email', 'tel'].includes((e.getAttribute('type') || '').toLowerCase()));
+			const named = /user|email|login|account|ident/i;
+			username = textual.find(e => /username|email/.test(e.getAttribute('autocomplete') || '')) ||
+				textual.find(e => named.test((e.name || '') + ' ' + (e.id || '') + ' ' + (e.getAttribute('placeholder') || '')));
+			if (!username && password) {
+				// The text input closest before the password field
+				const before = textual.filter(e => e.compareDocumentPosition(password) & Node.DOCUMENT_POSITION_FOLLOWING);
+				username = before[before.length - 1];
+			}
+		}
+
+		let submit = null;
+		if (marks.submit) {
+			submit = all(marks.submit)[0];
+		} else {
+			const form = (password && password.form) || (username && username.form);
+			if (form) {
+				submit = all('button[type="submit"], input[type="submit"], button:not([type])', form)[0];
+			}
+			if (!submit) {
+				const label = /^(log ?in|sign ?in|continue|next|submit)$/i;
+				submit = all('button, [role="button"], input[type="button"]').find(e =>
+					label.test((e.innerText || e.value || '').trim()));
+			}
+		}
+
+		if (username) username.setAttribute('data-rodmcp-login', 'username');
+		if (password) password.setAttribute('data-rodmcp-login', 'password');
+		if (submit) submit.setAttribute('data-rodmcp-login', 'submit');
+		return { username: !!username, password: !!password, submit: !!submit };
+	`, marks)
+}
+
+// loginStateScript reports what the page shows after submitting
+func loginStateScript(successSel, failureSel string) string {
+	success, _ := json.Marshal(successSel)
+	failure, _ := json.Marshal(failureSel)
+	return fmt.Sprintf(`
+		const successSel = %s;
+		const failureSel = %s;
+		const visible = (el) => {
+			const r = el.getBoundingClientRect();
+			const s = getComputedStyle(el);
+			return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+		};
+		const find = (sel) => {
+			if (!sel) return null;
+			try { return Array.from(document.querySelectorAll(sel)).find(visible) || null; } catch (e) { return null; }
+		};
+		const failure = find(failureSel);
+		return {
+			url: location.href,
+			ready: document.readyState,
+			success_selector: !!find(successSel),
+			failure: failure ? (failure.innerText || failure.textContent || 'login error').trim().slice(0, 200) : '',
+			password_visible: !!find('input[type="password"]')
+		};
+	`, success, failure)
+}
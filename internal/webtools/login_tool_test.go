@@ -0,0 +1,109 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/secrets"
+)
+
+func TestLoginVerified(t *testing.T) {
+	loaded := loginState{URL: "https://example.com/home", Ready: "complete"}
+
+	cases := []struct {
+		name       string
+		state      loginState
+		successSel string
+		successURL string
+		want       string
+	}{
+		{"selector present", loginState{SuccessSelector: true}, ".account", "", "success_selector"},
+		{"selector missing", loginState{Ready: "complete"}, ".account", "", ""},
+		{"url matches", loaded, "", "/home", "success_url_contains"},
+		{"url differs", loaded, "", "/dashboard", ""},
+		{"password gone", loaded, "", "", "password_field_gone"},
+		{"password still showing", loginState{Ready: "complete", PasswordVisible: true}, "", "", ""},
+		{"still loading", loginState{Ready: "loading"}, "", "", ""},
+	}
+	for _, tc := range cases {
+		if got := loginVerified(tc.state, tc.successSel, tc.successURL); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "sessions")
+	store := newSessionStore(dir)
+
+	if store.exists("github") {
+		t.Fatal("Empty store reports a session")
+	}
+	state := &browser.SessionState{
+		URL:          "https://github.com/",
+		Origin:       "https://github.com",
+		LocalStorage: map[string]string{"theme": "dark"},
+	}
+	path, err := store.save("github", state)
+	if err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("session file mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	loaded, err := store.load("github")
+	if err != nil || loaded.Origin != state.Origin || loaded.LocalStorage["theme"] != "dark" {
+		t.Errorf("load = %+v, %v", loaded, err)
+	}
+	if _, err := store.load("../github"); err == nil {
+		t.Error("Expected error for path-like session name")
+	}
+}
+
+func TestLoginToolValidation(t *testing.T) {
+	t.Setenv("RODMCP_TEST_LOGIN_PASSWORD", "pw")
+	secretsPath := filepath.Join(t.TempDir(), "secrets.json")
+	os.WriteFile(secretsPath, []byte(`{"profiles": {
+		"site": {"username": "me", "password": "env:RODMCP_TEST_LOGIN_PASSWORD"},
+		"nopass": {"username": "me"}
+	}}`), 0600)
+	store, err := secrets.Load(secretsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewLoginTool(createTestLogger(t), nil, store, t.TempDir())
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"no profile", map[string]interface{}{}, "profile is required"},
+		{"unknown profile", map[string]interface{}{"profile": "other"}, "other"},
+		{"no password", map[string]interface{}{"profile": "nopass"}, "no password"},
+		{"bad timeout", map[string]interface{}{"profile": "site", "timeout_ms": float64(60000)}, "timeout_ms"},
+		{"bad session name", map[string]interface{}{"profile": "site", "session_name": "../x"}, "session_name"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+			if resp != nil && strings.Contains(resp.Content[0].Text, "pw") {
+				t.Error("Response leaked the password")
+			}
+		})
+	}
+
+	unconfigured := NewLoginTool(createTestLogger(t), nil, nil, t.TempDir())
+	resp, _ := unconfigured.Execute(map[string]interface{}{"profile": "site"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "--secrets-file") {
+		t.Errorf("Expected --secrets-file hint, got %+v", resp)
+	}
+}
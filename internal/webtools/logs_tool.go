@@ -0,0 +1,435 @@
+package webtools
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// serverLogBaseName is the active log file written by the logger package
+	serverLogBaseName = "rodmcp"
+	defaultLogLimit   = 100
+	maxLogLimit       = 1000
+)
+
+// logPayloadFields are the fields the server logs call payloads under: the
+// params of MCP requests, tool call arguments and results. They can hold
+// credentials, headers and page content from any client's calls, so they
+// are never returned.
+var logPayloadFields = []string{"params", "args", "result"}
+
+// logLevelRank orders zap levels so a minimum level filter can be applied
+var logLevelRank = map[string]int{
+	"debug":  0,
+	"info":   1,
+	"warn":   2,
+	"error":  3,
+	"dpanic": 4,
+	"panic":  5,
+	"fatal":  6,
+}
+
+// LogEntry is a single parsed line from the server log files
+type LogEntry struct {
+	Time      time.Time              `json:"time"`
+	Level     string                 `json:"level"`
+	Component string                 `json:"component,omitempty"`
+	Message   string                 `json:"message"`
+	Caller    string                 `json:"caller,omitempty"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+}
+
+// LogFilter describes which log entries should be returned
+type LogFilter struct {
+	Component string
+	MinLevel  string
+	Since     time.Time
+	Until     time.Time
+	Contains  string
+	Limit     int
+}
+
+// GetServerLogsTool reads recent entries from the rotating server log files
+type GetServerLogsTool struct {
+	logger *logger.Logger
+	logDir string
+}
+
+func NewGetServerLogsTool(log *logger.Logger, logDir string) *GetServerLogsTool {
+	return &GetServerLogsTool{
+		logger: log,
+		logDir: logDir,
+	}
+}
+
+func (t *GetServerLogsTool) Name() string {
+	return "get_server_logs"
+}
+
+func (t *GetServerLogsTool) Description() string {
+	return "Return recent RodMCP server log entries filtered by component, level, and time range so failures can be diagnosed without shell access. Call arguments, request params and results are left out"
+}
+
+func (t *GetServerLogsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"component": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return entries logged by this component",
+				"examples":    []string{"tools", "browser", "mcp", "http"},
+			},
+			"level": map[string]interface{}{
+				"type":        "string",
+				"description": "Minimum log level to include",
+				"enum":        []string{"debug", "info", "warn", "error"},
+				"default":     "info",
+			},
+			"since": map[string]interface{}{
+				"type":        "string",
+				"description": "Start of the time range: RFC3339 timestamp or a duration relative to now (e.g. '15m', '2h')",
+				"examples":    []string{"10m", "1h", "2025-01-02T15:04:05Z"},
+			},
+			"until": map[string]interface{}{
+				"type":        "string",
+				"description": "End of the time range: RFC3339 timestamp or a duration relative to now",
+			},
+			"contains": map[string]interface{}{
+				"type":        "string",
+				"description": "Only return entries whose message contains this text (case-insensitive)",
+			},
+			"limit": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum number of entries to return (most recent first)",
+				"default":     defaultLogLimit,
+				"minimum":     1,
+				"maximum":     maxLogLimit,
+			},
+		},
+	}
+}
+
+func (t *GetServerLogsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	start := time.Now()
+
+	filter, err := parseLogFilter(args, start)
+	if err != nil {
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error: %v", err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	type result struct {
+		entries []LogEntry
+		scanned int
+		err     error
+	}
+	resultChan := make(chan result, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				resultChan <- result{err: fmt.Errorf("panic while reading logs: %v", r)}
+			}
+		}()
+		entries, scanned, err := ReadServerLogs(ctx, t.logDir, filter)
+		resultChan <- result{entries: entries, scanned: scanned, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-resultChan:
+	case <-ctx.Done():
+		res = result{err: fmt.Errorf("reading logs timed out after 30 seconds")}
+	}
+
+	if res.err != nil {
+		t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Error reading server logs: %v", res.err),
+			}},
+			IsError: true,
+		}, nil
+	}
+
+	t.logger.WithComponent("tools").Debug("Server logs retrieved",
+		zap.Int("returned", len(res.entries)),
+		zap.Int("scanned", res.scanned))
+	t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Returned %d of %d scanned log entries (most recent first)\n", len(res.entries), res.scanned)
+	for _, e := range res.entries {
+		component := e.Component
+		if component == "" {
+			component = "-"
+		}
+		fmt.Fprintf(&text, "%s [%s] %s: %s\n", e.Time.Format(time.RFC3339), strings.ToUpper(e.Level), component, e.Message)
+	}
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text.String(),
+			Data: map[string]interface{}{
+				"log_dir": t.logDir,
+				"entries": res.entries,
+				"count":   len(res.entries),
+				"scanned": res.scanned,
+			},
+		}},
+	}, nil
+}
+
+// parseLogFilter converts tool arguments into a LogFilter
+func parseLogFilter(args map[string]interface{}, now time.Time) (LogFilter, error) {
+	filter := LogFilter{
+		MinLevel: "info",
+		Limit:    defaultLogLimit,
+	}
+
+	if component, ok := args["component"].(string); ok {
+		filter.Component = component
+	}
+	if level, ok := args["level"].(string); ok && level != "" {
+		level = strings.ToLower(level)
+		if _, known := logLevelRank[level]; !known {
+			return filter, fmt.Errorf("unknown log level %q (use debug, info, warn, or error)", level)
+		}
+		filter.MinLevel = level
+	}
+	if contains, ok := args["contains"].(string); ok {
+		filter.Contains = contains
+	}
+	if limit, ok := args["limit"].(float64); ok {
+		filter.Limit = int(limit)
+	}
+	if filter.Limit < 1 {
+		filter.Limit = 1
+	}
+	if filter.Limit > maxLogLimit {
+		filter.Limit = maxLogLimit
+	}
+
+	if since, ok := args["since"].(string); ok && since != "" {
+		ts, err := parseLogTime(since, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+		filter.Since = ts
+	}
+	if until, ok := args["until"].(string); ok && until != "" {
+		ts, err := parseLogTime(until, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+		filter.Until = ts
+	}
+
+	return filter, nil
+}
+
+// parseLogTime accepts an RFC3339 timestamp or a duration before now
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if ts, err := time.Parse(time.RFC3339, value); err == nil {
+		return ts, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither an RFC3339 timestamp nor a duration", value)
+}
+
+// ReadServerLogs scans the active log file and its rotated backups, newest
+// file first, returning up to filter.Limit matching entries (most recent first)
+// along with the number of entries scanned.
+func ReadServerLogs(ctx context.Context, logDir string, filter LogFilter) ([]LogEntry, int, error) {
+	files, err := serverLogFiles(logDir)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matched []LogEntry
+	scanned := 0
+	for _, file := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, scanned, err
+		}
+
+		entries, err := readLogFile(file)
+		if err != nil {
+			return nil, scanned, err
+		}
+		scanned += len(entries)
+
+		// Entries within a file are chronological; walk backwards for recency
+		for i := len(entries) - 1; i >= 0; i-- {
+			if filter.matches(entries[i]) {
+				matched = append(matched, entries[i])
+				if len(matched) >= filter.Limit {
+					return matched, scanned, nil
+				}
+			}
+		}
+	}
+
+	return matched, scanned, nil
+}
+
+// serverLogFiles lists the active log and lumberjack backups, newest first
+func serverLogFiles(logDir string) ([]string, error) {
+	dirEntries, err := os.ReadDir(logDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read log directory %s: %w", logDir, err)
+	}
+
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []logFile
+	for _, entry := range dirEntries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, serverLogBaseName) {
+			continue
+		}
+		if !strings.HasSuffix(name, ".log") && !strings.HasSuffix(name, ".log.gz") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, logFile{path: filepath.Join(logDir, name), modTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.path
+	}
+	return paths, nil
+}
+
+// readLogFile parses every JSON line in a (possibly gzipped) log file
+func readLogFile(path string) ([]LogEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var reader io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress log file %s: %w", path, err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if entry, ok := parseLogLine(scanner.Bytes()); ok {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read log file %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// parseLogLine decodes a zap JSON line written with either the production
+// (level/ts/msg/caller) or development (L/T/M/C) encoder keys.
+func parseLogLine(line []byte) (LogEntry, bool) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return LogEntry{}, false
+	}
+
+	take := func(keys ...string) string {
+		for _, key := range keys {
+			if v, ok := raw[key].(string); ok {
+				delete(raw, key)
+				return v
+			}
+		}
+		return ""
+	}
+
+	entry := LogEntry{
+		Level:     strings.ToLower(take("level", "L")),
+		Message:   take("msg", "M"),
+		Caller:    take("caller", "C"),
+		Component: take("component"),
+	}
+	if ts := take("ts", "T"); ts != "" {
+		parsed, err := time.Parse("2006-01-02T15:04:05.000Z0700", ts)
+		if err != nil {
+			parsed, err = time.Parse(time.RFC3339Nano, ts)
+		}
+		if err == nil {
+			entry.Time = parsed
+		}
+	}
+	delete(raw, "stacktrace")
+	delete(raw, "S")
+	for _, field := range logPayloadFields {
+		delete(raw, field)
+	}
+	if len(raw) > 0 {
+		entry.Fields = raw
+	}
+
+	return entry, entry.Level != "" || entry.Message != ""
+}
+
+func (f LogFilter) matches(e LogEntry) bool {
+	if f.Component != "" && e.Component != f.Component {
+		return false
+	}
+	if f.MinLevel != "" {
+		if rank, ok := logLevelRank[e.Level]; ok && rank < logLevelRank[f.MinLevel] {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(strings.ToLower(e.Message), strings.ToLower(f.Contains)) {
+		return false
+	}
+	return true
+}
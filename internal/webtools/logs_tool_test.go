@@ -0,0 +1,160 @@
+package webtools
+
+import (
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTestLog(t *testing.T, path string, lines []string, gz bool) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	content := strings.Join(lines, "\n") + "\n"
+	if gz {
+		w := gzip.NewWriter(f)
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadServerLogsFiltering(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestLog(t, filepath.Join(dir, "rodmcp-2025-01-01T00-00-00.000.log.gz"), []string{
+		`{"level":"error","ts":"2025-01-01T09:00:00.000Z","msg":"old browser crash","component":"browser"}`,
+	}, true)
+	writeTestLog(t, filepath.Join(dir, "rodmcp.log"), []string{
+		`{"level":"info","ts":"2025-01-01T10:00:00.000Z","msg":"tool executed","component":"tools"}`,
+		`{"level":"debug","ts":"2025-01-01T10:00:01.000Z","msg":"debug noise","component":"tools"}`,
+		`not json at all`,
+		`{"L":"ERROR","T":"2025-01-01T10:00:02.000Z","M":"browser restart failed","component":"browser","error":"boom"}`,
+	}, false)
+
+	// Make sure the rotated backup sorts as older than the active file
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes(filepath.Join(dir, "rodmcp-2025-01-01T00-00-00.000.log.gz"), old, old)
+
+	ctx := context.Background()
+
+	entries, scanned, err := ReadServerLogs(ctx, dir, LogFilter{MinLevel: "info", Limit: 10})
+	if err != nil {
+		t.Fatalf("ReadServerLogs failed: %v", err)
+	}
+	if scanned != 4 {
+		t.Errorf("Expected 4 scanned entries, got %d", scanned)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries at info and above, got %d", len(entries))
+	}
+	if entries[0].Message != "browser restart failed" || entries[0].Fields["error"] != "boom" {
+		t.Errorf("Expected most recent development-encoded entry first, got %+v", entries[0])
+	}
+	if entries[2].Message != "old browser crash" {
+		t.Errorf("Expected rotated backup entry last, got %q", entries[2].Message)
+	}
+
+	entries, _, err = ReadServerLogs(ctx, dir, LogFilter{Component: "browser", MinLevel: "error", Limit: 1})
+	if err != nil {
+		t.Fatalf("ReadServerLogs failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Message != "browser restart failed" {
+		t.Errorf("Expected limit to keep only the newest browser error, got %+v", entries)
+	}
+
+	since, _ := time.Parse(time.RFC3339, "2025-01-01T09:30:00Z")
+	until, _ := time.Parse(time.RFC3339, "2025-01-01T10:00:01Z")
+	entries, _, err = ReadServerLogs(ctx, dir, LogFilter{MinLevel: "debug", Since: since, Until: until, Limit: 10})
+	if err != nil {
+		t.Fatalf("ReadServerLogs failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("Expected 2 entries in time range, got %d", len(entries))
+	}
+}
+
+func TestParseLogFilter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	filter, err := parseLogFilter(map[string]interface{}{
+		"level": "WARN",
+		"since": "15m",
+		"limit": float64(5000),
+	}, now)
+	if err != nil {
+		t.Fatalf("parseLogFilter failed: %v", err)
+	}
+	if filter.MinLevel != "warn" {
+		t.Errorf("Expected level warn, got %s", filter.MinLevel)
+	}
+	if !filter.Since.Equal(now.Add(-15 * time.Minute)) {
+		t.Errorf("Expected since to be 15 minutes before now, got %v", filter.Since)
+	}
+	if filter.Limit != maxLogLimit {
+		t.Errorf("Expected limit to be capped at %d, got %d", maxLogLimit, filter.Limit)
+	}
+
+	if _, err := parseLogFilter(map[string]interface{}{"level": "verbose"}, now); err == nil {
+		t.Error("Expected error for unknown level")
+	}
+	if _, err := parseLogFilter(map[string]interface{}{"since": "yesterday"}, now); err == nil {
+		t.Error("Expected error for unparseable since")
+	}
+}
+
+func TestGetServerLogsToolExecute(t *testing.T) {
+	dir := t.TempDir()
+	writeTestLog(t, filepath.Join(dir, "rodmcp.log"), []string{
+		`{"level":"warn","ts":"2025-01-01T10:00:00.000Z","msg":"slow navigation","component":"browser"}`,
+	}, false)
+
+	tool := NewGetServerLogsTool(createTestLogger(t), dir)
+	resp, err := tool.Execute(map[string]interface{}{"component": "browser"})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if resp.IsError {
+		t.Fatalf("Expected success, got %s", resp.Content[0].Text)
+	}
+	if !strings.Contains(resp.Content[0].Text, "slow navigation") {
+		t.Errorf("Expected log message in output, got %s", resp.Content[0].Text)
+	}
+
+	missing := NewGetServerLogsTool(createTestLogger(t), filepath.Join(dir, "missing"))
+	resp, _ = missing.Execute(map[string]interface{}{})
+	if !resp.IsError {
+		t.Error("Expected error for missing log directory")
+	}
+}
+
+func TestParseLogLineDropsCallPayloads(t *testing.T) {
+	entry, ok := parseLogLine([]byte(`{"level":"info","ts":"2025-01-01T10:00:00.000Z","msg":"MCP request","component":"mcp",` +
+		`"method":"tools/call","params":{"name":"type_text","arguments":{"text":"hunter2"}},"args":{"text":"hunter2"},"result":{"cookie":"sid=1"}}`))
+	if !ok {
+		t.Fatal("Expected the line to parse")
+	}
+	for _, field := range []string{"params", "args", "result"} {
+		if _, kept := entry.Fields[field]; kept {
+			t.Errorf("Expected %s to be left out, got %v", field, entry.Fields)
+		}
+	}
+	if entry.Fields["method"] != "tools/call" {
+		t.Errorf("Expected other fields to stay, got %v", entry.Fields)
+	}
+}
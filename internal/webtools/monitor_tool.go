@@ -0,0 +1,901 @@
+package webtools
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultMonitorDir holds baselines and change logs when no directory
+	// is configured, relative to the working directory
+	defaultMonitorDir = "monitors"
+
+	defaultMonitorInterval = 5 * time.Minute
+	minMonitorInterval     = 30 * time.Second
+
+	// pixelTolerance is how far a colour channel may drift (0-255) before a
+	// pixel counts as changed, so antialiasing noise is not reported
+	pixelTolerance = 16
+
+	defaultMonitorHistory = 20
+)
+
+// monitorConfig describes what a monitor loads and how it compares runs
+type monitorConfig struct {
+	ID                string                 `json:"id"`
+	URL               string                 `json:"url"`
+	Mode              string                 `json:"mode"` // "content" or "screenshot"
+	Recipe            string                 `json:"recipe,omitempty"`
+	Selectors         map[string]interface{} `json:"selectors,omitempty"`
+	ExtractType       string                 `json:"extract_type,omitempty"`
+	ContainerSelector string                 `json:"container_selector,omitempty"`
+	WaitFor           string                 `json:"wait_for,omitempty"`
+	RegionSelector    string                 `json:"region_selector,omitempty"`
+	IntervalSeconds   int                    `json:"interval_seconds"`
+	Threshold         float64                `json:"threshold"`
+}
+
+// fieldChange is one extracted value that differs from the baseline
+type fieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// MonitorChange is recorded and sent to the change handler whenever a
+// monitored page differs from its baseline by at least the threshold
+type MonitorChange struct {
+	MonitorID   string        `json:"monitor_id"`
+	URL         string        `json:"url"`
+	Mode        string        `json:"mode"`
+	DetectedAt  time.Time     `json:"detected_at"`
+	ChangeRatio float64       `json:"change_ratio"`
+	Changes     []fieldChange `json:"changes,omitempty"`
+	Screenshot  string        `json:"screenshot,omitempty"`
+}
+
+// monitorCheck is the outcome of one run of a monitor
+type monitorCheck struct {
+	Baseline    bool           `json:"baseline"` // first run; nothing to compare with
+	ChangeRatio float64        `json:"change_ratio"`
+	Changed     bool           `json:"changed"`
+	Change      *MonitorChange `json:"change,omitempty"`
+}
+
+// pageMonitor is a monitor running in the background
+type pageMonitor struct {
+	config monitorConfig
+	cancel context.CancelFunc
+
+	mu          sync.Mutex
+	checks      int
+	changes     int
+	lastChecked time.Time
+	lastError   string
+}
+
+func (m *pageMonitor) status() map[string]interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := map[string]interface{}{
+		"id":               m.config.ID,
+		"url":              m.config.URL,
+		"mode":             m.config.Mode,
+		"interval_seconds": m.config.IntervalSeconds,
+		"threshold":        m.config.Threshold,
+		"checks":           m.checks,
+		"changes":          m.changes,
+	}
+	if m.config.Recipe != "" {
+		status["recipe"] = m.config.Recipe
+	}
+	if !m.lastChecked.IsZero() {
+		status["last_checked"] = m.lastChecked.Format(time.RFC3339)
+	}
+	if m.lastError != "" {
+		status["last_error"] = m.lastError
+	}
+	return status
+}
+
+// MonitorPageTool watches pages for content or visual changes
+type MonitorPageTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	scraper    *ScreenScrapeTool
+	recipes    *recipeStore
+	dir        string
+
+	mu       sync.Mutex
+	monitors map[string]*pageMonitor
+	onChange func(MonitorChange)
+	gate     JobGate
+}
+
+// JobGate waits for a worker to run background work on and returns the
+// function that frees it (see mcp.HTTPServer.AcquireJobSlot)
+type JobGate func(ctx context.Context) (release func(), err error)
+
+// NewMonitorPageTool creates a monitor_page tool. Recipes are read from
+// recipeDir and baselines and change logs are kept under monitorDir (default:
+// monitors/ under the working directory).
+func NewMonitorPageTool(log *logger.Logger, mgr *browser.Manager, recipeDir, monitorDir string) *MonitorPageTool {
+	if monitorDir == "" {
+		monitorDir = defaultMonitorDir
+	}
+	return &MonitorPageTool{
+		logger:     log,
+		browserMgr: mgr,
+		scraper:    NewScreenScrapeTool(log, mgr),
+		recipes:    newRecipeStore(recipeDir),
+		dir:        monitorDir,
+		monitors:   make(map[string]*pageMonitor),
+	}
+}
+
+// SetChangeHandler registers a callback for detected changes, used to forward
+// them to the client as notifications
+func (t *MonitorPageTool) SetChangeHandler(fn func(MonitorChange)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.onChange = fn
+}
+
+// SetJobGate makes background checks wait for a worker, behind the
+// server's interactive calls
+func (t *MonitorPageTool) SetJobGate(gate JobGate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gate = gate
+}
+
+// StopAll stops every background monitor, for server shutdown
+func (t *MonitorPageTool) StopAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, m := range t.monitors {
+		m.cancel()
+		delete(t.monitors, id)
+	}
+}
+
+func (t *MonitorPageTool) Name() string {
+	return "monitor_page"
+}
+
+func (t *MonitorPageTool) Description() string {
+	return "Watch a page for changes: periodically reload it, compare extracted content (selectors or a saved scrape recipe) or a screenshot region with the last recorded version, and record changes above a threshold. Changes are pushed as " + types.MonitorChangeNotificationMethod + " notifications"
+}
+
+func (t *MonitorPageTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "start a background monitor, stop one, list running monitors, check once now, or show a monitor's recorded history",
+				"enum":        []string{"start", "stop", "list", "check", "history"},
+				"default":     "start",
+			},
+			"id": map[string]interface{}{
+				"type":        "string",
+				"description": "Monitor ID. Baselines are kept per ID, so checking or restarting with the same ID continues from its last recorded state (required except for start and list)",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "URL to monitor (start and check)",
+			},
+			"mode": map[string]interface{}{
+				"type":        "string",
+				"description": "Compare extracted content or a screenshot (default: content)",
+				"enum":        []string{"content", "screenshot"},
+				"default":     "content",
+			},
+			"recipe": map[string]interface{}{
+				"type":        "string",
+				"description": "Saved scrape recipe to extract with (content mode)",
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Selector schema in screen_scrape format (content mode, when no recipe is given)",
+				"additionalProperties": scrapeSelectorValueSchema(),
+			},
+			"extract_type": map[string]interface{}{
+				"type":        "string",
+				"description": "'single' or 'multiple' as in screen_scrape",
+				"enum":        []string{"single", "multiple"},
+			},
+			"container_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Container selector for multiple extraction",
+			},
+			"wait_for": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to wait for before extracting",
+			},
+			"region_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Element to screenshot in screenshot mode (default: the viewport)",
+			},
+			"interval_seconds": map[string]interface{}{
+				"type":        "integer",
+				"description": "Seconds between checks (default: 300, minimum: 30)",
+				"default":     300,
+				"minimum":     30,
+			},
+			"threshold": map[string]interface{}{
+				"type":        "number",
+				"description": "Fraction of fields or pixels (0-1) that must differ before a change is recorded (default: 0, any change)",
+				"default":     0,
+				"minimum":     0,
+				"maximum":     1,
+			},
+			"limit": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of recent changes to return for history (default: 20)",
+				"default":     defaultMonitorHistory,
+			},
+		},
+	}
+}
+
+func (t *MonitorPageTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "start"
+		}
+
+		var resp *types.CallToolResponse
+		switch action {
+		case "start":
+			resp = t.start(args)
+		case "stop":
+			resp = t.stop(args)
+		case "list":
+			resp = t.list()
+		case "check":
+			resp = t.checkOnce(args)
+		case "history":
+			resp = t.history(args)
+		default:
+			resp = recipeErrorResponse(fmt.Sprintf("Error: unknown action %q (use start, stop, list, check, or history)", action))
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, !resp.IsError, time.Since(start).Milliseconds())
+		return resp, nil
+	})
+}
+
+// parseMonitorConfig validates the arguments for start and check
+func (t *MonitorPageTool) parseMonitorConfig(args map[string]interface{}) (monitorConfig, error) {
+	cfg := monitorConfig{
+		IntervalSeconds: int(defaultMonitorInterval / time.Second),
+		Mode:            "content",
+	}
+	cfg.ID, _ = args["id"].(string)
+	cfg.URL, _ = args["url"].(string)
+	if cfg.URL == "" {
+		return cfg, fmt.Errorf("url is required")
+	}
+	if cfg.ID == "" {
+		cfg.ID = fmt.Sprintf("monitor-%d", time.Now().UnixNano()/int64(time.Millisecond))
+	}
+	if !recipeNamePattern.MatchString(cfg.ID) {
+		return cfg, fmt.Errorf("id must be 1-64 letters, digits, '.', '_' or '-'")
+	}
+
+	if mode, ok := args["mode"].(string); ok && mode != "" {
+		cfg.Mode = mode
+	}
+	cfg.Recipe, _ = args["recipe"].(string)
+	cfg.Selectors, _ = args["selectors"].(map[string]interface{})
+	cfg.ExtractType, _ = args["extract_type"].(string)
+	cfg.ContainerSelector, _ = args["container_selector"].(string)
+	cfg.WaitFor, _ = args["wait_for"].(string)
+	cfg.RegionSelector, _ = args["region_selector"].(string)
+
+	switch cfg.Mode {
+	case "content":
+		if cfg.Recipe != "" {
+			if _, err := t.recipes.load(cfg.Recipe); err != nil {
+				return cfg, err
+			}
+		} else if len(cfg.Selectors) == 0 {
+			return cfg, fmt.Errorf("content mode needs a recipe or selectors")
+		} else if _, err := parseScrapeFields(cfg.Selectors); err != nil {
+			return cfg, err
+		}
+	case "screenshot":
+	default:
+		return cfg, fmt.Errorf("mode must be 'content' or 'screenshot'")
+	}
+
+	if val, ok := args["interval_seconds"].(float64); ok {
+		cfg.IntervalSeconds = int(val)
+	}
+	if time.Duration(cfg.IntervalSeconds)*time.Second < minMonitorInterval {
+		return cfg, fmt.Errorf("interval_seconds must be at least %d", int(minMonitorInterval/time.Second))
+	}
+	if val, ok := args["threshold"].(float64); ok {
+		cfg.Threshold = val
+	}
+	if cfg.Threshold < 0 || cfg.Threshold > 1 {
+		return cfg, fmt.Errorf("threshold must be between 0 and 1")
+	}
+	return cfg, nil
+}
+
+func (t *MonitorPageTool) start(args map[string]interface{}) *types.CallToolResponse {
+	cfg, err := t.parseMonitorConfig(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+
+	t.mu.Lock()
+	if _, exists := t.monitors[cfg.ID]; exists {
+		t.mu.Unlock()
+		return recipeErrorResponse(fmt.Sprintf("Error: monitor %s is already running; stop it first", cfg.ID))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &pageMonitor{config: cfg, cancel: cancel}
+	t.monitors[cfg.ID] = monitor
+	t.mu.Unlock()
+
+	go t.run(ctx, monitor)
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Monitoring %s every %ds as %s", cfg.URL, cfg.IntervalSeconds, cfg.ID),
+			Data: map[string]interface{}{
+				"id":           cfg.ID,
+				"url":          cfg.URL,
+				"mode":         cfg.Mode,
+				"directory":    filepath.Join(t.dir, cfg.ID),
+				"notification": types.MonitorChangeNotificationMethod,
+			},
+		}},
+	}
+}
+
+func (t *MonitorPageTool) stop(args map[string]interface{}) *types.CallToolResponse {
+	id, _ := args["id"].(string)
+	t.mu.Lock()
+	monitor, ok := t.monitors[id]
+	if ok {
+		monitor.cancel()
+		delete(t.monitors, id)
+	}
+	t.mu.Unlock()
+	if !ok {
+		return recipeErrorResponse(fmt.Sprintf("Error: no running monitor with id %q", id))
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Stopped monitor %s", id),
+			Data: monitor.status(),
+		}},
+	}
+}
+
+func (t *MonitorPageTool) list() *types.CallToolResponse {
+	t.mu.Lock()
+	statuses := make([]map[string]interface{}, 0, len(t.monitors))
+	for _, m := range t.monitors {
+		statuses = append(statuses, m.status())
+	}
+	t.mu.Unlock()
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i]["id"].(string) < statuses[j]["id"].(string)
+	})
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%d monitors running", len(statuses)),
+			Data: map[string]interface{}{"monitors": statuses},
+		}},
+	}
+}
+
+func (t *MonitorPageTool) checkOnce(args map[string]interface{}) *types.CallToolResponse {
+	// Without an ID there is no baseline to compare against
+	if id, _ := args["id"].(string); id == "" {
+		return recipeErrorResponse("Error: id is required for check")
+	}
+	cfg, err := t.parseMonitorConfig(args)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error: %v", err))
+	}
+	result, err := t.check(cfg)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Check failed: %v", err))
+	}
+
+	text := fmt.Sprintf("No change on %s (%.1f%% differs)", cfg.URL, result.ChangeRatio*100)
+	switch {
+	case result.Baseline:
+		text = fmt.Sprintf("Recorded baseline for %s as %s", cfg.URL, cfg.ID)
+	case result.Changed:
+		text = fmt.Sprintf("Change detected on %s (%.1f%% differs)", cfg.URL, result.ChangeRatio*100)
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text,
+			Data: map[string]interface{}{
+				"id":     cfg.ID,
+				"result": result,
+			},
+		}},
+	}
+}
+
+func (t *MonitorPageTool) history(args map[string]interface{}) *types.CallToolResponse {
+	id, _ := args["id"].(string)
+	if !recipeNamePattern.MatchString(id) {
+		return recipeErrorResponse("Error: id is required")
+	}
+	limit := defaultMonitorHistory
+	if val, ok := args["limit"].(float64); ok && val > 0 {
+		limit = int(val)
+	}
+	changes, err := t.readChanges(id, limit)
+	if err != nil {
+		return recipeErrorResponse(fmt.Sprintf("Error reading history: %v", err))
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%d recorded changes for %s", len(changes), id),
+			Data: map[string]interface{}{
+				"id":      id,
+				"changes": changes,
+			},
+		}},
+	}
+}
+
+// run checks the page immediately and then on every interval until stopped
+func (t *MonitorPageTool) run(ctx context.Context, monitor *pageMonitor) {
+	ticker := time.NewTicker(time.Duration(monitor.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+	for {
+		t.runQueued(ctx, monitor)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runQueued performs one background check once the job gate lets it,
+// skipping the check when the queue is full
+func (t *MonitorPageTool) runQueued(ctx context.Context, monitor *pageMonitor) {
+	t.mu.Lock()
+	gate := t.gate
+	t.mu.Unlock()
+	if gate != nil {
+		release, err := gate(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				monitor.mu.Lock()
+				monitor.lastError = fmt.Sprintf("check skipped: %v", err)
+				monitor.mu.Unlock()
+				t.logger.WithComponent("monitor").Warn("Monitor check skipped",
+					zap.String("id", monitor.config.ID),
+					zap.Error(err))
+			}
+			return
+		}
+		defer release()
+	}
+	t.runGuarded(monitor)
+}
+
+// runGuarded performs one background check, keeping a panic from taking
+// down the server
+func (t *MonitorPageTool) runGuarded(monitor *pageMonitor) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.logger.WithComponent("monitor").Error("Monitor check panicked",
+				zap.String("id", monitor.config.ID),
+				zap.Any("panic", r))
+		}
+	}()
+
+	result, err := t.check(monitor.config)
+
+	monitor.mu.Lock()
+	monitor.checks++
+	monitor.lastChecked = time.Now()
+	monitor.lastError = ""
+	if err != nil {
+		monitor.lastError = err.Error()
+	} else if result.Changed {
+		monitor.changes++
+	}
+	monitor.mu.Unlock()
+
+	if err != nil {
+		t.logger.WithComponent("monitor").Warn("Monitor check failed",
+			zap.String("id", monitor.config.ID),
+			zap.Error(err))
+	}
+}
+
+// check loads the page once, compares it with the stored baseline and
+// records a change when the difference reaches the threshold. The baseline
+// only moves when a change is recorded, so slow drift below the threshold
+// still adds up to a reported change eventually.
+func (t *MonitorPageTool) check(cfg monitorConfig) (*monitorCheck, error) {
+	dir := filepath.Join(t.dir, cfg.ID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create monitor directory: %w", err)
+	}
+
+	if cfg.Mode == "screenshot" {
+		return t.checkScreenshot(cfg, dir)
+	}
+	return t.checkContent(cfg, dir)
+}
+
+func (t *MonitorPageTool) checkContent(cfg monitorConfig, dir string) (*monitorCheck, error) {
+	current, err := t.extract(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	baselinePath := filepath.Join(dir, "baseline.json")
+	var baseline map[string]string
+	if data, err := os.ReadFile(baselinePath); err == nil {
+		if err := json.Unmarshal(data, &baseline); err != nil {
+			baseline = nil
+		}
+	}
+	if baseline == nil {
+		return &monitorCheck{Baseline: true}, writeJSONFile(baselinePath, current)
+	}
+
+	ratio, changes := contentChangeRatio(baseline, current)
+	result := &monitorCheck{ChangeRatio: ratio}
+	if ratio == 0 || ratio < cfg.Threshold {
+		return result, nil
+	}
+
+	change := MonitorChange{
+		MonitorID:   cfg.ID,
+		URL:         cfg.URL,
+		Mode:        cfg.Mode,
+		DetectedAt:  time.Now().UTC(),
+		ChangeRatio: ratio,
+		Changes:     changes,
+	}
+	if err := t.recordChange(dir, change); err != nil {
+		return nil, err
+	}
+	result.Changed = true
+	result.Change = &change
+	return result, writeJSONFile(baselinePath, current)
+}
+
+// extract scrapes the page with the monitor's recipe or selectors and
+// flattens the result into field paths
+func (t *MonitorPageTool) extract(cfg monitorConfig) (map[string]string, error) {
+	scrapeArgs := map[string]interface{}{
+		"url":              cfg.URL,
+		"selectors":        cfg.Selectors,
+		"include_metadata": false,
+	}
+	extractType, container, waitFor := cfg.ExtractType, cfg.ContainerSelector, cfg.WaitFor
+	if cfg.Recipe != "" {
+		recipe, err := t.recipes.load(cfg.Recipe)
+		if err != nil {
+			return nil, err
+		}
+		scrapeArgs["selectors"] = recipe.Selectors
+		if extractType == "" {
+			extractType = recipe.ExtractType
+		}
+		if container == "" {
+			container = recipe.ContainerSelector
+		}
+		if waitFor == "" {
+			waitFor = recipe.WaitFor
+		}
+	}
+	if extractType != "" {
+		scrapeArgs["extract_type"] = extractType
+	}
+	if container != "" {
+		scrapeArgs["container_selector"] = container
+	}
+	if waitFor != "" {
+		scrapeArgs["wait_for"] = waitFor
+	}
+
+	resp, err := t.scraper.Execute(scrapeArgs)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError || len(resp.Content) == 0 {
+		if len(resp.Content) > 0 {
+			return nil, fmt.Errorf("%s", resp.Content[0].Text)
+		}
+		return nil, fmt.Errorf("scrape returned no content")
+	}
+	data, _ := resp.Content[0].Data.(map[string]interface{})
+
+	flat := make(map[string]string)
+	flattenScrapeData("", data["data"], flat)
+	return flat, nil
+}
+
+func (t *MonitorPageTool) checkScreenshot(cfg monitorConfig, dir string) (*monitorCheck, error) {
+	current, err := t.capture(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	baselinePath := filepath.Join(dir, "baseline.png")
+	baseline, err := os.ReadFile(baselinePath)
+	if err != nil {
+		return &monitorCheck{Baseline: true}, writeMonitorFile(baselinePath, current)
+	}
+
+	ratio, err := imageChangeRatio(baseline, current)
+	if err != nil {
+		return nil, err
+	}
+	result := &monitorCheck{ChangeRatio: ratio}
+	if ratio == 0 || ratio < cfg.Threshold {
+		return result, nil
+	}
+
+	now := time.Now().UTC()
+	shot := filepath.Join(dir, now.Format("20060102-150405")+".png")
+	if err := writeMonitorFile(shot, current); err != nil {
+		return nil, err
+	}
+	change := MonitorChange{
+		MonitorID:   cfg.ID,
+		URL:         cfg.URL,
+		Mode:        cfg.Mode,
+		DetectedAt:  now,
+		ChangeRatio: ratio,
+		Screenshot:  shot,
+	}
+	if err := t.recordChange(dir, change); err != nil {
+		return nil, err
+	}
+	result.Changed = true
+	result.Change = &change
+	return result, writeMonitorFile(baselinePath, current)
+}
+
+// capture opens the URL in a fresh page and screenshots the region
+func (t *MonitorPageTool) capture(cfg monitorConfig) ([]byte, error) {
+	_, pageID, err := t.browserMgr.NewPage(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", cfg.URL, err)
+	}
+	defer t.browserMgr.ClosePage(pageID)
+
+	if cfg.WaitFor != "" {
+		if _, err := t.browserMgr.ExecuteScript(pageID, waitForSelectorScript(cfg.WaitFor)); err != nil {
+			return nil, fmt.Errorf("wait_for %s: %w", cfg.WaitFor, err)
+		}
+	}
+	if cfg.RegionSelector != "" {
+		return t.browserMgr.ElementScreenshot(pageID, cfg.RegionSelector)
+	}
+	return t.browserMgr.Screenshot(pageID)
+}
+
+// waitForSelectorScript polls for a selector for up to 8 seconds, inside the
+// script timeout
+func waitForSelectorScript(selector string) string {
+	quoted, _ := json.Marshal(selector)
+	return fmt.Sprintf(`return new Promise((resolve, reject) => {
+		const deadline = Date.now() + 8000;
+		const poll = () => {
+			if (document.querySelector(%s)) return resolve(true);
+			if (Date.now() > deadline) return reject(new Error('element did not appear'));
+			setTimeout(poll, 200);
+		};
+		poll();
+	});`, quoted)
+}
+
+// recordChange appends a change to the monitor's change log and passes it
+// to the change handler
+func (t *MonitorPageTool) recordChange(dir string, change MonitorChange) error {
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(filepath.Join(dir, "changes.jsonl"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to record change: %w", err)
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record change: %w", err)
+	}
+
+	t.logger.WithComponent("monitor").Info("Page change detected",
+		zap.String("id", change.MonitorID),
+		zap.String("url", change.URL),
+		zap.Float64("change_ratio", change.ChangeRatio))
+
+	t.mu.Lock()
+	handler := t.onChange
+	t.mu.Unlock()
+	if handler != nil {
+		handler(change)
+	}
+	return nil
+}
+
+// readChanges returns the most recent limit changes, newest first
+func (t *MonitorPageTool) readChanges(id string, limit int) ([]MonitorChange, error) {
+	file, err := os.Open(filepath.Join(t.dir, id, "changes.jsonl"))
+	if os.IsNotExist(err) {
+		return []MonitorChange{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var changes []MonitorChange
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var change MonitorChange
+		if json.Unmarshal(scanner.Bytes(), &change) == nil {
+			changes = append(changes, change)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(changes) > limit {
+		changes = changes[len(changes)-limit:]
+	}
+	for i, j := 0, len(changes)-1; i < j; i, j = i+1, j-1 {
+		changes[i], changes[j] = changes[j], changes[i]
+	}
+	return changes, nil
+}
+
+// flattenScrapeData turns scraped data into path -> JSON value pairs, e.g.
+// "[2].price", so runs can be compared field by field
+func flattenScrapeData(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			flattenScrapeData(path, item, out)
+		}
+	case []interface{}:
+		for i, item := range v {
+			flattenScrapeData(fmt.Sprintf("%s[%d]", prefix, i), item, out)
+		}
+	case []map[string]interface{}:
+		for i, item := range v {
+			flattenScrapeData(fmt.Sprintf("%s[%d]", prefix, i), item, out)
+		}
+	default:
+		encoded, _ := json.Marshal(v)
+		out[prefix] = string(encoded)
+	}
+}
+
+// contentChangeRatio compares two flattened extractions and returns the
+// fraction of fields that were added, removed or changed
+func contentChangeRatio(before, after map[string]string) (float64, []fieldChange) {
+	keys := make(map[string]bool, len(before)+len(after))
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	var changes []fieldChange
+	for k := range keys {
+		if before[k] != after[k] {
+			changes = append(changes, fieldChange{Field: k, Before: before[k], After: after[k]})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return float64(len(changes)) / float64(len(keys)), changes
+}
+
+// imageChangeRatio returns the fraction of pixels that differ between two
+// images. Images of different sizes count as entirely changed.
+func imageChangeRatio(before, after []byte) (float64, error) {
+	a, _, err := image.Decode(bytes.NewReader(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode baseline image: %w", err)
+	}
+	b, _, err := image.Decode(bytes.NewReader(after))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode screenshot: %w", err)
+	}
+	ab, bb := a.Bounds(), b.Bounds()
+	if ab.Dx() != bb.Dx() || ab.Dy() != bb.Dy() {
+		return 1, nil
+	}
+	total := ab.Dx() * ab.Dy()
+	if total == 0 {
+		return 0, nil
+	}
+
+	differ := func(x, y uint32) bool {
+		d := int(x>>8) - int(y>>8)
+		return d > pixelTolerance || d < -pixelTolerance
+	}
+	changed := 0
+	for y := 0; y < ab.Dy(); y++ {
+		for x := 0; x < ab.Dx(); x++ {
+			r1, g1, b1, a1 := a.At(ab.Min.X+x, ab.Min.Y+y).RGBA()
+			r2, g2, b2, a2 := b.At(bb.Min.X+x, bb.Min.Y+y).RGBA()
+			if differ(r1, r2) || differ(g1, g2) || differ(b1, b2) || differ(a1, a2) {
+				changed++
+			}
+		}
+	}
+	return float64(changed) / float64(total), nil
+}
+
+func writeJSONFile(path string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeMonitorFile(path, data)
+}
+
+// writeMonitorFile replaces path atomically
+func writeMonitorFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package webtools
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlattenScrapeData(t *testing.T) {
+	flat := make(map[string]string)
+	flattenScrapeData("", []interface{}{
+		map[string]interface{}{"title": "A", "price": "9.99"},
+		map[string]interface{}{"title": "B", "tags": []interface{}{"x", "y"}},
+	}, flat)
+
+	want := map[string]string{
+		"[0].title":   `"A"`,
+		"[0].price":   `"9.99"`,
+		"[1].title":   `"B"`,
+		"[1].tags[0]": `"x"`,
+		"[1].tags[1]": `"y"`,
+	}
+	if len(flat) != len(want) {
+		t.Fatalf("got %v", flat)
+	}
+	for k, v := range want {
+		if flat[k] != v {
+			t.Errorf("%s = %s, want %s", k, flat[k], v)
+		}
+	}
+}
+
+func TestContentChangeRatio(t *testing.T) {
+	before := map[string]string{"title": `"A"`, "price": `"9.99"`, "stock": `"yes"`, "sku": `"1"`}
+	after := map[string]string{"title": `"A"`, "price": `"8.99"`, "stock": `"yes"`, "sku": `"1"`}
+
+	ratio, changes := contentChangeRatio(before, after)
+	if ratio != 0.25 {
+		t.Errorf("ratio = %v, want 0.25", ratio)
+	}
+	if len(changes) != 1 || changes[0].Field != "price" || changes[0].After != `"8.99"` {
+		t.Errorf("changes = %+v", changes)
+	}
+
+	if ratio, _ := contentChangeRatio(before, before); ratio != 0 {
+		t.Errorf("identical content ratio = %v", ratio)
+	}
+
+	// Added and removed fields both count
+	ratio, changes = contentChangeRatio(map[string]string{"a": "1"}, map[string]string{"b": "1"})
+	if ratio != 1 || len(changes) != 2 {
+		t.Errorf("ratio = %v, changes = %+v", ratio, changes)
+	}
+}
+
+func paintTestPNG(t *testing.T, w, h int, paint func(x, y int) color.Color) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, paint(x, y))
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageChangeRatio(t *testing.T) {
+	white := func(x, y int) color.Color { return color.White }
+	base := paintTestPNG(t, 10, 10, white)
+
+	// Slight colour noise stays under the tolerance
+	noisy := paintTestPNG(t, 10, 10, func(x, y int) color.Color { return color.RGBA{250, 250, 250, 255} })
+	if ratio, err := imageChangeRatio(base, noisy); err != nil || ratio != 0 {
+		t.Errorf("noise ratio = %v, %v", ratio, err)
+	}
+
+	// A black top row is 10% of the pixels
+	row := paintTestPNG(t, 10, 10, func(x, y int) color.Color {
+		if y == 0 {
+			return color.Black
+		}
+		return color.White
+	})
+	if ratio, err := imageChangeRatio(base, row); err != nil || ratio != 0.1 {
+		t.Errorf("row ratio = %v, %v", ratio, err)
+	}
+
+	resized := paintTestPNG(t, 12, 10, white)
+	if ratio, _ := imageChangeRatio(base, resized); ratio != 1 {
+		t.Errorf("resized ratio = %v, want 1", ratio)
+	}
+
+	if _, err := imageChangeRatio(base, []byte("not an image")); err == nil {
+		t.Error("Expected decode error")
+	}
+}
+
+func TestMonitorPageToolValidation(t *testing.T) {
+	tool := NewMonitorPageTool(createTestLogger(t), nil, t.TempDir(), t.TempDir())
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"no url", map[string]interface{}{"action": "start"}, "url is required"},
+		{"no selectors", map[string]interface{}{"url": "https://example.com"}, "recipe or selectors"},
+		{"missing recipe", map[string]interface{}{"url": "https://example.com", "recipe": "nope"}, "nope"},
+		{"bad mode", map[string]interface{}{"url": "https://example.com", "mode": "audio"}, "mode"},
+		{"short interval", map[string]interface{}{"url": "https://example.com", "mode": "screenshot", "interval_seconds": float64(5)}, "at least 30"},
+		{"bad threshold", map[string]interface{}{"url": "https://example.com", "mode": "screenshot", "threshold": float64(2)}, "threshold"},
+		{"check without id", map[string]interface{}{"action": "check", "url": "https://example.com", "mode": "screenshot"}, "id is required"},
+		{"stop unknown", map[string]interface{}{"action": "stop", "id": "ghost"}, "ghost"},
+		{"bad action", map[string]interface{}{"action": "pause"}, "unknown action"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+
+	resp, _ := tool.Execute(map[string]interface{}{"action": "list"})
+	if resp.IsError || !strings.Contains(resp.Content[0].Text, "0 monitors") {
+		t.Errorf("list = %+v", resp)
+	}
+}
+
+func TestMonitorChangeHistory(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewMonitorPageTool(createTestLogger(t), nil, "", dir)
+
+	if err := os.MkdirAll(filepath.Join(dir, "prices"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var notified []MonitorChange
+	tool.SetChangeHandler(func(c MonitorChange) { notified = append(notified, c) })
+
+	for i, ratio := range []float64{0.1, 0.2, 0.3} {
+		change := MonitorChange{MonitorID: "prices", URL: "https://example.com", DetectedAt: time.Unix(int64(i), 0), ChangeRatio: ratio}
+		if err := tool.recordChange(filepath.Join(dir, "prices"), change); err != nil {
+			t.Fatalf("recordChange: %v", err)
+		}
+	}
+	if len(notified) != 3 {
+		t.Errorf("notified %d changes, want 3", len(notified))
+	}
+
+	resp, _ := tool.Execute(map[string]interface{}{"action": "history", "id": "prices", "limit": float64(2)})
+	if resp.IsError {
+		t.Fatalf("history: %+v", resp)
+	}
+	changes := resp.Content[0].Data.(map[string]interface{})["changes"].([]MonitorChange)
+	if len(changes) != 2 || changes[0].ChangeRatio != 0.3 || changes[1].ChangeRatio != 0.2 {
+		t.Errorf("history = %+v, want newest two first", changes)
+	}
+}
@@ -0,0 +1,110 @@
+package webtools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// NavigateHistoryTool moves a page through its history or reloads it
+type NavigateHistoryTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewNavigateHistoryTool(log *logger.Logger, mgr *browser.Manager) *NavigateHistoryTool {
+	return &NavigateHistoryTool{logger: log, browserMgr: mgr}
+}
+
+func (t *NavigateHistoryTool) Name() string {
+	return "navigate_history"
+}
+
+func (t *NavigateHistoryTool) Description() string {
+	return "Go back or forward in a page's history, or reload it (hard_reload bypasses the cache), and return the URL and title it lands on"
+}
+
+func (t *NavigateHistoryTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        browser.HistoryActions,
+				"description": "back and forward move one entry through the page's history, reload reloads it, hard_reload reloads it bypassing the cache",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to navigate (uses current page if not specified)",
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (t *NavigateHistoryTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the page when the call ends
+func (t *NavigateHistoryTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		action, _ := args["action"].(string)
+		if err := browser.ValidateHistoryAction(action); err != nil {
+			return fail(err.Error())
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		info, err := t.browserMgr.NavigateHistoryContext(ctx, pageID, action)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to %s: %v", action, err))
+		}
+
+		var text string
+		switch action {
+		case browser.HistoryBack:
+			text = "Went back to"
+		case browser.HistoryForward:
+			text = "Went forward to"
+		case browser.HistoryHardReload:
+			text = "Hard reloaded"
+		default:
+			text = "Reloaded"
+		}
+		text += " " + info.URL
+		if info.Title != "" {
+			text += fmt.Sprintf(" (%s)", info.Title)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"action":  action,
+					"url":     info.URL,
+					"title":   info.Title,
+				},
+			}},
+		}, nil
+	})
+}
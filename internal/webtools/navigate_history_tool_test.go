@@ -0,0 +1,35 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestNavigateHistoryTool(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewNavigateHistoryTool(log, browser.NewManager(log, browser.Config{}))
+
+	if tool.Name() != "navigate_history" {
+		t.Errorf("Name = %q", tool.Name())
+	}
+	if schema := tool.InputSchema(); len(schema.Required) != 1 || schema.Required[0] != "action" {
+		t.Errorf("Expected action to be required, got %v", schema.Required)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{},
+		{"action": "refresh"},
+	} {
+		resp, _ := tool.Execute(args)
+		if !resp.IsError || !strings.Contains(resp.Content[0].Text, "hard_reload") {
+			t.Errorf("Expected an action error for %v, got %+v", args, resp)
+		}
+	}
+
+	resp, _ := tool.Execute(map[string]interface{}{"action": "back", "page_id": "missing"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "page not found") {
+		t.Errorf("Expected unknown page error, got %+v", resp)
+	}
+}
@@ -0,0 +1,251 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// maxCaptureSummary is how many requests stop_network_capture lists in its
+// response; the HAR file has all of them
+const maxCaptureSummary = 200
+
+// StartNetworkCaptureTool starts recording a page's network traffic
+type StartNetworkCaptureTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewStartNetworkCaptureTool(log *logger.Logger, mgr *browser.Manager) *StartNetworkCaptureTool {
+	return &StartNetworkCaptureTool{logger: log, browserMgr: mgr}
+}
+
+func (t *StartNetworkCaptureTool) Name() string {
+	return "start_network_capture"
+}
+
+func (t *StartNetworkCaptureTool) Description() string {
+	return "Start recording every request a page makes, with headers, status, sizes and timings, across navigations. Call stop_network_capture to get the results or a HAR file"
+}
+
+func (t *StartNetworkCaptureTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *StartNetworkCaptureTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		if err := t.browserMgr.StartNetworkCapture(pageID); err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(err.Error()), nil
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Capturing network traffic on page %s; call stop_network_capture when done", pageID),
+				Data: map[string]interface{}{"page_id": pageID},
+			}},
+		}, nil
+	})
+}
+
+// StopNetworkCaptureTool stops a capture and reports or saves what it
+// recorded
+type StopNetworkCaptureTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
+}
+
+func NewStopNetworkCaptureTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator) *StopNetworkCaptureTool {
+	return &StopNetworkCaptureTool{logger: log, browserMgr: mgr, validator: validator}
+}
+
+func (t *StopNetworkCaptureTool) Name() string {
+	return "stop_network_capture"
+}
+
+func (t *StopNetworkCaptureTool) Description() string {
+	return "Stop a capture started with start_network_capture and list the requests it recorded. Pass path to also write them as a HAR 1.2 file for browser devtools or other HAR viewers, or as a WARC web archive with response bodies for replay tools like pywb"
+}
+
+func (t *StopNetworkCaptureTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "File to write, e.g. capture.har, or capture.warc.gz for a gzipped WARC; must be inside the allowed paths",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "har or warc (default: warc for .warc and .warc.gz paths, otherwise har)",
+				"enum":        []string{"har", "warc"},
+			},
+			"overwrite": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Replace an existing file at path",
+				"default":     false,
+			},
+		},
+	}
+}
+
+func (t *StopNetworkCaptureTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		// Check the output path before stopping, so a bad path does not
+		// throw the capture away
+		path := ""
+		warc, compress := false, false
+		if p, _ := args["path"].(string); p != "" {
+			var err error
+			if path, err = resolveFileArg(t.validator, args, "write"); err != nil {
+				return fail(err.Error())
+			}
+			warc, compress = isWARCPath(path)
+			switch format, _ := args["format"].(string); format {
+			case "":
+			case "har", "warc":
+				warc = format == "warc"
+				compress = compress && warc
+			default:
+				return fail("format must be har or warc")
+			}
+			if overwrite, _ := args["overwrite"].(bool); !overwrite {
+				if _, err := os.Stat(path); err == nil {
+					return fail(fmt.Sprintf("%s already exists; pass overwrite=true to replace it", path))
+				}
+			}
+		}
+
+		capture, err := t.browserMgr.StopNetworkCapture(pageID)
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		var failed int
+		var transferred float64
+		summary := make([]map[string]interface{}, 0, min(len(capture.Requests), maxCaptureSummary))
+		for _, r := range capture.Requests {
+			transferred += r.EncodedBytes
+			if r.Failed {
+				failed++
+			}
+			if len(summary) < maxCaptureSummary {
+				summary = append(summary, map[string]interface{}{
+					"method":        r.Method,
+					"url":           r.URL,
+					"status":        r.Status,
+					"resource_type": r.ResourceType,
+					"duration_ms":   r.DurationMs,
+					"bytes":         r.EncodedBytes,
+					"failed":        r.Failed,
+				})
+			}
+		}
+		duration := capture.StoppedAt.Sub(capture.StartedAt)
+		text := fmt.Sprintf("Captured %d requests over %s (%d failed, %.1f KB transferred)",
+			len(capture.Requests), duration.Round(time.Millisecond), failed, transferred/1024)
+		if capture.Dropped > 0 {
+			text += fmt.Sprintf("; %d more were dropped after the first %d", capture.Dropped, len(capture.Requests))
+		}
+		data := map[string]interface{}{
+			"page_id":     pageID,
+			"requests":    len(capture.Requests),
+			"failed":      failed,
+			"bytes":       transferred,
+			"dropped":     capture.Dropped,
+			"duration_ms": duration.Milliseconds(),
+			"entries":     summary,
+		}
+
+		if path != "" {
+			var encoded []byte
+			if warc {
+				// Bodies are read now, while the browser still buffers them
+				var buf bytes.Buffer
+				stats, err := writeWARC(&buf, capture, compress, filepath.Base(path), func(r *browser.CapturedRequest) ([]byte, bool) {
+					body, err := t.browserMgr.ResponseBody(pageID, r.RequestID)
+					return body, err == nil
+				})
+				if err != nil {
+					return fail(fmt.Sprintf("Failed to build WARC: %v", err))
+				}
+				encoded = buf.Bytes()
+				data["archived"] = stats.Responses
+				data["skipped"] = stats.Skipped
+				text += fmt.Sprintf("; %d responses archived", stats.Responses)
+				if len(stats.Skipped) > 0 {
+					text += fmt.Sprintf(", %d left out because the browser no longer had their body", len(stats.Skipped))
+				}
+			} else {
+				title := ""
+				if info, err := t.browserMgr.GetPageInfo(pageID); err == nil {
+					title, _ = info["url"].(string)
+				}
+				var err error
+				if encoded, err = json.MarshalIndent(buildHAR(capture, title), "", "  "); err != nil {
+					return fail(fmt.Sprintf("Failed to build HAR: %v", err))
+				}
+			}
+			if err := t.validator.ValidateFileSize(int64(len(encoded))); err != nil {
+				return fail(err.Error())
+			}
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return fail(fmt.Sprintf("Failed to create directory: %v", err))
+			}
+			// HAR and WARC files carry cookies and auth headers, so keep
+			// them private
+			if err := os.WriteFile(path, encoded, 0600); err != nil {
+				return fail(fmt.Sprintf("Failed to write %s: %v", path, err))
+			}
+			data["path"] = path
+			if warc {
+				text += "; WARC written to " + path
+			} else {
+				text += "; HAR written to " + path
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
@@ -0,0 +1,291 @@
+package webtools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Defaults for the network waits
+const (
+	defaultNetworkIdle        = 500 * time.Millisecond
+	defaultNetworkWaitTimeout = 10 * time.Second
+)
+
+// networkWaitTimeout reads timeout in seconds, falling back to the default
+func networkWaitTimeout(args map[string]interface{}) time.Duration {
+	if seconds, ok := args["timeout"].(float64); ok && seconds > 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return defaultNetworkWaitTimeout
+}
+
+// WaitForNetworkIdleTool waits for a page's network traffic to settle
+type WaitForNetworkIdleTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewWaitForNetworkIdleTool(log *logger.Logger, mgr *browser.Manager) *WaitForNetworkIdleTool {
+	return &WaitForNetworkIdleTool{logger: log, browserMgr: mgr}
+}
+
+func (t *WaitForNetworkIdleTool) Name() string {
+	return "wait_for_network_idle"
+}
+
+func (t *WaitForNetworkIdleTool) Description() string {
+	return "Wait until a page has made no new requests, and has at most max_inflight still loading, for idle_ms. Use after an action that loads data in the background, such as a click that fetches search results"
+}
+
+func (t *WaitForNetworkIdleTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"idle_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "How long the network must stay quiet, in milliseconds (default: 500)",
+				"minimum":     0,
+				"default":     500,
+			},
+			"max_inflight": map[string]interface{}{
+				"type":        "integer",
+				"description": "Requests allowed to stay open while idle, for pages that hold a long poll or event stream open (default: 0)",
+				"minimum":     0,
+				"default":     0,
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum time to wait in seconds (default: 10)",
+				"default":     10,
+			},
+		},
+	}
+}
+
+func (t *WaitForNetworkIdleTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting when the call ends
+func (t *WaitForNetworkIdleTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		idle := defaultNetworkIdle
+		if ms, ok := args["idle_ms"].(float64); ok && ms >= 0 {
+			idle = time.Duration(ms) * time.Millisecond
+		}
+		maxInFlight := 0
+		if n, ok := args["max_inflight"].(float64); ok && n > 0 {
+			maxInFlight = int(n)
+		}
+
+		waited, err := t.browserMgr.WaitForNetworkIdleContext(ctx, pageID, idle, maxInFlight, networkWaitTimeout(args))
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(err.Error()), nil
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Network idle on page %s after %s", pageID, waited.Round(time.Millisecond)),
+				Data: map[string]interface{}{
+					"page_id":   pageID,
+					"waited_ms": waited.Milliseconds(),
+				},
+			}},
+		}, nil
+	})
+}
+
+// WaitForResponseTool waits for a page to request, or get the response
+// to, a URL
+type WaitForResponseTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewWaitForResponseTool(log *logger.Logger, mgr *browser.Manager) *WaitForResponseTool {
+	return &WaitForResponseTool{logger: log, browserMgr: mgr}
+}
+
+func (t *WaitForResponseTool) Name() string {
+	return "wait_for_response"
+}
+
+func (t *WaitForResponseTool) Description() string {
+	return "Wait for a page to send a request matching url_pattern, or to finish loading its response, and optionally return the response body. Use it to know when an API call behind a click has come back and read what it returned"
+}
+
+func (t *WaitForResponseTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"url_pattern": map[string]interface{}{
+				"type":        "string",
+				"description": "URL to wait for, where * stands for any run of characters",
+				"examples":    []interface{}{"*/api/orders*", "https://example.com/search?q=*"},
+			},
+			"method": map[string]interface{}{
+				"type":        "string",
+				"description": "Only match requests with this HTTP method",
+			},
+			"status": map[string]interface{}{
+				"type":        "integer",
+				"description": "Only match responses with this status code",
+			},
+			"event": map[string]interface{}{
+				"type":        "string",
+				"enum":        browser.NetworkWaitEvents,
+				"description": "request returns as soon as the page sends the request; response waits for it to finish loading or fail (default: response)",
+				"default":     browser.NetworkWaitResponse,
+			},
+			"include_body": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Return the response body: text as body, JSON also parsed under json, binary as body_base64 (default: false)",
+				"default":     false,
+			},
+			"include_existing": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also accept matching requests the page made since its last navigation but before this call, in case the response already came back (default: false)",
+				"default":     false,
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum time to wait in seconds (default: 10)",
+				"default":     10,
+			},
+		},
+		Required: []string{"url_pattern"},
+	}
+}
+
+// requestMatcher picks the requests wait_for_response waits for
+func requestMatcher(pattern, method string, status int) func(browser.NetworkRequest) bool {
+	return func(r browser.NetworkRequest) bool {
+		if method != "" && !strings.EqualFold(r.Method, method) {
+			return false
+		}
+		if status != 0 && r.Finished && r.Status != status {
+			return false
+		}
+		return matchURLPattern(pattern, r.URL)
+	}
+}
+
+func (t *WaitForResponseTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting when the call ends
+func (t *WaitForResponseTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pattern, _ := args["url_pattern"].(string)
+		if strings.TrimSpace(pattern) == "" {
+			return fail("url_pattern is required, e.g. */api/orders*")
+		}
+		pageID := pageIDOrCurrent(t.browserMgr, args)
+		if pageID == "" {
+			return createNoPagesErrorResponse(t.Name()), nil
+		}
+		method, _ := args["method"].(string)
+		status := 0
+		if n, ok := args["status"].(float64); ok {
+			status = int(n)
+		}
+		event, _ := args["event"].(string)
+		includeExisting, _ := args["include_existing"].(bool)
+
+		r, err := t.browserMgr.WaitForRequestContext(ctx, pageID, browser.NetworkWait{
+			Event:           event,
+			Match:           requestMatcher(pattern, method, status),
+			IncludeExisting: includeExisting,
+			Timeout:         networkWaitTimeout(args),
+		})
+		if err != nil {
+			return fail(fmt.Sprintf("%s: %v", pattern, err))
+		}
+
+		text := fmt.Sprintf("%s %s", r.Method, r.URL)
+		switch {
+		case r.Failed:
+			text += " failed: " + r.ErrorText
+		case r.Finished:
+			text += fmt.Sprintf(" returned %d %s (%.0f ms)", r.Status, r.MimeType, r.DurationMs)
+		default:
+			text += " sent"
+		}
+		data := map[string]interface{}{
+			"page_id": pageID,
+			"request": r,
+		}
+
+		if include, _ := args["include_body"].(bool); include && r.Finished && !r.Failed {
+			body, err := t.browserMgr.ResponseBody(pageID, r.RequestID)
+			if err != nil {
+				text += fmt.Sprintf("\nBody unavailable: %v", err)
+			} else {
+				text += addResponseBody(data, body, r.MimeType)
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// addResponseBody puts a body the browser loaded into data and describes it
+func addResponseBody(data map[string]interface{}, body []byte, mimeType string) string {
+	mediaType := responseMediaType(mimeType, body)
+	if responseBodyKind(mediaType) == bodyKindBinary {
+		if len(body) > maxInlineBinaryBytes {
+			return fmt.Sprintf("\nBody: %d bytes of %s, too large to return inline", len(body), mediaType)
+		}
+		data["body_base64"] = base64.StdEncoding.EncodeToString(body)
+		return fmt.Sprintf("\nBody: %d bytes of %s, returned as body_base64", len(body), mediaType)
+	}
+
+	if len(body) > maxInlineBinaryBytes {
+		data["body"] = string(body[:maxInlineBinaryBytes])
+		data["body_truncated"] = true
+		return fmt.Sprintf("\nBody: first %d of %d bytes of %s, returned as body", maxInlineBinaryBytes, len(body), mediaType)
+	}
+	data["body"] = string(body)
+	if responseBodyKind(mediaType) == bodyKindJSON {
+		var parsed interface{}
+		if json.Unmarshal(body, &parsed) == nil {
+			data["json"] = parsed
+		}
+	}
+	return fmt.Sprintf("\nBody:\n%s", body)
+}
@@ -0,0 +1,79 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+	"rodmcp/pkg/types"
+)
+
+func TestRequestMatcher(t *testing.T) {
+	orders := browser.NetworkRequest{URL: "https://shop.example.com/api/orders?page=2", Method: "POST", Status: 201, Finished: true}
+	cases := []struct {
+		pattern string
+		method  string
+		status  int
+		request browser.NetworkRequest
+		want    bool
+	}{
+		{"*/api/orders*", "", 0, orders, true},
+		{"*/api/orders", "", 0, orders, false},
+		{"*/api/orders*", "post", 201, orders, true},
+		{"*/api/orders*", "GET", 0, orders, false},
+		{"*/api/orders*", "", 200, orders, false},
+		// Requests not yet answered have no status to compare
+		{"*/api/orders*", "", 200, browser.NetworkRequest{URL: orders.URL, Method: "POST"}, true},
+	}
+	for _, c := range cases {
+		if got := requestMatcher(c.pattern, c.method, c.status)(c.request); got != c.want {
+			t.Errorf("%s %s %d on %+v = %v, want %v", c.pattern, c.method, c.status, c.request, got, c.want)
+		}
+	}
+}
+
+func TestAddResponseBody(t *testing.T) {
+	data := map[string]interface{}{}
+	text := addResponseBody(data, []byte(`{"id": 7}`), "application/json")
+	if parsed, ok := data["json"].(map[string]interface{}); !ok || parsed["id"] != float64(7) || !strings.Contains(text, `{"id": 7}`) {
+		t.Errorf("JSON body: %q, %v", text, data)
+	}
+
+	data = map[string]interface{}{}
+	addResponseBody(data, []byte{0x89, 'P', 'N', 'G', 0, 1}, "image/png")
+	if data["body_base64"] != "iVBORwAB" || data["body"] != nil {
+		t.Errorf("binary body: %v", data)
+	}
+
+	data = map[string]interface{}{}
+	text = addResponseBody(data, []byte(strings.Repeat("a", maxInlineBinaryBytes+1)), "text/plain")
+	if data["body_truncated"] != true || len(data["body"].(string)) != maxInlineBinaryBytes || !strings.Contains(text, "first") {
+		t.Errorf("long body not truncated: %q", text)
+	}
+}
+
+func TestNetworkWaitTools(t *testing.T) {
+	log := createTestLogger(t)
+	mgr := browser.NewManager(log, browser.Config{})
+	idle := NewWaitForNetworkIdleTool(log, mgr)
+	response := NewWaitForResponseTool(log, mgr)
+
+	if schema := response.InputSchema(); len(schema.Required) != 1 || schema.Required[0] != "url_pattern" {
+		t.Errorf("Expected url_pattern to be required, got %v", schema.Required)
+	}
+	resp, _ := response.Execute(map[string]interface{}{"page_id": "missing"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "url_pattern is required") {
+		t.Errorf("Expected a url_pattern error, got %+v", resp)
+	}
+
+	type tool interface {
+		Name() string
+		Execute(map[string]interface{}) (*types.CallToolResponse, error)
+	}
+	for _, tool := range []tool{idle, response} {
+		got, _ := tool.Execute(map[string]interface{}{"page_id": "missing", "url_pattern": "*"})
+		if !got.IsError || !strings.Contains(got.Content[0].Text, "page not found") {
+			t.Errorf("%s: expected unknown page error, got %+v", tool.Name(), got)
+		}
+	}
+}
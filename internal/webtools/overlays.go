@@ -0,0 +1,445 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// autoDismissWait is how long the post-navigation pass watches for a
+	// banner; consent scripts usually inject theirs within a second of load
+	autoDismissWait = 1500 * time.Millisecond
+
+	defaultDismissWait = 2 * time.Second
+	// maxDismissWait stays inside ExecuteScript's 10 second limit
+	maxDismissWait = 8 * time.Second
+)
+
+// overlayRule describes how to get rid of one kind of overlay: click the
+// first visible match of a click selector, and/or remove every element
+// matching a remove selector
+type overlayRule struct {
+	Name        string   `json:"name"`
+	URLPatterns []string `json:"url_patterns,omitempty"`
+	Click       []string `json:"click,omitempty"`
+	Remove      []string `json:"remove,omitempty"`
+}
+
+func (r overlayRule) validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rule needs a name")
+	}
+	if len(r.Click) == 0 && len(r.Remove) == 0 {
+		return fmt.Errorf("rule %s needs click or remove selectors", r.Name)
+	}
+	return nil
+}
+
+// appliesTo reports whether the rule is meant for url. Rules without URL
+// patterns apply everywhere.
+func (r overlayRule) appliesTo(url string) bool {
+	if len(r.URLPatterns) == 0 {
+		return true
+	}
+	for _, pattern := range r.URLPatterns {
+		if matchURLPattern(pattern, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// builtinOverlayRules covers the consent management platforms seen on most
+// sites. Banners rendered in a cross-origin iframe or closed shadow root
+// cannot be clicked, so their containers are removed instead.
+var builtinOverlayRules = []overlayRule{
+	{Name: "onetrust", Click: []string{"#onetrust-accept-btn-handler"}},
+	{Name: "cookiebot", Click: []string{"#CybotCookiebotDialogBodyLevelButtonLevelOptinAllowAll", "#CybotCookiebotDialogBodyButtonAccept"}},
+	{Name: "quantcast", Click: []string{`.qc-cmp2-summary-buttons button[mode="primary"]`}},
+	{Name: "trustarc", Click: []string{"#truste-consent-button"}},
+	{Name: "didomi", Click: []string{"#didomi-notice-agree-button"}},
+	{Name: "osano", Click: []string{".osano-cm-accept-all"}},
+	{Name: "complianz", Click: []string{".cmplz-btn.cmplz-accept"}},
+	{Name: "cookieyes", Click: []string{".cky-btn-accept"}},
+	{Name: "iubenda", Click: []string{".iubenda-cs-accept-btn"}},
+	{Name: "klaro", Click: []string{".klaro .cm-btn-success"}},
+	{Name: "termly", Click: []string{`[data-tid="banner-accept"]`}},
+	{Name: "google-funding-choices", Click: []string{".fc-cta-consent"}},
+	{Name: "cookie-notice", Click: []string{"#cn-accept-cookie"}},
+	{Name: "cookieconsent", Click: []string{".cc-window .cc-allow", ".cc-window .cc-dismiss"}},
+	{Name: "sourcepoint", Remove: []string{`div[id^="sp_message_container_"]`}},
+	{Name: "usercentrics", Remove: []string{"#usercentrics-root"}},
+}
+
+// overlayAcceptLabels are button labels (lower case, whitespace collapsed)
+// that the generic pass clicks when they sit inside a consent banner
+var overlayAcceptLabels = []string{
+	"accept", "accept all", "accept all cookies", "accept cookies", "allow all",
+	"allow all cookies", "allow cookies", "i accept", "i agree", "agree",
+	"agree and continue", "got it", "ok", "okay",
+	"alle akzeptieren", "akzeptieren", "zustimmen", "alle zulassen",
+	"tout accepter", "accepter", "j'accepte",
+	"aceptar", "aceptar todo", "aceptar todas",
+	"accetta", "accetta tutto", "accetto",
+	"aceitar", "aceitar todos", "alles accepteren", "accepteren",
+}
+
+// loadOverlayRules reads custom rules from a JSON file holding an array of
+// rules
+func loadOverlayRules(path string) ([]overlayRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read overlay rules: %w", err)
+	}
+	var rules []overlayRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("failed to parse overlay rules %s: %w", path, err)
+	}
+	for _, rule := range rules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return rules, nil
+}
+
+// dismissedOverlay records one action taken against an overlay
+type dismissedOverlay struct {
+	Rule     string `json:"rule"`
+	Action   string `json:"action"`
+	Selector string `json:"selector,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Count    int    `json:"count,omitempty"`
+}
+
+type overlayResult struct {
+	Dismissed      []dismissedOverlay `json:"dismissed"`
+	ScrollUnlocked bool               `json:"scroll_unlocked"`
+}
+
+// OverlayDismisser clears cookie consent banners and similar overlays, both
+// on demand and, when installed as a navigation hook, after every page load
+type OverlayDismisser struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	custom     []overlayRule
+}
+
+// NewOverlayDismisser creates a dismisser using the built-in rules plus any
+// custom rules in rulesFile (optional)
+func NewOverlayDismisser(log *logger.Logger, mgr *browser.Manager, rulesFile string) (*OverlayDismisser, error) {
+	d := &OverlayDismisser{logger: log, browserMgr: mgr}
+	if rulesFile != "" {
+		rules, err := loadOverlayRules(rulesFile)
+		if err != nil {
+			return nil, err
+		}
+		d.custom = rules
+	}
+	return d, nil
+}
+
+// rulesFor lists the rules to try on url: ad-hoc rules first, then custom
+// rules for the site, then the built-in ones
+func (d *OverlayDismisser) rulesFor(url string, extra []overlayRule, builtin bool) []overlayRule {
+	var rules []overlayRule
+	for _, group := range [][]overlayRule{extra, d.custom} {
+		for _, rule := range group {
+			if rule.appliesTo(url) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	if builtin {
+		rules = append(rules, builtinOverlayRules...)
+	}
+	return rules
+}
+
+// Dismiss watches the page for up to wait, applying rules (and the generic
+// accept-button heuristic when builtin is set) to any overlay that appears
+func (d *OverlayDismisser) Dismiss(pageID string, extra []overlayRule, builtin bool, wait time.Duration) (*overlayResult, error) {
+	url := ""
+	if info, err := d.browserMgr.GetPageInfo(pageID); err == nil {
+		url, _ = info["url"].(string)
+	}
+
+	script, err := dismissOverlaysScript(d.rulesFor(url, extra, builtin), builtin, wait)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := d.browserMgr.ExecuteScript(pageID, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dismiss overlays: %w", err)
+	}
+
+	var result overlayResult
+	if err := decodeScriptValue(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to read overlay result: %w", err)
+	}
+	return &result, nil
+}
+
+// AutoDismiss is a browser.NavigationHook that clears overlays after load
+func (d *OverlayDismisser) AutoDismiss(pageID, url string) {
+	result, err := d.Dismiss(pageID, nil, true, autoDismissWait)
+	if err != nil {
+		d.logger.WithComponent("overlays").Debug("Auto-dismiss failed",
+			zap.String("page_id", pageID),
+			zap.Error(err))
+		return
+	}
+	if len(result.Dismissed) > 0 {
+		d.logger.WithComponent("overlays").Info("Dismissed overlays after navigation",
+			zap.String("page_id", pageID),
+			zap.String("url", url),
+			zap.Int("count", len(result.Dismissed)))
+	}
+}
+
+func dismissOverlaysScript(rules []overlayRule, heuristic bool, wait time.Duration) (string, error) {
+	rulesJSON, err := json.Marshal(rules)
+	if err != nil {
+		return "", err
+	}
+	labelsJSON, _ := json.Marshal(overlayAcceptLabels)
+
+	return fmt.Sprintf(`
+		const rules = %s;
+		const heuristic = %t;
+		const waitMs = %d;
+		const acceptLabels = %s;
+		const maxActions = 5;
+
+		const visible = (el) => {
+			const r = el.getBoundingClientRect();
+			const s = getComputedStyle(el);
+			return r.width > 0 && r.height > 0 && s.visibility !== 'hidden' && s.display !== 'none';
+		};
+		const query = (sel) => {
+			try { return Array.from(document.querySelectorAll(sel)); } catch (e) { return []; }
+		};
+
+		const dismissed = [];
+		const clicked = new Set();
+		const click = (el) => {
+			clicked.add(el);
+			el.click();
+		};
+
+		const applyRules = () => {
+			let acted = false;
+			for (const rule of rules) {
+				for (const sel of rule.click || []) {
+					const el = query(sel).find(e => visible(e) && !clicked.has(e));
+					if (el) {
+						click(el);
+						dismissed.push({ rule: rule.name, action: 'click', selector: sel });
+						acted = true;
+						break;
+					}
+				}
+				for (const sel of rule.remove || []) {
+					const els = query(sel);
+					if (els.length) {
+						els.forEach(e => e.remove());
+						dismissed.push({ rule: rule.name, action: 'remove', selector: sel, count: els.length });
+						acted = true;
+					}
+				}
+			}
+			return acted;
+		};
+
+		const consentMarker = /cookie|consent|gdpr|privacy|cmp/i;
+		const applyHeuristic = () => {
+			const buttons = query('button, [role="button"], input[type="button"], input[type="submit"], a');
+			for (const b of buttons) {
+				if (clicked.has(b) || !visible(b)) continue;
+				const label = (b.innerText || b.value || b.getAttribute('aria-label') || '')
+					.trim().toLowerCase().replace(/\s+/g, ' ');
+				if (!label || label.length > 40 || !acceptLabels.includes(label)) continue;
+				let inBanner = false;
+				for (let n = b.parentElement; n && n !== document.documentElement; n = n.parentElement) {
+					const marker = (n.id || '') + ' ' + (typeof n.className === 'string' ? n.className : '') +
+						' ' + (n.getAttribute('aria-label') || '');
+					if (consentMarker.test(marker)) { inBanner = true; break; }
+				}
+				if (!inBanner) continue;
+				click(b);
+				dismissed.push({ rule: 'generic', action: 'click', text: label });
+				return true;
+			}
+			return false;
+		};
+
+		return (async () => {
+			const deadline = Date.now() + waitMs;
+			while (dismissed.length < maxActions) {
+				const acted = applyRules() || (heuristic && applyHeuristic());
+				// Once something was dismissed, stop as soon as a pass finds
+				// nothing more (a second consent layer shows up immediately)
+				if (!acted && dismissed.length > 0) break;
+				if (Date.now() >= deadline) break;
+				await new Promise(r => setTimeout(r, 250));
+			}
+
+			// Banners often lock scrolling; undo that once they are gone
+			let unlocked = false;
+			if (dismissed.length > 0) {
+				for (const el of [document.documentElement, document.body]) {
+					if (el && getComputedStyle(el).overflow === 'hidden') {
+						el.style.setProperty('overflow', 'auto', 'important');
+						unlocked = true;
+					}
+				}
+			}
+			return { dismissed: dismissed, scroll_unlocked: unlocked };
+		})();
+	`, rulesJSON, heuristic, wait.Milliseconds(), labelsJSON), nil
+}
+
+// DismissOverlaysTool clears cookie banners and similar overlays on demand
+type DismissOverlaysTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	dismisser  *OverlayDismisser
+}
+
+// NewDismissOverlaysTool creates a dismiss_overlays tool. A nil dismisser
+// uses the built-in rules only.
+func NewDismissOverlaysTool(log *logger.Logger, mgr *browser.Manager, dismisser *OverlayDismisser) *DismissOverlaysTool {
+	if dismisser == nil {
+		dismisser, _ = NewOverlayDismisser(log, mgr, "")
+	}
+	return &DismissOverlaysTool{
+		logger:     log,
+		browserMgr: mgr,
+		dismisser:  dismisser,
+	}
+}
+
+func (t *DismissOverlaysTool) Name() string {
+	return "dismiss_overlays"
+}
+
+func (t *DismissOverlaysTool) Description() string {
+	return "Dismiss cookie consent banners and similar overlays on a page by clicking their accept buttons (common consent platforms, custom rules, or a generic accept-button match) and restoring scrolling"
+}
+
+func (t *DismissOverlaysTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"wait_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "How long to watch for a banner to appear (default: 2000, max: 8000)",
+				"default":     int(defaultDismissWait / time.Millisecond),
+			},
+			"rules": map[string]interface{}{
+				"type":        "array",
+				"description": "Extra rules tried before the built-in ones",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"name":   map[string]interface{}{"type": "string"},
+						"click":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Selectors; the first visible match is clicked"},
+						"remove": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": "Selectors whose matches are removed"},
+					},
+					"required": []string{"name"},
+				},
+			},
+			"builtin": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also apply the built-in consent platform rules and generic accept-button match (default: true)",
+				"default":     true,
+			},
+		},
+	}
+}
+
+func (t *DismissOverlaysTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		wait := defaultDismissWait
+		if val, ok := args["wait_ms"].(float64); ok {
+			wait = time.Duration(val) * time.Millisecond
+		}
+		if wait < 0 || wait > maxDismissWait {
+			return fail(fmt.Sprintf("wait_ms must be between 0 and %d", maxDismissWait.Milliseconds()))
+		}
+
+		builtin := true
+		if val, ok := args["builtin"].(bool); ok {
+			builtin = val
+		}
+
+		var extra []overlayRule
+		if raw, ok := args["rules"]; ok {
+			data, _ := json.Marshal(raw)
+			if err := json.Unmarshal(data, &extra); err != nil {
+				return fail(fmt.Sprintf("rules must be an array of {name, click, remove}: %v", err))
+			}
+			for _, rule := range extra {
+				if err := rule.validate(); err != nil {
+					return fail(err.Error())
+				}
+			}
+		}
+		if !builtin && len(extra) == 0 && len(t.dismisser.custom) == 0 {
+			return fail("no rules to apply: pass rules or leave builtin enabled")
+		}
+
+		result, err := t.dismisser.Dismiss(pageID, extra, builtin, wait)
+		if err != nil {
+			return fail(err.Error())
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		text := "No overlays found"
+		if n := len(result.Dismissed); n > 0 {
+			var names []string
+			for _, d := range result.Dismissed {
+				names = append(names, d.Rule)
+			}
+			text = fmt.Sprintf("Dismissed %d overlays (%s)", n, strings.Join(names, ", "))
+		}
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"page_id":         pageID,
+					"dismissed":       result.Dismissed,
+					"scroll_unlocked": result.ScrollUnlocked,
+				},
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,94 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadOverlayRules(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "rules.json")
+	os.WriteFile(good, []byte(`[
+		{"name": "shop", "url_patterns": ["https://shop.example.com/*"], "click": ["#agree"]},
+		{"name": "newsletter", "remove": [".newsletter-modal"]}
+	]`), 0644)
+
+	rules, err := loadOverlayRules(good)
+	if err != nil {
+		t.Fatalf("loadOverlayRules: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Click[0] != "#agree" || rules[1].Remove[0] != ".newsletter-modal" {
+		t.Errorf("rules = %+v", rules)
+	}
+
+	bad := map[string]string{
+		"not json":   `{`,
+		"no name":    `[{"click": ["#a"]}]`,
+		"no actions": `[{"name": "empty"}]`,
+	}
+	for name, content := range bad {
+		path := filepath.Join(dir, strings.ReplaceAll(name, " ", "_")+".json")
+		os.WriteFile(path, []byte(content), 0644)
+		if _, err := loadOverlayRules(path); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	if _, err := loadOverlayRules(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestOverlayRulesFor(t *testing.T) {
+	d := &OverlayDismisser{custom: []overlayRule{
+		{Name: "shop", URLPatterns: []string{"https://shop.example.com/*"}, Click: []string{"#agree"}},
+		{Name: "everywhere", Remove: []string{".modal"}},
+	}}
+	extra := []overlayRule{{Name: "adhoc", Click: []string{"#ok"}}}
+
+	rules := d.rulesFor("https://shop.example.com/cart", extra, false)
+	var names []string
+	for _, r := range rules {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "adhoc,shop,everywhere" {
+		t.Errorf("rules = %v", names)
+	}
+
+	rules = d.rulesFor("https://other.example.com/", nil, true)
+	if len(rules) != 1+len(builtinOverlayRules) || rules[0].Name != "everywhere" {
+		t.Errorf("got %d rules starting with %s", len(rules), rules[0].Name)
+	}
+}
+
+func TestBuiltinOverlayRulesValid(t *testing.T) {
+	for _, rule := range builtinOverlayRules {
+		if err := rule.validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestDismissOverlaysToolValidation(t *testing.T) {
+	tool := NewDismissOverlaysTool(createTestLogger(t), nil, nil)
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"wait too long", map[string]interface{}{"page_id": "p", "wait_ms": float64(60000)}, "wait_ms"},
+		{"bad rules", map[string]interface{}{"page_id": "p", "rules": "nope"}, "rules must be"},
+		{"rule without actions", map[string]interface{}{"page_id": "p", "rules": []interface{}{map[string]interface{}{"name": "x"}}}, "click or remove"},
+		{"nothing to apply", map[string]interface{}{"page_id": "p", "builtin": false}, "no rules"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
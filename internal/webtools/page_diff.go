@@ -0,0 +1,601 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+const (
+	defaultSnapshotName = "default"
+	// maxSnapshots bounds the snapshots kept in memory; the oldest goes first
+	maxSnapshots = 20
+	// maxSnapshotNodes keeps a snapshot of a huge page within the script
+	// timeout and the diff within a few megabytes
+	maxSnapshotNodes = 6000
+	// maxAlignedNodes caps the changed region aligned node by node; larger
+	// regions are compared by position instead
+	maxAlignedNodes   = 2500
+	defaultMaxChanges = 100
+)
+
+// domNode is one element of a DOM snapshot, in document order
+type domNode struct {
+	Path  string            `json:"path"`
+	Depth int               `json:"depth"`
+	Tag   string            `json:"tag"`
+	ID    string            `json:"id,omitempty"`
+	Class string            `json:"class,omitempty"`
+	Attrs map[string]string `json:"attrs,omitempty"`
+	Text  string            `json:"text,omitempty"`
+}
+
+// identity is what must stay the same for two nodes to count as the same
+// element in two snapshots
+func (n domNode) identity() string {
+	return fmt.Sprintf("%d|%s|%s|%s", n.Depth, n.Tag, n.ID, n.Class)
+}
+
+// label names a node the way a person scanning a diff would recognise it
+func (n domNode) label() string {
+	label := n.Tag
+	if n.ID != "" {
+		label += "#" + n.ID
+	}
+	if n.Class != "" {
+		classes := strings.Fields(n.Class)
+		if len(classes) > 2 {
+			classes = classes[:2]
+		}
+		label += "." + strings.Join(classes, ".")
+	}
+	return label
+}
+
+type domSnapshot struct {
+	Name      string    `json:"name"`
+	PageID    string    `json:"page_id"`
+	URL       string    `json:"url"`
+	Selector  string    `json:"selector,omitempty"`
+	Nodes     []domNode `json:"-"`
+	Truncated bool      `json:"truncated,omitempty"`
+	TakenAt   time.Time `json:"taken_at"`
+}
+
+// attrChange is one attribute that differs between two states
+type attrChange struct {
+	Name   string  `json:"name"`
+	Before *string `json:"before"`
+	After  *string `json:"after"`
+}
+
+// domChange is one entry of a page state diff
+type domChange struct {
+	Kind       string       `json:"kind"` // added, removed, changed
+	Element    string       `json:"element"`
+	Selector   string       `json:"selector"`
+	Text       string       `json:"text,omitempty"`
+	TextBefore *string      `json:"text_before,omitempty"`
+	TextAfter  *string      `json:"text_after,omitempty"`
+	Attributes []attrChange `json:"attributes,omitempty"`
+	// Descendants counts the nested elements folded into an added or
+	// removed entry
+	Descendants int `json:"descendants,omitempty"`
+}
+
+func snapshotScript(selector string) string {
+	selectorJSON, _ := json.Marshal(selector)
+	return fmt.Sprintf(`
+		const rootSelector = %s;
+		const maxNodes = %d;
+		const root = rootSelector ? document.querySelector(rootSelector) : document.body;
+		if (!root) return { error: 'no element matches ' + rootSelector };
+
+		const skip = new Set(['SCRIPT', 'STYLE', 'NOSCRIPT', 'TEMPLATE', 'LINK', 'META']);
+		const clean = s => s.replace(/\s+/g, ' ').trim();
+		const clip = (s, n) => s.length > n ? s.slice(0, n - 1) + '…' : s;
+
+		const nodes = [];
+		let truncated = false;
+		const walk = (el, path, depth) => {
+			if (nodes.length >= maxNodes) { truncated = true; return; }
+			const node = { path: path, depth: depth, tag: el.tagName.toLowerCase() };
+			if (el.id) node.id = el.id;
+			const cls = typeof el.className === 'string' ? clean(el.className) : '';
+			if (cls) node.class = cls;
+
+			const attrs = {};
+			for (const a of el.attributes) {
+				if (a.name === 'id' || a.name === 'class' || a.name === 'style' || a.name.startsWith('data-rodmcp')) continue;
+				attrs[a.name] = clip(a.value, 200);
+			}
+			// Live form state lives in properties, not attributes
+			if ('value' in el && ['INPUT', 'TEXTAREA', 'SELECT'].includes(el.tagName) && el.type !== 'password') {
+				attrs[':value'] = clip(String(el.value), 200);
+			}
+			if (el.tagName === 'INPUT' && (el.type === 'checkbox' || el.type === 'radio')) {
+				attrs[':checked'] = String(el.checked);
+			}
+			if (el.tagName === 'DETAILS' || el.tagName === 'DIALOG') {
+				attrs[':open'] = String(el.open);
+			}
+			if (Object.keys(attrs).length) node.attrs = attrs;
+
+			let text = '';
+			for (const child of el.childNodes) {
+				if (child.nodeType === 3) text += child.textContent;
+			}
+			text = clean(text);
+			if (text) node.text = clip(text, 300);
+			nodes.push(node);
+
+			const counts = {};
+			for (const child of el.children) {
+				if (skip.has(child.tagName)) continue;
+				const tag = child.tagName.toLowerCase();
+				counts[tag] = (counts[tag] || 0) + 1;
+				walk(child, path + ' > ' + tag + ':nth-of-type(' + counts[tag] + ')', depth + 1);
+			}
+		};
+
+		let rootPath = root === document.body ? 'body' : rootSelector;
+		if (root.id) rootPath = '#' + CSS.escape(root.id);
+		walk(root, rootPath, 0);
+		return { url: location.href, nodes: nodes, truncated: truncated };
+	`, selectorJSON, maxSnapshotNodes)
+}
+
+// alignNodes pairs up the nodes of two snapshots that are the same element.
+// Unchanged leading and trailing runs are matched directly and the rest by
+// longest common subsequence of node identities, so an inserted element
+// does not make every later sibling look changed. It returns index pairs in
+// document order; unpaired nodes were removed (before) or added (after).
+func alignNodes(before, after []domNode) [][2]int {
+	var pairs [][2]int
+	start := 0
+	for start < len(before) && start < len(after) && before[start].identity() == after[start].identity() {
+		pairs = append(pairs, [2]int{start, start})
+		start++
+	}
+	endB, endA := len(before), len(after)
+	var tail [][2]int
+	for endB > start && endA > start && before[endB-1].identity() == after[endA-1].identity() {
+		endB--
+		endA--
+		tail = append(tail, [2]int{endB, endA})
+	}
+
+	midB, midA := before[start:endB], after[start:endA]
+	if len(midB) > 0 && len(midA) > 0 {
+		if len(midB) > maxAlignedNodes || len(midA) > maxAlignedNodes {
+			// Too large to align exactly: pair by position within the region
+			for i := 0; i < len(midB) && i < len(midA); i++ {
+				if midB[i].identity() == midA[i].identity() {
+					pairs = append(pairs, [2]int{start + i, start + i})
+				}
+			}
+		} else {
+			pairs = append(pairs, lcsPairs(midB, midA, start)...)
+		}
+	}
+
+	for i := len(tail) - 1; i >= 0; i-- {
+		pairs = append(pairs, tail[i])
+	}
+	return pairs
+}
+
+func lcsPairs(before, after []domNode, offset int) [][2]int {
+	n, m := len(before), len(after)
+	// lengths[i][j] is the LCS length of before[i:] and after[j:]
+	lengths := make([][]uint16, n+1)
+	for i := range lengths {
+		lengths[i] = make([]uint16, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if before[i].identity() == after[j].identity() {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < n && j < m; {
+		switch {
+		case before[i].identity() == after[j].identity():
+			pairs = append(pairs, [2]int{offset + i, offset + j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
+
+// diffSnapshots lists what changed from before to after. Added and removed
+// subtrees are reported once, at their top element.
+func diffSnapshots(before, after []domNode) []domChange {
+	pairs := alignNodes(before, after)
+	var changes []domChange
+
+	// fold collapses the contiguous unpaired nodes nested under the first
+	// one into a single entry
+	fold := func(kind string, nodes []domNode, from, to int) {
+		for i := from; i < to; {
+			top := nodes[i]
+			change := domChange{Kind: kind, Element: top.label(), Selector: top.Path}
+			var texts []string
+			if top.Text != "" {
+				texts = append(texts, top.Text)
+			}
+			j := i + 1
+			for j < to && nodes[j].Depth > top.Depth {
+				if nodes[j].Text != "" {
+					texts = append(texts, nodes[j].Text)
+				}
+				change.Descendants++
+				j++
+			}
+			change.Text = clipText(strings.Join(texts, " "), 200)
+			changes = append(changes, change)
+			i = j
+		}
+	}
+
+	prevB, prevA := 0, 0
+	for _, pair := range append(pairs, [2]int{len(before), len(after)}) {
+		fold("removed", before, prevB, pair[0])
+		fold("added", after, prevA, pair[1])
+		if pair[0] < len(before) && pair[1] < len(after) {
+			if change, ok := compareNodes(before[pair[0]], after[pair[1]]); ok {
+				changes = append(changes, change)
+			}
+		}
+		prevB, prevA = pair[0]+1, pair[1]+1
+	}
+	return changes
+}
+
+func compareNodes(before, after domNode) (domChange, bool) {
+	change := domChange{Kind: "changed", Element: after.label(), Selector: after.Path}
+	if before.Text != after.Text {
+		b, a := before.Text, after.Text
+		change.TextBefore, change.TextAfter = &b, &a
+	}
+
+	names := make(map[string]bool)
+	for name := range before.Attrs {
+		names[name] = true
+	}
+	for name := range after.Attrs {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		b, hadB := before.Attrs[name]
+		a, hasA := after.Attrs[name]
+		if hadB == hasA && a == b {
+			continue
+		}
+		attr := attrChange{Name: name}
+		if hadB {
+			attr.Before = &b
+		}
+		if hasA {
+			attr.After = &a
+		}
+		change.Attributes = append(change.Attributes, attr)
+	}
+	return change, change.TextBefore != nil || len(change.Attributes) > 0
+}
+
+func clipText(s string, max int) string {
+	if len([]rune(s)) <= max {
+		return s
+	}
+	return string([]rune(s)[:max-1]) + "…"
+}
+
+func quoteOrNone(s *string) string {
+	if s == nil {
+		return "(none)"
+	}
+	return fmt.Sprintf("%q", *s)
+}
+
+// formatDiff renders changes as one line per change
+func formatDiff(changes []domChange) string {
+	var b strings.Builder
+	for _, c := range changes {
+		switch c.Kind {
+		case "added", "removed":
+			sign := "+"
+			if c.Kind == "removed" {
+				sign = "-"
+			}
+			fmt.Fprintf(&b, "%s %s %s", sign, c.Kind, c.Element)
+			if c.Text != "" {
+				fmt.Fprintf(&b, " %q", c.Text)
+			}
+			if c.Descendants > 0 {
+				fmt.Fprintf(&b, " (+%d nested)", c.Descendants)
+			}
+			fmt.Fprintf(&b, "\n    %s\n", c.Selector)
+		default:
+			fmt.Fprintf(&b, "~ changed %s\n    %s\n", c.Element, c.Selector)
+			if c.TextBefore != nil {
+				fmt.Fprintf(&b, "    text: %s -> %s\n", quoteOrNone(c.TextBefore), quoteOrNone(c.TextAfter))
+			}
+			for _, attr := range c.Attributes {
+				fmt.Fprintf(&b, "    [%s]: %s -> %s\n", attr.Name, quoteOrNone(attr.Before), quoteOrNone(attr.After))
+			}
+		}
+	}
+	return b.String()
+}
+
+// snapshotStore keeps named DOM snapshots in memory, per page
+type snapshotStore struct {
+	mu        sync.Mutex
+	snapshots map[string]*domSnapshot
+}
+
+func snapshotKey(pageID, name string) string {
+	return pageID + "/" + name
+}
+
+func (s *snapshotStore) put(snapshot *domSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.snapshots == nil {
+		s.snapshots = make(map[string]*domSnapshot)
+	}
+	key := snapshotKey(snapshot.PageID, snapshot.Name)
+	if _, replacing := s.snapshots[key]; !replacing && len(s.snapshots) >= maxSnapshots {
+		var oldestKey string
+		var oldest time.Time
+		for k, existing := range s.snapshots {
+			if oldestKey == "" || existing.TakenAt.Before(oldest) {
+				oldestKey, oldest = k, existing.TakenAt
+			}
+		}
+		delete(s.snapshots, oldestKey)
+	}
+	s.snapshots[key] = snapshot
+}
+
+func (s *snapshotStore) get(pageID, name string) *domSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshots[snapshotKey(pageID, name)]
+}
+
+func (s *snapshotStore) remove(pageID, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := snapshotKey(pageID, name)
+	_, ok := s.snapshots[key]
+	delete(s.snapshots, key)
+	return ok
+}
+
+// DiffPageStateTool snapshots a page's DOM and reports what changed since
+type DiffPageStateTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	snapshots  snapshotStore
+}
+
+func NewDiffPageStateTool(log *logger.Logger, mgr *browser.Manager) *DiffPageStateTool {
+	return &DiffPageStateTool{logger: log, browserMgr: mgr}
+}
+
+func (t *DiffPageStateTool) Name() string {
+	return "diff_page_state"
+}
+
+func (t *DiffPageStateTool) Description() string {
+	return "Show what an action changed on a page: take a snapshot of the DOM (or a selector subtree), perform the action with other tools, then diff to list added, removed and changed elements, text, attributes and form values"
+}
+
+func (t *DiffPageStateTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "snapshot: record the current state; diff: compare the page (or compare_to snapshot) with a snapshot; clear: drop a snapshot",
+				"enum":        []string{"snapshot", "diff", "clear"},
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Snapshot name, to keep several states per page (default: \"default\")",
+				"pattern":     recipeNamePattern.String(),
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Snapshot only this subtree (snapshot action; diff reuses the snapshot's selector)",
+				"examples":    []string{"#cart", "main"},
+			},
+			"compare_to": map[string]interface{}{
+				"type":        "string",
+				"description": "Diff against this second snapshot instead of the live page",
+			},
+			"max_changes": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most changes to report (default: 100)",
+				"default":     defaultMaxChanges,
+				"minimum":     1,
+				"maximum":     1000,
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (t *DiffPageStateTool) capture(pageID, name, selector string) (*domSnapshot, error) {
+	raw, err := t.browserMgr.ExecuteScript(pageID, snapshotScript(selector))
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot page: %w", err)
+	}
+	var result struct {
+		Error     string    `json:"error"`
+		URL       string    `json:"url"`
+		Nodes     []domNode `json:"nodes"`
+		Truncated bool      `json:"truncated"`
+	}
+	if err := decodeScriptValue(raw, &result); err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	if result.Error != "" {
+		return nil, fmt.Errorf("%s", result.Error)
+	}
+	return &domSnapshot{
+		Name:      name,
+		PageID:    pageID,
+		URL:       result.URL,
+		Selector:  selector,
+		Nodes:     result.Nodes,
+		Truncated: result.Truncated,
+		TakenAt:   time.Now(),
+	}, nil
+}
+
+func (t *DiffPageStateTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+		succeed := func(text string, data map[string]interface{}) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+			}, nil
+		}
+
+		action, _ := args["action"].(string)
+		name, _ := args["name"].(string)
+		if name == "" {
+			name = defaultSnapshotName
+		}
+		if !recipeNamePattern.MatchString(name) {
+			return fail("name must be 1-64 letters, digits, '.', '_' or '-'")
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		switch action {
+		case "snapshot":
+			selector, _ := args["selector"].(string)
+			snapshot, err := t.capture(pageID, name, selector)
+			if err != nil {
+				return fail(err.Error())
+			}
+			t.snapshots.put(snapshot)
+			text := fmt.Sprintf("Saved snapshot %q of %s (%d elements)", name, snapshot.URL, len(snapshot.Nodes))
+			if snapshot.Truncated {
+				text += fmt.Sprintf("; only the first %d elements were recorded, narrow it with selector", maxSnapshotNodes)
+			}
+			return succeed(text, map[string]interface{}{
+				"snapshot": snapshot,
+				"elements": len(snapshot.Nodes),
+			})
+
+		case "diff":
+			before := t.snapshots.get(pageID, name)
+			if before == nil {
+				return fail(fmt.Sprintf("no snapshot named %q for page %s; take one first with action=snapshot", name, pageID))
+			}
+			var after *domSnapshot
+			if other, _ := args["compare_to"].(string); other != "" {
+				if after = t.snapshots.get(pageID, other); after == nil {
+					return fail(fmt.Sprintf("no snapshot named %q for page %s", other, pageID))
+				}
+			} else {
+				var err error
+				if after, err = t.capture(pageID, "", before.Selector); err != nil {
+					return fail(err.Error())
+				}
+			}
+
+			maxChanges := defaultMaxChanges
+			if val, ok := args["max_changes"].(float64); ok {
+				maxChanges = int(val)
+			}
+			changes := diffSnapshots(before.Nodes, after.Nodes)
+			total := len(changes)
+			if len(changes) > maxChanges {
+				changes = changes[:maxChanges]
+			}
+
+			counts := map[string]int{}
+			for _, c := range changes {
+				counts[c.Kind]++
+			}
+			var text strings.Builder
+			if total == 0 {
+				fmt.Fprintf(&text, "No changes since snapshot %q", name)
+			} else {
+				fmt.Fprintf(&text, "%d changes since snapshot %q (%d added, %d removed, %d changed)",
+					total, name, counts["added"], counts["removed"], counts["changed"])
+				if total > len(changes) {
+					fmt.Fprintf(&text, ", showing the first %d", len(changes))
+				}
+			}
+			if before.URL != after.URL {
+				fmt.Fprintf(&text, "\nURL: %s -> %s", before.URL, after.URL)
+			}
+			if len(changes) > 0 {
+				text.WriteString("\n\n" + formatDiff(changes))
+			}
+
+			return succeed(text.String(), map[string]interface{}{
+				"page_id":    pageID,
+				"snapshot":   name,
+				"url_before": before.URL,
+				"url_after":  after.URL,
+				"changes":    changes,
+				"total":      total,
+				"truncated":  total > len(changes),
+			})
+
+		case "clear":
+			if !t.snapshots.remove(pageID, name) {
+				return fail(fmt.Sprintf("no snapshot named %q for page %s", name, pageID))
+			}
+			return succeed(fmt.Sprintf("Cleared snapshot %q", name), map[string]interface{}{"page_id": pageID, "snapshot": name})
+		}
+		return fail("action must be snapshot, diff or clear")
+	})
+}
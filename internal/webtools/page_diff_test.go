@@ -0,0 +1,101 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	before := []domNode{
+		{Path: "body", Depth: 0, Tag: "body"},
+		{Path: "body > ul:nth-of-type(1)", Depth: 1, Tag: "ul", ID: "items"},
+		{Path: "body > ul:nth-of-type(1) > li:nth-of-type(1)", Depth: 2, Tag: "li", Text: "Apples"},
+		{Path: "body > ul:nth-of-type(1) > li:nth-of-type(2)", Depth: 2, Tag: "li", Text: "Pears"},
+		{Path: "body > span:nth-of-type(1)", Depth: 1, Tag: "span", ID: "count", Text: "2"},
+		{Path: "body > button:nth-of-type(1)", Depth: 1, Tag: "button", ID: "buy", Text: "Buy"},
+		{Path: "body > div:nth-of-type(1)", Depth: 1, Tag: "div", Class: "banner", Text: "Sale"},
+	}
+	after := []domNode{
+		{Path: "body", Depth: 0, Tag: "body"},
+		{Path: "body > ul:nth-of-type(1)", Depth: 1, Tag: "ul", ID: "items"},
+		{Path: "body > ul:nth-of-type(1) > li:nth-of-type(1)", Depth: 2, Tag: "li", Text: "Apples"},
+		{Path: "body > ul:nth-of-type(1) > li:nth-of-type(2)", Depth: 2, Tag: "li", Text: "Pears"},
+		{Path: "body > ul:nth-of-type(1) > li:nth-of-type(3)", Depth: 2, Tag: "li", Text: "Plums"},
+		{Path: "body > span:nth-of-type(1)", Depth: 1, Tag: "span", ID: "count", Text: "3"},
+		{Path: "body > button:nth-of-type(1)", Depth: 1, Tag: "button", ID: "buy", Text: "Buy", Attrs: map[string]string{"disabled": ""}},
+		{Path: "body > div:nth-of-type(1)", Depth: 1, Tag: "div", Class: "toast", Text: "Added"},
+		{Path: "body > div:nth-of-type(1) > a:nth-of-type(1)", Depth: 2, Tag: "a", Text: "View cart"},
+	}
+
+	changes := diffSnapshots(before, after)
+	if len(changes) != 5 {
+		t.Fatalf("expected 5 changes, got %d:\n%s", len(changes), formatDiff(changes))
+	}
+
+	if c := changes[0]; c.Kind != "added" || c.Text != "Plums" {
+		t.Errorf("inserted item not reported as added: %+v", c)
+	}
+	if c := changes[1]; c.Kind != "changed" || *c.TextBefore != "2" || *c.TextAfter != "3" {
+		t.Errorf("text change not reported: %+v", c)
+	}
+	if c := changes[2]; c.Kind != "changed" || len(c.Attributes) != 1 || c.Attributes[0].Before != nil {
+		t.Errorf("new attribute not reported: %+v", c)
+	}
+	// The replaced banner is one removal and one folded addition
+	if c := changes[3]; c.Kind != "removed" || c.Element != "div.banner" {
+		t.Errorf("expected banner removal, got %+v", c)
+	}
+	if c := changes[4]; c.Kind != "added" || c.Text != "Added View cart" || c.Descendants != 1 {
+		t.Errorf("expected folded toast addition, got %+v", c)
+	}
+
+	text := formatDiff(changes)
+	for _, want := range []string{
+		`+ added li "Plums"`,
+		`text: "2" -> "3"`,
+		`[disabled]: (none) -> ""`,
+		`- removed div.banner "Sale"`,
+		`+ added div.toast "Added View cart" (+1 nested)`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("diff missing %q:\n%s", want, text)
+		}
+	}
+
+	if changes := diffSnapshots(before, before); len(changes) != 0 {
+		t.Errorf("identical snapshots should not differ: %+v", changes)
+	}
+}
+
+func TestSnapshotStoreEvictsOldest(t *testing.T) {
+	var store snapshotStore
+	taken := time.Now()
+	for i := 0; i <= maxSnapshots; i++ {
+		store.put(&domSnapshot{PageID: "page", Name: string(rune('a' + i)), TakenAt: taken.Add(time.Duration(i) * time.Second)})
+	}
+	if store.get("page", "a") != nil {
+		t.Error("oldest snapshot should have been evicted")
+	}
+	if store.get("page", string(rune('a'+maxSnapshots))) == nil {
+		t.Error("newest snapshot missing")
+	}
+	if !store.remove("page", "b") || store.remove("page", "b") {
+		t.Error("remove should succeed once")
+	}
+}
+
+func TestDiffPageStateNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewDiffPageStateTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{"action": "snapshot"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
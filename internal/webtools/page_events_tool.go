@@ -0,0 +1,151 @@
+package webtools
+
+import (
+	"fmt"
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"strings"
+	"time"
+)
+
+// SubscribePageEventsTool manages per-page subscriptions to browser event notifications
+type SubscribePageEventsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSubscribePageEventsTool(log *logger.Logger, mgr *browser.Manager) *SubscribePageEventsTool {
+	return &SubscribePageEventsTool{
+		logger:     log,
+		browserMgr: mgr,
+	}
+}
+
+func (t *SubscribePageEventsTool) Name() string {
+	return "subscribe_page_events"
+}
+
+func (t *SubscribePageEventsTool) Description() string {
+	return "Subscribe to browser events for a page (load, navigation, dialog, download, console_error). Events are pushed as " + types.PageEventNotificationMethod + " notifications instead of polling with wait tools"
+}
+
+func (t *SubscribePageEventsTool) InputSchema() types.ToolSchema {
+	eventNames := make([]string, len(browser.AllPageEventTypes))
+	for i, e := range browser.AllPageEventTypes {
+		eventNames[i] = string(e)
+	}
+
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID to subscribe to (uses first page if not specified)",
+			},
+			"events": map[string]interface{}{
+				"type":        "array",
+				"description": "Event types to subscribe to or unsubscribe from (all types if omitted)",
+				"items": map[string]interface{}{
+					"type": "string",
+					"enum": eventNames,
+				},
+			},
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "Whether to add, remove, or list subscriptions",
+				"enum":        []string{"subscribe", "unsubscribe", "list"},
+				"default":     "subscribe",
+			},
+		},
+	}
+}
+
+func (t *SubscribePageEventsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pages := t.browserMgr.ListPages()
+			if len(pages) == 0 {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+			pageID = pages[0]
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "subscribe"
+		}
+
+		var eventTypes []browser.PageEventType
+		if raw, ok := args["events"].([]interface{}); ok {
+			for _, item := range raw {
+				name, _ := item.(string)
+				eventType, err := browser.ParsePageEventType(name)
+				if err != nil {
+					return &types.CallToolResponse{
+						Content: []types.ToolContent{{
+							Type: "text",
+							Text: fmt.Sprintf("Error: %v", err),
+						}},
+						IsError: true,
+					}, nil
+				}
+				eventTypes = append(eventTypes, eventType)
+			}
+		}
+
+		switch action {
+		case "subscribe":
+			if err := t.browserMgr.SubscribePageEvents(pageID, eventTypes); err != nil {
+				t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Error subscribing to page events: %v", err),
+					}},
+					IsError: true,
+				}, nil
+			}
+		case "unsubscribe":
+			t.browserMgr.UnsubscribePageEvents(pageID, eventTypes)
+		case "list":
+		default:
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: unknown action %q (use subscribe, unsubscribe, or list)", action),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		active := t.browserMgr.PageEventSubscriptions(pageID)
+		names := make([]string, len(active))
+		for i, e := range active {
+			names[i] = string(e)
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		summary := "none"
+		if len(names) > 0 {
+			summary = strings.Join(names, ", ")
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Page %s event subscriptions: %s", pageID, summary),
+				Data: map[string]interface{}{
+					"page_id":      pageID,
+					"action":       action,
+					"subscribed":   names,
+					"notification": types.PageEventNotificationMethod,
+				},
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,338 @@
+package webtools
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// maxGroupSamples is how many URLs each origin lists as examples
+const maxGroupSamples = 5
+
+// requestGroup summarizes the requests a page made to one origin
+type requestGroup struct {
+	Origin     string         `json:"origin"`
+	Site       string         `json:"site"`
+	ThirdParty bool           `json:"third_party"`
+	Unexpected bool           `json:"unexpected,omitempty"`
+	Requests   int            `json:"requests"`
+	Types      map[string]int `json:"types"`
+	Bytes      float64        `json:"bytes"`
+	Failed     int            `json:"failed,omitempty"`
+	Samples    []string       `json:"samples"`
+}
+
+// mixedContentRequest is an insecure request made from a secure page
+type mixedContentRequest struct {
+	URL          string `json:"url"`
+	ResourceType string `json:"resource_type"`
+	Blocked      bool   `json:"blocked"`
+}
+
+// requestInventory is everything list_page_requests reports for a page
+type requestInventory struct {
+	PageURL      string                `json:"page_url"`
+	Site         string                `json:"site"`
+	Total        int                   `json:"total"`
+	Bytes        float64               `json:"bytes"`
+	Failed       int                   `json:"failed"`
+	Inline       int                   `json:"inline"`
+	ByType       map[string]int        `json:"by_type"`
+	Origins      []*requestGroup       `json:"origins"`
+	MixedContent []mixedContentRequest `json:"mixed_content"`
+	ThirdParties []string              `json:"third_parties"`
+	Unexpected   []string              `json:"unexpected_third_parties"`
+}
+
+// siteOf returns the registrable domain of a host (example.co.uk for
+// www.example.co.uk); IP addresses and single-label hosts are their own site
+func siteOf(host string) string {
+	host = strings.ToLower(host)
+	if site, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return site
+	}
+	return host
+}
+
+// inventoryRequests groups a page's requests by origin and flags mixed
+// content and third parties. Hosts covered by allowedDomains (a domain
+// covers its subdomains) are expected third parties.
+func inventoryRequests(pageURL string, requests []browser.NetworkRequest, allowedDomains []string) requestInventory {
+	inv := requestInventory{PageURL: pageURL, ByType: make(map[string]int)}
+	page, _ := url.Parse(pageURL)
+	secure := page != nil && page.Scheme == "https"
+	if page != nil {
+		inv.Site = siteOf(page.Hostname())
+	}
+
+	groups := make(map[string]*requestGroup)
+	thirdParties := make(map[string]bool)
+	unexpected := make(map[string]bool)
+	for _, r := range requests {
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "ws" && u.Scheme != "wss") {
+			// data:, blob: and similar never leave the browser
+			inv.Inline++
+			continue
+		}
+
+		inv.Total++
+		inv.ByType[r.ResourceType]++
+		inv.Bytes += r.EncodedBytes
+		if r.Failed {
+			inv.Failed++
+		}
+
+		insecure := u.Scheme == "http" || u.Scheme == "ws"
+		if r.MixedContent != "" || r.BlockedReason == "mixed-content" || (secure && insecure) {
+			inv.MixedContent = append(inv.MixedContent, mixedContentRequest{
+				URL:          r.URL,
+				ResourceType: r.ResourceType,
+				Blocked:      r.BlockedReason == "mixed-content",
+			})
+		}
+
+		origin := u.Scheme + "://" + u.Host
+		group, ok := groups[origin]
+		if !ok {
+			site := siteOf(u.Hostname())
+			group = &requestGroup{
+				Origin:     origin,
+				Site:       site,
+				ThirdParty: inv.Site != "" && site != inv.Site,
+				Types:      make(map[string]int),
+			}
+			if group.ThirdParty {
+				thirdParties[site] = true
+				group.Unexpected = len(allowedDomains) > 0
+				for _, domain := range allowedDomains {
+					if cookieMatchesHost(domain, u.Hostname()) {
+						group.Unexpected = false
+						break
+					}
+				}
+				if group.Unexpected {
+					unexpected[site] = true
+				}
+			}
+			groups[origin] = group
+		}
+		group.Requests++
+		group.Types[r.ResourceType]++
+		group.Bytes += r.EncodedBytes
+		if r.Failed {
+			group.Failed++
+		}
+		if len(group.Samples) < maxGroupSamples {
+			group.Samples = append(group.Samples, r.URL)
+		}
+	}
+
+	for _, group := range groups {
+		inv.Origins = append(inv.Origins, group)
+	}
+	sort.Slice(inv.Origins, func(i, j int) bool {
+		a, b := inv.Origins[i], inv.Origins[j]
+		if a.ThirdParty != b.ThirdParty {
+			return !a.ThirdParty
+		}
+		if a.Requests != b.Requests {
+			return a.Requests > b.Requests
+		}
+		return a.Origin < b.Origin
+	})
+	inv.ThirdParties = sortedKeys(thirdParties)
+	inv.Unexpected = sortedKeys(unexpected)
+	return inv
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatCounts renders a type breakdown as "Script 4, Image 2"
+func formatCounts(counts map[string]int) string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		label := name
+		if label == "" {
+			label = "Other"
+		}
+		parts[i] = fmt.Sprintf("%s %d", label, counts[name])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatRequestInventory(inv requestInventory, allowlist bool) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d requests from %s (%s, %.1f KB", inv.Total, inv.PageURL, formatCounts(inv.ByType), inv.Bytes/1024)
+	if inv.Failed > 0 {
+		fmt.Fprintf(&b, ", %d failed", inv.Failed)
+	}
+	b.WriteString(")\n")
+
+	if len(inv.MixedContent) > 0 {
+		fmt.Fprintf(&b, "\nMixed content (%d):\n", len(inv.MixedContent))
+		for _, m := range inv.MixedContent {
+			state := "loaded"
+			if m.Blocked {
+				state = "blocked"
+			}
+			fmt.Fprintf(&b, "  - [%s] %s %s\n", state, m.ResourceType, m.URL)
+		}
+	}
+
+	switch {
+	case len(inv.Unexpected) > 0:
+		fmt.Fprintf(&b, "\nUnexpected third parties (%d): %s\n", len(inv.Unexpected), strings.Join(inv.Unexpected, ", "))
+	case allowlist && len(inv.ThirdParties) > 0:
+		b.WriteString("\nAll third parties are in allowed_domains\n")
+	}
+
+	b.WriteString("\nBy origin:\n")
+	for _, g := range inv.Origins {
+		marker := "first-party"
+		if g.Unexpected {
+			marker = "UNEXPECTED third-party"
+		} else if g.ThirdParty {
+			marker = "third-party"
+		}
+		fmt.Fprintf(&b, "  %s [%s] %d requests, %.1f KB (%s)", g.Origin, marker, g.Requests, g.Bytes/1024, formatCounts(g.Types))
+		if g.Failed > 0 {
+			fmt.Fprintf(&b, ", %d failed", g.Failed)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// ListPageRequestsTool inventories the network requests a page has made
+type ListPageRequestsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewListPageRequestsTool(log *logger.Logger, mgr *browser.Manager) *ListPageRequestsTool {
+	return &ListPageRequestsTool{logger: log, browserMgr: mgr}
+}
+
+func (t *ListPageRequestsTool) Name() string {
+	return "list_page_requests"
+}
+
+func (t *ListPageRequestsTool) Description() string {
+	return "List every resource the page has loaded since its last navigation, grouped by origin and type, flagging mixed content (insecure requests from an HTTPS page) and third parties outside allowed_domains"
+}
+
+func (t *ListPageRequestsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"allowed_domains": map[string]interface{}{
+				"type":        "array",
+				"description": "Third-party domains the page is expected to use; others are flagged as unexpected. A domain covers its subdomains",
+				"items":       map[string]interface{}{"type": "string"},
+				"examples":    []interface{}{[]string{"googleapis.com", "cdn.example.net"}},
+			},
+			"include_requests": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Also return every individual request in the result data (default: false)",
+				"default":     false,
+			},
+			"clear": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Empty the page's request log after reading it, so the next call shows only new requests (default: false)",
+				"default":     false,
+			},
+		},
+	}
+}
+
+func (t *ListPageRequestsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		var allowed []string
+		if list, ok := args["allowed_domains"].([]interface{}); ok {
+			for _, item := range list {
+				if domain, ok := item.(string); ok && strings.TrimSpace(domain) != "" {
+					allowed = append(allowed, strings.TrimSpace(domain))
+				}
+			}
+		}
+
+		requests, dropped, err := t.browserMgr.NetworkRequests(pageID)
+		if err != nil {
+			return fail(err.Error())
+		}
+		info, err := t.browserMgr.GetPageInfo(pageID)
+		if err != nil {
+			return fail(err.Error())
+		}
+		pageURL, _ := info["url"].(string)
+
+		if clear, _ := args["clear"].(bool); clear {
+			if err := t.browserMgr.ClearNetworkRequests(pageID); err != nil {
+				return fail(err.Error())
+			}
+		}
+
+		inv := inventoryRequests(pageURL, requests, allowed)
+		text := formatRequestInventory(inv, len(allowed) > 0)
+		if dropped > 0 {
+			text += fmt.Sprintf("\n%d older requests were dropped from the log\n", dropped)
+		}
+		data := map[string]interface{}{
+			"page_id":   pageID,
+			"inventory": inv,
+			"dropped":   dropped,
+		}
+		if include, _ := args["include_requests"].(bool); include {
+			data["requests"] = requests
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
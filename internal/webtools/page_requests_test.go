@@ -0,0 +1,83 @@
+package webtools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestInventoryRequests(t *testing.T) {
+	requests := []browser.NetworkRequest{
+		{URL: "https://www.shop.co.uk/", ResourceType: "Document", EncodedBytes: 10240},
+		{URL: "https://static.shop.co.uk/app.js", ResourceType: "Script", EncodedBytes: 4096},
+		{URL: "https://fonts.googleapis.com/css", ResourceType: "Stylesheet"},
+		{URL: "https://tracker.adnet.com/p.js", ResourceType: "Script"},
+		{URL: "https://tracker.adnet.com/collect", ResourceType: "XHR", Failed: true},
+		{URL: "http://img.partner.com/banner.png", ResourceType: "Image", MixedContent: "optionally-blockable"},
+		{URL: "http://legacy.shop.co.uk/old.js", ResourceType: "Script", Failed: true, BlockedReason: "mixed-content"},
+		{URL: "data:image/png;base64,AAAA", ResourceType: "Image"},
+	}
+
+	inv := inventoryRequests("https://www.shop.co.uk/", requests, []string{"googleapis.com", "partner.com"})
+
+	if inv.Site != "shop.co.uk" || inv.Total != 7 || inv.Inline != 1 || inv.Failed != 2 {
+		t.Errorf("unexpected totals: %+v", inv)
+	}
+	if inv.ByType["Script"] != 3 {
+		t.Errorf("by type = %v", inv.ByType)
+	}
+	// googleapis.com is a public suffix, so each of its hosts is a site
+	if want := []string{"adnet.com", "fonts.googleapis.com", "partner.com"}; !reflect.DeepEqual(inv.ThirdParties, want) {
+		t.Errorf("third parties = %v, want %v", inv.ThirdParties, want)
+	}
+	if want := []string{"adnet.com"}; !reflect.DeepEqual(inv.Unexpected, want) {
+		t.Errorf("unexpected = %v, want %v", inv.Unexpected, want)
+	}
+	if len(inv.MixedContent) != 2 || inv.MixedContent[0].Blocked || !inv.MixedContent[1].Blocked {
+		t.Errorf("mixed content = %+v", inv.MixedContent)
+	}
+
+	// First-party origins are listed before third parties
+	for _, g := range inv.Origins[:3] {
+		if g.ThirdParty {
+			t.Errorf("third party %s listed among first-party origins", g.Origin)
+		}
+	}
+
+	text := formatRequestInventory(inv, true)
+	for _, want := range []string{
+		"7 requests from https://www.shop.co.uk/ (Script 3,",
+		"  - [loaded] Image http://img.partner.com/banner.png",
+		"  - [blocked] Script http://legacy.shop.co.uk/old.js",
+		"Unexpected third parties (1): adnet.com",
+		"https://tracker.adnet.com [UNEXPECTED third-party] 2 requests, 0.0 KB (Script 1, XHR 1), 1 failed",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestInventoryRequestsWithoutAllowlist(t *testing.T) {
+	inv := inventoryRequests("https://example.com/", []browser.NetworkRequest{
+		{URL: "https://cdn.other.net/a.js", ResourceType: "Script"},
+	}, nil)
+	if len(inv.ThirdParties) != 1 || len(inv.Unexpected) != 0 {
+		t.Errorf("without allowed_domains nothing is unexpected: %+v", inv)
+	}
+}
+
+func TestListPageRequestsNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewListPageRequestsTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
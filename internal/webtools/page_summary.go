@@ -0,0 +1,379 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+const (
+	defaultSummaryElements   = 40
+	defaultSummaryTextBlocks = 12
+	defaultSummaryTextLength = 280
+)
+
+// summaryHeading is one entry of the page outline
+type summaryHeading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+}
+
+// summaryElement is a visible element the model can act on
+type summaryElement struct {
+	Kind     string `json:"kind"` // link, button, input, select, textarea
+	Text     string `json:"text,omitempty"`
+	Selector string `json:"selector"`
+	Href     string `json:"href,omitempty"`
+	Type     string `json:"type,omitempty"`
+}
+
+type summaryField struct {
+	Name     string `json:"name,omitempty"`
+	Type     string `json:"type"`
+	Label    string `json:"label,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Selector string `json:"selector"`
+}
+
+type summaryForm struct {
+	Selector string         `json:"selector"`
+	Action   string         `json:"action,omitempty"`
+	Method   string         `json:"method,omitempty"`
+	Fields   []summaryField `json:"fields"`
+}
+
+// pageSummary is a compact description of a page meant to stand in for its
+// HTML in a model's context
+type pageSummary struct {
+	URL               string           `json:"url"`
+	Title             string           `json:"title"`
+	Description       string           `json:"description,omitempty"`
+	Language          string           `json:"language,omitempty"`
+	Headings          []summaryHeading `json:"headings"`
+	Interactive       []summaryElement `json:"interactive"`
+	InteractiveTotal  int              `json:"interactive_total"`
+	Forms             []summaryForm    `json:"forms"`
+	TextBlocks        []string         `json:"text_blocks"`
+	HTMLLength        int              `json:"html_length"`
+	TokenEstimate     int              `json:"token_estimate"`
+	HTMLTokenEstimate int              `json:"html_token_estimate"`
+}
+
+// estimateTokens approximates how many tokens text costs a model, at the
+// usual four characters per token for English prose and markup
+func estimateTokens(length int) int {
+	return (length + 3) / 4
+}
+
+func summarizePageScript(root string, maxElements, maxBlocks, maxText int) string {
+	rootJSON, _ := json.Marshal(root)
+	return fmt.Sprintf(`
+		const rootSelector = %s;
+		const maxElements = %d;
+		const maxBlocks = %d;
+		const maxText = %d;
+
+		const root = rootSelector ? document.querySelector(rootSelector) : document.body;
+		if (!root) return { error: 'no element matches ' + rootSelector };
+
+		const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+		const clip = (s, n) => s.length > n ? s.slice(0, n - 1) + '…' : s;
+		const visible = el => {
+			const r = el.getBoundingClientRect();
+			if (r.width === 0 && r.height === 0) return false;
+			const s = getComputedStyle(el);
+			return s.visibility !== 'hidden' && s.display !== 'none';
+		};
+		const unique = sel => {
+			try { return document.querySelectorAll(sel).length === 1; } catch (e) { return false; }
+		};
+
+		// A short selector that identifies el without changing the page:
+		// an id or distinctive attribute when unique, otherwise a path of
+		// nth-of-type steps up to the nearest element with a unique id
+		const selectorFor = el => {
+			if (el.id && unique('#' + CSS.escape(el.id))) return '#' + CSS.escape(el.id);
+			const tag = el.tagName.toLowerCase();
+			for (const attr of ['data-testid', 'data-test', 'name', 'aria-label']) {
+				const v = el.getAttribute(attr);
+				if (v && v.length <= 60) {
+					const sel = tag + '[' + attr + '="' + CSS.escape(v) + '"]';
+					if (unique(sel)) return sel;
+				}
+			}
+			const steps = [];
+			for (let n = el; n && n.nodeType === 1 && n !== document.documentElement; n = n.parentElement) {
+				if (n !== el && n.id && unique('#' + CSS.escape(n.id))) {
+					steps.unshift('#' + CSS.escape(n.id));
+					break;
+				}
+				let step = n.tagName.toLowerCase();
+				const parent = n.parentElement;
+				if (parent) {
+					const same = Array.from(parent.children).filter(c => c.tagName === n.tagName);
+					if (same.length > 1) step += ':nth-of-type(' + (same.indexOf(n) + 1) + ')';
+				}
+				steps.unshift(step);
+				if (n === document.body) break;
+			}
+			return steps.join(' > ');
+		};
+
+		const labelFor = el => {
+			if (el.labels && el.labels.length) return clean(el.labels[0].innerText);
+			return clean(el.getAttribute('aria-label') || el.getAttribute('placeholder') || el.getAttribute('title') || '');
+		};
+
+		const headings = Array.from(root.querySelectorAll('h1, h2, h3, h4, h5, h6'))
+			.filter(visible)
+			.map(h => ({ level: Number(h.tagName[1]), text: clip(clean(h.innerText), 120) }))
+			.filter(h => h.text)
+			.slice(0, 60);
+
+		const controls = Array.from(root.querySelectorAll(
+			'a[href], button, input:not([type="hidden"]), select, textarea, [role="button"], [role="link"]'))
+			.filter(visible);
+		const interactive = [];
+		for (const el of controls) {
+			if (interactive.length >= maxElements) break;
+			const tag = el.tagName.toLowerCase();
+			let kind = tag === 'a' || el.getAttribute('role') === 'link' ? 'link'
+				: tag === 'button' || el.getAttribute('role') === 'button' ? 'button' : tag;
+			const type = tag === 'input' ? (el.type || 'text') : '';
+			if (tag === 'input' && ['submit', 'button', 'reset', 'image'].includes(type)) kind = 'button';
+			let text = clean(el.innerText || el.value || '') || labelFor(el);
+			const item = { kind: kind, text: clip(text, 80), selector: selectorFor(el) };
+			if (kind === 'link' && el.getAttribute('href')) item.href = clip(el.getAttribute('href'), 160);
+			if (type && kind !== 'button') item.type = type;
+			interactive.push(item);
+		}
+
+		const forms = Array.from(root.querySelectorAll('form')).filter(visible).slice(0, 10).map(form => ({
+			selector: selectorFor(form),
+			action: form.getAttribute('action') || '',
+			method: (form.getAttribute('method') || 'get').toUpperCase(),
+			fields: Array.from(form.elements)
+				.filter(f => f.tagName !== 'FIELDSET' && f.type !== 'hidden' && !['submit', 'button', 'reset'].includes(f.type))
+				.slice(0, 30)
+				.map(f => {
+					const field = { type: f.type || f.tagName.toLowerCase(), selector: selectorFor(f) };
+					if (f.name) field.name = f.name;
+					const label = labelFor(f);
+					if (label) field.label = clip(label, 80);
+					if (f.required) field.required = true;
+					return field;
+				}),
+		}));
+
+		// Key text: the longest paragraphs of the main content, kept in
+		// document order
+		const main = root.querySelector('main, article, [role="main"]') || root;
+		const candidates = Array.from(main.querySelectorAll('p, li, blockquote, dd, figcaption, pre'))
+			.filter(el => !el.closest('nav, footer, header, aside, form') && visible(el))
+			.map((el, index) => ({ index: index, text: clean(el.innerText) }))
+			.filter(c => c.text.length >= 40);
+		const seen = new Set();
+		const blocks = candidates
+			.filter(c => !seen.has(c.text) && seen.add(c.text))
+			.sort((a, b) => b.text.length - a.text.length)
+			.slice(0, maxBlocks)
+			.sort((a, b) => a.index - b.index)
+			.map(c => clip(c.text, maxText));
+
+		const meta = document.querySelector('meta[name="description"], meta[property="og:description"]');
+		return {
+			url: location.href,
+			title: document.title,
+			description: meta ? clip(clean(meta.getAttribute('content')), 300) : '',
+			language: document.documentElement.lang || '',
+			headings: headings,
+			interactive: interactive,
+			interactive_total: controls.length,
+			forms: forms,
+			text_blocks: blocks,
+			html_length: root.outerHTML.length,
+		};
+	`, rootJSON, maxElements, maxBlocks, maxText)
+}
+
+// formatPageSummary renders a summary as compact text for the model
+func formatPageSummary(s *pageSummary) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n%s\n", s.Title, s.URL)
+	if s.Description != "" {
+		fmt.Fprintf(&b, "%s\n", s.Description)
+	}
+
+	if len(s.Headings) > 0 {
+		b.WriteString("\n## Outline\n")
+		for _, h := range s.Headings {
+			fmt.Fprintf(&b, "%s- %s\n", strings.Repeat("  ", h.Level-1), h.Text)
+		}
+	}
+
+	if len(s.Interactive) > 0 {
+		fmt.Fprintf(&b, "\n## Interactive elements (%d of %d)\n", len(s.Interactive), s.InteractiveTotal)
+		for _, el := range s.Interactive {
+			kind := el.Kind
+			if el.Type != "" {
+				kind += ":" + el.Type
+			}
+			fmt.Fprintf(&b, "[%s] %q %s", kind, el.Text, el.Selector)
+			if el.Href != "" {
+				fmt.Fprintf(&b, " -> %s", el.Href)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(s.Forms) > 0 {
+		b.WriteString("\n## Forms\n")
+		for _, form := range s.Forms {
+			fmt.Fprintf(&b, "%s (%s %s)\n", form.Selector, form.Method, form.Action)
+			for _, field := range form.Fields {
+				name := field.Label
+				if name == "" {
+					name = field.Name
+				}
+				required := ""
+				if field.Required {
+					required = ", required"
+				}
+				fmt.Fprintf(&b, "  - %s [%s%s] %s\n", name, field.Type, required, field.Selector)
+			}
+		}
+	}
+
+	if len(s.TextBlocks) > 0 {
+		b.WriteString("\n## Key text\n")
+		for _, block := range s.TextBlocks {
+			fmt.Fprintf(&b, "- %s\n", block)
+		}
+	}
+
+	fmt.Fprintf(&b, "\n~%d tokens (page HTML ~%d tokens)\n", s.TokenEstimate, s.HTMLTokenEstimate)
+	return b.String()
+}
+
+// SummarizePageTool produces a compact structured summary of a page
+type SummarizePageTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSummarizePageTool(log *logger.Logger, mgr *browser.Manager) *SummarizePageTool {
+	return &SummarizePageTool{logger: log, browserMgr: mgr}
+}
+
+func (t *SummarizePageTool) Name() string {
+	return "summarize_page"
+}
+
+func (t *SummarizePageTool) Description() string {
+	return "Summarize a page compactly instead of reading its HTML: heading outline, visible links, buttons and inputs with selectors, forms and their fields, the main text blocks, and a token estimate"
+}
+
+func (t *SummarizePageTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Summarize only this part of the page (default: the whole body)",
+				"examples":    []string{"main", "#content"},
+			},
+			"max_elements": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most interactive elements to list (default: 40)",
+				"default":     defaultSummaryElements,
+				"minimum":     0,
+				"maximum":     500,
+			},
+			"max_text_blocks": map[string]interface{}{
+				"type":        "integer",
+				"description": "Most text blocks to include (default: 12)",
+				"default":     defaultSummaryTextBlocks,
+				"minimum":     0,
+				"maximum":     100,
+			},
+			"max_text_length": map[string]interface{}{
+				"type":        "integer",
+				"description": "Characters kept from each text block (default: 280)",
+				"default":     defaultSummaryTextLength,
+				"minimum":     40,
+				"maximum":     5000,
+			},
+		},
+	}
+}
+
+func (t *SummarizePageTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+		selector, _ := args["selector"].(string)
+
+		intArg := func(name string, def int) int {
+			if val, ok := args[name].(float64); ok {
+				return int(val)
+			}
+			return def
+		}
+		script := summarizePageScript(selector,
+			intArg("max_elements", defaultSummaryElements),
+			intArg("max_text_blocks", defaultSummaryTextBlocks),
+			intArg("max_text_length", defaultSummaryTextLength))
+
+		raw, err := t.browserMgr.ExecuteScript(pageID, script)
+		if err != nil {
+			return fail(fmt.Sprintf("failed to summarize page: %v", err))
+		}
+		var probe struct {
+			Error string `json:"error"`
+		}
+		if decodeScriptValue(raw, &probe) == nil && probe.Error != "" {
+			return fail(probe.Error)
+		}
+		var summary pageSummary
+		if err := decodeScriptValue(raw, &summary); err != nil {
+			return fail(fmt.Sprintf("failed to read page summary: %v", err))
+		}
+
+		summary.HTMLTokenEstimate = estimateTokens(summary.HTMLLength)
+		// The estimate covers the text the model actually receives
+		summary.TokenEstimate = estimateTokens(len(formatPageSummary(&summary)))
+		text := formatPageSummary(&summary)
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"summary": summary,
+				},
+			}},
+		}, nil
+	})
+}
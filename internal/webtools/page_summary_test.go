@@ -0,0 +1,76 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestFormatPageSummary(t *testing.T) {
+	summary := &pageSummary{
+		URL:         "https://shop.example.com/",
+		Title:       "Shop",
+		Description: "Everything for sale",
+		Headings: []summaryHeading{
+			{Level: 1, Text: "Welcome"},
+			{Level: 2, Text: "Offers"},
+		},
+		Interactive: []summaryElement{
+			{Kind: "link", Text: "Cart", Selector: "#cart", Href: "/cart"},
+			{Kind: "input", Type: "search", Text: "Search", Selector: "input[name=\"q\"]"},
+		},
+		InteractiveTotal: 12,
+		Forms: []summaryForm{{
+			Selector: "#login",
+			Method:   "POST",
+			Action:   "/login",
+			Fields: []summaryField{
+				{Name: "email", Type: "email", Label: "Email", Required: true, Selector: "#email"},
+				{Name: "password", Type: "password", Selector: "#password"},
+			},
+		}},
+		TextBlocks:        []string{"Free shipping on orders over $50."},
+		TokenEstimate:     90,
+		HTMLTokenEstimate: 25000,
+	}
+
+	text := formatPageSummary(summary)
+	for _, want := range []string{
+		"# Shop\nhttps://shop.example.com/\n",
+		"- Welcome\n  - Offers\n",
+		"## Interactive elements (2 of 12)",
+		`[link] "Cart" #cart -> /cart`,
+		`[input:search] "Search" input[name="q"]`,
+		"#login (POST /login)",
+		"  - Email [email, required] #email",
+		"  - password [password] #password",
+		"- Free shipping on orders over $50.",
+		"~90 tokens (page HTML ~25000 tokens)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	for length, want := range map[int]int{0: 0, 1: 1, 4: 1, 5: 2, 4000: 1000} {
+		if got := estimateTokens(length); got != want {
+			t.Errorf("estimateTokens(%d) = %d, want %d", length, got, want)
+		}
+	}
+}
+
+func TestSummarizePageNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewSummarizePageTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
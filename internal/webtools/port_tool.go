@@ -0,0 +1,317 @@
+package webtools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const defaultPortCheckTimeout = 2 * time.Second
+
+// PortOwner describes a process holding a listening socket
+type PortOwner struct {
+	PID     int    `json:"pid"`
+	Name    string `json:"name"`
+	Command string `json:"command,omitempty"`
+	UID     int    `json:"uid"`
+	Address string `json:"address"`
+}
+
+// CheckPortTool reports whether a local port is accepting connections and which process owns it
+type CheckPortTool struct {
+	logger *logger.Logger
+}
+
+func NewCheckPortTool(log *logger.Logger) *CheckPortTool {
+	return &CheckPortTool{logger: log}
+}
+
+func (t *CheckPortTool) Name() string {
+	return "check_port"
+}
+
+func (t *CheckPortTool) Description() string {
+	return "Check whether a local port is listening and which process owns it - use before navigating to localhost to tell 'server not started' from 'app broken'"
+}
+
+func (t *CheckPortTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"port": map[string]interface{}{
+				"type":        "number",
+				"description": "TCP port to check",
+				"minimum":     1,
+				"maximum":     65535,
+				"examples":    []int{3000, 8080, 5173},
+			},
+			"host": map[string]interface{}{
+				"type":        "string",
+				"description": "Local host to connect to (localhost or a loopback address)",
+				"default":     "localhost",
+			},
+			"timeout_ms": map[string]interface{}{
+				"type":        "number",
+				"description": "Connection timeout in milliseconds",
+				"default":     2000,
+				"minimum":     100,
+				"maximum":     10000,
+			},
+		},
+		Required: []string{"port"},
+	}
+}
+
+func (t *CheckPortTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		portVal, ok := args["port"].(float64)
+		if !ok || portVal < 1 || portVal > 65535 || portVal != float64(int(portVal)) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: "Error: port must be an integer between 1 and 65535",
+				}},
+				IsError: true,
+			}, nil
+		}
+		port := int(portVal)
+
+		host := "localhost"
+		if val, ok := args["host"].(string); ok && val != "" {
+			host = val
+		}
+		if !isLocalHost(host) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error: host %q is not local - check_port only inspects localhost and loopback addresses", host),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		timeout := defaultPortCheckTimeout
+		if val, ok := args["timeout_ms"].(float64); ok && val >= 100 {
+			timeout = time.Duration(val) * time.Millisecond
+			if timeout > 10*time.Second {
+				timeout = 10 * time.Second
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		listening, dialErr := probePort(ctx, host, port)
+		owners, ownerErr := FindPortOwners(port)
+
+		t.logger.WithComponent("tools").Info("Port checked",
+			zap.String("host", host),
+			zap.Int("port", port),
+			zap.Bool("listening", listening),
+			zap.Int("owners", len(owners)))
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		var text strings.Builder
+		if listening {
+			fmt.Fprintf(&text, "Port %d on %s is listening", port, host)
+		} else {
+			fmt.Fprintf(&text, "Port %d on %s is NOT listening", port, host)
+			if dialErr != nil {
+				fmt.Fprintf(&text, " (%v)", dialErr)
+			}
+			if len(owners) == 0 {
+				text.WriteString(" - the server does not appear to be running")
+			}
+		}
+		text.WriteString("\n")
+		for _, o := range owners {
+			fmt.Fprintf(&text, "  Owner: %s (pid %d) on %s", o.Name, o.PID, o.Address)
+			if o.Command != "" {
+				fmt.Fprintf(&text, " - %s", o.Command)
+			}
+			text.WriteString("\n")
+		}
+		if listening && len(owners) == 0 && ownerErr != nil {
+			fmt.Fprintf(&text, "  Owning process unavailable: %v\n", ownerErr)
+		}
+
+		data := map[string]interface{}{
+			"host":      host,
+			"port":      port,
+			"listening": listening,
+			"owners":    owners,
+		}
+		if dialErr != nil {
+			data["error"] = dialErr.Error()
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text.String(),
+				Data: data,
+			}},
+		}, nil
+	})
+}
+
+// isLocalHost reports whether host refers to this machine
+func isLocalHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && (ip.IsLoopback() || ip.IsUnspecified())
+}
+
+// probePort attempts a TCP connection to host:port
+func probePort(ctx context.Context, host string, port int) (bool, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(strings.Trim(host, "[]"), strconv.Itoa(port)))
+	if err != nil {
+		return false, err
+	}
+	conn.Close()
+	return true, nil
+}
+
+// FindPortOwners returns the processes with a listening TCP socket on port.
+// It reads /proc and so only identifies owners on Linux; processes belonging
+// to other users are reported without a PID when their fds are unreadable.
+func FindPortOwners(port int) ([]PortOwner, error) {
+	inodes := make(map[string]PortOwner)
+	var lastErr error
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		found, err := listeningSockets(table, port)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for inode, owner := range found {
+			inodes[inode] = owner
+		}
+	}
+	if len(inodes) == 0 {
+		return nil, lastErr
+	}
+
+	procDirs, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var owners []PortOwner
+	matched := make(map[string]bool)
+	for _, entry := range procDirs {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := filepath.Join("/proc", entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode := strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]")
+			owner, ok := inodes[inode]
+			if !ok || matched[inode] {
+				continue
+			}
+			matched[inode] = true
+			owner.PID = pid
+			if comm, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "comm")); err == nil {
+				owner.Name = strings.TrimSpace(string(comm))
+			}
+			if cmdline, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "cmdline")); err == nil {
+				owner.Command = strings.TrimSpace(strings.ReplaceAll(string(cmdline), "\x00", " "))
+			}
+			owners = append(owners, owner)
+		}
+	}
+
+	// Sockets whose owning process could not be read are still worth reporting
+	for inode, owner := range inodes {
+		if !matched[inode] {
+			owner.Name = "(unknown - process not readable)"
+			owners = append(owners, owner)
+		}
+	}
+	return owners, nil
+}
+
+// listeningSockets parses a /proc/net/tcp table for LISTEN sockets on port, keyed by inode
+func listeningSockets(table string, port int) (map[string]PortOwner, error) {
+	f, err := os.Open(table)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	result := make(map[string]PortOwner)
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // header
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 || fields[3] != "0A" {
+			continue
+		}
+		addr, sockPort, ok := parseProcNetAddress(fields[1])
+		if !ok || sockPort != port {
+			continue
+		}
+		uid, _ := strconv.Atoi(fields[7])
+		result[fields[9]] = PortOwner{
+			UID:     uid,
+			Address: net.JoinHostPort(addr, strconv.Itoa(sockPort)),
+		}
+	}
+	return result, scanner.Err()
+}
+
+// parseProcNetAddress decodes the hex "ADDR:PORT" form used in /proc/net/tcp{,6}
+func parseProcNetAddress(s string) (string, int, bool) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return "", 0, false
+	}
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return "", 0, false
+	}
+
+	raw := parts[0]
+	if len(raw) != 8 && len(raw) != 32 {
+		return "", 0, false
+	}
+	ip := make(net.IP, len(raw)/2)
+	// Addresses are stored as host-order 32-bit words
+	for word := 0; word < len(raw)/8; word++ {
+		for b := 0; b < 4; b++ {
+			v, err := strconv.ParseUint(raw[word*8+(3-b)*2:word*8+(3-b)*2+2], 16, 8)
+			if err != nil {
+				return "", 0, false
+			}
+			ip[word*4+b] = byte(v)
+		}
+	}
+	return ip.String(), int(port), true
+}
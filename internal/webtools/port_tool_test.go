@@ -0,0 +1,70 @@
+package webtools
+
+import (
+	"net"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestCheckPortListening(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	tool := NewCheckPortTool(createTestLogger(t))
+	resp, err := tool.Execute(map[string]interface{}{"port": float64(port), "host": "127.0.0.1"})
+	if err != nil || resp.IsError {
+		t.Fatalf("check_port failed: %v %v", err, resp)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if data["listening"] != true {
+		t.Fatalf("Expected port %d to be listening: %s", port, resp.Content[0].Text)
+	}
+
+	if runtime.GOOS == "linux" {
+		owners := data["owners"].([]PortOwner)
+		found := false
+		for _, o := range owners {
+			if o.PID == os.Getpid() {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected this process (%d) to own port %d, got %+v", os.Getpid(), port, owners)
+		}
+	}
+
+	listener.Close()
+	resp, _ = tool.Execute(map[string]interface{}{"port": float64(port)})
+	if resp.Content[0].Data.(map[string]interface{})["listening"] != false {
+		t.Errorf("Expected closed port to report not listening: %s", resp.Content[0].Text)
+	}
+}
+
+func TestCheckPortRejectsRemoteHost(t *testing.T) {
+	tool := NewCheckPortTool(createTestLogger(t))
+	for _, args := range []map[string]interface{}{
+		{"port": float64(80), "host": "example.com"},
+		{"port": float64(80), "host": "10.0.0.1"},
+		{"port": float64(70000)},
+	} {
+		resp, _ := tool.Execute(args)
+		if !resp.IsError {
+			t.Errorf("Expected %v to be rejected", args)
+		}
+	}
+}
+
+func TestParseProcNetAddress(t *testing.T) {
+	addr, port, ok := parseProcNetAddress("0100007F:1F90")
+	if !ok || addr != "127.0.0.1" || port != 8080 {
+		t.Errorf("Unexpected IPv4 parse: %s %d %v", addr, port, ok)
+	}
+	addr, port, ok = parseProcNetAddress("00000000000000000000000001000000:0BB8")
+	if !ok || addr != "::1" || port != 3000 {
+		t.Errorf("Unexpected IPv6 parse: %s %d %v", addr, port, ok)
+	}
+}
@@ -0,0 +1,295 @@
+package webtools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// SetUserAgentTool overrides the user agent one page sends
+type SetUserAgentTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSetUserAgentTool(log *logger.Logger, mgr *browser.Manager) *SetUserAgentTool {
+	return &SetUserAgentTool{logger: log, browserMgr: mgr}
+}
+
+func (t *SetUserAgentTool) Name() string {
+	return "set_user_agent"
+}
+
+func (t *SetUserAgentTool) Description() string {
+	return "Make one page present a custom user agent string, both in the User-Agent header of its requests and in navigator.userAgent. Takes precedence over emulate_device's user agent until reset"
+}
+
+func (t *SetUserAgentTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"set", "reset"},
+				"description": "set the user agent, or reset the page to the browser's own (or its emulated device's)",
+				"default":     "set",
+			},
+			"user_agent": map[string]interface{}{
+				"type":        "string",
+				"description": "User agent string to send (required for set)",
+				"maxLength":   1024,
+			},
+			"reload": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Reload the page afterwards so the site sees the new user agent",
+				"default":     false,
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to override (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *SetUserAgentTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "set"
+		}
+		if action != "set" && action != "reset" {
+			return fail("action must be one of set, reset")
+		}
+		userAgent, _ := args["user_agent"].(string)
+		userAgent = strings.TrimSpace(userAgent)
+		if action == "set" {
+			if userAgent == "" {
+				return fail("user_agent is required; use action 'reset' to remove the override")
+			}
+			if err := browser.ValidateUserAgent(userAgent); err != nil {
+				return fail(err.Error())
+			}
+		} else {
+			userAgent = ""
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		if err := t.browserMgr.SetUserAgent(pageID, userAgent); err != nil {
+			return fail(fmt.Sprintf("Failed to set user agent: %v", err))
+		}
+		data := map[string]interface{}{"page_id": pageID}
+		text := fmt.Sprintf("Page %s now sends user agent %q", pageID, userAgent)
+		if action == "reset" {
+			text = fmt.Sprintf("Page %s no longer overrides its user agent", pageID)
+		} else {
+			data["user_agent"] = userAgent
+		}
+
+		// The page's own navigator confirms the change took
+		if raw, err := t.browserMgr.ExecuteScript(pageID, "navigator.userAgent"); err == nil {
+			var measured string
+			if decodeScriptValue(raw, &measured) == nil {
+				data["navigator_user_agent"] = measured
+				if action == "reset" {
+					text += fmt.Sprintf(" (navigator.userAgent is %q)", measured)
+				}
+			}
+		}
+
+		if reload, _ := args["reload"].(bool); reload {
+			if _, err := t.browserMgr.ExecuteScript(pageID, "location.reload(); return true;"); err != nil {
+				return fail(fmt.Sprintf("User agent applied but reloading failed: %v", err))
+			}
+			data["reloaded"] = true
+			text += "; reloading"
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
+
+// SetExtraHeadersTool adds HTTP headers to every request one page makes
+type SetExtraHeadersTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewSetExtraHeadersTool(log *logger.Logger, mgr *browser.Manager) *SetExtraHeadersTool {
+	return &SetExtraHeadersTool{logger: log, browserMgr: mgr}
+}
+
+func (t *SetExtraHeadersTool) Name() string {
+	return "set_extra_headers"
+}
+
+func (t *SetExtraHeadersTool) Description() string {
+	return "Add HTTP headers, such as Authorization or an API key, to every request one page makes from now on, navigations and subresources alike, without a proxy. Header values are never echoed back or logged"
+}
+
+// SensitiveArguments keeps header values out of the server's logs; the
+// header names are still recorded (see mcp.SensitiveTool)
+func (t *SetExtraHeadersTool) SensitiveArguments() []string {
+	return []string{"headers"}
+}
+
+func (t *SetExtraHeadersTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"enum":        []string{"set", "merge", "reset"},
+				"description": "set replaces the page's extra headers, merge adds to them (an empty value removes one), reset removes them all",
+				"default":     "set",
+			},
+			"headers": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Header names and values, e.g. {\"Authorization\": \"Bearer ...\", \"X-Api-Key\": \"...\"}",
+				"additionalProperties": map[string]interface{}{"type": "string"},
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to add the headers to (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *SetExtraHeadersTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		// Headers often carry credentials; keep their values out of the log
+		logArgs := redactHeaderArgs(args)
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), logArgs, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		action, _ := args["action"].(string)
+		if action == "" {
+			action = "set"
+		}
+		if action != "set" && action != "merge" && action != "reset" {
+			return fail("action must be one of set, merge, reset")
+		}
+		headers := map[string]string{}
+		if action != "reset" {
+			raw, ok := args["headers"].(map[string]interface{})
+			if !ok || len(raw) == 0 {
+				return fail("headers is required: an object of header names and values; use action 'reset' to remove them")
+			}
+			for name, value := range raw {
+				s, ok := value.(string)
+				if !ok {
+					return fail(fmt.Sprintf("value of header %s must be a string", name))
+				}
+				headers[name] = s
+			}
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		if action == "merge" {
+			merged, err := mergeExtraHeaders(t.browserMgr.ExtraHeaders(pageID), headers)
+			if err != nil {
+				return fail(err.Error())
+			}
+			headers = merged
+		}
+		if err := t.browserMgr.SetExtraHeaders(pageID, headers); err != nil {
+			return fail(fmt.Sprintf("Failed to set extra headers: %v", err))
+		}
+
+		names := headerNames(t.browserMgr.ExtraHeaders(pageID))
+		text := fmt.Sprintf("Page %s sends no extra headers", pageID)
+		if len(names) > 0 {
+			text = fmt.Sprintf("Page %s now adds %d header(s) to every request: %s", pageID, len(names), strings.Join(names, ", "))
+		}
+
+		t.logger.LogToolExecution(t.Name(), logArgs, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{"page_id": pageID, "headers": names},
+			}},
+		}, nil
+	})
+}
+
+// mergeExtraHeaders adds updates to current, matching names without regard
+// to case; an empty value removes the header
+func mergeExtraHeaders(current, updates map[string]string) (map[string]string, error) {
+	canonical, err := browser.ValidateExtraHeaders(updates)
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]string, len(current)+len(canonical))
+	for name, value := range current {
+		merged[name] = value
+	}
+	for name, value := range canonical {
+		if value == "" {
+			delete(merged, name)
+		} else {
+			merged[name] = value
+		}
+	}
+	return merged, nil
+}
+
+// headerNames lists the names of headers, sorted
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// redactHeaderArgs copies args with every header value masked
+func redactHeaderArgs(args map[string]interface{}) map[string]interface{} {
+	raw, ok := args["headers"].(map[string]interface{})
+	if !ok {
+		return args
+	}
+	redacted := make(map[string]interface{}, len(args))
+	for key, value := range args {
+		redacted[key] = value
+	}
+	masked := make(map[string]interface{}, len(raw))
+	for name := range raw {
+		masked[name] = "[redacted]"
+	}
+	redacted["headers"] = masked
+	return redacted
+}
@@ -0,0 +1,61 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMergeExtraHeaders(t *testing.T) {
+	merged, err := mergeExtraHeaders(
+		map[string]string{"Authorization": "Bearer old", "X-Trace": "1"},
+		map[string]string{"authorization": "Bearer new", "x-trace": "", "X-Flag": "on"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged["Authorization"] != "Bearer new" || merged["X-Flag"] != "on" || len(merged) != 2 {
+		t.Errorf("mergeExtraHeaders = %v", merged)
+	}
+	if names := headerNames(merged); strings.Join(names, ",") != "Authorization,X-Flag" {
+		t.Errorf("headerNames = %v", names)
+	}
+	if _, err := mergeExtraHeaders(nil, map[string]string{"Host": "evil"}); err == nil {
+		t.Error("Expected Host to be rejected")
+	}
+}
+
+func TestRedactHeaderArgs(t *testing.T) {
+	args := map[string]interface{}{
+		"page_id": "page_1",
+		"headers": map[string]interface{}{"Authorization": "Bearer secret"},
+	}
+	redacted := redactHeaderArgs(args)
+	if redacted["headers"].(map[string]interface{})["Authorization"] != "[redacted]" || redacted["page_id"] != "page_1" {
+		t.Errorf("redactHeaderArgs = %v", redacted)
+	}
+	if args["headers"].(map[string]interface{})["Authorization"] != "Bearer secret" {
+		t.Error("redactHeaderArgs should not modify its input")
+	}
+}
+
+func TestRequestOverrideToolsValidateArgs(t *testing.T) {
+	headersTool := NewSetExtraHeadersTool(createTestLogger(t), nil)
+	resp, _ := headersTool.Execute(map[string]interface{}{})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "headers is required") {
+		t.Errorf("Expected missing headers error, got %+v", resp)
+	}
+	resp, _ = headersTool.Execute(map[string]interface{}{"headers": map[string]interface{}{"X-Count": float64(1)}})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "must be a string") {
+		t.Errorf("Expected non-string value error, got %+v", resp)
+	}
+
+	userAgentTool := NewSetUserAgentTool(createTestLogger(t), nil)
+	resp, _ = userAgentTool.Execute(map[string]interface{}{})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "user_agent is required") {
+		t.Errorf("Expected missing user_agent error, got %+v", resp)
+	}
+	resp, _ = userAgentTool.Execute(map[string]interface{}{"action": "spoof"})
+	if !resp.IsError {
+		t.Error("Expected error for unknown action")
+	}
+}
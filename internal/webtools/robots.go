@@ -0,0 +1,167 @@
+package webtools
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	robotsTimeout = 10 * time.Second
+	// maxRobotsSize is how much of a robots.txt is read, the limit RFC 9309
+	// requires crawlers to handle
+	maxRobotsSize = 500 << 10
+)
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	length  int // of the original pattern, for longest-match precedence
+	pattern *regexp.Regexp
+}
+
+// robotsRules are the rules a robots.txt sets for our crawler
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	// disallowAll is set when robots.txt could not be fetched for a
+	// server-side reason, which RFC 9309 treats as a full disallow
+	disallowAll bool
+}
+
+// robotsPattern compiles a robots.txt path pattern, where * matches any
+// run of characters and a trailing $ anchors the end
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(path), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// parseRobots reads the group for agent from a robots.txt, falling back to
+// the * group when no group names the agent
+func parseRobots(r io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+	var named, wildcard robotsRules
+	var current []*robotsRules
+	inAgents, namedSeen := false, false
+
+	scanner := bufio.NewScanner(io.LimitReader(r, maxRobotsSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			switch v := strings.ToLower(value); {
+			case v == "*":
+				current = append(current, &wildcard)
+			case v != "" && strings.Contains(agent, v):
+				current = append(current, &named)
+				namedSeen = true
+			}
+			continue
+		}
+		inAgents = false
+		for _, group := range current {
+			switch key {
+			case "allow", "disallow":
+				if value == "" {
+					// An empty Disallow allows everything
+					continue
+				}
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", length: len(value), pattern: robotsPattern(value)})
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					group.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+	if namedSeen {
+		return &named
+	}
+	return &wildcard
+}
+
+// allowed reports whether the path and query of u may be crawled. The
+// longest matching pattern wins, and Allow wins a tie.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	if r.disallowAll {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	best := robotsRule{allow: true, length: -1}
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > best.length || (rule.length == best.length && rule.allow) {
+			best = rule
+		}
+	}
+	return best.allow
+}
+
+// fetchRobots downloads and parses origin's robots.txt. A missing file
+// allows everything; a server error or unreachable host disallows
+// everything, as RFC 9309 asks.
+func fetchRobots(ctx context.Context, client *http.Client, origin, agent string) *robotsRules {
+	ctx, cancel := context.WithTimeout(ctx, robotsTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{disallowAll: true}
+	}
+	req.Header.Set("User-Agent", agent)
+	resp, err := client.Do(req)
+	if err != nil {
+		return &robotsRules{disallowAll: true}
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 500:
+		return &robotsRules{disallowAll: true}
+	case resp.StatusCode >= 400:
+		return &robotsRules{}
+	case resp.StatusCode >= 300:
+		// The client follows redirects, so this is a redirect loop
+		return &robotsRules{}
+	}
+	return parseRobots(resp.Body, agent)
+}
+
+// robotsOrigin is the scheme and host whose robots.txt covers u
+func robotsOrigin(u *url.URL) string {
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host)
+}
@@ -0,0 +1,104 @@
+package webtools
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func robotsAllowed(t *testing.T, rules *robotsRules, rawURL string) bool {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rules.allowed(u)
+}
+
+func TestParseRobotsGroups(t *testing.T) {
+	robots := `# comment
+User-agent: *
+Disallow: /private/
+Crawl-delay: 2
+
+User-agent: googlebot
+User-agent: rodmcp
+Disallow: /admin
+Allow: /admin/public
+Disallow: /*.pdf$
+Crawl-delay: 0.5
+`
+	rules := parseRobots(strings.NewReader(robots), "rodmcp")
+	cases := map[string]bool{
+		"https://example.com/":                 true,
+		"https://example.com/private/x":        true, // only the * group disallows it
+		"https://example.com/admin":            false,
+		"https://example.com/admin/settings":   false,
+		"https://example.com/admin/public/doc": true,
+		"https://example.com/docs/a.pdf":       false,
+		"https://example.com/docs/a.pdf?x=1":   true,
+		"https://example.com/robots.txt":       true,
+	}
+	for u, want := range cases {
+		if got := robotsAllowed(t, rules, u); got != want {
+			t.Errorf("allowed(%s) = %v, want %v", u, got, want)
+		}
+	}
+	if rules.crawlDelay != 500*time.Millisecond {
+		t.Errorf("crawl delay = %v, want 500ms", rules.crawlDelay)
+	}
+
+	other := parseRobots(strings.NewReader(robots), "otherbot")
+	if robotsAllowed(t, other, "https://example.com/private/x") {
+		t.Error("the * group should apply to agents without their own group")
+	}
+	if other.crawlDelay != 2*time.Second {
+		t.Errorf("crawl delay = %v, want 2s", other.crawlDelay)
+	}
+}
+
+func TestParseRobotsNamedGroupOverridesWildcard(t *testing.T) {
+	robots := "User-agent: *\nDisallow: /\n\nUser-agent: rodmcp\nDisallow:\n"
+	rules := parseRobots(strings.NewReader(robots), "rodmcp")
+	if !robotsAllowed(t, rules, "https://example.com/page") {
+		t.Error("an empty Disallow in our own group should allow everything")
+	}
+}
+
+func TestRobotsTieGoesToAllow(t *testing.T) {
+	rules := parseRobots(strings.NewReader("User-agent: *\nDisallow: /page\nAllow: /page\n"), "rodmcp")
+	if !robotsAllowed(t, rules, "https://example.com/page") {
+		t.Error("Allow should win a tie with an equally long Disallow")
+	}
+}
+
+func TestFetchRobotsStatus(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/robots.txt" {
+			t.Errorf("unexpected request for %s", r.URL.Path)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("User-agent: *\nDisallow: /secret\n"))
+	}))
+	defer server.Close()
+
+	fetch := func() *robotsRules {
+		return fetchRobots(context.Background(), server.Client(), server.URL, crawlUserAgent)
+	}
+	if robotsAllowed(t, fetch(), server.URL+"/secret") {
+		t.Error("disallowed path allowed")
+	}
+	status = http.StatusNotFound
+	if !robotsAllowed(t, fetch(), server.URL+"/secret") {
+		t.Error("a missing robots.txt should allow everything")
+	}
+	status = http.StatusServiceUnavailable
+	if robotsAllowed(t, fetch(), server.URL+"/anything") {
+		t.Error("a server error should disallow everything")
+	}
+}
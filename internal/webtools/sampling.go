@@ -0,0 +1,46 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"rodmcp/pkg/types"
+)
+
+// SampleFunc has the client's model generate a message (see
+// mcp.Server.Sample). It fails when the client cannot be asked.
+type SampleFunc func(req types.CreateMessageRequest) (*types.CreateMessageResult, error)
+
+// askModelJSON sends prompt to the client's model and decodes the JSON
+// object in its reply into out. Models often wrap JSON in prose or code
+// fences, so the outermost braces are taken.
+func askModelJSON(sample SampleFunc, system, prompt string, maxTokens int, out interface{}) error {
+	result, err := sample(types.CreateMessageRequest{
+		Messages: []types.SamplingMessage{{
+			Role:    "user",
+			Content: types.SamplingContent{Type: "text", Text: prompt},
+		}},
+		SystemPrompt: system,
+		MaxTokens:    maxTokens,
+		ModelPreferences: &types.ModelPreferences{
+			SpeedPriority:        0.5,
+			IntelligencePriority: 0.5,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if result.Content.Type != "text" {
+		return fmt.Errorf("model answered with %s content instead of text", result.Content.Type)
+	}
+	text := result.Content.Text
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start < 0 || end < start {
+		return fmt.Errorf("model answer holds no JSON object")
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), out); err != nil {
+		return fmt.Errorf("model answer is not valid JSON: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/pkg/types"
+)
+
+func TestAskModelJSON(t *testing.T) {
+	reply := func(text string) SampleFunc {
+		return func(req types.CreateMessageRequest) (*types.CreateMessageResult, error) {
+			if req.MaxTokens != 100 || req.SystemPrompt != "system" || req.Messages[0].Content.Text != "prompt" {
+				t.Errorf("request = %+v", req)
+			}
+			return &types.CreateMessageResult{Role: "assistant", Content: types.SamplingContent{Type: "text", Text: text}}, nil
+		}
+	}
+
+	var out map[string]string
+	if err := askModelJSON(reply("Here you go:\n```json\n{\"price\": \".cost::text\"}\n```"), "system", "prompt", 100, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out["price"] != ".cost::text" {
+		t.Errorf("out = %v", out)
+	}
+	if err := askModelJSON(reply("I cannot help with that"), "system", "prompt", 100, &out); err == nil {
+		t.Error("Expected error for an answer without JSON")
+	}
+}
+
+func TestHealPrompt(t *testing.T) {
+	prompt := healPrompt(map[string]string{"title": "h1.name::text", "price": ".cost::text"}, "<main></main>")
+	if !strings.Contains(prompt, "- price: .cost::text\n- title: h1.name::text") || !strings.HasSuffix(prompt, "<main></main>") {
+		t.Errorf("prompt = %q", prompt)
+	}
+}
@@ -0,0 +1,539 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+
+	"go.uber.org/zap"
+)
+
+// defaultRecipeDir is where scrape recipes are kept when no --recipe-dir is
+// configured, relative to the working directory
+const defaultRecipeDir = "scrape-recipes"
+
+var recipeNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// scrapeRecipe is a named, reusable screen_scrape configuration
+type scrapeRecipe struct {
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description,omitempty"`
+	URLPatterns       []string               `json:"url_patterns,omitempty"`
+	Selectors         map[string]interface{} `json:"selectors"`
+	ExtractType       string                 `json:"extract_type,omitempty"`
+	ContainerSelector string                 `json:"container_selector,omitempty"`
+	WaitFor           string                 `json:"wait_for,omitempty"`
+	UpdatedAt         time.Time              `json:"updated_at"`
+}
+
+// matches reports whether url fits one of the recipe's URL patterns
+func (r *scrapeRecipe) matches(url string) bool {
+	for _, pattern := range r.URLPatterns {
+		if matchURLPattern(pattern, url) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchURLPattern matches a URL against a pattern where * stands for any run
+// of characters, e.g. https://shop.example.com/products/*
+func matchURLPattern(pattern, url string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, err := regexp.MatchString(expr, url)
+	return err == nil && matched
+}
+
+// recipeStore keeps recipes as one JSON file each in a directory
+type recipeStore struct {
+	dir string
+}
+
+func newRecipeStore(dir string) *recipeStore {
+	if dir == "" {
+		dir = defaultRecipeDir
+	}
+	return &recipeStore{dir: dir}
+}
+
+func (s *recipeStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// save writes a recipe, replacing any with the same name. The file is
+// written under a temporary name and renamed so a crash never leaves half a
+// recipe behind.
+func (s *recipeStore) save(recipe *scrapeRecipe) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create recipe directory: %w", err)
+	}
+	data, err := json.MarshalIndent(recipe, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := s.path(recipe.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// load reads the named recipe
+func (s *recipeStore) load(name string) (*scrapeRecipe, error) {
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid recipe name %q", name)
+	}
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no scrape recipe named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var recipe scrapeRecipe
+	if err := json.Unmarshal(data, &recipe); err != nil {
+		return nil, fmt.Errorf("recipe %q is corrupt: %w", name, err)
+	}
+	return &recipe, nil
+}
+
+// list returns every readable recipe, sorted by name
+func (s *recipeStore) list() ([]*scrapeRecipe, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var recipes []*scrapeRecipe
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		if recipe, err := s.load(name); err == nil {
+			recipes = append(recipes, recipe)
+		}
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].Name < recipes[j].Name })
+	return recipes, nil
+}
+
+// findForURL returns the recipe whose URL patterns match url. More specific
+// patterns (longer, with fewer wildcards) win when several recipes match.
+func (s *recipeStore) findForURL(url string) (*scrapeRecipe, error) {
+	recipes, err := s.list()
+	if err != nil {
+		return nil, err
+	}
+	var best *scrapeRecipe
+	bestScore := -1
+	for _, recipe := range recipes {
+		for _, pattern := range recipe.URLPatterns {
+			if !matchURLPattern(pattern, url) {
+				continue
+			}
+			score := len(pattern) - 10*strings.Count(pattern, "*")
+			if score > bestScore {
+				best, bestScore = recipe, score
+			}
+		}
+	}
+	return best, nil
+}
+
+func recipeErrorResponse(message string) *types.CallToolResponse {
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: message,
+		}},
+		IsError: true,
+	}
+}
+
+// SaveScrapeRecipeTool stores a named screen_scrape configuration on disk
+type SaveScrapeRecipeTool struct {
+	logger *logger.Logger
+	store  *recipeStore
+}
+
+// NewSaveScrapeRecipeTool creates a save_scrape_recipe tool that keeps
+// recipes in dir (default: scrape-recipes under the working directory)
+func NewSaveScrapeRecipeTool(log *logger.Logger, dir string) *SaveScrapeRecipeTool {
+	return &SaveScrapeRecipeTool{logger: log, store: newRecipeStore(dir)}
+}
+
+func (t *SaveScrapeRecipeTool) Name() string {
+	return "save_scrape_recipe"
+}
+
+func (t *SaveScrapeRecipeTool) Description() string {
+	return "Save a named screen_scrape configuration (selectors, extraction mode and the URL patterns it applies to) so it can be rerun later with run_scrape_recipe"
+}
+
+func (t *SaveScrapeRecipeTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Recipe name (letters, digits, '.', '_' and '-'). Saving an existing name replaces it",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "What the recipe extracts, for whoever reuses it",
+			},
+			"url_patterns": map[string]interface{}{
+				"type":        "array",
+				"description": "URLs the recipe applies to, with * as a wildcard, e.g. 'https://shop.example.com/products/*'. run_scrape_recipe uses these to pick a recipe when none is named",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"description":          "Selector schema in screen_scrape format, including directives, nested schemas, XPath and :has-text()",
+				"additionalProperties": scrapeSelectorValueSchema(),
+			},
+			"extract_type": map[string]interface{}{
+				"type":        "string",
+				"description": "'single' or 'multiple' as in screen_scrape (default: single)",
+				"enum":        []string{"single", "multiple"},
+			},
+			"container_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Container selector for multiple extraction",
+			},
+			"wait_for": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to wait for before scraping",
+			},
+		},
+		Required: []string{"name", "selectors"},
+	}
+}
+
+func (t *SaveScrapeRecipeTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		name, _ := args["name"].(string)
+		if !recipeNamePattern.MatchString(name) {
+			return fail("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+		}
+
+		selectors, ok := args["selectors"].(map[string]interface{})
+		if !ok || len(selectors) == 0 {
+			return fail("selectors must be provided as key-value pairs")
+		}
+		if _, err := parseScrapeFields(selectors); err != nil {
+			return fail(err.Error())
+		}
+
+		recipe := &scrapeRecipe{
+			Name:      name,
+			Selectors: selectors,
+			UpdatedAt: time.Now().UTC(),
+		}
+		recipe.Description, _ = args["description"].(string)
+		recipe.ContainerSelector, _ = args["container_selector"].(string)
+		recipe.WaitFor, _ = args["wait_for"].(string)
+		recipe.ExtractType, _ = args["extract_type"].(string)
+		switch recipe.ExtractType {
+		case "", "single":
+		case "multiple":
+			if recipe.ContainerSelector == "" {
+				return fail("container_selector is required for multiple extraction")
+			}
+		default:
+			return fail("extract_type must be 'single' or 'multiple'")
+		}
+
+		if raw, ok := args["url_patterns"].([]interface{}); ok {
+			for i, item := range raw {
+				pattern, ok := item.(string)
+				if !ok || strings.TrimSpace(pattern) == "" {
+					return fail(fmt.Sprintf("url_patterns[%d] must be a non-empty string", i))
+				}
+				recipe.URLPatterns = append(recipe.URLPatterns, strings.TrimSpace(pattern))
+			}
+		}
+
+		path, err := t.store.save(recipe)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to save recipe: %v", err))
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Saved scrape recipe %s with %d fields", name, len(selectors)),
+				Data: map[string]interface{}{
+					"name":         name,
+					"path":         path,
+					"fields":       len(selectors),
+					"url_patterns": recipe.URLPatterns,
+				},
+			}},
+		}, nil
+	})
+}
+
+// RunScrapeRecipeTool runs a saved recipe through screen_scrape
+type RunScrapeRecipeTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	scraper    *ScreenScrapeTool
+	store      *recipeStore
+
+	// sample asks the client's model to repair selectors that no longer
+	// match; nil when the client cannot be asked
+	sample SampleFunc
+}
+
+// NewRunScrapeRecipeTool creates a run_scrape_recipe tool reading recipes
+// from dir (default: scrape-recipes under the working directory)
+func NewRunScrapeRecipeTool(log *logger.Logger, mgr *browser.Manager, dir string) *RunScrapeRecipeTool {
+	return &RunScrapeRecipeTool{
+		logger:     log,
+		browserMgr: mgr,
+		scraper:    NewScreenScrapeTool(log, mgr),
+		store:      newRecipeStore(dir),
+	}
+}
+
+// SetSampler lets the tool ask the client's model for replacement selectors
+// when a recipe's fields come back empty. Healed selectors are used for the
+// run and reported, but the saved recipe is left as it is.
+func (t *RunScrapeRecipeTool) SetSampler(fn SampleFunc) {
+	t.sample = fn
+}
+
+func (t *RunScrapeRecipeTool) Name() string {
+	return "run_scrape_recipe"
+}
+
+func (t *RunScrapeRecipeTool) Description() string {
+	return "Scrape a page with a saved recipe. Name the recipe, or leave it out to use the one whose URL patterns match the page"
+}
+
+func (t *RunScrapeRecipeTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Recipe to run (optional; chosen by URL pattern when omitted)",
+			},
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "URL to open and scrape (optional if page_id provided)",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Existing page to scrape (optional if url provided)",
+			},
+			"include_metadata": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Include page metadata in results (default: true)",
+				"default":     true,
+			},
+			"keep_page": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Keep the page opened for 'url' so it can be reused via page_id (default: false)",
+				"default":     false,
+			},
+		},
+	}
+}
+
+func (t *RunScrapeRecipeTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		url, _ := args["url"].(string)
+		pageID, _ := args["page_id"].(string)
+		if url == "" && pageID == "" {
+			return fail("either page_id or url must be provided")
+		}
+
+		// The page's own URL decides the recipe when only page_id is given
+		target := url
+		if target == "" {
+			if info, err := t.browserMgr.GetPageInfo(pageID); err == nil {
+				target, _ = info["url"].(string)
+			}
+		}
+
+		var recipe *scrapeRecipe
+		var err error
+		if name, _ := args["name"].(string); name != "" {
+			recipe, err = t.store.load(name)
+		} else {
+			recipe, err = t.store.findForURL(target)
+			if err == nil && recipe == nil {
+				return fail(t.noMatchMessage(target))
+			}
+		}
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		scrapeArgs := map[string]interface{}{
+			"selectors": recipe.Selectors,
+		}
+		for _, key := range []string{"url", "page_id", "include_metadata", "keep_page"} {
+			if val, ok := args[key]; ok {
+				scrapeArgs[key] = val
+			}
+		}
+		if recipe.ExtractType != "" {
+			scrapeArgs["extract_type"] = recipe.ExtractType
+		}
+		if recipe.ContainerSelector != "" {
+			scrapeArgs["container_selector"] = recipe.ContainerSelector
+		}
+		if recipe.WaitFor != "" {
+			scrapeArgs["wait_for"] = recipe.WaitFor
+		}
+
+		// Healing needs the page after the scrape
+		heal := t.sample != nil && recipe.ExtractType != "multiple"
+		closePage := false
+		if keep, _ := args["keep_page"].(bool); heal && pageID == "" && !keep {
+			scrapeArgs["keep_page"] = true
+			closePage = true
+		}
+
+		resp, err := t.scraper.Execute(scrapeArgs)
+		if heal && err == nil && resp != nil && !resp.IsError && len(resp.Content) > 0 {
+			t.healMissingFields(recipe, resp.Content[0].Data, closePage)
+		}
+		t.logger.LogToolExecution(t.Name(), args, err == nil && resp != nil && !resp.IsError, time.Since(start).Milliseconds())
+		if err != nil {
+			return fail(fmt.Sprintf("Recipe %s failed: %v", recipe.Name, err))
+		}
+
+		if len(resp.Content) > 0 {
+			resp.Content[0].Text = fmt.Sprintf("Recipe %s: %s", recipe.Name, resp.Content[0].Text)
+			if data, ok := resp.Content[0].Data.(map[string]interface{}); ok {
+				data["recipe"] = recipe.Name
+				if target != "" && len(recipe.URLPatterns) > 0 {
+					data["url_matched"] = recipe.matches(target)
+				}
+				if healed, ok := data["healed_selectors"].(map[string]string); ok {
+					resp.Content[0].Text += fmt.Sprintf(". Healed %d selectors that no longer matched; save them with save_scrape_recipe to keep them", len(healed))
+				}
+			}
+		}
+		return resp, nil
+	})
+}
+
+// healMissingFields asks the client's model for new selectors for the
+// recipe's plain selector fields that came back empty, and fills them in
+// from the page when the new selectors work. closePage closes the page
+// afterwards, for runs that only kept it open to heal.
+func (t *RunScrapeRecipeTool) healMissingFields(recipe *scrapeRecipe, data interface{}, closePage bool) {
+	result, ok := data.(map[string]interface{})
+	if !ok {
+		return
+	}
+	pageID, _ := result["page_id"].(string)
+	if pageID == "" {
+		return
+	}
+	if closePage {
+		defer func() {
+			if err := t.browserMgr.ClosePage(pageID); err != nil {
+				t.logger.WithComponent("tools").Debug("Failed to close scrape page", zap.Error(err))
+			}
+			delete(result, "page_id")
+		}()
+	}
+
+	item, _ := result["data"].(map[string]interface{})
+	broken := make(map[string]string)
+	for name, spec := range recipe.Selectors {
+		if selector, ok := spec.(string); ok && item != nil && item[name] == nil {
+			broken[name] = selector
+		}
+	}
+	if len(broken) == 0 {
+		return
+	}
+
+	healer := &selectorHealer{browserMgr: t.browserMgr, sample: t.sample}
+	healed, err := healer.heal(pageID, broken)
+	if err != nil {
+		t.logger.WithComponent("tools").Info("Could not heal recipe selectors",
+			zap.String("recipe", recipe.Name),
+			zap.Error(err))
+		result["heal_error"] = err.Error()
+		return
+	}
+	if len(healed) == 0 {
+		return
+	}
+
+	selectors := make(map[string]interface{}, len(healed))
+	for name, selector := range healed {
+		selectors[name] = selector
+	}
+	values, err := t.scraper.scrapeSingle(pageID, nil, selectors)
+	if err != nil {
+		result["heal_error"] = err.Error()
+		return
+	}
+	for name := range healed {
+		if values[name] == nil {
+			delete(healed, name)
+			continue
+		}
+		item[name] = values[name]
+	}
+	if len(healed) > 0 {
+		result["healed_selectors"] = healed
+		t.logger.WithComponent("tools").Info("Healed recipe selectors",
+			zap.String("recipe", recipe.Name),
+			zap.Any("selectors", healed))
+	}
+}
+
+// noMatchMessage explains that no recipe covers url and lists what exists
+func (t *RunScrapeRecipeTool) noMatchMessage(url string) string {
+	recipes, _ := t.store.list()
+	if len(recipes) == 0 {
+		return "No scrape recipes saved yet; create one with save_scrape_recipe"
+	}
+	var names []string
+	for _, r := range recipes {
+		names = append(names, fmt.Sprintf("%s (%s)", r.Name, strings.Join(r.URLPatterns, ", ")))
+	}
+	return fmt.Sprintf("No recipe's URL patterns match %q. Name one explicitly: %s", url, strings.Join(names, "; "))
+}
@@ -0,0 +1,129 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchURLPattern(t *testing.T) {
+	cases := []struct {
+		pattern string
+		url     string
+		want    bool
+	}{
+		{"https://shop.example.com/products/*", "https://shop.example.com/products/42", true},
+		{"https://shop.example.com/products/*", "https://shop.example.com/cart", false},
+		{"https://*.example.com/*", "https://news.example.com/a/b?c=d", true},
+		{"https://example.com/page.html", "https://exampleXcom/page.html", false},
+		{"https://example.com/", "https://example.com/", true},
+	}
+	for _, tc := range cases {
+		if got := matchURLPattern(tc.pattern, tc.url); got != tc.want {
+			t.Errorf("matchURLPattern(%q, %q) = %v, want %v", tc.pattern, tc.url, got, tc.want)
+		}
+	}
+}
+
+func TestRecipeStore(t *testing.T) {
+	store := newRecipeStore(t.TempDir())
+
+	generic := &scrapeRecipe{
+		Name:        "generic",
+		URLPatterns: []string{"https://shop.example.com/*"},
+		Selectors:   map[string]interface{}{"title": "h1"},
+	}
+	product := &scrapeRecipe{
+		Name:        "product",
+		URLPatterns: []string{"https://shop.example.com/products/*"},
+		Selectors:   map[string]interface{}{"price": ".price | regex:([0-9.]+)"},
+	}
+	for _, r := range []*scrapeRecipe{generic, product} {
+		if _, err := store.save(r); err != nil {
+			t.Fatalf("save %s: %v", r.Name, err)
+		}
+	}
+
+	loaded, err := store.load("product")
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if loaded.Selectors["price"] != ".price | regex:([0-9.]+)" {
+		t.Errorf("Selectors did not round-trip: %v", loaded.Selectors)
+	}
+
+	recipes, err := store.list()
+	if err != nil || len(recipes) != 2 || recipes[0].Name != "generic" {
+		t.Fatalf("list = %v, %v", recipes, err)
+	}
+
+	// The more specific pattern wins
+	found, _ := store.findForURL("https://shop.example.com/products/7")
+	if found == nil || found.Name != "product" {
+		t.Errorf("findForURL picked %v, want product", found)
+	}
+	found, _ = store.findForURL("https://other.example.com/")
+	if found != nil {
+		t.Errorf("findForURL picked %s for an unmatched URL", found.Name)
+	}
+
+	if _, err := store.load("missing"); err == nil {
+		t.Error("Expected error loading a missing recipe")
+	}
+	if _, err := store.load("../escape"); err == nil {
+		t.Error("Expected error for a path-like recipe name")
+	}
+}
+
+func TestSaveScrapeRecipeTool(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewSaveScrapeRecipeTool(createTestLogger(t), dir)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"name":               "articles",
+		"url_patterns":       []interface{}{"https://blog.example.com/*"},
+		"selectors":          map[string]interface{}{"title": "h2", "link": "a@href"},
+		"extract_type":       "multiple",
+		"container_selector": "article",
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("Save failed: %v %+v", err, resp)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "articles.json")); err != nil {
+		t.Errorf("Recipe file not written: %v", err)
+	}
+
+	invalid := []map[string]interface{}{
+		{"name": "../x", "selectors": map[string]interface{}{"a": "h1"}},
+		{"name": "ok"},
+		{"name": "ok", "selectors": map[string]interface{}{"a": "h1 | bogus"}},
+		{"name": "ok", "selectors": map[string]interface{}{"a": "h1"}, "extract_type": "multiple"},
+		{"name": "ok", "selectors": map[string]interface{}{"a": "h1"}, "url_patterns": []interface{}{""}},
+	}
+	for i, args := range invalid {
+		resp, err := tool.Execute(args)
+		if err != nil || !resp.IsError {
+			t.Errorf("case %d: expected an error response, got %+v, %v", i, resp, err)
+		}
+	}
+}
+
+func TestRunScrapeRecipeToolValidation(t *testing.T) {
+	tool := NewRunScrapeRecipeTool(createTestLogger(t), nil, t.TempDir())
+
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil || !resp.IsError {
+		t.Fatalf("Expected error without url or page_id, got %+v, %v", resp, err)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"url": "https://example.com/", "name": "missing"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "missing") {
+		t.Errorf("Expected unknown recipe error, got %+v", resp)
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{"url": "https://example.com/"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "save_scrape_recipe") {
+		t.Errorf("Expected no-recipes hint, got %+v", resp)
+	}
+}
@@ -0,0 +1,516 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"rodmcp/internal/browser"
+)
+
+// scrapeField is one entry of a screen_scrape selectors map. A plain CSS
+// selector keeps the per-tag heuristics; a selector with directives returns
+// exactly what they ask for:
+//
+//	a.link@href                     the href attribute
+//	img@src                         the image URL
+//	span.price | regex:([0-9.]+)    the text, narrowed by a pattern
+//	.summary@html                   the inner HTML
+//
+// A regex alternation must be wrapped in parentheses, regex:(USD|EUR), so
+// its '|' isn't read as the start of another filter.
+//
+// Fields can also be nested. An array holding one selector returns every
+// match, and an object describes a sub-schema scraped inside each element
+// its selector matches:
+//
+//	"tags":   [".tag@text"]
+//	"seller": {"selector": ".seller", "fields": {"name": ".name", "rating": "@data-rating"}}
+//	"offers": {"selector": ".offer", "multiple": true, "fields": {"price": ".price@text"}}
+//
+// Inside a nested schema a directive with no selector, such as "@data-rating",
+// reads the matched element itself.
+//
+// Selectors may also be XPath, written with an xpath= prefix or starting with
+// / or ./, and CSS selectors may use :has-text("...") (contains the text,
+// ignoring case) and :text-is("...") (exact text) for targets with no stable
+// class or id:
+//
+//	"price": "tr:has-text(\"Total\") td:last-child@text"
+//	"sku":   "xpath=//dt[normalize-space()='SKU']/following-sibling::dd[1]@text"
+//
+// XPath runs relative to the current container only when it starts with a dot.
+type scrapeField struct {
+	Name     string         `json:"name"`
+	Selector string         `json:"selector"`
+	Attr     string         `json:"attr,omitempty"` // attribute, or text/html; empty uses the heuristics
+	Filters  []scrapeFilter `json:"-"`
+	Multiple bool           `json:"multiple,omitempty"` // return every match as an array
+	Fields   []scrapeField  `json:"fields,omitempty"`   // sub-schema scraped inside each match
+}
+
+// maxScrapeDepth bounds how deeply selector schemas can nest
+const maxScrapeDepth = 5
+
+// scrapeSelectorValueSchema describes the values allowed in a selectors map
+func scrapeSelectorValueSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{
+				"type":     "array",
+				"minItems": 1,
+				"maxItems": 1,
+			},
+			map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"selector": map[string]interface{}{"type": "string"},
+					"multiple": map[string]interface{}{"type": "boolean"},
+					"fields":   map[string]interface{}{"type": "object"},
+				},
+			},
+		},
+	}
+}
+
+// scrapeFilter post-processes an extracted value
+type scrapeFilter struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+var scrapeAttrPattern = regexp.MustCompile(`^[A-Za-z_:][-A-Za-z0-9_:.]*$`)
+
+// parseScrapeField parses a selector with optional @attribute and | filter
+// directives. '@' and '|' inside brackets, quotes or parentheses belong to
+// the selector, so a[href$="@example.com"] and [lang|="en"] are left alone.
+func parseScrapeField(name, spec string) (scrapeField, error) {
+	parts := splitOutside(spec, '|')
+	field := scrapeField{Name: name}
+
+	xpath := isXPathSelector(strings.TrimSpace(spec))
+	if xpath {
+		parts = rejoinXPathUnions(parts)
+	}
+
+	selector := strings.TrimSpace(parts[0])
+	if at := lastIndexOutside(selector, '@'); at >= 0 && !(xpath && xpathAttributeStep(selector, at)) {
+		attr := strings.TrimSpace(selector[at+1:])
+		if !scrapeAttrPattern.MatchString(attr) {
+			return field, fmt.Errorf("field %s: invalid attribute %q after @", name, attr)
+		}
+		field.Attr = attr
+		selector = strings.TrimSpace(selector[:at])
+	}
+	if selector == "" && field.Attr == "" {
+		return field, fmt.Errorf("field %s: selector is empty", name)
+	}
+	field.Selector = selector
+
+	for _, raw := range parts[1:] {
+		filter, err := parseScrapeFilter(strings.TrimSpace(raw))
+		if err != nil {
+			return field, fmt.Errorf("field %s: %w", name, err)
+		}
+		field.Filters = append(field.Filters, filter)
+	}
+	if field.Attr == "" && len(field.Filters) > 0 {
+		field.Attr = "text"
+	}
+	return field, nil
+}
+
+// isXPathSelector reports whether a selector is XPath rather than CSS
+func isXPathSelector(selector string) bool {
+	trimmed := strings.TrimLeft(selector, "(")
+	return strings.HasPrefix(selector, "xpath=") || strings.HasPrefix(trimmed, "/") || strings.HasPrefix(trimmed, "./")
+}
+
+// xpathAttributeStep reports whether the '@' at index at is XPath's own
+// attribute axis, as in //a/@href, rather than an attribute directive
+func xpathAttributeStep(selector string, at int) bool {
+	before := strings.TrimRight(selector[:at], " ")
+	return before == "" || strings.HasSuffix(before, "/") || strings.HasSuffix(before, "::")
+}
+
+// rejoinXPathUnions puts back '|' union operators that splitOutside took for
+// filter separators; only parts that start like a filter are kept apart
+func rejoinXPathUnions(parts []string) []string {
+	out := []string{parts[0]}
+	for _, part := range parts[1:] {
+		name, _, _ := strings.Cut(strings.TrimSpace(part), ":")
+		if name == "regex" || name == "trim" {
+			out = append(out, part)
+			continue
+		}
+		out[len(out)-1] += "|" + part
+	}
+	return out
+}
+
+// parseScrapeFilter parses one "| name:argument" directive
+func parseScrapeFilter(raw string) (scrapeFilter, error) {
+	name, arg, _ := strings.Cut(raw, ":")
+	name = strings.TrimSpace(name)
+	switch name {
+	case "regex":
+		pattern, err := regexp.Compile(arg)
+		if err != nil {
+			return scrapeFilter{}, fmt.Errorf("invalid regex %q: %w", arg, err)
+		}
+		return scrapeFilter{name: name, pattern: pattern}, nil
+	case "trim":
+		return scrapeFilter{name: name}, nil
+	default:
+		return scrapeFilter{}, fmt.Errorf("unknown filter %q (supported: regex:PATTERN, trim)", name)
+	}
+}
+
+// apply runs the filter on a value; non-string values pass through
+func (f scrapeFilter) apply(value interface{}) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+	switch f.name {
+	case "regex":
+		m := f.pattern.FindStringSubmatch(s)
+		if m == nil {
+			return nil
+		}
+		if len(m) > 1 {
+			return m[1]
+		}
+		return m[0]
+	case "trim":
+		return strings.TrimSpace(s)
+	}
+	return value
+}
+
+// splitOutside splits s on sep where it isn't inside quotes, brackets or
+// parentheses
+func splitOutside(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	var quote byte
+	last := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == sep && depth == 0:
+			parts = append(parts, s[last:i])
+			last = i + 1
+		}
+	}
+	return append(parts, s[last:])
+}
+
+// lastIndexOutside returns the index of the last sep in s that isn't inside
+// quotes, brackets or parentheses, or -1
+func lastIndexOutside(s string, sep byte) int {
+	parts := splitOutside(s, sep)
+	if len(parts) == 1 {
+		return -1
+	}
+	return len(s) - len(parts[len(parts)-1]) - 1
+}
+
+// parseScrapeFields parses a selectors map into fields in a stable order
+func parseScrapeFields(selectors map[string]interface{}) ([]scrapeField, error) {
+	return parseScrapeSchema(selectors, "", 1)
+}
+
+// parseScrapeSchema parses one level of a selectors map. prefix names the
+// enclosing fields in error messages.
+func parseScrapeSchema(selectors map[string]interface{}, prefix string, depth int) ([]scrapeField, error) {
+	if depth > maxScrapeDepth {
+		return nil, fmt.Errorf("field %s: schemas can nest at most %d levels", strings.TrimSuffix(prefix, "."), maxScrapeDepth)
+	}
+
+	names := make([]string, 0, len(selectors))
+	for name := range selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := make([]scrapeField, 0, len(names))
+	for _, name := range names {
+		field, err := parseScrapeEntry(name, prefix+name, selectors[name], depth)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// parseScrapeEntry parses one selectors value: a selector string, a
+// one-element array for all matches, or a nested schema object
+func parseScrapeEntry(name, path string, spec interface{}, depth int) (scrapeField, error) {
+	switch v := spec.(type) {
+	case string:
+		field, err := parseScrapeField(path, v)
+		field.Name = name
+		return field, err
+
+	case []interface{}:
+		if len(v) != 1 {
+			return scrapeField{}, fmt.Errorf("field %s: an array must hold exactly one selector or schema", path)
+		}
+		field, err := parseScrapeEntry(name, path, v[0], depth)
+		field.Multiple = true
+		return field, err
+
+	case map[string]interface{}:
+		selector, _ := v["selector"].(string)
+		multiple, _ := v["multiple"].(bool)
+		rawFields, hasFields := v["fields"]
+		if !hasFields {
+			field, err := parseScrapeField(path, selector)
+			field.Name = name
+			field.Multiple = multiple
+			return field, err
+		}
+
+		subSchema, ok := rawFields.(map[string]interface{})
+		if !ok || len(subSchema) == 0 {
+			return scrapeField{}, fmt.Errorf("field %s: fields must be a non-empty object", path)
+		}
+		if strings.TrimSpace(selector) == "" {
+			return scrapeField{}, fmt.Errorf("field %s: a nested schema needs a selector", path)
+		}
+		sub, err := parseScrapeSchema(subSchema, path+".", depth+1)
+		if err != nil {
+			return scrapeField{}, err
+		}
+		return scrapeField{Name: name, Selector: strings.TrimSpace(selector), Multiple: multiple, Fields: sub}, nil
+
+	default:
+		return scrapeField{}, fmt.Errorf("field %s: selector must be a string, array or object", path)
+	}
+}
+
+// scrapeScript extracts fields from the page, or from every container when
+// a container selector is given
+const scrapeScript = `
+	const containerSelector = %s;
+	const fields = %s;
+
+	const heuristicValue = element => {
+		const tagName = element.tagName.toLowerCase();
+		let value = null;
+		if (tagName === 'img') {
+			value = {
+				src: element.src || element.getAttribute('src'),
+				alt: element.alt || element.getAttribute('alt'),
+				title: element.title || element.getAttribute('title')
+			};
+		} else if (tagName === 'a') {
+			value = {
+				href: element.href || element.getAttribute('href'),
+				text: element.textContent || element.innerText,
+				title: element.title || element.getAttribute('title')
+			};
+		} else if (tagName === 'input') {
+			value = {
+				type: element.type,
+				value: element.value,
+				placeholder: element.placeholder
+			};
+		} else if (element.hasAttribute('data-value')) {
+			value = element.getAttribute('data-value');
+		} else {
+			value = element.textContent || element.innerText || '';
+		}
+		return {
+			value: value,
+			attributes: {
+				class: element.className,
+				id: element.id,
+				tagName: tagName
+			}
+		};
+	};
+
+	const directedValue = (element, attr) => {
+		if (attr === 'text') {
+			return (element.textContent || '').replace(/\s+/g, ' ').trim();
+		}
+		if (attr === 'html') {
+			return element.innerHTML;
+		}
+		// href and src properties resolve relative URLs
+		if ((attr === 'href' || attr === 'src') && typeof element[attr] === 'string' && element[attr]) {
+			return element[attr];
+		}
+		if (attr === 'value' && 'value' in element) {
+			return element.value;
+		}
+		return element.getAttribute(attr);
+	};
+
+	const normalize = text => (text || '').replace(/\s+/g, ' ').trim();
+
+	// :has-text() and :text-is() aren't CSS, so matching elements are tagged
+	// with a temporary attribute the pseudo-class is rewritten to
+	const markers = [];
+	const markerFor = new Map();
+	const textPseudo = /:(has-text|text-is)\(\s*(?:"((?:[^"\\]|\\.)*)"|'((?:[^'\\]|\\.)*)'|([^)]*?))\s*\)/g;
+	const expandTextPseudos = selector => selector.replace(textPseudo, (all, kind, dq, sq, bare) => {
+		const text = normalize((dq ?? sq ?? bare ?? '').replace(/\\(.)/g, '$1'));
+		const key = kind + '\u0000' + text;
+		if (!markerFor.has(key)) {
+			const attr = 'data-rodmcp-text-' + markerFor.size;
+			const needle = text.toLowerCase();
+			document.querySelectorAll('*').forEach(el => {
+				const content = normalize(el.textContent);
+				const hit = kind === 'text-is' ? content === text : content.toLowerCase().includes(needle);
+				if (hit) {
+					el.setAttribute(attr, '');
+					markers.push([el, attr]);
+				}
+			});
+			markerFor.set(key, attr);
+		}
+		return '[' + markerFor.get(key) + ']';
+	});
+
+	const isXPath = selector => selector.startsWith('xpath=') || /^\(*\.?\//.test(selector);
+
+	// An empty selector means the element being scraped itself
+	const matchAll = (root, selector) => {
+		if (selector === '') {
+			return root.nodeType === Node.ELEMENT_NODE ? [root] : [];
+		}
+		try {
+			if (isXPath(selector)) {
+				const expression = selector.startsWith('xpath=') ? selector.slice(6) : selector;
+				const snapshot = document.evaluate(expression, root, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null);
+				const nodes = [];
+				for (let i = 0; i < snapshot.snapshotLength; i++) {
+					nodes.push(snapshot.snapshotItem(i));
+				}
+				return nodes;
+			}
+			return Array.from(root.querySelectorAll(expandTextPseudos(selector)));
+		} catch (e) {
+			// an invalid selector fails only its own field
+			return [];
+		}
+	};
+
+	const valueOf = (element, field) => {
+		// XPath can select attribute and text nodes, which only have a value
+		if (element.nodeType !== Node.ELEMENT_NODE) {
+			return field.fields ? null : normalize(element.nodeValue);
+		}
+		if (field.fields) {
+			return extract(element, field.fields);
+		}
+		return field.attr ? directedValue(element, field.attr) : heuristicValue(element);
+	};
+
+	const extract = (root, schema) => {
+		const item = {};
+		schema.forEach(field => {
+			const matches = matchAll(root, field.selector);
+			if (field.multiple) {
+				item[field.name] = matches.map(element => valueOf(element, field));
+			} else {
+				item[field.name] = matches.length > 0 ? valueOf(matches[0], field) : null;
+			}
+		});
+		return item;
+	};
+
+	try {
+		if (containerSelector === null) {
+			return extract(document, fields);
+		}
+		return matchAll(document, containerSelector).map((container, index) => {
+			const item = extract(container, fields);
+			item._index = index;
+			return item;
+		});
+	} finally {
+		markers.forEach(([el, attr]) => el.removeAttribute(attr));
+	}
+`
+
+// runScrape extracts fields from the page, or the iframe at frames, once
+// for the whole document or once per container, and applies each field's
+// filters
+func (t *ScreenScrapeTool) runScrape(pageID string, frames browser.FramePath, containerSelector *string, fields []scrapeField) (interface{}, error) {
+	containerJSON, _ := json.Marshal(containerSelector)
+	fieldsJSON, _ := json.Marshal(fields)
+	data, err := t.browserMgr.ExecuteScriptInFrame(pageID, frames, fmt.Sprintf(scrapeScript, containerJSON, fieldsJSON))
+	if err != nil {
+		return nil, err
+	}
+
+	if containerSelector == nil {
+		var item map[string]interface{}
+		if err := decodeScriptValue(data, &item); err != nil {
+			return nil, fmt.Errorf("unexpected data format returned from scraping script: %w", err)
+		}
+		applyScrapeFilters(item, fields)
+		return item, nil
+	}
+
+	var items []map[string]interface{}
+	if err := decodeScriptValue(data, &items); err != nil {
+		return nil, fmt.Errorf("unexpected data format returned from scraping script: %w", err)
+	}
+	for _, item := range items {
+		applyScrapeFilters(item, fields)
+	}
+	return items, nil
+}
+
+// applyScrapeFilters runs each field's filters over its extracted values,
+// descending into nested schemas
+func applyScrapeFilters(item map[string]interface{}, fields []scrapeField) {
+	for _, field := range fields {
+		value, ok := item[field.Name]
+		if !ok || value == nil {
+			continue
+		}
+		if list, ok := value.([]interface{}); ok && field.Multiple {
+			for i, v := range list {
+				list[i] = field.filterValue(v)
+			}
+			continue
+		}
+		item[field.Name] = field.filterValue(value)
+	}
+}
+
+// filterValue applies the field's filters, or its sub-schema's, to one match
+func (f scrapeField) filterValue(value interface{}) interface{} {
+	if len(f.Fields) > 0 {
+		if obj, ok := value.(map[string]interface{}); ok {
+			applyScrapeFilters(obj, f.Fields)
+		}
+		return value
+	}
+	for _, filter := range f.Filters {
+		value = filter.apply(value)
+	}
+	return value
+}
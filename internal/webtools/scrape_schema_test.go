@@ -0,0 +1,168 @@
+package webtools
+
+import "testing"
+
+func TestParseScrapeField(t *testing.T) {
+	cases := []struct {
+		spec     string
+		selector string
+		attr     string
+		filters  int
+	}{
+		{"h1", "h1", "", 0},
+		{"a.link@href", "a.link", "href", 0},
+		{"img @ src", "img", "src", 0},
+		{"span.price | regex:([0-9.]+)", "span.price", "text", 1},
+		{".summary@html | trim", ".summary", "html", 1},
+		{`a[href$="@example.com"]`, `a[href$="@example.com"]`, "", 0},
+		{`a[href$="@example.com"]@href`, `a[href$="@example.com"]`, "href", 0},
+		{`[lang|="en"]`, `[lang|="en"]`, "", 0},
+		{`td:nth-child(2) | regex:\d+|x`, "td:nth-child(2)", "text", 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			field, err := parseScrapeField("f", tc.spec)
+			if tc.filters == 2 {
+				// "x" is not a known filter
+				if err == nil {
+					t.Fatal("Expected error for unknown filter")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if field.Selector != tc.selector || field.Attr != tc.attr || len(field.Filters) != tc.filters {
+				t.Errorf("got selector %q attr %q filters %d", field.Selector, field.Attr, len(field.Filters))
+			}
+		})
+	}
+}
+
+func TestParseScrapeFieldErrors(t *testing.T) {
+	for _, spec := range []string{"", "a@", "a@bad attr", "span | regex:(", "span | upper"} {
+		if _, err := parseScrapeField("f", spec); err == nil {
+			t.Errorf("Expected error for %q", spec)
+		}
+	}
+}
+
+func TestScrapeFilters(t *testing.T) {
+	field, err := parseScrapeField("price", "span.price | regex:([0-9.]+)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := map[string]interface{}{"price": "Now $19.99!"}
+	applyScrapeFilters(item, []scrapeField{field})
+	if item["price"] != "19.99" {
+		t.Errorf("price = %v, want 19.99", item["price"])
+	}
+
+	item = map[string]interface{}{"price": "Sold out"}
+	applyScrapeFilters(item, []scrapeField{field})
+	if item["price"] != nil {
+		t.Errorf("Expected nil when the pattern doesn't match, got %v", item["price"])
+	}
+
+	whole, _ := parseScrapeField("sku", "#sku | regex:[A-Z]{3}-\\d+")
+	item = map[string]interface{}{"sku": "SKU: ABC-123"}
+	applyScrapeFilters(item, []scrapeField{whole})
+	if item["sku"] != "ABC-123" {
+		t.Errorf("sku = %v, want whole match ABC-123", item["sku"])
+	}
+}
+
+func TestParseScrapeSchemaNested(t *testing.T) {
+	fields, err := parseScrapeFields(map[string]interface{}{
+		"title": "h1@text",
+		"tags":  []interface{}{".tag@text"},
+		"offers": map[string]interface{}{
+			"selector": ".offer",
+			"multiple": true,
+			"fields": map[string]interface{}{
+				"price":  ".price | regex:([0-9.]+)",
+				"seller": "@data-seller",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	byName := map[string]scrapeField{}
+	for _, f := range fields {
+		byName[f.Name] = f
+	}
+	if tags := byName["tags"]; !tags.Multiple || tags.Selector != ".tag" || tags.Attr != "text" {
+		t.Errorf("tags = %+v", tags)
+	}
+	offers := byName["offers"]
+	if !offers.Multiple || offers.Selector != ".offer" || len(offers.Fields) != 2 {
+		t.Fatalf("offers = %+v", offers)
+	}
+	if seller := offers.Fields[1]; seller.Name != "seller" || seller.Selector != "" || seller.Attr != "data-seller" {
+		t.Errorf("seller = %+v", seller)
+	}
+
+	item := map[string]interface{}{
+		"offers": []interface{}{
+			map[string]interface{}{"price": "$12.50 each", "seller": "acme"},
+			map[string]interface{}{"price": "call us", "seller": "bolt"},
+		},
+	}
+	applyScrapeFilters(item, fields)
+	list := item["offers"].([]interface{})
+	if list[0].(map[string]interface{})["price"] != "12.50" || list[1].(map[string]interface{})["price"] != nil {
+		t.Errorf("Nested filters not applied: %v", list)
+	}
+}
+
+func TestParseScrapeSchemaErrors(t *testing.T) {
+	deep := map[string]interface{}{"leaf": "span"}
+	for i := 0; i < maxScrapeDepth; i++ {
+		deep = map[string]interface{}{"level": map[string]interface{}{"selector": "div", "fields": deep}}
+	}
+
+	cases := map[string]map[string]interface{}{
+		"empty array":        {"tags": []interface{}{}},
+		"nested no selector": {"seller": map[string]interface{}{"fields": map[string]interface{}{"name": ".name"}}},
+		"nested bad field":   {"seller": map[string]interface{}{"selector": ".s", "fields": map[string]interface{}{"name": 3.0}}},
+		"too deep":           deep,
+	}
+	for name, selectors := range cases {
+		if _, err := parseScrapeFields(selectors); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestParseScrapeFieldXPath(t *testing.T) {
+	cases := []struct {
+		spec     string
+		selector string
+		attr     string
+		filters  int
+	}{
+		{"//h1", "//h1", "", 0},
+		{"//a[@class='next']@href", "//a[@class='next']", "href", 0},
+		{"//a/@href", "//a/@href", "", 0},
+		{"//a/attribute::href", "//a/attribute::href", "", 0},
+		{"xpath=//dt[.='SKU']/following-sibling::dd[1]@text", "xpath=//dt[.='SKU']/following-sibling::dd[1]", "text", 0},
+		{"//h1 | //h2", "//h1 | //h2", "", 0},
+		{"//span[@class='price'] | regex:([0-9.]+)", "//span[@class='price']", "text", 1},
+		{".//td[2]@text", ".//td[2]", "text", 0},
+		{`tr:has-text("Total") td:last-child@text`, `tr:has-text("Total") td:last-child`, "text", 0},
+		{`th:text-is('a|b')`, `th:text-is('a|b')`, "", 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.spec, func(t *testing.T) {
+			field, err := parseScrapeField("f", tc.spec)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if field.Selector != tc.selector || field.Attr != tc.attr || len(field.Filters) != tc.filters {
+				t.Errorf("got selector %q attr %q filters %d", field.Selector, field.Attr, len(field.Filters))
+			}
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	maxScrapeURLs           = 200
+	maxScrapeConcurrency    = 16
+	defaultScrapeURLTimeout = 30 * time.Second
+	// scrapeURLsDeadline bounds the whole batch; URLs not started by then are reported as skipped
+	scrapeURLsDeadline = 10 * time.Minute
+)
+
+// ScrapeURLResult is the outcome of scraping one URL
+type ScrapeURLResult struct {
+	URL        string      `json:"url"`
+	Success    bool        `json:"success"`
+	Data       interface{} `json:"data,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	Session    string      `json:"session,omitempty"`
+	DurationMs int64       `json:"duration_ms"`
+}
+
+// ScrapeURLsTool scrapes a list of URLs concurrently across pooled pages
+type ScrapeURLsTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	scraper    *ScreenScrapeTool
+	sessions   *sessionStore
+}
+
+// NewScrapeURLsTool creates a scrape_urls tool that reads the sessions
+// named in its sessions argument from sessionDir
+func NewScrapeURLsTool(log *logger.Logger, mgr *browser.Manager, sessionDir string) *ScrapeURLsTool {
+	return &ScrapeURLsTool{
+		logger:     log,
+		browserMgr: mgr,
+		scraper:    NewScreenScrapeTool(log, mgr),
+		sessions:   newSessionStore(sessionDir),
+	}
+}
+
+func (t *ScrapeURLsTool) Name() string {
+	return "scrape_urls"
+}
+
+func (t *ScrapeURLsTool) Description() string {
+	return "Scrape many URLs concurrently with the same CSS selector map. Runs up to 'concurrency' pages at once (reusing warm pages when the server has a page pool), applies a per-URL timeout, and reports each URL's data or error so partial failures don't lose the successful results."
+}
+
+func (t *ScrapeURLsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"urls": map[string]interface{}{
+				"type":        "array",
+				"description": fmt.Sprintf("URLs to scrape (max %d)", maxScrapeURLs),
+				"items": map[string]interface{}{
+					"type": "string",
+				},
+				"examples": []interface{}{
+					[]string{"https://example.com/products/1", "https://example.com/products/2"},
+				},
+			},
+			"selectors": map[string]interface{}{
+				"type":                 "object",
+				"description":          "CSS selectors mapping field names to elements, applied to every URL (same format as screen_scrape, including directives and nested schemas)",
+				"additionalProperties": scrapeSelectorValueSchema(),
+				"examples": []interface{}{
+					map[string]interface{}{"title": "h1", "price": ".price"},
+				},
+			},
+			"extract_type": map[string]interface{}{
+				"type":        "string",
+				"description": "'single' extracts one item per URL, 'multiple' extracts an array per URL using container_selector",
+				"enum":        []string{"single", "multiple"},
+				"default":     "single",
+			},
+			"container_selector": map[string]interface{}{
+				"type":        "string",
+				"description": "Container selector for multiple items (required when extract_type='multiple')",
+			},
+			"wait_for": map[string]interface{}{
+				"type":        "string",
+				"description": "CSS selector to wait for on each page before scraping",
+			},
+			"block_resources": map[string]interface{}{
+				"type":        "array",
+				"description": "Resource types each page should not load, for faster DOM-only scraping. Blocked before the first request is made.",
+				"items": map[string]interface{}{
+					"type": "string",
+					"enum": browser.BlockableResourceTypes,
+				},
+				"examples": []interface{}{[]string{"image", "font", "media", "stylesheet"}},
+			},
+			"sessions":  domainSessionsSchema(),
+			"transform": transformSchema("each URL's scraped data"),
+			"concurrency": map[string]interface{}{
+				"type":        "integer",
+				"description": "Maximum number of URLs scraped at the same time",
+				"default":     4,
+				"minimum":     1,
+				"maximum":     maxScrapeConcurrency,
+			},
+			"timeout_per_url": map[string]interface{}{
+				"type":        "integer",
+				"description": "Seconds allowed for loading and scraping each URL",
+				"default":     int(defaultScrapeURLTimeout.Seconds()),
+				"minimum":     1,
+				"maximum":     120,
+			},
+		},
+		Required: []string{"urls", "selectors"},
+	}
+}
+
+func (t *ScrapeURLsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		urls, err := parseScrapeURLs(args["urls"])
+		if err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+
+		selectors, ok := args["selectors"].(map[string]interface{})
+		if !ok || len(selectors) == 0 {
+			return t.errorResponse(args, start, "selectors must be provided as key-value pairs")
+		}
+		if _, err := parseScrapeFields(selectors); err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+
+		blockResources, err := parseBlockResources(args)
+		if err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+
+		transform, err := parseTransform(args)
+		if err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+
+		sessionNames, err := parseDomainSessions(args)
+		if err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+		sessions, err := loadDomainSessions(t.sessions, sessionNames)
+		if err != nil {
+			return t.errorResponse(args, start, err.Error())
+		}
+
+		extractType := "single"
+		if val, ok := args["extract_type"].(string); ok && val != "" {
+			extractType = val
+		}
+		if extractType == "multiple" {
+			if cs, _ := args["container_selector"].(string); cs == "" {
+				return t.errorResponse(args, start, "container_selector is required for multiple extraction")
+			}
+		}
+
+		concurrency := 4
+		if val, ok := args["concurrency"].(float64); ok {
+			concurrency = int(val)
+		}
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		if concurrency > maxScrapeConcurrency {
+			concurrency = maxScrapeConcurrency
+		}
+
+		perURLTimeout := defaultScrapeURLTimeout
+		if val, ok := args["timeout_per_url"].(float64); ok && val > 0 {
+			perURLTimeout = time.Duration(val) * time.Second
+		}
+		if perURLTimeout > 120*time.Second {
+			perURLTimeout = 120 * time.Second
+		}
+
+		batchCtx, cancel := context.WithTimeout(context.Background(), scrapeURLsDeadline)
+		defer cancel()
+
+		results := make([]ScrapeURLResult, len(urls))
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, url := range urls {
+			select {
+			case sem <- struct{}{}:
+			case <-batchCtx.Done():
+				results[i] = ScrapeURLResult{URL: url, Error: fmt.Sprintf("skipped: batch exceeded %v", scrapeURLsDeadline)}
+				continue
+			}
+			wg.Add(1)
+			go func(i int, url string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i] = t.scrapeWithTimeout(url, selectors, extractType, blockResources, sessions, args, perURLTimeout)
+				if transform != nil && results[i].Success {
+					transformed, err := transform.apply(results[i].Data)
+					if err != nil {
+						results[i].Success = false
+						results[i].Data = nil
+						results[i].Error = err.Error()
+					} else {
+						results[i].Data = transformed
+					}
+				}
+			}(i, url)
+		}
+		wg.Wait()
+
+		succeeded := 0
+		for _, r := range results {
+			if r.Success {
+				succeeded++
+			}
+		}
+		failed := len(results) - succeeded
+		duration := time.Since(start).Milliseconds()
+
+		t.logger.WithComponent("tools").Info("URL list scraping completed",
+			zap.Int("urls", len(urls)),
+			zap.Int("succeeded", succeeded),
+			zap.Int("failed", failed),
+			zap.Int("concurrency", concurrency),
+			zap.Int64("duration_ms", duration))
+		t.logger.LogToolExecution(t.Name(), args, succeeded > 0, duration)
+
+		text := fmt.Sprintf("Scraped %d/%d URLs", succeeded, len(urls))
+		if failed > 0 {
+			text += fmt.Sprintf(" (%d failed)", failed)
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"results":     results,
+					"total":       len(urls),
+					"succeeded":   succeeded,
+					"failed":      failed,
+					"concurrency": concurrency,
+					"duration_ms": duration,
+				},
+			}},
+			IsError: succeeded == 0,
+		}, nil
+	})
+}
+
+// scrapeWithTimeout scrapes one URL, reporting a timeout if it runs past the deadline
+func (t *ScrapeURLsTool) scrapeWithTimeout(url string, selectors map[string]interface{}, extractType string, blockResources []string, sessions *domainSessions, args map[string]interface{}, timeout time.Duration) ScrapeURLResult {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	type outcome struct {
+		data interface{}
+		err  error
+	}
+	done := make(chan outcome, 1)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("scrape panicked: %v", r)}
+			}
+		}()
+		data, err := t.scrapeOne(ctx, url, selectors, extractType, blockResources, sessions, args)
+		done <- outcome{data, err}
+	}()
+
+	session, _ := sessions.forURL(url)
+	result := ScrapeURLResult{URL: url, Session: session}
+	select {
+	case o := <-done:
+		if o.err != nil {
+			result.Error = o.err.Error()
+		} else {
+			result.Success = true
+			result.Data = o.data
+		}
+	case <-ctx.Done():
+		result.Error = fmt.Sprintf("timed out after %v", timeout)
+	}
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result
+}
+
+func (t *ScrapeURLsTool) scrapeOne(ctx context.Context, url string, selectors map[string]interface{}, extractType string, blockResources []string, sessions *domainSessions, args map[string]interface{}) (interface{}, error) {
+	pageID, _, release, err := sessions.openPage(t.browserMgr, url, browser.PageOptions{BlockResources: blockResources})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := release(); err != nil {
+			t.logger.WithComponent("tools").Debug("Failed to release scrape page",
+				zap.String("page_id", pageID),
+				zap.Error(err))
+		}
+	}()
+
+	if waitFor, ok := args["wait_for"].(string); ok && waitFor != "" {
+		if err := t.waitForSelector(ctx, pageID, waitFor); err != nil {
+			return nil, err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if extractType == "multiple" {
+		return t.scraper.scrapeMultiple(pageID, nil, selectors, args)
+	}
+	return t.scraper.scrapeSingle(pageID, nil, selectors)
+}
+
+func (t *ScrapeURLsTool) waitForSelector(ctx context.Context, pageID, selector string) error {
+	encoded, _ := json.Marshal(selector)
+	script := fmt.Sprintf("return document.querySelector(%s) !== null;", encoded)
+
+	for {
+		found, err := t.browserMgr.ExecuteScript(pageID, script)
+		if err == nil && fmt.Sprint(found) == "true" {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timeout waiting for element %s", selector)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func (t *ScrapeURLsTool) errorResponse(args map[string]interface{}, start time.Time, message string) (*types.CallToolResponse, error) {
+	t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: message,
+		}},
+		IsError: true,
+	}, nil
+}
+
+// parseScrapeURLs validates the urls argument
+func parseScrapeURLs(raw interface{}) ([]string, error) {
+	list, ok := raw.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, fmt.Errorf("urls must be a non-empty array of strings")
+	}
+	if len(list) > maxScrapeURLs {
+		return nil, fmt.Errorf("too many urls: %d (max %d)", len(list), maxScrapeURLs)
+	}
+
+	urls := make([]string, 0, len(list))
+	for i, item := range list {
+		url, ok := item.(string)
+		if !ok || url == "" {
+			return nil, fmt.Errorf("urls[%d] must be a non-empty string", i)
+		}
+		urls = append(urls, url)
+	}
+	return urls, nil
+}
@@ -0,0 +1,94 @@
+package webtools
+
+import (
+	"rodmcp/internal/browser"
+	"strings"
+	"testing"
+)
+
+func TestScrapeURLsValidation(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewScrapeURLsTool(log, browser.NewManager(log, browser.Config{Headless: true}), t.TempDir())
+
+	tooMany := make([]interface{}, maxScrapeURLs+1)
+	for i := range tooMany {
+		tooMany[i] = "https://example.com"
+	}
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing urls", map[string]interface{}{"selectors": map[string]interface{}{"t": "h1"}}, "non-empty array"},
+		{"non-string url", map[string]interface{}{"urls": []interface{}{"https://a.test", 3.0}, "selectors": map[string]interface{}{"t": "h1"}}, "urls[1]"},
+		{"too many", map[string]interface{}{"urls": tooMany, "selectors": map[string]interface{}{"t": "h1"}}, "too many urls"},
+		{"missing selectors", map[string]interface{}{"urls": []interface{}{"https://a.test"}}, "selectors"},
+		{"multiple without container", map[string]interface{}{
+			"urls":         []interface{}{"https://a.test"},
+			"selectors":    map[string]interface{}{"t": "h1"},
+			"extract_type": "multiple",
+		}, "container_selector"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tool.Execute(tc.args)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("Expected error containing %q, got %q", tc.want, resp.Content[0].Text)
+			}
+		})
+	}
+}
+
+func TestScrapeURLsReportsPerURLFailures(t *testing.T) {
+	log := createTestLogger(t)
+	// The browser is never started, so every URL fails to load
+	tool := NewScrapeURLsTool(log, browser.NewManager(log, browser.Config{Headless: true}), t.TempDir())
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"urls":        []interface{}{"https://a.test", "https://b.test", "https://c.test"},
+		"selectors":   map[string]interface{}{"title": "h1"},
+		"concurrency": float64(2),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError {
+		t.Error("Expected error when every URL fails")
+	}
+
+	data := resp.Content[0].Data.(map[string]interface{})
+	results := data["results"].([]ScrapeURLResult)
+	if len(results) != 3 || data["failed"] != 3 || data["succeeded"] != 0 {
+		t.Fatalf("Unexpected summary: %+v", data)
+	}
+	for i, url := range []string{"https://a.test", "https://b.test", "https://c.test"} {
+		if results[i].URL != url {
+			t.Errorf("Expected results in input order, got %s at %d", results[i].URL, i)
+		}
+		if results[i].Success || !strings.Contains(results[i].Error, "browser not started") {
+			t.Errorf("Expected load failure for %s, got %+v", url, results[i])
+		}
+	}
+}
+
+func TestScrapeURLsRejectsUnknownBlockedResource(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewScrapeURLsTool(log, browser.NewManager(log, browser.Config{Headless: true}), t.TempDir())
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"urls":            []interface{}{"https://a.test"},
+		"selectors":       map[string]interface{}{"title": "h1"},
+		"block_resources": []interface{}{"image", "video"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "unknown resource type") {
+		t.Errorf("Expected unknown resource type error, got %q", resp.Content[0].Text)
+	}
+}
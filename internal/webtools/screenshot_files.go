@@ -0,0 +1,197 @@
+package webtools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+// defaultScreenshotDir is used for auto-named screenshots when no
+// --screenshot-dir is configured. It is relative to the working directory so
+// the usual path restrictions still apply.
+const defaultScreenshotDir = "screenshots"
+
+// screenshotOutput describes what a screenshot tool should do with its capture
+type screenshotOutput struct {
+	filename string
+	save     bool
+	inline   bool        // also return the image when saving to disk
+	shrink   imageShrink // size reduction for the returned image; saved files keep full resolution
+}
+
+// parseScreenshotOutput reads the filename, save, inline, inline_max_width,
+// scale and quality arguments shared by the screenshot tools
+func parseScreenshotOutput(args map[string]interface{}) (screenshotOutput, error) {
+	out := screenshotOutput{}
+	out.filename, _ = args["filename"].(string)
+	out.save, _ = args["save"].(bool)
+	out.inline, _ = args["inline"].(bool)
+
+	shrink, err := parseImageShrink(args)
+	if err != nil {
+		return out, err
+	}
+	out.shrink = shrink
+	return out, nil
+}
+
+// screenshotOutputSchema returns the schema properties for screenshotOutput
+func screenshotOutputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"filename": map[string]interface{}{
+			"type":        "string",
+			"description": "Filename to save screenshot (optional). Relative names are placed in the server's screenshot directory",
+		},
+		"save": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Save to a timestamped file in the screenshot directory when no filename is given (default: false)",
+			"default":     false,
+		},
+		"inline": map[string]interface{}{
+			"type":        "boolean",
+			"description": "When saving, also return the image inline so it can be viewed (default: false). Unsaved screenshots are always returned inline",
+			"default":     false,
+		},
+		"inline_max_width": map[string]interface{}{
+			"type":        "integer",
+			"description": "Downscale the returned image to at most this many pixels wide; the saved file keeps full resolution",
+			"minimum":     1,
+			"maximum":     maxInlineWidth,
+		},
+		"scale": map[string]interface{}{
+			"type":        "number",
+			"description": "Shrink the returned image by this factor, e.g. 0.5 for half size (0.05-1)",
+			"minimum":     0.05,
+			"maximum":     1,
+		},
+		"quality": map[string]interface{}{
+			"type":        "integer",
+			"description": "Return the image as JPEG at this quality (1-100) instead of PNG; much smaller for photos and busy pages",
+			"minimum":     1,
+			"maximum":     100,
+		},
+	}
+}
+
+// parseCaptureOptions reads take_screenshot's full_page, max_height and
+// format arguments. For JPEG and WebP the quality argument sets the capture
+// quality, so it is taken out of shrink rather than applied a second time
+// to the returned image.
+func parseCaptureOptions(args map[string]interface{}, shrink *imageShrink) (browser.ScreenshotOptions, error) {
+	opts := browser.ScreenshotOptions{Format: "png"}
+	opts.FullPage, _ = args["full_page"].(bool)
+	if val, ok := args["max_height"].(float64); ok {
+		if val < 1 || val > browser.MaxScreenshotHeight || val != float64(int(val)) {
+			return opts, fmt.Errorf("max_height must be a whole number between 1 and %d", browser.MaxScreenshotHeight)
+		}
+		opts.MaxHeight = int(val)
+	}
+	if val, _ := args["format"].(string); val != "" {
+		if !containsString(browser.ScreenshotFormats, val) {
+			return opts, fmt.Errorf("format must be one of %s", strings.Join(browser.ScreenshotFormats, ", "))
+		}
+		opts.Format = val
+	}
+	if opts.Format != "png" {
+		opts.Quality = shrink.quality
+		shrink.quality = 0
+	}
+	if opts.Format == "webp" && shrink.resizes() {
+		return opts, fmt.Errorf("inline_max_width and scale can't be used with webp screenshots; use png or jpeg")
+	}
+	return opts, nil
+}
+
+// resolveScreenshotPath works out where a screenshot should be written.
+// Relative filenames are placed in outputDir when one is configured, and
+// save without a filename generates a timestamped name there. trusted
+// reports that the path lies inside the operator-configured outputDir, which
+// is writable regardless of the file access restrictions. An empty path means
+// the screenshot should be returned inline instead. Generated names end in
+// ext, e.g. ".png".
+func resolveScreenshotPath(outputDir, filename, prefix, ext string, save bool) (path string, trusted bool) {
+	if filename == "" && !save {
+		return "", false
+	}
+
+	dir := outputDir
+	if dir == "" {
+		dir = defaultScreenshotDir
+	}
+
+	if filename == "" {
+		path = uniqueScreenshotName(dir, prefix, ext, time.Now())
+	} else if outputDir != "" && !filepath.IsAbs(filename) {
+		path = filepath.Join(outputDir, filename)
+	} else {
+		path = filepath.Clean(filename)
+	}
+
+	return path, outputDir != "" && isWithinDir(path, outputDir)
+}
+
+// uniqueScreenshotName returns "<prefix>-<timestamp><ext>" in dir, adding a
+// counter when a file with that name already exists
+func uniqueScreenshotName(dir, prefix, ext string, now time.Time) string {
+	base := fmt.Sprintf("%s-%s", prefix, now.Format("20060102-150405.000"))
+	name := filepath.Join(dir, base+ext)
+	for i := 2; ; i++ {
+		if _, err := os.Stat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = filepath.Join(dir, fmt.Sprintf("%s-%d%s", base, i, ext))
+	}
+}
+
+// isWithinDir reports whether path resolves to dir or somewhere below it
+func isWithinDir(path, dir string) bool {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(absDir, absPath)
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// validateScreenshotPath checks a screenshot destination against the file
+// access rules; paths inside the configured screenshot directory are always allowed
+func validateScreenshotPath(validator *PathValidator, path string, trusted bool) error {
+	if trusted {
+		return nil
+	}
+	return validator.ValidatePath(path, "write")
+}
+
+// writeScreenshotFile writes data to path, creating parent directories. The
+// file is written under a temporary name and renamed into place so readers
+// never see a partial image.
+func writeScreenshotFile(path string, data []byte) (string, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs, nil
+	}
+	return path, nil
+}
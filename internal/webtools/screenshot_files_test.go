@@ -0,0 +1,104 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveScreenshotPath(t *testing.T) {
+	dir := t.TempDir()
+
+	if path, _ := resolveScreenshotPath(dir, "", "screenshot", ".png", false); path != "" {
+		t.Errorf("Expected inline screenshot without filename or save, got %q", path)
+	}
+
+	path, trusted := resolveScreenshotPath(dir, "", "screenshot", ".png", true)
+	if !trusted || filepath.Dir(path) != dir || !strings.HasPrefix(filepath.Base(path), "screenshot-") || filepath.Ext(path) != ".png" {
+		t.Errorf("Expected auto-named file in %s, got %q (trusted=%v)", dir, path, trusted)
+	}
+
+	path, trusted = resolveScreenshotPath(dir, "shots/home.png", "screenshot", ".png", false)
+	if !trusted || path != filepath.Join(dir, "shots", "home.png") {
+		t.Errorf("Expected relative filename inside output dir, got %q (trusted=%v)", path, trusted)
+	}
+
+	if _, trusted := resolveScreenshotPath(dir, "../escape.png", "screenshot", ".png", false); trusted {
+		t.Error("Expected path escaping the output dir to go through validation")
+	}
+
+	outside := filepath.Join(t.TempDir(), "abs.png")
+	if path, trusted := resolveScreenshotPath(dir, outside, "screenshot", ".png", false); trusted || path != outside {
+		t.Errorf("Expected absolute path outside output dir to be untrusted, got %q (trusted=%v)", path, trusted)
+	}
+
+	path, trusted = resolveScreenshotPath("", "", "element", ".png", true)
+	if trusted || filepath.Dir(path) != defaultScreenshotDir {
+		t.Errorf("Expected default dir without --screenshot-dir, got %q (trusted=%v)", path, trusted)
+	}
+}
+
+func TestUniqueScreenshotName(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	first := uniqueScreenshotName(dir, "screenshot", ".png", now)
+	if filepath.Base(first) != "screenshot-20260102-030405.000.png" {
+		t.Errorf("Unexpected name %s", first)
+	}
+	if err := os.WriteFile(first, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if second := uniqueScreenshotName(dir, "screenshot", ".png", now); filepath.Base(second) != "screenshot-20260102-030405.000-2.png" {
+		t.Errorf("Expected counter suffix for a clash, got %s", second)
+	}
+}
+
+func TestParseCaptureOptions(t *testing.T) {
+	shrink := imageShrink{quality: 60, maxWidth: 800}
+	opts, err := parseCaptureOptions(map[string]interface{}{"full_page": true, "max_height": float64(4000), "format": "jpeg"}, &shrink)
+	if err != nil || !opts.FullPage || opts.MaxHeight != 4000 || opts.Format != "jpeg" || opts.Quality != 60 {
+		t.Fatalf("Unexpected capture options %+v %v", opts, err)
+	}
+	if shrink.quality != 0 || shrink.maxWidth != 800 {
+		t.Errorf("Expected quality to move to the capture, got %+v", shrink)
+	}
+
+	// PNG captures keep quality for the returned image
+	shrink = imageShrink{quality: 60}
+	if opts, _ := parseCaptureOptions(map[string]interface{}{}, &shrink); opts.Format != "png" || opts.Quality != 0 || shrink.quality != 60 {
+		t.Errorf("Unexpected png options %+v %+v", opts, shrink)
+	}
+
+	for _, args := range []map[string]interface{}{
+		{"format": "gif"},
+		{"max_height": float64(0)},
+		{"max_height": float64(1.5)},
+		{"format": "webp", "scale": float64(0.5)},
+	} {
+		shrink, _ := parseImageShrink(args)
+		if _, err := parseCaptureOptions(args, &shrink); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestWriteScreenshotFile(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "nested", "shot.png")
+
+	saved, err := writeScreenshotFile(target, []byte("png"))
+	if err != nil {
+		t.Fatalf("writeScreenshotFile failed: %v", err)
+	}
+	if !filepath.IsAbs(saved) {
+		t.Errorf("Expected absolute saved path, got %s", saved)
+	}
+	if data, err := os.ReadFile(target); err != nil || string(data) != "png" {
+		t.Errorf("Expected file contents to be written, got %q %v", data, err)
+	}
+	if _, err := os.Stat(target + ".tmp"); !os.IsNotExist(err) {
+		t.Error("Expected temporary file to be renamed away")
+	}
+}
@@ -0,0 +1,183 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"rodmcp/pkg/types"
+)
+
+// maxInlineWidth bounds inline_max_width so a typo can't request an upscale
+const maxInlineWidth = 7680
+
+// imageShrink controls how a returned image is reduced in size
+type imageShrink struct {
+	maxWidth int     // at most this many pixels wide (0 for no limit)
+	scale    float64 // multiply both dimensions by this factor (0 or 1 keeps size)
+	quality  int     // re-encode as JPEG at this quality (0 keeps PNG)
+}
+
+// targetWidth returns the width an image of the given width should shrink to
+func (o imageShrink) targetWidth(width int) int {
+	target := width
+	if o.scale > 0 && o.scale < 1 {
+		target = int(float64(width) * o.scale)
+	}
+	if o.maxWidth > 0 && target > o.maxWidth {
+		target = o.maxWidth
+	}
+	if target < 1 {
+		target = 1
+	}
+	return target
+}
+
+// resizes reports whether opts may change an image's dimensions
+func (o imageShrink) resizes() bool {
+	return o.maxWidth > 0 || (o.scale > 0 && o.scale < 1)
+}
+
+// shrinkImage downscales and re-encodes an image according to opts, returning
+// the new bytes and their MIME type. Images that need no change are returned
+// as-is; resized JPEGs stay JPEG and everything else becomes PNG.
+func shrinkImage(data []byte, opts imageShrink) ([]byte, string, error) {
+	if !opts.resizes() && opts.quality == 0 {
+		// Passed through untouched, so formats the standard library can't
+		// decode, like WebP, still work
+		return data, http.DetectContentType(data), nil
+	}
+
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read image size: %w", err)
+	}
+
+	width := opts.targetWidth(cfg.Width)
+	if width >= cfg.Width && opts.quality == 0 {
+		return data, "image/" + format, nil
+	}
+	if opts.quality == 0 && format == "jpeg" {
+		opts.quality = jpeg.DefaultQuality
+	}
+
+	var img image.Image
+	img, _, err = image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if width < cfg.Width {
+		height := cfg.Height * width / cfg.Width
+		if height < 1 {
+			height = 1
+		}
+		img = resizeBox(img, width, height)
+	}
+
+	var buf bytes.Buffer
+	if opts.quality > 0 {
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: opts.quality}); err != nil {
+			return nil, "", fmt.Errorf("failed to encode image: %w", err)
+		}
+		return buf.Bytes(), "image/jpeg", nil
+	}
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), "image/png", nil
+}
+
+// resizeBox downsamples src to width x height by averaging the source pixels
+// each destination pixel covers, which keeps text legible far better than
+// nearest-neighbour sampling
+func resizeBox(src image.Image, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	} else if bounds.Min != (image.Point{}) {
+		rgba = rgba.SubImage(bounds).(*image.RGBA)
+	}
+
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := y * srcH / height
+		y1 := (y + 1) * srcH / height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := x * srcW / width
+			x1 := (x + 1) * srcW / width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					b += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			d := dst.Pix[y*dst.Stride+x*4:]
+			d[0] = uint8(r / n)
+			d[1] = uint8(g / n)
+			d[2] = uint8(b / n)
+			d[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// inlineImageContent builds the image content item returned to the client,
+// shrinking it first as requested
+func inlineImageContent(data []byte, opts imageShrink) (types.ToolContent, error) {
+	shrunk, mimeType, err := shrinkImage(data, opts)
+	if err != nil {
+		return types.ToolContent{}, err
+	}
+	return types.ToolContent{
+		Type:     "image",
+		Data:     base64.StdEncoding.EncodeToString(shrunk),
+		MimeType: mimeType,
+	}, nil
+}
+
+// parseImageShrink reads the optional inline_max_width, scale and quality arguments
+func parseImageShrink(args map[string]interface{}) (imageShrink, error) {
+	var opts imageShrink
+	if val, ok := args["inline_max_width"].(float64); ok {
+		if val < 1 || val > maxInlineWidth {
+			return opts, fmt.Errorf("inline_max_width must be between 1 and %d", maxInlineWidth)
+		}
+		opts.maxWidth = int(val)
+	}
+	if val, ok := args["scale"].(float64); ok {
+		if val < 0.05 || val > 1 {
+			return opts, fmt.Errorf("scale must be between 0.05 and 1")
+		}
+		opts.scale = val
+	}
+	if val, ok := args["quality"].(float64); ok {
+		if val < 1 || val > 100 {
+			return opts, fmt.Errorf("quality must be between 1 and 100")
+		}
+		opts.quality = int(val)
+	}
+	return opts, nil
+}
@@ -0,0 +1,159 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func encodeTestPNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			// Left half black, right half white
+			c := color.RGBA{A: 255}
+			if x >= width/2 {
+				c = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestDownscaleImage(t *testing.T) {
+	original := encodeTestPNG(t, 200, 100)
+
+	scaled, mimeType, err := shrinkImage(original, imageShrink{maxWidth: 50})
+	if err != nil || mimeType != "image/png" {
+		t.Fatalf("shrinkImage failed: %v %s", err, mimeType)
+	}
+	img, err := png.Decode(bytes.NewReader(scaled))
+	if err != nil {
+		t.Fatalf("Scaled image is not a PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 50 || b.Dy() != 25 {
+		t.Errorf("Expected 50x25, got %dx%d", b.Dx(), b.Dy())
+	}
+	if r, _, _, _ := img.At(5, 10).RGBA(); r != 0 {
+		t.Errorf("Expected left side to stay black, got red=%d", r)
+	}
+	if r, _, _, _ := img.At(45, 10).RGBA(); r != 0xffff {
+		t.Errorf("Expected right side to stay white, got red=%d", r)
+	}
+
+	unchanged, _, err := shrinkImage(original, imageShrink{maxWidth: 400})
+	if err != nil || !bytes.Equal(unchanged, original) {
+		t.Error("Expected narrow images to be returned unchanged")
+	}
+
+	if _, _, err := shrinkImage([]byte("not an image"), imageShrink{maxWidth: 10}); err == nil {
+		t.Error("Expected error for invalid image data")
+	}
+}
+
+func TestShrinkImageScaleAndQuality(t *testing.T) {
+	original := encodeTestPNG(t, 200, 100)
+
+	// The smaller of scale and inline_max_width wins
+	scaled, _, err := shrinkImage(original, imageShrink{scale: 0.5, maxWidth: 80})
+	if err != nil {
+		t.Fatalf("shrinkImage failed: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(scaled)); err != nil || cfg.Width != 80 || cfg.Height != 40 {
+		t.Errorf("Expected 80x40, got %+v %v", cfg, err)
+	}
+
+	scaled, _, err = shrinkImage(original, imageShrink{scale: 0.25})
+	if err != nil {
+		t.Fatalf("shrinkImage failed: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(scaled)); err != nil || cfg.Width != 50 {
+		t.Errorf("Expected 50px wide, got %+v %v", cfg, err)
+	}
+
+	compressed, mimeType, err := shrinkImage(original, imageShrink{quality: 40})
+	if err != nil || mimeType != "image/jpeg" {
+		t.Fatalf("Expected JPEG output, got %s %v", mimeType, err)
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(compressed)); err != nil || cfg.Width != 200 {
+		t.Errorf("Expected full-size JPEG, got %+v %v", cfg, err)
+	}
+}
+
+func TestShrinkImageKeepsFormat(t *testing.T) {
+	// Untouched images pass through without decoding, so WebP works
+	webp := []byte("RIFF\x1a\x00\x00\x00WEBPVP8 \x0e\x00\x00\x00")
+	if data, mimeType, err := shrinkImage(webp, imageShrink{}); err != nil || mimeType != "image/webp" || !bytes.Equal(data, webp) {
+		t.Errorf("Expected WebP passthrough, got %s %v", mimeType, err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 120, 60)), nil); err != nil {
+		t.Fatal(err)
+	}
+	scaled, mimeType, err := shrinkImage(buf.Bytes(), imageShrink{maxWidth: 30})
+	if err != nil || mimeType != "image/jpeg" {
+		t.Fatalf("Expected resized JPEG, got %s %v", mimeType, err)
+	}
+	if cfg, err := jpeg.DecodeConfig(bytes.NewReader(scaled)); err != nil || cfg.Width != 30 {
+		t.Errorf("Expected 30px wide JPEG, got %+v %v", cfg, err)
+	}
+}
+
+func TestParseImageShrinkRejectsOutOfRange(t *testing.T) {
+	for _, args := range []map[string]interface{}{
+		{"scale": float64(0)},
+		{"scale": float64(1.5)},
+		{"quality": float64(0)},
+		{"quality": float64(101)},
+	} {
+		if _, err := parseImageShrink(args); err == nil {
+			t.Errorf("Expected error for %v", args)
+		}
+	}
+}
+
+func TestInlineImageContent(t *testing.T) {
+	content, err := inlineImageContent(encodeTestPNG(t, 64, 64), imageShrink{maxWidth: 16})
+	if err != nil {
+		t.Fatalf("inlineImageContent failed: %v", err)
+	}
+	if content.Type != "image" || content.MimeType != "image/png" {
+		t.Errorf("Unexpected content %+v", content)
+	}
+	data, err := base64.StdEncoding.DecodeString(content.Data.(string))
+	if err != nil {
+		t.Fatalf("Expected base64 data: %v", err)
+	}
+	if cfg, err := png.DecodeConfig(bytes.NewReader(data)); err != nil || cfg.Width != 16 {
+		t.Errorf("Expected 16px wide inline image, got %+v %v", cfg, err)
+	}
+}
+
+func TestParseScreenshotOutput(t *testing.T) {
+	out, err := parseScreenshotOutput(map[string]interface{}{
+		"filename":         "shot.png",
+		"inline":           true,
+		"inline_max_width": float64(800),
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if out.filename != "shot.png" || !out.inline || out.shrink.maxWidth != 800 {
+		t.Errorf("Unexpected output options %+v", out)
+	}
+
+	if _, err := parseScreenshotOutput(map[string]interface{}{"inline_max_width": float64(0)}); err == nil {
+		t.Error("Expected error for zero inline_max_width")
+	}
+}
@@ -0,0 +1,418 @@
+package webtools
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+const (
+	securityFetchTimeout = 15 * time.Second
+	// minHSTSMaxAge is the 180 days most preload and audit guidance asks for
+	minHSTSMaxAge = 180 * 24 * 60 * 60
+	// certificates expiring sooner than these are reported
+	certExpiryFailDays = 14
+	certExpiryWarnDays = 30
+)
+
+// tlsFacts describes the TLS connection a page was fetched over
+type tlsFacts struct {
+	Version     string    `json:"version"`
+	Cipher      string    `json:"cipher"`
+	Subject     string    `json:"subject"`
+	Issuer      string    `json:"issuer"`
+	DNSNames    []string  `json:"dns_names,omitempty"`
+	NotAfter    time.Time `json:"not_after"`
+	VerifyError string    `json:"verify_error,omitempty"`
+	legacy      bool      // negotiated below TLS 1.2
+}
+
+// securityFacts is what the security audit inspects for one page
+type securityFacts struct {
+	URL        string                 `json:"url"`
+	FinalURL   string                 `json:"final_url"`
+	StatusCode int                    `json:"status_code"`
+	Headers    http.Header            `json:"headers"`
+	TLS        *tlsFacts              `json:"tls,omitempty"`
+	Cookies    []*proto.NetworkCookie `json:"-"`
+}
+
+// securityReport is the result of a security header audit; Score is 0-100
+type securityReport struct {
+	URL    string      `json:"url"`
+	Score  int         `json:"score"`
+	Grade  string      `json:"grade"`
+	Checks auditChecks `json:"checks"`
+}
+
+var (
+	hstsMaxAgePattern = regexp.MustCompile(`(?i)max-age\s*=\s*"?(\d+)`)
+	versionPattern    = regexp.MustCompile(`\d+\.\d+`)
+)
+
+// cspDirective returns the sources of a CSP directive and whether it is set
+func cspDirective(policy, name string) ([]string, bool) {
+	for _, directive := range strings.Split(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) > 0 && strings.EqualFold(fields[0], name) {
+			return fields[1:], true
+		}
+	}
+	return nil, false
+}
+
+// auditSecurity runs the header, cookie and TLS checks and scores them
+func auditSecurity(f securityFacts, now time.Time) securityReport {
+	var checks auditChecks
+	add := checks.add
+	https := strings.HasPrefix(strings.ToLower(f.FinalURL), "https://")
+	h := f.Headers
+
+	if !https {
+		add("https", "fail", 20, "Page is served over plain HTTP")
+	} else if f.TLS != nil && f.TLS.VerifyError != "" {
+		add("https", "fail", 20, "Certificate is not trusted: %s", f.TLS.VerifyError)
+	} else {
+		add("https", "pass", 20, "Page is served over HTTPS")
+	}
+
+	if https && f.TLS != nil {
+		days := int(f.TLS.NotAfter.Sub(now).Hours() / 24)
+		switch {
+		case days < 0:
+			add("certificate", "fail", 10, "Certificate expired on %s", f.TLS.NotAfter.Format("2006-01-02"))
+		case days < certExpiryFailDays:
+			add("certificate", "fail", 10, "Certificate expires in %d days (%s)", days, f.TLS.NotAfter.Format("2006-01-02"))
+		case days < certExpiryWarnDays:
+			add("certificate", "warn", 10, "Certificate expires in %d days (%s)", days, f.TLS.NotAfter.Format("2006-01-02"))
+		default:
+			add("certificate", "pass", 10, "Certificate for %s from %s valid until %s", f.TLS.Subject, f.TLS.Issuer, f.TLS.NotAfter.Format("2006-01-02"))
+		}
+		if f.TLS.legacy {
+			add("tls_version", "fail", 10, "Negotiated %s; TLS 1.2 or later is expected", f.TLS.Version)
+		} else {
+			add("tls_version", "pass", 10, "Negotiated %s with %s", f.TLS.Version, f.TLS.Cipher)
+		}
+	}
+
+	if https {
+		hsts := h.Get("Strict-Transport-Security")
+		match := hstsMaxAgePattern.FindStringSubmatch(hsts)
+		switch {
+		case hsts == "":
+			add("hsts", "fail", 10, "No Strict-Transport-Security header")
+		case match == nil:
+			add("hsts", "fail", 10, "Strict-Transport-Security has no max-age: %s", hsts)
+		default:
+			maxAge, _ := strconv.Atoi(match[1])
+			if maxAge < minHSTSMaxAge {
+				add("hsts", "warn", 10, "Strict-Transport-Security max-age is %d seconds (recommended at least %d)", maxAge, minHSTSMaxAge)
+			} else {
+				add("hsts", "pass", 10, "Strict-Transport-Security: %s", hsts)
+			}
+		}
+	}
+
+	csp := h.Get("Content-Security-Policy")
+	switch {
+	case csp == "" && h.Get("Content-Security-Policy-Report-Only") != "":
+		add("csp", "warn", 15, "Content-Security-Policy is report-only and not enforced")
+	case csp == "":
+		add("csp", "fail", 15, "No Content-Security-Policy header")
+	default:
+		scripts, ok := cspDirective(csp, "script-src")
+		if !ok {
+			scripts, ok = cspDirective(csp, "default-src")
+		}
+		var weak []string
+		if !ok {
+			weak = append(weak, "no script-src or default-src, so scripts are unrestricted")
+		}
+		nonceOrHash := false
+		for _, source := range scripts {
+			lower := strings.ToLower(source)
+			if strings.HasPrefix(lower, "'nonce-") || strings.HasPrefix(lower, "'sha") || lower == "'strict-dynamic'" {
+				nonceOrHash = true
+			}
+		}
+		for _, source := range scripts {
+			switch strings.ToLower(source) {
+			case "'unsafe-inline'":
+				// Ignored by browsers when a nonce or hash is present
+				if !nonceOrHash {
+					weak = append(weak, "allows 'unsafe-inline' scripts")
+				}
+			case "'unsafe-eval'":
+				weak = append(weak, "allows 'unsafe-eval'")
+			case "*", "http:", "https:", "data:":
+				weak = append(weak, fmt.Sprintf("allows scripts from %s", source))
+			}
+		}
+		if len(weak) > 0 {
+			add("csp", "warn", 15, "Content-Security-Policy %s", strings.Join(weak, "; "))
+		} else {
+			add("csp", "pass", 15, "Content-Security-Policy restricts scripts")
+		}
+	}
+
+	_, frameAncestors := cspDirective(csp, "frame-ancestors")
+	xfo := strings.ToUpper(strings.TrimSpace(h.Get("X-Frame-Options")))
+	switch {
+	case frameAncestors:
+		add("framing", "pass", 10, "CSP frame-ancestors controls framing")
+	case xfo == "DENY" || xfo == "SAMEORIGIN":
+		add("framing", "pass", 10, "X-Frame-Options: %s", xfo)
+	case xfo != "":
+		add("framing", "warn", 10, "X-Frame-Options %q is not supported by current browsers; use DENY, SAMEORIGIN or CSP frame-ancestors", xfo)
+	default:
+		add("framing", "fail", 10, "No X-Frame-Options or CSP frame-ancestors; the page can be framed (clickjacking)")
+	}
+
+	if strings.EqualFold(strings.TrimSpace(h.Get("X-Content-Type-Options")), "nosniff") {
+		add("content_type_options", "pass", 5, "X-Content-Type-Options: nosniff")
+	} else {
+		add("content_type_options", "warn", 5, "X-Content-Type-Options is not nosniff")
+	}
+
+	referrer := strings.ToLower(h.Get("Referrer-Policy"))
+	switch {
+	case referrer == "":
+		add("referrer_policy", "warn", 5, "No Referrer-Policy header (browsers default to strict-origin-when-cross-origin)")
+	case strings.Contains(referrer, "unsafe-url") || strings.Contains(referrer, "no-referrer-when-downgrade"):
+		add("referrer_policy", "warn", 5, "Referrer-Policy %q leaks full URLs to other sites", referrer)
+	default:
+		add("referrer_policy", "pass", 5, "Referrer-Policy: %s", referrer)
+	}
+
+	if h.Get("Permissions-Policy") == "" {
+		add("permissions_policy", "warn", 5, "No Permissions-Policy header")
+	} else {
+		add("permissions_policy", "pass", 5, "Permissions-Policy set")
+	}
+
+	var disclosed []string
+	for _, name := range []string{"Server", "X-Powered-By", "X-AspNet-Version", "X-AspNetMvc-Version"} {
+		if value := h.Get(name); value != "" && versionPattern.MatchString(value) {
+			disclosed = append(disclosed, name+": "+value)
+		}
+	}
+	if len(disclosed) > 0 {
+		add("version_disclosure", "warn", 5, "Software versions disclosed: %s", strings.Join(disclosed, ", "))
+	} else {
+		add("version_disclosure", "pass", 5, "No software versions in headers")
+	}
+
+	var insecure, scriptable, noSameSite []string
+	for _, c := range f.Cookies {
+		if https && !c.Secure {
+			insecure = append(insecure, c.Name)
+		}
+		if !c.HTTPOnly {
+			scriptable = append(scriptable, c.Name)
+		}
+		if c.SameSite == "" || (c.SameSite == proto.NetworkCookieSameSiteNone && !c.Secure) {
+			noSameSite = append(noSameSite, c.Name)
+		}
+	}
+	switch {
+	case len(f.Cookies) == 0:
+		add("cookies", "pass", 10, "No cookies set for this site")
+	case len(insecure) > 0:
+		add("cookies", "fail", 10, "%s", cookieFindings(len(f.Cookies), insecure, scriptable, noSameSite))
+	case len(scriptable) > 0 || len(noSameSite) > 0:
+		add("cookies", "warn", 10, "%s", cookieFindings(len(f.Cookies), insecure, scriptable, noSameSite))
+	default:
+		add("cookies", "pass", 10, "All %d cookies are Secure, HttpOnly and have SameSite", len(f.Cookies))
+	}
+
+	score := checks.score()
+	return securityReport{URL: f.FinalURL, Score: score, Grade: auditGrade(score), Checks: checks}
+}
+
+func cookieFindings(total int, insecure, scriptable, noSameSite []string) string {
+	var parts []string
+	if len(insecure) > 0 {
+		parts = append(parts, "without Secure: "+strings.Join(insecure, ", "))
+	}
+	if len(scriptable) > 0 {
+		parts = append(parts, "without HttpOnly: "+strings.Join(scriptable, ", "))
+	}
+	if len(noSameSite) > 0 {
+		parts = append(parts, "without a usable SameSite: "+strings.Join(noSameSite, ", "))
+	}
+	return fmt.Sprintf("%d cookies; %s", total, strings.Join(parts, "; "))
+}
+
+// fetchSecurityFacts requests pageURL with the page's cookies and records the
+// final response headers and TLS details. An untrusted certificate is not an
+// error: the request is repeated without verification so it can be reported.
+func fetchSecurityFacts(pageURL string, cookies []*proto.NetworkCookie) (*securityFacts, error) {
+	fetch := func(insecure bool) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, pageURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range cookies {
+			req.AddCookie(&http.Cookie{Name: c.Name, Value: c.Value})
+		}
+		client := &http.Client{
+			Timeout: securityFetchTimeout,
+			Transport: meteredTransport(&http.Transport{
+				Proxy: http.ProxyFromEnvironment,
+				// Accept old protocol versions so they can be reported
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure, MinVersion: tls.VersionTLS10},
+			}),
+		}
+		return client.Do(req)
+	}
+
+	var verifyErr error
+	resp, err := fetch(false)
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		verifyErr = certErr.Err
+		resp, err = fetch(true)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	facts := &securityFacts{
+		URL:        pageURL,
+		FinalURL:   resp.Request.URL.String(),
+		StatusCode: resp.StatusCode,
+		Headers:    resp.Header,
+		Cookies:    cookies,
+	}
+	if state := resp.TLS; state != nil && len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		facts.TLS = &tlsFacts{
+			Version:  tls.VersionName(state.Version),
+			Cipher:   tls.CipherSuiteName(state.CipherSuite),
+			Subject:  cert.Subject.CommonName,
+			Issuer:   cert.Issuer.CommonName,
+			DNSNames: cert.DNSNames,
+			NotAfter: cert.NotAfter,
+			legacy:   state.Version < tls.VersionTLS12,
+		}
+		if verifyErr != nil {
+			facts.TLS.VerifyError = verifyErr.Error()
+		}
+	}
+	return facts, nil
+}
+
+// AuditSecurityHeadersTool reports gaps in a page's security headers,
+// cookie flags and TLS certificate
+type AuditSecurityHeadersTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewAuditSecurityHeadersTool(log *logger.Logger, mgr *browser.Manager) *AuditSecurityHeadersTool {
+	return &AuditSecurityHeadersTool{logger: log, browserMgr: mgr}
+}
+
+func (t *AuditSecurityHeadersTool) Name() string {
+	return "audit_security_headers"
+}
+
+func (t *AuditSecurityHeadersTool) Description() string {
+	return "Audit the current page's security: HTTPS and TLS certificate, HSTS, Content-Security-Policy, framing protection, X-Content-Type-Options, Referrer-Policy, version disclosure and the Secure/HttpOnly/SameSite flags of the site's cookies, with a 0-100 score"
+}
+
+func (t *AuditSecurityHeadersTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *AuditSecurityHeadersTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		// The browser knows the page's URL and its cookies' real flags
+		state, err := t.browserMgr.CaptureSession(pageID)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read page: %v", err))
+		}
+		u, err := url.Parse(state.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fail(fmt.Sprintf("Page is not on a web URL (%q); navigate to the site first", state.URL))
+		}
+		var cookies []*proto.NetworkCookie
+		for _, c := range state.Cookies {
+			if cookieMatchesHost(c.Domain, u.Hostname()) {
+				cookies = append(cookies, c)
+			}
+		}
+
+		facts, err := fetchSecurityFacts(state.URL, cookies)
+		if err != nil {
+			return fail(err.Error())
+		}
+		report := auditSecurity(*facts, time.Now())
+
+		var text strings.Builder
+		fmt.Fprintf(&text, "Security score for %s: %d/100 (%s)\n", report.URL, report.Score, report.Grade)
+		report.Checks.format(&text)
+
+		cookieFlags := make([]map[string]interface{}, len(cookies))
+		for i, c := range cookies {
+			cookieFlags[i] = map[string]interface{}{
+				"name":      c.Name,
+				"domain":    c.Domain,
+				"secure":    c.Secure,
+				"http_only": c.HTTPOnly,
+				"same_site": string(c.SameSite),
+			}
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text.String(),
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"report":  report,
+					"facts":   facts,
+					"cookies": cookieFlags,
+				},
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,137 @@
+package webtools
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+)
+
+func TestAuditSecurityHardenedPage(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headers := http.Header{}
+	headers.Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+	headers.Set("Content-Security-Policy", "default-src 'self'; script-src 'self' 'nonce-abc' 'unsafe-inline'; frame-ancestors 'none'")
+	headers.Set("X-Content-Type-Options", "nosniff")
+	headers.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	headers.Set("Permissions-Policy", "camera=()")
+	headers.Set("Server", "nginx")
+
+	report := auditSecurity(securityFacts{
+		FinalURL: "https://bank.example.com/",
+		Headers:  headers,
+		TLS:      &tlsFacts{Version: "TLS 1.3", NotAfter: now.AddDate(0, 3, 0)},
+		Cookies: []*proto.NetworkCookie{
+			{Name: "session", Secure: true, HTTPOnly: true, SameSite: proto.NetworkCookieSameSiteLax},
+		},
+	}, now)
+
+	if report.Score != 100 {
+		var b strings.Builder
+		report.Checks.format(&b)
+		t.Errorf("score = %d, want 100:\n%s", report.Score, b.String())
+	}
+}
+
+func TestAuditSecurityGaps(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	headers := http.Header{}
+	headers.Set("Strict-Transport-Security", "max-age=3600")
+	headers.Set("Content-Security-Policy", "script-src 'self' 'unsafe-inline' https:")
+	headers.Set("X-Frame-Options", "ALLOW-FROM https://partner.example")
+	headers.Set("Referrer-Policy", "unsafe-url")
+	headers.Set("X-Powered-By", "PHP/7.4.3")
+
+	report := auditSecurity(securityFacts{
+		FinalURL: "https://shop.example.com/",
+		Headers:  headers,
+		TLS:      &tlsFacts{Version: "TLS 1.0", NotAfter: now.AddDate(0, 0, 5), legacy: true},
+		Cookies: []*proto.NetworkCookie{
+			{Name: "sid", HTTPOnly: true, SameSite: proto.NetworkCookieSameSiteNone},
+			{Name: "prefs", Secure: true, SameSite: proto.NetworkCookieSameSiteStrict},
+		},
+	}, now)
+
+	for id, want := range map[string]string{
+		"https":                "pass",
+		"certificate":          "fail",
+		"tls_version":          "fail",
+		"hsts":                 "warn",
+		"csp":                  "warn",
+		"framing":              "warn",
+		"content_type_options": "warn",
+		"referrer_policy":      "warn",
+		"permissions_policy":   "warn",
+		"version_disclosure":   "warn",
+		"cookies":              "fail",
+	} {
+		if got := report.Checks.status(id); got != want {
+			t.Errorf("%s = %q, want %q", id, got, want)
+		}
+	}
+
+	var b strings.Builder
+	report.Checks.format(&b)
+	for _, want := range []string{
+		"Certificate expires in 5 days",
+		"allows 'unsafe-inline' scripts; allows scripts from https:",
+		"X-Powered-By: PHP/7.4.3",
+		"without Secure: sid; without HttpOnly: prefs; without a usable SameSite: sid",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Errorf("report missing %q:\n%s", want, b.String())
+		}
+	}
+}
+
+func TestAuditSecurityPlainHTTP(t *testing.T) {
+	report := auditSecurity(securityFacts{FinalURL: "http://intranet.example/", Headers: http.Header{}}, time.Now())
+	if report.Checks.status("https") != "fail" || report.Checks.status("hsts") != "" {
+		t.Errorf("plain HTTP should fail https and skip HSTS: %+v", report.Checks)
+	}
+	if report.Checks.status("framing") != "fail" || report.Checks.status("csp") != "fail" {
+		t.Errorf("missing headers should fail: %+v", report.Checks)
+	}
+}
+
+func TestFetchSecurityFacts(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("sid"); err != nil || c.Value != "abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("X-Frame-Options", "DENY")
+	}))
+	defer server.Close()
+
+	facts, err := fetchSecurityFacts(server.URL, []*proto.NetworkCookie{{Name: "sid", Value: "abc"}})
+	if err != nil {
+		t.Fatalf("fetch failed: %v", err)
+	}
+	if facts.StatusCode != http.StatusOK || facts.Headers.Get("X-Frame-Options") != "DENY" {
+		t.Errorf("unexpected response: %d %v", facts.StatusCode, facts.Headers)
+	}
+	// The test server's certificate is self-signed, which is reported rather
+	// than failing the audit
+	if facts.TLS == nil || facts.TLS.VerifyError == "" {
+		t.Errorf("expected an untrusted certificate to be reported: %+v", facts.TLS)
+	}
+}
+
+func TestAuditSecurityHeadersNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewAuditSecurityHeadersTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
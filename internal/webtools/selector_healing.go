@@ -0,0 +1,108 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"rodmcp/internal/browser"
+)
+
+const (
+	// healOutlineLimit caps the page markup sent to the model, in characters
+	healOutlineLimit = 30000
+
+	healMaxTokens = 1024
+)
+
+const healSystemPrompt = "You repair CSS selectors for a web scraper. A page changed and some saved selectors no longer match. " +
+	"Reply with only a JSON object mapping each field name to a new CSS selector for the same information, " +
+	"using the same ::text/::attr() suffix as the old selector. Leave out fields the page does not contain."
+
+// selectorHealer asks the client's model for replacement selectors when
+// saved ones stop matching
+type selectorHealer struct {
+	browserMgr *browser.Manager
+	sample     SampleFunc
+}
+
+// heal proposes new selectors for the broken fields (name to old selector)
+// on pageID. Only proposals that match an element on the page are returned.
+func (h *selectorHealer) heal(pageID string, broken map[string]string) (map[string]string, error) {
+	raw, err := h.browserMgr.ExecuteScript(pageID, fmt.Sprintf(healOutlineScript, healOutlineLimit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the page: %w", err)
+	}
+	var outline string
+	if err := decodeScriptValue(raw, &outline); err != nil {
+		return nil, err
+	}
+
+	var proposed map[string]string
+	if err := askModelJSON(h.sample, healSystemPrompt, healPrompt(broken, outline), healMaxTokens, &proposed); err != nil {
+		return nil, err
+	}
+
+	healed := make(map[string]string)
+	for name, selector := range proposed {
+		if _, ok := broken[name]; !ok || selector == "" || selector == broken[name] {
+			continue
+		}
+		if h.matches(pageID, selector) {
+			healed[name] = selector
+		}
+	}
+	return healed, nil
+}
+
+// matches reports whether selector, less any extraction suffix, finds an
+// element on the page
+func (h *selectorHealer) matches(pageID, selector string) bool {
+	css := selector
+	if i := strings.Index(css, "::"); i >= 0 {
+		css = css[:i]
+	}
+	cssJSON, _ := json.Marshal(strings.TrimSpace(css))
+	raw, err := h.browserMgr.ExecuteScript(pageID, fmt.Sprintf(`
+		try { return !!document.querySelector(%s); } catch (e) { return false; }`, cssJSON))
+	var found bool
+	return err == nil && decodeScriptValue(raw, &found) == nil && found
+}
+
+// healPrompt describes the broken fields and the page to the model
+func healPrompt(broken map[string]string, outline string) string {
+	names := make([]string, 0, len(broken))
+	for name := range broken {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("These fields no longer match anything:\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "- %s: %s\n", name, broken[name])
+	}
+	b.WriteString("\nPage markup (scripts and styles removed, long text shortened):\n")
+	b.WriteString(outline)
+	return b.String()
+}
+
+// healOutlineScript returns the body's markup without scripts, styles or
+// most attributes, cut to %d characters
+const healOutlineScript = `
+	const keep = ['id', 'class', 'name', 'role', 'href', 'src', 'alt', 'title', 'type', 'itemprop', 'aria-label'];
+	const body = document.body.cloneNode(true);
+	body.querySelectorAll('script, style, noscript, svg, template, link, meta').forEach(e => e.remove());
+	body.querySelectorAll('*').forEach(e => {
+		for (const attr of Array.from(e.attributes)) {
+			if (!keep.includes(attr.name) && !attr.name.startsWith('data-test')) e.removeAttribute(attr.name);
+		}
+	});
+	const walker = document.createTreeWalker(body, NodeFilter.SHOW_TEXT);
+	for (let node = walker.nextNode(); node; node = walker.nextNode()) {
+		const text = node.textContent.replace(/\s+/g, ' ');
+		node.textContent = text.length > 80 ? text.slice(0, 80) + '…' : text;
+	}
+	return body.innerHTML.replace(/>\s+</g, '><').slice(0, %d);
+`
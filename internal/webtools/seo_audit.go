@@ -0,0 +1,345 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Recommended lengths, in characters, as shown in search results
+const (
+	minTitleLength       = 30
+	maxTitleLength       = 60
+	minDescriptionLength = 70
+	maxDescriptionLength = 160
+)
+
+// seoFacts is what the audit script reads from the page
+type seoFacts struct {
+	URL             string   `json:"url"`
+	Titles          []string `json:"titles"`
+	Descriptions    []string `json:"descriptions"`
+	Canonicals      []string `json:"canonicals"`
+	Robots          []string `json:"robots"`
+	H1s             []string `json:"h1s"`
+	HeadingLevels   []int    `json:"heading_levels"`
+	Images          int      `json:"images"`
+	ImagesNoAlt     []string `json:"images_no_alt"`
+	ImagesNoAltN    int      `json:"images_no_alt_count"`
+	Lang            string   `json:"lang"`
+	Viewport        bool     `json:"viewport"`
+	OpenGraph       []string `json:"open_graph"`
+	StructuredData  []string `json:"structured_data"`
+	MicrodataScopes int      `json:"microdata_scopes"`
+}
+
+// seoReport is the result of an SEO audit; Score is 0-100
+type seoReport struct {
+	URL    string      `json:"url"`
+	Score  int         `json:"score"`
+	Grade  string      `json:"grade"`
+	Checks auditChecks `json:"checks"`
+}
+
+const seoAuditScript = `
+	const clean = s => (s || '').replace(/\s+/g, ' ').trim();
+	const metas = name => Array.from(document.querySelectorAll('meta[name]'))
+		.filter(m => m.getAttribute('name').toLowerCase() === name)
+		.map(m => clean(m.getAttribute('content')));
+
+	const missingAlt = Array.from(document.images).filter(img => !img.hasAttribute('alt'));
+	return {
+		url: location.href,
+		titles: Array.from(document.querySelectorAll('head title')).map(t => clean(t.textContent)),
+		descriptions: metas('description'),
+		canonicals: Array.from(document.querySelectorAll('link[rel~="canonical" i]')).map(l => l.href),
+		robots: metas('robots').concat(metas('googlebot')),
+		h1s: Array.from(document.querySelectorAll('h1')).map(h => clean(h.textContent)),
+		heading_levels: Array.from(document.querySelectorAll('h1,h2,h3,h4,h5,h6')).map(h => Number(h.tagName[1])),
+		images: document.images.length,
+		images_no_alt: missingAlt.slice(0, 10).map(img => img.currentSrc || img.src || img.outerHTML.slice(0, 120)),
+		images_no_alt_count: missingAlt.length,
+		lang: document.documentElement.getAttribute('lang') || '',
+		viewport: !!document.querySelector('meta[name="viewport" i]'),
+		open_graph: Array.from(document.querySelectorAll('meta[property^="og:"]')).map(m => m.getAttribute('property')),
+		structured_data: Array.from(document.querySelectorAll('script[type="application/ld+json" i]')).map(s => s.textContent.slice(0, 50000)),
+		microdata_scopes: document.querySelectorAll('[itemscope]').length,
+	};
+`
+
+// auditSEO runs the checks against the collected facts and scores them
+func auditSEO(f seoFacts) seoReport {
+	var checks auditChecks
+	add := checks.add
+
+	switch {
+	case len(f.Titles) == 0 || f.Titles[0] == "":
+		add("title", "fail", 15, "Page has no title")
+	case len(f.Titles) > 1:
+		add("title", "warn", 15, "Page has %d title elements; only the first is used", len(f.Titles))
+	default:
+		n := len([]rune(f.Titles[0]))
+		if n < minTitleLength || n > maxTitleLength {
+			add("title", "warn", 15, "Title is %d characters (recommended %d-%d): %q", n, minTitleLength, maxTitleLength, f.Titles[0])
+		} else {
+			add("title", "pass", 15, "Title is %d characters", n)
+		}
+	}
+
+	switch {
+	case len(f.Descriptions) == 0 || f.Descriptions[0] == "":
+		add("description", "fail", 10, "No meta description")
+	case len(f.Descriptions) > 1:
+		add("description", "warn", 10, "%d meta descriptions; search engines may pick either", len(f.Descriptions))
+	default:
+		n := len([]rune(f.Descriptions[0]))
+		if n < minDescriptionLength || n > maxDescriptionLength {
+			add("description", "warn", 10, "Meta description is %d characters (recommended %d-%d)", n, minDescriptionLength, maxDescriptionLength)
+		} else {
+			add("description", "pass", 10, "Meta description is %d characters", n)
+		}
+	}
+
+	switch {
+	case len(f.Canonicals) == 0:
+		add("canonical", "warn", 10, "No canonical link")
+	case len(f.Canonicals) > 1:
+		add("canonical", "fail", 10, "%d canonical links; search engines ignore conflicting canonicals", len(f.Canonicals))
+	case strings.SplitN(f.Canonicals[0], "#", 2)[0] != strings.SplitN(f.URL, "#", 2)[0]:
+		add("canonical", "pass", 10, "Canonical points elsewhere: %s", f.Canonicals[0])
+	default:
+		add("canonical", "pass", 10, "Canonical is self-referencing")
+	}
+
+	switch {
+	case len(f.H1s) == 0:
+		add("h1", "fail", 10, "No H1 heading")
+	case len(f.H1s) > 1:
+		add("h1", "warn", 10, "%d H1 headings; use one per page", len(f.H1s))
+	case f.H1s[0] == "":
+		add("h1", "warn", 10, "H1 heading is empty")
+	default:
+		add("h1", "pass", 10, "Single H1: %q", clipText(f.H1s[0], 80))
+	}
+
+	var skips []string
+	for i := 1; i < len(f.HeadingLevels); i++ {
+		if prev, cur := f.HeadingLevels[i-1], f.HeadingLevels[i]; cur > prev+1 {
+			skips = append(skips, fmt.Sprintf("h%d -> h%d", prev, cur))
+		}
+	}
+	if len(skips) > 0 {
+		add("heading_structure", "warn", 5, "Heading levels skipped: %s", strings.Join(uniqueStrings(skips), ", "))
+	} else {
+		add("heading_structure", "pass", 5, "Heading levels are nested in order")
+	}
+
+	switch {
+	case f.Images == 0:
+		add("image_alt", "pass", 10, "No images")
+	case f.ImagesNoAltN == 0:
+		add("image_alt", "pass", 10, "All %d images have alt text", f.Images)
+	default:
+		coverage := float64(f.Images-f.ImagesNoAltN) / float64(f.Images)
+		status := "fail"
+		if coverage >= 0.9 {
+			status = "warn"
+		}
+		message := fmt.Sprintf("%d of %d images have no alt attribute (%.0f%% coverage)", f.ImagesNoAltN, f.Images, coverage*100)
+		if len(f.ImagesNoAlt) > 0 {
+			message += ": " + strings.Join(f.ImagesNoAlt, ", ")
+		}
+		add("image_alt", status, 10, "%s", message)
+	}
+
+	var noindex, nofollow bool
+	for _, robots := range f.Robots {
+		for _, directive := range strings.Split(strings.ToLower(robots), ",") {
+			switch strings.TrimSpace(directive) {
+			case "noindex", "none":
+				noindex = true
+			case "nofollow":
+				nofollow = true
+			}
+		}
+	}
+	switch {
+	case noindex:
+		add("indexable", "fail", 15, "Robots meta blocks indexing: %s", strings.Join(f.Robots, "; "))
+	case nofollow:
+		add("indexable", "warn", 15, "Robots meta blocks following links: %s", strings.Join(f.Robots, "; "))
+	default:
+		add("indexable", "pass", 15, "Page is indexable")
+	}
+
+	var broken, untyped []string
+	for i, block := range f.StructuredData {
+		var data interface{}
+		if err := json.Unmarshal([]byte(block), &data); err != nil {
+			broken = append(broken, fmt.Sprintf("block %d: %v", i+1, err))
+			continue
+		}
+		if !hasSchemaType(data) {
+			untyped = append(untyped, fmt.Sprintf("block %d", i+1))
+		}
+	}
+	switch {
+	case len(broken) > 0:
+		add("structured_data", "fail", 10, "Invalid JSON-LD: %s", strings.Join(broken, "; "))
+	case len(untyped) > 0:
+		add("structured_data", "warn", 10, "JSON-LD without @type: %s", strings.Join(untyped, ", "))
+	case len(f.StructuredData) > 0:
+		add("structured_data", "pass", 10, "%d valid JSON-LD blocks", len(f.StructuredData))
+	case f.MicrodataScopes > 0:
+		add("structured_data", "pass", 10, "%d microdata items", f.MicrodataScopes)
+	default:
+		add("structured_data", "warn", 10, "No structured data")
+	}
+
+	if f.Lang == "" {
+		add("lang", "warn", 5, "<html> has no lang attribute")
+	} else {
+		add("lang", "pass", 5, "Language: %s", f.Lang)
+	}
+	if f.Viewport {
+		add("viewport", "pass", 5, "Viewport meta tag present")
+	} else {
+		add("viewport", "warn", 5, "No viewport meta tag; the page may not be treated as mobile-friendly")
+	}
+
+	var missingOG []string
+	for _, property := range []string{"og:title", "og:description", "og:image"} {
+		if !containsString(f.OpenGraph, property) {
+			missingOG = append(missingOG, property)
+		}
+	}
+	if len(missingOG) > 0 {
+		add("open_graph", "warn", 5, "Missing Open Graph tags: %s", strings.Join(missingOG, ", "))
+	} else {
+		add("open_graph", "pass", 5, "Open Graph title, description and image present")
+	}
+
+	score := checks.score()
+	return seoReport{URL: f.URL, Score: score, Grade: auditGrade(score), Checks: checks}
+}
+
+// hasSchemaType reports whether JSON-LD data declares a @type, either at
+// the top level, in each array item or in its @graph
+func hasSchemaType(data interface{}) bool {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if _, ok := v["@type"]; ok {
+			return true
+		}
+		if graph, ok := v["@graph"]; ok {
+			return hasSchemaType(graph)
+		}
+		return false
+	case []interface{}:
+		if len(v) == 0 {
+			return false
+		}
+		for _, item := range v {
+			if !hasSchemaType(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func uniqueStrings(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	var out []string
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func formatSEOReport(report seoReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "SEO score for %s: %d/100 (%s)\n", report.URL, report.Score, report.Grade)
+	report.Checks.format(&b)
+	return b.String()
+}
+
+// AuditSEOTool scores a page's search engine metadata and structure
+type AuditSEOTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+}
+
+func NewAuditSEOTool(log *logger.Logger, mgr *browser.Manager) *AuditSEOTool {
+	return &AuditSEOTool{logger: log, browserMgr: mgr}
+}
+
+func (t *AuditSEOTool) Name() string {
+	return "audit_seo"
+}
+
+func (t *AuditSEOTool) Description() string {
+	return "Audit a page's SEO: title and meta description lengths, canonical link, H1 uniqueness and heading order, image alt coverage, noindex/nofollow, JSON-LD validity, lang, viewport and Open Graph tags, with a 0-100 score"
+}
+
+func (t *AuditSEOTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+		},
+	}
+}
+
+func (t *AuditSEOTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		raw, err := t.browserMgr.ExecuteScript(pageID, seoAuditScript)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to read page: %v", err))
+		}
+		var facts seoFacts
+		if err := decodeScriptValue(raw, &facts); err != nil {
+			return fail(fmt.Sprintf("Failed to read page: %v", err))
+		}
+
+		report := auditSEO(facts)
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: formatSEOReport(report),
+				Data: map[string]interface{}{
+					"page_id": pageID,
+					"report":  report,
+					"facts":   facts,
+				},
+			}},
+		}, nil
+	})
+}
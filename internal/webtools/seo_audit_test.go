@@ -0,0 +1,109 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+)
+
+func TestAuditSEOWellFormedPage(t *testing.T) {
+	report := auditSEO(seoFacts{
+		URL:            "https://shop.example.com/shoes",
+		Titles:         []string{"Running Shoes for Every Distance | Example Shop"},
+		Descriptions:   []string{"Lightweight running shoes for road and trail, with free returns and next-day delivery on all orders."},
+		Canonicals:     []string{"https://shop.example.com/shoes"},
+		H1s:            []string{"Running Shoes"},
+		HeadingLevels:  []int{1, 2, 3, 2},
+		Images:         4,
+		Lang:           "en",
+		Viewport:       true,
+		OpenGraph:      []string{"og:title", "og:description", "og:image"},
+		StructuredData: []string{`{"@context":"https://schema.org","@graph":[{"@type":"Product"}]}`},
+	})
+
+	if report.Score != 100 || report.Grade != "A" {
+		t.Errorf("score = %d (%s), want 100 (A):\n%s", report.Score, report.Grade, formatSEOReport(report))
+	}
+}
+
+func TestAuditSEOProblems(t *testing.T) {
+	report := auditSEO(seoFacts{
+		URL:            "https://shop.example.com/shoes",
+		Titles:         []string{"Shoes"},
+		Canonicals:     []string{"https://shop.example.com/a", "https://shop.example.com/b"},
+		Robots:         []string{"noindex, follow"},
+		H1s:            []string{"Shoes", "Sale"},
+		HeadingLevels:  []int{1, 3, 1, 4},
+		Images:         10,
+		ImagesNoAlt:    []string{"/hero.jpg"},
+		ImagesNoAltN:   5,
+		StructuredData: []string{`{"@type": "Product",}`},
+	})
+
+	for id, want := range map[string]string{
+		"title":             "warn",
+		"description":       "fail",
+		"canonical":         "fail",
+		"h1":                "warn",
+		"heading_structure": "warn",
+		"image_alt":         "fail",
+		"indexable":         "fail",
+		"structured_data":   "fail",
+		"lang":              "warn",
+		"viewport":          "warn",
+		"open_graph":        "warn",
+	} {
+		if got := report.Checks.status(id); got != want {
+			t.Errorf("%s = %q, want %q", id, got, want)
+		}
+	}
+	if report.Score >= 40 || report.Grade != "F" {
+		t.Errorf("score = %d (%s), expected a failing grade", report.Score, report.Grade)
+	}
+
+	text := formatSEOReport(report)
+	for _, want := range []string{
+		"[fail] indexable: Robots meta blocks indexing: noindex, follow",
+		"5 of 10 images have no alt attribute (50% coverage): /hero.jpg",
+		"Heading levels skipped: h1 -> h3, h1 -> h4",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+	// Failures are listed before warnings and passes
+	if strings.Index(text, "[fail]") > strings.Index(text, "[warn]") {
+		t.Errorf("failures should come first:\n%s", text)
+	}
+}
+
+func TestHasSchemaType(t *testing.T) {
+	for _, tc := range []struct {
+		data interface{}
+		want bool
+	}{
+		{map[string]interface{}{"@type": "Organization"}, true},
+		{map[string]interface{}{"name": "x"}, false},
+		{[]interface{}{map[string]interface{}{"@type": "A"}, map[string]interface{}{"@type": "B"}}, true},
+		{[]interface{}{map[string]interface{}{"@type": "A"}, map[string]interface{}{}}, false},
+		{map[string]interface{}{"@graph": []interface{}{map[string]interface{}{"@type": "A"}}}, true},
+	} {
+		if got := hasSchemaType(tc.data); got != tc.want {
+			t.Errorf("hasSchemaType(%v) = %v, want %v", tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestAuditSEONoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewAuditSEOTool(log, browser.NewManager(log, browser.Config{Headless: true}))
+
+	resp, err := tool.Execute(map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
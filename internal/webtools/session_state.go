@@ -0,0 +1,215 @@
+package webtools
+
+import (
+	"fmt"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// SaveSessionTool saves the browser's cookies and the current origin's web
+// storage under a name, like Playwright's storageState
+type SaveSessionTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	sessions   *sessionStore
+}
+
+// NewSaveSessionTool creates a save_session tool that keeps sessions in
+// sessionDir, the directory login uses
+func NewSaveSessionTool(log *logger.Logger, mgr *browser.Manager, sessionDir string) *SaveSessionTool {
+	return &SaveSessionTool{logger: log, browserMgr: mgr, sessions: newSessionStore(sessionDir)}
+}
+
+func (t *SaveSessionTool) Name() string {
+	return "save_session"
+}
+
+func (t *SaveSessionTool) Description() string {
+	return "Save every browser cookie plus the localStorage and sessionStorage of the page's origin to a named JSON file, so a signed-in session can be resumed later with load_session"
+}
+
+func (t *SaveSessionTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Session name; also usable as login's session_name",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified). Its origin's storage is saved",
+			},
+			"overwrite": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Replace an existing session with the same name",
+				"default":     true,
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
+func (t *SaveSessionTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		name, _ := args["name"].(string)
+		if !recipeNamePattern.MatchString(name) {
+			return fail("name is required and may only contain letters, digits, '-' and '_'")
+		}
+		overwrite := true
+		if v, ok := args["overwrite"].(bool); ok {
+			overwrite = v
+		}
+		if !overwrite && t.sessions.exists(name) {
+			return fail(fmt.Sprintf("Session %q already exists; pass overwrite=true to replace it", name))
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		state, err := t.browserMgr.CaptureSession(pageID)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to capture session: %v", err))
+		}
+		path, err := t.sessions.save(name, state)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to save session: %v", err))
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Saved session %q: %d cookies, %d localStorage and %d sessionStorage items for %s", name, len(state.Cookies), len(state.LocalStorage), len(state.SessionStorage), state.Origin),
+				Data: map[string]interface{}{
+					"name":                  name,
+					"path":                  path,
+					"url":                   state.URL,
+					"origin":                state.Origin,
+					"cookies":               len(state.Cookies),
+					"local_storage_items":   len(state.LocalStorage),
+					"session_storage_items": len(state.SessionStorage),
+				},
+			}},
+		}, nil
+	})
+}
+
+// LoadSessionTool restores a session written by save_session or login
+type LoadSessionTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	sessions   *sessionStore
+}
+
+// NewLoadSessionTool creates a load_session tool reading from sessionDir
+func NewLoadSessionTool(log *logger.Logger, mgr *browser.Manager, sessionDir string) *LoadSessionTool {
+	return &LoadSessionTool{logger: log, browserMgr: mgr, sessions: newSessionStore(sessionDir)}
+}
+
+func (t *LoadSessionTool) Name() string {
+	return "load_session"
+}
+
+func (t *LoadSessionTool) Description() string {
+	return "Restore cookies, localStorage and sessionStorage saved by save_session or login. The page is taken to the session's origin so storage can be restored, then to the URL the session was saved from"
+}
+
+func (t *LoadSessionTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name the session was saved under",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page, or opens one, if not specified)",
+			},
+			"navigate": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Open the session's URL after loading. Without it only cookies are restored unless the page is already on the origin",
+				"default":     true,
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
+func (t *LoadSessionTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		name, _ := args["name"].(string)
+		if name == "" {
+			return fail("name is required")
+		}
+		state, err := t.sessions.load(name)
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		navigate := true
+		if v, ok := args["navigate"].(bool); ok {
+			navigate = v
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+		}
+		if pageID == "" {
+			if !navigate || state.Origin == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+			_, pageID, err = t.browserMgr.NewPage(state.Origin)
+			if err != nil {
+				return fail(fmt.Sprintf("Failed to open %s: %v", state.Origin, err))
+			}
+		}
+
+		restored, err := applySession(t.browserMgr, pageID, state, navigate)
+		if err != nil {
+			return fail(fmt.Sprintf("Loading session %q failed: %v", name, err))
+		}
+
+		text := fmt.Sprintf("Loaded session %q: %d cookies for %s (saved %s)", name, len(state.Cookies), state.Origin, state.CapturedAt.Format(time.RFC3339))
+		text += storageSummary(state, restored)
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"name":             name,
+					"page_id":          pageID,
+					"origin":           state.Origin,
+					"url":              state.URL,
+					"cookies":          len(state.Cookies),
+					"storage_restored": restored,
+					"captured_at":      state.CapturedAt,
+				},
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,67 @@
+package webtools
+
+import (
+	"strings"
+	"testing"
+
+	"rodmcp/internal/browser"
+	"rodmcp/pkg/types"
+)
+
+func TestSessionStoreKeepsSessionStorage(t *testing.T) {
+	store := newSessionStore(t.TempDir())
+	state := &browser.SessionState{
+		URL:            "https://app.example.com/inbox",
+		Origin:         "https://app.example.com",
+		SessionStorage: map[string]string{"csrf": "t0k3n"},
+	}
+	if !state.HasStorage() {
+		t.Error("sessionStorage alone should count as storage")
+	}
+	if _, err := store.save("app", state); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := store.load("app")
+	if err != nil || loaded.SessionStorage["csrf"] != "t0k3n" || loaded.LocalStorage != nil {
+		t.Errorf("load = %+v, %v", loaded, err)
+	}
+
+	if got := storageSummary(loaded, true); !strings.Contains(got, "0 localStorage and 1 sessionStorage") {
+		t.Errorf("storageSummary = %q", got)
+	}
+	if got := storageSummary(&browser.SessionState{}, false); got != "" {
+		t.Errorf("storageSummary without storage = %q", got)
+	}
+}
+
+func TestSessionStateToolValidation(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := newSessionStore(dir).save("taken", &browser.SessionState{}); err != nil {
+		t.Fatal(err)
+	}
+	save := NewSaveSessionTool(createTestLogger(t), nil, dir)
+	load := NewLoadSessionTool(createTestLogger(t), nil, dir)
+
+	cases := []struct {
+		name string
+		tool interface {
+			Execute(map[string]interface{}) (*types.CallToolResponse, error)
+		}
+		args map[string]interface{}
+		want string
+	}{
+		{"save without name", save, map[string]interface{}{}, "name is required"},
+		{"save bad name", save, map[string]interface{}{"name": "../x"}, "name is required"},
+		{"save existing", save, map[string]interface{}{"name": "taken", "overwrite": false}, "already exists"},
+		{"load without name", load, map[string]interface{}{}, "name is required"},
+		{"load unknown", load, map[string]interface{}{"name": "missing"}, "no saved session"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
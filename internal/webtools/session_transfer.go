@@ -0,0 +1,402 @@
+package webtools
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Session export files are a small JSON envelope around an AES-256-GCM
+// encrypted SessionState. The key is derived from a passphrase the server
+// is started with, so the same passphrase must be configured on both ends.
+const (
+	sessionExportFormat     = "rodmcp-session"
+	sessionExportVersion    = 1
+	sessionExportIterations = 600000
+)
+
+type sessionEnvelope struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	Origin     string `json:"origin"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// sessionAAD binds the ciphertext to the envelope fields that are stored in
+// the clear, so the origin cannot be swapped without failing decryption
+func sessionAAD(env *sessionEnvelope) []byte {
+	return []byte(fmt.Sprintf("%s/%d/%s", env.Format, env.Version, env.Origin))
+}
+
+func sessionCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptSession seals a session for writing to disk
+func encryptSession(state *browser.SessionState, passphrase string) ([]byte, error) {
+	plaintext, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+	env := &sessionEnvelope{
+		Format:     sessionExportFormat,
+		Version:    sessionExportVersion,
+		Origin:     state.Origin,
+		KDF:        "pbkdf2-sha256",
+		Iterations: sessionExportIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := sessionCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	env.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return nil, err
+	}
+	env.Ciphertext = aead.Seal(nil, env.Nonce, plaintext, sessionAAD(env))
+	return json.MarshalIndent(env, "", "  ")
+}
+
+// decryptSession opens a file written by encryptSession
+func decryptSession(data []byte, passphrase string) (*browser.SessionState, error) {
+	var env sessionEnvelope
+	if err := json.Unmarshal(data, &env); err != nil || env.Format != sessionExportFormat {
+		return nil, errors.New("not a rodmcp session export")
+	}
+	if env.Version != sessionExportVersion || env.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported session export version %d (%s)", env.Version, env.KDF)
+	}
+	if env.Iterations < 10000 || env.Iterations > 10*sessionExportIterations {
+		return nil, fmt.Errorf("session export has an implausible iteration count %d", env.Iterations)
+	}
+	aead, err := sessionCipher(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, errors.New("session export is corrupt")
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, sessionAAD(&env))
+	if err != nil {
+		return nil, errors.New("cannot decrypt session export: wrong passphrase or the file was modified")
+	}
+	var state browser.SessionState
+	if err := json.Unmarshal(plaintext, &state); err != nil {
+		return nil, fmt.Errorf("session export is corrupt: %w", err)
+	}
+	return &state, nil
+}
+
+// cookieMatchesHost reports whether a cookie set for domain would be sent
+// to host, following the RFC 6265 domain-match rules
+func cookieMatchesHost(domain, host string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	host = strings.ToLower(host)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// restrictSessionToOrigin drops cookies that the origin would never see,
+// so an export carries one site's sign-in rather than the whole profile
+func restrictSessionToOrigin(state *browser.SessionState) error {
+	u, err := url.Parse(state.Origin)
+	if err != nil || u.Hostname() == "" {
+		return fmt.Errorf("page has no web origin (%q); navigate to the site first", state.Origin)
+	}
+	host := u.Hostname()
+	kept := state.Cookies[:0]
+	for _, c := range state.Cookies {
+		if cookieMatchesHost(c.Domain, host) {
+			kept = append(kept, c)
+		}
+	}
+	state.Cookies = kept
+	return nil
+}
+
+// resolveFileArg resolves the path argument and checks it against the file
+// access rules for operation ("read" or "write")
+func resolveFileArg(validator *PathValidator, args map[string]interface{}, operation string) (string, error) {
+	pathStr, _ := args["path"].(string)
+	if pathStr == "" {
+		return "", errors.New("path is required")
+	}
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+	if err := validator.ValidatePath(cleanPath, operation); err != nil {
+		return "", fmt.Errorf("file access denied: %w", err)
+	}
+	return cleanPath, nil
+}
+
+const noSessionKeyMessage = "No session passphrase is configured (set RODMCP_SESSION_KEY or start the server with --session-key-file)"
+
+// ExportSessionTool writes one origin's cookies and localStorage to an
+// encrypted file
+type ExportSessionTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
+	passphrase string
+}
+
+// NewExportSessionTool creates an export_session tool. Exports are refused
+// while passphrase is empty.
+func NewExportSessionTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator, passphrase string) *ExportSessionTool {
+	return &ExportSessionTool{logger: log, browserMgr: mgr, validator: validator, passphrase: passphrase}
+}
+
+func (t *ExportSessionTool) Name() string {
+	return "export_session"
+}
+
+func (t *ExportSessionTool) Description() string {
+	return "Export the cookies, localStorage and sessionStorage of the page's current origin to an encrypted file, so a signed-in session can be loaded into another rodmcp instance with import_session"
+}
+
+func (t *ExportSessionTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "File to write; must be inside the allowed paths",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified). The page must be on the origin to export",
+			},
+			"overwrite": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Replace an existing file",
+				"default":     false,
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+func (t *ExportSessionTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		if t.passphrase == "" {
+			return fail(noSessionKeyMessage)
+		}
+		path, err := resolveFileArg(t.validator, args, "write")
+		if err != nil {
+			return fail(err.Error())
+		}
+		if overwrite, _ := args["overwrite"].(bool); !overwrite {
+			if _, err := os.Stat(path); err == nil {
+				return fail(fmt.Sprintf("%s already exists; pass overwrite=true to replace it", path))
+			}
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+
+		state, err := t.browserMgr.CaptureSession(pageID)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to capture session: %v", err))
+		}
+		if err := restrictSessionToOrigin(state); err != nil {
+			return fail(err.Error())
+		}
+
+		data, err := encryptSession(state, t.passphrase)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to encrypt session: %v", err))
+		}
+		if err := t.validator.ValidateFileSize(int64(len(data))); err != nil {
+			return fail(err.Error())
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return fail(fmt.Sprintf("Failed to create directory: %v", err))
+		}
+		tmp := path + ".tmp"
+		if err := os.WriteFile(tmp, data, 0600); err != nil {
+			return fail(fmt.Sprintf("Failed to write %s: %v", path, err))
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			os.Remove(tmp)
+			return fail(fmt.Sprintf("Failed to write %s: %v", path, err))
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Exported %d cookies, %d localStorage and %d sessionStorage items for %s to %s", len(state.Cookies), len(state.LocalStorage), len(state.SessionStorage), state.Origin, path),
+				Data: map[string]interface{}{
+					"path":                  path,
+					"origin":                state.Origin,
+					"cookies":               len(state.Cookies),
+					"local_storage_items":   len(state.LocalStorage),
+					"session_storage_items": len(state.SessionStorage),
+				},
+			}},
+		}, nil
+	})
+}
+
+// ImportSessionTool loads a file written by export_session into the browser
+type ImportSessionTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
+	passphrase string
+}
+
+// NewImportSessionTool creates an import_session tool. Imports are refused
+// while passphrase is empty.
+func NewImportSessionTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator, passphrase string) *ImportSessionTool {
+	return &ImportSessionTool{logger: log, browserMgr: mgr, validator: validator, passphrase: passphrase}
+}
+
+func (t *ImportSessionTool) Name() string {
+	return "import_session"
+}
+
+func (t *ImportSessionTool) Description() string {
+	return "Load cookies and web storage from a file written by export_session. The page is taken to the session's origin so storage can be restored, then to the URL the session was exported from"
+}
+
+func (t *ImportSessionTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"path": map[string]interface{}{
+				"type":        "string",
+				"description": "Session file written by export_session",
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page, or opens one, if not specified)",
+			},
+			"navigate": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Open the session's URL after importing. Without it only cookies are restored unless the page is already on the origin",
+				"default":     true,
+			},
+		},
+		Required: []string{"path"},
+	}
+}
+
+func (t *ImportSessionTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		if t.passphrase == "" {
+			return fail(noSessionKeyMessage)
+		}
+		path, err := resolveFileArg(t.validator, args, "read")
+		if err != nil {
+			return fail(err.Error())
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fail(fmt.Sprintf("Cannot read %s: %v", path, err))
+		}
+		if err := t.validator.ValidateFileSize(info.Size()); err != nil {
+			return fail(err.Error())
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fail(fmt.Sprintf("Cannot read %s: %v", path, err))
+		}
+		state, err := decryptSession(data, t.passphrase)
+		if err != nil {
+			return fail(err.Error())
+		}
+
+		navigate := true
+		if v, ok := args["navigate"].(bool); ok {
+			navigate = v
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+		}
+		if pageID == "" {
+			if !navigate {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+			_, pageID, err = t.browserMgr.NewPage(state.Origin)
+			if err != nil {
+				return fail(fmt.Sprintf("Failed to open %s: %v", state.Origin, err))
+			}
+		}
+
+		restored, err := applySession(t.browserMgr, pageID, state, navigate)
+		if err != nil {
+			return fail(fmt.Sprintf("Import failed: %v", err))
+		}
+
+		text := fmt.Sprintf("Imported %d cookies for %s (exported %s)", len(state.Cookies), state.Origin, state.CapturedAt.Format(time.RFC3339))
+		text += storageSummary(state, restored)
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"page_id":                pageID,
+					"origin":                 state.Origin,
+					"url":                    state.URL,
+					"cookies":                len(state.Cookies),
+					"local_storage_restored": restored,
+					"captured_at":            state.CapturedAt,
+				},
+			}},
+		}, nil
+	})
+}
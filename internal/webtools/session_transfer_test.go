@@ -0,0 +1,114 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+
+	"rodmcp/internal/browser"
+	"rodmcp/pkg/types"
+)
+
+func TestSessionEncryptionRoundTrip(t *testing.T) {
+	state := &browser.SessionState{
+		URL:          "https://app.example.com/home",
+		Origin:       "https://app.example.com",
+		Cookies:      []*proto.NetworkCookie{{Name: "sid", Value: "secret-cookie", Domain: ".example.com"}},
+		LocalStorage: map[string]string{"token": "abc"},
+	}
+	data, err := encryptSession(state, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret-cookie")) || bytes.Contains(data, []byte("abc")) {
+		t.Error("Export contains session values in the clear")
+	}
+
+	loaded, err := decryptSession(data, "correct horse")
+	if err != nil || loaded.Cookies[0].Value != "secret-cookie" || loaded.LocalStorage["token"] != "abc" {
+		t.Errorf("decrypt = %+v, %v", loaded, err)
+	}
+	if _, err := decryptSession(data, "wrong"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("Expected wrong passphrase error, got %v", err)
+	}
+
+	var env sessionEnvelope
+	json.Unmarshal(data, &env)
+	env.Origin = "https://evil.example"
+	tampered, _ := json.Marshal(env)
+	if _, err := decryptSession(tampered, "correct horse"); err == nil {
+		t.Error("Expected tampered origin to fail decryption")
+	}
+	if _, err := decryptSession([]byte(`{"cookies": []}`), "correct horse"); err == nil {
+		t.Error("Expected plain JSON to be rejected")
+	}
+}
+
+func TestRestrictSessionToOrigin(t *testing.T) {
+	state := &browser.SessionState{
+		Origin: "https://app.example.com",
+		Cookies: []*proto.NetworkCookie{
+			{Name: "parent", Domain: ".example.com"},
+			{Name: "host", Domain: "app.example.com"},
+			{Name: "sibling", Domain: "other.example.com"},
+			{Name: "lookalike", Domain: "badexample.com"},
+			{Name: "tracker", Domain: ".tracker.net"},
+		},
+	}
+	if err := restrictSessionToOrigin(state); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range state.Cookies {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "parent,host" {
+		t.Errorf("kept cookies = %v", names)
+	}
+
+	if err := restrictSessionToOrigin(&browser.SessionState{Origin: "null"}); err == nil {
+		t.Error("Expected error for a page without an origin")
+	}
+}
+
+func TestSessionTransferToolValidation(t *testing.T) {
+	dir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths: []string{dir},
+		MaxFileSize:  1024 * 1024,
+	})
+
+	existing := filepath.Join(dir, "existing.session")
+	os.WriteFile(existing, []byte("x"), 0600)
+
+	export := NewExportSessionTool(createTestLogger(t), nil, validator, "key")
+	imp := NewImportSessionTool(createTestLogger(t), nil, validator, "key")
+	cases := []struct {
+		name string
+		tool interface {
+			Execute(map[string]interface{}) (*types.CallToolResponse, error)
+		}
+		args map[string]interface{}
+		want string
+	}{
+		{"export without path", export, map[string]interface{}{}, "path is required"},
+		{"export outside allowed paths", export, map[string]interface{}{"path": "/etc/rodmcp.session"}, "access denied"},
+		{"export over existing", export, map[string]interface{}{"path": existing}, "overwrite"},
+		{"import missing file", imp, map[string]interface{}{"path": filepath.Join(dir, "none.session")}, "Cannot read"},
+		{"import non-export", imp, map[string]interface{}{"path": existing}, "not a rodmcp session export"},
+		{"no passphrase", NewImportSessionTool(createTestLogger(t), nil, validator, ""), map[string]interface{}{"path": existing}, "RODMCP_SESSION_KEY"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := tc.tool.Execute(tc.args)
+			if err != nil || !resp.IsError || !strings.Contains(resp.Content[0].Text, tc.want) {
+				t.Errorf("got %+v, %v; want error containing %q", resp, err, tc.want)
+			}
+		})
+	}
+}
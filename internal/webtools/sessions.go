@@ -0,0 +1,123 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"rodmcp/internal/browser"
+)
+
+// defaultSessionDir is where signed-in sessions are kept when no
+// --session-dir is configured, relative to the working directory
+const defaultSessionDir = "sessions"
+
+// sessionStore keeps captured browser sessions as one JSON file each. The
+// files hold live cookies, so they are readable by the owner only.
+type sessionStore struct {
+	dir string
+}
+
+func newSessionStore(dir string) *sessionStore {
+	if dir == "" {
+		dir = defaultSessionDir
+	}
+	return &sessionStore{dir: dir}
+}
+
+func (s *sessionStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *sessionStore) exists(name string) bool {
+	_, err := os.Stat(s.path(name))
+	return err == nil
+}
+
+// save writes a session under name, replacing any previous one
+func (s *sessionStore) save(name string, state *browser.SessionState) (string, error) {
+	if !recipeNamePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid session name %q", name)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create session directory: %w", err)
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := s.path(name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// load reads the named session
+func (s *sessionStore) load(name string) (*browser.SessionState, error) {
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid session name %q", name)
+	}
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no saved session named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state browser.SessionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("session %q is corrupt: %w", name, err)
+	}
+	return &state, nil
+}
+
+// applySession restores a session on a page and reports whether its web
+// storage was restored. With navigate, the page is first taken to the
+// session's origin when storage needs restoring, then to the session's URL.
+func applySession(mgr *browser.Manager, pageID string, state *browser.SessionState, navigate bool) (bool, error) {
+	restored, err := mgr.RestoreSession(pageID, state)
+	if err != nil {
+		return false, fmt.Errorf("failed to restore session: %w", err)
+	}
+	if !navigate {
+		return restored, nil
+	}
+
+	// Web storage can only be written once the page is on its origin
+	if !restored && state.HasStorage() {
+		if err := mgr.NavigateExistingPage(pageID, state.Origin); err != nil {
+			return false, fmt.Errorf("failed to open %s: %w", state.Origin, err)
+		}
+		if restored, err = mgr.RestoreSession(pageID, state); err != nil {
+			return false, fmt.Errorf("failed to restore session: %w", err)
+		}
+	}
+	target := state.URL
+	if target == "" {
+		target = state.Origin
+	}
+	if err := mgr.NavigateExistingPage(pageID, target); err != nil {
+		return restored, fmt.Errorf("session restored but opening %s failed: %w", target, err)
+	}
+	return restored, nil
+}
+
+// storageSummary describes how much web storage a session restored, for
+// tool responses
+func storageSummary(state *browser.SessionState, restored bool) string {
+	if !state.HasStorage() {
+		return ""
+	}
+	if !restored {
+		return "; web storage not restored because the page is not on the session's origin"
+	}
+	return fmt.Sprintf("; restored %d localStorage and %d sessionStorage items", len(state.LocalStorage), len(state.SessionStorage))
+}
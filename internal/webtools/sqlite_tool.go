@@ -0,0 +1,303 @@
+package webtools
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+const (
+	defaultSQLiteMaxRows = 1000
+	maxSQLiteMaxRows     = 10000
+	sqliteQueryTimeout   = 30 * time.Second
+)
+
+// SQLiteQueryTool runs parameterized queries against SQLite files under allowed paths
+type SQLiteQueryTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewSQLiteQueryTool(log *logger.Logger, validator *PathValidator) *SQLiteQueryTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &SQLiteQueryTool{
+		logger:    log,
+		validator: validator,
+	}
+}
+
+func (t *SQLiteQueryTool) Name() string {
+	return "sqlite_query"
+}
+
+func (t *SQLiteQueryTool) Description() string {
+	return "Run a parameterized SQL query against a SQLite database file under an allowed path (read-only unless allow_writes is set)"
+}
+
+func (t *SQLiteQueryTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"database": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the SQLite database file",
+				"examples":    []string{"scrape.db", "data/results.sqlite"},
+			},
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "SQL statement to run. Use ? placeholders for values rather than string formatting",
+				"examples": []string{
+					"SELECT url, title FROM pages WHERE status = ? LIMIT 20",
+					"INSERT OR IGNORE INTO pages (url, title) VALUES (?, ?)",
+				},
+			},
+			"params": map[string]interface{}{
+				"type":        "array",
+				"description": "Positional values bound to ? placeholders",
+				"items":       map[string]interface{}{},
+			},
+			"allow_writes": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Open the database read-write (and create it if missing). Without this the database is opened read-only and write statements fail",
+				"default":     false,
+			},
+			"max_rows": map[string]interface{}{
+				"type":        "number",
+				"description": "Maximum number of rows to return from a query",
+				"default":     defaultSQLiteMaxRows,
+				"minimum":     1,
+				"maximum":     maxSQLiteMaxRows,
+			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths)",
+			},
+		},
+		Required: []string{"database", "query"},
+	}
+}
+
+func (t *SQLiteQueryTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		resp, err := t.run(args)
+		duration := time.Since(start).Milliseconds()
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, duration)
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("SQLite error: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, duration)
+		return resp, nil
+	})
+}
+
+func (t *SQLiteQueryTool) run(args map[string]interface{}) (*types.CallToolResponse, error) {
+	dbPath, ok := args["database"].(string)
+	if !ok || dbPath == "" {
+		return nil, fmt.Errorf("database must be a non-empty string")
+	}
+	query, ok := args["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query must be a non-empty string")
+	}
+
+	allowWrites, _ := args["allow_writes"].(bool)
+	params, _ := args["params"].([]interface{})
+
+	maxRows := defaultSQLiteMaxRows
+	if val, ok := args["max_rows"].(float64); ok && val >= 1 {
+		maxRows = int(val)
+	}
+	if maxRows > maxSQLiteMaxRows {
+		maxRows = maxSQLiteMaxRows
+	}
+
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(dbPath, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("database access denied: %w", err)
+	}
+	operation := "read"
+	if allowWrites {
+		operation = "write"
+	}
+	if err := t.validator.ValidatePath(cleanPath, operation); err != nil {
+		return nil, fmt.Errorf("database access denied: %w", err)
+	}
+	if !allowWrites {
+		if _, err := os.Stat(cleanPath); err != nil {
+			return nil, fmt.Errorf("database %s is not accessible: %w", cleanPath, err)
+		}
+	}
+
+	db, err := sql.Open("sqlite", sqliteDSN(cleanPath, allowWrites))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteQueryTimeout)
+	defer cancel()
+
+	// ATTACH would reach database files the path checks above never saw,
+	// in either mode, so the connection may attach none
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer conn.Close()
+	if _, err := sqlite.Limit(conn, sqlite3.SQLITE_LIMIT_ATTACHED, 0); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if allowWrites && !isSQLiteReadStatement(query) {
+		result, err := conn.ExecContext(ctx, query, params...)
+		if err != nil {
+			return nil, err
+		}
+		affected, _ := result.RowsAffected()
+		lastID, _ := result.LastInsertId()
+
+		t.logger.WithComponent("tools").Info("SQLite statement executed",
+			zap.String("database", cleanPath),
+			zap.Int64("rows_affected", affected))
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Statement executed: %d row(s) affected", affected),
+				Data: map[string]interface{}{
+					"database":       cleanPath,
+					"rows_affected":  affected,
+					"last_insert_id": lastID,
+				},
+			}},
+		}, nil
+	}
+
+	rows, err := conn.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []map[string]interface{}
+	truncated := false
+	for rows.Next() {
+		if len(results) >= maxRows {
+			truncated = true
+			break
+		}
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(columns))
+		for i, c := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[c] = string(b)
+			} else {
+				row[c] = values[i]
+			}
+		}
+		results = append(results, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	t.logger.WithComponent("tools").Info("SQLite query executed",
+		zap.String("database", cleanPath),
+		zap.Int("rows", len(results)),
+		zap.Bool("truncated", truncated))
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d row(s)", len(results))
+	if truncated {
+		fmt.Fprintf(&text, " (truncated at max_rows=%d)", maxRows)
+	}
+	text.WriteString("\n")
+	if len(columns) > 0 {
+		text.WriteString(strings.Join(columns, " | ") + "\n")
+		for i, row := range results {
+			if i >= 50 {
+				fmt.Fprintf(&text, "... %d more row(s) in data\n", len(results)-i)
+				break
+			}
+			cells := make([]string, len(columns))
+			for j, c := range columns {
+				cells[j] = fmt.Sprintf("%v", row[c])
+			}
+			text.WriteString(strings.Join(cells, " | ") + "\n")
+		}
+	}
+
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: text.String(),
+			Data: map[string]interface{}{
+				"database":  cleanPath,
+				"columns":   columns,
+				"rows":      results,
+				"row_count": len(results),
+				"truncated": truncated,
+			},
+		}},
+	}, nil
+}
+
+// sqliteDSN builds a connection string that enforces read-only access unless writes are allowed
+func sqliteDSN(path string, allowWrites bool) string {
+	values := url.Values{}
+	values.Add("_pragma", "busy_timeout(5000)")
+	if allowWrites {
+		values.Set("mode", "rwc")
+	} else {
+		values.Set("mode", "ro")
+		values.Add("_pragma", "query_only(1)")
+	}
+	return "file:" + (&url.URL{Path: path}).EscapedPath() + "?" + values.Encode()
+}
+
+// isSQLiteReadStatement reports whether a statement returns rows rather than modifying data
+func isSQLiteReadStatement(query string) bool {
+	trimmed := strings.TrimSpace(strings.ToUpper(query))
+	for _, prefix := range []string{"SELECT", "WITH", "EXPLAIN", "VALUES"} {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	// PRAGMA reads return rows; PRAGMA assignments do not
+	return strings.HasPrefix(trimmed, "PRAGMA") && !strings.Contains(trimmed, "=")
+}
@@ -0,0 +1,118 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteQueryTool(t *testing.T) {
+	dir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{dir},
+		RestrictToWorkingDir: false,
+	})
+	tool := NewSQLiteQueryTool(createTestLogger(t), validator)
+
+	// Read-only mode must not create a missing database
+	resp, _ := tool.Execute(map[string]interface{}{
+		"database": "scrape.db",
+		"cwd":      dir,
+		"query":    "SELECT 1",
+	})
+	if !resp.IsError {
+		t.Fatal("Expected read-only query against missing database to fail")
+	}
+
+	exec := func(query string, params ...interface{}) {
+		t.Helper()
+		resp, err := tool.Execute(map[string]interface{}{
+			"database":     "scrape.db",
+			"cwd":          dir,
+			"query":        query,
+			"params":       params,
+			"allow_writes": true,
+		})
+		if err != nil || resp.IsError {
+			t.Fatalf("Statement %q failed: %v %v", query, err, resp)
+		}
+	}
+	exec("CREATE TABLE pages (url TEXT PRIMARY KEY, title TEXT, words INTEGER)")
+	exec("INSERT INTO pages VALUES (?, ?, ?)", "https://a.example", "A", float64(120))
+	exec("INSERT INTO pages VALUES (?, ?, ?)", "https://b.example", "B", float64(80))
+	exec("INSERT OR IGNORE INTO pages VALUES (?, ?, ?)", "https://a.example", "dup", float64(1))
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"database": filepath.Join(dir, "scrape.db"),
+		"query":    "SELECT url, title FROM pages WHERE words > ? ORDER BY url",
+		"params":   []interface{}{float64(50)},
+		"max_rows": float64(1),
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("Query failed: %v %v", err, resp)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	rows := data["rows"].([]map[string]interface{})
+	if len(rows) != 1 || rows[0]["title"] != "A" || data["truncated"] != true {
+		t.Errorf("Expected one truncated row for A, got %v", data)
+	}
+
+	// Writes are rejected without allow_writes
+	resp, _ = tool.Execute(map[string]interface{}{
+		"database": filepath.Join(dir, "scrape.db"),
+		"query":    "DELETE FROM pages",
+	})
+	if !resp.IsError {
+		t.Error("Expected write in read-only mode to fail")
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{
+		"database":     filepath.Join(t.TempDir(), "outside.db"),
+		"query":        "SELECT 1",
+		"allow_writes": true,
+	})
+	if !resp.IsError {
+		t.Error("Expected database outside allowed paths to be rejected")
+	}
+
+	// ATTACH cannot reach past the allowed paths in either mode, whether to
+	// read a database there or to create one
+	outside := t.TempDir()
+	existing := filepath.Join(outside, "secret.db")
+	if err := os.WriteFile(existing, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(outside, "created.db")
+	for _, allowWrites := range []bool{false, true} {
+		for _, path := range []string{existing, created} {
+			resp, _ = tool.Execute(map[string]interface{}{
+				"database":     filepath.Join(dir, "scrape.db"),
+				"query":        "ATTACH DATABASE ? AS other",
+				"params":       []interface{}{path},
+				"allow_writes": allowWrites,
+			})
+			if !resp.IsError {
+				t.Errorf("allow_writes=%v: expected ATTACH of %s to be refused", allowWrites, path)
+			}
+		}
+	}
+	if _, err := os.Stat(created); !os.IsNotExist(err) {
+		t.Errorf("Expected ATTACH not to create %s, got %v", created, err)
+	}
+}
+
+func TestIsSQLiteReadStatement(t *testing.T) {
+	cases := map[string]bool{
+		"select * from t":                        true,
+		"  WITH x AS (SELECT 1) SELECT * FROM x": true,
+		"PRAGMA table_info(t)":                   true,
+		"PRAGMA journal_mode=WAL":                false,
+		"INSERT INTO t VALUES (1)":               false,
+		"update t set a = 1":                     false,
+	}
+	for query, expected := range cases {
+		if got := isSQLiteReadStatement(query); got != expected {
+			t.Errorf("isSQLiteReadStatement(%q) = %v, want %v", query, got, expected)
+		}
+	}
+}
@@ -0,0 +1,541 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Table extraction option values
+const (
+	spanFillRepeat = "repeat" // every grid position a spanning cell covers gets its text
+	spanFillEmpty  = "empty"  // only the cell's first position gets its text
+
+	footerInclude  = "include"  // footer rows follow the body rows
+	footerExclude  = "exclude"  // footer rows are dropped
+	footerSeparate = "separate" // footer rows are returned on their own
+
+	nestedText    = "text"    // nested table text stays in the cell text
+	nestedSkip    = "skip"    // nested tables are left out of the cell text
+	nestedExtract = "extract" // nested tables are returned as their own rows
+)
+
+// tableCell is one position in the expanded table grid
+type tableCell struct {
+	Text       string     `json:"text"`
+	Link       string     `json:"link,omitempty"`
+	Image      string     `json:"image,omitempty"`
+	InputValue *string    `json:"input_value,omitempty"`
+	Nested     [][]string `json:"nested,omitempty"`
+	Spanned    bool       `json:"spanned,omitempty"` // covered by a colspan/rowspan from another cell
+}
+
+// tableGrid is a table laid out as rows of equal-width cells
+type tableGrid struct {
+	Error    string          `json:"error,omitempty"`
+	Rows     [][]tableCell   `json:"rows"`
+	Sections []string        `json:"sections"` // head, body or foot for each row
+	Total    int             `json:"total"`    // rows in the whole table
+	Next     int             `json:"next"`     // first row of the following slice
+	Carry    json.RawMessage `json:"carry"`    // rowspans open at the end of this slice
+}
+
+// tableOptions controls how a tableGrid is shaped into output
+type tableOptions struct {
+	includeHeaders bool
+	format         string
+	skipEmptyRows  bool
+	maxRows        int // 0 for no limit
+	columnFilter   []interface{}
+	headerRow      int
+	headerRows     int
+	spanFill       string
+	footer         string
+	nested         string
+}
+
+// validate rejects option values the extraction doesn't understand
+func (o tableOptions) validate() error {
+	checks := []struct {
+		name    string
+		value   string
+		allowed []string
+	}{
+		{"output_format", o.format, []string{"array", "objects", "csv"}},
+		{"span_fill", o.spanFill, []string{spanFillRepeat, spanFillEmpty}},
+		{"footer_rows", o.footer, []string{footerInclude, footerExclude, footerSeparate}},
+		{"nested_tables", o.nested, []string{nestedText, nestedSkip, nestedExtract}},
+	}
+	for _, c := range checks {
+		valid := false
+		for _, a := range c.allowed {
+			valid = valid || c.value == a
+		}
+		if !valid {
+			return fmt.Errorf("%s must be one of: %s", c.name, strings.Join(c.allowed, ", "))
+		}
+	}
+	return nil
+}
+
+// tableGridScript lays a table out on a grid, expanding colspan and rowspan
+// so every row has a cell for every column. Rows come back in thead, tbody,
+// tfoot order, and rows of nested tables are never mixed into the outer table.
+// It returns limit rows starting at start (all rows when limit is negative);
+// carry holds rowspans still open at the end of the previous slice.
+const tableGridScript = `
+	const selector = %s;
+	const nestedMode = %s;
+	const start = %d;
+	const limit = %d;
+	const carry = %s;
+	const root = document.querySelector(selector);
+	if (!root) {
+		return { error: 'Table not found with selector: ' + selector };
+	}
+
+	const sectionOf = tag => tag === 'THEAD' ? 'head' : tag === 'TFOOT' ? 'foot' : 'body';
+	let entries;
+	if (root.tagName === 'THEAD' || root.tagName === 'TBODY' || root.tagName === 'TFOOT') {
+		entries = Array.from(root.rows).map(row => ({ row, section: sectionOf(root.tagName) }));
+	} else {
+		const table = root.tagName === 'TABLE' ? root : root.querySelector('table');
+		if (!table) {
+			return { error: 'No table found with selector: ' + selector };
+		}
+		const all = Array.from(table.rows).map(row => ({ row, section: sectionOf(row.parentElement.tagName) }));
+		entries = ['head', 'body', 'foot'].flatMap(s => all.filter(e => e.section === s));
+	}
+	if (entries.length === 0) {
+		return { error: 'No rows found in table' };
+	}
+
+	const clean = text => (text || '').replace(/\s+/g, ' ').trim();
+	const ownTables = cell => Array.from(cell.querySelectorAll('table'))
+		.filter(t => t.parentElement.closest('td, th') === cell);
+
+	const cellData = cell => {
+		const nested = ownTables(cell);
+		let text;
+		if (nestedMode === 'text' || nested.length === 0) {
+			text = clean(cell.textContent);
+		} else {
+			const copy = cell.cloneNode(true);
+			copy.querySelectorAll('table').forEach(t => t.remove());
+			text = clean(copy.textContent);
+		}
+
+		const data = { text };
+		const link = cell.querySelector('a');
+		const img = cell.querySelector('img');
+		const input = cell.querySelector('input, select, textarea');
+		if (link && link.href) data.link = link.href;
+		if (img && img.src) data.image = img.src;
+		if (input) data.input_value = input.value;
+		if (nestedMode === 'extract' && nested.length > 0) {
+			data.nested = nested.flatMap(t => Array.from(t.rows).map(r => Array.from(r.cells).map(c => clean(c.textContent))));
+		}
+		return data;
+	};
+
+	// index one past the last row of each row's thead/tbody/tfoot
+	const groupEnd = new Array(entries.length);
+	for (let i = entries.length - 1; i >= 0; i--) {
+		groupEnd[i] = i + 1 < entries.length && entries[i + 1].section === entries[i].section ? groupEnd[i + 1] : i + 1;
+	}
+
+	const end = limit < 0 ? entries.length : Math.min(entries.length, start + limit);
+	const rows = [];
+	const sections = [];
+	let pending = [];
+	let section = carry ? carry.section : null;
+	if (carry) {
+		carry.spans.forEach(span => { pending[span.col] = { cell: span.cell, left: span.left }; });
+	}
+	entries.slice(start, end).forEach((entry, offset) => {
+		const r = start + offset;
+		// rowspan never reaches past its own thead/tbody/tfoot
+		if (entry.section !== section) {
+			pending = [];
+			section = entry.section;
+		}
+		const rowsLeft = groupEnd[r] - r;
+
+		const out = [];
+		const cells = Array.from(entry.row.cells);
+		let col = 0;
+		let next = 0;
+		const spanAhead = () => pending.some((p, c) => c >= col && p && p.left > 0);
+		while (next < cells.length || spanAhead()) {
+			if (pending[col] && pending[col].left > 0) {
+				out[col] = Object.assign({}, pending[col].cell, { spanned: true });
+				pending[col].left--;
+				col++;
+				continue;
+			}
+			if (next >= cells.length) {
+				out[col++] = { text: '' };
+				continue;
+			}
+			const cell = cells[next++];
+			const data = cellData(cell);
+			const colspan = Math.min(Math.max(cell.colSpan || 1, 1), 1000);
+			let rowspan = cell.rowSpan === 0 ? rowsLeft : cell.rowSpan || 1;
+			rowspan = Math.min(Math.max(rowspan, 1), rowsLeft);
+			for (let k = 0; k < colspan; k++) {
+				out[col + k] = k === 0 ? data : Object.assign({}, data, { spanned: true });
+				if (rowspan > 1) {
+					pending[col + k] = { cell: data, left: rowspan - 1 };
+				}
+			}
+			col += colspan;
+		}
+		rows.push(out);
+		sections.push(entry.section);
+	});
+
+	const spans = [];
+	pending.forEach((p, col) => {
+		if (p && p.left > 0) {
+			spans.push({ col, left: p.left, cell: p.cell });
+		}
+	});
+	return { rows, sections, total: entries.length, next: end, carry: { section, spans } };
+`
+
+// fetchTableGrid runs tableGridScript on the page for the whole table
+func (t *ExtractTableTool) fetchTableGrid(pageID, selector, nested string) (*tableGrid, error) {
+	return t.fetchTableSlice(pageID, selector, nested, 0, -1, nil)
+}
+
+// fetchTableSlice runs tableGridScript for limit rows starting at start,
+// continuing the rowspans in carry from the previous slice
+func (t *ExtractTableTool) fetchTableSlice(pageID, selector, nested string, start, limit int, carry json.RawMessage) (*tableGrid, error) {
+	selectorJSON, _ := json.Marshal(selector)
+	nestedJSON, _ := json.Marshal(nested)
+	if len(carry) == 0 {
+		carry = json.RawMessage("null")
+	}
+	script := fmt.Sprintf(tableGridScript, selectorJSON, nestedJSON, start, limit, carry)
+	result, err := t.browserMgr.ExecuteScript(pageID, script)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract table data: %w", err)
+	}
+	var grid tableGrid
+	if err := decodeScriptValue(result, &grid); err != nil {
+		return nil, fmt.Errorf("failed to parse table extraction result: %w", err)
+	}
+	return &grid, nil
+}
+
+// shapedTable is a tableGrid after headers, footers and filters are applied
+type shapedTable struct {
+	headers []string
+	rows    [][]tableCell
+	footer  [][]tableCell
+	columns []int // grid columns kept, in output order
+}
+
+// tableShaper applies the header, footer and row options to table rows one
+// at a time, so a table can be shaped without holding all of it in memory
+type tableShaper struct {
+	opts      tableOptions
+	width     int
+	seen      int           // rows considered for the header block so far
+	headerBuf [][]tableCell // header rows collected so far
+	headers   []string
+	columns   []int
+	ready     bool // headers are known and data rows can be emitted
+	dataRows  int
+	footer    [][]tableCell
+}
+
+func newTableShaper(opts tableOptions, width int) *tableShaper {
+	if opts.headerRows < 1 {
+		opts.headerRows = 1
+	}
+	s := &tableShaper{opts: opts, width: width}
+	if !opts.includeHeaders {
+		s.finish()
+	}
+	return s
+}
+
+// add takes the next row of the table and returns it if it is a data row
+// to output, or nil if it was skipped, kept as a header or set aside as a
+// footer. Rows beyond max_rows are dropped.
+func (s *tableShaper) add(row []tableCell, section string) []tableCell {
+	if len(row) < s.width {
+		padded := make([]tableCell, s.width)
+		copy(padded, row)
+		row = padded
+	}
+	if s.opts.skipEmptyRows && rowIsEmpty(row) {
+		return nil
+	}
+
+	if section == "foot" {
+		switch s.opts.footer {
+		case footerExclude:
+			return nil
+		case footerSeparate:
+			s.footer = append(s.footer, s.fill(row))
+			return nil
+		}
+	}
+
+	if !s.ready {
+		index := s.seen
+		s.seen++
+		if index < s.opts.headerRow {
+			return nil
+		}
+		s.headerBuf = append(s.headerBuf, row)
+		if len(s.headerBuf) == s.opts.headerRows {
+			s.finish()
+		}
+		return nil
+	}
+
+	if s.opts.maxRows > 0 && s.dataRows >= s.opts.maxRows {
+		return nil
+	}
+	s.dataRows++
+	return s.fill(row)
+}
+
+// full reports that max_rows data rows have been emitted
+func (s *tableShaper) full() bool {
+	return s.opts.maxRows > 0 && s.dataRows >= s.opts.maxRows
+}
+
+// finish settles the headers once the header rows are in, or when the table
+// ends before they were all seen
+func (s *tableShaper) finish() {
+	if s.ready {
+		return
+	}
+	s.ready = true
+	if s.opts.includeHeaders && len(s.headerBuf) > 0 {
+		s.headers = combineHeaderRows(s.headerBuf, s.width)
+	} else {
+		s.headers = make([]string, s.width)
+		for i := range s.headers {
+			s.headers[i] = fmt.Sprintf("column_%d", i)
+		}
+	}
+	s.columns = filterColumns(s.opts.columnFilter, s.headers, s.width)
+}
+
+// fill applies span_fill to an output row
+func (s *tableShaper) fill(row []tableCell) []tableCell {
+	if s.opts.spanFill != spanFillEmpty {
+		return row
+	}
+	return blankSpannedCells([][]tableCell{row})[0]
+}
+
+// shapeTable splits a grid into headers, data rows and footer rows and
+// applies the row and column options
+func shapeTable(grid *tableGrid, opts tableOptions) shapedTable {
+	shaper := newTableShaper(opts, gridWidth(grid.Rows))
+	var rows [][]tableCell
+	for i, row := range grid.Rows {
+		section := ""
+		if i < len(grid.Sections) {
+			section = grid.Sections[i]
+		}
+		if out := shaper.add(row, section); out != nil {
+			rows = append(rows, out)
+		}
+	}
+	shaper.finish()
+
+	return shapedTable{
+		headers: shaper.headers,
+		rows:    rows,
+		footer:  shaper.footer,
+		columns: shaper.columns,
+	}
+}
+
+// gridWidth returns the number of columns in the widest row
+func gridWidth(rows [][]tableCell) int {
+	width := 0
+	for _, row := range rows {
+		if len(row) > width {
+			width = len(row)
+		}
+	}
+	return width
+}
+
+func rowIsEmpty(row []tableCell) bool {
+	for _, cell := range row {
+		if cell.Text != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// blankSpannedCells clears the text of positions covered by another cell's span
+func blankSpannedCells(rows [][]tableCell) [][]tableCell {
+	out := make([][]tableCell, len(rows))
+	for i, row := range rows {
+		out[i] = make([]tableCell, len(row))
+		for j, cell := range row {
+			if cell.Spanned {
+				cell = tableCell{Spanned: true}
+			}
+			out[i][j] = cell
+		}
+	}
+	return out
+}
+
+// combineHeaderRows builds one name per column from one or more header rows,
+// joining grouped headers such as "Q1 / Revenue". Names are made unique so
+// object output doesn't drop columns.
+func combineHeaderRows(rows [][]tableCell, width int) []string {
+	headers := make([]string, width)
+	seen := make(map[string]int, width)
+	for col := 0; col < width; col++ {
+		var parts []string
+		for _, row := range rows {
+			text := row[col].Text
+			if text != "" && (len(parts) == 0 || parts[len(parts)-1] != text) {
+				parts = append(parts, text)
+			}
+		}
+		name := strings.Join(parts, " / ")
+		if name == "" {
+			name = fmt.Sprintf("column_%d", col)
+		}
+		seen[name]++
+		if n := seen[name]; n > 1 {
+			name = fmt.Sprintf("%s_%d", name, n)
+		}
+		headers[col] = name
+	}
+	return headers
+}
+
+// filterColumns turns column_filter entries (indices or header names) into
+// grid column indices, keeping every column when no filter is given
+func filterColumns(filter []interface{}, headers []string, width int) []int {
+	if len(filter) == 0 {
+		columns := make([]int, width)
+		for i := range columns {
+			columns[i] = i
+		}
+		return columns
+	}
+	var columns []int
+	for _, f := range filter {
+		switch v := f.(type) {
+		case float64:
+			columns = append(columns, int(v))
+		case int:
+			columns = append(columns, v)
+		case string:
+			for i, h := range headers {
+				if h == v {
+					columns = append(columns, i)
+					break
+				}
+			}
+		}
+	}
+	return columns
+}
+
+func (s shapedTable) cellAt(row []tableCell, col int) tableCell {
+	if col >= 0 && col < len(row) {
+		return row[col]
+	}
+	return tableCell{}
+}
+
+func (s shapedTable) headerAt(col int) string {
+	if col >= 0 && col < len(s.headers) {
+		return s.headers[col]
+	}
+	return fmt.Sprintf("column_%d", col)
+}
+
+// textRows returns the given rows as text restricted to the selected columns
+func (s shapedTable) textRows(rows [][]tableCell) [][]string {
+	out := make([][]string, len(rows))
+	for i, row := range rows {
+		out[i] = make([]string, len(s.columns))
+		for j, col := range s.columns {
+			out[i][j] = s.cellAt(row, col).Text
+		}
+	}
+	return out
+}
+
+// selectedHeaders returns the header names of the selected columns
+func (s shapedTable) selectedHeaders() []string {
+	out := make([]string, len(s.columns))
+	for i, col := range s.columns {
+		out[i] = s.headerAt(col)
+	}
+	return out
+}
+
+// objects returns the rows as header-keyed objects, with _link, _image,
+// _value and _table entries for cells that carry them
+func (s shapedTable) objects(rows [][]tableCell) []map[string]interface{} {
+	out := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		obj := make(map[string]interface{}, len(s.columns))
+		for _, col := range s.columns {
+			header := s.headerAt(col)
+			cell := s.cellAt(row, col)
+			obj[header] = cell.Text
+			if cell.Link != "" {
+				obj[header+"_link"] = cell.Link
+			}
+			if cell.Image != "" {
+				obj[header+"_image"] = cell.Image
+			}
+			if cell.InputValue != nil {
+				obj[header+"_value"] = *cell.InputValue
+			}
+			if len(cell.Nested) > 0 {
+				obj[header+"_table"] = cell.Nested
+			}
+		}
+		out[i] = obj
+	}
+	return out
+}
+
+// formatRows renders rows in the requested output format
+func (s shapedTable) formatRows(rows [][]tableCell, format string, withHeaders bool) (interface{}, error) {
+	switch format {
+	case "array":
+		data := s.textRows(rows)
+		if withHeaders {
+			data = append([][]string{s.selectedHeaders()}, data...)
+		}
+		return data, nil
+	case "csv":
+		var buf bytes.Buffer
+		w := csv.NewWriter(&buf)
+		if withHeaders {
+			w.Write(s.selectedHeaders())
+		}
+		w.WriteAll(s.textRows(rows))
+		if err := w.Error(); err != nil {
+			return nil, err
+		}
+		return buf.String(), nil
+	default:
+		return s.objects(rows), nil
+	}
+}
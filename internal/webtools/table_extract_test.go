@@ -0,0 +1,170 @@
+package webtools
+
+import (
+	"reflect"
+	"rodmcp/internal/browser"
+	"strings"
+	"testing"
+)
+
+// reportGrid is a two-level header table with a rowspan, a colspan and a totals footer
+func reportGrid() *tableGrid {
+	c := func(text string) tableCell { return tableCell{Text: text} }
+	s := func(text string) tableCell { return tableCell{Text: text, Spanned: true} }
+	return &tableGrid{
+		Rows: [][]tableCell{
+			{c("Region"), c("Q1"), s("Q1")},
+			{s("Region"), c("Revenue"), c("Cost")},
+			{c("North"), c("10"), c("4")},
+			{c("South"), c("n/a"), s("n/a")},
+			{c("Total"), c("10"), c("4")},
+		},
+		Sections: []string{"head", "head", "body", "body", "foot"},
+	}
+}
+
+func defaultTableOptions() tableOptions {
+	return tableOptions{
+		includeHeaders: true,
+		format:         "array",
+		skipEmptyRows:  true,
+		headerRows:     1,
+		spanFill:       spanFillRepeat,
+		footer:         footerInclude,
+		nested:         nestedText,
+	}
+}
+
+func TestShapeTableGroupedHeaders(t *testing.T) {
+	opts := defaultTableOptions()
+	opts.headerRows = 2
+	table := shapeTable(reportGrid(), opts)
+
+	want := []string{"Region", "Q1 / Revenue", "Q1 / Cost"}
+	if !reflect.DeepEqual(table.headers, want) {
+		t.Errorf("headers = %v, want %v", table.headers, want)
+	}
+	if got := table.textRows(table.rows); !reflect.DeepEqual(got[1], []string{"South", "n/a", "n/a"}) {
+		t.Errorf("colspan row = %v, want value repeated", got[1])
+	}
+	if len(table.rows) != 3 {
+		t.Errorf("Expected body and footer rows, got %d", len(table.rows))
+	}
+}
+
+func TestShapeTableSpanFillAndFooter(t *testing.T) {
+	opts := defaultTableOptions()
+	opts.headerRows = 2
+	opts.spanFill = spanFillEmpty
+	opts.footer = footerSeparate
+	table := shapeTable(reportGrid(), opts)
+
+	if got := table.textRows(table.rows); !reflect.DeepEqual(got, [][]string{{"North", "10", "4"}, {"South", "n/a", ""}}) {
+		t.Errorf("rows = %v", got)
+	}
+	if got := table.textRows(table.footer); !reflect.DeepEqual(got, [][]string{{"Total", "10", "4"}}) {
+		t.Errorf("footer = %v", got)
+	}
+
+	opts.footer = footerExclude
+	if table := shapeTable(reportGrid(), opts); len(table.footer) != 0 || len(table.rows) != 2 {
+		t.Errorf("Expected footer dropped, got %d rows and %d footer rows", len(table.rows), len(table.footer))
+	}
+}
+
+func TestShapeTableDuplicateHeadersAndFilter(t *testing.T) {
+	opts := defaultTableOptions()
+	opts.format = "objects"
+	opts.columnFilter = []interface{}{"Region", float64(2)}
+	table := shapeTable(reportGrid(), opts)
+
+	if !reflect.DeepEqual(table.headers, []string{"Region", "Q1", "Q1_2"}) {
+		t.Errorf("headers = %v", table.headers)
+	}
+	data, err := table.formatRows(table.rows, opts.format, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	objects := data.([]map[string]interface{})
+	if objects[1]["Region"] != "North" || objects[1]["Q1_2"] != "4" || len(objects[1]) != 2 {
+		t.Errorf("Unexpected object %v", objects[1])
+	}
+}
+
+func TestFormatRowsCSV(t *testing.T) {
+	table := shapeTable(&tableGrid{
+		Rows:     [][]tableCell{{{Text: "Name"}, {Text: "Note"}}, {{Text: "A"}, {Text: `say "hi", then go`}}},
+		Sections: []string{"head", "body"},
+	}, defaultTableOptions())
+
+	data, err := table.formatRows(table.rows, "csv", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Name,Note\nA,\"say \"\"hi\"\", then go\"\n"; data != want {
+		t.Errorf("csv = %q, want %q", data, want)
+	}
+}
+
+func TestTableOptionsValidate(t *testing.T) {
+	opts := defaultTableOptions()
+	if err := opts.validate(); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	opts.footer = "bottom"
+	if err := opts.validate(); err == nil || !strings.Contains(err.Error(), "footer_rows") {
+		t.Errorf("Expected footer_rows error, got %v", err)
+	}
+}
+
+func TestTableShaperIncremental(t *testing.T) {
+	opts := defaultTableOptions()
+	opts.headerRows = 2
+	opts.maxRows = 1
+	grid := reportGrid()
+	shaper := newTableShaper(opts, gridWidth(grid.Rows))
+
+	var emitted [][]tableCell
+	for i, row := range grid.Rows {
+		if out := shaper.add(row, grid.Sections[i]); out != nil {
+			emitted = append(emitted, out)
+		}
+		if i == 0 && shaper.ready {
+			t.Error("Headers settled before both header rows were seen")
+		}
+	}
+
+	if !shaper.ready || shaper.headers[1] != "Q1 / Revenue" {
+		t.Errorf("headers = %v", shaper.headers)
+	}
+	if len(emitted) != 1 || emitted[0][0].Text != "North" || !shaper.full() {
+		t.Errorf("Expected only the first data row, got %d rows", len(emitted))
+	}
+}
+
+func TestExtractTableOutputFileValidation(t *testing.T) {
+	log := createTestLogger(t)
+	mgr := browser.NewManager(log, browser.Config{Headless: true})
+	tool := NewExtractTableToolWithValidator(log, mgr, NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{t.TempDir()},
+		RestrictToWorkingDir: false,
+	}))
+
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"json format", map[string]interface{}{"selector": "table", "output_file": "out.csv", "output_format": "objects"}, "always writes CSV"},
+		{"chunk too large", map[string]interface{}{"selector": "table", "output_file": "out.csv", "chunk_size": float64(maxTableChunkSize + 1)}, "chunk_size"},
+		{"path not allowed", map[string]interface{}{"selector": "table", "output_file": "/etc/rodmcp-table.csv"}, "access denied"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tool.Execute(tc.args)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("Expected error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package webtools
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Rows fetched from the page per script call when streaming a table
+const (
+	defaultTableChunkSize = 1000
+	maxTableChunkSize     = 10000
+)
+
+// tableStreamResult summarises a table streamed to disk
+type tableStreamResult struct {
+	path    string
+	rows    int
+	chunks  int
+	bytes   int64
+	headers []string
+	footer  [][]string
+	total   int // rows in the page's table, before filtering
+}
+
+// countingWriter tracks how many bytes have passed through it
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// tableExtractionError is a problem with the table itself, such as a bad
+// selector, as opposed to a failure talking to the browser
+type tableExtractionError struct {
+	message string
+}
+
+func (e *tableExtractionError) Error() string {
+	return e.message
+}
+
+// streamTableCSV writes a table to a CSV file a slice of rows at a time, so
+// neither the page nor the server ever holds the whole table. The file is
+// written under a temporary name and renamed into place once complete.
+func (t *ExtractTableTool) streamTableCSV(ctx context.Context, pageID, selector string, opts tableOptions, path string, chunkSize int) (*tableStreamResult, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	done := false
+	defer func() {
+		file.Close()
+		if !done {
+			os.Remove(tmp)
+		}
+	}()
+
+	buffered := bufio.NewWriter(file)
+	counter := &countingWriter{w: buffered}
+	w := csv.NewWriter(counter)
+
+	result := &tableStreamResult{path: path}
+	var shaper *tableShaper
+	var table shapedTable
+	wroteHeader := false
+	writeHeader := func() {
+		if wroteHeader || !shaper.ready {
+			return
+		}
+		wroteHeader = true
+		table = shapedTable{headers: shaper.headers, columns: shaper.columns}
+		result.headers = table.selectedHeaders()
+		if opts.includeHeaders {
+			w.Write(result.headers)
+		}
+	}
+
+	start := 0
+	var carry json.RawMessage
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("table streaming stopped after %d rows: %w", result.rows, err)
+		}
+
+		grid, err := t.fetchTableSlice(pageID, selector, opts.nested, start, chunkSize, carry)
+		if err != nil {
+			return nil, err
+		}
+		if grid.Error != "" {
+			return nil, &tableExtractionError{message: grid.Error}
+		}
+		result.chunks++
+		result.total = grid.Total
+
+		// The first slice decides the column count; later rows are padded
+		// or cut to match the header
+		if shaper == nil {
+			shaper = newTableShaper(opts, gridWidth(grid.Rows))
+		}
+		for i, row := range grid.Rows {
+			section := ""
+			if i < len(grid.Sections) {
+				section = grid.Sections[i]
+			}
+			out := shaper.add(row, section)
+			writeHeader()
+			if out != nil {
+				w.Write(table.textRows([][]tableCell{out})[0])
+				result.rows++
+			}
+		}
+
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		if err := t.validator.ValidateFileSize(counter.n); err != nil {
+			return nil, err
+		}
+
+		if grid.Next >= grid.Total || (shaper.full() && opts.footer != footerSeparate) {
+			break
+		}
+		start = grid.Next
+		carry = grid.Carry
+	}
+
+	shaper.finish()
+	writeHeader()
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	done = true
+
+	result.bytes = counter.n
+	result.footer = table.textRows(shaper.footer)
+	if abs, err := filepath.Abs(path); err == nil {
+		result.path = abs
+	}
+	return result, nil
+}
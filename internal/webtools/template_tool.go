@@ -0,0 +1,328 @@
+package webtools
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"path/filepath"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxTemplateOutputs caps how many files a single render_template call may write
+const maxTemplateOutputs = 1000
+
+// RenderTemplateTool renders Go text/html templates with JSON data into files
+type RenderTemplateTool struct {
+	logger    *logger.Logger
+	validator *PathValidator
+}
+
+func NewRenderTemplateTool(log *logger.Logger, validator *PathValidator) *RenderTemplateTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &RenderTemplateTool{
+		logger:    log,
+		validator: validator,
+	}
+}
+
+func (t *RenderTemplateTool) Name() string {
+	return "render_template"
+}
+
+func (t *RenderTemplateTool) Description() string {
+	return "Render a Go text/html template file with JSON data to one output file, or to one file per item for bulk page generation"
+}
+
+func (t *RenderTemplateTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"template": map[string]interface{}{
+				"type":        "string",
+				"description": "Path to the Go template file (text/template or html/template syntax)",
+				"examples":    []string{"templates/product.html", "./report.tmpl"},
+			},
+			"output": map[string]interface{}{
+				"type":        "string",
+				"description": "Output file path. When 'items' is given this is itself a template evaluated per item, e.g. 'products/{{.slug}}.html'",
+				"examples":    []string{"index.html", "products/{{.slug}}.html"},
+			},
+			"data": map[string]interface{}{
+				"type":        "object",
+				"description": "Data object passed to the template as '.' (available as '.Data' when rendering items)",
+			},
+			"items": map[string]interface{}{
+				"type":        "array",
+				"description": "Optional list of objects; the template is rendered once per item with the item's fields as '.' and the shared data as '.Data'",
+				"items":       map[string]interface{}{"type": "object"},
+			},
+			"engine": map[string]interface{}{
+				"type":        "string",
+				"description": "Template engine: 'html' auto-escapes values for HTML output, 'text' writes values verbatim",
+				"enum":        []string{"html", "text"},
+				"default":     "html",
+			},
+			"create_dirs": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Create parent directories for output files if they don't exist",
+				"default":     true,
+			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths)",
+			},
+		},
+		Required: []string{"template", "output"},
+	}
+}
+
+func (t *RenderTemplateTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		type result struct {
+			written []string
+			err     error
+		}
+		resultChan := make(chan result, 1)
+
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					resultChan <- result{err: fmt.Errorf("template rendering panicked: %v", r)}
+				}
+			}()
+			written, err := t.render(ctx, args)
+			resultChan <- result{written: written, err: err}
+		}()
+
+		var res result
+		select {
+		case res = <-resultChan:
+		case <-ctx.Done():
+			res = result{err: fmt.Errorf("template rendering timed out after 30 seconds")}
+		}
+
+		duration := time.Since(start).Milliseconds()
+		if res.err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, duration)
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Error rendering template: %v", res.err),
+					Data: map[string]interface{}{
+						"written": res.written,
+					},
+				}},
+				IsError: true,
+			}, nil
+		}
+
+		t.logger.WithComponent("tools").Info("Template rendered",
+			zap.Int("files_written", len(res.written)),
+			zap.Int64("duration_ms", duration))
+		t.logger.LogToolExecution(t.Name(), args, true, duration)
+
+		text := fmt.Sprintf("Rendered %d file(s)", len(res.written))
+		if len(res.written) <= 20 {
+			text += ":\n  " + strings.Join(res.written, "\n  ")
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"written":     res.written,
+					"file_count":  len(res.written),
+					"duration_ms": duration,
+				},
+			}},
+		}, nil
+	})
+}
+
+// templateExecutor abstracts over text/template and html/template
+type templateExecutor interface {
+	Execute(buf *bytes.Buffer, data interface{}) error
+}
+
+type textExecutor struct{ tmpl *texttemplate.Template }
+
+func (e textExecutor) Execute(buf *bytes.Buffer, data interface{}) error {
+	return e.tmpl.Execute(buf, data)
+}
+
+type htmlExecutor struct{ tmpl *htmltemplate.Template }
+
+func (e htmlExecutor) Execute(buf *bytes.Buffer, data interface{}) error {
+	return e.tmpl.Execute(buf, data)
+}
+
+func (t *RenderTemplateTool) render(ctx context.Context, args map[string]interface{}) ([]string, error) {
+	templatePath, ok := args["template"].(string)
+	if !ok || templatePath == "" {
+		return nil, fmt.Errorf("template must be a non-empty string")
+	}
+	outputPattern, ok := args["output"].(string)
+	if !ok || outputPattern == "" {
+		return nil, fmt.Errorf("output must be a non-empty string")
+	}
+
+	engine := "html"
+	if val, ok := args["engine"].(string); ok && val != "" {
+		engine = val
+	}
+	if engine != "html" && engine != "text" {
+		return nil, fmt.Errorf("unknown engine %q (use html or text)", engine)
+	}
+
+	createDirs := true
+	if val, ok := args["create_dirs"].(bool); ok {
+		createDirs = val
+	}
+
+	cwd, _ := args["cwd"].(string)
+	templatePath, err := t.validator.ResolvePath(templatePath, cwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validator.ValidatePath(templatePath, "read"); err != nil {
+		return nil, fmt.Errorf("template access denied: %w", err)
+	}
+
+	info, err := os.Stat(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access template %s: %w", templatePath, err)
+	}
+	if err := t.validator.ValidateFileSize(info.Size()); err != nil {
+		return nil, fmt.Errorf("template too large: %w", err)
+	}
+
+	source, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+
+	name := filepath.Base(templatePath)
+	var executor templateExecutor
+	if engine == "html" {
+		tmpl, err := htmltemplate.New(name).Option("missingkey=zero").Parse(string(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		executor = htmlExecutor{tmpl}
+	} else {
+		tmpl, err := texttemplate.New(name).Option("missingkey=zero").Parse(string(source))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse template: %w", err)
+		}
+		executor = textExecutor{tmpl}
+	}
+
+	data, _ := args["data"].(map[string]interface{})
+
+	rawItems, hasItems := args["items"].([]interface{})
+	if !hasItems {
+		if err := t.renderOne(executor, data, outputPattern, cwd, createDirs); err != nil {
+			return nil, err
+		}
+		resolved, _ := t.validator.ResolvePath(outputPattern, cwd)
+		return []string{resolved}, nil
+	}
+
+	if len(rawItems) > maxTemplateOutputs {
+		return nil, fmt.Errorf("too many items (%d) - maximum is %d per call", len(rawItems), maxTemplateOutputs)
+	}
+
+	outputTmpl, err := texttemplate.New("output").Option("missingkey=error").Parse(outputPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output path template: %w", err)
+	}
+
+	var written []string
+	seen := make(map[string]int)
+	for i, raw := range rawItems {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			return written, fmt.Errorf("item %d is not an object", i)
+		}
+
+		// Expose shared data to each item without clobbering item fields
+		itemData := make(map[string]interface{}, len(item)+1)
+		for k, v := range item {
+			itemData[k] = v
+		}
+		if _, exists := itemData["Data"]; !exists && data != nil {
+			itemData["Data"] = data
+		}
+
+		var outBuf bytes.Buffer
+		if err := outputTmpl.Execute(&outBuf, itemData); err != nil {
+			return written, fmt.Errorf("item %d: failed to evaluate output path: %w", i, err)
+		}
+		outputPath := strings.TrimSpace(outBuf.String())
+		if outputPath == "" {
+			return written, fmt.Errorf("item %d: output path evaluated to an empty string", i)
+		}
+		if prev, dup := seen[outputPath]; dup {
+			return written, fmt.Errorf("items %d and %d both render to %s", prev, i, outputPath)
+		}
+		seen[outputPath] = i
+
+		if err := t.renderOne(executor, itemData, outputPath, cwd, createDirs); err != nil {
+			return written, fmt.Errorf("item %d: %w", i, err)
+		}
+		resolved, _ := t.validator.ResolvePath(outputPath, cwd)
+		written = append(written, resolved)
+	}
+
+	return written, nil
+}
+
+// renderOne executes the template and writes the result to a validated output path
+func (t *RenderTemplateTool) renderOne(executor templateExecutor, data interface{}, outputPath, cwd string, createDirs bool) error {
+	resolved, err := t.validator.ResolvePath(outputPath, cwd)
+	if err != nil {
+		return err
+	}
+	if err := t.validator.ValidatePath(resolved, "write"); err != nil {
+		return fmt.Errorf("output access denied: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := executor.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	if err := t.validator.ValidateFileSize(int64(buf.Len())); err != nil {
+		return fmt.Errorf("rendered output too large: %w", err)
+	}
+
+	if createDirs {
+		if err := os.MkdirAll(filepath.Dir(resolved), 0755); err != nil {
+			return fmt.Errorf("failed to create directories for %s: %w", resolved, err)
+		}
+	}
+
+	if err := os.WriteFile(resolved, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", resolved, err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTemplateTestTool(t *testing.T) (*RenderTemplateTool, string) {
+	dir := t.TempDir()
+	validator := NewPathValidator(&FileAccessConfig{
+		AllowedPaths:         []string{dir},
+		RestrictToWorkingDir: false,
+		MaxFileSize:          1024 * 1024,
+	})
+	return NewRenderTemplateTool(createTestLogger(t), validator), dir
+}
+
+func TestRenderTemplateSingleOutput(t *testing.T) {
+	tool, dir := newTemplateTestTool(t)
+	tmplPath := filepath.Join(dir, "page.html")
+	os.WriteFile(tmplPath, []byte(`<h1>{{.title}}</h1><p>{{.body}}</p>`), 0644)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"template": "page.html",
+		"output":   "out/index.html",
+		"cwd":      dir,
+		"data": map[string]interface{}{
+			"title": "Hello",
+			"body":  "<script>alert(1)</script>",
+		},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("render_template failed: %v %v", err, resp)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "out", "index.html"))
+	if err != nil {
+		t.Fatalf("Expected output file: %v", err)
+	}
+	if !strings.Contains(string(content), "<h1>Hello</h1>") {
+		t.Errorf("Expected title in output, got %s", content)
+	}
+	if strings.Contains(string(content), "<script>") {
+		t.Errorf("Expected html engine to escape values, got %s", content)
+	}
+}
+
+func TestRenderTemplateItems(t *testing.T) {
+	tool, dir := newTemplateTestTool(t)
+	tmplPath := filepath.Join(dir, "product.tmpl")
+	os.WriteFile(tmplPath, []byte(`{{.name}} from {{.Data.store}}`), 0644)
+
+	resp, err := tool.Execute(map[string]interface{}{
+		"template": tmplPath,
+		"output":   filepath.Join(dir, "products", "{{.slug}}.txt"),
+		"engine":   "text",
+		"data":     map[string]interface{}{"store": "Shop"},
+		"items": []interface{}{
+			map[string]interface{}{"slug": "mug", "name": "Mug"},
+			map[string]interface{}{"slug": "cup", "name": "Cup"},
+		},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("render_template failed: %v %v", err, resp)
+	}
+	if resp.Content[0].Data.(map[string]interface{})["file_count"] != 2 {
+		t.Errorf("Expected 2 files written, got %v", resp.Content[0].Data)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(dir, "products", "cup.txt"))
+	if string(content) != "Cup from Shop" {
+		t.Errorf("Unexpected item output: %q", content)
+	}
+
+	// Two items rendering to the same path must be rejected
+	resp, _ = tool.Execute(map[string]interface{}{
+		"template": tmplPath,
+		"output":   filepath.Join(dir, "same.txt"),
+		"items": []interface{}{
+			map[string]interface{}{"name": "A"},
+			map[string]interface{}{"name": "B"},
+		},
+	})
+	if !resp.IsError {
+		t.Error("Expected duplicate output paths to be rejected")
+	}
+}
+
+func TestRenderTemplateAccessDenied(t *testing.T) {
+	tool, dir := newTemplateTestTool(t)
+	os.WriteFile(filepath.Join(dir, "t.tmpl"), []byte("x"), 0644)
+
+	resp, _ := tool.Execute(map[string]interface{}{
+		"template": filepath.Join(dir, "t.tmpl"),
+		"output":   filepath.Join(t.TempDir(), "escape.txt"),
+	})
+	if !resp.IsError {
+		t.Error("Expected output outside allowed paths to be rejected")
+	}
+
+	resp, _ = tool.Execute(map[string]interface{}{
+		"template": filepath.Join(dir, "t.tmpl"),
+		"output":   filepath.Join(dir, "o.txt"),
+		"engine":   "jinja",
+	})
+	if !resp.IsError {
+		t.Error("Expected unknown engine to be rejected")
+	}
+}
@@ -250,7 +250,7 @@ func TestTimeouts_NetworkTools(t *testing.T) {
 	t.Run("HTTPRequestTool_Timeout", func(t *testing.T) {
 		t.Parallel()
 		log := createTestLogger(t)
-		tool := NewHTTPRequestTool(log)
+		tool := NewHTTPRequestTool(log, nil, nil)
 		
 		// Test with slow endpoint
 		args := map[string]interface{}{
@@ -276,7 +276,7 @@ func TestTimeouts_NetworkTools(t *testing.T) {
 	t.Run("HTTPRequestTool_FastRequest", func(t *testing.T) {
 		t.Parallel()
 		log := createTestLogger(t)
-		tool := NewHTTPRequestTool(log)
+		tool := NewHTTPRequestTool(log, nil, nil)
 		
 		// Test with fast endpoint
 		args := map[string]interface{}{
@@ -518,7 +518,7 @@ func TestTimeouts_PanicRecovery(t *testing.T) {
 			NewReadFileTool(log, NewPathValidator(DefaultFileAccessConfig())),
 			NewWriteFileTool(log, NewPathValidator(DefaultFileAccessConfig())),
 			NewListDirectoryTool(log, NewPathValidator(DefaultFileAccessConfig())),
-			NewHTTPRequestTool(log),
+			NewHTTPRequestTool(log, nil, nil),
 			NewCreatePageTool(log),
 			NewHelpTool(log),
 		}
@@ -585,7 +585,7 @@ func TestTimeouts_RealWorldScenarios(t *testing.T) {
 		
 		// Operation 3: HTTP request
 		go func() {
-			httpTool := NewHTTPRequestTool(log)
+			httpTool := NewHTTPRequestTool(log, nil, nil)
 			args := map[string]interface{}{
 				"url":    "https://httpbin.org/get",
 				"method": "GET",
@@ -716,7 +716,7 @@ func TestTimeouts_StressTest(t *testing.T) {
 				switch id % 3 {
 				case 0:
 					// HTTP request with timeout
-					tool := NewHTTPRequestTool(log)
+					tool := NewHTTPRequestTool(log, nil, nil)
 					args := map[string]interface{}{
 						"url":    "https://httpbin.org/delay/10",
 						"method": "GET",
@@ -0,0 +1,56 @@
+package webtools
+
+// Observes methods mark the calls an observer connection, such as a
+// dashboard watching an agent, may make (see mcp.ObservableTool). Anything
+// that navigates, scrolls, clears a log, resets a counter, changes page event
+// subscriptions or writes a file is left to full connections. So is
+// get_server_logs: the server log records every call's arguments, passwords
+// and tokens included.
+
+func (t *SwitchTabTool) Observes(args map[string]interface{}) bool {
+	action, _ := args["action"].(string)
+	return action == "list"
+}
+
+func (t *ScreenshotTool) Observes(args map[string]interface{}) bool {
+	filename, _ := args["filename"].(string)
+	save, _ := args["save"].(bool)
+	return filename == "" && !save
+}
+
+func (t *BrowserStatsTool) Observes(args map[string]interface{}) bool {
+	action, _ := args["action"].(string)
+	return action != "reset"
+}
+
+func (t *GetConsoleLogsTool) Observes(args map[string]interface{}) bool {
+	clear, _ := args["clear"].(bool)
+	return !clear
+}
+
+func (t *ListPageRequestsTool) Observes(args map[string]interface{}) bool {
+	clear, _ := args["clear"].(bool)
+	return !clear
+}
+
+// Subscriptions are shared by every connection, so observers may only list them
+func (t *SubscribePageEventsTool) Observes(args map[string]interface{}) bool {
+	action, _ := args["action"].(string)
+	return action == "list"
+}
+
+func (t *HelpTool) Observes(args map[string]interface{}) bool {
+	return true
+}
+
+func (t *DescribeToolTool) Observes(args map[string]interface{}) bool {
+	return true
+}
+
+func (t *WaitForNetworkIdleTool) Observes(args map[string]interface{}) bool {
+	return true
+}
+
+func (t *WaitForResponseTool) Observes(args map[string]interface{}) bool {
+	return true
+}
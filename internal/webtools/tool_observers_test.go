@@ -0,0 +1,36 @@
+package webtools
+
+import "testing"
+
+func TestObserves(t *testing.T) {
+	type observable interface {
+		Observes(args map[string]interface{}) bool
+	}
+	cases := []struct {
+		name string
+		tool observable
+		args map[string]interface{}
+		want bool
+	}{
+		{"list tabs", &SwitchTabTool{}, map[string]interface{}{"action": "list"}, true},
+		{"switch tabs", &SwitchTabTool{}, map[string]interface{}{"action": "switch"}, false},
+		{"switch tabs by default", &SwitchTabTool{}, map[string]interface{}{}, false},
+		{"inline screenshot", &ScreenshotTool{}, map[string]interface{}{"full_page": true}, true},
+		{"saved screenshot", &ScreenshotTool{}, map[string]interface{}{"save": true}, false},
+		{"named screenshot", &ScreenshotTool{}, map[string]interface{}{"filename": "shot.png"}, false},
+		{"read stats", &BrowserStatsTool{}, map[string]interface{}{}, true},
+		{"reset stats", &BrowserStatsTool{}, map[string]interface{}{"action": "reset"}, false},
+		{"read console", &GetConsoleLogsTool{}, map[string]interface{}{"level": "error"}, true},
+		{"clear console", &GetConsoleLogsTool{}, map[string]interface{}{"clear": true}, false},
+		{"read requests", &ListPageRequestsTool{}, map[string]interface{}{}, true},
+		{"clear requests", &ListPageRequestsTool{}, map[string]interface{}{"clear": true}, false},
+		{"list subscriptions", &SubscribePageEventsTool{}, map[string]interface{}{"action": "list"}, true},
+		{"subscribe", &SubscribePageEventsTool{}, map[string]interface{}{}, false},
+		{"unsubscribe", &SubscribePageEventsTool{}, map[string]interface{}{"action": "unsubscribe"}, false},
+	}
+	for _, c := range cases {
+		if got := c.tool.Observes(c.args); got != c.want {
+			t.Errorf("%s: Observes = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,606 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+
+	"rodmcp/pkg/types"
+)
+
+// Plans for dry-run mode: each describes what the tool's Execute would
+// change, using the same argument handling and access checks
+
+// planPreviewLength bounds the content and body previews in plans
+const planPreviewLength = 300
+
+func (t *WriteFileTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	pathStr, ok := args["path"].(string)
+	if !ok {
+		return nil, fmt.Errorf("path must be a string")
+	}
+	content, ok := args["content"].(string)
+	if !ok {
+		return nil, fmt.Errorf("content must be a string")
+	}
+	createDirs, _ := args["create_dirs"].(bool)
+
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+	if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
+	if err := t.validator.ValidateFileSize(int64(len(content))); err != nil {
+		return nil, fmt.Errorf("file size validation failed: %w", err)
+	}
+
+	details := map[string]interface{}{
+		"path":    cleanPath,
+		"bytes":   len(content),
+		"preview": clipText(content, planPreviewLength),
+	}
+	action := "create"
+	if info, err := os.Stat(cleanPath); err == nil {
+		action = "overwrite"
+		details["existing_bytes"] = info.Size()
+	} else if _, err := os.Stat(filepath.Dir(cleanPath)); os.IsNotExist(err) {
+		if !createDirs {
+			return nil, fmt.Errorf("directory %s does not exist (set create_dirs)", filepath.Dir(cleanPath))
+		}
+		details["create_dirs"] = filepath.Dir(cleanPath)
+	}
+	details["action"] = action
+
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("%s %s (%d bytes)", action, cleanPath, len(content)),
+		Details: details,
+	}, nil
+}
+
+// readOnlyHTTPMethods do not change server state
+var readOnlyHTTPMethods = []string{"GET", "HEAD", "OPTIONS"}
+
+func (t *HTTPRequestTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	url, ok := args["url"].(string)
+	if !ok {
+		return nil, fmt.Errorf("url must be a string")
+	}
+	method := "GET"
+	if val, ok := args["method"].(string); ok && val != "" {
+		method = strings.ToUpper(val)
+	}
+
+	details := map[string]interface{}{"method": method, "url": url}
+	if headers, ok := args["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+		details["headers"] = headers
+	}
+	if jsonData, ok := args["json"]; ok {
+		body, err := json.Marshal(jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		details["body"] = clipText(string(body), planPreviewLength)
+		details["content_type"] = "application/json"
+	} else if body, ok := args["body"].(string); ok && body != "" {
+		details["body"] = clipText(body, planPreviewLength)
+	}
+
+	summary := fmt.Sprintf("send %s %s", method, url)
+	if pagination, err := parsePagination(args); err != nil {
+		return nil, err
+	} else if pagination != nil {
+		details["max_pages"] = pagination.MaxPages
+		summary += fmt.Sprintf(", repeated for up to %d pages", pagination.MaxPages)
+	}
+	saveTo, _ := args["save_to"].(string)
+	if saveTo != "" {
+		details["save_to"] = saveTo
+		summary += " and save the body to " + saveTo
+	}
+
+	return &types.ToolPlan{
+		Mutates: saveTo != "" || !containsString(readOnlyHTTPMethods, method),
+		Summary: summary,
+		Details: details,
+	}, nil
+}
+
+// clickPlanScript inspects the element a click would hit without clicking it
+const clickPlanScript = `
+	const el = document.querySelector(%s);
+	if (!el) return {found: false};
+	const tag = el.tagName.toLowerCase();
+	const type = (el.getAttribute('type') || '').toLowerCase();
+	let submits = false;
+	if (tag === 'button') submits = !!el.form && (type === '' || type === 'submit');
+	if (tag === 'input') submits = !!el.form && (type === 'submit' || type === 'image');
+	const link = el.closest('a[href]');
+	return {
+		found: true,
+		tag: tag,
+		text: (el.innerText || el.value || '').trim().slice(0, 80),
+		submits: submits,
+		form_action: submits ? (el.formAction || el.form.action) : '',
+		form_method: submits ? (el.getAttribute('formmethod') || el.form.method || 'get').toLowerCase() : '',
+		href: link ? link.href : ''
+	};
+`
+
+func (t *ClickElementTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	selector, ok := args["selector"].(string)
+	if !ok {
+		return nil, fmt.Errorf("selector parameter must be a string")
+	}
+	pageID, _ := args["page_id"].(string)
+	if pageID == "" {
+		pages := t.browserMgr.ListPages()
+		if len(pages) == 0 {
+			// Nothing to click; let the call report it
+			return &types.ToolPlan{Summary: "click " + selector}, nil
+		}
+		pageID = pages[0]
+	}
+
+	quoted, _ := json.Marshal(selector)
+	raw, err := t.browserMgr.ExecuteScript(pageID, fmt.Sprintf(clickPlanScript, quoted))
+	if err != nil {
+		return nil, err
+	}
+	var target struct {
+		Found      bool   `json:"found"`
+		Tag        string `json:"tag"`
+		Text       string `json:"text"`
+		Submits    bool   `json:"submits"`
+		FormAction string `json:"form_action"`
+		FormMethod string `json:"form_method"`
+		Href       string `json:"href"`
+	}
+	if err := decodeScriptValue(raw, &target); err != nil {
+		return nil, err
+	}
+	if !target.Found || !target.Submits {
+		// Plain clicks only change the page, which a dry run allows
+		return &types.ToolPlan{Summary: "click " + selector}, nil
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("submit a form (%s %s) by clicking %s", strings.ToUpper(target.FormMethod), target.FormAction, selector),
+		Details: map[string]interface{}{
+			"page_id":     pageID,
+			"selector":    selector,
+			"element":     target.Tag,
+			"text":        target.Text,
+			"form_action": target.FormAction,
+			"form_method": target.FormMethod,
+		},
+	}, nil
+}
+
+func (t *FormFillTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	submit, _ := args["submit"].(bool)
+	var fields []string
+	if values, ok := args["fields"].(map[string]interface{}); ok {
+		for name := range values {
+			fields = append(fields, name)
+		}
+	}
+	steps, _ := args["steps"].([]interface{})
+	for _, raw := range steps {
+		step, _ := raw.(map[string]interface{})
+		if s, _ := step["submit"].(bool); s {
+			submit = true
+		}
+		if next, _ := step["next_selector"].(string); next != "" {
+			// Moving to the next page of a wizard usually posts the current one
+			submit = true
+		}
+		if values, ok := step["fields"].(map[string]interface{}); ok {
+			for name := range values {
+				fields = append(fields, name)
+			}
+		}
+	}
+	sort.Strings(fields)
+
+	formSelector, _ := args["form_selector"].(string)
+	if formSelector == "" {
+		formSelector = "form"
+	}
+	summary := fmt.Sprintf("fill %d field(s) in %s", len(fields), formSelector)
+	if submit {
+		summary += " and submit it"
+	}
+	details := map[string]interface{}{"form_selector": formSelector, "fields": fields, "submit": submit}
+	if len(steps) > 0 {
+		details["steps"] = len(steps)
+	}
+	// Filling without submitting only changes the page
+	return &types.ToolPlan{Mutates: submit, Summary: summary, Details: details}, nil
+}
+
+func (t *ExecuteScriptTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	script, ok := args["script"].(string)
+	if !ok {
+		return nil, fmt.Errorf("script is required")
+	}
+	// A script can do anything the page can, so it is always reviewed
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("run a %d character script in the page", len(script)),
+		Details: map[string]interface{}{"script": clipText(script, planPreviewLength)},
+	}, nil
+}
+
+func (t *SQLiteQueryTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	dbPath, ok := args["database"].(string)
+	if !ok || dbPath == "" {
+		return nil, fmt.Errorf("database must be a non-empty string")
+	}
+	query, ok := args["query"].(string)
+	if !ok || strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("query must be a non-empty string")
+	}
+	allowWrites, _ := args["allow_writes"].(bool)
+	if !allowWrites || isSQLiteReadStatement(query) {
+		return &types.ToolPlan{Summary: "run a read-only query"}, nil
+	}
+
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(dbPath, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("database access denied: %w", err)
+	}
+	if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		return nil, fmt.Errorf("database access denied: %w", err)
+	}
+	details := map[string]interface{}{"database": cleanPath, "query": query}
+	if params, ok := args["params"].([]interface{}); ok && len(params) > 0 {
+		details["params"] = params
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("run a write statement against %s", cleanPath),
+		Details: details,
+	}, nil
+}
+
+func (t *GitCommitTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	message, ok := args["message"].(string)
+	if !ok || strings.TrimSpace(message) == "" {
+		return nil, fmt.Errorf("message must be a non-empty string")
+	}
+
+	repo, root, err := openGitRepo(t.validator, args, "write")
+	if err != nil {
+		doInit, _ := args["init"].(bool)
+		if !doInit || !strings.Contains(err.Error(), "not inside a git repository") {
+			return nil, err
+		}
+		pathStr, _ := args["path"].(string)
+		return &types.ToolPlan{
+			Mutates: true,
+			Summary: fmt.Sprintf("initialize a repository at %s and commit every file in it: %s", pathStr, message),
+			Details: map[string]interface{}{"message": message, "init": true},
+		}, nil
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute status: %w", err)
+	}
+	var only []string
+	if raw, ok := args["files"].([]interface{}); ok {
+		for _, f := range raw {
+			if name, _ := f.(string); name != "" {
+				only = append(only, filepath.ToSlash(name))
+			}
+		}
+	}
+	var files []string
+	for path, fs := range status {
+		changed := fs.Staging != git.Unmodified || fs.Worktree != git.Unmodified
+		if changed && (len(only) == 0 || containsString(only, path)) {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nothing to commit in %s", root)
+	}
+	sort.Strings(files)
+
+	branch, _ := headBranch(repo)
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("commit %d file(s) to %s: %s", len(files), branch, message),
+		Details: map[string]interface{}{"root": root, "branch": branch, "files": files, "message": message},
+	}, nil
+}
+
+// filePlan describes writing what to path, saying whether it replaces a
+// file already there
+func filePlan(what, path string, details map[string]interface{}) *types.ToolPlan {
+	action := "create"
+	if _, err := os.Stat(path); err == nil {
+		action = "overwrite"
+	}
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["path"] = path
+	details["action"] = action
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("%s %s (%s)", action, path, what),
+		Details: details,
+	}
+}
+
+// pathArgPlan plans the tools that write the file named by their path
+// argument and refuse to replace one unless overwrite is set
+func pathArgPlan(validator *PathValidator, args map[string]interface{}, what string) (*types.ToolPlan, error) {
+	path, err := resolveFileArg(validator, args, "write")
+	if err != nil {
+		return nil, err
+	}
+	if overwrite, _ := args["overwrite"].(bool); !overwrite {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("%s already exists; pass overwrite=true to replace it", path)
+		}
+	}
+	return filePlan(what, path, nil), nil
+}
+
+func (t *CreatePageTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	filename, ok := args["filename"].(string)
+	if !ok {
+		return nil, fmt.Errorf("filename parameter must be a string")
+	}
+	if err := ValidateFilename(filename, t.Name()); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(filename, ".html") {
+		filename += ".html"
+	}
+	if cwd, _ := args["cwd"].(string); cwd != "" {
+		validator := t.validator
+		if validator == nil {
+			validator = NewPathValidator(DefaultFileAccessConfig())
+		}
+		resolved, err := validator.ResolvePath(filename, cwd)
+		if err == nil {
+			err = validator.ValidatePath(resolved, "write")
+		}
+		if err != nil {
+			return nil, err
+		}
+		filename = resolved
+	}
+	absPath, _ := filepath.Abs(filename)
+	return filePlan("HTML page", absPath, nil), nil
+}
+
+func (t *RenderTemplateTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	templatePath, ok := args["template"].(string)
+	if !ok || templatePath == "" {
+		return nil, fmt.Errorf("template must be a non-empty string")
+	}
+	output, ok := args["output"].(string)
+	if !ok || output == "" {
+		return nil, fmt.Errorf("output must be a non-empty string")
+	}
+	cwd, _ := args["cwd"].(string)
+	if items, ok := args["items"].([]interface{}); ok {
+		return &types.ToolPlan{
+			Mutates: true,
+			Summary: fmt.Sprintf("render %s once per item into %d file(s) named by %s", templatePath, len(items), output),
+			Details: map[string]interface{}{"template": templatePath, "output": output, "items": len(items)},
+		}, nil
+	}
+	resolved, err := t.validator.ResolvePath(output, cwd)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.validator.ValidatePath(resolved, "write"); err != nil {
+		return nil, fmt.Errorf("output access denied: %w", err)
+	}
+	return filePlan("rendered from "+templatePath, resolved, nil), nil
+}
+
+func (t *SaveScrapeRecipeTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	name, _ := args["name"].(string)
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+	}
+	return filePlan("scrape recipe "+name, t.store.path(name), nil), nil
+}
+
+func (t *SaveWorkflowTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	name, _ := args["name"].(string)
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+	}
+	return filePlan("workflow "+name, t.store.path(name), nil), nil
+}
+
+func (t *SaveSessionTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	name, _ := args["name"].(string)
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("name is required and may only contain letters, digits, '-' and '_'")
+	}
+	if overwrite, ok := args["overwrite"].(bool); ok && !overwrite && t.sessions.exists(name) {
+		return nil, fmt.Errorf("session %q already exists; pass overwrite=true to replace it", name)
+	}
+	return filePlan("cookies and storage of session "+name, t.sessions.path(name), nil), nil
+}
+
+func (t *ExportSessionTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	if t.passphrase == "" {
+		return nil, fmt.Errorf("%s", noSessionKeyMessage)
+	}
+	return pathArgPlan(t.validator, args, "encrypted session export")
+}
+
+func (t *ArchivePageTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	return pathArgPlan(t.validator, args, "page archive")
+}
+
+// Stopping without a path only reads the capture
+func (t *StopNetworkCaptureTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	if path, _ := args["path"].(string); path == "" {
+		return &types.ToolPlan{Summary: "stop the capture and list its requests"}, nil
+	}
+	return pathArgPlan(t.validator, args, "network capture")
+}
+
+func (t *ExtractTableTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	outputFile, _ := args["output_file"].(string)
+	if outputFile == "" {
+		return &types.ToolPlan{Summary: "read a table"}, nil
+	}
+	if err := t.validator.ValidatePath(outputFile, "write"); err != nil {
+		return nil, err
+	}
+	return filePlan("table rows as CSV", outputFile, nil), nil
+}
+
+// screenshotPlan plans the screenshot tools, which only write a file when
+// given a filename or save
+func screenshotPlan(validator *PathValidator, outputDir, prefix, ext string, args map[string]interface{}) (*types.ToolPlan, error) {
+	out, err := parseScreenshotOutput(args)
+	if err != nil {
+		return nil, err
+	}
+	path, trusted := resolveScreenshotPath(outputDir, out.filename, prefix, ext, out.save)
+	if path == "" {
+		return &types.ToolPlan{Summary: "return a screenshot"}, nil
+	}
+	if err := validateScreenshotPath(validator, path, trusted); err != nil {
+		return nil, fmt.Errorf("screenshot file access denied: %w", err)
+	}
+	return filePlan("screenshot", path, nil), nil
+}
+
+func (t *ScreenshotTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	format, _ := args["format"].(string)
+	if format == "" {
+		format = "png"
+	}
+	return screenshotPlan(t.validator, t.outputDir, "screenshot", "."+format, args)
+}
+
+func (t *TakeElementScreenshotTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	return screenshotPlan(t.validator, t.outputDir, "element", ".png", args)
+}
+
+func (t *FingerprintProfileTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	action, _ := args["action"].(string)
+	name, _ := args["name"].(string)
+	if action != "list" && !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+	}
+	switch action {
+	case "create":
+		if overwrite, _ := args["overwrite"].(bool); !overwrite && t.store.exists(name) {
+			return nil, fmt.Errorf("fingerprint profile %q already exists; pass overwrite=true to replace it", name)
+		}
+		return filePlan("fingerprint profile "+name, t.store.path(name), nil), nil
+	case "delete":
+		return &types.ToolPlan{
+			Mutates: true,
+			Summary: fmt.Sprintf("delete fingerprint profile %s", name),
+			Details: map[string]interface{}{"path": t.store.path(name)},
+		}, nil
+	}
+	return &types.ToolPlan{Summary: action + " fingerprint profiles"}, nil
+}
+
+// Monitors and crawls keep their snapshots and results on disk
+func (t *MonitorPageTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	action, _ := args["action"].(string)
+	if action != "" && action != "start" && action != "check" {
+		return &types.ToolPlan{Summary: action + " monitors"}, nil
+	}
+	cfg, err := t.parseMonitorConfig(args)
+	if err != nil {
+		return nil, err
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("record snapshots of %s under %s", cfg.URL, filepath.Join(t.dir, cfg.ID)),
+		Details: map[string]interface{}{"url": cfg.URL, "id": cfg.ID, "mode": cfg.Mode},
+	}, nil
+}
+
+func (t *CrawlSiteTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	action, _ := args["action"].(string)
+	if action != "" && action != "start" && action != "delete" {
+		return &types.ToolPlan{Summary: action + " crawls"}, nil
+	}
+	id, err := crawlID(args)
+	if err != nil {
+		return nil, err
+	}
+	summary := fmt.Sprintf("crawl and record pages under %s", t.store.crawlDir(id))
+	if action == "delete" {
+		summary = fmt.Sprintf("delete crawl %s and its results", id)
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: summary,
+		Details: map[string]interface{}{"id": id, "action": action},
+	}, nil
+}
+
+// Signing in submits the profile's credentials to the site
+func (t *LoginTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	profile, _ := args["profile"].(string)
+	if profile == "" {
+		return nil, fmt.Errorf("profile is required")
+	}
+	details := map[string]interface{}{"profile": profile}
+	if url, _ := args["url"].(string); url != "" {
+		details["url"] = url
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: fmt.Sprintf("sign in with profile %s and save the session", profile),
+		Details: details,
+	}, nil
+}
+
+// Stopping a recording saves it as a workflow
+func (t *RecordWorkflowTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	action, _ := args["action"].(string)
+	if action != "stop" {
+		return &types.ToolPlan{Summary: action + " a recording"}, nil
+	}
+	name, _ := args["name"].(string)
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+	}
+	return filePlan("recorded workflow "+name, t.store.path(name), nil), nil
+}
+
+// Solving sends the page's CAPTCHA to the configured solver service
+func (t *SolveCaptchaTool) Plan(args map[string]interface{}) (*types.ToolPlan, error) {
+	if t.solver == nil {
+		return &types.ToolPlan{Summary: "detect a CAPTCHA"}, nil
+	}
+	return &types.ToolPlan{
+		Mutates: true,
+		Summary: "send the page's CAPTCHA, if any, to the configured solver and fill in its token",
+	}, nil
+}
@@ -0,0 +1,174 @@
+package webtools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rodmcp/pkg/types"
+)
+
+func TestWriteFilePlan(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewWriteFileTool(createTestLogger(t), newGitTestValidator(dir))
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("old"), 0644)
+
+	plan, err := tool.Plan(map[string]interface{}{"path": "index.html", "cwd": dir, "content": "<h1>new</h1>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Mutates || plan.Details["action"] != "overwrite" || plan.Details["existing_bytes"] != int64(3) {
+		t.Errorf("overwrite plan = %+v", plan)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "index.html")); string(data) != "old" {
+		t.Errorf("planning wrote the file: %q", data)
+	}
+
+	if _, err := tool.Plan(map[string]interface{}{"path": "sub/page.html", "cwd": dir, "content": "x"}); err == nil {
+		t.Error("expected a missing directory without create_dirs to be reported")
+	}
+	plan, err = tool.Plan(map[string]interface{}{"path": "sub/page.html", "cwd": dir, "content": "x", "create_dirs": true})
+	if err != nil || plan.Details["action"] != "create" || plan.Details["create_dirs"] != filepath.Join(dir, "sub") {
+		t.Errorf("create plan = %+v, %v", plan, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub")); !os.IsNotExist(err) {
+		t.Error("planning created the directory")
+	}
+
+	if _, err := tool.Plan(map[string]interface{}{"path": "/etc/passwd", "content": "x"}); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("expected access denied, got %v", err)
+	}
+}
+
+func TestHTTPRequestPlan(t *testing.T) {
+	tool := NewHTTPRequestTool(createTestLogger(t), nil, nil)
+
+	plan, err := tool.Plan(map[string]interface{}{"url": "https://api.example.com/users"})
+	if err != nil || plan.Mutates {
+		t.Errorf("GET plan = %+v, %v", plan, err)
+	}
+	plan, err = tool.Plan(map[string]interface{}{
+		"url":    "https://api.example.com/users",
+		"method": "post",
+		"json":   map[string]interface{}{"name": "Ann"},
+	})
+	if err != nil || !plan.Mutates || plan.Summary != "send POST https://api.example.com/users" || plan.Details["body"] != `{"name":"Ann"}` {
+		t.Errorf("POST plan = %+v, %v", plan, err)
+	}
+	plan, err = tool.Plan(map[string]interface{}{"url": "https://example.com/logo.png", "save_to": "logo.png"})
+	if err != nil || !plan.Mutates || plan.Details["save_to"] != "logo.png" {
+		t.Errorf("download plan = %+v, %v", plan, err)
+	}
+	plan, err = tool.Plan(map[string]interface{}{
+		"url":      "https://api.example.com/users",
+		"paginate": map[string]interface{}{"strategy": "page", "max_pages": float64(3)},
+	})
+	if err != nil || plan.Mutates || plan.Summary != "send GET https://api.example.com/users, repeated for up to 3 pages" {
+		t.Errorf("paginated plan = %+v, %v", plan, err)
+	}
+}
+
+func TestGitCommitPlan(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewGitCommitTool(createTestLogger(t), newGitTestValidator(dir))
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("one\n"), 0644)
+
+	plan, err := tool.Plan(map[string]interface{}{"path": dir, "message": "first", "init": true})
+	if err != nil || !plan.Mutates || plan.Details["init"] != true {
+		t.Fatalf("init plan = %+v, %v", plan, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Fatal("planning initialized the repository")
+	}
+
+	if resp, _ := tool.Execute(map[string]interface{}{"path": dir, "message": "first", "init": true}); resp.IsError {
+		t.Fatalf("commit failed: %s", resp.Content[0].Text)
+	}
+	os.WriteFile(filepath.Join(dir, "index.html"), []byte("two\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "about.html"), []byte("about\n"), 0644)
+
+	plan, err = tool.Plan(map[string]interface{}{"path": dir, "message": "second", "files": []interface{}{"about.html"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files := plan.Details["files"].([]string); len(files) != 1 || files[0] != "about.html" {
+		t.Errorf("planned files = %v", files)
+	}
+}
+
+func TestSQLiteQueryPlan(t *testing.T) {
+	dir := t.TempDir()
+	tool := NewSQLiteQueryTool(createTestLogger(t), newGitTestValidator(dir))
+
+	plan, err := tool.Plan(map[string]interface{}{"database": "data.db", "cwd": dir, "query": "DELETE FROM pages"})
+	if err != nil || plan.Mutates {
+		t.Errorf("without allow_writes nothing can change: %+v, %v", plan, err)
+	}
+	plan, err = tool.Plan(map[string]interface{}{"database": "data.db", "cwd": dir, "query": "DELETE FROM pages", "allow_writes": true})
+	if err != nil || !plan.Mutates {
+		t.Errorf("write plan = %+v, %v", plan, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "data.db")); !os.IsNotExist(err) {
+		t.Error("planning created the database")
+	}
+}
+
+func TestFormFillPlan(t *testing.T) {
+	tool := &FormFillTool{}
+	plan, _ := tool.Plan(map[string]interface{}{"fields": map[string]interface{}{"email": "a@b.c", "name": "Ann"}})
+	if plan.Mutates || plan.Summary != "fill 2 field(s) in form" {
+		t.Errorf("fill-only plan = %+v", plan)
+	}
+	plan, _ = tool.Plan(map[string]interface{}{"steps": []interface{}{
+		map[string]interface{}{"fields": map[string]interface{}{"email": "a@b.c"}, "next_selector": "#next"},
+	}})
+	if !plan.Mutates || !strings.HasSuffix(plan.Summary, "and submit it") {
+		t.Errorf("wizard plan = %+v", plan)
+	}
+}
+
+func TestFileWriterPlans(t *testing.T) {
+	dir := t.TempDir()
+	log := createTestLogger(t)
+	validator := newGitTestValidator(dir)
+	os.WriteFile(filepath.Join(dir, "session.enc"), []byte("old"), 0644)
+
+	type planner interface {
+		Plan(args map[string]interface{}) (*types.ToolPlan, error)
+	}
+	cases := []struct {
+		name   string
+		tool   planner
+		args   map[string]interface{}
+		path   string
+		action string
+	}{
+		{"create_page", NewCreatePageToolWithValidator(log, validator), map[string]interface{}{"filename": "index", "cwd": dir}, "index.html", "create"},
+		{"render_template", NewRenderTemplateTool(log, validator), map[string]interface{}{"template": "t.html", "output": "out.html", "cwd": dir}, "out.html", "create"},
+		{"save_scrape_recipe", NewSaveScrapeRecipeTool(log, dir), map[string]interface{}{"name": "products"}, "products.json", "create"},
+		{"export_session", NewExportSessionTool(log, nil, validator, "key"), map[string]interface{}{"path": "session.enc", "cwd": dir, "overwrite": true}, "session.enc", "overwrite"},
+		{"extract_table", NewExtractTableToolWithValidator(log, nil, validator), map[string]interface{}{"output_file": filepath.Join(dir, "rows.csv")}, "rows.csv", "create"},
+		{"screenshot", NewScreenshotToolWithOutputDir(log, nil, dir), map[string]interface{}{"filename": "shot.jpeg", "format": "jpeg"}, "shot.jpeg", "create"},
+	}
+	for _, c := range cases {
+		plan, err := c.tool.Plan(c.args)
+		if err != nil || !plan.Mutates || plan.Details["path"] != filepath.Join(dir, c.path) || plan.Details["action"] != c.action {
+			t.Errorf("%s: plan = %+v, %v", c.name, plan, err)
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("planning wrote files: %v", entries)
+	}
+
+	// The same tools only read without an output file
+	for _, tool := range []planner{NewExtractTableToolWithValidator(log, nil, validator), NewScreenshotToolWithOutputDir(log, nil, dir)} {
+		if plan, err := tool.Plan(map[string]interface{}{}); err != nil || plan.Mutates {
+			t.Errorf("%T without a file: plan = %+v, %v", tool, plan, err)
+		}
+	}
+	if _, err := NewExportSessionTool(log, nil, validator, "key").Plan(map[string]interface{}{"path": "session.enc", "cwd": dir}); err == nil {
+		t.Error("Expected export_session's plan to refuse replacing a file without overwrite")
+	}
+}
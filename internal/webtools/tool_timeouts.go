@@ -0,0 +1,40 @@
+package webtools
+
+import (
+	"sync"
+	"time"
+)
+
+// configuredTimeouts holds the operator's per-tool timeouts from the config
+// file, keyed by tool name with "default" for every other tool
+var configuredTimeouts struct {
+	mutex    sync.RWMutex
+	timeouts map[string]time.Duration
+}
+
+// SetToolTimeouts makes tools use the configured timeouts in place of their
+// built-in limits
+func SetToolTimeouts(timeouts map[string]time.Duration) {
+	configuredTimeouts.mutex.Lock()
+	defer configuredTimeouts.mutex.Unlock()
+	configuredTimeouts.timeouts = timeouts
+}
+
+// configuredTimeout returns the operator's timeout for a tool, if any
+func configuredTimeout(name string) (time.Duration, bool) {
+	configuredTimeouts.mutex.RLock()
+	defer configuredTimeouts.mutex.RUnlock()
+	if d, ok := configuredTimeouts.timeouts[name]; ok {
+		return d, true
+	}
+	d, ok := configuredTimeouts.timeouts["default"]
+	return d, ok
+}
+
+// toolTimeout returns the operator's timeout for a tool, or fallback
+func toolTimeout(name string, fallback time.Duration) time.Duration {
+	if d, ok := configuredTimeout(name); ok {
+		return d
+	}
+	return fallback
+}
@@ -59,13 +59,20 @@ func executeWithPanicRecovery(toolName string, logger *logger.Logger, operation
 
 // CreatePageTool creates HTML pages
 type CreatePageTool struct {
-	logger *logger.Logger
+	logger    *logger.Logger
+	validator *PathValidator // used to validate the optional cwd parameter
 }
 
 func NewCreatePageTool(log *logger.Logger) *CreatePageTool {
 	return &CreatePageTool{logger: log}
 }
 
+// NewCreatePageToolWithValidator creates a create_page tool whose cwd parameter
+// is checked against the given file access configuration
+func NewCreatePageToolWithValidator(log *logger.Logger, validator *PathValidator) *CreatePageTool {
+	return &CreatePageTool{logger: log, validator: validator}
+}
+
 func (t *CreatePageTool) Name() string {
 	return "create_page"
 }
@@ -103,6 +110,10 @@ func (t *CreatePageTool) InputSchema() types.ToolSchema {
 				"description": "JavaScript code for interactivity, event handlers, and dynamic behavior. Examples: 'document.querySelector(\".btn\").onclick = () => alert(\"Clicked!\");'",
 				"examples":    []string{"console.log('Page loaded');", "document.querySelector('.btn').onclick = () => alert('Hello!');"},
 			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Directory to create the page in (must be within allowed paths; defaults to the server's working directory)",
+			},
 		},
 		Required: []string{"filename", "title", "html"},
 	}
@@ -162,6 +173,28 @@ func (t *CreatePageTool) Execute(args map[string]interface{}) (*types.CallToolRe
 		filename += ".html"
 	}
 
+	// Resolve against the per-call working directory if one was given
+	if cwd, _ := args["cwd"].(string); cwd != "" {
+		validator := t.validator
+		if validator == nil {
+			validator = NewPathValidator(DefaultFileAccessConfig())
+		}
+		resolved, err := validator.ResolvePath(filename, cwd)
+		if err == nil {
+			err = validator.ValidatePath(resolved, "write")
+		}
+		if err != nil {
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Failed to create file: %v", err),
+				}},
+				IsError: true,
+			}, nil
+		}
+		filename = resolved
+	}
+
 	// Write to file
 	if err := os.WriteFile(filename, []byte(document), 0644); err != nil {
 		return &types.CallToolResponse{
@@ -186,12 +219,19 @@ func (t *CreatePageTool) Execute(args map[string]interface{}) (*types.CallToolRe
 
 // NavigatePageTool navigates browser to a page
 type NavigatePageTool struct {
-	logger  *logger.Logger
-	browser *browser.Manager
+	logger       *logger.Logger
+	browser      *browser.Manager
+	fingerprints *fingerprintStore
 }
 
 func NewNavigatePageTool(log *logger.Logger, browserMgr *browser.Manager) *NavigatePageTool {
-	return &NavigatePageTool{logger: log, browser: browserMgr}
+	return NewNavigatePageToolWithFingerprints(log, browserMgr, "")
+}
+
+// NewNavigatePageToolWithFingerprints creates a navigate_page tool that
+// applies fingerprint profiles from fingerprintDir (default: fingerprints/)
+func NewNavigatePageToolWithFingerprints(log *logger.Logger, browserMgr *browser.Manager, fingerprintDir string) *NavigatePageTool {
+	return &NavigatePageTool{logger: log, browser: browserMgr, fingerprints: newFingerprintStore(fingerprintDir)}
 }
 
 func (t *NavigatePageTool) Name() string {
@@ -199,7 +239,7 @@ func (t *NavigatePageTool) Name() string {
 }
 
 func (t *NavigatePageTool) Description() string {
-	return "Navigate browser to a URL or local file"
+	return "Navigate browser to a URL or local file. Reports CAPTCHA and anti-bot pages (Cloudflare, reCAPTCHA, hCaptcha, ...) in a blocked_by field"
 }
 
 func (t *NavigatePageTool) InputSchema() types.ToolSchema {
@@ -211,15 +251,25 @@ func (t *NavigatePageTool) InputSchema() types.ToolSchema {
 				"description": "URL or file path to navigate to. Supports HTTP/HTTPS URLs, local files (file://), and relative paths. Examples: 'https://example.com', 'localhost:3000', './index.html', 'file:///path/to/file.html'",
 				"examples":    []string{"https://example.com", "localhost:3000", "./index.html", "file:///home/user/page.html", "http://localhost:8080/dashboard"},
 			},
+			"fingerprint": map[string]interface{}{
+				"type":        "string",
+				"description": "Fingerprint profile (see fingerprint_profile) to present. By default a profile whose url_patterns match the URL is used, if any",
+			},
 		},
 		Required: []string{"url"},
 	}
 }
 
 func (t *NavigatePageTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops the navigation when the call ends
+func (t *NavigatePageTool) ExecuteContext(callCtx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
 		// Add total execution timeout to prevent hanging
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		timeout := toolTimeout(t.Name(), 15*time.Second)
+		ctx, cancel := context.WithTimeout(callCtx, timeout)
 		defer cancel()
 	
 	// Use a channel to handle timeout
@@ -247,7 +297,8 @@ func (t *NavigatePageTool) Execute(args map[string]interface{}) (*types.CallTool
 			return
 		}
 		
-		resp, err := t.executeNavigation(url)
+		fingerprint, _ := args["fingerprint"].(string)
+		resp, err := t.executeNavigation(ctx, url, fingerprint)
 		resultChan <- result{resp, err}
 	}()
 	
@@ -258,7 +309,7 @@ func (t *NavigatePageTool) Execute(args map[string]interface{}) (*types.CallTool
 		return &types.CallToolResponse{
 			Content: []types.ToolContent{{
 				Type: "text",
-				Text: "Navigation timed out after 15 seconds",
+				Text: fmt.Sprintf("Navigation timed out after %s", timeout),
 			}},
 			IsError: true,
 		}, nil
@@ -266,7 +317,7 @@ func (t *NavigatePageTool) Execute(args map[string]interface{}) (*types.CallTool
 	})
 }
 
-func (t *NavigatePageTool) executeNavigation(url string) (*types.CallToolResponse, error) {
+func (t *NavigatePageTool) executeNavigation(ctx context.Context, url, fingerprint string) (*types.CallToolResponse, error) {
 	// Handle local file paths
 	if !strings.HasPrefix(url, "http") {
 		if absPath, err := filepath.Abs(url); err == nil {
@@ -274,6 +325,21 @@ func (t *NavigatePageTool) executeNavigation(url string) (*types.CallToolRespons
 		}
 	}
 
+	profile, err := t.fingerprintFor(url, fingerprint)
+	if err != nil {
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: err.Error(),
+			}},
+			IsError: true,
+		}, nil
+	}
+	var opts browser.PageOptions
+	if profile != nil {
+		opts.Fingerprint = &profile.Fingerprint
+	}
+
 	// Check if there are existing pages, if so navigate the first one instead of creating new
 	pages := t.browser.ListPages()
 	var pageID string
@@ -281,18 +347,38 @@ func (t *NavigatePageTool) executeNavigation(url string) (*types.CallToolRespons
 	if len(pages) > 0 {
 		// Use existing page and navigate it to new URL
 		pageID = pages[0]
-		if err := t.browser.NavigateExistingPage(pageID, url); err != nil {
+		if opts.Fingerprint != nil {
+			if err := t.browser.ApplyFingerprint(pageID, opts.Fingerprint); err != nil {
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Failed to apply fingerprint profile %s: %v", profile.Name, err),
+					}},
+					IsError: true,
+				}, nil
+			}
+		}
+		if err := t.browser.NavigateExistingPageContext(ctx, pageID, url); err != nil {
+			// A challenge page that never finishes loading looks like a
+			// timeout; say what is actually in the way
+			var data map[string]interface{}
+			text := fmt.Sprintf("Failed to navigate to %s: %v", url, err)
+			if report := detectChallenge(t.browser, pageID); report != nil {
+				data = map[string]interface{}{"page_id": pageID, "blocked_by": report}
+				text = fmt.Sprintf("Failed to navigate to %s: %s", url, report.summary())
+			}
 			return &types.CallToolResponse{
 				Content: []types.ToolContent{{
 					Type: "text",
-					Text: fmt.Sprintf("Failed to navigate to %s: %v", url, err),
+					Text: text,
+					Data: data,
 				}},
 				IsError: true,
 			}, nil
 		}
 	} else {
 		// Create new page if none exist
-		_, newPageID, err := t.browser.NewPage(url)
+		_, newPageID, err := t.browser.NewPageWithOptionsContext(ctx, url, opts)
 		if err != nil {
 			return &types.CallToolResponse{
 				Content: []types.ToolContent{{
@@ -314,15 +400,52 @@ func (t *NavigatePageTool) executeNavigation(url string) (*types.CallToolRespons
 		}
 	}
 
+	text := fmt.Sprintf("Navigated to %s (Page ID: %s)", currentURL, pageID)
+	if report := detectChallenge(t.browser, pageID); report != nil {
+		if info == nil {
+			info = map[string]interface{}{}
+		}
+		if report.Blocking {
+			info["blocked_by"] = report
+		} else {
+			info["captcha"] = report
+		}
+		text += "; " + report.summary()
+	}
+	if profile != nil {
+		if info == nil {
+			info = map[string]interface{}{}
+		}
+		info["fingerprint"] = profile.Name
+	}
+
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
-			Text: fmt.Sprintf("Navigated to %s (Page ID: %s)", currentURL, pageID),
+			Text: text,
 			Data: info,
 		}},
 	}, nil
 }
 
+// fingerprintFor resolves the fingerprint profile to navigate with: the one
+// named, else one assigned to the URL, else none
+func (t *NavigatePageTool) fingerprintFor(url, name string) (*fingerprintProfile, error) {
+	if t.fingerprints == nil {
+		return nil, nil
+	}
+	if name != "" {
+		return t.fingerprints.load(name)
+	}
+	profile, err := t.fingerprints.findForURL(url)
+	if err != nil {
+		// An unreadable profile directory should not stop plain navigation
+		t.logger.WithComponent("tools").Warn("Failed to read fingerprint profiles", zap.Error(err))
+		return nil, nil
+	}
+	return profile, nil
+}
+
 // getPageInfoWithTimeout wraps GetPageInfo with a timeout to prevent hanging
 func (t *NavigatePageTool) getPageInfoWithTimeout(pageID string, timeout time.Duration) map[string]interface{} {
 	type infoResult struct {
@@ -358,13 +481,21 @@ type ScreenshotTool struct {
 	logger    *logger.Logger
 	browser   *browser.Manager
 	validator *PathValidator
+	outputDir string
 }
 
 func NewScreenshotTool(log *logger.Logger, browserMgr *browser.Manager) *ScreenshotTool {
+	return NewScreenshotToolWithOutputDir(log, browserMgr, "")
+}
+
+// NewScreenshotToolWithOutputDir creates a screenshot tool that saves into
+// outputDir, which is writable even when outside the allowed file paths
+func NewScreenshotToolWithOutputDir(log *logger.Logger, browserMgr *browser.Manager, outputDir string) *ScreenshotTool {
 	return &ScreenshotTool{
 		logger:    log,
 		browser:   browserMgr,
 		validator: NewPathValidator(DefaultFileAccessConfig()),
+		outputDir: outputDir,
 	}
 }
 
@@ -373,26 +504,51 @@ func (t *ScreenshotTool) Name() string {
 }
 
 func (t *ScreenshotTool) Description() string {
-	return "Take a screenshot of a browser page"
+	return "Take a screenshot of a browser page's viewport, or of the whole page with full_page"
 }
 
 func (t *ScreenshotTool) InputSchema() types.ToolSchema {
+	properties := screenshotOutputSchema()
+	properties["page_id"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Page ID to screenshot (optional, uses first page if not specified)",
+	}
+	properties["full_page"] = map[string]interface{}{
+		"type":        "boolean",
+		"description": "Capture the entire scroll height instead of just the viewport (default: false)",
+		"default":     false,
+	}
+	properties["max_height"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "Cut full-page screenshots off at this many CSS pixels; very tall captures are slow and huge",
+		"default":     browser.DefaultScreenshotMaxHeight,
+		"minimum":     1,
+		"maximum":     browser.MaxScreenshotHeight,
+	}
+	properties["format"] = map[string]interface{}{
+		"type":        "string",
+		"description": "Image format to capture and save in; jpeg and webp use quality (default 80)",
+		"enum":        browser.ScreenshotFormats,
+		"default":     "png",
+	}
+	properties["quality"] = map[string]interface{}{
+		"type":        "integer",
+		"description": "Compression quality (1-100). For jpeg and webp this is the capture quality; for png the returned image is converted to JPEG at this quality and the saved file stays PNG",
+		"minimum":     1,
+		"maximum":     100,
+	}
 	return types.ToolSchema{
-		Type: "object",
-		Properties: map[string]interface{}{
-			"page_id": map[string]interface{}{
-				"type":        "string",
-				"description": "Page ID to screenshot (optional, uses first page if not specified)",
-			},
-			"filename": map[string]interface{}{
-				"type":        "string",
-				"description": "Filename to save screenshot (optional)",
-			},
-		},
+		Type:       "object",
+		Properties: properties,
 	}
 }
 
 func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops the capture when the call ends
+func (t *ScreenshotTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
 		start := time.Now()
 		defer func() {
@@ -400,6 +556,21 @@ func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolRe
 			t.logger.LogToolExecution(t.Name(), args, true, duration)
 		}()
 
+	out, err := parseScreenshotOutput(args)
+	var opts browser.ScreenshotOptions
+	if err == nil {
+		opts, err = parseCaptureOptions(args, &out.shrink)
+	}
+	if err != nil {
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: err.Error(),
+			}},
+			IsError: true,
+		}, nil
+	}
+
 	pageID, ok := args["page_id"].(string)
 	if !ok || pageID == "" {
 		// Use first available page
@@ -416,7 +587,7 @@ func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolRe
 		pageID = pages[0]
 	}
 
-	screenshot, err := t.browser.Screenshot(pageID)
+	capture, err := t.browser.CaptureScreenshotContext(ctx, pageID, opts)
 	if err != nil {
 		return &types.CallToolResponse{
 			Content: []types.ToolContent{{
@@ -426,12 +597,17 @@ func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolRe
 			IsError: true,
 		}, nil
 	}
+	screenshot := capture.Data
+	// Full-page captures cut off at max_height say so, since the image
+	// alone doesn't show that the rest of the page is missing
+	var note string
+	if capture.Truncated() {
+		note = fmt.Sprintf(" (page is %dpx tall; captured the top %dpx, raise max_height for more)", capture.ContentHeight, capture.Height)
+	}
 
-	filename, _ := args["filename"].(string)
-	if filename != "" {
+	if cleanPath, trusted := resolveScreenshotPath(t.outputDir, out.filename, "screenshot", "."+capture.Format, out.save); cleanPath != "" {
 		// Validate file path for security
-		cleanPath := filepath.Clean(filename)
-		if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		if err := validateScreenshotPath(t.validator, cleanPath, trusted); err != nil {
 			t.logger.WithComponent("tools").Warn("Screenshot file access denied",
 				zap.String("path", cleanPath),
 				zap.Error(err))
@@ -476,7 +652,8 @@ func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolRe
 			}, nil
 		}
 
-		if err := os.WriteFile(cleanPath, screenshot, 0644); err != nil {
+		savedPath, err := writeScreenshotFile(cleanPath, screenshot)
+		if err != nil {
 			return &types.CallToolResponse{
 				Content: []types.ToolContent{{
 					Type: "text",
@@ -486,24 +663,53 @@ func (t *ScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolRe
 			}, nil
 		}
 
+		content := []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Screenshot saved to %s%s", savedPath, note),
+			Data: map[string]interface{}{
+				"path":       savedPath,
+				"page_id":    pageID,
+				"size_bytes": len(screenshot),
+				"format":     capture.Format,
+				"width":      capture.Width,
+				"height":     capture.Height,
+				"full_page":  opts.FullPage,
+				"truncated":  capture.Truncated(),
+			},
+		}}
+		if out.inline {
+			image, err := inlineImageContent(screenshot, out.shrink)
+			if err != nil {
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: fmt.Sprintf("Screenshot saved to %s but could not be returned inline: %v", savedPath, err),
+					}},
+					IsError: true,
+				}, nil
+			}
+			content = append(content, image)
+		}
+		return &types.CallToolResponse{Content: content}, nil
+	}
+
+	// Return base64 encoded image
+	image, err := inlineImageContent(screenshot, out.shrink)
+	if err != nil {
 		return &types.CallToolResponse{
 			Content: []types.ToolContent{{
 				Type: "text",
-				Text: fmt.Sprintf("Screenshot saved to %s", cleanPath),
+				Text: fmt.Sprintf("Failed to prepare screenshot: %v", err),
 			}},
+			IsError: true,
 		}, nil
 	}
 
-	// Return base64 encoded image
-	encoded := base64.StdEncoding.EncodeToString(screenshot)
-
-	return &types.CallToolResponse{
-		Content: []types.ToolContent{{
-			Type:     "image",
-			Data:     encoded,
-			MimeType: "image/png",
-		}},
-	}, nil
+	content := []types.ToolContent{image}
+	if note != "" {
+		content = append(content, types.ToolContent{Type: "text", Text: "Screenshot" + note})
+	}
+	return &types.CallToolResponse{Content: content}, nil
 	})
 }
 
@@ -512,13 +718,21 @@ type TakeElementScreenshotTool struct {
 	logger     *logger.Logger
 	browserMgr *browser.Manager
 	validator  *PathValidator
+	outputDir  string
 }
 
 func NewTakeElementScreenshotTool(log *logger.Logger, browserMgr *browser.Manager) *TakeElementScreenshotTool {
+	return NewTakeElementScreenshotToolWithOutputDir(log, browserMgr, "")
+}
+
+// NewTakeElementScreenshotToolWithOutputDir creates an element screenshot tool
+// that saves into outputDir, which is writable even when outside the allowed file paths
+func NewTakeElementScreenshotToolWithOutputDir(log *logger.Logger, browserMgr *browser.Manager, outputDir string) *TakeElementScreenshotTool {
 	return &TakeElementScreenshotTool{
 		logger:     log,
 		browserMgr: browserMgr,
 		validator:  NewPathValidator(DefaultFileAccessConfig()),
+		outputDir:  outputDir,
 	}
 }
 
@@ -531,51 +745,56 @@ func (t *TakeElementScreenshotTool) Description() string {
 }
 
 func (t *TakeElementScreenshotTool) InputSchema() types.ToolSchema {
-	return types.ToolSchema{
-		Type: "object",
-		Properties: map[string]interface{}{
-			"selector": map[string]interface{}{
-				"type":        "string",
-				"description": "CSS selector for the element to screenshot",
-			},
-			"page_id": map[string]interface{}{
-				"type":        "string",
-				"description": "Page ID to screenshot from (optional, uses current page if not specified)",
-			},
-			"filename": map[string]interface{}{
-				"type":        "string",
-				"description": "Filename to save screenshot (optional)",
-			},
-			"padding": map[string]interface{}{
-				"type":        "integer",
-				"description": "Padding around the element in pixels (default: 10)",
-				"default":     10,
-				"minimum":     0,
-				"maximum":     100,
-			},
-			"scroll_into_view": map[string]interface{}{
-				"type":        "boolean",
-				"description": "Scroll element into view before screenshot (default: true)",
-				"default":     true,
-			},
-			"wait_for_element": map[string]interface{}{
-				"type":        "boolean",
-				"description": "Wait for element to be visible before screenshot (default: true)",
-				"default":     true,
-			},
-			"timeout": map[string]interface{}{
-				"type":        "integer",
-				"description": "Maximum time to wait for element in seconds (default: 10)",
-				"default":     10,
-				"minimum":     1,
-				"maximum":     60,
-			},
+	properties := map[string]interface{}{
+		"selector": map[string]interface{}{
+			"type":        "string",
+			"description": "CSS selector for the element to screenshot",
 		},
-		Required: []string{"selector"},
+		"page_id": map[string]interface{}{
+			"type":        "string",
+			"description": "Page ID to screenshot from (optional, uses current page if not specified)",
+		},
+		"padding": map[string]interface{}{
+			"type":        "integer",
+			"description": "Padding around the element in pixels (default: 10)",
+			"default":     10,
+			"minimum":     0,
+			"maximum":     100,
+		},
+		"scroll_into_view": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Scroll element into view before screenshot (default: true)",
+			"default":     true,
+		},
+		"wait_for_element": map[string]interface{}{
+			"type":        "boolean",
+			"description": "Wait for element to be visible before screenshot (default: true)",
+			"default":     true,
+		},
+		"timeout": map[string]interface{}{
+			"type":        "integer",
+			"description": "Maximum time to wait for element in seconds (default: 10)",
+			"default":     10,
+			"minimum":     1,
+			"maximum":     60,
+		},
+	}
+	for name, prop := range screenshotOutputSchema() {
+		properties[name] = prop
+	}
+	return types.ToolSchema{
+		Type:       "object",
+		Properties: properties,
+		Required:   []string{"selector"},
 	}
 }
 
 func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops the capture when the call ends
+func (t *TakeElementScreenshotTool) ExecuteContext(callCtx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
 		start := time.Now()
 		defer func() {
@@ -584,7 +803,7 @@ func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types
 		}()
 
 	// Add timeout protection
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	ctx, cancel := context.WithTimeout(callCtx, 60*time.Second)
 	defer cancel()
 
 	// Parse arguments
@@ -594,7 +813,10 @@ func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types
 	}
 
 	pageID, _ := args["page_id"].(string)
-	filename, _ := args["filename"].(string)
+	out, err := parseScreenshotOutput(args)
+	if err != nil {
+		return nil, err
+	}
 
 	padding := 10
 	if val, ok := args["padding"].(float64); ok {
@@ -621,7 +843,7 @@ func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types
 	errorChan := make(chan error, 1)
 
 	go func() {
-		result, err := t.captureElementScreenshot(pageID, selector, filename, padding, scrollIntoView, waitForElement, timeout)
+		result, err := t.captureElementScreenshot(ctx, pageID, selector, out, padding, scrollIntoView, waitForElement, timeout)
 		if err != nil {
 			errorChan <- err
 			return
@@ -632,6 +854,9 @@ func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types
 	// Wait for result or timeout
 	select {
 	case <-ctx.Done():
+		if callCtx.Err() != nil {
+			return nil, fmt.Errorf("element screenshot stopped: %w", callCtx.Err())
+		}
 		return nil, fmt.Errorf("element screenshot operation timed out after 60 seconds")
 	case err := <-errorChan:
 		return nil, err
@@ -641,7 +866,7 @@ func (t *TakeElementScreenshotTool) Execute(args map[string]interface{}) (*types
 	})
 }
 
-func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, filename string, padding int, scrollIntoView, waitForElement bool, timeout int) (*types.CallToolResponse, error) {
+func (t *TakeElementScreenshotTool) captureElementScreenshot(ctx context.Context, pageID, selector string, out screenshotOutput, padding int, scrollIntoView, waitForElement bool, timeout int) (*types.CallToolResponse, error) {
 	// First, find and prepare the element
 	script := fmt.Sprintf(`
 		// Find the target element
@@ -724,7 +949,7 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 	scrollIntoView,
 	padding)
 
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
+	result, err := t.browserMgr.ExecuteScriptContext(ctx, pageID, script, browser.ScriptTimeout)
 	if err != nil {
 		return nil, fmt.Errorf("failed to prepare element for screenshot: %w", err)
 	}
@@ -759,7 +984,7 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 	elementInfo, _ := jsResult["element_info"].(map[string]interface{})
 
 	// Take the full page screenshot first
-	fullScreenshot, err := t.browserMgr.Screenshot(pageID)
+	fullScreenshot, err := t.browserMgr.ScreenshotContext(ctx, pageID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to take full page screenshot: %w", err)
 	}
@@ -767,11 +992,10 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 	// For now, we'll return the full screenshot with bounds info
 	// TODO: In a future enhancement, we could crop the image to just the element bounds
 	
-	// If filename is provided, save the screenshot
-	if filename != "" {
+	// If filename is provided or save requested, save the screenshot
+	if cleanPath, trusted := resolveScreenshotPath(t.outputDir, out.filename, "element", ".png", out.save); cleanPath != "" {
 		// Validate file path for security
-		cleanPath := filepath.Clean(filename)
-		if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
+		if err := validateScreenshotPath(t.validator, cleanPath, trusted); err != nil {
 			t.logger.WithComponent("tools").Warn("Element screenshot file access denied",
 				zap.String("path", cleanPath),
 				zap.Error(err))
@@ -816,7 +1040,8 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 			}, nil
 		}
 
-		if err := os.WriteFile(cleanPath, fullScreenshot, 0644); err != nil {
+		savedPath, err := writeScreenshotFile(cleanPath, fullScreenshot)
+		if err != nil {
 			return &types.CallToolResponse{
 				Content: []types.ToolContent{{
 					Type: "text",
@@ -826,7 +1051,7 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 			}, nil
 		}
 
-		responseText := fmt.Sprintf("Element screenshot saved to %s", cleanPath)
+		responseText := fmt.Sprintf("Element screenshot saved to %s", savedPath)
 		if elementInfo != nil {
 			responseText += fmt.Sprintf("\n\nElement details:\n- Tag: %v\n- ID: %v\n- Classes: %v",
 				elementInfo["tag_name"], elementInfo["id"], elementInfo["class_name"])
@@ -839,21 +1064,31 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 				boundsData["x"], boundsData["y"], boundsData["width"], boundsData["height"])
 		}
 
-		return &types.CallToolResponse{
-			Content: []types.ToolContent{{
-				Type: "text",
-				Text: responseText,
-				Data: map[string]interface{}{
-					"filename": cleanPath,
-					"bounds":   boundsData,
-					"element":  elementInfo,
-				},
-			}},
-		}, nil
+		content := []types.ToolContent{{
+			Type: "text",
+			Text: responseText,
+			Data: map[string]interface{}{
+				"filename": cleanPath,
+				"path":     savedPath,
+				"bounds":   boundsData,
+				"element":  elementInfo,
+			},
+		}}
+		if out.inline {
+			image, err := inlineImageContent(fullScreenshot, out.shrink)
+			if err != nil {
+				return nil, fmt.Errorf("element screenshot saved to %s but could not be returned inline: %w", savedPath, err)
+			}
+			content = append(content, image)
+		}
+		return &types.CallToolResponse{Content: content}, nil
 	}
 
 	// Return base64 encoded image with element metadata
-	encoded := base64.StdEncoding.EncodeToString(fullScreenshot)
+	image, err := inlineImageContent(fullScreenshot, out.shrink)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare element screenshot: %w", err)
+	}
 	
 	responseText := "Element screenshot captured"
 	if elementInfo != nil {
@@ -867,11 +1102,7 @@ func (t *TakeElementScreenshotTool) captureElementScreenshot(pageID, selector, f
 	}
 
 	return &types.CallToolResponse{
-		Content: []types.ToolContent{{
-			Type:     "image",
-			Data:     encoded,
-			MimeType: "image/png",
-		}},
+		Content: []types.ToolContent{image},
 	}, nil
 }
 
@@ -896,6 +1127,15 @@ func (t *KeyboardShortcutTool) Description() string {
 	return "Send keyboard combinations and special keys like Ctrl+C/V, F5, Tab, Enter, etc."
 }
 
+// Meta records the 2.0.0 rename of element_selector to selector, matching
+// every other tool that targets an element
+func (t *KeyboardShortcutTool) Meta() types.ToolMeta {
+	return types.ToolMeta{
+		Version:           "2.0.0",
+		RenamedParameters: map[string]string{"element_selector": "selector"},
+	}
+}
+
 func (t *KeyboardShortcutTool) InputSchema() types.ToolSchema {
 	return types.ToolSchema{
 		Type: "object",
@@ -908,7 +1148,7 @@ func (t *KeyboardShortcutTool) InputSchema() types.ToolSchema {
 				"type":        "string",
 				"description": "Page ID to send keys to (optional, uses current page if not specified)",
 			},
-			"element_selector": map[string]interface{}{
+			"selector": map[string]interface{}{
 				"type":        "string",
 				"description": "CSS selector for element to focus before sending keys (optional)",
 			},
@@ -949,7 +1189,7 @@ func (t *KeyboardShortcutTool) Execute(args map[string]interface{}) (*types.Call
 	}
 
 	pageID, _ := args["page_id"].(string)
-	elementSelector, _ := args["element_selector"].(string)
+	elementSelector, _ := args["selector"].(string)
 
 	repeat := 1
 	if val, ok := args["repeat"].(float64); ok {
@@ -1222,15 +1462,40 @@ func (t *ExecuteScriptTool) InputSchema() types.ToolSchema {
 				"type":        "string",
 				"description": "JavaScript code to execute",
 			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Seconds the script may run (default: 10, or the configured execute_script timeout)",
+				"minimum":     1,
+				"maximum":     600,
+			},
 		},
 		Required: []string{"script"},
 	}
 }
 
 func (t *ExecuteScriptTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting on the script when the call ends
+func (t *ExecuteScriptTool) ExecuteContext(callCtx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		// The script's own limit comes from the call, else the config;
+		// the whole call gets a little longer to report a script timeout
+		scriptTimeout := browser.ScriptTimeout
+		if d, ok := configuredTimeout(t.Name()); ok {
+			scriptTimeout = d
+		}
+		timeout := toolTimeout(t.Name(), 30*time.Second)
+		if seconds, ok := args["timeout"].(float64); ok && seconds > 0 {
+			scriptTimeout = time.Duration(seconds * float64(time.Second))
+			if scriptTimeout+5*time.Second > timeout {
+				timeout = scriptTimeout + 5*time.Second
+			}
+		}
+
 		// Add total execution timeout to prevent hanging
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(callCtx, timeout)
 		defer cancel()
 	
 	// Use a channel to handle timeout
@@ -1264,7 +1529,7 @@ func (t *ExecuteScriptTool) Execute(args map[string]interface{}) (*types.CallToo
 			return
 		}
 
-		scriptResult, err := t.browser.ExecuteScript(pageID, script)
+		scriptResult, err := t.browser.ExecuteScriptContext(ctx, pageID, script, scriptTimeout)
 		if err != nil {
 			resultChan <- result{&types.CallToolResponse{
 				Content: []types.ToolContent{{
@@ -1292,7 +1557,7 @@ func (t *ExecuteScriptTool) Execute(args map[string]interface{}) (*types.CallToo
 		return &types.CallToolResponse{
 			Content: []types.ToolContent{{
 				Type: "text",
-				Text: "Script execution timed out after 30 seconds",
+				Text: fmt.Sprintf("Script execution timed out after %s", timeout),
 			}},
 			IsError: true,
 		}, nil
@@ -1511,7 +1776,13 @@ func (t *ReadFileTool) InputSchema() types.ToolSchema {
 			"path": map[string]interface{}{
 				"type":        "string",
 				"description": "Path to the file to read",
+				"examples":    []string{"index.html", "./src/components/header.js"},
+			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths; defaults to the server's working directory)",
 			},
+			"encoding": textEncodingSchema("the file"),
 		},
 		Required: []string{"path"},
 	}
@@ -1524,9 +1795,17 @@ func (t *ReadFileTool) Execute(args map[string]interface{}) (*types.CallToolResp
 	if !ok {
 		return nil, fmt.Errorf("path must be a string")
 	}
+	label, err := parseTextEncoding(args)
+	if err != nil {
+		return nil, err
+	}
 
-	// Clean the path to prevent directory traversal attacks
-	cleanPath := filepath.Clean(pathStr)
+	// Resolve against the per-call working directory and clean the path
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
 	
 	// Validate path access permissions
 	if err := t.validator.ValidatePath(cleanPath, "read"); err != nil {
@@ -1581,20 +1860,27 @@ func (t *ReadFileTool) Execute(args map[string]interface{}) (*types.CallToolResp
 		return nil, fmt.Errorf("failed to read file %s: %w", cleanPath, err)
 	}
 
+	// Files carry no Content-Type, so only the bytes say how to read them
+	text, encodingName, err := decodeText(content, "", label)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", cleanPath, err)
+	}
+
 	duration := time.Since(start).Milliseconds()
 	t.logger.WithComponent("tools").Info("File read successfully",
 		zap.String("path", cleanPath),
 		zap.Int("size_bytes", len(content)),
+		zap.String("encoding", encodingName),
 		zap.Int64("duration_ms", duration))
 
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
-			Text: string(content),
+			Text: text,
 			Data: map[string]interface{}{
 				"path":       cleanPath,
 				"size_bytes": len(content),
-				"encoding":   "utf-8",
+				"encoding":   encodingName,
 			},
 		}},
 	}, nil
@@ -1632,6 +1918,10 @@ func (t *WriteFileTool) InputSchema() types.ToolSchema {
 				"type":        "string",
 				"description": "Path to the file to write",
 			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths; defaults to the server's working directory)",
+			},
 			"content": map[string]interface{}{
 				"type":        "string",
 				"description": "Content to write to the file",
@@ -1664,8 +1954,12 @@ func (t *WriteFileTool) Execute(args map[string]interface{}) (*types.CallToolRes
 		createDirs = val
 	}
 
-	// Clean the path
-	cleanPath := filepath.Clean(pathStr)
+	// Resolve against the per-call working directory and clean the path
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("file access denied: %w", err)
+	}
 	
 	// Validate path access permissions
 	if err := t.validator.ValidatePath(cleanPath, "write"); err != nil {
@@ -1792,6 +2086,10 @@ func (t *ListDirectoryTool) InputSchema() types.ToolSchema {
 				"description": "Include hidden files (starting with .)",
 				"default":     false,
 			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that relative paths are resolved against (must be within allowed paths; defaults to the server's working directory)",
+			},
 		},
 	}
 }
@@ -1809,8 +2107,12 @@ func (t *ListDirectoryTool) Execute(args map[string]interface{}) (*types.CallToo
 		showHidden = val
 	}
 
-	// Clean the path
-	cleanPath := filepath.Clean(pathStr)
+	// Resolve against the per-call working directory and clean the path
+	cwd, _ := args["cwd"].(string)
+	cleanPath, err := t.validator.ResolvePath(pathStr, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("directory access denied: %w", err)
+	}
 	
 	// Validate path access permissions
 	if err := t.validator.ValidatePath(cleanPath, "read"); err != nil {
@@ -1898,11 +2200,18 @@ func (t *ListDirectoryTool) Execute(args map[string]interface{}) (*types.CallToo
 
 // HTTPRequestTool makes HTTP requests
 type HTTPRequestTool struct {
-	logger *logger.Logger
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	validator  *PathValidator
 }
 
-func NewHTTPRequestTool(log *logger.Logger) *HTTPRequestTool {
-	return &HTTPRequestTool{logger: log}
+// NewHTTPRequestTool builds http_request. mgr is only needed for extract
+// and may be nil.
+func NewHTTPRequestTool(log *logger.Logger, mgr *browser.Manager, validator *PathValidator) *HTTPRequestTool {
+	if validator == nil {
+		validator = NewPathValidator(DefaultFileAccessConfig())
+	}
+	return &HTTPRequestTool{logger: log, browserMgr: mgr, validator: validator}
 }
 
 func (t *HTTPRequestTool) Name() string {
@@ -1910,7 +2219,7 @@ func (t *HTTPRequestTool) Name() string {
 }
 
 func (t *HTTPRequestTool) Description() string {
-	return "Make HTTP requests (GET, POST, PUT, DELETE, etc.)"
+	return "Make HTTP requests (GET, POST, PUT, DELETE, etc.). JSON responses come back parsed under json, HTML and XML can be narrowed with extract, binary bodies are returned as base64 or written to save_to, and paginate follows an API's pages and merges their results"
 }
 
 func (t *HTTPRequestTool) InputSchema() types.ToolSchema {
@@ -1920,6 +2229,7 @@ func (t *HTTPRequestTool) InputSchema() types.ToolSchema {
 			"url": map[string]interface{}{
 				"type":        "string",
 				"description": "URL to request",
+				"examples":    []string{"https://api.example.com/users"},
 			},
 			"method": map[string]interface{}{
 				"type":        "string",
@@ -1938,12 +2248,26 @@ func (t *HTTPRequestTool) InputSchema() types.ToolSchema {
 			"json": map[string]interface{}{
 				"type":        "object",
 				"description": "JSON data to send (will set Content-Type: application/json)",
+				"examples":    []interface{}{map[string]interface{}{"name": "John"}},
 			},
 			"timeout": map[string]interface{}{
 				"type":        "integer",
 				"description": "Request timeout in seconds",
 				"default":     30,
 			},
+			"encoding": textEncodingSchema("the response body"),
+			"extract":  extractSchema(),
+			"save_to": map[string]interface{}{
+				"type":        "string",
+				"description": "Write the raw response body to this file, e.g. to download an image or PDF. The body is then left out of the response",
+				"examples":    []string{"downloads/logo.png", "report.pdf"},
+			},
+			"cwd": map[string]interface{}{
+				"type":        "string",
+				"description": "Working directory that a relative save_to is resolved against (must be within allowed paths; defaults to the server's working directory)",
+			},
+			"paginate":  paginateSchema(),
+			"transform": transformSchema("the response body (the extracted fields, the merged pages, parsed JSON, or the text otherwise)"),
 		},
 		Required: []string{"url"},
 	}
@@ -1967,8 +2291,45 @@ func (t *HTTPRequestTool) Execute(args map[string]interface{}) (*types.CallToolR
 		timeout = int(val)
 	}
 
-	var body io.Reader
+	transform, err := parseTransform(args)
+	if err != nil {
+		return nil, err
+	}
+	label, err := parseTextEncoding(args)
+	if err != nil {
+		return nil, err
+	}
+	var extractFields []scrapeField
+	if raw, ok := args["extract"]; ok && raw != nil {
+		selectors, ok := raw.(map[string]interface{})
+		if !ok || len(selectors) == 0 {
+			return nil, fmt.Errorf("extract must map field names to selectors")
+		}
+		if extractFields, err = parseScrapeFields(selectors); err != nil {
+			return nil, err
+		}
+	}
+	if saveTo, ok := args["save_to"]; ok {
+		if s, ok := saveTo.(string); !ok || s == "" {
+			return nil, fmt.Errorf("save_to must be a file path")
+		}
+	}
+	pagination, err := parsePagination(args)
+	if err != nil {
+		return nil, err
+	}
+	if pagination != nil {
+		if _, ok := args["save_to"]; ok {
+			return nil, fmt.Errorf("paginate cannot be combined with save_to")
+		}
+		if extractFields != nil {
+			return nil, fmt.Errorf("paginate works on JSON APIs and cannot be combined with extract")
+		}
+	}
+
+	var bodyBytes []byte
 	var bodyContent string
+	hasBody := false
 
 	// Handle JSON body
 	if jsonData, ok := args["json"]; ok {
@@ -1976,53 +2337,64 @@ func (t *HTTPRequestTool) Execute(args map[string]interface{}) (*types.CallToolR
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		body = bytes.NewReader(jsonBytes)
+		bodyBytes, hasBody = jsonBytes, true
 		bodyContent = string(jsonBytes)
 	} else if bodyStr, ok := args["body"].(string); ok {
-		body = strings.NewReader(bodyStr)
+		bodyBytes, hasBody = []byte(bodyStr), true
 		bodyContent = bodyStr
 	}
 
-	// Create request
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	// Create client with timeout
+	client := &http.Client{
+		Timeout:   time.Duration(timeout) * time.Second,
+		Transport: meteredTransport(nil),
 	}
 
-	// Set headers
-	if headers, ok := args["headers"].(map[string]interface{}); ok {
-		for key, value := range headers {
-			if valueStr, ok := value.(string); ok {
-				req.Header.Set(key, valueStr)
+	// send makes the request to requestURL; paginate repeats it for each page
+	send := func(requestURL string) (*http.Response, []byte, error) {
+		var body io.Reader
+		if hasBody {
+			body = bytes.NewReader(bodyBytes)
+		}
+		req, err := http.NewRequest(method, requestURL, body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Set headers
+		if headers, ok := args["headers"].(map[string]interface{}); ok {
+			for key, value := range headers {
+				if valueStr, ok := value.(string); ok {
+					req.Header.Set(key, valueStr)
+				}
 			}
 		}
-	}
 
-	// Set Content-Type for JSON
-	if _, hasJSON := args["json"]; hasJSON {
-		req.Header.Set("Content-Type", "application/json")
-	}
+		// Set Content-Type for JSON
+		if _, hasJSON := args["json"]; hasJSON {
+			req.Header.Set("Content-Type", "application/json")
+		}
 
-	// Create client with timeout
-	client := &http.Client{
-		Timeout: time.Duration(timeout) * time.Second,
-	}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.logger.WithComponent("tools").Error("HTTP request failed",
+				zap.String("url", requestURL),
+				zap.String("method", method),
+				zap.Error(err))
+			return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
+		}
+		defer resp.Body.Close()
 
-	// Make request
-	resp, err := client.Do(req)
-	if err != nil {
-		t.logger.WithComponent("tools").Error("HTTP request failed",
-			zap.String("url", url),
-			zap.String("method", method),
-			zap.Error(err))
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		responseBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+		}
+		return resp, responseBody, nil
 	}
-	defer resp.Body.Close()
 
-	// Read response
-	responseBody, err := io.ReadAll(resp.Body)
+	resp, responseBody, err := send(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
 	duration := time.Since(start).Milliseconds()
@@ -2048,25 +2420,147 @@ func (t *HTTPRequestTool) Execute(args map[string]interface{}) (*types.CallToolR
 		responseText += fmt.Sprintf("  %s: %s\n", key, value)
 	}
 	
-	responseText += fmt.Sprintf("\nBody:\n%s", string(responseBody))
+	contentType := resp.Header.Get("Content-Type")
+	mediaType := responseMediaType(contentType, responseBody)
+	kind := responseBodyKind(mediaType)
+
+	data := map[string]interface{}{
+		"url":           url,
+		"method":        method,
+		"status_code":   resp.StatusCode,
+		"status":        resp.Status,
+		"headers":       responseHeaders,
+		"content_type":  mediaType,
+		"body_kind":     kind,
+		"response_size": len(responseBody),
+		"duration_ms":   duration,
+		"request_body":  bodyContent,
+	}
+
+	var savedPath string
+	if _, ok := args["save_to"]; ok {
+		if savedPath, err = t.saveResponseBody(args, responseBody); err != nil {
+			return nil, err
+		}
+		data["saved_to"] = savedPath
+	}
 
-	return &types.CallToolResponse{
-		Content: []types.ToolContent{{
-			Type: "text",
-			Text: responseText,
-			Data: map[string]interface{}{
-				"url":            url,
-				"method":         method,
-				"status_code":    resp.StatusCode,
-				"status":         resp.Status,
-				"headers":        responseHeaders,
-				"body":           string(responseBody),
-				"response_size":  len(responseBody),
-				"duration_ms":    duration,
-				"request_body":   bodyContent,
-			},
-		}},
-	}, nil
+	// Text bodies are converted to UTF-8 from whatever the server sent;
+	// binary ones are passed through as they are
+	bodyText, charsetName := "", ""
+	if label != "" || kind != bodyKindBinary {
+		if bodyText, charsetName, err = decodeText(responseBody, contentType, label); err != nil {
+			return nil, err
+		}
+	} else if transform != nil || extractFields != nil {
+		return nil, fmt.Errorf("the response is %s, which transform and extract cannot read", mediaType)
+	}
+
+	// The value transform sees: extracted fields, parsed JSON or the text
+	var value interface{} = bodyText
+	if kind == bodyKindJSON {
+		var parsed interface{}
+		if json.Unmarshal([]byte(bodyText), &parsed) == nil {
+			data["json"] = parsed
+			value = parsed
+		}
+	} else if transform != nil {
+		var parsed interface{}
+		if json.Unmarshal([]byte(bodyText), &parsed) == nil {
+			value = parsed
+		}
+	}
+	// Later pages' results are merged with the first page's
+	var paginationSummary map[string]interface{}
+	if pagination != nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		firstBody := data["json"]
+		if firstBody == nil {
+			// Some APIs label their JSON as text
+			json.Unmarshal([]byte(bodyText), &firstBody)
+		}
+		if firstBody == nil {
+			return nil, fmt.Errorf("paginate needs a JSON response, not %s", mediaType)
+		}
+		first := &jsonPage{header: resp.Header, status: resp.StatusCode, body: firstBody, size: len(responseBody)}
+		items, summary, err := pagination.follow(url, first, func(pageURL string) (*jsonPage, error) {
+			pageResp, pageBody, err := send(pageURL)
+			if err != nil {
+				return nil, err
+			}
+			page := &jsonPage{header: pageResp.Header, status: pageResp.StatusCode, size: len(pageBody)}
+			if text, _, err := decodeText(pageBody, pageResp.Header.Get("Content-Type"), label); err == nil {
+				var parsed interface{}
+				if json.Unmarshal([]byte(text), &parsed) == nil {
+					page.body = parsed
+				}
+			}
+			return page, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		data["json"] = items
+		data["pagination"] = summary
+		value = items
+		paginationSummary = summary
+		responseText += fmt.Sprintf("\nPagination: %d items from %d pages (stopped: %s)\n", len(items), summary["pages"], summary["stopped"])
+	}
+	if extractFields != nil {
+		if kind != bodyKindHTML && kind != bodyKindXML {
+			return nil, fmt.Errorf("extract needs an HTML or XML response, not %s", mediaType)
+		}
+		extracted, err := t.extractFromBody(bodyText, kind, url, extractFields)
+		if err != nil {
+			return nil, err
+		}
+		data["extracted"] = extracted
+		value = extracted
+	}
+
+	switch {
+	case transform != nil:
+		transformed, err := transform.apply(value)
+		if err != nil {
+			return nil, err
+		}
+		pretty, _ := json.MarshalIndent(transformed, "", "  ")
+		responseText += fmt.Sprintf("\nTransformed body:\n%s", pretty)
+		data["body"] = transformed
+		delete(data, "json")
+		delete(data, "extracted")
+	case extractFields != nil:
+		pretty, _ := json.MarshalIndent(value, "", "  ")
+		responseText += fmt.Sprintf("\nExtracted:\n%s", pretty)
+	case paginationSummary != nil:
+		pretty, _ := json.MarshalIndent(value, "", "  ")
+		responseText += fmt.Sprintf("\nItems:\n%s", pretty)
+	case savedPath != "":
+		responseText += fmt.Sprintf("\nBody: %d bytes of %s saved to %s", len(responseBody), mediaType, savedPath)
+	case kind == bodyKindBinary:
+		if len(responseBody) > maxInlineBinaryBytes {
+			responseText += fmt.Sprintf("\nBody: %d bytes of %s, too large to return inline; pass save_to to keep it", len(responseBody), mediaType)
+		} else {
+			data["body_base64"] = base64.StdEncoding.EncodeToString(responseBody)
+			responseText += fmt.Sprintf("\nBody: %d bytes of %s, returned as body_base64", len(responseBody), mediaType)
+		}
+	default:
+		data["body"] = bodyText
+		responseText += fmt.Sprintf("\nBody:\n%s", bodyText)
+	}
+
+	if charsetName != "" {
+		data["charset"] = charsetName
+	}
+	if transform != nil {
+		data["transformed"] = true
+	}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: responseText,
+			Data: data,
+		}},
+	}, nil
 }
 
 // ClickElementTool clicks on browser elements
@@ -2084,7 +2578,7 @@ func (t *ClickElementTool) Name() string {
 }
 
 func (t *ClickElementTool) Description() string {
-	return "Click on a browser element using CSS selector"
+	return "Click on a browser element using a CSS selector or XPath. The click is sent as a real mouse event after scrolling the element into view"
 }
 
 func (t *ClickElementTool) InputSchema() types.ToolSchema {
@@ -2093,13 +2587,14 @@ func (t *ClickElementTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector or XPath (prefix with //) for the element to click. CSS selectors: #id (ID), .class (class), tag (element), [attr] (attribute). XPath: //tag[@attr='value'] or //text()='content'. Examples: '#submit-btn', '.nav-link', 'button[type=\"submit\"]', '//button[text()=\"Login\"]'",
+				"description": "Element to click. " + selectorSyntax + ". Examples: '#submit-btn', '.nav-link', 'button[type=\"submit\"]', '//button[text()=\"Login\"]', 'text=Log in'",
 				"examples":    []string{"#submit-button", ".btn-primary", "button[type='submit']", "input[value='Submit']", "//button[contains(text(), 'Login')]", ".modal .close-btn"},
 			},
 			"page_id": map[string]interface{}{
 				"type":        "string",
 				"description": "Page ID to click on (optional, uses current active page if not specified). Get page IDs from switch_tab list action",
 			},
+			"frame_selector": frameSelectorSchema(),
 			"timeout": map[string]interface{}{
 				"type":        "integer",
 				"description": "Maximum seconds to wait for element to become clickable. Use 2-5s for static elements, 5-10s for dynamic content, 10-30s for heavy AJAX (default: 10)",
@@ -2114,6 +2609,11 @@ func (t *ClickElementTool) InputSchema() types.ToolSchema {
 }
 
 func (t *ClickElementTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *ClickElementTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
 		start := time.Now()
 	
@@ -2125,15 +2625,19 @@ func (t *ClickElementTool) Execute(args map[string]interface{}) (*types.CallTool
 	if err := ValidateSelector(selector, t.Name()); err != nil {
 		return nil, err
 	}
+	frames, err := parseFrameSelector(args)
+	if err != nil {
+		return nil, err
+	}
 
 	pageID := ""
 	if val, ok := args["page_id"].(string); ok {
 		pageID = val
 	}
 
-	_ = 10 // timeout for future use
-	if _, ok := args["timeout"].(float64); ok {
-		// timeout = int(val) // for future use
+	timeout := browser.ElementTimeout
+	if val, ok := args["timeout"].(float64); ok && val > 0 {
+		timeout = time.Duration(val * float64(time.Second))
 	}
 
 	// Get the page ID to use
@@ -2146,18 +2650,7 @@ func (t *ClickElementTool) Execute(args map[string]interface{}) (*types.CallTool
 		pageID = pages[0]
 	}
 
-	// For now, use execute_script as the underlying mechanism until we have direct Rod access
-	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
-		if (!element) {
-			throw new Error('Element not found with selector: %s');
-		}
-		element.click();
-		return 'Clicked element: ' + '%s';
-	`, selector, selector, selector)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
-	if err != nil {
+	if err := t.browserMgr.ClickContext(ctx, pageID, frames, selector, timeout); err != nil {
 		t.logger.WithComponent("tools").Error("Failed to click element",
 			zap.String("selector", selector),
 			zap.Error(err))
@@ -2169,16 +2662,17 @@ func (t *ClickElementTool) Execute(args map[string]interface{}) (*types.CallTool
 		zap.String("selector", selector),
 		zap.Int64("duration_ms", duration))
 
+	data := map[string]interface{}{
+		"selector":    selector,
+		"page_id":     pageID,
+		"duration_ms": duration,
+	}
+	addFrameData(data, frames)
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
 			Text: fmt.Sprintf("Successfully clicked element: %s", selector),
-			Data: map[string]interface{}{
-				"selector":    selector,
-				"page_id":     pageID,
-				"duration_ms": duration,
-				"result":      result,
-			},
+			Data: data,
 		}},
 	}, nil
 	})
@@ -2199,7 +2693,7 @@ func (t *TypeTextTool) Name() string {
 }
 
 func (t *TypeTextTool) Description() string {
-	return "Type text into an input field or textarea"
+	return "Type text into an input field or textarea through browser input events"
 }
 
 func (t *TypeTextTool) InputSchema() types.ToolSchema {
@@ -2208,7 +2702,7 @@ func (t *TypeTextTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the input element (input, textarea, contenteditable). Examples: 'input[name=\"email\"]', '#password', '.search-box', 'textarea[placeholder=\"Message\"]'",
+				"description": "Input element (input, textarea, contenteditable). " + selectorSyntax + ". Examples: 'input[name=\"email\"]', '#password', '.search-box', 'textarea[placeholder=\"Message\"]'",
 				"examples":    []string{"input[name='email']", "#username", ".search-input", "textarea[placeholder='Message']", "input[type='password']"},
 			},
 			"text": map[string]interface{}{
@@ -2220,6 +2714,7 @@ func (t *TypeTextTool) InputSchema() types.ToolSchema {
 				"type":        "string",
 				"description": "Page ID to type in (optional, uses current active page if not specified). Get page IDs from switch_tab list action",
 			},
+			"frame_selector": frameSelectorSchema(),
 			"clear": map[string]interface{}{
 				"type":        "boolean",
 				"description": "Clear existing content before typing. Set to false to append text (default: true)",
@@ -2232,6 +2727,11 @@ func (t *TypeTextTool) InputSchema() types.ToolSchema {
 }
 
 func (t *TypeTextTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *TypeTextTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	start := time.Now()
 	
 	selector, ok := args["selector"].(string)
@@ -2251,6 +2751,10 @@ func (t *TypeTextTool) Execute(args map[string]interface{}) (*types.CallToolResp
 	if err := ValidateText(text, t.Name(), false); err != nil {
 		return nil, err
 	}
+	frames, err := parseFrameSelector(args)
+	if err != nil {
+		return nil, err
+	}
 
 	pageID := ""
 	if val, ok := args["page_id"].(string); ok {
@@ -2272,31 +2776,7 @@ func (t *TypeTextTool) Execute(args map[string]interface{}) (*types.CallToolResp
 		clear = val
 	}
 
-	// Escape text for JavaScript
-	escapedText := strings.ReplaceAll(text, `"`, `\"`)
-	escapedText = strings.ReplaceAll(escapedText, `'`, `\'`)
-	escapedText = strings.ReplaceAll(escapedText, "\n", "\\n")
-
-	clearScript := ""
-	if clear {
-		clearScript = "element.value = '';"
-	}
-
-	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
-		if (!element) {
-			throw new Error('Element not found with selector: %s');
-		}
-		%s
-		element.focus();
-		element.value = '%s';
-		element.dispatchEvent(new Event('input', { bubbles: true }));
-		element.dispatchEvent(new Event('change', { bubbles: true }));
-		return 'Typed text into: %s';
-	`, selector, selector, clearScript, escapedText, selector)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
-	if err != nil {
+	if err := t.browserMgr.InputContext(ctx, pageID, frames, selector, text, clear, browser.ElementTimeout); err != nil {
 		t.logger.WithComponent("tools").Error("Failed to type text",
 			zap.String("selector", selector),
 			zap.String("text", text),
@@ -2311,18 +2791,19 @@ func (t *TypeTextTool) Execute(args map[string]interface{}) (*types.CallToolResp
 		zap.Bool("cleared", clear),
 		zap.Int64("duration_ms", duration))
 
+	data := map[string]interface{}{
+		"selector":    selector,
+		"text":        text,
+		"page_id":     pageID,
+		"cleared":     clear,
+		"duration_ms": duration,
+	}
+	addFrameData(data, frames)
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
 			Text: fmt.Sprintf("Successfully typed '%s' into element: %s", text, selector),
-			Data: map[string]interface{}{
-				"selector":    selector,
-				"text":        text,
-				"page_id":     pageID,
-				"cleared":     clear,
-				"duration_ms": duration,
-				"result":      result,
-			},
+			Data: data,
 		}},
 	}, nil
 }
@@ -2351,6 +2832,7 @@ func (t *WaitTool) InputSchema() types.ToolSchema {
 			"seconds": map[string]interface{}{
 				"type":        "number",
 				"description": "Number of seconds to wait",
+				"examples":    []interface{}{3, 0.5},
 				"minimum":     0.1,
 				"maximum":     60,
 			},
@@ -2391,12 +2873,15 @@ func (t *WaitTool) Execute(args map[string]interface{}) (*types.CallToolResponse
 	}, nil
 }
 
-// WaitForElementTool waits for an element to appear
+// WaitForElementTool waits for an element to appear, become visible, or go away
 type WaitForElementTool struct {
 	logger     *logger.Logger
 	browserMgr *browser.Manager
 }
 
+// waitStates lists the element states wait_for_element can wait for
+var waitStates = []string{"attached", "visible", "hidden", "detached"}
+
 func NewWaitForElementTool(log *logger.Logger, mgr *browser.Manager) *WaitForElementTool {
 	return &WaitForElementTool{logger: log, browserMgr: mgr}
 }
@@ -2406,7 +2891,7 @@ func (t *WaitForElementTool) Name() string {
 }
 
 func (t *WaitForElementTool) Description() string {
-	return "Wait for an element to appear in the DOM"
+	return "Wait for an element to appear in the DOM, become visible, become hidden, or be removed"
 }
 
 func (t *WaitForElementTool) InputSchema() types.ToolSchema {
@@ -2415,7 +2900,7 @@ func (t *WaitForElementTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the element to wait for",
+				"description": "Element to wait for. " + selectorSyntax,
 			},
 			"page_id": map[string]interface{}{
 				"type":        "string",
@@ -2426,6 +2911,12 @@ func (t *WaitForElementTool) InputSchema() types.ToolSchema {
 				"description": "Maximum time to wait in seconds (default: 10)",
 				"default":     10,
 			},
+			"state": map[string]interface{}{
+				"type":        "string",
+				"description": "State to wait for: attached (in the DOM), visible, hidden (missing or not visible, e.g. a spinner going away), or detached (removed from the DOM). Default: attached",
+				"enum":        waitStates,
+				"default":     "attached",
+			},
 		},
 		Required: []string{"selector"},
 	}
@@ -2438,6 +2929,25 @@ func (t *WaitForElementTool) Execute(args map[string]interface{}) (*types.CallTo
 	if !ok {
 		return nil, fmt.Errorf("selector must be a string")
 	}
+	sel, err := browser.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	state := "attached"
+	if val, ok := args["state"].(string); ok && val != "" {
+		state = val
+	}
+	valid := false
+	for _, s := range waitStates {
+		if state == s {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, fmt.Errorf("state must be one of: %s", strings.Join(waitStates, ", "))
+	}
 
 	pageID := ""
 	if val, ok := args["page_id"].(string); ok {
@@ -2459,57 +2969,35 @@ func (t *WaitForElementTool) Execute(args map[string]interface{}) (*types.CallTo
 		timeout = int(val)
 	}
 
-	// JavaScript to poll for element
-	script := fmt.Sprintf(`
-		const maxWait = %d * 1000; // Convert to milliseconds
-		const startTime = Date.now();
-		
-		function checkElement() {
-			const element = document.querySelector('%s');
-			if (element) {
-				return 'Element found: %s';
-			}
-			
-			if (Date.now() - startTime > maxWait) {
-				throw new Error('Timeout waiting for element: %s');
-			}
-			
-			// Wait 100ms and try again
-			return new Promise((resolve, reject) => {
-				setTimeout(() => {
-					try {
-						resolve(checkElement());
-					} catch (e) {
-						reject(e);
-					}
-				}, 100);
-			});
-		}
-		
-		return checkElement();
-	`, timeout, selector, selector, selector)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
+	result, err := t.browserMgr.ExecuteScript(pageID, waitForElementScript(sel, state, timeout))
 	if err != nil {
 		t.logger.WithComponent("tools").Error("Failed to wait for element",
 			zap.String("selector", selector),
+			zap.String("state", state),
 			zap.Int("timeout", timeout),
 			zap.Error(err))
-		return nil, fmt.Errorf("timeout waiting for element %s: %w", selector, err)
+		return nil, fmt.Errorf("timeout waiting for element %s to be %s: %w", selector, state, err)
 	}
 
 	duration := time.Since(start).Milliseconds()
-	t.logger.WithComponent("tools").Info("Element found successfully",
+	t.logger.WithComponent("tools").Info("Element reached expected state",
 		zap.String("selector", selector),
+		zap.String("state", state),
 		zap.Int("timeout", timeout),
 		zap.Int64("duration_ms", duration))
 
+	text := fmt.Sprintf("Element found: %s", selector)
+	if state != "attached" {
+		text = fmt.Sprintf("Element %s: %s", state, selector)
+	}
+
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
-			Text: fmt.Sprintf("Element found: %s", selector),
+			Text: text,
 			Data: map[string]interface{}{
 				"selector":    selector,
+				"state":       state,
 				"page_id":     pageID,
 				"timeout":     timeout,
 				"duration_ms": duration,
@@ -2519,6 +3007,61 @@ func (t *WaitForElementTool) Execute(args map[string]interface{}) (*types.CallTo
 	}, nil
 }
 
+// waitForElementScript builds a script that polls until the element matching
+// sel reaches state, throwing once timeout seconds have passed
+func waitForElementScript(sel browser.Selector, state string, timeout int) string {
+	selectorJSON, _ := json.Marshal(sel.Raw)
+	stateJSON, _ := json.Marshal(state)
+	return fmt.Sprintf(`
+		const selector = %s;
+		const state = %s;
+		const maxWait = %d * 1000; // Convert to milliseconds
+		const startTime = Date.now();
+		
+		function isVisible(el) {
+			const style = window.getComputedStyle(el);
+			if (style.display === 'none' || style.visibility === 'hidden' || style.opacity === '0') {
+				return false;
+			}
+			const rect = el.getBoundingClientRect();
+			return rect.width > 0 && rect.height > 0;
+		}
+		
+		function reached() {
+			const element = %s;
+			switch (state) {
+				case 'visible':  return element !== null && isVisible(element);
+				case 'hidden':   return element === null || !isVisible(element);
+				case 'detached': return element === null;
+				default:         return element !== null;
+			}
+		}
+		
+		function checkElement() {
+			if (reached()) {
+				return state === 'attached' ? 'Element found: ' + selector : 'Element ' + state + ': ' + selector;
+			}
+			
+			if (Date.now() - startTime > maxWait) {
+				throw new Error('Timeout waiting for element to be ' + state + ': ' + selector);
+			}
+			
+			// Wait 100ms and try again
+			return new Promise((resolve, reject) => {
+				setTimeout(() => {
+					try {
+						resolve(checkElement());
+					} catch (e) {
+						reject(e);
+					}
+				}, 100);
+			});
+		}
+		
+		return checkElement();
+	`, selectorJSON, stateJSON, timeout, sel.JSFirst())
+}
+
 // GetElementTextTool extracts text from elements
 type GetElementTextTool struct {
 	logger     *logger.Logger
@@ -2543,24 +3086,34 @@ func (t *GetElementTextTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the element to get text from",
+				"description": "Element to get text from. " + selectorSyntax,
 			},
 			"page_id": map[string]interface{}{
 				"type":        "string",
 				"description": "Page ID (optional)",
 			},
+			"frame_selector": frameSelectorSchema(),
 		},
 		Required: []string{"selector"},
 	}
 }
 
 func (t *GetElementTextTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *GetElementTextTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	start := time.Now()
 	
 	selector, ok := args["selector"].(string)
 	if !ok {
 		return nil, fmt.Errorf("selector must be a string")
 	}
+	frames, err := parseFrameSelector(args)
+	if err != nil {
+		return nil, err
+	}
 
 	pageID := ""
 	if val, ok := args["page_id"].(string); ok {
@@ -2577,15 +3130,7 @@ func (t *GetElementTextTool) Execute(args map[string]interface{}) (*types.CallTo
 		pageID = pages[0]
 	}
 
-	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
-		if (!element) {
-			throw new Error('Element not found with selector: %s');
-		}
-		return element.textContent || element.innerText || '';
-	`, selector, selector)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
+	text, err := t.browserMgr.ElementTextContext(ctx, pageID, frames, selector, browser.ElementTimeout)
 	if err != nil {
 		t.logger.WithComponent("tools").Error("Failed to get element text",
 			zap.String("selector", selector),
@@ -2593,30 +3138,24 @@ func (t *GetElementTextTool) Execute(args map[string]interface{}) (*types.CallTo
 		return nil, fmt.Errorf("failed to get text from element %s: %w", selector, err)
 	}
 
-	text := ""
-	if resultStr, ok := result.(string); ok {
-		text = resultStr
-	} else {
-		// Handle non-string results (e.g., gson.JSON from go-rod)
-		text = fmt.Sprintf("%v", result)
-	}
-
 	duration := time.Since(start).Milliseconds()
 	t.logger.WithComponent("tools").Info("Element text extracted successfully",
 		zap.String("selector", selector),
 		zap.String("text", text),
 		zap.Int64("duration_ms", duration))
 
+	data := map[string]interface{}{
+		"selector":    selector,
+		"text":        text,
+		"page_id":     pageID,
+		"duration_ms": duration,
+	}
+	addFrameData(data, frames)
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
 			Text: fmt.Sprintf("Text from %s: %s", selector, text),
-			Data: map[string]interface{}{
-				"selector":    selector,
-				"text":        text,
-				"page_id":     pageID,
-				"duration_ms": duration,
-			},
+			Data: data,
 		}},
 	}, nil
 }
@@ -2645,7 +3184,7 @@ func (t *GetElementAttributeTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the element",
+				"description": "Element to read. " + selectorSyntax,
 			},
 			"attribute": map[string]interface{}{
 				"type":        "string",
@@ -2661,6 +3200,11 @@ func (t *GetElementAttributeTool) InputSchema() types.ToolSchema {
 }
 
 func (t *GetElementAttributeTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *GetElementAttributeTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	start := time.Now()
 	
 	selector, ok := args["selector"].(string)
@@ -2694,15 +3238,7 @@ func (t *GetElementAttributeTool) Execute(args map[string]interface{}) (*types.C
 		pageID = pages[0]
 	}
 
-	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
-		if (!element) {
-			throw new Error('Element not found with selector: %s');
-		}
-		return element.getAttribute('%s');
-	`, selector, selector, attribute)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
+	value, present, err := t.browserMgr.ElementAttributeContext(ctx, pageID, selector, attribute, browser.ElementTimeout)
 	if err != nil {
 		t.logger.WithComponent("tools").Error("Failed to get element attribute",
 			zap.String("selector", selector),
@@ -2711,14 +3247,6 @@ func (t *GetElementAttributeTool) Execute(args map[string]interface{}) (*types.C
 		return nil, fmt.Errorf("failed to get attribute %s from element %s: %w", attribute, selector, err)
 	}
 
-	value := ""
-	if resultStr, ok := result.(string); ok {
-		value = resultStr
-	} else {
-		// Handle non-string results (e.g., gson.JSON from go-rod)
-		value = fmt.Sprintf("%v", result)
-	}
-
 	duration := time.Since(start).Milliseconds()
 	t.logger.WithComponent("tools").Info("Element attribute retrieved successfully",
 		zap.String("selector", selector),
@@ -2726,14 +3254,20 @@ func (t *GetElementAttributeTool) Execute(args map[string]interface{}) (*types.C
 		zap.String("value", value),
 		zap.Int64("duration_ms", duration))
 
+	text := fmt.Sprintf("Attribute %s from %s: %s", attribute, selector, value)
+	if !present {
+		text = fmt.Sprintf("Element %s has no %s attribute", selector, attribute)
+	}
+
 	return &types.CallToolResponse{
 		Content: []types.ToolContent{{
 			Type: "text",
-			Text: fmt.Sprintf("Attribute %s from %s: %s", attribute, selector, value),
+			Text: text,
 			Data: map[string]interface{}{
 				"selector":    selector,
 				"attribute":   attribute,
 				"value":       value,
+				"present":     present,
 				"page_id":     pageID,
 				"duration_ms": duration,
 			},
@@ -2896,7 +3430,7 @@ func (t *HoverElementTool) Name() string {
 }
 
 func (t *HoverElementTool) Description() string {
-	return "Hover over a browser element to trigger hover effects"
+	return "Hover over a browser element by moving the mouse onto it, triggering :hover styles and mouse listeners"
 }
 
 func (t *HoverElementTool) InputSchema() types.ToolSchema {
@@ -2905,7 +3439,7 @@ func (t *HoverElementTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the element to hover over",
+				"description": "Element to hover over. " + selectorSyntax,
 			},
 			"page_id": map[string]interface{}{
 				"type":        "string",
@@ -2917,6 +3451,11 @@ func (t *HoverElementTool) InputSchema() types.ToolSchema {
 }
 
 func (t *HoverElementTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext stops waiting for the element when the call ends
+func (t *HoverElementTool) ExecuteContext(ctx context.Context, args map[string]interface{}) (*types.CallToolResponse, error) {
 	start := time.Now()
 	
 	selector, ok := args["selector"].(string)
@@ -2945,33 +3484,7 @@ func (t *HoverElementTool) Execute(args map[string]interface{}) (*types.CallTool
 		pageID = pages[0]
 	}
 
-	script := fmt.Sprintf(`
-		const element = document.querySelector('%s');
-		if (!element) {
-			throw new Error('Element not found with selector: %s');
-		}
-		
-		// Create and dispatch mouseover event
-		const event = new MouseEvent('mouseover', {
-			bubbles: true,
-			cancelable: true,
-			view: window
-		});
-		element.dispatchEvent(event);
-		
-		// Also trigger mouseenter for completeness
-		const enterEvent = new MouseEvent('mouseenter', {
-			bubbles: false,
-			cancelable: true,
-			view: window
-		});
-		element.dispatchEvent(enterEvent);
-		
-		return 'Hovered over element: %s';
-	`, selector, selector, selector)
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
-	if err != nil {
+	if err := t.browserMgr.HoverContext(ctx, pageID, selector, browser.ElementTimeout); err != nil {
 		t.logger.WithComponent("tools").Error("Failed to hover over element",
 			zap.String("selector", selector),
 			zap.Error(err))
@@ -2991,7 +3504,6 @@ func (t *HoverElementTool) Execute(args map[string]interface{}) (*types.CallTool
 				"selector":    selector,
 				"page_id":     pageID,
 				"duration_ms": duration,
-				"result":      result,
 			},
 		}},
 	}, nil
@@ -3029,10 +3541,8 @@ func (t *ScreenScrapeTool) InputSchema() types.ToolSchema {
 			},
 			"selectors": map[string]interface{}{
 				"type":        "object",
-				"description": "CSS selectors mapping field names to elements. Examples: {'title': 'h1', 'price': '.price-value', 'description': 'p.desc', 'link': 'a[href]', 'image': 'img[src]', 'rating': '[data-rating]'}. Supports: #id, .class, [attribute], tag, :nth-child(), :contains(), descendant combinators.",
-				"additionalProperties": map[string]interface{}{
-					"type": "string",
-				},
+				"description": "CSS selectors mapping field names to elements. Examples: {'title': 'h1', 'price': '.price-value', 'description': 'p.desc', 'link': 'a[href]', 'image': 'img[src]', 'rating': '[data-rating]'}. Supports: #id, .class, [attribute], tag, :nth-child(), descendant combinators. Add directives to choose exactly what is returned: 'a.link@href' reads an attribute, '.summary@text' or '@html' the text or markup, and 'span.price | regex:([0-9.]+)' narrows the text to the first capture group. Plain selectors return the value with element details. Nest schemas to capture structure in one pass: ['.tag@text'] returns every match, and {'selector': '.seller', 'fields': {...}} scrapes a sub-object inside each match (add 'multiple': true for an array of them). Where there are no stable classes, use XPath ('xpath=//dt[.=\"SKU\"]/following-sibling::dd[1]@text', or any selector starting with / or ./) or the text pseudo-classes 'tr:has-text(\"Total\") td' and 'th:text-is(\"Price\")'.",
+				"additionalProperties": scrapeSelectorValueSchema(),
 				"examples": []interface{}{
 					map[string]interface{}{
 						"title":       "h1.product-title",
@@ -3040,6 +3550,20 @@ func (t *ScreenScrapeTool) InputSchema() types.ToolSchema {
 						"description": ".product-description p",
 						"image":       "img.hero-image",
 					},
+					map[string]interface{}{
+						"title": "h1.product-title@text",
+						"price": "span.price | regex:([0-9.]+)",
+						"link":  "a.product-link@href",
+						"image": "img.hero-image@src",
+					},
+					map[string]interface{}{
+						"title": "h1@text",
+						"tags":  []interface{}{".tag@text"},
+						"seller": map[string]interface{}{
+							"selector": ".seller",
+							"fields":   map[string]interface{}{"name": ".name@text", "rating": "@data-rating"},
+						},
+					},
 				},
 			},
 			"extract_type": map[string]interface{}{
@@ -3075,6 +3599,22 @@ func (t *ScreenScrapeTool) InputSchema() types.ToolSchema {
 				"type":        "string",
 				"description": "Custom JavaScript to execute before scraping. Examples: 'document.querySelector(\".load-more\").click()', 'window.scrollTo(0, document.body.scrollHeight)', 'localStorage.setItem(\"view\", \"list\")'. Use for clicking buttons, changing views, triggering content.",
 			},
+			"block_resources": map[string]interface{}{
+				"type":        "array",
+				"description": "Resource types the page should not load, for faster DOM-only scraping. Blocked before the first request is made.",
+				"items": map[string]interface{}{
+					"type": "string",
+					"enum": browser.BlockableResourceTypes,
+				},
+				"examples": []interface{}{[]string{"image", "font", "media", "stylesheet"}},
+			},
+			"keep_page": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Keep the page opened for 'url' so it can be reused via page_id. By default, when the server runs with a page pool, the page is cleared and returned to the pool after scraping.",
+				"default":     false,
+			},
+			"frame_selector": frameSelectorSchema(),
+			"transform":      transformSchema("the scraped data (the single item, or the array of items)"),
 		},
 		Required: []string{"selectors"},
 	}
@@ -3114,24 +3654,51 @@ func (t *ScreenScrapeTool) Execute(args map[string]interface{}) (*types.CallTool
 func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*types.CallToolResponse, error) {
 	start := time.Now()
 
+	transform, err := parseTransform(args)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := parseFrameSelector(args)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get or create page
 	pageID := ""
 	if val, ok := args["page_id"].(string); ok {
 		pageID = val
 	}
 
+	recycled := false
 	if pageID == "" {
 		url, hasURL := args["url"].(string)
 		if !hasURL || url == "" {
 			return nil, fmt.Errorf("either page_id or url must be provided")
 		}
 
-		// Create new page
-		_, newPageID, err := t.browserMgr.NewPage(url)
+		blockResources, err := parseBlockResources(args)
+		if err != nil {
+			return nil, err
+		}
+
+		// Take a warm page from the pool when one is available
+		_, newPageID, err := t.browserMgr.NewPooledPage(url, browser.PageOptions{BlockResources: blockResources})
 		if err != nil {
 			return nil, fmt.Errorf("failed to navigate to %s: %w", url, err)
 		}
 		pageID = newPageID
+
+		keepPage, _ := args["keep_page"].(bool)
+		if !keepPage && t.browserMgr.PagePoolEnabled() {
+			recycled = true
+			defer func() {
+				if err := t.browserMgr.RecyclePage(newPageID); err != nil {
+					t.logger.WithComponent("tools").Warn("Failed to recycle scrape page",
+						zap.String("page_id", newPageID),
+						zap.Error(err))
+				}
+			}()
+		}
 	}
 
 	// Wait for specific element if requested
@@ -3169,7 +3736,7 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 			return checkElement();
 		`, timeout, waitFor, waitFor)
 
-		if _, err := t.browserMgr.ExecuteScript(pageID, waitScript); err != nil {
+		if _, err := t.browserMgr.ExecuteScriptInFrame(pageID, frames, waitScript); err != nil {
 			return nil, fmt.Errorf("timeout waiting for element %s: %w", waitFor, err)
 		}
 	}
@@ -3204,7 +3771,7 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 			});
 		`
 
-		if _, err := t.browserMgr.ExecuteScript(pageID, scrollScript); err != nil {
+		if _, err := t.browserMgr.ExecuteScriptInFrame(pageID, frames, scrollScript); err != nil {
 			t.logger.WithComponent("tools").Warn("Scroll to load failed",
 				zap.Error(err))
 		}
@@ -3212,7 +3779,7 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 
 	// Execute custom script if provided
 	if customScript, ok := args["custom_script"].(string); ok && customScript != "" {
-		if _, err := t.browserMgr.ExecuteScript(pageID, customScript); err != nil {
+		if _, err := t.browserMgr.ExecuteScriptInFrame(pageID, frames, customScript); err != nil {
 			t.logger.WithComponent("tools").Warn("Custom script execution failed",
 				zap.Error(err))
 		}
@@ -3230,17 +3797,21 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 	}
 
 	var result interface{}
-	var err error
 
 	if extractType == "multiple" {
-		result, err = t.scrapeMultiple(pageID, selectors, args)
+		result, err = t.scrapeMultiple(pageID, frames, selectors, args)
 	} else {
-		result, err = t.scrapeSingle(pageID, selectors)
+		result, err = t.scrapeSingle(pageID, frames, selectors)
 	}
 
 	if err != nil {
 		return nil, fmt.Errorf("scraping failed: %w", err)
 	}
+	if transform != nil {
+		if result, err = transform.apply(result); err != nil {
+			return nil, err
+		}
+	}
 
 	// Add metadata if requested
 	includeMetadata := true
@@ -3257,6 +3828,12 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 			"timestamp": time.Now().Format(time.RFC3339Nano),
 			"page_id":   pageID,
 		}
+		addFrameData(responseData, frames)
+		if recycled {
+			// The page goes back to the pool, so its ID is not reusable
+			delete(responseData, "page_id")
+			responseData["page_recycled"] = true
+		}
 	} else {
 		responseData = map[string]interface{}{
 			"data": result,
@@ -3279,184 +3856,56 @@ func (t *ScreenScrapeTool) executeScreenScrape(args map[string]interface{}) (*ty
 	}, nil
 }
 
-func (t *ScreenScrapeTool) scrapeSingle(pageID string, selectors map[string]interface{}) (map[string]interface{}, error) {
-	result := make(map[string]interface{})
-
-	for fieldName, selectorInterface := range selectors {
-		selector, ok := selectorInterface.(string)
+// parseBlockResources reads the optional block_resources argument
+func parseBlockResources(args map[string]interface{}) ([]string, error) {
+	raw, ok := args["block_resources"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	list, ok := raw.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("block_resources must be an array of strings")
+	}
+	names := make([]string, 0, len(list))
+	for _, item := range list {
+		name, ok := item.(string)
 		if !ok {
-			continue
-		}
-
-		script := fmt.Sprintf(`
-			const element = document.querySelector('%s');
-			if (!element) {
-				return null;
-			}
-
-			// Extract different types of data based on element type
-			const tagName = element.tagName.toLowerCase();
-			let value = null;
-
-			if (tagName === 'img') {
-				value = {
-					src: element.src || element.getAttribute('src'),
-					alt: element.alt || element.getAttribute('alt'),
-					title: element.title || element.getAttribute('title')
-				};
-			} else if (tagName === 'a') {
-				value = {
-					href: element.href || element.getAttribute('href'),
-					text: element.textContent || element.innerText,
-					title: element.title || element.getAttribute('title')
-				};
-			} else if (tagName === 'input') {
-				value = {
-					type: element.type,
-					value: element.value,
-					placeholder: element.placeholder
-				};
-			} else if (element.hasAttribute('data-value')) {
-				value = element.getAttribute('data-value');
-			} else {
-				value = element.textContent || element.innerText || '';
-			}
-
-			return {
-				value: value,
-				attributes: {
-					class: element.className,
-					id: element.id,
-					tagName: tagName
-				}
-			};
-		`, selector)
-
-		data, err := t.browserMgr.ExecuteScript(pageID, script)
-		if err != nil {
-			t.logger.WithComponent("tools").Warn("Failed to scrape field",
-				zap.String("field", fieldName),
-				zap.String("selector", selector),
-				zap.Error(err))
-			result[fieldName] = nil
-			continue
+			return nil, fmt.Errorf("block_resources must be an array of strings")
 		}
+		names = append(names, name)
+	}
+	return browser.NormalizeResourceTypes(names)
+}
 
-		result[fieldName] = data
+func (t *ScreenScrapeTool) scrapeSingle(pageID string, frames browser.FramePath, selectors map[string]interface{}) (map[string]interface{}, error) {
+	fields, err := parseScrapeFields(selectors)
+	if err != nil {
+		return nil, err
 	}
 
-	return result, nil
+	data, err := t.runScrape(pageID, frames, nil, fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute scraping script: %w", err)
+	}
+	return data.(map[string]interface{}), nil
 }
 
-func (t *ScreenScrapeTool) scrapeMultiple(pageID string, selectors map[string]interface{}, args map[string]interface{}) ([]map[string]interface{}, error) {
+func (t *ScreenScrapeTool) scrapeMultiple(pageID string, frames browser.FramePath, selectors map[string]interface{}, args map[string]interface{}) ([]map[string]interface{}, error) {
 	containerSelector, ok := args["container_selector"].(string)
 	if !ok || containerSelector == "" {
 		return nil, fmt.Errorf("container_selector is required for multiple extraction")
 	}
 
-	// Build the scraping script for multiple items
-	var selectorPairs []string
-	for fieldName, selectorInterface := range selectors {
-		if selector, ok := selectorInterface.(string); ok {
-			selectorPairs = append(selectorPairs, fmt.Sprintf(`'%s': '%s'`, fieldName, selector))
-		}
+	fields, err := parseScrapeFields(selectors)
+	if err != nil {
+		return nil, err
 	}
 
-	script := fmt.Sprintf(`
-		const containers = document.querySelectorAll('%s');
-		const selectors = {%s};
-		const results = [];
-
-		containers.forEach((container, index) => {
-			const item = {};
-
-			Object.keys(selectors).forEach(fieldName => {
-				const selector = selectors[fieldName];
-				const element = container.querySelector(selector);
-
-				if (!element) {
-					item[fieldName] = null;
-					return;
-				}
-
-				const tagName = element.tagName.toLowerCase();
-				let value = null;
-
-				if (tagName === 'img') {
-					value = {
-						src: element.src || element.getAttribute('src'),
-						alt: element.alt || element.getAttribute('alt'),
-						title: element.title || element.getAttribute('title')
-					};
-				} else if (tagName === 'a') {
-					value = {
-						href: element.href || element.getAttribute('href'),
-						text: element.textContent || element.innerText,
-						title: element.title || element.getAttribute('title')
-					};
-				} else if (tagName === 'input') {
-					value = {
-						type: element.type,
-						value: element.value,
-						placeholder: element.placeholder
-					};
-				} else if (element.hasAttribute('data-value')) {
-					value = element.getAttribute('data-value');
-				} else {
-					value = element.textContent || element.innerText || '';
-				}
-
-				item[fieldName] = {
-					value: value,
-					attributes: {
-						class: element.className,
-						id: element.id,
-						tagName: tagName
-					}
-				};
-			});
-
-			item._index = index;
-			results.push(item);
-		});
-
-		return results;
-	`, containerSelector, strings.Join(selectorPairs, ", "))
-
-	data, err := t.browserMgr.ExecuteScript(pageID, script)
+	data, err := t.runScrape(pageID, frames, &containerSelector, fields)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute multiple scraping script: %w", err)
 	}
-
-	// Debug log the data type
-	t.logger.WithComponent("tools").Debug("Scraping script returned data",
-		zap.String("type", fmt.Sprintf("%T", data)),
-		zap.Any("data", data))
-
-	// Convert the result to the expected format
-	// Rod might return different data types, handle various cases
-	switch v := data.(type) {
-	case []interface{}:
-		var result []map[string]interface{}
-		for _, item := range v {
-			if itemMap, ok := item.(map[string]interface{}); ok {
-				result = append(result, itemMap)
-			}
-		}
-		return result, nil
-	case []map[string]interface{}:
-		return v, nil
-	case interface{}:
-		// Try to convert to JSON and back to handle go-rod's gson types
-		if jsonBytes, err := json.Marshal(v); err == nil {
-			var result []map[string]interface{}
-			if err := json.Unmarshal(jsonBytes, &result); err == nil {
-				return result, nil
-			}
-		}
-	}
-
-	return nil, fmt.Errorf("unexpected data format returned from scraping script: %T", data)
+	return data.([]map[string]interface{}), nil
 }
 
 // FormFillTool fills out forms with structured data
@@ -3488,7 +3937,7 @@ func (t *FormFillTool) InputSchema() types.ToolSchema {
 			},
 			"fields": map[string]interface{}{
 				"type":        "object",
-				"description": "Object mapping field selectors to values. Keys are CSS selectors, values are the data to fill. Example: {\"#email\": \"test@example.com\", \"select[name='country']\": \"US\", \"input[name='subscribe']\": true}",
+				"description": "Object mapping fields to values. Keys can be CSS selectors, name or id attributes, or the field's visible label, aria-label or placeholder text. Example: {\"#email\": \"test@example.com\", \"country\": \"US\", \"Subscribe to newsletter\": true}",
 				"additionalProperties": interface{}(map[string]interface{}{
 					"oneOf": []interface{}{
 						map[string]interface{}{"type": "string"},
@@ -3516,8 +3965,39 @@ func (t *FormFillTool) InputSchema() types.ToolSchema {
 				"description": "Whether to trigger input/change events after filling fields (default: true)",
 				"default":     true,
 			},
+			"input_mode": map[string]interface{}{
+				"type":        "string",
+				"description": "How text is entered: 'set' assigns the value directly, 'type' sends real keystrokes for masked or framework-controlled inputs (default: set)",
+				"enum":        []string{inputModeSet, inputModeType},
+				"default":     inputModeSet,
+			},
+			"field_modes": map[string]interface{}{
+				"type":        "object",
+				"description": "Per-field input_mode overrides keyed like fields. Example: {\"Phone\": \"type\"}",
+				"additionalProperties": interface{}(map[string]interface{}{
+					"type": "string",
+					"enum": []string{inputModeSet, inputModeType},
+				}),
+			},
+			"typing_delay_ms": map[string]interface{}{
+				"type":        "integer",
+				"description": "Delay between keystrokes in typing mode (default: 0)",
+				"default":     0,
+				"minimum":     0,
+				"maximum":     maxTypingDelayMs,
+			},
+			"verify_values": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Read each field back after filling and report values that don't match (default: true)",
+				"default":     true,
+			},
+			"report_validity": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Report the browser's native validation messages for the form after filling (default: true)",
+				"default":     true,
+			},
+			"steps": formStepSchema(),
 		},
-		Required: []string{"fields"},
 	}
 }
 
@@ -3533,6 +4013,11 @@ func (t *FormFillTool) Execute(args map[string]interface{}) (*types.CallToolResp
 	resultChan := make(chan result, 1)
 	
 	go func() {
+		if steps, ok := args["steps"].([]interface{}); ok && len(steps) > 0 {
+			resp, err := t.executeFormSteps(args, steps)
+			resultChan <- result{resp, err}
+			return
+		}
 		resp, err := t.executeFormFill(args)
 		resultChan <- result{resp, err}
 	}()
@@ -3587,7 +4072,7 @@ func (t *FormFillTool) executeFormFill(args map[string]interface{}) (*types.Call
 	// Get fields to fill
 	fields, ok := args["fields"].(map[string]interface{})
 	if !ok || len(fields) == 0 {
-		return nil, fmt.Errorf("fields must be provided as key-value pairs")
+		return nil, fmt.Errorf("fields must be provided as key-value pairs, or use steps for a multi-page form")
 	}
 
 	// Get options
@@ -3606,17 +4091,91 @@ func (t *FormFillTool) executeFormFill(args map[string]interface{}) (*types.Call
 		triggerEvents = val
 	}
 
+	inputMode, fieldModes, err := parseFieldModes(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var typingDelay time.Duration
+	if val, ok := args["typing_delay_ms"].(float64); ok {
+		if val < 0 || val > maxTypingDelayMs {
+			return nil, fmt.Errorf("typing_delay_ms must be between 0 and %d", maxTypingDelayMs)
+		}
+		typingDelay = time.Duration(val) * time.Millisecond
+	}
+
+	verifyValues := true
+	if val, ok := args["verify_values"].(bool); ok {
+		verifyValues = val
+	}
+
+	reportValidity := true
+	if val, ok := args["report_validity"].(bool); ok {
+		reportValidity = val
+	}
+
 	// Build the form filling script
 	var fillResults []map[string]interface{}
 	var errors []string
+	var filled []filledField
+
+	// Resolve labels and names to selectors; if that fails the keys are
+	// still usable as plain CSS selectors
+	keys := sortedFieldKeys(fields)
+	targets, err := t.resolveFormFields(pageID, formSelector, keys)
+	if err != nil {
+		t.logger.WithComponent("tools").Debug("Falling back to selector-only form fields", zap.Error(err))
+	}
+
+	for _, key := range keys {
+		fieldSelector := key
+		target, resolved := targets[key]
+		if resolved && !target.Found {
+			errors = append(errors, fmt.Sprintf("Field %s: no field matches this selector, name or label", key))
+			continue
+		}
+		if resolved {
+			fieldSelector = target.Selector
+		}
+
+		mode := inputMode
+		if m, ok := fieldModes[key]; ok {
+			mode = m
+		}
 
-	for fieldSelector, value := range fields {
-		result, err := t.fillSingleField(pageID, formSelector, fieldSelector, value, triggerEvents)
+		var result map[string]interface{}
+		if mode == inputModeType {
+			result, err = t.typeField(pageID, formSelector, fieldSelector, fields[key], triggerEvents, typingDelay)
+		} else {
+			result, err = t.fillSingleField(pageID, formSelector, fieldSelector, fields[key], triggerEvents)
+		}
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("Field %s: %v", fieldSelector, err))
+			errors = append(errors, fmt.Sprintf("Field %s: %v", key, err))
 			continue
 		}
+		result["field"] = key
+		if resolved {
+			result["matched_by"] = target.MatchedBy
+		}
 		fillResults = append(fillResults, result)
+		filled = append(filled, filledField{Key: key, Selector: fieldSelector, Expected: fields[key]})
+	}
+
+	// Read the fields back; a value that didn't stick counts as a field error
+	// so the form isn't submitted with it
+	var verification []fieldVerification
+	var mismatches []string
+	if verifyValues {
+		verification, err = t.verifyFields(pageID, formSelector, filled)
+		if err != nil {
+			t.logger.WithComponent("tools").Debug("Could not verify form field values", zap.Error(err))
+		}
+		for _, v := range verification {
+			if !v.Matches {
+				mismatches = append(mismatches, v.Field)
+				errors = append(errors, fmt.Sprintf("Field %s: expected %v but field contains %v", v.Field, v.Expected, v.Actual))
+			}
+		}
 	}
 
 	// Validate required fields if requested
@@ -3625,6 +4184,20 @@ func (t *FormFillTool) executeFormFill(args map[string]interface{}) (*types.Call
 		validationErrors, _ = t.validateRequiredFields(pageID, formSelector)
 	}
 
+	var validity []validityError
+	if reportValidity {
+		validity, err = t.validityErrors(pageID, formSelector)
+		if err != nil {
+			t.logger.WithComponent("tools").Debug("Could not check form validity", zap.Error(err))
+		}
+		for _, v := range validity {
+			if v.ValueMissing && validateRequired {
+				continue
+			}
+			validationErrors = append(validationErrors, fmt.Sprintf("%s: %s", v.Field, v.Message))
+		}
+	}
+
 	// Submit form if requested and no critical errors
 	var submitResult string
 	if submit && len(errors) == 0 {
@@ -3654,6 +4227,14 @@ func (t *FormFillTool) executeFormFill(args map[string]interface{}) (*types.Call
 	if len(errors) > 0 {
 		messageText.WriteString(fmt.Sprintf(", %d failed", len(errors)))
 	}
+
+	if len(mismatches) > 0 {
+		messageText.WriteString(fmt.Sprintf(", %d values did not stick (%s)", len(mismatches), strings.Join(mismatches, ", ")))
+	}
+
+	if len(validity) > 0 {
+		messageText.WriteString(fmt.Sprintf(", %d fields fail browser validation", len(validity)))
+	}
 	
 	if submit {
 		messageText.WriteString(fmt.Sprintf(", submission: %s", submitResult))
@@ -3664,6 +4245,9 @@ func (t *FormFillTool) executeFormFill(args map[string]interface{}) (*types.Call
 		"successful_fills": fillResults,
 		"errors":          errors,
 		"validation_errors": validationErrors,
+		"value_checks":     verification,
+		"validity_errors":  validity,
+		"input_mode":       inputMode,
 		"submit_requested": submit,
 		"submit_result":    submitResult,
 		"form_selector":    formSelector,
@@ -4214,6 +4798,9 @@ func (t *WaitForConditionTool) executeWaitForCondition(args map[string]interface
 	}, nil
 }
 
+// assertRetryInterval is how often retry_until_timeout re-checks an assertion
+const assertRetryInterval = 250 * time.Millisecond
+
 // AssertElementTool provides comprehensive element assertions for testing
 type AssertElementTool struct {
 	logger     *logger.Logger
@@ -4238,7 +4825,7 @@ func (t *AssertElementTool) InputSchema() types.ToolSchema {
 		Properties: map[string]interface{}{
 			"selector": map[string]interface{}{
 				"type":        "string",
-				"description": "CSS selector for the element to assert",
+				"description": "Element to assert. " + selectorSyntax,
 			},
 			"assertion": map[string]interface{}{
 				"type":        "string",
@@ -4278,6 +4865,12 @@ func (t *AssertElementTool) InputSchema() types.ToolSchema {
 				"description": "Whether text comparisons should be case sensitive (default: false)",
 				"default":     false,
 			},
+			"retry_until_timeout": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Re-check the assertion until it passes or timeout expires instead of asserting once after the element appears (default: false)",
+				"default":     false,
+			},
+			"frame_selector": frameSelectorSchema(),
 		},
 		Required: []string{"selector", "assertion"},
 	}
@@ -4345,6 +4938,14 @@ func (t *AssertElementTool) executeAssertElement(args map[string]interface{}) (*
 	if !ok || selector == "" {
 		return nil, fmt.Errorf("selector must be provided as a string")
 	}
+	sel, err := browser.ParseSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	frames, err := parseFrameSelector(args)
+	if err != nil {
+		return nil, err
+	}
 
 	assertion, ok := args["assertion"].(string)
 	if !ok || assertion == "" {
@@ -4372,19 +4973,22 @@ func (t *AssertElementTool) executeAssertElement(args map[string]interface{}) (*
 		caseSensitive = val
 	}
 
+	retry, _ := args["retry_until_timeout"].(bool)
+
 	// Validate required parameters for specific assertions
 	if err := t.validateAssertionParams(assertion, expectedValue, attributeName); err != nil {
 		return nil, err
 	}
 
-	// Wait for element if timeout > 0 and assertion requires element to exist
-	if timeout > 0 && !strings.Contains(assertion, "not_exists") {
+	// Wait for element if timeout > 0 and assertion requires element to exist.
+	// Retry mode polls the assertion itself instead.
+	if timeout > 0 && !retry && !strings.Contains(assertion, "not_exists") {
 		waitScript := fmt.Sprintf(`
 			const maxWait = %d * 1000;
 			const startTime = Date.now();
 			
 			function checkElement() {
-				const elements = document.querySelectorAll('%s');
+				const elements = %s;
 				if (elements.length > 0) {
 					return true;
 				}
@@ -4399,55 +5003,40 @@ func (t *AssertElementTool) executeAssertElement(args map[string]interface{}) (*
 			}
 			
 			return checkElement();
-		`, timeout, selector)
+		`, timeout, sel.JSAll())
 
-		_, err := t.browserMgr.ExecuteScript(pageID, waitScript)
+		_, err := t.browserMgr.ExecuteScriptInFrame(pageID, frames, waitScript)
 		if err != nil {
 			// Element not found within timeout, but continue with assertion
 			// The assertion itself will handle the "not found" case
 		}
 	}
 
-	// Perform the assertion
-	result, err := t.performAssertion(pageID, selector, assertion, expectedValue, attributeName, caseSensitive)
-	if err != nil {
-		return &types.CallToolResponse{
-			Content: []types.ToolContent{{
-				Type: "text",
-				Text: fmt.Sprintf("Assertion execution failed: %v", err),
-			}},
-			IsError: true,
-		}, nil
-	}
-
-	// Parse the assertion result
+	// Perform the assertion, polling until it passes in retry mode
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	attempts := 0
 	var assertionData map[string]interface{}
-	if directMap, ok := result.(map[string]interface{}); ok {
-		assertionData = directMap
-	} else if jsonBytes, err := json.Marshal(result); err == nil {
-		if err := json.Unmarshal(jsonBytes, &assertionData); err != nil {
-			return &types.CallToolResponse{
-				Content: []types.ToolContent{{
-					Type: "text",
-					Text: fmt.Sprintf("Failed to parse assertion result: %v", err),
-				}},
-				IsError: true,
-			}, nil
+	var passed bool
+	for {
+		attempts++
+		data, err := t.evaluateAssertion(pageID, frames, sel, assertion, expectedValue, attributeName, caseSensitive)
+		if err == nil {
+			assertionData = data
+			passed, _ = data["passed"].(bool)
 		}
-	} else {
-		return &types.CallToolResponse{
-			Content: []types.ToolContent{{
-				Type: "text",
-				Text: fmt.Sprintf("Unexpected assertion result format: %T", result),
-			}},
-			IsError: true,
-		}, nil
-	}
-
-	// Extract assertion result
-	passed := false
-	if val, ok := assertionData["passed"].(bool); ok {
-		passed = val
+		if passed || !retry || !time.Now().Add(assertRetryInterval).Before(deadline) {
+			if err != nil {
+				return &types.CallToolResponse{
+					Content: []types.ToolContent{{
+						Type: "text",
+						Text: err.Error(),
+					}},
+					IsError: true,
+				}, nil
+			}
+			break
+		}
+		time.Sleep(assertRetryInterval)
 	}
 
 	message := "Assertion completed"
@@ -4466,6 +5055,12 @@ func (t *AssertElementTool) executeAssertElement(args map[string]interface{}) (*
 		"case_sensitive": caseSensitive,
 		"page_id":        pageID,
 	}
+	addFrameData(responseData, frames)
+	if retry {
+		responseData["retry_until_timeout"] = true
+		responseData["attempts"] = attempts
+		responseData["elapsed_ms"] = time.Since(start).Milliseconds()
+	}
 
 	// Add any additional data from the assertion
 	for key, value := range assertionData {
@@ -4491,6 +5086,27 @@ func (t *AssertElementTool) executeAssertElement(args map[string]interface{}) (*
 	}, nil
 }
 
+// evaluateAssertion runs one assertion check and decodes its result
+func (t *AssertElementTool) evaluateAssertion(pageID string, frames browser.FramePath, sel browser.Selector, assertion, expectedValue, attributeName string, caseSensitive bool) (map[string]interface{}, error) {
+	result, err := t.performAssertion(pageID, frames, sel, assertion, expectedValue, attributeName, caseSensitive)
+	if err != nil {
+		return nil, fmt.Errorf("Assertion execution failed: %v", err)
+	}
+
+	if directMap, ok := result.(map[string]interface{}); ok {
+		return directMap, nil
+	}
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("Unexpected assertion result format: %T", result)
+	}
+	var assertionData map[string]interface{}
+	if err := json.Unmarshal(jsonBytes, &assertionData); err != nil {
+		return nil, fmt.Errorf("Failed to parse assertion result: %v", err)
+	}
+	return assertionData, nil
+}
+
 func (t *AssertElementTool) validateAssertionParams(assertion, expectedValue, attributeName string) error {
 	switch assertion {
 	case "contains_text", "exact_text", "not_contains_text":
@@ -4517,7 +5133,7 @@ func (t *AssertElementTool) validateAssertionParams(assertion, expectedValue, at
 	return nil
 }
 
-func (t *AssertElementTool) performAssertion(pageID, selector, assertion, expectedValue, attributeName string, caseSensitive bool) (interface{}, error) {
+func (t *AssertElementTool) performAssertion(pageID string, frames browser.FramePath, sel browser.Selector, assertion, expectedValue, attributeName string, caseSensitive bool) (interface{}, error) {
 	script := fmt.Sprintf(`
 		const selector = '%s';
 		const assertion = '%s';
@@ -4525,7 +5141,7 @@ func (t *AssertElementTool) performAssertion(pageID, selector, assertion, expect
 		const attributeName = '%s';
 		const caseSensitive = %v;
 		
-		const elements = document.querySelectorAll(selector);
+		const elements = %s;
 		const count = elements.length;
 		const element = elements[0]; // First element for single-element assertions
 		
@@ -4801,25 +5417,34 @@ func (t *AssertElementTool) performAssertion(pageID, selector, assertion, expect
 		
 		return result;
 	`, 
-	strings.ReplaceAll(selector, "'", "\\'"),
+	strings.ReplaceAll(sel.Raw, "'", "\\'"),
 	assertion,
 	strings.ReplaceAll(expectedValue, "'", "\\'"),
 	strings.ReplaceAll(attributeName, "'", "\\'"),
-	caseSensitive)
+	caseSensitive,
+	sel.JSAll())
 
-	return t.browserMgr.ExecuteScript(pageID, script)
+	return t.browserMgr.ExecuteScriptInFrame(pageID, frames, script)
 }
 
 // ExtractTableTool extracts structured data from HTML tables
 type ExtractTableTool struct {
 	logger     *logger.Logger
 	browserMgr *browser.Manager
+	validator  *PathValidator
 }
 
 func NewExtractTableTool(log *logger.Logger, browserMgr *browser.Manager) *ExtractTableTool {
+	return NewExtractTableToolWithValidator(log, browserMgr, NewPathValidator(DefaultFileAccessConfig()))
+}
+
+// NewExtractTableToolWithValidator creates an extract_table tool whose
+// output_file is checked against the given file access configuration
+func NewExtractTableToolWithValidator(log *logger.Logger, browserMgr *browser.Manager, validator *PathValidator) *ExtractTableTool {
 	return &ExtractTableTool{
 		logger:     log,
 		browserMgr: browserMgr,
+		validator:  validator,
 	}
 }
 
@@ -4861,7 +5486,7 @@ func (t *ExtractTableTool) InputSchema() types.ToolSchema {
 			},
 			"max_rows": map[string]interface{}{
 				"type":        "integer",
-				"description": "Maximum number of rows to extract (default: no limit)",
+				"description": "Maximum number of data rows to extract (default: no limit)",
 				"minimum":     1,
 			},
 			"column_filter": map[string]interface{}{
@@ -4880,6 +5505,41 @@ func (t *ExtractTableTool) InputSchema() types.ToolSchema {
 				"default":     0,
 				"minimum":     0,
 			},
+			"header_rows": map[string]interface{}{
+				"type":        "integer",
+				"description": "Number of header rows starting at header_row; grouped headers are joined, e.g. 'Q1 / Revenue' (default: 1)",
+				"default":     1,
+				"minimum":     1,
+			},
+			"span_fill": map[string]interface{}{
+				"type":        "string",
+				"description": "How cells spanning several columns or rows fill the grid: 'repeat' copies the value into every position, 'empty' leaves the covered positions blank (default: repeat)",
+				"enum":        []string{spanFillRepeat, spanFillEmpty},
+				"default":     spanFillRepeat,
+			},
+			"footer_rows": map[string]interface{}{
+				"type":        "string",
+				"description": "What to do with tfoot rows such as totals: 'include' after the body rows, 'exclude', or 'separate' to return them in a footer field (default: include)",
+				"enum":        []string{footerInclude, footerExclude, footerSeparate},
+				"default":     footerInclude,
+			},
+			"nested_tables": map[string]interface{}{
+				"type":        "string",
+				"description": "Tables inside cells: 'text' keeps their text in the cell, 'skip' leaves it out, 'extract' returns their rows separately (default: text)",
+				"enum":        []string{nestedText, nestedSkip, nestedExtract},
+				"default":     nestedText,
+			},
+			"output_file": map[string]interface{}{
+				"type":        "string",
+				"description": "Stream the table to this CSV file instead of returning it. Rows are read from the page in chunks, so tables with tens of thousands of rows don't need to fit in memory",
+			},
+			"chunk_size": map[string]interface{}{
+				"type":        "integer",
+				"description": "Rows read from the page per chunk when streaming to output_file (default: 1000)",
+				"default":     defaultTableChunkSize,
+				"minimum":     1,
+				"maximum":     maxTableChunkSize,
+			},
 		},
 		Required: []string{"selector"},
 	}
@@ -4904,35 +5564,65 @@ func (t *ExtractTableTool) Execute(args map[string]interface{}) (*types.CallTool
 
 	pageID, _ := args["page_id"].(string)
 	
-	includeHeaders := true
+	opts := tableOptions{
+		includeHeaders: true,
+		format:         "objects",
+		skipEmptyRows:  true,
+		headerRows:     1,
+		spanFill:       spanFillRepeat,
+		footer:         footerInclude,
+		nested:         nestedText,
+	}
 	if val, ok := args["include_headers"].(bool); ok {
-		includeHeaders = val
+		opts.includeHeaders = val
 	}
-
-	outputFormat := "objects"
 	if val, ok := args["output_format"].(string); ok {
-		outputFormat = val
+		opts.format = val
 	}
-
-	skipEmptyRows := true
 	if val, ok := args["skip_empty_rows"].(bool); ok {
-		skipEmptyRows = val
+		opts.skipEmptyRows = val
 	}
-
-	var maxRows *int
 	if val, ok := args["max_rows"].(float64); ok {
-		maxRowsInt := int(val)
-		maxRows = &maxRowsInt
+		opts.maxRows = int(val)
 	}
-
-	var columnFilter []interface{}
 	if val, ok := args["column_filter"].([]interface{}); ok {
-		columnFilter = val
+		opts.columnFilter = val
 	}
-
-	headerRow := 0
 	if val, ok := args["header_row"].(float64); ok {
-		headerRow = int(val)
+		opts.headerRow = int(val)
+	}
+	if val, ok := args["header_rows"].(float64); ok && val >= 1 {
+		opts.headerRows = int(val)
+	}
+	if val, ok := args["span_fill"].(string); ok && val != "" {
+		opts.spanFill = val
+	}
+	if val, ok := args["footer_rows"].(string); ok && val != "" {
+		opts.footer = val
+	}
+	if val, ok := args["nested_tables"].(string); ok && val != "" {
+		opts.nested = val
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	outputFile, _ := args["output_file"].(string)
+	chunkSize := defaultTableChunkSize
+	if outputFile != "" {
+		if format, ok := args["output_format"].(string); ok && format != "csv" {
+			return nil, fmt.Errorf("output_file always writes CSV; omit output_format or set it to csv")
+		}
+		opts.format = "csv"
+		if val, ok := args["chunk_size"].(float64); ok {
+			if val < 1 || val > maxTableChunkSize {
+				return nil, fmt.Errorf("chunk_size must be between 1 and %d", maxTableChunkSize)
+			}
+			chunkSize = int(val)
+		}
+		if err := t.validator.ValidatePath(outputFile, "write"); err != nil {
+			return nil, err
+		}
 	}
 
 	// Execute extraction in goroutine with timeout
@@ -4940,7 +5630,13 @@ func (t *ExtractTableTool) Execute(args map[string]interface{}) (*types.CallTool
 	errorChan := make(chan error, 1)
 
 	go func() {
-		result, err := t.extractTableData(pageID, selector, includeHeaders, outputFormat, skipEmptyRows, maxRows, columnFilter, headerRow)
+		var result *types.CallToolResponse
+		var err error
+		if outputFile != "" {
+			result, err = t.streamTableData(ctx, pageID, selector, opts, outputFile, chunkSize)
+		} else {
+			result, err = t.extractTableData(pageID, selector, opts)
+		}
 		if err != nil {
 			errorChan <- err
 			return
@@ -4959,253 +5655,125 @@ func (t *ExtractTableTool) Execute(args map[string]interface{}) (*types.CallTool
 	}
 }
 
-func (t *ExtractTableTool) extractTableData(pageID, selector string, includeHeaders bool, outputFormat string, skipEmptyRows bool, maxRows *int, columnFilter []interface{}, headerRow int) (*types.CallToolResponse, error) {
-	// Build JavaScript for table extraction
-	script := fmt.Sprintf(`
-		// Extract table data with comprehensive options
-		const table = document.querySelector('%s');
-		if (!table) {
-			return { error: 'Table not found with selector: %s' };
-		}
-
-		// Get all rows from table
-		let rows = [];
-		if (table.tagName === 'TABLE') {
-			// Full table - get all rows from tbody, thead, or directly from table
-			const tbody = table.querySelector('tbody');
-			const thead = table.querySelector('thead');
-			
-			if (thead) {
-				rows = rows.concat(Array.from(thead.querySelectorAll('tr')));
-			}
-			if (tbody) {
-				rows = rows.concat(Array.from(tbody.querySelectorAll('tr')));
-			} else {
-				// No tbody, get direct tr children
-				rows = Array.from(table.querySelectorAll('tr'));
-			}
-		} else {
-			// Selector points to tbody, thead, or other container
-			rows = Array.from(table.querySelectorAll('tr'));
-		}
-
-		if (rows.length === 0) {
-			return { error: 'No rows found in table' };
+// streamTableData streams a table to outputFile and reports what was written
+func (t *ExtractTableTool) streamTableData(ctx context.Context, pageID, selector string, opts tableOptions, outputFile string, chunkSize int) (*types.CallToolResponse, error) {
+	if pageID == "" {
+		pages := t.browserMgr.ListPages()
+		if len(pages) == 0 {
+			return createNoPagesErrorResponse(t.Name()), nil
 		}
+		pageID = pages[0]
+	}
 
-		// Extract cell data from rows
-		const rawData = rows.map((row, rowIndex) => {
-			const cells = Array.from(row.querySelectorAll('td, th'));
-			return cells.map(cell => {
-				// Get text content, handling nested elements
-				let text = cell.textContent || cell.innerText || '';
-				text = text.trim();
-				
-				// Check for special attributes
-				const href = cell.querySelector('a')?.href;
-				const src = cell.querySelector('img')?.src;
-				const value = cell.querySelector('input')?.value;
-				
-				// Return enhanced cell data
-				const cellData = { text: text };
-				if (href) cellData.link = href;
-				if (src) cellData.image = src;
-				if (value !== undefined) cellData.input_value = value;
-				
-				return cellData;
-			});
-		});
-
-		// Apply row filtering
-		let filteredData = rawData;
-		
-		// Skip empty rows if requested
-		const skipEmpty = %t;
-		if (skipEmpty) {
-			filteredData = filteredData.filter(row => 
-				row.some(cell => cell.text && cell.text.length > 0)
-			);
-		}
+	result, err := t.streamTableCSV(ctx, pageID, selector, opts, outputFile, chunkSize)
+	if extractErr, ok := err.(*tableExtractionError); ok {
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Table extraction failed: %s", extractErr.message),
+			}},
+			IsError: true,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
-		// Apply max rows limit
-		const maxRowsLimit = %s;
-		if (maxRowsLimit !== null) {
-			filteredData = filteredData.slice(0, maxRowsLimit);
-		}
+	responseData := map[string]interface{}{
+		"path":        result.path,
+		"rows":        result.rows,
+		"table_rows":  result.total,
+		"chunks":      result.chunks,
+		"size_bytes":  result.bytes,
+		"headers":     result.headers,
+		"format":      "csv",
+		"page_id":     pageID,
+	}
+	if opts.footer == footerSeparate {
+		responseData["footer"] = result.footer
+	}
 
-		// Determine headers
-		const headerRowIndex = %d;
-		const includeHeaders = %t;
-		let headers = [];
-		
-		if (includeHeaders && filteredData.length > headerRowIndex) {
-			headers = filteredData[headerRowIndex].map(cell => cell.text);
-		}
-
-		// Apply column filtering
-		const columnFilterList = %s;
-		let columnIndices = null;
-		if (columnFilterList && columnFilterList.length > 0) {
-			columnIndices = [];
-			for (const filter of columnFilterList) {
-				if (typeof filter === 'number') {
-					columnIndices.push(filter);
-				} else if (typeof filter === 'string' && headers.length > 0) {
-					// Find column by header name
-					const index = headers.indexOf(filter);
-					if (index !== -1) {
-						columnIndices.push(index);
-					}
-				}
-			}
-		}
+	return &types.CallToolResponse{
+		Content: []types.ToolContent{{
+			Type: "text",
+			Text: fmt.Sprintf("Streamed %d rows to %s (%d bytes, %d chunks)", result.rows, result.path, result.bytes, result.chunks),
+			Data: responseData,
+		}},
+	}, nil
+}
 
-		// Process data based on output format
-		const outputFormat = '%s';
-		let processedData;
-		
-		if (outputFormat === 'array') {
-			// Array of arrays format
-			processedData = filteredData.map(row => {
-				let rowData = row.map(cell => cell.text);
-				if (columnIndices) {
-					rowData = columnIndices.map(i => rowData[i] || '');
-				}
-				return rowData;
-			});
-		} else if (outputFormat === 'objects') {
-			// Array of objects format
-			if (headers.length === 0) {
-				// Generate default headers
-				const maxCols = Math.max(...filteredData.map(row => row.length));
-				headers = Array.from({length: maxCols}, (_, i) => 'column_' + i);
-			}
-			
-			const dataRows = includeHeaders ? filteredData.slice(headerRowIndex + 1) : filteredData;
-			processedData = dataRows.map(row => {
-				const obj = {};
-				let workingHeaders = headers;
-				if (columnIndices) {
-					workingHeaders = columnIndices.map(i => headers[i] || 'column_' + i);
-				}
-				
-				workingHeaders.forEach((header, index) => {
-					const cellIndex = columnIndices ? columnIndices[index] : index;
-					const cell = row[cellIndex];
-					if (cell) {
-						obj[header] = cell.text;
-						// Include additional data if present
-						if (cell.link) obj[header + '_link'] = cell.link;
-						if (cell.image) obj[header + '_image'] = cell.image;
-						if (cell.input_value !== undefined) obj[header + '_value'] = cell.input_value;
-					} else {
-						obj[header] = '';
-					}
-				});
-				return obj;
-			});
-		} else if (outputFormat === 'csv') {
-			// CSV string format
-			const csvRows = [];
-			
-			// Add headers if included
-			if (includeHeaders && headers.length > 0) {
-				let headerRow = headers;
-				if (columnIndices) {
-					headerRow = columnIndices.map(i => headers[i] || 'column_' + i);
-				}
-				csvRows.push(headerRow.map(h => '"' + h.replace(/"/g, '""') + '"').join(','));
-			}
-			
-			// Add data rows
-			const dataRows = includeHeaders ? filteredData.slice(headerRowIndex + 1) : filteredData;
-			dataRows.forEach(row => {
-				let csvRow = row.map(cell => cell.text);
-				if (columnIndices) {
-					csvRow = columnIndices.map(i => csvRow[i] || '');
-				}
-				csvRows.push(csvRow.map(text => '"' + (text || '').replace(/"/g, '""') + '"').join(','));
-			});
-			
-			processedData = csvRows.join('\n');
+func (t *ExtractTableTool) extractTableData(pageID, selector string, opts tableOptions) (*types.CallToolResponse, error) {
+	if pageID == "" {
+		pages := t.browserMgr.ListPages()
+		if len(pages) == 0 {
+			return createNoPagesErrorResponse(t.Name()), nil
 		}
-
-		return {
-			success: true,
-			data: processedData,
-			metadata: {
-				total_rows: filteredData.length,
-				total_columns: filteredData.length > 0 ? filteredData[0].length : 0,
-				headers: headers,
-				output_format: outputFormat,
-				table_selector: '%s'
-			}
-		};
-	`,
-	strings.ReplaceAll(selector, "'", "\\'"),
-	strings.ReplaceAll(selector, "'", "\\'"),
-	skipEmptyRows,
-	func() string { if maxRows != nil { return fmt.Sprintf("%d", *maxRows) } else { return "null" } }(),
-	headerRow,
-	includeHeaders,
-	func() string { 
-		if columnFilter != nil { 
-			filterJSON, _ := json.Marshal(columnFilter)
-			return string(filterJSON)
-		} else { 
-			return "null" 
-		} 
-	}(),
-	outputFormat,
-	strings.ReplaceAll(selector, "'", "\\'"))
-
-	result, err := t.browserMgr.ExecuteScript(pageID, script)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract table data: %w", err)
+		pageID = pages[0]
 	}
 
-	// Parse the JavaScript result
-	var jsResult map[string]interface{}
-	resultStr, ok := result.(string)
-	if !ok {
-		return nil, fmt.Errorf("unexpected result type from JavaScript execution")
-	}
-	if err := json.Unmarshal([]byte(resultStr), &jsResult); err != nil {
-		return nil, fmt.Errorf("failed to parse table extraction result: %w", err)
+	grid, err := t.fetchTableGrid(pageID, selector, opts.nested)
+	if err != nil {
+		return nil, err
 	}
 
 	// Check for extraction errors
-	if errorMsg, exists := jsResult["error"]; exists {
+	if grid.Error != "" {
 		return &types.CallToolResponse{
 			Content: []types.ToolContent{{
 				Type: "text",
-				Text: fmt.Sprintf("Table extraction failed: %v", errorMsg),
+				Text: fmt.Sprintf("Table extraction failed: %s", grid.Error),
 			}},
+			IsError: true,
 		}, nil
 	}
 
-	// Format successful response
-	data := jsResult["data"]
-	metadata := jsResult["metadata"]
+	table := shapeTable(grid, opts)
+	data, err := table.formatRows(table.rows, opts.format, opts.includeHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to format table data: %w", err)
+	}
+
+	metadata := map[string]interface{}{
+		"total_rows":     len(table.rows),
+		"total_columns":  len(table.columns),
+		"headers":        table.selectedHeaders(),
+		"output_format":  opts.format,
+		"table_selector": selector,
+		"footer_rows":    len(table.footer),
+	}
 
 	var responseText string
-	switch outputFormat {
+	switch opts.format {
 	case "csv":
 		responseText = fmt.Sprintf("Table extracted as CSV:\n\n%v", data)
 	case "array":
 		dataJSON, _ := json.MarshalIndent(data, "", "  ")
 		responseText = fmt.Sprintf("Table extracted as array:\n\n%s", string(dataJSON))
-	case "objects":
+	default:
 		dataJSON, _ := json.MarshalIndent(data, "", "  ")
 		responseText = fmt.Sprintf("Table extracted as objects:\n\n%s", string(dataJSON))
 	}
 
-	// Add metadata info
-	if meta, ok := metadata.(map[string]interface{}); ok {
-		responseText += fmt.Sprintf("\n\nMetadata:\n- Rows: %v\n- Columns: %v\n- Format: %v", 
-			meta["total_rows"], meta["total_columns"], meta["output_format"])
-		if headers, ok := meta["headers"].([]interface{}); ok && len(headers) > 0 {
-			responseText += fmt.Sprintf("\n- Headers: %v", headers)
+	responseText += fmt.Sprintf("\n\nMetadata:\n- Rows: %d\n- Columns: %d\n- Format: %s",
+		len(table.rows), len(table.columns), opts.format)
+	if opts.includeHeaders && len(table.columns) > 0 {
+		responseText += fmt.Sprintf("\n- Headers: %v", table.selectedHeaders())
+	}
+
+	responseData := map[string]interface{}{
+		"table_data": data,
+		"metadata":   metadata,
+		"format":     opts.format,
+		"page_id":    pageID,
+	}
+	if opts.footer == footerSeparate {
+		footer, err := table.formatRows(table.footer, opts.format, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format table footer: %w", err)
+		}
+		responseData["footer"] = footer
+		if len(table.footer) > 0 {
+			responseText += fmt.Sprintf("\n- Footer rows: %d", len(table.footer))
 		}
 	}
 
@@ -5213,11 +5781,7 @@ func (t *ExtractTableTool) extractTableData(pageID, selector string, includeHead
 		Content: []types.ToolContent{{
 			Type: "text",
 			Text: responseText,
-			Data: map[string]interface{}{
-				"table_data": data,
-				"metadata":   metadata,
-				"format":     outputFormat,
-			},
+			Data: responseData,
 		}},
 	}, nil
 }
@@ -0,0 +1,540 @@
+package webtools
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Tracker categories
+const (
+	trackerAnalytics     = "analytics"
+	trackerAdvertising   = "advertising"
+	trackerTagManager    = "tag_manager"
+	trackerSessionReplay = "session_replay"
+	trackerMarketing     = "marketing"
+)
+
+// defaultTrackerPhase is the phase of requests made before the first marker
+const defaultTrackerPhase = "page load"
+
+// maxTrackerMarkers bounds the action markers kept per page
+const maxTrackerMarkers = 50
+
+// trackerDomain identifies a tracking service by the host (and optionally
+// path prefix) its requests go to. A domain covers its subdomains.
+type trackerDomain struct {
+	Domain   string `json:"domain"`
+	Path     string `json:"path,omitempty"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Company  string `json:"company,omitempty"`
+}
+
+func (d trackerDomain) matches(u *url.URL) bool {
+	if !cookieMatchesHost(d.Domain, u.Hostname()) {
+		return false
+	}
+	// A path covers itself and everything below it, so /tr does not
+	// match /translate
+	path := strings.TrimSuffix(d.Path, "/")
+	return path == "" || u.Path == path || strings.HasPrefix(u.Path, path+"/")
+}
+
+// builtinTrackers covers widely deployed analytics, advertising and session
+// replay services
+var builtinTrackers = []trackerDomain{
+	{Domain: "google-analytics.com", Name: "Google Analytics", Category: trackerAnalytics, Company: "Google"},
+	{Domain: "analytics.google.com", Name: "Google Analytics", Category: trackerAnalytics, Company: "Google"},
+	{Domain: "googletagmanager.com", Name: "Google Tag Manager", Category: trackerTagManager, Company: "Google"},
+	{Domain: "doubleclick.net", Name: "DoubleClick", Category: trackerAdvertising, Company: "Google"},
+	{Domain: "googleadservices.com", Name: "Google Ads", Category: trackerAdvertising, Company: "Google"},
+	{Domain: "googlesyndication.com", Name: "Google AdSense", Category: trackerAdvertising, Company: "Google"},
+	{Domain: "connect.facebook.net", Name: "Meta Pixel", Category: trackerAdvertising, Company: "Meta"},
+	{Domain: "facebook.com", Path: "/tr", Name: "Meta Pixel", Category: trackerAdvertising, Company: "Meta"},
+	{Domain: "analytics.tiktok.com", Name: "TikTok Pixel", Category: trackerAdvertising, Company: "ByteDance"},
+	{Domain: "snap.licdn.com", Name: "LinkedIn Insight Tag", Category: trackerAdvertising, Company: "Microsoft"},
+	{Domain: "px.ads.linkedin.com", Name: "LinkedIn Insight Tag", Category: trackerAdvertising, Company: "Microsoft"},
+	{Domain: "bat.bing.com", Name: "Microsoft Advertising UET", Category: trackerAdvertising, Company: "Microsoft"},
+	{Domain: "clarity.ms", Name: "Microsoft Clarity", Category: trackerSessionReplay, Company: "Microsoft"},
+	{Domain: "static.ads-twitter.com", Name: "X Pixel", Category: trackerAdvertising, Company: "X"},
+	{Domain: "analytics.twitter.com", Name: "X Pixel", Category: trackerAdvertising, Company: "X"},
+	{Domain: "ct.pinterest.com", Name: "Pinterest Tag", Category: trackerAdvertising, Company: "Pinterest"},
+	{Domain: "alb.reddit.com", Name: "Reddit Pixel", Category: trackerAdvertising, Company: "Reddit"},
+	{Domain: "q.quora.com", Name: "Quora Pixel", Category: trackerAdvertising, Company: "Quora"},
+	{Domain: "sc-static.net", Name: "Snap Pixel", Category: trackerAdvertising, Company: "Snap"},
+	{Domain: "tr.snapchat.com", Name: "Snap Pixel", Category: trackerAdvertising, Company: "Snap"},
+	{Domain: "criteo.com", Name: "Criteo", Category: trackerAdvertising, Company: "Criteo"},
+	{Domain: "criteo.net", Name: "Criteo", Category: trackerAdvertising, Company: "Criteo"},
+	{Domain: "taboola.com", Name: "Taboola", Category: trackerAdvertising, Company: "Taboola"},
+	{Domain: "outbrain.com", Name: "Outbrain", Category: trackerAdvertising, Company: "Outbrain"},
+	{Domain: "adnxs.com", Name: "Xandr", Category: trackerAdvertising, Company: "Microsoft"},
+	{Domain: "amazon-adsystem.com", Name: "Amazon Ads", Category: trackerAdvertising, Company: "Amazon"},
+	{Domain: "adsrvr.org", Name: "The Trade Desk", Category: trackerAdvertising, Company: "The Trade Desk"},
+	{Domain: "rubiconproject.com", Name: "Magnite", Category: trackerAdvertising, Company: "Magnite"},
+	{Domain: "pubmatic.com", Name: "PubMatic", Category: trackerAdvertising, Company: "PubMatic"},
+	{Domain: "hotjar.com", Name: "Hotjar", Category: trackerSessionReplay, Company: "Hotjar"},
+	{Domain: "hotjar.io", Name: "Hotjar", Category: trackerSessionReplay, Company: "Hotjar"},
+	{Domain: "fullstory.com", Name: "FullStory", Category: trackerSessionReplay, Company: "FullStory"},
+	{Domain: "mouseflow.com", Name: "Mouseflow", Category: trackerSessionReplay, Company: "Mouseflow"},
+	{Domain: "logrocket.io", Name: "LogRocket", Category: trackerSessionReplay, Company: "LogRocket"},
+	{Domain: "lr-ingest.io", Name: "LogRocket", Category: trackerSessionReplay, Company: "LogRocket"},
+	{Domain: "smartlook.com", Name: "Smartlook", Category: trackerSessionReplay, Company: "Smartlook"},
+	{Domain: "mixpanel.com", Name: "Mixpanel", Category: trackerAnalytics, Company: "Mixpanel"},
+	{Domain: "mxpnl.com", Name: "Mixpanel", Category: trackerAnalytics, Company: "Mixpanel"},
+	{Domain: "segment.com", Name: "Segment", Category: trackerAnalytics, Company: "Twilio"},
+	{Domain: "segment.io", Name: "Segment", Category: trackerAnalytics, Company: "Twilio"},
+	{Domain: "amplitude.com", Name: "Amplitude", Category: trackerAnalytics, Company: "Amplitude"},
+	{Domain: "heapanalytics.com", Name: "Heap", Category: trackerAnalytics, Company: "Heap"},
+	{Domain: "heap.io", Name: "Heap", Category: trackerAnalytics, Company: "Heap"},
+	{Domain: "plausible.io", Name: "Plausible", Category: trackerAnalytics, Company: "Plausible"},
+	{Domain: "matomo.cloud", Name: "Matomo", Category: trackerAnalytics, Company: "Matomo"},
+	{Domain: "stats.wp.com", Name: "WordPress Stats", Category: trackerAnalytics, Company: "Automattic"},
+	{Domain: "scorecardresearch.com", Name: "Comscore", Category: trackerAnalytics, Company: "Comscore"},
+	{Domain: "quantserve.com", Name: "Quantcast", Category: trackerAnalytics, Company: "Quantcast"},
+	{Domain: "chartbeat.com", Name: "Chartbeat", Category: trackerAnalytics, Company: "Chartbeat"},
+	{Domain: "chartbeat.net", Name: "Chartbeat", Category: trackerAnalytics, Company: "Chartbeat"},
+	{Domain: "nr-data.net", Name: "New Relic Browser", Category: trackerAnalytics, Company: "New Relic"},
+	{Domain: "mc.yandex.ru", Name: "Yandex Metrica", Category: trackerAnalytics, Company: "Yandex"},
+	{Domain: "omtrdc.net", Name: "Adobe Analytics", Category: trackerAnalytics, Company: "Adobe"},
+	{Domain: "2o7.net", Name: "Adobe Analytics", Category: trackerAnalytics, Company: "Adobe"},
+	{Domain: "demdex.net", Name: "Adobe Audience Manager", Category: trackerAdvertising, Company: "Adobe"},
+	{Domain: "assets.adobedtm.com", Name: "Adobe Experience Platform Tags", Category: trackerTagManager, Company: "Adobe"},
+	{Domain: "tiqcdn.com", Name: "Tealium iQ", Category: trackerTagManager, Company: "Tealium"},
+	{Domain: "tealiumiq.com", Name: "Tealium", Category: trackerTagManager, Company: "Tealium"},
+	{Domain: "js.hs-scripts.com", Name: "HubSpot", Category: trackerMarketing, Company: "HubSpot"},
+	{Domain: "js.hs-analytics.net", Name: "HubSpot", Category: trackerMarketing, Company: "HubSpot"},
+	{Domain: "track.hubspot.com", Name: "HubSpot", Category: trackerMarketing, Company: "HubSpot"},
+	{Domain: "munchkin.marketo.net", Name: "Marketo Munchkin", Category: trackerMarketing, Company: "Adobe"},
+	{Domain: "pardot.com", Name: "Pardot", Category: trackerMarketing, Company: "Salesforce"},
+	{Domain: "klaviyo.com", Name: "Klaviyo", Category: trackerMarketing, Company: "Klaviyo"},
+	{Domain: "optimizely.com", Name: "Optimizely", Category: trackerMarketing, Company: "Optimizely"},
+}
+
+// TrackerList is the tracker domain list detect_trackers matches requests
+// against: the built-in entries plus any loaded from a file
+type TrackerList struct {
+	domains []trackerDomain
+}
+
+// LoadTrackerList returns the built-in list extended with the entries in
+// path (optional). The file is either a JSON array of {"domain", "path",
+// "name", "category", "company"} objects or an EasyPrivacy-style filter
+// list, of which only "||domain^" and "||domain/path" rules are used.
+func LoadTrackerList(path string) (*TrackerList, error) {
+	list := &TrackerList{}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tracker list: %w", err)
+		}
+		custom, err := parseTrackerList(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tracker list %s: %w", path, err)
+		}
+		list.domains = custom
+	}
+	list.domains = append(list.domains, builtinTrackers...)
+	return list, nil
+}
+
+func parseTrackerList(data []byte) ([]trackerDomain, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var domains []trackerDomain
+		if err := json.Unmarshal(trimmed, &domains); err != nil {
+			return nil, err
+		}
+		for i, d := range domains {
+			if d.Domain == "" {
+				return nil, fmt.Errorf("entry %d has no domain", i)
+			}
+			if d.Name == "" {
+				domains[i].Name = d.Domain
+			}
+			if d.Category == "" {
+				domains[i].Category = trackerAnalytics
+			}
+		}
+		return domains, nil
+	}
+
+	var domains []trackerDomain
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Comments, exceptions, cosmetic filters and rules with options
+		// are not domain rules
+		if !strings.HasPrefix(line, "||") || strings.ContainsAny(line, "$#*") {
+			continue
+		}
+		rule := strings.TrimSuffix(strings.TrimPrefix(line, "||"), "^")
+		domain, path := rule, ""
+		if i := strings.IndexAny(rule, "/^"); i >= 0 {
+			domain, path = rule[:i], strings.TrimRight(rule[i:], "^")
+		}
+		if domain == "" {
+			continue
+		}
+		domains = append(domains, trackerDomain{Domain: domain, Path: path, Name: domain, Category: trackerAnalytics})
+	}
+	return domains, scanner.Err()
+}
+
+// match returns the first entry covering u
+func (l *TrackerList) match(u *url.URL) (trackerDomain, bool) {
+	for _, d := range l.domains {
+		if d.matches(u) {
+			return d, true
+		}
+	}
+	return trackerDomain{}, false
+}
+
+// trackerMarker labels the user action that starts a phase of requests
+type trackerMarker struct {
+	Label string    `json:"label"`
+	At    time.Time `json:"at"`
+}
+
+// phaseAt names the phase a request started in: the last marker placed
+// before it
+func phaseAt(markers []trackerMarker, at time.Time) string {
+	phase := defaultTrackerPhase
+	for _, m := range markers {
+		if m.At.After(at) {
+			break
+		}
+		phase = m.Label
+	}
+	return phase
+}
+
+// trackerHit is one tracking service seen on the page
+type trackerHit struct {
+	Name     string         `json:"name"`
+	Category string         `json:"category"`
+	Company  string         `json:"company,omitempty"`
+	Domains  []string       `json:"domains"`
+	Requests int            `json:"requests"`
+	Failed   int            `json:"failed,omitempty"`
+	Phases   []string       `json:"phases"`
+	ByPhase  map[string]int `json:"by_phase"`
+	Samples  []string       `json:"samples"`
+}
+
+// unlistedBeacon is a third-party request that looks like tracking but
+// matched no list entry
+type unlistedBeacon struct {
+	URL          string `json:"url"`
+	ResourceType string `json:"resource_type"`
+	Phase        string `json:"phase"`
+}
+
+// trackerReport is everything detect_trackers reports for a page
+type trackerReport struct {
+	PageURL    string           `json:"page_url"`
+	Requests   int              `json:"requests"`
+	Markers    []trackerMarker  `json:"markers"`
+	Trackers   []*trackerHit    `json:"trackers"`
+	ByCategory map[string]int   `json:"by_category"`
+	Phases     []string         `json:"phases"`
+	Unlisted   []unlistedBeacon `json:"unlisted_beacons"`
+}
+
+// beaconPathWords are path segments typical of collection endpoints
+var beaconPathWords = []string{"collect", "pixel", "beacon", "track", "tracking", "event", "events", "analytics", "impression", "pageview", "hit"}
+
+// looksLikeBeacon reports whether a request that matched no list entry is
+// still probably tracking: sendBeacon pings, or pixel/collect endpoints
+func looksLikeBeacon(r browser.NetworkRequest, u *url.URL) bool {
+	switch r.ResourceType {
+	case "Ping":
+		return true
+	case "Image", "XHR", "Fetch":
+		for _, segment := range strings.Split(strings.ToLower(u.Path), "/") {
+			if i := strings.IndexByte(segment, '.'); i >= 0 {
+				segment = segment[:i]
+			}
+			if containsString(beaconPathWords, segment) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// detectTrackers matches a page's requests against the tracker list and
+// attributes each hit to the user action (marker) it followed
+func detectTrackers(pageURL string, requests []browser.NetworkRequest, list *TrackerList, markers []trackerMarker) trackerReport {
+	report := trackerReport{PageURL: pageURL, Markers: markers, ByCategory: make(map[string]int)}
+	pageSite := ""
+	if page, err := url.Parse(pageURL); err == nil {
+		pageSite = siteOf(page.Hostname())
+	}
+
+	hits := make(map[string]*trackerHit)
+	phasesSeen := make(map[string]bool)
+	for _, r := range requests {
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			continue
+		}
+		report.Requests++
+		phase := phaseAt(markers, r.StartedAt)
+
+		entry, ok := list.match(u)
+		if !ok {
+			if pageSite != "" && siteOf(u.Hostname()) != pageSite && looksLikeBeacon(r, u) {
+				report.Unlisted = append(report.Unlisted, unlistedBeacon{URL: r.URL, ResourceType: r.ResourceType, Phase: phase})
+			}
+			continue
+		}
+
+		hit, ok := hits[entry.Name]
+		if !ok {
+			hit = &trackerHit{Name: entry.Name, Category: entry.Category, Company: entry.Company, ByPhase: make(map[string]int)}
+			hits[entry.Name] = hit
+			report.ByCategory[entry.Category]++
+		}
+		hit.Requests++
+		if r.Failed {
+			hit.Failed++
+		}
+		if !containsString(hit.Domains, u.Hostname()) {
+			hit.Domains = append(hit.Domains, u.Hostname())
+		}
+		if hit.ByPhase[phase] == 0 {
+			hit.Phases = append(hit.Phases, phase)
+		}
+		hit.ByPhase[phase]++
+		if len(hit.Samples) < 3 {
+			hit.Samples = append(hit.Samples, r.URL)
+		}
+		if !phasesSeen[phase] {
+			phasesSeen[phase] = true
+			report.Phases = append(report.Phases, phase)
+		}
+	}
+
+	for _, hit := range hits {
+		report.Trackers = append(report.Trackers, hit)
+	}
+	sort.Slice(report.Trackers, func(i, j int) bool {
+		a, b := report.Trackers[i], report.Trackers[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+	return report
+}
+
+func formatTrackerReport(report trackerReport) string {
+	var b strings.Builder
+	if len(report.Trackers) == 0 {
+		fmt.Fprintf(&b, "No known trackers in %d requests from %s\n", report.Requests, report.PageURL)
+	} else {
+		fmt.Fprintf(&b, "%d trackers (%s) in %d requests from %s\n",
+			len(report.Trackers), formatCounts(report.ByCategory), report.Requests, report.PageURL)
+
+		b.WriteString("\nBy action:\n")
+		for _, phase := range report.Phases {
+			var names []string
+			for _, hit := range report.Trackers {
+				if n := hit.ByPhase[phase]; n > 0 {
+					names = append(names, fmt.Sprintf("%s (%d)", hit.Name, n))
+				}
+			}
+			fmt.Fprintf(&b, "  %s: %s\n", phase, strings.Join(names, ", "))
+		}
+
+		b.WriteString("\nTrackers:\n")
+		for _, hit := range report.Trackers {
+			owner := hit.Category
+			if hit.Company != "" {
+				owner += ", " + hit.Company
+			}
+			fmt.Fprintf(&b, "  %s [%s] %d requests to %s", hit.Name, owner, hit.Requests, strings.Join(hit.Domains, ", "))
+			if hit.Failed > 0 {
+				fmt.Fprintf(&b, ", %d failed", hit.Failed)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(report.Unlisted) > 0 {
+		fmt.Fprintf(&b, "\nPossible unlisted beacons (%d):\n", len(report.Unlisted))
+		for _, beacon := range report.Unlisted {
+			fmt.Fprintf(&b, "  - [%s] %s (%s)\n", beacon.ResourceType, clipText(beacon.URL, 160), beacon.Phase)
+		}
+	}
+	return b.String()
+}
+
+// DetectTrackersTool reports the analytics and marketing tags a page fires
+// and which user actions trigger them
+type DetectTrackersTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	list       *TrackerList
+
+	mutex   sync.Mutex
+	markers map[string][]trackerMarker // page ID -> markers in time order
+}
+
+// NewDetectTrackersTool creates the tool; a nil list uses the built-in
+// tracker domains only
+func NewDetectTrackersTool(log *logger.Logger, mgr *browser.Manager, list *TrackerList) *DetectTrackersTool {
+	if list == nil {
+		list = &TrackerList{domains: builtinTrackers}
+	}
+	return &DetectTrackersTool{
+		logger:     log,
+		browserMgr: mgr,
+		list:       list,
+		markers:    make(map[string][]trackerMarker),
+	}
+}
+
+func (t *DetectTrackersTool) Name() string {
+	return "detect_trackers"
+}
+
+func (t *DetectTrackersTool) Description() string {
+	return "Detect tracking pixels and analytics/marketing tags by matching the page's requests against a tracker domain list. Call with action 'mark' before each user action (click, form submit) to see which tags fire under which action, then 'report'"
+}
+
+func (t *DetectTrackersTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "'report' lists trackers seen since the last navigation; 'mark' labels the user action about to happen so later requests are attributed to it; 'reset' clears markers and the request log (default: report)",
+				"enum":        []string{"report", "mark", "reset"},
+				"default":     "report",
+			},
+			"label": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the user action for 'mark'",
+				"examples":    []string{"click add to cart", "accept cookies"},
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page ID (uses current page if not specified)",
+			},
+			"extra_domains": map[string]interface{}{
+				"type":        "array",
+				"description": "Additional tracker domains to match for this report. A domain covers its subdomains",
+				"items":       map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+}
+
+func (t *DetectTrackersTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pageID = t.browserMgr.GetCurrentPageID()
+			if pageID == "" {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+		}
+		if _, err := t.browserMgr.GetPage(pageID); err != nil {
+			return fail(err.Error())
+		}
+
+		action, _ := args["action"].(string)
+		var text string
+		data := map[string]interface{}{"page_id": pageID, "action": action}
+		switch action {
+		case "mark":
+			label, _ := args["label"].(string)
+			label = strings.TrimSpace(label)
+			if label == "" {
+				return fail("label is required for action 'mark'")
+			}
+			marker := trackerMarker{Label: label, At: time.Now()}
+			t.mutex.Lock()
+			markers := append(t.markers[pageID], marker)
+			if len(markers) > maxTrackerMarkers {
+				markers = markers[len(markers)-maxTrackerMarkers:]
+			}
+			t.markers[pageID] = markers
+			t.mutex.Unlock()
+			text = fmt.Sprintf("Marked %q; requests from now on are attributed to it until the next mark", label)
+			data["marker"] = marker
+
+		case "reset":
+			t.mutex.Lock()
+			delete(t.markers, pageID)
+			t.mutex.Unlock()
+			if err := t.browserMgr.ClearNetworkRequests(pageID); err != nil {
+				return fail(err.Error())
+			}
+			text = "Cleared action markers and the page's request log"
+
+		case "", "report":
+			data["action"] = "report"
+			list := t.list
+			if extra, ok := args["extra_domains"].([]interface{}); ok && len(extra) > 0 {
+				domains := append([]trackerDomain(nil), t.list.domains...)
+				for _, item := range extra {
+					if domain, ok := item.(string); ok && strings.TrimSpace(domain) != "" {
+						domain = strings.TrimSpace(domain)
+						domains = append(domains, trackerDomain{Domain: domain, Name: domain, Category: trackerAnalytics})
+					}
+				}
+				list = &TrackerList{domains: domains}
+			}
+
+			requests, dropped, err := t.browserMgr.NetworkRequests(pageID)
+			if err != nil {
+				return fail(err.Error())
+			}
+			info, err := t.browserMgr.GetPageInfo(pageID)
+			if err != nil {
+				return fail(err.Error())
+			}
+			pageURL, _ := info["url"].(string)
+
+			t.mutex.Lock()
+			markers := append([]trackerMarker(nil), t.markers[pageID]...)
+			t.mutex.Unlock()
+
+			report := detectTrackers(pageURL, requests, list, markers)
+			text = formatTrackerReport(report)
+			if dropped > 0 {
+				text += fmt.Sprintf("\n%d older requests were dropped from the log and not checked\n", dropped)
+			}
+			data["report"] = report
+			data["dropped"] = dropped
+
+		default:
+			return fail(fmt.Sprintf("unknown action %q (use report, mark or reset)", action))
+		}
+
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{Type: "text", Text: text, Data: data}},
+		}, nil
+	})
+}
@@ -0,0 +1,146 @@
+package webtools
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+func TestDetectTrackers(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return base.Add(time.Duration(seconds) * time.Second) }
+
+	requests := []browser.NetworkRequest{
+		{URL: "https://shop.example.com/", ResourceType: "Document", StartedAt: at(0)},
+		{URL: "https://www.googletagmanager.com/gtm.js?id=GTM-1", ResourceType: "Script", StartedAt: at(1)},
+		{URL: "https://region1.google-analytics.com/g/collect?en=page_view", ResourceType: "Fetch", StartedAt: at(2)},
+		{URL: "https://www.facebook.com/translate/widget.js", ResourceType: "Script", StartedAt: at(2)},
+		{URL: "https://www.facebook.com/tr/?ev=AddToCart", ResourceType: "Image", StartedAt: at(11)},
+		{URL: "https://region1.google-analytics.com/g/collect?en=add_to_cart", ResourceType: "Ping", StartedAt: at(11), Failed: true},
+		{URL: "https://metrics.unknown-vendor.io/v1/collect", ResourceType: "Ping", StartedAt: at(21)},
+		{URL: "https://cdn.other.net/login.js", ResourceType: "Script", StartedAt: at(21)},
+		{URL: "https://shop.example.com/api/track", ResourceType: "Fetch", StartedAt: at(21)},
+	}
+	markers := []trackerMarker{
+		{Label: "click add to cart", At: at(10)},
+		{Label: "checkout", At: at(20)},
+	}
+
+	list, err := LoadTrackerList("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := detectTrackers("https://shop.example.com/", requests, list, markers)
+
+	if report.Requests != 9 || len(report.Trackers) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	byName := make(map[string]*trackerHit)
+	for _, hit := range report.Trackers {
+		byName[hit.Name] = hit
+	}
+	ga := byName["Google Analytics"]
+	if ga == nil || ga.Requests != 2 || ga.Failed != 1 || ga.ByPhase["page load"] != 1 || ga.ByPhase["click add to cart"] != 1 {
+		t.Errorf("google analytics hit = %+v", ga)
+	}
+	// Only the /tr pixel counts, not the translate widget
+	if pixel := byName["Meta Pixel"]; pixel == nil || pixel.Requests != 1 || pixel.Phases[0] != "click add to cart" {
+		t.Errorf("meta pixel hit = %+v", pixel)
+	}
+	if report.ByCategory["analytics"] != 1 || report.ByCategory["advertising"] != 1 || report.ByCategory["tag_manager"] != 1 {
+		t.Errorf("by category = %v", report.ByCategory)
+	}
+	// First-party collection endpoints are not third-party beacons
+	if len(report.Unlisted) != 1 || report.Unlisted[0].Phase != "checkout" {
+		t.Errorf("unlisted = %+v", report.Unlisted)
+	}
+
+	text := formatTrackerReport(report)
+	for _, want := range []string{
+		"3 trackers (advertising 1, analytics 1, tag_manager 1) in 9 requests",
+		"  click add to cart: Meta Pixel (1), Google Analytics (1)",
+		"  Google Analytics [analytics, Google] 2 requests to region1.google-analytics.com, 1 failed",
+		"  - [Ping] https://metrics.unknown-vendor.io/v1/collect (checkout)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestLoadTrackerList(t *testing.T) {
+	dir := t.TempDir()
+	filterList := filepath.Join(dir, "easyprivacy.txt")
+	content := "! Title: test list\n||stats.example.org^\n||example.net/pixel/\n@@||allowed.example.org^\n||ads.example.com^$third-party\nexample.com##.banner\n"
+	if err := os.WriteFile(filterList, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err := LoadTrackerList(filterList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.domains) != len(builtinTrackers)+2 {
+		t.Fatalf("expected 2 custom entries, got %+v", list.domains[:len(list.domains)-len(builtinTrackers)])
+	}
+	for _, tc := range []struct {
+		url  string
+		want bool
+	}{
+		{"https://eu.stats.example.org/hit", true},
+		{"https://example.net/pixel/1.gif", true},
+		{"https://example.net/pixels.js", false},
+		{"https://allowed.example.org/", false},
+		{"https://ads.example.com/", false},
+	} {
+		u := mustParseURL(t, tc.url)
+		if _, got := list.match(u); got != tc.want {
+			t.Errorf("match(%s) = %v, want %v", tc.url, got, tc.want)
+		}
+	}
+
+	jsonList := filepath.Join(dir, "trackers.json")
+	if err := os.WriteFile(jsonList, []byte(`[{"domain": "collect.example.io", "name": "In-house", "category": "marketing"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	list, err = LoadTrackerList(jsonList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry, ok := list.match(mustParseURL(t, "https://collect.example.io/e")); !ok || entry.Name != "In-house" || entry.Category != "marketing" {
+		t.Errorf("json entry not matched: %+v", entry)
+	}
+
+	if err := os.WriteFile(jsonList, []byte(`[{"name": "no domain"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadTrackerList(jsonList); err == nil {
+		t.Error("expected an error for an entry without a domain")
+	}
+}
+
+func TestDetectTrackersNoPages(t *testing.T) {
+	log := createTestLogger(t)
+	tool := NewDetectTrackersTool(log, browser.NewManager(log, browser.Config{Headless: true}), nil)
+
+	resp, err := tool.Execute(map[string]interface{}{"action": "mark", "label": "click"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, "No browser pages") {
+		t.Errorf("Expected no pages error, got %q", resp.Content[0].Text)
+	}
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
@@ -0,0 +1,187 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/itchyny/gojq"
+)
+
+const (
+	// transformTimeout bounds a single transform run, so a runaway loop in
+	// a user's script can't hold up the tool
+	transformTimeout = 5 * time.Second
+	// maxTransformLength caps the size of a transform's source
+	maxTransformLength = 16 << 10
+)
+
+// resultTransform reshapes a tool's result on the server before it is
+// returned, with either a jq filter or a JavaScript function
+type resultTransform struct {
+	jq *gojq.Code
+	js *goja.Program
+}
+
+// transformSchema describes the transform argument
+func transformSchema(input string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "Reshape " + input + " on the server before it is returned, to trim the payload or rename fields. Give either jq, a jq filter (several outputs become an array), or js, a JavaScript function taking the value and returning the new one. Transforms have no network or file access and stop after 5 seconds",
+		"properties": map[string]interface{}{
+			"jq": map[string]interface{}{
+				"type":     "string",
+				"examples": []string{".items | map({name, price: .cost})", "[.[] | select(.in_stock)] | length"},
+			},
+			"js": map[string]interface{}{
+				"type":     "string",
+				"examples": []string{"data => data.items.map(i => ({name: i.title.trim(), price: parseFloat(i.price)}))"},
+			},
+		},
+	}
+}
+
+// parseTransform compiles the optional transform argument; it returns nil
+// when there is none
+func parseTransform(args map[string]interface{}) (*resultTransform, error) {
+	raw, ok := args["transform"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	obj, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("transform must be an object with jq or js")
+	}
+	jqSource, _ := obj["jq"].(string)
+	jsSource, _ := obj["js"].(string)
+	switch {
+	case jqSource != "" && jsSource != "":
+		return nil, fmt.Errorf("transform takes jq or js, not both")
+	case len(jqSource) > maxTransformLength || len(jsSource) > maxTransformLength:
+		return nil, fmt.Errorf("transform is longer than %d bytes", maxTransformLength)
+	case jqSource != "":
+		query, err := gojq.Parse(jqSource)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jq transform: %w", err)
+		}
+		code, err := gojq.Compile(query)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jq transform: %w", err)
+		}
+		return &resultTransform{jq: code}, nil
+	case jsSource != "":
+		// Parenthesized so a function expression evaluates to the function
+		program, err := goja.Compile("transform", "("+jsSource+"\n)", true)
+		if err != nil {
+			return nil, fmt.Errorf("invalid js transform: %w", err)
+		}
+		return &resultTransform{js: program}, nil
+	}
+	return nil, fmt.Errorf("transform must be an object with jq or js")
+}
+
+// jsonValue converts v to the plain maps, slices, strings, float64s and
+// bools that encoding/json produces, which both engines work on
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// apply runs the transform on value and returns its result
+func (t *resultTransform) apply(value interface{}) (interface{}, error) {
+	input, err := jsonValue(value)
+	if err != nil {
+		return nil, fmt.Errorf("transform input is not JSON: %w", err)
+	}
+	var out interface{}
+	if t.jq != nil {
+		out, err = t.runJQ(input)
+	} else {
+		out, err = t.runJS(input)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if out, err = jsonValue(out); err != nil {
+		return nil, fmt.Errorf("transform result is not JSON: %w", err)
+	}
+	return out, nil
+}
+
+// runJQ returns the filter's only output, or an array when it produces
+// several
+func (t *resultTransform) runJQ(input interface{}) (interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), transformTimeout)
+	defer cancel()
+	var outputs []interface{}
+	iter := t.jq.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			var halt *gojq.HaltError
+			if errors.As(err, &halt) && halt.Value() == nil {
+				break
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, fmt.Errorf("jq transform took longer than %v", transformTimeout)
+			}
+			return nil, fmt.Errorf("jq transform failed: %w", err)
+		}
+		outputs = append(outputs, v)
+	}
+	switch len(outputs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return outputs[0], nil
+	}
+	return outputs, nil
+}
+
+// runJS calls the function in a fresh runtime, interrupting it if it runs
+// past the timeout
+func (t *resultTransform) runJS(input interface{}) (interface{}, error) {
+	vm := goja.New()
+	timer := time.AfterFunc(transformTimeout, func() {
+		vm.Interrupt(fmt.Sprintf("js transform took longer than %v", transformTimeout))
+	})
+	defer timer.Stop()
+
+	fnValue, err := vm.RunProgram(t.js)
+	if err != nil {
+		return nil, jsTransformError(err)
+	}
+	fn, ok := goja.AssertFunction(fnValue)
+	if !ok {
+		return nil, fmt.Errorf("js transform must be a function, e.g. data => data.items")
+	}
+	result, err := fn(goja.Undefined(), vm.ToValue(input))
+	if err != nil {
+		return nil, jsTransformError(err)
+	}
+	if goja.IsUndefined(result) || goja.IsNull(result) {
+		return nil, nil
+	}
+	return result.Export(), nil
+}
+
+func jsTransformError(err error) error {
+	var interrupted *goja.InterruptedError
+	if errors.As(err, &interrupted) {
+		return fmt.Errorf("%v", interrupted.Value())
+	}
+	return fmt.Errorf("js transform failed: %w", err)
+}
@@ -0,0 +1,126 @@
+package webtools
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func runTransform(t *testing.T, spec map[string]interface{}, input interface{}) (interface{}, error) {
+	t.Helper()
+	transform, err := parseTransform(map[string]interface{}{"transform": spec})
+	if err != nil {
+		t.Fatalf("parseTransform(%v): %v", spec, err)
+	}
+	return transform.apply(input)
+}
+
+func TestTransformJQ(t *testing.T) {
+	input := map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"title": "A", "cost": 3, "stock": true},
+			{"title": "B", "cost": 5, "stock": false},
+		},
+	}
+	cases := []struct {
+		jq   string
+		want string
+	}{
+		{".items | map({name: .title, price: .cost})", "[map[name:A price:3] map[name:B price:5]]"},
+		{".items[] | select(.stock) | .title", "A"},
+		{".items[].title", "[A B]"},
+		{".missing", "<nil>"},
+		{"empty", "<nil>"},
+	}
+	for _, c := range cases {
+		got, err := runTransform(t, map[string]interface{}{"jq": c.jq}, input)
+		if err != nil {
+			t.Errorf("%s: %v", c.jq, err)
+			continue
+		}
+		if fmt.Sprint(got) != c.want {
+			t.Errorf("%s = %v, want %s", c.jq, got, c.want)
+		}
+	}
+
+	if _, err := runTransform(t, map[string]interface{}{"jq": ".items | error(\"boom\")"}, input); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("jq error not reported: %v", err)
+	}
+}
+
+func TestTransformJS(t *testing.T) {
+	input := []interface{}{
+		map[string]interface{}{"title": " A ", "price": "3.50"},
+		map[string]interface{}{"title": "B", "price": "5"},
+	}
+	got, err := runTransform(t, map[string]interface{}{
+		"js": "items => items.map(i => ({name: i.title.trim(), price: parseFloat(i.price)}))",
+	}, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(got) != "[map[name:A price:3.5] map[name:B price:5]]" {
+		t.Errorf("got %v", got)
+	}
+
+	got, err = runTransform(t, map[string]interface{}{"js": "function (d) { return d.length }"}, input)
+	if err != nil || fmt.Sprint(got) != "2" {
+		t.Errorf("function expression: %v %v", got, err)
+	}
+
+	if _, err := runTransform(t, map[string]interface{}{"js": "42"}, input); err == nil || !strings.Contains(err.Error(), "must be a function") {
+		t.Errorf("non-function accepted: %v", err)
+	}
+	if _, err := runTransform(t, map[string]interface{}{"js": "d => d.nope.deeper"}, input); err == nil {
+		t.Error("script error not reported")
+	}
+}
+
+func TestParseTransformErrors(t *testing.T) {
+	cases := []struct {
+		spec interface{}
+		want string
+	}{
+		{"jq .", "must be an object"},
+		{map[string]interface{}{}, "jq or js"},
+		{map[string]interface{}{"jq": ".", "js": "d => d"}, "not both"},
+		{map[string]interface{}{"jq": ".items[ |"}, "invalid jq"},
+		{map[string]interface{}{"js": "d => {"}, "invalid js"},
+		{map[string]interface{}{"jq": strings.Repeat(".", maxTransformLength+1)}, "longer than"},
+	}
+	for _, c := range cases {
+		_, err := parseTransform(map[string]interface{}{"transform": c.spec})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("parseTransform(%v) = %v, want error containing %q", c.spec, err, c.want)
+		}
+	}
+	if transform, err := parseTransform(map[string]interface{}{}); transform != nil || err != nil {
+		t.Errorf("no transform: %v %v", transform, err)
+	}
+}
+
+func TestHTTPRequestTransform(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data": {"users": [{"id": 1, "full_name": "Ada"}, {"id": 2, "full_name": "Alan"}]}, "meta": {"page": 1}}`))
+	}))
+	defer server.Close()
+
+	tool := NewHTTPRequestTool(createTestLogger(t), nil, nil)
+	resp, err := tool.Execute(map[string]interface{}{
+		"url":       server.URL,
+		"transform": map[string]interface{}{"jq": "[.data.users[] | {id, name: .full_name}]"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := resp.Content[0].Data.(map[string]interface{})
+	if fmt.Sprint(data["body"]) != "[map[id:1 name:Ada] map[id:2 name:Alan]]" || data["transformed"] != true {
+		t.Errorf("body = %v", data["body"])
+	}
+	if strings.Contains(resp.Content[0].Text, "meta") {
+		t.Errorf("response text still holds the untransformed body:\n%s", resp.Content[0].Text)
+	}
+}
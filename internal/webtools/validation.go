@@ -39,6 +39,10 @@ func (e *ValidationError) Error() string {
 	return msg
 }
 
+// selectorSyntax describes the selector forms element tools accept (see
+// browser.ParseSelector)
+const selectorSyntax = "CSS by default; XPath when it starts with // or xpath=; text=Sign in matches the innermost element containing that text (ignoring case) and text=\"Sign in\" its exact text"
+
 // ValidateSelector provides comprehensive CSS selector validation
 func ValidateSelector(selector string, toolName string) error {
 	if selector == "" {
@@ -62,6 +66,11 @@ func ValidateSelector(selector string, toolName string) error {
 			HelpTopic: toolName,
 		}
 	}
+
+	// Text selectors match page text, spaces and all
+	if strings.HasPrefix(strings.TrimSpace(selector), "text=") {
+		return nil
+	}
 	
 	// Check for common selector issues
 	if strings.Contains(selector, "  ") {
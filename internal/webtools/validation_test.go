@@ -95,6 +95,7 @@ func TestValidateSelector_ValidSelectors(t *testing.T) {
 		".parent .child",
 		"//button[text()='Login']",
 		"//div[@class='content']",
+		"text=Sign  in",
 		"button[type='submit']",
 		"form .error-message",
 	}
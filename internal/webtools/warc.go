@@ -0,0 +1,214 @@
+package webtools
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+// WARC 1.1 (ISO 28500:2017), the format web archives are stored in and
+// replay tools like pywb read. Each request is written as a request record
+// and a response record holding the HTTP messages as the page saw them.
+
+// warcStats counts what a WARC file holds
+type warcStats struct {
+	Responses int // response records written
+	// Skipped are finished requests left out because the browser no
+	// longer had their body
+	Skipped []string
+}
+
+// isWARCPath reports whether path names a WARC file, and whether it
+// should be gzipped
+func isWARCPath(path string) (warc, compressed bool) {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".warc.gz") {
+		return true, true
+	}
+	return filepath.Ext(lower) == ".warc", false
+}
+
+// warcRecordID returns a new record ID in the urn:uuid form WARC uses
+func warcRecordID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// warcDigest is the SHA-1 digest form WARC-Block-Digest and
+// WARC-Payload-Digest use
+func warcDigest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
+
+// warcWriter writes records, each as its own gzip member when compressed
+// so readers can seek to any record
+type warcWriter struct {
+	w        io.Writer
+	compress bool
+}
+
+// write writes one record; fields are the named WARC headers in order,
+// Content-Length is added
+func (ww *warcWriter) write(fields [][2]string, block []byte) error {
+	var record bytes.Buffer
+	record.WriteString("WARC/1.1\r\n")
+	for _, f := range fields {
+		fmt.Fprintf(&record, "%s: %s\r\n", f[0], f[1])
+	}
+	fmt.Fprintf(&record, "Content-Length: %d\r\n\r\n", len(block))
+	record.Write(block)
+	record.WriteString("\r\n\r\n")
+
+	if !ww.compress {
+		_, err := ww.w.Write(record.Bytes())
+		return err
+	}
+	gz := gzip.NewWriter(ww.w)
+	if _, err := gz.Write(record.Bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// writeHTTPHeaders writes headers one per line, sorted by name, skipping
+// HTTP/2 pseudo-headers and those named in skip
+func writeHTTPHeaders(buf *bytes.Buffer, headers map[string]string, skip ...string) {
+	for _, h := range harHeaders(headers) {
+		if strings.HasPrefix(h.Name, ":") || containsFold(skip, h.Name) {
+			continue
+		}
+		fmt.Fprintf(buf, "%s: %s\r\n", h.Name, h.Value)
+	}
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// warcRequestBlock rebuilds the HTTP request message
+func warcRequestBlock(r *browser.CapturedRequest, u *url.URL) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s HTTP/1.1\r\n", r.Method, u.RequestURI())
+	if headerValue(r.RequestHeaders, "Host") == "" {
+		fmt.Fprintf(&buf, "Host: %s\r\n", u.Host)
+	}
+	writeHTTPHeaders(&buf, r.RequestHeaders)
+	buf.WriteString("\r\n")
+	buf.WriteString(r.PostData)
+	return buf.Bytes()
+}
+
+// warcResponseBlock rebuilds the HTTP response message. The browser hands
+// out decoded bodies, so transfer and content encodings are dropped and
+// Content-Length is set to match what was written.
+func warcResponseBlock(r *browser.CapturedRequest, body []byte) []byte {
+	var buf bytes.Buffer
+	statusText := r.StatusText
+	if statusText == "" {
+		statusText = http.StatusText(r.Status)
+	}
+	fmt.Fprintf(&buf, "HTTP/1.1 %d %s\r\n", r.Status, statusText)
+	writeHTTPHeaders(&buf, r.ResponseHeaders, "Content-Encoding", "Transfer-Encoding", "Content-Length")
+	fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n", len(body))
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+// hasNoBody reports whether a response carries no body by definition
+func hasNoBody(r *browser.CapturedRequest) bool {
+	return r.RedirectedTo != "" || r.Method == http.MethodHead ||
+		r.Status == http.StatusNoContent || r.Status == http.StatusNotModified || r.Status < 200
+}
+
+// writeWARC writes a capture as a WARC file: a warcinfo record, then a
+// response and request record for every finished HTTP request. bodyOf
+// returns a response's body, or false when the browser no longer has it;
+// such requests are left out rather than archived with an empty body.
+func writeWARC(w io.Writer, capture *browser.NetworkCapture, compress bool, filename string, bodyOf func(*browser.CapturedRequest) ([]byte, bool)) (warcStats, error) {
+	var stats warcStats
+	ww := &warcWriter{w: w, compress: compress}
+
+	info := fmt.Sprintf("software: rodmcp/%s\r\nformat: WARC File Format 1.1\r\nconformsTo: http://iipc.github.io/warc-specifications/specifications/warc-format/warc-1.1/\r\n", rodmcpVersion())
+	err := ww.write([][2]string{
+		{"WARC-Type", "warcinfo"},
+		{"WARC-Record-ID", warcRecordID()},
+		{"WARC-Date", capture.StartedAt.UTC().Format(time.RFC3339)},
+		{"WARC-Filename", filename},
+		{"Content-Type", "application/warc-fields"},
+	}, []byte(info))
+	if err != nil {
+		return stats, err
+	}
+
+	for _, r := range capture.Requests {
+		u, err := url.Parse(r.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !r.Finished || r.Failed || r.Status == 0 {
+			continue
+		}
+		var body []byte
+		if !hasNoBody(r) {
+			var ok bool
+			if body, ok = bodyOf(r); !ok {
+				stats.Skipped = append(stats.Skipped, r.URL)
+				continue
+			}
+		}
+
+		date := r.StartedAt.UTC().Format(time.RFC3339)
+		responseID := warcRecordID()
+		block := warcResponseBlock(r, body)
+		fields := [][2]string{
+			{"WARC-Type", "response"},
+			{"WARC-Record-ID", responseID},
+			{"WARC-Date", date},
+			{"WARC-Target-URI", r.URL},
+		}
+		if ip := strings.Trim(r.RemoteIP, "[]"); ip != "" {
+			fields = append(fields, [2]string{"WARC-IP-Address", ip})
+		}
+		fields = append(fields,
+			[2]string{"Content-Type", "application/http; msgtype=response"},
+			[2]string{"WARC-Block-Digest", warcDigest(block)},
+			[2]string{"WARC-Payload-Digest", warcDigest(body)},
+		)
+		if err := ww.write(fields, block); err != nil {
+			return stats, err
+		}
+
+		block = warcRequestBlock(r, u)
+		err = ww.write([][2]string{
+			{"WARC-Type", "request"},
+			{"WARC-Record-ID", warcRecordID()},
+			{"WARC-Date", date},
+			{"WARC-Target-URI", r.URL},
+			{"WARC-Concurrent-To", responseID},
+			{"Content-Type", "application/http; msgtype=request"},
+			{"WARC-Block-Digest", warcDigest(block)},
+		}, block)
+		if err != nil {
+			return stats, err
+		}
+		stats.Responses++
+	}
+	return stats, nil
+}
@@ -0,0 +1,160 @@
+package webtools
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+// warcRecord is a parsed WARC record
+type warcRecord struct {
+	headers http.Header
+	block   []byte
+}
+
+func readWARC(t *testing.T, r io.Reader) []warcRecord {
+	t.Helper()
+	var records []warcRecord
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return records
+		}
+		if err != nil || line != "WARC/1.1\r\n" {
+			t.Fatalf("expected a record, got %q %v", line, err)
+		}
+		headers := http.Header{}
+		for {
+			line, _ = br.ReadString('\n')
+			if line == "\r\n" {
+				break
+			}
+			name, value, _ := strings.Cut(strings.TrimRight(line, "\r\n"), ": ")
+			headers.Add(name, value)
+		}
+		length, _ := strconv.Atoi(headers.Get("Content-Length"))
+		block := make([]byte, length+4)
+		if _, err := io.ReadFull(br, block); err != nil || string(block[length:]) != "\r\n\r\n" {
+			t.Fatalf("record %s: bad block: %v", headers.Get("WARC-Type"), err)
+		}
+		records = append(records, warcRecord{headers: headers, block: block[:length]})
+	}
+}
+
+func TestWriteWARC(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	request := func(url string, status int) browser.NetworkRequest {
+		return browser.NetworkRequest{RequestID: url, URL: url, Method: "GET", StartedAt: started, Status: status, Finished: true}
+	}
+	redirect := request("http://example.com/", 301)
+	redirect.RedirectedTo = "https://example.com/"
+	failed := request("https://ads.example.net/x.js", 0)
+	failed.Failed = true
+	capture := &browser.NetworkCapture{
+		StartedAt: started,
+		Requests: []*browser.CapturedRequest{
+			{NetworkRequest: redirect, ResponseHeaders: map[string]string{"Location": "https://example.com/"}},
+			{
+				NetworkRequest:  request("https://example.com/", 200),
+				RequestHeaders:  map[string]string{"Accept": "text/html", ":authority": "example.com"},
+				ResponseHeaders: map[string]string{"Content-Type": "text/html", "Content-Encoding": "br", "Content-Length": "12", "Set-Cookie": "a=1\nb=2"},
+			},
+			{NetworkRequest: request("https://example.com/evicted.png", 200)},
+			{NetworkRequest: failed},
+			{NetworkRequest: request("data:image/png;base64,AAAA", 200)},
+		},
+	}
+	bodies := map[string]string{"https://example.com/": "<h1>Hi</h1>"}
+	bodyOf := func(r *browser.CapturedRequest) ([]byte, bool) {
+		body, ok := bodies[r.RequestID]
+		return []byte(body), ok
+	}
+
+	var plain bytes.Buffer
+	stats, err := writeWARC(&plain, capture, false, "capture.warc", bodyOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Responses != 2 || len(stats.Skipped) != 1 || stats.Skipped[0] != "https://example.com/evicted.png" {
+		t.Errorf("stats = %+v", stats)
+	}
+	records := readWARC(t, &plain)
+	var types []string
+	for _, r := range records {
+		types = append(types, r.headers.Get("WARC-Type"))
+	}
+	if strings.Join(types, ",") != "warcinfo,response,request,response,request" {
+		t.Fatalf("record types = %v", types)
+	}
+
+	page, pageRequest := records[3], records[4]
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(page.block)), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || string(body) != "<h1>Hi</h1>" || resp.Header.Get("Content-Encoding") != "" || len(resp.Header.Values("Set-Cookie")) != 2 {
+		t.Errorf("response = %+v, body %q", resp, body)
+	}
+	if page.headers.Get("WARC-Block-Digest") != warcDigest(page.block) || page.headers.Get("WARC-Payload-Digest") != warcDigest(body) {
+		t.Error("digests don't match the record")
+	}
+	if pageRequest.headers.Get("WARC-Concurrent-To") != page.headers.Get("WARC-Record-ID") {
+		t.Error("request record isn't linked to its response")
+	}
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(pageRequest.block)))
+	if err != nil || req.Host != "example.com" || req.Header.Get("Accept") != "text/html" || strings.Contains(string(pageRequest.block), ":authority") {
+		t.Errorf("request = %q, %v", pageRequest.block, err)
+	}
+
+	// Gzipped WARCs hold one gzip member per record
+	var compressed bytes.Buffer
+	if _, err := writeWARC(&compressed, capture, true, "capture.warc.gz", bodyOf); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&compressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz.Multistream(false)
+	members := 0
+	for {
+		if _, err := io.Copy(io.Discard, gz); err != nil {
+			t.Fatal(err)
+		}
+		members++
+		if err := gz.Reset(&compressed); err == io.EOF {
+			break
+		}
+		gz.Multistream(false)
+	}
+	if members != 5 {
+		t.Errorf("got %d gzip members, want 5", members)
+	}
+}
+
+func TestIsWARCPath(t *testing.T) {
+	cases := []struct {
+		path           string
+		warc, compress bool
+	}{
+		{"crawl.warc", true, false},
+		{"crawl.WARC.GZ", true, true},
+		{"crawl.har", false, false},
+		{"crawl.gz", false, false},
+	}
+	for _, tc := range cases {
+		if warc, compress := isWARCPath(tc.path); warc != tc.warc || compress != tc.compress {
+			t.Errorf("isWARCPath(%q) = %v, %v", tc.path, warc, compress)
+		}
+	}
+}
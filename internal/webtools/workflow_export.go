@@ -0,0 +1,642 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// Workflow export formats
+const (
+	ExportPlaywright = "playwright"
+	ExportRodGo      = "rod-go"
+)
+
+// ExportFormats lists the formats ExportWorkflow accepts
+var ExportFormats = []string{ExportPlaywright, ExportRodGo}
+
+// formFieldName matches form_fill keys that name a field by its name or id
+// attribute rather than by selector
+var formFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// formFieldTags are the bare words form_fill keys treat as CSS tag selectors
+var formFieldTags = map[string]bool{"input": true, "select": true, "textarea": true, "button": true}
+
+// ExportWorkflow loads the named workflow from dir (default: workflows)
+// and renders it as a standalone program in format. The notes list the
+// steps that could not be translated and were left as TODO comments.
+func ExportWorkflow(dir, name, format string) (string, []string, error) {
+	w, err := newWorkflowStore(dir).load(name)
+	if err != nil {
+		return "", nil, err
+	}
+	return exportWorkflow(w, format)
+}
+
+// exportWorkflow renders a validated workflow. Every step runs on the one
+// page the program opens, so page_id arguments are dropped. Conditions,
+// loops, sub-workflows, parallel steps and tools without a direct
+// counterpart become TODO comments rather than guesses.
+func exportWorkflow(w *workflow, format string) (string, []string, error) {
+	var target exportTarget
+	switch format {
+	case ExportPlaywright:
+		target = &playwrightTarget{}
+	case ExportRodGo:
+		target = &rodTarget{}
+	default:
+		return "", nil, fmt.Errorf("format must be %s", strings.Join(ExportFormats, " or "))
+	}
+
+	e := &workflowExporter{w: w, target: target, results: make(map[string]bool)}
+	for _, step := range w.Steps {
+		e.step(step)
+	}
+	code, err := target.program(w, exportVars(w), e.body, len(e.results) > 0)
+	if err != nil {
+		return "", nil, err
+	}
+	return code, e.notes, nil
+}
+
+// exportVar is a workflow variable as the generated program reads it: from
+// the environment variable Env, else Default
+type exportVar struct {
+	Name     string
+	Env      string
+	Default  string
+	Required bool
+}
+
+// exportVars returns the workflow's variables sorted by name
+func exportVars(w *workflow) []exportVar {
+	vars := make([]exportVar, 0, len(w.Vars))
+	for name, v := range w.Vars {
+		vars = append(vars, exportVar{
+			Name:     name,
+			Env:      strings.ToUpper(name),
+			Default:  formatTemplateValue(v.Default),
+			Required: v.Default == nil,
+		})
+	}
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars
+}
+
+// exportTarget writes the expressions and statements of one language.
+// Element arguments are expressions from element; text arguments are
+// string expressions.
+type exportTarget interface {
+	quote(s string) string
+	variable(name string) string
+	env(name string) string
+	result(id string) string
+	firstSet(alternatives []string) string
+	element(query string, xpath bool) string
+	child(parent, query string, xpath bool) string
+
+	navigate(url string) []string
+	click(el string) []string
+	hover(el string) []string
+	typeText(el, text string, clear bool) []string
+	fill(el, value string) []string
+	check(el string, checked bool) []string
+	submit(form string) []string
+	waitFor(query string, sel browser.Selector, state string, timeout time.Duration) ([]string, error)
+	sleep(d time.Duration) []string
+	press(el string, modifiers []string, key string, repeat int) ([]string, error)
+	storeText(id, el string) []string
+	storeAttribute(id, el, name string) []string
+	screenshot(path string) []string
+	evaluate(fn string) []string
+
+	program(w *workflow, vars []exportVar, body []string, results bool) (string, error)
+
+	// begin starts a step, and discard forgets what it needed when it
+	// turns out it cannot be exported
+	begin()
+	discard()
+}
+
+// workflowExporter walks a workflow's steps, collecting the statements the
+// target writes for them
+type workflowExporter struct {
+	w      *workflow
+	target exportTarget
+	body   []string
+	notes  []string
+	// results holds the IDs of steps whose text the program keeps, so
+	// later steps can use steps.ID.text
+	results map[string]bool
+}
+
+// step exports one step, or leaves a TODO comment saying why it cannot
+func (e *workflowExporter) step(step workflowStep) {
+	var (
+		lines []string
+		err   error
+	)
+	e.target.begin()
+	switch {
+	case step.If != "":
+		err = fmt.Errorf("conditions are not exported")
+	case step.ForEach != nil:
+		err = fmt.Errorf("for_each loops are not exported")
+	case step.Workflow != "":
+		err = fmt.Errorf("workflow steps are not exported; export %s separately", step.Workflow)
+	case len(step.Parallel) > 0:
+		err = fmt.Errorf("parallel steps are not exported")
+	default:
+		lines, err = e.tool(step)
+	}
+	if err != nil {
+		e.target.discard()
+		delete(e.results, step.ID)
+		e.notes = append(e.notes, fmt.Sprintf("step %s: %v", step.ID, err))
+		definition, _ := json.Marshal(step)
+		e.body = append(e.body, fmt.Sprintf("// TODO step %s: %v", step.ID, err), "// "+string(definition))
+		return
+	}
+	e.body = append(e.body, lines...)
+}
+
+// tool translates a tool step
+func (e *workflowExporter) tool(step workflowStep) ([]string, error) {
+	t := e.target
+	args := step.Args
+	switch step.Tool {
+	case "navigate_page", "create_page":
+		if args["url"] == nil && step.Tool == "create_page" {
+			return nil, nil
+		}
+		url, err := e.text(args["url"])
+		if err != nil {
+			return nil, fmt.Errorf("url: %w", err)
+		}
+		return t.navigate(url), nil
+
+	case "click_element", "hover_element":
+		el, _, err := e.element(args["selector"])
+		if err != nil {
+			return nil, err
+		}
+		if step.Tool == "hover_element" {
+			return t.hover(el), nil
+		}
+		return t.click(el), nil
+
+	case "type_text":
+		el, _, err := e.element(args["selector"])
+		if err != nil {
+			return nil, err
+		}
+		text, err := e.text(args["text"])
+		if err != nil {
+			return nil, fmt.Errorf("text: %w", err)
+		}
+		clear, ok := args["clear"].(bool)
+		return t.typeText(el, text, clear || !ok), nil
+
+	case "form_fill":
+		return e.formFill(args)
+
+	case "wait_for_element":
+		query, sel, err := e.selector(args["selector"])
+		if err != nil {
+			return nil, err
+		}
+		state, _ := args["state"].(string)
+		if state == "" {
+			state = "attached"
+		}
+		timeout := 10 * time.Second
+		if seconds, ok := args["timeout"].(float64); ok && seconds > 0 {
+			timeout = time.Duration(seconds * float64(time.Second))
+		}
+		return t.waitFor(query, sel, state, timeout)
+
+	case "wait":
+		seconds, ok := args["seconds"].(float64)
+		if !ok {
+			return nil, fmt.Errorf("seconds must be a number")
+		}
+		return t.sleep(time.Duration(seconds * float64(time.Second))), nil
+
+	case "keyboard_shortcuts":
+		keys, _ := args["keys"].(string)
+		modifiers, key, err := parseExportKeys(keys)
+		if err != nil {
+			return nil, err
+		}
+		el := ""
+		if args["selector"] != nil {
+			if el, _, err = e.element(args["selector"]); err != nil {
+				return nil, err
+			}
+		}
+		repeat := 1
+		if n, ok := args["repeat"].(float64); ok && n > 1 {
+			repeat = int(n)
+		}
+		return t.press(el, modifiers, key, repeat)
+
+	case "get_element_text", "get_element_attribute":
+		el, _, err := e.element(args["selector"])
+		if err != nil {
+			return nil, err
+		}
+		e.results[step.ID] = true
+		if step.Tool == "get_element_text" {
+			return t.storeText(step.ID, el), nil
+		}
+		name, err := e.text(args["attribute"])
+		if err != nil {
+			return nil, fmt.Errorf("attribute: %w", err)
+		}
+		return t.storeAttribute(step.ID, el, name), nil
+
+	case "take_screenshot":
+		filename := interface{}(step.ID + ".png")
+		if args["filename"] != nil {
+			filename = args["filename"]
+		}
+		path, err := e.text(filename)
+		if err != nil {
+			return nil, fmt.Errorf("filename: %w", err)
+		}
+		return t.screenshot(path), nil
+
+	case "execute_script":
+		script, _ := args["script"].(string)
+		if strings.Contains(script, "{{") {
+			return nil, fmt.Errorf("scripts with placeholders are not exported")
+		}
+		return t.evaluate(exportScriptFunction(script)), nil
+	}
+	return nil, fmt.Errorf("%s has no equivalent to export", step.Tool)
+}
+
+// formFill translates form_fill. Keys are selectors, name or id attributes,
+// or a label, aria-label or placeholder, as form_fill resolves them.
+func (e *workflowExporter) formFill(args map[string]interface{}) ([]string, error) {
+	t := e.target
+	fields, _ := args["fields"].(map[string]interface{})
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fields are required")
+	}
+	formSelector, _ := args["form_selector"].(string)
+	if formSelector == "" {
+		formSelector = "form"
+	}
+	if strings.Contains(formSelector, "{{") {
+		return nil, fmt.Errorf("form_selector with placeholders is not exported")
+	}
+	form, err := browser.ParseSelector(formSelector)
+	if err != nil {
+		return nil, err
+	}
+	formEl, _, err := e.element(formSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var lines []string
+	for _, key := range keys {
+		var el string
+		switch {
+		case formFieldName.MatchString(key) && !formFieldTags[key]:
+			query := fmt.Sprintf("[name=%q], [id=%q]", key, key)
+			if form.Kind == browser.SelectorCSS {
+				el = t.child(formEl, t.quote(query), false)
+			} else {
+				el = t.element(t.quote(query), false)
+			}
+		case strings.Contains(key, " ") && !strings.ContainsAny(key, "#.[]>:=*'\"/()"):
+			el = t.element(t.quote(labelXPath(key)), true)
+		default:
+			sel, err := browser.ParseSelector(key)
+			if err != nil {
+				return nil, err
+			}
+			query, xpath := e.query(sel)
+			if sel.Kind == browser.SelectorCSS && form.Kind == browser.SelectorCSS && !strings.Contains(sel.Query, ",") {
+				el = t.child(formEl, query, false)
+			} else {
+				el = t.element(query, xpath)
+			}
+		}
+
+		if checked, ok := fields[key].(bool); ok {
+			lines = append(lines, t.check(el, checked)...)
+			continue
+		}
+		value, err := e.text(fields[key])
+		if err != nil {
+			return nil, fmt.Errorf("fields.%s: %w", key, err)
+		}
+		lines = append(lines, t.fill(el, value)...)
+	}
+	if submit, _ := args["submit"].(bool); submit {
+		lines = append(lines, t.submit(formEl)...)
+	}
+	return lines, nil
+}
+
+// labelXPath finds the field a label names, or the field whose aria-label
+// or placeholder is text
+func labelXPath(text string) string {
+	literal := browser.XPathLiteral(text)
+	return fmt.Sprintf("//*[@id=//label[normalize-space(.)=%s]/@for] | //label[normalize-space(.)=%s]//*[self::input or self::select or self::textarea] | //*[@aria-label=%s or @placeholder=%s]",
+		literal, literal, literal, literal)
+}
+
+// element renders a selector argument as an element expression
+func (e *workflowExporter) element(arg interface{}) (string, bool, error) {
+	query, sel, err := e.selector(arg)
+	if err != nil {
+		return "", false, err
+	}
+	return e.target.element(query, sel.IsXPath()), sel.IsXPath(), nil
+}
+
+// selector renders a selector argument as a query expression. Selectors
+// with placeholders are passed through as CSS.
+func (e *workflowExporter) selector(arg interface{}) (string, browser.Selector, error) {
+	raw, ok := arg.(string)
+	if !ok || raw == "" {
+		return "", browser.Selector{}, fmt.Errorf("selector is required")
+	}
+	if strings.Contains(raw, "{{") {
+		query, err := e.text(raw)
+		return query, browser.Selector{Raw: raw, Kind: browser.SelectorCSS}, err
+	}
+	sel, err := browser.ParseSelector(raw)
+	if err != nil {
+		return "", sel, err
+	}
+	query, _ := e.query(sel)
+	return query, sel, nil
+}
+
+// query renders a parsed selector for the target. Playwright understands
+// text= itself and is told which selectors are XPath; rod gets the
+// compiled XPath.
+func (e *workflowExporter) query(sel browser.Selector) (string, bool) {
+	if _, ok := e.target.(*playwrightTarget); ok {
+		switch sel.Kind {
+		case browser.SelectorText:
+			return e.target.quote(strings.TrimSpace(sel.Raw)), false
+		case browser.SelectorXPath:
+			return e.target.quote("xpath=" + sel.Query), false
+		}
+	}
+	return e.target.quote(sel.Query), sel.IsXPath()
+}
+
+// text renders an argument as a string expression, translating its
+// placeholders
+func (e *workflowExporter) text(arg interface{}) (string, error) {
+	switch v := arg.(type) {
+	case nil:
+		return "", fmt.Errorf("value is required")
+	case float64, bool:
+		return e.target.quote(formatTemplateValue(v)), nil
+	case string:
+		parts, err := splitTemplate(v)
+		if err != nil {
+			return "", err
+		}
+		if len(parts) == 0 {
+			return e.target.quote(""), nil
+		}
+		rendered := make([]string, 0, len(parts))
+		for _, part := range parts {
+			if !part.expr {
+				rendered = append(rendered, e.target.quote(part.text))
+				continue
+			}
+			expr, err := e.expr(part.text)
+			if err != nil {
+				return "", err
+			}
+			rendered = append(rendered, expr)
+		}
+		return strings.Join(rendered, " + "), nil
+	}
+	return "", fmt.Errorf("only text, numbers and true/false can be exported")
+}
+
+// expr translates the inside of a placeholder: ?? alternatives of +
+// concatenations
+func (e *workflowExporter) expr(expr string) (string, error) {
+	alts := splitTopLevel(expr, "??")
+	rendered := make([]string, 0, len(alts))
+	for _, alt := range alts {
+		terms := splitTopLevel(alt, "+")
+		parts := make([]string, 0, len(terms))
+		for _, term := range terms {
+			part, err := e.term(strings.TrimSpace(term))
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		joined := strings.Join(parts, " + ")
+		if len(parts) > 1 {
+			joined = "(" + joined + ")"
+		}
+		rendered = append(rendered, joined)
+	}
+	if len(rendered) == 1 {
+		return rendered[0], nil
+	}
+	return e.target.firstSet(rendered), nil
+}
+
+// term translates a literal or a reference
+func (e *workflowExporter) term(term string) (string, error) {
+	if term == "" {
+		return "", fmt.Errorf("empty expression")
+	}
+	if quote := term[0]; quote == '\'' || quote == '"' {
+		if len(term) < 2 || term[len(term)-1] != quote {
+			return "", fmt.Errorf("unterminated string %s", term)
+		}
+		return e.target.quote(strings.ReplaceAll(term[1:len(term)-1], `\`+string(quote), string(quote))), nil
+	}
+	if _, err := strconv.ParseFloat(term, 64); err == nil || term == "true" || term == "false" {
+		return e.target.quote(term), nil
+	}
+	if term == "null" {
+		return e.target.quote(""), nil
+	}
+
+	path := strings.Split(term, ".")
+	switch {
+	case path[0] == "env" && len(path) == 2 && strings.HasPrefix(path[1], workflowEnvPrefix):
+		return e.target.env(path[1]), nil
+	case path[0] == "steps" && len(path) == 3 && path[2] == "text" && e.results[path[1]]:
+		return e.target.result(path[1]), nil
+	case len(path) == 1:
+		if _, ok := e.w.Vars[term]; ok {
+			return e.target.variable(term), nil
+		}
+	}
+	return "", fmt.Errorf("%s cannot be exported; only variables, env.%s* and steps.ID.text of text and attribute steps can", term, workflowEnvPrefix)
+}
+
+// exportScriptFunction makes an execute_script script a function, as
+// execute_script runs it: functions stay as they are, scripts that return
+// become the body, and anything else is the returned expression
+func exportScriptFunction(script string) string {
+	script = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(script), ";"))
+	if strings.HasPrefix(script, "function") || strings.HasPrefix(script, "async") ||
+		(strings.HasPrefix(script, "(") && strings.Contains(strings.SplitN(script, "\n", 2)[0], "=>")) {
+		return script
+	}
+	if strings.Contains(script, "return") || strings.Contains(script, ";") {
+		return "() => {\n" + script + ";\n}"
+	}
+	return "() => (" + script + ")"
+}
+
+// parseExportKeys splits a keyboard_shortcuts combination such as Ctrl+A
+// into modifiers (Control, Alt, Shift, Meta) and a key: a lowercase
+// letter, a digit or a named key
+func parseExportKeys(keys string) ([]string, string, error) {
+	parts := strings.Split(strings.TrimSpace(keys), "+")
+	var modifiers []string
+	for _, part := range parts[:len(parts)-1] {
+		switch strings.ToLower(strings.TrimSpace(part)) {
+		case "ctrl", "control":
+			modifiers = append(modifiers, "Control")
+		case "alt", "option":
+			modifiers = append(modifiers, "Alt")
+		case "shift":
+			modifiers = append(modifiers, "Shift")
+		case "cmd", "command", "meta":
+			modifiers = append(modifiers, "Meta")
+		default:
+			return nil, "", fmt.Errorf("unknown modifier %q in %q", part, keys)
+		}
+	}
+
+	key := strings.TrimSpace(parts[len(parts)-1])
+	aliases := map[string]string{
+		"esc": "Escape", "return": "Enter", "del": "Delete", "space": "Space",
+		"left": "ArrowLeft", "right": "ArrowRight", "up": "ArrowUp", "down": "ArrowDown",
+	}
+	if alias, ok := aliases[strings.ToLower(key)]; ok {
+		key = alias
+	}
+	switch {
+	case len(key) == 1 && (key[0] >= '0' && key[0] <= '9' || strings.ContainsAny(strings.ToLower(key), "abcdefghijklmnopqrstuvwxyz")):
+		return modifiers, strings.ToLower(key), nil
+	case exportNamedKeys[key]:
+		return modifiers, key, nil
+	}
+	return nil, "", fmt.Errorf("key %q has no equivalent to export", keys)
+}
+
+// exportNamedKeys are the keys both Playwright and rod know by name
+var exportNamedKeys = map[string]bool{
+	"Enter": true, "Tab": true, "Escape": true, "Backspace": true, "Delete": true, "Insert": true, "Space": true,
+	"ArrowUp": true, "ArrowDown": true, "ArrowLeft": true, "ArrowRight": true,
+	"PageUp": true, "PageDown": true, "Home": true, "End": true,
+	"F1": true, "F2": true, "F3": true, "F4": true, "F5": true, "F6": true,
+	"F7": true, "F8": true, "F9": true, "F10": true, "F11": true, "F12": true,
+}
+
+// ExportWorkflowTool renders a saved workflow as a Playwright test or a
+// go-rod program
+type ExportWorkflowTool struct {
+	logger *logger.Logger
+	store  *workflowStore
+}
+
+// NewExportWorkflowTool creates an export_workflow tool reading workflows
+// from dir (default: workflows under the working directory)
+func NewExportWorkflowTool(log *logger.Logger, dir string) *ExportWorkflowTool {
+	return &ExportWorkflowTool{logger: log, store: newWorkflowStore(dir)}
+}
+
+func (t *ExportWorkflowTool) Name() string {
+	return "export_workflow"
+}
+
+func (t *ExportWorkflowTool) Description() string {
+	return "Convert a saved workflow into standalone code: a Playwright TypeScript test or a go-rod Go program, for moving stable flows into your own test suite"
+}
+
+func (t *ExportWorkflowTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name of the saved workflow",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "playwright for a @playwright/test spec, rod-go for a Go program using go-rod",
+				"enum":        ExportFormats,
+				"default":     ExportPlaywright,
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
+func (t *ExportWorkflowTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		name, _ := args["name"].(string)
+		format, _ := args["format"].(string)
+		if format == "" {
+			format = ExportPlaywright
+		}
+
+		w, err := t.store.load(name)
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(err.Error()), nil
+		}
+		code, notes, err := exportWorkflow(w, format)
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(err.Error()), nil
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		summary := fmt.Sprintf("Exported workflow %s as %s", name, format)
+		if len(notes) > 0 {
+			summary += fmt.Sprintf(". %d steps need finishing by hand (TODO comments):\n- %s", len(notes), strings.Join(notes, "\n- "))
+		}
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: summary + "\n\n" + code,
+				Data: map[string]interface{}{
+					"name":   name,
+					"format": format,
+					"code":   code,
+					"notes":  notes,
+				},
+			}},
+		}, nil
+	})
+}
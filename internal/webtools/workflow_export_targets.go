@@ -0,0 +1,569 @@
+package webtools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"strconv"
+	"strings"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+// playwrightTarget writes a @playwright/test spec. Locators take the first
+// match, as rodmcp's element tools do, rather than failing on several.
+type playwrightTarget struct {
+	usesFill, usedFill bool
+}
+
+func (t *playwrightTarget) begin()   { t.usedFill = t.usesFill }
+func (t *playwrightTarget) discard() { t.usesFill = t.usedFill }
+
+func (t *playwrightTarget) quote(s string) string {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func (t *playwrightTarget) variable(name string) string { return "vars." + name }
+func (t *playwrightTarget) env(name string) string      { return "process.env." + name }
+func (t *playwrightTarget) result(id string) string     { return "results." + id }
+
+func (t *playwrightTarget) firstSet(alternatives []string) string {
+	return "(" + strings.Join(alternatives, " ?? ") + ")"
+}
+
+func (t *playwrightTarget) element(query string, xpath bool) string {
+	return "page.locator(" + query + ").first()"
+}
+
+func (t *playwrightTarget) child(parent, query string, xpath bool) string {
+	return parent + ".locator(" + query + ").first()"
+}
+
+func (t *playwrightTarget) navigate(url string) []string {
+	return []string{"await page.goto(" + url + ");"}
+}
+
+func (t *playwrightTarget) click(el string) []string {
+	return []string{"await " + el + ".click();"}
+}
+
+func (t *playwrightTarget) hover(el string) []string {
+	return []string{"await " + el + ".hover();"}
+}
+
+func (t *playwrightTarget) typeText(el, text string, clear bool) []string {
+	if clear {
+		return []string{"await " + el + ".fill(" + text + ");"}
+	}
+	return []string{"await " + el + ".pressSequentially(" + text + ");"}
+}
+
+func (t *playwrightTarget) fill(el, value string) []string {
+	t.usesFill = true
+	return []string{"await fill(" + el + ", " + value + ");"}
+}
+
+func (t *playwrightTarget) check(el string, checked bool) []string {
+	return []string{fmt.Sprintf("await %s.setChecked(%t);", el, checked)}
+}
+
+func (t *playwrightTarget) submit(form string) []string {
+	return []string{"await " + form + ".evaluate((form) => (form as HTMLFormElement).requestSubmit());"}
+}
+
+func (t *playwrightTarget) waitFor(query string, sel browser.Selector, state string, timeout time.Duration) ([]string, error) {
+	if !exportWaitState(state) {
+		return nil, fmt.Errorf("unknown state %q", state)
+	}
+	return []string{fmt.Sprintf("await page.locator(%s).first().waitFor({ state: '%s', timeout: %d });", query, state, timeout.Milliseconds())}, nil
+}
+
+func (t *playwrightTarget) sleep(d time.Duration) []string {
+	return []string{fmt.Sprintf("await page.waitForTimeout(%d);", d.Milliseconds())}
+}
+
+func (t *playwrightTarget) press(el string, modifiers []string, key string, repeat int) ([]string, error) {
+	target := "page.keyboard"
+	if el != "" {
+		target = el
+	}
+	press := fmt.Sprintf("await %s.press(%s);", target, t.quote(strings.Join(append(modifiers, key), "+")))
+	if repeat <= 1 {
+		return []string{press}, nil
+	}
+	return []string{fmt.Sprintf("for (let i = 0; i < %d; i++) {", repeat), "  " + press, "}"}, nil
+}
+
+func (t *playwrightTarget) storeText(id, el string) []string {
+	return []string{t.result(id) + " = await " + el + ".innerText();"}
+}
+
+func (t *playwrightTarget) storeAttribute(id, el, name string) []string {
+	return []string{t.result(id) + " = (await " + el + ".getAttribute(" + name + ")) ?? '';"}
+}
+
+func (t *playwrightTarget) screenshot(path string) []string {
+	return []string{"await page.screenshot({ path: " + path + " });"}
+}
+
+func (t *playwrightTarget) evaluate(fn string) []string {
+	return strings.Split("await page.evaluate("+fn+");", "\n")
+}
+
+func (t *playwrightTarget) program(w *workflow, vars []exportVar, body []string, results bool) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Playwright test generated by rodmcp export_workflow from the %q workflow.\n", w.Name)
+	writeExportHeader(&b, w, vars)
+	b.WriteString("\n")
+	if t.usesFill {
+		b.WriteString("import { test, type Locator } from '@playwright/test';\n")
+	} else {
+		b.WriteString("import { test } from '@playwright/test';\n")
+	}
+
+	required := false
+	if len(vars) > 0 {
+		b.WriteString("\nconst vars = {\n")
+		for _, v := range vars {
+			if v.Required {
+				required = true
+				fmt.Fprintf(&b, "  %s: required(%s),\n", v.Name, t.quote(v.Env))
+			} else {
+				fmt.Fprintf(&b, "  %s: process.env.%s ?? %s,\n", v.Name, v.Env, t.quote(v.Default))
+			}
+		}
+		b.WriteString("};\n")
+	}
+
+	fmt.Fprintf(&b, "\ntest(%s, async ({ page }) => {\n", t.quote(w.Name))
+	if results {
+		b.WriteString("  const results: Record<string, string> = {};\n")
+	}
+	for _, line := range body {
+		b.WriteString("  " + line + "\n")
+	}
+	if results {
+		b.WriteString("  console.log(JSON.stringify(results, null, 2));\n")
+	}
+	b.WriteString("});\n")
+
+	if required {
+		b.WriteString(`
+function required(name: string): string {
+  const value = process.env[name];
+  if (value === undefined) {
+    throw new Error(name + ' must be set');
+  }
+  return value;
+}
+`)
+	}
+	if t.usesFill {
+		b.WriteString(`
+// fill sets a text field or chooses a select option, as form_fill does
+async function fill(field: Locator, value: string) {
+  if (await field.evaluate((el) => el.tagName === 'SELECT')) {
+    await field.selectOption(value);
+  } else {
+    await field.fill(value);
+  }
+}
+`)
+	}
+	return b.String(), nil
+}
+
+// rodTarget writes a Go program using go-rod. Helpers and imports are
+// only written when a step needs them, so the program compiles as it is.
+type rodTarget struct {
+	uses map[string]bool
+	// used is uses before the current step
+	used map[string]bool
+}
+
+func (t *rodTarget) begin() {
+	t.used = make(map[string]bool, len(t.uses))
+	for name := range t.uses {
+		t.used[name] = true
+	}
+}
+
+func (t *rodTarget) discard() {
+	t.uses = t.used
+}
+
+func (t *rodTarget) use(names ...string) {
+	if t.uses == nil {
+		t.uses = make(map[string]bool)
+	}
+	for _, name := range names {
+		t.uses[name] = true
+	}
+}
+
+func (t *rodTarget) quote(s string) string {
+	return strconv.Quote(s)
+}
+
+func (t *rodTarget) variable(name string) string {
+	t.use("vars")
+	return "vars[" + strconv.Quote(name) + "]"
+}
+
+func (t *rodTarget) env(name string) string {
+	t.use("os")
+	return "os.Getenv(" + strconv.Quote(name) + ")"
+}
+
+func (t *rodTarget) result(id string) string {
+	return "results[" + strconv.Quote(id) + "]"
+}
+
+func (t *rodTarget) firstSet(alternatives []string) string {
+	t.use("firstSet")
+	return "firstSet(" + strings.Join(alternatives, ", ") + ")"
+}
+
+func (t *rodTarget) element(query string, xpath bool) string {
+	t.use("element")
+	return fmt.Sprintf("element(page, %s, %t)", query, xpath)
+}
+
+func (t *rodTarget) child(parent, query string, xpath bool) string {
+	if xpath {
+		return parent + ".MustElementX(" + query + ")"
+	}
+	return parent + ".MustElement(" + query + ")"
+}
+
+func (t *rodTarget) navigate(url string) []string {
+	return []string{"page.MustNavigate(" + url + ").MustWaitLoad()"}
+}
+
+func (t *rodTarget) click(el string) []string {
+	return []string{el + ".MustClick()"}
+}
+
+func (t *rodTarget) hover(el string) []string {
+	return []string{el + ".MustHover()"}
+}
+
+func (t *rodTarget) typeText(el, text string, clear bool) []string {
+	if clear {
+		return []string{el + ".MustSelectAllText().MustInput(" + text + ")"}
+	}
+	return []string{el + ".MustInput(" + text + ")"}
+}
+
+func (t *rodTarget) fill(el, value string) []string {
+	t.use("fill")
+	return []string{"fill(" + el + ", " + value + ")"}
+}
+
+func (t *rodTarget) check(el string, checked bool) []string {
+	t.use("check")
+	return []string{fmt.Sprintf("check(%s, %t)", el, checked)}
+}
+
+func (t *rodTarget) submit(form string) []string {
+	return []string{form + ".MustEval(`() => this.requestSubmit()`)"}
+}
+
+// waitFor polls in the page, so it can wait for elements to go as well as
+// come. Selectors with placeholders can only be waited for to appear.
+func (t *rodTarget) waitFor(query string, sel browser.Selector, state string, timeout time.Duration) ([]string, error) {
+	if !exportWaitState(state) {
+		return nil, fmt.Errorf("unknown state %q", state)
+	}
+	if sel.Query == "" {
+		switch state {
+		case "attached":
+			return []string{t.element(query, false)}, nil
+		case "visible":
+			return []string{t.element(query, false) + ".MustWaitVisible()"}, nil
+		}
+		return nil, fmt.Errorf("waiting for a selector with placeholders to be %s is not exported", state)
+	}
+
+	var js string
+	switch state {
+	case "attached":
+		js = fmt.Sprintf("() => !!%s", sel.JSFirst())
+	case "visible":
+		js = fmt.Sprintf("() => { const el = %s; return !!el && el.checkVisibility(); }", sel.JSFirst())
+	case "hidden":
+		js = fmt.Sprintf("() => { const el = %s; return !el || !el.checkVisibility(); }", sel.JSFirst())
+	case "detached":
+		js = fmt.Sprintf("() => !%s", sel.JSFirst())
+	}
+	t.use("time")
+	return []string{fmt.Sprintf("page.Timeout(%s).MustWait(%s)", goDuration(timeout), goScript(js))}, nil
+}
+
+func (t *rodTarget) sleep(d time.Duration) []string {
+	t.use("time")
+	return []string{"time.Sleep(" + goDuration(d) + ")"}
+}
+
+func (t *rodTarget) press(el string, modifiers []string, key string, repeat int) ([]string, error) {
+	t.use("input")
+	actions := "page.KeyActions()"
+	if len(modifiers) > 0 {
+		pressed := make([]string, len(modifiers))
+		for i, modifier := range modifiers {
+			pressed[i] = "input." + modifier + "Left"
+		}
+		actions += ".Press(" + strings.Join(pressed, ", ") + ")"
+	}
+	switch {
+	case key >= "0" && key <= "9":
+		key = "Digit" + key
+	case len(key) == 1:
+		key = "Key" + strings.ToUpper(key)
+	}
+	actions += ".Type(input." + key + ").MustDo()"
+
+	var lines []string
+	if el != "" {
+		lines = append(lines, el+".MustFocus()")
+	}
+	if repeat <= 1 {
+		return append(lines, actions), nil
+	}
+	return append(lines, fmt.Sprintf("for i := 0; i < %d; i++ {", repeat), "\t"+actions, "}"), nil
+}
+
+func (t *rodTarget) storeText(id, el string) []string {
+	t.use("results")
+	return []string{t.result(id) + " = " + el + ".MustText()"}
+}
+
+func (t *rodTarget) storeAttribute(id, el, name string) []string {
+	t.use("results", "attribute")
+	return []string{t.result(id) + " = attribute(" + el + ", " + name + ")"}
+}
+
+func (t *rodTarget) screenshot(path string) []string {
+	return []string{"page.MustScreenshot(" + path + ")"}
+}
+
+func (t *rodTarget) evaluate(fn string) []string {
+	return []string{"page.MustEval(" + goScript(fn) + ")"}
+}
+
+// rodHelpers are the helper functions a program can need, in the order
+// they are written
+var rodHelpers = []struct {
+	name   string
+	source string
+}{
+	{"element", `
+// element waits up to stepTimeout for the first element matching a CSS
+// selector, or an XPath expression when xpath is set
+func element(page *rod.Page, selector string, xpath bool) *rod.Element {
+	page = page.Timeout(stepTimeout)
+	if xpath {
+		return page.MustElementX(selector).CancelTimeout()
+	}
+	return page.MustElement(selector).CancelTimeout()
+}
+`},
+	{"fill", `
+// fill sets a text field or chooses a select option, as form_fill does
+func fill(el *rod.Element, value string) {
+	if el.MustEval("() => this.tagName").Str() == "SELECT" {
+		el.MustEval(` + "`" + `(value) => {
+			this.value = value;
+			this.dispatchEvent(new Event("input", { bubbles: true }));
+			this.dispatchEvent(new Event("change", { bubbles: true }));
+		}` + "`" + `, value)
+		return
+	}
+	el.MustSelectAllText().MustInput(value)
+}
+`},
+	{"check", `
+// check ticks or clears a checkbox or radio button
+func check(el *rod.Element, checked bool) {
+	if el.MustProperty("checked").Bool() != checked {
+		el.MustClick()
+	}
+}
+`},
+	{"attribute", `
+// attribute returns an element's attribute, or "" when it has none
+func attribute(el *rod.Element, name string) string {
+	if value := el.MustAttribute(name); value != nil {
+		return *value
+	}
+	return ""
+}
+`},
+	{"firstSet", `
+// firstSet returns the first value that is not empty
+func firstSet(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+`},
+	{"envOr", `
+// envOr returns the environment variable name, or fallback when it is unset
+func envOr(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
+`},
+	{"mustEnv", `
+// mustEnv returns the environment variable name, exiting when it is unset
+func mustEnv(name string) string {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s must be set\n", name)
+		os.Exit(2)
+	}
+	return value
+}
+`},
+}
+
+func (t *rodTarget) program(w *workflow, vars []exportVar, body []string, results bool) (string, error) {
+	var code strings.Builder
+	if t.uses["vars"] {
+		code.WriteString("\tvars := map[string]string{\n")
+		for _, v := range vars {
+			if v.Required {
+				t.use("mustEnv", "os", "fmt")
+				fmt.Fprintf(&code, "\t\t%q: mustEnv(%q),\n", v.Name, v.Env)
+			} else {
+				t.use("envOr", "os")
+				fmt.Fprintf(&code, "\t\t%q: envOr(%q, %s),\n", v.Name, v.Env, strconv.Quote(v.Default))
+			}
+		}
+		code.WriteString("\t}\n")
+	}
+	if results {
+		t.use("results")
+		code.WriteString("\tresults := map[string]string{}\n")
+	}
+	if code.Len() > 0 {
+		code.WriteString("\n")
+	}
+	code.WriteString("\tbrowser := rod.New().MustConnect()\n\tdefer browser.MustClose()\n\tpage := browser.MustPage()\n\n")
+
+	usesPage := false
+	for _, line := range body {
+		if !strings.HasPrefix(line, "//") {
+			usesPage = true
+		}
+		code.WriteString("\t" + line + "\n")
+	}
+	if !usesPage {
+		code.WriteString("\t_ = page\n")
+	}
+	if results {
+		t.use("json", "fmt")
+		code.WriteString("\n\tout, _ := json.MarshalIndent(results, \"\", \"  \")\n\tfmt.Println(string(out))\n")
+	}
+	if t.uses["element"] {
+		t.use("time")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "// Command %s was generated by rodmcp export_workflow from the %q workflow.\n", goIdentifier(w.Name), w.Name)
+	writeExportHeader(&b, w, vars)
+	b.WriteString("package main\n\nimport (\n")
+	for _, imp := range []struct{ use, path string }{
+		{"json", "encoding/json"}, {"fmt", "fmt"}, {"os", "os"}, {"time", "time"},
+	} {
+		if t.uses[imp.use] {
+			fmt.Fprintf(&b, "\t%q\n", imp.path)
+		}
+	}
+	b.WriteString("\n\t\"github.com/go-rod/rod\"\n")
+	if t.uses["input"] {
+		b.WriteString("\t\"github.com/go-rod/rod/lib/input\"\n")
+	}
+	b.WriteString(")\n")
+	if t.uses["element"] {
+		b.WriteString("\n// stepTimeout bounds how long a step waits for its element\nconst stepTimeout = 30 * time.Second\n")
+	}
+	b.WriteString("\nfunc main() {\n" + code.String() + "}\n")
+	for _, helper := range rodHelpers {
+		if t.uses[helper.name] {
+			b.WriteString(helper.source)
+		}
+	}
+
+	source, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return "", fmt.Errorf("generated Go does not parse: %w", err)
+	}
+	return string(source), nil
+}
+
+// writeExportHeader writes the rest of a generated file's leading comment:
+// the workflow's description and the environment variables it reads
+func writeExportHeader(b *strings.Builder, w *workflow, vars []exportVar) {
+	if w.Description != "" {
+		b.WriteString("//\n// " + strings.ReplaceAll(w.Description, "\n", "\n// ") + "\n")
+	}
+	if len(vars) > 0 {
+		b.WriteString("//\n// Variables come from the environment:\n//\n")
+		for _, v := range vars {
+			if v.Required {
+				fmt.Fprintf(b, "//\t%s (required)\n", v.Env)
+			} else {
+				fmt.Fprintf(b, "//\t%s (default %q)\n", v.Env, v.Default)
+			}
+		}
+	}
+}
+
+// exportWaitState reports whether state is one wait_for_element accepts
+func exportWaitState(state string) bool {
+	for _, known := range waitStates {
+		if state == known {
+			return true
+		}
+	}
+	return false
+}
+
+// goDuration renders d as a Go duration expression
+func goDuration(d time.Duration) string {
+	if d%time.Second == 0 {
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	}
+	return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+}
+
+// goScript quotes JavaScript as a Go string, raw when it can be
+func goScript(js string) string {
+	if !strings.Contains(js, "`") {
+		return "`" + js + "`"
+	}
+	return strconv.Quote(js)
+}
+
+// goIdentifier makes a workflow name usable in a Go doc comment's first
+// word
+func goIdentifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' {
+			return '_'
+		}
+		return r
+	}, name)
+}
@@ -0,0 +1,109 @@
+package webtools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// exportTestWorkflow exercises most of what export translates, plus a loop
+// it cannot
+const exportTestWorkflow = `{
+	"name": "checkout",
+	"description": "Log in and read the order total",
+	"vars": {"base_url": {"default": "https://shop.example"}, "password": {}},
+	"steps": [
+		{"id": "open", "tool": "navigate_page", "args": {"url": "{{base_url}}/login"}},
+		{"tool": "type_text", "args": {"page_id": "{{steps.open.data.id}}", "selector": "#user", "text": "{{env.RODMCP_USER ?? 'demo'}}"}},
+		{"tool": "type_text", "args": {"selector": "#pass", "text": "{{password}}"}},
+		{"tool": "click_element", "args": {"selector": "text=\"Sign in\""}},
+		{"tool": "wait_for_element", "args": {"selector": "//div[@id='cart']", "state": "visible", "timeout": 5}},
+		{"tool": "form_fill", "args": {"form_selector": "#shipping", "fields": {"country": "DE", "#gift": true}, "submit": true}},
+		{"id": "total", "tool": "get_element_text", "args": {"selector": ".total"}},
+		{"tool": "keyboard_shortcuts", "args": {"keys": "Ctrl+A", "selector": "#note"}},
+		{"tool": "take_screenshot", "args": {"filename": "total-{{steps.total.text}}.png"}},
+		{"id": "each", "for_each": ["a", "b"], "steps": [{"tool": "wait", "args": {"seconds": 1}}]}
+	]
+}`
+
+func TestExportWorkflow(t *testing.T) {
+	var w workflow
+	if err := json.Unmarshal([]byte(exportTestWorkflow), &w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{ExportPlaywright, []string{
+			`import { test, type Locator } from '@playwright/test';`,
+			`base_url: process.env.BASE_URL ?? "https://shop.example",`,
+			`password: required("PASSWORD"),`,
+			`await page.goto(vars.base_url + "/login");`,
+			`await page.locator("#user").first().fill((process.env.RODMCP_USER ?? "demo"));`,
+			`await page.locator("text=\"Sign in\"").first().click();`,
+			`await page.locator("xpath=//div[@id='cart']").first().waitFor({ state: 'visible', timeout: 5000 });`,
+			`await fill(page.locator("#shipping").first().locator("[name=\"country\"], [id=\"country\"]").first(), "DE");`,
+			`await page.locator("#shipping").first().locator("#gift").first().setChecked(true);`,
+			`requestSubmit()`,
+			`results.total = await page.locator(".total").first().innerText();`,
+			`await page.locator("#note").first().press("Control+a");`,
+			`await page.screenshot({ path: "total-" + results.total + ".png" });`,
+			`// TODO step each: for_each loops are not exported`,
+		}},
+		{ExportRodGo, []string{
+			`"github.com/go-rod/rod/lib/input"`,
+			`"base_url": envOr("BASE_URL", "https://shop.example"),`,
+			`"password": mustEnv("PASSWORD"),`,
+			`page.MustNavigate(vars["base_url"] + "/login").MustWaitLoad()`,
+			`element(page, "#user", false).MustSelectAllText().MustInput(firstSet(os.Getenv("RODMCP_USER"), "demo"))`,
+			`element(page, "//body//*[normalize-space(.)='Sign in'][not(.//*[normalize-space(.)='Sign in'])]", true).MustClick()`,
+			`page.Timeout(5 * time.Second).MustWait(`,
+			`fill(element(page, "#shipping", false).MustElement("[name=\"country\"], [id=\"country\"]"), "DE")`,
+			`check(element(page, "#shipping", false).MustElement("#gift"), true)`,
+			`results["total"] = element(page, ".total", false).MustText()`,
+			`page.KeyActions().Press(input.ControlLeft).Type(input.KeyA).MustDo()`,
+			`page.MustScreenshot("total-" + results["total"] + ".png")`,
+			`// TODO step each: for_each loops are not exported`,
+		}},
+	}
+	for _, tt := range tests {
+		code, notes, err := exportWorkflow(&w, tt.format)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.format, err)
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(code, want) {
+				t.Errorf("%s export is missing %s\n%s", tt.format, want, code)
+			}
+		}
+		if strings.Contains(code, "page_id") {
+			t.Errorf("%s export kept page_id:\n%s", tt.format, code)
+		}
+		if len(notes) != 1 || !strings.Contains(notes[0], "step each") {
+			t.Errorf("%s notes = %v", tt.format, notes)
+		}
+	}
+
+	if _, _, err := exportWorkflow(&w, "cypress"); err == nil {
+		t.Error("unknown format was accepted")
+	}
+}
+
+func TestExportWorkflowUnknownReference(t *testing.T) {
+	w := &workflow{Name: "search", Steps: []workflowStep{
+		{ID: "open", Tool: "navigate_page", Args: map[string]interface{}{"url": "https://example.com"}},
+		{ID: "find", Tool: "type_text", Args: map[string]interface{}{"selector": "#q", "text": "{{steps.open.data.title}}"}},
+	}}
+	code, notes, err := exportWorkflow(w, ExportRodGo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(notes) != 1 || strings.Contains(code, "func element") || !strings.Contains(code, "// TODO step find: text: steps.open.data.title cannot be exported") {
+		t.Errorf("notes = %v\n%s", notes, code)
+	}
+}
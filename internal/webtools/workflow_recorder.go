@@ -0,0 +1,285 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"rodmcp/internal/browser"
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+// causedNavigationWindow is how soon after a click or Enter a navigation
+// counts as caused by it, so replaying the click is enough
+const causedNavigationWindow = 3 * time.Second
+
+// workflowFromRecording turns recorded actions into workflow steps. The
+// first page becomes the start_url variable, navigation caused by a click
+// or Enter is left to the click, and the first action on each new page
+// waits for its element. Text typed into password fields becomes a
+// password variable instead of being written to disk.
+func workflowFromRecording(name string, actions []browser.RecordedAction) *workflow {
+	w := &workflow{Name: name, Vars: map[string]workflowVar{}}
+	pageRef := ""
+	needWait := false
+	passwords := 0
+	var lastTrigger time.Time
+
+	for i, action := range actions {
+		switch action.Type {
+		case browser.RecordedNavigate:
+			if action.URL == "" || action.URL == "about:blank" {
+				continue
+			}
+			if !lastTrigger.IsZero() && action.Time.Sub(lastTrigger) <= causedNavigationWindow {
+				needWait = true
+				continue
+			}
+			id := "open"
+			url := interface{}(action.URL)
+			if pageRef == "" {
+				w.Vars["start_url"] = workflowVar{Description: "Page the recording started on", Default: action.URL}
+				url = "{{start_url}}"
+			} else {
+				id = fmt.Sprintf("open_%d", i+1)
+			}
+			w.Steps = append(w.Steps, workflowStep{ID: id, Tool: "navigate_page", Args: map[string]interface{}{"url": url}})
+			pageRef = "{{steps." + id + ".data.id}}"
+			needWait = false
+			continue
+
+		case browser.RecordedInput:
+			// Only the final text of a field matters
+			if i+1 < len(actions) && actions[i+1].Type == browser.RecordedInput && actions[i+1].Selector == action.Selector {
+				continue
+			}
+		}
+		if action.Selector == "" {
+			continue
+		}
+
+		args := map[string]interface{}{"selector": action.Selector}
+		if pageRef != "" {
+			args["page_id"] = pageRef
+		}
+		if needWait {
+			wait := map[string]interface{}{"selector": action.Selector, "state": "visible"}
+			if pageRef != "" {
+				wait["page_id"] = pageRef
+			}
+			w.Steps = append(w.Steps, workflowStep{Tool: "wait_for_element", Args: wait})
+			needWait = false
+		}
+
+		switch action.Type {
+		case browser.RecordedClick:
+			w.Steps = append(w.Steps, workflowStep{Tool: "click_element", Args: args})
+			lastTrigger = action.Time
+		case browser.RecordedInput:
+			args["text"] = action.Value
+			if action.Sensitive {
+				passwords++
+				variable := "password"
+				if passwords > 1 {
+					variable = fmt.Sprintf("password_%d", passwords)
+				}
+				w.Vars[variable] = workflowVar{Description: "Typed into " + action.Selector + " while recording"}
+				args["text"] = "{{" + variable + "}}"
+			}
+			w.Steps = append(w.Steps, workflowStep{Tool: "type_text", Args: args})
+		case browser.RecordedSelect:
+			fill := map[string]interface{}{
+				"form_selector": "body",
+				"fields":        map[string]interface{}{action.Selector: action.Value},
+			}
+			if pageRef != "" {
+				fill["page_id"] = pageRef
+			}
+			w.Steps = append(w.Steps, workflowStep{Tool: "form_fill", Args: fill})
+		case browser.RecordedKey:
+			args["keys"] = action.Value
+			w.Steps = append(w.Steps, workflowStep{Tool: "keyboard_shortcuts", Args: args})
+			lastTrigger = action.Time
+		}
+	}
+	if len(w.Vars) == 0 {
+		w.Vars = nil
+	}
+	return w
+}
+
+// RecordWorkflowTool records what a person does in the visible browser and
+// saves it as a workflow
+type RecordWorkflowTool struct {
+	logger     *logger.Logger
+	browserMgr *browser.Manager
+	store      *workflowStore
+
+	mutex      sync.Mutex
+	recordings map[string]*browser.Recording
+}
+
+// NewRecordWorkflowTool creates a record_workflow tool saving workflows to
+// dir (default: workflows under the working directory)
+func NewRecordWorkflowTool(log *logger.Logger, mgr *browser.Manager, dir string) *RecordWorkflowTool {
+	return &RecordWorkflowTool{
+		logger:     log,
+		browserMgr: mgr,
+		store:      newWorkflowStore(dir),
+		recordings: make(map[string]*browser.Recording),
+	}
+}
+
+func (t *RecordWorkflowTool) Name() string {
+	return "record_workflow"
+}
+
+func (t *RecordWorkflowTool) Description() string {
+	return "Record clicks, typing and navigation done by hand in the visible browser and save them as a replayable workflow. Start recording, use the browser, then stop with a name"
+}
+
+func (t *RecordWorkflowTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"action": map[string]interface{}{
+				"type":        "string",
+				"description": "start begins recording a page, status shows what has been recorded, stop ends it and saves the workflow, discard ends it without saving",
+				"enum":        []string{"start", "status", "stop", "discard"},
+			},
+			"page_id": map[string]interface{}{
+				"type":        "string",
+				"description": "Page to record (default: the first open page)",
+			},
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Name to save the workflow under (required for stop)",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "What the recorded workflow does",
+			},
+		},
+		Required: []string{"action"},
+	}
+}
+
+func (t *RecordWorkflowTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		pageID, _ := args["page_id"].(string)
+		if pageID == "" {
+			pages := t.browserMgr.ListPages()
+			if len(pages) == 0 {
+				return createNoPagesErrorResponse(t.Name()), nil
+			}
+			pageID = pages[0]
+		}
+
+		action, _ := args["action"].(string)
+		t.mutex.Lock()
+		rec := t.recordings[pageID]
+		t.mutex.Unlock()
+
+		switch action {
+		case "start":
+			if !t.browserMgr.IsVisible() {
+				return fail("Recording needs a visible browser so someone can use it; call set_browser_visibility with visible=true first")
+			}
+			if rec != nil {
+				return fail(fmt.Sprintf("Page %s is already being recorded; stop or discard that recording first", pageID))
+			}
+			rec, err := t.browserMgr.StartRecording(pageID)
+			if err != nil {
+				return fail(fmt.Sprintf("Failed to start recording: %v", err))
+			}
+			t.mutex.Lock()
+			t.recordings[pageID] = rec
+			t.mutex.Unlock()
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Recording page %s. Use the browser, then call record_workflow with action=stop and a name", pageID),
+					Data: map[string]interface{}{"page_id": pageID, "recording": true},
+				}},
+			}, nil
+
+		case "status", "stop", "discard":
+			if rec == nil {
+				return fail(fmt.Sprintf("Page %s is not being recorded", pageID))
+			}
+
+		default:
+			return fail("action must be start, status, stop or discard")
+		}
+
+		if action == "status" {
+			actions := rec.Actions()
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Recording page %s for %s: %d actions so far", pageID, time.Since(rec.Started).Round(time.Second), len(actions)),
+					Data: map[string]interface{}{"page_id": pageID, "actions": actions},
+				}},
+			}, nil
+		}
+
+		name, _ := args["name"].(string)
+		if action == "stop" && !recipeNamePattern.MatchString(name) {
+			return fail("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+		}
+
+		t.mutex.Lock()
+		delete(t.recordings, pageID)
+		t.mutex.Unlock()
+		actions := rec.Stop()
+
+		if action == "discard" {
+			t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("Discarded the recording of page %s (%d actions)", pageID, len(actions)),
+				}},
+			}, nil
+		}
+
+		w := workflowFromRecording(name, actions)
+		w.Description, _ = args["description"].(string)
+		w.UpdatedAt = time.Now().UTC()
+		if len(w.Steps) == 0 {
+			return fail("Nothing was recorded, so no workflow was saved")
+		}
+		if err := w.validate(); err != nil {
+			return fail(fmt.Sprintf("Recorded workflow is invalid: %v", err))
+		}
+		path, err := t.store.save(w)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to save workflow: %v", err))
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		definition, _ := json.Marshal(w)
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Saved workflow %s with %d steps from %d recorded actions. Review it before relying on it:\n%s", name, len(w.Steps), len(actions), definition),
+				Data: map[string]interface{}{
+					"name":     name,
+					"path":     path,
+					"steps":    len(w.Steps),
+					"workflow": w,
+				},
+			}},
+		}, nil
+	})
+}
@@ -0,0 +1,72 @@
+package webtools
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"rodmcp/internal/browser"
+)
+
+func TestWorkflowFromRecording(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	after := func(seconds int) time.Time { return at.Add(time.Duration(seconds) * time.Second) }
+	actions := []browser.RecordedAction{
+		{Type: browser.RecordedNavigate, URL: "https://example.com/login", Time: after(0)},
+		{Type: browser.RecordedInput, Selector: "input[name=\"user\"]", Value: "al", Time: after(2)},
+		{Type: browser.RecordedInput, Selector: "input[name=\"user\"]", Value: "alice", Time: after(3)},
+		{Type: browser.RecordedInput, Selector: "#pass", Value: "hunter2", Sensitive: true, Time: after(4)},
+		{Type: browser.RecordedClick, Selector: "text=\"Sign in\"", Time: after(5)},
+		{Type: browser.RecordedNavigate, URL: "https://example.com/home", Time: after(6)},
+		{Type: browser.RecordedSelect, Selector: "#lang", Value: "de", Time: after(8)},
+		{Type: browser.RecordedNavigate, URL: "https://example.com/search", Time: after(20)},
+		{Type: browser.RecordedKey, Selector: "#q", Value: "Enter", Time: after(22)},
+	}
+
+	w := workflowFromRecording("login", actions)
+	if err := w.validate(); err != nil {
+		t.Fatalf("generated workflow is invalid: %v", err)
+	}
+
+	var tools []string
+	for _, step := range w.Steps {
+		tools = append(tools, step.Tool)
+	}
+	want := []string{"navigate_page", "type_text", "type_text", "click_element", "wait_for_element", "form_fill", "navigate_page", "keyboard_shortcuts"}
+	if !reflect.DeepEqual(tools, want) {
+		t.Fatalf("tools = %v, want %v", tools, want)
+	}
+
+	if w.Steps[0].Args["url"] != "{{start_url}}" || w.Vars["start_url"].Default != "https://example.com/login" {
+		t.Errorf("first page not made a variable: %v, %v", w.Steps[0].Args, w.Vars["start_url"])
+	}
+	if w.Steps[1].Args["text"] != "alice" || w.Steps[1].Args["page_id"] != "{{steps.open.data.id}}" {
+		t.Errorf("typing not collapsed or not on the opened page: %v", w.Steps[1].Args)
+	}
+	if w.Steps[2].Args["text"] != "{{password}}" {
+		t.Errorf("password written into the workflow: %v", w.Steps[2].Args)
+	}
+	if _, ok := w.Vars["password"]; !ok || w.Vars["password"].Default != nil {
+		t.Errorf("password variable = %v, want required", w.Vars)
+	}
+	if w.Steps[4].Args["selector"] != "#lang" || w.Steps[4].Args["state"] != "visible" {
+		t.Errorf("wait after navigation = %v", w.Steps[4].Args)
+	}
+	if fields, _ := w.Steps[5].Args["fields"].(map[string]interface{}); fields["#lang"] != "de" {
+		t.Errorf("select = %v", w.Steps[5].Args)
+	}
+	if w.Steps[6].Args["url"] != "https://example.com/search" || w.Steps[7].Args["page_id"] != "{{steps."+w.Steps[6].ID+".data.id}}" {
+		t.Errorf("typed navigation = %v then %v", w.Steps[6], w.Steps[7].Args)
+	}
+	if w.Steps[7].Args["keys"] != "Enter" {
+		t.Errorf("key = %v", w.Steps[7].Args)
+	}
+}
+
+func TestRecordWorkflowToolRequiresRecording(t *testing.T) {
+	tool := NewRecordWorkflowTool(createTestLogger(t), nil, t.TempDir())
+	resp, err := tool.Execute(map[string]interface{}{"action": "stop", "page_id": "missing", "name": "flow"})
+	if err != nil || !resp.IsError {
+		t.Fatalf("stop without a recording = %v, %v; want an error response", resp, err)
+	}
+}
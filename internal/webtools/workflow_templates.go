@@ -0,0 +1,482 @@
+package webtools
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// workflowEnvPrefix limits {{env.NAME}} to variables meant for workflows, so
+// a saved workflow cannot read the server's other secrets
+const workflowEnvPrefix = "RODMCP_"
+
+// errUndefined marks a template reference that resolved to nothing; a ??
+// fallback catches it
+type errUndefined struct {
+	name string
+}
+
+func (e errUndefined) Error() string {
+	return fmt.Sprintf("%s is not defined", e.name)
+}
+
+// templateScope is what workflow templates can refer to: the workflow's
+// variables by name, env.NAME and steps.ID.text/data/is_error
+type templateScope struct {
+	vars  map[string]interface{}
+	steps map[string]interface{}
+	env   func(string) (string, bool)
+}
+
+func newTemplateScope(vars map[string]interface{}) *templateScope {
+	return &templateScope{
+		vars:  vars,
+		steps: make(map[string]interface{}),
+		env:   os.LookupEnv,
+	}
+}
+
+// with returns a scope that also has vars, for the body of a loop. Step
+// results stay shared with s.
+func (s *templateScope) with(vars map[string]interface{}) *templateScope {
+	merged := make(map[string]interface{}, len(s.vars)+len(vars))
+	for name, value := range s.vars {
+		merged[name] = value
+	}
+	for name, value := range vars {
+		merged[name] = value
+	}
+	return &templateScope{vars: merged, steps: s.steps, env: s.env}
+}
+
+// fork returns a scope with its own copy of the step results so far, for
+// a parallel branch: the branch sees earlier results, and what it adds
+// stays out of the other branches' way until they are joined
+func (s *templateScope) fork() *templateScope {
+	steps := make(map[string]interface{}, len(s.steps))
+	for id, result := range s.steps {
+		steps[id] = result
+	}
+	return &templateScope{vars: s.vars, steps: steps, env: s.env}
+}
+
+// expand replaces the {{...}} placeholders in every string inside value,
+// descending into objects and arrays. A string that is exactly one
+// placeholder takes the value's own type, so a step can pass on a list or
+// object; otherwise values are formatted into the surrounding text.
+func (s *templateScope) expand(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return s.expandString(v)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded, err := s.expand(item)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			out[key] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded, err := s.expand(item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	}
+	return value, nil
+}
+
+func (s *templateScope) expandString(text string) (interface{}, error) {
+	parts, err := splitTemplate(text)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 1 && parts[0].expr {
+		return s.eval(parts[0].text)
+	}
+
+	var b strings.Builder
+	for _, part := range parts {
+		if !part.expr {
+			b.WriteString(part.text)
+			continue
+		}
+		value, err := s.eval(part.text)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(formatTemplateValue(value))
+	}
+	return b.String(), nil
+}
+
+// eval evaluates one placeholder: alternatives separated by ??, each a
+// + concatenation of quoted strings, numbers and references. The first
+// alternative whose references are all defined wins.
+func (s *templateScope) eval(expr string) (interface{}, error) {
+	var lastErr error
+	for _, alt := range splitTopLevel(expr, "??") {
+		terms := splitTopLevel(alt, "+")
+		values := make([]interface{}, 0, len(terms))
+		for _, term := range terms {
+			value, err := s.term(strings.TrimSpace(term))
+			if err != nil {
+				lastErr = err
+				values = nil
+				break
+			}
+			values = append(values, value)
+		}
+		if values == nil {
+			if _, undefined := lastErr.(errUndefined); undefined {
+				continue
+			}
+			return nil, lastErr
+		}
+		if len(values) == 1 {
+			return values[0], nil
+		}
+		var b strings.Builder
+		for _, value := range values {
+			b.WriteString(formatTemplateValue(value))
+		}
+		return b.String(), nil
+	}
+	return nil, lastErr
+}
+
+// term evaluates a literal or a reference
+func (s *templateScope) term(term string) (interface{}, error) {
+	if term == "" {
+		return nil, fmt.Errorf("empty expression")
+	}
+	if quote := term[0]; quote == '\'' || quote == '"' {
+		if len(term) < 2 || term[len(term)-1] != quote {
+			return nil, fmt.Errorf("unterminated string %s", term)
+		}
+		return strings.ReplaceAll(term[1:len(term)-1], `\`+string(quote), string(quote)), nil
+	}
+	if n, err := strconv.ParseFloat(term, 64); err == nil {
+		return n, nil
+	}
+	switch term {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return s.lookup(term)
+}
+
+// lookup resolves a dotted reference such as query, env.RODMCP_USER or
+// steps.search.data.items[0].title
+func (s *templateScope) lookup(ref string) (interface{}, error) {
+	path := strings.Split(strings.NewReplacer("[", ".", "]", "").Replace(ref), ".")
+	for _, segment := range path {
+		if segment == "" || strings.ContainsAny(segment, " \t'\"") {
+			return nil, fmt.Errorf("invalid reference %q", ref)
+		}
+	}
+
+	var current interface{}
+	rest := path[1:]
+	switch path[0] {
+	case "env":
+		if len(path) != 2 {
+			return nil, fmt.Errorf("invalid reference %q; use env.NAME", ref)
+		}
+		if !strings.HasPrefix(path[1], workflowEnvPrefix) {
+			return nil, fmt.Errorf("only %s* environment variables can be used in workflows", workflowEnvPrefix)
+		}
+		value, ok := s.env(path[1])
+		if !ok {
+			return nil, errUndefined{ref}
+		}
+		return value, nil
+	case "steps":
+		current = s.steps
+	default:
+		value, ok := s.vars[path[0]]
+		if !ok {
+			return nil, errUndefined{ref}
+		}
+		current = value
+	}
+
+	for _, segment := range rest {
+		switch v := current.(type) {
+		case map[string]interface{}:
+			value, ok := v[segment]
+			if !ok {
+				return nil, errUndefined{ref}
+			}
+			current = value
+		case []interface{}:
+			i, err := strconv.Atoi(segment)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, errUndefined{ref}
+			}
+			current = v[i]
+		default:
+			return nil, errUndefined{ref}
+		}
+	}
+	return current, nil
+}
+
+// templatePart is a run of literal text or the expression inside {{ }}
+type templatePart struct {
+	text string
+	expr bool
+}
+
+// splitTemplate splits text into literal runs and placeholders
+func splitTemplate(text string) ([]templatePart, error) {
+	var parts []templatePart
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			if text != "" {
+				parts = append(parts, templatePart{text: text})
+			}
+			return parts, nil
+		}
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed {{ in %q", text)
+		}
+		if start > 0 {
+			parts = append(parts, templatePart{text: text[:start]})
+		}
+		expr := strings.TrimSpace(text[start+2 : start+end])
+		if expr == "" {
+			return nil, fmt.Errorf("empty {{ }} in %q", text)
+		}
+		parts = append(parts, templatePart{text: expr, expr: true})
+		text = text[start+end+2:]
+	}
+}
+
+// checkTemplates reports the first malformed placeholder in value, so
+// broken workflows are refused when saved rather than when run
+func checkTemplates(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		_, err := splitTemplate(v)
+		return err
+	case map[string]interface{}:
+		for key, item := range v {
+			if err := checkTemplates(item); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			if err := checkTemplates(item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// splitTopLevel splits expr on sep where it is not inside a quoted string
+func splitTopLevel(expr, sep string) []string {
+	var parts []string
+	var quote byte
+	last := 0
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case strings.HasPrefix(expr[i:], sep):
+			parts = append(parts, expr[last:i])
+			i += len(sep) - 1
+			last = i + 1
+		}
+	}
+	return append(parts, expr[last:])
+}
+
+// formatTemplateValue renders a value inside surrounding text: strings as
+// they are, whole numbers without a decimal point, and anything else as JSON
+func formatTemplateValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// conditionOperators are tried in order, so two-character operators win
+// over their one-character prefixes
+var conditionOperators = []string{"==", "!=", ">=", "<=", " contains ", ">", "<"}
+
+// condition evaluates a workflow if: clauses joined by || and &&, each a
+// comparison (==, !=, <, <=, >, >=, contains), a value tested for
+// truthiness, or a !negated value. Undefined references count as empty, so
+// conditions can test for results that may be missing. Surrounding {{ }}
+// are optional.
+func (s *templateScope) condition(expr string) (bool, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{{") && strings.HasSuffix(expr, "}}") {
+		expr = strings.TrimSpace(expr[2 : len(expr)-2])
+	}
+	if expr == "" {
+		return false, fmt.Errorf("empty condition")
+	}
+
+	for _, alt := range splitTopLevel(expr, "||") {
+		all := true
+		for _, clause := range splitTopLevel(alt, "&&") {
+			ok, err := s.clause(strings.TrimSpace(clause))
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *templateScope) clause(clause string) (bool, error) {
+	for _, op := range conditionOperators {
+		sides := splitTopLevel(clause, op)
+		if len(sides) == 1 {
+			continue
+		}
+		if len(sides) != 2 {
+			return false, fmt.Errorf("cannot chain %s in %q", strings.TrimSpace(op), clause)
+		}
+		left, err := s.optional(sides[0])
+		if err != nil {
+			return false, err
+		}
+		right, err := s.optional(sides[1])
+		if err != nil {
+			return false, err
+		}
+		return compareValues(left, right, strings.TrimSpace(op)), nil
+	}
+
+	if strings.HasPrefix(clause, "!") {
+		ok, err := s.clause(strings.TrimSpace(clause[1:]))
+		return !ok, err
+	}
+	value, err := s.optional(clause)
+	return truthy(value), err
+}
+
+// optional evaluates expr, treating undefined references as nil
+func (s *templateScope) optional(expr string) (interface{}, error) {
+	value, err := s.eval(strings.TrimSpace(expr))
+	if _, undefined := err.(errUndefined); undefined {
+		return nil, nil
+	}
+	return value, err
+}
+
+// compareValues applies a condition operator. Ordering compares numbers
+// when both sides are numeric and text otherwise; contains looks for an
+// element in a list or a substring in text.
+func compareValues(left, right interface{}, op string) bool {
+	if op == "contains" {
+		if list, ok := left.([]interface{}); ok {
+			for _, item := range list {
+				if compareValues(item, right, "==") {
+					return true
+				}
+			}
+			return false
+		}
+		return left != nil && strings.Contains(formatTemplateValue(left), formatTemplateValue(right))
+	}
+
+	ln, lnum := templateNumber(left)
+	rn, rnum := templateNumber(right)
+	numeric := lnum && rnum
+	ls, rs := formatTemplateValue(left), formatTemplateValue(right)
+	switch op {
+	case "==":
+		if numeric {
+			return ln == rn
+		}
+		return ls == rs && (left == nil) == (right == nil)
+	case "!=":
+		return !compareValues(left, right, "==")
+	case ">":
+		return numeric && ln > rn || !numeric && ls > rs
+	case ">=":
+		return numeric && ln >= rn || !numeric && ls >= rs
+	case "<":
+		return numeric && ln < rn || !numeric && ls < rs
+	case "<=":
+		return numeric && ln <= rn || !numeric && ls <= rs
+	}
+	return false
+}
+
+// templateNumber reads a number, including one written as text
+func templateNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// truthy is false for nil, false, 0, "", "false" and empty lists and
+// objects
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != "" && v != "false"
+	case []interface{}:
+		return len(v) > 0
+	case map[string]interface{}:
+		return len(v) > 0
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package webtools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTemplateScopeExpand(t *testing.T) {
+	scope := newTemplateScope(map[string]interface{}{
+		"base_url": "https://shop.example.com",
+		"query":    "red shoes",
+		"limit":    float64(20),
+	})
+	scope.env = func(name string) (string, bool) {
+		if name == "RODMCP_USER" {
+			return "ada", true
+		}
+		return "", false
+	}
+	scope.steps["search"] = map[string]interface{}{
+		"text": "Found 2",
+		"data": map[string]interface{}{
+			"items": []interface{}{
+				map[string]interface{}{"title": "Runner", "url": "/p/1"},
+				map[string]interface{}{"title": "Trail", "url": "/p/2"},
+			},
+		},
+	}
+
+	cases := []struct {
+		in   interface{}
+		want interface{}
+	}{
+		{"{{base_url}}/search?q={{query}}", "https://shop.example.com/search?q=red shoes"},
+		{"{{limit}}", float64(20)},
+		{"max {{limit}}", "max 20"},
+		{"{{missing ?? 'none'}}", "none"},
+		{"{{missing ?? other ?? 5}}", float64(5)},
+		{"{{env.RODMCP_USER}}", "ada"},
+		{"{{base_url + steps.search.data.items[1].url}}", "https://shop.example.com/p/2"},
+		{"{{steps.search.data.items.0.title}}", "Runner"},
+		{"{{'a + b' + \"?? c\"}}", "a + b?? c"},
+		{"{{steps.search.data.items}}", scope.steps["search"].(map[string]interface{})["data"].(map[string]interface{})["items"]},
+		{map[string]interface{}{"fields": []interface{}{"{{query}}", 1.0}}, map[string]interface{}{"fields": []interface{}{"red shoes", 1.0}}},
+		{"no placeholders", "no placeholders"},
+	}
+	for _, tc := range cases {
+		got, err := scope.expand(tc.in)
+		if err != nil {
+			t.Errorf("expand(%v): %v", tc.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("expand(%v) = %#v, want %#v", tc.in, got, tc.want)
+		}
+	}
+
+	errorCases := map[string]string{
+		"{{missing}}":                  "missing is not defined",
+		"{{steps.search.data.nope}}":   "not defined",
+		"{{env.HOME}}":                 "only RODMCP_* environment variables",
+		"{{env.RODMCP_UNSET}}":         "not defined",
+		"{{query":                      "unclosed",
+		"{{ }}":                        "empty",
+		"{{'unterminated}}":            "unterminated",
+		"{{steps.search.items[0]x y}}": "invalid reference",
+	}
+	for in, want := range errorCases {
+		if _, err := scope.expand(in); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("expand(%q) error = %v, want %q", in, err, want)
+		}
+	}
+}
+
+func TestCheckTemplates(t *testing.T) {
+	if err := checkTemplates(map[string]interface{}{"url": "{{base}}/x", "n": 1.0}); err != nil {
+		t.Errorf("valid templates rejected: %v", err)
+	}
+	if err := checkTemplates(map[string]interface{}{"list": []interface{}{"{{oops"}}); err == nil || !strings.Contains(err.Error(), "list: [0]") {
+		t.Errorf("error = %v, want path to the broken template", err)
+	}
+}
+
+func TestTemplateScopeCondition(t *testing.T) {
+	scope := newTemplateScope(map[string]interface{}{
+		"count": float64(3),
+		"query": "red shoes",
+		"tags":  []interface{}{"sale", "new"},
+	})
+	scope.steps["check"] = map[string]interface{}{"text": "ok", "data": nil, "is_error": false}
+
+	cases := []struct {
+		expr string
+		want bool
+	}{
+		{"count > 2", true},
+		{"count >= '3'", true},
+		{"{{count < 3}}", false},
+		{"query == 'red shoes'", true},
+		{"query contains 'shoes' && count != 0", true},
+		{"tags contains 'sale'", true},
+		{"tags contains 'old' || steps.check.is_error == false", true},
+		{"steps.check.is_error", false},
+		{"!steps.missing.text", true},
+		{"missing", false},
+		{"query", true},
+	}
+	for _, tc := range cases {
+		got, err := scope.condition(tc.expr)
+		if err != nil || got != tc.want {
+			t.Errorf("condition(%q) = %v, %v; want %v", tc.expr, got, err, tc.want)
+		}
+	}
+
+	for _, expr := range []string{"", "a == b == c", "'open"} {
+		if _, err := scope.condition(expr); err == nil {
+			t.Errorf("condition(%q): expected an error", expr)
+		}
+	}
+}
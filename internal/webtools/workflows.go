@@ -0,0 +1,1148 @@
+package webtools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"rodmcp/internal/logger"
+	"rodmcp/pkg/types"
+)
+
+const (
+	// defaultWorkflowDir is where workflows are kept, relative to the
+	// working directory
+	defaultWorkflowDir = "workflows"
+
+	maxWorkflowSteps = 200
+
+	// maxWorkflowNesting caps how deeply for_each loops can nest
+	maxWorkflowNesting = 3
+
+	// maxLoopIterations caps the items a for_each loop runs over
+	maxLoopIterations = 1000
+
+	// maxParallelBranches caps the branches of one parallel step
+	maxParallelBranches = 10
+
+	// maxSubWorkflowDepth caps how many workflows can be running inside
+	// each other, counting the outermost
+	maxSubWorkflowDepth = 5
+)
+
+var workflowStepIDPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,63}$`)
+
+// ToolCaller runs a registered tool the way a client's tools/call would
+// (see mcp.Server.CallTool)
+type ToolCaller func(name string, args map[string]interface{}) (*types.CallToolResponse, error)
+
+// ContextToolCaller is a ToolCaller whose calls end with ctx (see
+// mcp.Server.CallToolContext)
+type ContextToolCaller func(ctx context.Context, name string, args map[string]interface{}) (*types.CallToolResponse, error)
+
+// planOnly wraps call so every call asks for a dry run: tools that would
+// change something return a plan instead (see mcp.PlanningTool), the rest
+// run as usual
+func planOnly(call ToolCaller) ToolCaller {
+	return func(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		planned := make(map[string]interface{}, len(args)+1)
+		for key, value := range args {
+			planned[key] = value
+		}
+		planned["dry_run"] = true
+		return call(name, planned)
+	}
+}
+
+// workflow is a named sequence of tool calls. Strings in step arguments may
+// hold {{...}} templates over the workflow's variables, env.RODMCP_* and
+// earlier steps' results. Steps can be skipped with an if condition, and a
+// for_each step repeats its own steps for every item in a list. A workflow
+// step runs another saved workflow, so common flows such as signing in can
+// be shared, and a parallel step runs branches of steps at the same time.
+type workflow struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Vars        map[string]workflowVar `json:"vars,omitempty"`
+	Steps       []workflowStep         `json:"steps"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+}
+
+// workflowVar declares an input. Variables without a default must be given
+// when the workflow runs.
+type workflowVar struct {
+	Description string      `json:"description,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+}
+
+// workflowStep calls Tool with Args, runs the saved Workflow with Args as
+// its variables, runs the branches in Parallel concurrently, or, with
+// ForEach, runs Steps once per item of a list with the item in the variable
+// named by As (default "item") and its position in As + "_index"
+type workflowStep struct {
+	ID       string                 `json:"id,omitempty"`
+	Tool     string                 `json:"tool,omitempty"`
+	Workflow string                 `json:"workflow,omitempty"`
+	Args     map[string]interface{} `json:"args,omitempty"`
+	If       string                 `json:"if,omitempty"`
+	ForEach  interface{}            `json:"for_each,omitempty"`
+	As       string                 `json:"as,omitempty"`
+	Steps    []workflowStep         `json:"steps,omitempty"`
+	Parallel []workflowBranch       `json:"parallel,omitempty"`
+}
+
+// Branch error policies: what a failed branch does to its parallel step
+const (
+	// branchFail fails the parallel step once every branch has finished
+	branchFail = "fail"
+	// branchCancel fails the parallel step and stops the other branches
+	// before their next step
+	branchCancel = "cancel"
+	// branchContinue reports the failure but lets the parallel step succeed
+	branchContinue = "continue"
+)
+
+// workflowBranch is one sequence of steps in a parallel step. Branches
+// should work on different pages; steps on the same page wait their turn.
+type workflowBranch struct {
+	ID      string         `json:"id,omitempty"`
+	Steps   []workflowStep `json:"steps"`
+	OnError string         `json:"on_error,omitempty"`
+}
+
+// onError is the branch's error policy, branchFail unless set
+func (b *workflowBranch) onError() string {
+	if b.OnError != "" {
+		return b.OnError
+	}
+	return branchFail
+}
+
+// loopVar is the name a for_each step gives each item
+func (s *workflowStep) loopVar() string {
+	if s.As != "" {
+		return s.As
+	}
+	return "item"
+}
+
+// validate checks the workflow's structure and templates, assigning IDs
+// (step1, step2, ...) to steps without one
+func (w *workflow) validate() error {
+	if len(w.Steps) == 0 {
+		return fmt.Errorf("a workflow needs at least one step")
+	}
+	if countSteps(w.Steps) > maxWorkflowSteps {
+		return fmt.Errorf("a workflow can have at most %d steps", maxWorkflowSteps)
+	}
+	for name := range w.Vars {
+		if !workflowStepIDPattern.MatchString(name) || name == "env" || name == "steps" {
+			return fmt.Errorf("invalid variable name %q", name)
+		}
+	}
+
+	return w.validateSteps(w.Steps, "step", make(map[string]bool), 1)
+}
+
+// validateSteps checks one level of steps. Step IDs must be unique across
+// the whole workflow; unnamed steps inside loops are named after the loop.
+func (w *workflow) validateSteps(steps []workflowStep, prefix string, seen map[string]bool, depth int) error {
+	for i := range steps {
+		step := &steps[i]
+		if step.ID == "" {
+			step.ID = fmt.Sprintf("%s%d", prefix, i+1)
+		}
+		if !workflowStepIDPattern.MatchString(step.ID) {
+			return fmt.Errorf("step %s: id must be letters, digits and '_', not starting with a digit", step.ID)
+		}
+		if seen[step.ID] {
+			return fmt.Errorf("step %s: duplicate id", step.ID)
+		}
+		seen[step.ID] = true
+
+		if step.If != "" {
+			if err := checkTemplates(step.If); err != nil {
+				return fmt.Errorf("step %s: if: %w", step.ID, err)
+			}
+		}
+
+		if len(step.Parallel) > 0 {
+			if step.Tool != "" || step.Workflow != "" || step.ForEach != nil || len(step.Steps) > 0 || step.As != "" || len(step.Args) > 0 {
+				return fmt.Errorf("step %s: a parallel step only runs its branches", step.ID)
+			}
+			if err := w.validateBranches(step, seen, depth); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if step.Workflow != "" {
+			if step.Tool != "" || step.ForEach != nil || len(step.Steps) > 0 || step.As != "" {
+				return fmt.Errorf("step %s: a workflow step runs a workflow, not a tool or loop", step.ID)
+			}
+			if !recipeNamePattern.MatchString(step.Workflow) {
+				return fmt.Errorf("step %s: invalid workflow name %q", step.ID, step.Workflow)
+			}
+			if step.Workflow == w.Name {
+				return fmt.Errorf("step %s: workflow %s cannot run itself", step.ID, w.Name)
+			}
+			if err := checkTemplates(step.Args); err != nil {
+				return fmt.Errorf("step %s: %w", step.ID, err)
+			}
+			continue
+		}
+
+		if step.ForEach == nil {
+			if step.Tool == "" {
+				return fmt.Errorf("step %s: tool or workflow is required", step.ID)
+			}
+			if len(step.Steps) > 0 || step.As != "" {
+				return fmt.Errorf("step %s: steps and as only go with for_each", step.ID)
+			}
+			if step.Tool == "run_workflow" {
+				return fmt.Errorf("step %s: use a workflow step to run another workflow", step.ID)
+			}
+			if err := checkTemplates(step.Args); err != nil {
+				return fmt.Errorf("step %s: %w", step.ID, err)
+			}
+			continue
+		}
+
+		if step.Tool != "" || len(step.Args) > 0 {
+			return fmt.Errorf("step %s: a for_each step runs its steps, not a tool", step.ID)
+		}
+		if len(step.Steps) == 0 {
+			return fmt.Errorf("step %s: for_each needs steps to repeat", step.ID)
+		}
+		if depth >= maxWorkflowNesting {
+			return fmt.Errorf("step %s: loops and parallel steps can nest at most %d deep", step.ID, maxWorkflowNesting-1)
+		}
+		switch over := step.ForEach.(type) {
+		case string:
+			if err := checkTemplates(over); err != nil {
+				return fmt.Errorf("step %s: for_each: %w", step.ID, err)
+			}
+		case []interface{}:
+		default:
+			return fmt.Errorf("step %s: for_each must be a list or an expression such as steps.search.data.items", step.ID)
+		}
+		name := step.loopVar()
+		if _, declared := w.Vars[name]; declared || !workflowStepIDPattern.MatchString(name) || name == "env" || name == "steps" {
+			return fmt.Errorf("step %s: invalid loop variable %q", step.ID, name)
+		}
+		if err := w.validateSteps(step.Steps, step.ID+"_", seen, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateBranches checks a parallel step's branches, naming unnamed ones
+// after the step. Branch IDs share the namespace of step IDs.
+func (w *workflow) validateBranches(step *workflowStep, seen map[string]bool, depth int) error {
+	if len(step.Parallel) < 2 {
+		return fmt.Errorf("step %s: parallel needs at least two branches", step.ID)
+	}
+	if len(step.Parallel) > maxParallelBranches {
+		return fmt.Errorf("step %s: parallel can have at most %d branches", step.ID, maxParallelBranches)
+	}
+	if depth >= maxWorkflowNesting {
+		return fmt.Errorf("step %s: loops and parallel steps can nest at most %d deep", step.ID, maxWorkflowNesting-1)
+	}
+	for i := range step.Parallel {
+		branch := &step.Parallel[i]
+		if branch.ID == "" {
+			branch.ID = fmt.Sprintf("%s_branch%d", step.ID, i+1)
+		}
+		if !workflowStepIDPattern.MatchString(branch.ID) {
+			return fmt.Errorf("step %s: branch id %s must be letters, digits and '_', not starting with a digit", step.ID, branch.ID)
+		}
+		if seen[branch.ID] {
+			return fmt.Errorf("step %s: duplicate id %s", step.ID, branch.ID)
+		}
+		seen[branch.ID] = true
+		switch branch.OnError {
+		case "", branchFail, branchCancel, branchContinue:
+		default:
+			return fmt.Errorf("step %s: branch %s: on_error must be %s, %s or %s", step.ID, branch.ID, branchFail, branchCancel, branchContinue)
+		}
+		if len(branch.Steps) == 0 {
+			return fmt.Errorf("step %s: branch %s needs steps", step.ID, branch.ID)
+		}
+		if err := w.validateSteps(branch.Steps, branch.ID+"_", seen, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// countSteps counts the steps in steps, including those inside loops and
+// parallel branches
+func countSteps(steps []workflowStep) int {
+	n := len(steps)
+	for _, step := range steps {
+		n += countSteps(step.Steps)
+		for _, branch := range step.Parallel {
+			n += countSteps(branch.Steps)
+		}
+	}
+	return n
+}
+
+// workflowStore keeps workflows as one JSON file each in a directory
+type workflowStore struct {
+	dir string
+}
+
+func newWorkflowStore(dir string) *workflowStore {
+	if dir == "" {
+		dir = defaultWorkflowDir
+	}
+	return &workflowStore{dir: dir}
+}
+
+func (s *workflowStore) path(name string) string {
+	return filepath.Join(s.dir, name+".json")
+}
+
+// save writes a workflow, replacing any with the same name, through a
+// temporary file so a crash never leaves half a workflow behind
+func (s *workflowStore) save(w *workflow) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create workflow directory: %w", err)
+	}
+	data, err := json.MarshalIndent(w, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := s.path(w.Name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return "", err
+	}
+	return path, nil
+}
+
+// load reads and validates the named workflow
+func (s *workflowStore) load(name string) (*workflow, error) {
+	if !recipeNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid workflow name %q", name)
+	}
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no workflow named %q", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var w workflow
+	if err := json.Unmarshal(data, &w); err != nil {
+		return nil, fmt.Errorf("workflow %q is corrupt: %w", name, err)
+	}
+	if err := w.validate(); err != nil {
+		return nil, fmt.Errorf("workflow %q is invalid: %w", name, err)
+	}
+	return &w, nil
+}
+
+// list returns every readable workflow, sorted by name
+func (s *workflowStore) list() ([]*workflow, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var workflows []*workflow
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if entry.IsDir() || name == entry.Name() {
+			continue
+		}
+		if w, err := s.load(name); err == nil {
+			workflows = append(workflows, w)
+		}
+	}
+	sort.Slice(workflows, func(i, j int) bool { return workflows[i].Name < workflows[j].Name })
+	return workflows, nil
+}
+
+// workflowStepOutcome is one step's entry in a run's report. Steps inside
+// loops appear once per iteration, as loop[i].step, and those of a
+// sub-workflow as parent.step.
+type workflowStepOutcome struct {
+	ID      string `json:"id"`
+	Tool    string `json:"tool,omitempty"`
+	OK      bool   `json:"ok"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Text    string `json:"text,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// workflowRun is the report of one run
+type workflowRun struct {
+	Steps   []workflowStepOutcome  `json:"steps"`
+	Results map[string]interface{} `json:"results"`
+	Failed  bool                   `json:"failed"`
+}
+
+// workflowRunner runs workflows through call. load resolves the workflows
+// that workflow steps name; without it such steps fail.
+type workflowRunner struct {
+	call            ToolCaller
+	load            func(name string) (*workflow, error)
+	continueOnError bool
+
+	// stack holds the names of the workflows being run, outermost first
+	stack []string
+
+	// cancelled holds the flags of the parallel branches this runner is
+	// inside; once one is set, no further steps start
+	cancelled []*atomic.Bool
+
+	// ctx, when set, is the call the run belongs to; once it ends, no
+	// further steps start
+	ctx context.Context
+}
+
+// branch returns a runner for one branch of a parallel step, stopping when
+// cancelled is set
+func (r *workflowRunner) branch(cancelled *atomic.Bool) *workflowRunner {
+	return &workflowRunner{
+		call:            r.call,
+		load:            r.load,
+		continueOnError: r.continueOnError,
+		stack:           append([]string(nil), r.stack...),
+		cancelled:       append(append([]*atomic.Bool(nil), r.cancelled...), cancelled),
+		ctx:             r.ctx,
+	}
+}
+
+// isCancelled reports whether another branch has asked this one to stop
+func (r *workflowRunner) isCancelled() bool {
+	for _, flag := range r.cancelled {
+		if flag.Load() {
+			return true
+		}
+	}
+	return false
+}
+
+// run runs w's steps in order. vars overrides the workflow's defaults. A
+// failing step ends the run unless continueOnError is set.
+func (r *workflowRunner) run(w *workflow, vars map[string]interface{}) (*workflowRun, error) {
+	values := make(map[string]interface{}, len(w.Vars))
+	for name, v := range w.Vars {
+		if v.Default != nil {
+			values[name] = v.Default
+		}
+	}
+	for name, value := range vars {
+		if _, declared := w.Vars[name]; !declared {
+			return nil, fmt.Errorf("workflow %s has no variable %q", w.Name, name)
+		}
+		values[name] = value
+	}
+	for name := range w.Vars {
+		if _, ok := values[name]; !ok {
+			return nil, fmt.Errorf("variable %q is required", name)
+		}
+	}
+
+	r.stack = append(r.stack, w.Name)
+	defer func() { r.stack = r.stack[:len(r.stack)-1] }()
+
+	scope := newTemplateScope(values)
+	run := &workflowRun{Results: scope.steps}
+	r.runSteps(w.Steps, "", scope, run)
+	return run, nil
+}
+
+// runSteps runs one level of steps, reporting false when a failure should
+// stop the run
+func (r *workflowRunner) runSteps(steps []workflowStep, prefix string, scope *templateScope, run *workflowRun) bool {
+	for _, step := range steps {
+		outcome := workflowStepOutcome{ID: prefix + step.ID, Tool: step.Tool}
+		if r.isCancelled() {
+			outcome.Error = "cancelled after another branch failed"
+			run.Steps = append(run.Steps, outcome)
+			run.Failed = true
+			return false
+		}
+		if r.ctx != nil && r.ctx.Err() != nil {
+			outcome.Error = "not started: the call ran out of time"
+			if !errors.Is(r.ctx.Err(), context.DeadlineExceeded) {
+				outcome.Error = "not started: the call was cancelled"
+			}
+			run.Steps = append(run.Steps, outcome)
+			run.Failed = true
+			return false
+		}
+		if step.If != "" {
+			ok, err := scope.condition(step.If)
+			if err != nil {
+				outcome.Error = fmt.Sprintf("if: %v", err)
+			} else if !ok {
+				outcome.OK, outcome.Skipped = true, true
+				run.Steps = append(run.Steps, outcome)
+				continue
+			}
+		}
+
+		if outcome.Error == "" && step.ForEach != nil {
+			if !r.runLoop(step, prefix, scope, run) {
+				return false
+			}
+			continue
+		}
+		if outcome.Error == "" && len(step.Parallel) > 0 {
+			if !r.runParallel(step, prefix, scope, run) {
+				return false
+			}
+			continue
+		}
+		if outcome.Error == "" && step.Workflow != "" {
+			if !r.runSubWorkflow(step, prefix, scope, run) {
+				return false
+			}
+			continue
+		}
+
+		if outcome.Error == "" {
+			resp, err := runWorkflowStep(step, scope, r.call)
+			switch {
+			case err != nil:
+				outcome.Error = err.Error()
+			case resp.IsError:
+				outcome.Error = toolResponseText(resp)
+			default:
+				outcome.OK = true
+				outcome.Text = toolResponseText(resp)
+			}
+			if resp != nil {
+				scope.steps[step.ID] = stepResult(resp)
+			}
+		}
+		run.Steps = append(run.Steps, outcome)
+		if !outcome.OK {
+			run.Failed = true
+			if !r.continueOnError {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// fail records a step that failed before it could run
+func (r *workflowRunner) fail(step workflowStep, id, message string, scope *templateScope, run *workflowRun) bool {
+	scope.steps[step.ID] = map[string]interface{}{"text": message, "data": nil, "is_error": true}
+	run.Steps = append(run.Steps, workflowStepOutcome{ID: id, Error: message})
+	run.Failed = true
+	return r.continueOnError
+}
+
+// runLoop runs a for_each step's steps for every item. Afterwards
+// steps.ID.data.iterations holds each iteration's step results, while
+// steps.<inner id> holds the last iteration's.
+func (r *workflowRunner) runLoop(step workflowStep, prefix string, scope *templateScope, run *workflowRun) bool {
+	id := prefix + step.ID
+	items, err := loopItems(step.ForEach, scope)
+	if err != nil {
+		return r.fail(step, id, fmt.Sprintf("for_each: %v", err), scope, run)
+	}
+	if len(items) > maxLoopIterations {
+		return r.fail(step, id, fmt.Sprintf("for_each: %d items is more than the limit of %d", len(items), maxLoopIterations), scope, run)
+	}
+
+	name := step.loopVar()
+	iterations := make([]interface{}, 0, len(items))
+	failedBefore := run.Failed
+	run.Failed = false
+	for i, item := range items {
+		inner := scope.with(map[string]interface{}{name: item, name + "_index": float64(i)})
+		keepGoing := r.runSteps(step.Steps, fmt.Sprintf("%s[%d].", id, i), inner, run)
+
+		results := make(map[string]interface{}, len(step.Steps))
+		for _, s := range step.Steps {
+			if result, ok := scope.steps[s.ID]; ok {
+				results[s.ID] = result
+			}
+		}
+		iterations = append(iterations, results)
+		if !keepGoing {
+			break
+		}
+	}
+	loopFailed := run.Failed
+	run.Failed = run.Failed || failedBefore
+
+	text := fmt.Sprintf("Ran %d of %d iterations", len(iterations), len(items))
+	scope.steps[step.ID] = map[string]interface{}{
+		"text":     text,
+		"data":     map[string]interface{}{"iterations": iterations, "count": float64(len(items))},
+		"is_error": loopFailed,
+	}
+	run.Steps = append(run.Steps, workflowStepOutcome{ID: id, OK: !loopFailed, Text: text})
+	return !loopFailed || r.continueOnError
+}
+
+// runSubWorkflow runs the workflow a step names, with the step's expanded
+// args as its variables. Its steps are reported as ID.step, and
+// steps.ID.data.steps holds their results.
+func (r *workflowRunner) runSubWorkflow(step workflowStep, prefix string, scope *templateScope, run *workflowRun) bool {
+	id := prefix + step.ID
+	for _, name := range r.stack {
+		if name == step.Workflow {
+			return r.fail(step, id, fmt.Sprintf("workflow %s calls itself (%s -> %s)", step.Workflow, strings.Join(r.stack, " -> "), step.Workflow), scope, run)
+		}
+	}
+	if len(r.stack) >= maxSubWorkflowDepth {
+		return r.fail(step, id, fmt.Sprintf("workflows can call each other at most %d deep", maxSubWorkflowDepth-1), scope, run)
+	}
+	if r.load == nil {
+		return r.fail(step, id, "no workflow library to load "+step.Workflow+" from", scope, run)
+	}
+	sub, err := r.load(step.Workflow)
+	if err != nil {
+		return r.fail(step, id, err.Error(), scope, run)
+	}
+	expanded, err := scope.expand(step.Args)
+	if err != nil {
+		return r.fail(step, id, err.Error(), scope, run)
+	}
+	vars, _ := expanded.(map[string]interface{})
+
+	subRun, err := r.run(sub, vars)
+	if err != nil {
+		return r.fail(step, id, fmt.Sprintf("workflow %s: %v", step.Workflow, err), scope, run)
+	}
+	for _, outcome := range subRun.Steps {
+		outcome.ID = id + "." + outcome.ID
+		run.Steps = append(run.Steps, outcome)
+	}
+
+	text := fmt.Sprintf("Ran workflow %s", step.Workflow)
+	if subRun.Failed {
+		text += " (failed)"
+		run.Failed = true
+	}
+	scope.steps[step.ID] = map[string]interface{}{
+		"text":     text,
+		"data":     map[string]interface{}{"steps": subRun.Results},
+		"is_error": subRun.Failed,
+	}
+	run.Steps = append(run.Steps, workflowStepOutcome{ID: id, OK: !subRun.Failed, Text: text})
+	return !subRun.Failed || r.continueOnError
+}
+
+// runParallel runs a parallel step's branches concurrently and joins them.
+// Each branch sees the results of earlier steps; once all have finished
+// their results are merged, so later steps can use them, and
+// steps.ID.data.branches.BRANCH.is_error tells how each branch went. Steps
+// are reported as ID.branch.step.
+func (r *workflowRunner) runParallel(step workflowStep, prefix string, scope *templateScope, run *workflowRun) bool {
+	id := prefix + step.ID
+	runs := make([]*workflowRun, len(step.Parallel))
+	scopes := make([]*templateScope, len(step.Parallel))
+	cancelled := new(atomic.Bool)
+
+	var wg sync.WaitGroup
+	for i, branch := range step.Parallel {
+		scopes[i] = scope.fork()
+		runs[i] = &workflowRun{Results: scopes[i].steps}
+		runner := r.branch(cancelled)
+		wg.Add(1)
+		go func(i int, branch workflowBranch) {
+			defer wg.Done()
+			runner.runSteps(branch.Steps, id+"."+branch.ID+".", scopes[i], runs[i])
+			if runs[i].Failed && branch.onError() == branchCancel {
+				cancelled.Store(true)
+			}
+		}(i, branch)
+	}
+	wg.Wait()
+
+	failed, succeeded := false, 0
+	branches := make(map[string]interface{}, len(step.Parallel))
+	for i, branch := range step.Parallel {
+		run.Steps = append(run.Steps, runs[i].Steps...)
+		for resultID, result := range scopes[i].steps {
+			scope.steps[resultID] = result
+		}
+		branches[branch.ID] = map[string]interface{}{"is_error": runs[i].Failed}
+		if !runs[i].Failed {
+			succeeded++
+		} else if branch.onError() != branchContinue {
+			failed = true
+		}
+	}
+
+	text := fmt.Sprintf("%d of %d branches succeeded", succeeded, len(step.Parallel))
+	scope.steps[step.ID] = map[string]interface{}{
+		"text":     text,
+		"data":     map[string]interface{}{"branches": branches},
+		"is_error": failed,
+	}
+	outcome := workflowStepOutcome{ID: id, OK: !failed, Text: text}
+	if failed {
+		outcome.Error = text
+		run.Failed = true
+	}
+	run.Steps = append(run.Steps, outcome)
+	return !failed || r.continueOnError
+}
+
+// loopItems evaluates a for_each value: a literal list, or an expression
+// (with or without {{ }}) that yields one
+func loopItems(over interface{}, scope *templateScope) ([]interface{}, error) {
+	if list, ok := over.([]interface{}); ok {
+		expanded, err := scope.expand(list)
+		if err != nil {
+			return nil, err
+		}
+		return expanded.([]interface{}), nil
+	}
+
+	expr := strings.TrimSpace(over.(string))
+	if !strings.HasPrefix(expr, "{{") {
+		expr = "{{" + expr + "}}"
+	}
+	value, err := scope.expand(expr)
+	if err != nil {
+		return nil, err
+	}
+	switch v := value.(type) {
+	case []interface{}:
+		return v, nil
+	case nil:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%s is not a list", over)
+}
+
+// runWorkflowStep expands a step's templates and calls its tool
+func runWorkflowStep(step workflowStep, scope *templateScope, call ToolCaller) (*types.CallToolResponse, error) {
+	expanded, err := scope.expand(step.Args)
+	if err != nil {
+		return nil, err
+	}
+	args, _ := expanded.(map[string]interface{})
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	resp, err := call(step.Tool, args)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("%s returned no result", step.Tool)
+	}
+	return resp, nil
+}
+
+// stepResult is what later steps see of a step as steps.ID: its text, its
+// structured data as plain JSON values, and whether it failed
+func stepResult(resp *types.CallToolResponse) map[string]interface{} {
+	var data interface{}
+	for _, content := range resp.Content {
+		if content.Data != nil {
+			if raw, err := json.Marshal(content.Data); err == nil {
+				json.Unmarshal(raw, &data)
+			}
+			break
+		}
+	}
+	return map[string]interface{}{
+		"text":     toolResponseText(resp),
+		"data":     data,
+		"is_error": resp.IsError,
+	}
+}
+
+// toolResponseText joins the text content of a tool response
+func toolResponseText(resp *types.CallToolResponse) string {
+	var texts []string
+	for _, content := range resp.Content {
+		if content.Type == "text" && content.Text != "" {
+			texts = append(texts, content.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// SaveWorkflowTool stores a named workflow on disk
+type SaveWorkflowTool struct {
+	logger *logger.Logger
+	store  *workflowStore
+}
+
+// NewSaveWorkflowTool creates a save_workflow tool that keeps workflows in
+// dir (default: workflows under the working directory)
+func NewSaveWorkflowTool(log *logger.Logger, dir string) *SaveWorkflowTool {
+	return &SaveWorkflowTool{logger: log, store: newWorkflowStore(dir)}
+}
+
+func (t *SaveWorkflowTool) Name() string {
+	return "save_workflow"
+}
+
+func (t *SaveWorkflowTool) Description() string {
+	return "Save a named workflow: a list of tool calls run in order by run_workflow. Step arguments can use {{var}} placeholders, {{var ?? 'default'}}, {{env.RODMCP_NAME}}, concatenation with + and earlier results such as {{steps.open.data.page_id}}, so one workflow covers many inputs"
+}
+
+func (t *SaveWorkflowTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Workflow name (letters, digits, '.', '_' and '-'). Saving an existing name replaces it",
+			},
+			"description": map[string]interface{}{
+				"type":        "string",
+				"description": "What the workflow does, for whoever reuses it",
+			},
+			"vars": map[string]interface{}{
+				"type":        "object",
+				"description": "Inputs by name, each {\"description\": ..., \"default\": ...}. Inputs without a default must be passed to run_workflow",
+				"additionalProperties": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"description": map[string]interface{}{"type": "string"},
+						"default":     map[string]interface{}{},
+					},
+				},
+			},
+			"steps": map[string]interface{}{
+				"type":        "array",
+				"description": "Tool calls to run in order: {\"id\": \"open\", \"tool\": \"navigate_page\", \"args\": {\"url\": \"{{base_url}}/search?q={{query}}\"}}. A string that is only a placeholder keeps the value's type. Add \"if\": \"steps.check.is_error == false && count > 0\" to skip a step, or use {\"for_each\": \"steps.search.data.items\", \"as\": \"item\", \"steps\": [...]} to repeat steps per item ({{item}}, {{item_index}}). {\"workflow\": \"login\", \"args\": {...}} runs another saved workflow, and {\"parallel\": [{\"steps\": [...]}, {\"steps\": [...]}]} runs branches concurrently on different pages",
+				"items": map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"id":       map[string]interface{}{"type": "string"},
+						"tool":     map[string]interface{}{"type": "string"},
+						"workflow": map[string]interface{}{"type": "string", "description": "Saved workflow to run instead of a tool; args become its variables"},
+						"args":     map[string]interface{}{"type": "object"},
+						"if":       map[string]interface{}{"type": "string", "description": "Condition: comparisons (==, !=, <, <=, >, >=, contains) joined by && and ||, or a value tested for truthiness; !negates"},
+						"for_each": map[string]interface{}{"description": "List, or expression yielding one, to repeat steps over"},
+						"as":       map[string]interface{}{"type": "string", "description": "Loop variable name (default: item)"},
+						"steps":    map[string]interface{}{"type": "array", "description": "Steps a for_each repeats", "items": map[string]interface{}{"type": "object"}},
+						"parallel": map[string]interface{}{
+							"type":        "array",
+							"description": "Branches to run at the same time, joined before the next step: [{\"id\": \"home\", \"steps\": [...], \"on_error\": \"fail\"}]. on_error is fail (default), cancel (also stop the other branches) or continue (ignore the failure)",
+							"items":       map[string]interface{}{"type": "object"},
+						},
+					},
+				},
+			},
+		},
+		Required: []string{"name", "steps"},
+	}
+}
+
+func (t *SaveWorkflowTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+
+		name, _ := args["name"].(string)
+		if !recipeNamePattern.MatchString(name) {
+			return fail("name must be 1-64 letters, digits, '.', '_' or '-', starting with a letter or digit")
+		}
+
+		var w workflow
+		raw, _ := json.Marshal(map[string]interface{}{"vars": args["vars"], "steps": args["steps"]})
+		if err := json.Unmarshal(raw, &w); err != nil {
+			return fail(fmt.Sprintf("Invalid workflow: %v", err))
+		}
+		w.Name = name
+		w.Description, _ = args["description"].(string)
+		w.UpdatedAt = time.Now().UTC()
+		if err := w.validate(); err != nil {
+			return fail(fmt.Sprintf("Invalid workflow: %v", err))
+		}
+
+		path, err := t.store.save(&w)
+		if err != nil {
+			return fail(fmt.Sprintf("Failed to save workflow: %v", err))
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: fmt.Sprintf("Saved workflow %s with %d steps", name, len(w.Steps)),
+				Data: map[string]interface{}{
+					"name":  name,
+					"path":  path,
+					"steps": len(w.Steps),
+				},
+			}},
+		}, nil
+	})
+}
+
+// RunWorkflowTool runs a saved workflow's steps through the server's tools
+type RunWorkflowTool struct {
+	logger *logger.Logger
+	store  *workflowStore
+	call   ToolCaller
+}
+
+// NewRunWorkflowTool creates a run_workflow tool reading workflows from dir
+// (default: workflows under the working directory). call runs each step; a
+// nil call leaves the tool unable to run anything.
+func NewRunWorkflowTool(log *logger.Logger, dir string, call ToolCaller) *RunWorkflowTool {
+	return &RunWorkflowTool{logger: log, store: newWorkflowStore(dir), call: call}
+}
+
+func (t *RunWorkflowTool) Name() string {
+	return "run_workflow"
+}
+
+func (t *RunWorkflowTool) Description() string {
+	return "Run a saved workflow with the given variables. Steps run in order on the server, with if conditions and for_each loops evaluated there too, and stop at the first failure; the result lists each step's outcome"
+}
+
+func (t *RunWorkflowTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type: "object",
+		Properties: map[string]interface{}{
+			"name": map[string]interface{}{
+				"type":        "string",
+				"description": "Workflow to run",
+			},
+			"vars": map[string]interface{}{
+				"type":        "object",
+				"description": "Values for the workflow's variables, overriding their defaults",
+			},
+			"continue_on_error": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Run the remaining steps after one fails (default: false)",
+				"default":     false,
+			},
+			"timeout": map[string]interface{}{
+				"type":        "number",
+				"description": "Seconds the whole run may take (default: the server's tool timeout)",
+			},
+			"dry_run": map[string]interface{}{
+				"type":        "boolean",
+				"description": "Run every step as a dry run: steps that would write files, commit or send data return what they would do instead (default: false)",
+				"default":     false,
+			},
+		},
+		Required: []string{"name"},
+	}
+}
+
+func (t *RunWorkflowTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		fail := func(message string) (*types.CallToolResponse, error) {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(message), nil
+		}
+		if t.call == nil {
+			return fail("run_workflow is not available in this mode")
+		}
+
+		name, _ := args["name"].(string)
+		w, err := t.store.load(name)
+		if err != nil {
+			return fail(err.Error())
+		}
+		vars, _ := args["vars"].(map[string]interface{})
+		continueOnError, _ := args["continue_on_error"].(bool)
+		call := t.call
+		dryRun, _ := args["dry_run"].(bool)
+		if dryRun {
+			call = planOnly(call)
+		}
+
+		runner := &workflowRunner{call: call, load: t.store.load, continueOnError: continueOnError}
+		run, err := runner.run(w, vars)
+		if err != nil {
+			return fail(fmt.Sprintf("Workflow %s: %v", name, err))
+		}
+		t.logger.LogToolExecution(t.Name(), args, !run.Failed, time.Since(start).Milliseconds())
+
+		succeeded, skipped := 0, 0
+		for _, step := range run.Steps {
+			switch {
+			case step.Skipped:
+				skipped++
+			case step.OK:
+				succeeded++
+			}
+		}
+		text := fmt.Sprintf("Workflow %s: %d of %d steps succeeded", name, succeeded, len(run.Steps)-skipped)
+		if skipped > 0 {
+			text += fmt.Sprintf(", %d skipped", skipped)
+		}
+		if dryRun {
+			text += " (dry run)"
+		}
+		for _, step := range run.Steps {
+			if !step.OK {
+				if step.Tool != "" {
+					text += fmt.Sprintf("; step %s (%s) failed: %s", step.ID, step.Tool, step.Error)
+				} else {
+					text += fmt.Sprintf("; step %s failed: %s", step.ID, step.Error)
+				}
+				break
+			}
+		}
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: text,
+				Data: map[string]interface{}{
+					"workflow": name,
+					"steps":    run.Steps,
+					"results":  run.Results,
+					"dry_run":  dryRun,
+				},
+			}},
+			IsError: run.Failed,
+		}, nil
+	})
+}
+
+// ListWorkflowsTool describes the saved workflows so agents can find and
+// reuse them
+type ListWorkflowsTool struct {
+	logger *logger.Logger
+	store  *workflowStore
+}
+
+// NewListWorkflowsTool creates a list_workflows tool reading workflows from
+// dir (default: workflows under the working directory)
+func NewListWorkflowsTool(log *logger.Logger, dir string) *ListWorkflowsTool {
+	return &ListWorkflowsTool{logger: log, store: newWorkflowStore(dir)}
+}
+
+func (t *ListWorkflowsTool) Name() string {
+	return "list_workflows"
+}
+
+func (t *ListWorkflowsTool) Description() string {
+	return "List the saved workflows with their descriptions, variables and the other workflows they run"
+}
+
+func (t *ListWorkflowsTool) InputSchema() types.ToolSchema {
+	return types.ToolSchema{
+		Type:       "object",
+		Properties: map[string]interface{}{},
+	}
+}
+
+func (t *ListWorkflowsTool) Execute(args map[string]interface{}) (*types.CallToolResponse, error) {
+	return executeWithPanicRecovery(t.Name(), t.logger, func() (*types.CallToolResponse, error) {
+		start := time.Now()
+		workflows, err := t.store.list()
+		if err != nil {
+			t.logger.LogToolExecution(t.Name(), args, false, time.Since(start).Milliseconds())
+			return recipeErrorResponse(fmt.Sprintf("Failed to list workflows: %v", err)), nil
+		}
+		t.logger.LogToolExecution(t.Name(), args, true, time.Since(start).Milliseconds())
+
+		if len(workflows) == 0 {
+			return &types.CallToolResponse{
+				Content: []types.ToolContent{{
+					Type: "text",
+					Text: fmt.Sprintf("No workflows saved in %s", t.store.dir),
+					Data: map[string]interface{}{"workflows": []interface{}{}},
+				}},
+			}, nil
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d workflows in %s:", len(workflows), t.store.dir)
+		entries := make([]map[string]interface{}, 0, len(workflows))
+		for _, w := range workflows {
+			var vars []string
+			for name, v := range w.Vars {
+				if v.Default == nil {
+					vars = append(vars, name+" (required)")
+				} else {
+					vars = append(vars, fmt.Sprintf("%s = %s", name, formatTemplateValue(v.Default)))
+				}
+			}
+			sort.Strings(vars)
+			uses := workflowsUsed(w.Steps)
+
+			fmt.Fprintf(&b, "\n- %s", w.Name)
+			if w.Description != "" {
+				fmt.Fprintf(&b, ": %s", w.Description)
+			}
+			if len(vars) > 0 {
+				fmt.Fprintf(&b, " [vars: %s]", strings.Join(vars, ", "))
+			}
+			if len(uses) > 0 {
+				fmt.Fprintf(&b, " [runs: %s]", strings.Join(uses, ", "))
+			}
+			entries = append(entries, map[string]interface{}{
+				"name":        w.Name,
+				"description": w.Description,
+				"vars":        w.Vars,
+				"steps":       countSteps(w.Steps),
+				"workflows":   uses,
+				"updated_at":  w.UpdatedAt,
+			})
+		}
+
+		return &types.CallToolResponse{
+			Content: []types.ToolContent{{
+				Type: "text",
+				Text: b.String(),
+				Data: map[string]interface{}{"workflows": entries},
+			}},
+		}, nil
+	})
+}
+
+// workflowsUsed lists the workflows that steps run, sorted and without
+// duplicates
+func workflowsUsed(steps []workflowStep) []string {
+	seen := make(map[string]bool)
+	var walk func([]workflowStep)
+	walk = func(steps []workflowStep) {
+		for _, step := range steps {
+			if step.Workflow != "" {
+				seen[step.Workflow] = true
+			}
+			walk(step.Steps)
+			for _, branch := range step.Parallel {
+				walk(branch.Steps)
+			}
+		}
+	}
+	walk(steps)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
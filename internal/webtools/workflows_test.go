@@ -0,0 +1,468 @@
+package webtools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"rodmcp/pkg/types"
+)
+
+// fakeToolCaller answers tool calls from handlers and records the arguments
+type fakeToolCaller struct {
+	handlers map[string]func(args map[string]interface{}) *types.CallToolResponse
+	calls    []string
+	args     []map[string]interface{}
+}
+
+func (f *fakeToolCaller) call(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+	f.calls = append(f.calls, name)
+	f.args = append(f.args, args)
+	handler, ok := f.handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+	return handler(args), nil
+}
+
+func textResponse(text string, data interface{}) *types.CallToolResponse {
+	return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: text, Data: data}}}
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	w := &workflow{Steps: []workflowStep{{Tool: "navigate_page"}, {ID: "read", Tool: "get_element_text"}}}
+	if err := w.validate(); err != nil || w.Steps[0].ID != "step1" {
+		t.Fatalf("validate = %v, ids %q", err, w.Steps[0].ID)
+	}
+
+	bad := []struct {
+		w    workflow
+		want string
+	}{
+		{workflow{}, "at least one step"},
+		{workflow{Steps: []workflowStep{{ID: "a", Tool: "x"}, {ID: "a", Tool: "y"}}}, "duplicate id"},
+		{workflow{Steps: []workflowStep{{ID: "1a", Tool: "x"}}}, "id must be"},
+		{workflow{Steps: []workflowStep{{ID: "a"}}}, "tool or workflow is required"},
+		{workflow{Steps: []workflowStep{{Tool: "run_workflow"}}}, "use a workflow step"},
+		{workflow{Steps: []workflowStep{{Tool: "x", Args: map[string]interface{}{"u": "{{a"}}}}, "unclosed"},
+		{workflow{Vars: map[string]workflowVar{"steps": {}}, Steps: []workflowStep{{Tool: "x"}}}, "invalid variable name"},
+	}
+	for _, tc := range bad {
+		if err := tc.w.validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validate(%+v) = %v, want %q", tc.w, err, tc.want)
+		}
+	}
+}
+
+func TestRunWorkflow(t *testing.T) {
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"navigate_page": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("Navigated", map[string]interface{}{"page_id": "page-7"})
+		},
+		"get_element_text": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("Text: "+args["selector"].(string), nil)
+		},
+		"fail": func(args map[string]interface{}) *types.CallToolResponse {
+			return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: "boom"}}, IsError: true}
+		},
+	}}
+	w := &workflow{
+		Name: "search",
+		Vars: map[string]workflowVar{
+			"base_url": {Default: "https://example.com"},
+			"query":    {},
+		},
+		Steps: []workflowStep{
+			{ID: "open", Tool: "navigate_page", Args: map[string]interface{}{"url": "{{base_url}}/?q={{query}}"}},
+			{ID: "read", Tool: "get_element_text", Args: map[string]interface{}{"page_id": "{{steps.open.data.page_id}}", "selector": "h1"}},
+		},
+	}
+	if err := w.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := (&workflowRunner{call: fake.call}).run(w, nil); err == nil || !strings.Contains(err.Error(), `"query" is required`) {
+		t.Errorf("missing variable error = %v", err)
+	}
+	if _, err := (&workflowRunner{call: fake.call}).run(w, map[string]interface{}{"query": "x", "other": 1}); err == nil {
+		t.Error("expected undeclared variable to be rejected")
+	}
+
+	run, err := (&workflowRunner{call: fake.call}).run(w, map[string]interface{}{"query": "go"})
+	if err != nil || run.Failed || len(run.Steps) != 2 {
+		t.Fatalf("run = %+v, %v", run, err)
+	}
+	if fake.args[0]["url"] != "https://example.com/?q=go" || fake.args[1]["page_id"] != "page-7" {
+		t.Errorf("step args = %v", fake.args)
+	}
+	if run.Steps[1].Text != "Text: h1" {
+		t.Errorf("outcome = %+v", run.Steps[1])
+	}
+
+	// A failing step stops the run unless told to continue
+	w.Steps = append([]workflowStep{{ID: "bad", Tool: "fail"}}, w.Steps...)
+	fake.calls = nil
+	run, _ = (&workflowRunner{call: fake.call}).run(w, map[string]interface{}{"query": "go"})
+	if !run.Failed || len(run.Steps) != 1 || run.Steps[0].Error != "boom" || len(fake.calls) != 1 {
+		t.Errorf("stopped run = %+v, calls %v", run, fake.calls)
+	}
+	run, _ = (&workflowRunner{call: fake.call, continueOnError: true}).run(w, map[string]interface{}{"query": "go"})
+	if !run.Failed || len(run.Steps) != 3 || !run.Steps[2].OK {
+		t.Errorf("continued run = %+v", run)
+	}
+}
+
+func TestWorkflowTools(t *testing.T) {
+	dir := t.TempDir()
+	save := NewSaveWorkflowTool(createTestLogger(t), dir)
+	resp, err := save.Execute(map[string]interface{}{
+		"name": "greet",
+		"vars": map[string]interface{}{"who": map[string]interface{}{"default": "world"}},
+		"steps": []interface{}{
+			map[string]interface{}{"tool": "echo", "args": map[string]interface{}{"message": "hello {{who}}"}},
+		},
+	})
+	if err != nil || resp.IsError {
+		t.Fatalf("save = %+v, %v", resp, err)
+	}
+
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"echo": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse(args["message"].(string), nil)
+		},
+	}}
+	run := NewRunWorkflowTool(createTestLogger(t), dir, fake.call)
+	resp, err = run.Execute(map[string]interface{}{"name": "greet", "vars": map[string]interface{}{"who": "Ada"}})
+	if err != nil || resp.IsError || !strings.Contains(resp.Content[0].Text, "1 of 1 steps succeeded") {
+		t.Fatalf("run = %+v, %v", resp, err)
+	}
+	if fake.args[0]["message"] != "hello Ada" {
+		t.Errorf("echo args = %v", fake.args[0])
+	}
+
+	resp, _ = run.Execute(map[string]interface{}{"name": "missing"})
+	if !resp.IsError || !strings.Contains(resp.Content[0].Text, `no workflow named "missing"`) {
+		t.Errorf("missing workflow = %+v", resp)
+	}
+}
+
+func TestWorkflowValidateLoops(t *testing.T) {
+	w := &workflow{Steps: []workflowStep{{ID: "each", ForEach: "steps.x.data", Steps: []workflowStep{{Tool: "x"}}}}}
+	if err := w.validate(); err != nil || w.Steps[0].Steps[0].ID != "each_1" {
+		t.Fatalf("validate = %v, inner id %q", err, w.Steps[0].Steps[0].ID)
+	}
+
+	deep := workflowStep{ForEach: "a", Steps: []workflowStep{{ForEach: "b", Steps: []workflowStep{{ForEach: "c", Steps: []workflowStep{{Tool: "x"}}}}}}}
+	bad := []struct {
+		w    workflow
+		want string
+	}{
+		{workflow{Steps: []workflowStep{{ForEach: "items"}}}, "needs steps"},
+		{workflow{Steps: []workflowStep{{ForEach: "items", Tool: "x", Steps: []workflowStep{{Tool: "y"}}}}}, "not a tool"},
+		{workflow{Steps: []workflowStep{{Tool: "x", Steps: []workflowStep{{Tool: "y"}}}}}, "only go with for_each"},
+		{workflow{Steps: []workflowStep{{ForEach: 3.0, Steps: []workflowStep{{Tool: "y"}}}}}, "must be a list"},
+		{workflow{Steps: []workflowStep{{ForEach: "items", As: "steps", Steps: []workflowStep{{Tool: "y"}}}}}, "invalid loop variable"},
+		{workflow{Steps: []workflowStep{{ID: "a", ForEach: "items", Steps: []workflowStep{{ID: "a", Tool: "y"}}}}}, "duplicate id"},
+		{workflow{Steps: []workflowStep{deep}}, "nest at most"},
+		{workflow{Steps: []workflowStep{{Tool: "x", If: "{{a"}}}, "unclosed"},
+	}
+	for _, tc := range bad {
+		if err := tc.w.validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validate(%+v) = %v, want %q", tc.w, err, tc.want)
+		}
+	}
+}
+
+func TestRunWorkflowConditionsAndLoops(t *testing.T) {
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"search": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse("Found 2", map[string]interface{}{
+				"items": []interface{}{
+					map[string]interface{}{"url": "/p/1"},
+					map[string]interface{}{"url": "/p/2"},
+				},
+			})
+		},
+		"open": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse(fmt.Sprintf("Opened %v #%v", args["url"], args["n"]), nil)
+		},
+		"fail": func(args map[string]interface{}) *types.CallToolResponse {
+			return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: "boom"}}, IsError: true}
+		},
+	}}
+	w := &workflow{
+		Vars: map[string]workflowVar{"login": {Default: false}},
+		Steps: []workflowStep{
+			{ID: "sign_in", Tool: "fail", If: "login"},
+			{ID: "find", Tool: "search"},
+			{ID: "each", ForEach: "steps.find.data.items", As: "product", Steps: []workflowStep{
+				{ID: "visit", Tool: "open", Args: map[string]interface{}{"url": "{{product.url}}", "n": "{{product_index}}"}},
+			}},
+			{ID: "report", Tool: "open", If: "steps.each.data.count == 2", Args: map[string]interface{}{"url": "{{steps.each.data.iterations[1].visit.text}}"}},
+		},
+	}
+	if err := w.validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	run, err := (&workflowRunner{call: fake.call}).run(w, nil)
+	if err != nil || run.Failed {
+		t.Fatalf("run = %+v, %v", run, err)
+	}
+	var ids []string
+	for _, step := range run.Steps {
+		ids = append(ids, step.ID)
+	}
+	if got := strings.Join(ids, ","); got != "sign_in,find,each[0].visit,each[1].visit,each,report" {
+		t.Errorf("outcome ids = %s", got)
+	}
+	if !run.Steps[0].Skipped || run.Steps[4].Text != "Ran 2 of 2 iterations" {
+		t.Errorf("outcomes = %+v", run.Steps)
+	}
+	if fake.args[2]["url"] != "/p/2" || fake.args[2]["n"] != float64(1) {
+		t.Errorf("second iteration args = %v", fake.args[2])
+	}
+	if fake.args[3]["url"] != "Opened /p/2 #1" {
+		t.Errorf("report args = %v", fake.args[3])
+	}
+
+	// A failure inside a loop stops the loop and the run
+	w.Steps[2].Steps[0].Tool = "fail"
+	fake.calls = nil
+	run, _ = (&workflowRunner{call: fake.call}).run(w, nil)
+	if !run.Failed || len(fake.calls) != 2 || run.Steps[len(run.Steps)-1].ID != "each" {
+		t.Errorf("failed loop = %+v, calls %v", run, fake.calls)
+	}
+
+	// for_each over something that is not a list fails the step
+	w.Steps[2].ForEach = "steps.find.text"
+	run, _ = (&workflowRunner{call: fake.call}).run(w, nil)
+	if !run.Failed || !strings.Contains(run.Steps[len(run.Steps)-1].Error, "not a list") {
+		t.Errorf("bad for_each = %+v", run.Steps)
+	}
+}
+
+func TestRunSubWorkflows(t *testing.T) {
+	dir := t.TempDir()
+	store := newWorkflowStore(dir)
+	login := &workflow{
+		Name:  "login",
+		Vars:  map[string]workflowVar{"user": {}},
+		Steps: []workflowStep{{ID: "type", Tool: "echo", Args: map[string]interface{}{"message": "signed in as {{user}}"}}},
+	}
+	checkout := &workflow{
+		Name: "checkout",
+		Vars: map[string]workflowVar{"user": {Default: "ada"}},
+		Steps: []workflowStep{
+			{ID: "auth", Workflow: "login", Args: map[string]interface{}{"user": "{{user}}"}},
+			{ID: "pay", Tool: "echo", Args: map[string]interface{}{"message": "{{steps.auth.data.steps.type.text}}, paying"}},
+		},
+	}
+	loop := &workflow{Name: "loop_a", Steps: []workflowStep{{Workflow: "loop_b"}}}
+	loopBack := &workflow{Name: "loop_b", Steps: []workflowStep{{Workflow: "loop_a"}}}
+	for _, w := range []*workflow{login, checkout, loop, loopBack} {
+		if err := w.validate(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.save(w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fake := &fakeToolCaller{handlers: map[string]func(map[string]interface{}) *types.CallToolResponse{
+		"echo": func(args map[string]interface{}) *types.CallToolResponse {
+			return textResponse(args["message"].(string), nil)
+		},
+	}}
+	runner := &workflowRunner{call: fake.call, load: store.load}
+	run, err := runner.run(checkout, nil)
+	if err != nil || run.Failed {
+		t.Fatalf("run = %+v, %v", run, err)
+	}
+	if len(run.Steps) != 3 || run.Steps[0].ID != "auth.type" || run.Steps[1].ID != "auth" {
+		t.Errorf("outcomes = %+v", run.Steps)
+	}
+	if fake.args[1]["message"] != "signed in as ada, paying" {
+		t.Errorf("pay args = %v", fake.args[1])
+	}
+
+	run, err = runner.run(loop, nil)
+	if err != nil || !run.Failed {
+		t.Fatalf("cycle run = %+v, %v", run, err)
+	}
+	if got := run.Steps[0].Error; !strings.Contains(got, "loop_a -> loop_b -> loop_a") {
+		t.Errorf("cycle error = %q", got)
+	}
+
+	if err := (&workflow{Name: "self", Steps: []workflowStep{{Workflow: "self"}}}).validate(); err == nil || !strings.Contains(err.Error(), "cannot run itself") {
+		t.Errorf("self reference = %v", err)
+	}
+	run, _ = (&workflowRunner{call: fake.call}).run(checkout, nil)
+	if !run.Failed || !strings.Contains(run.Steps[0].Error, "no workflow library") {
+		t.Errorf("run without a library = %+v", run.Steps)
+	}
+}
+
+func TestListWorkflowsTool(t *testing.T) {
+	dir := t.TempDir()
+	list := NewListWorkflowsTool(createTestLogger(t), dir)
+	resp, err := list.Execute(map[string]interface{}{})
+	if err != nil || resp.IsError || !strings.Contains(resp.Content[0].Text, "No workflows saved") {
+		t.Fatalf("empty list = %+v, %v", resp, err)
+	}
+
+	save := NewSaveWorkflowTool(createTestLogger(t), dir)
+	for _, args := range []map[string]interface{}{
+		{"name": "login", "description": "Sign in", "vars": map[string]interface{}{"user": map[string]interface{}{}},
+			"steps": []interface{}{map[string]interface{}{"tool": "echo"}}},
+		{"name": "checkout", "vars": map[string]interface{}{"qty": map[string]interface{}{"default": 2}},
+			"steps": []interface{}{map[string]interface{}{"workflow": "login", "args": map[string]interface{}{"user": "ada"}}}},
+	} {
+		if resp, err := save.Execute(args); err != nil || resp.IsError {
+			t.Fatalf("save = %+v, %v", resp, err)
+		}
+	}
+
+	resp, _ = list.Execute(map[string]interface{}{})
+	text := resp.Content[0].Text
+	if !strings.Contains(text, "- checkout [vars: qty = 2] [runs: login]") || !strings.Contains(text, "- login: Sign in [vars: user (required)]") {
+		t.Errorf("list text = %q", text)
+	}
+	if strings.Index(text, "checkout") > strings.Index(text, "- login") {
+		t.Errorf("expected workflows sorted by name: %q", text)
+	}
+}
+
+func TestWorkflowValidateParallel(t *testing.T) {
+	w := &workflow{Steps: []workflowStep{{ID: "checks", Parallel: []workflowBranch{
+		{Steps: []workflowStep{{Tool: "x"}}},
+		{ID: "b", Steps: []workflowStep{{Tool: "y"}}},
+	}}}}
+	if err := w.validate(); err != nil || w.Steps[0].Parallel[0].ID != "checks_branch1" || w.Steps[0].Parallel[1].Steps[0].ID != "b_1" {
+		t.Fatalf("validate = %v, %+v", err, w.Steps[0].Parallel)
+	}
+
+	one := []workflowBranch{{Steps: []workflowStep{{Tool: "x"}}}}
+	two := func(onError string) []workflowBranch {
+		return []workflowBranch{{Steps: []workflowStep{{Tool: "x"}}}, {Steps: []workflowStep{{Tool: "y"}}, OnError: onError}}
+	}
+	bad := []struct {
+		w    workflow
+		want string
+	}{
+		{workflow{Steps: []workflowStep{{Parallel: one}}}, "at least two branches"},
+		{workflow{Steps: []workflowStep{{Tool: "x", Parallel: two("")}}}, "only runs its branches"},
+		{workflow{Steps: []workflowStep{{Parallel: two("retry")}}}, "on_error must be"},
+		{workflow{Steps: []workflowStep{{Parallel: []workflowBranch{{Steps: []workflowStep{{Tool: "x"}}}, {}}}}}, "needs steps"},
+	}
+	for _, tc := range bad {
+		if err := tc.w.validate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("validate(%+v) = %v, want %q", tc.w, err, tc.want)
+		}
+	}
+}
+
+func TestRunWorkflowParallel(t *testing.T) {
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	call := func(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		switch name {
+		case "slow":
+			// Both branches must be running at once for this to return
+			started <- args["page"].(string)
+			<-release
+			return textResponse("loaded "+args["page"].(string), nil), nil
+		case "fail":
+			return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: "boom"}}, IsError: true}, nil
+		}
+		return textResponse(fmt.Sprint(args["message"]), nil), nil
+	}
+	go func() {
+		<-started
+		<-started
+		close(release)
+	}()
+
+	w := &workflow{Steps: []workflowStep{
+		{ID: "checks", Parallel: []workflowBranch{
+			{ID: "home", Steps: []workflowStep{{ID: "open_home", Tool: "slow", Args: map[string]interface{}{"page": "home"}}}},
+			{ID: "pricing", Steps: []workflowStep{{ID: "open_pricing", Tool: "slow", Args: map[string]interface{}{"page": "pricing"}}}},
+		}},
+		{ID: "report", Tool: "echo", Args: map[string]interface{}{"message": "{{steps.open_home.text}} and {{steps.open_pricing.text}}"}},
+	}}
+	if err := w.validate(); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan *workflowRun)
+	go func() {
+		run, _ := (&workflowRunner{call: call}).run(w, nil)
+		done <- run
+	}()
+	var run *workflowRun
+	select {
+	case run = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("branches did not run concurrently")
+	}
+	if run.Failed || run.Steps[len(run.Steps)-1].Text != "loaded home and loaded pricing" {
+		t.Fatalf("run = %+v", run)
+	}
+	if run.Steps[0].ID != "checks.home.open_home" || run.Steps[2].ID != "checks" {
+		t.Errorf("outcome ids = %+v", run.Steps)
+	}
+
+	// Error policies decide what a failed branch does to the step
+	policyRun := func(onError string) *workflowRun {
+		w := &workflow{Steps: []workflowStep{
+			{ID: "group", Parallel: []workflowBranch{
+				{ID: "bad", OnError: onError, Steps: []workflowStep{{Tool: "fail"}}},
+				{ID: "good", Steps: []workflowStep{{Tool: "echo", Args: map[string]interface{}{"message": "ok"}}}},
+			}},
+			{ID: "after", Tool: "echo", Args: map[string]interface{}{"message": "{{steps.group.data.branches.bad.is_error}}"}},
+		}}
+		if err := w.validate(); err != nil {
+			t.Fatal(err)
+		}
+		run, _ := (&workflowRunner{call: call}).run(w, nil)
+		return run
+	}
+	if run := policyRun("continue"); run.Failed || run.Steps[len(run.Steps)-1].Text != "true" {
+		t.Errorf("continue policy run = %+v", run)
+	}
+	if run := policyRun(""); !run.Failed || run.Steps[len(run.Steps)-1].ID != "group" {
+		t.Errorf("fail policy run = %+v", run)
+	}
+}
+
+func TestRunWorkflowParallelCancel(t *testing.T) {
+	failed := make(chan struct{})
+	call := func(name string, args map[string]interface{}) (*types.CallToolResponse, error) {
+		switch name {
+		case "fail":
+			defer close(failed)
+			return &types.CallToolResponse{Content: []types.ToolContent{{Type: "text", Text: "boom"}}, IsError: true}, nil
+		case "wait":
+			<-failed
+			// Give the failing branch time to raise the flag
+			time.Sleep(50 * time.Millisecond)
+		}
+		return textResponse(name, nil), nil
+	}
+	w := &workflow{Steps: []workflowStep{{ID: "group", Parallel: []workflowBranch{
+		{ID: "bad", OnError: "cancel", Steps: []workflowStep{{Tool: "fail"}}},
+		{ID: "slow", Steps: []workflowStep{{ID: "first", Tool: "wait"}, {ID: "second", Tool: "echo"}}},
+	}}}}
+	if err := w.validate(); err != nil {
+		t.Fatal(err)
+	}
+	run, _ := (&workflowRunner{call: call}).run(w, nil)
+	if !run.Failed {
+		t.Fatalf("run = %+v", run)
+	}
+	for _, step := range run.Steps {
+		if step.ID == "group.slow.second" && !strings.Contains(step.Error, "cancelled") {
+			t.Errorf("second step = %+v", step)
+		}
+	}
+}
@@ -47,6 +47,7 @@ type InitializeResponse struct {
 type ClientCapabilities struct {
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 	Sampling     map[string]interface{} `json:"sampling,omitempty"`
+	Elicitation  map[string]interface{} `json:"elicitation,omitempty"`
 }
 
 type ServerCapabilities struct {
@@ -54,6 +55,7 @@ type ServerCapabilities struct {
 	Prompts      *PromptsCapability     `json:"prompts,omitempty"`
 	Resources    *ResourcesCapability   `json:"resources,omitempty"`
 	Tools        *ToolsCapability       `json:"tools,omitempty"`
+	Completions  *CompletionsCapability `json:"completions,omitempty"`
 	Experimental map[string]interface{} `json:"experimental,omitempty"`
 }
 
@@ -61,6 +63,7 @@ type LoggingCapability struct{}
 type PromptsCapability struct{}
 type ResourcesCapability struct{}
 type ToolsCapability struct{}
+type CompletionsCapability struct{}
 
 type ClientInfo struct {
 	Name    string `json:"name"`
@@ -77,6 +80,31 @@ type Tool struct {
 	Name        string     `json:"name"`
 	Description string     `json:"description,omitempty"`
 	InputSchema ToolSchema `json:"inputSchema"`
+	Meta        *ToolMeta  `json:"_meta,omitempty"`
+}
+
+// DefaultToolVersion is reported for tools that do not declare a version
+const DefaultToolVersion = "1.0.0"
+
+// ToolMeta carries a tool's version and deprecation status so prompt
+// libraries can notice when a tool they depend on changes
+type ToolMeta struct {
+	Version            string `json:"version"`
+	Deprecated         bool   `json:"deprecated,omitempty"`
+	DeprecationMessage string `json:"deprecationMessage,omitempty"`
+	ReplacedBy         string `json:"replacedBy,omitempty"`
+	// RenamedParameters maps old parameter names to their current names
+	RenamedParameters map[string]string `json:"renamedParameters,omitempty"`
+}
+
+// ToolPlan describes what a tool call would do without doing it, for
+// reviewing an agent's actions in dry-run mode
+type ToolPlan struct {
+	// Mutates is false for calls that only read (a GET request, a click
+	// that submits nothing); dry-run mode runs those normally
+	Mutates bool                   `json:"mutates"`
+	Summary string                 `json:"summary"`
+	Details map[string]interface{} `json:"details,omitempty"`
 }
 
 type ToolSchema struct {
@@ -85,6 +113,110 @@ type ToolSchema struct {
 	Required   []string               `json:"required,omitempty"`
 }
 
+// Elicitation types (elicitation/create)
+
+// ElicitationMethod is the request a server sends to ask the client's user
+// for input in the middle of a tool call
+const ElicitationMethod = "elicitation/create"
+
+// ElicitRequest asks the user for the fields in RequestedSchema, a flat
+// object of string, number, boolean and enum properties
+type ElicitRequest struct {
+	Message         string     `json:"message"`
+	RequestedSchema ToolSchema `json:"requestedSchema"`
+}
+
+// ElicitResult is the user's answer. Action is "accept" (Content holds the
+// fields), "decline" or "cancel".
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// Sampling types (sampling/createMessage)
+
+// SamplingMethod is the request a server sends to have the client's model
+// generate a message
+const SamplingMethod = "sampling/createMessage"
+
+// SamplingContent is one piece of a sampling message; rodmcp only sends and
+// reads text
+type SamplingContent struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+}
+
+type SamplingMessage struct {
+	Role    string          `json:"role"`
+	Content SamplingContent `json:"content"`
+}
+
+// ModelPreferences hints which model the client should pick. Priorities
+// range from 0 to 1.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         float64     `json:"costPriority,omitempty"`
+	SpeedPriority        float64     `json:"speedPriority,omitempty"`
+	IntelligencePriority float64     `json:"intelligencePriority,omitempty"`
+}
+
+type ModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+type CreateMessageRequest struct {
+	Messages         []SamplingMessage `json:"messages"`
+	SystemPrompt     string            `json:"systemPrompt,omitempty"`
+	MaxTokens        int               `json:"maxTokens"`
+	Temperature      *float64          `json:"temperature,omitempty"`
+	StopSequences    []string          `json:"stopSequences,omitempty"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+}
+
+type CreateMessageResult struct {
+	Role       string          `json:"role"`
+	Content    SamplingContent `json:"content"`
+	Model      string          `json:"model"`
+	StopReason string          `json:"stopReason,omitempty"`
+}
+
+// Completion related types (completion/complete)
+
+// CompletionReference names what an argument belongs to. Besides the
+// standard "ref/prompt" and "ref/resource", rodmcp accepts "ref/tool" with a
+// tool name so clients can complete tool arguments.
+type CompletionReference struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	URI  string `json:"uri,omitempty"`
+}
+
+type CompletionArgument struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// CompletionContext carries arguments the client has already filled in
+type CompletionContext struct {
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+type CompleteRequest struct {
+	Ref      CompletionReference `json:"ref"`
+	Argument CompletionArgument  `json:"argument"`
+	Context  *CompletionContext  `json:"context,omitempty"`
+}
+
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total,omitempty"`
+	HasMore bool     `json:"hasMore,omitempty"`
+}
+
+type CompleteResult struct {
+	Completion Completion `json:"completion"`
+}
+
 type CallToolRequest struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments,omitempty"`
@@ -108,3 +240,9 @@ type LoggingMessage struct {
 	Data   json.RawMessage `json:"data,omitempty"`
 	Logger string          `json:"logger,omitempty"`
 }
+
+// PageEventNotificationMethod is the notification method used to stream browser page events
+const PageEventNotificationMethod = "notifications/page_event"
+
+// MonitorChangeNotificationMethod is the notification method used to report changes found by monitor_page
+const MonitorChangeNotificationMethod = "notifications/monitor_change"